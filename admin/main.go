@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/admin/namespaces"
+	"github.com/kelda/blimp/admin/sandboxes"
+	"github.com/kelda/blimp/admin/selftest"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/cfgdir"
+)
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:   "blimp-admin",
+		Short: "Manage a Blimp cluster",
+
+		PersistentPreRun:  setup,
+		PersistentPostRun: closeManager,
+
+		// The call to rootCmd.Execute prints the error, so we silence errors
+		// here to avoid double printing.
+		SilenceErrors: true,
+	}
+	rootCmd.AddCommand(namespaces.New(), sandboxes.New(), selftest.New())
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func setup(cmd *cobra.Command, _ []string) {
+	cfg, err := cfgdir.ParseConfig()
+	if err != nil {
+		log.WithError(err).Fatal("Failed to read blimp config")
+	}
+
+	if err := manager.SetupClient(cfg.ManagerHost, cfg.ManagerCert); err != nil {
+		log.WithError(err).Fatal("Failed to connect to the Blimp cluster")
+	}
+}
+
+func closeManager(_ *cobra.Command, _ []string) {
+	manager.C.Close()
+}
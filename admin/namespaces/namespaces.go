@@ -0,0 +1,97 @@
+package namespaces
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/admin/sandboxes"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "namespaces",
+		Short: "Map sandbox namespaces to the users that own them",
+	}
+	cmd.AddCommand(newListCommand(), newLookupCommand())
+	return cmd
+}
+
+func newListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the owner of every namespace",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := lookup(&cluster.LookupNamespaceRequest{}); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+}
+
+func newLookupCommand() *cobra.Command {
+	var user string
+	cmd := &cobra.Command{
+		Use:   "lookup [NAMESPACE]",
+		Short: "Find the owner of a namespace, or the namespace of a user",
+		Long: "Find the owner of a namespace, or the namespace of a user.\n\n" +
+			"The output also lists the users that were rejected because their names " +
+			"map to a namespace that's owned by someone else.",
+		Example: "  blimp-admin namespaces lookup alice-1a2b3c4d5e\n" +
+			"  blimp-admin namespaces lookup --user alice",
+		Run: func(_ *cobra.Command, args []string) {
+			if (len(args) == 1) == (user != "") {
+				fmt.Fprintln(os.Stderr, "Exactly one of a namespace or --user is required")
+				os.Exit(1)
+			}
+
+			req := &cluster.LookupNamespaceRequest{User: user}
+			if len(args) == 1 {
+				req.Namespace = args[0]
+			}
+			if err := lookup(req); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&user, "user", "", "The user whose namespace to look up")
+	return cmd
+}
+
+func lookup(req *cluster.LookupNamespaceRequest) error {
+	adminToken, err := sandboxes.GetAdminToken()
+	if err != nil {
+		return err
+	}
+	req.AdminToken = adminToken
+
+	resp, err := manager.C.LookupNamespace(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Records) == 0 {
+		fmt.Println("No namespaces found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "NAMESPACE\tOWNER\tCLAIMED\tREJECTED USERS")
+	for _, record := range resp.Records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			record.Namespace,
+			record.Owner,
+			time.Unix(record.ClaimedAt, 0).Format(time.RFC3339),
+			strings.Join(record.RejectedUsers, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package sandboxes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/buger/goterm"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/ps"
+	cliUsage "github.com/kelda/blimp/cli/usage"
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// adminTokenKey is the environment variable that can be used to override the
+// admin token in the Blimp config file.
+const adminTokenKey = "BLIMP_ADMIN_TOKEN"
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sandboxes",
+		Short: "Inspect and manage the sandboxes running in the cluster",
+	}
+	cmd.AddCommand(newListCommand(), newDescribeCommand(), newDeleteCommand(), newUsageCommand())
+	return cmd
+}
+
+func newListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all sandboxes in the cluster",
+		Run: func(_ *cobra.Command, args []string) {
+			if err := list(); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+}
+
+func newDescribeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe NAMESPACE",
+		Short: "Print detailed information about a sandbox",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Exactly one sandbox namespace is required")
+				os.Exit(1)
+			}
+
+			if err := describe(args[0]); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+}
+
+func newDeleteCommand() *cobra.Command {
+	var deleteVolumes bool
+	cmd := &cobra.Command{
+		Use:   "delete NAMESPACE",
+		Short: "Evict a sandbox from the cluster",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Exactly one sandbox namespace is required")
+				os.Exit(1)
+			}
+
+			if err := evict(args[0], deleteVolumes); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cmd.Flags().BoolVarP(&deleteVolumes, "volumes", "v", false,
+		"Also permanently delete the sandbox's volumes")
+	return cmd
+}
+
+func newUsageCommand() *cobra.Command {
+	var period string
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Print the resources used by each sandbox",
+		Run: func(_ *cobra.Command, args []string) {
+			if err := usage(period); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&period, "month", "",
+		"The month to report usage for, formatted as YYYY-MM. Defaults to the current month.")
+	return cmd
+}
+
+func list() error {
+	adminToken, err := GetAdminToken()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.ListSandboxes(context.Background(), &cluster.ListSandboxesRequest{
+		AdminToken: adminToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Sandboxes) == 0 {
+		fmt.Println("No sandboxes found.")
+		return nil
+	}
+
+	sandboxes := resp.Sandboxes
+	sort.Slice(sandboxes, func(i, j int) bool {
+		return sandboxes[i].Namespace < sandboxes[j].Namespace
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "NAMESPACE\tOWNER\tSTATUS\tAGE\tLAST ACTIVITY\tPODS\tCPU\tMEMORY")
+	for _, sandbox := range sandboxes {
+		statusStr, statusColor := ps.GetSandboxStatusString(sandbox.Phase)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			sandbox.Namespace,
+			sandbox.Owner,
+			goterm.Color(statusStr, statusColor),
+			since(sandbox.CreatedAt),
+			since(sandbox.LastActivity),
+			sandbox.NumPods,
+			sandbox.CpuRequested,
+			sandbox.MemoryRequested)
+	}
+	return nil
+}
+
+func describe(namespace string) error {
+	adminToken, err := GetAdminToken()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.DescribeSandbox(context.Background(), &cluster.DescribeSandboxRequest{
+		AdminToken: adminToken,
+		Namespace:  namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	sandbox := resp.Sandbox
+	statusStr, statusColor := ps.GetSandboxStatusString(sandbox.Phase)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Namespace:\t%s\n", sandbox.Namespace)
+	fmt.Fprintf(w, "Owner:\t%s\n", sandbox.Owner)
+	fmt.Fprintf(w, "Status:\t%s\n", goterm.Color(statusStr, statusColor))
+	fmt.Fprintf(w, "Age:\t%s\n", since(sandbox.CreatedAt))
+	fmt.Fprintf(w, "Last Activity:\t%s\n", since(sandbox.LastActivity))
+	fmt.Fprintf(w, "Pods:\t%d\n", sandbox.NumPods)
+	fmt.Fprintf(w, "CPU Requested:\t%s\n", sandbox.CpuRequested)
+	fmt.Fprintf(w, "Memory Requested:\t%s\n", sandbox.MemoryRequested)
+	w.Flush()
+
+	services := resp.GetStatus().GetServices()
+	if len(services) == 0 {
+		fmt.Println("\nNo services found.")
+		return nil
+	}
+
+	var serviceNames []string
+	for name := range services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "SERVICE\tSTATUS")
+	for _, name := range serviceNames {
+		statusStr, statusColor, _ := ps.GetStatusString(services[name])
+		fmt.Fprintf(w, "%s\t%s\n", name, goterm.Color(statusStr, statusColor))
+	}
+	return nil
+}
+
+func evict(namespace string, deleteVolumes bool) error {
+	adminToken, err := GetAdminToken()
+	if err != nil {
+		return err
+	}
+
+	_, err = manager.C.EvictSandbox(context.Background(), &cluster.EvictSandboxRequest{
+		AdminToken:    adminToken,
+		Namespace:     namespace,
+		DeleteVolumes: deleteVolumes,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Evicted sandbox %s.\n", namespace)
+	return nil
+}
+
+func usage(period string) error {
+	adminToken, err := GetAdminToken()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.GetUsage(context.Background(), &cluster.GetUsageRequest{
+		AdminToken: adminToken,
+		Period:     period,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Usage) == 0 {
+		fmt.Printf("No usage recorded for %s.\n", resp.GetQuota().GetPeriod())
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "NAMESPACE\tCPU (CORE-HOURS)\tMEMORY (GIB-HOURS)\tSTORAGE (GIB-HOURS)\tEGRESS")
+	for _, record := range resp.Usage {
+		fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%.2f\t%s\n",
+			record.Namespace,
+			record.CpuCoreHours,
+			record.MemoryGibHours,
+			record.StorageGibHours,
+			cliUsage.FormatBytes(record.EgressBytes))
+	}
+	return nil
+}
+
+// GetAdminToken returns the admin token used to authenticate admin requests to
+// the cluster manager.
+func GetAdminToken() (string, error) {
+	if token := os.Getenv(adminTokenKey); token != "" {
+		return token, nil
+	}
+
+	cfg, err := cfgdir.ParseConfig()
+	if err != nil {
+		return "", errors.WithContext("parse config file", err)
+	}
+
+	if cfg.AdminToken == "" {
+		return "", errors.NewFriendlyError(
+			"No admin token set. Set the \"admin_token\" field in your ~/.blimp/blimp.yaml, "+
+				"or set the %s environment variable.", adminTokenKey)
+	}
+	return cfg.AdminToken, nil
+}
+
+func since(timestamp int64) string {
+	if timestamp == 0 {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(time.Unix(timestamp, 0)))
+}
@@ -0,0 +1,76 @@
+package selftest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/buger/goterm"
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/admin/sandboxes"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	return &cobra.Command{
+		Use:   "selftest",
+		Short: "Boot a reference sandbox to check that the cluster is healthy",
+		Long: "Boot a reference sandbox to check that the cluster is healthy.\n\n" +
+			"The self-test boots a sandbox with a volume shared between two services, " +
+			"checks that the services boot normally, and reads data from the volume " +
+			"through a tunnel. The sandbox is deleted once the test completes.",
+		Run: func(_ *cobra.Command, _ []string) {
+			passed, err := run()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			if !passed {
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func run() (bool, error) {
+	adminToken, err := sandboxes.GetAdminToken()
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Println("Running self-test. This may take a few minutes..")
+	resp, err := manager.C.RunSelfTest(context.Background(), &cluster.RunSelfTestRequest{
+		AdminToken: adminToken,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	result := resp.Result
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "STEP\tRESULT\tDURATION\tDETAILS")
+	for _, step := range result.Steps {
+		status := goterm.Color("Passed", goterm.GREEN)
+		if !step.Passed {
+			status = goterm.Color("Failed", goterm.RED)
+		}
+
+		duration := (time.Duration(step.DurationMs) * time.Millisecond).Round(time.Millisecond)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", step.Name, status, duration, step.Msg)
+	}
+	if err := w.Flush(); err != nil {
+		return false, err
+	}
+
+	if result.Passed {
+		fmt.Println("\nSelf-test passed.")
+	} else {
+		fmt.Println("\nSelf-test failed.")
+	}
+	return result.Passed, nil
+}
@@ -0,0 +1,286 @@
+package addons
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:   "addons",
+		Short: "Manage databases that run alongside your services",
+		Long: "Manage throwaway databases that run alongside your services, so that " +
+			"they don't need to be defined in the Docker Compose file.\n\n" +
+			"Each add-on stores its data in your sandbox's volume, and its connection " +
+			"details are injected into your services as environment variables, such " +
+			"as DATABASE_URL. Environment variables set in the Compose file take " +
+			"precedence.\n\n" +
+			"The supported add-ons are postgres, mysql, and redis.",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runList(outputFormat); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	output.AddFlag(cobraCmd, &outputFormat)
+	cobraCmd.AddCommand(newCreateCommand(), newRemoveCommand(),
+		newSnapshotCommand(), newRestoreCommand())
+	return cobraCmd
+}
+
+func newCreateCommand() *cobra.Command {
+	var name string
+	var services []string
+	cobraCmd := &cobra.Command{
+		Use:   "create ADDON[:VERSION]",
+		Short: "Create a database in your sandbox",
+		Long: "Create a database in your sandbox.\n\n" +
+			"The connection environment variables are injected into all services " +
+			"unless --services is set. Running services are restarted so that they " +
+			"pick up the variables.",
+		Example: "  blimp addons create postgres:14 --services web,worker",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Please specify the add-on to create, such as postgres:14.")
+				os.Exit(1)
+			}
+
+			if err := runCreate(args[0], name, services); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().StringVar(&name, "name", "",
+		"The hostname of the add-on. Defaults to the add-on's type, such as postgres.")
+	cobraCmd.Flags().StringSliceVar(&services, "services", nil,
+		"The services to inject the connection environment variables into. Defaults to all services.")
+	return cobraCmd
+}
+
+func newRemoveCommand() *cobra.Command {
+	var deleteData bool
+	cobraCmd := &cobra.Command{
+		Use:   "rm NAME",
+		Short: "Delete an add-on",
+		Long: "Delete an add-on. Its data is kept, and is reused if an add-on with " +
+			"the same name is created, unless --data is set.",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Please specify the add-on to delete.")
+				os.Exit(1)
+			}
+
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			_, err = manager.C.DeleteAddon(context.Background(), &cluster.DeleteAddonRequest{
+				Auth:       blimpConfig.BlimpAuth(),
+				Name:       args[0],
+				DeleteData: deleteData,
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			fmt.Printf("Deleted add-on %s\n", args[0])
+		},
+	}
+	cobraCmd.Flags().BoolVar(&deleteData, "data", false,
+		"Also delete the add-on's data and snapshots")
+	return cobraCmd
+}
+
+func newSnapshotCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "snapshot NAME [SNAPSHOT]",
+		Short: "Save a snapshot of an add-on's data",
+		Long: "Save a snapshot of an add-on's data so that it can be restored with " +
+			"`blimp addons restore`. The snapshot is named after the current time " +
+			"if SNAPSHOT isn't set.",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 && len(args) != 2 {
+				fmt.Fprintln(os.Stderr, "Please specify the add-on to snapshot.")
+				os.Exit(1)
+			}
+
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			req := &cluster.SnapshotAddonRequest{
+				Auth: blimpConfig.BlimpAuth(),
+				Name: args[0],
+			}
+			if len(args) == 2 {
+				req.Snapshot = args[1]
+			}
+
+			resp, err := manager.C.SnapshotAddon(context.Background(), req)
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			fmt.Printf("Saved snapshot %s of %s\n", resp.GetSnapshot(), args[0])
+		},
+	}
+}
+
+func newRestoreCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore NAME SNAPSHOT",
+		Short: "Restore an add-on's data from a snapshot",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 2 {
+				fmt.Fprintln(os.Stderr, "Please specify the add-on and the snapshot to restore.\n"+
+					"Run `blimp addons` to see the available snapshots.")
+				os.Exit(1)
+			}
+
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			_, err = manager.C.RestoreAddon(context.Background(), &cluster.RestoreAddonRequest{
+				Auth:     blimpConfig.BlimpAuth(),
+				Name:     args[0],
+				Snapshot: args[1],
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			fmt.Printf("Restored %s from snapshot %s\n", args[0], args[1])
+		},
+	}
+}
+
+func runCreate(addon, name string, services []string) error {
+	engine, version := addon, ""
+	if i := strings.Index(addon, ":"); i != -1 {
+		engine, version = addon[:i], addon[i+1:]
+	}
+
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.CreateAddon(context.Background(), &cluster.CreateAddonRequest{
+		Auth:     blimpConfig.BlimpAuth(),
+		Name:     name,
+		Engine:   engine,
+		Version:  version,
+		Services: services,
+	})
+	if err != nil {
+		return err
+	}
+
+	created := resp.GetAddon()
+	fmt.Printf("Created %s:%s add-on %s.\n", created.GetEngine(), created.GetVersion(), created.GetName())
+	if len(created.GetServices()) == 0 {
+		fmt.Println("The following environment variables are set in all services:")
+	} else {
+		fmt.Printf("The following environment variables are set in %s:\n",
+			strings.Join(created.GetServices(), ", "))
+	}
+	printEnv(created.GetEnv())
+
+	if len(resp.GetRestartedServices()) != 0 {
+		fmt.Printf("Restarted services: %s\n", strings.Join(resp.GetRestartedServices(), ", "))
+	}
+	return nil
+}
+
+// Addon is the schema for the machine-readable output of `blimp addons`.
+type Addon struct {
+	Name    string `json:"name"`
+	Engine  string `json:"engine"`
+	Version string `json:"version"`
+
+	// Services is empty if the environment variables are set in all
+	// services.
+	Services  []string          `json:"services"`
+	Env       map[string]string `json:"env"`
+	Status    string            `json:"status"`
+	Snapshots []string          `json:"snapshots"`
+}
+
+func runList(outputFormat output.Format) error {
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.ListAddons(context.Background(), &cluster.ListAddonsRequest{
+		Auth: blimpConfig.BlimpAuth(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if outputFormat != output.Text {
+		out := []Addon{}
+		for _, a := range resp.GetAddons() {
+			out = append(out, Addon{
+				Name:      a.GetName(),
+				Engine:    a.GetEngine(),
+				Version:   a.GetVersion(),
+				Services:  a.GetServices(),
+				Env:       a.GetEnv(),
+				Status:    a.GetPhase(),
+				Snapshots: a.GetSnapshots(),
+			})
+		}
+		return output.Print(outputFormat, out)
+	}
+
+	if len(resp.GetAddons()) == 0 {
+		fmt.Println("No add-ons have been created. Create one with `blimp addons create postgres`.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "NAME\tADDON\tSTATUS\tSERVICES\tSNAPSHOTS")
+	for _, a := range resp.GetAddons() {
+		services := "all"
+		if len(a.GetServices()) != 0 {
+			services = strings.Join(a.GetServices(), ",")
+		}
+
+		snapshots := "-"
+		if len(a.GetSnapshots()) != 0 {
+			snapshots = strings.Join(a.GetSnapshots(), ",")
+		}
+
+		fmt.Fprintf(w, "%s\t%s:%s\t%s\t%s\t%s\n",
+			a.GetName(), a.GetEngine(), a.GetVersion(), a.GetPhase(), services, snapshots)
+	}
+	return w.Flush()
+}
+
+func printEnv(env map[string]string) {
+	var keys []string
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Printf("  %s=%s\n", key, env[key])
+	}
+}
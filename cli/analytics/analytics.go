@@ -0,0 +1,312 @@
+// Package analytics records anonymous usage data, such as which commands are
+// run, how long sandboxes take to boot, and which errors users run into.
+// Events don't contain file paths, service names, or identities. Users are
+// only identified by a salted hash, and the salt never leaves the user's
+// machine.
+//
+// Events are queued on disk, and sent in batches. Analytics are disabled if
+// the user sets `opt_out_analytics` in blimp.yaml, if $BLIMP_DISABLE_ANALYTICS
+// is set, or if no endpoint is configured.
+package analytics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/hash"
+	"github.com/kelda/blimp/pkg/version"
+)
+
+// DefaultEndpoint is where events are sent if the user hasn't configured an
+// `analytics_endpoint`. It's set by make.
+var DefaultEndpoint = ""
+
+// DisableEnvVar disables analytics when it's set to any value. It lets
+// operators turn off analytics without editing each user's blimp.yaml.
+const DisableEnvVar = "BLIMP_DISABLE_ANALYTICS"
+
+const (
+	// batchSize is how many events are queued before they're sent.
+	batchSize = 20
+
+	// maxBatchAge is how long events can be queued before they're sent,
+	// even if there are fewer than batchSize of them.
+	maxBatchAge = time.Hour
+
+	// maxQueuedEvents caps the size of the queue when events can't be sent,
+	// such as when the endpoint is unreachable. The oldest events are
+	// dropped first.
+	maxQueuedEvents = 500
+
+	// sendTimeout is how long the CLI waits for the endpoint before giving
+	// up, so that analytics never noticeably slow down commands.
+	sendTimeout = 2 * time.Second
+)
+
+// Event is a single analytics event. Only the fields relevant to the event's
+// name are set.
+type Event struct {
+	Name     string    `json:"name"`
+	Time     time.Time `json:"time"`
+	ClientID string    `json:"client_id"`
+	Version  string    `json:"version"`
+	OS       string    `json:"os"`
+
+	// Command is the path of the command that was run, such as `blimp up`.
+	// Arguments aren't included since they may contain file paths.
+	Command string `json:"command,omitempty"`
+
+	DurationMillis int64 `json:"duration_ms,omitempty"`
+
+	// ErrorCode is the error's code from the catalog in pkg/errors, or
+	// `unknown` for other errors. Error messages aren't included since they
+	// may contain file paths.
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+const (
+	// EventCommand is recorded when a command finishes.
+	EventCommand = "command"
+
+	// EventBoot is recorded when `blimp up` finishes booting the sandbox.
+	EventBoot = "boot"
+)
+
+var state struct {
+	sync.Mutex
+
+	enabled  bool
+	endpoint string
+	clientID string
+}
+
+// Init enables analytics according to the user's configuration.
+func Init(cfg cfgdir.Config) {
+	state.Lock()
+	defer state.Unlock()
+
+	state.endpoint = cfg.AnalyticsEndpoint
+	if state.endpoint == "" {
+		state.endpoint = DefaultEndpoint
+	}
+
+	_, disabled := os.LookupEnv(DisableEnvVar)
+	if cfg.OptOutAnalytics || disabled || state.endpoint == "" {
+		state.enabled = false
+		return
+	}
+
+	salt, err := getSalt()
+	if err != nil {
+		log.WithError(err).Debug("Failed to get analytics salt. Disabling analytics.")
+		state.enabled = false
+		return
+	}
+
+	// The hash is stable for each user on each machine, but can't be
+	// reversed without the salt.
+	state.clientID = hash.Bytes(append(salt, []byte(cfg.ClusterToken)...))
+	state.enabled = true
+}
+
+// RecordCommand records that the command finished. err is the error that the
+// command failed with, if any.
+func RecordCommand(command string, duration time.Duration, err error) {
+	record(Event{
+		Name:           EventCommand,
+		Command:        command,
+		DurationMillis: duration.Milliseconds(),
+		ErrorCode:      getErrorCode(err),
+	})
+}
+
+// RecordBoot records how long it took to boot the sandbox.
+func RecordBoot(duration time.Duration, err error) {
+	record(Event{
+		Name:           EventBoot,
+		DurationMillis: duration.Milliseconds(),
+		ErrorCode:      getErrorCode(err),
+	})
+}
+
+func getErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if codedErr, ok := errors.GetCode(err); ok {
+		return string(codedErr.Code())
+	}
+	return "unknown"
+}
+
+func record(event Event) {
+	state.Lock()
+	defer state.Unlock()
+	if !state.enabled {
+		return
+	}
+
+	event.Time = time.Now()
+	event.ClientID = state.clientID
+	event.Version = version.Version
+	event.OS = runtime.GOOS
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.WithError(err).Debug("Failed to marshal analytics event")
+		return
+	}
+
+	f, err := os.OpenFile(queuePath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		log.WithError(err).Debug("Failed to open analytics queue")
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(eventJSON, '\n')); err != nil {
+		log.WithError(err).Debug("Failed to queue analytics event")
+	}
+}
+
+// Flush sends the queued events if there are enough of them, or if they've
+// been queued for long enough. Events that fail to send stay in the queue,
+// and are retried by the next Flush.
+func Flush() {
+	state.Lock()
+	defer state.Unlock()
+	if !state.enabled {
+		return
+	}
+
+	events, err := readQueue()
+	if err != nil {
+		log.WithError(err).Debug("Failed to read analytics queue")
+		return
+	}
+
+	if len(events) == 0 ||
+		(len(events) < batchSize && time.Since(events[0].Time) < maxBatchAge) {
+		return
+	}
+
+	if err := send(state.endpoint, events); err != nil {
+		log.WithError(err).Debug("Failed to send analytics")
+		if len(events) > maxQueuedEvents {
+			if err := writeQueue(events[len(events)-maxQueuedEvents:]); err != nil {
+				log.WithError(err).Debug("Failed to trim analytics queue")
+			}
+		}
+		return
+	}
+
+	if err := os.Remove(queuePath()); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).Debug("Failed to clear analytics queue")
+	}
+}
+
+func send(endpoint string, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return errors.WithContext("marshal", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithContext("create request", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func readQueue() ([]Event, error) {
+	f, err := os.Open(queuePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			// Skip events that were only partially written.
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+func writeQueue(events []Event) error {
+	var buf bytes.Buffer
+	for _, event := range events {
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		buf.Write(eventJSON)
+		buf.WriteByte('\n')
+	}
+	return ioutil.WriteFile(queuePath(), buf.Bytes(), 0600)
+}
+
+// getSalt returns the salt used to hash the user's identity, creating it if
+// necessary.
+func getSalt() ([]byte, error) {
+	path := cfgdir.Expand("analytics-salt")
+	saltHex, err := ioutil.ReadFile(path)
+	if err == nil {
+		if salt, err := hex.DecodeString(string(saltHex)); err == nil && len(salt) != 0 {
+			return salt, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.WithContext("generate salt", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(hex.EncodeToString(salt)), 0600); err != nil {
+		return nil, errors.WithContext("write salt", err)
+	}
+	return salt, nil
+}
+
+func queuePath() string {
+	return cfgdir.Expand("analytics-queue.jsonl")
+}
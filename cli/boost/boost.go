@@ -0,0 +1,81 @@
+package boost
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	var cpu string
+	var duration time.Duration
+	cobraCmd := &cobra.Command{
+		Use:   "boost",
+		Short: "Temporarily give your services more CPU",
+		Long: "Temporarily give your services more CPU, for occasional heavy tasks " +
+			"like big test runs or data imports.\n\n" +
+			"Services that are currently being throttled are restarted so that they " +
+			"get the extra CPU immediately. Other services get the extra CPU the next " +
+			"time they're deployed or restarted. Boosted services are restarted with " +
+			"their normal resources once the boost ends.\n\n" +
+			"The extra CPU counts towards your sandbox's usage.",
+		Example: "  blimp boost --cpu 2x --for 30m",
+		Run: func(_ *cobra.Command, args []string) {
+			if err := run(cpu, duration); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().StringVar(&cpu, "cpu", "2x",
+		"How much to multiply the CPU available to each service by")
+	cobraCmd.Flags().DurationVar(&duration, "for", 30*time.Minute,
+		"How long the boost should last")
+	return cobraCmd
+}
+
+func run(cpu string, duration time.Duration) error {
+	cpuMultiplier, err := parseMultiplier(cpu)
+	if err != nil {
+		return err
+	}
+
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.Boost(context.Background(), &cluster.BoostRequest{
+		Auth:            blimpConfig.BlimpAuth(),
+		CpuMultiplier:   cpuMultiplier,
+		DurationSeconds: int64(duration.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Unix(resp.ExpiresAt, 0)
+	fmt.Printf("Boosted CPU by %gx until %s.\n", cpuMultiplier, expiresAt.Format(time.Kitchen))
+	if len(resp.RestartedServices) != 0 {
+		fmt.Printf("Restarted throttled services: %s\n", strings.Join(resp.RestartedServices, ", "))
+	}
+	return nil
+}
+
+// parseMultiplier parses multipliers such as "2x" or "1.5".
+func parseMultiplier(str string) (float64, error) {
+	multiplier, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(str), "x"), 64)
+	if err != nil {
+		return 0, errors.NewFriendlyError(
+			"Invalid CPU multiplier %q. It should be formatted like `2x`.", str)
+	}
+	return multiplier, nil
+}
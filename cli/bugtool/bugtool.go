@@ -21,22 +21,39 @@ import (
 
 var fs = afero.NewOsFs()
 
+// reportOptions configures what's included in the report.
+type reportOptions struct {
+	composePaths       []string
+	maxStatusSnapshots int
+}
+
 func New() *cobra.Command {
 	var out string
+	var opts reportOptions
 	cmd := cobra.Command{
-		Use:   "bug-tool",
-		Short: "Generate an archive for blimp debugging",
+		Use:     "bug-report",
+		Aliases: []string{"bug-tool"},
+		Short:   "Generate an archive for blimp debugging",
+		Long: "Generate an archive containing information for debugging Blimp, such as " +
+			"the CLI's logs, Syncthing's logs, your Docker Compose file, and the status " +
+			"of your sandbox according to the Blimp cluster.\n\n" +
+			"The values of environment variables and build args are removed from the " +
+			"Docker Compose file, and your credentials are removed from the logs.",
 		Run: func(_ *cobra.Command, args []string) {
-			if err := run(out); err != nil {
+			if err := run(out, opts); err != nil {
 				log.Fatal(err)
 			}
 		},
 	}
 	cmd.Flags().StringVarP(&out, "out", "o", "", "path to write archive to")
+	cmd.Flags().StringSliceVarP(&opts.composePaths, "file", "f", nil,
+		"Specify an alternate compose file\nDefaults to docker-compose.yml and docker-compose.yaml")
+	cmd.Flags().IntVarP(&opts.maxStatusSnapshots, "status-snapshots", "", 20,
+		"The number of recent status updates to include")
 	return &cmd
 }
 
-func run(out string) error {
+func run(out string, opts reportOptions) error {
 	tmpdir, err := afero.TempDir(fs, "", "kelda-bug-tool")
 	if err != nil {
 		return fmt.Errorf("failed to create report directory:\n%s", err)
@@ -49,7 +66,11 @@ func run(out string) error {
 		}
 	}()
 
-	setupReports(tmpdir)
+	setupReports(tmpdir, opts)
+
+	if err := redactSecrets(tmpdir); err != nil {
+		return fmt.Errorf("failed to redact secrets:\n%s", err)
+	}
 
 	if out == "" {
 		out = fmt.Sprintf("blimp-bug-info-%s.tar.gz",
@@ -62,13 +83,14 @@ func run(out string) error {
 
 	msg := `Created bug information archive at '%s'.
 Please send it to the Kelda team at 'kevin@kelda.io'.
-You may want to edit the archive if your deployment contains sensitive information.
+Credentials and environment variables were redacted, but you may want to
+review the archive if your deployment contains other sensitive information.
 `
 	fmt.Printf(msg, out)
 	return nil
 }
 
-func setupReports(dir string) {
+func setupReports(dir string, opts reportOptions) {
 	//Add more bug reports here.
 	var err error
 	err = reportCliVersion(dir)
@@ -95,6 +117,16 @@ func setupReports(dir string) {
 	if err != nil {
 		log.Errorf("failed to report syncthing log files because: %s", err)
 	}
+
+	err = reportCompose(dir, opts.composePaths)
+	if err != nil {
+		log.Errorf("failed to report compose file because: %s", err)
+	}
+
+	err = reportDiagnostics(dir, opts.maxStatusSnapshots)
+	if err != nil {
+		log.Errorf("failed to report manager diagnostics because: %s", err)
+	}
 }
 
 func reportCliVersion(dir string) error {
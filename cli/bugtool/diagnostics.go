@@ -0,0 +1,170 @@
+package bugtool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	composeTypes "github.com/kelda/compose-go/types"
+	"github.com/spf13/afero"
+
+	"github.com/kelda/blimp/cli/authstore"
+	cliConfig "github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const redacted = "<redacted>"
+
+// diagnosticsTimeout is how long to wait for the manager's diagnostics. The
+// manager may be unreachable, which is often why a report is being made.
+const diagnosticsTimeout = 30 * time.Second
+
+// secretAssignmentPattern matches values assigned to keys that look like
+// credentials, such as `DB_PASSWORD=hunter2` or `api_key: abc123`.
+var secretAssignmentPattern = regexp.MustCompile(
+	`(?i)((?:password|passwd|secret|token|api[_-]?key|credential)[a-z0-9_-]*"?\s*[:=]\s*"?)[^\s"',]+`)
+
+func reportDiagnostics(dir string, maxStatusSnapshots int) error {
+	blimpConfig, err := cliConfig.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get blimp config: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), diagnosticsTimeout)
+	defer cancel()
+
+	diagnostics, err := manager.C.GetDiagnostics(ctx, &cluster.GetDiagnosticsRequest{
+		Auth:               blimpConfig.BlimpAuth(),
+		MaxStatusSnapshots: int32(maxStatusSnapshots),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get diagnostics from manager: %s", err)
+	}
+
+	marshaler := jsonpb.Marshaler{Indent: "  "}
+	diagnosticsJSON, err := marshaler.MarshalToString(diagnostics)
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnostics: %s", err)
+	}
+	return writeToReportFile(filepath.Join(dir, "manager-diagnostics.json"), diagnosticsJSON)
+}
+
+// reportCompose writes the merged Compose file, with the values of
+// environment variables and build args removed.
+func reportCompose(dir string, composePaths []string) error {
+	composePath, overridePaths, err := compose.GetPaths(composePaths)
+	if err != nil {
+		return fmt.Errorf("failed to find compose file: %s", err)
+	}
+
+	project, err := compose.Load(composePath, overridePaths, nil)
+	if err != nil {
+		return fmt.Errorf("failed to load compose file: %s", err)
+	}
+
+	composeBytes, err := compose.Marshal(sanitizeCompose(project))
+	if err != nil {
+		return fmt.Errorf("failed to marshal compose file: %s", err)
+	}
+	return writeToReportFile(filepath.Join(dir, "docker-compose.sanitized.yml"), string(composeBytes))
+}
+
+func sanitizeCompose(project composeTypes.Project) composeTypes.Project {
+	redactMapping := func(mapping composeTypes.MappingWithEquals) composeTypes.MappingWithEquals {
+		if mapping == nil {
+			return nil
+		}
+
+		redactedMapping := composeTypes.MappingWithEquals{}
+		for key, val := range mapping {
+			if val == nil {
+				redactedMapping[key] = nil
+				continue
+			}
+			redactedVal := redacted
+			redactedMapping[key] = &redactedVal
+		}
+		return redactedMapping
+	}
+
+	var services composeTypes.Services
+	for _, svc := range project.Services {
+		svc.Environment = redactMapping(svc.Environment)
+		if svc.Build != nil {
+			build := *svc.Build
+			build.Args = redactMapping(build.Args)
+			svc.Build = &build
+		}
+		services = append(services, svc)
+	}
+	project.Services = services
+	return project
+}
+
+// redactSecrets removes the user's credentials, and anything that looks like
+// a credential, from the files in the report.
+func redactSecrets(dir string) error {
+	var secrets []string
+	if cfg, err := cfgdir.ParseConfig(); err == nil {
+		secrets = append(secrets, cfg.ClusterToken, cfg.AdminToken)
+	}
+	if store, err := authstore.New(); err == nil {
+		secrets = append(secrets, store.Username, store.KubeToken)
+	}
+
+	return afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		contents, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %s", path, err)
+		}
+
+		redactedContents := redact(contents, secrets)
+		if bytes.Equal(contents, redactedContents) {
+			return nil
+		}
+		return afero.WriteFile(fs, path, redactedContents, fi.Mode())
+	})
+}
+
+func redact(contents []byte, secrets []string) []byte {
+	// Replace longer secrets first so that a secret that contains another
+	// secret isn't partially redacted.
+	secrets = append([]string(nil), secrets...)
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+
+	for _, secret := range secrets {
+		// Short values are too likely to match unrelated text.
+		if len(secret) < 8 {
+			continue
+		}
+		contents = bytes.Replace(contents, []byte(secret), []byte(redacted), -1)
+
+		// Secrets that span multiple lines are escaped in JSON files, such
+		// as the manager's diagnostics.
+		if escaped, err := json.Marshal(secret); err == nil {
+			escaped = escaped[1 : len(escaped)-1]
+			contents = bytes.Replace(contents, escaped, []byte(redacted), -1)
+		}
+	}
+	return secretAssignmentPattern.ReplaceAll(contents, []byte("${1}"+redacted))
+}
@@ -19,7 +19,7 @@ import (
 	"github.com/kelda/blimp/pkg/build"
 	"github.com/kelda/blimp/pkg/build/buildkit"
 	"github.com/kelda/blimp/pkg/build/docker"
-	"github.com/kelda/blimp/pkg/dockercompose"
+	"github.com/kelda/blimp/pkg/compose"
 	"github.com/kelda/blimp/pkg/errors"
 	protoAuth "github.com/kelda/blimp/pkg/proto/auth"
 	"github.com/kelda/blimp/pkg/proto/cluster"
@@ -72,7 +72,7 @@ func New() *cobra.Command {
 			// Convert the compose path to an absolute path so that the code
 			// that makes identifiers for bind volumes are unique for relative
 			// paths.
-			composePath, overridePaths, err := dockercompose.GetPaths(composePaths)
+			composePath, overridePaths, err := compose.GetPaths(composePaths)
 			if err != nil {
 				if os.IsNotExist(err) {
 					log.Fatal("Docker Compose file not found.\n" +
@@ -83,7 +83,7 @@ func New() *cobra.Command {
 				log.WithError(err).Fatal("Failed to get absolute path to Compose file")
 			}
 
-			parsedCompose, err := dockercompose.Load(composePath, overridePaths, services)
+			parsedCompose, err := compose.Load(composePath, overridePaths, services)
 			if err != nil {
 				log.WithError(err).Fatal("Failed to load compose file")
 			}
@@ -0,0 +1,57 @@
+package completion
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+func New() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion bash|zsh|fish",
+		Short: "Generate shell completion scripts",
+		Long: "Generate a script that completes Blimp commands and flags.\n\n" +
+			"To load completions in the current shell:\n" +
+			"  bash: source <(blimp completion bash)\n" +
+			"  zsh:  source <(blimp completion zsh)\n" +
+			"  fish: blimp completion fish | source\n\n" +
+			"To load completions in every new shell, write the script to your " +
+			"shell's completion directory, such as /etc/bash_completion.d/blimp, " +
+			"a directory in your $fpath named _blimp, or " +
+			"~/.config/fish/completions/blimp.fish.",
+		ValidArgs: []string{"bash", "zsh", "fish"},
+
+		// Generating completions doesn't require connecting to the cluster.
+		PersistentPreRun:  func(*cobra.Command, []string) {},
+		PersistentPostRun: func(*cobra.Command, []string) {},
+
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Please specify the shell to generate completions for. "+
+					"Either bash, zsh, or fish.")
+				os.Exit(1)
+			}
+
+			if err := run(cmd.Root(), args[0]); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+}
+
+func run(rootCmd *cobra.Command, shell string) error {
+	switch shell {
+	case "bash":
+		return rootCmd.GenBashCompletion(os.Stdout)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		return rootCmd.GenFishCompletion(os.Stdout, true)
+	default:
+		return errors.NewFriendlyError("Unsupported shell %q. "+
+			"Completions can be generated for bash, zsh, or fish.", shell)
+	}
+}
@@ -0,0 +1,135 @@
+package composeconfig
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// maxValueLength is the longest value that's printed before it's truncated.
+const maxValueLength = 60
+
+func New() *cobra.Command {
+	var composePaths []string
+	var merged bool
+	cobraCmd := &cobra.Command{
+		Use:   "config [options]",
+		Short: "Show the merged Docker Compose configuration",
+		Long: "Show each field in the merged Docker Compose configuration, and the file that set it.\n\n" +
+			"Compose files are merged in order, so fields in later files override earlier ones. " +
+			"If a blimp.override.yaml file exists next to docker-compose.yml, it's merged last. " +
+			"It lets you change ports, environment variables, or images for your own sandbox " +
+			"without changing the files shared with your team, so it should be added to .gitignore.",
+
+		// Showing the configuration doesn't require connecting to the cluster.
+		PersistentPreRun:  func(*cobra.Command, []string) {},
+		PersistentPostRun: func(*cobra.Command, []string) {},
+
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := run(composePaths, merged); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().StringSliceVarP(&composePaths, "file", "f", nil,
+		"Specify an alternate compose file\nDefaults to docker-compose.yml and docker-compose.yaml")
+	cobraCmd.Flags().BoolVarP(&merged, "merged", "", false,
+		"Print the merged Compose file rather than where each field came from")
+	cobraCmd.AddCommand(newSetCommand(), newUnsetCommand())
+	return cobraCmd
+}
+
+func run(composePaths []string, merged bool) error {
+	composePath, overridePaths, err := compose.GetPaths(composePaths)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.NewFriendlyError("Docker Compose file not found.\n" +
+				"Blimp must be run from the same directory as docker-compose.yml.")
+		}
+		return errors.WithContext("get compose file paths", err)
+	}
+
+	if merged {
+		parsed, err := compose.Load(composePath, overridePaths, nil)
+		if err != nil {
+			return err
+		}
+
+		b, err := compose.Marshal(parsed)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+
+	paths := append([]string{composePath}, overridePaths...)
+	sources, err := compose.GetProvenance(paths)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Compose files, in the order they're merged:")
+	var unignoredOverride string
+	for i, path := range paths {
+		var note string
+		if compose.IsPersonalOverride(path) {
+			note = " (personal override)"
+			if isNotGitIgnored(path) {
+				unignoredOverride = path
+			}
+		}
+		fmt.Printf("  %d. %s%s\n", i+1, displayPath(path), note)
+	}
+
+	if unignoredOverride != "" {
+		fmt.Printf("\nWarning: %s isn't ignored by Git. "+
+			"Add it to .gitignore so that it isn't shared with your team.\n", displayPath(unignoredOverride))
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 10, 3, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tVALUE\tSOURCE")
+	for _, source := range sources {
+		value := source.Value
+		if len(value) > maxValueLength {
+			value = value[:maxValueLength-3] + "..."
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", source.Path, value, displayPath(source.File))
+	}
+	return w.Flush()
+}
+
+// displayPath returns the path relative to the working directory, if
+// possible.
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(wd, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// isNotGitIgnored returns true if the file is in a Git repository, and isn't
+// ignored. It returns false if Git isn't installed.
+func isNotGitIgnored(path string) bool {
+	cmd := exec.Command("git", "check-ignore", "--quiet", filepath.Base(path))
+	cmd.Dir = filepath.Dir(path)
+
+	// `git check-ignore` exits with 1 if the file isn't ignored, and 128 if
+	// it's not in a repository.
+	exitErr, ok := cmd.Run().(*exec.ExitError)
+	return ok && exitErr.ExitCode() == 1
+}
@@ -0,0 +1,109 @@
+package composeconfig
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/util"
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// settableKeys maps the blimp.yaml keys that can be changed with `blimp
+// config set` to functions that validate their values.
+var settableKeys = map[string]func(string) error{
+	"sync.max-upload":      validateRate,
+	"sync.max-download":    validateRate,
+	"tunnels.max-upload":   validateRate,
+	"tunnels.max-download": validateRate,
+}
+
+func newSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Change a setting in blimp.yaml",
+		Long: "Change a setting in the Blimp configuration file (~/.blimp/blimp.yaml).\n\n" +
+			"Supported keys:\n" + strings.Join(sortedKeys(), "\n") + "\n\n" +
+			"The `max-upload` and `max-download` settings limit the bandwidth used by " +
+			"file syncing and port tunnels, such as `blimp config set sync.max-upload 5MB/s`.",
+
+		// Changing settings doesn't require connecting to the cluster.
+		PersistentPreRun:  func(*cobra.Command, []string) {},
+		PersistentPostRun: func(*cobra.Command, []string) {},
+
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 2 {
+				fmt.Fprintln(os.Stderr, "Please specify the key and value to set.")
+				os.Exit(1)
+			}
+
+			if err := setValue(args[0], args[1]); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+}
+
+func newUnsetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset KEY",
+		Short: "Reset a setting in blimp.yaml to its default",
+
+		PersistentPreRun:  func(*cobra.Command, []string) {},
+		PersistentPostRun: func(*cobra.Command, []string) {},
+
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Please specify the key to unset.")
+				os.Exit(1)
+			}
+
+			if err := setValue(args[0], ""); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+}
+
+func setValue(key, value string) error {
+	validate, ok := settableKeys[key]
+	if !ok {
+		return errors.NewFriendlyError("Unknown setting %q. Supported settings are:\n%s",
+			key, strings.Join(sortedKeys(), "\n"))
+	}
+
+	if value != "" {
+		if err := validate(value); err != nil {
+			return err
+		}
+	}
+
+	if err := cfgdir.SetConfigValue(key, value); err != nil {
+		return errors.WithContext("update config", err)
+	}
+
+	if value == "" {
+		fmt.Printf("Unset %s\n", key)
+	} else {
+		fmt.Printf("Set %s to %s\n", key, value)
+	}
+	return nil
+}
+
+func validateRate(value string) error {
+	_, err := util.ParseRate(value)
+	return err
+}
+
+func sortedKeys() []string {
+	var keys []string
+	for key := range settableKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
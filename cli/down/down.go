@@ -11,6 +11,7 @@ import (
 	"github.com/kelda/blimp/cli/config"
 	"github.com/kelda/blimp/cli/manager"
 	"github.com/kelda/blimp/cli/util"
+	"github.com/kelda/blimp/pkg/compose"
 	"github.com/kelda/blimp/pkg/errors"
 	"github.com/kelda/blimp/pkg/proto/auth"
 	"github.com/kelda/blimp/pkg/proto/cluster"
@@ -18,6 +19,7 @@ import (
 
 func New() *cobra.Command {
 	var deleteVolumes bool
+	var project string
 	cobraCmd := &cobra.Command{
 		Use:   "down",
 		Short: "Delete your cloud sandbox",
@@ -25,6 +27,9 @@ func New() *cobra.Command {
 
 All containers are removed.
 Volumes aren't removed unless the -v flag is used.
+
+If the Compose project is named, and other projects are deployed in the
+sandbox, only the project's containers are removed.
 `,
 		Run: func(_ *cobra.Command, args []string) {
 			blimpConfig, err := config.GetConfig()
@@ -44,25 +49,59 @@ Volumes aren't removed unless the -v flag is used.
 				}
 			}
 
-			if err := Run(blimpConfig.BlimpAuth(), deleteVolumes); err != nil {
+			if project == "" {
+				project = getProjectName()
+			}
+
+			if err := Run(blimpConfig.BlimpAuth(), project, deleteVolumes); err != nil {
 				errors.HandleFatalError(err)
 			}
 		},
 	}
 	cobraCmd.Flags().BoolVarP(&deleteVolumes, "volumes", "v", false,
 		"Remove named volumes declared in the `volumes` section of the Compose file.")
+	cobraCmd.Flags().StringVarP(&project, "project-name", "p", "",
+		"The Compose project to remove. Defaults to the project in the current directory.")
 	return cobraCmd
 }
 
-func Run(auth *auth.BlimpAuth, deleteVolumes bool) error {
-	_, err := manager.C.DeleteSandbox(context.Background(), &cluster.DeleteSandboxRequest{
+// getProjectName returns the name of the Compose project in the current
+// directory. It returns an empty string if there's no Compose file, or if the
+// project isn't named.
+func getProjectName() string {
+	composePath, overridePaths, err := compose.GetPaths(nil)
+	if err != nil {
+		return os.Getenv(compose.ProjectNameEnv)
+	}
+
+	project, err := compose.LoadProjectName(composePath, overridePaths)
+	if err != nil {
+		return ""
+	}
+	return project
+}
+
+// Run removes the given project from the sandbox. The entire sandbox is
+// deleted if the project is the only one in the sandbox, or if it's empty.
+func Run(auth *auth.BlimpAuth, project string, deleteVolumes bool) error {
+	resp, err := manager.C.DeleteSandbox(context.Background(), &cluster.DeleteSandboxRequest{
 		Auth:          auth,
 		DeleteVolumes: deleteVolumes,
+		Project:       project,
 	})
 	if err != nil {
 		return errors.WithContext("start sandbox deletion", err)
 	}
 
+	if resp.GetProjectOnly() {
+		fmt.Printf("Removed the services in project %s. "+
+			"The other projects in the sandbox are still running.\n", project)
+		if deleteVolumes {
+			fmt.Println("Volumes are only removed once every project is removed from the sandbox.")
+		}
+		return nil
+	}
+
 	fmt.Println("Sandbox deletion successfully started")
 	fmt.Println("Note that `blimp up` won't work until the previous sandbox is completely deleted")
 	pp := util.NewProgressPrinter(os.Stdout, "Waiting for sandbox deletion to complete")
@@ -71,13 +110,9 @@ func Run(auth *auth.BlimpAuth, deleteVolumes bool) error {
 
 	watchCtx, cancelWatch := context.WithCancel(context.Background())
 	defer cancelWatch()
-	statusStream, err := manager.C.WatchStatus(watchCtx, &cluster.GetStatusRequest{
+	statusStream := manager.WatchStatus(watchCtx, manager.C, &cluster.GetStatusRequest{
 		Auth: auth,
 	})
-	if err != nil {
-		return errors.WithContext("start sandbox status watch", err)
-	}
-
 	for {
 		update, err := statusStream.Recv()
 		if err != nil {
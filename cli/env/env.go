@@ -0,0 +1,189 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:   "env",
+		Short: "Manage environment variables that are set in every service",
+		Long: "Manage environment variables that are set across your sandbox, such as " +
+			"feature flags, without editing the Docker Compose file.\n\n" +
+			"The variables are stored in your sandbox, and are injected into every " +
+			"service each time it's deployed. They override the values set in the " +
+			"Docker Compose file.",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runList(outputFormat); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	output.AddFlag(cobraCmd, &outputFormat)
+	cobraCmd.AddCommand(newSetCommand(), newUnsetCommand())
+	return cobraCmd
+}
+
+func newSetCommand() *cobra.Command {
+	var services []string
+	cobraCmd := &cobra.Command{
+		Use:   "set KEY=VALUE...",
+		Short: "Set environment variables in your services",
+		Long: "Set environment variables in your services.\n\n" +
+			"The variables are set in all services unless --services is set. Running " +
+			"services whose environment changed are restarted so that they pick up " +
+			"the variables.",
+		Example: "  blimp env set FEATURE_CHECKOUT=true\n" +
+			"  blimp env set LOG_LEVEL=debug --services web,worker",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) == 0 {
+				fmt.Fprintln(os.Stderr, "Please specify the variables to set, such as FEATURE_CHECKOUT=true.")
+				os.Exit(1)
+			}
+
+			if err := runSet(args, services); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().StringSliceVar(&services, "services", nil,
+		"The services to set the variables in. Defaults to all services.")
+	return cobraCmd
+}
+
+func newUnsetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset KEY...",
+		Short: "Remove environment variables set with `blimp env set`",
+		Long: "Remove environment variables set with `blimp env set`. Services go back " +
+			"to using the values in the Docker Compose file.",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) == 0 {
+				fmt.Fprintln(os.Stderr, "Please specify the variables to remove.")
+				os.Exit(1)
+			}
+
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			resp, err := manager.C.UnsetSandboxEnv(context.Background(), &cluster.UnsetSandboxEnvRequest{
+				Auth:  blimpConfig.BlimpAuth(),
+				Names: args,
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			fmt.Printf("Removed %s\n", strings.Join(args, ", "))
+			printRestarted(resp.GetRestartedServices())
+		},
+	}
+}
+
+func runSet(args, services []string) error {
+	var env []*cluster.SandboxEnvVar
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return errors.NewFriendlyError(
+				"Invalid variable %q. Variables should be formatted like KEY=VALUE.", arg)
+		}
+
+		env = append(env, &cluster.SandboxEnvVar{
+			Name:     arg[:i],
+			Value:    arg[i+1:],
+			Services: services,
+		})
+	}
+
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.SetSandboxEnv(context.Background(), &cluster.SetSandboxEnvRequest{
+		Auth: blimpConfig.BlimpAuth(),
+		Env:  env,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, v := range env {
+		fmt.Printf("Set %s=%s\n", v.GetName(), v.GetValue())
+	}
+	printRestarted(resp.GetRestartedServices())
+	return nil
+}
+
+func printRestarted(services []string) {
+	if len(services) != 0 {
+		fmt.Printf("Restarted services: %s\n", strings.Join(services, ", "))
+	}
+}
+
+// EnvVar is the schema for the machine-readable output of `blimp env`.
+type EnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+
+	// Services is empty if the variable is set in all services.
+	Services []string `json:"services"`
+}
+
+func runList(outputFormat output.Format) error {
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.ListSandboxEnv(context.Background(), &cluster.ListSandboxEnvRequest{
+		Auth: blimpConfig.BlimpAuth(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if outputFormat != output.Text {
+		out := []EnvVar{}
+		for _, v := range resp.GetEnv() {
+			out = append(out, EnvVar{
+				Name:     v.GetName(),
+				Value:    v.GetValue(),
+				Services: v.GetServices(),
+			})
+		}
+		return output.Print(outputFormat, out)
+	}
+
+	if len(resp.GetEnv()) == 0 {
+		fmt.Println("No variables have been set. Set one with `blimp env set KEY=VALUE`.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVALUE\tSERVICES")
+	for _, v := range resp.GetEnv() {
+		services := "all"
+		if len(v.GetServices()) != 0 {
+			services = strings.Join(v.GetServices(), ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", v.GetName(), v.GetValue(), services)
+	}
+	return w.Flush()
+}
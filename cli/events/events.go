@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:   "events [SERVICE ...]",
+		Short: "Stream the lifecycle events of services in the cloud sandbox",
+		Long: "Stream the lifecycle events of services in the cloud sandbox, such as " +
+			"image pulls, container starts, and crashes.\n\n" +
+			"If no services are provided, the events of all services are printed. " +
+			"Only events that happen after the command starts are printed.\n\n" +
+			"With --output json, each event is printed as a JSON object on its own line.",
+		Run: func(_ *cobra.Command, args []string) {
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			if err := run(blimpConfig, args, outputFormat); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	output.AddFlag(cobraCmd, &outputFormat)
+	return cobraCmd
+}
+
+// Event is the schema for the machine-readable output of `blimp events`.
+type Event struct {
+	// Type is the type of the event, such as "IMAGE_PULL_STARTED" or
+	// "CONTAINER_CRASHED".
+	Type    string `json:"type"`
+	Service string `json:"service"`
+
+	// Time is the Unix timestamp of when the event happened.
+	Time    int64  `json:"time"`
+	Message string `json:"message"`
+
+	// ExitCode is only set for events about the container exiting.
+	ExitCode *int32 `json:"exitCode,omitempty"`
+}
+
+func run(blimpConfig config.Config, services []string, outputFormat output.Format) error {
+	// Stop streaming when the user Ctrl-C's.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		cancel()
+	}()
+
+	stream, err := manager.C.WatchEvents(ctx, &cluster.WatchEventsRequest{
+		Auth:     blimpConfig.BlimpAuth(),
+		Services: services,
+	})
+	if err != nil {
+		return errors.WithContext("watch events", err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err := errors.Unmarshal(err, resp.GetError()); err != nil {
+			return errors.WithContext("receive events", err)
+		}
+
+		for _, event := range resp.GetEvents() {
+			if err := printEvent(outputFormat, toEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toEvent(event *cluster.LifecycleEvent) Event {
+	out := Event{
+		Type:    event.GetType().String(),
+		Service: event.GetService(),
+		Time:    event.GetTime(),
+		Message: event.GetMessage(),
+	}
+
+	switch event.GetType() {
+	case cluster.LifecycleEvent_CONTAINER_EXITED,
+		cluster.LifecycleEvent_CONTAINER_CRASHED,
+		cluster.LifecycleEvent_CONTAINER_OOM_KILLED:
+		exitCode := event.GetExitCode()
+		out.ExitCode = &exitCode
+	}
+	return out
+}
+
+func printEvent(outputFormat output.Format, event Event) error {
+	switch outputFormat {
+	case output.Text:
+		fmt.Printf("%s  %s  %s\n",
+			time.Unix(event.Time, 0).Format("15:04:05"), event.Service, event.Message)
+		return nil
+
+	// Print each event on its own line so that the stream can be parsed as
+	// it arrives.
+	case output.JSON:
+		out, err := json.Marshal(event)
+		if err != nil {
+			return errors.WithContext("marshal", err)
+		}
+		fmt.Println(string(out))
+		return nil
+
+	default:
+		fmt.Println("---")
+		return output.Print(outputFormat, event)
+	}
+}
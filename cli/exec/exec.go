@@ -2,13 +2,16 @@ package exec
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh/terminal"
 	core "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
 
 	"github.com/kelda/blimp/cli/config"
 	"github.com/kelda/blimp/cli/manager"
@@ -17,7 +20,7 @@ import (
 )
 
 func New() *cobra.Command {
-	var disableTTY bool
+	var disableTTY, autoStart bool
 	execCmd := cobra.Command{
 		Short: "Run a command in a service",
 		Run: func(_ *cobra.Command, args []string) {
@@ -26,7 +29,7 @@ func New() *cobra.Command {
 				os.Exit(1)
 			}
 
-			if err := run(args[0], args[1], args[2:], !disableTTY); err != nil {
+			if err := run(args[0], args[1], args[2:], !disableTTY, autoStart); err != nil {
 				errors.HandleFatalError(err)
 			}
 		},
@@ -38,25 +41,34 @@ func New() *cobra.Command {
 	}
 	execCmd.Flags().BoolVarP(&disableTTY, "disable-tty", "T", false,
 		"Disable pseudo-tty allocation. By default 'blimp exec' allocates a TTY.")
+	execCmd.Flags().BoolVar(&autoStart, "auto-start", false,
+		"If the service has exited, start it and its dependencies without prompting.")
 	execCmd.Flags().SetInterspersed(false)
 	return &execCmd
 }
 
-func run(svc, cmd string, cmdArguments []string, enableTTY bool) error {
+func run(svc, cmd string, cmdArguments []string, enableTTY, autoStart bool) error {
 	blimpConfig, err := config.GetConfig()
 	if err != nil {
 		return err
 	}
 
-	// Make sure the pod is actually booted.
+	// Make sure the pod is actually booted. If it exited, offer to start it
+	// rather than failing.
 	err = manager.CheckServiceRunning(svc, blimpConfig.BlimpAuth())
 	if err != nil {
-		return err
-	}
+		stopped, stoppedErr := manager.IsServiceStopped(svc, blimpConfig.BlimpAuth())
+		if stoppedErr != nil || !stopped {
+			return err
+		}
 
-	kubeClient, restConfig, err := blimpConfig.Auth.KubeClient()
-	if err != nil {
-		return errors.WithContext("get kube client", err)
+		if !autoStart && !confirmStart(svc) {
+			return err
+		}
+
+		if err := manager.StartService(svc, blimpConfig.BlimpAuth()); err != nil {
+			return err
+		}
 	}
 
 	// Put the terminal into raw mode to prevent it echoing characters twice.
@@ -72,18 +84,39 @@ func run(svc, cmd string, cmdArguments []string, enableTTY bool) error {
 		}()
 	}
 
-	execOpts := core.PodExecOptions{
-		Command: append([]string{cmd}, cmdArguments...),
-		Stdin:   true,
-		Stdout:  true,
-		Stderr:  true,
-		TTY:     tty,
-	}
-	streamOpts := remotecommand.StreamOptions{
+	return stream(blimpConfig, svc, append([]string{cmd}, cmdArguments...), remotecommand.StreamOptions{
 		Stdin:  os.Stdin,
 		Stdout: os.Stdout,
 		Stderr: os.Stderr,
 		Tty:    tty,
+	})
+}
+
+// RunCommand runs a non-interactive command in a running service, and returns
+// its exit code.
+func RunCommand(blimpConfig config.Config, svc string, command []string, stdout, stderr io.Writer) (int, error) {
+	err := stream(blimpConfig, svc, command, remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if exitErr, ok := errors.RootCause(err).(utilexec.ExitError); ok {
+		return exitErr.ExitStatus(), nil
+	}
+	return 0, err
+}
+
+func stream(blimpConfig config.Config, svc string, command []string, streamOpts remotecommand.StreamOptions) error {
+	kubeClient, restConfig, err := blimpConfig.Auth.KubeClient()
+	if err != nil {
+		return errors.WithContext("get kube client", err)
+	}
+
+	execOpts := core.PodExecOptions{
+		Command: command,
+		Stdin:   streamOpts.Stdin != nil,
+		Stdout:  true,
+		Stderr:  true,
+		TTY:     streamOpts.Tty,
 	}
 
 	req := kubeClient.CoreV1().RESTClient().Post().
@@ -103,3 +136,17 @@ func run(svc, cmd string, cmdArguments []string, enableTTY bool) error {
 	}
 	return nil
 }
+
+// confirmStart asks the user whether to start the exited service. It returns
+// false if there's no terminal to prompt on.
+func confirmStart(svc string) bool {
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return false
+	}
+
+	fmt.Printf("%s has exited. Start it and any of its dependencies that have exited? (y/N) ", svc)
+	var response string
+	num, err := fmt.Scanln(&response)
+	return err == nil && num == 1 &&
+		(strings.ToLower(response) == "y" || strings.ToLower(response) == "yes")
+}
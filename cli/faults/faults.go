@@ -0,0 +1,246 @@
+package faults
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/faults"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:   "faults",
+		Short: "Inject network faults between services",
+		Long: "Inject network faults between pairs of services, such as dropping " +
+			"5% of the packets sent from api to db, or adding 300ms of latency to " +
+			"requests from web to api.\n\n" +
+			"Faults can also be declared in the Docker Compose file with the " +
+			"x-blimp.faults field. Faults added with this command override the " +
+			"faults in the Compose file for the same pair of services, and last " +
+			"until they're removed.",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runList(outputFormat); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	output.AddFlag(cobraCmd, &outputFormat)
+	cobraCmd.AddCommand(newAddCommand(), newRemoveCommand(), newClearCommand())
+	return cobraCmd
+}
+
+func newAddCommand() *cobra.Command {
+	var drop, delay string
+	cobraCmd := &cobra.Command{
+		Use:   "add FROM TO",
+		Short: "Degrade the traffic sent from one service to another",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 2 {
+				fmt.Fprintln(os.Stderr, "Please specify the source and destination services. For example,\n"+
+					"to drop 5% of the packets from api to db, run `blimp faults add api db --drop 5%`.")
+				os.Exit(1)
+			}
+
+			rule := faults.Rule{From: args[0], To: args[1]}
+			if drop != "" {
+				var err error
+				rule.DropPercent, err = faults.ParsePercent(drop)
+				if err != nil {
+					errors.HandleFatalError(err)
+				}
+			}
+
+			if delay != "" {
+				var err error
+				rule.Delay, err = time.ParseDuration(delay)
+				if err != nil {
+					errors.HandleFatalError(errors.NewFriendlyError(
+						"Invalid delay %q. Delays should be formatted like \"300ms\".", delay))
+				}
+			}
+
+			if err := rule.Validate(); err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			err := updateRuntimeRules(func(rules []faults.Rule) []faults.Rule {
+				return append(removeRule(rules, rule.From, rule.To), rule)
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			fmt.Printf("Added fault %s\n", rule)
+		},
+	}
+	cobraCmd.Flags().StringVar(&drop, "drop", "", "The percentage of packets to drop, such as 5%.")
+	cobraCmd.Flags().StringVar(&delay, "delay", "", "The latency to add to each packet, such as 300ms.")
+	return cobraCmd
+}
+
+func newRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm FROM TO",
+		Short: "Remove a fault added with `blimp faults add`",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 2 {
+				fmt.Fprintln(os.Stderr, "Please specify the source and destination services.")
+				os.Exit(1)
+			}
+
+			err := updateRuntimeRules(func(rules []faults.Rule) []faults.Rule {
+				return removeRule(rules, args[0], args[1])
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			fmt.Printf("Removed faults from %s to %s\n", args[0], args[1])
+		},
+	}
+}
+
+func newClearCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all faults added with `blimp faults add`",
+		Long: "Remove all faults added with `blimp faults add`. Faults declared in " +
+			"the Docker Compose file remain active.",
+		Run: func(_ *cobra.Command, _ []string) {
+			err := updateRuntimeRules(func([]faults.Rule) []faults.Rule {
+				return nil
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			fmt.Println("Cleared all faults added with `blimp faults add`.")
+		},
+	}
+}
+
+// Fault is the schema for the machine-readable output of `blimp faults`.
+type Fault struct {
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	DropPercent float64 `json:"dropPercent,omitempty"`
+
+	// Delay is formatted as a duration, such as "300ms".
+	Delay string `json:"delay,omitempty"`
+
+	// Source is either "runtime" for faults added with `blimp faults add`,
+	// or "compose" for faults declared in the Docker Compose file.
+	Source string `json:"source"`
+
+	// Overridden is true for faults from the Docker Compose file that are
+	// replaced by a runtime fault.
+	Overridden bool `json:"overridden,omitempty"`
+}
+
+func runList(outputFormat output.Format) error {
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.GetFaults(context.Background(), &cluster.GetFaultsRequest{
+		Auth: blimpConfig.BlimpAuth(),
+	})
+	if err != nil {
+		return err
+	}
+
+	composeRules := faults.FromProtobuf(resp.GetComposeRules())
+	runtimeRules := faults.FromProtobuf(resp.GetRuntimeRules())
+	overridden := map[string]struct{}{}
+	for _, rule := range runtimeRules {
+		overridden[rule.From+"->"+rule.To] = struct{}{}
+	}
+
+	if outputFormat != output.Text {
+		out := []Fault{}
+		for _, rule := range runtimeRules {
+			out = append(out, toFault(rule, "runtime", false))
+		}
+		for _, rule := range composeRules {
+			_, isOverridden := overridden[rule.From+"->"+rule.To]
+			out = append(out, toFault(rule, "compose", isOverridden))
+		}
+		return output.Print(outputFormat, out)
+	}
+
+	if len(composeRules) == 0 && len(runtimeRules) == 0 {
+		fmt.Println("No faults are active.")
+		return nil
+	}
+
+	if len(runtimeRules) != 0 {
+		fmt.Println("Added with `blimp faults add`:")
+		for _, rule := range runtimeRules {
+			fmt.Printf("  %s\n", rule)
+		}
+	}
+
+	if len(composeRules) != 0 {
+		fmt.Println("From the Docker Compose file:")
+		for _, rule := range composeRules {
+			if _, ok := overridden[rule.From+"->"+rule.To]; ok {
+				fmt.Printf("  %s (overridden)\n", rule)
+			} else {
+				fmt.Printf("  %s\n", rule)
+			}
+		}
+	}
+	return nil
+}
+
+func toFault(rule faults.Rule, source string, overridden bool) Fault {
+	fault := Fault{
+		From:        rule.From,
+		To:          rule.To,
+		DropPercent: rule.DropPercent,
+		Source:      source,
+		Overridden:  overridden,
+	}
+	if rule.Delay != 0 {
+		fault.Delay = rule.Delay.String()
+	}
+	return fault
+}
+
+func updateRuntimeRules(update func([]faults.Rule) []faults.Rule) error {
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.GetFaults(context.Background(), &cluster.GetFaultsRequest{
+		Auth: blimpConfig.BlimpAuth(),
+	})
+	if err != nil {
+		return err
+	}
+
+	rules := update(faults.FromProtobuf(resp.GetRuntimeRules()))
+	_, err = manager.C.SetFaults(context.Background(), &cluster.SetFaultsRequest{
+		Auth:         blimpConfig.BlimpAuth(),
+		RuntimeRules: faults.ToProtobuf(rules),
+	})
+	return err
+}
+
+func removeRule(rules []faults.Rule, from, to string) (filtered []faults.Rule) {
+	for _, rule := range rules {
+		if rule.From != from || rule.To != to {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered
+}
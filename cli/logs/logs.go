@@ -17,14 +17,18 @@ import (
 	"github.com/buger/goterm"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/kelda/blimp/cli/config"
 	"github.com/kelda/blimp/cli/manager"
 	"github.com/kelda/blimp/pkg/errors"
 	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/cluster"
 )
 
 type Command struct {
@@ -32,6 +36,14 @@ type Command struct {
 	Opts     corev1.PodLogOptions
 	Config   config.Config
 
+	// Since limits the logs to those logged within the duration, if it's
+	// non-zero.
+	Since time.Duration
+
+	// Tail limits the logs to the most recent lines of each service, if it's
+	// set.
+	Tail *int64
+
 	svcStatus map[string]*statusNotifier
 }
 
@@ -69,6 +81,7 @@ type parsedLogLine struct {
 
 func New() *cobra.Command {
 	cmd := &Command{}
+	var tail int64
 
 	cobraCmd := &cobra.Command{
 		Use:   "logs SERVICE ...",
@@ -88,6 +101,9 @@ func New() *cobra.Command {
 
 			cmd.Config = blimpConfig
 			cmd.Services = args
+			if tail >= 0 {
+				cmd.Tail = &tail
+			}
 			if err := cmd.Run(context.Background()); err != nil {
 				errors.HandleFatalError(err)
 			}
@@ -97,7 +113,14 @@ func New() *cobra.Command {
 	cobraCmd.Flags().BoolVarP(&cmd.Opts.Follow, "follow", "f", false,
 		"Specify if the logs should be streamed.")
 	cobraCmd.Flags().BoolVarP(&cmd.Opts.Previous, "previous", "p", false,
-		"If true, print the logs for the previous instance of the container if it crashed.")
+		"If true, print the logs for the previous instance of the container if it crashed.\n"+
+			"For scheduled services, print the logs for the run before the latest one.")
+	cobraCmd.Flags().DurationVar(&cmd.Since, "since", 0,
+		"Only print the logs newer than a relative duration like 10m or 1h.\n"+
+			"Includes the logs from before the service restarted, if the cluster retains them.")
+	cobraCmd.Flags().Int64Var(&tail, "tail", -1,
+		"The number of recent lines to print for each service. All lines are printed if it's negative.\n"+
+			"Includes the logs from before the service restarted, if the cluster retains them.")
 
 	return cobraCmd
 }
@@ -128,6 +151,42 @@ func (cmd Command) Run(ctx context.Context) error {
 		cancel()
 	}()
 
+	hideServiceName := len(cmd.Services) == 1
+
+	// If the logs are limited by --since or --tail, read the logs retained
+	// by the manager, since they include the logs from before the services
+	// restarted. The logs are then followed from the containers, starting
+	// after the last retained line.
+	streamSince := map[string]time.Time{}
+	var buffered bool
+	if (cmd.Since != 0 || cmd.Tail != nil) && !cmd.Opts.Previous {
+		var bufferedLogs []rawLogLine
+		bufferedLogs, streamSince, buffered, err = cmd.getBufferedLogs()
+		if err != nil {
+			return err
+		}
+
+		if buffered {
+			if err := printBufferedLogs(ctx, bufferedLogs, hideServiceName); err != nil {
+				return err
+			}
+
+			if !cmd.Opts.Follow {
+				return nil
+			}
+		}
+	}
+
+	// If the manager doesn't retain logs, Kubernetes can still limit the
+	// logs of the services' current containers.
+	if !buffered {
+		if cmd.Since != 0 {
+			sinceSeconds := int64(cmd.Since.Seconds())
+			cmd.Opts.SinceSeconds = &sinceSeconds
+		}
+		cmd.Opts.TailLines = cmd.Tail
+	}
+
 	if cmd.Opts.Follow {
 		if err := cmd.startStatusUpdater(ctx); err != nil {
 			return errors.WithContext("start status updater", err)
@@ -153,7 +212,7 @@ func (cmd Command) Run(ctx context.Context) error {
 	for _, service := range cmd.Services {
 		go func(service string) {
 			for {
-				err := cmd.forwardLogs(ctx, combinedLogs, service, kubeClient)
+				err := cmd.forwardLogs(ctx, combinedLogs, service, kubeClient, streamSince[service])
 				if err != nil && errors.RootCause(err) != io.EOF && err != context.Canceled {
 					log.WithError(err).Debug("Dirty logs termination")
 				}
@@ -204,16 +263,130 @@ func (cmd Command) Run(ctx context.Context) error {
 		cancel()
 	}()
 
-	hideServiceName := len(cmd.Services) == 1
 	return printLogs(ctx, combinedLogs, hideServiceName)
 }
 
+// getBufferedLogs returns the logs that the manager retained for the
+// services, sorted by when they were logged. It also returns when each
+// service's logs should be followed from, so that the retained lines aren't
+// printed twice. It returns false if the manager doesn't retain logs.
+func (cmd Command) getBufferedLogs() ([]rawLogLine, map[string]time.Time, bool, error) {
+	fetchedAt := time.Now()
+	var since int64
+	if cmd.Since != 0 {
+		since = fetchedAt.Add(-cmd.Since).UnixNano()
+	}
+
+	var tail int32
+	if cmd.Tail != nil {
+		tail = int32(*cmd.Tail)
+	}
+
+	var logs []rawLogLine
+	var loggedAt []time.Time
+	streamSince := map[string]time.Time{}
+	for _, service := range cmd.Services {
+		// Print nothing for --tail 0, rather than all the lines.
+		if cmd.Tail != nil && *cmd.Tail == 0 {
+			streamSince[service] = fetchedAt
+			continue
+		}
+
+		resp, err := getRetainedLogs(&cluster.GetLogsRequest{
+			Auth:    cmd.Config.BlimpAuth(),
+			Service: service,
+			Since:   since,
+			Tail:    tail,
+		})
+		if err != nil {
+			// Older managers don't retain logs.
+			if status.Code(err) == codes.Unimplemented {
+				return nil, nil, false, nil
+			}
+			return nil, nil, false, errors.WithContext("get retained logs", err)
+		}
+
+		if !resp.GetBuffered() {
+			return nil, nil, false, nil
+		}
+
+		streamSince[service] = fetchedAt
+		for _, line := range resp.GetLines() {
+			lineTime := time.Unix(0, line.GetTime()).UTC()
+			logs = append(logs, rawLogLine{
+				fromContainer: service,
+				// Format the line like Kubernetes does so that it can be
+				// parsed by printLogs.
+				message:    lineTime.Format(time.RFC3339Nano) + " " + line.GetMessage(),
+				receivedAt: fetchedAt,
+			})
+			loggedAt = append(loggedAt, lineTime)
+			streamSince[service] = lineTime
+		}
+	}
+
+	// Interleave the services' logs.
+	sort.Stable(byLoggedAt{logs, loggedAt})
+	return logs, streamSince, true, nil
+}
+
+// getRetainedLogs fetches the logs retained by the manager. The logs are
+// streamed in chunks so that they don't exceed the maximum message size.
+func getRetainedLogs(req *cluster.GetLogsRequest) (*cluster.GetLogsResponse, error) {
+	stream, err := manager.C.StreamLogs(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &cluster.GetLogsResponse{}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return merged, nil
+		}
+		if err != nil {
+			// Fall back to GetLogs for managers that don't support
+			// streaming logs.
+			if status.Code(err) == codes.Unimplemented {
+				return manager.C.GetLogs(context.Background(), req)
+			}
+			return nil, err
+		}
+
+		merged.Buffered = resp.GetBuffered()
+		merged.Lines = append(merged.Lines, resp.GetLines()...)
+	}
+}
+
+type byLoggedAt struct {
+	logs     []rawLogLine
+	loggedAt []time.Time
+}
+
+func (l byLoggedAt) Len() int           { return len(l.logs) }
+func (l byLoggedAt) Less(i, j int) bool { return l.loggedAt[i].Before(l.loggedAt[j]) }
+func (l byLoggedAt) Swap(i, j int) {
+	l.logs[i], l.logs[j] = l.logs[j], l.logs[i]
+	l.loggedAt[i], l.loggedAt[j] = l.loggedAt[j], l.loggedAt[i]
+}
+
+func printBufferedLogs(ctx context.Context, logs []rawLogLine, hideServiceName bool) error {
+	logsChan := make(chan rawLogLine, len(logs))
+	for _, line := range logs {
+		logsChan <- line
+	}
+	close(logsChan)
+	return printLogs(ctx, logsChan, hideServiceName)
+}
+
 // forwardLogs forwards each log line from `logsReq` to the `combinedLogs`
-// channel. If we are following logs, this function should only return if the
+// channel. If `since` is set, only the lines logged after it are forwarded.
+// If we are following logs, this function should only return if the
 // container exits.
 func (cmd *Command) forwardLogs(ctx context.Context, combinedLogs chan<- rawLogLine,
-	service string, kubeClient kubernetes.Interface) error {
-	var lastMessageTime, sinceTime time.Time
+	service string, kubeClient kubernetes.Interface, since time.Time) error {
+	lastMessageTime := since
+	var sinceTime time.Time
 
 	isOldMessage := func(message string) bool {
 		if message == "" {
@@ -248,6 +421,9 @@ func (cmd *Command) forwardLogs(ctx context.Context, combinedLogs chan<- rawLogL
 		opts := cmd.Opts
 		// Enable timestamps so that `forwardLogs` can parse the logs.
 		opts.Timestamps = true
+		// Select the service's container, since pods may also contain
+		// sidecars added by Blimp.
+		opts.Container = names.ToDNS1123(service)
 		// If we are reconnecting, set SinceTime so we don't double-print logs.
 		if !lastMessageTime.IsZero() {
 			// The SinceTime parameter only has second-level resolution, which
@@ -256,11 +432,19 @@ func (cmd *Command) forwardLogs(ctx context.Context, combinedLogs chan<- rawLogL
 			sinceTime = lastMessageTime
 			metaSinceTime := metav1.NewTime(lastMessageTime)
 			opts.SinceTime = &metaSinceTime
+			opts.SinceSeconds = nil
+			opts.TailLines = nil
 		}
 
+		podName, previous, err := cmd.getPodName(kubeClient, service)
+		if err != nil {
+			return err
+		}
+		opts.Previous = previous
+
 		logsReq := kubeClient.CoreV1().
 			Pods(cmd.Config.Auth.KubeNamespace).
-			GetLogs(names.ToDNS1123(service), &opts)
+			GetLogs(podName, &opts)
 
 		logsStream, err := logsReq.Stream()
 		if err != nil {
@@ -327,6 +511,39 @@ func (cmd *Command) forwardLogs(ctx context.Context, combinedLogs chan<- rawLogL
 	}
 }
 
+// getPodName returns the pod to get the service's logs from, and whether the
+// logs should be from the previous instance of the container. Each run of a
+// scheduled service is in a different pod, so for scheduled services,
+// --previous selects the run before the latest one instead.
+func (cmd *Command) getPodName(kubeClient kubernetes.Interface, service string) (string, bool, error) {
+	runs, err := kubeClient.CoreV1().Pods(cmd.Config.Auth.KubeNamespace).List(metav1.ListOptions{
+		LabelSelector: labels.Set{
+			"blimp.scheduled": "true",
+			"blimp.service":   service,
+		}.String(),
+	})
+	if err != nil {
+		return "", false, errors.WithContext("list scheduled runs", err)
+	}
+
+	if len(runs.Items) == 0 {
+		return names.ToDNS1123(service), cmd.Opts.Previous, nil
+	}
+
+	sort.Slice(runs.Items, func(i, j int) bool {
+		return runs.Items[j].CreationTimestamp.Before(&runs.Items[i].CreationTimestamp)
+	})
+
+	run := 0
+	if cmd.Opts.Previous {
+		run = 1
+	}
+	if run >= len(runs.Items) {
+		return "", false, errors.NewFriendlyError("%s hasn't finished a previous run.", service)
+	}
+	return runs.Items[run].Name, false, nil
+}
+
 func printStatusMessage(service, message string, hideServiceName bool) {
 	servicePrefix := ""
 	if !hideServiceName {
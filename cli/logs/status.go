@@ -88,6 +88,7 @@ func (p *statusNotifier) UpdatePhase(phase cluster.ServicePhase) {
 
 func phaseExited(phase cluster.ServicePhase) bool {
 	return phase == cluster.ServicePhase_EXITED ||
+		phase == cluster.ServicePhase_SCHEDULED ||
 		phase == cluster.ServicePhase_UNKNOWN
 }
 
@@ -101,7 +102,8 @@ func (cmd *Command) startStatusUpdater(ctx context.Context) error {
 	// Fetch initial statuses.
 	fetchCtx, _ := context.WithTimeout(ctx, 15*time.Second)
 	initStatus, err := manager.C.GetStatus(fetchCtx, &cluster.GetStatusRequest{
-		Auth: cmd.Config.BlimpAuth(),
+		Auth:     cmd.Config.BlimpAuth(),
+		Services: cmd.Services,
 	})
 	if err != nil {
 		return errors.WithContext("logs fetch initial statuses", err)
@@ -148,13 +150,15 @@ func (cmd *Command) startStatusUpdater(ctx context.Context) error {
 }
 
 func watchStatus(ctx context.Context, statuses map[string]*statusNotifier, auth *auth.BlimpAuth) error {
-	stream, err := manager.C.WatchStatus(ctx, &cluster.GetStatusRequest{
-		Auth: auth,
-	})
-	if err != nil {
-		return errors.WithContext("watch status", err)
+	var services []string
+	for svc := range statuses {
+		services = append(services, svc)
 	}
 
+	stream := manager.WatchStatus(ctx, manager.C, &cluster.GetStatusRequest{
+		Auth:     auth,
+		Services: services,
+	})
 	for {
 		msg, err := stream.Recv()
 		if err != nil {
@@ -3,22 +3,41 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/buger/goterm"
 	"github.com/spf13/cobra"
 
+	"github.com/kelda/blimp/cli/addons"
+	"github.com/kelda/blimp/cli/analytics"
+	"github.com/kelda/blimp/cli/boost"
 	"github.com/kelda/blimp/cli/bugtool"
 	"github.com/kelda/blimp/cli/build"
+	"github.com/kelda/blimp/cli/completion"
+	"github.com/kelda/blimp/cli/composeconfig"
 	"github.com/kelda/blimp/cli/cp"
 	"github.com/kelda/blimp/cli/down"
+	"github.com/kelda/blimp/cli/env"
+	"github.com/kelda/blimp/cli/events"
 	"github.com/kelda/blimp/cli/exec"
 	"github.com/kelda/blimp/cli/expose"
+	"github.com/kelda/blimp/cli/faults"
 	"github.com/kelda/blimp/cli/logs"
 	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/pause"
 	"github.com/kelda/blimp/cli/ps"
 	"github.com/kelda/blimp/cli/restart"
+	"github.com/kelda/blimp/cli/scaffold"
+	"github.com/kelda/blimp/cli/scan"
+	"github.com/kelda/blimp/cli/share"
 	"github.com/kelda/blimp/cli/ssh"
+	"github.com/kelda/blimp/cli/sync"
+	"github.com/kelda/blimp/cli/test"
+	"github.com/kelda/blimp/cli/token"
+	"github.com/kelda/blimp/cli/tunnel"
 	"github.com/kelda/blimp/cli/up"
+	"github.com/kelda/blimp/cli/usage"
+	"github.com/kelda/blimp/cli/util"
 	"github.com/kelda/blimp/pkg/cfgdir"
 	"github.com/kelda/blimp/pkg/errors"
 
@@ -48,20 +67,61 @@ func main() {
 		SilenceErrors: true,
 	}
 	rootCmd.AddCommand(
+		addons.New(),
+		boost.New(),
 		bugtool.New(),
 		build.New(),
+		up.NewCICommand(),
+		completion.New(),
+		composeconfig.New(),
 		cp.New(),
 		down.New(),
+		env.New(),
+		events.New(),
 		exec.New(),
 		expose.New(),
+		faults.New(),
 		logs.New(),
+		pause.New(),
 		ps.New(),
 		restart.New(),
+		pause.NewResumeCommand(),
+		scaffold.New(),
+		scan.New(),
+		share.New(),
+		up.NewSnapshotCommand(),
 		ssh.New(),
+		sync.New(),
+		test.New(),
+		token.New(),
+		tunnel.New(),
 		up.New(),
+		usage.New(),
 	)
 
-	if err := rootCmd.Execute(); err != nil {
+	// Analytics are best effort, so an invalid config just disables them.
+	// setup reports the error for commands that require the config.
+	if cfg, err := cfgdir.ParseConfig(); err == nil {
+		analytics.Init(cfg)
+	}
+
+	// Only record the command's name, since its arguments may contain file
+	// paths.
+	commandPath := rootCmd.CommandPath()
+	if cmd, _, err := rootCmd.Find(os.Args[1:]); err == nil {
+		commandPath = cmd.CommandPath()
+	}
+
+	start := time.Now()
+	errors.OnFatalError(func(err error) {
+		analytics.RecordCommand(commandPath, time.Since(start), err)
+		analytics.Flush()
+	})
+
+	err := rootCmd.Execute()
+	analytics.RecordCommand(commandPath, time.Since(start), err)
+	analytics.Flush()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -73,6 +133,9 @@ func setup(cmd *cobra.Command, _ []string) {
 	if err != nil {
 		log.WithError(err).Fatal("Failed to read blimp config")
 	}
+	if err := util.ConfigureTransport(cfg); err != nil {
+		errors.HandleFatalError(err)
+	}
 
 	if err := manager.SetupClient(cfg.ManagerHost, cfg.ManagerCert); err != nil {
 		log.WithError(err).Fatal("Failed to connect to the Blimp cluster")
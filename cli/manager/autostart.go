@@ -0,0 +1,130 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kelda/blimp/cli/util"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// startTimeout is how long StartService waits for the service to boot.
+const startTimeout = 5 * time.Minute
+
+// IsServiceStopped returns whether the service was deployed, but has since
+// exited.
+func IsServiceStopped(svc string, auth *auth.BlimpAuth) (bool, error) {
+	statusResp, err := C.GetStatus(context.Background(), &cluster.GetStatusRequest{
+		Auth:     auth,
+		Services: []string{svc},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	status := statusResp.GetStatus()
+	if status.GetPhase() != cluster.SandboxStatus_RUNNING {
+		return false, nil
+	}
+
+	svcStatus, ok := status.GetServices()[svc]
+	return ok && svcStatus.GetPhase() == cluster.ServicePhase_EXITED, nil
+}
+
+// StartService restarts the given service, along with any of the services it
+// depends on that have exited. It blocks until the service is running.
+func StartService(svc string, auth *auth.BlimpAuth) error {
+	graphResp, err := C.GetDependencyGraph(context.Background(), &cluster.GetDependencyGraphRequest{
+		Auth: auth,
+	})
+	if err != nil {
+		return errors.WithContext("get dependencies", err)
+	}
+
+	nodes := map[string]*cluster.ServiceNode{}
+	for _, node := range graphResp.GetServices() {
+		nodes[node.Name] = node
+	}
+
+	// Order the services so that dependencies are started before the
+	// services that depend on them.
+	var toStart []string
+	visited := map[string]bool{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+
+		node, ok := nodes[name]
+		if !ok || node.GetStatus() == nil {
+			return errors.NewFriendlyError("The service %q isn't deployed. "+
+				"Please run `blimp up` first.", name)
+		}
+
+		for _, dep := range node.DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		if node.GetStatus().GetPhase() == cluster.ServicePhase_EXITED {
+			toStart = append(toStart, name)
+		}
+		return nil
+	}
+	if err := visit(svc); err != nil {
+		return err
+	}
+
+	pp := util.NewProgressPrinter(os.Stdout, fmt.Sprintf("Starting %s", svc))
+	go pp.Run()
+	defer pp.Stop()
+
+	for _, name := range toStart {
+		_, err := C.Restart(context.Background(), &cluster.RestartRequest{
+			Auth:    auth,
+			Service: name,
+		})
+		if err != nil {
+			return errors.WithContext(fmt.Sprintf("start %s", name), err)
+		}
+	}
+
+	return waitForRunning(svc, auth)
+}
+
+func waitForRunning(svc string, auth *auth.BlimpAuth) error {
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	stream := WatchStatus(ctx, C, &cluster.GetStatusRequest{
+		Auth:     auth,
+		Services: []string{svc},
+	})
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return errors.NewFriendlyError("Timed out waiting for %s to start. "+
+					"You can check its status with `blimp ps`.", svc)
+			}
+			return errors.WithContext("status stream recv", err)
+		}
+
+		// The service is booted if it's running, even if it's unhealthy,
+		// since the user may be trying to debug it.
+		switch msg.GetStatus().GetServices()[svc].GetPhase() {
+		case cluster.ServicePhase_RUNNING, cluster.ServicePhase_UNHEALTHY:
+			return nil
+		case cluster.ServicePhase_INIT_TIMEOUT:
+			return errors.NewFriendlyError("%s is stuck waiting to boot: %s",
+				svc, msg.GetStatus().GetServices()[svc].GetMsg())
+		}
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
 
 	"github.com/kelda/blimp/cli/util"
 	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/msgsize"
 	"github.com/kelda/blimp/pkg/proto/auth"
 	"github.com/kelda/blimp/pkg/proto/cluster"
 	"github.com/kelda/blimp/pkg/version"
@@ -16,6 +18,10 @@ import (
 
 var C Client
 
+// managerMaxMessageBytes is the largest request that the manager accepts. It's
+// zero if the manager predates negotiating message sizes.
+var managerMaxMessageBytes int64
+
 type Client struct {
 	cluster.ManagerClient
 	*grpc.ClientConn
@@ -38,7 +44,10 @@ func dial(host, cert, hostname string) (Client, error) {
 	// Since we are manually specifying the exact certificate to use, it should
 	// be okay to override the server name. This simplifies self-hosted
 	// deployments.
-	conn, err := util.Dial(host, cert, hostname)
+	conn, err := util.Dial(host, cert, hostname,
+		grpc.WithChainUnaryInterceptor(msgsize.SendLimitInterceptor(func() int {
+			return int(atomic.LoadInt64(&managerMaxMessageBytes))
+		})))
 	if err != nil {
 		return Client{}, errors.WithContext("dial", err)
 	}
@@ -49,11 +58,13 @@ func dial(host, cert, hostname string) (Client, error) {
 	}
 
 	resp, err := client.CheckVersion(context.Background(), &cluster.CheckVersionRequest{
-		Version: version.Version,
+		Version:         version.Version,
+		MaxMessageBytes: int64(util.MaxMessageBytes()),
 	})
 	if err != nil {
 		return client, errors.WithContext("check version", err)
 	}
+	atomic.StoreInt64(&managerMaxMessageBytes, resp.GetMaxMessageBytes())
 
 	if resp.DisplayMessage != "" {
 		fmt.Println(resp.DisplayMessage)
@@ -80,6 +91,10 @@ func CheckServiceStatus(svc string, auth *auth.BlimpAuth,
 	}
 
 	status := statusResp.GetStatus()
+	if status.GetPhase() == cluster.SandboxStatus_PAUSED {
+		return errors.NewFriendlyError(
+			"Your sandbox is paused. Please run `blimp resume` first.")
+	}
 	if status.GetPhase() != cluster.SandboxStatus_RUNNING {
 		return errors.NewFriendlyError(
 			"Your sandbox is not booted. Please run `blimp up` first.")
@@ -0,0 +1,195 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// maxStreamResets is how many times the status stream can be reset
+	// within streamResetWindow before falling back to polling.
+	maxStreamResets   = 3
+	streamResetWindow = 2 * time.Minute
+
+	// pollWaitSeconds is how long the manager holds each poll open while
+	// waiting for a change.
+	pollWaitSeconds = 20
+
+	// pollRetryInterval is how long to wait before retrying a failed poll.
+	pollRetryInterval = 5 * time.Second
+)
+
+// StatusStream is a stream of status updates. Each update contains the full
+// status of the watched services.
+type StatusStream interface {
+	Recv() (*cluster.GetStatusResponse, error)
+}
+
+// WatchStatus watches the status of the sandbox. Unlike calling
+// client.WatchStatus directly, the stream is transparently reopened if the
+// connection is reset, and resumed so that only the updates that were missed
+// while disconnected are received. If the stream keeps getting reset, such as
+// when a proxy kills long-lived connections, it falls back to polling for
+// status changes.
+// Recv returns the context's error once the context is cancelled.
+func WatchStatus(ctx context.Context, client cluster.ManagerClient, req *cluster.GetStatusRequest) StatusStream {
+	return &statusWatcher{ctx: ctx, client: client, req: req}
+}
+
+type statusWatcher struct {
+	ctx    context.Context
+	client cluster.ManagerClient
+	req    *cluster.GetStatusRequest
+
+	stream      cluster.Manager_WatchStatusClient
+	resets      []time.Time
+	resumeToken string
+
+	polling bool
+	cursor  string
+	status  cluster.SandboxStatus
+}
+
+func (w *statusWatcher) Recv() (*cluster.GetStatusResponse, error) {
+	for {
+		if w.polling {
+			return w.poll()
+		}
+
+		if w.stream == nil {
+			stream, err := w.client.WatchStatus(w.ctx, &cluster.GetStatusRequest{
+				OldToken:    w.req.GetOldToken(),
+				Auth:        w.req.GetAuth(),
+				Services:    w.req.GetServices(),
+				ResumeToken: w.resumeToken,
+			})
+			if err != nil {
+				if err := w.handleStreamError(err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			w.stream = stream
+		}
+
+		msg, err := w.stream.Recv()
+		if err == nil {
+			w.resumeToken = msg.GetResumeToken()
+			return msg, nil
+		}
+
+		w.stream = nil
+		if err := w.handleStreamError(err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// handleStreamError returns nil if the stream should be retried.
+func (w *statusWatcher) handleStreamError(err error) error {
+	if ctxErr := w.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	if !isConnectionReset(err) {
+		return err
+	}
+
+	now := time.Now()
+	var recentResets []time.Time
+	for _, t := range append(w.resets, now) {
+		if now.Sub(t) < streamResetWindow {
+			recentResets = append(recentResets, t)
+		}
+	}
+	w.resets = recentResets
+
+	if len(w.resets) >= maxStreamResets {
+		log.WithError(err).Debug("Status stream keeps getting reset. Falling back to polling.")
+		w.polling = true
+		return nil
+	}
+
+	log.WithError(err).Debug("Status stream reset. Reconnecting.")
+	return w.sleep(time.Second)
+}
+
+// poll blocks until the status changes, and returns the new status.
+func (w *statusWatcher) poll() (*cluster.GetStatusResponse, error) {
+	for {
+		resp, err := w.client.PollStatus(w.ctx, &cluster.PollStatusRequest{
+			Auth:        w.req.GetAuth(),
+			Services:    w.req.GetServices(),
+			Cursor:      w.cursor,
+			WaitSeconds: pollWaitSeconds,
+		})
+		if err != nil {
+			if ctxErr := w.ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+
+			if !isConnectionReset(err) {
+				return nil, errors.WithContext("poll status", err)
+			}
+
+			if err := w.sleep(pollRetryInterval); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.NotModified {
+			continue
+		}
+
+		if w.status.Services == nil {
+			w.status.Services = map[string]*cluster.ServiceStatus{}
+		}
+		w.cursor = resp.Cursor
+		w.status.Phase = resp.Phase
+		for name, svcStatus := range resp.Services {
+			w.status.Services[name] = svcStatus
+		}
+		for _, name := range resp.RemovedServices {
+			delete(w.status.Services, name)
+		}
+
+		// Copy the status so that the caller can hold onto it while we
+		// apply later updates.
+		statusCopy := cluster.SandboxStatus{
+			Phase:    w.status.Phase,
+			Services: map[string]*cluster.ServiceStatus{},
+		}
+		for name, svcStatus := range w.status.Services {
+			statusCopy.Services[name] = svcStatus
+		}
+		return &cluster.GetStatusResponse{Status: &statusCopy}, nil
+	}
+}
+
+func (w *statusWatcher) sleep(d time.Duration) error {
+	select {
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// isConnectionReset returns whether the error was caused by the connection
+// to the manager breaking, rather than by the manager itself.
+func isConnectionReset(err error) bool {
+	switch status.Code(errors.RootCause(err)) {
+	case codes.Unavailable, codes.Internal:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,81 @@
+// Package output prints the results of read commands in machine-readable
+// formats, so that scripts can consume them.
+//
+// The JSON and YAML schemas are part of Blimp's public interface. Fields may
+// be added, but existing fields shouldn't be renamed or removed.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// Format is the format that a command prints its results in.
+type Format string
+
+const (
+	// Text is the default, human-readable format.
+	Text Format = ""
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+// AddFlag adds the --output flag to the command.
+func AddFlag(cmd *cobra.Command, format *Format) {
+	cmd.Flags().VarP((*formatValue)(format), "output", "o",
+		"Print the output in a machine-readable format. Either \"json\" or \"yaml\".")
+}
+
+// Print writes `v` to stdout in the given format. It shouldn't be called
+// with the Text format, since each command formats its own text output.
+func Print(format Format, v interface{}) error {
+	return Fprint(os.Stdout, format, v)
+}
+
+// Fprint writes `v` to `w` in the given format.
+func Fprint(w io.Writer, format Format, v interface{}) error {
+	var out []byte
+	var err error
+	switch format {
+	case JSON:
+		out, err = json.MarshalIndent(v, "", "  ")
+		out = append(out, '\n')
+	case YAML:
+		out, err = yaml.Marshal(v)
+	default:
+		return errors.New("unsupported output format %q", format)
+	}
+	if err != nil {
+		return errors.WithContext("marshal", err)
+	}
+
+	_, err = w.Write(out)
+	return err
+}
+
+type formatValue Format
+
+func (f *formatValue) String() string {
+	return string(*f)
+}
+
+func (f *formatValue) Set(str string) error {
+	switch Format(str) {
+	case JSON, YAML:
+		*f = formatValue(str)
+		return nil
+	default:
+		return fmt.Errorf("must be either \"json\" or \"yaml\"")
+	}
+}
+
+func (f *formatValue) Type() string {
+	return "format"
+}
@@ -0,0 +1,59 @@
+package pause
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pause",
+		Short: "Stop all services without deleting the sandbox",
+		Long: "Stop all services without deleting the sandbox.\n\n" +
+			"The sandbox's volumes and configuration are kept, so `blimp resume` " +
+			"boots the services again in seconds rather than requiring a full " +
+			"`blimp up`. Running `blimp up` also resumes the sandbox.",
+		Run: func(_ *cobra.Command, _ []string) {
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			_, err = manager.C.PauseSandbox(context.Background(), &cluster.PauseSandboxRequest{
+				Auth: blimpConfig.BlimpAuth(),
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			fmt.Println("Paused sandbox. Resume it with `blimp resume`.")
+		},
+	}
+}
+
+func NewResumeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume",
+		Short: "Boot the services in a paused sandbox",
+		Run: func(_ *cobra.Command, _ []string) {
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			_, err = manager.C.ResumeSandbox(context.Background(), &cluster.ResumeSandboxRequest{
+				Auth: blimpConfig.BlimpAuth(),
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			fmt.Println("Resumed sandbox. Check the status of your services with `blimp ps`.")
+		},
+	}
+}
@@ -0,0 +1,172 @@
+package ps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/buger/goterm"
+
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func runGraph(auth *auth.BlimpAuth, format string) error {
+	resp, err := manager.C.GetDependencyGraph(context.Background(), &cluster.GetDependencyGraphRequest{
+		Auth: auth,
+	})
+	if err != nil {
+		return err
+	}
+
+	graph := newDependencyGraph(resp.GetServices())
+	switch format {
+	case "ascii":
+		if len(graph.services) == 0 {
+			fmt.Println("No services found.")
+			return nil
+		}
+		graph.printASCII(os.Stdout)
+	case "dot":
+		graph.printDOT(os.Stdout)
+	default:
+		return errors.NewFriendlyError("Unknown graph format %q. The format must be either \"ascii\" or \"dot\".", format)
+	}
+	return nil
+}
+
+type dependencyGraph struct {
+	services map[string]*cluster.ServiceNode
+
+	// names contains the name of every service, including dependencies that
+	// aren't deployed, in the order they should be printed.
+	names []string
+}
+
+func newDependencyGraph(nodes []*cluster.ServiceNode) dependencyGraph {
+	graph := dependencyGraph{services: map[string]*cluster.ServiceNode{}}
+	for _, node := range nodes {
+		graph.services[node.Name] = node
+		graph.names = append(graph.names, node.Name)
+	}
+
+	// The manager only returns the services that have been deployed, so add
+	// any dependencies that are missing.
+	for _, node := range nodes {
+		for _, dep := range node.DependsOn {
+			if _, ok := graph.services[dep]; !ok {
+				graph.services[dep] = &cluster.ServiceNode{Name: dep}
+				graph.names = append(graph.names, dep)
+			}
+		}
+	}
+	return graph
+}
+
+// roots returns the services that no other services depend on.
+func (graph dependencyGraph) roots() []string {
+	isDependency := map[string]bool{}
+	for _, node := range graph.services {
+		for _, dep := range node.DependsOn {
+			isDependency[dep] = true
+		}
+	}
+
+	var roots []string
+	for _, name := range graph.names {
+		if !isDependency[name] {
+			roots = append(roots, name)
+		}
+	}
+
+	return roots
+}
+
+func (graph dependencyGraph) statusString(name string) (string, int) {
+	node := graph.services[name]
+	if node.Status == nil {
+		return "Not deployed", goterm.RED
+	}
+
+	msg, color, _ := GetStatusString(node.Status)
+	return msg, color
+}
+
+// printASCII prints each service as a tree of the services that it depends
+// on. Services that are depended on by multiple services are only expanded
+// the first time they're printed.
+func (graph dependencyGraph) printASCII(out io.Writer) {
+	expanded := map[string]bool{}
+
+	var printNode func(name, prefix, childPrefix string, ancestors map[string]bool)
+	printNode = func(name, prefix, childPrefix string, ancestors map[string]bool) {
+		status, color := graph.statusString(name)
+		line := fmt.Sprintf("%s%s: %s", prefix, name, goterm.Color(status, color))
+
+		deps := graph.services[name].DependsOn
+		switch {
+		case ancestors[name]:
+			fmt.Fprintln(out, line+" (circular dependency)")
+			return
+		case expanded[name] && len(deps) != 0:
+			fmt.Fprintln(out, line+" (dependencies shown above)")
+			return
+		}
+
+		fmt.Fprintln(out, line)
+		expanded[name] = true
+
+		ancestors[name] = true
+		defer delete(ancestors, name)
+		for i, dep := range deps {
+			if i == len(deps)-1 {
+				printNode(dep, childPrefix+"└── ", childPrefix+"    ", ancestors)
+			} else {
+				printNode(dep, childPrefix+"├── ", childPrefix+"│   ", ancestors)
+			}
+		}
+	}
+
+	for _, root := range graph.roots() {
+		printNode(root, "", "", map[string]bool{})
+	}
+
+	// Print any services that are only reachable through a cycle.
+	for _, name := range graph.names {
+		if !expanded[name] {
+			printNode(name, "", "", map[string]bool{})
+		}
+	}
+}
+
+// printDOT prints the graph in the Graphviz DOT language. Edges point from a
+// service to the services it depends on.
+func (graph dependencyGraph) printDOT(out io.Writer) {
+	fmt.Fprintln(out, "digraph services {")
+	for _, name := range graph.names {
+		status, color := graph.statusString(name)
+		fmt.Fprintf(out, "  %q [label=%q, color=%q];\n",
+			name, fmt.Sprintf("%s\n%s", name, status), dotColor(color))
+	}
+
+	for _, name := range graph.names {
+		for _, dep := range graph.services[name].DependsOn {
+			fmt.Fprintf(out, "  %q -> %q;\n", name, dep)
+		}
+	}
+	fmt.Fprintln(out, "}")
+}
+
+func dotColor(color int) string {
+	switch color {
+	case goterm.GREEN:
+		return "green"
+	case goterm.RED:
+		return "red"
+	default:
+		return "orange"
+	}
+}
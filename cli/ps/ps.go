@@ -12,29 +12,96 @@ import (
 
 	"github.com/kelda/blimp/cli/config"
 	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/output"
 	"github.com/kelda/blimp/pkg/errors"
 	"github.com/kelda/blimp/pkg/proto/auth"
 	"github.com/kelda/blimp/pkg/proto/cluster"
 )
 
 func New() *cobra.Command {
-	return &cobra.Command{
-		Use:   "ps",
-		Short: "Print the status of services in the cloud sandbox",
+	var graphFormat string
+	var showTunnels bool
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:     "ps",
+		Aliases: []string{"status"},
+		Short:   "Print the status of services in the cloud sandbox",
 		Run: func(_ *cobra.Command, args []string) {
+			if showTunnels {
+				if err := runTunnels(outputFormat); err != nil {
+					errors.HandleFatalError(err)
+				}
+				return
+			}
+
 			blimpConfig, err := config.GetConfig()
 			if err != nil {
 				errors.HandleFatalError(err)
 			}
 
-			if err := run(blimpConfig.BlimpAuth()); err != nil {
+			switch {
+			case graphFormat != "" && outputFormat != output.Text:
+				err = errors.NewFriendlyError("The --graph and --output flags can't be used together.")
+			case graphFormat != "":
+				err = runGraph(blimpConfig.BlimpAuth(), graphFormat)
+			default:
+				err = run(blimpConfig.BlimpAuth(), outputFormat)
+			}
+			if err != nil {
 				errors.HandleFatalError(err)
 			}
 		},
 	}
+	cobraCmd.Flags().StringVar(&graphFormat, "graph", "",
+		"Print the dependencies between services, and what each service is waiting on. "+
+			"The format can be either \"ascii\" or \"dot\".")
+	cobraCmd.Flags().Lookup("graph").NoOptDefVal = "ascii"
+	cobraCmd.Flags().BoolVar(&showTunnels, "tunnels", false,
+		"Print the connection statistics for the tunnels run by `blimp up` and `blimp tunnel`.")
+	output.AddFlag(cobraCmd, &outputFormat)
+	return cobraCmd
+}
+
+// Status is the schema for the machine-readable output of `blimp ps`.
+type Status struct {
+	// Sandbox is the phase of the sandbox, such as "RUNNING".
+	Sandbox  string          `json:"sandbox"`
+	Services []ServiceStatus `json:"services"`
+
+	// Warnings are problems that don't stop the sandbox from running, such
+	// as vulnerabilities found in its images.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type ServiceStatus struct {
+	Name string `json:"name"`
+
+	// Project is the Compose project that the service belongs to. It's
+	// empty for services in the default project.
+	Project string `json:"project,omitempty"`
+
+	// Phase is the phase of the service, such as "RUNNING" or "EXITED".
+	Phase      string `json:"phase"`
+	Message    string `json:"message,omitempty"`
+	HasStarted bool   `json:"hasStarted"`
+
+	// ExitCode is only set if the service exited, or if it's a scheduled
+	// service that has finished a run.
+	ExitCode *int32 `json:"exitCode,omitempty"`
+
+	// Schedule is the cron schedule of scheduled services.
+	Schedule string `json:"schedule,omitempty"`
+
+	// LastRunAt is the Unix timestamp of when a scheduled service last
+	// started running.
+	LastRunAt int64 `json:"lastRunAt,omitempty"`
+
+	// MeshSidecarReady is only set if a service mesh injected its proxy
+	// into the service.
+	MeshSidecarReady *bool `json:"meshSidecarReady,omitempty"`
 }
 
-func run(auth *auth.BlimpAuth) error {
+func run(auth *auth.BlimpAuth, outputFormat output.Format) error {
 	status, err := manager.C.GetStatus(context.Background(), &cluster.GetStatusRequest{
 		Auth: auth,
 	})
@@ -42,31 +109,98 @@ func run(auth *auth.BlimpAuth) error {
 		return err
 	}
 
+	if outputFormat != output.Text {
+		return output.Print(outputFormat, toStatus(*status.Status))
+	}
+
 	printStatus(*status.Status)
 	return nil
 }
 
+func toStatus(status cluster.SandboxStatus) Status {
+	out := Status{
+		Sandbox:  status.Phase.String(),
+		Services: []ServiceStatus{},
+		Warnings: status.Warnings,
+	}
+	for name, svcStatus := range status.Services {
+		svcOut := ServiceStatus{
+			Name:       name,
+			Project:    svcStatus.Project,
+			Phase:      svcStatus.Phase.String(),
+			Message:    svcStatus.Msg,
+			HasStarted: svcStatus.HasStarted,
+			Schedule:   svcStatus.Schedule,
+			LastRunAt:  svcStatus.LastRunAt,
+		}
+		if svcStatus.Phase == cluster.ServicePhase_EXITED || svcStatus.LastRunAt != 0 {
+			exitCode := svcStatus.ExitCode
+			svcOut.ExitCode = &exitCode
+		}
+		if svcStatus.Mesh != nil {
+			sidecarReady := svcStatus.Mesh.SidecarReady
+			svcOut.MeshSidecarReady = &sidecarReady
+		}
+		out.Services = append(out.Services, svcOut)
+	}
+	sort.Slice(out.Services, func(i, j int) bool {
+		if out.Services[i].Project != out.Services[j].Project {
+			return out.Services[i].Project < out.Services[j].Project
+		}
+		return out.Services[i].Name < out.Services[j].Name
+	})
+	return out
+}
+
 func printStatus(status cluster.SandboxStatus) {
 	sandboxStr, sandboxColor := GetSandboxStatusString(status.Phase)
 	fmt.Printf("Sandbox: %s\n", goterm.Color(sandboxStr, sandboxColor))
+	for _, warning := range status.Warnings {
+		fmt.Println(goterm.Color("WARNING: "+warning, goterm.YELLOW))
+	}
 
 	if len(status.Services) == 0 {
 		fmt.Println("No services found.")
 		return
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
-	defer w.Flush()
-	fmt.Fprintln(w, "SERVICE\tSTATUS")
-
+	// Only show the project column if services are in named projects.
+	hasProjects := false
 	var serviceNames []string
-	for name := range status.Services {
+	for name, svc := range status.Services {
 		serviceNames = append(serviceNames, name)
+		if svc.Project != "" {
+			hasProjects = true
+		}
+	}
+	sort.Slice(serviceNames, func(i, j int) bool {
+		iProject := status.Services[serviceNames[i]].Project
+		jProject := status.Services[serviceNames[j]].Project
+		if iProject != jProject {
+			return iProject < jProject
+		}
+		return serviceNames[i] < serviceNames[j]
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	if hasProjects {
+		fmt.Fprintln(w, "PROJECT\tSERVICE\tSTATUS")
+	} else {
+		fmt.Fprintln(w, "SERVICE\tSTATUS")
 	}
-	sort.Strings(serviceNames)
 
 	for _, name := range serviceNames {
-		statusStr, statusColor, _ := GetStatusString(status.Services[name])
-		fmt.Fprintf(w, "%s\t%s\n", name, goterm.Color(statusStr, statusColor))
+		svc := status.Services[name]
+		statusStr, statusColor, _ := GetStatusString(svc)
+		if hasProjects {
+			project := svc.Project
+			if project == "" {
+				project = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", project, name, goterm.Color(statusStr, statusColor))
+		} else {
+			fmt.Fprintf(w, "%s\t%s\n", name, goterm.Color(statusStr, statusColor))
+		}
 	}
 }
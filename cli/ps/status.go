@@ -2,6 +2,7 @@ package ps
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/buger/goterm"
 
@@ -23,6 +24,9 @@ func GetSandboxStatusString(sandboxStatus cluster.SandboxStatus_SandboxPhase) (m
 	case cluster.SandboxStatus_PREPARING:
 		msg = "Preparing to deploy"
 		color = goterm.YELLOW
+	case cluster.SandboxStatus_PAUSED:
+		msg = "Paused"
+		color = goterm.YELLOW
 	default:
 		msg = "Unknown"
 		color = goterm.YELLOW
@@ -34,9 +38,13 @@ func GetSandboxStatusString(sandboxStatus cluster.SandboxStatus_SandboxPhase) (m
 func GetStatusString(svcStatus *cluster.ServiceStatus) (msg string, color int, booted bool) {
 	color = goterm.YELLOW
 	msg = "Unknown"
+	booted = svcStatus.HasStarted
 	switch svcStatus.Phase {
 	case cluster.ServicePhase_INITIALIZING_VOLUMES:
 		msg = "Initializing volumes"
+		if progress := getVolumeInitProgress(svcStatus.VolumeInit); progress != "" {
+			msg += fmt.Sprintf(" (%s)", progress)
+		}
 	case cluster.ServicePhase_WAIT_DEPENDS_ON:
 		msg = "Waiting for dependencies to be ready"
 	case cluster.ServicePhase_WAIT_SYNC_BIND:
@@ -50,15 +58,92 @@ func GetStatusString(svcStatus *cluster.ServiceStatus) (msg string, color int, b
 		msg = "Running"
 		color = goterm.GREEN
 	case cluster.ServicePhase_EXITED:
-		msg = "Exited"
+		msg = fmt.Sprintf("Exited (%d)", svcStatus.ExitCode)
 		color = goterm.RED
 	case cluster.ServicePhase_UNSCHEDULABLE:
 		msg = "Unschedulable. You may need to run `blimp down` and recreate your sandbox."
 		color = goterm.RED
+	case cluster.ServicePhase_INIT_TIMEOUT:
+		msg = "Stuck waiting to boot"
+		color = goterm.RED
+	case cluster.ServicePhase_SCHEDULED:
+		msg = fmt.Sprintf("Scheduled (%s)", svcStatus.Schedule)
+		color = goterm.GREEN
+		if svcStatus.LastRunAt != 0 {
+			lastRun := time.Since(time.Unix(svcStatus.LastRunAt, 0)).Round(time.Second)
+			msg += fmt.Sprintf(", last run %s ago exited with code %d", lastRun, svcStatus.ExitCode)
+			if svcStatus.ExitCode != 0 {
+				color = goterm.RED
+			}
+		}
+
+		// Scheduled services are considered booted once they're scheduled,
+		// even if they haven't run yet.
+		booted = true
+	case cluster.ServicePhase_RESCHEDULING:
+		msg = "Rescheduling"
+	case cluster.ServicePhase_WAITING_FOR_CAPACITY:
+		msg = "Waiting for the cluster to add capacity"
+	case cluster.ServicePhase_RATE_LIMITED:
+		msg = "Rate limited while pulling image"
+		if svcStatus.RetryAt != 0 {
+			retryIn := time.Until(time.Unix(svcStatus.RetryAt, 0)).Round(time.Second)
+			if retryIn > 0 {
+				msg += fmt.Sprintf(" (retrying in %s)", retryIn)
+			} else {
+				msg += " (retrying now)"
+			}
+		}
+	}
+
+	// Services in a service mesh can only communicate securely once the
+	// mesh's proxy is ready.
+	if svcStatus.Mesh != nil && svcStatus.Phase == cluster.ServicePhase_RUNNING {
+		if svcStatus.Mesh.SidecarReady {
+			msg += " (mTLS)"
+		} else {
+			msg += " (mesh proxy not ready)"
+			color = goterm.YELLOW
+		}
+	}
+
+	if svcStatus.Phase != cluster.ServicePhase_INITIALIZING_VOLUMES {
+		for _, volume := range svcStatus.VolumeInit {
+			if volume.State == cluster.VolumeInitStatus_FAILED {
+				msg += fmt.Sprintf(" (failed to initialize volume %s)", volume.Volume)
+			}
+		}
 	}
 
 	if svcStatus.Msg != "" {
 		msg += ": " + svcStatus.Msg
 	}
-	return msg, color, svcStatus.HasStarted
+	return msg, color, booted
+}
+
+// getVolumeInitProgress summarizes how many of the volumes have finished
+// initializing, and how much of the volumes being copied has been copied.
+func getVolumeInitProgress(volumes []*cluster.VolumeInitStatus) string {
+	if len(volumes) == 0 {
+		return ""
+	}
+
+	var finished int
+	var copied, total int64
+	for _, volume := range volumes {
+		switch volume.State {
+		case cluster.VolumeInitStatus_PENDING:
+		case cluster.VolumeInitStatus_COPYING:
+			copied += volume.CopiedBytes
+			total += volume.TotalBytes
+		default:
+			finished++
+		}
+	}
+
+	progress := fmt.Sprintf("%d/%d", finished, len(volumes))
+	if total > 0 {
+		progress += fmt.Sprintf(", copying %d%%", copied*100/total)
+	}
+	return progress
 }
@@ -0,0 +1,58 @@
+package ps
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/tunnel"
+)
+
+func runTunnels(outputFormat output.Format) error {
+	stats, err := tunnel.ReadStats()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat != output.Text {
+		if stats == nil {
+			stats = []tunnel.Stats{}
+		}
+		return output.Print(outputFormat, stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Println("No tunnels are running. Tunnels are started by `blimp up` and `blimp tunnel`.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tLOCAL ADDRESS\tACTIVE\tTOTAL\tDEAD\tSENT\tRECEIVED\tLAST ACTIVITY")
+	for _, s := range stats {
+		lastActivity := "-"
+		if !s.LastActivity.IsZero() {
+			lastActivity = time.Since(s.LastActivity).Round(time.Second).String() + " ago"
+		}
+		fmt.Fprintf(w, "%s:%d\t%s\t%d\t%d\t%d\t%s\t%s\t%s\n",
+			s.Service, s.ServicePort, s.LocalAddr,
+			s.ActiveConnections, s.TotalConnections, s.DeadConnections,
+			formatBytes(s.BytesSent), formatBytes(s.BytesReceived), lastActivity)
+	}
+	return w.Flush()
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
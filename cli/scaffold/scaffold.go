@@ -0,0 +1,142 @@
+package scaffold
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	var templateName string
+	var force bool
+	cobraCmd := &cobra.Command{
+		Use:   "init [--template TEMPLATE] [DIRECTORY]",
+		Short: "Create a Docker Compose project from a template",
+		Long: "Create a Docker Compose project from a template.\n\n" +
+			"Init writes a docker-compose.yml and Dockerfile for a common stack " +
+			"into DIRECTORY, which defaults to the current directory. " +
+			"Run without --template to list the available templates.",
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			if templateName == "" {
+				err = listTemplates(blimpConfig.BlimpAuth())
+			} else {
+				dir := "."
+				if len(args) == 1 {
+					dir = args[0]
+				}
+				err = scaffold(blimpConfig.BlimpAuth(), templateName, dir, force)
+			}
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().StringVarP(&templateName, "template", "t", "",
+		"The template to create the project from")
+	cobraCmd.Flags().BoolVarP(&force, "force", "", false,
+		"Overwrite files that already exist")
+	return cobraCmd
+}
+
+func listTemplates(auth *auth.BlimpAuth) error {
+	resp, err := manager.C.ListTemplates(context.Background(), &cluster.ListTemplatesRequest{
+		Auth: auth,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 10, 5, ' ', 0)
+	fmt.Fprintln(w, "TEMPLATE\tDESCRIPTION")
+	for _, tmpl := range resp.GetTemplates() {
+		fmt.Fprintf(w, "%s\t%s\n", tmpl.GetName(), tmpl.GetDescription())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println("\nRun `blimp init --template TEMPLATE` to create a project.")
+	return nil
+}
+
+func scaffold(auth *auth.BlimpAuth, name, dir string, force bool) error {
+	resp, err := manager.C.GetTemplate(context.Background(), &cluster.GetTemplateRequest{
+		Auth: auth,
+		Name: name,
+	})
+	if err != nil {
+		return err
+	}
+	tmpl := resp.GetTemplate()
+
+	// Check all the files before writing any of them so that we don't leave
+	// the project partially scaffolded.
+	paths := map[string]string{}
+	for path := range tmpl.GetFiles() {
+		fullPath, err := templatePath(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(fullPath); err == nil && !force {
+			return errors.NewFriendlyError("%s already exists. Use --force to overwrite it.", fullPath)
+		}
+		paths[path] = fullPath
+	}
+
+	var sortedPaths []string
+	for path := range paths {
+		sortedPaths = append(sortedPaths, path)
+	}
+	sort.Strings(sortedPaths)
+
+	for _, path := range sortedPaths {
+		fullPath := paths[path]
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return errors.WithContext("create directory", err)
+		}
+
+		if err := ioutil.WriteFile(fullPath, []byte(tmpl.GetFiles()[path]), 0644); err != nil {
+			return errors.WithContext("write file", err)
+		}
+		fmt.Printf("Created %s\n", fullPath)
+	}
+
+	fmt.Printf("\nCreated a project from the %s template (version %d).\n", tmpl.GetName(), tmpl.GetVersion())
+	if dir != "." {
+		fmt.Printf("Run `cd %s && blimp up` to boot it.\n", dir)
+	} else {
+		fmt.Println("Run `blimp up` to boot it.")
+	}
+	return nil
+}
+
+// templatePath returns where a template's file should be written. The path
+// must be within the project directory.
+func templatePath(dir, path string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(cleaned) || cleaned == ".." ||
+		strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", errors.New("template contains invalid path %q", path)
+	}
+	return filepath.Join(dir, cleaned), nil
+}
@@ -0,0 +1,211 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/buger/goterm"
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// severities are the vulnerability severities, from least to most severe.
+var severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+func New() *cobra.Command {
+	var minSeverity string
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:   "scan [SERVICE ...]",
+		Short: "Print the vulnerabilities found in your services' images",
+		Long: "Print the known vulnerabilities found in the images used by your services.\n\n" +
+			"Images are scanned when they're first deployed, and rescanned daily. " +
+			"Depending on how the Blimp cluster is configured, deploying images with " +
+			"severe vulnerabilities may be blocked.\n\n" +
+			"If no services are provided, the results for all services are printed.",
+		Example: "  blimp scan\n" +
+			"  blimp scan web --severity HIGH",
+		Run: func(_ *cobra.Command, args []string) {
+			if err := run(args, strings.ToUpper(minSeverity), outputFormat); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().StringVar(&minSeverity, "severity", "",
+		"Only print vulnerabilities at or above this severity. "+
+			"Either UNKNOWN, LOW, MEDIUM, HIGH, or CRITICAL.")
+	output.AddFlag(cobraCmd, &outputFormat)
+	return cobraCmd
+}
+
+// ImageScan is the schema for the machine-readable output of `blimp scan`.
+type ImageScan struct {
+	Service string `json:"service"`
+	Image   string `json:"image"`
+
+	// ScannedAt is the Unix timestamp of when the image was scanned.
+	ScannedAt int64 `json:"scannedAt"`
+
+	// Error is set if the image couldn't be scanned.
+	Error           string          `json:"error,omitempty"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+type Vulnerability struct {
+	ID               string `json:"id"`
+	Package          string `json:"package"`
+	InstalledVersion string `json:"installedVersion"`
+	FixedVersion     string `json:"fixedVersion,omitempty"`
+	Severity         string `json:"severity"`
+	Title            string `json:"title,omitempty"`
+}
+
+func run(services []string, minSeverity string, outputFormat output.Format) error {
+	if minSeverity != "" && severityRank(minSeverity) < 0 {
+		return errors.NewFriendlyError("Unknown severity %q. It must be one of %s.",
+			minSeverity, strings.Join(severities, ", "))
+	}
+
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.GetImageScans(context.Background(), &cluster.GetImageScansRequest{
+		Auth: blimpConfig.BlimpAuth(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var scans []*cluster.ImageScan
+	for _, scan := range resp.GetScans() {
+		if len(services) == 0 || contains(services, scan.GetService()) {
+			scans = append(scans, scan)
+		}
+	}
+
+	if outputFormat != output.Text {
+		out := []ImageScan{}
+		for _, scan := range scans {
+			scanOut := ImageScan{
+				Service:         scan.GetService(),
+				Image:           scan.GetImage(),
+				ScannedAt:       scan.GetScannedAt(),
+				Error:           scan.GetError(),
+				Vulnerabilities: []Vulnerability{},
+			}
+			for _, v := range filterVulnerabilities(scan.GetVulnerabilities(), minSeverity) {
+				scanOut.Vulnerabilities = append(scanOut.Vulnerabilities, Vulnerability{
+					ID:               v.GetId(),
+					Package:          v.GetPackage(),
+					InstalledVersion: v.GetInstalledVersion(),
+					FixedVersion:     v.GetFixedVersion(),
+					Severity:         v.GetSeverity(),
+					Title:            v.GetTitle(),
+				})
+			}
+			out = append(out, scanOut)
+		}
+		return output.Print(outputFormat, out)
+	}
+
+	if len(scans) == 0 {
+		fmt.Println("No images have been scanned yet. Images are scanned when they're deployed with `blimp up`.")
+		return nil
+	}
+
+	if resp.GetBlockSeverity() != "" {
+		fmt.Printf("Images with vulnerabilities of severity %s or higher can't be deployed.\n\n",
+			resp.GetBlockSeverity())
+	}
+
+	for i, scan := range scans {
+		if i != 0 {
+			fmt.Println()
+		}
+		printScan(scan, minSeverity)
+	}
+	return nil
+}
+
+func printScan(scan *cluster.ImageScan, minSeverity string) {
+	scannedAgo := time.Since(time.Unix(scan.GetScannedAt(), 0)).Round(time.Minute)
+	fmt.Printf("%s (%s), scanned %s ago\n", scan.GetService(), scan.GetImage(), scannedAgo)
+
+	if scan.GetError() != "" {
+		fmt.Println(goterm.Color("Failed to scan image: "+scan.GetError(), goterm.RED))
+		return
+	}
+
+	vulns := filterVulnerabilities(scan.GetVulnerabilities(), minSeverity)
+	if len(vulns) == 0 {
+		fmt.Println("No vulnerabilities found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "SEVERITY\tID\tPACKAGE\tINSTALLED\tFIXED")
+	for _, v := range vulns {
+		fixed := v.GetFixedVersion()
+		if fixed == "" {
+			fixed = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", colorSeverity(v.GetSeverity()),
+			v.GetId(), v.GetPackage(), v.GetInstalledVersion(), fixed)
+	}
+}
+
+func filterVulnerabilities(vulns []*cluster.Vulnerability, minSeverity string) []*cluster.Vulnerability {
+	if minSeverity == "" {
+		return vulns
+	}
+
+	var filtered []*cluster.Vulnerability
+	for _, v := range vulns {
+		if severityRank(v.GetSeverity()) >= severityRank(minSeverity) {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+// severityRank returns -1 for unknown severities.
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+func colorSeverity(severity string) string {
+	switch severity {
+	case "CRITICAL", "HIGH":
+		return goterm.Color(severity, goterm.RED)
+	case "MEDIUM":
+		return goterm.Color(severity, goterm.YELLOW)
+	default:
+		return severity
+	}
+}
+
+func contains(slc []string, str string) bool {
+	for _, s := range slc {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package share
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "share",
+		Short: "Share your sandbox with people outside your team",
+	}
+	cobraCmd.AddCommand(newLinkCommand())
+	return cobraCmd
+}
+
+func newLinkCommand() *cobra.Command {
+	var expires time.Duration
+	var services, scopes []string
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:   "link",
+		Short: "Create a temporary link to your sandbox",
+		Long: "Create links that give temporary access to your sandbox, so that you can " +
+			"share a repro with someone outside your team.\n\n" +
+			"--service creates a preview URL for an HTTP port on a service. " +
+			"--scope creates a guest token with read-only access to your sandbox. " +
+			"To use the guest token, set it as the username in ~/.blimp/auth.yaml, and " +
+			"run `blimp ps` or `blimp logs`.\n\n" +
+			"Everything is revoked automatically once the link expires.",
+		Example: "  blimp share link --expires 2h --service web:8080\n" +
+			"  blimp share link --expires 30m --scope status,logs",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runLink(expires, services, scopes, outputFormat); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().DurationVar(&expires, "expires", time.Hour,
+		"How long the link is valid for. Links can be valid for at most 7 days.")
+	cobraCmd.Flags().StringSliceVar(&services, "service", nil,
+		"The service ports to share, in the form SERVICE:PORT.")
+	cobraCmd.Flags().StringSliceVar(&scopes, "scope", nil,
+		"The read-only access granted to the guest token. Valid scopes are: logs, status.")
+	output.AddFlag(cobraCmd, &outputFormat)
+	return cobraCmd
+}
+
+// Link is the schema for the machine-readable output of `blimp share link`.
+type Link struct {
+	// Token is empty if no scopes were requested.
+	Token     string        `json:"token,omitempty"`
+	Previews  []PreviewLink `json:"previews"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
+
+type PreviewLink struct {
+	Service string `json:"service"`
+	Port    uint32 `json:"port"`
+	URL     string `json:"url"`
+}
+
+func runLink(expires time.Duration, services, scopes []string, outputFormat output.Format) error {
+	var ports []*cluster.SharedPort
+	for _, service := range services {
+		port, err := parseSharedPort(service)
+		if err != nil {
+			return err
+		}
+		ports = append(ports, port)
+	}
+
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.CreateShareLink(context.Background(), &cluster.CreateShareLinkRequest{
+		Auth:      blimpConfig.BlimpAuth(),
+		Ports:     ports,
+		Scopes:    scopes,
+		ExpiresIn: int64(expires.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	out := Link{
+		Token:     resp.GetToken(),
+		Previews:  []PreviewLink{},
+		ExpiresAt: time.Unix(resp.GetExpiresAt(), 0),
+	}
+	for i, link := range resp.GetLinks() {
+		out.Previews = append(out.Previews, PreviewLink{
+			Service: ports[i].GetService(),
+			Port:    ports[i].GetPort(),
+			URL:     link,
+		})
+	}
+
+	if outputFormat != output.Text {
+		return output.Print(outputFormat, out)
+	}
+
+	fmt.Printf("The following links expire at %s.\n", out.ExpiresAt.Format(time.RFC1123))
+	if len(out.Previews) != 0 {
+		fmt.Println("\nPreview URLs:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+		for _, preview := range out.Previews {
+			fmt.Fprintf(w, "  %s:%d\t%s\n", preview.Service, preview.Port, preview.URL)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if out.Token != "" {
+		fmt.Printf("\nGuest token (%s):\n  %s\n", strings.Join(scopes, ", "), out.Token)
+		fmt.Println("\nTo use it, set it as the username in ~/.blimp/auth.yaml, " +
+			"and run `blimp ps` or `blimp logs`.")
+	}
+	return nil
+}
+
+func parseSharedPort(str string) (*cluster.SharedPort, error) {
+	sep := strings.LastIndex(str, ":")
+	if sep == -1 {
+		return nil, errors.NewFriendlyError(
+			"%q should be in the form SERVICE:PORT. For example, web:8080.", str)
+	}
+
+	port, err := strconv.ParseUint(str[sep+1:], 10, 16)
+	if err != nil {
+		return nil, errors.NewFriendlyError("%q does not look like a valid port number", str[sep+1:])
+	}
+	return &cluster.SharedPort{Service: str[:sep], Port: uint32(port)}, nil
+}
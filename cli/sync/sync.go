@@ -0,0 +1,206 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/syncthing"
+)
+
+// resyncTimeout is how long `blimp sync restart` waits for the local and
+// remote volumes to match after rescanning them.
+const resyncTimeout = 5 * time.Minute
+
+func New() *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Inspect and repair the syncing of bind volumes",
+		Long: "Inspect and repair the syncing of bind volumes to your sandbox.\n\n" +
+			"These commands talk to the sync process started by `blimp up`, so " +
+			"`blimp up` must be running in another terminal.",
+	}
+	cobraCmd.AddCommand(newRestartCommand(), newStatusCommand())
+	return cobraCmd
+}
+
+func newRestartCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restart [VOLUME]",
+		Short: "Force a full rescan and resync of bind volumes",
+		Long: "Rescan the bind volumes on your machine and in your sandbox, and wait " +
+			"for them to match.\n\n" +
+			"Use this if changes aren't showing up in your sandbox. Blimp normally " +
+			"watches for file changes, but it can miss events, such as when editors " +
+			"save files by renaming a temporary file over the original.\n\n" +
+			"VOLUME is the local path of the bind volume to resync. All bind volumes " +
+			"are resynced if it's not set.",
+		Example: "  blimp sync restart ./src",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) > 1 {
+				fmt.Fprintln(os.Stderr, "At most one volume can be specified.")
+				os.Exit(1)
+			}
+
+			var volume string
+			if len(args) == 1 {
+				volume = args[0]
+			}
+			if err := runRestart(volume); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+}
+
+func newStatusCommand() *cobra.Command {
+	var showConflicts bool
+	cobraCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether bind volumes are in sync",
+		Long: "Show whether the bind volumes on your machine match the copies in " +
+			"your sandbox.\n\n" +
+			"With --conflicts, the files that differ are listed along with their " +
+			"sizes and modification times on each side.",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runStatus(showConflicts); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().BoolVar(&showConflicts, "conflicts", false,
+		"List the files that differ between your machine and your sandbox")
+	return cobraCmd
+}
+
+func runRestart(volume string) error {
+	folders, err := getFolders(volume)
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for _, folder := range folders {
+		fmt.Printf("Rescanning %s\n", folder.Path)
+		ids = append(ids, folder.ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resyncTimeout)
+	defer cancel()
+	if err := syncthing.Resync(ctx, syncthing.LocalAPI, syncthing.RemoteAPI, ids); err != nil {
+		return errors.WithContext("resync", err)
+	}
+	fmt.Println("Volumes are in sync.")
+	return nil
+}
+
+func runStatus(showConflicts bool) error {
+	folders, err := getFolders("")
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := syncthing.GetConflicts(syncthing.LocalAPI, syncthing.RemoteAPI, folders)
+	if err != nil {
+		return errors.WithContext("get out of sync files", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+
+	if showConflicts {
+		if len(conflicts) == 0 {
+			fmt.Println("All files are in sync.")
+			return nil
+		}
+
+		fmt.Fprintln(w, "PATH\tLOCAL SIZE\tLOCAL MODIFIED\tREMOTE SIZE\tREMOTE MODIFIED")
+		for _, conflict := range conflicts {
+			localSize, localModified := formatFile(conflict.Local)
+			remoteSize, remoteModified := formatFile(conflict.Remote)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				filepath.Join(conflict.Folder, conflict.Path),
+				localSize, localModified, remoteSize, remoteModified)
+		}
+		return nil
+	}
+
+	outOfSync := map[string]int{}
+	for _, conflict := range conflicts {
+		outOfSync[conflict.Folder]++
+	}
+
+	fmt.Fprintln(w, "VOLUME\tSTATE\tOUT OF SYNC FILES")
+	for _, folder := range folders {
+		state := "unknown"
+		if status, err := syncthing.LocalAPI.GetStatus(folder.ID); err == nil {
+			state = status.State
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\n", folder.Path, state, outOfSync[folder.Path])
+	}
+	return nil
+}
+
+// getFolders returns the synced folders that contain the given volume. All
+// folders are returned if the volume is empty.
+func getFolders(volume string) ([]syncthing.FolderConfig, error) {
+	if err := syncthing.LocalAPI.Ping(); err != nil {
+		return nil, errors.NewFriendlyError(
+			"Failed to connect to the sync process. Is `blimp up` running?\n"+
+				"The underlying error was: %s", err)
+	}
+
+	config, err := syncthing.LocalAPI.GetConfig()
+	if err != nil {
+		return nil, errors.WithContext("get sync config", err)
+	}
+
+	if volume == "" {
+		return config.Folders, nil
+	}
+
+	volumePath, err := filepath.Abs(volume)
+	if err != nil {
+		return nil, errors.WithContext("get absolute path", err)
+	}
+
+	for _, folder := range config.Folders {
+		if folder.ID == volume || folder.Path == volumePath ||
+			strings.HasPrefix(volumePath, folder.Path+string(filepath.Separator)) {
+			return []syncthing.FolderConfig{folder}, nil
+		}
+	}
+	return nil, errors.NewFriendlyError(
+		"%s isn't synced to your sandbox. Run `blimp sync status` to see the synced volumes.", volume)
+}
+
+func formatFile(file syncthing.FileInfo) (size, modified string) {
+	switch {
+	case file.Name == "":
+		return "-", "-"
+	case file.Deleted:
+		return "deleted", file.Modified.Local().Format(time.RFC822)
+	}
+	return formatSize(file.Size), file.Modified.Local().Format(time.RFC822)
+}
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a `testsuite` element. The contents aren't parsed since
+// they're just copied into the merged report.
+type junitTestSuite struct {
+	XMLName xml.Name   `xml:"testsuite"`
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   []byte     `xml:",innerxml"`
+}
+
+// mergeJUnitReports combines the test suites in the given JUnit reports into a
+// single report. Reports may either contain a single `testsuite`, or multiple
+// test suites wrapped in `testsuites`.
+func mergeJUnitReports(reports [][]byte) ([]byte, error) {
+	var merged junitTestSuites
+	for _, report := range reports {
+		root, err := junitRootElement(report)
+		if err != nil {
+			return nil, err
+		}
+
+		switch root {
+		case "testsuites":
+			var suites junitTestSuites
+			if err := xml.Unmarshal(report, &suites); err != nil {
+				return nil, errors.WithContext("parse", err)
+			}
+			merged.Suites = append(merged.Suites, suites.Suites...)
+		case "testsuite":
+			var suite junitTestSuite
+			if err := xml.Unmarshal(report, &suite); err != nil {
+				return nil, errors.WithContext("parse", err)
+			}
+			merged.Suites = append(merged.Suites, suite)
+		default:
+			return nil, errors.New("unexpected root element %q", root)
+		}
+	}
+
+	out, err := xml.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return nil, errors.WithContext("marshal", err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func junitRootElement(report []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(report))
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return "", errors.New("empty report")
+		}
+		if err != nil {
+			return "", errors.WithContext("parse", err)
+		}
+
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
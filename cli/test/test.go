@@ -0,0 +1,321 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/cp/kubectlcp"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// junitFilename is the name of the JUnit report that `--junit` copies out of
+// the results directory.
+const junitFilename = "junit.xml"
+
+type testCmd struct {
+	config      config.Config
+	service     string
+	command     []string
+	junitPath   string
+	artifacts   string
+	shards      int
+	copyVolumes bool
+}
+
+func New() *cobra.Command {
+	cmd := testCmd{}
+	cobraCmd := &cobra.Command{
+		Use:   "test --service SERVICE [options] [-- COMMAND...]",
+		Short: "Run a service's tests against the sandbox",
+		Long: "Run a service to completion against the running sandbox, and exit " +
+			"with the same exit code as the service.\n\n" +
+			"The service runs with the same image, volumes, and environment as " +
+			"the running service, and can connect to all the other services in " +
+			"the sandbox. If COMMAND is provided, it overrides the service's command.\n\n" +
+			"Test results written to the directory in $BLIMP_TEST_RESULTS_DIR (" +
+			kube.TestResultsDir + ") can be copied to the local machine with the " +
+			"--junit and --artifacts flags.\n\n" +
+			"With --shards, the sandbox is cloned once per shard, and the shards run " +
+			"in parallel in the clones. Each shard can find its index and the " +
+			"total number of shards in $BLIMP_TEST_SHARD_INDEX and " +
+			"$BLIMP_TEST_SHARD_COUNT. The clones are deleted once the tests finish.",
+		Run: func(_ *cobra.Command, args []string) {
+			if cmd.service == "" {
+				fmt.Fprintf(os.Stderr, "The --service flag is required\n")
+				os.Exit(1)
+			}
+
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			cmd.config = blimpConfig
+			cmd.command = args
+			exitCode, err := cmd.run()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			os.Exit(int(exitCode))
+		},
+	}
+	cobraCmd.Flags().StringVarP(&cmd.service, "service", "s", "",
+		"The service that contains the tests.")
+	cobraCmd.Flags().StringVar(&cmd.junitPath, "junit", "",
+		fmt.Sprintf("Copy the JUnit report (%s/%s) to the given local path.",
+			kube.TestResultsDir, junitFilename))
+	cobraCmd.Flags().StringVar(&cmd.artifacts, "artifacts", "",
+		fmt.Sprintf("Copy the contents of %s to the given local directory.",
+			kube.TestResultsDir))
+	cobraCmd.Flags().IntVar(&cmd.shards, "shards", 1,
+		"The number of sandbox clones to split the tests across.")
+	cobraCmd.Flags().BoolVar(&cmd.copyVolumes, "copy-volumes", false,
+		"Copy the contents of the sandbox's volumes into each clone when sharding.")
+	return cobraCmd
+}
+
+func (cmd testCmd) run() (int32, error) {
+	err := manager.CheckServiceStarted(cmd.service, cmd.config.BlimpAuth())
+	if err != nil {
+		return 0, err
+	}
+
+	// Cancelling the context closes the streams, which signals to the cluster
+	// manager that it can clean up the test pods.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if cmd.shards <= 1 {
+		return cmd.runShard(ctx, 0, os.Stdout, cmd.junitPath, cmd.artifacts)
+	}
+	return cmd.runShards(ctx)
+}
+
+// runShards runs each shard in parallel, and combines their results. The exit
+// code is the exit code of the first shard to fail.
+func (cmd testCmd) runShards(ctx context.Context) (int32, error) {
+	var junitDir string
+	if cmd.junitPath != "" {
+		var err error
+		junitDir, err = ioutil.TempDir("", "blimp-test-junit")
+		if err != nil {
+			return 0, errors.WithContext("create temp dir", err)
+		}
+		defer os.RemoveAll(junitDir)
+	}
+
+	fmt.Printf("Cloning sandbox for %d test shards. This may take a few minutes.\n", cmd.shards)
+
+	var outputLock sync.Mutex
+	exitCodes := make([]int32, cmd.shards)
+	errs := make([]error, cmd.shards)
+	junitPaths := make([]string, cmd.shards)
+	var wg sync.WaitGroup
+	for i := 0; i < cmd.shards; i++ {
+		var artifacts string
+		if cmd.artifacts != "" {
+			artifacts = filepath.Join(cmd.artifacts, fmt.Sprintf("shard-%d", i))
+		}
+		if junitDir != "" {
+			junitPaths[i] = filepath.Join(junitDir, fmt.Sprintf("shard-%d.xml", i))
+		}
+
+		wg.Add(1)
+		go func(i int, artifacts string) {
+			defer wg.Done()
+
+			out := &prefixWriter{
+				prefix: fmt.Sprintf("[shard %d] ", i),
+				out:    os.Stdout,
+				lock:   &outputLock,
+			}
+			exitCodes[i], errs[i] = cmd.runShard(ctx, int32(i), out, junitPaths[i], artifacts)
+			out.Flush()
+		}(i, artifacts)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return 0, errors.WithContext(fmt.Sprintf("shard %d", i), err)
+		}
+	}
+
+	if cmd.junitPath != "" {
+		var reports [][]byte
+		for _, path := range junitPaths {
+			report, err := ioutil.ReadFile(path)
+			if err != nil {
+				return 0, errors.WithContext("read JUnit report", err)
+			}
+			reports = append(reports, report)
+		}
+
+		merged, err := mergeJUnitReports(reports)
+		if err != nil {
+			return 0, errors.WithContext("merge JUnit reports", err)
+		}
+
+		if err := ioutil.WriteFile(cmd.junitPath, merged, 0644); err != nil {
+			return 0, errors.WithContext("write JUnit report", err)
+		}
+	}
+
+	for i, exitCode := range exitCodes {
+		if exitCode != 0 {
+			fmt.Printf("Shard %d failed with exit code %d.\n", i, exitCode)
+			return exitCode, nil
+		}
+	}
+	return 0, nil
+}
+
+func (cmd testCmd) runShard(ctx context.Context, index int32, out io.Writer,
+	junitPath, artifacts string) (int32, error) {
+	req := &cluster.RunTestRequest{
+		Auth:        cmd.config.BlimpAuth(),
+		Service:     cmd.service,
+		Command:     cmd.command,
+		ShardIndex:  index,
+		ShardCount:  int32(cmd.shards),
+		CopyVolumes: cmd.copyVolumes,
+	}
+	if cmd.shards <= 1 {
+		req.ShardCount = 0
+	}
+
+	stream, err := manager.C.RunTest(ctx, req)
+	if err != nil {
+		return 0, errors.WithContext("start tests", err)
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return 0, errors.New("test stream ended unexpectedly")
+		}
+		if err := errors.Unmarshal(err, msg.GetError()); err != nil {
+			return 0, err
+		}
+
+		if _, err := out.Write(msg.GetOutput()); err != nil {
+			return 0, errors.WithContext("write output", err)
+		}
+
+		if msg.GetFinished() {
+			err := cmd.collectResults(msg.GetNamespace(), msg.GetPodName(), junitPath, artifacts)
+			if err != nil {
+				return 0, errors.WithContext("collect test results", err)
+			}
+			return msg.GetExitCode(), nil
+		}
+	}
+}
+
+func (cmd testCmd) collectResults(namespace, podName, junitPath, artifacts string) error {
+	if junitPath == "" && artifacts == "" {
+		return nil
+	}
+
+	kubeClient, restConfig, err := cmd.config.Auth.KubeClient()
+	if err != nil {
+		return errors.WithContext("get kube client", err)
+	}
+
+	// Required by `kubectlcp` to access the Kubernetes API.
+	restConfig.GroupVersion = &schema.GroupVersion{Group: "", Version: "v1"}
+	restConfig.APIPath = "/api"
+	restConfig.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+
+	// Older cluster managers don't report the namespace of the test pod.
+	if namespace == "" {
+		namespace = cmd.config.Auth.KubeNamespace
+	}
+
+	copier := &kubectlcp.CopyOptions{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    os.Stdout,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Namespace:    namespace,
+		Clientset:    kubeClient,
+		ClientConfig: restConfig,
+		Container:    kube.ContainerNameTestResults,
+	}
+
+	if junitPath != "" {
+		err := copier.CopyFromPod(
+			kubectlcp.FileSpec{PodName: podName, File: path.Join(kube.TestResultsDir, junitFilename)},
+			kubectlcp.FileSpec{File: junitPath})
+		if err != nil {
+			return errors.WithContext("copy JUnit report", err)
+		}
+	}
+
+	if artifacts != "" {
+		err := copier.CopyFromPod(
+			kubectlcp.FileSpec{PodName: podName, File: kube.TestResultsDir},
+			kubectlcp.FileSpec{File: artifacts})
+		if err != nil {
+			return errors.WithContext("copy artifacts", err)
+		}
+	}
+	return nil
+}
+
+// prefixWriter prefixes each line written to it. Lines are written to `out`
+// atomically so that the output of concurrent writers isn't interleaved.
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	lock   *sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		line := w.buf.Bytes()
+		i := bytes.IndexByte(line, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+
+		if err := w.writeLine(line[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf.Next(i + 1)
+	}
+}
+
+// Flush writes any remaining partial line.
+func (w *prefixWriter) Flush() {
+	if w.buf.Len() != 0 {
+		w.writeLine(append(w.buf.Bytes(), '\n'))
+		w.buf.Reset()
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err := w.out.Write(append([]byte(w.prefix), line...))
+	return err
+}
@@ -0,0 +1,158 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage tokens with limited access to your sandbox",
+		Long: "Manage tokens with limited access to your sandbox.\n\n" +
+			"Scoped tokens can be shared with teammates or used in CI to view the " +
+			"status and logs of your sandbox, without granting full access to it.",
+	}
+	cobraCmd.AddCommand(newCreateCommand(), newListCommand(), newRevokeCommand())
+	return cobraCmd
+}
+
+func newCreateCommand() *cobra.Command {
+	var name string
+	var scopes []string
+	cobraCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a scoped token",
+		Long: "Create a token that only has access to the given scopes.\n\n" +
+			"The token is only printed once, so make sure to save it. To use it, set " +
+			"it as the username in ~/.blimp/auth.yaml.",
+		Example: "  blimp token create --scope status,logs --name ci",
+		Run: func(_ *cobra.Command, _ []string) {
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			resp, err := manager.C.CreateToken(context.Background(), &cluster.CreateTokenRequest{
+				Auth:   blimpConfig.BlimpAuth(),
+				Name:   name,
+				Scopes: scopes,
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			fmt.Fprintf(os.Stderr, "Created token %s. It won't be shown again.\n", resp.GetInfo().GetId())
+			fmt.Println(resp.GetToken())
+		},
+	}
+	cobraCmd.Flags().StringVar(&name, "name", "", "A description of what the token is used for.")
+	cobraCmd.Flags().StringSliceVar(&scopes, "scope", nil,
+		"The scopes granted to the token. Valid scopes are: logs, status.")
+	return cobraCmd
+}
+
+// Token is the schema for the machine-readable output of `blimp token list`.
+type Token struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newListCommand() *cobra.Command {
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the scoped tokens for your sandbox",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runList(outputFormat); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	output.AddFlag(cobraCmd, &outputFormat)
+	return cobraCmd
+}
+
+func runList(outputFormat output.Format) error {
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.ListTokens(context.Background(), &cluster.ListTokensRequest{
+		Auth: blimpConfig.BlimpAuth(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if outputFormat != output.Text {
+		out := []Token{}
+		for _, t := range resp.GetTokens() {
+			out = append(out, Token{
+				ID:        t.GetId(),
+				Name:      t.GetName(),
+				Scopes:    t.GetScopes(),
+				CreatedAt: time.Unix(t.GetCreatedAt(), 0),
+			})
+		}
+		return output.Print(outputFormat, out)
+	}
+
+	if len(resp.GetTokens()) == 0 {
+		fmt.Println("No tokens have been created. Create one with `blimp token create`.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSCOPES\tCREATED")
+	for _, t := range resp.GetTokens() {
+		created := time.Unix(t.GetCreatedAt(), 0).Format(time.RFC3339)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.GetId(), t.GetName(),
+			strings.Join(t.GetScopes(), ","), created)
+	}
+	return w.Flush()
+}
+
+func newRevokeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke ID",
+		Short: "Revoke a scoped token",
+		Long:  "Revoke a scoped token. Requests made with the token are rejected immediately.",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Please specify the ID of the token to revoke.")
+				os.Exit(1)
+			}
+
+			blimpConfig, err := config.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			_, err = manager.C.RevokeToken(context.Background(), &cluster.RevokeTokenRequest{
+				Auth: blimpConfig.BlimpAuth(),
+				Id:   args[0],
+			})
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			fmt.Printf("Revoked token %s\n", args[0])
+		},
+	}
+}
@@ -0,0 +1,251 @@
+package tunnel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+var validName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+func newInstallCommand() *cobra.Command {
+	var name string
+	var localPort int
+	cobraCmd := &cobra.Command{
+		Use:   "install SERVICE:PORT",
+		Short: "Keep a tunnel running in the background",
+		Long: "Register a background service that keeps a tunnel to the sandbox " +
+			"running, even after logging out or rebooting. The tunnel is managed " +
+			"by launchd on macOS, and by a systemd user unit on Linux.",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Please specify a service and port. For example,\n"+
+					"to always forward port 3000 on the \"web\" service, run `blimp tunnel install web:3000`.")
+				os.Exit(1)
+			}
+
+			spec, err := parseSpec(args[0], localPort)
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			if name == "" {
+				name = fmt.Sprintf("%s-%d", spec.Service, spec.LocalPort)
+			}
+
+			if err := runInstall(name, spec); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().StringVar(&name, "name", "",
+		"The name used to refer to the tunnel. Defaults to SERVICE-LOCAL_PORT.")
+	cobraCmd.Flags().IntVar(&localPort, "local-port", 0,
+		"The local port to listen on. Defaults to the service's port.")
+	return cobraCmd
+}
+
+func newListCommand() *cobra.Command {
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the tunnels created by `blimp tunnel install`",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runList(outputFormat); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	output.AddFlag(cobraCmd, &outputFormat)
+	return cobraCmd
+}
+
+func newRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove NAME",
+		Short: "Stop and remove a tunnel created by `blimp tunnel install`",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Please specify the name of the tunnel to remove. "+
+					"The names are shown by `blimp tunnel list`.")
+				os.Exit(1)
+			}
+
+			if err := runRemove(args[0]); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+}
+
+func runInstall(name string, spec Spec) error {
+	if !validName.MatchString(name) {
+		return errors.NewFriendlyError("Invalid tunnel name %q. Names may only contain "+
+			"lowercase letters, numbers, and dashes.", name)
+	}
+
+	tunnels, err := readInstalled()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := tunnels[name]; ok {
+		return errors.NewFriendlyError("A tunnel named %q is already installed. "+
+			"Remove it with `blimp tunnel remove %s`, or choose another name with --name.", name, name)
+	}
+
+	for otherName, other := range tunnels {
+		if other.LocalPort == spec.LocalPort {
+			return errors.NewFriendlyError("The tunnel %q already uses local port %d.", otherName, spec.LocalPort)
+		}
+	}
+
+	svcManager, err := getServiceManager()
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return errors.WithContext("get path to blimp", err)
+	}
+
+	if err := svcManager.install(name, append([]string{exe}, spec.args()...)); err != nil {
+		return errors.WithContext("install background service", err)
+	}
+
+	tunnels[name] = spec
+	if err := writeInstalled(tunnels); err != nil {
+		return err
+	}
+
+	fmt.Printf("Installed tunnel %q. localhost:%d now forwards to %s.\n", name, spec.LocalPort, spec)
+	if hint := svcManager.hint(name); hint != "" {
+		fmt.Println(hint)
+	}
+	return nil
+}
+
+// InstalledTunnel is the schema for the machine-readable output of `blimp
+// tunnel list`.
+type InstalledTunnel struct {
+	Name        string `json:"name"`
+	Service     string `json:"service"`
+	ServicePort uint32 `json:"servicePort"`
+	LocalPort   uint32 `json:"localPort"`
+}
+
+func runList(outputFormat output.Format) error {
+	tunnels, err := readInstalled()
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range tunnels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if outputFormat != output.Text {
+		installed := []InstalledTunnel{}
+		for _, name := range names {
+			spec := tunnels[name]
+			installed = append(installed, InstalledTunnel{
+				Name:        name,
+				Service:     spec.Service,
+				ServicePort: spec.ServicePort,
+				LocalPort:   spec.LocalPort,
+			})
+		}
+		return output.Print(outputFormat, installed)
+	}
+
+	if len(tunnels) == 0 {
+		fmt.Println("No tunnels are installed. Install one with `blimp tunnel install SERVICE:PORT`.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 10, 5, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "NAME\tLOCAL PORT\tSERVICE\tSERVICE PORT")
+	for _, name := range names {
+		spec := tunnels[name]
+		fmt.Fprintf(w, "%s\t%d\t%s\t%d\n", name, spec.LocalPort, spec.Service, spec.ServicePort)
+	}
+	return nil
+}
+
+func runRemove(name string) error {
+	tunnels, err := readInstalled()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := tunnels[name]; !ok {
+		return errors.NewFriendlyError("No tunnel named %q is installed. "+
+			"The installed tunnels are shown by `blimp tunnel list`.", name)
+	}
+
+	svcManager, err := getServiceManager()
+	if err != nil {
+		return err
+	}
+
+	if err := svcManager.uninstall(name); err != nil {
+		return errors.WithContext("uninstall background service", err)
+	}
+
+	delete(tunnels, name)
+	if err := writeInstalled(tunnels); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed tunnel %q.\n", name)
+	return nil
+}
+
+// readInstalled returns the tunnels created by `blimp tunnel install`, keyed
+// by name.
+func readInstalled() (map[string]Spec, error) {
+	tunnels := map[string]Spec{}
+	tunnelsBytes, err := ioutil.ReadFile(getInstalledPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tunnels, nil
+		}
+		return nil, errors.WithContext("read installed tunnels", err)
+	}
+
+	if err := yaml.Unmarshal(tunnelsBytes, &tunnels); err != nil {
+		return nil, errors.WithContext("parse installed tunnels", err)
+	}
+	return tunnels, nil
+}
+
+func writeInstalled(tunnels map[string]Spec) error {
+	tunnelsBytes, err := yaml.Marshal(tunnels)
+	if err != nil {
+		return errors.WithContext("marshal yaml", err)
+	}
+
+	if err := ioutil.WriteFile(getInstalledPath(), tunnelsBytes, 0644); err != nil {
+		return errors.WithContext("write installed tunnels", err)
+	}
+	return nil
+}
+
+func getInstalledPath() string {
+	return cfgdir.Expand("tunnels.yaml")
+}
@@ -0,0 +1,214 @@
+package tunnel
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// serviceManager registers background services with the operating system so
+// that they're started on login, and restarted if they crash.
+type serviceManager interface {
+	install(name string, command []string) error
+	uninstall(name string) error
+
+	// hint returns extra instructions to show the user after installing a
+	// service.
+	hint(name string) string
+}
+
+func getServiceManager() (serviceManager, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return launchd{}, nil
+	case "linux":
+		return systemd{}, nil
+	default:
+		return nil, errors.NewFriendlyError("Installing tunnels isn't supported on %s. "+
+			"Run `blimp tunnel SERVICE:PORT` instead.", runtime.GOOS)
+	}
+}
+
+type launchd struct{}
+
+func (launchd) label(name string) string {
+	return "io.kelda.blimp.tunnel." + name
+}
+
+func (l launchd) plistPath(name string) (string, error) {
+	return homedir.Expand(filepath.Join("~/Library/LaunchAgents", l.label(name)+".plist"))
+}
+
+func (l launchd) install(name string, command []string) error {
+	path, err := l.plistPath(name)
+	if err != nil {
+		return errors.WithContext("get plist path", err)
+	}
+
+	var args bytes.Buffer
+	for _, arg := range command {
+		args.WriteString("\t\t<string>")
+		if err := xml.EscapeText(&args, []byte(arg)); err != nil {
+			return errors.WithContext("escape argument", err)
+		}
+		args.WriteString("</string>\n")
+	}
+
+	logPath := cfgdir.Expand(fmt.Sprintf("tunnel-%s.log", name))
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>ThrottleInterval</key>
+	<integer>10</integer>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, l.label(name), args.String(), logPath, logPath)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.WithContext("create LaunchAgents directory", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(plist), 0644); err != nil {
+		return errors.WithContext("write plist", err)
+	}
+
+	return runCommand("launchctl", "load", "-w", path)
+}
+
+func (l launchd) uninstall(name string) error {
+	path, err := l.plistPath(name)
+	if err != nil {
+		return errors.WithContext("get plist path", err)
+	}
+
+	if err := runCommand("launchctl", "unload", "-w", path); err != nil {
+		return err
+	}
+	return removeIfExists(path)
+}
+
+func (launchd) hint(name string) string {
+	return fmt.Sprintf("Logs are written to %s.", cfgdir.Expand(fmt.Sprintf("tunnel-%s.log", name)))
+}
+
+type systemd struct{}
+
+func (systemd) unit(name string) string {
+	return fmt.Sprintf("blimp-tunnel-%s.service", name)
+}
+
+func (s systemd) unitPath(name string) (string, error) {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		var err error
+		configDir, err = homedir.Expand("~/.config")
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(configDir, "systemd", "user", s.unit(name)), nil
+}
+
+func (s systemd) install(name string, command []string) error {
+	path, err := s.unitPath(name)
+	if err != nil {
+		return errors.WithContext("get unit path", err)
+	}
+
+	// Quote each argument in case the path to blimp contains spaces, and
+	// escape percent signs since systemd uses them for specifiers.
+	var args []string
+	for _, arg := range command {
+		args = append(args, strings.Replace(strconv.Quote(arg), "%", "%%", -1))
+	}
+
+	unit := fmt.Sprintf(`[Unit]
+Description=Blimp tunnel %s
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+ExecStart=%s
+Restart=always
+RestartSec=10
+
+[Install]
+WantedBy=default.target
+`, name, strings.Join(args, " "))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.WithContext("create systemd user directory", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(unit), 0644); err != nil {
+		return errors.WithContext("write unit", err)
+	}
+
+	if err := runCommand("systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	return runCommand("systemctl", "--user", "enable", "--now", s.unit(name))
+}
+
+func (s systemd) uninstall(name string) error {
+	path, err := s.unitPath(name)
+	if err != nil {
+		return errors.WithContext("get unit path", err)
+	}
+
+	if err := runCommand("systemctl", "--user", "disable", "--now", s.unit(name)); err != nil {
+		return err
+	}
+
+	if err := removeIfExists(path); err != nil {
+		return err
+	}
+	return runCommand("systemctl", "--user", "daemon-reload")
+}
+
+func (s systemd) hint(name string) string {
+	return fmt.Sprintf("View its logs with `journalctl --user -u %s`.\n"+
+		"To keep the tunnel running while you're logged out, run `loginctl enable-linger`.", s.unit(name))
+}
+
+func runCommand(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return errors.WithContext(fmt.Sprintf("run %s %s (%s)",
+			name, strings.Join(args, " "), strings.TrimSpace(string(out))), err)
+	}
+	return nil
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.WithContext("remove "+path, err)
+	}
+	return nil
+}
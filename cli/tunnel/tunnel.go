@@ -0,0 +1,143 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/util"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+	"github.com/kelda/blimp/pkg/proto/node"
+	"github.com/kelda/blimp/pkg/tunnel"
+)
+
+// retryInterval is how long to wait before retrying to connect to the sandbox.
+const retryInterval = 10 * time.Second
+
+func New() *cobra.Command {
+	var localPort int
+	cobraCmd := &cobra.Command{
+		Use:   "tunnel SERVICE:PORT",
+		Short: "Forward a local port to a service",
+		Long: "Forward a local port to a port on a service in the sandbox. The tunnel " +
+			"waits for the sandbox to be available, and runs until it's interrupted.\n\n" +
+			"Use `blimp tunnel install` to keep the tunnel running in the background, " +
+			"even after logging out or rebooting.",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Please specify a service and port. For example,\n"+
+					"to forward port 3000 on the \"web\" service, run `blimp tunnel web:3000`.")
+				os.Exit(1)
+			}
+
+			spec, err := parseSpec(args[0], localPort)
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			if err := run(spec); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().IntVar(&localPort, "local-port", 0,
+		"The local port to listen on. Defaults to the service's port.")
+	cobraCmd.AddCommand(newInstallCommand(), newListCommand(), newRemoveCommand())
+	return cobraCmd
+}
+
+// Spec describes a tunnel from a local port to a service.
+type Spec struct {
+	Service     string `json:"service"`
+	ServicePort uint32 `json:"service_port"`
+	LocalPort   uint32 `json:"local_port"`
+}
+
+func (spec Spec) String() string {
+	if spec.LocalPort == spec.ServicePort {
+		return fmt.Sprintf("%s:%d", spec.Service, spec.ServicePort)
+	}
+	return fmt.Sprintf("%s:%d (local port %d)", spec.Service, spec.ServicePort, spec.LocalPort)
+}
+
+// args returns the arguments to `blimp tunnel` that create the tunnel.
+func (spec Spec) args() []string {
+	return []string{
+		"tunnel", fmt.Sprintf("%s:%d", spec.Service, spec.ServicePort),
+		"--local-port", strconv.Itoa(int(spec.LocalPort)),
+	}
+}
+
+func parseSpec(str string, localPort int) (Spec, error) {
+	parts := strings.Split(str, ":")
+	if len(parts) != 2 || parts[0] == "" {
+		return Spec{}, errors.NewFriendlyError(
+			"Invalid tunnel %q. Tunnels should be formatted like SERVICE:PORT, such as web:3000.", str)
+	}
+
+	servicePort, err := strconv.ParseUint(parts[1], 10, 16)
+	if err != nil || servicePort == 0 {
+		return Spec{}, errors.NewFriendlyError("%q does not look like a valid port number", parts[1])
+	}
+
+	if localPort == 0 {
+		localPort = int(servicePort)
+	}
+	if localPort < 0 || localPort > 65535 {
+		return Spec{}, errors.NewFriendlyError("%d does not look like a valid port number", localPort)
+	}
+
+	return Spec{
+		Service:     parts[0],
+		ServicePort: uint32(servicePort),
+		LocalPort:   uint32(localPort),
+	}, nil
+}
+
+// run forwards connections until it's killed. Connecting to the sandbox is
+// retried since the tunnel may be started in the background before the
+// network is up, or while the sandbox is down.
+func run(spec Spec) error {
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	var resp *cluster.AttachToSandboxResponse
+	for {
+		resp, err = manager.C.AttachToSandbox(context.Background(), &cluster.AttachToSandboxRequest{
+			Auth: blimpConfig.BlimpAuth(),
+		})
+		if err == nil {
+			break
+		}
+
+		log.WithError(err).WithField("tunnel", spec).
+			Errorf("Failed to connect to sandbox. Retrying in %s.", retryInterval)
+		time.Sleep(retryInterval)
+	}
+
+	conn, err := util.Dial(resp.NodeAddress, resp.NodeCert, "")
+	if err != nil {
+		return errors.WithContext("connect to node controller", err)
+	}
+	defer conn.Close()
+
+	ready := make(chan struct{})
+	go func() {
+		<-ready
+		fmt.Printf("Forwarding localhost:%d to %s.\n", spec.LocalPort, spec)
+	}()
+
+	tunnelManager := tunnel.NewManager(node.NewControllerClient(conn), blimpConfig.BlimpAuth())
+	return tunnelManager.Run("127.0.0.1", spec.LocalPort, spec.Service, spec.ServicePort, ready)
+}
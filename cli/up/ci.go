@@ -0,0 +1,242 @@
+package up
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/kelda/blimp/cli/down"
+	"github.com/kelda/blimp/cli/exec"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/util"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// teardownTimeout is how long `blimp ci` waits for the sandbox to be deleted
+// before giving up.
+const teardownTimeout = 5 * time.Minute
+
+func NewCICommand() *cobra.Command {
+	var composePaths []string
+	var cmd ci
+	cobraCmd := &cobra.Command{
+		Use:   "ci --service SERVICE [options] -- COMMAND [ARGS...]",
+		Short: "Boot a sandbox, run a test command in it, and delete it",
+		Long: "Boot a sandbox, run a test command in it, and delete it\n\n" +
+			"CI boots the docker-compose.yml in the current directory, waits for " +
+			"all the services to be running, and then runs COMMAND in SERVICE. " +
+			"The sandbox is always deleted afterwards, and `blimp ci` exits with " +
+			"the exit code of COMMAND.",
+		Run: func(_ *cobra.Command, command []string) {
+			if cmd.service == "" || len(command) == 0 {
+				errors.HandleFatalError(errors.NewFriendlyError(
+					"A service and a command to run in it are required.\n" +
+						"For example: blimp ci --service web -- npm test"))
+			}
+
+			cmd.configure(composePaths)
+			cmd.service = compose.ProjectServiceName(cmd.project, cmd.service)
+			exitCode, err := cmd.run(command)
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+			os.Exit(exitCode)
+		},
+		DisableFlagsInUseLine: true,
+	}
+	cobraCmd.Flags().StringSliceVarP(&composePaths, "file", "f", nil,
+		"Specify an alternate compose file\nDefaults to docker-compose.yml and docker-compose.yaml")
+	cobraCmd.Flags().StringVarP(&cmd.service, "service", "s", "",
+		"The service to run the test command in")
+	cobraCmd.Flags().DurationVarP(&cmd.timeout, "timeout", "", 30*time.Minute,
+		"The maximum time to spend booting the sandbox and running the tests")
+	cobraCmd.Flags().BoolVarP(&cmd.alwaysBuild, "build", "", false,
+		"Build images before starting containers")
+	cobraCmd.Flags().BoolVarP(&cmd.pinDigests, "pin-digests", "", false,
+		"Resolve image tags to digests before deploying, so that services run exactly the resolved images")
+	cobraCmd.Flags().BoolVarP(&cmd.forceBuildkit, "remote-build", "", false,
+		"Force Docker images to be built in your sandbox instead of locally")
+	cobraCmd.Flags().StringSliceVarP(&cmd.kubernetesPaths, "kubernetes", "k", nil,
+		"Kubernetes YAML files to deploy alongside the Compose services")
+	return cobraCmd
+}
+
+type ci struct {
+	up
+
+	service string
+	timeout time.Duration
+}
+
+// run boots the sandbox and runs the tests, and then deletes the sandbox. It
+// returns the exit code of the test command.
+func (cmd *ci) run(command []string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.timeout)
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	type testResult struct {
+		exitCode int
+		err      error
+	}
+	results := make(chan testResult, 1)
+	go func() {
+		exitCode, err := cmd.runTests(ctx, command)
+		results <- testResult{exitCode, err}
+	}()
+
+	var result testResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			result.err = errors.NewFriendlyError("The tests didn't finish within %s.", cmd.timeout)
+		} else {
+			result.err = errors.NewFriendlyError("Interrupted.")
+		}
+	}
+
+	fmt.Println("Cleaning up your containers and volumes.")
+	if err := cmd.teardown(); err != nil {
+		if result.err == nil {
+			result.err = errors.WithContext("delete sandbox", err)
+		} else {
+			log.WithError(err).Warn("Failed to delete sandbox")
+		}
+	}
+	return result.exitCode, result.err
+}
+
+func (cmd *ci) runTests(ctx context.Context, command []string) (int, error) {
+	sess, err := cmd.start(nil)
+	if err != nil {
+		return 0, err
+	}
+	defer sess.stop()
+
+	if !hasService(sess.compose, cmd.service) {
+		return 0, errors.NewFriendlyError("The service %q isn't in the Docker Compose file.", cmd.service)
+	}
+
+	if err := cmd.waitForReady(ctx, sess); err != nil {
+		return 0, err
+	}
+
+	fmt.Printf("Running `%s` in %s.\n", strings.Join(command, " "), cmd.service)
+	return exec.RunCommand(cmd.config, cmd.service, command, os.Stdout, os.Stderr)
+}
+
+// waitForReady blocks until all the services are running. Services that
+// exited successfully are considered ready, except for the service that the
+// tests are run in.
+func (cmd *ci) waitForReady(ctx context.Context, sess *session) error {
+	pp := util.NewProgressPrinter(os.Stdout, "Waiting for services to boot")
+	go pp.Run()
+	defer pp.Stop()
+
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	defer cancelWatch()
+
+	type statusUpdate struct {
+		msg *cluster.GetStatusResponse
+		err error
+	}
+	updates := make(chan statusUpdate)
+	go func() {
+		stream := manager.WatchStatus(watchCtx, manager.C, &cluster.GetStatusRequest{
+			Auth: cmd.config.BlimpAuth(),
+		})
+		for {
+			msg, err := stream.Recv()
+			select {
+			case updates <- statusUpdate{msg, err}:
+			case <-watchCtx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case err := <-sess.syncthingError:
+			return errors.WithContext("syncthing error", err)
+		case err := <-sess.tunnelsError:
+			return errors.WithContext("tunnel crashed", err)
+		case <-ctx.Done():
+			return ctx.Err()
+		case update := <-updates:
+			if update.err != nil {
+				return errors.WithContext("watch status", update.err)
+			}
+
+			ready, err := cmd.checkReady(sess, update.msg.GetStatus().GetServices())
+			if ready || err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (cmd *ci) checkReady(sess *session, statuses map[string]*cluster.ServiceStatus) (bool, error) {
+	ready := true
+	for _, name := range sess.compose.ServiceNames() {
+		status, ok := statuses[name]
+		if !ok {
+			ready = false
+			continue
+		}
+
+		switch status.GetPhase() {
+		case cluster.ServicePhase_RUNNING, cluster.ServicePhase_SCHEDULED:
+		case cluster.ServicePhase_EXITED:
+			if name == cmd.service || status.GetExitCode() != 0 {
+				return false, errors.NewFriendlyError(
+					"%s exited with code %d before the tests started.\n"+
+						"Use `blimp logs %s` to see its output.", name, status.GetExitCode(), name)
+			}
+		case cluster.ServicePhase_UNSCHEDULABLE, cluster.ServicePhase_INIT_TIMEOUT:
+			return false, errors.NewFriendlyError("%s failed to boot: %s", name, status.GetMsg())
+		default:
+			ready = false
+		}
+	}
+	return ready, nil
+}
+
+// teardown deletes the sandbox, including its volumes. It gives up if the
+// sandbox isn't deleted within teardownTimeout.
+func (cmd *ci) teardown() error {
+	downFinished := make(chan error, 1)
+	go func() {
+		downFinished <- down.Run(cmd.config.BlimpAuth(), cmd.project, true)
+	}()
+
+	select {
+	case err := <-downFinished:
+		return err
+	case <-time.After(teardownTimeout):
+		return errors.New("timed out after %s", teardownTimeout)
+	}
+}
@@ -0,0 +1,101 @@
+package up
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	composeTypes "github.com/kelda/compose-go/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// pinImageDigests replaces the image tags of the services that have
+// `x-blimp.pin_digest` set with the digests that the tags currently refer
+// to. If --pin-digests was passed, all services are pinned. Images that are
+// built by Blimp are skipped since they're already deployed by digest.
+func (cmd *up) pinImageDigests(cfg *composeTypes.Project) error {
+	builtImages := map[string]struct{}{}
+	for _, svc := range cfg.Services {
+		if svc.Build != nil && svc.Image != "" {
+			builtImages[svc.Image] = struct{}{}
+		}
+	}
+
+	pinned := make([]string, len(cfg.Services))
+	var lookups errgroup.Group
+	for i, svc := range cfg.Services {
+		i, svc := i, svc
+		if _, ok := builtImages[svc.Image]; ok || svc.Build != nil || svc.Image == "" {
+			continue
+		}
+
+		ext, err := compose.GetServiceExtension(svc)
+		if err != nil {
+			return err
+		}
+
+		if !cmd.pinDigests && !ext.PinDigest {
+			continue
+		}
+
+		lookups.Go(func() error {
+			image, err := cmd.resolveDigest(svc.Image)
+			if err != nil {
+				return errors.NewFriendlyError(
+					"Failed to resolve the digest of %s for service %s: %s", svc.Image, svc.Name, err)
+			}
+			pinned[i] = image
+			return nil
+		})
+	}
+
+	if err := lookups.Wait(); err != nil {
+		return err
+	}
+
+	for i, image := range pinned {
+		if image == "" || image == cfg.Services[i].Image {
+			continue
+		}
+
+		fmt.Printf("Pinned %s to %s\n", cfg.Services[i].Name, image)
+		cfg.Services[i].Image = image
+	}
+	return nil
+}
+
+// resolveDigest returns a reference to the image by digest. Images that are
+// already referenced by digest are returned unchanged.
+func (cmd *up) resolveDigest(image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", errors.WithContext("parse image reference", err)
+	}
+
+	if _, ok := ref.(name.Digest); ok {
+		return image, nil
+	}
+
+	authenticator := authn.Anonymous
+	if cred, ok := cmd.regCreds.LookupByImage(image); ok {
+		authenticator = &authn.Basic{
+			Username: cred.Username,
+			Password: cred.Password,
+		}
+	}
+
+	remoteImage, err := remote.Image(ref, remote.WithAuth(authenticator))
+	if err != nil {
+		return "", errors.WithContext("get remote image", err)
+	}
+
+	digest, err := remoteImage.Digest()
+	if err != nil {
+		return "", errors.WithContext("get digest", err)
+	}
+	return ref.Context().Name() + "@" + digest.String(), nil
+}
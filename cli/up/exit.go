@@ -0,0 +1,48 @@
+package up
+
+import (
+	"context"
+
+	composeTypes "github.com/kelda/compose-go/types"
+
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// exitedService is a service whose container exited.
+type exitedService struct {
+	name     string
+	exitCode int32
+}
+
+// waitForExit blocks until one of the given services exits. If `services` is
+// empty, all services are watched.
+func (cmd *up) waitForExit(ctx context.Context, services []string) (exitedService, error) {
+	stream := manager.WatchStatus(ctx, manager.C, &cluster.GetStatusRequest{
+		Auth:     cmd.config.BlimpAuth(),
+		Services: services,
+	})
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return exitedService{}, errors.WithContext("watch status", err)
+		}
+
+		for name, svcStatus := range msg.GetStatus().GetServices() {
+			if svcStatus.GetPhase() == cluster.ServicePhase_EXITED {
+				return exitedService{name: name, exitCode: svcStatus.GetExitCode()}, nil
+			}
+		}
+	}
+}
+
+func hasService(cfg composeTypes.Project, name string) bool {
+	for _, svc := range cfg.ServiceNames() {
+		if svc == name {
+			return true
+		}
+	}
+	return false
+}
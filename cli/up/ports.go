@@ -0,0 +1,229 @@
+package up
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ghodss/yaml"
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/tunnel"
+)
+
+// The modes for handling published ports that are already in use locally.
+const (
+	// portConflictsAuto publishes the service on a different local port.
+	portConflictsAuto = "auto"
+
+	// portConflictsPrompt asks the user which local port to use instead. It
+	// falls back to portConflictsAuto if there's no terminal.
+	portConflictsPrompt = "prompt"
+
+	// portConflictsFail fails to boot.
+	portConflictsFail = "fail"
+)
+
+// fallbackPortOffset is added to a port that's in use to get the first
+// fallback port, so that the fallback is easy to associate with the original
+// port. For example, 3000 falls back to 13000.
+const fallbackPortOffset = 10000
+
+// publishedPort is a local port that's forwarded to a service.
+type publishedPort struct {
+	service  string
+	target   uint32
+	listener net.Listener
+
+	// requested is the port in the Compose file. It's different than the
+	// listener's port if the requested port was in use.
+	requested uint32
+}
+
+func (p publishedPort) port() uint32 {
+	return uint32(p.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// portAssignments are the fallback ports that were used for each project, so
+// that services keep the same local port between runs of `blimp up`. They
+// are keyed by the project's directory, and then by `service:port`.
+type portAssignments map[string]map[string]uint32
+
+// listenPublishedPorts listens on the local ports published by the services.
+// If a port is already in use, a different port is picked according to
+// cmd.portConflicts, and the reassigned ports are reported to the user.
+func (cmd *up) listenPublishedPorts(project composeTypes.Project) (ports []publishedPort, err error) {
+	defer func() {
+		if err != nil {
+			for _, p := range ports {
+				p.listener.Close()
+			}
+		}
+	}()
+
+	assignments, err := readPortAssignments()
+	if err != nil {
+		log.WithError(err).Warn("Failed to read previous port assignments")
+		assignments = portAssignments{}
+	}
+
+	projectKey := filepath.Dir(cmd.composePath)
+	projectAssignments := assignments[projectKey]
+	if projectAssignments == nil {
+		projectAssignments = map[string]uint32{}
+		assignments[projectKey] = projectAssignments
+	}
+
+	var reassigned []publishedPort
+	for _, svc := range project.Services {
+		for _, mapping := range svc.Ports {
+			if mapping.Protocol != "tcp" {
+				continue
+			}
+
+			ln, err := net.Listen("tcp", fmt.Sprintf("%s:%d", mapping.HostIP, mapping.Published))
+			if err != nil {
+				if mapping.Published == 0 || !tunnel.IsAddressInUse(err) || cmd.portConflicts == portConflictsFail {
+					return ports, tunnel.ListenError(err, mapping.Published, svc.Name)
+				}
+
+				key := fmt.Sprintf("%s:%d", svc.Name, mapping.Published)
+				ln, err = cmd.listenFallbackPort(svc.Name, mapping, projectAssignments[key])
+				if err != nil {
+					return ports, err
+				}
+			}
+
+			p := publishedPort{
+				service:   svc.Name,
+				target:    mapping.Target,
+				listener:  ln,
+				requested: mapping.Published,
+			}
+			ports = append(ports, p)
+			if mapping.Published != 0 && p.port() != mapping.Published {
+				reassigned = append(reassigned, p)
+				projectAssignments[fmt.Sprintf("%s:%d", svc.Name, mapping.Published)] = p.port()
+			}
+		}
+	}
+
+	if len(reassigned) == 0 {
+		return ports, nil
+	}
+
+	fmt.Println("Some published ports are already in use, so they're available on different local ports:")
+	for _, p := range reassigned {
+		fmt.Printf("  %s: %d → %d (%d in use)\n", p.service, p.requested, p.port(), p.requested)
+	}
+
+	if err := writePortAssignments(assignments); err != nil {
+		log.WithError(err).Warn("Failed to save port assignments")
+	}
+	return ports, nil
+}
+
+// listenFallbackPort listens on a different port for a mapping whose
+// published port is in use. It prefers the port that was used last time,
+// followed by the published port plus fallbackPortOffset, followed by any
+// free port.
+func (cmd *up) listenFallbackPort(service string, mapping composeTypes.ServicePortConfig,
+	previous uint32) (net.Listener, error) {
+	var candidates []uint32
+	if previous != 0 {
+		candidates = append(candidates, previous)
+	}
+	if mapping.Published+fallbackPortOffset <= 65535 {
+		candidates = append(candidates, mapping.Published+fallbackPortOffset)
+	}
+	candidates = append(candidates, 0)
+
+	var ln net.Listener
+	var err error
+	for _, port := range candidates {
+		ln, err = net.Listen("tcp", fmt.Sprintf("%s:%d", mapping.HostIP, port))
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, tunnel.ListenError(err, mapping.Published, service)
+	}
+
+	if cmd.portConflicts != portConflictsPrompt || !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return ln, nil
+	}
+	return promptForPort(service, mapping, ln)
+}
+
+// promptForPort asks the user which port to use instead of the mapping's
+// published port. The suggested listener is used if the user doesn't enter a
+// port.
+func promptForPort(service string, mapping composeTypes.ServicePortConfig,
+	suggested net.Listener) (net.Listener, error) {
+	suggestedPort := suggested.Addr().(*net.TCPAddr).Port
+	for {
+		fmt.Printf("Port %d for %s is already in use. Enter a different port, or press enter to use %d: ",
+			mapping.Published, service, suggestedPort)
+		var response string
+		//nolint:errcheck // An empty response uses the suggested port.
+		fmt.Scanln(&response)
+		if response == "" {
+			return suggested, nil
+		}
+
+		port, err := strconv.ParseUint(response, 10, 16)
+		if err != nil || port == 0 {
+			fmt.Println("The port must be a number between 1 and 65535.")
+			continue
+		}
+
+		ln, err := net.Listen("tcp", fmt.Sprintf("%s:%d", mapping.HostIP, port))
+		if err != nil {
+			fmt.Printf("Can't listen on port %d: %s\n", port, err)
+			continue
+		}
+
+		suggested.Close()
+		return ln, nil
+	}
+}
+
+func readPortAssignments() (portAssignments, error) {
+	assignments := portAssignments{}
+	assignmentsBytes, err := ioutil.ReadFile(getPortAssignmentsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return assignments, nil
+		}
+		return nil, errors.WithContext("read port assignments", err)
+	}
+
+	if err := yaml.Unmarshal(assignmentsBytes, &assignments); err != nil {
+		return nil, errors.WithContext("parse port assignments", err)
+	}
+	return assignments, nil
+}
+
+func writePortAssignments(assignments portAssignments) error {
+	assignmentsBytes, err := yaml.Marshal(assignments)
+	if err != nil {
+		return errors.WithContext("marshal yaml", err)
+	}
+
+	if err := ioutil.WriteFile(getPortAssignmentsPath(), assignmentsBytes, 0644); err != nil {
+		return errors.WithContext("write port assignments", err)
+	}
+	return nil
+}
+
+func getPortAssignmentsPath() string {
+	return cfgdir.Expand("ports.yaml")
+}
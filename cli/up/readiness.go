@@ -0,0 +1,110 @@
+package up
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// readinessGate tracks which services are ready to accept connections, so
+// that the tunnels to a service can hold connections until the service
+// finishes booting, or while it's restarting.
+type readinessGate struct {
+	// ready maps services to a channel that's closed while the service is
+	// ready. It's replaced with an open channel when the service stops being
+	// ready. It's protected by `lock`.
+	ready   map[string]chan struct{}
+	isReady map[string]bool
+	lock    sync.Mutex
+}
+
+func newReadinessGate(services []string) *readinessGate {
+	g := &readinessGate{
+		ready:   map[string]chan struct{}{},
+		isReady: map[string]bool{},
+	}
+	for _, svc := range services {
+		g.ready[svc] = make(chan struct{})
+	}
+	return g
+}
+
+// Wait returns a channel that's closed once the service is ready. Services
+// that aren't tracked are always ready.
+func (g *readinessGate) Wait(service string) <-chan struct{} {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	ready, ok := g.ready[service]
+	if !ok {
+		ready = make(chan struct{})
+		close(ready)
+	}
+	return ready
+}
+
+func (g *readinessGate) set(service string, isReady bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if _, ok := g.ready[service]; !ok || g.isReady[service] == isReady {
+		return
+	}
+
+	if isReady {
+		close(g.ready[service])
+	} else {
+		g.ready[service] = make(chan struct{})
+	}
+	g.isReady[service] = isReady
+}
+
+// openAll marks every service as ready. It's used if the services' status
+// can't be tracked, so that connections aren't held forever.
+func (g *readinessGate) openAll() {
+	g.lock.Lock()
+	var services []string
+	for svc := range g.ready {
+		services = append(services, svc)
+	}
+	g.lock.Unlock()
+
+	for _, svc := range services {
+		g.set(svc, true)
+	}
+}
+
+// Run updates the gate as the services' status changes. It blocks until the
+// context is cancelled.
+func (g *readinessGate) Run(ctx context.Context, blimpAuth *auth.BlimpAuth) {
+	var services []string
+	for svc := range g.ready {
+		services = append(services, svc)
+	}
+
+	stream := manager.WatchStatus(ctx, manager.C, &cluster.GetStatusRequest{
+		Auth:     blimpAuth,
+		Services: services,
+	})
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.WithError(err).Warn("Failed to watch service status. " +
+					"Connections will be forwarded even if the service isn't ready.")
+				g.openAll()
+			}
+			return
+		}
+
+		for _, svc := range services {
+			phase := msg.GetStatus().GetServices()[svc].GetPhase()
+			g.set(svc, phase == cluster.ServicePhase_RUNNING)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package up
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// startReadyHooks runs each service's `on_ready` commands on the user's
+// machine whenever the service becomes ready, such as to open the service in
+// a browser, or to seed a database.
+func (cmd *up) startReadyHooks(ctx context.Context, parsedCompose composeTypes.Project) error {
+	hooks := map[string][]string{}
+	for _, svc := range parsedCompose.Services {
+		ext, err := compose.GetServiceExtension(svc)
+		if err != nil {
+			return err
+		}
+
+		if len(ext.OnReady) != 0 {
+			hooks[svc.Name] = ext.OnReady
+		}
+	}
+
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	var services []string
+	for svc := range hooks {
+		services = append(services, svc)
+	}
+
+	// Relative paths in the commands are relative to the Compose file, like
+	// the paths in the Compose file itself.
+	workDir := filepath.Dir(cmd.composePath)
+	go func() {
+		err := watchReady(ctx, cmd.config.BlimpAuth(), services, func(svc string) {
+			if err := runReadyHook(ctx, workDir, hooks[svc]); err != nil {
+				log.WithError(err).WithField("service", svc).Warn("Failed to run on_ready commands")
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			log.WithError(err).Warn("Stopped running on_ready commands")
+		}
+	}()
+	return nil
+}
+
+// watchReady calls `onReady` whenever one of the given services transitions
+// into the running phase. It blocks until the context is cancelled.
+func watchReady(ctx context.Context, blimpAuth *auth.BlimpAuth, services []string, onReady func(string)) error {
+	stream := manager.WatchStatus(ctx, manager.C, &cluster.GetStatusRequest{
+		Auth:     blimpAuth,
+		Services: services,
+	})
+
+	running := map[string]bool{}
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return errors.WithContext("watch status", err)
+		}
+
+		for _, svc := range services {
+			isRunning := msg.GetStatus().GetServices()[svc].GetPhase() == cluster.ServicePhase_RUNNING
+			if isRunning && !running[svc] {
+				// Run the hook in the background so that slow commands
+				// don't delay the hooks for other services.
+				go onReady(svc)
+			}
+			running[svc] = isRunning
+		}
+	}
+}
+
+func runReadyHook(ctx context.Context, workDir string, commands []string) error {
+	for _, command := range commands {
+		shell := exec.CommandContext(ctx, "sh", "-c", command)
+		if runtime.GOOS == "windows" {
+			shell = exec.CommandContext(ctx, "cmd", "/C", command)
+		}
+		shell.Dir = workDir
+
+		output, err := shell.CombinedOutput()
+		if err != nil {
+			return errors.WithContext(fmt.Sprintf("run %q (%s)", command, strings.TrimSpace(string(output))), err)
+		}
+	}
+	return nil
+}
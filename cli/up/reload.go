@@ -0,0 +1,131 @@
+package up
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/syncthing"
+)
+
+// reloadHook is a service's `x-blimp.on_sync` configuration.
+type reloadHook struct {
+	service     string
+	bindSources []string
+	commands    []string
+}
+
+// startReloadHooks runs each service's `on_sync` commands whenever a batch of
+// changes to its bind volumes finishes syncing, so that servers can reload
+// the changes without restarting the container.
+func (cmd *up) startReloadHooks(ctx context.Context, parsedCompose composeTypes.Project,
+	idPathMap map[string]string) error {
+
+	var hooks []reloadHook
+	for _, svc := range parsedCompose.Services {
+		ext, err := compose.GetServiceExtension(svc)
+		if err != nil {
+			return err
+		}
+
+		if len(ext.OnSync) == 0 {
+			continue
+		}
+
+		hook := reloadHook{service: svc.Name, commands: ext.OnSync}
+		for _, v := range svc.Volumes {
+			if v.Type == composeTypes.VolumeTypeBind {
+				hook.bindSources = append(hook.bindSources, v.Source)
+			}
+		}
+
+		if len(hook.bindSources) == 0 {
+			log.WithField("service", svc.Name).Warn("Service has on_sync commands, but no bind volumes. " +
+				"The commands will never run.")
+			continue
+		}
+		hooks = append(hooks, hook)
+	}
+
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	go syncthing.WatchSyncBatches(ctx, idPathMap, func(syncedPaths []string) {
+		for _, hook := range hooks {
+			if !hook.affectedBy(syncedPaths) {
+				continue
+			}
+
+			fmt.Printf("Synced changes to %s. Running on_sync commands.\n", hook.service)
+			if err := cmd.runReloadHook(hook); err != nil {
+				log.WithError(err).WithField("service", hook.service).Warn("Failed to run on_sync commands")
+			}
+		}
+	})
+	return nil
+}
+
+// affectedBy returns whether any of the service's bind volumes overlap with
+// the given synced paths. Syncthing may sync a parent directory of a bind
+// volume if several services mount related directories.
+func (hook reloadHook) affectedBy(syncedPaths []string) bool {
+	isWithin := func(parent, child string) bool {
+		relPath, err := filepath.Rel(parent, child)
+		return err == nil && !strings.HasPrefix(relPath, "..")
+	}
+
+	for _, synced := range syncedPaths {
+		for _, source := range hook.bindSources {
+			if isWithin(synced, source) || isWithin(source, synced) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cmd *up) runReloadHook(hook reloadHook) error {
+	kubeClient, restConfig, err := cmd.config.Auth.KubeClient()
+	if err != nil {
+		return errors.WithContext("get kube client", err)
+	}
+
+	for _, command := range hook.commands {
+		var output bytes.Buffer
+		req := kubeClient.CoreV1().RESTClient().Post().
+			Resource("pods").
+			SubResource("exec").
+			Name(names.ToDNS1123(hook.service)).
+			Namespace(cmd.config.Auth.KubeNamespace).
+			VersionedParams(&corev1.PodExecOptions{
+				Command: []string{"sh", "-c", command},
+				Stdout:  true,
+				Stderr:  true,
+			}, scheme.ParameterCodec)
+		exec, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+		if err != nil {
+			return errors.WithContext("setup exec", err)
+		}
+
+		err = exec.Stream(remotecommand.StreamOptions{
+			Stdout: &output,
+			Stderr: &output,
+		})
+		if err != nil {
+			return errors.WithContext(fmt.Sprintf("run %q (%s)", command, strings.TrimSpace(output.String())), err)
+		}
+	}
+	return nil
+}
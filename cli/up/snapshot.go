@@ -0,0 +1,176 @@
+package up
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	cliConfig "github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/util"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+	"github.com/kelda/blimp/pkg/syncthing"
+)
+
+func NewSnapshotCommand() *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Share a copy of your sandbox",
+		Long: "Share a copy of your sandbox, such as to let a teammate reproduce a bug.\n\n" +
+			"Snapshots contain the images your services are running, the contents of " +
+			"your volumes, and your Docker Compose configuration. The files in bind " +
+			"volumes are copied into the snapshot, so they aren't synced when the " +
+			"snapshot is booted.",
+	}
+	cobraCmd.AddCommand(newSnapshotCreateCommand(), newSnapshotBootCommand())
+	return cobraCmd
+}
+
+func newSnapshotCreateCommand() *cobra.Command {
+	var composePaths []string
+	cobraCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Snapshot your sandbox",
+		Long: "Snapshot your sandbox, and print an ID that can be passed to " +
+			"`blimp snapshot boot`. Anyone with the ID can boot the snapshot.",
+		Run: func(_ *cobra.Command, _ []string) {
+			var cmd up
+			cmd.configure(composePaths)
+			if err := cmd.createSnapshot(); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().StringSliceVarP(&composePaths, "file", "f", nil,
+		"Specify an alternate compose file\nDefaults to docker-compose.yml and docker-compose.yaml")
+	return cobraCmd
+}
+
+func newSnapshotBootCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "boot ID",
+		Short: "Boot a snapshot in your sandbox",
+		Long: "Boot a snapshot in your sandbox. Your sandbox must not have any services " +
+			"running, since the snapshot's volumes replace yours.\n\n" +
+			"The sandbox keeps running until `blimp down` is run.",
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Fprintln(os.Stderr, "Please specify the ID of the snapshot to boot.")
+				os.Exit(1)
+			}
+
+			// The Compose file comes from the snapshot, so booting doesn't
+			// require a local Compose file.
+			blimpConfig, err := cliConfig.GetConfig()
+			if err != nil {
+				errors.HandleFatalError(err)
+			}
+
+			cmd := up{config: blimpConfig}
+			if err := cmd.bootSnapshot(args[0]); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+}
+
+func (cmd *up) createSnapshot() error {
+	parsedCompose, err := compose.Load(cmd.composePath, cmd.overridePaths, nil)
+	if err != nil {
+		return errors.WithContext("load compose file", err)
+	}
+
+	parsedComposeBytes, err := compose.Marshal(parsedCompose)
+	if err != nil {
+		return err
+	}
+
+	regCreds, err := auth.GetLocalRegistryCredentials(cmd.dockerConfig)
+	if err != nil {
+		log.WithError(err).Debug("Failed to get local registry credentials. Private images will fail to copy.")
+		regCreds = auth.RegistryCredentials{}
+	}
+
+	pp := util.NewProgressPrinter(os.Stdout, "Saving images and volumes")
+	go pp.Run()
+	resp, err := manager.C.CreateSnapshot(context.Background(), &cluster.CreateSnapshotRequest{
+		Auth:                cmd.config.BlimpAuth(),
+		ComposeFile:         string(parsedComposeBytes),
+		RegistryCredentials: regCreds.ToProtobuf(),
+	})
+	pp.Stop()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created snapshot %s\n", resp.GetId())
+	fmt.Printf("Anyone can boot it with `blimp snapshot boot %s`.\n", resp.GetId())
+	return nil
+}
+
+func (cmd *up) bootSnapshot(id string) error {
+	statusResp, err := manager.C.GetStatus(context.Background(), &cluster.GetStatusRequest{
+		Auth: cmd.config.BlimpAuth(),
+	})
+	if err != nil {
+		return errors.WithContext("get sandbox status", err)
+	}
+	if len(statusResp.GetStatus().GetServices()) != 0 {
+		return errors.NewFriendlyError(
+			"Your sandbox already has services deployed. " +
+				"Run `blimp down` before booting a snapshot.")
+	}
+
+	snapshot, err := manager.C.GetSnapshot(context.Background(), &cluster.GetSnapshotRequest{
+		Auth: cmd.config.BlimpAuth(),
+		Id:   id,
+	})
+	if err != nil {
+		return err
+	}
+
+	createdAt := time.Unix(snapshot.GetCreatedAt(), 0)
+	fmt.Printf("Booting snapshot %s from %s\n", id, createdAt.Format(time.RFC1123))
+
+	// The snapshot's images are in the Blimp registry, which the sandbox
+	// always has credentials for, and its bind volumes were converted to
+	// named volumes, so nothing needs to be synced.
+	cmd.regCreds = auth.RegistryCredentials{}
+	if err := cmd.createSandbox(snapshot.GetComposeFile(), syncthing.Client{}); err != nil {
+		return err
+	}
+	defer cmd.nodeControllerConn.Close()
+
+	pp := util.NewProgressPrinter(os.Stdout, "Restoring volumes")
+	go pp.Run()
+	_, err = manager.C.BootSnapshot(context.Background(), &cluster.BootSnapshotRequest{
+		Auth: cmd.config.BlimpAuth(),
+		Id:   id,
+	})
+	pp.Stop()
+	if err != nil {
+		return err
+	}
+
+	pp = util.NewProgressPrinter(os.Stdout, "Deploying snapshot to sandbox")
+	go pp.Run()
+	_, err = manager.C.DeployToSandbox(context.Background(), &cluster.DeployRequest{
+		Auth:        cmd.config.BlimpAuth(),
+		ComposeFile: snapshot.GetComposeFile(),
+		BuiltImages: snapshot.GetBuiltImages(),
+	})
+	pp.Stop()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Booted snapshot. Check the status of the services with `blimp ps`.")
+	return nil
+}
@@ -0,0 +1,89 @@
+package up
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/node"
+	"github.com/kelda/blimp/pkg/tunnel"
+)
+
+// startSSHAgentForwarding lets services that enabled `x-blimp.ssh_agent` use
+// the SSH agent on the user's machine, such as to clone private repositories.
+// The private keys never leave the user's machine.
+func (cmd *up) startSSHAgentForwarding(ctx context.Context, parsedCompose composeTypes.Project) error {
+	var services []string
+	for _, svc := range parsedCompose.Services {
+		ext, err := compose.GetServiceExtension(svc)
+		if err != nil {
+			return err
+		}
+
+		if ext.SSHAgent {
+			services = append(services, svc.Name)
+		}
+	}
+
+	if len(services) == 0 {
+		return nil
+	}
+
+	agentPath := os.Getenv("SSH_AUTH_SOCK")
+	if agentPath == "" {
+		fmt.Fprintf(os.Stderr, "WARNING: SSH agent forwarding is enabled for %v, "+
+			"but SSH_AUTH_SOCK isn't set. Start an SSH agent and rerun `blimp up` to use it.\n", services)
+		return nil
+	}
+
+	go func() {
+		for {
+			err := cmd.forwardSSHAgent(ctx, agentPath)
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.WithError(err).Debug("SSH agent forwarding disconnected. Reconnecting.")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}()
+	return nil
+}
+
+// forwardSSHAgent connects the local SSH agent to each connection announced
+// by the node controller. It blocks until the stream closes.
+func (cmd *up) forwardSSHAgent(ctx context.Context, agentPath string) error {
+	stream, err := cmd.nodeControllerClient.ForwardSSHAgent(ctx, &node.ForwardSSHAgentRequest{
+		Auth: cmd.config.BlimpAuth(),
+	})
+	if err != nil {
+		return errors.WithContext("start forwarding", err)
+	}
+
+	for {
+		conn, err := stream.Recv()
+		if err != nil {
+			return errors.WithContext("receive connection", err)
+		}
+
+		agent, err := net.Dial("unix", agentPath)
+		if err != nil {
+			log.WithError(err).WithField("service", conn.GetService()).
+				Warn("Failed to connect to local SSH agent")
+			continue
+		}
+
+		go tunnel.ConnectSSHAgent(cmd.nodeControllerClient, agent, cmd.config.BlimpAuth(), conn.GetId())
+	}
+}
@@ -80,17 +80,14 @@ func (sp *statusPrinter) Run(ctx context.Context,
 
 func (sp *statusPrinter) syncStatus(ctx context.Context,
 	clusterManager manager.Client, auth *auth.BlimpAuth) {
-	syncStream := func(stream cluster.Manager_WatchStatusClient) error {
+	syncStream := func(stream manager.StatusStream) error {
 		for {
 			msg, err := stream.Recv()
 			switch {
+			case err != nil && ctx.Err() != nil:
+				return nil
 			case status.Code(err) == codes.Canceled:
-				select {
-				case <-ctx.Done():
-					return nil
-				default:
-					return errors.New("unexpected stream termination")
-				}
+				return errors.New("unexpected stream termination")
 			case err != nil:
 				return errors.WithContext("read stream", err)
 			}
@@ -102,16 +99,13 @@ func (sp *statusPrinter) syncStatus(ctx context.Context,
 	}
 
 	for {
-		statusStream, err := clusterManager.WatchStatus(ctx, &cluster.GetStatusRequest{
+		// The stream reconnects on its own if the connection is reset, so
+		// this only retries unexpected errors.
+		statusStream := manager.WatchStatus(ctx, clusterManager, &cluster.GetStatusRequest{
 			Auth: auth,
 		})
-		if err != nil {
-			log.WithError(err).Warn("Failed to start status watch")
-			time.Sleep(5 * time.Second)
-			continue
-		}
-
-		if err := syncStream(statusStream); err == nil {
+		err := syncStream(statusStream)
+		if err == nil {
 			return
 		}
 
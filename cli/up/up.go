@@ -3,11 +3,15 @@ package up
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
@@ -19,13 +23,14 @@ import (
 	"google.golang.org/grpc"
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/kelda/blimp/cli/analytics"
 	cliConfig "github.com/kelda/blimp/cli/config"
 	"github.com/kelda/blimp/cli/down"
 	"github.com/kelda/blimp/cli/logs"
 	"github.com/kelda/blimp/cli/manager"
 	"github.com/kelda/blimp/cli/util"
 	"github.com/kelda/blimp/pkg/auth"
-	"github.com/kelda/blimp/pkg/dockercompose"
+	"github.com/kelda/blimp/pkg/compose"
 	"github.com/kelda/blimp/pkg/errors"
 	"github.com/kelda/blimp/pkg/proto/cluster"
 	"github.com/kelda/blimp/pkg/proto/node"
@@ -43,47 +48,56 @@ func New() *cobra.Command {
 			"Up boots the docker-compose.yml in the current directory. " +
 			"If service are specified, `up` boots the services, as well as their dependencies.",
 		Run: func(_ *cobra.Command, services []string) {
-			blimpConfig, err := cliConfig.GetConfig()
-			if err != nil {
-				errors.HandleFatalError(err)
-			}
+			cmd.configure(composePaths)
 
-			// Convert the compose path to an absolute path so that the code
-			// that makes identifiers for bind volumes are unique for relative
-			// paths.
-			composePath, overridePaths, err := dockercompose.GetPaths(composePaths)
-			if err != nil {
-				if os.IsNotExist(err) {
-					log.Fatal("Docker Compose file not found.\n" +
-						"Blimp must be run from the same directory as docker-compose.yml.\n" +
-						"If you don't have a docker-compose.yml, you can use one of our examples:\n" +
-						"https://kelda.io/blimp/docs/examples/")
-				}
-				log.WithError(err).Fatal("Failed to get absolute path to Compose file")
+			if cmd.exitCodeFrom != "" {
+				cmd.abortOnContainerExit = true
+				cmd.exitCodeFrom = compose.ProjectServiceName(cmd.project, cmd.exitCodeFrom)
+			}
+			if cmd.abortOnContainerExit && cmd.detach {
+				errors.HandleFatalError(errors.NewFriendlyError(
+					"The --abort-on-container-exit and --exit-code-from flags can't be used with --detach."))
 			}
 
-			dockerConfig, err := config.Load(config.Dir())
-			if err != nil {
-				log.WithError(err).Fatal("Failed to load docker config")
+			switch cmd.portConflicts {
+			case portConflictsAuto, portConflictsPrompt, portConflictsFail:
+			default:
+				errors.HandleFatalError(errors.NewFriendlyError(
+					"The --port-conflicts flag must be either %q, %q, or %q.",
+					portConflictsAuto, portConflictsPrompt, portConflictsFail))
 			}
 
-			cmd.composePath = composePath
-			cmd.overridePaths = overridePaths
-			cmd.dockerConfig = dockerConfig
-			cmd.config = blimpConfig
 			if err := cmd.run(services); err != nil {
 				errors.HandleFatalError(err)
 			}
+
+			if cmd.exitCode != 0 {
+				os.Exit(int(cmd.exitCode))
+			}
 		},
 	}
 	cobraCmd.Flags().StringSliceVarP(&composePaths, "file", "f", nil,
 		"Specify an alternate compose file\nDefaults to docker-compose.yml and docker-compose.yaml")
 	cobraCmd.Flags().BoolVarP(&cmd.alwaysBuild, "build", "", false,
 		"Build images before starting containers")
+	cobraCmd.Flags().BoolVarP(&cmd.pinDigests, "pin-digests", "", false,
+		"Resolve image tags to digests before deploying, so that services run exactly the resolved images")
 	cobraCmd.Flags().BoolVarP(&cmd.detach, "detach", "d", false,
 		"Leave containers running after blimp up exits")
 	cobraCmd.Flags().BoolVarP(&cmd.forceBuildkit, "remote-build", "", false,
 		"Force Docker images to be built in your sandbox instead of locally")
+	cobraCmd.Flags().StringSliceVarP(&cmd.kubernetesPaths, "kubernetes", "k", nil,
+		"Kubernetes YAML files to deploy alongside the Compose services")
+	cobraCmd.Flags().BoolVarP(&cmd.abortOnContainerExit, "abort-on-container-exit", "", false,
+		"Stop all containers if any container exits")
+	cobraCmd.Flags().StringVarP(&cmd.exitCodeFrom, "exit-code-from", "", "",
+		"Stop all containers once SERVICE exits, and return its exit code.\n"+
+			"Implies --abort-on-container-exit")
+	cobraCmd.Flags().BoolVarP(&cmd.noWait, "no-wait", "", false,
+		"Forward connections to published ports immediately, rather than waiting for the service to be ready")
+	cobraCmd.Flags().StringVarP(&cmd.portConflicts, "port-conflicts", "", portConflictsAuto,
+		"What to do when a published port is already in use locally.\n"+
+			"\"auto\" uses a different local port, \"prompt\" asks which port to use, and \"fail\" exits")
 
 	cobraCmd.Flags().BoolVarP(&cmd.disableStatusOutput, "disable-status-output", "", false,
 		"Don't print status updates. Used by preview implementation.")
@@ -99,6 +113,7 @@ type up struct {
 	composePath         string
 	overridePaths       []string
 	alwaysBuild         bool
+	pinDigests          bool
 	detach              bool
 	forceBuildkit       bool
 	disableStatusOutput bool
@@ -106,9 +121,86 @@ type up struct {
 	regCreds            auth.RegistryCredentials
 	imageNamespace      string
 
+	// project is the name of the Compose project. If it's set, the services
+	// are prefixed with the project name so that they don't conflict with
+	// other projects deployed into the sandbox.
+	project string
+
+	// Kubernetes YAML files that are deployed into the sandbox alongside the
+	// Compose services.
+	kubernetesPaths     []string
+	kubernetesManifests []string
+
+	// If abortOnContainerExit is set, the sandbox is torn down once a service
+	// exits, and `blimp up` exits with its exit code. If exitCodeFrom is set,
+	// only that service is watched.
+	abortOnContainerExit bool
+	exitCodeFrom         string
+	exitCode             int32
+
+	// portConflicts is how published ports that are already in use locally
+	// are handled. See the portConflicts constants.
+	portConflicts string
+
+	// If noWait is set, connections to published ports are forwarded even
+	// if the service isn't ready yet.
+	noWait bool
+
 	nodeControllerConn   *grpc.ClientConn
 	nodeControllerClient node.ControllerClient
 	tunnelManager        tunnel.Manager
+
+	// The deployed Compose file and images. They're used to redeploy the
+	// sandbox when `develop.watch` rules trigger rebuilds.
+	composeFile string
+	builtImages map[string]string
+	rebuildLock sync.Mutex
+}
+
+// configure loads the configuration needed to boot the sandbox. It exits if
+// the configuration can't be loaded.
+func (cmd *up) configure(composePaths []string) {
+	blimpConfig, err := cliConfig.GetConfig()
+	if err != nil {
+		errors.HandleFatalError(err)
+	}
+
+	// Convert the compose path to an absolute path so that the code
+	// that makes identifiers for bind volumes are unique for relative
+	// paths.
+	composePath, overridePaths, err := compose.GetPaths(composePaths)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Fatal("Docker Compose file not found.\n" +
+				"Blimp must be run from the same directory as docker-compose.yml.\n" +
+				"If you don't have a docker-compose.yml, you can use one of our examples:\n" +
+				"https://kelda.io/blimp/docs/examples/")
+		}
+		log.WithError(err).Fatal("Failed to get absolute path to Compose file")
+	}
+
+	for _, path := range overridePaths {
+		if compose.IsPersonalOverride(path) {
+			log.Infof("Applying personal overrides from %s. "+
+				"Run `blimp config` to see the fields it changes.", filepath.Base(path))
+		}
+	}
+
+	dockerConfig, err := config.Load(config.Dir())
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load docker config")
+	}
+
+	project, err := compose.LoadProjectName(composePath, overridePaths)
+	if err != nil {
+		errors.HandleFatalError(err)
+	}
+
+	cmd.project = project
+	cmd.composePath = composePath
+	cmd.overridePaths = overridePaths
+	cmd.dockerConfig = dockerConfig
+	cmd.config = blimpConfig
 }
 
 func (cmd *up) run(services []string) error {
@@ -125,23 +217,187 @@ func (cmd *up) run(services []string) error {
 		}
 	}
 
-	err := util.TakeUpLock()
+	bootStart := time.Now()
+	sess, err := cmd.start(services)
+	analytics.RecordBoot(time.Since(bootStart), err)
 	if err != nil {
 		return err
 	}
-	defer util.ReleaseUpLock()
+	defer sess.stop()
+
+	// Start the GUI.
+	guiCtx, cancelGui := context.WithCancel(context.Background())
+	guiError := make(chan error, 1)
+	go func() {
+		guiError <- cmd.runGUI(guiCtx, sess.compose)
+	}()
+
+	// Wait for the user to exit, or for something to error.
+	exit := make(chan os.Signal, 1)
+	signal.Notify(exit, syscall.SIGINT, syscall.SIGTERM)
 
-	parsedCompose, err := dockercompose.Load(cmd.composePath, cmd.overridePaths, services)
+	exitedServices := make(chan exitedService, 1)
+	exitWatchError := make(chan error, 1)
+	if cmd.abortOnContainerExit {
+		var watchedServices []string
+		if cmd.exitCodeFrom != "" {
+			watchedServices = []string{cmd.exitCodeFrom}
+		}
+
+		go func() {
+			exited, err := cmd.waitForExit(sess.ctx, watchedServices)
+			if err != nil {
+				exitWatchError <- err
+				return
+			}
+			exitedServices <- exited
+		}()
+	}
+
+	abort := func(exited exitedService) error {
+		cancelGui()
+		fmt.Printf("%s exited with code %d. Stopping all containers.\n", exited.name, exited.exitCode)
+		cmd.exitCode = exited.exitCode
+
+		sess.stopSyncthing()
+		return down.Run(cmd.config.BlimpAuth(), cmd.project, false)
+	}
+
+	select {
+	case err := <-sess.syncthingError:
+		return errors.WithContext("syncthing error", err)
+
+	case err := <-guiError:
+		if err != nil {
+			return errors.WithContext("run gui error", err)
+		}
+
+		// Tear down the sandbox and propagate the exit code.
+		if cmd.abortOnContainerExit {
+			select {
+			case err := <-exitWatchError:
+				return errors.WithContext("watch for exited services", err)
+			case exited := <-exitedServices:
+				return abort(exited)
+			}
+		}
+		log.Info("All containers have completed. Exiting.")
+		return nil
+
+	case err := <-sess.tunnelsError:
+		return errors.WithContext("tunnel crashed", err)
+
+	case err := <-exitWatchError:
+		return errors.WithContext("watch for exited services", err)
+
+	case exited := <-exitedServices:
+		return abort(exited)
+
+	case <-exit:
+		cancelGui()
+
+		if cmd.detach {
+			fmt.Println("Cleaning up local processes. The remote containers will continue running.")
+			fmt.Println("Use `blimp down` to clean up your remote sandbox.")
+		}
+
+		// If we spawned a child process for Syncthing, terminate it gracefully.
+		sess.stopSyncthing()
+
+		if !cmd.detach {
+			fmt.Println("Cleaning up your containers and volumes.")
+			fmt.Println("To keep your sandbox running, use `blimp up -d` instead.")
+
+			downFinished := make(chan error)
+			go func() {
+				downFinished <- down.Run(cmd.config.BlimpAuth(), cmd.project, false)
+			}()
+
+			select {
+			case err := <-downFinished:
+				return err
+			case <-exit:
+				// This is the second signal, so we exit immediately without
+				// waiting for `blimp down` to finish. We exit naturally without
+				// `os.Exit` so that the lock is released.
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// session is a sandbox deployed by `blimp up`, along with the local processes
+// that keep it in sync with the local machine.
+type session struct {
+	compose composeTypes.Project
+
+	// ctx is cancelled when the session is stopped.
+	ctx context.Context
+
+	syncthingError  chan error
+	tunnelsError    chan error
+	syncing         bool
+	cancelSyncthing func()
+
+	cleanups []func()
+}
+
+// start deploys the Compose file to the sandbox, and starts syncing files and
+// forwarding ports. It doesn't wait for the services to boot.
+func (cmd *up) start(services []string) (_ *session, err error) {
+	if err := util.TakeUpLock(); err != nil {
+		return nil, err
+	}
+
+	// Clean up if booting fails.
+	sess := &session{}
+	sess.addCleanup(util.ReleaseUpLock)
+	defer func() {
+		if err != nil {
+			sess.stop()
+		}
+	}()
+
+	parsedCompose, err := compose.Load(cmd.composePath, cmd.overridePaths, services)
 	if err != nil {
-		return errors.WithContext("load compose file", err)
+		return nil, errors.WithContext("load compose file", err)
+	}
+
+	if err := compose.ApplyProjectName(&parsedCompose, cmd.project); err != nil {
+		return nil, errors.WithContext("apply project name", err)
+	}
+
+	if cmd.exitCodeFrom != "" && !hasService(parsedCompose, cmd.exitCodeFrom) {
+		return nil, errors.NewFriendlyError("The --exit-code-from service %q isn't in the Docker Compose file.",
+			cmd.exitCodeFrom)
 	}
 
-	parsedComposeBytes, err := dockercompose.Marshal(parsedCompose)
+	// Listen on the published ports before deploying so that conflicts are
+	// resolved before any other output is printed.
+	publishedPorts, err := cmd.listenPublishedPorts(parsedCompose)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	sess.addCleanup(func() {
+		for _, p := range publishedPorts {
+			p.listener.Close()
+		}
+	})
 
-	stClient := cmd.makeSyncthingClient(parsedCompose)
+	cmd.kubernetesManifests = nil
+	for _, path := range cmd.kubernetesPaths {
+		manifest, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.WithContext("read kubernetes manifest", err)
+		}
+		cmd.kubernetesManifests = append(cmd.kubernetesManifests, string(manifest))
+	}
+
+	stClient, err := cmd.makeSyncthingClient(parsedCompose)
+	if err != nil {
+		return nil, err
+	}
 	idPathMap := stClient.GetIDPathMap()
 
 	regCreds, err := auth.GetLocalRegistryCredentials(cmd.dockerConfig)
@@ -152,16 +408,26 @@ func (cmd *up) run(services []string) error {
 	}
 	cmd.regCreds = regCreds
 
+	if err := cmd.pinImageDigests(&parsedCompose); err != nil {
+		return nil, err
+	}
+	sess.compose = parsedCompose
+
+	parsedComposeBytes, err := compose.Marshal(parsedCompose)
+	if err != nil {
+		return nil, err
+	}
+
 	// Start creating the sandbox immediately so that the systems services
 	// start booting as soon as possible.
-	if err := cmd.createSandbox(string(parsedComposeBytes), idPathMap); err != nil {
+	if err := cmd.createSandbox(string(parsedComposeBytes), stClient); err != nil {
 		log.WithError(err).Fatal("Failed to create development sandbox")
 	}
-	defer cmd.nodeControllerConn.Close()
+	sess.addCleanup(func() { cmd.nodeControllerConn.Close() })
 
 	builtImages, err := cmd.buildImages(parsedCompose)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Send the boot request to the cluster manager.
@@ -169,19 +435,42 @@ func (cmd *up) run(services []string) error {
 	go pp.Run()
 
 	_, err = manager.C.DeployToSandbox(context.Background(), &cluster.DeployRequest{
-		Auth:        cmd.config.BlimpAuth(),
-		ComposeFile: string(parsedComposeBytes),
-		BuiltImages: builtImages,
+		Auth:                cmd.config.BlimpAuth(),
+		ComposeFile:         string(parsedComposeBytes),
+		BuiltImages:         builtImages,
+		KubernetesManifests: cmd.kubernetesManifests,
+		Project:             cmd.project,
 	})
 	pp.Stop()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	cmd.composeFile = string(parsedComposeBytes)
+	cmd.builtImages = builtImages
+
+	var cancelWatches func()
+	sess.ctx, cancelWatches = context.WithCancel(context.Background())
+	sess.addCleanup(cancelWatches)
+	cmd.startWatches(sess.ctx, parsedCompose)
+	if len(idPathMap) != 0 {
+		if err = cmd.startReloadHooks(sess.ctx, parsedCompose, idPathMap); err != nil {
+			return nil, errors.WithContext("start reload hooks", err)
+		}
+	}
+	if err = cmd.startReadyHooks(sess.ctx, parsedCompose); err != nil {
+		return nil, errors.WithContext("start ready hooks", err)
+	}
+	if err = cmd.startSSHAgentForwarding(sess.ctx, parsedCompose); err != nil {
+		return nil, errors.WithContext("start SSH agent forwarding", err)
 	}
 
-	syncthingError := make(chan error, 1)
+	sess.syncthingError = make(chan error, 1)
 	syncthingCtx, cancelSyncthing := context.WithCancel(context.Background())
-	defer cancelSyncthing()
+	sess.cancelSyncthing = cancelSyncthing
+	sess.addCleanup(cancelSyncthing)
 	if len(idPathMap) != 0 {
+		sess.syncing = true
+		syncthingError := sess.syncthingError
 		go func() {
 			defer close(syncthingError)
 
@@ -202,101 +491,76 @@ func (cmd *up) run(services []string) error {
 		}()
 	}
 
-	// Start the tunnels.
-	var tunnelsErrGroup errgroup.Group
-	startedTunnels := false
-	for _, svc := range parsedCompose.Services {
-		svc := svc
-		for _, mapping := range svc.Ports {
-			mapping := mapping
-			if mapping.Protocol == "tcp" {
-				startedTunnels = true
-				tunnelsErrGroup.Go(func() error {
-					return cmd.tunnelManager.Run(mapping.HostIP, mapping.Published, svc.Name, mapping.Target, nil)
-				})
-			}
+	// Start the tunnels. Unless --no-wait is set, connections are held until
+	// the service is ready so that they aren't reset by a service that's
+	// still booting.
+	tunnelManager := cmd.tunnelManager
+	if !cmd.noWait && len(publishedPorts) != 0 {
+		var services []string
+		for _, p := range publishedPorts {
+			services = append(services, p.service)
 		}
+
+		gate := newReadinessGate(services)
+		go gate.Run(sess.ctx, cmd.config.BlimpAuth())
+		tunnelManager = tunnelManager.WaitForReady(gate.Wait)
+	}
+
+	var tunnelsErrGroup errgroup.Group
+	for _, p := range publishedPorts {
+		p := p
+		tunnelsErrGroup.Go(func() error {
+			return tunnelManager.Serve(p.listener, p.service, p.target)
+		})
 	}
-	tunnelsError := make(chan error, 1)
-	if startedTunnels {
+	sess.tunnelsError = make(chan error, 1)
+	if len(publishedPorts) != 0 {
 		go func() {
-			tunnelsError <- tunnelsErrGroup.Wait()
+			sess.tunnelsError <- tunnelsErrGroup.Wait()
 		}()
 	}
+	return sess, nil
+}
 
-	// Start the GUI.
-	guiCtx, cancelGui := context.WithCancel(context.Background())
-	guiError := make(chan error, 1)
-	go func() {
-		guiError <- cmd.runGUI(guiCtx, parsedCompose)
-	}()
-
-	// Wait for the user to exit, or for something to error.
-	exit := make(chan os.Signal, 1)
-	signal.Notify(exit, syscall.SIGINT, syscall.SIGTERM)
-
-	select {
-	case err := <-syncthingError:
-		return errors.WithContext("syncthing error", err)
-
-	case err := <-guiError:
-		if err != nil {
-			return errors.WithContext("run gui error", err)
-		}
-		log.Info("All containers have completed. Exiting.")
-		return nil
-
-	case err := <-tunnelsError:
-		return errors.WithContext("tunnel crashed", err)
-
-	case <-exit:
-		cancelGui()
-
-		if cmd.detach {
-			fmt.Println("Cleaning up local processes. The remote containers will continue running.")
-			fmt.Println("Use `blimp down` to clean up your remote sandbox.")
-		}
-
-		// If we spawned a child process for Syncthing, terminate it gracefully.
-		if len(idPathMap) != 0 {
-			cancelSyncthing()
-			<-syncthingError
-		}
-
-		if !cmd.detach {
-			fmt.Println("Cleaning up your containers and volumes.")
-			fmt.Println("To keep your sandbox running, use `blimp up -d` instead.")
+func (sess *session) addCleanup(f func()) {
+	sess.cleanups = append(sess.cleanups, f)
+}
 
-			downFinished := make(chan error)
-			go func() {
-				downFinished <- down.Run(cmd.config.BlimpAuth(), false)
-			}()
+// stopSyncthing gracefully terminates Syncthing, if it's running.
+func (sess *session) stopSyncthing() {
+	if sess.syncing {
+		sess.cancelSyncthing()
+		<-sess.syncthingError
+	}
+}
 
-			select {
-			case err := <-downFinished:
-				return err
-			case <-exit:
-				// This is the second signal, so we exit immediately without
-				// waiting for `blimp down` to finish. We exit naturally without
-				// `os.Exit` so that the lock is released.
-			}
-		}
-		return nil
+// stop stops the local processes. It doesn't affect the sandbox.
+func (sess *session) stop() {
+	for i := len(sess.cleanups) - 1; i >= 0; i-- {
+		sess.cleanups[i]()
 	}
-	return nil
 }
 
-func (cmd *up) createSandbox(composeCfg string, idPathMap map[string]string) error {
+func (cmd *up) createSandbox(composeCfg string, stClient syncthing.Client) error {
+	lineEndingRules := map[string]*cluster.FilePatterns{}
+	for id, patterns := range stClient.GetLineEndingRules() {
+		lineEndingRules[id] = &cluster.FilePatterns{Patterns: patterns}
+	}
+
 	pp := util.NewProgressPrinter(os.Stdout, "Booting cloud sandbox")
 	go pp.Run()
 	defer pp.Stop()
 
 	resp, err := manager.C.CreateSandbox(context.TODO(),
 		&cluster.CreateSandboxRequest{
-			Auth:                cmd.config.BlimpAuth(),
-			ComposeFile:         composeCfg,
-			RegistryCredentials: cmd.regCreds.ToProtobuf(),
-			SyncedFolders:       idPathMap,
+			Auth:                   cmd.config.BlimpAuth(),
+			ComposeFile:            composeCfg,
+			RegistryCredentials:    cmd.regCreds.ToProtobuf(),
+			SyncedFolders:          stClient.GetIDPathMap(),
+			DeltaSyncFolders:       stClient.GetDeltaFolders(),
+			SyncFolderOwners:       stClient.GetFolderOwners(),
+			InferExecutableFolders: stClient.GetInferExecutableFolders(),
+			LineEndingRules:        lineEndingRules,
 		})
 	if err != nil {
 		return err
@@ -360,9 +624,14 @@ func (cmd *up) runGUI(ctx context.Context, parsedCompose composeTypes.Project) e
 	}.Run(ctx)
 }
 
-func (cmd *up) makeSyncthingClient(dcCfg composeTypes.Project) syncthing.Client {
+func (cmd *up) makeSyncthingClient(dcCfg composeTypes.Project) (syncthing.Client, error) {
 	var allVolumes []syncthing.BindVolume
 	for _, svc := range dcCfg.Services {
+		ext, err := compose.GetServiceExtension(svc)
+		if err != nil {
+			return syncthing.Client{}, err
+		}
+
 		// bindVolumes maps target paths (the paths to be mounted in the
 		// container) to syncthing.BindVolume structs that represent the
 		// necessary syncing information.
@@ -372,9 +641,53 @@ func (cmd *up) makeSyncthingClient(dcCfg composeTypes.Project) syncthing.Client
 			if v.Type != composeTypes.VolumeTypeBind {
 				continue
 			}
-			bindVolumes[v.Target] = syncthing.BindVolume{
-				LocalPath: v.Source,
+
+			volumeExt := ext.Volumes[v.Target]
+			volume := syncthing.BindVolume{
+				LocalPath:          v.Source,
+				DisableCompression: volumeExt.Compression != nil && !*volumeExt.Compression,
+				Delta:              volumeExt.Delta,
+
+				NormalizeLineEndings: volumeExt.NormalizeLineEndings,
+			}
+
+			for _, pattern := range volumeExt.NormalizeLineEndings {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return syncthing.Client{}, errors.NewFriendlyError(
+						"Invalid normalize_line_endings pattern %q for volume %s in service %s: %s",
+						pattern, v.Target, svc.Name, err)
+				}
+			}
+
+			if volumeExt.Owner != "" {
+				owner, err := syncthing.ParseOwner(volumeExt.Owner)
+				if err != nil {
+					return syncthing.Client{}, errors.NewFriendlyError(
+						"Invalid owner %q for volume %s in service %s. "+
+							"It should be formatted like `1000:1000`.",
+						volumeExt.Owner, v.Target, svc.Name)
+				}
+				volume.Owner = owner.String()
+			}
+
+			maxFileSize, err := volumeExt.GetMaxFileSize()
+			if err != nil {
+				return syncthing.Client{}, err
+			}
+			if maxFileSize != 0 {
+				largeFiles, err := syncthing.FindLargeFiles(v.Source, maxFileSize)
+				if err != nil {
+					return syncthing.Client{}, errors.WithContext("find large files", err)
+				}
+
+				for _, file := range largeFiles {
+					log.Warnf("Not syncing %s (%d bytes) since it's larger than the "+
+						"max_file_size of %s.", filepath.Join(v.Source, file.Path),
+						file.Size, volumeExt.MaxFileSize)
+					volume.Masks = append(volume.Masks, file.Path)
+				}
 			}
+			bindVolumes[v.Target] = volume
 		}
 
 		// Now, mask off any native volumes that fall under these mounts.
@@ -405,12 +718,16 @@ func (cmd *up) makeSyncthingClient(dcCfg composeTypes.Project) syncthing.Client
 	}
 
 	for _, namedVol := range dcCfg.Volumes {
-		source, ok := dockercompose.ParseNamedBindVolume(namedVol)
+		source, ok := compose.ParseNamedBindVolume(namedVol)
 
 		if ok {
 			allVolumes = append(allVolumes, syncthing.BindVolume{LocalPath: source})
 		}
 	}
 
-	return syncthing.NewClient(allVolumes)
+	maxUpload, maxDownload, err := util.ParseBandwidthLimits(cmd.config.ConfigFile.Sync)
+	if err != nil {
+		return syncthing.Client{}, errors.WithContext("parse sync bandwidth limits", err)
+	}
+	return syncthing.NewClient(allVolumes).WithBandwidthLimits(maxUpload, maxDownload), nil
 }
@@ -0,0 +1,179 @@
+package up
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/pkg/build"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// watchPollInterval is how often watched paths are checked for changes.
+	watchPollInterval = time.Second
+
+	// watchSyncDelay is how long to wait for Syncthing to sync changes before
+	// restarting a service. Syncthing batches file change notifications for a
+	// second before syncing them.
+	watchSyncDelay = 3 * time.Second
+)
+
+// startWatches starts watching the paths in each service's `develop.watch`
+// configuration. Changes to paths with the sync action don't need any
+// handling here since they're synced by Syncthing like any other bind volume.
+func (cmd *up) startWatches(ctx context.Context, parsedCompose composeTypes.Project) {
+	for _, svc := range parsedCompose.Services {
+		for _, rule := range compose.GetDevelopConfig(svc).Watch {
+			if rule.Action == compose.WatchActionSync {
+				continue
+			}
+
+			go cmd.watch(ctx, parsedCompose.Name, svc, rule)
+		}
+	}
+}
+
+func (cmd *up) watch(ctx context.Context, projectName string,
+	svc composeTypes.ServiceConfig, rule compose.WatchRule) {
+	prev, err := snapshotWatchPath(rule)
+	if err != nil {
+		log.WithError(err).WithField("path", rule.Path).Warn("Failed to watch path")
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		curr, err := snapshotWatchPath(rule)
+		if err != nil {
+			log.WithError(err).WithField("path", rule.Path).Debug("Failed to check path for changes")
+			continue
+		}
+
+		if reflect.DeepEqual(prev, curr) {
+			continue
+		}
+		prev = curr
+
+		var handleErr error
+		switch rule.Action {
+		case compose.WatchActionSyncRestart:
+			fmt.Printf("Detected changes in %s. Restarting %s.\n", rule.Path, svc.Name)
+			handleErr = cmd.restartAfterSync(ctx, svc.Name)
+		case compose.WatchActionRebuild:
+			fmt.Printf("Detected changes in %s. Rebuilding %s.\n", rule.Path, svc.Name)
+			handleErr = cmd.rebuild(ctx, projectName, svc)
+		}
+		if handleErr != nil {
+			log.WithError(handleErr).WithField("service", svc.Name).Warn("Failed to apply file changes")
+		}
+	}
+}
+
+func (cmd *up) restartAfterSync(ctx context.Context, svc string) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(watchSyncDelay):
+	}
+
+	_, err := manager.C.Restart(ctx, &cluster.RestartRequest{
+		Auth:    cmd.config.BlimpAuth(),
+		Service: svc,
+	})
+	return err
+}
+
+// rebuild rebuilds the image for the given service, and redeploys the sandbox
+// so that the service uses the new image.
+func (cmd *up) rebuild(ctx context.Context, projectName string, svc composeTypes.ServiceConfig) error {
+	// Rebuilds modify the deployed images, so they must happen one at a time.
+	cmd.rebuildLock.Lock()
+	defer cmd.rebuildLock.Unlock()
+
+	builder, err := cmd.getImageBuilder(projectName)
+	if err != nil {
+		return errors.WithContext("get image builder", err)
+	}
+
+	images, err := builder.BuildAndPush(map[string]build.BuildPushConfig{
+		svc.Name: {
+			BuildConfig: *svc.Build,
+			ImageName:   build.RemoteImageName(cmd.composePath, svc.Name, cmd.imageNamespace),
+			ForceBuild:  true,
+		},
+	})
+	if err != nil {
+		return errors.WithContext("build image", err)
+	}
+
+	for name, image := range images {
+		cmd.builtImages[name] = image
+	}
+
+	_, err = manager.C.DeployToSandbox(ctx, &cluster.DeployRequest{
+		Auth:                cmd.config.BlimpAuth(),
+		ComposeFile:         cmd.composeFile,
+		BuiltImages:         cmd.builtImages,
+		KubernetesManifests: cmd.kubernetesManifests,
+		Project:             cmd.project,
+	})
+	if err != nil {
+		return errors.WithContext("deploy", err)
+	}
+	return nil
+}
+
+type watchFileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshotWatchPath returns the state of all the files covered by the given
+// rule, so that changes can be detected by comparing snapshots.
+func snapshotWatchPath(rule compose.WatchRule) (map[string]watchFileState, error) {
+	snapshot := map[string]watchFileState{}
+	err := filepath.Walk(rule.Path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// The file may have been deleted while we were walking.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		relPath, err := filepath.Rel(rule.Path, path)
+		if err != nil {
+			return err
+		}
+
+		if relPath != "." && rule.Ignored(relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		snapshot[relPath] = watchFileState{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		}
+		return nil
+	})
+	return snapshot, err
+}
@@ -0,0 +1,131 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kelda/blimp/cli/config"
+	"github.com/kelda/blimp/cli/manager"
+	"github.com/kelda/blimp/cli/output"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func New() *cobra.Command {
+	var period string
+	var outputFormat output.Format
+	cobraCmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Print the resources used by your sandbox",
+		Long: "Print the CPU, memory, storage, and network egress used by your " +
+			"sandbox during a month.\n\n" +
+			"CPU and memory are measured based on the resources reserved for " +
+			"your running services, and storage is measured based on the size " +
+			"of your sandbox's volume, which exists until `blimp down --volumes` is run.",
+		Run: func(_ *cobra.Command, args []string) {
+			if err := run(period, outputFormat); err != nil {
+				errors.HandleFatalError(err)
+			}
+		},
+	}
+	cobraCmd.Flags().StringVar(&period, "month", "",
+		"The month to report usage for, formatted as YYYY-MM. Defaults to the current month.")
+	output.AddFlag(cobraCmd, &outputFormat)
+	return cobraCmd
+}
+
+// Usage is the schema for the machine-readable output of `blimp usage`.
+type Usage struct {
+	// Period is the month of the usage, formatted as YYYY-MM.
+	Period string    `json:"period"`
+	Usage  Resources `json:"usage"`
+
+	// Quota is zero for resources that don't have a quota.
+	Quota Resources `json:"quota"`
+}
+
+type Resources struct {
+	CPUCoreHours      float64 `json:"cpuCoreHours"`
+	BoostCPUCoreHours float64 `json:"boostCpuCoreHours,omitempty"`
+	MemoryGiBHours    float64 `json:"memoryGiBHours"`
+	StorageGiBHours   float64 `json:"storageGiBHours"`
+	EgressBytes       int64   `json:"egressBytes"`
+}
+
+func run(period string, outputFormat output.Format) error {
+	blimpConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	resp, err := manager.C.GetUsage(context.Background(), &cluster.GetUsageRequest{
+		Auth:   blimpConfig.BlimpAuth(),
+		Period: period,
+	})
+	if err != nil {
+		return err
+	}
+
+	quota := resp.GetQuota()
+	var usage cluster.UsageRecord
+	if len(resp.GetUsage()) == 1 {
+		usage = *resp.GetUsage()[0]
+	}
+
+	if outputFormat != output.Text {
+		return output.Print(outputFormat, Usage{
+			Period: quota.GetPeriod(),
+			Usage: Resources{
+				CPUCoreHours:      usage.CpuCoreHours,
+				BoostCPUCoreHours: usage.BoostCpuCoreHours,
+				MemoryGiBHours:    usage.MemoryGibHours,
+				StorageGiBHours:   usage.StorageGibHours,
+				EgressBytes:       usage.EgressBytes,
+			},
+			Quota: Resources{
+				CPUCoreHours:    quota.GetCpuCoreHours(),
+				MemoryGiBHours:  quota.GetMemoryGibHours(),
+				StorageGiBHours: quota.GetStorageGibHours(),
+				EgressBytes:     quota.GetEgressBytes(),
+			},
+		})
+	}
+
+	fmt.Printf("Usage for %s:\n\n", quota.GetPeriod())
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "RESOURCE\tUSED\tQUOTA")
+	fmt.Fprintf(w, "CPU\t%.2f core-hours\t%s\n",
+		usage.CpuCoreHours, formatQuota(quota.GetCpuCoreHours(), "core-hours"))
+	if usage.BoostCpuCoreHours > 0 {
+		fmt.Fprintf(w, "  From boosts\t%.2f core-hours\t\n", usage.BoostCpuCoreHours)
+	}
+	fmt.Fprintf(w, "Memory\t%.2f GiB-hours\t%s\n",
+		usage.MemoryGibHours, formatQuota(quota.GetMemoryGibHours(), "GiB-hours"))
+	fmt.Fprintf(w, "Storage\t%.2f GiB-hours\t%s\n",
+		usage.StorageGibHours, formatQuota(quota.GetStorageGibHours(), "GiB-hours"))
+
+	egressQuota := "none"
+	if quota.GetEgressBytes() != 0 {
+		egressQuota = FormatBytes(quota.GetEgressBytes())
+	}
+	fmt.Fprintf(w, "Egress\t%s\t%s\n", FormatBytes(usage.EgressBytes), egressQuota)
+	return nil
+}
+
+func formatQuota(quota float64, units string) string {
+	if quota == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%.2f %s", quota, units)
+}
+
+// FormatBytes formats the given number of bytes in human readable units.
+func FormatBytes(bytes int64) string {
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
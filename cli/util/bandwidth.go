@@ -0,0 +1,39 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// ParseRate parses rates such as `5MB/s` into bytes per second. The `/s`
+// suffix is optional. Empty rates are unlimited, and are returned as zero.
+func ParseRate(str string) (int64, error) {
+	if str == "" {
+		return 0, nil
+	}
+
+	rate, err := compose.ParseSize(strings.TrimSuffix(strings.ToLower(str), "/s"))
+	if err != nil {
+		return 0, errors.NewFriendlyError(
+			"Invalid rate %q. It should be formatted like `5MB/s`.", str)
+	}
+	return rate, nil
+}
+
+// ParseBandwidthLimits returns the upload and download limits in bytes per
+// second.
+func ParseBandwidthLimits(limits cfgdir.BandwidthLimits) (upload, download int64, err error) {
+	upload, err = ParseRate(limits.MaxUpload)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	download, err = ParseRate(limits.MaxDownload)
+	if err != nil {
+		return 0, 0, err
+	}
+	return upload, download, nil
+}
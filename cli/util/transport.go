@@ -0,0 +1,208 @@
+package util
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/tunnel"
+	"github.com/kelda/blimp/pkg/wsconn"
+)
+
+// transport configures how Dial connects to Blimp's servers.
+var transport struct {
+	webSocketURL    string
+	forceWebSocket  bool
+	maxMessageBytes int
+}
+
+// ConfigureTransport sets up Dial to use the WebSocket tunnel configured in
+// the user's blimp.yaml, and applies the bandwidth limits for tunnels.
+func ConfigureTransport(cfg cfgdir.Config) error {
+	transport.webSocketURL = cfg.WebSocketURL
+	transport.forceWebSocket = cfg.ForceWebSocket
+	transport.maxMessageBytes = cfg.MaxMessageBytes
+
+	upload, download, err := ParseBandwidthLimits(cfg.Tunnels)
+	if err != nil {
+		return errors.WithContext("parse tunnel bandwidth limits", err)
+	}
+	tunnel.SetLimits(tunnel.Limits{Upload: upload, Download: download})
+	return nil
+}
+
+// dialContext opens the connections for gRPC. Connections go through the
+// proxy set in the environment, if any, and fall back to the WebSocket tunnel
+// if it's configured.
+func dialContext(ctx context.Context, addr string) (net.Conn, error) {
+	if transport.webSocketURL != "" && transport.forceWebSocket {
+		return dialWebSocket(ctx, addr)
+	}
+
+	conn, err := dialDirect(ctx, addr)
+	if err == nil || transport.webSocketURL == "" {
+		return conn, err
+	}
+
+	log.WithError(err).WithField("address", addr).Debug(
+		"Failed to connect. Falling back to the WebSocket tunnel.")
+	return dialWebSocket(ctx, addr)
+}
+
+// dialDirect connects to the address, through a proxy if one is set in the
+// environment. HTTPS_PROXY and ALL_PROXY may be HTTP, HTTPS, or SOCKS5
+// proxies, and NO_PROXY is respected.
+func dialDirect(ctx context.Context, addr string) (net.Conn, error) {
+	proxyURL, err := getProxy(addr)
+	if err != nil {
+		return nil, errors.WithContext("get proxy", err)
+	}
+
+	if proxyURL == nil {
+		return (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https":
+		return dialHTTPProxy(ctx, proxyURL, addr)
+	case "socks5", "socks5h":
+		// The SOCKS5 dialer always lets the proxy resolve hostnames.
+		proxyURL.Scheme = "socks5"
+		dialer, err := proxy.FromURL(proxyURL, proxy.Direct)
+		if err != nil {
+			return nil, errors.WithContext("create SOCKS5 dialer", err)
+		}
+
+		if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+			return contextDialer.DialContext(ctx, "tcp", addr)
+		}
+		return dialer.Dial("tcp", addr)
+	default:
+		return nil, errors.NewFriendlyError(
+			"Unsupported proxy scheme %q. Blimp supports http, https, and socks5 proxies.",
+			proxyURL.Scheme)
+	}
+}
+
+func getProxy(addr string) (*url.URL, error) {
+	getenv := func(keys ...string) string {
+		for _, key := range keys {
+			if val := os.Getenv(key); val != "" {
+				return val
+			}
+		}
+		return ""
+	}
+
+	proxyConfig := httpproxy.Config{
+		HTTPSProxy: getenv("HTTPS_PROXY", "https_proxy", "ALL_PROXY", "all_proxy"),
+		NoProxy:    getenv("NO_PROXY", "no_proxy"),
+	}
+	return proxyConfig.ProxyFunc()(&url.URL{Scheme: "https", Host: addr})
+}
+
+// dialHTTPProxy connects to the address through the HTTP proxy using the
+// CONNECT method.
+func dialHTTPProxy(ctx context.Context, proxyURL *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, errors.WithContext("dial proxy", err)
+	}
+
+	if proxyURL.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: addr},
+		Header: http.Header{},
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, errors.WithContext("send CONNECT request", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, errors.WithContext("read CONNECT response", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, errors.New("proxy refused connection: %s", resp.Status)
+	}
+	return bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// bufferedConn reads any data that was buffered while reading the proxy's
+// response before reading from the connection.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// dialWebSocket connects to the address through the manager's WebSocket
+// tunnel. The WebSocket connection also respects the proxy settings in the
+// environment.
+func dialWebSocket(ctx context.Context, addr string) (net.Conn, error) {
+	tunnelURL, err := url.Parse(transport.webSocketURL)
+	if err != nil {
+		return nil, errors.NewFriendlyError(
+			"Invalid websocket_url %q in your blimp.yaml: %s", transport.webSocketURL, err)
+	}
+
+	query := tunnelURL.Query()
+	query.Set("target", addr)
+	tunnelURL.RawQuery = query.Encode()
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 30 * time.Second,
+	}
+	ws, _, err := dialer.DialContext(ctx, tunnelURL.String(), nil)
+	if err != nil {
+		return nil, errors.WithContext("dial websocket tunnel", err)
+	}
+	return wsconn.New(ws), nil
+}
@@ -10,19 +10,41 @@ import (
 	"google.golang.org/grpc/keepalive"
 
 	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/msgsize"
 )
 
-func Dial(addr, certPEM, serverNameOverride string) (*grpc.ClientConn, error) {
+// Dial connects to one of Blimp's gRPC servers. `opts` are applied after the
+// default options.
+func Dial(addr, certPEM, serverNameOverride string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	cp := x509.NewCertPool()
 	if !cp.AppendCertsFromPEM([]byte(certPEM)) {
 		return nil, errors.New("failed to parse cert")
 	}
 
-	return grpc.Dial(addr,
+	maxMessageBytes := MaxMessageBytes()
+	return grpc.Dial(addr, append([]grpc.DialOption{
 		grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(cp, serverNameOverride)),
+		grpc.WithContextDialer(dialContext),
 		// AWS ELBs close connections that are inactive for 60s, so we set a
-		// keepalive interval lower than this.
-		grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: 30 * time.Second}),
-		grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)),
-		grpc.WithUnaryInterceptor(errors.UnaryClientInterceptor))
+		// keepalive interval lower than this. The pings also act as
+		// heartbeats that let the servers clean up after killed CLIs.
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    30 * time.Second,
+			Timeout: 20 * time.Second,
+		}),
+		grpc.WithDefaultCallOptions(
+			grpc.UseCompressor(gzip.Name),
+			grpc.MaxCallRecvMsgSize(maxMessageBytes)),
+		grpc.WithChainUnaryInterceptor(errors.UnaryClientInterceptor,
+			msgsize.UnaryClientInterceptor(maxMessageBytes)),
+		grpc.WithChainStreamInterceptor(msgsize.StreamClientInterceptor(maxMessageBytes)),
+	}, opts...)...)
+}
+
+// MaxMessageBytes returns the largest gRPC message that the CLI accepts.
+func MaxMessageBytes() int {
+	if transport.maxMessageBytes > 0 {
+		return transport.maxMessageBytes
+	}
+	return msgsize.DefaultCLILimit
 }
@@ -0,0 +1,762 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/cluster-controller/volume"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// addonLabel marks the pods that run add-ons so that they're not deleted
+	// by deployCustomerPods.
+	addonLabel = "blimp.addon"
+
+	// addonMountPath is where the add-on's directory in the sandbox's volume
+	// is mounted. The database's data is stored in a subdirectory so that
+	// snapshots can be stored next to it.
+	addonMountPath    = "/blimp-addon"
+	addonDataDir      = addonMountPath + "/data"
+	addonSnapshotsDir = addonMountPath + "/snapshots"
+
+	// addonUser and addonDatabase are the credentials and database created
+	// in each add-on.
+	addonUser     = "blimp"
+	addonDatabase = "blimp"
+)
+
+var (
+	// Add-on names are used as hostnames, so they must be valid DNS labels.
+	addonNamePattern    = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+	addonVersionPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+	snapshotNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+)
+
+// addon is stored in the sandbox namespace's annotations.
+type addon struct {
+	Name    string `json:"name"`
+	Engine  string `json:"engine"`
+	Version string `json:"version"`
+
+	// Services are the services that the connection environment variables
+	// are injected into. If empty, they're injected into all services.
+	Services []string `json:"services,omitempty"`
+	Password string   `json:"password"`
+}
+
+// addonEngine describes how to run and back up a type of database.
+type addonEngine struct {
+	image          string
+	defaultVersion string
+	port           int32
+	command        []string
+	args           []string
+	containerEnv   func(a addon) []corev1.EnvVar
+	connectionEnv  func(a addon) map[string]string
+
+	// snapshotCmd and restoreCmd are shell commands that are run in the
+	// add-on's container. The %s is replaced with the path of the snapshot.
+	snapshotCmd string
+	restoreCmd  string
+	snapshotExt string
+
+	// restartAfterRestore is true for engines that only load restored data
+	// when they boot.
+	restartAfterRestore bool
+}
+
+var addonEngines = map[string]addonEngine{
+	"postgres": {
+		image:          "postgres",
+		defaultVersion: "14",
+		port:           5432,
+		containerEnv: func(a addon) []corev1.EnvVar {
+			return []corev1.EnvVar{
+				{Name: "POSTGRES_USER", Value: addonUser},
+				{Name: "POSTGRES_PASSWORD", Value: a.Password},
+				{Name: "POSTGRES_DB", Value: addonDatabase},
+				{Name: "PGDATA", Value: addonDataDir},
+			}
+		},
+		connectionEnv: func(a addon) map[string]string {
+			return map[string]string{
+				"DATABASE_URL": fmt.Sprintf("postgres://%s:%s@%s:5432/%s",
+					addonUser, a.Password, a.Name, addonDatabase),
+				"PGHOST":     a.Name,
+				"PGPORT":     "5432",
+				"PGUSER":     addonUser,
+				"PGPASSWORD": a.Password,
+				"PGDATABASE": addonDatabase,
+			}
+		},
+		snapshotCmd: "pg_dump -U " + addonUser + " -d " + addonDatabase + " -Fc -f %s",
+		restoreCmd:  "pg_restore -U " + addonUser + " -d " + addonDatabase + " --clean --if-exists %s",
+		snapshotExt: ".dump",
+	},
+	"mysql": {
+		image:          "mysql",
+		defaultVersion: "8",
+		port:           3306,
+		args:           []string{"--datadir=" + addonDataDir},
+		containerEnv: func(a addon) []corev1.EnvVar {
+			return []corev1.EnvVar{
+				{Name: "MYSQL_ROOT_PASSWORD", Value: a.Password},
+				{Name: "MYSQL_DATABASE", Value: addonDatabase},
+				{Name: "MYSQL_USER", Value: addonUser},
+				{Name: "MYSQL_PASSWORD", Value: a.Password},
+			}
+		},
+		connectionEnv: func(a addon) map[string]string {
+			return map[string]string{
+				"DATABASE_URL": fmt.Sprintf("mysql://%s:%s@%s:3306/%s",
+					addonUser, a.Password, a.Name, addonDatabase),
+				"MYSQL_HOST":     a.Name,
+				"MYSQL_PORT":     "3306",
+				"MYSQL_USER":     addonUser,
+				"MYSQL_PASSWORD": a.Password,
+				"MYSQL_DATABASE": addonDatabase,
+			}
+		},
+		snapshotCmd: `mysqldump -uroot -p"$MYSQL_ROOT_PASSWORD" --add-drop-database --databases ` +
+			addonDatabase + " > %s",
+		restoreCmd:  `mysql -uroot -p"$MYSQL_ROOT_PASSWORD" < %s`,
+		snapshotExt: ".sql",
+	},
+	"redis": {
+		image:          "redis",
+		defaultVersion: "6",
+		port:           6379,
+		// Redis overwrites its dump file when it shuts down, so restored
+		// snapshots are staged, and moved into place before Redis boots.
+		command: []string{"sh", "-c", fmt.Sprintf(
+			"mkdir -p %[1]s && if [ -f %[1]s/restore.rdb ]; then mv %[1]s/restore.rdb %[1]s/dump.rdb; fi && "+
+				"exec redis-server --dir %[1]s --save 60 1", addonDataDir)},
+		containerEnv: func(addon) []corev1.EnvVar { return nil },
+		connectionEnv: func(a addon) map[string]string {
+			return map[string]string{
+				"REDIS_URL":  fmt.Sprintf("redis://%s:6379", a.Name),
+				"REDIS_HOST": a.Name,
+				"REDIS_PORT": "6379",
+			}
+		},
+		snapshotCmd:         "redis-cli SAVE > /dev/null && cp " + addonDataDir + "/dump.rdb %s",
+		restoreCmd:          "cp %s " + addonDataDir + "/restore.rdb",
+		snapshotExt:         ".rdb",
+		restartAfterRestore: true,
+	},
+}
+
+func (a addon) appliesTo(svc string) bool {
+	return len(a.Services) == 0 || contains(a.Services, svc)
+}
+
+func (a addon) snapshotPath(snapshot string) string {
+	return addonSnapshotsDir + "/" + snapshot + addonEngines[a.Engine].snapshotExt
+}
+
+func (a addon) pod(user auth.User, placement affinity.Placement) corev1.Pod {
+	engine := addonEngines[a.Engine]
+	name := names.ToDNS1123(a.Name)
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: user.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				addonLabel:                    "true",
+				"blimp.service":               a.Name,
+				affinity.ColocateNamespaceKey: user.Namespace,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    name,
+				Image:   fmt.Sprintf("%s:%s", engine.image, a.Version),
+				Command: engine.command,
+				Args:    engine.args,
+				Env:     engine.containerEnv(a),
+				Ports:   []corev1.ContainerPort{{ContainerPort: engine.port}},
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      volume.PersistentVolume.Name,
+					MountPath: addonMountPath,
+					SubPath:   volume.AddonDir(a.Name),
+				}},
+				ReadinessProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						TCPSocket: &corev1.TCPSocketAction{
+							Port: intstr.FromInt(int(engine.port)),
+						},
+					},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						"cpu":    resource.MustParse("1"),
+						"memory": resource.MustParse("1Gi"),
+					},
+					Requests: corev1.ResourceList{
+						"cpu":    resource.MustParse("100m"),
+						"memory": resource.MustParse("256Mi"),
+					},
+				},
+			}},
+			Volumes:     []corev1.Volume{volume.PersistentVolume},
+			Affinity:    affinity.ForUser(user, placement),
+			Tolerations: affinity.Tolerations(),
+		},
+	}
+}
+
+func (a addon) toProtobuf() *cluster.Addon {
+	return &cluster.Addon{
+		Name:     a.Name,
+		Engine:   a.Engine,
+		Version:  a.Version,
+		Services: a.Services,
+		Env:      addonEngines[a.Engine].connectionEnv(a),
+	}
+}
+
+func (s *server) CreateAddon(ctx context.Context, req *cluster.CreateAddonRequest) (
+	*cluster.CreateAddonResponse, error) {
+	log.Info("Start CreateAddon")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.CreateAddonResponse{}, err
+	}
+
+	newAddon, err := newAddonFromRequest(req)
+	if err != nil {
+		return &cluster.CreateAddonResponse{}, err
+	}
+
+	if _, err := s.statusFetcher.namespaceLister.Get(user.Namespace); err != nil {
+		if kerrors.IsNotFound(err) {
+			return &cluster.CreateAddonResponse{}, errors.NewFriendlyError(
+				"Your sandbox doesn't exist yet. Run `blimp up` before creating add-ons.")
+		}
+		return &cluster.CreateAddonResponse{}, errors.WithContext("get namespace", err)
+	}
+
+	customerPods, err := s.statusFetcher.podLister.Pods(user.Namespace).List(
+		labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return &cluster.CreateAddonResponse{}, errors.WithContext("list services", err)
+	}
+
+	for _, pod := range customerPods {
+		if pod.Labels["blimp.service"] == newAddon.Name {
+			return &cluster.CreateAddonResponse{}, errors.NewFriendlyError(
+				"There's already a service named %q. Use --name to choose a different name for the add-on.",
+				newAddon.Name)
+		}
+	}
+
+	var allAddons []addon
+	err = s.updateAddons(user.Namespace, func(addons []addon) ([]addon, error) {
+		for _, a := range addons {
+			if a.Name == newAddon.Name {
+				return nil, errors.NewFriendlyError("The add-on %q already exists.", a.Name)
+			}
+		}
+
+		if err := checkAddonConflicts(addons, newAddon); err != nil {
+			return nil, err
+		}
+
+		allAddons = append(addons, newAddon)
+		return allAddons, nil
+	})
+	if err != nil {
+		return &cluster.CreateAddonResponse{}, err
+	}
+	s.recordActivity(user.Namespace)
+
+	placement, err := s.getPlacement(user.Namespace)
+	if err != nil {
+		return &cluster.CreateAddonResponse{}, errors.WithContext("get placement", err)
+	}
+
+	if err := kube.DeployPod(s.kubeClient, newAddon.pod(user, placement), kube.DeployPodOptions{
+		Sanitizers: []kube.Sanitizer{kube.SanitizeIgnoreNodeAffinity},
+	}); err != nil {
+		return &cluster.CreateAddonResponse{}, errors.WithContext("deploy add-on", err)
+	}
+
+	// Restart the running services so that they pick up the connection
+	// environment variables. Other services get them when they're deployed.
+	var restarted []string
+	for _, pod := range customerPods {
+		svc := pod.Labels["blimp.service"]
+		if !newAddon.appliesTo(svc) {
+			continue
+		}
+
+		newPod := copyCustomerPod(pod)
+		injectAddonEnv(&newPod, allAddons)
+		err := kube.DeployPod(s.kubeClient, newPod, kube.DeployPodOptions{ForceRestart: true})
+		if err != nil {
+			return &cluster.CreateAddonResponse{}, errors.WithContext(
+				fmt.Sprintf("restart %s with connection environment", svc), err)
+		}
+		restarted = append(restarted, svc)
+	}
+	sort.Strings(restarted)
+
+	addonPB := newAddon.toProtobuf()
+	addonPB.Phase = "Starting"
+	return &cluster.CreateAddonResponse{
+		Addon:             addonPB,
+		RestartedServices: restarted,
+	}, nil
+}
+
+func newAddonFromRequest(req *cluster.CreateAddonRequest) (addon, error) {
+	engine, ok := addonEngines[req.GetEngine()]
+	if !ok {
+		var supported []string
+		for name := range addonEngines {
+			supported = append(supported, name)
+		}
+		sort.Strings(supported)
+		return addon{}, errors.NewFriendlyError("Unknown add-on %q. The supported add-ons are: %s.",
+			req.GetEngine(), strings.Join(supported, ", "))
+	}
+
+	a := addon{
+		Name:     req.GetName(),
+		Engine:   req.GetEngine(),
+		Version:  req.GetVersion(),
+		Services: req.GetServices(),
+	}
+	if a.Name == "" {
+		a.Name = a.Engine
+	}
+	if a.Version == "" {
+		a.Version = engine.defaultVersion
+	}
+
+	if !addonNamePattern.MatchString(a.Name) {
+		return addon{}, errors.NewFriendlyError(
+			"Invalid add-on name %q. Names must consist of lowercase letters, numbers, and dashes.", a.Name)
+	}
+
+	if !addonVersionPattern.MatchString(a.Version) {
+		return addon{}, errors.NewFriendlyError("Invalid version %q.", a.Version)
+	}
+
+	password, err := randomAddonPassword()
+	if err != nil {
+		return addon{}, err
+	}
+	a.Password = password
+	return a, nil
+}
+
+// checkAddonConflicts returns an error if the new add-on would set the same
+// environment variable as an existing add-on in any service.
+func checkAddonConflicts(existing []addon, newAddon addon) error {
+	newEnv := addonEngines[newAddon.Engine].connectionEnv(newAddon)
+	for _, a := range existing {
+		var overlapping string
+		switch {
+		case len(a.Services) == 0 || len(newAddon.Services) == 0:
+			overlapping = "all services"
+		default:
+			for _, svc := range newAddon.Services {
+				if contains(a.Services, svc) {
+					overlapping = svc
+					break
+				}
+			}
+		}
+		if overlapping == "" {
+			continue
+		}
+
+		var shared []string
+		for key := range addonEngines[a.Engine].connectionEnv(a) {
+			if _, ok := newEnv[key]; ok {
+				shared = append(shared, key)
+			}
+		}
+		if len(shared) != 0 {
+			sort.Strings(shared)
+			return errors.NewFriendlyError(
+				"The add-on %q already sets %s in %s.\n"+
+					"Use --services to inject the new add-on's environment into different services.",
+				a.Name, strings.Join(shared, ", "), overlapping)
+		}
+	}
+	return nil
+}
+
+func randomAddonPassword() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.WithContext("generate password", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *server) DeleteAddon(ctx context.Context, req *cluster.DeleteAddonRequest) (
+	*cluster.DeleteAddonResponse, error) {
+	log.Info("Start DeleteAddon")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.DeleteAddonResponse{}, err
+	}
+
+	a, err := s.getAddon(user.Namespace, req.GetName())
+	if err != nil {
+		return &cluster.DeleteAddonResponse{}, err
+	}
+
+	if req.GetDeleteData() {
+		pod, err := s.getRunningAddonPod(user.Namespace, a)
+		if err != nil {
+			return &cluster.DeleteAddonResponse{}, err
+		}
+
+		rmCmd := []string{"rm", "-rf", addonDataDir, addonSnapshotsDir}
+		if err := s.execInPod(*pod, rmCmd, nil, nil); err != nil {
+			return &cluster.DeleteAddonResponse{}, errors.WithContext("delete data", err)
+		}
+	}
+
+	err = kube.DeletePod(s.kubeClient, user.Namespace, names.ToDNS1123(a.Name))
+	if err != nil && !kerrors.IsNotFound(err) {
+		return &cluster.DeleteAddonResponse{}, errors.WithContext("delete pod", err)
+	}
+
+	err = s.updateAddons(user.Namespace, func(addons []addon) ([]addon, error) {
+		var remaining []addon
+		for _, other := range addons {
+			if other.Name != a.Name {
+				remaining = append(remaining, other)
+			}
+		}
+		return remaining, nil
+	})
+	if err != nil {
+		return &cluster.DeleteAddonResponse{}, errors.WithContext("update add-ons", err)
+	}
+	s.recordActivity(user.Namespace)
+	return &cluster.DeleteAddonResponse{}, nil
+}
+
+func (s *server) ListAddons(ctx context.Context, req *cluster.ListAddonsRequest) (
+	*cluster.ListAddonsResponse, error) {
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.ListAddonsResponse{}, err
+	}
+
+	addons, err := s.getAddons(user.Namespace)
+	if err != nil {
+		return &cluster.ListAddonsResponse{}, err
+	}
+
+	var addonsPB []*cluster.Addon
+	for _, a := range addons {
+		addonPB := a.toProtobuf()
+		pod, err := s.statusFetcher.podLister.Pods(user.Namespace).Get(names.ToDNS1123(a.Name))
+		switch {
+		case kerrors.IsNotFound(err):
+			addonPB.Phase = "Missing"
+		case err != nil:
+			return &cluster.ListAddonsResponse{}, errors.WithContext("get pod", err)
+		default:
+			addonPB.Phase = addonPhase(pod)
+		}
+
+		if addonPB.Phase == "Running" {
+			addonPB.Snapshots, err = s.listSnapshots(*pod, a)
+			if err != nil {
+				log.WithError(err).WithField("addon", a.Name).Warn("Failed to list snapshots")
+			}
+		}
+		addonsPB = append(addonsPB, addonPB)
+	}
+	return &cluster.ListAddonsResponse{Addons: addonsPB}, nil
+}
+
+func addonPhase(pod *corev1.Pod) string {
+	switch pod.Status.Phase {
+	case corev1.PodPending:
+		return "Starting"
+	case corev1.PodRunning:
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				return "Running"
+			}
+		}
+		return "Starting"
+	default:
+		return string(pod.Status.Phase)
+	}
+}
+
+func (s *server) SnapshotAddon(ctx context.Context, req *cluster.SnapshotAddonRequest) (
+	*cluster.SnapshotAddonResponse, error) {
+	log.Info("Start SnapshotAddon")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.SnapshotAddonResponse{}, err
+	}
+
+	snapshot := req.GetSnapshot()
+	if snapshot == "" {
+		snapshot = time.Now().UTC().Format("20060102-150405")
+	}
+	if !snapshotNamePattern.MatchString(snapshot) {
+		return &cluster.SnapshotAddonResponse{}, errors.NewFriendlyError(
+			"Invalid snapshot name %q. Names must consist of letters, numbers, dots, dashes, and underscores.",
+			snapshot)
+	}
+
+	a, err := s.getAddon(user.Namespace, req.GetName())
+	if err != nil {
+		return &cluster.SnapshotAddonResponse{}, err
+	}
+
+	pod, err := s.getRunningAddonPod(user.Namespace, a)
+	if err != nil {
+		return &cluster.SnapshotAddonResponse{}, err
+	}
+
+	cmd := fmt.Sprintf("mkdir -p %s && "+addonEngines[a.Engine].snapshotCmd,
+		addonSnapshotsDir, a.snapshotPath(snapshot))
+	if err := s.execInPod(*pod, []string{"sh", "-c", cmd}, nil, nil); err != nil {
+		return &cluster.SnapshotAddonResponse{}, errors.WithContext("snapshot", err)
+	}
+	s.recordActivity(user.Namespace)
+	return &cluster.SnapshotAddonResponse{Snapshot: snapshot}, nil
+}
+
+func (s *server) RestoreAddon(ctx context.Context, req *cluster.RestoreAddonRequest) (
+	*cluster.RestoreAddonResponse, error) {
+	log.Info("Start RestoreAddon")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.RestoreAddonResponse{}, err
+	}
+
+	a, err := s.getAddon(user.Namespace, req.GetName())
+	if err != nil {
+		return &cluster.RestoreAddonResponse{}, err
+	}
+
+	pod, err := s.getRunningAddonPod(user.Namespace, a)
+	if err != nil {
+		return &cluster.RestoreAddonResponse{}, err
+	}
+
+	snapshots, err := s.listSnapshots(*pod, a)
+	if err != nil {
+		return &cluster.RestoreAddonResponse{}, errors.WithContext("list snapshots", err)
+	}
+	if !contains(snapshots, req.GetSnapshot()) {
+		return &cluster.RestoreAddonResponse{}, errors.NewFriendlyError(
+			"The add-on %q doesn't have a snapshot named %q.\n"+
+				"Run `blimp addons` to see the available snapshots.", a.Name, req.GetSnapshot())
+	}
+
+	engine := addonEngines[a.Engine]
+	cmd := fmt.Sprintf(engine.restoreCmd, a.snapshotPath(req.GetSnapshot()))
+	if err := s.execInPod(*pod, []string{"sh", "-c", cmd}, nil, nil); err != nil {
+		return &cluster.RestoreAddonResponse{}, errors.WithContext("restore", err)
+	}
+
+	if engine.restartAfterRestore {
+		placement, err := s.getPlacement(user.Namespace)
+		if err != nil {
+			return &cluster.RestoreAddonResponse{}, errors.WithContext("get placement", err)
+		}
+
+		err = kube.DeployPod(s.kubeClient, a.pod(user, placement), kube.DeployPodOptions{
+			ForceRestart: true,
+			Sanitizers:   []kube.Sanitizer{kube.SanitizeIgnoreNodeAffinity},
+		})
+		if err != nil {
+			return &cluster.RestoreAddonResponse{}, errors.WithContext("restart add-on", err)
+		}
+	}
+	s.recordActivity(user.Namespace)
+	return &cluster.RestoreAddonResponse{}, nil
+}
+
+// listSnapshots returns the names of the add-on's snapshots.
+func (s *server) listSnapshots(pod corev1.Pod, a addon) ([]string, error) {
+	var out strings.Builder
+	cmd := []string{"sh", "-c", fmt.Sprintf("ls -1 %s 2> /dev/null || true", addonSnapshotsDir)}
+	if err := s.execInPod(pod, cmd, nil, &out); err != nil {
+		return nil, err
+	}
+
+	ext := addonEngines[a.Engine].snapshotExt
+	var snapshots []string
+	for _, file := range strings.Fields(out.String()) {
+		if strings.HasSuffix(file, ext) {
+			snapshots = append(snapshots, strings.TrimSuffix(file, ext))
+		}
+	}
+	return snapshots, nil
+}
+
+func (s *server) getRunningAddonPod(namespace string, a addon) (*corev1.Pod, error) {
+	pod, err := s.statusFetcher.podLister.Pods(namespace).Get(names.ToDNS1123(a.Name))
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, errors.WithContext("get pod", err)
+	}
+
+	if err != nil || addonPhase(pod) != "Running" {
+		return nil, errors.NewFriendlyError(
+			"The add-on %q isn't running. Check its status with `blimp addons`.", a.Name)
+	}
+	return pod, nil
+}
+
+func (s *server) getAddon(namespace, name string) (addon, error) {
+	addons, err := s.getAddons(namespace)
+	if err != nil {
+		return addon{}, err
+	}
+
+	for _, a := range addons {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return addon{}, errors.NewFriendlyError("The add-on %q doesn't exist.", name)
+}
+
+func (s *server) getAddons(namespace string) ([]addon, error) {
+	ns, err := s.statusFetcher.namespaceLister.Get(namespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.WithContext("get namespace", err)
+	}
+	return parseAddons(ns)
+}
+
+func parseAddons(ns *corev1.Namespace) ([]addon, error) {
+	addonsJSON, ok := ns.Annotations[kube.AddonsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var addons []addon
+	if err := json.Unmarshal([]byte(addonsJSON), &addons); err != nil {
+		return nil, errors.WithContext("parse add-ons", err)
+	}
+	return addons, nil
+}
+
+// updateAddons replaces the add-ons recorded in the namespace with the result
+// of `update`. The add-ons are kept sorted by name so that their environment
+// variables are injected in a consistent order.
+func (s *server) updateAddons(namespace string, update func([]addon) ([]addon, error)) error {
+	namespacesClient := s.kubeClient.CoreV1().Namespaces()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := namespacesClient.Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		addons, err := parseAddons(ns)
+		if err != nil {
+			return err
+		}
+
+		addons, err = update(addons)
+		if err != nil {
+			return err
+		}
+		sort.Slice(addons, func(i, j int) bool {
+			return addons[i].Name < addons[j].Name
+		})
+
+		if len(addons) == 0 {
+			delete(ns.Annotations, kube.AddonsAnnotation)
+		} else {
+			addonsJSON, err := json.Marshal(addons)
+			if err != nil {
+				return err
+			}
+
+			if ns.Annotations == nil {
+				ns.Annotations = map[string]string{}
+			}
+			ns.Annotations[kube.AddonsAnnotation] = string(addonsJSON)
+		}
+
+		_, err = namespacesClient.Update(ns)
+		return err
+	})
+}
+
+// injectAddonEnv adds the connection environment variables for the add-ons to
+// the service's container. Variables that are already set, such as by the
+// Compose file, take precedence.
+func injectAddonEnv(pod *corev1.Pod, addons []addon) {
+	svc := pod.Labels["blimp.service"]
+	for i, c := range pod.Spec.Containers {
+		if c.Name != names.ToDNS1123(svc) {
+			continue
+		}
+
+		existing := map[string]struct{}{}
+		for _, env := range c.Env {
+			existing[env.Name] = struct{}{}
+		}
+
+		for _, a := range addons {
+			if !a.appliesTo(svc) {
+				continue
+			}
+
+			env := addonEngines[a.Engine].connectionEnv(a)
+			var keys []string
+			for key := range env {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				if _, ok := existing[key]; ok {
+					continue
+				}
+				existing[key] = struct{}{}
+				pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env,
+					corev1.EnvVar{Name: key, Value: env[key]})
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/names"
+)
+
+func TestCheckAddonConflicts(t *testing.T) {
+	pgAll := addon{Name: "postgres", Engine: "postgres"}
+	pgWeb := addon{Name: "postgres", Engine: "postgres", Services: []string{"web"}}
+	mysqlWorker := addon{Name: "mysql", Engine: "mysql", Services: []string{"worker"}}
+	mysqlWeb := addon{Name: "mysql", Engine: "mysql", Services: []string{"web"}}
+	redisAll := addon{Name: "redis", Engine: "redis"}
+
+	// Both set DATABASE_URL in all services.
+	assert.Error(t, checkAddonConflicts([]addon{pgAll}, mysqlWorker))
+
+	// The services don't overlap.
+	assert.NoError(t, checkAddonConflicts([]addon{pgWeb}, mysqlWorker))
+	assert.Error(t, checkAddonConflicts([]addon{pgWeb}, mysqlWeb))
+
+	// Redis doesn't set any of the same variables as the SQL databases.
+	assert.NoError(t, checkAddonConflicts([]addon{pgAll}, redisAll))
+}
+
+func TestInjectAddonEnv(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"blimp.service": "web"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: names.ToDNS1123("web"),
+					Env:  []corev1.EnvVar{{Name: "REDIS_URL", Value: "redis://cache:6379"}},
+				},
+				{Name: chaosContainerName},
+			},
+		},
+	}
+
+	injectAddonEnv(&pod, []addon{
+		{Name: "db", Engine: "postgres", Password: "password"},
+		{Name: "redis", Engine: "redis"},
+		{Name: "other", Engine: "mysql", Services: []string{"worker"}},
+	})
+
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "REDIS_URL", Value: "redis://cache:6379"},
+		{Name: "DATABASE_URL", Value: "postgres://blimp:password@db:5432/blimp"},
+		{Name: "PGDATABASE", Value: "blimp"},
+		{Name: "PGHOST", Value: "db"},
+		{Name: "PGPASSWORD", Value: "password"},
+		{Name: "PGPORT", Value: "5432"},
+		{Name: "PGUSER", Value: "blimp"},
+		{Name: "REDIS_HOST", Value: "redis"},
+		{Name: "REDIS_PORT", Value: "6379"},
+	}, pod.Spec.Containers[0].Env)
+	assert.Empty(t, pod.Spec.Containers[1].Env)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+
+	clusterAuth "github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func (s *server) ListSandboxes(ctx context.Context, req *cluster.ListSandboxesRequest) (
+	*cluster.ListSandboxesResponse, error) {
+	log.Info("Start ListSandboxes")
+
+	if err := clusterAuth.AuthorizeAdminRequest(req.GetAdminToken()); err != nil {
+		return &cluster.ListSandboxesResponse{}, err
+	}
+
+	namespaces, err := s.statusFetcher.namespaceLister.List(labels.Set{"blimp.sandbox": "true"}.AsSelector())
+	if err != nil {
+		return &cluster.ListSandboxesResponse{}, errors.WithContext("list namespaces", err)
+	}
+
+	var sandboxes []*cluster.SandboxInfo
+	for _, namespace := range namespaces {
+		info, err := s.getSandboxInfo(namespace)
+		if err != nil {
+			return &cluster.ListSandboxesResponse{}, errors.WithContext("get sandbox info", err)
+		}
+		sandboxes = append(sandboxes, &info)
+	}
+
+	sort.Slice(sandboxes, func(i, j int) bool {
+		return sandboxes[i].Namespace < sandboxes[j].Namespace
+	})
+	return &cluster.ListSandboxesResponse{Sandboxes: sandboxes}, nil
+}
+
+func (s *server) DescribeSandbox(ctx context.Context, req *cluster.DescribeSandboxRequest) (
+	*cluster.DescribeSandboxResponse, error) {
+	log.Info("Start DescribeSandbox")
+
+	if err := clusterAuth.AuthorizeAdminRequest(req.GetAdminToken()); err != nil {
+		return &cluster.DescribeSandboxResponse{}, err
+	}
+
+	namespace, err := s.getSandboxNamespace(req.GetNamespace())
+	if err != nil {
+		return &cluster.DescribeSandboxResponse{}, err
+	}
+
+	info, err := s.getSandboxInfo(namespace)
+	if err != nil {
+		return &cluster.DescribeSandboxResponse{}, errors.WithContext("get sandbox info", err)
+	}
+
+	status, err := s.statusFetcher.Get(namespace.Name)
+	if err != nil {
+		return &cluster.DescribeSandboxResponse{}, errors.WithContext("get sandbox status", err)
+	}
+
+	return &cluster.DescribeSandboxResponse{
+		Sandbox: &info,
+		Status:  &status,
+	}, nil
+}
+
+func (s *server) EvictSandbox(ctx context.Context, req *cluster.EvictSandboxRequest) (
+	*cluster.EvictSandboxResponse, error) {
+	log.Info("Start EvictSandbox")
+
+	if err := clusterAuth.AuthorizeAdminRequest(req.GetAdminToken()); err != nil {
+		return &cluster.EvictSandboxResponse{}, err
+	}
+
+	namespace, err := s.getSandboxNamespace(req.GetNamespace())
+	if err != nil {
+		return &cluster.EvictSandboxResponse{}, err
+	}
+
+	log.WithField("namespace", namespace.Name).
+		WithField("owner", namespace.Annotations[kube.OwnerAnnotation]).
+		Info("Evicting sandbox")
+	if err := s.deleteSandbox(namespace.Name, req.GetDeleteVolumes()); err != nil {
+		return &cluster.EvictSandboxResponse{}, errors.WithContext("delete sandbox", err)
+	}
+	return &cluster.EvictSandboxResponse{}, nil
+}
+
+// getSandboxNamespace gets the namespace with the given name, and makes sure
+// that it's a sandbox so that admins can't accidentally delete system
+// namespaces.
+func (s *server) getSandboxNamespace(name string) (*corev1.Namespace, error) {
+	namespace, err := s.statusFetcher.namespaceLister.Get(name)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, errors.NewFriendlyError("Sandbox %q does not exist", name)
+		}
+		return nil, errors.WithContext("get namespace", err)
+	}
+
+	if namespace.Labels["blimp.sandbox"] != "true" {
+		return nil, errors.NewFriendlyError("Namespace %q is not a sandbox", name)
+	}
+	return namespace, nil
+}
+
+func (s *server) getSandboxInfo(namespace *corev1.Namespace) (cluster.SandboxInfo, error) {
+	pods, err := s.statusFetcher.podLister.Pods(namespace.Name).List(labels.Everything())
+	if err != nil {
+		return cluster.SandboxInfo{}, errors.WithContext("list pods", err)
+	}
+
+	var cpu, memory resource.Quantity
+	for _, pod := range pods {
+		for _, c := range pod.Spec.Containers {
+			cpu.Add(*c.Resources.Requests.Cpu())
+			memory.Add(*c.Resources.Requests.Memory())
+		}
+	}
+
+	info := cluster.SandboxInfo{
+		Namespace:       namespace.Name,
+		Owner:           namespace.Annotations[kube.OwnerAnnotation],
+		CreatedAt:       namespace.CreationTimestamp.Unix(),
+		NumPods:         int32(len(pods)),
+		CpuRequested:    cpu.String(),
+		MemoryRequested: memory.String(),
+	}
+
+	if lastActivity, ok := namespace.Annotations[kube.LastActivityAnnotation]; ok {
+		t, err := time.Parse(time.RFC3339, lastActivity)
+		if err != nil {
+			log.WithError(err).WithField("namespace", namespace.Name).
+				Warn("Failed to parse last activity annotation")
+		} else {
+			info.LastActivity = t.Unix()
+		}
+	}
+
+	status, err := s.statusFetcher.Get(namespace.Name)
+	if err != nil {
+		return cluster.SandboxInfo{}, errors.WithContext("get status", err)
+	}
+	info.Phase = status.Phase
+	return info, nil
+}
+
+// recordActivity marks the sandbox as recently used so that operators can
+// find idle sandboxes. Failures are only logged since the activity timestamp
+// is informational.
+func (s *server) recordActivity(namespace string) {
+	namespacesClient := s.kubeClient.CoreV1().Namespaces()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := namespacesClient.Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		ns.Annotations[kube.LastActivityAnnotation] = time.Now().UTC().Format(time.RFC3339)
+
+		_, err = namespacesClient.Update(ns)
+		return err
+	})
+	if err != nil {
+		log.WithError(err).WithField("namespace", namespace).Warn("Failed to record sandbox activity")
+	}
+}
@@ -2,7 +2,10 @@ package affinity
 
 import (
 	"os"
+	"sort"
+	"strings"
 
+	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -20,11 +23,21 @@ const (
 	// OS rather than COS because rootless buildkit doesn't work on COS:
 	// https://github.com/moby/buildkit/issues/879.
 	buildkitNodeKey = "blimp.buildkit"
+
+	// sandboxNodeLabelsEnv restricts sandbox pods to nodes with the given
+	// labels, so that operators can run sandboxes in a dedicated node pool.
+	// It's a comma separated list, such as "pool=sandboxes,spot=true".
+	sandboxNodeLabelsEnv = "SANDBOX_NODE_LABELS"
+
+	// sandboxNodeTaintsEnv lists the taints on the sandbox node pool that
+	// sandbox pods should tolerate. It's a comma separated list of taints in
+	// the same format as `kubectl taint`, such as
+	// "dedicated=sandboxes:NoSchedule,cloud.google.com/gke-spot=true:NoSchedule".
+	sandboxNodeTaintsEnv = "SANDBOX_NODE_TAINTS"
 )
 
 func OnBuilderNode() *corev1.Affinity {
-	isolateBuildkit, ok := os.LookupEnv("ISOLATE_BUILDKIT")
-	if ok && isolateBuildkit == "false" {
+	if !isolateBuildkit() {
 		// Let buildkit run on any node.
 		return &corev1.Affinity{}
 	}
@@ -32,19 +45,167 @@ func OnBuilderNode() *corev1.Affinity {
 	return newAffinity(onNode(buildkitNodeKey))
 }
 
-func ForUser(user auth.User) *corev1.Affinity {
-	opts := []affinityOption{
-		withPods(ColocateNamespaceKey, user.Namespace),
+// Placement describes where a sandbox's pods should run. It's stored as JSON
+// in the sandbox namespace's kube.PlacementAnnotation.
+type Placement struct {
+	// Node is the node that all of the sandbox's pods are pinned to.
+	Node string `json:"node,omitempty"`
+
+	// Spread is set when the sandbox's volume can be mounted from multiple
+	// nodes at once, so the sandbox's pods don't have to share a node.
+	Spread bool `json:"spread,omitempty"`
+}
+
+// ForUser returns the affinity for pods in the user's sandbox. If the
+// placement doesn't specify a node, the pods are colocated with the other
+// pods in the sandbox so that they can share its volume.
+func ForUser(user auth.User, placement Placement) *corev1.Affinity {
+	var opts []affinityOption
+	switch {
+	case placement.Node != "":
+		opts = append(opts, onNodeWithLabel(corev1.LabelHostname, placement.Node))
+	case !placement.Spread:
+		opts = append(opts, withPods(ColocateNamespaceKey, user.Namespace))
 	}
 
-	isolateBuildkit, ok := os.LookupEnv("ISOLATE_BUILDKIT")
-	if !ok || isolateBuildkit != "false" {
+	if isolateBuildkit() {
 		opts = append(opts, notNode(buildkitNodeKey))
 	}
 
+	// Sort the labels so that the affinity is the same each time, since
+	// changes to it cause pods to be recreated.
+	nodeLabels := parseNodeLabels(os.Getenv(sandboxNodeLabelsEnv))
+	var keys []string
+	for key := range nodeLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		opts = append(opts, onNodeWithLabel(key, nodeLabels[key]))
+	}
+
 	return newAffinity(opts...)
 }
 
+// OnSandboxNode returns the affinity for pods that should run on the nodes
+// that sandboxes run on, without being tied to a particular sandbox.
+func OnSandboxNode() *corev1.Affinity {
+	return ForUser(auth.User{}, Placement{Spread: true})
+}
+
+// SchedulesOn returns whether sandbox pods are allowed to run on the node,
+// based on its labels and taints. It doesn't take the node's capacity into
+// account.
+func SchedulesOn(node *corev1.Node) bool {
+	if _, ok := node.Labels[buildkitNodeKey]; ok && isolateBuildkit() {
+		return false
+	}
+
+	for key, value := range parseNodeLabels(os.Getenv(sandboxNodeLabelsEnv)) {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+
+	tolerations := Tolerations()
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		var tolerated bool
+		for _, toleration := range tolerations {
+			taint := taint
+			if toleration.ToleratesTaint(&taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+func isolateBuildkit() bool {
+	isolate, ok := os.LookupEnv("ISOLATE_BUILDKIT")
+	return !ok || isolate != "false"
+}
+
+// Tolerations returns the tolerations that sandbox pods need to run in the
+// sandbox node pool. It returns nil if the node pool isn't tainted.
+func Tolerations() []corev1.Toleration {
+	var tolerations []corev1.Toleration
+	for _, taint := range strings.Split(os.Getenv(sandboxNodeTaintsEnv), ",") {
+		taint = strings.TrimSpace(taint)
+		if taint == "" {
+			continue
+		}
+
+		toleration, ok := parseToleration(taint)
+		if !ok {
+			log.WithField("taint", taint).Warnf("Ignoring malformed taint in $%s", sandboxNodeTaintsEnv)
+			continue
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations
+}
+
+func parseNodeLabels(labelsStr string) map[string]string {
+	labels := map[string]string{}
+	for _, label := range strings.Split(labelsStr, ",") {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			log.WithField("label", label).Warnf("Ignoring malformed label in $%s", sandboxNodeLabelsEnv)
+			continue
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels
+}
+
+// parseToleration parses a taint in the form "key=value:Effect" or
+// "key:Effect" into a toleration for it.
+func parseToleration(taint string) (corev1.Toleration, bool) {
+	colon := strings.LastIndex(taint, ":")
+	if colon == -1 {
+		return corev1.Toleration{}, false
+	}
+
+	effect := corev1.TaintEffect(taint[colon+1:])
+	switch effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return corev1.Toleration{}, false
+	}
+
+	keyValue := strings.SplitN(taint[:colon], "=", 2)
+	if keyValue[0] == "" {
+		return corev1.Toleration{}, false
+	}
+
+	if len(keyValue) == 1 {
+		return corev1.Toleration{
+			Key:      keyValue[0],
+			Operator: corev1.TolerationOpExists,
+			Effect:   effect,
+		}, true
+	}
+	return corev1.Toleration{
+		Key:      keyValue[0],
+		Operator: corev1.TolerationOpEqual,
+		Value:    keyValue[1],
+		Effect:   effect,
+	}, true
+}
+
 func newAffinity(opts ...affinityOption) *corev1.Affinity {
 	affinity := &corev1.Affinity{}
 	for _, opt := range opts {
@@ -64,6 +225,16 @@ func onNode(key string) affinityOption {
 	}
 }
 
+func onNodeWithLabel(key, value string) affinityOption {
+	return func(affinity *corev1.Affinity) {
+		addNodeSelector(affinity, corev1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{value},
+		})
+	}
+}
+
 func notNode(key string) affinityOption {
 	return func(affinity *corev1.Affinity) {
 		addNodeSelector(affinity, corev1.NodeSelectorRequirement{
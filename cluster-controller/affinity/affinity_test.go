@@ -0,0 +1,103 @@
+package affinity
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/auth"
+)
+
+func TestTolerations(t *testing.T) {
+	defer os.Unsetenv(sandboxNodeTaintsEnv)
+
+	os.Unsetenv(sandboxNodeTaintsEnv)
+	assert.Nil(t, Tolerations())
+
+	os.Setenv(sandboxNodeTaintsEnv,
+		"dedicated=sandboxes:NoSchedule, cloud.google.com/gke-spot:NoExecute,malformed,bad:Effect")
+	assert.Equal(t, []corev1.Toleration{
+		{
+			Key:      "dedicated",
+			Operator: corev1.TolerationOpEqual,
+			Value:    "sandboxes",
+			Effect:   corev1.TaintEffectNoSchedule,
+		},
+		{
+			Key:      "cloud.google.com/gke-spot",
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoExecute,
+		},
+	}, Tolerations())
+}
+
+func TestForUserNodeLabels(t *testing.T) {
+	defer os.Unsetenv(sandboxNodeLabelsEnv)
+	os.Setenv(sandboxNodeLabelsEnv, "spot=true,pool=sandboxes,malformed")
+	os.Setenv("ISOLATE_BUILDKIT", "false")
+	defer os.Unsetenv("ISOLATE_BUILDKIT")
+
+	affinity := ForUser(auth.User{Namespace: "ns"}, Placement{})
+	assert.Equal(t, []corev1.NodeSelectorRequirement{
+		{
+			Key:      "pool",
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"sandboxes"},
+		},
+		{
+			Key:      "spot",
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"true"},
+		},
+	}, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+}
+
+func TestForUserPlacement(t *testing.T) {
+	os.Setenv("ISOLATE_BUILDKIT", "false")
+	defer os.Unsetenv("ISOLATE_BUILDKIT")
+
+	user := auth.User{Namespace: "ns"}
+
+	colocated := ForUser(user, Placement{})
+	assert.Nil(t, colocated.NodeAffinity)
+	assert.Len(t, colocated.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution, 1)
+
+	pinned := ForUser(user, Placement{Node: "node-1"})
+	assert.Nil(t, pinned.PodAffinity)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{{
+		Key:      corev1.LabelHostname,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{"node-1"},
+	}}, pinned.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+
+	assert.Equal(t, &corev1.Affinity{}, ForUser(user, Placement{Spread: true}))
+}
+
+func TestSchedulesOn(t *testing.T) {
+	defer os.Unsetenv(sandboxNodeLabelsEnv)
+	defer os.Unsetenv(sandboxNodeTaintsEnv)
+	os.Setenv(sandboxNodeLabelsEnv, "pool=sandboxes")
+	os.Setenv(sandboxNodeTaintsEnv, "dedicated=sandboxes:NoSchedule")
+	os.Unsetenv("ISOLATE_BUILDKIT")
+
+	node := func(labels map[string]string, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       corev1.NodeSpec{Taints: taints},
+		}
+	}
+	sandboxLabels := map[string]string{"pool": "sandboxes"}
+	sandboxTaint := corev1.Taint{Key: "dedicated", Value: "sandboxes", Effect: corev1.TaintEffectNoSchedule}
+
+	assert.True(t, SchedulesOn(node(sandboxLabels)))
+	assert.True(t, SchedulesOn(node(sandboxLabels, sandboxTaint)))
+	assert.True(t, SchedulesOn(node(sandboxLabels, corev1.Taint{
+		Key: "other", Effect: corev1.TaintEffectPreferNoSchedule})))
+	assert.False(t, SchedulesOn(node(map[string]string{"pool": "other"})))
+	assert.False(t, SchedulesOn(node(sandboxLabels, corev1.Taint{
+		Key: "other", Effect: corev1.TaintEffectNoSchedule})))
+	assert.False(t, SchedulesOn(node(map[string]string{"pool": "sandboxes", buildkitNodeKey: "true"})))
+}
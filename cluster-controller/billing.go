@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kelda/blimp/cluster-controller/billing"
+	"github.com/kelda/blimp/cluster-controller/metering"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// billingUsageInterval is how often the usage of running sandboxes is
+// reported to the billing hook.
+const billingUsageInterval = 15 * time.Minute
+
+// getBillingHook returns the hook configured by $BILLING_WEBHOOK_URL, or nil
+// if billing is disabled. If $BILLING_FAIL_OPEN is true, actions are allowed
+// when the webhook can't be reached.
+func getBillingHook() billing.Hook {
+	url := os.Getenv("BILLING_WEBHOOK_URL")
+	if url == "" {
+		return nil
+	}
+	return billing.NewWebhook(url, os.Getenv("BILLING_FAIL_OPEN") == "true")
+}
+
+// checkBilling asks the billing hook whether the action is allowed.
+func (s *server) checkBilling(ctx context.Context, action billing.Action, namespace, owner string) error {
+	if s.billing == nil {
+		return nil
+	}
+	return s.billing.Check(ctx, billing.Event{
+		Action:    action,
+		Namespace: namespace,
+		Owner:     owner,
+	})
+}
+
+func (s *server) runBillingUsageReporter(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := s.reportBillingUsage(); err != nil {
+			log.WithError(err).Warn("Failed to report usage to billing hook")
+		}
+	}
+}
+
+// reportBillingUsage sends the usage of each running sandbox to the billing
+// hook. Sandboxes whose usage is denied are paused.
+func (s *server) reportBillingUsage() error {
+	period := time.Now().Format(metering.PeriodFormat)
+	usage, err := s.meter.List(period)
+	if err != nil {
+		return errors.WithContext("list usage", err)
+	}
+
+	namespaces, err := s.statusFetcher.namespaceLister.List(labels.Set{"blimp.sandbox": "true"}.AsSelector())
+	if err != nil {
+		return errors.WithContext("list namespaces", err)
+	}
+
+	for _, ns := range namespaces {
+		if _, paused, err := parsePaused(ns); err != nil || paused {
+			continue
+		}
+
+		nsUsage := usage[ns.Name]
+		err := s.billing.Check(context.Background(), billing.Event{
+			Action:    billing.ActionUsage,
+			Namespace: ns.Name,
+			Owner:     ns.Annotations[kube.OwnerAnnotation],
+			Period:    period,
+			Usage:     &nsUsage,
+		})
+		if !billing.IsDenied(err) {
+			continue
+		}
+
+		log.WithError(err).WithField("namespace", ns.Name).Info("Pausing sandbox denied by billing hook")
+		if err := s.pauseSandbox(ns.Name); err != nil {
+			log.WithError(err).WithField("namespace", ns.Name).Warn("Failed to pause sandbox")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Package billing lets hosted Blimp offerings check billable actions against
+// an external billing system. The manager consults the billing hook when
+// sandboxes are created or resumed, and periodically reports each sandbox's
+// usage. The hook can deny the action, in which case the user is shown a link
+// to upgrade their plan.
+package billing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/cluster-controller/metering"
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// Action is the billable action that the hook is consulted for.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionResume Action = "resume"
+
+	// ActionUsage is sent periodically for each running sandbox. Denying it
+	// pauses the sandbox.
+	ActionUsage Action = "usage"
+)
+
+// Event describes the billable action.
+type Event struct {
+	Action    Action `json:"action"`
+	Namespace string `json:"namespace"`
+
+	// Owner is the identity of the user that owns the sandbox.
+	Owner string `json:"owner"`
+
+	// Period and Usage are the sandbox's usage so far in the current billing
+	// period. They're only set for usage events.
+	Period string          `json:"period,omitempty"`
+	Usage  *metering.Usage `json:"usage,omitempty"`
+}
+
+// Hook decides whether billable actions are allowed.
+type Hook interface {
+	// Check returns an error if the action isn't allowed. Denials are coded
+	// errors with either errors.CodePaymentRequired or
+	// errors.CodePlanLimitReached.
+	Check(ctx context.Context, event Event) error
+}
+
+// IsDenied returns whether the error is a denial from the billing hook, as
+// opposed to a failure to reach the billing system.
+func IsDenied(err error) bool {
+	codedErr, ok := errors.GetCode(err)
+	if !ok {
+		return false
+	}
+	return codedErr.Code() == errors.CodePaymentRequired ||
+		codedErr.Code() == errors.CodePlanLimitReached
+}
+
+// webhookTimeout bounds how long the billing system can take to respond,
+// since it's called while the user waits for their sandbox.
+const webhookTimeout = 5 * time.Second
+
+// Webhook is a Hook that posts each Event as JSON to an HTTP endpoint. The
+// endpoint responds with a decision:
+//
+//	{"allowed": false, "reason": "plan_limit_reached",
+//	 "message": "You've used all 100 hours in your plan.",
+//	 "upgradeURL": "https://example.com/billing"}
+//
+// `reason` is either "payment_required" or "plan_limit_reached".
+type Webhook struct {
+	url string
+
+	// failOpen allows actions when the billing system can't be reached.
+	failOpen bool
+
+	client http.Client
+}
+
+type decision struct {
+	Allowed    bool   `json:"allowed"`
+	Reason     string `json:"reason"`
+	Message    string `json:"message"`
+	UpgradeURL string `json:"upgradeURL"`
+}
+
+// NewWebhook returns a Hook that consults the billing system at the given URL.
+func NewWebhook(url string, failOpen bool) *Webhook {
+	return &Webhook{
+		url:      url,
+		failOpen: failOpen,
+		client:   http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (w *Webhook) Check(ctx context.Context, event Event) error {
+	d, err := w.post(ctx, event)
+	if err != nil {
+		log.WithError(err).WithField("action", event.Action).
+			WithField("namespace", event.Namespace).
+			Warn("Failed to consult billing webhook")
+		if w.failOpen {
+			return nil
+		}
+		return errors.NewFriendlyError("Failed to check your plan with the billing system. " +
+			"Please try again later.")
+	}
+
+	if d.Allowed {
+		return nil
+	}
+
+	switch d.Reason {
+	case "plan_limit_reached":
+		msg := d.Message
+		if msg == "" {
+			msg = "Your sandbox has reached the limits of your plan."
+		}
+		return errors.NewCodedErrorWithURL(errors.CodePlanLimitReached, d.UpgradeURL, "%s", msg)
+	default:
+		msg := d.Message
+		if msg == "" {
+			msg = "Payment is required to use this sandbox."
+		}
+		return errors.NewCodedErrorWithURL(errors.CodePaymentRequired, d.UpgradeURL, "%s", msg)
+	}
+}
+
+func (w *Webhook) post(ctx context.Context, event Event) (decision, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return decision{}, errors.WithContext("marshal", err)
+	}
+
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return decision{}, errors.WithContext("create request", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return decision{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decision{}, errors.New("unexpected status %s", resp.Status)
+	}
+
+	var d decision
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return decision{}, errors.WithContext("parse response", err)
+	}
+	return d, nil
+}
@@ -0,0 +1,65 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+func TestWebhook(t *testing.T) {
+	var received Event
+	var respond decision
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		assert.NoError(t, json.NewEncoder(w).Encode(respond))
+	}))
+	defer server.Close()
+
+	hook := NewWebhook(server.URL, false)
+	event := Event{Action: ActionCreate, Namespace: "ns", Owner: "owner"}
+
+	respond = decision{Allowed: true}
+	assert.NoError(t, hook.Check(context.Background(), event))
+	assert.Equal(t, event, received)
+
+	// Denials link to the billing system's upgrade page.
+	respond = decision{
+		Reason:     "plan_limit_reached",
+		Message:    "You've used all 100 hours in your plan.",
+		UpgradeURL: "https://example.com/billing",
+	}
+	err := hook.Check(context.Background(), event)
+	assert.True(t, IsDenied(err))
+	codedErr, ok := errors.GetCode(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodePlanLimitReached, codedErr.Code())
+	assert.Equal(t, "https://example.com/billing", codedErr.RemediationURL())
+	assert.Equal(t, "You've used all 100 hours in your plan.", errors.GetPrintableMessage(err))
+
+	respond = decision{Reason: "payment_required"}
+	codedErr, ok = errors.GetCode(hook.Check(context.Background(), event))
+	require.True(t, ok)
+	assert.Equal(t, errors.CodePaymentRequired, codedErr.Code())
+	assert.Equal(t, errors.CodePaymentRequired.RemediationURL(), codedErr.RemediationURL())
+}
+
+func TestWebhookUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	event := Event{Action: ActionResume, Namespace: "ns"}
+	err := NewWebhook(server.URL, false).Check(context.Background(), event)
+	assert.Error(t, err)
+	assert.False(t, IsDenied(err))
+
+	assert.NoError(t, NewWebhook(server.URL, true).Check(context.Background(), event))
+}
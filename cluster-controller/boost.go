@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/cluster-controller/metering"
+	clusterAuth "github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// boostCheckInterval is how often the manager checks for expired boosts.
+	boostCheckInterval = 30 * time.Second
+
+	// throttledThreshold is the fraction of its CPU limit that a service must
+	// be using for it to be considered throttled.
+	throttledThreshold = 0.8
+
+	defaultMaxBoostCPUMultiplier = 4
+	defaultMaxBoostDuration      = 2 * time.Hour
+)
+
+// boostPolicy limits how much users can boost their sandboxes.
+type boostPolicy struct {
+	maxCPUMultiplier float64
+	maxDuration      time.Duration
+}
+
+// boost is stored in the sandbox namespace's annotations while the sandbox is
+// boosted.
+type boost struct {
+	CPUMultiplier float64 `json:"cpuMultiplier"`
+	ExpiresAt     int64   `json:"expiresAt"`
+}
+
+func (b boost) expired() bool {
+	return time.Now().Unix() >= b.ExpiresAt
+}
+
+// getBoostPolicy reads the boost limits from the environment.
+func getBoostPolicy() boostPolicy {
+	policy := boostPolicy{
+		maxCPUMultiplier: defaultMaxBoostCPUMultiplier,
+		maxDuration:      defaultMaxBoostDuration,
+	}
+
+	if str, ok := os.LookupEnv("BLIMP_BOOST_MAX_CPU_MULTIPLIER"); ok {
+		val, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			log.WithError(err).WithField("BLIMP_BOOST_MAX_CPU_MULTIPLIER", str).
+				Warn("Couldn't parse $BLIMP_BOOST_MAX_CPU_MULTIPLIER")
+		} else {
+			policy.maxCPUMultiplier = val
+		}
+	}
+
+	if str, ok := os.LookupEnv("BLIMP_BOOST_MAX_DURATION"); ok {
+		val, err := time.ParseDuration(str)
+		if err != nil {
+			log.WithError(err).WithField("BLIMP_BOOST_MAX_DURATION", str).
+				Warn("Couldn't parse $BLIMP_BOOST_MAX_DURATION")
+		} else {
+			policy.maxDuration = val
+		}
+	}
+	return policy
+}
+
+func (p boostPolicy) validate(cpuMultiplier float64, duration time.Duration) error {
+	if p.maxCPUMultiplier <= 1 {
+		return errors.NewFriendlyError("Boosts are disabled on this cluster.")
+	}
+
+	if cpuMultiplier <= 1 || cpuMultiplier > p.maxCPUMultiplier {
+		return errors.NewFriendlyError("The CPU multiplier must be greater than 1x, "+
+			"and at most %gx.", p.maxCPUMultiplier)
+	}
+
+	if duration <= 0 || duration > p.maxDuration {
+		return errors.NewFriendlyError("Boosts must last at most %s.", p.maxDuration)
+	}
+	return nil
+}
+
+func (s *server) Boost(ctx context.Context, req *cluster.BoostRequest) (*cluster.BoostResponse, error) {
+	user, err := clusterAuth.AuthorizeRequest(clusterAuth.GetAuth(req))
+	if err != nil {
+		return &cluster.BoostResponse{}, err
+	}
+
+	duration := time.Duration(req.GetDurationSeconds()) * time.Second
+	if err := s.boostPolicy.validate(req.GetCpuMultiplier(), duration); err != nil {
+		return &cluster.BoostResponse{}, err
+	}
+
+	// Don't let users boost past their CPU quota.
+	if s.usageQuota.CpuCoreHours > 0 {
+		usage, err := s.meter.Get(user.Namespace, time.Now().Format(metering.PeriodFormat))
+		if err != nil {
+			return &cluster.BoostResponse{}, errors.WithContext("get usage", err)
+		}
+
+		if usage.CPUCoreHours >= s.usageQuota.CpuCoreHours {
+			return &cluster.BoostResponse{}, errors.NewCodedError(errors.CodeQuotaExceeded,
+				"Your sandbox has used its CPU quota for this month, so it can't be boosted.")
+		}
+	}
+
+	b := boost{
+		CPUMultiplier: req.GetCpuMultiplier(),
+		ExpiresAt:     time.Now().Add(duration).Unix(),
+	}
+	if err := s.setBoost(user.Namespace, &b); err != nil {
+		return &cluster.BoostResponse{}, errors.WithContext("update sandbox", err)
+	}
+
+	throttled, err := s.getThrottledPods(user.Namespace)
+	if err != nil {
+		return &cluster.BoostResponse{}, errors.WithContext("get throttled services", err)
+	}
+
+	var restarted []string
+	for _, pod := range throttled {
+		if err := s.restartWithCPUMultiplier(pod, b.CPUMultiplier); err != nil {
+			return &cluster.BoostResponse{}, errors.WithContext("restart throttled service", err)
+		}
+		restarted = append(restarted, pod.Labels["blimp.service"])
+	}
+	sort.Strings(restarted)
+	s.recordActivity(user.Namespace)
+
+	return &cluster.BoostResponse{
+		ExpiresAt:         b.ExpiresAt,
+		RestartedServices: restarted,
+	}, nil
+}
+
+// getCPUMultiplier returns the CPU multiplier that should be applied to the
+// services in the sandbox. It's 1 if the sandbox isn't boosted.
+func (s *server) getCPUMultiplier(namespace string) (float64, error) {
+	ns, err := s.statusFetcher.namespaceLister.Get(namespace)
+	if err != nil {
+		return 0, errors.WithContext("get namespace", err)
+	}
+
+	b, ok, err := parseBoost(ns)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok || b.expired() {
+		return 1, nil
+	}
+	return b.CPUMultiplier, nil
+}
+
+func parseBoost(ns *corev1.Namespace) (boost, bool, error) {
+	boostJSON, ok := ns.Annotations[kube.BoostAnnotation]
+	if !ok {
+		return boost{}, false, nil
+	}
+
+	var b boost
+	if err := json.Unmarshal([]byte(boostJSON), &b); err != nil {
+		return boost{}, false, errors.WithContext("parse boost", err)
+	}
+	return b, true, nil
+}
+
+// setBoost records the boost in the namespace. If `b` is nil, any existing
+// boost is removed.
+func (s *server) setBoost(namespace string, b *boost) error {
+	namespacesClient := s.kubeClient.CoreV1().Namespaces()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := namespacesClient.Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if b == nil {
+			delete(ns.Annotations, kube.BoostAnnotation)
+		} else {
+			boostJSON, err := json.Marshal(b)
+			if err != nil {
+				return err
+			}
+
+			if ns.Annotations == nil {
+				ns.Annotations = map[string]string{}
+			}
+			ns.Annotations[kube.BoostAnnotation] = string(boostJSON)
+		}
+
+		_, err = namespacesClient.Update(ns)
+		return err
+	})
+}
+
+// cpuStatsSummary is the subset of the kubelet's stats summary API that's
+// used for detecting throttled services.
+type cpuStatsSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Containers []struct {
+			Name string `json:"name"`
+			CPU  *struct {
+				UsageNanoCores *uint64 `json:"usageNanoCores"`
+			} `json:"cpu"`
+		} `json:"containers"`
+	} `json:"pods"`
+}
+
+// getThrottledPods returns the services in the namespace that are using most
+// of their CPU limit.
+func (s *server) getThrottledPods(namespace string) ([]*corev1.Pod, error) {
+	pods, err := s.statusFetcher.podLister.Pods(namespace).List(
+		labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("list pods", err)
+	}
+
+	podsByName := map[string]*corev1.Pod{}
+	nodes := map[string]struct{}{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || len(pod.Spec.Containers) == 0 {
+			continue
+		}
+		podsByName[pod.Name] = pod
+		nodes[pod.Spec.NodeName] = struct{}{}
+	}
+
+	var throttled []*corev1.Pod
+	for node := range nodes {
+		summaryJSON, err := s.kubeClient.CoreV1().RESTClient().Get().
+			Resource("nodes").
+			Name(node).
+			SubResource("proxy").
+			Suffix("stats/summary").
+			DoRaw()
+		if err != nil {
+			return nil, errors.WithContext("get node stats", err)
+		}
+
+		var summary cpuStatsSummary
+		if err := json.Unmarshal(summaryJSON, &summary); err != nil {
+			return nil, errors.WithContext("parse node stats", err)
+		}
+
+		for _, podStats := range summary.Pods {
+			if podStats.PodRef.Namespace != namespace {
+				continue
+			}
+
+			pod, ok := podsByName[podStats.PodRef.Name]
+			if !ok {
+				continue
+			}
+
+			container := pod.Spec.Containers[0]
+			limit := container.Resources.Limits.Cpu().MilliValue()
+			for _, c := range podStats.Containers {
+				if c.Name != container.Name || c.CPU == nil || c.CPU.UsageNanoCores == nil {
+					continue
+				}
+
+				usage := float64(*c.CPU.UsageNanoCores) / 1e6
+				if limit > 0 && usage >= throttledThreshold*float64(limit) {
+					throttled = append(throttled, pod)
+				}
+			}
+		}
+	}
+	return throttled, nil
+}
+
+// restartWithCPUMultiplier recreates the pod with its CPU resources scaled by
+// `multiplier`.
+func (s *server) restartWithCPUMultiplier(pod *corev1.Pod, multiplier float64) error {
+	newPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pod.Name,
+			Namespace:   pod.Namespace,
+			Labels:      pod.Labels,
+			Annotations: map[string]string{},
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	for k, v := range pod.Annotations {
+		if metadata.IsCustomPodAnnotation(pod.Annotations, k) {
+			newPod.Annotations[k] = v
+		}
+	}
+
+	compose.SetCPUMultiplier(&newPod, multiplier)
+	return kube.DeployPod(s.kubeClient, newPod, kube.DeployPodOptions{ForceRestart: true})
+}
+
+// runBoostExpirer ends boosts once they expire, and restarts any boosted
+// services with their normal resources. It never returns.
+func (s *server) runBoostExpirer(interval time.Duration) {
+	for range time.Tick(interval) {
+		namespaces, err := s.statusFetcher.namespaceLister.List(
+			labels.Set{"blimp.sandbox": "true"}.AsSelector())
+		if err != nil {
+			log.WithError(err).Warn("Failed to list namespaces")
+			continue
+		}
+
+		for _, ns := range namespaces {
+			b, ok, err := parseBoost(ns)
+			if !ok || (err == nil && !b.expired()) {
+				continue
+			}
+
+			logger := log.WithField("namespace", ns.Name)
+			if err := s.endBoost(ns.Name); err != nil {
+				logger.WithError(err).Warn("Failed to end boost")
+				continue
+			}
+			logger.Info("Ended boost")
+		}
+	}
+}
+
+func (s *server) endBoost(namespace string) error {
+	pods, err := s.statusFetcher.podLister.Pods(namespace).List(
+		labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return errors.WithContext("list pods", err)
+	}
+
+	for _, pod := range pods {
+		if _, ok := pod.Annotations[metadata.CPUMultiplierKey]; !ok {
+			continue
+		}
+
+		if err := s.restartWithCPUMultiplier(pod, 1); err != nil {
+			return errors.WithContext("restart boosted service", err)
+		}
+	}
+
+	// Remove the boost last so that we retry if any of the restarts fail.
+	return s.setBoost(namespace, nil)
+}
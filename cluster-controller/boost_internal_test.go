@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoostPolicyValidate(t *testing.T) {
+	policy := boostPolicy{maxCPUMultiplier: 4, maxDuration: 2 * time.Hour}
+
+	assert.NoError(t, policy.validate(2, 30*time.Minute))
+	assert.NoError(t, policy.validate(4, 2*time.Hour))
+	assert.Error(t, policy.validate(1, 30*time.Minute))
+	assert.Error(t, policy.validate(8, 30*time.Minute))
+	assert.Error(t, policy.validate(2, 0))
+	assert.Error(t, policy.validate(2, 3*time.Hour))
+
+	disabled := boostPolicy{maxCPUMultiplier: 1, maxDuration: 2 * time.Hour}
+	assert.Error(t, disabled.validate(2, 30*time.Minute))
+}
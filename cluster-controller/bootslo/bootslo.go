@@ -0,0 +1,532 @@
+// Package bootslo tracks how long services take to boot across all sandboxes,
+// so that operators can tell when a release makes booting slower.
+//
+// Boot times are derived from the timestamps that Kubernetes records for each
+// pod: when it was scheduled, when each of Blimp's init containers ran, and
+// when the service's container started. Each init container corresponds to
+// one of the phases reported by `blimp ps`, so the durations are broken down
+// by the same phases.
+//
+// The percentiles for each release are persisted in a ConfigMap in the Blimp
+// system namespace. Once enough boots have been recorded for the current
+// release, its percentiles are compared against the previous release's, and
+// regressions are logged and sent to an optional webhook.
+package bootslo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+)
+
+const (
+	// configMapName is the ConfigMap in the Blimp system namespace that
+	// stores the boot time percentiles of each release.
+	configMapName = "blimp-boot-slo"
+
+	// maxReleases is the number of releases whose percentiles are kept in
+	// the ConfigMap.
+	maxReleases = 10
+
+	// maxSamples is the number of recent boots per phase that percentiles
+	// are calculated from.
+	maxSamples = 1000
+
+	// minSamples is the number of boots that must be recorded for a release
+	// before it's compared against another release.
+	minSamples = 50
+
+	// regressionRatio and minRegression control how much slower a phase's
+	// p95 must be than the previous release's to be reported. The absolute
+	// minimum avoids alerting on phases that normally take under a second.
+	regressionRatio = 1.25
+	minRegression   = 5 * time.Second
+)
+
+// The phases that boot times are broken down into.
+const (
+	PhaseScheduling          = "scheduling"
+	PhaseInitializingVolumes = "initializing_volumes"
+	PhaseWaitDependsOn       = "wait_depends_on"
+	PhaseWaitSyncBind        = "wait_sync_bind"
+
+	// PhaseStarting covers pulling the service's image and starting its
+	// container.
+	PhaseStarting = "starting"
+
+	// PhaseTotal is the time from when the pod was created to when the
+	// service's container started.
+	PhaseTotal = "total"
+)
+
+var phases = []string{PhaseScheduling, PhaseInitializingVolumes, PhaseWaitDependsOn,
+	PhaseWaitSyncBind, PhaseStarting, PhaseTotal}
+
+// initContainerPhases maps Blimp's init containers to the phase they
+// implement. Init containers that aren't listed don't have their own phase.
+var initContainerPhases = map[string]string{
+	kube.ContainerNameCopyVCP:                   PhaseInitializingVolumes,
+	kube.ContainerNameInitializeVolumeFromImage: PhaseInitializingVolumes,
+	kube.ContainerNameWaitInitializedVolumes:    PhaseInitializingVolumes,
+	kube.ContainerNameWaitDependsOn:             PhaseWaitDependsOn,
+	kube.ContainerNameWaitInitialSync:           PhaseWaitSyncBind,
+}
+
+// Percentiles summarizes the boot times of a phase.
+type Percentiles struct {
+	P50 float64 `json:"p50Seconds"`
+	P95 float64 `json:"p95Seconds"`
+}
+
+// releaseStats is stored in the ConfigMap for each release.
+type releaseStats struct {
+	// FirstSeen is the Unix timestamp of when the release was first
+	// recorded. It's used to find the release that preceded the current
+	// one.
+	FirstSeen int64 `json:"firstSeen"`
+
+	Boots  int                    `json:"boots"`
+	Phases map[string]Percentiles `json:"phases"`
+}
+
+// Regression is a phase that's slower in the current release than in the
+// previous release.
+type Regression struct {
+	Phase           string  `json:"phase"`
+	Release         string  `json:"release"`
+	P95             float64 `json:"p95Seconds"`
+	BaselineRelease string  `json:"baselineRelease"`
+	BaselineP95     float64 `json:"baselineP95Seconds"`
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("Boot phase %s regressed in release %s: p95 is %.1fs, up from %.1fs in %s",
+		r.Phase, r.Release, r.P95, r.BaselineP95, r.BaselineRelease)
+}
+
+// Tracker records the boot times of the services in all sandboxes.
+type Tracker struct {
+	kubeClient kubernetes.Interface
+	podLister  listers.PodLister
+	release    string
+	webhookURL string
+
+	// startedAt is when the Tracker was created. Pods that booted before
+	// then are ignored so that restarting the manager doesn't record them
+	// again, or attribute them to the wrong release.
+	startedAt time.Time
+
+	sync.Mutex
+	recorded    map[string]struct{}
+	samples     map[string][]time.Duration
+	sums        map[string]time.Duration
+	counts      map[string]int
+	boots       int
+	baseline    string
+	regressions map[string]Regression
+}
+
+// New creates a Tracker for the given release. If webhookURL is non-empty,
+// regressions are POSTed to it as JSON.
+func New(kubeClient kubernetes.Interface, podLister listers.PodLister,
+	release, webhookURL string) *Tracker {
+	return &Tracker{
+		kubeClient:  kubeClient,
+		podLister:   podLister,
+		release:     release,
+		webhookURL:  webhookURL,
+		startedAt:   time.Now(),
+		recorded:    map[string]struct{}{},
+		samples:     map[string][]time.Duration{},
+		sums:        map[string]time.Duration{},
+		counts:      map[string]int{},
+		regressions: map[string]Regression{},
+	}
+}
+
+// Run records the boot times of newly booted services every `interval`, and
+// checks the current release for regressions. It never returns.
+func (t *Tracker) Run(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := t.sample(); err != nil {
+			log.WithError(err).Warn("Failed to record boot times")
+			continue
+		}
+
+		if err := t.checkRegressions(); err != nil {
+			log.WithError(err).Warn("Failed to check boot times for regressions")
+		}
+	}
+}
+
+func (t *Tracker) sample() error {
+	pods, err := t.podLister.List(labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return errors.WithContext("list pods", err)
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	// Replacing the map also drops pods that no longer exist.
+	recorded := map[string]struct{}{}
+	for _, pod := range pods {
+		uid := string(pod.UID)
+		if _, ok := t.recorded[uid]; ok {
+			recorded[uid] = struct{}{}
+			continue
+		}
+
+		durations, startedAt, ok := bootDurations(pod)
+		if !ok {
+			continue
+		}
+
+		recorded[uid] = struct{}{}
+		if startedAt.Before(t.startedAt) {
+			continue
+		}
+
+		t.boots++
+		for phase, duration := range durations {
+			t.samples[phase] = append(t.samples[phase], duration)
+			if len(t.samples[phase]) > maxSamples {
+				t.samples[phase] = t.samples[phase][1:]
+			}
+			t.sums[phase] += duration
+			t.counts[phase]++
+		}
+	}
+	t.recorded = recorded
+	return nil
+}
+
+// bootDurations returns how long the pod spent in each boot phase, and when
+// its service's container started. It returns false if the pod hasn't
+// finished booting for the first time.
+func bootDurations(pod *corev1.Pod) (map[string]time.Duration, time.Time, bool) {
+	containerName := names.ToDNS1123(pod.Labels["blimp.service"])
+	var started time.Time
+	for _, cs := range pod.Status.ContainerStatuses {
+		// Restarts reset the start time, so the first boot can't be
+		// measured anymore.
+		if cs.Name == containerName && cs.State.Running != nil && cs.RestartCount == 0 {
+			started = cs.State.Running.StartedAt.Time
+		}
+	}
+	if started.IsZero() {
+		return nil, time.Time{}, false
+	}
+
+	created := pod.CreationTimestamp.Time
+	durations := map[string]time.Duration{
+		PhaseTotal: started.Sub(created),
+	}
+
+	initStarted := created
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionTrue {
+			durations[PhaseScheduling] = cond.LastTransitionTime.Sub(created)
+			initStarted = cond.LastTransitionTime.Time
+		}
+	}
+
+	lastInitFinished := initStarted
+	for _, c := range pod.Status.InitContainerStatuses {
+		terminated := c.State.Terminated
+		if terminated == nil || terminated.Reason != "Completed" {
+			return nil, time.Time{}, false
+		}
+
+		if phase, ok := initContainerPhases[c.Name]; ok {
+			durations[phase] += terminated.FinishedAt.Sub(terminated.StartedAt.Time)
+		}
+		if terminated.FinishedAt.After(lastInitFinished) {
+			lastInitFinished = terminated.FinishedAt.Time
+		}
+	}
+	durations[PhaseStarting] = started.Sub(lastInitFinished)
+	return durations, started, true
+}
+
+// checkRegressions saves the current release's percentiles, and compares
+// them against the previous release.
+func (t *Tracker) checkRegressions() error {
+	t.Lock()
+	if t.boots < minSamples {
+		t.Unlock()
+		return nil
+	}
+	curr := releaseStats{Boots: t.boots, Phases: t.percentiles()}
+	t.Unlock()
+
+	releases, err := t.saveRelease(curr)
+	if err != nil {
+		return errors.WithContext("save release", err)
+	}
+
+	baselineRelease, ok := previousRelease(releases, t.release)
+	if !ok {
+		return nil
+	}
+
+	regressions := findRegressions(t.release, curr, baselineRelease, releases[baselineRelease])
+
+	t.Lock()
+	t.baseline = baselineRelease
+	var newRegressions []Regression
+	for _, regression := range regressions {
+		if _, ok := t.regressions[regression.Phase]; !ok {
+			newRegressions = append(newRegressions, regression)
+		}
+	}
+	t.regressions = map[string]Regression{}
+	for _, regression := range regressions {
+		t.regressions[regression.Phase] = regression
+	}
+	t.Unlock()
+
+	// Only alert the first time that each phase regresses.
+	for _, regression := range newRegressions {
+		log.WithField("phase", regression.Phase).
+			WithField("p95", regression.P95).
+			WithField("baselineP95", regression.BaselineP95).
+			WithField("baselineRelease", regression.BaselineRelease).
+			Warn("Boot time regressed")
+
+		if err := t.sendWebhook(regression); err != nil {
+			log.WithError(err).Warn("Failed to send boot time regression webhook")
+		}
+	}
+	return nil
+}
+
+// percentiles must be called while holding the lock.
+func (t *Tracker) percentiles() map[string]Percentiles {
+	result := map[string]Percentiles{}
+	for phase, samples := range t.samples {
+		result[phase] = Percentiles{
+			P50: percentile(samples, 0.5).Seconds(),
+			P95: percentile(samples, 0.95).Seconds(),
+		}
+	}
+	return result
+}
+
+// percentile returns the nearest-rank percentile of the samples.
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	} else if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+func findRegressions(release string, curr releaseStats, baselineRelease string,
+	baseline releaseStats) (regressions []Regression) {
+	for _, phase := range phases {
+		currPhase, ok := curr.Phases[phase]
+		if !ok {
+			continue
+		}
+
+		basePhase, ok := baseline.Phases[phase]
+		if !ok || basePhase.P95 == 0 {
+			continue
+		}
+
+		if currPhase.P95 > basePhase.P95*regressionRatio &&
+			currPhase.P95-basePhase.P95 >= minRegression.Seconds() {
+			regressions = append(regressions, Regression{
+				Phase:           phase,
+				Release:         release,
+				P95:             currPhase.P95,
+				BaselineRelease: baselineRelease,
+				BaselineP95:     basePhase.P95,
+			})
+		}
+	}
+	return regressions
+}
+
+// previousRelease returns the most recent release other than `curr` that
+// has recorded enough boots to be compared against.
+func previousRelease(releases map[string]releaseStats, curr string) (string, bool) {
+	var prev string
+	var prevFirstSeen int64
+	for release, stats := range releases {
+		if release == curr || stats.Boots < minSamples {
+			continue
+		}
+
+		if prev == "" || stats.FirstSeen > prevFirstSeen {
+			prev = release
+			prevFirstSeen = stats.FirstSeen
+		}
+	}
+	return prev, prev != ""
+}
+
+// saveRelease records the current release's percentiles in the ConfigMap,
+// and returns the percentiles of all the releases.
+func (t *Tracker) saveRelease(stats releaseStats) (map[string]releaseStats, error) {
+	configMapsClient := t.kubeClient.CoreV1().ConfigMaps(kube.BlimpNamespace)
+	var releases map[string]releaseStats
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := configMapsClient.Get(configMapName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      configMapName,
+					Namespace: kube.BlimpNamespace,
+				},
+			}
+		} else if err != nil {
+			return errors.WithContext("get", err)
+		}
+
+		releases = map[string]releaseStats{}
+		for release, statsJSON := range configMap.Data {
+			var parsed releaseStats
+			if err := json.Unmarshal([]byte(statsJSON), &parsed); err != nil {
+				log.WithError(err).WithField("release", release).Warn("Ignoring malformed boot time stats")
+				continue
+			}
+			releases[release] = parsed
+		}
+
+		stats.FirstSeen = time.Now().Unix()
+		if prev, ok := releases[t.release]; ok {
+			stats.FirstSeen = prev.FirstSeen
+		}
+		releases[t.release] = stats
+		pruneReleases(releases)
+
+		configMap.Data = map[string]string{}
+		for release, currStats := range releases {
+			statsJSON, err := json.Marshal(currStats)
+			if err != nil {
+				return errors.WithContext("marshal", err)
+			}
+			configMap.Data[release] = string(statsJSON)
+		}
+
+		if configMap.ResourceVersion == "" {
+			_, err = configMapsClient.Create(configMap)
+		} else {
+			_, err = configMapsClient.Update(configMap)
+		}
+		return err
+	})
+	return releases, err
+}
+
+// pruneReleases removes the oldest releases so that at most maxReleases are
+// kept.
+func pruneReleases(releases map[string]releaseStats) {
+	for len(releases) > maxReleases {
+		var oldest string
+		for release, stats := range releases {
+			if oldest == "" || stats.FirstSeen < releases[oldest].FirstSeen {
+				oldest = release
+			}
+		}
+		delete(releases, oldest)
+	}
+}
+
+func (t *Tracker) sendWebhook(regression Regression) error {
+	if t.webhookURL == "" {
+		return nil
+	}
+
+	// The `text` field lets the webhook be posted directly to chat services
+	// such as Slack.
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+		Regression
+	}{regression.String(), regression})
+	if err != nil {
+		return errors.WithContext("marshal", err)
+	}
+
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(t.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// WriteMetrics writes the boot time percentiles in the Prometheus text
+// format.
+func (t *Tracker) WriteMetrics(w io.Writer) {
+	t.Lock()
+	defer t.Unlock()
+
+	fmt.Fprintln(w, "# HELP blimp_boot_phase_duration_seconds How long services spend in each boot phase.")
+	fmt.Fprintln(w, "# TYPE blimp_boot_phase_duration_seconds summary")
+	percentiles := t.percentiles()
+	for _, phase := range phases {
+		if t.counts[phase] == 0 {
+			continue
+		}
+
+		phaseLabels := fmt.Sprintf("phase=%q,release=%q", phase, t.release)
+		fmt.Fprintf(w, "blimp_boot_phase_duration_seconds{%s,quantile=\"0.5\"} %.3f\n",
+			phaseLabels, percentiles[phase].P50)
+		fmt.Fprintf(w, "blimp_boot_phase_duration_seconds{%s,quantile=\"0.95\"} %.3f\n",
+			phaseLabels, percentiles[phase].P95)
+		fmt.Fprintf(w, "blimp_boot_phase_duration_seconds_sum{%s} %.3f\n", phaseLabels, t.sums[phase].Seconds())
+		fmt.Fprintf(w, "blimp_boot_phase_duration_seconds_count{%s} %d\n", phaseLabels, t.counts[phase])
+	}
+
+	if t.baseline == "" {
+		return
+	}
+
+	fmt.Fprintln(w, "# HELP blimp_boot_phase_regression Whether each boot phase is slower than in the previous release.")
+	fmt.Fprintln(w, "# TYPE blimp_boot_phase_regression gauge")
+	for _, phase := range phases {
+		_, regressed := t.regressions[phase]
+		regressedInt := 0
+		if regressed {
+			regressedInt = 1
+		}
+		fmt.Fprintf(w, "blimp_boot_phase_regression{phase=%q,release=%q,baseline_release=%q} %d\n",
+			phase, t.release, t.baseline, regressedInt)
+	}
+}
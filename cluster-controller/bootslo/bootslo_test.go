@@ -0,0 +1,199 @@
+package bootslo
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+func TestBootDurations(t *testing.T) {
+	created := time.Unix(1600000000, 0)
+	at := func(seconds int) metav1.Time {
+		return metav1.NewTime(created.Add(time.Duration(seconds) * time.Second))
+	}
+	completed := func(name string, start, finish int) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name: name,
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{
+					Reason:     "Completed",
+					StartedAt:  at(start),
+					FinishedAt: at(finish),
+				},
+			},
+		}
+	}
+	running := func(name string, start int, restarts int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:         name,
+			RestartCount: restarts,
+			State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{StartedAt: at(start)},
+			},
+		}
+	}
+	makePod := func(initStatuses []corev1.ContainerStatus, cs corev1.ContainerStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "my-service",
+				Labels:            map[string]string{"blimp.service": "my_service"},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{
+					Type:               corev1.PodScheduled,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: at(2),
+				}},
+				InitContainerStatuses: initStatuses,
+				ContainerStatuses:     []corev1.ContainerStatus{cs},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		pod          *corev1.Pod
+		expOK        bool
+		expDurations map[string]time.Duration
+	}{
+		{
+			name: "Booted",
+			pod: makePod([]corev1.ContainerStatus{
+				completed(kube.ContainerNameCopyVCP, 3, 4),
+				completed(kube.ContainerNameInitializeVolumeFromImage, 5, 8),
+				completed(kube.ContainerNameWaitDependsOn, 8, 20),
+				completed(kube.ContainerNameWaitInitialSync, 20, 50),
+			}, running("my-service", 60, 0)),
+			expOK: true,
+			expDurations: map[string]time.Duration{
+				PhaseScheduling:          2 * time.Second,
+				PhaseInitializingVolumes: 4 * time.Second,
+				PhaseWaitDependsOn:       12 * time.Second,
+				PhaseWaitSyncBind:        30 * time.Second,
+				PhaseStarting:            10 * time.Second,
+				PhaseTotal:               60 * time.Second,
+			},
+		},
+		{
+			name: "StillInitializing",
+			pod: makePod([]corev1.ContainerStatus{
+				completed(kube.ContainerNameCopyVCP, 3, 4),
+				{Name: kube.ContainerNameWaitDependsOn, State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{StartedAt: at(5)},
+				}},
+			}, corev1.ContainerStatus{Name: "my-service"}),
+		},
+		{
+			name:  "Restarted",
+			pod:   makePod(nil, running("my-service", 60, 1)),
+			expOK: false,
+		},
+		{
+			name:  "IgnoresSidecars",
+			pod:   makePod(nil, running("blimp-chaos", 60, 0)),
+			expOK: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			durations, _, ok := bootDurations(test.pod)
+			assert.Equal(t, test.expOK, ok)
+			assert.Equal(t, test.expDurations, durations)
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i > 0; i-- {
+		samples = append(samples, time.Duration(i)*time.Second)
+	}
+
+	assert.Equal(t, 50*time.Second, percentile(samples, 0.5))
+	assert.Equal(t, 95*time.Second, percentile(samples, 0.95))
+	assert.Equal(t, time.Duration(0), percentile(nil, 0.5))
+
+	// The samples shouldn't be reordered.
+	assert.Equal(t, 100*time.Second, samples[0])
+}
+
+func TestFindRegressions(t *testing.T) {
+	baseline := releaseStats{
+		Boots: 100,
+		Phases: map[string]Percentiles{
+			PhaseTotal:         {P50: 30, P95: 60},
+			PhaseScheduling:    {P50: 0.5, P95: 1},
+			PhaseWaitDependsOn: {P50: 10, P95: 20},
+		},
+	}
+	curr := releaseStats{
+		Boots: 100,
+		Phases: map[string]Percentiles{
+			// Regressed by more than the ratio and the minimum.
+			PhaseTotal: {P50: 40, P95: 90},
+
+			// Regressed by more than the ratio, but by less than the minimum.
+			PhaseScheduling: {P50: 1, P95: 3},
+
+			// Regressed by more than the minimum, but by less than the ratio.
+			PhaseWaitDependsOn: {P50: 10, P95: 24},
+
+			// Not in the baseline.
+			PhaseStarting: {P50: 10, P95: 100},
+		},
+	}
+
+	assert.Equal(t, []Regression{{
+		Phase:           PhaseTotal,
+		Release:         "v2",
+		P95:             90,
+		BaselineRelease: "v1",
+		BaselineP95:     60,
+	}}, findRegressions("v2", curr, "v1", baseline))
+}
+
+func TestPreviousRelease(t *testing.T) {
+	releases := map[string]releaseStats{
+		"v1":  {FirstSeen: 1, Boots: 100},
+		"v2":  {FirstSeen: 2, Boots: 100},
+		"v3":  {FirstSeen: 3, Boots: minSamples - 1},
+		"v4":  {FirstSeen: 4, Boots: 100},
+		"old": {FirstSeen: 0, Boots: 100},
+	}
+
+	prev, ok := previousRelease(releases, "v4")
+	assert.True(t, ok)
+	assert.Equal(t, "v2", prev)
+
+	_, ok = previousRelease(map[string]releaseStats{"v4": {FirstSeen: 4, Boots: 100}}, "v4")
+	assert.False(t, ok)
+
+	pruneReleases(releases)
+	assert.Len(t, releases, 5)
+}
+
+func TestWriteMetrics(t *testing.T) {
+	tracker := New(nil, nil, "v2", "")
+	tracker.samples[PhaseTotal] = []time.Duration{10 * time.Second, 20 * time.Second}
+	tracker.sums[PhaseTotal] = 30 * time.Second
+	tracker.counts[PhaseTotal] = 2
+
+	var out bytes.Buffer
+	tracker.WriteMetrics(&out)
+	assert.Equal(t, `# HELP blimp_boot_phase_duration_seconds How long services spend in each boot phase.
+# TYPE blimp_boot_phase_duration_seconds summary
+blimp_boot_phase_duration_seconds{phase="total",release="v2",quantile="0.5"} 10.000
+blimp_boot_phase_duration_seconds{phase="total",release="v2",quantile="0.95"} 20.000
+blimp_boot_phase_duration_seconds_sum{phase="total",release="v2"} 30.000
+blimp_boot_phase_duration_seconds_count{phase="total",release="v2"} 2
+`, out.String())
+}
@@ -7,9 +7,9 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/pkg/errors"
 	"github.com/kelda/blimp/pkg/kube"
 	"github.com/kelda/blimp/pkg/version"
-	"github.com/kelda/blimp/pkg/errors"
 )
 
 func createBuildkitd(kubeClient kubernetes.Interface, namespace string) error {
@@ -80,6 +80,7 @@ func createBuildkitd(kubeClient kubernetes.Interface, namespace string) error {
 			RestartPolicy: corev1.RestartPolicyAlways,
 		},
 	}
+	excludeFromMesh(&pod)
 
 	if err := kube.DeployPod(kubeClient, pod, kube.DeployPodOptions{}); err != nil {
 		return errors.WithContext("deploy pod", err)
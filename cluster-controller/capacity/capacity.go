@@ -0,0 +1,250 @@
+// Package capacity keeps spare room in the sandbox node pool, so that
+// sandboxes don't have to wait for new nodes to boot.
+//
+// Cloud providers are scaled through the cluster autoscaler rather than
+// directly: the manager runs a Deployment of placeholder pods that have a
+// lower priority than every sandbox pod. The placeholders occupy the spare
+// room until a sandbox needs it, at which point Kubernetes preempts them. The
+// evicted placeholders become unschedulable, which makes the cluster
+// autoscaler add nodes before sandboxes have to queue for them.
+//
+// The number of placeholders grows with the number of sandbox pods that are
+// waiting for capacity, and shrinks back to the configured headroom once
+// demand has been low for a while.
+package capacity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/version"
+)
+
+const (
+	// priorityClassName is the priority class of the placeholder pods. Its
+	// priority is below the default of zero, so any sandbox pod can preempt
+	// the placeholders.
+	priorityClassName   = "blimp-capacity-placeholder"
+	placeholderPriority = -10
+
+	deploymentName = "capacity-placeholder"
+
+	// placeholderServices is the number of services that each placeholder
+	// reserves room for.
+	placeholderServices = 10
+
+	// scaleDownDelay is how long the placeholders are kept after the last
+	// time that sandboxes were waiting for capacity.
+	scaleDownDelay = 15 * time.Minute
+)
+
+// Manager scales the placeholder pods.
+type Manager struct {
+	kubeClient kubernetes.Interface
+	podLister  listers.PodLister
+
+	// headroom is the number of placeholders to run when no sandboxes are
+	// waiting for capacity, and maxPlaceholders caps how many are run when
+	// sandboxes are waiting.
+	headroom        int
+	maxPlaceholders int
+
+	lastDemand time.Time
+}
+
+func New(kubeClient kubernetes.Interface, podLister listers.PodLister,
+	headroom, maxPlaceholders int) *Manager {
+	return &Manager{
+		kubeClient:      kubeClient,
+		podLister:       podLister,
+		headroom:        headroom,
+		maxPlaceholders: maxPlaceholders,
+	}
+}
+
+// Run creates the placeholder deployment, and updates its number of replicas
+// every `interval`. It never returns.
+func (m *Manager) Run(interval time.Duration) {
+	for {
+		if err := m.createPlaceholders(); err != nil {
+			log.WithError(err).Warn("Failed to create capacity placeholders")
+			time.Sleep(interval)
+			continue
+		}
+		break
+	}
+
+	for range time.Tick(interval) {
+		if err := m.scale(); err != nil {
+			log.WithError(err).Warn("Failed to scale capacity placeholders")
+		}
+	}
+}
+
+func (m *Manager) scale() error {
+	pods, err := m.podLister.List(labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return errors.WithContext("list pods", err)
+	}
+
+	var waiting int
+	for _, pod := range pods {
+		if IsWaitingForCapacity(pod) {
+			waiting++
+		}
+	}
+
+	now := time.Now()
+	if waiting != 0 {
+		m.lastDemand = now
+	}
+
+	deploymentsClient := m.kubeClient.AppsV1().Deployments(kube.BlimpNamespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment, err := deploymentsClient.Get(deploymentName, metav1.GetOptions{})
+		if err != nil {
+			return errors.WithContext("get deployment", err)
+		}
+
+		var curr int
+		if deployment.Spec.Replicas != nil {
+			curr = int(*deployment.Spec.Replicas)
+		}
+
+		desired := desiredPlaceholders(curr, waiting, m.headroom, m.maxPlaceholders,
+			now.Sub(m.lastDemand) > scaleDownDelay)
+		if desired == curr {
+			return nil
+		}
+
+		log.WithField("waiting", waiting).Infof("Scaling capacity placeholders from %d to %d", curr, desired)
+		replicas := int32(desired)
+		deployment.Spec.Replicas = &replicas
+		_, err = deploymentsClient.Update(deployment)
+		return err
+	})
+}
+
+// desiredPlaceholders returns how many placeholders should run. Each pod
+// waiting for capacity adds a placeholder, so that the autoscaler adds enough
+// room for the next sandboxes as well. Placeholders are only removed once
+// demand has been low for scaleDownDelay, so that the cluster doesn't
+// thrash between scaling up and down.
+func desiredPlaceholders(curr, waiting, headroom, max int, idle bool) int {
+	desired := headroom + waiting
+	if desired < curr && !idle {
+		desired = curr
+	}
+
+	if desired > max {
+		desired = max
+	}
+	if desired < 0 {
+		desired = 0
+	}
+	return desired
+}
+
+func (m *Manager) createPlaceholders() error {
+	priorityClass := &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: priorityClassName,
+		},
+		Value:       placeholderPriority,
+		Description: "Placeholder pods that reserve room for Blimp sandboxes.",
+	}
+	_, err := m.kubeClient.SchedulingV1().PriorityClasses().Create(priorityClass)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return errors.WithContext("create priority class", err)
+	}
+
+	deployment := m.deployment()
+	deploymentsClient := m.kubeClient.AppsV1().Deployments(kube.BlimpNamespace)
+	curr, err := deploymentsClient.Get(deploymentName, metav1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		_, err = deploymentsClient.Create(deployment)
+		return err
+	case err != nil:
+		return errors.WithContext("get deployment", err)
+	}
+
+	// Keep the current number of replicas, but update the pod template in
+	// case the placeholder's size or image changed.
+	curr.Spec.Template = deployment.Spec.Template
+	_, err = deploymentsClient.Update(curr)
+	return err
+}
+
+func (m *Manager) deployment() *appsv1.Deployment {
+	podLabels := map[string]string{"service": deploymentName}
+	replicas := int32(m.headroom)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentName,
+			Namespace: kube.BlimpNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "placeholder",
+						Image: version.ReservationImage,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								"cpu": resource.MustParse(fmt.Sprintf("%d%s",
+									compose.CPURequest*placeholderServices, compose.CPURequestUnits)),
+								"memory": resource.MustParse(fmt.Sprintf("%d%s",
+									compose.MemoryRequest*placeholderServices, compose.MemoryRequestUnits)),
+							},
+						},
+					}},
+					PriorityClassName:             priorityClassName,
+					TerminationGracePeriodSeconds: new(int64),
+					Affinity:                      affinity.OnSandboxNode(),
+					Tolerations:                   affinity.Tolerations(),
+				},
+			},
+		},
+	}
+}
+
+// IsWaitingForCapacity returns whether the pod can't be scheduled because the
+// cluster doesn't have enough room for it. Pods that can't be scheduled for
+// other reasons, such as their node being cordoned, aren't included since
+// adding nodes won't help them.
+func IsWaitingForCapacity(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodPending {
+		return false
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled &&
+			condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable &&
+			strings.Contains(condition.Message, "Insufficient") {
+			return true
+		}
+	}
+	return false
+}
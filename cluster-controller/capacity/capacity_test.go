@@ -0,0 +1,55 @@
+package capacity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDesiredPlaceholders(t *testing.T) {
+	tests := []struct {
+		name                    string
+		curr, waiting, headroom int
+		idle                    bool
+		exp                     int
+	}{
+		{name: "Headroom", curr: 0, waiting: 0, headroom: 2, exp: 2},
+		{name: "Scale up with demand", curr: 2, waiting: 3, headroom: 2, exp: 5},
+		{name: "Capped", curr: 2, waiting: 20, headroom: 2, exp: 10},
+		{name: "Hold while busy", curr: 5, waiting: 0, headroom: 2, exp: 5},
+		{name: "Scale down once idle", curr: 5, waiting: 0, headroom: 2, idle: true, exp: 2},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.exp,
+				desiredPlaceholders(test.curr, test.waiting, test.headroom, 10, test.idle))
+		})
+	}
+}
+
+func TestIsWaitingForCapacity(t *testing.T) {
+	pod := func(phase corev1.PodPhase, msg string) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: phase,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Reason:  corev1.PodReasonUnschedulable,
+						Message: msg,
+					},
+				},
+			},
+		}
+	}
+
+	assert.True(t, IsWaitingForCapacity(pod(corev1.PodPending,
+		"0/3 nodes are available: 3 Insufficient cpu.")))
+	assert.False(t, IsWaitingForCapacity(pod(corev1.PodPending,
+		"0/3 nodes are available: 3 node(s) were unschedulable.")))
+	assert.False(t, IsWaitingForCapacity(pod(corev1.PodRunning, "")))
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/cluster-controller/node"
+	"github.com/kelda/blimp/cluster-controller/volume"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/version"
+)
+
+// cloneOfAnnotation is set on sandbox clones to the namespace of the sandbox
+// they were cloned from.
+const cloneOfAnnotation = "blimp.clone-of"
+
+// cloneNamespace returns the namespace used for the given clone of a sandbox.
+func cloneNamespace(namespace string, idx int) string {
+	return names.ToDNS1123(fmt.Sprintf("%s-clone-%d", namespace, idx))
+}
+
+// cloneSandbox creates a copy of the user's sandbox in the given namespace.
+// The clone runs the same pods as the original sandbox, but has its own DNS
+// server and volumes so that it's fully isolated from the original.
+// Bind volumes aren't synced into the clone, so the contents of the original
+// volumes are only available in the clone if `copyVolumes` is set.
+func (s *server) cloneSandbox(ctx context.Context, user auth.User, namespace string, copyVolumes bool) error {
+	clone := auth.User{Name: user.Name, Namespace: namespace}
+	if err := s.createNamespace(ctx, clone); err != nil {
+		return errors.WithContext("create namespace", err)
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := s.kubeClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		ns.Annotations[cloneOfAnnotation] = user.Namespace
+		_, err = s.kubeClient.CoreV1().Namespaces().Update(ns)
+		return err
+	})
+	if err != nil {
+		return errors.WithContext("annotate namespace", err)
+	}
+
+	if err := s.copySandboxConfig(user.Namespace, namespace); err != nil {
+		return errors.WithContext("copy config", err)
+	}
+
+	if err := s.grantCloneAccess(user.Namespace, namespace); err != nil {
+		return errors.WithContext("grant access", err)
+	}
+
+	placement, err := s.placeSandbox(namespace, s.sandboxRequests(user.Namespace))
+	if err != nil {
+		return errors.WithContext("place sandbox", err)
+	}
+
+	if err := s.deployDNS(clone, placement); err != nil {
+		return errors.WithContext("deploy dns", err)
+	}
+
+	dnsPod, err := s.getPod(ctx, namespace, "dns", podIsReady)
+	if err != nil {
+		return errors.WithContext("get dns pod", err)
+	}
+
+	nodeControllerIP, err := node.GetNodeControllerInternalIP(s.kubeClient, dnsPod.Spec.NodeName)
+	if err != nil {
+		return errors.WithContext("get node controller's IP", err)
+	}
+
+	if copyVolumes {
+		if err := s.copyVolumes(ctx, user, clone); err != nil {
+			return errors.WithContext("copy volumes", err)
+		}
+	}
+
+	origDNSPod, err := s.kubeClient.CoreV1().Pods(user.Namespace).Get("dns", metav1.GetOptions{})
+	if err != nil {
+		return errors.WithContext("get original dns pod", err)
+	}
+
+	currPods, err := s.kubeClient.CoreV1().Pods(user.Namespace).List(metav1.ListOptions{
+		LabelSelector: "blimp.customerPod=true",
+	})
+	if err != nil {
+		return errors.WithContext("list pods", err)
+	}
+
+	projectPods := map[string][]corev1.Pod{}
+	for _, pod := range currPods.Items {
+		if pod.Name == "reservation" || isSurgePod(&pod) {
+			continue
+		}
+		project := pod.Labels[projectLabel]
+		projectPods[project] = append(projectPods[project], clonePod(pod, clone, placement,
+			origDNSPod.Status.PodIP, dnsPod.Status.PodIP, nodeControllerIP))
+	}
+
+	for project, pods := range projectPods {
+		if err := s.deployCustomerPods(namespace, project, pods, nil); err != nil {
+			return errors.WithContext("deploy pods", err)
+		}
+	}
+	return nil
+}
+
+// copySandboxConfig copies the ConfigMaps and Secrets referenced by the
+// sandbox's pods into the clone.
+func (s *server) copySandboxConfig(from, to string) error {
+	configMaps, err := s.kubeClient.CoreV1().ConfigMaps(from).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.WithContext("list configmaps", err)
+	}
+
+	for _, configMap := range configMaps.Items {
+		err := kube.DeployConfigMap(s.kubeClient, corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      configMap.Name,
+				Namespace: to,
+				Labels:    configMap.Labels,
+			},
+			Data:       configMap.Data,
+			BinaryData: configMap.BinaryData,
+		})
+		if err != nil {
+			return errors.WithContext("deploy configmap", err)
+		}
+	}
+
+	secrets, err := s.kubeClient.CoreV1().Secrets(from).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.WithContext("list secrets", err)
+	}
+
+	for _, secret := range secrets.Items {
+		// Service account tokens are generated by Kubernetes for the service
+		// accounts in the clone.
+		if secret.Type == corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+
+		err := kube.DeploySecret(s.kubeClient, corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secret.Name,
+				Namespace: to,
+				Labels:    secret.Labels,
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		})
+		if err != nil {
+			return errors.WithContext("deploy secret", err)
+		}
+	}
+
+	podRunner, err := s.kubeClient.CoreV1().ServiceAccounts(from).Get("pod-runner", metav1.GetOptions{})
+	if err != nil {
+		return errors.WithContext("get pod runner service account", err)
+	}
+
+	return kube.DeployServiceAccount(s.kubeClient, corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podRunner.Name,
+			Namespace: to,
+		},
+		ImagePullSecrets: podRunner.ImagePullSecrets,
+	})
+}
+
+// grantCloneAccess allows the CLI credentials for the original sandbox to
+// access the pods in the clone.
+func (s *server) grantCloneAccess(from, to string) error {
+	role := rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: to,
+			Name:      "blimp-client",
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods/log"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods/exec"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "watch"},
+			},
+		},
+	}
+	if err := kube.DeployRole(s.kubeClient, role); err != nil {
+		return errors.WithContext("deploy role", err)
+	}
+
+	return kube.DeployRoleBinding(s.kubeClient, rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: to,
+			Name:      "blimp-client",
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      "ServiceAccount",
+			Name:      "blimp-client",
+			Namespace: from,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     role.Name,
+		},
+	})
+}
+
+// clonePod converts a pod from the original sandbox so that it runs in the
+// clone.
+func clonePod(pod corev1.Pod, clone auth.User, placement affinity.Placement,
+	origDNSIP, dnsIP, nodeControllerIP string) corev1.Pod {
+
+	labels := map[string]string{}
+	for k, v := range pod.Labels {
+		labels[k] = v
+	}
+	labels[affinity.ColocateNamespaceKey] = clone.Namespace
+
+	spec := *pod.Spec.DeepCopy()
+	spec.NodeName = ""
+	spec.Affinity = affinity.ForUser(clone, placement)
+	spec.Tolerations = affinity.Tolerations()
+
+	if spec.DNSConfig != nil {
+		for i, ns := range spec.DNSConfig.Nameservers {
+			if ns == origDNSIP {
+				spec.DNSConfig.Nameservers[i] = dnsIP
+			}
+		}
+	}
+
+	// The service account token volumes are injected by Kubernetes, and
+	// reference secrets that only exist in the original namespace.
+	tokenPrefix := spec.ServiceAccountName + "-token-"
+	var volumes []corev1.Volume
+	removedVolumes := map[string]struct{}{}
+	for _, v := range spec.Volumes {
+		if v.Secret != nil && strings.HasPrefix(v.Secret.SecretName, tokenPrefix) {
+			removedVolumes[v.Name] = struct{}{}
+			continue
+		}
+		volumes = append(volumes, v)
+	}
+	spec.Volumes = volumes
+
+	fixContainer := func(c *corev1.Container) {
+		var mounts []corev1.VolumeMount
+		for _, mount := range c.VolumeMounts {
+			if _, ok := removedVolumes[mount.Name]; !ok {
+				mounts = append(mounts, mount)
+			}
+		}
+		c.VolumeMounts = mounts
+
+		for i, env := range c.Env {
+			if env.Name == "NODE_CONTROLLER_HOST" {
+				c.Env[i].Value = nodeControllerIP
+			}
+		}
+	}
+
+	// Bind volumes aren't synced into clones, so don't wait for them.
+	var initContainers []corev1.Container
+	for _, c := range spec.InitContainers {
+		if c.Name == kube.ContainerNameWaitInitialSync {
+			continue
+		}
+		fixContainer(&c)
+		initContainers = append(initContainers, c)
+	}
+	spec.InitContainers = initContainers
+
+	for i := range spec.Containers {
+		fixContainer(&spec.Containers[i])
+	}
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pod.Name,
+			Namespace:   clone.Namespace,
+			Labels:      labels,
+			Annotations: pod.Annotations,
+		},
+		Spec: spec,
+	}
+}
+
+// copyVolumes copies the contents of the original sandbox's volumes into the
+// clone by streaming a tarball between helper pods in each namespace.
+func (s *server) copyVolumes(ctx context.Context, from, to auth.User) error {
+	src, cleanupSrc, err := s.startVolumeHelper(ctx, from)
+	if err != nil {
+		return err
+	}
+	defer cleanupSrc()
+
+	dst, cleanupDst, err := s.startVolumeHelper(ctx, to)
+	if err != nil {
+		return err
+	}
+	defer cleanupDst()
+
+	tarReader, tarWriter := io.Pipe()
+	exportErr := make(chan error, 1)
+	go func() {
+		err := s.execInPod(src, []string{"tar", "-C", "/pv", "-cf", "-", "."}, nil, tarWriter)
+		tarWriter.CloseWithError(err)
+		exportErr <- err
+	}()
+
+	if err := s.execInPod(dst, []string{"tar", "-C", "/pv", "-xf", "-"}, tarReader, nil); err != nil {
+		// Unblock the export if the import failed.
+		tarReader.CloseWithError(err)
+		return errors.WithContext("import", err)
+	}
+
+	if err := <-exportErr; err != nil {
+		return errors.WithContext("export", err)
+	}
+	return nil
+}
+
+// startVolumeHelper boots a pod that mounts the user's volumes at /pv, so that
+// their contents can be read and written with execInPod. The returned function
+// deletes the pod.
+func (s *server) startVolumeHelper(ctx context.Context, user auth.User) (corev1.Pod, func(), error) {
+	placement, err := s.getPlacement(user.Namespace)
+	if err != nil {
+		return corev1.Pod{}, nil, errors.WithContext("get placement", err)
+	}
+
+	pod := volumeHelperPod(user, placement)
+	if err := kube.DeployPod(s.kubeClient, pod, kube.DeployPodOptions{}); err != nil {
+		return corev1.Pod{}, nil, errors.WithContext("deploy helper", err)
+	}
+
+	cleanup := func() {
+		if err := kube.DeletePod(s.kubeClient, pod.Namespace, pod.Name); err != nil {
+			log.WithError(err).WithField("namespace", pod.Namespace).Warn("Failed to delete volume copier")
+		}
+	}
+
+	if _, err := s.getPod(ctx, pod.Namespace, pod.Name, podIsReady); err != nil {
+		cleanup()
+		return corev1.Pod{}, nil, errors.WithContext("wait for helper", err)
+	}
+	return pod, cleanup, nil
+}
+
+func volumeHelperPod(user auth.User, placement affinity.Placement) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "volume-copier",
+			Namespace: user.Namespace,
+			Labels: map[string]string{
+				"service":                     "volume-copier",
+				affinity.ColocateNamespaceKey: user.Namespace,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    "volume-copier",
+				Image:   version.InitImage,
+				Command: []string{"sh", "-c", "trap 'exit 0' TERM; while true; do sleep 1; done"},
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      volume.PersistentVolume.Name,
+					MountPath: "/pv",
+				}},
+			}},
+			Volumes:     []corev1.Volume{volume.PersistentVolume},
+			Affinity:    affinity.ForUser(user, placement),
+			Tolerations: affinity.Tolerations(),
+		},
+	}
+}
+
+func (s *server) execInPod(pod corev1.Pod, cmd []string, stdin io.Reader, stdout io.Writer) error {
+	var stderr strings.Builder
+	req := s.kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		SubResource("exec").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		VersionedParams(&corev1.PodExecOptions{
+			Container: pod.Spec.Containers[0].Name,
+			Command:   cmd,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(s.restConfig, "POST", req.URL())
+	if err != nil {
+		return errors.WithContext("setup exec", err)
+	}
+
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return errors.WithContext(fmt.Sprintf("exec (%s)", stderr.String()), err)
+	}
+	return nil
+}
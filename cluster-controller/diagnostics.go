@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	clusterAuth "github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// defaultMaxStatusSnapshots is how many status updates are returned by
+// GetDiagnostics if the client doesn't specify a limit.
+const defaultMaxStatusSnapshots = 20
+
+func (s *server) GetDiagnostics(ctx context.Context, req *cluster.GetDiagnosticsRequest) (
+	*cluster.GetDiagnosticsResponse, error) {
+
+	user, err := clusterAuth.AuthorizeRequest(clusterAuth.GetAuth(req))
+	if err != nil {
+		return &cluster.GetDiagnosticsResponse{}, err
+	}
+	log.WithField("namespace", user.Namespace).Info("Start GetDiagnostics")
+
+	status, err := s.statusFetcher.Get(user.Namespace)
+	if err != nil {
+		return &cluster.GetDiagnosticsResponse{}, errors.WithContext("get status", err)
+	}
+
+	maxSnapshots := int(req.GetMaxStatusSnapshots())
+	if maxSnapshots <= 0 {
+		maxSnapshots = defaultMaxStatusSnapshots
+	}
+	var history []*cluster.StatusSnapshot
+	for _, update := range s.statusSessions.recent(user.Namespace, maxSnapshots) {
+		update := update
+		history = append(history, &cluster.StatusSnapshot{
+			Time:   update.time.Unix(),
+			Status: &update.status,
+		})
+	}
+
+	pods, err := s.statusFetcher.podLister.Pods(user.Namespace).List(labels.Everything())
+	if err != nil {
+		return &cluster.GetDiagnosticsResponse{}, errors.WithContext("list pods", err)
+	}
+
+	var podDiagnostics []*cluster.PodDiagnostics
+	for _, pod := range pods {
+		podDiagnostics = append(podDiagnostics, getPodDiagnostics(pod))
+	}
+	sort.Slice(podDiagnostics, func(i, j int) bool {
+		return podDiagnostics[i].Pod < podDiagnostics[j].Pod
+	})
+
+	events, err := s.getSandboxEvents(user.Namespace)
+	if err != nil {
+		return &cluster.GetDiagnosticsResponse{}, errors.WithContext("get events", err)
+	}
+
+	return &cluster.GetDiagnosticsResponse{
+		Status:        &status,
+		StatusHistory: history,
+		Pods:          podDiagnostics,
+		Events:        events,
+	}, nil
+}
+
+// getPodDiagnostics summarizes the pod's status. It intentionally doesn't
+// include the pod's spec, since it contains the service's environment
+// variables.
+func getPodDiagnostics(pod *corev1.Pod) *cluster.PodDiagnostics {
+	diagnostics := &cluster.PodDiagnostics{
+		Service: pod.Labels["blimp.service"],
+		Pod:     pod.Name,
+		Phase:   string(pod.Status.Phase),
+		Node:    pod.Spec.NodeName,
+	}
+
+	// Copy the statuses to avoid modifying the pod, which is shared with the
+	// informer's cache.
+	var containers []corev1.ContainerStatus
+	containers = append(containers, pod.Status.InitContainerStatuses...)
+	containers = append(containers, pod.Status.ContainerStatuses...)
+	for _, c := range containers {
+		containerDiagnostics := &cluster.ContainerDiagnostics{
+			Name:         c.Name,
+			Ready:        c.Ready,
+			RestartCount: c.RestartCount,
+			State:        describeContainerState(c.State),
+		}
+		if c.LastTerminationState.Terminated != nil {
+			containerDiagnostics.LastTermination = describeContainerState(c.LastTerminationState)
+		}
+		diagnostics.Containers = append(diagnostics.Containers, containerDiagnostics)
+	}
+	return diagnostics
+}
+
+func describeContainerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "running"
+	case state.Waiting != nil:
+		return fmt.Sprintf("waiting: %s %s", state.Waiting.Reason, state.Waiting.Message)
+	case state.Terminated != nil:
+		return fmt.Sprintf("terminated: %s (exit code %d) %s", state.Terminated.Reason,
+			state.Terminated.ExitCode, state.Terminated.Message)
+	default:
+		return "unknown"
+	}
+}
+
+func (s *server) getSandboxEvents(namespace string) ([]*cluster.SandboxEvent, error) {
+	// List the events from the API server rather than the cache, since the
+	// cache only contains the events about pods.
+	eventsList, err := s.kubeClient.CoreV1().Events(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	events := eventsList.Items
+	sort.Slice(events, func(i, j int) bool {
+		return eventTimestamp(&events[i]).Before(eventTimestamp(&events[j]))
+	})
+
+	var sandboxEvents []*cluster.SandboxEvent
+	for i := range events {
+		event := &events[i]
+		sandboxEvents = append(sandboxEvents, &cluster.SandboxEvent{
+			Time:    eventTimestamp(event).Unix(),
+			Object:  fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+			Type:    event.Type,
+			Reason:  event.Reason,
+			Message: event.Message,
+			Count:   event.Count,
+		})
+	}
+	return sandboxEvents, nil
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/cluster-controller/node"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/version"
+)
+
+const (
+	egressProxyName = "egress-proxy"
+	egressProxyPort = 3128
+)
+
+// defaultDeniedCIDRs can't be reached through the egress proxy, even if an
+// allowlisted domain resolves to them. They cover the private networks that
+// clusters usually run in, and the cloud metadata servers.
+var defaultDeniedCIDRs = []string{
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"169.254.0.0/16",
+	"100.64.0.0/10",
+}
+
+// egressRestrictedLabels select the pods that run user code. Blimp's own pods
+// in the sandbox, such as the DNS server, aren't restricted.
+var egressRestrictedLabels = map[string]string{
+	"services":  "blimp.customerPod",
+	"manifests": manifestLabel,
+	"docker":    kube.DockerOwnerLabel,
+}
+
+// egressPolicy controls what sandboxes can connect to outside of the
+// cluster. By default, sandboxes can connect anywhere.
+//
+// Operators restrict sandboxes by writing a policy to the YAML file at
+// $EGRESS_POLICY_PATH. For example:
+// ```
+// allowedDomains:
+// - github.com
+// - "*.npmjs.org"
+// allowedCIDRs:
+// - 203.0.113.0/24
+// ```
+// The policy of individual sandboxes can be overridden by setting
+// kube.EgressPolicyAnnotation on their namespace to the JSON version of the
+// policy, such as `{"allowAll": true}`.
+type egressPolicy struct {
+	// AllowAll disables the restrictions.
+	AllowAll bool `json:"allowAll,omitempty"`
+
+	// AllowedDomains can be reached through the egress proxy. Domains
+	// starting with "*." allow all of their subdomains.
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+
+	// AllowedCIDRs can be reached directly.
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+
+	// DeniedCIDRs are added to defaultDeniedCIDRs.
+	DeniedCIDRs []string `json:"deniedCIDRs,omitempty"`
+}
+
+func loadEgressPolicy() (egressPolicy, error) {
+	path, ok := os.LookupEnv("EGRESS_POLICY_PATH")
+	if !ok {
+		return egressPolicy{AllowAll: true}, nil
+	}
+
+	policyYAML, err := ioutil.ReadFile(path)
+	if err != nil {
+		return egressPolicy{}, errors.WithContext("read policy", err)
+	}
+
+	policyJSON, err := yaml.ToJSON(policyYAML)
+	if err != nil {
+		return egressPolicy{}, errors.WithContext("parse policy", err)
+	}
+	return parseEgressPolicy(string(policyJSON))
+}
+
+func parseEgressPolicy(policyJSON string) (egressPolicy, error) {
+	var policy egressPolicy
+	if err := json.Unmarshal([]byte(policyJSON), &policy); err != nil {
+		return egressPolicy{}, errors.WithContext("parse policy", err)
+	}
+
+	for _, cidr := range append(policy.AllowedCIDRs, policy.DeniedCIDRs...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return egressPolicy{}, errors.NewFriendlyError("Invalid CIDR %q in egress policy", cidr)
+		}
+	}
+	return policy, nil
+}
+
+// getEgressPolicy returns the policy for the namespace.
+func (s *server) getEgressPolicy(namespace string) (egressPolicy, error) {
+	ns, err := s.statusFetcher.namespaceLister.Get(namespace)
+	if err != nil {
+		// Namespaces that were just created may not be in the cache yet.
+		// They can't have an override.
+		if kerrors.IsNotFound(err) {
+			return s.egressPolicy, nil
+		}
+		return egressPolicy{}, errors.WithContext("get namespace", err)
+	}
+
+	policyJSON, ok := ns.Annotations[kube.EgressPolicyAnnotation]
+	if !ok {
+		return s.egressPolicy, nil
+	}
+	return parseEgressPolicy(policyJSON)
+}
+
+func (policy egressPolicy) deniedCIDRs() []string {
+	return append(append([]string{}, defaultDeniedCIDRs...), policy.DeniedCIDRs...)
+}
+
+// deployEgressPolicy restricts the sandbox's network traffic according to
+// the policy. It returns the address of the egress proxy, or an empty string
+// if the sandbox isn't restricted.
+func (s *server) deployEgressPolicy(ctx context.Context, user auth.User, placement affinity.Placement,
+	policy egressPolicy) (string, error) {
+	namespace := user.Namespace
+	policyClient := s.kubeClient.NetworkingV1().NetworkPolicies(namespace)
+	if policy.AllowAll {
+		// Skip the cleanup for sandboxes that were never restricted, which
+		// is the common case.
+		if _, err := s.statusFetcher.podLister.Pods(namespace).Get(egressProxyName); kerrors.IsNotFound(err) {
+			return "", nil
+		}
+
+		for _, np := range egressNetworkPolicies(namespace, policy) {
+			err := policyClient.Delete(np.Name, &metav1.DeleteOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return "", errors.WithContext("delete network policy", err)
+			}
+		}
+
+		err := kube.DeletePod(s.kubeClient, namespace, egressProxyName)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return "", errors.WithContext("delete egress proxy", err)
+		}
+		return "", nil
+	}
+
+	for _, np := range egressNetworkPolicies(namespace, policy) {
+		if err := s.deployNetworkPolicy(np); err != nil {
+			return "", errors.WithContext("deploy network policy", err)
+		}
+	}
+
+	opts := kube.DeployPodOptions{
+		Sanitizers: []kube.Sanitizer{kube.SanitizeIgnoreNodeAffinity},
+	}
+	if err := kube.DeployPod(s.kubeClient, egressProxyPod(user, placement, policy), opts); err != nil {
+		return "", errors.WithContext("deploy egress proxy", err)
+	}
+
+	proxyPod, err := s.getPod(ctx, namespace, egressProxyName, podIsReady)
+	if err != nil {
+		return "", errors.WithContext("get egress proxy's IP", err)
+	}
+	return fmt.Sprintf("http://%s:%d", proxyPod.Status.PodIP, egressProxyPort), nil
+}
+
+func (s *server) deployNetworkPolicy(np networkingv1.NetworkPolicy) error {
+	policyClient := s.kubeClient.NetworkingV1().NetworkPolicies(np.Namespace)
+	curr, err := policyClient.Get(np.Name, metav1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		_, err = policyClient.Create(&np)
+		return err
+	case err != nil:
+		return err
+	}
+
+	curr.Spec = np.Spec
+	_, err = policyClient.Update(curr)
+	return err
+}
+
+// egressNetworkPolicies returns the NetworkPolicies that block the sandbox's
+// pods from connecting outside of the sandbox, except to the allowlisted
+// CIDRs. Only the egress proxy can connect to the internet.
+func egressNetworkPolicies(namespace string, policy egressPolicy) []networkingv1.NetworkPolicy {
+	restricted := []networkingv1.NetworkPolicyPeer{
+		{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"namespace": namespace},
+			},
+		},
+
+		// The node controller forwards the user's SSH agent, and serves the
+		// emulated Docker socket.
+		{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"namespace": node.NodeControllerNamespace},
+			},
+		},
+	}
+	for _, cidr := range policy.AllowedCIDRs {
+		restricted = append(restricted, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+		})
+	}
+
+	var policies []networkingv1.NetworkPolicy
+	for name, label := range egressRestrictedLabels {
+		policies = append(policies, networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "egress-" + name,
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: label, Operator: metav1.LabelSelectorOpExists},
+					},
+				},
+				Egress:      []networkingv1.NetworkPolicyEgressRule{{To: restricted}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			},
+		})
+	}
+
+	// The proxy can reach anything other than the denied networks, and the
+	// cluster's DNS server.
+	var except []string
+	for _, cidr := range policy.deniedCIDRs() {
+		if !strings.Contains(cidr, ":") {
+			except = append(except, cidr)
+		}
+	}
+	udp := corev1.ProtocolUDP
+	tcp := corev1.ProtocolTCP
+	dnsPort := intstr.FromInt(53)
+	policies = append(policies, networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "egress-proxy",
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"service": egressProxyName},
+			},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{
+					To: []networkingv1.NetworkPolicyPeer{
+						{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0", Except: except}},
+					},
+				},
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: &udp, Port: &dnsPort},
+						{Protocol: &tcp, Port: &dnsPort},
+					},
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		},
+	})
+	return policies
+}
+
+func egressProxyPod(user auth.User, placement affinity.Placement, policy egressPolicy) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: user.Namespace,
+			Name:      egressProxyName,
+			Labels: map[string]string{
+				"service":                     egressProxyName,
+				affinity.ColocateNamespaceKey: user.Namespace,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    egressProxyName,
+				Image:   version.InitImage,
+				Command: []string{"/bin/blimp-egress-proxy"},
+				Env: []corev1.EnvVar{
+					{Name: "ALLOWED_DOMAINS", Value: strings.Join(policy.AllowedDomains, ",")},
+					{Name: "ALLOWED_CIDRS", Value: strings.Join(policy.AllowedCIDRs, ",")},
+					{Name: "DENIED_CIDRS", Value: strings.Join(policy.deniedCIDRs(), ",")},
+				},
+				Ports: []corev1.ContainerPort{{ContainerPort: egressProxyPort}},
+				ReadinessProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						TCPSocket: &corev1.TCPSocketAction{
+							Port: intstr.FromInt(egressProxyPort),
+						},
+					},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						"cpu":    resource.MustParse("500m"),
+						"memory": resource.MustParse("256Mi"),
+					},
+					Requests: corev1.ResourceList{
+						"cpu":    resource.MustParse("50m"),
+						"memory": resource.MustParse("50Mi"),
+					},
+				},
+			}},
+			Affinity:    affinity.ForUser(user, placement),
+			Tolerations: affinity.Tolerations(),
+		},
+	}
+	excludeFromMesh(&pod)
+	return pod
+}
+
+// injectEgressProxyEnv configures the service's container to send HTTP and
+// HTTPS traffic through the egress proxy. Traffic to the other services in
+// the sandbox goes directly. Variables that are already set, such as by the
+// Compose file, take precedence.
+func injectEgressProxyEnv(pod *corev1.Pod, proxyAddr string, sandboxHosts []string) {
+	noProxy := strings.Join(append([]string{"localhost", "127.0.0.1"}, sandboxHosts...), ",")
+	env := []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: proxyAddr},
+		{Name: "HTTPS_PROXY", Value: proxyAddr},
+		{Name: "NO_PROXY", Value: noProxy},
+		{Name: "http_proxy", Value: proxyAddr},
+		{Name: "https_proxy", Value: proxyAddr},
+		{Name: "no_proxy", Value: noProxy},
+	}
+
+	svc := pod.Labels["blimp.service"]
+	for i, c := range pod.Spec.Containers {
+		if c.Name != names.ToDNS1123(svc) {
+			continue
+		}
+
+		existing := map[string]struct{}{}
+		for _, env := range c.Env {
+			existing[env.Name] = struct{}{}
+		}
+
+		for _, v := range env {
+			if _, ok := existing[v.Name]; !ok {
+				pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, v)
+			}
+		}
+	}
+}
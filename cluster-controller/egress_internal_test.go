@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseEgressPolicy(t *testing.T) {
+	policy, err := parseEgressPolicy(`{"allowedDomains": ["github.com"], "allowedCIDRs": ["203.0.113.0/24"]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, egressPolicy{
+		AllowedDomains: []string{"github.com"},
+		AllowedCIDRs:   []string{"203.0.113.0/24"},
+	}, policy)
+
+	policy, err = parseEgressPolicy(`{"allowAll": true}`)
+	assert.NoError(t, err)
+	assert.True(t, policy.AllowAll)
+
+	_, err = parseEgressPolicy(`{"deniedCIDRs": ["10.0.0.1"]}`)
+	assert.Error(t, err)
+}
+
+func TestEgressNetworkPolicies(t *testing.T) {
+	policies := egressNetworkPolicies("sandbox", egressPolicy{
+		AllowedCIDRs: []string{"203.0.113.0/24"},
+		DeniedCIDRs:  []string{"198.51.100.0/24", "fd00::/8"},
+	})
+	assert.Len(t, policies, len(egressRestrictedLabels)+1)
+
+	for _, policy := range policies {
+		if policy.Name == "egress-proxy" {
+			// IPv6 ranges can't be excluded from the IPv4 block.
+			except := policy.Spec.Egress[0].To[0].IPBlock.Except
+			assert.Contains(t, except, "198.51.100.0/24")
+			assert.NotContains(t, except, "fd00::/8")
+			continue
+		}
+
+		to := policy.Spec.Egress[0].To
+		assert.Equal(t, "203.0.113.0/24", to[len(to)-1].IPBlock.CIDR)
+	}
+}
+
+func TestInjectEgressProxyEnv(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"blimp.service": "web"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "web",
+					Env:  []corev1.EnvVar{{Name: "NO_PROXY", Value: "custom"}},
+				},
+				{Name: "sidecar"},
+			},
+		},
+	}
+
+	injectEgressProxyEnv(&pod, "http://10.0.0.5:3128", []string{"web", "db"})
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "NO_PROXY", Value: "custom"},
+		{Name: "HTTP_PROXY", Value: "http://10.0.0.5:3128"},
+		{Name: "HTTPS_PROXY", Value: "http://10.0.0.5:3128"},
+		{Name: "http_proxy", Value: "http://10.0.0.5:3128"},
+		{Name: "https_proxy", Value: "http://10.0.0.5:3128"},
+		{Name: "no_proxy", Value: "localhost,127.0.0.1,web,db"},
+	}, pod.Spec.Containers[0].Env)
+	assert.Empty(t, pod.Spec.Containers[1].Env)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// lifecycleEvent is a LifecycleEvent along with an ID that uniquely
+// identifies it, so that each event is only sent once.
+type lifecycleEvent struct {
+	id string
+	*cluster.LifecycleEvent
+}
+
+func (s *server) WatchEvents(req *cluster.WatchEventsRequest, stream cluster.Manager_WatchEventsServer) error {
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return err
+	}
+
+	// Stop watching once the client disconnects, or stops responding to
+	// heartbeats.
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	trig := s.statusFetcher.WatchLifecycle(ctx, user.Namespace)
+
+	// The events are derived from the current state of the namespace, so the
+	// events that already happened are skipped.
+	events, err := s.getLifecycleEvents(user.Namespace, req.GetServices())
+	if err != nil {
+		return err
+	}
+	sent := map[string]struct{}{}
+	for _, event := range events {
+		sent[event.id] = struct{}{}
+	}
+
+	for {
+		select {
+		case <-trig:
+		case <-ctx.Done():
+			return nil
+		}
+
+		events, err := s.getLifecycleEvents(user.Namespace, req.GetServices())
+		if err != nil {
+			return err
+		}
+
+		// Only track the events that can still be derived so that the set
+		// doesn't grow for the lifetime of the stream.
+		current := map[string]struct{}{}
+		var newEvents []*cluster.LifecycleEvent
+		for _, event := range events {
+			current[event.id] = struct{}{}
+			if _, ok := sent[event.id]; !ok {
+				newEvents = append(newEvents, event.LifecycleEvent)
+			}
+		}
+		sent = current
+
+		if len(newEvents) == 0 {
+			continue
+		}
+
+		sort.SliceStable(newEvents, func(i, j int) bool {
+			return newEvents[i].Time < newEvents[j].Time
+		})
+		if err := stream.Send(&cluster.WatchEventsResponse{Events: newEvents}); err != nil {
+			return err
+		}
+	}
+}
+
+// getLifecycleEvents derives the events of the services in the namespace from
+// their pods, and the Kubernetes events about the pods. If `services` is
+// empty, the events of all services are returned.
+func (s *server) getLifecycleEvents(namespace string, services []string) ([]lifecycleEvent, error) {
+	pods, err := s.statusFetcher.podLister.Pods(namespace).List(
+		labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("list pods", err)
+	}
+
+	kubeEvents, err := s.statusFetcher.eventsLister.Events(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, errors.WithContext("list kubernetes events", err)
+	}
+
+	included := func(service string) bool {
+		if len(services) == 0 {
+			return true
+		}
+		for _, svc := range services {
+			if svc == service {
+				return true
+			}
+		}
+		return false
+	}
+
+	podServices := map[string]string{}
+	var events []lifecycleEvent
+	for _, pod := range pods {
+		service := pod.Labels["blimp.service"]
+		if !included(service) {
+			continue
+		}
+		podServices[pod.Name] = service
+		events = append(events, getPodLifecycleEvents(pod, service)...)
+	}
+
+	for _, kubeEvent := range kubeEvents {
+		service, ok := podServices[kubeEvent.InvolvedObject.Name]
+		if !ok || kubeEvent.InvolvedObject.Kind != "Pod" ||
+			kubeEvent.InvolvedObject.FieldPath != fmt.Sprintf("spec.containers{%s}", names.ToDNS1123(service)) {
+			continue
+		}
+
+		var eventType cluster.LifecycleEvent_Type
+		switch kubeEvent.Reason {
+		case "Pulling":
+			eventType = cluster.LifecycleEvent_IMAGE_PULL_STARTED
+		case "Pulled":
+			eventType = cluster.LifecycleEvent_IMAGE_PULL_FINISHED
+		default:
+			continue
+		}
+
+		// Repeated events are aggregated into a single object by
+		// incrementing its count.
+		events = append(events, lifecycleEvent{
+			id: fmt.Sprintf("event/%s/%d", kubeEvent.UID, kubeEvent.Count),
+			LifecycleEvent: &cluster.LifecycleEvent{
+				Type:    eventType,
+				Service: service,
+				Time:    eventTimestamp(kubeEvent).Unix(),
+				Message: kubeEvent.Message,
+			},
+		})
+	}
+	return events, nil
+}
+
+func getPodLifecycleEvents(pod *corev1.Pod, service string) []lifecycleEvent {
+	var events []lifecycleEvent
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if cs.Name != kube.ContainerNameWaitInitialSync ||
+			cs.State.Terminated == nil || cs.State.Terminated.ExitCode != 0 {
+			continue
+		}
+
+		events = append(events, lifecycleEvent{
+			id: fmt.Sprintf("sync/%s", pod.UID),
+			LifecycleEvent: &cluster.LifecycleEvent{
+				Type:    cluster.LifecycleEvent_SYNC_COMPLETED,
+				Service: service,
+				Time:    cs.State.Terminated.FinishedAt.Unix(),
+				Message: "Finished syncing bind volumes",
+			},
+		})
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != names.ToDNS1123(service) {
+			continue
+		}
+
+		if running := cs.State.Running; running != nil {
+			events = append(events, lifecycleEvent{
+				id: fmt.Sprintf("started/%s/%d", pod.UID, cs.RestartCount),
+				LifecycleEvent: &cluster.LifecycleEvent{
+					Type:    cluster.LifecycleEvent_CONTAINER_STARTED,
+					Service: service,
+					Time:    running.StartedAt.Unix(),
+					Message: "Started container",
+				},
+			})
+		}
+
+		for _, terminated := range []*corev1.ContainerStateTerminated{
+			cs.State.Terminated, cs.LastTerminationState.Terminated} {
+			if terminated == nil {
+				continue
+			}
+
+			event := &cluster.LifecycleEvent{
+				Service:  service,
+				Time:     terminated.FinishedAt.Unix(),
+				ExitCode: terminated.ExitCode,
+			}
+			switch {
+			case terminated.Reason == "OOMKilled":
+				event.Type = cluster.LifecycleEvent_CONTAINER_OOM_KILLED
+				event.Message = "Container ran out of memory"
+			case terminated.ExitCode == 0:
+				event.Type = cluster.LifecycleEvent_CONTAINER_EXITED
+				event.Message = "Container exited"
+			default:
+				event.Type = cluster.LifecycleEvent_CONTAINER_CRASHED
+				event.Message = fmt.Sprintf("Container crashed with exit code %d", terminated.ExitCode)
+			}
+
+			id := terminated.ContainerID
+			if id == "" {
+				id = fmt.Sprintf("%s/%s", pod.UID, terminated.FinishedAt)
+			}
+			events = append(events, lifecycleEvent{
+				id:             "terminated/" + id,
+				LifecycleEvent: event,
+			})
+		}
+	}
+	return events
+}
+
+// eventTimestamp returns when the event last occurred. Events that were
+// created with the events.k8s.io API only set EventTime, so the creation time
+// is used as a last resort.
+func eventTimestamp(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func TestGetPodLifecycleEvents(t *testing.T) {
+	startedAt := metav1.NewTime(time.Unix(300, 0))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", UID: "uid"},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: kube.ContainerNameWaitInitialSync,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							FinishedAt: metav1.NewTime(time.Unix(100, 0)),
+						},
+					},
+				},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:         "web",
+					RestartCount: 1,
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{StartedAt: startedAt},
+					},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ContainerID: "docker://old",
+							ExitCode:    137,
+							Reason:      "OOMKilled",
+							FinishedAt:  metav1.NewTime(time.Unix(200, 0)),
+						},
+					},
+				},
+				// Sidecars are ignored.
+				{
+					Name: chaosContainerName,
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{StartedAt: startedAt},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []lifecycleEvent{
+		{
+			id: "sync/uid",
+			LifecycleEvent: &cluster.LifecycleEvent{
+				Type:    cluster.LifecycleEvent_SYNC_COMPLETED,
+				Service: "web",
+				Time:    100,
+				Message: "Finished syncing bind volumes",
+			},
+		},
+		{
+			id: "started/uid/1",
+			LifecycleEvent: &cluster.LifecycleEvent{
+				Type:    cluster.LifecycleEvent_CONTAINER_STARTED,
+				Service: "web",
+				Time:    300,
+				Message: "Started container",
+			},
+		},
+		{
+			id: "terminated/docker://old",
+			LifecycleEvent: &cluster.LifecycleEvent{
+				Type:     cluster.LifecycleEvent_CONTAINER_OOM_KILLED,
+				Service:  "web",
+				Time:     200,
+				Message:  "Container ran out of memory",
+				ExitCode: 137,
+			},
+		},
+	}, getPodLifecycleEvents(pod, "web"))
+}
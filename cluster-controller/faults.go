@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/faults"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+	"github.com/kelda/blimp/pkg/version"
+)
+
+const (
+	// faultsMountPath is where the chaos agent reads the fault rules from.
+	faultsMountPath = "/etc/blimp/faults"
+
+	// chaosContainerName is the name of the container that enforces the
+	// fault rules for a service.
+	chaosContainerName = "blimp-chaos"
+)
+
+func (s *server) GetFaults(ctx context.Context, req *cluster.GetFaultsRequest) (*cluster.GetFaultsResponse, error) {
+	log.Info("Start GetFaults")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.GetFaultsResponse{}, err
+	}
+
+	composeRules, runtimeRules, err := s.getFaultRules(user.Namespace)
+	if err != nil {
+		return &cluster.GetFaultsResponse{}, errors.WithContext("get rules", err)
+	}
+
+	return &cluster.GetFaultsResponse{
+		ComposeRules: faults.ToProtobuf(composeRules),
+		RuntimeRules: faults.ToProtobuf(runtimeRules),
+	}, nil
+}
+
+func (s *server) SetFaults(ctx context.Context, req *cluster.SetFaultsRequest) (*cluster.SetFaultsResponse, error) {
+	log.Info("Start SetFaults")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.SetFaultsResponse{}, err
+	}
+
+	services, err := s.statusFetcher.podLister.Pods(user.Namespace).List(
+		labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return &cluster.SetFaultsResponse{}, errors.WithContext("list services", err)
+	}
+
+	servicePods := map[string]*corev1.Pod{}
+	for _, pod := range services {
+		servicePods[pod.Labels["blimp.service"]] = pod
+	}
+
+	runtimeRules := faults.FromProtobuf(req.GetRuntimeRules())
+	for _, rule := range runtimeRules {
+		if err := rule.Validate(); err != nil {
+			return &cluster.SetFaultsResponse{}, err
+		}
+
+		for _, svc := range []string{rule.From, rule.To} {
+			if _, ok := servicePods[svc]; !ok {
+				return &cluster.SetFaultsResponse{}, errors.NewFriendlyError(
+					"Service %q isn't running. Make sure that it's defined in "+
+						"your Docker Compose file, and that `blimp up` is running.", svc)
+			}
+		}
+	}
+
+	composeRules, _, err := s.getFaultRules(user.Namespace)
+	if err != nil {
+		return &cluster.SetFaultsResponse{}, errors.WithContext("get rules", err)
+	}
+
+	allRules := faults.Merge(composeRules, runtimeRules)
+	if err := validateFaultRuleCount(allRules); err != nil {
+		return &cluster.SetFaultsResponse{}, err
+	}
+
+	if err := s.updateFaultRules(user.Namespace, faults.RuntimeRulesKey, runtimeRules); err != nil {
+		return &cluster.SetFaultsResponse{}, errors.WithContext("update rules", err)
+	}
+	s.recordActivity(user.Namespace)
+
+	// The chaos agent is only deployed alongside services that have fault
+	// rules, so services that didn't previously have any need to be restarted
+	// with the agent.
+	for svc := range faults.Sources(allRules) {
+		pod := servicePods[svc]
+		if hasChaosAgent(pod) {
+			continue
+		}
+
+		newPod := copyCustomerPod(pod)
+		addChaosAgent(&newPod, svc)
+
+		err := kube.DeployPod(s.kubeClient, newPod, kube.DeployPodOptions{ForceRestart: true})
+		if err != nil {
+			return &cluster.SetFaultsResponse{}, errors.WithContext(
+				fmt.Sprintf("restart %s with chaos agent", svc), err)
+		}
+	}
+
+	return &cluster.SetFaultsResponse{}, nil
+}
+
+// deployFaultRules updates the sandbox's fault rules to match the Compose
+// file, and returns the services that need a chaos agent. The rules defined
+// with `blimp faults` are left untouched.
+func (s *server) deployFaultRules(namespace, project string, services []composeTypes.ServiceConfig) (
+	map[string]struct{}, error) {
+
+	composeRules, err := composeFaultRules(services)
+	if err != nil {
+		return nil, err
+	}
+
+	prevComposeRules, runtimeRules, err := s.getFaultRules(namespace)
+	if err != nil {
+		return nil, errors.WithContext("get runtime rules", err)
+	}
+
+	// Keep the rules defined by the other projects in the sandbox.
+	pods, err := s.statusFetcher.podLister.Pods(namespace).List(
+		labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("list services", err)
+	}
+	serviceProjects := map[string]string{}
+	for _, pod := range pods {
+		serviceProjects[pod.Labels["blimp.service"]] = pod.Labels[projectLabel]
+	}
+	for _, rule := range prevComposeRules {
+		if otherProject, ok := serviceProjects[rule.From]; ok && otherProject != project {
+			composeRules = append(composeRules, rule)
+		}
+	}
+
+	// Runtime rules may refer to services that were removed from the Compose
+	// file. They're harmless since the chaos agent ignores services that
+	// don't resolve.
+	allRules := faults.Merge(composeRules, runtimeRules)
+	if err := validateFaultRuleCount(allRules); err != nil {
+		return nil, err
+	}
+
+	if err := s.updateFaultRules(namespace, faults.ComposeRulesKey, composeRules); err != nil {
+		return nil, errors.WithContext("update compose rules", err)
+	}
+	return faults.Sources(allRules), nil
+}
+
+// composeFaultRules parses the fault rules defined in each service's x-blimp
+// extension.
+func composeFaultRules(services []composeTypes.ServiceConfig) ([]faults.Rule, error) {
+	serviceNames := map[string]struct{}{}
+	for _, svc := range services {
+		serviceNames[svc.Name] = struct{}{}
+	}
+
+	var rules []faults.Rule
+	for _, svc := range services {
+		ext, err := compose.GetServiceExtension(svc)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fault := range ext.Faults {
+			rule := faults.Rule{From: svc.Name, To: fault.To}
+			if fault.Drop != "" {
+				rule.DropPercent, err = faults.ParsePercent(string(fault.Drop))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if fault.Delay != "" {
+				rule.Delay, err = time.ParseDuration(fault.Delay)
+				if err != nil {
+					return nil, errors.NewFriendlyError(
+						"Invalid delay %q for fault from %s to %s. Delays should be formatted like \"300ms\".",
+						fault.Delay, svc.Name, fault.To)
+				}
+			}
+
+			if err := rule.Validate(); err != nil {
+				return nil, err
+			}
+
+			if _, ok := serviceNames[rule.To]; !ok {
+				return nil, errors.NewFriendlyError(
+					"Service %s has a fault rule for undefined service %s.", svc.Name, rule.To)
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func validateFaultRuleCount(rules []faults.Rule) error {
+	count := map[string]int{}
+	for _, rule := range rules {
+		count[rule.From]++
+		if count[rule.From] > faults.MaxRulesPerService {
+			return errors.NewFriendlyError(
+				"Service %s has more than the maximum of %d fault rules.",
+				rule.From, faults.MaxRulesPerService)
+		}
+	}
+	return nil
+}
+
+func (s *server) getFaultRules(namespace string) (composeRules, runtimeRules []faults.Rule, err error) {
+	configMap, err := s.kubeClient.CoreV1().ConfigMaps(namespace).Get(faults.ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, errors.WithContext("get configmap", err)
+	}
+
+	composeRules, err = faults.Unmarshal(configMap.Data[faults.ComposeRulesKey])
+	if err != nil {
+		return nil, nil, errors.WithContext("parse compose rules", err)
+	}
+
+	runtimeRules, err = faults.Unmarshal(configMap.Data[faults.RuntimeRulesKey])
+	if err != nil {
+		return nil, nil, errors.WithContext("parse runtime rules", err)
+	}
+	return composeRules, runtimeRules, nil
+}
+
+// updateFaultRules replaces the rules stored in the given key of the faults
+// ConfigMap.
+func (s *server) updateFaultRules(namespace, key string, rules []faults.Rule) error {
+	rulesJSON, err := faults.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	configMapsClient := s.kubeClient.CoreV1().ConfigMaps(namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := configMapsClient.Get(faults.ConfigMapName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			_, err = configMapsClient.Create(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      faults.ConfigMapName,
+					Namespace: namespace,
+				},
+				Data: map[string]string{key: rulesJSON},
+			})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[key] = rulesJSON
+		_, err = configMapsClient.Update(configMap)
+		return err
+	})
+}
+
+func hasChaosAgent(pod *corev1.Pod) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == chaosContainerName {
+			return true
+		}
+	}
+	return false
+}
+
+// addChaosAgent adds a sidecar that enforces the fault rules for traffic sent
+// by the pod. Containers in a pod share a network namespace, so the agent
+// can shape the service's traffic without any changes to the service
+// container.
+func addChaosAgent(pod *corev1.Pod, svc string) {
+	volumeName := "blimp-faults"
+	optional := true
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: faults.ConfigMapName,
+				},
+				Optional: &optional,
+			},
+		},
+	})
+
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+		Name:  chaosContainerName,
+		Image: version.ChaosImage,
+		Env: []corev1.EnvVar{
+			{Name: "BLIMP_SERVICE", Value: svc},
+			{Name: "BLIMP_FAULTS_DIR", Value: faultsMountPath},
+		},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      volumeName,
+			MountPath: faultsMountPath,
+			ReadOnly:  true,
+		}},
+		SecurityContext: &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{
+				Add: []corev1.Capability{"NET_ADMIN"},
+			},
+		},
+	})
+}
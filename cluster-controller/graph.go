@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func (s *server) GetDependencyGraph(ctx context.Context, req *cluster.GetDependencyGraphRequest) (
+	*cluster.GetDependencyGraphResponse, error) {
+	log.Info("Start GetDependencyGraph")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.GetDependencyGraphResponse{}, err
+	}
+
+	services, err := s.statusFetcher.GetDependencyGraph(user.Namespace)
+	if err != nil {
+		return &cluster.GetDependencyGraphResponse{}, err
+	}
+	return &cluster.GetDependencyGraphResponse{Services: services}, nil
+}
+
+// GetDependencyGraph returns each service in the namespace along with its
+// status and the services it depends on. The dependencies are read from the
+// annotations on the customer pods, so they reflect what was most recently
+// deployed.
+func (sf *statusFetcher) GetDependencyGraph(namespace string) ([]*cluster.ServiceNode, error) {
+	pods, err := sf.podLister.
+		Pods(namespace).
+		List(labels.Set(
+			map[string]string{"blimp.customerPod": "true"},
+		).AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("get services", err)
+	}
+
+	var services []*cluster.ServiceNode
+	for _, pod := range pods {
+		if pod.GetName() == "reservation" {
+			continue
+		}
+
+		status := sf.getServiceStatus(pod)
+		node := &cluster.ServiceNode{
+			Name:   pod.GetLabels()["blimp.service"],
+			Status: &status,
+		}
+		if dependsOn, ok := pod.Annotations[metadata.DependsOnKey]; ok {
+			node.DependsOn = metadata.ParseDependsOn(dependsOn)
+			sort.Strings(node.DependsOn)
+		}
+		services = append(services, node)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/cluster-controller/node"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/ports"
+)
+
+// dialTunnelTarget connects WebSocket tunnels from CLIs that can't make gRPC
+// connections directly. The CLI sets the target to the address that it wants
+// to connect to. Tunnels to Node Controllers are forwarded to the Node
+// Controller, and all other tunnels are assumed to be for the manager.
+// Forwarding arbitrary addresses would let anyone use the manager as a proxy.
+//
+// The gRPC connection is still encrypted end to end, since the tunnel only
+// forwards the bytes of the TLS connection.
+func (s *server) dialTunnelTarget(target string) (net.Conn, error) {
+	addr := fmt.Sprintf("127.0.0.1:%d", ports.ClusterManagerGRPCInternalPort)
+	if target != "" {
+		isNode, err := node.IsControllerAddress(s.kubeClient, target)
+		if err != nil {
+			return nil, errors.WithContext("check target", err)
+		}
+
+		if isNode {
+			addr = target
+		}
+	}
+
+	log.WithField("target", target).WithField("address", addr).Debug("Opening gRPC tunnel")
+	return net.DialTimeout("tcp", addr, 10*time.Second)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// defaultHeartbeatTimeout is how long clients can go without responding to
+// heartbeats before their streams are torn down. Streams such as WatchStatus
+// and tunnels otherwise stay open forever when a CLI is killed without
+// closing its connection, such as when the user's laptop goes to sleep.
+const defaultHeartbeatTimeout = 90 * time.Second
+
+// minClientPingInterval is the most frequently that clients may send
+// keepalive pings. The CLI sends them every 30 seconds.
+const minClientPingInterval = 20 * time.Second
+
+// getHeartbeatTimeout returns the heartbeat timeout configured by
+// $CLIENT_HEARTBEAT_TIMEOUT.
+func getHeartbeatTimeout() time.Duration {
+	timeoutStr, ok := os.LookupEnv("CLIENT_HEARTBEAT_TIMEOUT")
+	if !ok {
+		return defaultHeartbeatTimeout
+	}
+
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil || timeout <= 0 {
+		log.WithError(err).WithField("CLIENT_HEARTBEAT_TIMEOUT", timeoutStr).
+			Warn("Couldn't parse $CLIENT_HEARTBEAT_TIMEOUT")
+		return defaultHeartbeatTimeout
+	}
+	return timeout
+}
+
+// heartbeatServerOptions configures the gRPC server to ping clients that
+// have been idle for a third of the timeout, and to close the connections of
+// clients that don't respond. Closing the connection cancels the contexts of
+// its streams, which tears down their watches and tunnels.
+func heartbeatServerOptions(timeout time.Duration) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    timeout / 3,
+			Timeout: timeout - timeout/3,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             minClientPingInterval,
+			PermitWithoutStream: true,
+		}),
+	}
+}
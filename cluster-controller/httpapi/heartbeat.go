@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// heartbeat pings the client periodically, and makes reads from the
+// connection fail if the client doesn't respond for longer than the timeout.
+// This lets handlers clean up after clients that disappear without closing
+// the connection, such as when the client's machine goes to sleep. WebSocket
+// clients respond to pings automatically. A zero timeout disables heartbeats.
+// The returned function stops the heartbeat.
+func heartbeat(conn *websocket.Conn, timeout time.Duration) (stop func()) {
+	if timeout == 0 {
+		return func() {}
+	}
+
+	extendDeadline := func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(timeout))
+	}
+	_ = extendDeadline("")
+	conn.SetPongHandler(extendDeadline)
+
+	stopped := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(timeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				// WriteControl is safe to call concurrently with the
+				// handler's writes.
+				err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(timeout/3))
+				if err != nil {
+					return
+				}
+			case <-stopped:
+				return
+			}
+		}
+	}()
+	return func() { close(stopped) }
+}
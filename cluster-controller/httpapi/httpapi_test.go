@@ -3,15 +3,23 @@ package httpapi
 import (
 	"bytes"
 	"context"
+	"io"
 	"io/ioutil"
+	"net"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/kelda/blimp/pkg/errors"
 	"github.com/kelda/blimp/pkg/proto/cluster"
+	"github.com/kelda/blimp/pkg/wsconn"
 )
 
 func TestHTTPAPI(t *testing.T) {
@@ -88,3 +96,91 @@ func TestHTTPAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestTunnel(t *testing.T) {
+	// Start a TCP server that echoes back whatever it receives.
+	echoListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer echoListener.Close()
+	go func() {
+		for {
+			conn, err := echoListener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	server, err := NewServer("", map[string]Handler{
+		"/api/grpc-tunnel": TunnelHandler{
+			Dial: func(target string) (net.Conn, error) {
+				if target != "echo" {
+					return nil, errors.New("unknown target")
+				}
+				return net.Dial("tcp", echoListener.Addr().String())
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(server.Handler)
+	defer httpServer.Close()
+	tunnelURL := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/api/grpc-tunnel?target="
+
+	ws, _, err := websocket.DefaultDialer.Dial(tunnelURL+"echo", nil)
+	require.NoError(t, err)
+	conn := wsconn.New(ws)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("hello "))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("world"))
+	require.NoError(t, err)
+
+	resp := make([]byte, len("hello world"))
+	_, err = io.ReadFull(conn, resp)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(resp))
+
+	_, httpResp, err := websocket.DefaultDialer.Dial(tunnelURL+"other", nil)
+	assert.Error(t, err)
+	require.NotNil(t, httpResp)
+	assert.Equal(t, http.StatusBadGateway, httpResp.StatusCode)
+}
+
+func TestStreamHeartbeat(t *testing.T) {
+	rpcDone := make(chan struct{})
+	server, err := NewServer("", map[string]Handler{
+		"/api/watch-status": StreamHandler{
+			RequestType: &cluster.GetStatusRequest{},
+			RPC: func(_ proto.Message, wss WebSocketStream) error {
+				<-wss.Context().Done()
+				close(rpcDone)
+				return nil
+			},
+			HeartbeatTimeout: 300 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(server.Handler)
+	defer httpServer.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial(
+		"ws"+strings.TrimPrefix(httpServer.URL, "http")+"/api/watch-status", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+	require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte("{}")))
+
+	// The client never reads from the connection, so it never responds to
+	// the server's pings.
+	select {
+	case <-rpcDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream wasn't cancelled after the client stopped responding to heartbeats")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
@@ -18,6 +19,10 @@ import (
 type StreamHandler struct {
 	RequestType proto.Message
 	RPC         StreamRPC
+
+	// HeartbeatTimeout is how long the client can go without responding to
+	// pings before the stream is cancelled. Zero disables heartbeats.
+	HeartbeatTimeout time.Duration
 }
 
 type StreamRPC func(proto.Message, WebSocketStream) error
@@ -76,6 +81,9 @@ func (handler StreamHandler) Handler() (http.HandlerFunc, error) {
 			CheckOrigin: func(r *http.Request) bool {
 				return true
 			},
+			// Compress the JSON messages for clients that support it, since
+			// the statuses of large sandboxes compress well.
+			EnableCompression: true,
 		}
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
@@ -83,6 +91,7 @@ func (handler StreamHandler) Handler() (http.HandlerFunc, error) {
 			return
 		}
 		defer conn.Close()
+		defer heartbeat(conn, handler.HeartbeatTimeout)()
 
 		// The client's first message should always be the protobuf request.
 		_, reqJSON, err := conn.ReadMessage()
@@ -99,8 +108,9 @@ func (handler StreamHandler) Handler() (http.HandlerFunc, error) {
 				_, _, err := conn.ReadMessage()
 				if err != nil {
 					// err could be non-nil if the client gracefully closes the
-					// connection with a Close message, or if the connection
-					// breaks. Either way, we should stop sending messages
+					// connection with a Close message, if the connection
+					// breaks, or if the client stops responding to
+					// heartbeats. Either way, we should stop sending messages
 					// back.
 					cancelForward()
 					return
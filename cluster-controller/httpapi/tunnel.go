@@ -0,0 +1,68 @@
+package httpapi
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/wsconn"
+)
+
+// TunnelHandler forwards the data from WebSocket connections to TCP
+// connections. It lets clients that can only make HTTP requests, such as
+// clients behind restrictive firewalls, connect to gRPC servers.
+type TunnelHandler struct {
+	// Dial connects to the server for the target in the `target` query
+	// parameter. It's responsible for rejecting targets that clients
+	// shouldn't be able to connect to.
+	Dial func(target string) (net.Conn, error)
+
+	// HeartbeatTimeout is how long the client can go without responding to
+	// pings before the tunnel is closed. Zero disables heartbeats.
+	HeartbeatTimeout time.Duration
+}
+
+func (handler TunnelHandler) Handler() (http.HandlerFunc, error) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("target")
+		upstream, err := handler.Dial(target)
+		if err != nil {
+			log.WithError(err).WithField("target", target).Warn("Failed to dial tunnel target")
+			http.Error(w, "failed to connect to target", http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+
+		upgrader := &websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true
+			},
+		}
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.WithError(err).Warn("Failed to upgrade connection")
+			return
+		}
+
+		conn := wsconn.New(ws)
+		defer conn.Close()
+		defer heartbeat(ws, handler.HeartbeatTimeout)()
+
+		// Copy until either side closes the connection. The deferred Closes
+		// then unblock the other copy.
+		done := make(chan struct{}, 2)
+		go func() {
+			_, _ = io.Copy(upstream, conn)
+			done <- struct{}{}
+		}()
+		go func() {
+			_, _ = io.Copy(conn, upstream)
+			done <- struct{}{}
+		}()
+		<-done
+	}, nil
+}
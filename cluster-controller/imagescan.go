@@ -0,0 +1,544 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// imageScansConfigMap stores the most recent scan of each service's
+	// image, keyed by service name.
+	imageScansConfigMap = "blimp-image-scans"
+
+	// imageScanMaxAge is how long scan results are reused for. Tags can be
+	// moved to new images, and new vulnerabilities are published for old
+	// images, so images are rescanned after this long even if their name
+	// hasn't changed.
+	imageScanMaxAge = 24 * time.Hour
+
+	// imageScanTimeout bounds how long a single image scan can take. Images
+	// that can't be scanned in time are deployed anyway.
+	imageScanTimeout = 5 * time.Minute
+
+	// maxParallelImageScans is the number of images in a sandbox that are
+	// scanned at the same time.
+	maxParallelImageScans = 4
+
+	// maxRecordedVulnerabilities is the number of vulnerabilities stored for
+	// each image, so that images with many vulnerabilities don't overflow
+	// the ConfigMap. The most severe vulnerabilities are kept.
+	maxRecordedVulnerabilities = 100
+
+	// warnSeverity is the severity at or above which vulnerabilities are
+	// reported as warnings in the sandbox's status.
+	warnSeverity = "HIGH"
+)
+
+// severities are the vulnerability severities reported by Trivy, from least
+// to most severe.
+var severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// severityRank returns -1 for unknown severities.
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// imageScanner scans images for known vulnerabilities by running the Trivy
+// CLI against a Trivy server. The server holds the vulnerability database, so
+// the manager doesn't have to download it.
+type imageScanner struct {
+	trivyServer string
+
+	// blockSeverity is the severity at or above which vulnerabilities block
+	// deployments. If empty, vulnerabilities are only reported.
+	blockSeverity string
+}
+
+// loadImageScanner returns nil if image scanning is disabled.
+func loadImageScanner() (*imageScanner, error) {
+	trivyServer := os.Getenv("IMAGE_SCAN_TRIVY_SERVER")
+	if trivyServer == "" {
+		return nil, nil
+	}
+
+	blockSeverity := strings.ToUpper(os.Getenv("IMAGE_SCAN_BLOCK_SEVERITY"))
+	if blockSeverity != "" && severityRank(blockSeverity) < 0 {
+		return nil, errors.New("unknown severity %q. It must be one of %s",
+			blockSeverity, strings.Join(severities, ", "))
+	}
+	return &imageScanner{trivyServer: trivyServer, blockSeverity: blockSeverity}, nil
+}
+
+// imageScan is the result of scanning a service's image.
+type imageScan struct {
+	Service         string          `json:"service"`
+	Project         string          `json:"project,omitempty"`
+	Image           string          `json:"image"`
+	ScannedAt       time.Time       `json:"scannedAt"`
+	Error           string          `json:"error,omitempty"`
+	Vulnerabilities []vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+type vulnerability struct {
+	ID               string `json:"id"`
+	Package          string `json:"package"`
+	InstalledVersion string `json:"installedVersion"`
+	FixedVersion     string `json:"fixedVersion,omitempty"`
+	Severity         string `json:"severity"`
+	Title            string `json:"title,omitempty"`
+}
+
+func (scan imageScan) toProtobuf() *cluster.ImageScan {
+	pb := &cluster.ImageScan{
+		Service:   scan.Service,
+		Image:     scan.Image,
+		ScannedAt: scan.ScannedAt.Unix(),
+		Error:     scan.Error,
+	}
+	for _, v := range scan.Vulnerabilities {
+		pb.Vulnerabilities = append(pb.Vulnerabilities, &cluster.Vulnerability{
+			Id:               v.ID,
+			Package:          v.Package,
+			InstalledVersion: v.InstalledVersion,
+			FixedVersion:     v.FixedVersion,
+			Severity:         v.Severity,
+			Title:            v.Title,
+		})
+	}
+	return pb
+}
+
+// countAtLeast returns the number of vulnerabilities at or above the given
+// severity.
+func (scan imageScan) countAtLeast(severity string) int {
+	var count int
+	for _, v := range scan.Vulnerabilities {
+		if severityRank(v.Severity) >= severityRank(severity) {
+			count++
+		}
+	}
+	return count
+}
+
+func (scan imageScan) isStale(image string, now time.Time) bool {
+	return scan.Image != image || scan.Error != "" || now.Sub(scan.ScannedAt) > imageScanMaxAge
+}
+
+func (s *server) GetImageScans(ctx context.Context, req *cluster.GetImageScansRequest) (
+	*cluster.GetImageScansResponse, error) {
+	log.Info("Start GetImageScans")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.GetImageScansResponse{}, err
+	}
+
+	if s.imageScanner == nil {
+		return &cluster.GetImageScansResponse{}, errors.NewFriendlyError(
+			"Image scanning isn't enabled on this Blimp cluster.")
+	}
+
+	scans, err := s.getImageScans(user.Namespace)
+	if err != nil {
+		return &cluster.GetImageScansResponse{}, err
+	}
+
+	var scansPB []*cluster.ImageScan
+	for _, scan := range scans {
+		scansPB = append(scansPB, scan.toProtobuf())
+	}
+	return &cluster.GetImageScansResponse{
+		Scans:         scansPB,
+		BlockSeverity: s.imageScanner.blockSeverity,
+	}, nil
+}
+
+// scanImages scans the images used by the project's services that haven't
+// been scanned recently, and records the results in the sandbox. It returns
+// a friendly error if any image has vulnerabilities at or above the block
+// severity.
+// Images that fail to scan are allowed, since the scanner being unavailable
+// shouldn't prevent users from working.
+func (s *server) scanImages(ctx context.Context, namespace, project string,
+	services []composeTypes.ServiceConfig, builtImages map[string]string) error {
+	images := map[string]string{}
+	for _, svc := range services {
+		if image, ok := builtImages[svc.Name]; ok {
+			images[svc.Name] = image
+		} else if svc.Image != "" {
+			images[svc.Name] = svc.Image
+		}
+	}
+
+	prevScans, err := s.getImageScans(namespace)
+	if err != nil {
+		return errors.WithContext("get previous scans", err)
+	}
+
+	now := time.Now()
+	var toScan []string
+	for svc, image := range images {
+		if prev, ok := findImageScan(prevScans, svc); !ok || prev.isStale(image, now) {
+			toScan = append(toScan, svc)
+		}
+	}
+
+	// Scans for services that were removed from the project are dropped.
+	isRemoved := func(scan imageScan) bool {
+		_, ok := images[scan.Service]
+		return !ok && scan.Project == project
+	}
+	var hasRemoved bool
+	for _, scan := range prevScans {
+		hasRemoved = hasRemoved || isRemoved(scan)
+	}
+
+	// Avoid writing to the sandbox on every deploy if nothing changed.
+	if len(toScan) == 0 && !hasRemoved {
+		return s.checkImageScans(prevScans, images)
+	}
+
+	dockerConfigDir, err := s.writeDockerConfig(namespace)
+	if err != nil {
+		return errors.WithContext("write registry credentials", err)
+	}
+	defer os.RemoveAll(dockerConfigDir)
+
+	newScans := make([]imageScan, len(toScan))
+	sem := make(chan struct{}, maxParallelImageScans)
+	var wg sync.WaitGroup
+	for i, svc := range toScan {
+		i, svc := i, svc
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			scan := imageScan{
+				Service:   svc,
+				Project:   project,
+				Image:     images[svc],
+				ScannedAt: time.Now(),
+			}
+			vulns, err := s.imageScanner.scan(ctx, images[svc], dockerConfigDir)
+			if err != nil {
+				log.WithError(err).WithField("namespace", namespace).
+					WithField("image", images[svc]).Warn("Failed to scan image")
+				scan.Error = err.Error()
+			}
+			scan.Vulnerabilities = vulns
+			newScans[i] = scan
+		}()
+	}
+	wg.Wait()
+
+	var scans []imageScan
+	err = s.updateImageScans(namespace, func(curr []imageScan) []imageScan {
+		scans = nil
+		for _, scan := range curr {
+			if isRemoved(scan) {
+				continue
+			}
+			if _, ok := findImageScan(newScans, scan.Service); !ok {
+				scans = append(scans, scan)
+			}
+		}
+		scans = append(scans, newScans...)
+		return scans
+	})
+	if err != nil {
+		return errors.WithContext("save scans", err)
+	}
+	return s.checkImageScans(scans, images)
+}
+
+// checkImageScans returns a friendly error if any of the images have
+// vulnerabilities at or above the block severity. `images` maps the services
+// being deployed to their images.
+func (s *server) checkImageScans(scans []imageScan, images map[string]string) error {
+	if s.imageScanner.blockSeverity == "" {
+		return nil
+	}
+
+	var blocked []string
+	for _, scan := range scans {
+		if _, ok := images[scan.Service]; !ok {
+			continue
+		}
+
+		if count := scan.countAtLeast(s.imageScanner.blockSeverity); count != 0 {
+			blocked = append(blocked, fmt.Sprintf("- %s (%s): %d vulnerabilities", scan.Service, scan.Image, count))
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+
+	sort.Strings(blocked)
+	return errors.NewFriendlyError(
+		"The following services use images with vulnerabilities of severity %s or higher:\n%s\n"+
+			"Update the images, then try again. Run `blimp scan` for details.",
+		s.imageScanner.blockSeverity, strings.Join(blocked, "\n"))
+}
+
+func findImageScan(scans []imageScan, svc string) (imageScan, bool) {
+	for _, scan := range scans {
+		if scan.Service == svc {
+			return scan, true
+		}
+	}
+	return imageScan{}, false
+}
+
+// trivyReport is the subset of Trivy's JSON output that Blimp uses.
+type trivyReport struct {
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string
+			PkgName          string
+			InstalledVersion string
+			FixedVersion     string
+			Severity         string
+			Title            string
+		}
+	}
+}
+
+// scan returns the vulnerabilities in the image. Registry credentials are
+// read from the Docker config in `dockerConfigDir`.
+func (scanner *imageScanner) scan(ctx context.Context, image, dockerConfigDir string) ([]vulnerability, error) {
+	ctx, cancel := context.WithTimeout(ctx, imageScanTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "trivy", "--quiet", "image",
+		"--server", scanner.trivyServer,
+		"--format", "json",
+		image)
+	cmd.Env = append(os.Environ(), "DOCKER_CONFIG="+dockerConfigDir)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.WithContext(fmt.Sprintf("run trivy (%s)", strings.TrimSpace(stderr.String())), err)
+	}
+	return parseTrivyReport(stdout.Bytes())
+}
+
+func parseTrivyReport(reportJSON []byte) ([]vulnerability, error) {
+	var report trivyReport
+	if err := json.Unmarshal(reportJSON, &report); err != nil {
+		return nil, errors.WithContext("parse trivy report", err)
+	}
+
+	var vulns []vulnerability
+	seen := map[string]bool{}
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			// The same vulnerability can be reported by multiple targets in
+			// the image.
+			key := v.VulnerabilityID + "/" + v.PkgName
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			vulns = append(vulns, vulnerability{
+				ID:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         strings.ToUpper(v.Severity),
+				Title:            v.Title,
+			})
+		}
+	}
+
+	sort.SliceStable(vulns, func(i, j int) bool {
+		return severityRank(vulns[i].Severity) > severityRank(vulns[j].Severity)
+	})
+	if len(vulns) > maxRecordedVulnerabilities {
+		vulns = vulns[:maxRecordedVulnerabilities]
+	}
+	return vulns, nil
+}
+
+// writeDockerConfig writes the sandbox's registry credentials to a temporary
+// directory in the format expected by Trivy's $DOCKER_CONFIG, so that
+// private images can be scanned. The caller is responsible for removing the
+// directory.
+func (s *server) writeDockerConfig(namespace string) (string, error) {
+	dockerConfig := []byte("{}")
+	secret, err := s.kubeClient.CoreV1().Secrets(namespace).Get("registry-auth", metav1.GetOptions{})
+	switch {
+	case err == nil:
+		dockerConfig = secret.Data[corev1.DockerConfigJsonKey]
+	case !kerrors.IsNotFound(err):
+		return "", errors.WithContext("get registry credentials", err)
+	}
+
+	dir, err := ioutil.TempDir("", "blimp-image-scan")
+	if err != nil {
+		return "", errors.WithContext("create temp dir", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "config.json"), dockerConfig, 0600); err != nil {
+		//nolint:errcheck // Failing to clean up is harmless.
+		os.RemoveAll(dir)
+		return "", errors.WithContext("write config", err)
+	}
+	return dir, nil
+}
+
+// getImageScans returns the scans recorded in the sandbox, sorted by service
+// name.
+func (s *server) getImageScans(namespace string) ([]imageScan, error) {
+	configMap, err := s.kubeClient.CoreV1().ConfigMaps(namespace).Get(imageScansConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.WithContext("get configmap", err)
+	}
+	return parseImageScans(configMap)
+}
+
+func parseImageScans(configMap *corev1.ConfigMap) ([]imageScan, error) {
+	var scans []imageScan
+	for svc, scanJSON := range configMap.Data {
+		var scan imageScan
+		if err := json.Unmarshal([]byte(scanJSON), &scan); err != nil {
+			return nil, errors.WithContext(fmt.Sprintf("parse scan for %s", svc), err)
+		}
+		scans = append(scans, scan)
+	}
+	sort.Slice(scans, func(i, j int) bool {
+		return scans[i].Service < scans[j].Service
+	})
+	return scans, nil
+}
+
+// updateImageScans replaces the scans recorded in the sandbox with the result
+// of `update`, and updates the warnings reported in the sandbox's status to
+// match.
+func (s *server) updateImageScans(namespace string, update func([]imageScan) []imageScan) error {
+	configMapsClient := s.kubeClient.CoreV1().ConfigMaps(namespace)
+	var scans []imageScan
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := configMapsClient.Get(imageScansConfigMap, metav1.GetOptions{})
+		exists := err == nil
+		switch {
+		case kerrors.IsNotFound(err):
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      imageScansConfigMap,
+					Namespace: namespace,
+				},
+			}
+		case err != nil:
+			return err
+		}
+
+		scans, err = parseImageScans(configMap)
+		if err != nil {
+			return err
+		}
+		scans = update(scans)
+
+		configMap.Data = map[string]string{}
+		for _, scan := range scans {
+			scanJSON, err := json.Marshal(scan)
+			if err != nil {
+				return err
+			}
+			configMap.Data[scan.Service] = string(scanJSON)
+		}
+
+		if exists {
+			_, err = configMapsClient.Update(configMap)
+		} else {
+			_, err = configMapsClient.Create(configMap)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// The warnings are stored on the namespace so that the status fetcher
+	// can read them from its cache.
+	warningsJSON, err := json.Marshal(getImageScanWarnings(scans))
+	if err != nil {
+		return err
+	}
+
+	namespacesClient := s.kubeClient.CoreV1().Namespaces()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := namespacesClient.Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		ns.Annotations[kube.ImageScanWarningsAnnotation] = string(warningsJSON)
+		_, err = namespacesClient.Update(ns)
+		return err
+	})
+}
+
+func getImageScanWarnings(scans []imageScan) []string {
+	warnings := []string{}
+	for _, scan := range scans {
+		if count := scan.countAtLeast(warnSeverity); count != 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"The image for %s has %d vulnerabilities of severity %s or higher. "+
+					"Run `blimp scan` for details.", scan.Service, count, warnSeverity))
+		}
+	}
+	return warnings
+}
+
+// parseImageScanWarnings returns the warnings recorded by the last scan of
+// the sandbox's images.
+func parseImageScanWarnings(ns *corev1.Namespace) []string {
+	warningsJSON, ok := ns.Annotations[kube.ImageScanWarningsAnnotation]
+	if !ok {
+		return nil
+	}
+
+	var warnings []string
+	if err := json.Unmarshal([]byte(warningsJSON), &warnings); err != nil {
+		log.WithError(err).WithField("namespace", ns.Name).Warn("Failed to parse image scan warnings")
+		return nil
+	}
+	return warnings
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrivyReport(t *testing.T) {
+	report := `{
+  "SchemaVersion": 2,
+  "ArtifactName": "nginx:1.14",
+  "Results": [
+    {
+      "Target": "nginx:1.14 (debian 9.8)",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2019-1", "PkgName": "libc6", "InstalledVersion": "2.24", "Severity": "LOW"},
+        {"VulnerabilityID": "CVE-2019-2", "PkgName": "openssl", "InstalledVersion": "1.1.0",
+         "FixedVersion": "1.1.1", "Severity": "CRITICAL", "Title": "openssl: bad"},
+        {"VulnerabilityID": "CVE-2019-1", "PkgName": "libc6", "InstalledVersion": "2.24", "Severity": "LOW"}
+      ]
+    },
+    {
+      "Target": "usr/local/bin/app",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2020-3", "PkgName": "golang.org/x/net", "InstalledVersion": "0.1", "Severity": "high"}
+      ]
+    }
+  ]
+}`
+
+	vulns, err := parseTrivyReport([]byte(report))
+	require.NoError(t, err)
+	assert.Equal(t, []vulnerability{
+		{
+			ID:               "CVE-2019-2",
+			Package:          "openssl",
+			InstalledVersion: "1.1.0",
+			FixedVersion:     "1.1.1",
+			Severity:         "CRITICAL",
+			Title:            "openssl: bad",
+		},
+		{
+			ID:               "CVE-2020-3",
+			Package:          "golang.org/x/net",
+			InstalledVersion: "0.1",
+			Severity:         "HIGH",
+		},
+		{
+			ID:               "CVE-2019-1",
+			Package:          "libc6",
+			InstalledVersion: "2.24",
+			Severity:         "LOW",
+		},
+	}, vulns)
+}
+
+func TestGetImageScanWarnings(t *testing.T) {
+	scans := []imageScan{
+		{
+			Service: "db",
+			Vulnerabilities: []vulnerability{
+				{ID: "CVE-1", Severity: "MEDIUM"},
+			},
+		},
+		{
+			Service: "web",
+			Vulnerabilities: []vulnerability{
+				{ID: "CVE-2", Severity: "CRITICAL"},
+				{ID: "CVE-3", Severity: "HIGH"},
+				{ID: "CVE-4", Severity: "LOW"},
+			},
+		},
+		{
+			Service: "worker",
+			Error:   "timed out",
+		},
+	}
+
+	assert.Equal(t, []string{
+		"The image for web has 2 vulnerabilities of severity HIGH or higher. Run `blimp scan` for details.",
+	}, getImageScanWarnings(scans))
+	assert.Equal(t, []string{}, getImageScanWarnings(nil))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// defaultResyncPeriod is how often the informers replay their caches to their
+// event handlers, unless overridden by $INFORMER_RESYNC_PERIOD.
+const defaultResyncPeriod = 30 * time.Second
+
+// eventsFieldSelector limits the events cache to the events about pods, since
+// they're the only events used to explain the status of sandboxes. Events are
+// cached for the whole cluster, so they're usually the largest cache.
+var eventsFieldSelector = fields.OneTermEqualSelector("involvedObject.kind", "Pod").String()
+
+// informerConfig configures the caches of the statusFetcher.
+type informerConfig struct {
+	resyncPeriod time.Duration
+}
+
+var defaultInformerConfig = informerConfig{resyncPeriod: defaultResyncPeriod}
+
+// getInformerConfig reads the informer configuration from the environment.
+func getInformerConfig() informerConfig {
+	config := defaultInformerConfig
+	if resyncVar, ok := os.LookupEnv("INFORMER_RESYNC_PERIOD"); ok {
+		parsedVar, err := time.ParseDuration(resyncVar)
+		if err != nil {
+			log.WithError(err).WithField("INFORMER_RESYNC_PERIOD", resyncVar).
+				Warn("Couldn't parse $INFORMER_RESYNC_PERIOD")
+		} else {
+			config.resyncPeriod = parsedVar
+		}
+	}
+	return config
+}
+
+// cacheSyncStatus returns whether each of the caches has been filled.
+func (sf *statusFetcher) cacheSyncStatus() map[string]bool {
+	return map[string]bool{
+		"pods":       sf.podInformer.HasSynced(),
+		"events":     sf.eventsInformer.HasSynced(),
+		"namespaces": sf.namespaceInformer.HasSynced(),
+		"cronjobs":   sf.cronJobInformer.HasSynced(),
+		"nodes":      sf.nodeInformer.HasSynced(),
+	}
+}
+
+// serveReadyz reports whether the manager is ready to serve RPCs. The manager
+// isn't ready until its caches are filled, since RPCs that read from cold
+// caches would see empty sandboxes.
+func (s *server) serveReadyz(w http.ResponseWriter, _ *http.Request) {
+	syncStatus := s.statusFetcher.cacheSyncStatus()
+	var caches []string
+	for name := range syncStatus {
+		caches = append(caches, name)
+	}
+	sort.Strings(caches)
+
+	serving := atomic.LoadInt32(&s.serving) == 1
+	ready := serving
+	for _, synced := range syncStatus {
+		ready = ready && synced
+	}
+
+	if ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	for _, name := range caches {
+		state := "synced"
+		if !syncStatus[name] {
+			state = "syncing"
+		}
+		fmt.Fprintf(w, "cache %s: %s\n", name, state)
+	}
+	if !serving {
+		fmt.Fprintln(w, "grpc: starting")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+	kubeTesting "k8s.io/client-go/testing"
+)
+
+func TestEventsFieldSelector(t *testing.T) {
+	kubeClient := fakeKube.NewSimpleClientset()
+	var fieldSelectors []string
+	kubeClient.PrependReactor("list", "events",
+		func(action kubeTesting.Action) (bool, runtime.Object, error) {
+			fieldSelectors = append(fieldSelectors,
+				action.(kubeTesting.ListAction).GetListRestrictions().Fields.String())
+			return false, nil, nil
+		})
+
+	sf := newStatusFetcher(kubeClient, defaultInformerConfig)
+	stop := make(chan struct{})
+	defer close(stop)
+	sf.Start(stop)
+
+	// Only pod events are cached.
+	assert.Equal(t, []string{"involvedObject.kind=Pod"}, fieldSelectors)
+}
+
+func TestEventTimestamp(t *testing.T) {
+	at := func(minute int) time.Time {
+		return time.Date(2020, 1, 1, 0, minute, 0, 0, time.UTC)
+	}
+
+	event := &corev1.Event{
+		ObjectMeta:    metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(at(1))},
+		EventTime:     metav1.NewMicroTime(at(2)),
+		LastTimestamp: metav1.NewTime(at(3)),
+	}
+	assert.Equal(t, at(3), eventTimestamp(event))
+
+	event.LastTimestamp = metav1.Time{}
+	assert.Equal(t, at(2), eventTimestamp(event))
+
+	event.EventTime = metav1.MicroTime{}
+	assert.Equal(t, at(1), eventTimestamp(event))
+}
+
+func TestServeReadyz(t *testing.T) {
+	sf := newStatusFetcher(fakeKube.NewSimpleClientset(), defaultInformerConfig)
+	s := &server{statusFetcher: sf}
+
+	readyz := func() int {
+		w := httptest.NewRecorder()
+		s.serveReadyz(w, httptest.NewRequest("GET", "/readyz", nil))
+		return w.Code
+	}
+
+	// The caches haven't been filled yet.
+	assert.Equal(t, http.StatusServiceUnavailable, readyz())
+
+	stop := make(chan struct{})
+	defer close(stop)
+	sf.Start(stop)
+
+	// The gRPC server isn't running yet.
+	assert.Equal(t, http.StatusServiceUnavailable, readyz())
+
+	s.serving = 1
+	assert.Equal(t, http.StatusOK, readyz())
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// checkInitTimeout returns an error status if the given waiter init container
+// has been running for longer than its timeout. The status explains what the
+// waiter is blocked on.
+func (sf *statusFetcher) checkInitTimeout(pod *corev1.Pod, cs corev1.ContainerStatus) (cluster.ServiceStatus, bool) {
+	if cs.State.Running == nil {
+		return cluster.ServiceStatus{}, false
+	}
+
+	var timeout time.Duration
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name != cs.Name {
+			continue
+		}
+
+		for _, env := range c.Env {
+			if env.Name == compose.WaitTimeoutEnv {
+				timeout, _ = time.ParseDuration(env.Value)
+			}
+		}
+	}
+
+	// Pods deployed before timeouts were added don't have a timeout.
+	if timeout == 0 || time.Since(cs.State.Running.StartedAt.Time) < timeout {
+		return cluster.ServiceStatus{}, false
+	}
+
+	var msg string
+	switch cs.Name {
+	case kube.ContainerNameWaitDependsOn:
+		msg = sf.describeDependencies(pod)
+	case kube.ContainerNameWaitInitialSync:
+		msg = "Bind volumes never finished syncing. Make sure that `blimp up` is still running."
+	case kube.ContainerNameWaitInitializedVolumes:
+		msg = "Other services never finished initializing the volumes shared with this service."
+	default:
+		return cluster.ServiceStatus{}, false
+	}
+
+	return cluster.ServiceStatus{
+		Phase: cluster.ServicePhase_INIT_TIMEOUT,
+		Msg:   fmt.Sprintf("Gave up waiting after %s. %s", timeout, msg),
+	}, true
+}
+
+// describeDependencies explains why the pod's dependencies aren't ready,
+// including the most recent probe output for each of them.
+func (sf *statusFetcher) describeDependencies(pod *corev1.Pod) string {
+	dependsOn, ok := pod.Annotations[metadata.DependsOnKey]
+	if !ok {
+		return "Dependencies never became ready."
+	}
+
+	var notReady []string
+	for _, svc := range metadata.ParseDependsOn(dependsOn) {
+		depPod, err := sf.podLister.Pods(pod.Namespace).Get(names.ToDNS1123(svc))
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				notReady = append(notReady, fmt.Sprintf("%s doesn't exist", svc))
+			} else {
+				notReady = append(notReady, fmt.Sprintf("%s has an unknown status", svc))
+			}
+			continue
+		}
+
+		if podIsReady(depPod) {
+			continue
+		}
+
+		if probeOutput := sf.lastProbeFailure(depPod); probeOutput != "" {
+			notReady = append(notReady, fmt.Sprintf("%s never became ready (%s)", svc, probeOutput))
+		} else {
+			notReady = append(notReady, fmt.Sprintf("%s never became ready (%s)", svc, describeContainers(depPod)))
+		}
+	}
+
+	if len(notReady) == 0 {
+		return "Dependencies never became ready."
+	}
+	return strings.Join(notReady, ". ") + "."
+}
+
+// lastProbeFailure returns the output of the most recent failed probe for the
+// given pod.
+func (sf *statusFetcher) lastProbeFailure(pod *corev1.Pod) string {
+	events, err := sf.eventsLister.Events(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		return ""
+	}
+
+	var probeEvents []*corev1.Event
+	for _, event := range events {
+		if event.InvolvedObject.Kind == "Pod" &&
+			event.InvolvedObject.Name == pod.Name &&
+			event.InvolvedObject.UID == pod.UID &&
+			event.Reason == "Unhealthy" {
+			probeEvents = append(probeEvents, event)
+		}
+	}
+
+	if len(probeEvents) == 0 {
+		return ""
+	}
+
+	sort.Slice(probeEvents, func(i, j int) bool {
+		return eventTimestamp(probeEvents[i]).Before(eventTimestamp(probeEvents[j]))
+	})
+	return strings.TrimSpace(probeEvents[len(probeEvents)-1].Message)
+}
+
+func describeContainers(pod *corev1.Pod) string {
+	var statuses []corev1.ContainerStatus
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		switch {
+		case cs.State.Waiting != nil:
+			return fmt.Sprintf("container %s is waiting: %s", cs.Name, cs.State.Waiting.Reason)
+		case cs.State.Terminated != nil && cs.State.Terminated.Reason != "Completed":
+			return fmt.Sprintf("container %s exited: %s", cs.Name, cs.State.Terminated.Reason)
+		}
+	}
+	return fmt.Sprintf("pod is %s", strings.ToLower(string(pod.Status.Phase)))
+}
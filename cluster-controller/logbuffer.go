@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// logBufferSyncInterval is how often the log buffer checks for
+	// containers that started.
+	logBufferSyncInterval = 5 * time.Second
+
+	// defaultLogBufferBytes is how many bytes of logs are retained for each
+	// service, unless overridden by $LOG_BUFFER_BYTES.
+	defaultLogBufferBytes = 1024 * 1024
+)
+
+// logBuffer retains the recent logs of each service. Kubernetes only keeps
+// the logs of the current and previous instance of each container, so
+// without it, logs are lost when a service restarts more than once, or when
+// its pod is recreated.
+type logBuffer struct {
+	kubeClient      kubernetes.Interface
+	podLister       listers.PodLister
+	namespaceLister listers.NamespaceLister
+
+	// maxBytes is the maximum size of the logs retained for each service.
+	// The oldest lines are discarded once it's exceeded.
+	maxBytes int
+
+	mu       sync.Mutex
+	services map[serviceLogsKey]*serviceLogs
+
+	// instances tracks the container instances whose logs have been
+	// collected, keyed by the pod's UID and the container's restart count.
+	instances map[string]*containerInstance
+}
+
+type serviceLogsKey struct {
+	namespace, service string
+}
+
+type serviceLogs struct {
+	lines []*cluster.LogLine
+	bytes int
+}
+
+type containerInstance struct {
+	// following is set while the instance's logs are being streamed.
+	following bool
+
+	// lastLogged is the time of the most recent line collected from the
+	// instance. It's used to avoid collecting lines twice if the stream is
+	// reconnected.
+	lastLogged time.Time
+}
+
+// logCollection describes the logs to collect from a container instance.
+type logCollection struct {
+	key       serviceLogsKey
+	pod       string
+	container string
+	previous  bool
+	instance  *containerInstance
+}
+
+func newLogBuffer(kubeClient kubernetes.Interface, podLister listers.PodLister,
+	namespaceLister listers.NamespaceLister, maxBytes int) *logBuffer {
+	return &logBuffer{
+		kubeClient:      kubeClient,
+		podLister:       podLister,
+		namespaceLister: namespaceLister,
+		maxBytes:        maxBytes,
+		services:        map[serviceLogsKey]*serviceLogs{},
+		instances:       map[string]*containerInstance{},
+	}
+}
+
+func (lb *logBuffer) Run(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := lb.sync(); err != nil {
+			log.WithError(err).Warn("Failed to sync log buffer")
+		}
+	}
+}
+
+// sync starts collecting the logs of any containers that started since the
+// last sync, and discards the logs of deleted sandboxes.
+func (lb *logBuffer) sync() error {
+	pods, err := lb.podLister.List(labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return errors.WithContext("list pods", err)
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	seen := map[string]struct{}{}
+	for _, pod := range pods {
+		service := pod.Labels["blimp.service"]
+		key := serviceLogsKey{pod.Namespace, service}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != names.ToDNS1123(service) {
+				continue
+			}
+
+			// Collect the previous instance first so that the lines are
+			// buffered in order. It's only collected if it exited before the
+			// log buffer noticed it, such as if it crashed quickly, or if the
+			// manager restarted.
+			var collections []logCollection
+			if cs.RestartCount > 0 && cs.LastTerminationState.Terminated != nil {
+				id := fmt.Sprintf("%s/%d", pod.UID, cs.RestartCount-1)
+				seen[id] = struct{}{}
+				if _, ok := lb.instances[id]; !ok {
+					instance := &containerInstance{following: true}
+					lb.instances[id] = instance
+					collections = append(collections, logCollection{key, pod.Name, cs.Name, true, instance})
+				}
+			}
+
+			id := fmt.Sprintf("%s/%d", pod.UID, cs.RestartCount)
+			seen[id] = struct{}{}
+			instance, ok := lb.instances[id]
+			switch {
+			case !ok && (cs.State.Running != nil || cs.State.Terminated != nil):
+				instance = &containerInstance{following: true}
+				lb.instances[id] = instance
+				collections = append(collections, logCollection{key, pod.Name, cs.Name, false, instance})
+
+			// Reconnect to running containers whose stream was interrupted.
+			case ok && !instance.following && cs.State.Running != nil:
+				instance.following = true
+				collections = append(collections, logCollection{key, pod.Name, cs.Name, false, instance})
+			}
+
+			if len(collections) != 0 {
+				go lb.collectAll(collections)
+			}
+		}
+	}
+
+	for id, instance := range lb.instances {
+		if _, ok := seen[id]; !ok && !instance.following {
+			delete(lb.instances, id)
+		}
+	}
+
+	for key := range lb.services {
+		if _, err := lb.namespaceLister.Get(key.namespace); kerrors.IsNotFound(err) {
+			delete(lb.services, key)
+		}
+	}
+	return nil
+}
+
+func (lb *logBuffer) collectAll(collections []logCollection) {
+	for _, c := range collections {
+		if err := lb.collect(c); err != nil {
+			log.WithError(err).WithField("namespace", c.key.namespace).
+				WithField("service", c.key.service).Debug("Failed to collect logs")
+		}
+
+		lb.mu.Lock()
+		c.instance.following = false
+		lb.mu.Unlock()
+	}
+}
+
+// collect reads the logs of a container instance into its service's buffer.
+// If the container is running, it blocks until the container exits.
+func (lb *logBuffer) collect(c logCollection) error {
+	opts := corev1.PodLogOptions{
+		Container:  c.container,
+		Follow:     !c.previous,
+		Previous:   c.previous,
+		Timestamps: true,
+	}
+
+	lb.mu.Lock()
+	lastLogged := c.instance.lastLogged
+	lb.mu.Unlock()
+	if !lastLogged.IsZero() {
+		sinceTime := metav1.NewTime(lastLogged)
+		opts.SinceTime = &sinceTime
+	}
+
+	stream, err := lb.kubeClient.CoreV1().Pods(c.key.namespace).GetLogs(c.pod, &opts).Stream()
+	if err != nil {
+		return errors.WithContext("start logs stream", err)
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			lb.add(c, strings.TrimSuffix(line, "\n"))
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.WithContext("read logs", err)
+		}
+	}
+}
+
+// add buffers a log line that has the timestamp added by Kubernetes.
+func (lb *logBuffer) add(c logCollection, rawLine string) {
+	parts := strings.SplitN(rawLine, " ", 2)
+	if len(parts) != 2 {
+		return
+	}
+
+	loggedAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	// SinceTime only has second-level resolution, so reconnecting can
+	// return lines that were already collected.
+	if !loggedAt.After(c.instance.lastLogged) {
+		return
+	}
+	c.instance.lastLogged = loggedAt
+
+	logs, ok := lb.services[c.key]
+	if !ok {
+		logs = &serviceLogs{}
+		lb.services[c.key] = logs
+	}
+	logs.add(&cluster.LogLine{Time: loggedAt.UnixNano(), Message: parts[1]}, lb.maxBytes)
+}
+
+// Get returns the buffered lines of the given service that were logged at or
+// after `since`, which is a Unix timestamp in nanoseconds. If `tail` is
+// positive, only the most recent `tail` lines are returned.
+func (lb *logBuffer) Get(namespace, service string, since int64, tail int) []*cluster.LogLine {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	logs, ok := lb.services[serviceLogsKey{namespace, service}]
+	if !ok {
+		return nil
+	}
+
+	var lines []*cluster.LogLine
+	for _, line := range logs.lines {
+		if line.Time >= since {
+			lines = append(lines, line)
+		}
+	}
+
+	// The lines of different pods, such as the runs of scheduled services,
+	// may have been collected concurrently.
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Time < lines[j].Time
+	})
+
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	return lines
+}
+
+func (logs *serviceLogs) add(line *cluster.LogLine, maxBytes int) {
+	logs.lines = append(logs.lines, line)
+	logs.bytes += len(line.Message)
+	if logs.bytes <= maxBytes {
+		return
+	}
+
+	// Discard lines until the logs are well under the limit so that the
+	// remaining lines don't have to be copied every time a line is added.
+	var drop int
+	for drop < len(logs.lines) && logs.bytes > maxBytes*3/4 {
+		logs.bytes -= len(logs.lines[drop].Message)
+		drop++
+	}
+	logs.lines = append([]*cluster.LogLine(nil), logs.lines[drop:]...)
+}
+
+func (s *server) GetLogs(ctx context.Context, req *cluster.GetLogsRequest) (*cluster.GetLogsResponse, error) {
+	log.Info("Start GetLogs")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.GetLogsResponse{}, err
+	}
+
+	// Log buffering is disabled, so the client should get the logs directly
+	// from the container.
+	if s.logBuffer == nil {
+		return &cluster.GetLogsResponse{}, nil
+	}
+
+	// Older clients don't support StreamLogs, so return as many of the most
+	// recent lines as fit in a single response.
+	lines := s.logBuffer.Get(user.Namespace, req.GetService(), req.GetSince(), int(req.GetTail()))
+	return &cluster.GetLogsResponse{
+		Buffered: true,
+		Lines:    newestLogsWithin(lines, s.responseBudget(ctx)),
+	}, nil
+}
+
+// StreamLogs is the same as GetLogs, except that the lines are split across
+// multiple responses if they're larger than the client's maximum message
+// size.
+func (s *server) StreamLogs(req *cluster.GetLogsRequest, stream cluster.Manager_StreamLogsServer) error {
+	log.Info("Start StreamLogs")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return err
+	}
+
+	if s.logBuffer == nil {
+		return stream.Send(&cluster.GetLogsResponse{})
+	}
+
+	lines := s.logBuffer.Get(user.Namespace, req.GetService(), req.GetSince(), int(req.GetTail()))
+	chunks := chunkLogs(lines, s.responseBudget(stream.Context()))
+	if len(chunks) == 0 {
+		return stream.Send(&cluster.GetLogsResponse{Buffered: true})
+	}
+
+	for _, chunk := range chunks {
+		if err := stream.Send(&cluster.GetLogsResponse{Buffered: true, Lines: chunk}); err != nil {
+			return errors.WithContext("send logs", err)
+		}
+	}
+	return nil
+}
+
+// logLineSize returns how many bytes the line adds to a GetLogsResponse,
+// including the field's tag and length prefix.
+func logLineSize(line *cluster.LogLine) int {
+	return proto.Size(line) + 1 + binary.MaxVarintLen32
+}
+
+// chunkLogs splits the lines into chunks of at most maxBytes each. Lines that
+// are too large to fit in a chunk on their own are truncated.
+func chunkLogs(lines []*cluster.LogLine, maxBytes int) [][]*cluster.LogLine {
+	var chunks [][]*cluster.LogLine
+	var chunk []*cluster.LogLine
+	var chunkBytes int
+	for _, line := range lines {
+		line = truncateLogLine(line, maxBytes)
+		size := logLineSize(line)
+		if len(chunk) != 0 && chunkBytes+size > maxBytes {
+			chunks = append(chunks, chunk)
+			chunk, chunkBytes = nil, 0
+		}
+		chunk = append(chunk, line)
+		chunkBytes += size
+	}
+
+	if len(chunk) != 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// newestLogsWithin returns the most recent lines that fit in maxBytes.
+func newestLogsWithin(lines []*cluster.LogLine, maxBytes int) []*cluster.LogLine {
+	var total int
+	for i := len(lines) - 1; i >= 0; i-- {
+		total += logLineSize(lines[i])
+		if total <= maxBytes {
+			continue
+		}
+
+		if i == len(lines)-1 {
+			return []*cluster.LogLine{truncateLogLine(lines[i], maxBytes)}
+		}
+		return lines[i+1:]
+	}
+	return lines
+}
+
+// truncateLogLine shortens the line's message so that it fits in maxBytes.
+// The line is copied rather than modified since it's shared with the log
+// buffer.
+func truncateLogLine(line *cluster.LogLine, maxBytes int) *cluster.LogLine {
+	excess := logLineSize(line) - maxBytes
+	if excess <= 0 {
+		return line
+	}
+
+	suffix := " [truncated]"
+	keep := len(line.Message) - excess
+	if keep > len(suffix) {
+		keep -= len(suffix)
+	} else {
+		keep, suffix = 0, ""
+	}
+
+	// Don't split multi-byte characters, since protobuf strings must be
+	// valid UTF-8.
+	for keep > 0 && !utf8.RuneStart(line.Message[keep]) {
+		keep--
+	}
+	return &cluster.LogLine{Time: line.Time, Message: line.Message[:keep] + suffix}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func TestLogBufferGet(t *testing.T) {
+	lb := newLogBuffer(nil, nil, nil, 1024)
+	web := logCollection{key: serviceLogsKey{"ns", "web"}, instance: &containerInstance{}}
+	restartedWeb := logCollection{key: serviceLogsKey{"ns", "web"}, instance: &containerInstance{}}
+
+	lb.add(web, "2020-01-01T00:00:01.000000000Z first")
+	lb.add(web, "2020-01-01T00:00:03.000000000Z third")
+	lb.add(restartedWeb, "2020-01-01T00:00:02.000000000Z second")
+
+	// Lines that were already collected from the instance, or that are
+	// missing a timestamp are ignored.
+	lb.add(web, "2020-01-01T00:00:03.000000000Z third")
+	lb.add(web, "malformed")
+
+	lineAt := func(second int, message string) *cluster.LogLine {
+		return &cluster.LogLine{
+			Time:    time.Date(2020, 1, 1, 0, 0, second, 0, time.UTC).UnixNano(),
+			Message: message,
+		}
+	}
+
+	assert.Equal(t, []*cluster.LogLine{
+		lineAt(1, "first"),
+		lineAt(2, "second"),
+		lineAt(3, "third"),
+	}, lb.Get("ns", "web", 0, 0))
+
+	assert.Equal(t, []*cluster.LogLine{
+		lineAt(2, "second"),
+		lineAt(3, "third"),
+	}, lb.Get("ns", "web", lineAt(2, "").Time, 0))
+
+	assert.Equal(t, []*cluster.LogLine{
+		lineAt(3, "third"),
+	}, lb.Get("ns", "web", 0, 1))
+
+	assert.Empty(t, lb.Get("ns", "db", 0, 0))
+	assert.Empty(t, lb.Get("other-ns", "web", 0, 0))
+}
+
+func TestServiceLogsAdd(t *testing.T) {
+	var logs serviceLogs
+	for i := 0; i < 10; i++ {
+		logs.add(&cluster.LogLine{Time: int64(i), Message: "0123456789"}, 50)
+		assert.True(t, logs.bytes <= 50)
+	}
+
+	// Once the limit is exceeded, the oldest lines are discarded until the
+	// logs are under three quarters of the limit.
+	assert.Len(t, logs.lines, 4)
+	assert.Equal(t, int64(9), logs.lines[len(logs.lines)-1].Time)
+}
+
+func TestChunkLogs(t *testing.T) {
+	var lines []*cluster.LogLine
+	for i := 0; i < 10; i++ {
+		lines = append(lines, &cluster.LogLine{Time: int64(i + 1), Message: "0123456789"})
+	}
+	lineSize := logLineSize(lines[0])
+
+	chunks := chunkLogs(lines, 3*lineSize)
+	assert.Len(t, chunks, 4)
+	for _, chunk := range chunks[:3] {
+		assert.Len(t, chunk, 3)
+	}
+	assert.Equal(t, lines[9:], chunks[3])
+
+	// Lines that are too large for a chunk are truncated, without modifying
+	// the original line.
+	chunks = chunkLogs(lines[:1], lineSize-5)
+	assert.Len(t, chunks, 1)
+	assert.True(t, logLineSize(chunks[0][0]) <= lineSize-5)
+	assert.Equal(t, "0123456789", lines[0].Message)
+
+	assert.Empty(t, chunkLogs(nil, lineSize))
+}
+
+func TestNewestLogsWithin(t *testing.T) {
+	var lines []*cluster.LogLine
+	for i := 0; i < 10; i++ {
+		lines = append(lines, &cluster.LogLine{Time: int64(i + 1), Message: "0123456789"})
+	}
+	lineSize := logLineSize(lines[0])
+
+	assert.Equal(t, lines, newestLogsWithin(lines, 10*lineSize))
+	assert.Equal(t, lines[7:], newestLogsWithin(lines, 3*lineSize+1))
+
+	newest := newestLogsWithin(lines, lineSize-5)
+	assert.Len(t, newest, 1)
+	assert.Equal(t, int64(10), newest[0].Time)
+	assert.True(t, logLineSize(newest[0]) <= lineSize-5)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/names"
+)
+
+// logReadySyncInterval is how often the log readiness watcher checks for
+// containers that started.
+const logReadySyncInterval = 5 * time.Second
+
+// logReadiness marks the pods of services with x-blimp.ready_log_pattern as
+// ready once their container logs a line that matches the pattern. Readiness
+// is tracked with a pod readiness gate, so the pod only becomes ready once
+// both the pattern has matched and any healthcheck passes.
+type logReadiness struct {
+	kubeClient kubernetes.Interface
+	podLister  listers.PodLister
+
+	mu sync.Mutex
+
+	// following contains the container instances whose logs are being
+	// tailed, keyed by the pod's UID and the container's restart count.
+	following map[string]struct{}
+}
+
+func newLogReadiness(kubeClient kubernetes.Interface, podLister listers.PodLister) *logReadiness {
+	return &logReadiness{
+		kubeClient: kubeClient,
+		podLister:  podLister,
+		following:  map[string]struct{}{},
+	}
+}
+
+func (lr *logReadiness) Run(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := lr.sync(); err != nil {
+			log.WithError(err).Warn("Failed to sync log readiness")
+		}
+	}
+}
+
+// sync starts tailing the logs of containers that haven't logged their ready
+// line yet, and resets the readiness of containers that restarted.
+func (lr *logReadiness) sync() error {
+	pods, err := lr.podLister.List(labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return errors.WithContext("list pods", err)
+	}
+
+	for _, pod := range pods {
+		pattern, ok := pod.Annotations[metadata.ReadyLogPatternKey]
+		if !ok {
+			continue
+		}
+
+		// The pattern is validated when the pod is created, so this
+		// should never fail.
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.WithError(err).WithField("namespace", pod.Namespace).
+				WithField("pod", pod.Name).Warn("Invalid ready log pattern")
+			continue
+		}
+
+		container := names.ToDNS1123(pod.Labels["blimp.service"])
+		cs, ok := getContainerStatus(pod, container)
+		if !ok || cs.State.Running == nil {
+			continue
+		}
+
+		ready, stale := logReadyStatus(pod, cs)
+		if ready {
+			continue
+		}
+		if stale {
+			err := lr.setLogReady(pod.Namespace, pod.Name, corev1.ConditionFalse,
+				"ContainerRestarted", "The container restarted, so it must log its ready line again.")
+			if err != nil {
+				log.WithError(err).WithField("namespace", pod.Namespace).
+					WithField("pod", pod.Name).Warn("Failed to reset log readiness")
+				continue
+			}
+		}
+
+		id := fmt.Sprintf("%s/%d", pod.UID, cs.RestartCount)
+		if lr.startFollowing(id) {
+			go lr.follow(id, pod.Namespace, pod.Name, container, re)
+		}
+	}
+	return nil
+}
+
+// startFollowing marks the container instance as being tailed. It returns
+// false if the instance is already being tailed.
+func (lr *logReadiness) startFollowing(id string) bool {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if _, ok := lr.following[id]; ok {
+		return false
+	}
+	lr.following[id] = struct{}{}
+	return true
+}
+
+// logReadyStatus returns whether the current instance of the container has
+// logged its ready line. If the pod is marked as ready by an earlier instance
+// of the container, `stale` is true.
+func logReadyStatus(pod *corev1.Pod, cs corev1.ContainerStatus) (ready, stale bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != kube.LogReadyCondition || condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		if cs.State.Running != nil && condition.LastTransitionTime.Before(&cs.State.Running.StartedAt) {
+			return false, true
+		}
+		return true, false
+	}
+	return false, false
+}
+
+// follow tails the container's logs until a line matches the pattern, or the
+// container exits. If the stream is interrupted, the next sync tails the logs
+// again from the start.
+func (lr *logReadiness) follow(id, namespace, pod, container string, re *regexp.Regexp) {
+	defer func() {
+		lr.mu.Lock()
+		delete(lr.following, id)
+		lr.mu.Unlock()
+	}()
+
+	logger := log.WithField("namespace", namespace).WithField("pod", pod)
+	stream, err := lr.kubeClient.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+		Follow:    true,
+	}).Stream()
+	if err != nil {
+		logger.WithError(err).Debug("Failed to start logs stream")
+		return
+	}
+	defer stream.Close()
+
+	matched, err := matchLogs(stream, re)
+	if err != nil {
+		logger.WithError(err).Debug("Failed to read logs")
+	}
+	if !matched {
+		return
+	}
+
+	err = lr.setLogReady(namespace, pod, corev1.ConditionTrue,
+		"LogPatternMatched", "The service logged a line matching its ready_log_pattern.")
+	if err != nil {
+		logger.WithError(err).Warn("Failed to mark pod as ready")
+	}
+}
+
+// matchLogs reads log lines until one matches the pattern.
+func matchLogs(logs io.Reader, re *regexp.Regexp) (bool, error) {
+	reader := bufio.NewReader(logs)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" && re.MatchString(line) {
+			return true, nil
+		}
+
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+func (lr *logReadiness) setLogReady(namespace, name string, status corev1.ConditionStatus,
+	reason, message string) error {
+	podsClient := lr.kubeClient.CoreV1().Pods(namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pod, err := podsClient.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		condition := corev1.PodCondition{
+			Type:               kube.LogReadyCondition,
+			Status:             status,
+			LastTransitionTime: metav1.Now(),
+			Reason:             reason,
+			Message:            message,
+		}
+
+		updated := false
+		for i, c := range pod.Status.Conditions {
+			if c.Type == kube.LogReadyCondition {
+				pod.Status.Conditions[i] = condition
+				updated = true
+			}
+		}
+		if !updated {
+			pod.Status.Conditions = append(pod.Status.Conditions, condition)
+		}
+
+		_, err = podsClient.UpdateStatus(pod)
+		return err
+	})
+}
+
+func getContainerStatus(pod *corev1.Pod, name string) (corev1.ContainerStatus, bool) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == name {
+			return cs, true
+		}
+	}
+	return corev1.ContainerStatus{}, false
+}
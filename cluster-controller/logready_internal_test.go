@@ -0,0 +1,103 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+func TestMatchLogs(t *testing.T) {
+	re := regexp.MustCompile(`Listening on port \d+`)
+
+	matched, err := matchLogs(strings.NewReader("Starting\nListening on port 3000\n"), re)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+
+	// The last line may not have a trailing newline.
+	matched, err = matchLogs(strings.NewReader("Starting\nListening on port 3000"), re)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+
+	matched, err = matchLogs(strings.NewReader("Starting\nListening on port\n"), re)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+}
+
+func TestLogReadyStatus(t *testing.T) {
+	startedAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cs := corev1.ContainerStatus{
+		State: corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(startedAt)},
+		},
+	}
+	pod := func(status corev1.ConditionStatus, transitionedAt time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{
+					Type:               kube.LogReadyCondition,
+					Status:             status,
+					LastTransitionTime: metav1.NewTime(transitionedAt),
+				}},
+			},
+		}
+	}
+
+	ready, stale := logReadyStatus(&corev1.Pod{}, cs)
+	assert.False(t, ready)
+	assert.False(t, stale)
+
+	ready, stale = logReadyStatus(pod(corev1.ConditionFalse, startedAt.Add(time.Minute)), cs)
+	assert.False(t, ready)
+	assert.False(t, stale)
+
+	ready, stale = logReadyStatus(pod(corev1.ConditionTrue, startedAt.Add(time.Minute)), cs)
+	assert.True(t, ready)
+	assert.False(t, stale)
+
+	// The pod was marked as ready by the previous instance of the container.
+	ready, stale = logReadyStatus(pod(corev1.ConditionTrue, startedAt.Add(-time.Minute)), cs)
+	assert.False(t, ready)
+	assert.True(t, stale)
+}
+
+func TestStartFollowing(t *testing.T) {
+	lr := newLogReadiness(fakeKube.NewSimpleClientset(), nil)
+	assert.True(t, lr.startFollowing("pod/0"))
+	assert.False(t, lr.startFollowing("pod/0"))
+
+	// Restarted containers are tailed again.
+	assert.True(t, lr.startFollowing("pod/1"))
+}
+
+func TestSetLogReady(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web"},
+		Spec: corev1.PodSpec{
+			ReadinessGates: []corev1.PodReadinessGate{{ConditionType: kube.LogReadyCondition}},
+		},
+	}
+	kubeClient := fakeKube.NewSimpleClientset(pod)
+	lr := newLogReadiness(kubeClient, nil)
+
+	getPod := func() corev1.Pod {
+		pod, err := kubeClient.CoreV1().Pods("ns").Get("web", metav1.GetOptions{})
+		assert.NoError(t, err)
+		return *pod
+	}
+	assert.False(t, kube.IsLogReady(getPod()))
+
+	assert.NoError(t, lr.setLogReady("ns", "web", corev1.ConditionTrue, "LogPatternMatched", ""))
+	assert.True(t, kube.IsLogReady(getPod()))
+
+	assert.NoError(t, lr.setLogReady("ns", "web", corev1.ConditionFalse, "ContainerRestarted", ""))
+	assert.False(t, kube.IsLogReady(getPod()))
+	assert.Len(t, getPod().Status.Conditions, 1)
+}
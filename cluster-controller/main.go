@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/Masterminds/semver"
@@ -18,10 +19,11 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
-	composeTypes "github.com/kelda/compose-go/types"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -33,12 +35,17 @@ import (
 	"k8s.io/client-go/util/retry"
 
 	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/cluster-controller/billing"
+	"github.com/kelda/blimp/cluster-controller/bootslo"
+	"github.com/kelda/blimp/cluster-controller/capacity"
 	"github.com/kelda/blimp/cluster-controller/httpapi"
+	"github.com/kelda/blimp/cluster-controller/metering"
 	"github.com/kelda/blimp/cluster-controller/node"
+	"github.com/kelda/blimp/cluster-controller/store"
 	"github.com/kelda/blimp/cluster-controller/volume"
 	"github.com/kelda/blimp/pkg/auth"
 	clusterAuth "github.com/kelda/blimp/pkg/auth"
-	"github.com/kelda/blimp/pkg/dockercompose"
+	"github.com/kelda/blimp/pkg/compose"
 	"github.com/kelda/blimp/pkg/errors"
 	"github.com/kelda/blimp/pkg/expose"
 	"github.com/kelda/blimp/pkg/kube"
@@ -48,6 +55,7 @@ import (
 	"github.com/kelda/blimp/pkg/ports"
 	protoAuth "github.com/kelda/blimp/pkg/proto/auth"
 	"github.com/kelda/blimp/pkg/proto/cluster"
+	"github.com/kelda/blimp/pkg/strs"
 	"github.com/kelda/blimp/pkg/syncthing"
 	"github.com/kelda/blimp/pkg/version"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -63,8 +71,42 @@ type server struct {
 	kubeClient        kubernetes.Interface
 	restConfig        *rest.Config
 	statusFetcher     *statusFetcher
+	statusSessions    *statusSessions
+	heartbeatTimeout  time.Duration
 	certPath, keyPath string
 	maxSandboxes      int
+	meter             *metering.Meter
+	sandboxes         *sandboxController
+	usageQuota        cluster.UsageRecord
+	boostPolicy       boostPolicy
+	egressPolicy      egressPolicy
+	selfTests         selfTestState
+	bootSLO           *bootslo.Tracker
+
+	// logBuffer is nil if log buffering is disabled.
+	logBuffer *logBuffer
+
+	// imageScanner is nil if image scanning is disabled.
+	imageScanner *imageScanner
+
+	// shareLinkKey signs the guest tokens created by CreateShareLink. It's
+	// nil if share links are disabled.
+	shareLinkKey []byte
+
+	// maxMessageBytes is the largest gRPC message that the manager sends or
+	// receives.
+	maxMessageBytes int
+
+	namespaces *namespaceRegistry
+
+	// store persists the manager's records, such as scoped tokens.
+	store store.Store
+
+	// serving is set to 1 once the gRPC server is accepting connections.
+	serving int32
+
+	// billing is nil if billing hooks are disabled.
+	billing billing.Hook
 }
 
 var (
@@ -72,14 +114,23 @@ var (
 	// BLIMP_REGISTRY_HOSTNAME.
 	RegistryHostname string
 
+	// ImageCacheHostname is the hostname of the image cache that images are
+	// pulled through. It's set by make, or by the environment variable
+	// BLIMP_IMAGE_CACHE_HOSTNAME. Images are pulled directly from their
+	// registries if it's empty.
+	ImageCacheHostname string
+
 	// LinkProxyBaseHostname is the base hostname for Blimp preview links. It
 	// should match base hostname used in the link proxy.
 	LinkProxyBaseHostname string
 )
 
-// MaxServices is the maximum number of service pods allowed in a single
-// sandbox.
-const MaxServices = 150
+// bootSLOSampleInterval is how often the boot times of newly booted services
+// are recorded.
+const bootSLOSampleInterval = 30 * time.Second
+
+// capacityScaleInterval is how often the capacity placeholders are scaled.
+const capacityScaleInterval = 15 * time.Second
 
 func main() {
 	kubeClient, restConfig, err := kube.GetClient()
@@ -100,6 +151,10 @@ func main() {
 		RegistryHostname = registryHostnameVar
 	}
 
+	if imageCacheHostnameVar, ok := os.LookupEnv("BLIMP_IMAGE_CACHE_HOSTNAME"); ok {
+		ImageCacheHostname = imageCacheHostnameVar
+	}
+
 	if linkProxyBaseHostnameVar, ok := os.LookupEnv("BLIMP_LINK_PROXY_BASE_HOSTNAME"); ok {
 		LinkProxyBaseHostname = linkProxyBaseHostnameVar
 	}
@@ -116,26 +171,173 @@ func main() {
 	}
 	log.Infof("Capping maximum concurrent sandboxes to %d", maxSandboxes)
 
-	s := &server{
-		statusFetcher: newStatusFetcher(kubeClient),
-		kubeClient:    kubeClient,
-		restConfig:    restConfig,
-		certPath:      *certPath,
-		keyPath:       *keyPath,
-		maxSandboxes:  maxSandboxes,
+	var selfTestInterval time.Duration
+	if selfTestIntervalVar, ok := os.LookupEnv("SELF_TEST_INTERVAL"); ok {
+		parsedVar, err := time.ParseDuration(selfTestIntervalVar)
+		if err != nil {
+			log.WithError(err).WithField("SELF_TEST_INTERVAL", selfTestIntervalVar).
+				Warn("Couldn't parse $SELF_TEST_INTERVAL")
+		} else {
+			selfTestInterval = parsedVar
+		}
+	}
+
+	logBufferBytes := defaultLogBufferBytes
+	if logBufferBytesVar, ok := os.LookupEnv("LOG_BUFFER_BYTES"); ok {
+		parsedVar, err := strconv.Atoi(logBufferBytesVar)
+		if err != nil {
+			log.WithError(err).WithField("LOG_BUFFER_BYTES", logBufferBytesVar).
+				Warn("Couldn't parse $LOG_BUFFER_BYTES")
+		} else {
+			logBufferBytes = parsedVar
+		}
+	}
+
+	// CAPACITY_MAX_PLACEHOLDERS enables proactive scaling of the sandbox
+	// node pool. It requires the cluster autoscaler. See the capacity
+	// package for details.
+	parseInt := func(key string) int {
+		str, ok := os.LookupEnv(key)
+		if !ok {
+			return 0
+		}
+
+		val, err := strconv.Atoi(str)
+		if err != nil {
+			log.WithError(err).WithField(key, str).Warnf("Couldn't parse $%s", key)
+			return 0
+		}
+		return val
+	}
+	capacityHeadroom := parseInt("CAPACITY_HEADROOM")
+	capacityMaxPlaceholders := parseInt("CAPACITY_MAX_PLACEHOLDERS")
+
+	// GRPC_MAX_MESSAGE_BYTES raises the limit on gRPC messages for clusters
+	// with large sandboxes. Clients learn the limit from CheckVersion.
+	maxMessageBytes := defaultMaxMessageBytes
+	if parsedVar := parseInt("GRPC_MAX_MESSAGE_BYTES"); parsedVar > 0 {
+		maxMessageBytes = parsedVar
+	}
+
+	meshCompatibility = os.Getenv("MESH_COMPATIBILITY") == "true"
+	if meshCompatibility {
+		log.Info("Running in service mesh compatibility mode")
+	}
+
+	if err := configurePodPatches(); err != nil {
+		log.WithError(err).Error("Failed to load pod patches")
+		os.Exit(1)
+	}
+
+	egressPolicy, err := loadEgressPolicy()
+	if err != nil {
+		log.WithError(err).Error("Failed to load egress policy")
+		os.Exit(1)
+	}
+
+	imageScanner, err := loadImageScanner()
+	if err != nil {
+		log.WithError(err).Error("Failed to configure image scanning")
+		os.Exit(1)
+	}
+
+	// BLIMP_STORE selects where the manager's records are persisted. See
+	// store.New for the supported URLs.
+	managerStore, err := store.New(os.Getenv("BLIMP_STORE"), restConfig)
+	if err != nil {
+		log.WithError(err).Error("Failed to connect to store")
+		os.Exit(1)
+	}
+	defer managerStore.Close()
+
+	sandboxes, err := newSandboxController(restConfig)
+	if err != nil {
+		log.WithError(err).Error("Failed to create sandbox controller")
+		os.Exit(1)
+	}
+
+	statusFetcher := newStatusFetcher(kubeClient, getInformerConfig())
+	if err := configureRuntimeClass(kubeClient, statusFetcher.namespaceLister); err != nil {
+		log.WithError(err).Error("Failed to configure runtime class")
+		os.Exit(1)
 	}
+
+	s := &server{
+		statusFetcher:    statusFetcher,
+		statusSessions:   newStatusSessions(),
+		heartbeatTimeout: getHeartbeatTimeout(),
+		kubeClient:       kubeClient,
+		restConfig:       restConfig,
+		certPath:         *certPath,
+		keyPath:          *keyPath,
+		maxSandboxes:     maxSandboxes,
+		meter:            metering.New(kubeClient, statusFetcher.namespaceLister, statusFetcher.podLister, managerStore),
+		sandboxes:        sandboxes,
+		usageQuota:       getUsageQuota(),
+		boostPolicy:      getBoostPolicy(),
+		egressPolicy:     egressPolicy,
+		imageScanner:     imageScanner,
+		shareLinkKey:     loadShareLinkKey(),
+		maxMessageBytes:  maxMessageBytes,
+		namespaces:       newNamespaceRegistry(managerStore),
+		store:            managerStore,
+		billing:          getBillingHook(),
+		bootSLO: bootslo.New(kubeClient, statusFetcher.podLister, version.Version,
+			os.Getenv("BOOT_SLO_WEBHOOK_URL")),
+	}
+
+	// Serve metrics and readiness checks while the caches warm up, so that
+	// orchestrators can tell that the manager is still starting.
+	metricsAddr := fmt.Sprintf(":%d", ports.ClusterManagerMetricsPort)
+	serveMetricsErr := make(chan error, 1)
+	go func() {
+		serveMetricsErr <- s.serveMetrics(metricsAddr)
+	}()
+	log.WithField("address", metricsAddr).Info("Listening for metrics requests..")
+
 	s.statusFetcher.Start(nil)
+	if err := s.namespaces.migrateNamespaces(kubeClient); err != nil {
+		log.WithError(err).Warn("Failed to record the owners of existing namespaces")
+	}
+	if err := s.migrateScopedTokens(); err != nil {
+		log.WithError(err).Warn("Failed to migrate scoped tokens")
+	}
+	if logBufferBytes > 0 {
+		s.logBuffer = newLogBuffer(kubeClient, statusFetcher.podLister, statusFetcher.namespaceLister, logBufferBytes)
+		go s.logBuffer.Run(logBufferSyncInterval)
+	}
+	go newLogReadiness(kubeClient, statusFetcher.podLister).Run(logReadySyncInterval)
+	go s.meter.Run(usageSampleInterval)
+	go s.runSandboxController(sandboxControllerWorkers)
+	go s.runRateLimitRetrier(rateLimitCheckInterval)
+	go s.runBoostExpirer(boostCheckInterval)
+	go s.bootSLO.Run(bootSLOSampleInterval)
+	go s.runPreemptionRescheduler(preemptionCheckInterval)
+	if s.billing != nil {
+		go s.runBillingUsageReporter(billingUsageInterval)
+	}
+	if capacityMaxPlaceholders > 0 {
+		capacityManager := capacity.New(kubeClient, statusFetcher.podLister,
+			capacityHeadroom, capacityMaxPlaceholders)
+		go capacityManager.Run(capacityScaleInterval)
+	}
+	if selfTestInterval > 0 {
+		log.Infof("Running self-tests every %s", selfTestInterval)
+		go s.runScheduledSelfTests(selfTestInterval)
+	}
 
 	useNodePort := os.Getenv("USE_NODE_PORT_FOR_NODE_CONTROLLER") == "true"
 	node.StartControllerBooter(kubeClient, useNodePort)
 
-	if err := s.listenAndServe(); err != nil {
+	if err := s.listenAndServe(serveMetricsErr); err != nil {
 		log.WithError(err).Error("Unexpected error")
 		os.Exit(1)
 	}
 }
 
-func (s *server) listenAndServe() error {
+// listenAndServe starts the gRPC and HTTP servers. It returns if any of the
+// servers, including the metrics server, fail.
+func (s *server) listenAndServe(serveMetricsErr <-chan error) error {
 	grpcAddr := fmt.Sprintf(":%d", ports.ClusterManagerGRPCInternalPort)
 	httpAddr := fmt.Sprintf(":%d", ports.ClusterManagerHTTPInternalPort)
 
@@ -150,13 +352,20 @@ func (s *server) listenAndServe() error {
 		return errors.WithContext("parse cert", err)
 	}
 
-	grpcServer := grpc.NewServer(grpc.Creds(grpcCreds), grpc.UnaryInterceptor(errors.UnaryServerInterceptor))
+	grpcServer := grpc.NewServer(append(heartbeatServerOptions(s.heartbeatTimeout),
+		grpc.Creds(grpcCreds),
+		grpc.MaxRecvMsgSize(s.maxMessageBytes),
+		grpc.MaxSendMsgSize(s.maxMessageBytes),
+		grpc.ChainUnaryInterceptor(errors.UnaryServerInterceptor,
+			s.scopedTokenUnaryInterceptor, s.namespaceUnaryInterceptor),
+		grpc.ChainStreamInterceptor(s.scopedTokenStreamInterceptor, s.namespaceStreamInterceptor))...)
 	cluster.RegisterManagerServer(grpcServer, s)
 
 	serveGrpcErr := make(chan error, 1)
 	go func() {
 		serveGrpcErr <- grpcServer.Serve(grpcLis)
 	}()
+	atomic.StoreInt32(&s.serving, 1)
 
 	// Start the HTTP server.
 	httpServer, err := httpapi.NewServer(httpAddr, map[string]httpapi.Handler{
@@ -166,15 +375,24 @@ func (s *server) listenAndServe() error {
 				shim := &blimpUpPreviewShim{WebSocketStream: wss}
 				return s.BlimpUpPreview(req.(*cluster.BlimpUpPreviewRequest), shim)
 			},
+			HeartbeatTimeout: s.heartbeatTimeout,
 		},
+		"/api/create-preview": httpapi.UnaryHandler{RPC: s.CreatePreview},
+		"/api/delete-preview": httpapi.UnaryHandler{RPC: s.DeletePreview},
 		"/api/delete-sandbox": httpapi.UnaryHandler{RPC: s.DeleteSandbox},
 		"/api/expose":         httpapi.UnaryHandler{RPC: s.Expose},
+		"/api/grpc-tunnel": httpapi.TunnelHandler{
+			Dial:             s.dialTunnelTarget,
+			HeartbeatTimeout: s.heartbeatTimeout,
+		},
+		"/api/poll-status": httpapi.UnaryHandler{RPC: s.PollStatus},
 		"/api/watch-status": httpapi.StreamHandler{
 			RequestType: &cluster.GetStatusRequest{},
 			RPC: func(req proto.Message, wss httpapi.WebSocketStream) error {
 				shim := &watchStatusShim{WebSocketStream: wss}
 				return s.WatchStatus(req.(*cluster.GetStatusRequest), shim)
 			},
+			HeartbeatTimeout: s.heartbeatTimeout,
 		},
 	})
 	if err != nil {
@@ -191,6 +409,8 @@ func (s *server) listenAndServe() error {
 	select {
 	case err := <-serveHTTPErr:
 		return errors.WithContext("serve http", err)
+	case err := <-serveMetricsErr:
+		return errors.WithContext("serve metrics", err)
 	case err := <-serveGrpcErr:
 		return errors.WithContext("serve grpc", err)
 	}
@@ -203,9 +423,10 @@ func (s *server) CheckVersion(ctx context.Context, req *cluster.CheckVersionRequ
 	if clientVersionStr == "latest" || version.Version == "latest" {
 		// Running in development, so don't complain about version.
 		return &cluster.CheckVersionResponse{
-			Version:        version.Version,
-			DisplayMessage: "",
-			Action:         cluster.CLIAction_OK,
+			Version:         version.Version,
+			DisplayMessage:  "",
+			Action:          cluster.CLIAction_OK,
+			MaxMessageBytes: int64(s.maxMessageBytes),
 		}, nil
 	}
 
@@ -213,9 +434,10 @@ func (s *server) CheckVersion(ctx context.Context, req *cluster.CheckVersionRequ
 	if err != nil {
 		log.WithError(err).WithField("version", clientVersionStr).Warn("Failed to parse client version")
 		return &cluster.CheckVersionResponse{
-			Version:        version.Version,
-			DisplayMessage: "",
-			Action:         cluster.CLIAction_OK,
+			Version:         version.Version,
+			DisplayMessage:  "",
+			Action:          cluster.CLIAction_OK,
+			MaxMessageBytes: int64(s.maxMessageBytes),
 		}, nil
 	}
 
@@ -223,9 +445,10 @@ func (s *server) CheckVersion(ctx context.Context, req *cluster.CheckVersionRequ
 	if err != nil {
 		log.WithError(err).Warn("Failed to create version constraint")
 		return &cluster.CheckVersionResponse{
-			Version:        version.Version,
-			DisplayMessage: "",
-			Action:         cluster.CLIAction_OK,
+			Version:         version.Version,
+			DisplayMessage:  "",
+			Action:          cluster.CLIAction_OK,
+			MaxMessageBytes: int64(s.maxMessageBytes),
 		}, nil
 	}
 
@@ -235,14 +458,16 @@ func (s *server) CheckVersion(ctx context.Context, req *cluster.CheckVersionRequ
 			DisplayMessage: "CLI version is incompatible with server. " +
 				"Please upgrade by running:\n\n" +
 				"curl -fsSL 'https://blimpup.io/install-selfhosted.sh' | sh\n\n",
-			Action: cluster.CLIAction_EXIT,
+			Action:          cluster.CLIAction_EXIT,
+			MaxMessageBytes: int64(s.maxMessageBytes),
 		}, nil
 	}
 
 	return &cluster.CheckVersionResponse{
-		Version:        version.Version,
-		DisplayMessage: "",
-		Action:         cluster.CLIAction_OK,
+		Version:         version.Version,
+		DisplayMessage:  "",
+		Action:          cluster.CLIAction_OK,
+		MaxMessageBytes: int64(s.maxMessageBytes),
 	}, nil
 }
 
@@ -259,7 +484,7 @@ func (s *server) AttachToSandbox(ctx context.Context, req *cluster.AttachToSandb
 	_, err = s.kubeClient.CoreV1().Namespaces().Get(user.Namespace, metav1.GetOptions{})
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			return &cluster.AttachToSandboxResponse{}, errors.NewFriendlyError("Sandbox does not exist")
+			return &cluster.AttachToSandboxResponse{}, errors.NewCodedError(errors.CodeSandboxNotFound, "Sandbox does not exist")
 		}
 		return &cluster.AttachToSandboxResponse{}, errors.WithContext("get sandbox", err)
 	}
@@ -278,6 +503,7 @@ func (s *server) AttachToSandbox(ctx context.Context, req *cluster.AttachToSandb
 	if err != nil {
 		return &cluster.AttachToSandboxResponse{}, errors.WithContext("get kube credentials", err)
 	}
+	s.recordActivity(user.Namespace)
 
 	return &cluster.AttachToSandboxResponse{
 		NodeAddress:     nodeAddress,
@@ -305,7 +531,7 @@ func (s *server) GetBuildkit(ctx context.Context, req *cluster.GetBuildkitReques
 			return &cluster.GetBuildkitResponse{}, errors.WithContext("get sandbox", err)
 		}
 
-		if err := s.createNamespace(ctx, user.Namespace); err != nil {
+		if err := s.createNamespace(ctx, user); err != nil {
 			return &cluster.GetBuildkitResponse{}, errors.WithContext("create namespace", err)
 		}
 	}
@@ -354,7 +580,7 @@ func (s *server) CreateSandbox(ctx context.Context, req *cluster.CreateSandboxRe
 		return &cluster.CreateSandboxResponse{}, err
 	}
 
-	dcCfg, err := dockercompose.Unmarshal([]byte(req.GetComposeFile()))
+	dcCfg, err := compose.Parse([]byte(req.GetComposeFile()))
 	if err != nil {
 		return &cluster.CreateSandboxResponse{}, errors.WithContext("unmarshal compose file", err)
 	}
@@ -372,29 +598,40 @@ func (s *server) CreateSandbox(ctx context.Context, req *cluster.CreateSandboxRe
 		return &cluster.CreateSandboxResponse{}, errors.WithContext("list namespaces", err)
 	}
 	if len(sandboxes) >= s.maxSandboxes {
-		return &cluster.CreateSandboxResponse{}, errors.NewFriendlyError(
-			"Sorry, the Blimp servers are overloaded right now.\n" +
+		return &cluster.CreateSandboxResponse{}, errors.NewCodedError(errors.CodeClusterOverloaded,
+			"Sorry, the Blimp servers are overloaded right now.\n"+
 				"Please try again later.")
 	}
 
-	composeFileIssues := ValidateComposeFile(dcCfg)
+	composeFileIssues := compose.Validate(dcCfg)
 	if len(composeFileIssues) > 0 {
 		prettyIssues := ""
 		for _, issue := range composeFileIssues {
 			prettyIssues += fmt.Sprintf("- %s\n", issue)
 		}
 
-		err := errors.NewFriendlyError(
-			"We found the following issues with your Docker Compose file:\n" +
-				prettyIssues +
-				"Please fix these and try blimp up again!")
+		err := errors.NewCodedError(errors.CodeInvalidCompose,
+			"We found the following issues with your Docker Compose file:\n%s"+
+				"Please fix these and try blimp up again!", prettyIssues)
+		return &cluster.CreateSandboxResponse{}, err
+	}
+
+	if err := s.checkBilling(ctx, billing.ActionCreate, user.Namespace, user.Name); err != nil {
 		return &cluster.CreateSandboxResponse{}, err
 	}
 
 	namespace := user.Namespace
-	if err := s.createNamespace(ctx, namespace); err != nil {
+	if err := s.createNamespace(ctx, user); err != nil {
 		return &cluster.CreateSandboxResponse{}, errors.WithContext("create namespace", err)
 	}
+	s.recordActivity(namespace)
+
+	// Pick the node for the sandbox's pods. If the sandbox no longer fits on
+	// its current node, this moves its pods to a node that has room.
+	placement, err := s.placeSandbox(namespace, newSandboxRequests(len(dcCfg.Services)))
+	if err != nil {
+		return &cluster.CreateSandboxResponse{}, errors.WithContext("place sandbox", err)
+	}
 
 	// If customer pods are already present in the namespace, don't worry about
 	// creating a reservation pod.
@@ -409,7 +646,7 @@ func (s *server) CreateSandbox(ctx context.Context, req *cluster.CreateSandboxRe
 		// will ultimately be deployed, to make sure that the namespace is
 		// scheduled on a node that ultimately will be able to handle the
 		// workload.
-		if err := s.createReservation(user, len(dcCfg.Services)); err != nil {
+		if err := s.createReservation(user, len(dcCfg.Services), placement); err != nil {
 			return &cluster.CreateSandboxResponse{}, errors.WithContext("deploy reservation", err)
 		}
 
@@ -427,7 +664,7 @@ func (s *server) CreateSandbox(ctx context.Context, req *cluster.CreateSandboxRe
 		}
 	}
 
-	if err := s.createSyncthing(user, req.GetSyncedFolders()); err != nil {
+	if err := s.createSyncthing(user, req, placement); err != nil {
 		return &cluster.CreateSandboxResponse{}, errors.WithContext("deploy syncthing", err)
 	}
 
@@ -435,7 +672,7 @@ func (s *server) CreateSandbox(ctx context.Context, req *cluster.CreateSandboxRe
 		return &cluster.CreateSandboxResponse{}, errors.WithContext("deploy buildkitd", err)
 	}
 
-	if err := s.deployDNS(user); err != nil {
+	if err := s.deployDNS(user, placement); err != nil {
 		return &cluster.CreateSandboxResponse{}, errors.WithContext("deploy dns", err)
 	}
 
@@ -461,17 +698,28 @@ func (s *server) CreateSandbox(ctx context.Context, req *cluster.CreateSandboxRe
 		return &cluster.CreateSandboxResponse{}, errors.WithContext("create Blimp registry credential", err)
 	}
 	creds[RegistryHostname] = blimpRegCred.ToProtobuf()
+
+	// The image cache authenticates users with the same credentials as the
+	// Blimp registry.
+	if ImageCacheHostname != "" {
+		creds[ImageCacheHostname] = blimpRegCred.ToProtobuf()
+	}
 	if err := s.createPodRunnerServiceAccount(namespace, creds); err != nil {
 		return &cluster.CreateSandboxResponse{}, errors.WithContext("create pod runner service account", err)
 	}
 
+	// Start pulling images now so that they're ready by the time the services
+	// are deployed. The pre-pull pods use the pod runner's registry
+	// credentials.
+	go s.prePullImages(user, dcCfg.Services, placement)
+
 	cliCreds, err := s.createCLICreds(ctx, namespace)
 	if err != nil {
 		return &cluster.CreateSandboxResponse{}, errors.WithContext("get kube credentials", err)
 	}
 
 	var featuresMsg string
-	unsupportedFeatures := GetUnsupportedFeatures(dcCfg)
+	unsupportedFeatures := compose.UnsupportedFeatures(dcCfg)
 	if len(unsupportedFeatures) > 0 {
 		featuresMsg = fmt.Sprintf("WARNING: Docker Compose file uses features unsupported by Kelda Blimp: %v\n"+
 			"Blimp will attempt to continue to boot.\n"+
@@ -504,44 +752,200 @@ func (s *server) DeployToSandbox(ctx context.Context, req *cluster.DeployRequest
 		return &cluster.DeployResponse{}, err
 	}
 
-	dcCfg, err := dockercompose.Unmarshal([]byte(req.GetComposeFile()))
+	// Validate the Compose file before saving it so that the user gets the
+	// parse error directly.
+	if _, err := compose.Parse([]byte(req.GetComposeFile())); err != nil {
+		return &cluster.DeployResponse{}, err
+	}
+
+	namespace := user.Namespace
+
+	// Boot the services that were stopped by `blimp pause`, so that the
+	// services that haven't changed don't have to be recreated.
+	if _, err := s.resumeSandbox(namespace); err != nil {
+		return &cluster.DeployResponse{}, errors.WithContext("resume sandbox", err)
+	}
+
+	err = s.sandboxes.apply(namespace, sandboxSpec{
+		Project:             req.GetProject(),
+		ComposeFile:         req.GetComposeFile(),
+		BuiltImages:         req.GetBuiltImages(),
+		KubernetesManifests: req.GetKubernetesManifests(),
+	})
 	if err != nil {
+		return &cluster.DeployResponse{}, errors.WithContext("update sandbox", err)
+	}
+
+	// Reconcile the sandbox directly rather than waiting for the controller
+	// so that deployment errors are returned to the user.
+	if err := s.reconcileSandbox(ctx, namespace, getSandboxObjectName(req.GetProject()), true); err != nil {
 		return &cluster.DeployResponse{}, err
 	}
+	s.recordActivity(namespace)
+	return &cluster.DeployResponse{}, nil
+}
+
+// deploySandbox boots the services described by the sandbox's spec, and
+// removes any services that are no longer in the spec.
+func (s *server) deploySandbox(ctx context.Context, user auth.User, spec sandboxSpec) error {
+	dcCfg, err := compose.Parse([]byte(spec.ComposeFile))
+	if err != nil {
+		return err
+	}
 
 	namespace := user.Namespace
+	manifests, err := parseManifests(namespace, spec.Project, spec.KubernetesManifests, dcCfg.ServiceNames())
+	if err != nil {
+		return err
+	}
+
+	addons, err := s.getAddons(namespace)
+	if err != nil {
+		return errors.WithContext("get add-ons", err)
+	}
+	for _, a := range addons {
+		if contains(dcCfg.ServiceNames(), a.Name) {
+			return errors.NewFriendlyError(
+				"The service %q has the same name as an add-on.\n"+
+					"Rename the service, or delete the add-on with `blimp addons rm %s`.", a.Name, a.Name)
+		}
+	}
+
 	dnsPod, err := s.getPod(ctx, namespace, "dns", podIsReady)
 	if err != nil {
-		return &cluster.DeployResponse{}, errors.WithContext("get dns server's IP", err)
+		return errors.WithContext("get dns server's IP", err)
 	}
 
 	nodeControllerIP, err := node.GetNodeControllerInternalIP(s.kubeClient, dnsPod.Spec.NodeName)
 	if err != nil {
-		return &cluster.DeployResponse{}, errors.WithContext("get node controller's IP", err)
+		return errors.WithContext("get node controller's IP", err)
+	}
+
+	cpuMultiplier, err := s.getCPUMultiplier(namespace)
+	if err != nil {
+		return errors.WithContext("get boost", err)
 	}
 
-	customerPods, configMaps, err := toPods(user, dnsPod.Status.PodIP, nodeControllerIP, dcCfg, req.BuiltImages)
+	placement, err := s.getPlacement(namespace)
 	if err != nil {
-		return &cluster.DeployResponse{}, errors.WithContext("make pod specs", err)
+		return errors.WithContext("get placement", err)
+	}
+
+	egressPolicy, err := s.getEgressPolicy(namespace)
+	if err != nil {
+		return errors.WithContext("get egress policy", err)
+	}
+
+	egressProxyAddr, err := s.deployEgressPolicy(ctx, user, placement, egressPolicy)
+	if err != nil {
+		return errors.WithContext("deploy egress policy", err)
+	}
+
+	customerPods, configMaps, err := compose.ToKubernetes(dcCfg, compose.KubeOptions{
+		User:              user,
+		DNSIP:             dnsPod.Status.PodIP,
+		NodeControllerIP:  nodeControllerIP,
+		BuiltImages:       spec.BuiltImages,
+		ImageCache:        ImageCacheHostname,
+		CPUMultiplier:     cpuMultiplier,
+		Placement:         placement,
+		MeshCompatibility: meshCompatibility,
+		Project:           spec.Project,
+	})
+	if err != nil {
+		return errors.WithContext("make pod specs", err)
+	}
+
+	if s.imageScanner != nil {
+		if err := s.scanImages(ctx, namespace, spec.Project, dcCfg.Services, spec.BuiltImages); err != nil {
+			return err
+		}
+	}
+
+	if err := s.deployMTLSCerts(namespace, dcCfg.Services); err != nil {
+		return errors.WithContext("deploy mtls certificates", err)
+	}
+
+	if err := s.deploySecretEnv(namespace, dcCfg.Services); err != nil {
+		return errors.WithContext("deploy secret environment variables", err)
+	}
+
+	schedules, err := getSchedules(dcCfg.Services)
+	if err != nil {
+		return err
+	}
+
+	faultSources, err := s.deployFaultRules(namespace, spec.Project, dcCfg.Services)
+	if err != nil {
+		return errors.WithContext("deploy fault rules", err)
+	}
+
+	sandboxEnv, err := s.getSandboxEnv(namespace)
+	if err != nil {
+		return errors.WithContext("get sandbox env", err)
+	}
+
+	sandboxHosts := dcCfg.ServiceNames()
+	for _, a := range addons {
+		sandboxHosts = append(sandboxHosts, a.Name)
+	}
+
+	for i, pod := range customerPods {
+		svc := pod.Labels["blimp.service"]
+		if _, ok := faultSources[svc]; ok {
+			addChaosAgent(&customerPods[i], svc)
+		}
+		injectSandboxEnv(&customerPods[i], sandboxEnv)
+		injectAddonEnv(&customerPods[i], addons)
+		if egressProxyAddr != "" {
+			injectEgressProxyEnv(&customerPods[i], egressProxyAddr, sandboxHosts)
+		}
+	}
+
+	// Scheduled services are run by CronJobs rather than booted directly.
+	var cronJobs []batchv1beta1.CronJob
+	var continuousPods []corev1.Pod
+	for _, pod := range customerPods {
+		if schedule, ok := schedules[pod.Labels["blimp.service"]]; ok {
+			cronJobs = append(cronJobs, toCronJob(pod, schedule))
+		} else {
+			continuousPods = append(continuousPods, pod)
+		}
 	}
+	customerPods = continuousPods
 
 	// TODO: Garbage collect config maps.
 	for _, configMap := range configMaps {
 		if err := kube.DeployConfigMap(s.kubeClient, configMap); err != nil {
-			return &cluster.DeployResponse{}, errors.WithContext("create configmap", err)
+			return errors.WithContext("create configmap", err)
 		}
 	}
 
+	if err := s.deployManifests(namespace, spec.Project, manifests); err != nil {
+		return errors.WithContext("deploy kubernetes manifests", err)
+	}
+
+	if err := s.deployCronJobs(namespace, spec.Project, cronJobs); err != nil {
+		return errors.WithContext("deploy scheduled services", err)
+	}
+
 	log.WithField("namespace", namespace).
 		WithField("numPods", len(customerPods)).
 		Info("Deploying customer pods")
-	if err := s.deployCustomerPods(namespace, customerPods); err != nil {
-		return &cluster.DeployResponse{}, errors.WithContext("boot customer pods", err)
+	if err := s.deployCustomerPods(namespace, spec.Project, customerPods,
+		getSurgeableServices(dcCfg.Services)); err != nil {
+		return errors.WithContext("boot customer pods", err)
 	}
-	return &cluster.DeployResponse{}, nil
+	s.recordActivity(namespace)
+	return nil
 }
 
-func (s *server) createNamespace(ctx context.Context, namespace string) error {
+func (s *server) createNamespace(ctx context.Context, user auth.User) error {
+	if err := s.namespaces.claim(user); err != nil {
+		return err
+	}
+
+	namespace := user.Namespace
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: namespace,
@@ -550,6 +954,9 @@ func (s *server) createNamespace(ctx context.Context, namespace string) error {
 				"namespace":     namespace,
 				"blimp.sandbox": "true",
 			},
+			Annotations: map[string]string{
+				kube.OwnerAnnotation: user.Name,
+			},
 		},
 	}
 
@@ -622,6 +1029,12 @@ func (s *server) createNamespace(ctx context.Context, namespace string) error {
 		return errors.WithContext("get network policy", err)
 	}
 
+	// Let the node controllers manage the pods for the emulated Docker
+	// socket, and record the progress of volume initialization.
+	if err := kube.DeployRoleBinding(s.kubeClient, node.SandboxRoleBinding(namespace)); err != nil {
+		return errors.WithContext("deploy node controller role binding", err)
+	}
+
 	if err := volume.CreatePVC(ctx, s.kubeClient, namespace); err != nil {
 		return errors.WithContext("create persistent volume claim", err)
 	}
@@ -657,11 +1070,11 @@ func (s *server) getPod(ctx context.Context, namespace, name string, cond podCon
 	return pod, nil
 }
 
-func (s *server) createReservation(user auth.User, numServices int) error {
+func (s *server) createReservation(user auth.User, numServices int, placement affinity.Placement) error {
 	cpu := resource.MustParse(
-		fmt.Sprintf("%d%s", cpuRequest*numServices, cpuRequestUnits))
+		fmt.Sprintf("%d%s", compose.CPURequest*numServices, compose.CPURequestUnits))
 	memory := resource.MustParse(
-		fmt.Sprintf("%d%s", memoryRequest*numServices, memoryRequestUnits))
+		fmt.Sprintf("%d%s", compose.MemoryRequest*numServices, compose.MemoryRequestUnits))
 
 	pod := corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -686,9 +1099,11 @@ func (s *server) createReservation(user auth.User, numServices int) error {
 					},
 				},
 			}},
-			Affinity: affinity.ForUser(user),
+			Affinity:    affinity.ForUser(user, placement),
+			Tolerations: affinity.Tolerations(),
 		},
 	}
+	excludeFromMesh(&pod)
 
 	if err := kube.DeployPod(s.kubeClient, pod, kube.DeployPodOptions{}); err != nil {
 		return errors.WithContext("deploy pod", err)
@@ -696,17 +1111,30 @@ func (s *server) createReservation(user auth.User, numServices int) error {
 	return nil
 }
 
-func (s *server) createSyncthing(user auth.User, syncedFolders map[string]string) error {
+func (s *server) createSyncthing(user auth.User, req *cluster.CreateSandboxRequest,
+	placement affinity.Placement) error {
+
 	mount := corev1.VolumeMount{
 		Name:      volume.PersistentVolume.Name,
 		MountPath: "/pv",
 	}
 
 	idPathMap := map[string]string{}
-	for id, src := range syncedFolders {
+	for id, src := range req.GetSyncedFolders() {
 		idPathMap[id] = filepath.Join(mount.MountPath, volume.BindVolumeDir(src))
 	}
 
+	args := syncthing.MapToArgs(idPathMap)
+	args = append(args, syncthing.DeltaToArgs(req.GetDeltaSyncFolders())...)
+	args = append(args, syncthing.PermissionsToArgs(
+		req.GetSyncFolderOwners(), req.GetInferExecutableFolders())...)
+
+	lineEndingRules := map[string][]string{}
+	for id, patterns := range req.GetLineEndingRules() {
+		lineEndingRules[id] = patterns.GetPatterns()
+	}
+	args = append(args, syncthing.LineEndingsToArgs(lineEndingRules)...)
+
 	pod := corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: user.Namespace,
@@ -720,7 +1148,7 @@ func (s *server) createSyncthing(user auth.User, syncedFolders map[string]string
 			Containers: []corev1.Container{{
 				Name:         kube.PodNameSyncthing,
 				Image:        version.SyncthingImage,
-				Args:         syncthing.MapToArgs(idPathMap),
+				Args:         args,
 				VolumeMounts: []corev1.VolumeMount{mount},
 				Resources: corev1.ResourceRequirements{
 					Limits: corev1.ResourceList{
@@ -733,10 +1161,12 @@ func (s *server) createSyncthing(user auth.User, syncedFolders map[string]string
 					},
 				},
 			}},
-			Volumes:  []corev1.Volume{volume.PersistentVolume},
-			Affinity: affinity.ForUser(user),
+			Volumes:     []corev1.Volume{volume.PersistentVolume},
+			Affinity:    affinity.ForUser(user, placement),
+			Tolerations: affinity.Tolerations(),
 		},
 	}
+	excludeFromMesh(&pod)
 
 	opts := kube.DeployPodOptions{
 		Sanitizers: []kube.Sanitizer{kube.SanitizeIgnoreNodeAffinity},
@@ -747,7 +1177,7 @@ func (s *server) createSyncthing(user auth.User, syncedFolders map[string]string
 	return nil
 }
 
-func (s *server) deployDNS(user auth.User) error {
+func (s *server) deployDNS(user auth.User, placement affinity.Placement) error {
 	namespace := user.Namespace
 	serviceAccount := corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
@@ -808,10 +1238,12 @@ func (s *server) deployDNS(user auth.User) error {
 					},
 				},
 			}},
-			Affinity:           affinity.ForUser(user),
+			Affinity:           affinity.ForUser(user, placement),
+			Tolerations:        affinity.Tolerations(),
 			ServiceAccountName: serviceAccount.Name,
 		},
 	}
+	excludeFromMesh(&pod)
 
 	opts := kube.DeployPodOptions{
 		Sanitizers: []kube.Sanitizer{kube.SanitizeIgnoreNodeAffinity},
@@ -851,10 +1283,12 @@ func (s *server) createCLICreds(ctx context.Context, namespace string) (cluster.
 			},
 
 			// Get needed for `blimp cp`. Get and watch needed for `blimp logs`.
+			// List needed for `blimp logs` to find the runs of scheduled
+			// services.
 			{
 				APIGroups: []string{""},
 				Resources: []string{"pods"},
-				Verbs:     []string{"get", "watch"},
+				Verbs:     []string{"get", "list", "watch"},
 			},
 		},
 	}
@@ -965,7 +1399,11 @@ func (s *server) createPodRunnerServiceAccount(namespace string,
 	return kube.DeployServiceAccount(s.kubeClient, serviceAccount)
 }
 
-func (s *server) deployCustomerPods(namespace string, desired []corev1.Pod) error {
+// deployCustomerPods deploys the desired pods, and deletes the other pods in
+// the project. Changed pods for services in `surgeable` are updated without
+// downtime.
+func (s *server) deployCustomerPods(namespace, project string, desired []corev1.Pod,
+	surgeable map[string]struct{}) error {
 	currPods, err := s.kubeClient.CoreV1().Pods(namespace).List(metav1.ListOptions{
 		LabelSelector: "blimp.customerPod=true",
 	})
@@ -973,23 +1411,57 @@ func (s *server) deployCustomerPods(namespace string, desired []corev1.Pod) erro
 		return errors.WithContext("list", err)
 	}
 
-	// TODO: Parallelize
+	// Pods are named after their service, so services in different projects
+	// could collide if one project's name is a prefix of another's.
+	for _, pod := range desired {
+		for _, curr := range currPods.Items {
+			if curr.Name == pod.Name && curr.Labels[projectLabel] != project {
+				return errors.NewFriendlyError(
+					"The service %q conflicts with a service in another project in the sandbox.\n"+
+						"Rename the service or the project, or remove the other project with `blimp down`.",
+					pod.Labels["blimp.service"])
+			}
+		}
+	}
+
+	// Surge updates wait for the new version of the pod to boot, so they're
+	// run in parallel.
+	// TODO: Parallelize the other deploys.
+	var surgeUpdates errgroup.Group
 	desiredNames := map[string]struct{}{}
 	for _, pod := range desired {
+		pod := pod
 		opts := kube.DeployPodOptions{
 			Sanitizers: []kube.Sanitizer{
 				kube.SanitizeIgnoreInitContainerImages,
 				kube.SanitizeIgnoreNodeAffinity,
 			},
 		}
+		desiredNames[pod.Name] = struct{}{}
+
+		if _, ok := surgeable[pod.Labels["blimp.service"]]; ok {
+			surgeUpdates.Go(func() error {
+				return s.surgeDeployPod(pod, opts)
+			})
+			continue
+		}
+
 		if err := kube.DeployPod(s.kubeClient, pod, opts); err != nil {
 			return errors.WithContext("create", err)
 		}
-		desiredNames[pod.Name] = struct{}{}
+	}
+
+	if err := surgeUpdates.Wait(); err != nil {
+		return errors.WithContext("update", err)
 	}
 
 	// Delete any stale pods.
+	inProject := projectSelector(project)
 	for _, pod := range currPods.Items {
+		if !inProject.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
 		if _, ok := desiredNames[pod.Name]; !ok {
 			if err := kube.DeletePod(s.kubeClient, pod.Namespace, pod.Name); err != nil {
 				return errors.WithContext("delete", err)
@@ -999,6 +1471,28 @@ func (s *server) deployCustomerPods(namespace string, desired []corev1.Pod) erro
 	return nil
 }
 
+// copyCustomerPod returns a pod that can be deployed in place of the given
+// running pod.
+func copyCustomerPod(pod *corev1.Pod) corev1.Pod {
+	newPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Labels:    pod.Labels,
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	for k, v := range pod.Annotations {
+		if metadata.IsCustomPodAnnotation(pod.Annotations, k) {
+			if newPod.Annotations == nil {
+				newPod.Annotations = map[string]string{}
+			}
+			newPod.Annotations[k] = v
+		}
+	}
+	return newPod
+}
+
 func (s *server) DeleteSandbox(ctx context.Context, req *cluster.DeleteSandboxRequest) (
 	*cluster.DeleteSandboxResponse, error) {
 	user, err := clusterAuth.AuthorizeRequest(clusterAuth.GetAuth(req))
@@ -1009,29 +1503,49 @@ func (s *server) DeleteSandbox(ctx context.Context, req *cluster.DeleteSandboxRe
 	_, err = s.kubeClient.CoreV1().Namespaces().Get(user.Namespace, metav1.GetOptions{})
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			return &cluster.DeleteSandboxResponse{}, errors.NewFriendlyError("Sandbox does not exist")
+			return &cluster.DeleteSandboxResponse{}, errors.NewCodedError(errors.CodeSandboxNotFound, "Sandbox does not exist")
 		}
 		return &cluster.DeleteSandboxResponse{}, errors.WithContext("get sandbox", err)
 	}
 
-	if req.DeleteVolumes {
-		if err := volume.PermanentlyDeletePVC(s.kubeClient, user.Namespace); err != nil {
-			return &cluster.DeleteSandboxResponse{}, errors.WithContext("delete persistent volume", err)
+	// Only remove the project's services if other projects are still
+	// deployed. Volumes are only deleted along with the entire sandbox.
+	if project := req.GetProject(); project != "" {
+		deleted, err := s.deleteProject(user.Namespace, project)
+		if err != nil {
+			return &cluster.DeleteSandboxResponse{}, errors.WithContext("delete project", err)
+		}
+		if deleted {
+			return &cluster.DeleteSandboxResponse{ProjectOnly: true}, nil
+		}
+	}
+
+	if err := s.deleteSandbox(user.Namespace, req.DeleteVolumes); err != nil {
+		return &cluster.DeleteSandboxResponse{}, err
+	}
+
+	return &cluster.DeleteSandboxResponse{}, nil
+}
+
+func (s *server) deleteSandbox(namespace string, deleteVolumes bool) error {
+	if deleteVolumes {
+		if err := volume.PermanentlyDeletePVC(s.kubeClient, namespace); err != nil {
+			return errors.WithContext("delete persistent volume", err)
 		}
 	}
 
 	// Give the pods 10 seconds to shut down (rather than the default of 30
 	// seconds). This gives applications a chance to flush their state to disk
 	// to avoid data loss/corruption in volumes.
-	pods, err := s.kubeClient.CoreV1().Pods(user.Namespace).List(metav1.ListOptions{})
+	pods, err := s.kubeClient.CoreV1().Pods(namespace).List(metav1.ListOptions{})
 	if err == nil {
 		for _, pod := range pods.Items {
 			ten := int64(10)
-			err = s.kubeClient.CoreV1().Pods(user.Namespace).Delete(pod.Name, &metav1.DeleteOptions{
+			err = s.kubeClient.CoreV1().Pods(namespace).Delete(pod.Name, &metav1.DeleteOptions{
 				GracePeriodSeconds: &ten,
 			})
 			if err != nil {
-				log.WithField("namespace", user.Namespace).
+				log.WithField("namespace", namespace).
 					WithField("pod", pod.Name).
 					WithError(err).
 					Warn("Failed to delete pod during sandbox teardown")
@@ -1039,11 +1553,22 @@ func (s *server) DeleteSandbox(ctx context.Context, req *cluster.DeleteSandboxRe
 		}
 	}
 
-	if err := s.kubeClient.CoreV1().Namespaces().Delete(user.Namespace, nil); err != nil {
-		return &cluster.DeleteSandboxResponse{}, err
+	if err := s.kubeClient.CoreV1().Namespaces().Delete(namespace, nil); err != nil {
+		return err
 	}
 
-	return &cluster.DeleteSandboxResponse{}, nil
+	// The namespace can be claimed by another user once the owner's data is
+	// gone. Sandboxes whose volumes are kept stay claimed so that the
+	// volumes can't be accessed by anyone else.
+	if deleteVolumes {
+		if err := s.deleteScopedTokens(namespace); err != nil {
+			return err
+		}
+		if err := s.namespaces.release(namespace); err != nil {
+			return errors.WithContext("release namespace", err)
+		}
+	}
+	return nil
 }
 
 func (s *server) GetStatus(ctx context.Context, req *cluster.GetStatusRequest) (*cluster.GetStatusResponse, error) {
@@ -1057,18 +1582,90 @@ func (s *server) GetStatus(ctx context.Context, req *cluster.GetStatusRequest) (
 		return &cluster.GetStatusResponse{}, err
 	}
 
+	status = filterServices(status, req.GetServices())
 	return &cluster.GetStatusResponse{Status: &status}, nil
 }
 
+// GetStatuses returns the status of multiple sandboxes, so that dashboards can
+// show the sandboxes of an entire team without making a request per sandbox.
+func (s *server) GetStatuses(ctx context.Context, req *cluster.GetStatusesRequest) (
+	*cluster.GetStatusesResponse, error) {
+	user, err := clusterAuth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.GetStatusesResponse{}, err
+	}
+
+	// Sandboxes are shared by giving out tokens with the `status` scope.
+	allowed := map[string]struct{}{user.Namespace: {}}
+	for _, token := range req.GetTokens() {
+		if _, err := s.verifyScopedToken(token, getStatusMethod); err != nil {
+			return &cluster.GetStatusesResponse{}, err
+		}
+
+		namespace, _, _, _ := clusterAuth.ParseScopedToken(token)
+		allowed[namespace] = struct{}{}
+	}
+
+	var namespaces []string
+	for _, namespace := range strs.Unique(req.GetNamespaces()) {
+		if _, ok := allowed[namespace]; !ok {
+			return &cluster.GetStatusesResponse{}, errors.NewCodedError(errors.CodeUnauthorized,
+				"You don't have access to %s. Ask its owner for a token with the `status` scope.",
+				namespace)
+		}
+		namespaces = append(namespaces, namespace)
+	}
+
+	statuses, err := s.statusFetcher.GetMany(namespaces)
+	if err != nil {
+		return &cluster.GetStatusesResponse{}, err
+	}
+
+	statusesPB := map[string]*cluster.SandboxStatus{}
+	for namespace, status := range statuses {
+		status := status
+		statusesPB[namespace] = &status
+	}
+	return &cluster.GetStatusesResponse{Statuses: statusesPB}, nil
+}
+
 func (s *server) WatchStatus(req *cluster.GetStatusRequest, stream cluster.Manager_WatchStatusServer) error {
 	user, err := clusterAuth.AuthorizeRequest(clusterAuth.GetAuth(req))
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// Stop watching once the client disconnects, or stops responding to
+	// heartbeats.
+	ctx, cancel := context.WithCancel(stream.Context())
 	defer cancel()
-	trig := s.statusFetcher.Watch(ctx, user.Namespace)
+	trig := s.statusFetcher.Watch(ctx, user.Namespace, req.GetServices()...)
+
+	sessionID, missed, resumed, err := s.statusSessions.resume(
+		req.GetResumeToken(), user.Namespace, req.GetServices())
+	if err != nil {
+		return err
+	}
+	defer s.statusSessions.detach(sessionID)
+
+	// Replay the updates that the client missed while it was disconnected.
+	for _, update := range missed {
+		status := update.status
+		err := stream.Send(&cluster.GetStatusResponse{
+			Status:      &status,
+			ResumeToken: makeResumeToken(sessionID, update.seq),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var lastSent *cluster.SandboxStatus
+	if resumed {
+		if latest, ok := s.statusSessions.latest(sessionID); ok {
+			lastSent = &latest
+		}
+	}
 
 	for {
 		status, err := s.statusFetcher.Get(user.Namespace)
@@ -1076,11 +1673,26 @@ func (s *server) WatchStatus(req *cluster.GetStatusRequest, stream cluster.Manag
 			return err
 		}
 
-		if err := stream.Send(&cluster.GetStatusResponse{Status: &status}); err != nil {
-			return err
+		// When watching specific services, or resuming a watch, don't send
+		// updates unless the statuses actually changed.
+		status = filterServices(status, req.GetServices())
+		skipUnchanged := len(req.GetServices()) != 0 || resumed
+		if !skipUnchanged || lastSent == nil || !proto.Equal(lastSent, &status) {
+			err := stream.Send(&cluster.GetStatusResponse{
+				Status:      &status,
+				ResumeToken: s.statusSessions.add(sessionID, status),
+			})
+			if err != nil {
+				return err
+			}
+			lastSent = &status
 		}
 
-		<-trig
+		select {
+		case <-trig:
+		case <-ctx.Done():
+			return nil
+		}
 	}
 }
 
@@ -1097,28 +1709,14 @@ func (s *server) Restart(ctx context.Context, req *cluster.RestartRequest) (*clu
 		return &cluster.RestartResponse{}, errors.WithContext("get current pod", err)
 	}
 
-	newPod := corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podName,
-			Namespace: user.Namespace,
-			Labels:    currPod.Labels,
-		},
-		Spec: currPod.Spec,
-	}
-	for k, v := range currPod.Annotations {
-		if contains(metadata.CustomPodAnnotations, k) {
-			if newPod.Annotations == nil {
-				newPod.Annotations = map[string]string{}
-			}
-			newPod.Annotations[k] = v
-		}
-	}
+	newPod := copyCustomerPod(currPod)
 
 	// Since we are setting ForceRestart, we don't both adding any Sanitizers here.
 	err = kube.DeployPod(s.kubeClient, newPod, kube.DeployPodOptions{ForceRestart: true})
 	if err != nil {
 		return &cluster.RestartResponse{}, errors.WithContext("deploy new pod", err)
 	}
+	s.recordActivity(user.Namespace)
 
 	return &cluster.RestartResponse{}, nil
 }
@@ -1274,35 +1872,43 @@ func (s *server) Expose(ctx context.Context, req *cluster.ExposeRequest) (
 	_, err = namespacesClient.Get(user.Namespace, metav1.GetOptions{})
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			return &cluster.ExposeResponse{}, errors.NewFriendlyError("Sandbox does not exist")
+			return &cluster.ExposeResponse{}, errors.NewCodedError(errors.CodeSandboxNotFound, "Sandbox does not exist")
 		}
 		return &cluster.ExposeResponse{}, errors.WithContext("get sandbox", err)
 	}
 
-	exposeInfo := expose.ExposeInfo{
+	link, err := s.expose(user.Namespace, expose.ExposeInfo{
 		Service: req.Service,
 		Port:    int(req.Port),
+	})
+	if err != nil {
+		return &cluster.ExposeResponse{}, errors.WithContext("update sandbox", err)
 	}
+	return &cluster.ExposeResponse{Link: link}, nil
+}
 
+// expose creates a link to the given service, and returns its URL.
+func (s *server) expose(namespace string, exposeInfo expose.ExposeInfo) (string, error) {
 	// Secret should be 8 hex digits, so between 0x00000000 and 0xffffffff
 	secretNum, err := rand.Int(rand.Reader, big.NewInt(0x100000000))
 	if err != nil {
-		return &cluster.ExposeResponse{}, errors.WithContext("generate secret", err)
+		return "", errors.WithContext("generate secret", err)
 	}
 	secret := fmt.Sprintf("%08x", secretNum)
 
+	namespacesClient := s.kubeClient.CoreV1().Namespaces()
 	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		namespace, err := namespacesClient.Get(user.Namespace, metav1.GetOptions{})
+		ns, err := namespacesClient.Get(namespace, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 
-		if namespace.Annotations == nil {
-			namespace.Annotations = map[string]string{}
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
 		}
 
 		annotation := expose.ExposeAnnotation{}
-		annotationJson, ok := namespace.Annotations[kube.ExposeAnnotation]
+		annotationJson, ok := ns.Annotations[kube.ExposeAnnotation]
 		if ok {
 			annotation, err = expose.ParseJsonAnnotation(annotationJson)
 			if err != nil {
@@ -1310,23 +1916,30 @@ func (s *server) Expose(ctx context.Context, req *cluster.ExposeRequest) (
 			}
 		}
 
+		// Drop expired share links so that they don't accumulate.
+		for token, info := range annotation {
+			if info.Expired(time.Now()) {
+				delete(annotation, token)
+			}
+		}
 		annotation[secret] = exposeInfo
 		annotationJson, err = annotation.ToJson()
 		if err != nil {
 			return err
 		}
-		namespace.Annotations[kube.ExposeAnnotation] = annotationJson
+		ns.Annotations[kube.ExposeAnnotation] = annotationJson
 
-		_, err = namespacesClient.Update(namespace)
+		_, err = namespacesClient.Update(ns)
 		return err
 	})
 	if err != nil {
-		return &cluster.ExposeResponse{}, errors.WithContext("update sandbox", err)
+		return "", err
 	}
+	return exposeLink(namespace, secret), nil
+}
 
-	return &cluster.ExposeResponse{
-		Link: fmt.Sprintf("https://%s%s.%s/", user.Namespace, secret, LinkProxyBaseHostname),
-	}, nil
+func exposeLink(namespace, secret string) string {
+	return fmt.Sprintf("https://%s%s.%s/", namespace, secret, LinkProxyBaseHostname)
 }
 
 func (s *server) Unexpose(ctx context.Context, req *cluster.UnexposeRequest) (
@@ -1340,7 +1953,7 @@ func (s *server) Unexpose(ctx context.Context, req *cluster.UnexposeRequest) (
 	_, err = namespacesClient.Get(user.Namespace, metav1.GetOptions{})
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			return &cluster.UnexposeResponse{}, errors.NewFriendlyError("Sandbox does not exist")
+			return &cluster.UnexposeResponse{}, errors.NewCodedError(errors.CodeSandboxNotFound, "Sandbox does not exist")
 		}
 		return &cluster.UnexposeResponse{}, errors.WithContext("get sandbox", err)
 	}
@@ -1367,41 +1980,6 @@ func (s *server) Unexpose(ctx context.Context, req *cluster.UnexposeRequest) (
 	return &cluster.UnexposeResponse{}, nil
 }
 
-func toPods(
-	user auth.User,
-	dnsIP,
-	nodeControllerIP string,
-	cfg composeTypes.Project,
-	builtImages map[string]string,
-) (
-	pods []corev1.Pod,
-	configMaps []corev1.ConfigMap,
-	err error,
-) {
-	if len(cfg.Services) > MaxServices {
-		return nil, nil, errors.NewFriendlyError(
-			"Blimp supports a maximum of %d services, but %d are defined.",
-			MaxServices, len(cfg.Services))
-	}
-
-	b, err := newPodBuilder(user, dnsIP, nodeControllerIP, builtImages, cfg.Services, cfg.Volumes)
-	if err != nil {
-		return nil, nil, errors.WithContext("make pod builder", err)
-	}
-
-	for _, svc := range cfg.Services {
-		p, cm, err := b.ToPod(svc)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		pods = append(pods, p)
-		configMaps = append(configMaps, cm...)
-	}
-
-	return pods, configMaps, nil
-}
-
 type podCondition func(*corev1.Pod) bool
 
 func podIsReady(pod *corev1.Pod) bool {
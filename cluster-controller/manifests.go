@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// manifestLabel marks the objects that were deployed from the Kubernetes
+// manifests passed to `blimp up -k`, so that they're deleted once they're
+// removed from the manifests.
+const manifestLabel = "blimp.manifest"
+
+// manifestObjects contains the objects defined by Kubernetes manifests. Only
+// namespaced objects that can't affect other sandboxes are supported.
+type manifestObjects struct {
+	configMaps   []corev1.ConfigMap
+	secrets      []corev1.Secret
+	services     []corev1.Service
+	deployments  []appsv1.Deployment
+	statefulSets []appsv1.StatefulSet
+	jobs         []batchv1.Job
+}
+
+// parseManifests parses and validates the given Kubernetes YAML files, and
+// moves the objects into the sandbox's namespace. The pods created by
+// workloads are labeled with the workload's name so that they show up in
+// `blimp ps` like Compose services.
+func parseManifests(namespace, project string, manifests []string, composeServices []string) (
+	manifestObjects, error) {
+	workloadNames := map[string]struct{}{}
+	for _, svc := range composeServices {
+		workloadNames[svc] = struct{}{}
+	}
+
+	var objs manifestObjects
+	addWorkload := func(kind string, meta *metav1.ObjectMeta, template *corev1.PodTemplateSpec) error {
+		if _, ok := workloadNames[meta.Name]; ok {
+			return errors.NewFriendlyError(
+				"The %s %q in the Kubernetes manifests has the same name as another service.\n"+
+					"Services must have unique names so that their statuses can be distinguished.",
+				kind, meta.Name)
+		}
+		workloadNames[meta.Name] = struct{}{}
+
+		if err := validateManifestPodSpec(kind, meta.Name, template.Spec); err != nil {
+			return err
+		}
+
+		if template.Labels == nil {
+			template.Labels = map[string]string{}
+		}
+		template.Labels["blimp.service"] = meta.Name
+		template.Labels[manifestLabel] = "true"
+		setProjectLabel(&template.ObjectMeta, project)
+		return nil
+	}
+
+	for _, manifest := range manifests {
+		reader := yaml.NewYAMLReader(bufio.NewReader(strings.NewReader(manifest)))
+		for {
+			doc, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return manifestObjects{}, errors.NewFriendlyError(
+					"Failed to read Kubernetes manifest: %s", err)
+			}
+
+			if strings.TrimSpace(string(doc)) == "" {
+				continue
+			}
+
+			obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(doc, nil, nil)
+			if err != nil {
+				return manifestObjects{}, errors.NewFriendlyError(
+					"Failed to parse Kubernetes manifest: %s", err)
+			}
+
+			switch obj := obj.(type) {
+			case *corev1.ConfigMap:
+				setManifestMetadata(&obj.ObjectMeta, namespace, project)
+				objs.configMaps = append(objs.configMaps, *obj)
+			case *corev1.Secret:
+				setManifestMetadata(&obj.ObjectMeta, namespace, project)
+				objs.secrets = append(objs.secrets, *obj)
+			case *corev1.Service:
+				if obj.Spec.Type != "" && obj.Spec.Type != corev1.ServiceTypeClusterIP {
+					return manifestObjects{}, errors.NewFriendlyError(
+						"Service %q has type %s, but only ClusterIP services are supported.\n"+
+							"Use `ports` in the Compose file to access services from your machine.",
+						obj.Name, obj.Spec.Type)
+				}
+				if len(obj.Spec.ExternalIPs) != 0 {
+					return manifestObjects{}, errors.NewFriendlyError(
+						"Service %q can't have external IPs.", obj.Name)
+				}
+				setManifestMetadata(&obj.ObjectMeta, namespace, project)
+				objs.services = append(objs.services, *obj)
+			case *appsv1.Deployment:
+				if err := addWorkload("Deployment", &obj.ObjectMeta, &obj.Spec.Template); err != nil {
+					return manifestObjects{}, err
+				}
+				setManifestMetadata(&obj.ObjectMeta, namespace, project)
+				objs.deployments = append(objs.deployments, *obj)
+			case *appsv1.StatefulSet:
+				if err := addWorkload("StatefulSet", &obj.ObjectMeta, &obj.Spec.Template); err != nil {
+					return manifestObjects{}, err
+				}
+				setManifestMetadata(&obj.ObjectMeta, namespace, project)
+				objs.statefulSets = append(objs.statefulSets, *obj)
+			case *batchv1.Job:
+				if err := addWorkload("Job", &obj.ObjectMeta, &obj.Spec.Template); err != nil {
+					return manifestObjects{}, err
+				}
+				setManifestMetadata(&obj.ObjectMeta, namespace, project)
+				objs.jobs = append(objs.jobs, *obj)
+			default:
+				return manifestObjects{}, errors.NewFriendlyError(
+					"Kubernetes manifests can't contain %s objects.\n"+
+						"The supported kinds are ConfigMap, Secret, Service, Deployment, StatefulSet, and Job.",
+					gvk.Kind)
+			}
+		}
+	}
+	return objs, nil
+}
+
+func setManifestMetadata(meta *metav1.ObjectMeta, namespace, project string) {
+	meta.Namespace = namespace
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	meta.Labels[manifestLabel] = "true"
+	setProjectLabel(meta, project)
+}
+
+// validateManifestPodSpec checks that the pod doesn't request access to the
+// underlying node, since the node is shared with other sandboxes.
+func validateManifestPodSpec(kind, name string, spec corev1.PodSpec) error {
+	fail := func(reason string) error {
+		return errors.NewFriendlyError("The %s %q can't %s.", kind, name, reason)
+	}
+
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		return fail("use the host's namespaces")
+	}
+
+	for _, vol := range spec.Volumes {
+		if vol.HostPath != nil {
+			return fail("mount host paths")
+		}
+	}
+
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		if sc := c.SecurityContext; sc != nil {
+			if sc.Privileged != nil && *sc.Privileged {
+				return fail("run privileged containers")
+			}
+			if sc.Capabilities != nil && len(sc.Capabilities.Add) != 0 {
+				return fail("add capabilities")
+			}
+		}
+
+		for _, port := range c.Ports {
+			if port.HostPort != 0 {
+				return fail("use host ports")
+			}
+		}
+	}
+	return nil
+}
+
+// deployManifests deploys the objects, and deletes the project's objects that
+// were removed from the manifests since the last deploy.
+func (s *server) deployManifests(namespace, project string, objs manifestObjects) error {
+	desired := map[string]struct{}{}
+	for _, configMap := range objs.configMaps {
+		if err := kube.DeployConfigMap(s.kubeClient, configMap); err != nil {
+			return err
+		}
+		desired["ConfigMap/"+configMap.Name] = struct{}{}
+	}
+	for _, secret := range objs.secrets {
+		if err := kube.DeploySecret(s.kubeClient, secret); err != nil {
+			return err
+		}
+		desired["Secret/"+secret.Name] = struct{}{}
+	}
+	for _, service := range objs.services {
+		if err := kube.DeployService(s.kubeClient, service); err != nil {
+			return err
+		}
+		desired["Service/"+service.Name] = struct{}{}
+	}
+	for _, deployment := range objs.deployments {
+		if err := kube.DeployDeployment(s.kubeClient, deployment); err != nil {
+			return err
+		}
+		desired["Deployment/"+deployment.Name] = struct{}{}
+	}
+	for _, statefulSet := range objs.statefulSets {
+		if err := kube.DeployStatefulSet(s.kubeClient, statefulSet); err != nil {
+			return err
+		}
+		desired["StatefulSet/"+statefulSet.Name] = struct{}{}
+	}
+	for _, job := range objs.jobs {
+		if err := kube.DeployJob(s.kubeClient, job); err != nil {
+			return err
+		}
+		desired["Job/"+job.Name] = struct{}{}
+	}
+
+	return s.deleteStaleManifestObjects(namespace, project, desired)
+}
+
+func (s *server) deleteStaleManifestObjects(namespace, project string, desired map[string]struct{}) error {
+	listOpts := metav1.ListOptions{
+		LabelSelector: manifestLabel + "=true," + projectSelector(project).String(),
+	}
+
+	// Delete the pods owned by workloads as well.
+	background := metav1.DeletePropagationBackground
+	deleteOpts := &metav1.DeleteOptions{PropagationPolicy: &background}
+
+	var stale []string
+	deleteIfStale := func(kind, name string, deleteFn func(string, *metav1.DeleteOptions) error) error {
+		if _, ok := desired[kind+"/"+name]; ok {
+			return nil
+		}
+
+		stale = append(stale, kind+"/"+name)
+		return deleteFn(name, deleteOpts)
+	}
+
+	coreClient := s.kubeClient.CoreV1()
+	appsClient := s.kubeClient.AppsV1()
+	batchClient := s.kubeClient.BatchV1()
+
+	configMaps, err := coreClient.ConfigMaps(namespace).List(listOpts)
+	if err != nil {
+		return errors.WithContext("list configmaps", err)
+	}
+	for _, obj := range configMaps.Items {
+		if err := deleteIfStale("ConfigMap", obj.Name, coreClient.ConfigMaps(namespace).Delete); err != nil {
+			return errors.WithContext("delete configmap", err)
+		}
+	}
+
+	secrets, err := coreClient.Secrets(namespace).List(listOpts)
+	if err != nil {
+		return errors.WithContext("list secrets", err)
+	}
+	for _, obj := range secrets.Items {
+		if err := deleteIfStale("Secret", obj.Name, coreClient.Secrets(namespace).Delete); err != nil {
+			return errors.WithContext("delete secret", err)
+		}
+	}
+
+	services, err := coreClient.Services(namespace).List(listOpts)
+	if err != nil {
+		return errors.WithContext("list services", err)
+	}
+	for _, obj := range services.Items {
+		if err := deleteIfStale("Service", obj.Name, coreClient.Services(namespace).Delete); err != nil {
+			return errors.WithContext("delete service", err)
+		}
+	}
+
+	deployments, err := appsClient.Deployments(namespace).List(listOpts)
+	if err != nil {
+		return errors.WithContext("list deployments", err)
+	}
+	for _, obj := range deployments.Items {
+		if err := deleteIfStale("Deployment", obj.Name, appsClient.Deployments(namespace).Delete); err != nil {
+			return errors.WithContext("delete deployment", err)
+		}
+	}
+
+	statefulSets, err := appsClient.StatefulSets(namespace).List(listOpts)
+	if err != nil {
+		return errors.WithContext("list statefulsets", err)
+	}
+	for _, obj := range statefulSets.Items {
+		if err := deleteIfStale("StatefulSet", obj.Name, appsClient.StatefulSets(namespace).Delete); err != nil {
+			return errors.WithContext("delete statefulset", err)
+		}
+	}
+
+	jobs, err := batchClient.Jobs(namespace).List(listOpts)
+	if err != nil {
+		return errors.WithContext("list jobs", err)
+	}
+	for _, obj := range jobs.Items {
+		if err := deleteIfStale("Job", obj.Name, batchClient.Jobs(namespace).Delete); err != nil {
+			return errors.WithContext("delete job", err)
+		}
+	}
+
+	if len(stale) != 0 {
+		log.WithField("namespace", namespace).
+			WithField("objects", stale).
+			Info("Deleted objects that were removed from the Kubernetes manifests")
+	}
+	return nil
+}
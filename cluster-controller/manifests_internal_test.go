@@ -0,0 +1,136 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifests(t *testing.T) {
+	manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: other
+data:
+  key: value
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: queue
+spec:
+  selector:
+    app: queue
+  ports:
+  - port: 5672
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: queue
+spec:
+  selector:
+    matchLabels:
+      app: queue
+  template:
+    metadata:
+      labels:
+        app: queue
+    spec:
+      containers:
+      - name: rabbitmq
+        image: rabbitmq
+`
+
+	objs, err := parseManifests("namespace", "", []string{manifest}, []string{"web"})
+	assert.NoError(t, err)
+
+	if assert.Len(t, objs.configMaps, 1) {
+		assert.Equal(t, "namespace", objs.configMaps[0].Namespace)
+		assert.Equal(t, "true", objs.configMaps[0].Labels[manifestLabel])
+	}
+	assert.Len(t, objs.services, 1)
+	if assert.Len(t, objs.deployments, 1) {
+		assert.Equal(t, map[string]string{
+			"app":           "queue",
+			"blimp.service": "queue",
+			manifestLabel:   "true",
+		}, objs.deployments[0].Spec.Template.Labels)
+	}
+}
+
+func TestParseManifestsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expError string
+	}{
+		{
+			name: "unsupported kind",
+			manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: admin
+`,
+			expError: "Kubernetes manifests can't contain ClusterRole objects",
+		},
+		{
+			name: "node port",
+			manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: queue
+spec:
+  type: NodePort
+`,
+			expError: `Service "queue" has type NodePort`,
+		},
+		{
+			name: "host path",
+			manifest: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  template:
+    spec:
+      containers:
+      - name: migrate
+        image: migrate
+      volumes:
+      - name: host
+        hostPath:
+          path: /
+`,
+			expError: `The Job "migrate" can't mount host paths`,
+		},
+		{
+			name: "conflicts with compose service",
+			manifest: `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+`,
+			expError: `The StatefulSet "web" in the Kubernetes manifests has the same name as another service`,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := parseManifests("namespace", "", []string{test.manifest}, []string{"web"})
+		if assert.Error(t, err, test.name) {
+			assert.Contains(t, err.Error(), test.expError, test.name)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// meshInjectAnnotation controls whether Istio injects its sidecar into a
+	// pod.
+	meshInjectAnnotation = "sidecar.istio.io/inject"
+
+	// The names of the containers that Istio injects into pods.
+	meshProxyContainerName = "istio-proxy"
+	meshInitContainerName  = "istio-init"
+)
+
+// meshCompatibility is set by the environment variable MESH_COMPATIBILITY.
+// It should be enabled when the cluster runs Istio, and injects its sidecar
+// into sandbox pods.
+var meshCompatibility bool
+
+// excludeFromMesh disables sidecar injection for the pods that Blimp runs in
+// each sandbox, such as the DNS server. Their traffic is between Blimp's own
+// components, so it doesn't benefit from the mesh, and the proxy would
+// interfere with it.
+func excludeFromMesh(pod *corev1.Pod) {
+	if !meshCompatibility {
+		return
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[meshInjectAnnotation] = "false"
+}
+
+// getMeshStatus returns the status of the pod's mesh sidecar, or nil if a
+// sidecar wasn't injected.
+func getMeshStatus(pod *corev1.Pod) *cluster.MeshStatus {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == meshProxyContainerName {
+			return &cluster.MeshStatus{SidecarReady: cs.Ready}
+		}
+	}
+	return nil
+}
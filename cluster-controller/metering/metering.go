@@ -0,0 +1,387 @@
+// Package metering records the resources consumed by each sandbox so that
+// they can be reported back to users and operators.
+//
+// Usage is sampled periodically. CPU and memory are metered based on the
+// resource requests of the sandbox's running pods, including any extra CPU
+// requested while the sandbox is boosted. Storage is metered based on the
+// capacity of the sandbox's PersistentVolume, and egress is metered based on
+// the bytes transmitted by the sandbox's pods, as reported by the kubelet.
+//
+// Usage is aggregated by month, and persisted in the manager's store so that it
+// survives both manager restarts and `blimp down`.
+package metering
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/kelda/blimp/cluster-controller/store"
+	"github.com/kelda/blimp/cluster-controller/volume"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/metadata"
+)
+
+const (
+	// PeriodFormat is the time format used to identify billing periods.
+	PeriodFormat = "2006-01"
+
+	// usageKind is the kind of the store records containing usage. The
+	// records are keyed by namespace.
+	usageKind = "usage"
+
+	// usageLabel was used to select the ConfigMaps containing usage records
+	// before usage was persisted in the store.
+	usageLabel = "blimp.usage"
+
+	// namespaceAnnotation records which sandbox a usage ConfigMap belongs to.
+	namespaceAnnotation = "blimp.usage-namespace"
+
+	bytesPerGiB = 1 << 30
+)
+
+// Usage is the resources consumed by a sandbox in a single period.
+type Usage struct {
+	CPUCoreHours    float64 `json:"cpuCoreHours"`
+	MemoryGiBHours  float64 `json:"memoryGiBHours"`
+	StorageGiBHours float64 `json:"storageGiBHours"`
+	EgressBytes     int64   `json:"egressBytes"`
+
+	// BoostCPUCoreHours is the portion of CPUCoreHours requested because of
+	// boosts.
+	BoostCPUCoreHours float64 `json:"boostCPUCoreHours,omitempty"`
+}
+
+func (u *Usage) add(other Usage) {
+	u.CPUCoreHours += other.CPUCoreHours
+	u.BoostCPUCoreHours += other.BoostCPUCoreHours
+	u.MemoryGiBHours += other.MemoryGiBHours
+	u.StorageGiBHours += other.StorageGiBHours
+	u.EgressBytes += other.EgressBytes
+}
+
+func (u Usage) isZero() bool {
+	return u == Usage{}
+}
+
+// Meter periodically samples the resources used by each sandbox.
+type Meter struct {
+	kubeClient      kubernetes.Interface
+	namespaceLister listers.NamespaceLister
+	podLister       listers.PodLister
+	store           store.Store
+
+	// lastTxBytes tracks the cumulative number of bytes transmitted by each
+	// pod, keyed by pod UID, so that each sample only records the bytes sent
+	// since the previous sample.
+	lastTxBytes   map[string]uint64
+	sampledEgress bool
+	lastSample    time.Time
+}
+
+func New(kubeClient kubernetes.Interface, namespaceLister listers.NamespaceLister,
+	podLister listers.PodLister, store store.Store) *Meter {
+	return &Meter{
+		kubeClient:      kubeClient,
+		namespaceLister: namespaceLister,
+		podLister:       podLister,
+		store:           store,
+		lastTxBytes:     map[string]uint64{},
+	}
+}
+
+// Run samples usage every `interval`. It never returns.
+func (m *Meter) Run(interval time.Duration) {
+	if err := m.migrateConfigMaps(); err != nil {
+		log.WithError(err).Warn("Failed to migrate usage records from ConfigMaps")
+	}
+
+	m.lastSample = time.Now()
+	for range time.Tick(interval) {
+		if err := m.sample(); err != nil {
+			log.WithError(err).Warn("Failed to sample sandbox usage")
+		}
+	}
+}
+
+func (m *Meter) sample() error {
+	now := time.Now()
+	hours := now.Sub(m.lastSample).Hours()
+	m.lastSample = now
+
+	usage := map[string]Usage{}
+
+	namespaces, err := m.namespaceLister.List(labels.Set{"blimp.sandbox": "true"}.AsSelector())
+	if err != nil {
+		return errors.WithContext("list namespaces", err)
+	}
+
+	for _, namespace := range namespaces {
+		pods, err := m.podLister.Pods(namespace.Name).List(labels.Everything())
+		if err != nil {
+			return errors.WithContext("list pods", err)
+		}
+
+		var nsUsage Usage
+		for _, pod := range pods {
+			if pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+
+			cpuMultiplier, err := strconv.ParseFloat(pod.Annotations[metadata.CPUMultiplierKey], 64)
+			if err != nil || cpuMultiplier < 1 {
+				cpuMultiplier = 1
+			}
+
+			for _, c := range pod.Spec.Containers {
+				cpuCoreHours := float64(c.Resources.Requests.Cpu().MilliValue()) / 1000 * hours
+				nsUsage.CPUCoreHours += cpuCoreHours
+				nsUsage.BoostCPUCoreHours += cpuCoreHours * (1 - 1/cpuMultiplier)
+				nsUsage.MemoryGiBHours += float64(c.Resources.Requests.Memory().Value()) / bytesPerGiB * hours
+			}
+		}
+		usage[namespace.Name] = nsUsage
+	}
+
+	capacities, err := volume.GetCapacities(m.kubeClient)
+	if err != nil {
+		return errors.WithContext("get storage capacities", err)
+	}
+	for namespace, capacity := range capacities {
+		nsUsage := usage[namespace]
+		nsUsage.StorageGiBHours += float64(capacity.Value()) / bytesPerGiB * hours
+		usage[namespace] = nsUsage
+	}
+
+	egress, err := m.sampleEgress()
+	if err != nil {
+		// Still record the other resources.
+		log.WithError(err).Warn("Failed to sample egress")
+	}
+	for namespace, bytes := range egress {
+		if nsUsage, ok := usage[namespace]; ok {
+			nsUsage.EgressBytes += bytes
+			usage[namespace] = nsUsage
+		}
+	}
+
+	period := now.Format(PeriodFormat)
+	for namespace, nsUsage := range usage {
+		if nsUsage.isZero() {
+			continue
+		}
+
+		if err := m.record(namespace, period, nsUsage); err != nil {
+			log.WithError(err).WithField("namespace", namespace).Warn("Failed to record usage")
+		}
+	}
+	return nil
+}
+
+// statsSummary is the subset of the kubelet's stats summary API that's used
+// for metering egress.
+type statsSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Namespace string `json:"namespace"`
+			UID       string `json:"uid"`
+		} `json:"podRef"`
+		Network *struct {
+			TxBytes *uint64 `json:"txBytes"`
+		} `json:"network"`
+	} `json:"pods"`
+}
+
+// sampleEgress returns the number of bytes transmitted by each namespace
+// since the last sample.
+func (m *Meter) sampleEgress() (map[string]int64, error) {
+	nodes, err := m.kubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.WithContext("list nodes", err)
+	}
+
+	// If we don't have any previous samples (e.g. because the manager just
+	// restarted), only initialize the counters. Otherwise we'd double count
+	// all the traffic since the pods started.
+	initializing := !m.sampledEgress
+
+	egress := map[string]int64{}
+	txBytes := map[string]uint64{}
+	var missingNodes bool
+	for _, node := range nodes.Items {
+		summaryJSON, err := m.kubeClient.CoreV1().RESTClient().Get().
+			Resource("nodes").
+			Name(node.Name).
+			SubResource("proxy").
+			Suffix("stats/summary").
+			DoRaw()
+		if err != nil {
+			log.WithError(err).WithField("node", node.Name).Warn("Failed to get node stats")
+			missingNodes = true
+			continue
+		}
+
+		var summary statsSummary
+		if err := json.Unmarshal(summaryJSON, &summary); err != nil {
+			log.WithError(err).WithField("node", node.Name).Warn("Failed to parse node stats")
+			missingNodes = true
+			continue
+		}
+
+		for _, pod := range summary.Pods {
+			if pod.Network == nil || pod.Network.TxBytes == nil {
+				continue
+			}
+
+			curr := *pod.Network.TxBytes
+			txBytes[pod.PodRef.UID] = curr
+			if initializing {
+				continue
+			}
+
+			// If we haven't seen the pod before, it started after the last
+			// sample, so all of its traffic is new. If the counter decreased,
+			// the pod's network was reset.
+			prev, ok := m.lastTxBytes[pod.PodRef.UID]
+			if !ok || curr < prev {
+				prev = 0
+			}
+			egress[pod.PodRef.Namespace] += int64(curr - prev)
+		}
+	}
+
+	// Keep the counters for pods on nodes that we couldn't sample so that
+	// their traffic isn't counted twice once the node is reachable again.
+	if missingNodes {
+		for uid, bytes := range m.lastTxBytes {
+			if _, ok := txBytes[uid]; !ok {
+				txBytes[uid] = bytes
+			}
+		}
+	}
+
+	// Replacing the map also drops the counters for pods that no longer exist.
+	m.lastTxBytes = txBytes
+	m.sampledEgress = true
+	return egress, nil
+}
+
+func (m *Meter) record(namespace, period string, usage Usage) error {
+	return m.store.Update(usageKind, namespace, func(value []byte) ([]byte, error) {
+		periods, err := parsePeriods(value)
+		if err != nil {
+			return nil, err
+		}
+
+		total := periods[period]
+		total.add(usage)
+		periods[period] = total
+		return json.Marshal(periods)
+	})
+}
+
+// Get returns the usage of the given sandbox during the given period.
+func (m *Meter) Get(namespace, period string) (Usage, error) {
+	value, err := m.store.Get(usageKind, namespace)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return Usage{}, nil
+		}
+		return Usage{}, errors.WithContext("get", err)
+	}
+
+	periods, err := parsePeriods(value)
+	if err != nil {
+		return Usage{}, err
+	}
+	return periods[period], nil
+}
+
+// List returns the usage of all sandboxes during the given period, keyed by
+// namespace. Sandboxes without any usage during the period are omitted.
+func (m *Meter) List(period string) (map[string]Usage, error) {
+	records, err := m.store.List(usageKind)
+	if err != nil {
+		return nil, errors.WithContext("list", err)
+	}
+
+	usage := map[string]Usage{}
+	for namespace, value := range records {
+		periods, err := parsePeriods(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if nsUsage, ok := periods[period]; ok {
+			usage[namespace] = nsUsage
+		}
+	}
+	return usage, nil
+}
+
+// migrateConfigMaps moves the usage records written by previous versions of
+// the manager from ConfigMaps into the store. Periods that are already in the
+// store aren't overwritten, so the migration can safely be retried if it's
+// interrupted.
+func (m *Meter) migrateConfigMaps() error {
+	configMapsClient := m.kubeClient.CoreV1().ConfigMaps(kube.BlimpNamespace)
+	configMaps, err := configMapsClient.List(metav1.ListOptions{LabelSelector: usageLabel})
+	if err != nil {
+		return errors.WithContext("list", err)
+	}
+
+	for _, configMap := range configMaps.Items {
+		namespace := configMap.Annotations[namespaceAnnotation]
+		err := m.store.Update(usageKind, namespace, func(value []byte) ([]byte, error) {
+			periods, err := parsePeriods(value)
+			if err != nil {
+				return nil, err
+			}
+
+			for period, usageJSON := range configMap.Data {
+				if _, ok := periods[period]; ok {
+					continue
+				}
+
+				var usage Usage
+				if err := json.Unmarshal([]byte(usageJSON), &usage); err != nil {
+					return nil, errors.WithContext(fmt.Sprintf("parse usage for %s", period), err)
+				}
+				periods[period] = usage
+			}
+			return json.Marshal(periods)
+		})
+		if err != nil {
+			return errors.WithContext(fmt.Sprintf("migrate %s", configMap.Name), err)
+		}
+
+		err = configMapsClient.Delete(configMap.Name, nil)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.WithContext(fmt.Sprintf("delete %s", configMap.Name), err)
+		}
+	}
+	return nil
+}
+
+// parsePeriods parses a sandbox's usage record, which is keyed by period.
+func parsePeriods(value []byte) (map[string]Usage, error) {
+	periods := map[string]Usage{}
+	if len(value) == 0 {
+		return periods, nil
+	}
+
+	if err := json.Unmarshal(value, &periods); err != nil {
+		return nil, errors.WithContext("parse usage", err)
+	}
+	return periods, nil
+}
@@ -0,0 +1,243 @@
+package metering
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+	listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kelda/blimp/cluster-controller/store"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/metadata"
+)
+
+func newTestStore(t *testing.T, dir string) store.Store {
+	s, err := store.NewSQLite(filepath.Join(dir, "manager.db"))
+	require.NoError(t, err)
+	return s
+}
+
+func newTestMeter(t *testing.T, st store.Store, objs ...runtime.Object) *Meter {
+	namespaces := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	pods := cache.NewIndexer(cache.MetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range objs {
+		switch obj.(type) {
+		case *corev1.Namespace:
+			require.NoError(t, namespaces.Add(obj))
+		case *corev1.Pod:
+			require.NoError(t, pods.Add(obj))
+		}
+	}
+
+	return New(fakeKube.NewSimpleClientset(objs...), listers.NewNamespaceLister(namespaces),
+		listers.NewPodLister(pods), st)
+}
+
+func sandboxNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"blimp.sandbox": "true"},
+		},
+	}
+}
+
+func persistentVolume(namespace, capacity string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   namespace,
+			Labels: map[string]string{"blimp.kelda.io/namespace": namespace},
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+		},
+	}
+}
+
+func podWithRequests(namespace, name string, phase corev1.PodPhase, cpuMultiplier string) *corev1.Pod {
+	container := corev1.Container{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{container, container}},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	if cpuMultiplier != "" {
+		pod.Annotations = map[string]string{metadata.CPUMultiplierKey: cpuMultiplier}
+	}
+	return pod
+}
+
+func TestSample(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blimp-metering")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st := newTestStore(t, dir)
+	defer st.Close()
+
+	m := newTestMeter(t, st,
+		sandboxNamespace("alice"),
+		podWithRequests("alice", "web", corev1.PodRunning, "2"),
+		podWithRequests("alice", "build", corev1.PodPending, ""),
+		persistentVolume("alice", "2Gi"),
+
+		// Bob's sandbox is down, but its volume is still metered.
+		sandboxNamespace("bob"),
+		persistentVolume("bob", "10Gi"),
+
+		// Sandboxes that don't use any resources aren't recorded.
+		sandboxNamespace("carol"),
+
+		// Pods outside of sandboxes aren't metered.
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		podWithRequests("kube-system", "dns", corev1.PodRunning, ""),
+	)
+
+	m.lastSample = time.Now().Add(-time.Hour)
+	require.NoError(t, m.sample())
+
+	period := time.Now().Format(PeriodFormat)
+	usage, err := m.List(period)
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+
+	alice := usage["alice"]
+	assert.InDelta(t, 1, alice.CPUCoreHours, 0.01)
+	assert.InDelta(t, 0.5, alice.BoostCPUCoreHours, 0.01)
+	assert.InDelta(t, 2, alice.MemoryGiBHours, 0.01)
+	assert.InDelta(t, 2, alice.StorageGiBHours, 0.01)
+	assert.Zero(t, alice.EgressBytes)
+
+	bob := usage["bob"]
+	assert.Zero(t, bob.CPUCoreHours)
+	assert.InDelta(t, 10, bob.StorageGiBHours, 0.01)
+
+	// Each sample adds the usage since the previous sample to the period's
+	// total.
+	m.lastSample = time.Now().Add(-30 * time.Minute)
+	require.NoError(t, m.sample())
+
+	alice, err = m.Get("alice", period)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, alice.CPUCoreHours, 0.01)
+	assert.InDelta(t, 0.75, alice.BoostCPUCoreHours, 0.01)
+	assert.InDelta(t, 3, alice.MemoryGiBHours, 0.01)
+	assert.InDelta(t, 3, alice.StorageGiBHours, 0.01)
+
+	// The usage is persisted in the store, so it survives the manager
+	// restarting.
+	restarted := newTestMeter(t, st)
+	restartedUsage, err := restarted.Get("alice", period)
+	require.NoError(t, err)
+	assert.Equal(t, alice, restartedUsage)
+
+	carol, err := restarted.Get("carol", period)
+	assert.NoError(t, err)
+	assert.Equal(t, Usage{}, carol)
+}
+
+func TestRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blimp-metering")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st := newTestStore(t, dir)
+	defer st.Close()
+	m := newTestMeter(t, st)
+
+	require.NoError(t, m.record("alice", "2020-01", Usage{CPUCoreHours: 1, EgressBytes: 100}))
+	require.NoError(t, m.record("alice", "2020-01", Usage{CPUCoreHours: 2, BoostCPUCoreHours: 1}))
+	require.NoError(t, m.record("alice", "2020-02", Usage{MemoryGiBHours: 4}))
+	require.NoError(t, m.record("bob", "2020-02", Usage{StorageGiBHours: 8}))
+
+	usage, err := m.Get("alice", "2020-01")
+	assert.NoError(t, err)
+	assert.Equal(t, Usage{CPUCoreHours: 3, BoostCPUCoreHours: 1, EgressBytes: 100}, usage)
+
+	january, err := m.List("2020-01")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Usage{
+		"alice": {CPUCoreHours: 3, BoostCPUCoreHours: 1, EgressBytes: 100},
+	}, january)
+
+	february, err := m.List("2020-02")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Usage{
+		"alice": {MemoryGiBHours: 4},
+		"bob":   {StorageGiBHours: 8},
+	}, february)
+
+	// Malformed records are reported rather than overwritten.
+	require.NoError(t, st.Update(usageKind, "carol", func([]byte) ([]byte, error) {
+		return []byte("{"), nil
+	}))
+	assert.Error(t, m.record("carol", "2020-01", Usage{CPUCoreHours: 1}))
+	_, err = m.Get("carol", "2020-01")
+	assert.Error(t, err)
+}
+
+func TestMigrateConfigMaps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blimp-metering")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st := newTestStore(t, dir)
+	defer st.Close()
+
+	usageJSON := func(usage Usage) string {
+		b, err := json.Marshal(usage)
+		require.NoError(t, err)
+		return string(b)
+	}
+	m := newTestMeter(t, st, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "usage-alice",
+			Namespace:   kube.BlimpNamespace,
+			Labels:      map[string]string{usageLabel: "true"},
+			Annotations: map[string]string{namespaceAnnotation: "alice"},
+		},
+		Data: map[string]string{
+			"2020-01": usageJSON(Usage{CPUCoreHours: 1}),
+			"2020-02": usageJSON(Usage{CPUCoreHours: 2}),
+		},
+	})
+
+	// Periods that are already in the store aren't overwritten.
+	require.NoError(t, m.record("alice", "2020-02", Usage{CPUCoreHours: 5}))
+
+	require.NoError(t, m.migrateConfigMaps())
+	require.NoError(t, m.migrateConfigMaps())
+
+	january, err := m.Get("alice", "2020-01")
+	assert.NoError(t, err)
+	assert.Equal(t, Usage{CPUCoreHours: 1}, january)
+
+	february, err := m.Get("alice", "2020-02")
+	assert.NoError(t, err)
+	assert.Equal(t, Usage{CPUCoreHours: 5}, february)
+
+	configMaps, err := m.kubeClient.CoreV1().ConfigMaps(kube.BlimpNamespace).List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, configMaps.Items)
+}
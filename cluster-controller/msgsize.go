@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+
+	"github.com/kelda/blimp/pkg/msgsize"
+)
+
+const (
+	// defaultMaxMessageBytes is the largest gRPC message that the manager
+	// sends or receives, unless overridden by $GRPC_MAX_MESSAGE_BYTES. It's
+	// larger than gRPC's default of 4MiB since the statuses of sandboxes with
+	// dozens of services can exceed it.
+	defaultMaxMessageBytes = 16 * 1024 * 1024
+
+	// responseHeadroom is the space reserved in chunked responses for the
+	// fields other than the chunked one.
+	responseHeadroom = 64 * 1024
+
+	// minResponseBudget is the least chunked data that's put in a response.
+	// Smaller limits, such as ones advertised by misconfigured clients,
+	// would leave no room for the data.
+	minResponseBudget = 64 * 1024
+)
+
+// responseBudget returns how many bytes of chunked data can be put in a single
+// response to the client that made the request. It's limited by both the
+// client's and the manager's maximum message size. If the limits are too
+// small, the budget is clamped to minResponseBudget, and the client rejects the
+// response with an error that explains how to raise its limit.
+func (s *server) responseBudget(ctx context.Context) int {
+	limit := msgsize.ClientLimit(ctx)
+	if s.maxMessageBytes > 0 && s.maxMessageBytes < limit {
+		limit = s.maxMessageBytes
+	}
+
+	if budget := limit - responseHeadroom; budget > minResponseBudget {
+		return budget
+	}
+	return minResponseBudget
+}
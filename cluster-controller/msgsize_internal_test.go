@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/kelda/blimp/pkg/msgsize"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func TestResponseBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		clientLimit int
+		serverLimit int
+		exp         int
+	}{
+		{
+			name: "DefaultClientLimit",
+			exp:  msgsize.DefaultLimit - responseHeadroom,
+		},
+		{
+			name:        "ServerLimitSmaller",
+			clientLimit: 64 * 1024 * 1024,
+			serverLimit: 16 * 1024 * 1024,
+			exp:         16*1024*1024 - responseHeadroom,
+		},
+		{
+			name:        "ClientLimitSmaller",
+			clientLimit: 1024 * 1024,
+			serverLimit: 16 * 1024 * 1024,
+			exp:         1024*1024 - responseHeadroom,
+		},
+		{
+			name:        "ClientLimitSmallerThanHeadroom",
+			clientLimit: 1024,
+			serverLimit: 16 * 1024 * 1024,
+			exp:         minResponseBudget,
+		},
+		{
+			name:        "ServerLimitSmallerThanHeadroom",
+			serverLimit: responseHeadroom,
+			exp:         minResponseBudget,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.clientLimit != 0 {
+				ctx = metadata.NewIncomingContext(ctx,
+					metadata.Pairs("blimp-max-recv-message-bytes", strconv.Itoa(test.clientLimit)))
+			}
+
+			s := &server{maxMessageBytes: test.serverLimit}
+			assert.Equal(t, test.exp, s.responseBudget(ctx))
+		})
+	}
+}
+
+func TestChunkLogsSmallLimit(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("blimp-max-recv-message-bytes", "1"))
+	budget := (&server{}).responseBudget(ctx)
+
+	// Even with a tiny client limit, each chunk holds at least one line, and
+	// oversized lines are truncated rather than dropped.
+	lines := []*cluster.LogLine{
+		{Message: "short"},
+		{Message: strings.Repeat("a", 2*budget)},
+		{Message: "short"},
+	}
+	chunks := chunkLogs(lines, budget)
+	var total int
+	for _, chunk := range chunks {
+		assert.NotEmpty(t, chunk)
+		for _, line := range chunk {
+			assert.True(t, logLineSize(line) <= budget)
+		}
+		total += len(chunk)
+	}
+	assert.Equal(t, len(lines), total)
+	assert.Len(t, newestLogsWithin(lines, budget), 1)
+}
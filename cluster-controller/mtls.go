@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/strs"
+)
+
+const (
+	// mtlsCASecretName is the name of the secret containing the certificate
+	// authority that signs the certificates of all services in a sandbox.
+	mtlsCASecretName = "blimp-mtls-ca"
+
+	mtlsCAKeyKey = "ca.key"
+
+	// Reissue service certificates once they're this close to expiring.
+	mtlsRenewBefore = 30 * 24 * time.Hour
+)
+
+// deployMTLSCerts issues certificates for all services that have opted into
+// mTLS via the x-blimp extension. All certificates are signed by a
+// certificate authority that's unique to the sandbox, so services can
+// authenticate each other without any external infrastructure.
+func (s *server) deployMTLSCerts(namespace string, services []composeTypes.ServiceConfig) error {
+	var mtlsServices []composeTypes.ServiceConfig
+	for _, svc := range services {
+		ext, err := compose.GetServiceExtension(svc)
+		if err != nil {
+			return err
+		}
+
+		if ext.MTLS {
+			mtlsServices = append(mtlsServices, svc)
+		}
+	}
+
+	if len(mtlsServices) == 0 {
+		return nil
+	}
+
+	ca, err := s.getOrCreateMTLSCA(namespace)
+	if err != nil {
+		return errors.WithContext("get certificate authority", err)
+	}
+
+	secretsClient := s.kubeClient.CoreV1().Secrets(namespace)
+	for _, svc := range mtlsServices {
+		dnsNames := mtlsDNSNames(svc, services)
+		secretName := compose.MTLSSecretName(svc.Name)
+		currSecret, err := secretsClient.Get(secretName, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			if !mtlsNeedsReissue(currSecret, ca, dnsNames) {
+				continue
+			}
+		case !kerrors.IsNotFound(err):
+			return errors.WithContext("get service certificate", err)
+		}
+
+		cert, key, err := ca.issue(svc.Name, dnsNames)
+		if err != nil {
+			return errors.WithContext("issue certificate", err)
+		}
+
+		err = kube.DeploySecret(s.kubeClient, corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				compose.MTLSCACertKey: ca.certPEM,
+				compose.MTLSCertKey:   cert,
+				compose.MTLSKeyKey:    key,
+			},
+		})
+		if err != nil {
+			return errors.WithContext("deploy service certificate", err)
+		}
+	}
+	return nil
+}
+
+// mtlsDNSNames returns all the hostnames that other services may use to
+// refer to the given service.
+func mtlsDNSNames(svc composeTypes.ServiceConfig, services []composeTypes.ServiceConfig) []string {
+	dnsNames := []string{svc.Name}
+	if svc.Hostname != "" {
+		dnsNames = append(dnsNames, svc.Hostname)
+	}
+	if svc.ContainerName != "" {
+		dnsNames = append(dnsNames, svc.ContainerName)
+	}
+	for _, network := range svc.Networks {
+		if network != nil {
+			dnsNames = append(dnsNames, network.Aliases...)
+		}
+	}
+
+	for _, other := range services {
+		for _, link := range other.Links {
+			linkParts := strings.Split(link, ":")
+			if len(linkParts) == 2 && linkParts[0] == svc.Name {
+				dnsNames = append(dnsNames, linkParts[1])
+			}
+		}
+	}
+
+	dnsNames = strs.Unique(dnsNames)
+	sort.Strings(dnsNames)
+	return dnsNames
+}
+
+func mtlsNeedsReissue(secret *corev1.Secret, ca mtlsCA, dnsNames []string) bool {
+	if !bytes.Equal(secret.Data[compose.MTLSCACertKey], ca.certPEM) {
+		return true
+	}
+
+	cert, err := parseCertificate(secret.Data[compose.MTLSCertKey])
+	if err != nil {
+		return true
+	}
+
+	currNames := append([]string{}, cert.DNSNames...)
+	sort.Strings(currNames)
+	return !reflect.DeepEqual(currNames, dnsNames) ||
+		time.Until(cert.NotAfter) < mtlsRenewBefore
+}
+
+type mtlsCA struct {
+	cert    *x509.Certificate
+	key     crypto.Signer
+	certPEM []byte
+}
+
+func (s *server) getOrCreateMTLSCA(namespace string) (mtlsCA, error) {
+	secretsClient := s.kubeClient.CoreV1().Secrets(namespace)
+	secret, err := secretsClient.Get(mtlsCASecretName, metav1.GetOptions{})
+	if err == nil {
+		return parseMTLSCA(secret.Data[compose.MTLSCACertKey], secret.Data[mtlsCAKeyKey])
+	}
+
+	if !kerrors.IsNotFound(err) {
+		return mtlsCA{}, errors.WithContext("get secret", err)
+	}
+
+	certPEM, keyPEM, err := newMTLSCA(namespace)
+	if err != nil {
+		return mtlsCA{}, errors.WithContext("generate", err)
+	}
+
+	_, err = secretsClient.Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mtlsCASecretName,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			compose.MTLSCACertKey: certPEM,
+			mtlsCAKeyKey:          keyPEM,
+		},
+	})
+	if err != nil {
+		return mtlsCA{}, errors.WithContext("create secret", err)
+	}
+	return parseMTLSCA(certPEM, keyPEM)
+}
+
+func newMTLSCA(namespace string) (pemCert, pemKey []byte, err error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, errors.WithContext("create private key", err)
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, errors.WithContext("generate serial number", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"Kelda Blimp Sandbox"},
+			CommonName:   namespace,
+		},
+		// Set the NotBefore date to a bit earlier to allow for clients who
+		// have slow clocks.
+		NotBefore:             time.Now().Add(-1 * 24 * time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * time.Hour * 24),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		return nil, nil, errors.WithContext("create certificate", err)
+	}
+	return encodeCertAndKey(derBytes, priv)
+}
+
+// issue creates a certificate for the given service that can be used for
+// both sides of a mutual TLS connection.
+func (ca mtlsCA) issue(svc string, dnsNames []string) (pemCert, pemKey []byte, err error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, errors.WithContext("create private key", err)
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, errors.WithContext("generate serial number", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"Kelda Blimp Sandbox"},
+			CommonName:   svc,
+		},
+		NotBefore:             time.Now().Add(-1 * 24 * time.Hour),
+		NotAfter:              time.Now().Add(365 * time.Hour * 24),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              dnsNames,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, ca.cert, &priv.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, errors.WithContext("create certificate", err)
+	}
+	return encodeCertAndKey(derBytes, priv)
+}
+
+func parseMTLSCA(certPEM, keyPEM []byte) (mtlsCA, error) {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return mtlsCA{}, errors.WithContext("parse certificate", err)
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return mtlsCA{}, errors.New("no PEM data in private key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return mtlsCA{}, errors.WithContext("parse private key", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return mtlsCA{}, errors.New("unexpected private key type %T", key)
+	}
+	return mtlsCA{cert: cert, key: signer, certPEM: certPEM}, nil
+}
+
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("no PEM data in certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	return rand.Int(rand.Reader, serialNumberLimit)
+}
+
+func encodeCertAndKey(derBytes []byte, priv *rsa.PrivateKey) (pemCert, pemKey []byte, err error) {
+	var certOut bytes.Buffer
+	if err := pem.Encode(&certOut, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes}); err != nil {
+		return nil, nil, errors.WithContext("pem encode certificate", err)
+	}
+
+	var keyOut bytes.Buffer
+	privBytes, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, nil, errors.WithContext("marshal private key", err)
+	}
+	if err := pem.Encode(&keyOut, &pem.Block{Type: "PRIVATE KEY", Bytes: privBytes}); err != nil {
+		return nil, nil, errors.WithContext("pem encode private key", err)
+	}
+
+	return certOut.Bytes(), keyOut.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"crypto/x509"
+	"testing"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMTLSDNSNames(t *testing.T) {
+	web := composeTypes.ServiceConfig{
+		Name:          "web",
+		ContainerName: "project_web_1",
+		Networks: map[string]*composeTypes.ServiceNetworkConfig{
+			"default": {Aliases: []string{"frontend"}},
+		},
+	}
+	services := []composeTypes.ServiceConfig{
+		web,
+		{Name: "proxy", Links: []string{"web:upstream", "web"}},
+	}
+
+	assert.Equal(t, []string{"frontend", "project_web_1", "upstream", "web"},
+		mtlsDNSNames(web, services))
+}
+
+func TestMTLSIssue(t *testing.T) {
+	caCert, caKey, err := newMTLSCA("namespace")
+	require.NoError(t, err)
+
+	ca, err := parseMTLSCA(caCert, caKey)
+	require.NoError(t, err)
+
+	certPEM, _, err := ca.issue("web", []string{"web"})
+	require.NoError(t, err)
+
+	cert, err := parseCertificate(certPEM)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth} {
+		_, err = cert.Verify(x509.VerifyOptions{
+			DNSName:   "web",
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{usage},
+		})
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kelda/blimp/cluster-controller/store"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// namespaceKind is the kind of the store records that map sandbox namespaces
+// to their owners. The records are keyed by namespace.
+const namespaceKind = "namespace"
+
+// namespaceRecord tracks which user a namespace belongs to. Namespaces are
+// derived from a hash of the user's token, so different users can map to the
+// same namespace. The first user to create a sandbox in a namespace owns it
+// until the sandbox is deleted along with its volumes, and other users are
+// rejected rather than being given access to it.
+type namespaceRecord struct {
+	Owner     string    `json:"owner"`
+	ClaimedAt time.Time `json:"claimedAt"`
+
+	// RejectedUsers are the other users whose tokens map to the namespace.
+	RejectedUsers []string `json:"rejectedUsers,omitempty"`
+}
+
+// namespaceRegistry records the owner of each sandbox namespace.
+type namespaceRegistry struct {
+	store store.Store
+
+	// claimed caches the users whose claims succeeded. Owners never change,
+	// so the cache never needs to be invalidated.
+	claimedLock sync.Mutex
+	claimed     map[auth.User]struct{}
+}
+
+func newNamespaceRegistry(store store.Store) *namespaceRegistry {
+	return &namespaceRegistry{
+		store:   store,
+		claimed: map[auth.User]struct{}{},
+	}
+}
+
+// claim records that the user owns their namespace. It returns an error if
+// the namespace already belongs to someone else.
+func (r *namespaceRegistry) claim(user auth.User) error {
+	r.claimedLock.Lock()
+	_, ok := r.claimed[user]
+	r.claimedLock.Unlock()
+	if ok {
+		return nil
+	}
+
+	owner, err := r.update(user, user.Name)
+	if err != nil {
+		return err
+	}
+	if owner != user.Name {
+		return kube.NamespaceCollisionError(user.Namespace)
+	}
+
+	r.claimedLock.Lock()
+	r.claimed[user] = struct{}{}
+	r.claimedLock.Unlock()
+	return nil
+}
+
+// recordCollision records that the user's namespace belongs to `owner`, and
+// returns the error that should be returned to the user.
+func (r *namespaceRegistry) recordCollision(user auth.User, owner string) error {
+	if _, err := r.update(user, owner); err != nil {
+		log.WithError(err).WithField("namespace", user.Namespace).
+			Warn("Failed to record namespace collision")
+	}
+	return kube.NamespaceCollisionError(user.Namespace)
+}
+
+// release removes the namespace's owner so that it can be claimed by another
+// user. It should only be called once the sandbox and its volumes have been
+// deleted, so that the next owner can't access the previous owner's data.
+func (r *namespaceRegistry) release(namespace string) error {
+	if err := r.store.Delete(namespaceKind, namespace); err != nil && err != store.ErrNotFound {
+		return errors.WithContext("delete namespace record", err)
+	}
+
+	r.claimedLock.Lock()
+	for user := range r.claimed {
+		if user.Namespace == namespace {
+			delete(r.claimed, user)
+		}
+	}
+	r.claimedLock.Unlock()
+	return nil
+}
+
+// update claims the user's namespace for `defaultOwner` if it's unclaimed.
+// If the namespace belongs to someone other than the user, the user is
+// recorded as rejected. It returns the namespace's owner.
+func (r *namespaceRegistry) update(user auth.User, defaultOwner string) (string, error) {
+	var record namespaceRecord
+	err := r.store.Update(namespaceKind, user.Namespace, func(value []byte) ([]byte, error) {
+		record = namespaceRecord{}
+		if len(value) != 0 {
+			if err := json.Unmarshal(value, &record); err != nil {
+				return nil, errors.WithContext("parse namespace record", err)
+			}
+		}
+
+		if record.Owner == "" {
+			record.Owner = defaultOwner
+			record.ClaimedAt = time.Now()
+		}
+		if record.Owner != user.Name && !contains(record.RejectedUsers, user.Name) {
+			record.RejectedUsers = append(record.RejectedUsers, user.Name)
+		}
+		return json.Marshal(record)
+	})
+	if err != nil {
+		return "", errors.WithContext("update namespace record", err)
+	}
+
+	if record.Owner != user.Name {
+		log.WithField("namespace", user.Namespace).
+			WithField("owner", record.Owner).
+			WithField("user", user.Name).
+			Error("Rejected user whose namespace collides with another user's")
+	}
+	return record.Owner, nil
+}
+
+// lookup returns the records of the given namespaces. If no namespaces are
+// given, all records are returned.
+func (r *namespaceRegistry) lookup(namespaces ...string) (map[string]namespaceRecord, error) {
+	values := map[string][]byte{}
+	if len(namespaces) == 0 {
+		var err error
+		values, err = r.store.List(namespaceKind)
+		if err != nil {
+			return nil, errors.WithContext("list namespace records", err)
+		}
+	}
+
+	for _, namespace := range namespaces {
+		value, err := r.store.Get(namespaceKind, namespace)
+		switch {
+		case err == store.ErrNotFound:
+			continue
+		case err != nil:
+			return nil, errors.WithContext("get namespace record", err)
+		}
+		values[namespace] = value
+	}
+
+	records := map[string]namespaceRecord{}
+	for namespace, value := range values {
+		var record namespaceRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return nil, errors.WithContext(fmt.Sprintf("parse record for %s", namespace), err)
+		}
+		records[namespace] = record
+	}
+	return records, nil
+}
+
+// migrateNamespaces records the owners of the sandboxes that were created
+// before the registry existed. Namespaces that are already registered aren't
+// modified, so the migration can safely be retried.
+func (r *namespaceRegistry) migrateNamespaces(kubeClient kubernetes.Interface) error {
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(metav1.ListOptions{
+		LabelSelector: "blimp.sandbox=true",
+	})
+	if err != nil {
+		return errors.WithContext("list namespaces", err)
+	}
+
+	for _, namespace := range namespaces.Items {
+		owner, ok := namespace.Annotations[kube.OwnerAnnotation]
+		if !ok {
+			continue
+		}
+
+		err := r.store.Update(namespaceKind, namespace.Name, func(value []byte) ([]byte, error) {
+			if len(value) != 0 {
+				return value, nil
+			}
+			return json.Marshal(namespaceRecord{
+				Owner:     owner,
+				ClaimedAt: namespace.CreationTimestamp.Time,
+			})
+		})
+		if err != nil {
+			return errors.WithContext(fmt.Sprintf("migrate %s", namespace.Name), err)
+		}
+	}
+	return nil
+}
+
+// namespaceUnaryInterceptor rejects requests from users whose namespace
+// belongs to someone else, so that they can't access the other user's
+// sandbox. It runs after scopedTokenUnaryInterceptor so that scoped tokens
+// have already been replaced with the owner's token.
+func (s *server) namespaceUnaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.checkNamespaceOwner(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *server) namespaceStreamInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, namespaceStream{ServerStream: ss, s: s})
+}
+
+// namespaceStream checks the requests received by streaming RPCs.
+type namespaceStream struct {
+	grpc.ServerStream
+	s *server
+}
+
+func (ss namespaceStream) RecvMsg(m interface{}) error {
+	if err := ss.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return ss.s.checkNamespaceOwner(m)
+}
+
+// checkNamespaceOwner checks that the user that made the request owns their
+// namespace, if it exists. The owner is read from the namespace's annotation
+// since it's cached by the informer. Namespaces are claimed in the registry
+// before they're created, so the annotation always matches the registry.
+func (s *server) checkNamespaceOwner(req interface{}) error {
+	authReq, ok := req.(auth.AuthenticatedRequest)
+	if !ok {
+		return nil
+	}
+
+	// Leave rejecting invalid credentials to the RPC's handler.
+	blimpAuth := auth.GetAuth(authReq)
+	if blimpAuth.GetToken() == "" {
+		return nil
+	}
+	user, err := auth.AuthorizeRequest(blimpAuth)
+	if err != nil {
+		return nil
+	}
+
+	owner, err := kube.NamespaceOwner(s.statusFetcher.namespaceLister, user.Namespace)
+	if err != nil {
+		return errors.WithContext("check namespace owner", err)
+	}
+
+	if owner == "" || owner == user.Name {
+		return nil
+	}
+	return s.namespaces.recordCollision(user, owner)
+}
+
+func (s *server) LookupNamespace(ctx context.Context, req *cluster.LookupNamespaceRequest) (
+	*cluster.LookupNamespaceResponse, error) {
+	log.Info("Start LookupNamespace")
+
+	if err := auth.AuthorizeAdminRequest(req.GetAdminToken()); err != nil {
+		return &cluster.LookupNamespaceResponse{}, err
+	}
+
+	var namespaces []string
+	switch {
+	case req.GetNamespace() != "":
+		namespaces = []string{req.GetNamespace()}
+	case req.GetUser() != "":
+		user, err := auth.ParseIDToken(req.GetUser())
+		if err != nil {
+			return &cluster.LookupNamespaceResponse{}, errors.WithContext("parse user", err)
+		}
+		namespaces = []string{user.Namespace}
+	}
+
+	records, err := s.namespaces.lookup(namespaces...)
+	if err != nil {
+		return &cluster.LookupNamespaceResponse{}, err
+	}
+
+	var pbRecords []*cluster.NamespaceRecord
+	for namespace, record := range records {
+		pbRecords = append(pbRecords, &cluster.NamespaceRecord{
+			Namespace:     namespace,
+			Owner:         record.Owner,
+			ClaimedAt:     record.ClaimedAt.Unix(),
+			RejectedUsers: record.RejectedUsers,
+		})
+	}
+	sort.Slice(pbRecords, func(i, j int) bool {
+		return pbRecords[i].Namespace < pbRecords[j].Namespace
+	})
+	return &cluster.LookupNamespaceResponse{Records: pbRecords}, nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kelda/blimp/cluster-controller/store"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	authProto "github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// memStore is an in-memory store.Store for tests.
+type memStore map[string][]byte
+
+func (s memStore) Get(kind, key string) ([]byte, error) {
+	value, ok := s[kind+"/"+key]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return value, nil
+}
+
+func (s memStore) List(kind string) (map[string][]byte, error) {
+	values := map[string][]byte{}
+	for k, value := range s {
+		if len(k) > len(kind) && k[:len(kind)+1] == kind+"/" {
+			values[k[len(kind)+1:]] = value
+		}
+	}
+	return values, nil
+}
+
+func (s memStore) Update(kind, key string, fn func([]byte) ([]byte, error)) error {
+	value, err := fn(s[kind+"/"+key])
+	if err != nil {
+		return err
+	}
+	s[kind+"/"+key] = value
+	return nil
+}
+
+func (s memStore) Delete(kind, key string) error {
+	delete(s, kind+"/"+key)
+	return nil
+}
+
+func (s memStore) Close() error {
+	return nil
+}
+
+func TestNamespaceRegistry(t *testing.T) {
+	registry := newNamespaceRegistry(memStore{})
+	alice := auth.User{Name: "alice", Namespace: "ns"}
+	bob := auth.User{Name: "bob", Namespace: "ns"}
+
+	require.NoError(t, registry.claim(alice))
+	require.NoError(t, registry.claim(alice))
+
+	// Users whose names collide with the owner's are rejected, and recorded
+	// for operators.
+	err := registry.claim(bob)
+	codedErr, ok := errors.GetCode(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeNamespaceCollision, codedErr.Code())
+
+	records, err := registry.lookup("ns", "other-ns")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "alice", records["ns"].Owner)
+	assert.Equal(t, []string{"bob"}, records["ns"].RejectedUsers)
+
+	// Collisions detected on existing namespaces don't let the rejected user
+	// claim an unregistered namespace.
+	carol := auth.User{Name: "carol", Namespace: "unregistered-ns"}
+	assert.Error(t, registry.recordCollision(carol, "dave"))
+	records, err = registry.lookup()
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "dave", records["unregistered-ns"].Owner)
+	assert.Equal(t, []string{"carol"}, records["unregistered-ns"].RejectedUsers)
+}
+
+func TestNamespaceRegistryRelease(t *testing.T) {
+	registry := newNamespaceRegistry(memStore{})
+	alice := auth.User{Name: "alice", Namespace: "ns"}
+	bob := auth.User{Name: "bob", Namespace: "ns"}
+
+	require.NoError(t, registry.claim(alice))
+	require.Error(t, registry.claim(bob))
+
+	// Once released, the namespace can be claimed by someone else, and the
+	// previous owner's cached claim is forgotten.
+	require.NoError(t, registry.release("ns"))
+	require.NoError(t, registry.claim(bob))
+	assert.Error(t, registry.claim(alice))
+
+	// Releasing unclaimed namespaces is a no-op.
+	assert.NoError(t, registry.release("unclaimed-ns"))
+}
+
+// failingNamespaceLister simulates an error reading the informer's cache.
+type failingNamespaceLister struct {
+	listers.NamespaceLister
+}
+
+func (failingNamespaceLister) Get(string) (*corev1.Namespace, error) {
+	return nil, errors.New("cache unavailable")
+}
+
+func TestCheckNamespaceOwner(t *testing.T) {
+	alice, err := auth.ParseIDToken("alice")
+	require.NoError(t, err)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	s := &server{
+		statusFetcher: &statusFetcher{namespaceLister: listers.NewNamespaceLister(indexer)},
+		namespaces:    newNamespaceRegistry(memStore{}),
+	}
+	req := &cluster.GetStatusRequest{Auth: &authProto.BlimpAuth{Token: "alice"}}
+
+	// Namespaces that don't exist yet are claimed when they're created.
+	assert.NoError(t, s.checkNamespaceOwner(req))
+
+	// Requests without credentials are left to the handler to reject.
+	assert.NoError(t, s.checkNamespaceOwner(&cluster.GetStatusRequest{}))
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        alice.Namespace,
+			Annotations: map[string]string{kube.OwnerAnnotation: "alice"},
+		},
+	}
+	require.NoError(t, indexer.Add(namespace))
+	assert.NoError(t, s.checkNamespaceOwner(req))
+
+	// Users are rejected from namespaces owned by someone else.
+	namespace = namespace.DeepCopy()
+	namespace.Annotations[kube.OwnerAnnotation] = "mallory"
+	require.NoError(t, indexer.Update(namespace))
+	err = s.checkNamespaceOwner(req)
+	codedErr, ok := errors.GetCode(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeNamespaceCollision, codedErr.Code())
+
+	// Requests from old CLIs are also checked.
+	err = s.checkNamespaceOwner(&cluster.GetStatusRequest{OldToken: "alice"})
+	codedErr, ok = errors.GetCode(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeNamespaceCollision, codedErr.Code())
+
+	// Requests are rejected if the owner can't be checked.
+	s.statusFetcher.namespaceLister = failingNamespaceLister{}
+	assert.Error(t, s.checkNamespaceOwner(req))
+}
@@ -59,3 +59,19 @@ func GetNodeControllerInternalIP(kubeClient kubernetes.Interface, node string) (
 	}
 	return pod.Status.PodIP, nil
 }
+
+// IsControllerAddress returns whether the address is the public address of a
+// Node Controller, as returned by GetConnectionInfo.
+func IsControllerAddress(kubeClient kubernetes.Interface, addr string) (bool, error) {
+	secrets, err := kubeClient.CoreV1().Secrets(NodeControllerNamespace).List(metav1.ListOptions{})
+	if err != nil {
+		return false, errors.WithContext("list node controller secrets", err)
+	}
+
+	for _, secret := range secrets.Items {
+		if host, ok := secret.Annotations["host"]; ok && host == addr {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -27,11 +27,13 @@ import (
 	"k8s.io/client-go/util/retry"
 	"k8s.io/client-go/util/workqueue"
 
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/hash"
 	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/kubewait"
 	"github.com/kelda/blimp/pkg/ports"
 	"github.com/kelda/blimp/pkg/version"
-	"github.com/kelda/blimp/pkg/errors"
-	"github.com/kelda/blimp/pkg/kubewait"
 )
 
 const (
@@ -43,6 +45,8 @@ const (
 
 	// numWorkers is the max number of node controllers to deploy in parallel.
 	numWorkers = 4
+
+	nodeControllerServiceAccount = "node-controller"
 )
 
 var dnsTaint = corev1.Taint{
@@ -182,10 +186,65 @@ func (booter *booter) requeue(key interface{}) {
 	}
 }
 
+// sandboxRole grants the node controllers write access to the pods in a
+// sandbox. It's bound in each sandbox's namespace, rather than cluster-wide,
+// so that the node controllers can't modify the pods of the Blimp system
+// components.
+var sandboxRole = rbacv1.ClusterRole{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "node-controller-sandbox-role",
+	},
+	Rules: []rbacv1.PolicyRule{
+		// Create and delete the pods for the containers started through the
+		// emulated Docker socket.
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods"},
+			Verbs:     []string{"create", "delete"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods/log"},
+			Verbs:     []string{"get"},
+		},
+
+		// Record the progress of volume initialization in the pods'
+		// annotations.
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods"},
+			Verbs:     []string{"patch"},
+		},
+	},
+}
+
+// SandboxRoleBinding returns the RoleBinding that grants the node controllers
+// access to the pods in the given sandbox namespace.
+func SandboxRoleBinding(namespace string) rbacv1.RoleBinding {
+	return rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node-controller",
+			Namespace: namespace,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      nodeControllerServiceAccount,
+				Namespace: NodeControllerNamespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     sandboxRole.Name,
+		},
+	}
+}
+
 func (booter *booter) deployNodeController(node *corev1.Node) error {
 	serviceAccount := corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "node-controller",
+			Name:      nodeControllerServiceAccount,
 			Namespace: NodeControllerNamespace,
 		},
 	}
@@ -218,6 +277,16 @@ func (booter *booter) deployNodeController(node *corev1.Node) error {
 		},
 	}
 
+	// The node controller's access to the pods in sandboxes is granted
+	// separately for each sandbox by SandboxRoleBinding.
+	if err := kube.DeployClusterRole(booter.kubeClient, sandboxRole); err != nil {
+		return errors.WithContext("deploy sandbox role", err)
+	}
+
+	// The node controller applies the sandbox pod patches to the pods it
+	// creates for the emulated Docker socket.
+	podPatches := kube.SandboxPodPatchesConfigMap()
+
 	volumes := []corev1.Volume{
 		{
 			Name: "cert",
@@ -227,12 +296,26 @@ func (booter *booter) deployNodeController(node *corev1.Node) error {
 				},
 			},
 		},
+		{
+			Name: "pod-patches",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: podPatches.Name,
+					},
+				},
+			},
+		},
 	}
 	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      "cert",
 			MountPath: "/etc/blimp/certs",
 		},
+		{
+			Name:      "pod-patches",
+			MountPath: "/etc/blimp/pod-patches",
+		},
 	}
 
 	pod := corev1.Pod{
@@ -243,6 +326,11 @@ func (booter *booter) deployNodeController(node *corev1.Node) error {
 				"service": "node-controller",
 				"node":    node.Name,
 			},
+			Annotations: map[string]string{
+				// Restart the node controller when the patches change, since
+				// they're only loaded at startup.
+				"blimp.podPatchesHash": hash.Bytes([]byte(podPatches.Data[kube.PodPatchesKey])),
+			},
 		},
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{
@@ -266,6 +354,18 @@ func (booter *booter) deployNodeController(node *corev1.Node) error {
 							Name:  "NODE_NAME",
 							Value: node.Name,
 						},
+						{
+							Name: "POD_IP",
+							ValueFrom: &corev1.EnvVarSource{
+								FieldRef: &corev1.ObjectFieldSelector{
+									FieldPath: "status.podIP",
+								},
+							},
+						},
+						{
+							Name:  "POD_PATCHES_PATH",
+							Value: "/etc/blimp/pod-patches/" + kube.PodPatchesKey,
+						},
 					},
 					Resources: corev1.ResourceRequirements{
 						Requests: corev1.ResourceList{
@@ -282,13 +382,15 @@ func (booter *booter) deployNodeController(node *corev1.Node) error {
 			Volumes:            volumes,
 			ServiceAccountName: serviceAccount.Name,
 			NodeName:           node.Name,
-			Tolerations: []corev1.Toleration{
+			// The Node Controller has to run on the nodes in the sandbox
+			// node pool, so it needs the same tolerations as sandbox pods.
+			Tolerations: append([]corev1.Toleration{
 				{
 					Key:      dnsTaint.Key,
 					Operator: corev1.TolerationOpExists,
 					Effect:   dnsTaint.Effect,
 				},
-			},
+			}, affinity.Tolerations()...),
 		},
 	}
 
@@ -350,6 +452,10 @@ func (booter *booter) deployNodeController(node *corev1.Node) error {
 		return err
 	}
 
+	if err := kube.DeployConfigMap(booter.kubeClient, podPatches); err != nil {
+		return errors.WithContext("deploy pod patches", err)
+	}
+
 	if err := kube.DeployPod(booter.kubeClient, pod, kube.DeployPodOptions{}); err != nil {
 		return errors.WithContext("deploy", err)
 	}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/cluster-controller/billing"
+	clusterAuth "github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// pausedPodsSecret stores the specs of the pods that were stopped when the
+// sandbox was paused, so that they can be booted again by `blimp resume`. It's
+// a secret since the pod specs may contain sensitive environment variables.
+const pausedPodsSecret = "blimp-paused-pods"
+
+// pausedSandbox is stored in the sandbox namespace's annotations while the
+// sandbox is paused.
+type pausedSandbox struct {
+	PausedAt int64 `json:"pausedAt"`
+
+	// Deployments and StatefulSets contain the number of replicas that the
+	// workloads in the Kubernetes manifests had before they were scaled to
+	// zero.
+	Deployments  map[string]int32 `json:"deployments,omitempty"`
+	StatefulSets map[string]int32 `json:"statefulSets,omitempty"`
+}
+
+func (s *server) PauseSandbox(ctx context.Context, req *cluster.PauseSandboxRequest) (
+	*cluster.PauseSandboxResponse, error) {
+	user, err := clusterAuth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.PauseSandboxResponse{}, err
+	}
+
+	ns, err := s.kubeClient.CoreV1().Namespaces().Get(user.Namespace, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return &cluster.PauseSandboxResponse{}, errors.NewCodedError(errors.CodeSandboxNotFound, "Sandbox does not exist")
+		}
+		return &cluster.PauseSandboxResponse{}, errors.WithContext("get sandbox", err)
+	}
+
+	if _, ok, err := parsePaused(ns); err != nil {
+		return &cluster.PauseSandboxResponse{}, err
+	} else if ok {
+		return &cluster.PauseSandboxResponse{}, errors.NewFriendlyError("Your sandbox is already paused.")
+	}
+
+	if err := s.pauseSandbox(user.Namespace); err != nil {
+		return &cluster.PauseSandboxResponse{}, err
+	}
+	return &cluster.PauseSandboxResponse{}, nil
+}
+
+// pauseSandbox stops all the services in the sandbox. The sandbox's volumes,
+// DNS server, and file syncing are left running so that resuming only has to
+// boot the services.
+func (s *server) pauseSandbox(namespace string) error {
+	podsClient := s.kubeClient.CoreV1().Pods(namespace)
+	var pods []corev1.Pod
+	for _, selector := range []string{"blimp.customerPod=true", addonLabel + "=true"} {
+		currPods, err := podsClient.List(metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return errors.WithContext("list pods", err)
+		}
+		pods = append(pods, currPods.Items...)
+	}
+
+	var pausedPods []corev1.Pod
+	for _, pod := range pods {
+		pausedPods = append(pausedPods, appliedPod(&pod))
+	}
+
+	podsJSON, err := json.Marshal(pausedPods)
+	if err != nil {
+		return errors.WithContext("marshal pods", err)
+	}
+
+	err = kube.DeploySecret(s.kubeClient, corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pausedPodsSecret,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"pods": podsJSON},
+	})
+	if err != nil {
+		return errors.WithContext("save pods", err)
+	}
+
+	paused := pausedSandbox{
+		PausedAt:     time.Now().Unix(),
+		Deployments:  map[string]int32{},
+		StatefulSets: map[string]int32{},
+	}
+	if err := s.scaleManifestWorkloads(namespace, paused.Deployments, paused.StatefulSets, true); err != nil {
+		return errors.WithContext("scale down workloads", err)
+	}
+
+	if err := s.setCronJobsSuspended(namespace, true); err != nil {
+		return errors.WithContext("suspend scheduled services", err)
+	}
+
+	// Mark the sandbox as paused before deleting the pods so that the pods
+	// can be recovered by `blimp resume` even if some of the deletions fail.
+	if err := s.setPaused(namespace, &paused); err != nil {
+		return errors.WithContext("update sandbox", err)
+	}
+
+	for _, pod := range pods {
+		// Give the pods the same amount of time to shut down as when the
+		// sandbox is deleted.
+		ten := int64(10)
+		err := podsClient.Delete(pod.Name, &metav1.DeleteOptions{GracePeriodSeconds: &ten})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.WithContext("delete pod", err)
+		}
+	}
+
+	log.WithField("namespace", namespace).
+		WithField("numPods", len(pods)).
+		Info("Paused sandbox")
+	return nil
+}
+
+// appliedPod returns the spec that should be used to boot the pod again, such
+// as when the sandbox is resumed. The spec that the pod was originally
+// deployed with is preferred so that `blimp up` doesn't recreate the pod if it
+// hasn't changed.
+func appliedPod(pod *corev1.Pod) corev1.Pod {
+	if applied, ok := pod.Annotations["blimp.appliedObject"]; ok {
+		var desired corev1.Pod
+		if err := json.Unmarshal([]byte(applied), &desired); err == nil {
+			return desired
+		}
+	}
+	return copyCustomerPod(pod)
+}
+
+func (s *server) ResumeSandbox(ctx context.Context, req *cluster.ResumeSandboxRequest) (
+	*cluster.ResumeSandboxResponse, error) {
+	user, err := clusterAuth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.ResumeSandboxResponse{}, err
+	}
+
+	resumed, err := s.resumeSandbox(user.Namespace)
+	if err != nil {
+		return &cluster.ResumeSandboxResponse{}, err
+	}
+
+	if !resumed {
+		return &cluster.ResumeSandboxResponse{}, errors.NewFriendlyError("Your sandbox isn't paused.")
+	}
+	s.recordActivity(user.Namespace)
+	return &cluster.ResumeSandboxResponse{}, nil
+}
+
+// resumeSandbox boots the services that were stopped by pauseSandbox. It
+// returns false if the sandbox wasn't paused.
+func (s *server) resumeSandbox(namespace string) (bool, error) {
+	ns, err := s.kubeClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, errors.NewCodedError(errors.CodeSandboxNotFound, "Sandbox does not exist")
+		}
+		return false, errors.WithContext("get sandbox", err)
+	}
+
+	paused, ok, err := parsePaused(ns)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	err = s.checkBilling(context.Background(), billing.ActionResume, namespace, ns.Annotations[kube.OwnerAnnotation])
+	if err != nil {
+		return false, err
+	}
+
+	secretsClient := s.kubeClient.CoreV1().Secrets(namespace)
+	secret, err := secretsClient.Get(pausedPodsSecret, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.WithContext("get paused pods", err)
+	}
+
+	var pods []corev1.Pod
+	if err := json.Unmarshal(secret.Data["pods"], &pods); err != nil {
+		return false, errors.WithContext("parse paused pods", err)
+	}
+
+	// The sandbox may have moved to a different node since it was paused.
+	placement, err := parsePlacement(ns)
+	if err != nil {
+		return false, err
+	}
+
+	for _, pod := range pods {
+		pod = placedPod(pod, placement)
+		if err := kube.DeployPod(s.kubeClient, pod, kube.DeployPodOptions{}); err != nil {
+			return false, errors.WithContext("boot pod", err)
+		}
+	}
+
+	if err := s.scaleManifestWorkloads(namespace, paused.Deployments, paused.StatefulSets, false); err != nil {
+		return false, errors.WithContext("scale up workloads", err)
+	}
+
+	if err := s.setCronJobsSuspended(namespace, false); err != nil {
+		return false, errors.WithContext("resume scheduled services", err)
+	}
+
+	if err := s.setPaused(namespace, nil); err != nil {
+		return false, errors.WithContext("update sandbox", err)
+	}
+
+	if err := secretsClient.Delete(pausedPodsSecret, nil); err != nil && !kerrors.IsNotFound(err) {
+		return false, errors.WithContext("delete paused pods", err)
+	}
+
+	log.WithField("namespace", namespace).
+		WithField("numPods", len(pods)).
+		Info("Resumed sandbox")
+	return true, nil
+}
+
+// scaleManifestWorkloads scales the Deployments and StatefulSets from the
+// Kubernetes manifests. If `down` is true, they're scaled to zero, and their
+// previous replica counts are recorded in the given maps. Otherwise, they're
+// scaled back to the replica counts in the maps.
+func (s *server) scaleManifestWorkloads(namespace string, deployments, statefulSets map[string]int32,
+	down bool) error {
+	listOpts := metav1.ListOptions{LabelSelector: manifestLabel + "=true"}
+
+	deploymentsClient := s.kubeClient.AppsV1().Deployments(namespace)
+	currDeployments, err := deploymentsClient.List(listOpts)
+	if err != nil {
+		return errors.WithContext("list deployments", err)
+	}
+
+	for _, deployment := range currDeployments.Items {
+		replicas, ok := scaleReplicas(deployment.Name, deployment.Spec.Replicas, deployments, down)
+		if !ok {
+			continue
+		}
+
+		deployment.Spec.Replicas = &replicas
+		if _, err := deploymentsClient.Update(&deployment); err != nil {
+			return errors.WithContext("scale deployment", err)
+		}
+	}
+
+	statefulSetsClient := s.kubeClient.AppsV1().StatefulSets(namespace)
+	currStatefulSets, err := statefulSetsClient.List(listOpts)
+	if err != nil {
+		return errors.WithContext("list statefulsets", err)
+	}
+
+	for _, statefulSet := range currStatefulSets.Items {
+		replicas, ok := scaleReplicas(statefulSet.Name, statefulSet.Spec.Replicas, statefulSets, down)
+		if !ok {
+			continue
+		}
+
+		statefulSet.Spec.Replicas = &replicas
+		if _, err := statefulSetsClient.Update(&statefulSet); err != nil {
+			return errors.WithContext("scale statefulset", err)
+		}
+	}
+	return nil
+}
+
+// scaleReplicas returns the number of replicas that the workload should be
+// scaled to, and whether it should be updated at all.
+func scaleReplicas(name string, curr *int32, saved map[string]int32, down bool) (int32, bool) {
+	if down {
+		// Kubernetes defaults to one replica if it's not set.
+		replicas := int32(1)
+		if curr != nil {
+			replicas = *curr
+		}
+		saved[name] = replicas
+		return 0, true
+	}
+
+	// Workloads that were created after the sandbox was paused are left
+	// alone.
+	replicas, ok := saved[name]
+	return replicas, ok
+}
+
+func (s *server) setCronJobsSuspended(namespace string, suspend bool) error {
+	cronJobsClient := s.kubeClient.BatchV1beta1().CronJobs(namespace)
+	cronJobs, err := cronJobsClient.List(metav1.ListOptions{LabelSelector: scheduledLabel + "=true"})
+	if err != nil {
+		return errors.WithContext("list cronjobs", err)
+	}
+
+	for _, cronJob := range cronJobs.Items {
+		cronJob.Spec.Suspend = &suspend
+		if _, err := cronJobsClient.Update(&cronJob); err != nil {
+			return errors.WithContext("update cronjob", err)
+		}
+	}
+	return nil
+}
+
+func parsePaused(ns *corev1.Namespace) (pausedSandbox, bool, error) {
+	pausedJSON, ok := ns.Annotations[kube.PausedAnnotation]
+	if !ok {
+		return pausedSandbox{}, false, nil
+	}
+
+	var paused pausedSandbox
+	if err := json.Unmarshal([]byte(pausedJSON), &paused); err != nil {
+		return pausedSandbox{}, false, errors.WithContext("parse paused state", err)
+	}
+	return paused, true, nil
+}
+
+// setPaused records that the sandbox is paused. If `paused` is nil, the
+// sandbox is marked as running.
+func (s *server) setPaused(namespace string, paused *pausedSandbox) error {
+	namespacesClient := s.kubeClient.CoreV1().Namespaces()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := namespacesClient.Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if paused == nil {
+			delete(ns.Annotations, kube.PausedAnnotation)
+		} else {
+			pausedJSON, err := json.Marshal(paused)
+			if err != nil {
+				return err
+			}
+
+			if ns.Annotations == nil {
+				ns.Annotations = map[string]string{}
+			}
+			ns.Annotations[kube.PausedAnnotation] = string(pausedJSON)
+		}
+
+		_, err = namespacesClient.Update(ns)
+		return err
+	})
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+func TestPauseAndResume(t *testing.T) {
+	replicas := int32(2)
+	kubeClient := fakeKube.NewSimpleClientset(
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ns"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "ns",
+				Labels:    map[string]string{"blimp.customerPod": "true", "blimp.service": "web"},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx"}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "postgres",
+				Namespace: "ns",
+				Labels:    map[string]string{addonLabel: "true", "blimp.service": "postgres"},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "ns"},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api",
+				Namespace: "ns",
+				Labels:    map[string]string{manifestLabel: "true"},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+		&batchv1beta1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cleanup",
+				Namespace: "ns",
+				Labels:    map[string]string{scheduledLabel: "true"},
+			},
+		},
+	)
+	s := &server{kubeClient: kubeClient}
+
+	assert.NoError(t, s.pauseSandbox("ns"))
+
+	pods, err := kubeClient.CoreV1().Pods("ns").List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, pods.Items, 1) {
+		assert.Equal(t, "dns", pods.Items[0].Name)
+	}
+
+	ns, err := kubeClient.CoreV1().Namespaces().Get("ns", metav1.GetOptions{})
+	assert.NoError(t, err)
+	paused, ok, err := parsePaused(ns)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]int32{"api": 2}, paused.Deployments)
+
+	deployment, err := kubeClient.AppsV1().Deployments("ns").Get("api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+
+	cronJob, err := kubeClient.BatchV1beta1().CronJobs("ns").Get("cleanup", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, *cronJob.Spec.Suspend)
+
+	resumed, err := s.resumeSandbox("ns")
+	assert.NoError(t, err)
+	assert.True(t, resumed)
+
+	pods, err = kubeClient.CoreV1().Pods("ns").List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, pods.Items, 3)
+
+	web, err := kubeClient.CoreV1().Pods("ns").Get("web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx", web.Spec.Containers[0].Image)
+
+	ns, err = kubeClient.CoreV1().Namespaces().Get("ns", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, ns.Annotations, kube.PausedAnnotation)
+
+	deployment, err = kubeClient.AppsV1().Deployments("ns").Get("api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+
+	cronJob, err = kubeClient.BatchV1beta1().CronJobs("ns").Get("cleanup", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, *cronJob.Spec.Suspend)
+
+	_, err = kubeClient.CoreV1().Secrets("ns").Get(pausedPodsSecret, metav1.GetOptions{})
+	assert.Error(t, err)
+
+	// Resuming a sandbox that isn't paused is a no-op.
+	resumed, err = s.resumeSandbox("ns")
+	assert.NoError(t, err)
+	assert.False(t, resumed)
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/cluster-controller/volume"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// sandboxOverhead is the resources requested by the pods that Blimp runs in
+// every sandbox, such as syncthing and the DNS server.
+var sandboxOverhead = corev1.ResourceList{
+	corev1.ResourceCPU:    resource.MustParse("200m"),
+	corev1.ResourceMemory: resource.MustParse("200Mi"),
+}
+
+// getPlacement returns where the sandbox's pods should run. Sandboxes that
+// were created before placements were tracked have an empty placement, which
+// colocates their pods with whichever node the sandbox is already on.
+func (s *server) getPlacement(namespace string) (affinity.Placement, error) {
+	ns, err := s.kubeClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		return affinity.Placement{}, errors.WithContext("get namespace", err)
+	}
+	return parsePlacement(ns)
+}
+
+func parsePlacement(ns *corev1.Namespace) (affinity.Placement, error) {
+	var placement affinity.Placement
+	placementStr, ok := ns.Annotations[kube.PlacementAnnotation]
+	if !ok {
+		return placement, nil
+	}
+
+	if err := json.Unmarshal([]byte(placementStr), &placement); err != nil {
+		return affinity.Placement{}, errors.WithContext("parse placement", err)
+	}
+	return placement, nil
+}
+
+func (s *server) setPlacement(namespace string, placement affinity.Placement) error {
+	placementBytes, err := json.Marshal(placement)
+	if err != nil {
+		return errors.WithContext("marshal placement", err)
+	}
+
+	namespaceClient := s.kubeClient.CoreV1().Namespaces()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := namespaceClient.Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		ns.Annotations[kube.PlacementAnnotation] = string(placementBytes)
+		_, err = namespaceClient.Update(ns)
+		return err
+	})
+}
+
+// placeSandbox picks the node that the sandbox's pods should run on, given
+// the resources that the sandbox will request. Sandboxes stay on their
+// current node if it still has room. Otherwise, they overflow onto the node
+// with the most free resources. If no node can fit the sandbox, and the
+// sandbox's volume can be mounted from multiple nodes, the sandbox's pods are
+// spread across nodes instead.
+func (s *server) placeSandbox(namespace string, request corev1.ResourceList) (affinity.Placement, error) {
+	curr, err := s.getPlacement(namespace)
+	if err != nil {
+		return affinity.Placement{}, err
+	}
+
+	nodes, err := s.statusFetcher.nodeLister.List(labels.Everything())
+	if err != nil {
+		return affinity.Placement{}, errors.WithContext("list nodes", err)
+	}
+
+	pods, err := s.statusFetcher.podLister.List(labels.Everything())
+	if err != nil {
+		return affinity.Placement{}, errors.WithContext("list pods", err)
+	}
+
+	// Prefer the node that the sandbox is already running on, so that
+	// sandboxes created before placements were tracked don't get moved.
+	preferred := curr.Node
+	if preferred == "" {
+		for _, pod := range pods {
+			if pod.Namespace == namespace && pod.Labels[affinity.ColocateNamespaceKey] == namespace &&
+				pod.Spec.NodeName != "" {
+				preferred = pod.Spec.NodeName
+				break
+			}
+		}
+	}
+
+	node, ok := pickNode(nodes, pods, namespace, request, preferred)
+	switch {
+	case ok:
+		return s.updatePlacement(namespace, curr, affinity.Placement{Node: node})
+	case curr.Spread:
+		return curr, nil
+	}
+
+	shared, err := volume.IsShared(s.kubeClient, namespace)
+	if err != nil {
+		return affinity.Placement{}, errors.WithContext("get volume access mode", err)
+	}
+	if shared {
+		return s.updatePlacement(namespace, curr, affinity.Placement{Spread: true})
+	}
+
+	// Leave the sandbox where it is, rather than moving it onto a node that
+	// also can't fit it. Its new pods will be pending until resources are
+	// freed up.
+	if curr.Node != "" {
+		return curr, nil
+	}
+	return affinity.Placement{}, errors.NewFriendlyError(
+		"Failed to schedule your sandbox. The blimp servers may be overloaded.")
+}
+
+// updatePlacement saves the sandbox's new placement, and moves the sandbox's
+// pods to match it.
+func (s *server) updatePlacement(namespace string, curr, placement affinity.Placement) (affinity.Placement, error) {
+	if curr == placement {
+		return curr, nil
+	}
+
+	if err := s.setPlacement(namespace, placement); err != nil {
+		return affinity.Placement{}, errors.WithContext("save placement", err)
+	}
+
+	if err := s.moveSandbox(namespace, placement); err != nil {
+		return affinity.Placement{}, errors.WithContext("move sandbox", err)
+	}
+	return placement, nil
+}
+
+// moveSandbox recreates the sandbox's pods that aren't on the placement's
+// node. All of the pods are deleted before any are recreated so that the
+// sandbox's volume is detached from the old node before the new pods try to
+// mount it.
+func (s *server) moveSandbox(namespace string, placement affinity.Placement) error {
+	// Pods can run on any node if the sandbox is spread out.
+	if placement.Node == "" {
+		return nil
+	}
+
+	pods, err := s.statusFetcher.podLister.Pods(namespace).List(
+		labels.Set{affinity.ColocateNamespaceKey: namespace}.AsSelector())
+	if err != nil {
+		return errors.WithContext("list pods", err)
+	}
+
+	var toMove []corev1.Pod
+	for _, pod := range pods {
+		// Pods created by CronJobs are moved when they next run, and image
+		// pre-pulls are short lived.
+		if pod.Spec.NodeName == placement.Node || len(pod.OwnerReferences) != 0 ||
+			pod.Labels[prePullLabel] == "true" || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		log.WithField("namespace", namespace).
+			WithField("pod", pod.Name).
+			WithField("from", pod.Spec.NodeName).
+			WithField("to", placement.Node).
+			Info("Moving pod to sandbox's node")
+
+		toMove = append(toMove, placedPod(appliedPod(pod), placement))
+		if err := kube.DeletePod(s.kubeClient, pod.Namespace, pod.Name); err != nil {
+			return errors.WithContext(fmt.Sprintf("delete pod %s", pod.Name), err)
+		}
+	}
+
+	for _, pod := range toMove {
+		if err := kube.DeployPod(s.kubeClient, pod, kube.DeployPodOptions{}); err != nil {
+			return errors.WithContext(fmt.Sprintf("deploy pod %s", pod.Name), err)
+		}
+	}
+	return nil
+}
+
+// placedPod returns a copy of the pod that runs according to the placement.
+func placedPod(pod corev1.Pod, placement affinity.Placement) corev1.Pod {
+	pod.Spec.NodeName = ""
+	pod.Spec.Affinity = affinity.ForUser(auth.User{Namespace: pod.Namespace}, placement)
+	return pod
+}
+
+// pickNode returns the node that has room for the sandbox. The preferred
+// node is used if it has room. Otherwise, the node with the most free CPU is
+// used.
+func pickNode(nodes []*corev1.Node, pods []*corev1.Pod, namespace string,
+	request corev1.ResourceList, preferred string) (string, bool) {
+
+	// Compute how much of each node is already requested by other sandboxes.
+	// The sandbox's own pods are excluded since they're accounted for by the
+	// request.
+	requested := map[string]corev1.ResourceList{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Namespace == namespace ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		nodeRequested, ok := requested[pod.Spec.NodeName]
+		if !ok {
+			nodeRequested = corev1.ResourceList{}
+			requested[pod.Spec.NodeName] = nodeRequested
+		}
+		addResources(nodeRequested, podRequests(pod))
+	}
+
+	type candidate struct {
+		name    string
+		freeCPU resource.Quantity
+	}
+	var candidates []candidate
+	for _, node := range nodes {
+		if !isSchedulable(node) {
+			continue
+		}
+
+		free := node.Status.Allocatable.DeepCopy()
+		subtractResources(free, requested[node.Name])
+		if !fits(request, free) {
+			continue
+		}
+
+		if node.Name == preferred {
+			return node.Name, true
+		}
+		candidates = append(candidates, candidate{node.Name, free[corev1.ResourceCPU]})
+	}
+
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if cmp := candidates[i].freeCPU.Cmp(candidates[j].freeCPU); cmp != 0 {
+			return cmp > 0
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	return candidates[0].name, true
+}
+
+func isSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable || node.DeletionTimestamp != nil ||
+		hasPreemptionTaint(node) || !affinity.SchedulesOn(node) {
+		return false
+	}
+
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// newSandboxRequests returns the resources that will be requested by a
+// sandbox with the given number of services.
+func newSandboxRequests(numServices int) corev1.ResourceList {
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse(
+			fmt.Sprintf("%d%s", compose.CPURequest*numServices, compose.CPURequestUnits)),
+		corev1.ResourceMemory: resource.MustParse(
+			fmt.Sprintf("%d%s", compose.MemoryRequest*numServices, compose.MemoryRequestUnits)),
+	}
+	addResources(requests, sandboxOverhead)
+	return requests
+}
+
+// sandboxRequests returns the resources requested by the sandbox's current
+// pods.
+func (s *server) sandboxRequests(namespace string) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	pods, err := s.statusFetcher.podLister.Pods(namespace).List(
+		labels.Set{affinity.ColocateNamespaceKey: namespace}.AsSelector())
+	if err != nil {
+		log.WithError(err).WithField("namespace", namespace).Warn("Failed to list pods")
+		return requests
+	}
+
+	for _, pod := range pods {
+		addResources(requests, podRequests(pod))
+	}
+	return requests
+}
+
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		addResources(requests, c.Resources.Requests)
+	}
+	return requests
+}
+
+func addResources(dst, src corev1.ResourceList) {
+	for name, quantity := range src {
+		total := dst[name]
+		total.Add(quantity)
+		dst[name] = total
+	}
+}
+
+func subtractResources(dst, src corev1.ResourceList) {
+	for name, quantity := range src {
+		if total, ok := dst[name]; ok {
+			total.Sub(quantity)
+			dst[name] = total
+		}
+	}
+}
+
+func fits(request, free corev1.ResourceList) bool {
+	for name, quantity := range request {
+		available, ok := free[name]
+		if !ok || available.Cmp(quantity) < 0 {
+			return false
+		}
+	}
+	return true
+}
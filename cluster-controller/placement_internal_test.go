@@ -0,0 +1,228 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/cluster-controller/volume"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+func makeNode(name, cpu string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+			Conditions: []corev1.NodeCondition{{
+				Type:   corev1.NodeReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+}
+
+func makeSandboxPod(namespace, name, node, cpu string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{affinity.ColocateNamespaceKey: namespace},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name: name,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestPickNode(t *testing.T) {
+	cordoned := makeNode("cordoned", "64")
+	cordoned.Spec.Unschedulable = true
+
+	notReady := makeNode("not-ready", "64")
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+
+	nodes := []*corev1.Node{
+		makeNode("small", "2"),
+		makeNode("busy", "8"),
+		makeNode("large", "8"),
+		cordoned,
+		notReady,
+	}
+	pods := []*corev1.Pod{
+		makeSandboxPod("other", "web", "busy", "7"),
+		makeSandboxPod("other", "db", "large", "1"),
+
+		// Pods in the sandbox being placed don't count against the node.
+		makeSandboxPod("ns", "web", "small", "2"),
+
+		// Finished pods don't count against the node.
+		func() *corev1.Pod {
+			pod := makeSandboxPod("other", "job", "large", "6")
+			pod.Status.Phase = corev1.PodSucceeded
+			return pod
+		}(),
+	}
+	request := func(cpu string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+	}
+
+	tests := []struct {
+		name      string
+		request   corev1.ResourceList
+		preferred string
+		expNode   string
+		expOK     bool
+	}{
+		{
+			name:      "StayOnPreferred",
+			request:   request("2"),
+			preferred: "small",
+			expNode:   "small",
+			expOK:     true,
+		},
+		{
+			name:      "OverflowFromPreferred",
+			request:   request("3"),
+			preferred: "small",
+			expNode:   "large",
+			expOK:     true,
+		},
+		{
+			name:    "MostFreeCPU",
+			request: request("1"),
+			expNode: "large",
+			expOK:   true,
+		},
+		{
+			name:    "NoRoom",
+			request: request("32"),
+			expOK:   false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			node, ok := pickNode(nodes, pods, "ns", test.request, test.preferred)
+			assert.Equal(t, test.expOK, ok)
+			assert.Equal(t, test.expNode, node)
+		})
+	}
+}
+
+func TestPlaceSandbox(t *testing.T) {
+	nodeA := makeNode("a", "4")
+	nodeB := makeNode("b", "8")
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ns",
+			Annotations: map[string]string{kube.PlacementAnnotation: `{"node":"a"}`},
+		},
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: volume.PersistentVolumeClaimName},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		},
+	}
+
+	newServer := func(objs ...runtime.Object) (*server, func()) {
+		kubeClient := fakeKube.NewSimpleClientset(objs...)
+		sf := newStatusFetcher(kubeClient, defaultInformerConfig)
+		stop := make(chan struct{})
+		sf.Start(stop)
+		return &server{kubeClient: kubeClient, statusFetcher: sf}, func() { close(stop) }
+	}
+	cpu := func(cpu string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+	}
+
+	t.Run("Stay", func(t *testing.T) {
+		s, stop := newServer(ns.DeepCopy(), pvc.DeepCopy(), nodeA, nodeB,
+			makeSandboxPod("ns", "web", "a", "1"))
+		defer stop()
+
+		placement, err := s.placeSandbox("ns", cpu("2"))
+		assert.NoError(t, err)
+		assert.Equal(t, affinity.Placement{Node: "a"}, placement)
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		s, stop := newServer(ns.DeepCopy(), pvc.DeepCopy(), nodeA, nodeB,
+			makeSandboxPod("ns", "web", "a", "1"))
+		defer stop()
+
+		placement, err := s.placeSandbox("ns", cpu("6"))
+		assert.NoError(t, err)
+		assert.Equal(t, affinity.Placement{Node: "b"}, placement)
+
+		saved, err := s.getPlacement("ns")
+		assert.NoError(t, err)
+		assert.Equal(t, placement, saved)
+
+		// The sandbox's pods should be moved to the new node.
+		moved, err := s.kubeClient.CoreV1().Pods("ns").Get("web", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, moved.Spec.NodeName)
+		assert.Equal(t, []string{"b"}, moved.Spec.Affinity.NodeAffinity.
+			RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Values)
+	})
+
+	t.Run("NoRoom", func(t *testing.T) {
+		s, stop := newServer(ns.DeepCopy(), pvc.DeepCopy(), nodeA, nodeB)
+		defer stop()
+
+		placement, err := s.placeSandbox("ns", cpu("16"))
+		assert.NoError(t, err)
+		assert.Equal(t, affinity.Placement{Node: "a"}, placement)
+	})
+
+	t.Run("NoRoomNewSandbox", func(t *testing.T) {
+		newNS := ns.DeepCopy()
+		newNS.Annotations = nil
+		s, stop := newServer(newNS, pvc.DeepCopy(), nodeA, nodeB)
+		defer stop()
+
+		_, err := s.placeSandbox("ns", cpu("16"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Spread", func(t *testing.T) {
+		sharedPVC := pvc.DeepCopy()
+		sharedPVC.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+		s, stop := newServer(ns.DeepCopy(), sharedPVC, nodeA, nodeB)
+		defer stop()
+
+		placement, err := s.placeSandbox("ns", cpu("16"))
+		assert.NoError(t, err)
+		assert.Equal(t, affinity.Placement{Spread: true}, placement)
+	})
+}
+
+func TestPlacedPod(t *testing.T) {
+	pod := makeSandboxPod("ns", "web", "a", "1")
+
+	placed := placedPod(*pod, affinity.Placement{Node: "b"})
+	assert.Empty(t, placed.Spec.NodeName)
+	assert.Equal(t, affinity.ForUser(auth.User{Namespace: "ns"}, affinity.Placement{Node: "b"}),
+		placed.Spec.Affinity)
+	assert.Equal(t, pod.Spec.Containers, placed.Spec.Containers)
+}
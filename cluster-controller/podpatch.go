@@ -0,0 +1,42 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// configurePodPatches loads the strategic merge patches that are applied to
+// every sandbox pod. Operators use them to add company-specific labels,
+// tolerations, or sidecars. The patches are read from the YAML file at
+// $POD_PATCHES_PATH, which may contain multiple documents that are applied in
+// order. For example:
+// ```
+// metadata:
+//   labels:
+//     cost-center: dev-environments
+// spec:
+//   tolerations:
+//   - key: dedicated
+//     value: blimp
+//     effect: NoSchedule
+// ```
+func configurePodPatches() error {
+	path, ok := os.LookupEnv("POD_PATCHES_PATH")
+	if !ok {
+		return nil
+	}
+
+	patchesYAML, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.WithContext("read patches", err)
+	}
+
+	patches, err := kube.ParsePodPatches(patchesYAML)
+	if err != nil {
+		return err
+	}
+	return kube.SetSandboxPodPatches(patches)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	clusterAuth "github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/hash"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// maxPollWait caps how long PollStatus waits for a change. It's below the
+// idle timeout of most proxies, since the point of polling is to avoid
+// connections getting killed.
+const maxPollWait = 25 * time.Second
+
+// statusCursor records the state of a sandbox as seen by a polling client.
+// Clients treat it as an opaque validator, similar to an ETag. It contains a
+// hash of each service's status so that the manager can compute what changed
+// without storing any per-client state.
+type statusCursor struct {
+	Phase    cluster.SandboxStatus_SandboxPhase `json:"p"`
+	Services map[string]string                  `json:"s,omitempty"`
+}
+
+func (s *server) PollStatus(ctx context.Context, req *cluster.PollStatusRequest) (*cluster.PollStatusResponse, error) {
+	user, err := clusterAuth.AuthorizeRequest(clusterAuth.GetAuth(req))
+	if err != nil {
+		return &cluster.PollStatusResponse{}, err
+	}
+
+	var prev statusCursor
+	if req.GetCursor() != "" {
+		prev, err = parseStatusCursor(req.GetCursor())
+		if err != nil {
+			return &cluster.PollStatusResponse{}, errors.WithContext("parse cursor", err)
+		}
+	}
+
+	wait := time.Duration(req.GetWaitSeconds()) * time.Second
+	if wait > maxPollWait {
+		wait = maxPollWait
+	}
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	trig := s.statusFetcher.Watch(ctx, user.Namespace, req.GetServices()...)
+
+	for {
+		status, err := s.statusFetcher.Get(user.Namespace)
+		if err != nil {
+			return &cluster.PollStatusResponse{}, err
+		}
+
+		status = filterServices(status, req.GetServices())
+		resp, err := diffStatus(prev, req.GetCursor() == "", status)
+		if err != nil {
+			return &cluster.PollStatusResponse{}, err
+		}
+
+		if !resp.NotModified {
+			return resp, nil
+		}
+
+		select {
+		case <-trig:
+		case <-ctx.Done():
+			return resp, nil
+		}
+	}
+}
+
+// diffStatus returns the changes in `status` since the `prev` cursor. If
+// `full` is set, the full status is returned.
+func diffStatus(prev statusCursor, full bool, status cluster.SandboxStatus) (*cluster.PollStatusResponse, error) {
+	curr := makeStatusCursor(status)
+	currStr, err := curr.encode()
+	if err != nil {
+		return nil, errors.WithContext("encode cursor", err)
+	}
+
+	resp := &cluster.PollStatusResponse{Cursor: currStr}
+	if !full && curr.equal(prev) {
+		resp.NotModified = true
+		return resp, nil
+	}
+
+	resp.Phase = status.Phase
+	resp.Services = map[string]*cluster.ServiceStatus{}
+	for name, svcStatus := range status.Services {
+		if full || prev.Services[name] != curr.Services[name] {
+			resp.Services[name] = svcStatus
+		}
+	}
+
+	if !full {
+		for name := range prev.Services {
+			if _, ok := curr.Services[name]; !ok {
+				resp.RemovedServices = append(resp.RemovedServices, name)
+			}
+		}
+		sort.Strings(resp.RemovedServices)
+	}
+	return resp, nil
+}
+
+func makeStatusCursor(status cluster.SandboxStatus) statusCursor {
+	cursor := statusCursor{
+		Phase:    status.Phase,
+		Services: map[string]string{},
+	}
+	for name, svcStatus := range status.Services {
+		svcStatusBytes, err := proto.Marshal(svcStatus)
+		if err != nil {
+			// Marshalling a valid status never fails, but make sure the
+			// service is reported as changed if it does.
+			svcStatusBytes = []byte(err.Error())
+		}
+		cursor.Services[name] = hash.Bytes(svcStatusBytes)[:16]
+	}
+	return cursor
+}
+
+func (c statusCursor) equal(other statusCursor) bool {
+	if c.Phase != other.Phase || len(c.Services) != len(other.Services) {
+		return false
+	}
+
+	for name, h := range c.Services {
+		if otherHash, ok := other.Services[name]; !ok || otherHash != h {
+			return false
+		}
+	}
+	return true
+}
+
+func (c statusCursor) encode() (string, error) {
+	cursorJSON, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(cursorJSON), nil
+}
+
+func parseStatusCursor(cursorStr string) (statusCursor, error) {
+	cursorJSON, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err != nil {
+		return statusCursor{}, err
+	}
+
+	var cursor statusCursor
+	if err := json.Unmarshal(cursorJSON, &cursor); err != nil {
+		return statusCursor{}, err
+	}
+	return cursor, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func TestDiffStatus(t *testing.T) {
+	running := &cluster.ServiceStatus{Phase: cluster.ServicePhase_RUNNING}
+	pending := &cluster.ServiceStatus{Phase: cluster.ServicePhase_PENDING}
+
+	initial := cluster.SandboxStatus{
+		Phase: cluster.SandboxStatus_RUNNING,
+		Services: map[string]*cluster.ServiceStatus{
+			"web": pending,
+			"db":  running,
+		},
+	}
+
+	// The first poll returns the full status.
+	resp, err := diffStatus(statusCursor{}, true, initial)
+	require.NoError(t, err)
+	assert.False(t, resp.NotModified)
+	assert.Equal(t, cluster.SandboxStatus_RUNNING, resp.Phase)
+	assert.Equal(t, initial.Services, resp.Services)
+
+	cursor, err := parseStatusCursor(resp.Cursor)
+	require.NoError(t, err)
+
+	// Polling again without any changes returns not modified.
+	resp, err = diffStatus(cursor, false, initial)
+	require.NoError(t, err)
+	assert.True(t, resp.NotModified)
+	assert.Empty(t, resp.Services)
+
+	// Only the services that changed are returned.
+	updated := cluster.SandboxStatus{
+		Phase: cluster.SandboxStatus_RUNNING,
+		Services: map[string]*cluster.ServiceStatus{
+			"web":    running,
+			"worker": pending,
+		},
+	}
+	resp, err = diffStatus(cursor, false, updated)
+	require.NoError(t, err)
+	assert.False(t, resp.NotModified)
+	assert.Equal(t, map[string]*cluster.ServiceStatus{
+		"web":    running,
+		"worker": pending,
+	}, resp.Services)
+	assert.Equal(t, []string{"db"}, resp.RemovedServices)
+
+	// Changes to the sandbox phase are returned even if no services changed.
+	cursor, err = parseStatusCursor(resp.Cursor)
+	require.NoError(t, err)
+	updated.Phase = cluster.SandboxStatus_TERMINATING
+	resp, err = diffStatus(cursor, false, updated)
+	require.NoError(t, err)
+	assert.False(t, resp.NotModified)
+	assert.Equal(t, cluster.SandboxStatus_TERMINATING, resp.Phase)
+	assert.Empty(t, resp.Services)
+}
+
+func TestParseStatusCursorInvalid(t *testing.T) {
+	_, err := parseStatusCursor("not a cursor")
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+const (
+	reschedulingMsg = "The node running the service was preempted. " +
+		"Blimp is moving it to another node."
+
+	// rescheduledAtKey is the pod annotation containing the Unix timestamp
+	// of when the pod was moved off of a preempted node.
+	rescheduledAtKey = "io.kelda.blimp/rescheduled-at"
+
+	// preemptionCheckInterval is how often the manager checks for services
+	// on preempted nodes.
+	preemptionCheckInterval = 15 * time.Second
+)
+
+// preemptionTaints are added by cloud providers, or their termination
+// handlers, to spot and preemptible nodes that are about to be reclaimed.
+var preemptionTaints = []string{
+	"cloud.google.com/impending-node-termination",
+	"aws-node-termination-handler/spot-itn",
+	"aws-node-termination-handler/scheduled-maintenance",
+}
+
+// preemptedReasons are the pod status reasons set when a pod is stopped
+// because its node is shutting down.
+var preemptedReasons = map[string]bool{
+	"Shutdown":     true,
+	"NodeShutdown": true,
+	"Terminated":   true,
+	"NodeLost":     true,
+}
+
+// isPreempted returns whether the pod was stopped, or is about to be stopped,
+// because its node is being reclaimed. Blimp deploys services as bare pods,
+// so Kubernetes doesn't reschedule them on its own.
+func (sf *statusFetcher) isPreempted(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodFailed && preemptedReasons[pod.Status.Reason] {
+		return true
+	}
+
+	if pod.Spec.NodeName == "" ||
+		(pod.Status.Phase != corev1.PodPending && pod.Status.Phase != corev1.PodRunning) {
+		return false
+	}
+
+	node, err := sf.nodeLister.Get(pod.Spec.NodeName)
+	if err != nil {
+		return kerrors.IsNotFound(err)
+	}
+	return hasPreemptionTaint(node)
+}
+
+func hasPreemptionTaint(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		for _, key := range preemptionTaints {
+			if taint.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runPreemptionRescheduler moves services off of nodes that were preempted.
+// It never returns.
+func (s *server) runPreemptionRescheduler(interval time.Duration) {
+	for range time.Tick(interval) {
+		pods, err := s.statusFetcher.podLister.List(labels.Set{"blimp.customerPod": "true"}.AsSelector())
+		if err != nil {
+			log.WithError(err).Warn("Failed to list pods")
+			continue
+		}
+
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil || !s.statusFetcher.isPreempted(pod) {
+				continue
+			}
+
+			logger := log.WithField("namespace", pod.Namespace).
+				WithField("pod", pod.Name).
+				WithField("node", pod.Spec.NodeName)
+			if err := s.reschedulePod(pod); err != nil {
+				logger.WithError(err).Warn("Failed to reschedule pod from preempted node")
+				continue
+			}
+			logger.Info("Rescheduled pod from preempted node")
+		}
+	}
+}
+
+// reschedulePod recreates the pod so that it's scheduled onto another node.
+func (s *server) reschedulePod(pod *corev1.Pod) error {
+	// The node cache may not contain nodes that were just created, so make
+	// sure that the node is really gone before deleting the pod.
+	if pod.Status.Phase != corev1.PodFailed {
+		_, err := s.statusFetcher.nodeLister.Get(pod.Spec.NodeName)
+		if kerrors.IsNotFound(err) {
+			_, err := s.kubeClient.CoreV1().Nodes().Get(pod.Spec.NodeName, metav1.GetOptions{})
+			if err == nil {
+				return nil
+			} else if !kerrors.IsNotFound(err) {
+				return errors.WithContext("get node", err)
+			}
+		}
+	}
+
+	placement, err := s.getPlacement(pod.Namespace)
+	if err != nil {
+		return errors.WithContext("get placement", err)
+	}
+
+	// If the sandbox is pinned to the preempted node, the rest of its pods
+	// are going away as well, so try to move the entire sandbox to a new
+	// node. This recreates this pod too.
+	var moved bool
+	if placement.Node == pod.Spec.NodeName {
+		newPlacement, err := s.placeSandbox(pod.Namespace, s.sandboxRequests(pod.Namespace))
+		if err != nil {
+			return errors.WithContext("place sandbox", err)
+		}
+		moved = newPlacement.Node != "" && newPlacement != placement
+		placement = newPlacement
+	}
+
+	if !moved {
+		// Deploy the pod with the spec it was originally deployed with so
+		// that it isn't pinned to the old node, and so that `blimp up`
+		// doesn't recreate it again.
+		newPod := placedPod(appliedPod(pod), placement)
+		if err := kube.DeployPod(s.kubeClient, newPod, kube.DeployPodOptions{ForceRestart: true}); err != nil {
+			return errors.WithContext("deploy pod", err)
+		}
+	}
+
+	podsClient := s.kubeClient.CoreV1().Pods(pod.Namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		curr, err := podsClient.Get(pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if curr.Annotations == nil {
+			curr.Annotations = map[string]string{}
+		}
+		curr.Annotations[rescheduledAtKey] = strconv.FormatInt(time.Now().Unix(), 10)
+		_, err = podsClient.Update(curr)
+		return err
+	})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsPreempted(t *testing.T) {
+	kubeClient := fakeKube.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "healthy"}},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "terminating"},
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{{
+					Key:    "cloud.google.com/impending-node-termination",
+					Effect: corev1.TaintEffectNoSchedule,
+				}},
+			},
+		},
+	)
+	sf := newStatusFetcher(kubeClient, defaultInformerConfig)
+	stop := make(chan struct{})
+	defer close(stop)
+	sf.Start(stop)
+
+	pod := func(node string, phase corev1.PodPhase, reason string) *corev1.Pod {
+		return &corev1.Pod{
+			Spec:   corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{Phase: phase, Reason: reason},
+		}
+	}
+
+	assert.False(t, sf.isPreempted(pod("healthy", corev1.PodRunning, "")))
+	assert.False(t, sf.isPreempted(pod("", corev1.PodPending, "")))
+	assert.False(t, sf.isPreempted(pod("healthy", corev1.PodFailed, "Evicted")))
+	assert.True(t, sf.isPreempted(pod("terminating", corev1.PodRunning, "")))
+	assert.True(t, sf.isPreempted(pod("deleted", corev1.PodRunning, "")))
+	assert.True(t, sf.isPreempted(pod("healthy", corev1.PodFailed, "Terminated")))
+
+	// Pods that already finished on a deleted node aren't restarted.
+	assert.False(t, sf.isPreempted(pod("deleted", corev1.PodSucceeded, "")))
+}
+
+func TestReschedulePod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "ns",
+			Labels:    map[string]string{"blimp.customerPod": "true", "blimp.service": "web"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   "deleted",
+			Containers: []corev1.Container{{Name: "web", Image: "nginx"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	kubeClient := fakeKube.NewSimpleClientset(pod,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}})
+	sf := newStatusFetcher(kubeClient, defaultInformerConfig)
+	stop := make(chan struct{})
+	defer close(stop)
+	sf.Start(stop)
+
+	s := &server{kubeClient: kubeClient, statusFetcher: sf}
+	assert.NoError(t, s.reschedulePod(pod))
+
+	rescheduled, err := kubeClient.CoreV1().Pods("ns").Get("web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, rescheduled.Spec.NodeName)
+	assert.Equal(t, pod.Spec.Containers, rescheduled.Spec.Containers)
+	assert.Equal(t, pod.Labels, rescheduled.Labels)
+	assert.Contains(t, rescheduled.Annotations, rescheduledAtKey)
+	assert.Contains(t, rescheduled.Annotations, "blimp.appliedObject")
+}
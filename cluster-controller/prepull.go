@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/imagecache"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/version"
+)
+
+const (
+	// prePullLabel is set on the pods that pull images ahead of time.
+	prePullLabel = "blimp.prepull"
+
+	// prePullTimeout is how long to wait for images to be pulled before
+	// cleaning up the pre-pull pods.
+	prePullTimeout = 15 * time.Minute
+)
+
+// prePullImages starts pulling the images used by the sandbox's services onto
+// the sandbox's node, so that the images are ready by the time the services
+// are deployed. Each image is pulled by a separate pod so that the kubelet can
+// pull them in parallel. The pods are created in dependency order so that the
+// images for services that must boot first are requested first.
+// Images that are built by Blimp are skipped since they haven't been pushed
+// yet.
+// The pods are deleted once the pulls complete. Failures are only logged since
+// the images will be pulled again when the services are deployed.
+func (s *server) prePullImages(user auth.User, services []composeTypes.ServiceConfig,
+	placement affinity.Placement) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), prePullTimeout)
+	defer cancel()
+
+	logger := log.WithField("namespace", user.Namespace)
+	for i, image := range prePullOrder(services) {
+		// Pull the same image name that the service will use so that the
+		// kubelet can reuse the pulled image.
+		if ImageCacheHostname != "" {
+			if cachedImage, err := imagecache.ToCachedImage(ImageCacheHostname, image); err == nil {
+				image = cachedImage
+			}
+		}
+
+		pod := toPrePullPod(user, i, image, placement)
+		if err := kube.DeployPod(s.kubeClient, pod, kube.DeployPodOptions{ForceRestart: true}); err != nil {
+			logger.WithError(err).WithField("image", image).Warn("Failed to start image pre-pull")
+		}
+	}
+
+	if err := s.waitForPrePulls(ctx, user.Namespace); err != nil {
+		logger.WithError(err).Warn("Failed to wait for image pre-pulls")
+	}
+
+	err := s.kubeClient.CoreV1().Pods(user.Namespace).DeleteCollection(&metav1.DeleteOptions{},
+		metav1.ListOptions{
+			LabelSelector: labels.Set{prePullLabel: "true"}.String(),
+		})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to clean up image pre-pull pods")
+	}
+}
+
+// prePullOrder returns the images to pull. Images for services that have no
+// dependencies come first, followed by the services that depend on them.
+func prePullOrder(services []composeTypes.ServiceConfig) []string {
+	byName := map[string]composeTypes.ServiceConfig{}
+	for _, svc := range services {
+		byName[svc.Name] = svc
+	}
+
+	depths := map[string]int{}
+	var getDepth func(name string, visiting map[string]bool) int
+	getDepth = func(name string, visiting map[string]bool) int {
+		if depth, ok := depths[name]; ok {
+			return depth
+		}
+
+		// Ignore dependency cycles and undefined services.
+		svc, ok := byName[name]
+		if !ok || visiting[name] {
+			return 0
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		var dependencies []string
+		for dep := range svc.DependsOn {
+			dependencies = append(dependencies, dep)
+		}
+		for _, link := range svc.Links {
+			dependencies = append(dependencies, strings.Split(link, ":")[0])
+		}
+
+		depth := 0
+		for _, dep := range dependencies {
+			if depDepth := getDepth(dep, visiting) + 1; depDepth > depth {
+				depth = depDepth
+			}
+		}
+		depths[name] = depth
+		return depth
+	}
+
+	var toPull []composeTypes.ServiceConfig
+	for _, svc := range services {
+		if svc.Build == nil && svc.Image != "" {
+			getDepth(svc.Name, map[string]bool{})
+			toPull = append(toPull, svc)
+		}
+	}
+
+	sort.Slice(toPull, func(i, j int) bool {
+		if depths[toPull[i].Name] != depths[toPull[j].Name] {
+			return depths[toPull[i].Name] < depths[toPull[j].Name]
+		}
+		return toPull[i].Name < toPull[j].Name
+	})
+
+	var images []string
+	seen := map[string]bool{}
+	for _, svc := range toPull {
+		if !seen[svc.Image] {
+			images = append(images, svc.Image)
+			seen[svc.Image] = true
+		}
+	}
+	return images
+}
+
+// toPrePullPod returns a pod that pulls the given image, and then exits
+// immediately. Images may not contain any binaries, so the pod runs a static
+// binary copied from the init image.
+func toPrePullPod(user auth.User, idx int, image string, placement affinity.Placement) corev1.Pod {
+	binMount := corev1.VolumeMount{
+		Name:      "prepullbin",
+		MountPath: "/prepullbin",
+	}
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: user.Namespace,
+			Name:      fmt.Sprintf("prepull-%d", idx),
+			Labels: map[string]string{
+				prePullLabel:                  "true",
+				affinity.ColocateNamespaceKey: user.Namespace,
+			},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{
+					Name:         "copy-bin",
+					Image:        version.InitImage,
+					Command:      []string{"/bin/cp", "/bin/busybox.static", "/prepullbin/true"},
+					VolumeMounts: []corev1.VolumeMount{binMount},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:            "pull",
+					Image:           image,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Command:         []string{"/prepullbin/true"},
+					VolumeMounts:    []corev1.VolumeMount{binMount},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: binMount.Name,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+			},
+			Affinity:           affinity.ForUser(user, placement),
+			Tolerations:        affinity.Tolerations(),
+			ServiceAccountName: "pod-runner",
+			RestartPolicy:      corev1.RestartPolicyNever,
+		},
+	}
+}
+
+// waitForPrePulls blocks until all the pre-pull pods have either pulled their
+// image, or failed to pull it.
+func (s *server) waitForPrePulls(ctx context.Context, namespace string) error {
+	podsChanged := s.statusFetcher.podWatcher.Watch(ctx, kube.Key{Namespace: namespace})
+	selector := labels.Set{prePullLabel: "true"}.AsSelector()
+	for {
+		pods, err := s.statusFetcher.podLister.Pods(namespace).List(selector)
+		if err != nil {
+			return errors.WithContext("list pods", err)
+		}
+
+		done := true
+		for _, pod := range pods {
+			if !prePullDone(pod) {
+				done = false
+				break
+			}
+		}
+
+		if done {
+			return nil
+		}
+
+		select {
+		case <-podsChanged:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func prePullDone(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return true
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Running != nil || cs.State.Terminated != nil || isImagePullFailure(cs) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrePullOrder(t *testing.T) {
+	services := []composeTypes.ServiceConfig{
+		{
+			Name:  "web",
+			Image: "nginx",
+			DependsOn: composeTypes.DependsOnConfig{
+				"api": {Condition: composeTypes.ServiceConditionStarted},
+			},
+		},
+		{
+			Name:  "api",
+			Build: &composeTypes.BuildConfig{},
+			Links: []string{"db:database"},
+		},
+		{
+			Name:  "worker",
+			Image: "worker",
+			Links: []string{"db"},
+		},
+		{
+			Name:  "db",
+			Image: "postgres",
+		},
+		{
+			Name:  "cache",
+			Image: "redis",
+		},
+		{
+			Name:  "replica",
+			Image: "postgres",
+		},
+	}
+
+	assert.Equal(t, []string{"redis", "postgres", "worker", "nginx"}, prePullOrder(services))
+}
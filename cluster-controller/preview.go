@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"time"
@@ -14,12 +15,17 @@ import (
 
 	"github.com/kelda/blimp/pkg/auth"
 	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/expose"
 	"github.com/kelda/blimp/pkg/kube"
 	"github.com/kelda/blimp/pkg/names"
 	"github.com/kelda/blimp/pkg/proto/cluster"
 	"github.com/kelda/blimp/pkg/version"
 )
 
+// previewCLIContainer is the name of the container that runs the Blimp CLI
+// in preview pods.
+const previewCLIContainer = "blimp-cli"
+
 //nolint:deadcode,unused
 func createCLINamespace(kubeClient kubernetes.Interface) {
 	for {
@@ -39,28 +45,41 @@ func createCLINamespace(kubeClient kubernetes.Interface) {
 	}
 }
 
-func (s *server) BlimpUpPreview(req *cluster.BlimpUpPreviewRequest, srv cluster.Manager_BlimpUpPreviewServer) error {
-	user, err := auth.AuthorizeRequest(req.GetAuth())
-	if err != nil {
-		return err
-	}
+// cliPodOptions configures a pod that runs `blimp up` against a Git
+// repository.
+type cliPodOptions struct {
+	name         string
+	token        string
+	repo         string
+	ref          string
+	composeFiles []string
+	env          map[string]string
+
+	// forceRestart reruns the CLI even if the pod's spec hasn't changed, so
+	// that new commits to ref are picked up.
+	forceRestart bool
+}
 
+func (s *server) deployCLIPod(opts cliPodOptions) (corev1.Pod, error) {
 	blimpCmd := []string{"blimp", "up", "-d", "--disable-status-output"}
-	for _, f := range req.GetComposeFiles() {
+	for _, f := range opts.composeFiles {
 		blimpCmd = append(blimpCmd, "-f", f)
 	}
 
 	env := []corev1.EnvVar{
 		{
 			Name:  "BLIMP_TOKEN",
-			Value: req.GetAuth().GetToken(),
+			Value: opts.token,
 		},
 		{
 			Name:  "GIT_REPO",
-			Value: req.GetRepo(),
+			Value: opts.repo,
 		},
 	}
-	for k, v := range req.GetEnv() {
+	if opts.ref != "" {
+		env = append(env, corev1.EnvVar{Name: "GIT_REF", Value: opts.ref})
+	}
+	for k, v := range opts.env {
 		env = append(env, corev1.EnvVar{Name: k, Value: v})
 	}
 
@@ -70,12 +89,12 @@ func (s *server) BlimpUpPreview(req *cluster.BlimpUpPreviewRequest, srv cluster.
 	// the CLI to be running after it completes the initial boot anyways.
 	pod := corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      names.ToDNS1123(fmt.Sprintf("blimp-cli-%s", user.Namespace)),
+			Name:      names.ToDNS1123(opts.name),
 			Namespace: kube.PreviewCLINamespace,
 		},
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{{
-				Name:  "blimp-cli",
+				Name:  previewCLIContainer,
 				Image: version.CLIImage,
 				Resources: corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
@@ -94,7 +113,23 @@ func (s *server) BlimpUpPreview(req *cluster.BlimpUpPreviewRequest, srv cluster.
 		},
 	}
 
-	err = kube.DeployPod(s.kubeClient, pod, kube.DeployPodOptions{})
+	err := kube.DeployPod(s.kubeClient, pod, kube.DeployPodOptions{ForceRestart: opts.forceRestart})
+	return pod, err
+}
+
+func (s *server) BlimpUpPreview(req *cluster.BlimpUpPreviewRequest, srv cluster.Manager_BlimpUpPreviewServer) error {
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return err
+	}
+
+	pod, err := s.deployCLIPod(cliPodOptions{
+		name:         fmt.Sprintf("blimp-cli-%s", user.Namespace),
+		token:        req.GetAuth().GetToken(),
+		repo:         req.GetRepo(),
+		composeFiles: req.GetComposeFiles(),
+		env:          req.GetEnv(),
+	})
 	if err != nil {
 		return srv.Send(&cluster.BlimpUpPreviewResponse{
 			Error: errors.Marshal(errors.WithContext("start cli", err)),
@@ -120,7 +155,10 @@ func (s *server) BlimpUpPreview(req *cluster.BlimpUpPreviewRequest, srv cluster.
 	}
 
 	logsReq := s.kubeClient.CoreV1().Pods(pod.Namespace).
-		GetLogs(pod.Name, &corev1.PodLogOptions{Follow: true})
+		GetLogs(pod.Name, &corev1.PodLogOptions{
+			Container: previewCLIContainer,
+			Follow:    true,
+		})
 	logsStream, err := logsReq.Stream()
 	if err != nil {
 		return errors.WithContext("start logs stream", err)
@@ -143,3 +181,123 @@ func (s *server) BlimpUpPreview(req *cluster.BlimpUpPreviewRequest, srv cluster.
 		}
 	}
 }
+
+// CreatePreview boots a sandbox for a pull request, and exposes the given
+// service so that it can be previewed. It returns once the CLI that deploys
+// the sandbox has been started, so the sandbox may still be booting.
+func (s *server) CreatePreview(ctx context.Context, req *cluster.CreatePreviewRequest) (
+	*cluster.CreatePreviewResponse, error) {
+	if _, err := auth.AuthorizeRequest(req.GetAuth()); err != nil {
+		return &cluster.CreatePreviewResponse{}, err
+	}
+
+	pr := req.GetPullRequest()
+	if pr.GetRepo() == "" || pr.GetNumber() == 0 {
+		return &cluster.CreatePreviewResponse{}, errors.NewFriendlyError(
+			"A repository and pull request number are required")
+	}
+	if req.GetCloneUrl() == "" {
+		return &cluster.CreatePreviewResponse{}, errors.NewFriendlyError("A clone URL is required")
+	}
+	if req.GetService() != "" && (req.GetPort() < 1 || req.GetPort() > 65535) {
+		return &cluster.CreatePreviewResponse{}, errors.NewFriendlyError("Port must be between 1 and 65535")
+	}
+
+	token := auth.PreviewToken(pr.GetRepo(), pr.GetNumber())
+	user, err := auth.ParseIDToken(token)
+	if err != nil {
+		return &cluster.CreatePreviewResponse{}, err
+	}
+
+	// Create the namespace before the CLI boots the sandbox so that we can
+	// return the link right away.
+	if err := s.createNamespace(ctx, user); err != nil {
+		return &cluster.CreatePreviewResponse{}, errors.WithContext("create namespace", err)
+	}
+
+	_, err = s.deployCLIPod(cliPodOptions{
+		name:         fmt.Sprintf("blimp-cli-%s", user.Namespace),
+		token:        token,
+		repo:         req.GetCloneUrl(),
+		ref:          req.GetRef(),
+		composeFiles: req.GetComposeFiles(),
+		env:          req.GetEnv(),
+		forceRestart: true,
+	})
+	if err != nil {
+		return &cluster.CreatePreviewResponse{}, errors.WithContext("start cli", err)
+	}
+
+	var link string
+	if req.GetService() != "" {
+		link, err = s.previewLink(user.Namespace, expose.ExposeInfo{
+			Service: req.GetService(),
+			Port:    int(req.GetPort()),
+		})
+		if err != nil {
+			return &cluster.CreatePreviewResponse{}, errors.WithContext("expose service", err)
+		}
+	}
+
+	log.WithField("namespace", user.Namespace).Info("Created preview sandbox")
+	return &cluster.CreatePreviewResponse{Namespace: user.Namespace, Link: link}, nil
+}
+
+// previewLink returns the link to the given service. Links are reused so that
+// the URL stays the same as new commits are pushed to the pull request.
+func (s *server) previewLink(namespace string, exposeInfo expose.ExposeInfo) (string, error) {
+	ns, err := s.kubeClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.WithContext("get namespace", err)
+	}
+
+	if annotationJSON, ok := ns.Annotations[kube.ExposeAnnotation]; ok {
+		annotation, err := expose.ParseJsonAnnotation(annotationJSON)
+		if err != nil {
+			return "", errors.WithContext("parse expose annotation", err)
+		}
+
+		for secret, info := range annotation {
+			if info == exposeInfo {
+				return exposeLink(namespace, secret), nil
+			}
+		}
+	}
+	return s.expose(namespace, exposeInfo)
+}
+
+// DeletePreview deletes the sandbox for a pull request, including its
+// volumes. It succeeds if the sandbox was already deleted.
+func (s *server) DeletePreview(ctx context.Context, req *cluster.DeletePreviewRequest) (
+	*cluster.DeletePreviewResponse, error) {
+	if _, err := auth.AuthorizeRequest(req.GetAuth()); err != nil {
+		return &cluster.DeletePreviewResponse{}, err
+	}
+
+	pr := req.GetPullRequest()
+	if pr.GetRepo() == "" || pr.GetNumber() == 0 {
+		return &cluster.DeletePreviewResponse{}, errors.NewFriendlyError(
+			"A repository and pull request number are required")
+	}
+
+	namespace := names.PreviewNamespace(pr.GetRepo(), pr.GetNumber())
+	cliPod := names.ToDNS1123(fmt.Sprintf("blimp-cli-%s", namespace))
+	if err := kube.DeletePod(s.kubeClient, kube.PreviewCLINamespace, cliPod); err != nil && !kerrors.IsNotFound(err) {
+		return &cluster.DeletePreviewResponse{}, errors.WithContext("delete cli", err)
+	}
+
+	_, err := s.kubeClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		return &cluster.DeletePreviewResponse{}, nil
+	case err != nil:
+		return &cluster.DeletePreviewResponse{}, errors.WithContext("get sandbox", err)
+	}
+
+	if err := s.deleteSandbox(namespace, true); err != nil {
+		return &cluster.DeletePreviewResponse{}, err
+	}
+
+	log.WithField("namespace", namespace).Info("Deleted preview sandbox")
+	return &cluster.DeletePreviewResponse{}, nil
+}
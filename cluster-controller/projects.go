@@ -0,0 +1,84 @@
+package main
+
+import (
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// projectLabel marks the objects deployed for a named Compose project, so
+// that deploying one project doesn't remove the services of the other
+// projects in the sandbox. Objects in the default project don't have the
+// label.
+const projectLabel = "blimp.project"
+
+// projectSelector returns a label selector that matches the objects in the
+// given project.
+func projectSelector(project string) labels.Selector {
+	var req *labels.Requirement
+	if project == "" {
+		req, _ = labels.NewRequirement(projectLabel, selection.DoesNotExist, nil)
+	} else {
+		req, _ = labels.NewRequirement(projectLabel, selection.Equals, []string{project})
+	}
+	return labels.NewSelector().Add(*req)
+}
+
+// setProjectLabel labels the object as part of the given project.
+func setProjectLabel(meta *metav1.ObjectMeta, project string) {
+	if project == "" {
+		return
+	}
+
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	meta.Labels[projectLabel] = project
+}
+
+// deleteProject removes the services in the given project from the sandbox.
+// It returns false if the project is the only one in the sandbox, in which
+// case nothing is deleted so that the entire sandbox can be removed instead.
+func (s *server) deleteProject(namespace, project string) (bool, error) {
+	client := s.sandboxes.client.Namespace(namespace)
+	sandboxes, err := client.List(metav1.ListOptions{})
+	if err != nil {
+		return false, errors.WithContext("list sandbox resources", err)
+	}
+
+	name := getSandboxObjectName(project)
+	otherProjects := false
+	for _, sandbox := range sandboxes.Items {
+		if sandbox.GetName() != name {
+			otherProjects = true
+			break
+		}
+	}
+	if !otherProjects {
+		return false, nil
+	}
+
+	unlock := s.sandboxes.lock(namespace, name)
+	defer unlock()
+
+	err = client.Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return false, errors.WithContext("delete sandbox resource", err)
+	}
+
+	if err := s.deployManifests(namespace, project, manifestObjects{}); err != nil {
+		return false, errors.WithContext("delete kubernetes manifests", err)
+	}
+
+	if err := s.deployCronJobs(namespace, project, nil); err != nil {
+		return false, errors.WithContext("delete scheduled services", err)
+	}
+
+	if err := s.deployCustomerPods(namespace, project, nil, nil); err != nil {
+		return false, errors.WithContext("delete pods", err)
+	}
+	return true, nil
+}
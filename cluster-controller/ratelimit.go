@@ -0,0 +1,175 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	rateLimitedMsg = "The image registry is rate limiting pulls. Blimp will retry automatically. " +
+		"Run `docker login` before `blimp up` to use your account's rate limit."
+
+	// rateLimitRetriesKey is the pod annotation tracking how many times the
+	// manager has retried a rate limited image pull. It's preserved across
+	// retries so that the backoff grows, and reset when the service is
+	// redeployed.
+	rateLimitRetriesKey = "io.kelda.blimp/rate-limit-retries"
+
+	// rateLimitRetryAtKey is the pod annotation containing the Unix
+	// timestamp of the next retry.
+	rateLimitRetryAtKey = "io.kelda.blimp/rate-limit-retry-at"
+
+	// rateLimitCheckInterval is how often the manager checks for rate
+	// limited pods that are ready to be retried.
+	rateLimitCheckInterval = 30 * time.Second
+
+	rateLimitInitialBackoff = 2 * time.Minute
+	rateLimitMaxBackoff     = time.Hour
+)
+
+// isRateLimited returns whether the container's image pull failed because the
+// registry rejected it with a 429.
+func (sf *statusFetcher) isRateLimited(pod *corev1.Pod, fieldPath string, cs corev1.ContainerStatus) bool {
+	if cs.State.Waiting != nil && isRateLimitMessage(cs.State.Waiting.Message) {
+		return true
+	}
+
+	// Once the kubelet starts backing off, the container status only says
+	// that it's backing off, so check the most recent pull failure.
+	events, err := sf.eventsLister.Events(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		log.WithError(err).Warn("Failed to get events")
+		return false
+	}
+
+	var lastFailure *corev1.Event
+	for _, event := range events {
+		if event.InvolvedObject.Kind != "Pod" ||
+			event.InvolvedObject.Name != pod.Name ||
+			event.InvolvedObject.FieldPath != fieldPath ||
+			event.Reason != "Failed" {
+			continue
+		}
+
+		if lastFailure == nil || eventTimestamp(lastFailure).Before(eventTimestamp(event)) {
+			lastFailure = event
+		}
+	}
+	return lastFailure != nil && isRateLimitMessage(lastFailure.Message)
+}
+
+func isRateLimitMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "toomanyrequests") ||
+		strings.Contains(msg, "429 too many requests") ||
+		strings.Contains(msg, "pull rate limit")
+}
+
+func rateLimitedStatus(pod *corev1.Pod) cluster.ServiceStatus {
+	status := cluster.ServiceStatus{
+		Phase: cluster.ServicePhase_RATE_LIMITED,
+		Msg:   rateLimitedMsg,
+	}
+	if retryAt, err := strconv.ParseInt(pod.Annotations[rateLimitRetryAtKey], 10, 64); err == nil {
+		status.RetryAt = retryAt
+	}
+	return status
+}
+
+// rateLimitBackoff returns how long to wait before the given retry. The
+// backoff doubles after each retry.
+func rateLimitBackoff(retries int) time.Duration {
+	backoff := rateLimitInitialBackoff
+	for i := 1; i < retries && backoff < rateLimitMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > rateLimitMaxBackoff {
+		backoff = rateLimitMaxBackoff
+	}
+	return backoff
+}
+
+// runRateLimitRetrier restarts services whose image pulls were rate limited
+// once their backoff expires. Restarting the pod resets the kubelet's own
+// backoff, which can otherwise keep the service waiting long after the rate
+// limit has reset.
+func (s *server) runRateLimitRetrier(interval time.Duration) {
+	for range time.Tick(interval) {
+		pods, err := s.statusFetcher.podLister.List(labels.Set{"blimp.customerPod": "true"}.AsSelector())
+		if err != nil {
+			log.WithError(err).Warn("Failed to list pods")
+			continue
+		}
+
+		for _, pod := range pods {
+			if s.statusFetcher.getServiceStatus(pod).Phase != cluster.ServicePhase_RATE_LIMITED {
+				continue
+			}
+
+			logger := log.WithField("namespace", pod.Namespace).WithField("pod", pod.Name)
+			retryAt, err := strconv.ParseInt(pod.Annotations[rateLimitRetryAtKey], 10, 64)
+			switch {
+			case err != nil:
+				if err := s.scheduleRateLimitRetry(pod); err != nil {
+					logger.WithError(err).Warn("Failed to schedule rate limit retry")
+				}
+			case time.Now().Unix() >= retryAt:
+				logger.Info("Retrying rate limited image pull")
+				if err := s.retryRateLimitedPod(pod); err != nil {
+					logger.WithError(err).Warn("Failed to retry rate limited image pull")
+				}
+			}
+		}
+	}
+}
+
+func (s *server) scheduleRateLimitRetry(pod *corev1.Pod) error {
+	retries, _ := strconv.Atoi(pod.Annotations[rateLimitRetriesKey])
+	retries++
+
+	pod = pod.DeepCopy()
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[rateLimitRetriesKey] = strconv.Itoa(retries)
+	pod.Annotations[rateLimitRetryAtKey] = strconv.FormatInt(
+		time.Now().Add(rateLimitBackoff(retries)).Unix(), 10)
+
+	if _, err := s.kubeClient.CoreV1().Pods(pod.Namespace).Update(pod); err != nil {
+		return errors.WithContext("update pod", err)
+	}
+	return nil
+}
+
+// retryRateLimitedPod recreates the pod so that the kubelet immediately
+// retries the pull. The retry count is preserved so that the next backoff is
+// longer if the pull is rate limited again.
+func (s *server) retryRateLimitedPod(pod *corev1.Pod) error {
+	newPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pod.Name,
+			Namespace:   pod.Namespace,
+			Labels:      pod.Labels,
+			Annotations: map[string]string{},
+		},
+		Spec: pod.Spec,
+	}
+	for k, v := range pod.Annotations {
+		if metadata.IsCustomPodAnnotation(pod.Annotations, k) || k == rateLimitRetriesKey {
+			newPod.Annotations[k] = v
+		}
+	}
+
+	return kube.DeployPod(s.kubeClient, newPod, kube.DeployPodOptions{ForceRestart: true})
+}
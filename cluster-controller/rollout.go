@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+)
+
+// surgeLabel marks the temporary pods that serve a service's traffic while
+// the service's pod is recreated.
+const surgeLabel = "blimp.surge"
+
+// surgeReadyTimeout is how long to wait for a surge pod to become ready
+// before falling back to recreating the service's pod directly.
+const surgeReadyTimeout = 2 * time.Minute
+
+// getSurgeableServices returns the services that can be updated by booting
+// the new version before removing the old one. Services that mount named
+// volumes are always recreated, since two versions of a service writing to
+// the same volume at once could corrupt it. Services without ports don't
+// receive any traffic, so there's no downtime to avoid.
+func getSurgeableServices(services []composeTypes.ServiceConfig) map[string]struct{} {
+	surgeable := map[string]struct{}{}
+	for _, svc := range services {
+		if len(svc.Ports) == 0 {
+			continue
+		}
+
+		hasNamedVolume := false
+		for _, v := range svc.Volumes {
+			if v.Type == composeTypes.VolumeTypeVolume {
+				hasNamedVolume = true
+				break
+			}
+		}
+		if !hasNamedVolume {
+			surgeable[svc.Name] = struct{}{}
+		}
+	}
+	return surgeable
+}
+
+// surgeDeployPod updates the pod without downtime if it's out of date. It
+// boots a copy of the new version of the pod, waits for it to become ready,
+// and then recreates the pod. The copy is removed once the recreated pod is
+// ready. DNS and tunnels resolve to the copy while the pod is recreated.
+func (s *server) surgeDeployPod(pod corev1.Pod, opts kube.DeployPodOptions) error {
+	outOfDate, err := kube.PodOutOfDate(s.kubeClient, pod, opts)
+	if err != nil {
+		return err
+	}
+
+	if !outOfDate {
+		return kube.DeployPod(s.kubeClient, pod, opts)
+	}
+
+	surge := toSurgePod(pod)
+	if err := kube.DeployPod(s.kubeClient, surge, kube.DeployPodOptions{ForceRestart: true}); err != nil {
+		return errors.WithContext("create surge pod", err)
+	}
+	defer func() {
+		if err := kube.DeletePod(s.kubeClient, surge.Namespace, surge.Name); err != nil {
+			log.WithError(err).WithField("pod", surge.Name).Warn("Failed to delete surge pod")
+		}
+	}()
+
+	logger := log.WithField("namespace", pod.Namespace).WithField("pod", pod.Name)
+	if err := s.waitForPodReady(pod.Namespace, surge.Name); err != nil {
+		// Fall back to recreating the pod. The new version is probably
+		// broken, so there's no point in keeping the old version around.
+		logger.WithError(err).Info("Surge pod didn't become ready. Recreating pod without surge")
+		return kube.DeployPod(s.kubeClient, pod, opts)
+	}
+
+	if err := kube.DeployPod(s.kubeClient, pod, opts); err != nil {
+		return err
+	}
+
+	if err := s.waitForPodReady(pod.Namespace, pod.Name); err != nil {
+		logger.WithError(err).Warn("Updated pod didn't become ready")
+	}
+	return nil
+}
+
+func (s *server) waitForPodReady(namespace, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), surgeReadyTimeout)
+	defer cancel()
+	_, err := s.getPod(ctx, namespace, name, podIsReady)
+	return err
+}
+
+// toSurgePod returns a copy of the pod that can run alongside it.
+func toSurgePod(pod corev1.Pod) corev1.Pod {
+	surge := *pod.DeepCopy()
+	surge.Name = names.ToDNS1123(pod.Name + "-surge")
+	surge.Labels[surgeLabel] = "true"
+	return surge
+}
+
+func isSurgePod(pod *corev1.Pod) bool {
+	return pod.Labels[surgeLabel] == "true"
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSurgeableServices(t *testing.T) {
+	ports := []composeTypes.ServicePortConfig{{Target: 80}}
+	surgeable := getSurgeableServices([]composeTypes.ServiceConfig{
+		{Name: "web", Ports: ports},
+		{Name: "worker"},
+		{
+			Name:  "db",
+			Ports: ports,
+			Volumes: []composeTypes.ServiceVolumeConfig{
+				{Type: composeTypes.VolumeTypeVolume, Source: "data", Target: "/data"},
+			},
+		},
+		{
+			Name:  "app",
+			Ports: ports,
+			Volumes: []composeTypes.ServiceVolumeConfig{
+				{Type: composeTypes.VolumeTypeBind, Source: "/src", Target: "/app"},
+			},
+		},
+	})
+	assert.Equal(t, map[string]struct{}{"web": {}, "app": {}}, surgeable)
+}
+
+func TestToSurgePod(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "web",
+			Labels: map[string]string{"blimp.service": "web"},
+		},
+	}
+
+	surge := toSurgePod(pod)
+	assert.True(t, strings.HasPrefix(surge.Name, "web-surge-"), surge.Name)
+	assert.True(t, isSurgePod(&surge))
+	assert.Equal(t, "web", surge.Labels["blimp.service"])
+
+	// The original pod shouldn't be modified.
+	assert.False(t, isSurgePod(&pod))
+}
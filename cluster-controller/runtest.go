@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+	"github.com/kelda/blimp/pkg/version"
+)
+
+// testResultsGracePeriod is how long the test pod is kept around after the
+// tests finish so that the client can collect the results.
+const testResultsGracePeriod = 10 * time.Minute
+
+// RunTest runs the given service as a Job in the user's sandbox, and streams
+// its output back to the client. The Job is derived from the service's
+// running pod, so it has the same image, volumes, and environment, and can
+// reach the other services in the sandbox. When the tests are sharded, each
+// shard runs in a temporary clone of the sandbox that's deleted afterwards.
+func (s *server) RunTest(req *cluster.RunTestRequest, srv cluster.Manager_RunTestServer) error {
+	log.Info("Start RunTest")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return err
+	}
+
+	s.recordActivity(user.Namespace)
+
+	// Shards run in their own clone of the sandbox so that they don't
+	// interfere with each other.
+	namespace := user.Namespace
+	var env []corev1.EnvVar
+	if req.GetShardCount() > 1 {
+		if req.GetShardIndex() < 0 || req.GetShardIndex() >= req.GetShardCount() {
+			return srv.Send(&cluster.RunTestResponse{
+				Error: errors.Marshal(errors.New("shard index %d out of range", req.GetShardIndex())),
+			})
+		}
+
+		namespace = cloneNamespace(user.Namespace, int(req.GetShardIndex()))
+		defer func() {
+			if err := s.deleteSandbox(namespace, true); err != nil {
+				log.WithError(err).WithField("namespace", namespace).Warn("Failed to delete sandbox clone")
+			}
+		}()
+
+		if err := s.cloneSandbox(srv.Context(), user, namespace, req.GetCopyVolumes()); err != nil {
+			return srv.Send(&cluster.RunTestResponse{
+				Error: errors.Marshal(errors.WithContext("clone sandbox", err)),
+			})
+		}
+
+		env = []corev1.EnvVar{
+			{Name: "BLIMP_TEST_SHARD_INDEX", Value: fmt.Sprintf("%d", req.GetShardIndex())},
+			{Name: "BLIMP_TEST_SHARD_COUNT", Value: fmt.Sprintf("%d", req.GetShardCount())},
+		}
+	}
+
+	svcPod, err := s.kubeClient.CoreV1().Pods(namespace).
+		Get(names.ToDNS1123(req.GetService()), metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return srv.Send(&cluster.RunTestResponse{
+				Error: errors.Marshal(errors.NewFriendlyError(
+					"Service %q isn't running. Make sure that it's defined in "+
+						"your Docker Compose file, and that `blimp up` is running.",
+					req.GetService())),
+			})
+		}
+		return srv.Send(&cluster.RunTestResponse{
+			Error: errors.Marshal(errors.WithContext("get service pod", err)),
+		})
+	}
+
+	job := toTestJob(namespace, req.GetService(), svcPod, req.GetCommand(), env)
+	job.Spec.Template, err = kube.PatchSandboxPodTemplate(namespace, job.Spec.Template)
+	if err != nil {
+		return srv.Send(&cluster.RunTestResponse{
+			Error: errors.Marshal(errors.WithContext("patch pod template", err)),
+		})
+	}
+	jobsClient := s.kubeClient.BatchV1().Jobs(namespace)
+
+	// Clean up any test runs for this service that weren't cleaned up
+	// properly, such as if the manager restarted in the middle of a run.
+	propagationPolicy := metav1.DeletePropagationBackground
+	deleteOpts := &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}
+	err = jobsClient.DeleteCollection(deleteOpts, metav1.ListOptions{
+		LabelSelector: labels.Set(map[string]string{
+			"blimp.testService": req.GetService(),
+		}).String(),
+	})
+	if err != nil {
+		log.WithError(err).WithField("namespace", namespace).Warn("Failed to delete old test jobs")
+	}
+
+	createdJob, err := jobsClient.Create(&job)
+	if err != nil {
+		return srv.Send(&cluster.RunTestResponse{
+			Error: errors.Marshal(errors.WithContext("create job", err)),
+		})
+	}
+	job = *createdJob
+	defer func() {
+		if err := jobsClient.Delete(job.Name, deleteOpts); err != nil && !kerrors.IsNotFound(err) {
+			log.WithError(err).WithField("namespace", namespace).Warn("Failed to delete test job")
+		}
+	}()
+
+	testContainer := job.Spec.Template.Spec.Containers[0].Name
+	started := func(status corev1.ContainerStatus) bool {
+		return status.State.Running != nil || status.State.Terminated != nil
+	}
+	pod, err := s.waitForJobPod(srv.Context(), job, testContainer, started)
+	if err != nil {
+		return srv.Send(&cluster.RunTestResponse{
+			Error: errors.Marshal(errors.WithContext("tests never started", err)),
+		})
+	}
+
+	if err := srv.Send(&cluster.RunTestResponse{PodName: pod.Name, Namespace: pod.Namespace}); err != nil {
+		return errors.WithContext("send", err)
+	}
+
+	logsReq := s.kubeClient.CoreV1().Pods(pod.Namespace).
+		GetLogs(pod.Name, &corev1.PodLogOptions{Container: testContainer, Follow: true})
+	logsStream, err := logsReq.Stream()
+	if err != nil {
+		return srv.Send(&cluster.RunTestResponse{
+			Error: errors.Marshal(errors.WithContext("start logs stream", err)),
+		})
+	}
+	defer logsStream.Close()
+
+	output := make([]byte, 16*1024)
+	for {
+		n, err := logsStream.Read(output)
+		if n > 0 {
+			if err := srv.Send(&cluster.RunTestResponse{Output: output[:n]}); err != nil {
+				return errors.WithContext("send", err)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return srv.Send(&cluster.RunTestResponse{
+				Error: errors.Marshal(errors.WithContext("read logs", err)),
+			})
+		}
+	}
+
+	exited := func(status corev1.ContainerStatus) bool {
+		return status.State.Terminated != nil
+	}
+	pod, err = s.waitForJobPod(srv.Context(), job, testContainer, exited)
+	if err != nil {
+		return srv.Send(&cluster.RunTestResponse{
+			Error: errors.Marshal(errors.WithContext("wait for tests to exit", err)),
+		})
+	}
+
+	var exitCode int32
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == testContainer {
+			exitCode = status.State.Terminated.ExitCode
+		}
+	}
+
+	err = srv.Send(&cluster.RunTestResponse{
+		PodName:   pod.Name,
+		Namespace: pod.Namespace,
+		Finished:  true,
+		ExitCode:  exitCode,
+	})
+	if err != nil {
+		return errors.WithContext("send", err)
+	}
+
+	// Keep the pod around until the client has collected the test results.
+	// The client closes the stream once it's done.
+	select {
+	case <-srv.Context().Done():
+	case <-time.After(testResultsGracePeriod):
+	}
+	return nil
+}
+
+// waitForJobPod blocks until the given container in the Job's pod satisfies
+// `cond`.
+func (s *server) waitForJobPod(ctx context.Context, job batchv1.Job, container string,
+	cond func(corev1.ContainerStatus) bool) (*corev1.Pod, error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	podsChanged := s.statusFetcher.podWatcher.Watch(ctx, kube.Key{Namespace: job.Namespace})
+	selector := labels.Set(map[string]string{"job-name": job.Name}).AsSelector()
+	for {
+		pods, err := s.statusFetcher.podLister.Pods(job.Namespace).List(selector)
+		if err != nil {
+			return nil, errors.WithContext("list pods", err)
+		}
+
+		for _, pod := range pods {
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.Name == container && cond(status) {
+					return pod, nil
+				}
+			}
+
+			if pod.Status.Phase == corev1.PodFailed {
+				return nil, errors.New("pod failed: %s", pod.Status.Message)
+			}
+		}
+
+		select {
+		case <-podsChanged:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// toTestJob creates a Job that runs the given service to completion. The Job
+// mounts a results directory that's shared with a sidecar container, so that
+// test artifacts can be copied out after the tests exit.
+func toTestJob(namespace, svc string, svcPod *corev1.Pod, command []string, env []corev1.EnvVar) batchv1.Job {
+	podSpec := *svcPod.Spec.DeepCopy()
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+	podSpec.NodeName = ""
+	podSpec.Hostname = ""
+
+	resultsVolume := corev1.Volume{
+		Name: "blimp-test-results",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	resultsMount := corev1.VolumeMount{
+		Name:      resultsVolume.Name,
+		MountPath: kube.TestResultsDir,
+	}
+	podSpec.Volumes = append(podSpec.Volumes, resultsVolume)
+
+	testContainer := &podSpec.Containers[0]
+	if len(command) != 0 {
+		testContainer.Args = command
+	}
+	testContainer.VolumeMounts = append(testContainer.VolumeMounts, resultsMount)
+	testContainer.Env = append(testContainer.Env, corev1.EnvVar{
+		Name:  "BLIMP_TEST_RESULTS_DIR",
+		Value: kube.TestResultsDir,
+	})
+	testContainer.Env = append(testContainer.Env, env...)
+
+	// The sidecar keeps the pod running after the tests exit so that `blimp
+	// test` can copy the results out of the shared volume.
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:         kube.ContainerNameTestResults,
+		Image:        version.InitImage,
+		Command:      []string{"sh", "-c", "trap 'exit 0' TERM; while true; do sleep 1; done"},
+		VolumeMounts: []corev1.VolumeMount{resultsMount},
+	})
+
+	var backoffLimit int32
+	return batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: names.ToDNS1123(fmt.Sprintf("test-%s", svc)) + "-",
+			Namespace:    namespace,
+			Labels: map[string]string{
+				"blimp.testService": svc,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					// Don't set the blimp.customerPod label so that the test
+					// pod doesn't show up in the sandbox status, and isn't
+					// garbage collected by `blimp up`.
+					Labels: map[string]string{
+						"blimp.testService":           svc,
+						affinity.ColocateNamespaceKey: namespace,
+					},
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// defaultRuntimeClassOverride can be set as a namespace's
+// kube.RuntimeClassAnnotation to run its pods with the cluster's default
+// runtime, even if $SANDBOX_RUNTIME_CLASS is set.
+const defaultRuntimeClassOverride = "default"
+
+// runtimeClasses checks that RuntimeClasses exist before pods are deployed
+// with them, since pods that reference a missing RuntimeClass are rejected
+// without an explanation that users can act on.
+type runtimeClasses struct {
+	kubeClient kubernetes.Interface
+
+	lock   sync.Mutex
+	exists map[string]struct{}
+}
+
+// configureRuntimeClass runs sandbox pods with the RuntimeClass in
+// $SANDBOX_RUNTIME_CLASS, so that operators can isolate untrusted workloads
+// with runtimes such as gVisor or Kata Containers. Individual sandboxes can be
+// moved to a different RuntimeClass by setting kube.RuntimeClassAnnotation on
+// their namespace.
+func configureRuntimeClass(kubeClient kubernetes.Interface, namespaceLister listers.NamespaceLister) error {
+	classes := &runtimeClasses{kubeClient: kubeClient, exists: map[string]struct{}{}}
+
+	defaultClass := os.Getenv("SANDBOX_RUNTIME_CLASS")
+	if defaultClass != "" {
+		if err := classes.check(defaultClass); err != nil {
+			return err
+		}
+		log.WithField("runtimeClass", defaultClass).Info("Running sandbox pods with RuntimeClass")
+	}
+
+	kube.SetSandboxRuntimeClass(func(namespace string) (string, error) {
+		var override string
+		ns, err := namespaceLister.Get(namespace)
+		switch {
+		case err == nil:
+			override = ns.Annotations[kube.RuntimeClassAnnotation]
+		case !kerrors.IsNotFound(err):
+			return "", errors.WithContext("get namespace", err)
+		}
+
+		runtimeClass := selectRuntimeClass(defaultClass, override)
+		if runtimeClass == "" || runtimeClass == defaultClass {
+			return runtimeClass, nil
+		}
+
+		if err := classes.check(runtimeClass); err != nil {
+			return "", err
+		}
+		return runtimeClass, nil
+	})
+	return nil
+}
+
+// selectRuntimeClass returns the RuntimeClass for a namespace, given its
+// override annotation.
+func selectRuntimeClass(defaultClass, override string) string {
+	switch override {
+	case "":
+		return defaultClass
+	case defaultRuntimeClassOverride:
+		return ""
+	default:
+		return override
+	}
+}
+
+// check returns an error if the RuntimeClass doesn't exist. RuntimeClasses
+// that exist are cached, since they're rarely deleted.
+func (classes *runtimeClasses) check(name string) error {
+	classes.lock.Lock()
+	_, ok := classes.exists[name]
+	classes.lock.Unlock()
+	if ok {
+		return nil
+	}
+
+	_, err := classes.kubeClient.NodeV1beta1().RuntimeClasses().Get(name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return errors.NewFriendlyError("RuntimeClass %q doesn't exist. "+
+				"Make sure that the runtime is installed on the cluster's nodes, "+
+				"and that its RuntimeClass has been created.", name)
+		}
+		return errors.WithContext("get runtime class", err)
+	}
+
+	classes.lock.Lock()
+	classes.exists[name] = struct{}{}
+	classes.lock.Unlock()
+	return nil
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectRuntimeClass(t *testing.T) {
+	assert.Equal(t, "gvisor", selectRuntimeClass("gvisor", ""))
+	assert.Equal(t, "kata", selectRuntimeClass("gvisor", "kata"))
+	assert.Equal(t, "", selectRuntimeClass("gvisor", defaultRuntimeClassOverride))
+	assert.Equal(t, "kata", selectRuntimeClass("", "kata"))
+	assert.Equal(t, "", selectRuntimeClass("", ""))
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// Each sandbox is represented by a Sandbox custom resource in its namespace,
+// with an additional resource for each named Compose project deployed into
+// the sandbox. The spec is the desired state derived from the user's Compose
+// file, and the status summarizes what the statusFetcher computes for the
+// project's services. This lets operators inspect sandboxes with kubectl, and
+// lets sandboxes be deployed by applying the resource directly.
+var sandboxResource = kube.CustomResource{
+	Group:             "blimp.kelda.io",
+	Version:           "v1",
+	Kind:              "Sandbox",
+	Singular:          "sandbox",
+	Plural:            "sandboxes",
+	StatusSubresource: true,
+	PrinterColumns: []kube.PrinterColumn{
+		{Name: "Phase", JSONPath: ".status.phase"},
+		{Name: "Error", JSONPath: ".status.error"},
+	},
+}
+
+const (
+	// sandboxObjectName is the name of the Sandbox resource for the default
+	// project within each sandbox's namespace.
+	sandboxObjectName = "sandbox"
+
+	// sandboxResyncInterval is how often every sandbox is reconciled, even if
+	// it hasn't changed. This keeps the services' status up to date, and
+	// retries deployments that were interrupted.
+	sandboxResyncInterval = 30 * time.Second
+
+	// sandboxReconcileTimeout bounds how long a single reconciliation can
+	// wait for the sandbox's pods.
+	sandboxReconcileTimeout = 5 * time.Minute
+
+	sandboxControllerWorkers = 4
+)
+
+const (
+	sandboxPhaseDeploying = "Deploying"
+	sandboxPhaseDeployed  = "Deployed"
+	sandboxPhaseFailed    = "Failed"
+	sandboxPhasePaused    = "Paused"
+)
+
+type sandboxSpec struct {
+	Project             string            `json:"project,omitempty"`
+	ComposeFile         string            `json:"composeFile"`
+	BuiltImages         map[string]string `json:"builtImages,omitempty"`
+	KubernetesManifests []string          `json:"kubernetesManifests,omitempty"`
+}
+
+type sandboxStatus struct {
+	// ObservedGeneration is the generation of the spec that was last
+	// deployed.
+	ObservedGeneration int64                           `json:"observedGeneration,omitempty"`
+	Phase              string                          `json:"phase,omitempty"`
+	Error              string                          `json:"error,omitempty"`
+	Services           map[string]sandboxServiceStatus `json:"services,omitempty"`
+}
+
+type sandboxServiceStatus struct {
+	Phase   string `json:"phase"`
+	Message string `json:"message,omitempty"`
+}
+
+// sandboxController reconciles Sandbox resources.
+type sandboxController struct {
+	client   dynamic.NamespaceableResourceInterface
+	informer cache.SharedIndexInformer
+	queue    workqueue.RateLimitingInterface
+
+	// locks serializes the reconciliations of each sandbox, since sandboxes
+	// are reconciled both by the controller and by DeployToSandbox.
+	locksMu sync.Mutex
+	locks   map[string]*sync.Mutex
+}
+
+func newSandboxController(restConfig *rest.Config) (*sandboxController, error) {
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.WithContext("create client", err)
+	}
+
+	if err := kube.CreateCustomResourceDefinition(dynamicClient, sandboxResource); err != nil {
+		return nil, errors.WithContext("create custom resource definition", err)
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, sandboxResyncInterval)
+	sc := &sandboxController{
+		client:   dynamicClient.Resource(sandboxResource.GroupVersionResource()),
+		informer: factory.ForResource(sandboxResource.GroupVersionResource()).Informer(),
+		queue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "sandboxes"),
+		locks:    map[string]*sync.Mutex{},
+	}
+
+	// Resyncs are delivered as updates, so every sandbox is periodically
+	// queued.
+	sc.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    sc.enqueue,
+		UpdateFunc: func(_, obj interface{}) { sc.enqueue(obj) },
+	})
+	return sc, nil
+}
+
+// getSandboxObjectName returns the name of the Sandbox resource for the
+// given project.
+func getSandboxObjectName(project string) string {
+	if project == "" {
+		return sandboxObjectName
+	}
+	return sandboxObjectName + "-" + project
+}
+
+func (sc *sandboxController) enqueue(obj interface{}) {
+	if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+		sc.queue.Add(key)
+	}
+}
+
+// lock acquires the lock for the given Sandbox resource, and returns a
+// function that releases it.
+func (sc *sandboxController) lock(namespace, name string) func() {
+	key := namespace + "/" + name
+	sc.locksMu.Lock()
+	lock, ok := sc.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		sc.locks[key] = lock
+	}
+	sc.locksMu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// apply creates or updates the Sandbox resource for the spec's project in the
+// given namespace. The API server increments the resource's generation if the
+// spec changed.
+func (sc *sandboxController) apply(namespace string, spec sandboxSpec) error {
+	specMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&spec)
+	if err != nil {
+		return errors.WithContext("convert spec", err)
+	}
+
+	name := getSandboxObjectName(spec.Project)
+	client := sc.client.Namespace(namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		sandbox, err := client.Get(name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			sandbox = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": sandboxResource.APIVersion(),
+				"kind":       sandboxResource.Kind,
+				"spec":       specMap,
+			}}
+			sandbox.SetName(name)
+			sandbox.SetNamespace(namespace)
+			_, err = client.Create(sandbox, metav1.CreateOptions{})
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		sandbox.Object["spec"] = specMap
+		_, err = client.Update(sandbox, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (s *server) runSandboxController(workers int) {
+	go s.sandboxes.informer.Run(nil)
+	cache.WaitForCacheSync(nil, s.sandboxes.informer.HasSynced)
+
+	for i := 0; i < workers; i++ {
+		go s.runSandboxWorker()
+	}
+}
+
+func (s *server) runSandboxWorker() {
+	queue := s.sandboxes.queue
+	for {
+		item, shutdown := queue.Get()
+		if shutdown {
+			return
+		}
+
+		namespace, name, err := cache.SplitMetaNamespaceKey(item.(string))
+		if err != nil {
+			queue.Forget(item)
+			queue.Done(item)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), sandboxReconcileTimeout)
+		err = s.reconcileSandbox(ctx, namespace, name, false)
+		cancel()
+
+		if err != nil {
+			log.WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Warn("Failed to reconcile sandbox")
+			queue.AddRateLimited(item)
+		} else {
+			queue.Forget(item)
+		}
+		queue.Done(item)
+	}
+}
+
+// reconcileSandbox deploys the Sandbox resource's spec if it changed since it
+// was last deployed, and updates its status. If `force` is set, the spec is
+// deployed even if it hasn't changed, which recreates any services that were
+// removed.
+func (s *server) reconcileSandbox(ctx context.Context, namespace, name string, force bool) error {
+	unlock := s.sandboxes.lock(namespace, name)
+	defer unlock()
+
+	client := s.sandboxes.client.Namespace(namespace)
+
+	// Get the objects directly rather than from the informers' caches, since
+	// DeployToSandbox reconciles immediately after modifying them.
+	sandbox, err := client.Get(name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.WithContext("get sandbox", err)
+	}
+
+	ns, err := s.kubeClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.WithContext("get namespace", err)
+	}
+
+	var spec sandboxSpec
+	var status sandboxStatus
+	if err := parseSandbox(sandbox, &spec, &status); err != nil {
+		return err
+	}
+
+	_, paused, err := parsePaused(ns)
+	if err != nil {
+		return err
+	}
+
+	newStatus := status
+	var deployErr error
+	switch {
+	// Don't boot the services of paused sandboxes. Changes to the spec are
+	// deployed once the sandbox is resumed.
+	case paused:
+		newStatus.Phase = sandboxPhasePaused
+
+	// Deployments that were interrupted, such as by the manager restarting,
+	// are retried. Deployments that failed aren't retried until the spec
+	// changes since the failure is likely caused by the user's config.
+	case force || sandbox.GetGeneration() != status.ObservedGeneration ||
+		status.Phase == sandboxPhaseDeploying:
+		newStatus.Phase = sandboxPhaseDeploying
+		sandbox, err = s.updateSandboxStatus(sandbox, newStatus)
+		if err != nil {
+			return errors.WithContext("update status", err)
+		}
+		status = newStatus
+
+		user := auth.User{Name: ns.Annotations[kube.OwnerAnnotation], Namespace: namespace}
+		deployErr = s.deploySandbox(ctx, user, spec)
+		newStatus.ObservedGeneration = sandbox.GetGeneration()
+		if deployErr != nil {
+			newStatus.Phase = sandboxPhaseFailed
+			newStatus.Error = errors.GetPrintableMessage(deployErr)
+		} else {
+			newStatus.Phase = sandboxPhaseDeployed
+			newStatus.Error = ""
+		}
+	}
+
+	servicesStatus, err := s.statusFetcher.Get(namespace)
+	if err != nil {
+		return errors.WithContext("get status", err)
+	}
+	newStatus.Services = map[string]sandboxServiceStatus{}
+	for name, svc := range servicesStatus.Services {
+		if svc.Project != spec.Project {
+			continue
+		}
+		newStatus.Services[name] = sandboxServiceStatus{
+			Phase:   svc.Phase.String(),
+			Message: svc.Msg,
+		}
+	}
+
+	// Empty maps are omitted when the status is stored, so normalize them to
+	// avoid updating the status on every reconciliation.
+	if len(newStatus.Services) == 0 {
+		newStatus.Services = nil
+	}
+
+	if !reflect.DeepEqual(status, newStatus) {
+		if _, err := s.updateSandboxStatus(sandbox, newStatus); err != nil {
+			return errors.WithContext("update status", err)
+		}
+	}
+	return deployErr
+}
+
+func (s *server) updateSandboxStatus(sandbox *unstructured.Unstructured, status sandboxStatus) (
+	*unstructured.Unstructured, error) {
+	statusMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&status)
+	if err != nil {
+		return nil, errors.WithContext("convert status", err)
+	}
+
+	sandbox = sandbox.DeepCopy()
+	sandbox.Object["status"] = statusMap
+	return s.sandboxes.client.Namespace(sandbox.GetNamespace()).UpdateStatus(sandbox, metav1.UpdateOptions{})
+}
+
+func parseSandbox(sandbox *unstructured.Unstructured, spec *sandboxSpec, status *sandboxStatus) error {
+	if specMap, ok := sandbox.Object["spec"].(map[string]interface{}); ok {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(specMap, spec); err != nil {
+			return errors.WithContext("parse spec", err)
+		}
+	}
+
+	if statusMap, ok := sandbox.Object["status"].(map[string]interface{}); ok {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(statusMap, status); err != nil {
+			return errors.WithContext("parse status", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+	kubeTesting "k8s.io/client-go/testing"
+
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// invalidComposeFile fails to deploy before the deployment touches the
+// cluster, so that the tests can tell when a deployment is attempted.
+const invalidComposeFile = "services: ["
+
+func newSandboxObject(t *testing.T, generation int64, status *sandboxStatus) *unstructured.Unstructured {
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&sandboxSpec{
+		ComposeFile: invalidComposeFile,
+	})
+	require.NoError(t, err)
+
+	sandbox := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": sandboxResource.APIVersion(),
+		"kind":       sandboxResource.Kind,
+		"spec":       spec,
+	}}
+	if status != nil {
+		statusMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(status)
+		require.NoError(t, err)
+		sandbox.Object["status"] = statusMap
+	}
+	sandbox.SetName(sandboxObjectName)
+	sandbox.SetNamespace("namespace")
+	sandbox.SetGeneration(generation)
+	return sandbox
+}
+
+func newSandboxTestServer(stop chan struct{}, paused bool, sandbox *unstructured.Unstructured) (
+	*server, *dynamicFake.FakeDynamicClient) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "namespace",
+			Annotations: map[string]string{kube.OwnerAnnotation: "alice"},
+		},
+	}
+	if paused {
+		ns.Annotations[kube.PausedAnnotation] = `{"pausedAt":1600000000}`
+	}
+
+	kubeClient := fakeKube.NewSimpleClientset(ns)
+	sf := newStatusFetcher(kubeClient, defaultInformerConfig)
+	sf.Start(stop)
+
+	dynamicClient := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), sandbox)
+	return &server{
+		kubeClient:    kubeClient,
+		statusFetcher: sf,
+		sandboxes: &sandboxController{
+			client: dynamicClient.Resource(sandboxResource.GroupVersionResource()),
+			locks:  map[string]*sync.Mutex{},
+		},
+	}, dynamicClient
+}
+
+// getStatusUpdates returns the statuses written to the Sandbox resource.
+func getStatusUpdates(client *dynamicFake.FakeDynamicClient) (updates []string) {
+	for _, action := range client.Actions() {
+		update, ok := action.(kubeTesting.UpdateAction)
+		if !ok || update.GetSubresource() != "status" {
+			continue
+		}
+
+		phase, _, _ := unstructured.NestedString(
+			update.GetObject().(*unstructured.Unstructured).Object, "status", "phase")
+		updates = append(updates, phase)
+	}
+	return updates
+}
+
+func getSandboxStatus(t *testing.T, s *server) sandboxStatus {
+	sandbox, err := s.sandboxes.client.Namespace("namespace").Get(sandboxObjectName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	var spec sandboxSpec
+	var status sandboxStatus
+	require.NoError(t, parseSandbox(sandbox, &spec, &status))
+	return status
+}
+
+func TestReconcileSandbox(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation int64
+		status     *sandboxStatus
+		paused     bool
+		force      bool
+		expDeploy  bool
+		expStatus  sandboxStatus
+	}{
+		{
+			name:       "New",
+			generation: 1,
+			expDeploy:  true,
+			expStatus:  sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseFailed},
+		},
+		{
+			// Changes made directly to the sandbox's pods, such as by
+			// `kubectl`, aren't reverted until the spec changes.
+			name:       "UpToDate",
+			generation: 1,
+			status:     &sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseDeployed},
+			expStatus:  sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseDeployed},
+		},
+		{
+			name:       "SpecChanged",
+			generation: 2,
+			status:     &sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseDeployed},
+			expDeploy:  true,
+			expStatus:  sandboxStatus{ObservedGeneration: 2, Phase: sandboxPhaseFailed},
+		},
+		{
+			name:       "Forced",
+			generation: 1,
+			status:     &sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseDeployed},
+			force:      true,
+			expDeploy:  true,
+			expStatus:  sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseFailed},
+		},
+		{
+			name:       "Interrupted",
+			generation: 1,
+			status:     &sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseDeploying},
+			expDeploy:  true,
+			expStatus:  sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseFailed},
+		},
+		{
+			name:       "FailedNotRetried",
+			generation: 1,
+			status:     &sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseFailed, Error: "bad config"},
+			expStatus:  sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseFailed, Error: "bad config"},
+		},
+		{
+			name:       "Paused",
+			generation: 2,
+			status:     &sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhaseDeployed},
+			paused:     true,
+			expStatus:  sandboxStatus{ObservedGeneration: 1, Phase: sandboxPhasePaused},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stop := make(chan struct{})
+			defer close(stop)
+			s, dynamicClient := newSandboxTestServer(stop, test.paused,
+				newSandboxObject(t, test.generation, test.status))
+
+			err := s.reconcileSandbox(context.Background(), "namespace", sandboxObjectName, test.force)
+			status := getSandboxStatus(t, s)
+			if test.expDeploy {
+				assert.Error(t, err)
+				assert.Equal(t, []string{sandboxPhaseDeploying, sandboxPhaseFailed}, getStatusUpdates(dynamicClient))
+				assert.NotEmpty(t, status.Error)
+				status.Error = ""
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expStatus, status)
+		})
+	}
+}
+
+func TestReconcileSandboxConverges(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	s, dynamicClient := newSandboxTestServer(stop, false, newSandboxObject(t, 1, nil))
+	reconcile := func() error {
+		dynamicClient.ClearActions()
+		return s.reconcileSandbox(context.Background(), "namespace", sandboxObjectName, false)
+	}
+
+	assert.Error(t, reconcile())
+	assert.Equal(t, []string{sandboxPhaseDeploying, sandboxPhaseFailed}, getStatusUpdates(dynamicClient))
+
+	// Once the sandbox matches its spec, later reconciliations, such as
+	// periodic resyncs, don't modify it.
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, reconcile())
+		assert.Empty(t, getStatusUpdates(dynamicClient))
+	}
+
+	// Sandboxes that were deleted are ignored.
+	require.NoError(t, s.sandboxes.client.Namespace("namespace").Delete(sandboxObjectName, nil))
+	assert.NoError(t, reconcile())
+}
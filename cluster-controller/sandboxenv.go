@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// sandboxEnvVar is an environment variable set with `blimp env`. The
+// variables are stored in an annotation on the sandbox's namespace, and are
+// injected into the services each time they're deployed.
+type sandboxEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+
+	// Services are the services that the variable is injected into. If
+	// empty, it's injected into all services.
+	Services []string `json:"services,omitempty"`
+}
+
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (v sandboxEnvVar) appliesTo(svc string) bool {
+	return len(v.Services) == 0 || contains(v.Services, svc)
+}
+
+func (v sandboxEnvVar) toProtobuf() *cluster.SandboxEnvVar {
+	return &cluster.SandboxEnvVar{
+		Name:     v.Name,
+		Value:    v.Value,
+		Services: v.Services,
+	}
+}
+
+func (s *server) ListSandboxEnv(ctx context.Context, req *cluster.ListSandboxEnvRequest) (
+	*cluster.ListSandboxEnvResponse, error) {
+	log.Info("Start ListSandboxEnv")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.ListSandboxEnvResponse{}, err
+	}
+
+	env, err := s.getSandboxEnv(user.Namespace)
+	if err != nil {
+		return &cluster.ListSandboxEnvResponse{}, err
+	}
+
+	var envPB []*cluster.SandboxEnvVar
+	for _, v := range env {
+		envPB = append(envPB, v.toProtobuf())
+	}
+	return &cluster.ListSandboxEnvResponse{Env: envPB}, nil
+}
+
+func (s *server) SetSandboxEnv(ctx context.Context, req *cluster.SetSandboxEnvRequest) (
+	*cluster.SetSandboxEnvResponse, error) {
+	log.Info("Start SetSandboxEnv")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.SetSandboxEnvResponse{}, err
+	}
+
+	newVars := map[string]sandboxEnvVar{}
+	for _, v := range req.GetEnv() {
+		if !envNameRegex.MatchString(v.GetName()) {
+			return &cluster.SetSandboxEnvResponse{}, errors.NewFriendlyError(
+				"%q isn't a valid environment variable name.", v.GetName())
+		}
+		newVars[v.GetName()] = sandboxEnvVar{
+			Name:     v.GetName(),
+			Value:    v.GetValue(),
+			Services: v.GetServices(),
+		}
+	}
+
+	// Track both the previous and new versions of the variables, since the
+	// services they apply to may have changed.
+	var changed []sandboxEnvVar
+	err = s.updateSandboxEnv(user.Namespace, func(env []sandboxEnvVar) ([]sandboxEnvVar, error) {
+		changed = nil
+		var updated []sandboxEnvVar
+		for _, v := range env {
+			if _, ok := newVars[v.Name]; ok {
+				changed = append(changed, v)
+			} else {
+				updated = append(updated, v)
+			}
+		}
+
+		for _, v := range newVars {
+			changed = append(changed, v)
+			updated = append(updated, v)
+		}
+		return updated, nil
+	})
+	if err != nil {
+		return &cluster.SetSandboxEnvResponse{}, err
+	}
+	s.recordActivity(user.Namespace)
+
+	restarted, err := s.deploySandboxEnv(ctx, user.Namespace, changed)
+	if err != nil {
+		return &cluster.SetSandboxEnvResponse{}, err
+	}
+	return &cluster.SetSandboxEnvResponse{RestartedServices: restarted}, nil
+}
+
+func (s *server) UnsetSandboxEnv(ctx context.Context, req *cluster.UnsetSandboxEnvRequest) (
+	*cluster.UnsetSandboxEnvResponse, error) {
+	log.Info("Start UnsetSandboxEnv")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.UnsetSandboxEnvResponse{}, err
+	}
+
+	var removed []sandboxEnvVar
+	err = s.updateSandboxEnv(user.Namespace, func(env []sandboxEnvVar) ([]sandboxEnvVar, error) {
+		removed = nil
+		isSet := map[string]struct{}{}
+		var updated []sandboxEnvVar
+		for _, v := range env {
+			isSet[v.Name] = struct{}{}
+			if contains(req.GetNames(), v.Name) {
+				removed = append(removed, v)
+			} else {
+				updated = append(updated, v)
+			}
+		}
+
+		for _, name := range req.GetNames() {
+			if _, ok := isSet[name]; !ok {
+				return nil, errors.NewFriendlyError(
+					"%s isn't set. Run `blimp env` to see the variables that are set.", name)
+			}
+		}
+		return updated, nil
+	})
+	if err != nil {
+		return &cluster.UnsetSandboxEnvResponse{}, err
+	}
+	s.recordActivity(user.Namespace)
+
+	restarted, err := s.deploySandboxEnv(ctx, user.Namespace, removed)
+	if err != nil {
+		return &cluster.UnsetSandboxEnvResponse{}, err
+	}
+	return &cluster.UnsetSandboxEnvResponse{RestartedServices: restarted}, nil
+}
+
+// deploySandboxEnv redeploys the sandbox's projects so that the running
+// services pick up the changed variables. Only the services whose
+// environment changed are restarted. It returns the restarted services.
+func (s *server) deploySandboxEnv(ctx context.Context, namespace string, changed []sandboxEnvVar) (
+	[]string, error) {
+	pods, err := s.statusFetcher.podLister.Pods(namespace).List(
+		labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("list services", err)
+	}
+
+	var restarted []string
+	for _, pod := range pods {
+		svc := pod.Labels["blimp.service"]
+		if isSurgePod(pod) || pod.Name == "reservation" {
+			continue
+		}
+
+		for _, v := range changed {
+			if v.appliesTo(svc) {
+				restarted = append(restarted, svc)
+				break
+			}
+		}
+	}
+	sort.Strings(restarted)
+
+	if len(restarted) == 0 {
+		return nil, nil
+	}
+
+	sandboxes, err := s.sandboxes.client.Namespace(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.WithContext("list sandbox resources", err)
+	}
+
+	for _, sandbox := range sandboxes.Items {
+		if err := s.reconcileSandbox(ctx, namespace, sandbox.GetName(), true); err != nil {
+			return nil, errors.WithContext("deploy", err)
+		}
+	}
+	return restarted, nil
+}
+
+// getSandboxEnv returns the variables set with `blimp env`. It reads the
+// namespace directly rather than from the cache, so that deploys triggered by
+// `blimp env` see the change.
+func (s *server) getSandboxEnv(namespace string) ([]sandboxEnvVar, error) {
+	ns, err := s.kubeClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.WithContext("get namespace", err)
+	}
+	return parseSandboxEnv(ns)
+}
+
+func parseSandboxEnv(ns *corev1.Namespace) ([]sandboxEnvVar, error) {
+	envJSON, ok := ns.Annotations[kube.SandboxEnvAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var env []sandboxEnvVar
+	if err := json.Unmarshal([]byte(envJSON), &env); err != nil {
+		return nil, errors.WithContext("parse sandbox env", err)
+	}
+	return env, nil
+}
+
+// updateSandboxEnv replaces the variables recorded in the namespace with the
+// result of `update`. The variables are kept sorted by name so that they're
+// injected in a consistent order.
+func (s *server) updateSandboxEnv(namespace string,
+	update func([]sandboxEnvVar) ([]sandboxEnvVar, error)) error {
+	namespacesClient := s.kubeClient.CoreV1().Namespaces()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := namespacesClient.Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return errors.NewFriendlyError(
+					"Your sandbox doesn't exist yet. Run `blimp up` before setting environment variables.")
+			}
+			return err
+		}
+
+		env, err := parseSandboxEnv(ns)
+		if err != nil {
+			return err
+		}
+
+		env, err = update(env)
+		if err != nil {
+			return err
+		}
+		sort.Slice(env, func(i, j int) bool {
+			return env[i].Name < env[j].Name
+		})
+
+		if len(env) == 0 {
+			delete(ns.Annotations, kube.SandboxEnvAnnotation)
+		} else {
+			envJSON, err := json.Marshal(env)
+			if err != nil {
+				return err
+			}
+
+			if ns.Annotations == nil {
+				ns.Annotations = map[string]string{}
+			}
+			ns.Annotations[kube.SandboxEnvAnnotation] = string(envJSON)
+		}
+
+		_, err = namespacesClient.Update(ns)
+		return err
+	})
+}
+
+// injectSandboxEnv sets the variables in the service's container. Unlike the
+// add-on variables, they override the values set by the Compose file.
+func injectSandboxEnv(pod *corev1.Pod, env []sandboxEnvVar) {
+	svc := pod.Labels["blimp.service"]
+	for i, c := range pod.Spec.Containers {
+		if c.Name != names.ToDNS1123(svc) {
+			continue
+		}
+
+	injectVars:
+		for _, v := range env {
+			if !v.appliesTo(svc) {
+				continue
+			}
+
+			for j, existing := range c.Env {
+				if existing.Name == v.Name {
+					pod.Spec.Containers[i].Env[j] = corev1.EnvVar{Name: v.Name, Value: v.Value}
+					continue injectVars
+				}
+			}
+			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env,
+				corev1.EnvVar{Name: v.Name, Value: v.Value})
+		}
+	}
+}
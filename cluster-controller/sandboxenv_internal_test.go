@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/names"
+)
+
+func TestInjectSandboxEnv(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"blimp.service": "web"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: names.ToDNS1123("web"),
+					Env: []corev1.EnvVar{
+						{Name: "FEATURE_CHECKOUT", Value: "false"},
+						{Name: "PORT", Value: "3000"},
+					},
+				},
+				{Name: chaosContainerName},
+			},
+		},
+	}
+
+	injectSandboxEnv(&pod, []sandboxEnvVar{
+		{Name: "FEATURE_CHECKOUT", Value: "true"},
+		{Name: "LOG_LEVEL", Value: "debug", Services: []string{"web"}},
+		{Name: "WORKER_THREADS", Value: "4", Services: []string{"worker"}},
+	})
+
+	// Variables set in the Compose file are overridden.
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "FEATURE_CHECKOUT", Value: "true"},
+		{Name: "PORT", Value: "3000"},
+		{Name: "LOG_LEVEL", Value: "debug"},
+	}, pod.Spec.Containers[0].Env)
+	assert.Empty(t, pod.Spec.Containers[1].Env)
+}
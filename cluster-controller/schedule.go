@@ -0,0 +1,296 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// scheduledLabel marks the CronJobs for services with x-blimp.schedule,
+	// and the pods created for each of their runs.
+	scheduledLabel = "blimp.scheduled"
+
+	// maxCronJobNameLength is the longest name that Kubernetes allows for
+	// CronJobs, since the names of their Jobs have a timestamp appended.
+	maxCronJobNameLength = 52
+
+	// scheduledRunHistory is how many finished runs are kept for each
+	// scheduled service, so that `blimp logs --previous` can show the logs of
+	// the run before the latest one.
+	scheduledRunHistory = 3
+)
+
+var (
+	cronMacros = map[string]struct{}{
+		"@yearly": {}, "@annually": {}, "@monthly": {}, "@weekly": {},
+		"@daily": {}, "@midnight": {}, "@hourly": {},
+	}
+	cronFieldPattern = regexp.MustCompile(
+		`^(\*|\?|[0-9A-Za-z]+(-[0-9A-Za-z]+)?)(/[0-9]+)?(,(\*|[0-9A-Za-z]+(-[0-9A-Za-z]+)?)(/[0-9]+)?)*$`)
+)
+
+// getSchedules returns the cron schedules of the services that set
+// x-blimp.schedule.
+func getSchedules(services []composeTypes.ServiceConfig) (map[string]string, error) {
+	schedules := map[string]string{}
+	for _, svc := range services {
+		ext, err := compose.GetServiceExtension(svc)
+		if err != nil {
+			return nil, err
+		}
+
+		if ext.Schedule == "" {
+			continue
+		}
+
+		if !validSchedule(ext.Schedule) {
+			return nil, errors.NewFriendlyError(
+				"Service %s has an invalid schedule %q. Schedules use the cron format, "+
+					"such as \"*/5 * * * *\" to run every five minutes.", svc.Name, ext.Schedule)
+		}
+
+		if len(svc.Ports) != 0 {
+			return nil, errors.NewFriendlyError(
+				"Service %s can't publish ports since it runs on a schedule.", svc.Name)
+		}
+		schedules[svc.Name] = ext.Schedule
+	}
+
+	// Scheduled services don't run continuously, so other services would
+	// never finish waiting for them.
+	for _, svc := range services {
+		deps := append([]string{}, svc.Links...)
+		for dep := range svc.DependsOn {
+			deps = append(deps, dep)
+		}
+
+		for _, dep := range deps {
+			dep = strings.Split(dep, ":")[0]
+			if _, ok := schedules[dep]; ok {
+				return nil, errors.NewFriendlyError(
+					"Service %s can't depend on %s since %s runs on a schedule.", svc.Name, dep, dep)
+			}
+		}
+	}
+	return schedules, nil
+}
+
+func validSchedule(schedule string) bool {
+	if _, ok := cronMacros[schedule]; ok {
+		return true
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return false
+	}
+
+	for _, field := range fields {
+		if !cronFieldPattern.MatchString(field) {
+			return false
+		}
+	}
+	return true
+}
+
+// cronJobName returns the name of the CronJob for the given service. It's the
+// same as the service's pod name, except that it's truncated to fit within
+// the CronJob name limit.
+func cronJobName(svc string) string {
+	name := names.ToDNS1123(svc)
+	if len(name) <= maxCronJobNameLength {
+		return name
+	}
+
+	// ToDNS1123 ends with a dash and a hash, which we keep so that the name
+	// is still unique.
+	suffix := name[len(name)-11:]
+	prefix := strings.TrimRight(name[:maxCronJobNameLength-len(suffix)], "-")
+	return prefix + suffix
+}
+
+// toCronJob converts the pod for a service into a CronJob that runs the
+// service on the given schedule.
+func toCronJob(pod corev1.Pod, schedule string) batchv1beta1.CronJob {
+	svc := pod.Labels["blimp.service"]
+
+	// Don't set the blimp.customerPod label so that the runs aren't garbage
+	// collected by deployCustomerPods.
+	podLabels := map[string]string{scheduledLabel: "true"}
+	for k, v := range pod.Labels {
+		if k != "blimp.customerPod" {
+			podLabels[k] = v
+		}
+	}
+
+	podSpec := *pod.Spec.DeepCopy()
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+
+	var backoffLimit int32
+	history := int32(scheduledRunHistory)
+	cronJob := batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cronJobName(svc),
+			Namespace: pod.Namespace,
+			Labels: map[string]string{
+				scheduledLabel:  "true",
+				"blimp.service": svc,
+			},
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   schedule,
+			ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &history,
+			FailedJobsHistoryLimit:     &history,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels:      podLabels,
+							Annotations: pod.Annotations,
+						},
+						Spec: podSpec,
+					},
+				},
+			},
+		},
+	}
+	setProjectLabel(&cronJob.ObjectMeta, pod.Labels[projectLabel])
+	return cronJob
+}
+
+// deployCronJobs deploys the CronJobs for scheduled services, and deletes the
+// project's CronJobs for services that are no longer scheduled.
+func (s *server) deployCronJobs(namespace, project string, cronJobs []batchv1beta1.CronJob) error {
+	desired := map[string]struct{}{}
+	for _, cronJob := range cronJobs {
+		if err := kube.DeployCronJob(s.kubeClient, cronJob); err != nil {
+			return err
+		}
+		desired[cronJob.Name] = struct{}{}
+	}
+
+	cronJobsClient := s.kubeClient.BatchV1beta1().CronJobs(namespace)
+	curr, err := cronJobsClient.List(metav1.ListOptions{
+		LabelSelector: scheduledLabel + "=true," + projectSelector(project).String(),
+	})
+	if err != nil {
+		return errors.WithContext("list cronjobs", err)
+	}
+
+	// Delete the runs as well.
+	background := metav1.DeletePropagationBackground
+	for _, cronJob := range curr.Items {
+		if _, ok := desired[cronJob.Name]; ok {
+			continue
+		}
+
+		err := cronJobsClient.Delete(cronJob.Name, &metav1.DeleteOptions{PropagationPolicy: &background})
+		if err != nil {
+			return errors.WithContext("delete cronjob", err)
+		}
+		log.WithField("namespace", namespace).
+			WithField("cronjob", cronJob.Name).
+			Info("Deleted CronJob for service that's no longer scheduled")
+	}
+	return nil
+}
+
+// getScheduledStatuses returns the statuses of the scheduled services in the
+// namespace.
+func (sf *statusFetcher) getScheduledStatuses(namespace string) (map[string]*cluster.ServiceStatus, error) {
+	selector := labels.Set{scheduledLabel: "true"}.AsSelector()
+	cronJobs, err := sf.cronJobLister.CronJobs(namespace).List(selector)
+	if err != nil {
+		return nil, errors.WithContext("list cronjobs", err)
+	}
+
+	pods, err := sf.podLister.Pods(namespace).List(selector)
+	if err != nil {
+		return nil, errors.WithContext("list runs", err)
+	}
+
+	runs := map[string][]*corev1.Pod{}
+	for _, pod := range pods {
+		svc := pod.Labels["blimp.service"]
+		runs[svc] = append(runs[svc], pod)
+	}
+
+	statuses := map[string]*cluster.ServiceStatus{}
+	for _, cronJob := range cronJobs {
+		svc := cronJob.Labels["blimp.service"]
+		status := sf.getScheduledStatus(svc, cronJob.Spec.Schedule, runs[svc])
+		status.Project = cronJob.Labels[projectLabel]
+		statuses[svc] = &status
+	}
+	return statuses, nil
+}
+
+func (sf *statusFetcher) getScheduledStatus(svc, schedule string, runs []*corev1.Pod) cluster.ServiceStatus {
+	if len(runs) == 0 {
+		return cluster.ServiceStatus{
+			Phase:    cluster.ServicePhase_SCHEDULED,
+			Schedule: schedule,
+		}
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[j].CreationTimestamp.Before(&runs[i].CreationTimestamp)
+	})
+	latest := runs[0]
+
+	lastRunAt := latest.CreationTimestamp.Unix()
+	if latest.Status.StartTime != nil {
+		lastRunAt = latest.Status.StartTime.Unix()
+	}
+
+	switch latest.Status.Phase {
+	case corev1.PodSucceeded, corev1.PodFailed:
+		status := cluster.ServiceStatus{
+			Phase:      cluster.ServicePhase_SCHEDULED,
+			Schedule:   schedule,
+			HasStarted: true,
+			LastRunAt:  lastRunAt,
+		}
+
+		exited := false
+		for _, c := range latest.Status.ContainerStatuses {
+			if c.Name == names.ToDNS1123(svc) && c.State.Terminated != nil {
+				status.ExitCode = c.State.Terminated.ExitCode
+				exited = true
+			}
+		}
+
+		if latest.Status.Phase == corev1.PodFailed && !exited {
+			status.Msg = "The last run failed to start"
+		}
+		return status
+	default:
+		status := sf.getServiceStatus(latest)
+		status.Schedule = schedule
+		status.LastRunAt = lastRunAt
+		return status
+	}
+}
+
+// isScheduled returns whether the service runs on a schedule.
+func (sf *statusFetcher) isScheduled(namespace, svc string) bool {
+	_, err := sf.cronJobLister.CronJobs(namespace).Get(cronJobName(svc))
+	return err == nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func TestValidSchedule(t *testing.T) {
+	for _, schedule := range []string{
+		"*/5 * * * *", "0 3 * * 1-5", "30 2,14 1 JAN MON", "@daily", "@hourly",
+	} {
+		assert.True(t, validSchedule(schedule), schedule)
+	}
+
+	for _, schedule := range []string{
+		"", "* * * *", "* * * * * *", "@sometimes", "*/x * * * *", "5; rm * * * *",
+	} {
+		assert.False(t, validSchedule(schedule), schedule)
+	}
+}
+
+func TestCronJobName(t *testing.T) {
+	assert.Equal(t, names.ToDNS1123("cleanup"), cronJobName("cleanup"))
+
+	long := strings.Repeat("a", 100)
+	name := cronJobName(long)
+	assert.Len(t, name, maxCronJobNameLength)
+	assert.True(t, strings.HasSuffix(names.ToDNS1123(long), name[len(name)-11:]))
+}
+
+func TestGetSchedules(t *testing.T) {
+	scheduled := func(name, schedule string) composeTypes.ServiceConfig {
+		return composeTypes.ServiceConfig{
+			Name: name,
+			Extras: map[string]interface{}{
+				compose.ExtensionKey: map[string]interface{}{"schedule": schedule},
+			},
+		}
+	}
+
+	schedules, err := getSchedules([]composeTypes.ServiceConfig{
+		{Name: "web"},
+		scheduled("cleanup", "@daily"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cleanup": "@daily"}, schedules)
+
+	_, err = getSchedules([]composeTypes.ServiceConfig{scheduled("cleanup", "every day")})
+	assert.Error(t, err)
+
+	withPorts := scheduled("cleanup", "@daily")
+	withPorts.Ports = []composeTypes.ServicePortConfig{{Target: 80}}
+	_, err = getSchedules([]composeTypes.ServiceConfig{withPorts})
+	assert.Error(t, err)
+
+	_, err = getSchedules([]composeTypes.ServiceConfig{
+		scheduled("cleanup", "@daily"),
+		{
+			Name: "web",
+			DependsOn: composeTypes.DependsOnConfig{
+				"cleanup": {Condition: composeTypes.ServiceConditionStarted},
+			},
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = getSchedules([]composeTypes.ServiceConfig{
+		scheduled("cleanup", "@daily"),
+		{Name: "web", Links: []string{"cleanup:janitor"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestToCronJob(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.ToDNS1123("cleanup"),
+			Namespace: "ns",
+			Labels: map[string]string{
+				"blimp.service":     "cleanup",
+				"blimp.customerPod": "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyAlways,
+			Containers:    []corev1.Container{{Name: names.ToDNS1123("cleanup")}},
+		},
+	}
+
+	cronJob := toCronJob(pod, "@daily")
+	assert.Equal(t, cronJobName("cleanup"), cronJob.Name)
+	assert.Equal(t, "ns", cronJob.Namespace)
+	assert.Equal(t, "@daily", cronJob.Spec.Schedule)
+
+	template := cronJob.Spec.JobTemplate.Spec.Template
+	assert.Equal(t, map[string]string{
+		"blimp.service": "cleanup",
+		scheduledLabel:  "true",
+	}, template.Labels)
+	assert.Equal(t, corev1.RestartPolicyNever, template.Spec.RestartPolicy)
+
+	// The original pod shouldn't be modified.
+	assert.Equal(t, corev1.RestartPolicyAlways, pod.Spec.RestartPolicy)
+	assert.Equal(t, "true", pod.Labels["blimp.customerPod"])
+}
+
+func TestGetScheduledStatus(t *testing.T) {
+	sf := &statusFetcher{}
+	assert.Equal(t, cluster.ServiceStatus{
+		Phase:    cluster.ServicePhase_SCHEDULED,
+		Schedule: "@daily",
+	}, sf.getScheduledStatus("cleanup", "@daily", nil))
+
+	run := func(created int64, exitCode int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.Unix(created, 0),
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: names.ToDNS1123("cleanup"),
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	assert.Equal(t, cluster.ServiceStatus{
+		Phase:      cluster.ServicePhase_SCHEDULED,
+		Schedule:   "@daily",
+		HasStarted: true,
+		LastRunAt:  200,
+		ExitCode:   2,
+	}, sf.getScheduledStatus("cleanup", "@daily", []*corev1.Pod{run(100, 1), run(200, 2)}))
+
+	failedToStart := run(300, 0)
+	failedToStart.Status.ContainerStatuses = nil
+	assert.Equal(t, cluster.ServiceStatus{
+		Phase:      cluster.ServicePhase_SCHEDULED,
+		Schedule:   "@daily",
+		HasStarted: true,
+		LastRunAt:  300,
+		Msg:        "The last run failed to start",
+	}, sf.getScheduledStatus("cleanup", "@daily", []*corev1.Pod{failedToStart}))
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	composeTypes "github.com/kelda/compose-go/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// deploySecretEnv creates a secret for each service that marks environment
+// variables as secret via the x-blimp extension. The service's pod reads the
+// variables from the secret when it boots, so the values never appear in the
+// pod spec.
+func (s *server) deploySecretEnv(namespace string, services []composeTypes.ServiceConfig) error {
+	for _, svc := range services {
+		secretEnv, err := compose.GetSecretEnv(svc)
+		if err != nil {
+			return err
+		}
+
+		if len(secretEnv) == 0 {
+			continue
+		}
+
+		data := map[string][]byte{}
+		for k, v := range secretEnv {
+			data[k] = []byte(v)
+		}
+
+		err = kube.DeploySecret(s.kubeClient, corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      compose.SecretEnvName(svc.Name),
+				Namespace: namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		})
+		if err != nil {
+			return errors.WithContext("deploy secret environment", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	clusterAuth "github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	protoAuth "github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+	"github.com/kelda/blimp/pkg/proto/node"
+	"github.com/kelda/blimp/pkg/tunnel"
+)
+
+const (
+	// selfTestToken identifies the self-test sandbox. Tokens are only used
+	// for namespacing, so all self-tests share the same sandbox.
+	selfTestToken = "blimp-self-test"
+
+	// selfTestTimeout bounds the entire self-test, including cleanup.
+	selfTestTimeout = 15 * time.Minute
+
+	// selfTestBootTimeout is how long to wait for the reference services to
+	// boot.
+	selfTestBootTimeout = 5 * time.Minute
+
+	// selfTestTunnelTimeout is how long to retry reading the volume data
+	// through the tunnel.
+	selfTestTunnelTimeout = time.Minute
+)
+
+// selfTestServices are the services in the reference sandbox. The writer
+// writes a random token into a shared volume, and the web service serves it
+// so that the self-test can read it back through a tunnel.
+var selfTestServices = []string{"web", "writer"}
+
+func selfTestComposeFile(dataToken string) string {
+	return fmt.Sprintf(`version: '3'
+services:
+  web:
+    image: nginx:alpine
+    volumes:
+      - data:/usr/share/nginx/html
+    depends_on:
+      - writer
+  writer:
+    image: busybox
+    command: ["sh", "-c", "echo %s > /data/index.html && exec sleep 1000000"]
+    volumes:
+      - data:/data
+volumes:
+  data:
+`, dataToken)
+}
+
+// selfTestState tracks the self-tests run by this manager so that they can be
+// exported as metrics.
+type selfTestState struct {
+	sync.Mutex
+
+	running  bool
+	last     *cluster.SelfTestResult
+	passes   int
+	failures int
+}
+
+func (s *server) RunSelfTest(ctx context.Context, req *cluster.RunSelfTestRequest) (
+	*cluster.RunSelfTestResponse, error) {
+	log.Info("Start RunSelfTest")
+
+	if err := clusterAuth.AuthorizeAdminRequest(req.GetAdminToken()); err != nil {
+		return &cluster.RunSelfTestResponse{}, err
+	}
+
+	result, err := s.startSelfTest()
+	if err != nil {
+		return &cluster.RunSelfTestResponse{}, err
+	}
+	return &cluster.RunSelfTestResponse{Result: result}, nil
+}
+
+// runScheduledSelfTests runs a self-test every `interval`. It never returns.
+func (s *server) runScheduledSelfTests(interval time.Duration) {
+	for range time.Tick(interval) {
+		result, err := s.startSelfTest()
+		if err != nil {
+			log.WithError(err).Info("Skipping scheduled self-test")
+			continue
+		}
+
+		logger := log.WithField("steps", describeSelfTestSteps(result))
+		if result.Passed {
+			logger.Info("Self-test passed")
+		} else {
+			logger.Warn("Self-test failed")
+		}
+	}
+}
+
+// startSelfTest runs a self-test, unless one is already running.
+func (s *server) startSelfTest() (*cluster.SelfTestResult, error) {
+	s.selfTests.Lock()
+	if s.selfTests.running {
+		s.selfTests.Unlock()
+		return nil, errors.NewFriendlyError("A self-test is already running. Try again once it completes.")
+	}
+	s.selfTests.running = true
+	s.selfTests.Unlock()
+
+	// Don't use the request's context so that the sandbox is still cleaned
+	// up if the operator disconnects.
+	ctx, cancel := context.WithTimeout(context.Background(), selfTestTimeout)
+	defer cancel()
+	result := s.runSelfTest(ctx)
+
+	s.selfTests.Lock()
+	defer s.selfTests.Unlock()
+	s.selfTests.running = false
+	s.selfTests.last = result
+	if result.Passed {
+		s.selfTests.passes++
+	} else {
+		s.selfTests.failures++
+	}
+	return result, nil
+}
+
+// runSelfTest boots the reference sandbox, and checks that the services boot
+// normally, and that data written to a volume can be read through a tunnel.
+// The sandbox is always deleted afterwards so that each run starts from
+// scratch.
+func (s *server) runSelfTest(ctx context.Context) *cluster.SelfTestResult {
+	result := &cluster.SelfTestResult{
+		Passed:    true,
+		StartedAt: time.Now().Unix(),
+	}
+	runStep := func(name string, fn func() (string, error)) bool {
+		start := time.Now()
+		msg, err := fn()
+		if err != nil {
+			msg = err.Error()
+			result.Passed = false
+		}
+
+		result.Steps = append(result.Steps, &cluster.SelfTestStep{
+			Name:       name,
+			Passed:     err == nil,
+			Msg:        msg,
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+		return err == nil
+	}
+
+	blimpAuth := &protoAuth.BlimpAuth{
+		Token:       selfTestToken,
+		ClusterAuth: os.Getenv("BLIMP_CLUSTER_SECRET"),
+	}
+	user, err := clusterAuth.ParseIDToken(selfTestToken)
+	if err != nil {
+		runStep("authenticate", func() (string, error) { return "", err })
+		return result
+	}
+
+	dataToken, err := randomSelfTestToken()
+	if err != nil {
+		runStep("generate_data", func() (string, error) { return "", err })
+		return result
+	}
+	composeFile := selfTestComposeFile(dataToken)
+
+	defer runStep("delete_sandbox", func() (string, error) {
+		return "", s.deleteSandbox(user.Namespace, true)
+	})
+
+	_ = runStep("create_sandbox", func() (string, error) {
+		_, err := s.CreateSandbox(ctx, &cluster.CreateSandboxRequest{
+			Auth:        blimpAuth,
+			ComposeFile: composeFile,
+		})
+		return "", err
+	}) && runStep("deploy", func() (string, error) {
+		_, err := s.DeployToSandbox(ctx, &cluster.DeployRequest{
+			Auth:        blimpAuth,
+			ComposeFile: composeFile,
+		})
+		return "", err
+	}) && runStep("boot", func() (string, error) {
+		return s.waitForSelfTestBoot(ctx, user.Namespace)
+	}) && runStep("tunnel", func() (string, error) {
+		return "Read volume data through a tunnel to web:80",
+			s.checkSelfTestTunnel(ctx, blimpAuth, dataToken)
+	})
+	return result
+}
+
+// waitForSelfTestBoot waits for the reference services to be running, and
+// checks that they moved through the boot phases in order.
+func (s *server) waitForSelfTestBoot(ctx context.Context, namespace string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, selfTestBootTimeout)
+	defer cancel()
+
+	statusChanged := s.statusFetcher.Watch(ctx, namespace)
+	history := map[string][]cluster.ServicePhase{}
+	for {
+		status, err := s.statusFetcher.Get(namespace)
+		if err != nil {
+			return "", errors.WithContext("get status", err)
+		}
+
+		booted := len(status.Services) == len(selfTestServices)
+		for name, svc := range status.Services {
+			phases := history[name]
+			if len(phases) == 0 || phases[len(phases)-1] != svc.Phase {
+				if err := checkPhaseTransition(name, phases, svc); err != nil {
+					return "", err
+				}
+				history[name] = append(phases, svc.Phase)
+			}
+
+			if svc.Phase != cluster.ServicePhase_RUNNING {
+				booted = false
+			}
+		}
+
+		if booted {
+			return describePhaseHistory(history), nil
+		}
+
+		select {
+		case <-statusChanged:
+		case <-ctx.Done():
+			return "", errors.New("timed out waiting for services to boot (%s)", describePhaseHistory(history))
+		}
+	}
+}
+
+// initPhaseOrder is the order in which services move through the phases
+// reported for their init containers.
+var initPhaseOrder = map[cluster.ServicePhase]int{
+	cluster.ServicePhase_INITIALIZING_VOLUMES: 1,
+	cluster.ServicePhase_WAIT_DEPENDS_ON:      2,
+	cluster.ServicePhase_WAIT_SYNC_BIND:       3,
+}
+
+// checkPhaseTransition returns an error if a service in the reference sandbox
+// enters a failure phase, goes back to an earlier init phase, or stops
+// running after it has started.
+func checkPhaseTransition(svc string, history []cluster.ServicePhase, status *cluster.ServiceStatus) error {
+	switch status.Phase {
+	case cluster.ServicePhase_UNKNOWN, cluster.ServicePhase_PENDING, cluster.ServicePhase_RUNNING,
+		cluster.ServicePhase_INITIALIZING_VOLUMES, cluster.ServicePhase_WAIT_DEPENDS_ON,
+		cluster.ServicePhase_WAIT_SYNC_BIND:
+	default:
+		return errors.New("%s entered phase %s: %s", svc, status.Phase, status.Msg)
+	}
+
+	for _, prev := range history {
+		if prev == cluster.ServicePhase_RUNNING {
+			return errors.New("%s went from %s to %s", svc, prev, status.Phase)
+		}
+
+		if order, ok := initPhaseOrder[status.Phase]; ok && initPhaseOrder[prev] > order {
+			return errors.New("%s went back from %s to %s", svc, prev, status.Phase)
+		}
+	}
+	return nil
+}
+
+func describePhaseHistory(history map[string][]cluster.ServicePhase) string {
+	var services []string
+	for name, phases := range history {
+		var phaseStrs []string
+		for _, phase := range phases {
+			phaseStrs = append(phaseStrs, phase.String())
+		}
+		services = append(services, fmt.Sprintf("%s: %s", name, strings.Join(phaseStrs, " -> ")))
+	}
+	sort.Strings(services)
+	return strings.Join(services, ", ")
+}
+
+// checkSelfTestTunnel connects to the sandbox's node controller the same way
+// the CLI does, and reads the data written by the writer service from the web
+// service.
+func (s *server) checkSelfTestTunnel(ctx context.Context, blimpAuth *protoAuth.BlimpAuth, expData string) error {
+	attachResp, err := s.AttachToSandbox(ctx, &cluster.AttachToSandboxRequest{Auth: blimpAuth})
+	if err != nil {
+		return errors.WithContext("attach to sandbox", err)
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM([]byte(attachResp.NodeCert)) {
+		return errors.New("failed to parse node controller cert")
+	}
+
+	conn, err := grpc.DialContext(ctx, attachResp.NodeAddress,
+		grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(certPool, "")),
+		grpc.WithUnaryInterceptor(errors.UnaryClientInterceptor))
+	if err != nil {
+		return errors.WithContext("dial node controller", err)
+	}
+	defer conn.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.WithContext("listen", err)
+	}
+	defer ln.Close()
+
+	// tunnel.Client returns once the listener is closed.
+	go tunnel.Client(node.NewControllerClient(conn), ln, blimpAuth, "web", 80) //nolint:errcheck
+
+	ctx, cancel := context.WithTimeout(ctx, selfTestTunnelTimeout)
+	defer cancel()
+
+	// The writer may not have written the data by the time nginx is running,
+	// so retry until the data shows up.
+	url := fmt.Sprintf("http://%s/", ln.Addr())
+	for {
+		data, err := httpGet(ctx, url)
+		if err == nil && strings.TrimSpace(data) == expData {
+			return nil
+		}
+		if err == nil {
+			err = errors.New("got %q, expected %q", data, expData)
+		}
+
+		select {
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			return errors.WithContext("read volume data through tunnel", err)
+		}
+	}
+}
+
+func httpGet(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New("unexpected status %s", resp.Status)
+	}
+	return string(body), nil
+}
+
+func randomSelfTestToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.WithContext("generate token", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func describeSelfTestSteps(result *cluster.SelfTestResult) string {
+	var steps []string
+	for _, step := range result.Steps {
+		status := "passed"
+		if !step.Passed {
+			status = "failed: " + step.Msg
+		}
+		steps = append(steps, fmt.Sprintf("%s %s", step.Name, status))
+	}
+	return strings.Join(steps, ", ")
+}
+
+// serveMetrics exports the results of the self-tests, and the boot times of
+// services, in the Prometheus text format. It also serves the manager's
+// readiness check at /readyz.
+func (s *server) serveMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/readyz", s.serveReadyz)
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		s.selfTests.Lock()
+		writeSelfTestMetrics(w, &s.selfTests)
+		s.selfTests.Unlock()
+
+		s.bootSLO.WriteMetrics(w)
+	})
+	return http.ListenAndServe(addr, mux)
+}
+
+func writeSelfTestMetrics(w io.Writer, state *selfTestState) {
+	fmt.Fprintln(w, "# HELP blimp_self_test_runs_total The number of self-tests run, by result.")
+	fmt.Fprintln(w, "# TYPE blimp_self_test_runs_total counter")
+	fmt.Fprintf(w, "blimp_self_test_runs_total{result=\"pass\"} %d\n", state.passes)
+	fmt.Fprintf(w, "blimp_self_test_runs_total{result=\"fail\"} %d\n", state.failures)
+
+	if state.last == nil {
+		return
+	}
+
+	fmt.Fprintln(w, "# HELP blimp_self_test_success Whether the most recent self-test passed.")
+	fmt.Fprintln(w, "# TYPE blimp_self_test_success gauge")
+	fmt.Fprintf(w, "blimp_self_test_success %d\n", boolToInt(state.last.Passed))
+
+	fmt.Fprintln(w, "# HELP blimp_self_test_last_run_timestamp_seconds When the most recent self-test started.")
+	fmt.Fprintln(w, "# TYPE blimp_self_test_last_run_timestamp_seconds gauge")
+	fmt.Fprintf(w, "blimp_self_test_last_run_timestamp_seconds %d\n", state.last.StartedAt)
+
+	fmt.Fprintln(w, "# HELP blimp_self_test_step_success Whether each step of the most recent self-test passed.")
+	fmt.Fprintln(w, "# TYPE blimp_self_test_step_success gauge")
+	for _, step := range state.last.Steps {
+		fmt.Fprintf(w, "blimp_self_test_step_success{step=%q} %d\n", step.Name, boolToInt(step.Passed))
+	}
+
+	fmt.Fprintln(w, "# HELP blimp_self_test_step_duration_seconds How long each step of the most recent self-test took.")
+	fmt.Fprintln(w, "# TYPE blimp_self_test_step_duration_seconds gauge")
+	for _, step := range state.last.Steps {
+		fmt.Fprintf(w, "blimp_self_test_step_duration_seconds{step=%q} %.3f\n",
+			step.Name, float64(step.DurationMs)/1000)
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
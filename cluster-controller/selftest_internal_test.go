@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func TestCheckPhaseTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  []cluster.ServicePhase
+		phase    cluster.ServicePhase
+		expError bool
+	}{
+		{
+			name:    "Normal boot",
+			history: []cluster.ServicePhase{cluster.ServicePhase_PENDING, cluster.ServicePhase_WAIT_DEPENDS_ON},
+			phase:   cluster.ServicePhase_RUNNING,
+		},
+		{
+			name:     "Crashed",
+			history:  []cluster.ServicePhase{cluster.ServicePhase_PENDING},
+			phase:    cluster.ServicePhase_UNHEALTHY,
+			expError: true,
+		},
+		{
+			name:     "Stopped running",
+			history:  []cluster.ServicePhase{cluster.ServicePhase_RUNNING},
+			phase:    cluster.ServicePhase_PENDING,
+			expError: true,
+		},
+		{
+			name:     "Init phase went backwards",
+			history:  []cluster.ServicePhase{cluster.ServicePhase_WAIT_SYNC_BIND},
+			phase:    cluster.ServicePhase_INITIALIZING_VOLUMES,
+			expError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := checkPhaseTransition("web", test.history, &cluster.ServiceStatus{Phase: test.phase})
+			if test.expError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWriteSelfTestMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	writeSelfTestMetrics(&buf, &selfTestState{failures: 1})
+	assert.Contains(t, buf.String(), `blimp_self_test_runs_total{result="fail"} 1`)
+	assert.NotContains(t, buf.String(), "blimp_self_test_success")
+
+	buf.Reset()
+	writeSelfTestMetrics(&buf, &selfTestState{
+		passes: 1,
+		last: &cluster.SelfTestResult{
+			Passed:    true,
+			StartedAt: 1600000000,
+			Steps: []*cluster.SelfTestStep{
+				{Name: "boot", Passed: true, DurationMs: 1500},
+			},
+		},
+	})
+	assert.Contains(t, buf.String(), "blimp_self_test_success 1\n")
+	assert.Contains(t, buf.String(), "blimp_self_test_last_run_timestamp_seconds 1600000000\n")
+	assert.Contains(t, buf.String(), `blimp_self_test_step_success{step="boot"} 1`)
+	assert.Contains(t, buf.String(), `blimp_self_test_step_duration_seconds{step="boot"} 1.500`)
+}
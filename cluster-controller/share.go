@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/expose"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// maxShareLinkDuration caps how long share links are valid for. Share tokens
+// aren't stored anywhere, so they can't be revoked before they expire.
+const maxShareLinkDuration = 7 * 24 * time.Hour
+
+// loadShareLinkKey returns the key used to sign share tokens. Share links are
+// disabled if BLIMP_SHARE_LINK_SECRET isn't set. Changing the secret revokes
+// all outstanding share tokens.
+func loadShareLinkKey() []byte {
+	secret := os.Getenv("BLIMP_SHARE_LINK_SECRET")
+	if secret == "" {
+		return nil
+	}
+	return []byte(secret)
+}
+
+func (s *server) CreateShareLink(ctx context.Context, req *cluster.CreateShareLinkRequest) (
+	*cluster.CreateShareLinkResponse, error) {
+	log.Info("Start CreateShareLink")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.CreateShareLinkResponse{}, err
+	}
+
+	if s.shareLinkKey == nil {
+		return &cluster.CreateShareLinkResponse{}, errors.NewFriendlyError(
+			"Share links are disabled on this cluster.\n" +
+				"Set BLIMP_SHARE_LINK_SECRET on the Blimp manager to enable them.")
+	}
+
+	if len(req.GetPorts()) == 0 && len(req.GetScopes()) == 0 {
+		return &cluster.CreateShareLinkResponse{}, errors.NewFriendlyError(
+			"Nothing to share. Specify the services to share with --service, " +
+				"or the access to grant with --scope.")
+	}
+
+	for _, scope := range req.GetScopes() {
+		if _, ok := tokenScopes[scope]; !ok {
+			return &cluster.CreateShareLinkResponse{}, errors.NewFriendlyError(
+				"Unknown scope %q. The valid scopes are: %s.", scope, validScopes())
+		}
+	}
+
+	for _, port := range req.GetPorts() {
+		if port.GetPort() < 1 || port.GetPort() > 65535 {
+			return &cluster.CreateShareLinkResponse{}, errors.NewFriendlyError(
+				"Port must be between 1 and 65535")
+		}
+	}
+
+	expiresIn := time.Duration(req.GetExpiresIn()) * time.Second
+	if expiresIn <= 0 || expiresIn > maxShareLinkDuration {
+		return &cluster.CreateShareLinkResponse{}, errors.NewFriendlyError(
+			"Share links must expire within %d days.", int(maxShareLinkDuration.Hours()/24))
+	}
+
+	_, err = s.kubeClient.CoreV1().Namespaces().Get(user.Namespace, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return &cluster.CreateShareLinkResponse{}, errors.NewCodedError(
+				errors.CodeSandboxNotFound, "Sandbox does not exist")
+		}
+		return &cluster.CreateShareLinkResponse{}, errors.WithContext("get sandbox", err)
+	}
+
+	expiresAt := time.Now().Add(expiresIn).Unix()
+	var links []string
+	for _, port := range req.GetPorts() {
+		link, err := s.expose(user.Namespace, expose.ExposeInfo{
+			Service:   port.GetService(),
+			Port:      int(port.GetPort()),
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return &cluster.CreateShareLinkResponse{}, errors.WithContext("expose service", err)
+		}
+		links = append(links, link)
+	}
+
+	var token string
+	if len(req.GetScopes()) != 0 {
+		token, err = auth.SignShareToken(s.shareLinkKey, auth.ShareClaims{
+			Namespace: user.Namespace,
+			Scopes:    req.GetScopes(),
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return &cluster.CreateShareLinkResponse{}, errors.WithContext("sign token", err)
+		}
+	}
+
+	log.WithField("namespace", user.Namespace).
+		WithField("expiresAt", time.Unix(expiresAt, 0)).
+		Info("Created share link")
+	return &cluster.CreateShareLinkResponse{
+		Token:     token,
+		Links:     links,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// verifyShareToken checks that the share token is valid, and that it allows
+// the RPC. It returns the token of the sandbox's owner.
+func (s *server) verifyShareToken(token, method string) (string, error) {
+	invalidTokenErr := errors.NewCodedError(errors.CodeUnauthorized, "Invalid share token.")
+	if s.shareLinkKey == nil {
+		return "", invalidTokenErr
+	}
+
+	claims, err := auth.VerifyShareToken(s.shareLinkKey, token, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	if !(scopedToken{Scopes: claims.Scopes}).allows(method) {
+		return "", errors.NewCodedError(errors.CodeUnauthorized,
+			"This share link doesn't have access to %s. Its scopes are: %s.",
+			method[strings.LastIndex(method, "/")+1:], strings.Join(claims.Scopes, ", "))
+	}
+
+	ns, err := s.kubeClient.CoreV1().Namespaces().Get(claims.Namespace, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", errors.NewFriendlyError("The shared sandbox no longer exists.")
+		}
+		return "", errors.WithContext("get namespace", err)
+	}
+
+	owner, ok := ns.Annotations[kube.OwnerAnnotation]
+	if !ok {
+		return "", invalidTokenErr
+	}
+	return owner, nil
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kelda/blimp/cluster-controller/volume"
+	"github.com/kelda/blimp/pkg/auth"
+	clusterAuth "github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/hash"
+	"github.com/kelda/blimp/pkg/names"
+	protoAuth "github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// snapshotRepo is the repository within each user's image namespace that
+	// stores their sandbox snapshots. The registry lets any user pull from it
+	// so that snapshots can be shared.
+	snapshotRepo = "snapshots"
+
+	// snapshotArtifact is the image within a snapshot that contains the
+	// sandbox's volumes. The snapshot's metadata is stored in the image's
+	// config.
+	snapshotArtifact = "sandbox"
+
+	snapshotMetadataLabel = "io.kelda.blimp.snapshot"
+)
+
+// snapshotIDPattern matches snapshot IDs, which are the owner's namespace
+// followed by a random suffix.
+var snapshotIDPattern = regexp.MustCompile(`^[a-z0-9-]+/[0-9a-f]+$`)
+
+type snapshotMetadata struct {
+	ComposeFile string            `json:"composeFile"`
+	BuiltImages map[string]string `json:"builtImages"`
+	CreatedAt   int64             `json:"createdAt"`
+
+	// BindVolumes maps the paths of the bind volumes in the original sandbox
+	// to the named volumes that replace them in the snapshot.
+	BindVolumes map[string]string `json:"bindVolumes,omitempty"`
+}
+
+// CreateSnapshot captures the images, volumes, and Compose file of the
+// user's sandbox into an artifact in the Blimp registry.
+func (s *server) CreateSnapshot(ctx context.Context, req *cluster.CreateSnapshotRequest) (
+	*cluster.CreateSnapshotResponse, error) {
+	user, err := clusterAuth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.CreateSnapshotResponse{}, err
+	}
+
+	dcCfg, err := compose.Parse([]byte(req.GetComposeFile()))
+	if err != nil {
+		return &cluster.CreateSnapshotResponse{}, err
+	}
+
+	images, err := s.getServiceImages(user.Namespace, dcCfg.ServiceNames())
+	if err != nil {
+		return &cluster.CreateSnapshotResponse{}, err
+	}
+
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return &cluster.CreateSnapshotResponse{}, errors.WithContext("generate id", err)
+	}
+	id := fmt.Sprintf("%s/%x", user.Namespace, idBytes)
+
+	creds := req.GetRegistryCredentials()
+	if creds == nil {
+		creds = map[string]*cluster.RegistryCredential{}
+	}
+
+	blimpRegCred, err := auth.BlimpRegcred(req.GetAuth())
+	if err != nil {
+		return &cluster.CreateSnapshotResponse{}, errors.WithContext("create Blimp registry credential", err)
+	}
+	creds[RegistryHostname] = blimpRegCred.ToProtobuf()
+	if ImageCacheHostname != "" {
+		creds[ImageCacheHostname] = blimpRegCred.ToProtobuf()
+	}
+
+	// Copy the images so that the snapshot boots the same code, even if the
+	// original tags are overwritten.
+	builtImages := map[string]string{}
+	for svc, image := range images {
+		snapshotImage := snapshotRef(id, names.ToDNS1123(svc))
+		if err := s.pushImage(image, snapshotImage, req.GetAuth(), creds); err != nil {
+			return &cluster.CreateSnapshotResponse{}, errors.WithContext(fmt.Sprintf("copy image for %s", svc), err)
+		}
+		builtImages[svc] = snapshotImage
+	}
+
+	snapshotCfg, bindVolumes := toSnapshotCompose(dcCfg)
+	composeFile, err := compose.Marshal(snapshotCfg)
+	if err != nil {
+		return &cluster.CreateSnapshotResponse{}, errors.WithContext("marshal compose file", err)
+	}
+
+	metadata := snapshotMetadata{
+		ComposeFile: string(composeFile),
+		BuiltImages: builtImages,
+		CreatedAt:   time.Now().Unix(),
+		BindVolumes: bindVolumes,
+	}
+	err = s.pushSnapshotVolumes(ctx, user, snapshotRef(id, snapshotArtifact), metadata,
+		blimpRegCred.ToContainerRegistry())
+	if err != nil {
+		return &cluster.CreateSnapshotResponse{}, errors.WithContext("save volumes", err)
+	}
+
+	log.WithField("namespace", user.Namespace).WithField("id", id).Info("Created snapshot")
+	return &cluster.CreateSnapshotResponse{Id: id}, nil
+}
+
+// GetSnapshot returns the Compose file and images that should be deployed to
+// boot the snapshot.
+func (s *server) GetSnapshot(ctx context.Context, req *cluster.GetSnapshotRequest) (
+	*cluster.GetSnapshotResponse, error) {
+	if _, err := clusterAuth.AuthorizeRequest(req.GetAuth()); err != nil {
+		return &cluster.GetSnapshotResponse{}, err
+	}
+
+	_, metadata, err := getSnapshot(req.GetId(), req.GetAuth())
+	if err != nil {
+		return &cluster.GetSnapshotResponse{}, err
+	}
+
+	return &cluster.GetSnapshotResponse{
+		ComposeFile: metadata.ComposeFile,
+		BuiltImages: metadata.BuiltImages,
+		CreatedAt:   metadata.CreatedAt,
+	}, nil
+}
+
+// BootSnapshot restores the snapshot's volumes into the user's sandbox. The
+// sandbox must not have any services deployed, since their volumes are
+// replaced.
+func (s *server) BootSnapshot(ctx context.Context, req *cluster.BootSnapshotRequest) (
+	*cluster.BootSnapshotResponse, error) {
+	user, err := clusterAuth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.BootSnapshotResponse{}, err
+	}
+
+	pods, err := s.statusFetcher.podLister.Pods(user.Namespace).List(
+		labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return &cluster.BootSnapshotResponse{}, errors.WithContext("list pods", err)
+	}
+	for _, pod := range pods {
+		if pod.Name != "reservation" {
+			return &cluster.BootSnapshotResponse{}, errors.NewFriendlyError(
+				"Your sandbox already has services deployed. " +
+					"Run `blimp down` before booting a snapshot.")
+		}
+	}
+
+	img, metadata, err := getSnapshot(req.GetId(), req.GetAuth())
+	if err != nil {
+		return &cluster.BootSnapshotResponse{}, err
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return &cluster.BootSnapshotResponse{}, errors.WithContext("get volumes", err)
+	}
+	if len(layers) != 1 {
+		return &cluster.BootSnapshotResponse{}, errors.New("expected one layer, got %d", len(layers))
+	}
+
+	volumes, err := layers[0].Uncompressed()
+	if err != nil {
+		return &cluster.BootSnapshotResponse{}, errors.WithContext("read volumes", err)
+	}
+	defer volumes.Close()
+
+	helper, cleanup, err := s.startVolumeHelper(ctx, user)
+	if err != nil {
+		return &cluster.BootSnapshotResponse{}, err
+	}
+	defer cleanup()
+
+	err = s.execInPod(helper, []string{"sh", "-c", "rm -rf /pv/* && tar -C /pv -xf -"}, volumes, nil)
+	if err != nil {
+		return &cluster.BootSnapshotResponse{}, errors.WithContext("restore volumes", err)
+	}
+
+	// Move the contents of the bind volumes to the named volumes that
+	// replace them. Nested paths are moved first so that they aren't moved
+	// along with their parents.
+	var bindPaths []string
+	for path := range metadata.BindVolumes {
+		bindPaths = append(bindPaths, path)
+	}
+	sort.Slice(bindPaths, func(i, j int) bool {
+		return len(bindPaths[i]) > len(bindPaths[j])
+	})
+
+	for _, path := range bindPaths {
+		src := filepath.Join("/pv", volume.BindVolumeDir(path))
+		dst := filepath.Join("/pv", volume.NamedVolumeDir(metadata.BindVolumes[path]))
+		cmd := []string{"sh", "-c",
+			`if [ -e "$1" ]; then mkdir -p "$(dirname "$2")" && rm -rf "$2" && mv "$1" "$2"; fi`,
+			"sh", src, dst}
+		if err := s.execInPod(helper, cmd, nil, nil); err != nil {
+			return &cluster.BootSnapshotResponse{}, errors.WithContext("move bind volume", err)
+		}
+	}
+
+	log.WithField("namespace", user.Namespace).WithField("id", req.GetId()).Info("Restored snapshot")
+	return &cluster.BootSnapshotResponse{}, nil
+}
+
+// getServiceImages returns the images that the given services are running.
+func (s *server) getServiceImages(namespace string, services []string) (map[string]string, error) {
+	ns, err := s.statusFetcher.namespaceLister.Get(namespace)
+	if err != nil {
+		return nil, errors.WithContext("get namespace", err)
+	}
+	if _, ok, _ := parsePaused(ns); ok {
+		return nil, errors.NewFriendlyError(
+			"Your sandbox is paused. Run `blimp resume` before creating a snapshot.")
+	}
+
+	type servicePod struct {
+		service string
+		spec    corev1.PodSpec
+	}
+
+	var podSpecs []servicePod
+	pods, err := s.statusFetcher.podLister.Pods(namespace).List(
+		labels.Set{"blimp.customerPod": "true"}.AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("list pods", err)
+	}
+	for _, pod := range pods {
+		if isSurgePod(pod) {
+			continue
+		}
+		podSpecs = append(podSpecs, servicePod{pod.Labels["blimp.service"], pod.Spec})
+	}
+
+	cronJobs, err := s.statusFetcher.cronJobLister.CronJobs(namespace).List(
+		labels.Set{scheduledLabel: "true"}.AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("list cronjobs", err)
+	}
+	for _, cronJob := range cronJobs {
+		podSpecs = append(podSpecs, servicePod{
+			cronJob.Labels["blimp.service"], cronJob.Spec.JobTemplate.Spec.Template.Spec})
+	}
+
+	running := map[string]string{}
+	for _, spec := range podSpecs {
+		for _, c := range spec.spec.Containers {
+			if c.Name == names.ToDNS1123(spec.service) {
+				running[spec.service] = c.Image
+			}
+		}
+	}
+
+	images := map[string]string{}
+	for _, svc := range services {
+		image, ok := running[svc]
+		if !ok {
+			return nil, errors.NewFriendlyError(
+				"%s isn't deployed, so it can't be included in the snapshot.\n"+
+					"Run `blimp up` before creating a snapshot.", svc)
+		}
+		images[svc] = image
+	}
+	return images, nil
+}
+
+// toSnapshotCompose converts the Compose file so that it boots from the
+// snapshot. Bind volumes are replaced by named volumes since the users that
+// boot the snapshot don't have the original files, and all services are
+// marked as built so that they use the snapshot's copies of their images. It
+// returns a map from the original bind volumes to the named volumes that
+// replace them.
+func toSnapshotCompose(cfg composeTypes.Project) (composeTypes.Project, map[string]string) {
+	bindVolumes := map[string]string{}
+	volumes := map[string]composeTypes.VolumeConfig{}
+	for volName, vol := range cfg.Volumes {
+		if source, ok := compose.ParseNamedBindVolume(vol); ok {
+			bindVolumes[source] = volName
+			vol = composeTypes.VolumeConfig{}
+		}
+		volumes[volName] = vol
+	}
+
+	var services []composeTypes.ServiceConfig
+	for _, svc := range cfg.Services {
+		var svcVolumes []composeTypes.ServiceVolumeConfig
+		for _, v := range svc.Volumes {
+			if v.Type == composeTypes.VolumeTypeBind {
+				volName := "bind-" + hash.DNSCompliant(v.Source)
+				bindVolumes[v.Source] = volName
+				volumes[volName] = composeTypes.VolumeConfig{}
+
+				v.Type = composeTypes.VolumeTypeVolume
+				v.Source = volName
+				v.Bind = nil
+			}
+			svcVolumes = append(svcVolumes, v)
+		}
+		svc.Volumes = svcVolumes
+		svc.Build = &composeTypes.BuildConfig{}
+		services = append(services, svc)
+	}
+
+	cfg.Services = services
+	cfg.Volumes = volumes
+	return cfg, bindVolumes
+}
+
+// pushSnapshotVolumes pushes an image containing the contents of the user's
+// volumes and the snapshot's metadata.
+func (s *server) pushSnapshotVolumes(ctx context.Context, user auth.User, refStr string,
+	metadata snapshotMetadata, regcred authn.Authenticator) error {
+	helper, cleanup, err := s.startVolumeHelper(ctx, user)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// Buffer the volumes on disk since the layer is read more than once
+	// while it's pushed.
+	f, err := ioutil.TempFile("", "blimp-snapshot-")
+	if err != nil {
+		return errors.WithContext("create temp file", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := s.execInPod(helper, []string{"tar", "-C", "/pv", "-cf", "-", "."}, nil, f); err != nil {
+		return errors.WithContext("export", err)
+	}
+
+	layer, err := tarball.LayerFromFile(f.Name())
+	if err != nil {
+		return errors.WithContext("make layer", err)
+	}
+
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		return errors.WithContext("make image", err)
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.WithContext("marshal metadata", err)
+	}
+
+	img, err = mutate.Config(img, v1.Config{
+		Labels: map[string]string{snapshotMetadataLabel: string(metadataJSON)},
+	})
+	if err != nil {
+		return errors.WithContext("set metadata", err)
+	}
+
+	ref, err := name.ParseReference(refStr)
+	if err != nil {
+		return errors.WithContext("parse reference", err)
+	}
+
+	if err := remote.Write(ref, img, remote.WithAuth(regcred)); err != nil {
+		return errors.WithContext("push", err)
+	}
+	return nil
+}
+
+// getSnapshot pulls the image containing the snapshot's volumes, and parses
+// its metadata.
+func getSnapshot(id string, blimpAuth *protoAuth.BlimpAuth) (v1.Image, snapshotMetadata, error) {
+	if !snapshotIDPattern.MatchString(id) {
+		return nil, snapshotMetadata{}, errors.NewFriendlyError("%q isn't a valid snapshot ID.", id)
+	}
+
+	ref, err := name.ParseReference(snapshotRef(id, snapshotArtifact))
+	if err != nil {
+		return nil, snapshotMetadata{}, errors.WithContext("parse reference", err)
+	}
+
+	regcred, err := auth.BlimpRegcred(blimpAuth)
+	if err != nil {
+		return nil, snapshotMetadata{}, errors.WithContext("create Blimp registry credential", err)
+	}
+
+	img, err := remote.Image(ref, remote.WithAuth(regcred.ToContainerRegistry()))
+	if err != nil {
+		return nil, snapshotMetadata{}, errors.NewFriendlyError(
+			"Failed to get snapshot %s. Check that the ID is correct.\n%s", id, err)
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, snapshotMetadata{}, errors.WithContext("get config", err)
+	}
+
+	metadataJSON, ok := cfg.Config.Labels[snapshotMetadataLabel]
+	if !ok {
+		return nil, snapshotMetadata{}, errors.New("snapshot is missing metadata")
+	}
+
+	var metadata snapshotMetadata
+	if err := json.Unmarshal([]byte(metadataJSON), &metadata); err != nil {
+		return nil, snapshotMetadata{}, errors.WithContext("parse metadata", err)
+	}
+	return img, metadata, nil
+}
+
+// snapshotRef returns the image reference for an artifact in the snapshot.
+func snapshotRef(id, artifact string) string {
+	parts := strings.SplitN(id, "/", 2)
+	return fmt.Sprintf("%s/%s/%s/%s/%s:latest", RegistryHostname, parts[0], snapshotRepo, parts[1], artifact)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelda/blimp/pkg/hash"
+)
+
+func TestToSnapshotCompose(t *testing.T) {
+	cfg := composeTypes.Project{
+		Services: []composeTypes.ServiceConfig{
+			{
+				Name:  "web",
+				Build: &composeTypes.BuildConfig{Context: "."},
+				Volumes: []composeTypes.ServiceVolumeConfig{
+					{
+						Type:   composeTypes.VolumeTypeBind,
+						Source: "/app",
+						Target: "/usr/src/app",
+					},
+					{
+						Type:   composeTypes.VolumeTypeVolume,
+						Source: "code",
+						Target: "/code",
+					},
+					{
+						Type:   composeTypes.VolumeTypeVolume,
+						Source: "data",
+						Target: "/data",
+					},
+				},
+			},
+			{
+				Name:  "db",
+				Image: "postgres",
+			},
+		},
+		Volumes: map[string]composeTypes.VolumeConfig{
+			"code": {
+				DriverOpts: map[string]string{
+					"o":      "bind",
+					"device": "/code",
+				},
+			},
+			"data": {},
+		},
+	}
+
+	bindVolumeName := "bind-" + hash.DNSCompliant("/app")
+	expCfg := composeTypes.Project{
+		Services: []composeTypes.ServiceConfig{
+			{
+				Name:  "web",
+				Build: &composeTypes.BuildConfig{},
+				Volumes: []composeTypes.ServiceVolumeConfig{
+					{
+						Type:   composeTypes.VolumeTypeVolume,
+						Source: bindVolumeName,
+						Target: "/usr/src/app",
+					},
+					{
+						Type:   composeTypes.VolumeTypeVolume,
+						Source: "code",
+						Target: "/code",
+					},
+					{
+						Type:   composeTypes.VolumeTypeVolume,
+						Source: "data",
+						Target: "/data",
+					},
+				},
+			},
+			{
+				Name:  "db",
+				Image: "postgres",
+				Build: &composeTypes.BuildConfig{},
+			},
+		},
+		Volumes: map[string]composeTypes.VolumeConfig{
+			"code":         {},
+			"data":         {},
+			bindVolumeName: {},
+		},
+	}
+	expBindVolumes := map[string]string{
+		"/app":  bindVolumeName,
+		"/code": "code",
+	}
+
+	snapshotCfg, bindVolumes := toSnapshotCompose(cfg)
+	assert.Equal(t, expCfg, snapshotCfg)
+	assert.Equal(t, expBindVolumes, bindVolumes)
+}
+
+func TestSnapshotRef(t *testing.T) {
+	id := "a1b2c3/0123456789abcdef"
+	assert.True(t, snapshotIDPattern.MatchString(id))
+	assert.Equal(t, RegistryHostname+"/a1b2c3/snapshots/0123456789abcdef/web:latest",
+		snapshotRef(id, "web"))
+
+	for _, invalid := range []string{"", "0123456789abcdef", "a1b2c3/../other", "A/0123"} {
+		assert.False(t, snapshotIDPattern.MatchString(invalid), invalid)
+	}
+}
@@ -3,20 +3,25 @@ package main
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	batchlisters "k8s.io/client-go/listers/batch/v1beta1"
 	listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 
-	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/cluster-controller/capacity"
 	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
 	"github.com/kelda/blimp/pkg/proto/cluster"
 )
 
@@ -25,6 +30,10 @@ const (
 		"and that Blimp has access to it."
 	imagePullingMsg = "Pulling image"
 
+	waitingForCapacityMsg = "The cluster is adding servers to make room for this service"
+
+	waitingForReadyLogMsg = "Waiting for a log line matching ready_log_pattern"
+
 	createContainerErrorTemplate = "Encountered blimp system error (%s: %s). " +
 		"If this error persists, redeploy your sandbox with `blimp down && blimp up`"
 )
@@ -39,16 +48,27 @@ type statusFetcher struct {
 	eventsLister      listers.EventLister
 	namespaceInformer cache.SharedIndexInformer
 	namespaceLister   listers.NamespaceLister
+	cronJobInformer   cache.SharedIndexInformer
+	cronJobLister     batchlisters.CronJobLister
+	nodeInformer      cache.SharedIndexInformer
+	nodeLister        listers.NodeLister
 
 	podWatcher       *kube.Watcher
 	namespaceWatcher *kube.Watcher
+	eventsWatcher    *kube.Watcher
 }
 
-func newStatusFetcher(kubeClient kubernetes.Interface) *statusFetcher {
-	factory := informers.NewSharedInformerFactory(kubeClient, 30*time.Second)
+func newStatusFetcher(kubeClient kubernetes.Interface, config informerConfig) *statusFetcher {
+	factory := informers.NewSharedInformerFactory(kubeClient, config.resyncPeriod)
+	eventsFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, config.resyncPeriod,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = eventsFieldSelector
+		}))
 	podInformer := factory.Core().V1().Pods()
-	eventsInformer := factory.Core().V1().Events()
+	eventsInformer := eventsFactory.Core().V1().Events()
 	namespaceInformer := factory.Core().V1().Namespaces()
+	cronJobInformer := factory.Batch().V1beta1().CronJobs()
+	nodeInformer := factory.Core().V1().Nodes()
 
 	return &statusFetcher{
 		podInformer:       podInformer.Informer(),
@@ -57,8 +77,13 @@ func newStatusFetcher(kubeClient kubernetes.Interface) *statusFetcher {
 		eventsLister:      eventsInformer.Lister(),
 		namespaceInformer: namespaceInformer.Informer(),
 		namespaceLister:   namespaceInformer.Lister(),
+		cronJobInformer:   cronJobInformer.Informer(),
+		cronJobLister:     cronJobInformer.Lister(),
+		nodeInformer:      nodeInformer.Informer(),
+		nodeLister:        nodeInformer.Lister(),
 		podWatcher:        kube.NewWatcher(podInformer.Informer()),
 		namespaceWatcher:  kube.NewWatcher(namespaceInformer.Informer()),
+		eventsWatcher:     kube.NewWatcher(eventsInformer.Informer()),
 	}
 }
 
@@ -66,12 +91,49 @@ func (sf *statusFetcher) Start(stop chan struct{}) {
 	go sf.podInformer.Run(stop)
 	go sf.eventsInformer.Run(stop)
 	go sf.namespaceInformer.Run(stop)
+	go sf.cronJobInformer.Run(stop)
+	go sf.nodeInformer.Run(stop)
 	cache.WaitForCacheSync(stop, sf.podInformer.HasSynced)
 	cache.WaitForCacheSync(stop, sf.eventsInformer.HasSynced)
 	cache.WaitForCacheSync(stop, sf.namespaceInformer.HasSynced)
+	cache.WaitForCacheSync(stop, sf.cronJobInformer.HasSynced)
+	cache.WaitForCacheSync(stop, sf.nodeInformer.HasSynced)
+}
+
+// Watch returns a channel that receives a notification whenever the status
+// of the namespace may have changed. If services are specified, changes to
+// other services in the namespace are ignored.
+func (sf *statusFetcher) Watch(ctx context.Context, namespace string, services ...string) chan struct{} {
+	// Send notifications whenever a relevant pod within the namespace
+	// changes, or the namespace itself changes.
+	subs := []chan struct{}{sf.namespaceWatcher.Watch(ctx, kube.Key{Name: namespace})}
+	if len(services) == 0 {
+		subs = append(subs, sf.podWatcher.Watch(ctx, kube.Key{Namespace: namespace}))
+	}
+	for _, svc := range services {
+		key := kube.Key{Namespace: namespace, Name: names.ToDNS1123(svc)}
+
+		// Each run of a scheduled service has a different pod name.
+		if sf.isScheduled(namespace, svc) {
+			key = kube.Key{Namespace: namespace}
+		}
+		subs = append(subs, sf.podWatcher.Watch(ctx, key))
+	}
+	return mergeNotifications(ctx, subs)
 }
 
-func (sf *statusFetcher) Watch(ctx context.Context, namespace string) chan struct{} {
+// WatchLifecycle returns a channel that receives a notification whenever a
+// pod or Kubernetes event in the namespace changes.
+func (sf *statusFetcher) WatchLifecycle(ctx context.Context, namespace string) chan struct{} {
+	return mergeNotifications(ctx, []chan struct{}{
+		sf.podWatcher.Watch(ctx, kube.Key{Namespace: namespace}),
+		sf.eventsWatcher.Watch(ctx, kube.Key{Namespace: namespace}),
+	})
+}
+
+// mergeNotifications returns a channel that receives a notification whenever
+// any of the given channels does.
+func mergeNotifications(ctx context.Context, subs []chan struct{}) chan struct{} {
 	notifier := make(chan struct{}, 1)
 	notify := func() {
 		select {
@@ -80,26 +142,51 @@ func (sf *statusFetcher) Watch(ctx context.Context, namespace string) chan struc
 		}
 	}
 
-	// Send notifications whenever a pod within the namespace changes, or the
-	// namespace itself changes.
-	podSub := sf.podWatcher.Watch(ctx, kube.Key{Namespace: namespace})
-	namespaceSub := sf.namespaceWatcher.Watch(ctx, kube.Key{Name: namespace})
-	go func() {
-		for {
-			select {
-			case <-podSub:
-				notify()
-			case <-namespaceSub:
-				notify()
-			case <-ctx.Done():
-				return
+	for _, sub := range subs {
+		sub := sub
+		go func() {
+			for {
+				select {
+				case <-sub:
+					notify()
+				case <-ctx.Done():
+					return
+				}
 			}
-		}
-	}()
+		}()
+	}
 
 	return notifier
 }
 
+// GetMany returns the status of each of the given namespaces. The statuses
+// are fetched concurrently, but since they're read from the informer caches,
+// no requests are made to the Kubernetes API.
+func (sf *statusFetcher) GetMany(namespaces []string) (map[string]cluster.SandboxStatus, error) {
+	var lock sync.Mutex
+	var group errgroup.Group
+	statuses := map[string]cluster.SandboxStatus{}
+	for _, namespace := range namespaces {
+		namespace := namespace
+		group.Go(func() error {
+			status, err := sf.Get(namespace)
+			if err != nil {
+				return errors.WithContext(fmt.Sprintf("get status of %s", namespace), err)
+			}
+
+			lock.Lock()
+			statuses[namespace] = status
+			lock.Unlock()
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
 func (sf *statusFetcher) Get(namespace string) (cluster.SandboxStatus, error) {
 	ns, err := sf.namespaceLister.Get(namespace)
 	if err != nil {
@@ -113,6 +200,12 @@ func (sf *statusFetcher) Get(namespace string) (cluster.SandboxStatus, error) {
 		return cluster.SandboxStatus{Phase: cluster.SandboxStatus_TERMINATING}, nil
 	}
 
+	// The services in paused sandboxes aren't running, so there's nothing
+	// else to report.
+	if _, ok := ns.Annotations[kube.PausedAnnotation]; ok {
+		return cluster.SandboxStatus{Phase: cluster.SandboxStatus_PAUSED}, nil
+	}
+
 	pods, err := sf.podLister.
 		Pods(namespace).
 		List(labels.Set(
@@ -122,6 +215,28 @@ func (sf *statusFetcher) Get(namespace string) (cluster.SandboxStatus, error) {
 		return cluster.SandboxStatus{}, errors.WithContext("get services", err)
 	}
 
+	// Also include the pods created by workloads in Kubernetes manifests.
+	manifestPods, err := sf.podLister.
+		Pods(namespace).
+		List(labels.Set(
+			map[string]string{manifestLabel: "true"},
+		).AsSelector())
+	if err != nil {
+		return cluster.SandboxStatus{}, errors.WithContext("get manifest services", err)
+	}
+	pods = append(pods, manifestPods...)
+
+	// And the pods running add-ons created with `blimp addons`.
+	addonPods, err := sf.podLister.
+		Pods(namespace).
+		List(labels.Set(
+			map[string]string{addonLabel: "true"},
+		).AsSelector())
+	if err != nil {
+		return cluster.SandboxStatus{}, errors.WithContext("get add-ons", err)
+	}
+	pods = append(pods, addonPods...)
+
 	sandboxPhase := cluster.SandboxStatus_RUNNING
 
 	services := map[string]*cluster.ServiceStatus{}
@@ -133,16 +248,50 @@ func (sf *statusFetcher) Get(namespace string) (cluster.SandboxStatus, error) {
 			sandboxPhase = cluster.SandboxStatus_PREPARING
 			continue
 		}
+
+		// Surge pods only run while the service's pod is being updated, so
+		// they're an implementation detail of the update.
+		if isSurgePod(pod) {
+			continue
+		}
+
 		svcName := pod.GetLabels()["blimp.service"]
 		serviceStatus := sf.getServiceStatus(pod)
+		serviceStatus.Project = pod.GetLabels()[projectLabel]
 		services[svcName] = &serviceStatus
 	}
+
+	scheduled, err := sf.getScheduledStatuses(namespace)
+	if err != nil {
+		return cluster.SandboxStatus{}, errors.WithContext("get scheduled services", err)
+	}
+	for svcName, status := range scheduled {
+		services[svcName] = status
+	}
 	return cluster.SandboxStatus{
 		Phase:    sandboxPhase,
 		Services: services,
+		Warnings: parseImageScanWarnings(ns),
 	}, nil
 }
 
+// filterServices removes the statuses for services that aren't in `services`.
+// If `services` is empty, the status is returned unchanged.
+func filterServices(status cluster.SandboxStatus, services []string) cluster.SandboxStatus {
+	if len(services) == 0 {
+		return status
+	}
+
+	filtered := map[string]*cluster.ServiceStatus{}
+	for _, svc := range services {
+		if svcStatus, ok := status.Services[svc]; ok {
+			filtered[svc] = svcStatus
+		}
+	}
+	status.Services = filtered
+	return status
+}
+
 func (sf *statusFetcher) isPulling(namespace, pod, fieldPath string) bool {
 	events, err := sf.eventsLister.Events(namespace).List(labels.Everything())
 	if err != nil {
@@ -151,8 +300,8 @@ func (sf *statusFetcher) isPulling(namespace, pod, fieldPath string) bool {
 	}
 
 	// Get the most recent timestamps of the image events.
-	var pullStarted metav1.Time
-	var pullCompleted metav1.Time
+	var pullStarted time.Time
+	var pullCompleted time.Time
 	for _, event := range events {
 		if event.InvolvedObject.Kind != "Pod" ||
 			event.InvolvedObject.Namespace != namespace ||
@@ -161,14 +310,15 @@ func (sf *statusFetcher) isPulling(namespace, pod, fieldPath string) bool {
 			continue
 		}
 
+		timestamp := eventTimestamp(event)
 		switch event.Reason {
 		case "Pulling":
-			if pullStarted.IsZero() || pullStarted.Before(&event.LastTimestamp) {
-				pullStarted = event.LastTimestamp
+			if pullStarted.IsZero() || pullStarted.Before(timestamp) {
+				pullStarted = timestamp
 			}
 		case "Pulled":
-			if pullCompleted.IsZero() || pullCompleted.Before(&event.LastTimestamp) {
-				pullCompleted = event.LastTimestamp
+			if pullCompleted.IsZero() || pullCompleted.Before(timestamp) {
+				pullCompleted = timestamp
 			}
 		}
 	}
@@ -180,10 +330,34 @@ func (sf *statusFetcher) isPulling(namespace, pod, fieldPath string) bool {
 
 	// We're currently pulling if a pull has never completed, or the completion
 	// event was from before the current image pull.
-	return pullCompleted.IsZero() || pullCompleted.Before(&pullStarted)
+	return pullCompleted.IsZero() || pullCompleted.Before(pullStarted)
 }
 
 func (sf *statusFetcher) getServiceStatus(pod *corev1.Pod) cluster.ServiceStatus {
+	if sf.isPreempted(pod) {
+		return cluster.ServiceStatus{
+			Phase: cluster.ServicePhase_RESCHEDULING,
+			Msg:   reschedulingMsg,
+		}
+	}
+
+	status := sf.getPodStatus(pod)
+	status.Mesh = getMeshStatus(pod)
+	status.VolumeInit = getVolumeInitStatus(pod, status.Phase)
+
+	// Pods that were moved off of a preempted node are reported as
+	// rescheduling until they make progress booting.
+	if _, ok := pod.Annotations[rescheduledAtKey]; ok &&
+		status.Phase == cluster.ServicePhase_PENDING && status.Msg == "" {
+		return cluster.ServiceStatus{
+			Phase: cluster.ServicePhase_RESCHEDULING,
+			Msg:   reschedulingMsg,
+		}
+	}
+	return status
+}
+
+func (sf *statusFetcher) getPodStatus(pod *corev1.Pod) cluster.ServiceStatus {
 	// Check if the pod isn't running because an init container is
 	// blocking boot.
 	for _, c := range pod.Status.InitContainerStatuses {
@@ -196,6 +370,8 @@ func (sf *statusFetcher) getServiceStatus(pod *corev1.Pod) cluster.ServiceStatus
 			phase = cluster.ServicePhase_WAIT_DEPENDS_ON
 		case kube.ContainerNameWaitInitialSync:
 			phase = cluster.ServicePhase_WAIT_SYNC_BIND
+		case meshInitContainerName:
+			phase = cluster.ServicePhase_PENDING
 		}
 
 		if c.State.Terminated != nil {
@@ -213,16 +389,18 @@ func (sf *statusFetcher) getServiceStatus(pod *corev1.Pod) cluster.ServiceStatus
 		// Because the volume initialization container uses the user's image,
 		// we need to explicitly tell users about those errors.
 		if c.Name == kube.ContainerNameInitializeVolumeFromImage {
+			fieldPath := fmt.Sprintf("spec.initContainers{%s}", kube.ContainerNameInitializeVolumeFromImage)
 			if isImagePullFailure(c) {
+				if sf.isRateLimited(pod, fieldPath, c) {
+					return rateLimitedStatus(pod)
+				}
 				return cluster.ServiceStatus{
 					Phase: cluster.ServicePhase_PENDING,
 					Msg:   imagePullFailureMsg,
 				}
 			}
 
-			isPulling := sf.isPulling(pod.Namespace, pod.Name,
-				fmt.Sprintf("spec.initContainers{%s}", kube.ContainerNameInitializeVolumeFromImage))
-			if isPulling {
+			if sf.isPulling(pod.Namespace, pod.Name, fieldPath) {
 				return cluster.ServiceStatus{
 					Phase: cluster.ServicePhase_PENDING,
 					Msg:   imagePullingMsg,
@@ -234,14 +412,19 @@ func (sf *statusFetcher) getServiceStatus(pod *corev1.Pod) cluster.ServiceStatus
 			return status
 		}
 
+		if status, ok := sf.checkInitTimeout(pod, c); ok {
+			return status
+		}
+
 		// For all other states, we just tell the user that we're still working on the
 		// system task.
 		return cluster.ServiceStatus{Phase: phase}
 	}
 
-	// Inspect the container's status to give more detailed information.
-	if len(pod.Status.ContainerStatuses) == 1 {
-		cs := pod.Status.ContainerStatuses[0]
+	// Inspect the service container's status to give more detailed
+	// information. Sidecars, whether they're added by Blimp, service meshes,
+	// or operator pod patches, don't affect the service's status.
+	if cs, ok := getContainerStatus(pod, names.ToDNS1123(pod.Labels["blimp.service"])); ok {
 		if status, ok := checkClusterError(cs); ok {
 			return status
 		}
@@ -254,21 +437,30 @@ func (sf *statusFetcher) getServiceStatus(pod *corev1.Pod) cluster.ServiceStatus
 					HasStarted: true,
 				}
 			}
+			if !kube.IsLogReady(*pod) {
+				return cluster.ServiceStatus{
+					Phase:      cluster.ServicePhase_UNHEALTHY,
+					Msg:        waitingForReadyLogMsg,
+					HasStarted: true,
+				}
+			}
 			return cluster.ServiceStatus{
 				Phase:      cluster.ServicePhase_RUNNING,
 				HasStarted: true,
 			}
 		case cs.State.Waiting != nil:
+			fieldPath := fmt.Sprintf("spec.containers{%s}", cs.Name)
 			if isImagePullFailure(cs) {
+				if sf.isRateLimited(pod, fieldPath, cs) {
+					return rateLimitedStatus(pod)
+				}
 				return cluster.ServiceStatus{
 					Phase: cluster.ServicePhase_PENDING,
 					Msg:   imagePullFailureMsg,
 				}
 			}
 
-			isPulling := sf.isPulling(pod.Namespace, pod.Name,
-				fmt.Sprintf("spec.containers{%s}", cs.Name))
-			if isPulling {
+			if sf.isPulling(pod.Namespace, pod.Name, fieldPath) {
 				return cluster.ServiceStatus{
 					Phase:      cluster.ServicePhase_PENDING,
 					Msg:        imagePullingMsg,
@@ -285,12 +477,19 @@ func (sf *statusFetcher) getServiceStatus(pod *corev1.Pod) cluster.ServiceStatus
 				Phase:      cluster.ServicePhase_EXITED,
 				Msg:        cs.State.Terminated.Message,
 				HasStarted: true,
+				ExitCode:   cs.State.Terminated.ExitCode,
 			}
 		}
 	}
 
 	// If we are pending because the pod is unschedulable, report this
 	// specifically.
+	if capacity.IsWaitingForCapacity(pod) {
+		return cluster.ServiceStatus{
+			Phase: cluster.ServicePhase_WAITING_FOR_CAPACITY,
+			Msg:   waitingForCapacityMsg,
+		}
+	}
 	if isUnschedulable(pod) {
 		return cluster.ServiceStatus{Phase: cluster.ServicePhase_UNSCHEDULABLE}
 	}
@@ -304,11 +503,20 @@ func (sf *statusFetcher) getServiceStatus(pod *corev1.Pod) cluster.ServiceStatus
 		}
 	case corev1.PodPending:
 		return cluster.ServiceStatus{Phase: cluster.ServicePhase_PENDING}
-	case corev1.PodSucceeded, corev1.PodFailed:
+	case corev1.PodSucceeded:
+		return cluster.ServiceStatus{
+			Phase:      cluster.ServicePhase_EXITED,
+			Msg:        pod.Status.Message,
+			HasStarted: true,
+		}
+	case corev1.PodFailed:
+		// The container's exit code isn't available, but it must have been
+		// non-zero.
 		return cluster.ServiceStatus{
 			Phase:      cluster.ServicePhase_EXITED,
 			Msg:        pod.Status.Message,
 			HasStarted: true,
+			ExitCode:   1,
 		}
 	default:
 		return cluster.ServiceStatus{Phase: cluster.ServicePhase_UNKNOWN}
@@ -1,16 +1,26 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	fakeKube "k8s.io/client-go/kubernetes/fake"
+	listers "k8s.io/client-go/listers/core/v1"
 
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/compose"
+	"github.com/kelda/blimp/pkg/errors"
 	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/names"
+	authProto "github.com/kelda/blimp/pkg/proto/auth"
 	"github.com/kelda/blimp/pkg/proto/cluster"
 )
 
@@ -41,7 +51,17 @@ func TestGetStatus(t *testing.T) {
 					},
 					Status: corev1.PodStatus{
 						ContainerStatuses: []corev1.ContainerStatus{
+							// Sidecars added by operator pod patches don't
+							// affect the service's status.
 							{
+								Name: "log-shipper",
+								State: corev1.ContainerState{
+									Running: &corev1.ContainerStateRunning{},
+								},
+								Ready: true,
+							},
+							{
+								Name: names.ToDNS1123("web"),
 								State: corev1.ContainerState{
 									Waiting: &corev1.ContainerStateWaiting{
 										Reason:  "CreateContainerError",
@@ -142,10 +162,265 @@ func TestGetStatus(t *testing.T) {
 						Msg: "The node was low on resource: memory. " +
 							"Container nuxtpublic-8c9fd51e73 was using 819944Ki, which exceeds its request of 50Mi.",
 						HasStarted: true,
+						ExitCode:   1,
 					},
 				},
 			},
 		},
+		{
+			name:      "ExitCode",
+			namespace: "namespace",
+			mockObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "namespace",
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "namespace",
+						Name:      "tests",
+						Labels: map[string]string{
+							"blimp.customerPod": "true",
+							"blimp.service":     "tests",
+						},
+					},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodFailed,
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name: names.ToDNS1123("tests"),
+								State: corev1.ContainerState{
+									Terminated: &corev1.ContainerStateTerminated{
+										ExitCode: 3,
+										Reason:   "Error",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			exp: cluster.SandboxStatus{
+				Phase: cluster.SandboxStatus_RUNNING,
+				Services: map[string]*cluster.ServiceStatus{
+					"tests": {
+						Phase:      cluster.ServicePhase_EXITED,
+						HasStarted: true,
+						ExitCode:   3,
+					},
+				},
+			},
+		},
+		{
+			name:      "DependsOnTimeout",
+			namespace: "namespace",
+			mockObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "namespace",
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "namespace",
+						Name:      "web",
+						Labels: map[string]string{
+							"blimp.customerPod": "true",
+							"blimp.service":     "web",
+						},
+						Annotations: map[string]string{
+							metadata.DependsOnKey: "db,cache",
+						},
+					},
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{
+							{
+								Name: kube.ContainerNameWaitDependsOn,
+								Env: []corev1.EnvVar{
+									{Name: compose.WaitTimeoutEnv, Value: "5m0s"},
+								},
+							},
+						},
+					},
+					Status: corev1.PodStatus{
+						InitContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name: kube.ContainerNameWaitDependsOn,
+								State: corev1.ContainerState{
+									Running: &corev1.ContainerStateRunning{
+										StartedAt: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+									},
+								},
+							},
+						},
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "namespace",
+						Name:      "db",
+						UID:       "db-uid",
+					},
+				},
+				&corev1.Event{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "namespace",
+						Name:      "db-unhealthy",
+					},
+					InvolvedObject: corev1.ObjectReference{
+						Kind:      "Pod",
+						Namespace: "namespace",
+						Name:      "db",
+						UID:       "db-uid",
+					},
+					Reason:  "Unhealthy",
+					Message: "Readiness probe failed: connection refused",
+				},
+			},
+			exp: cluster.SandboxStatus{
+				Phase: cluster.SandboxStatus_RUNNING,
+				Services: map[string]*cluster.ServiceStatus{
+					"web": {
+						Phase: cluster.ServicePhase_INIT_TIMEOUT,
+						Msg: "Gave up waiting after 5m0s. " +
+							"db never became ready (Readiness probe failed: connection refused). " +
+							"cache doesn't exist.",
+					},
+				},
+			},
+		},
+		{
+			name:      "RateLimited",
+			namespace: "namespace",
+			mockObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "namespace",
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "namespace",
+						Name:      "web",
+						Labels: map[string]string{
+							"blimp.customerPod": "true",
+							"blimp.service":     "web",
+						},
+						Annotations: map[string]string{
+							rateLimitRetryAtKey: "1600000000",
+						},
+					},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name: names.ToDNS1123("web"),
+								State: corev1.ContainerState{
+									Waiting: &corev1.ContainerStateWaiting{
+										Reason:  "ImagePullBackOff",
+										Message: "Back-off pulling image \"nginx\"",
+									},
+								},
+							},
+						},
+					},
+				},
+				&corev1.Event{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "namespace",
+						Name:      "web-failed",
+					},
+					InvolvedObject: corev1.ObjectReference{
+						Kind:      "Pod",
+						Namespace: "namespace",
+						Name:      "web",
+						FieldPath: fmt.Sprintf("spec.containers{%s}", names.ToDNS1123("web")),
+					},
+					Reason: "Failed",
+					Message: "Failed to pull image \"nginx\": toomanyrequests: " +
+						"You have reached your pull rate limit.",
+				},
+			},
+			exp: cluster.SandboxStatus{
+				Phase: cluster.SandboxStatus_RUNNING,
+				Services: map[string]*cluster.ServiceStatus{
+					"web": {
+						Phase:   cluster.ServicePhase_RATE_LIMITED,
+						Msg:     rateLimitedMsg,
+						RetryAt: 1600000000,
+					},
+				},
+			},
+		},
+		{
+			name:      "Preempted",
+			namespace: "namespace",
+			mockObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "namespace",
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "namespace",
+						Name:      "web",
+						Labels: map[string]string{
+							"blimp.customerPod": "true",
+							"blimp.service":     "web",
+						},
+					},
+					Status: corev1.PodStatus{
+						Phase:  corev1.PodFailed,
+						Reason: "Terminated",
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "namespace",
+						Name:      "db",
+						Labels: map[string]string{
+							"blimp.customerPod": "true",
+							"blimp.service":     "db",
+						},
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "deleted-node",
+					},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodRunning,
+					},
+				},
+			},
+			exp: cluster.SandboxStatus{
+				Phase: cluster.SandboxStatus_RUNNING,
+				Services: map[string]*cluster.ServiceStatus{
+					"web": {
+						Phase: cluster.ServicePhase_RESCHEDULING,
+						Msg:   reschedulingMsg,
+					},
+					"db": {
+						Phase: cluster.ServicePhase_RESCHEDULING,
+						Msg:   reschedulingMsg,
+					},
+				},
+			},
+		},
+		{
+			name:      "Paused",
+			namespace: "namespace",
+			mockObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "namespace",
+						Annotations: map[string]string{kube.PausedAnnotation: `{"pausedAt":1600000000}`},
+					},
+				},
+			},
+			exp: cluster.SandboxStatus{
+				Phase: cluster.SandboxStatus_PAUSED,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -153,7 +428,7 @@ func TestGetStatus(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			kubeClient := fakeKube.NewSimpleClientset(test.mockObjects...)
 
-			sf := newStatusFetcher(kubeClient)
+			sf := newStatusFetcher(kubeClient, defaultInformerConfig)
 
 			stop := make(chan struct{})
 			defer close(stop)
@@ -165,3 +440,217 @@ func TestGetStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterServices(t *testing.T) {
+	status := cluster.SandboxStatus{
+		Phase: cluster.SandboxStatus_RUNNING,
+		Services: map[string]*cluster.ServiceStatus{
+			"web": {Phase: cluster.ServicePhase_RUNNING},
+			"db":  {Phase: cluster.ServicePhase_WAIT_DEPENDS_ON},
+		},
+	}
+
+	assert.Equal(t, status, filterServices(status, nil))
+	assert.Equal(t, cluster.SandboxStatus{
+		Phase: cluster.SandboxStatus_RUNNING,
+		Services: map[string]*cluster.ServiceStatus{
+			"db": {Phase: cluster.ServicePhase_WAIT_DEPENDS_ON},
+		},
+	}, filterServices(status, []string{"db", "cache"}))
+}
+
+func TestRateLimitBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Minute, rateLimitBackoff(1))
+	assert.Equal(t, 4*time.Minute, rateLimitBackoff(2))
+	assert.Equal(t, 32*time.Minute, rateLimitBackoff(5))
+	assert.Equal(t, time.Hour, rateLimitBackoff(6))
+	assert.Equal(t, time.Hour, rateLimitBackoff(100))
+}
+
+// partiallyFailingNamespaceLister simulates an error reading a single
+// namespace from the informer's cache.
+type partiallyFailingNamespaceLister struct {
+	listers.NamespaceLister
+	failing string
+}
+
+func (l partiallyFailingNamespaceLister) Get(name string) (*corev1.Namespace, error) {
+	if name == l.failing {
+		return nil, errors.New("cache unavailable")
+	}
+	return l.NamespaceLister.Get(name)
+}
+
+func TestGetMany(t *testing.T) {
+	kubeClient := fakeKube.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "running"}},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "paused",
+				Annotations: map[string]string{kube.PausedAnnotation: `{"pausedAt":1600000000}`},
+			},
+		},
+	)
+	sf := newStatusFetcher(kubeClient, defaultInformerConfig)
+	stop := make(chan struct{})
+	defer close(stop)
+	sf.Start(stop)
+
+	statuses, err := sf.GetMany([]string{"running", "paused", "missing"})
+	require.NoError(t, err)
+	assert.Len(t, statuses, 3)
+	assert.Equal(t, cluster.SandboxStatus_RUNNING, statuses["running"].Phase)
+	assert.Equal(t, cluster.SandboxStatus_PAUSED, statuses["paused"].Phase)
+	assert.Equal(t, cluster.SandboxStatus_DOES_NOT_EXIST, statuses["missing"].Phase)
+
+	statuses, err = sf.GetMany(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, statuses)
+
+	// If any of the statuses can't be fetched, the error says which one, and
+	// no partial results are returned.
+	sf.namespaceLister = partiallyFailingNamespaceLister{
+		NamespaceLister: sf.namespaceLister,
+		failing:         "paused",
+	}
+	statuses, err = sf.GetMany([]string{"running", "paused"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "paused")
+	}
+	assert.Nil(t, statuses)
+}
+
+func TestGetStatuses(t *testing.T) {
+	alice, err := auth.ParseIDToken("alice")
+	require.NoError(t, err)
+	bob, err := auth.ParseIDToken("bob")
+	require.NoError(t, err)
+	carol, err := auth.ParseIDToken("carol")
+	require.NoError(t, err)
+
+	kubeClient := fakeKube.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: alice.Namespace}},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        bob.Namespace,
+				Annotations: map[string]string{kube.PausedAnnotation: `{"pausedAt":1600000000}`},
+			},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: carol.Namespace}},
+	)
+	sf := newStatusFetcher(kubeClient, defaultInformerConfig)
+	stop := make(chan struct{})
+	defer close(stop)
+	sf.Start(stop)
+
+	managerStore := memStore{}
+	s := &server{
+		kubeClient:    kubeClient,
+		statusFetcher: sf,
+		namespaces:    newNamespaceRegistry(managerStore),
+		store:         managerStore,
+	}
+	require.NoError(t, s.namespaces.claim(bob))
+
+	createToken := func(scope string) string {
+		resp, err := s.CreateToken(context.Background(), &cluster.CreateTokenRequest{
+			Auth:   &authProto.BlimpAuth{Token: bob.Name},
+			Scopes: []string{scope},
+		})
+		require.NoError(t, err)
+		return resp.Token
+	}
+	statusToken := createToken("status")
+	logsToken := createToken("logs")
+
+	tests := []struct {
+		name       string
+		namespaces []string
+		tokens     []string
+		exp        map[string]cluster.SandboxStatus_SandboxPhase
+		expErr     bool
+	}{
+		{
+			name:       "OwnNamespace",
+			namespaces: []string{alice.Namespace, alice.Namespace},
+			exp:        map[string]cluster.SandboxStatus_SandboxPhase{alice.Namespace: cluster.SandboxStatus_RUNNING},
+		},
+		{
+			name:       "SharedNamespace",
+			namespaces: []string{alice.Namespace, bob.Namespace},
+			tokens:     []string{statusToken},
+			exp: map[string]cluster.SandboxStatus_SandboxPhase{
+				alice.Namespace: cluster.SandboxStatus_RUNNING,
+				bob.Namespace:   cluster.SandboxStatus_PAUSED,
+			},
+		},
+		{
+			name:       "NoToken",
+			namespaces: []string{alice.Namespace, bob.Namespace},
+			expErr:     true,
+		},
+		{
+			name:       "WrongScope",
+			namespaces: []string{bob.Namespace},
+			tokens:     []string{logsToken},
+			expErr:     true,
+		},
+		{
+			// A token for one namespace doesn't grant access to others.
+			name:       "MixedAuthorization",
+			namespaces: []string{alice.Namespace, bob.Namespace, carol.Namespace},
+			tokens:     []string{statusToken},
+			expErr:     true,
+		},
+		{
+			name:       "InvalidToken",
+			namespaces: []string{alice.Namespace},
+			tokens:     []string{auth.ScopedToken(bob.Namespace, "unknown", "secret")},
+			expErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := s.GetStatuses(context.Background(), &cluster.GetStatusesRequest{
+				Auth:       &authProto.BlimpAuth{Token: alice.Name},
+				Namespaces: test.namespaces,
+				Tokens:     test.tokens,
+			})
+			if test.expErr {
+				codedErr, ok := errors.GetCode(err)
+				require.True(t, ok)
+				assert.Equal(t, errors.CodeUnauthorized, codedErr.Code())
+				assert.Empty(t, resp.GetStatuses())
+				return
+			}
+
+			require.NoError(t, err)
+			phases := map[string]cluster.SandboxStatus_SandboxPhase{}
+			for namespace, status := range resp.GetStatuses() {
+				phases[namespace] = status.Phase
+			}
+			assert.Equal(t, test.exp, phases)
+		})
+	}
+
+	// Tokens stop working once they're revoked.
+	require.NoError(t, s.deleteScopedTokens(bob.Namespace))
+	_, err = s.GetStatuses(context.Background(), &cluster.GetStatusesRequest{
+		Auth:       &authProto.BlimpAuth{Token: alice.Name},
+		Namespaces: []string{bob.Namespace},
+		Tokens:     []string{statusToken},
+	})
+	assert.Error(t, err)
+
+	// Errors reading one of the sandboxes fail the whole request.
+	sf.namespaceLister = partiallyFailingNamespaceLister{
+		NamespaceLister: sf.namespaceLister,
+		failing:         alice.Namespace,
+	}
+	_, err = s.GetStatuses(context.Background(), &cluster.GetStatusesRequest{
+		Auth:       &authProto.BlimpAuth{Token: alice.Name},
+		Namespaces: []string{alice.Namespace},
+	})
+	assert.Error(t, err)
+}
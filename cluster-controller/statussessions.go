@@ -0,0 +1,228 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// maxBufferedUpdates is how many updates are buffered for each watch
+	// session. Clients that miss more updates than this get the full status
+	// instead.
+	maxBufferedUpdates = 32
+
+	// statusSessionTTL is how long a watch session can be resumed after its
+	// last update was sent.
+	statusSessionTTL = 5 * time.Minute
+)
+
+// statusSessions buffers the updates sent by WatchStatus so that clients
+// whose connection breaks can resume their watch without missing updates.
+// Sessions are stored in memory, so resuming a watch on a different manager
+// replica falls back to sending the full status.
+type statusSessions struct {
+	sync.Mutex
+	sessions map[string]*statusSession
+}
+
+type statusSession struct {
+	namespace string
+	services  []string
+
+	// nextSeq is the sequence number of the next update.
+	nextSeq int
+	updates []bufferedStatus
+	lastUse time.Time
+
+	// attached is set while a WatchStatus stream is using the session.
+	attached bool
+}
+
+type bufferedStatus struct {
+	seq    int
+	time   time.Time
+	status cluster.SandboxStatus
+}
+
+func newStatusSessions() *statusSessions {
+	return &statusSessions{sessions: map[string]*statusSession{}}
+}
+
+// resume looks up the session for the resume token, and returns the updates
+// that were sent after the token. If the session can't be resumed, a new
+// session is created, and `ok` is false.
+func (ss *statusSessions) resume(token, namespace string, services []string) (
+	id string, missed []bufferedStatus, ok bool, err error) {
+
+	ss.Lock()
+	defer ss.Unlock()
+	ss.expire()
+
+	if token != "" {
+		id, missed, ok = ss.lookup(token, namespace, services)
+		if ok {
+			return id, missed, true, nil
+		}
+	}
+
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", nil, false, errors.WithContext("generate session id", err)
+	}
+	id = fmt.Sprintf("%x", idBytes)
+	ss.sessions[id] = &statusSession{
+		namespace: namespace,
+		services:  services,
+		lastUse:   time.Now(),
+		attached:  true,
+	}
+	return id, nil, false, nil
+}
+
+func (ss *statusSessions) lookup(token, namespace string, services []string) (
+	string, []bufferedStatus, bool) {
+
+	id, seq, err := parseResumeToken(token)
+	if err != nil {
+		return "", nil, false
+	}
+
+	session, ok := ss.sessions[id]
+	if !ok || session.attached || session.namespace != namespace ||
+		!stringSlicesEqual(session.services, services) {
+		return "", nil, false
+	}
+
+	// If the client missed updates that are no longer buffered, it needs
+	// the full status.
+	if seq >= session.nextSeq ||
+		(len(session.updates) > 0 && seq < session.updates[0].seq-1) {
+		return "", nil, false
+	}
+
+	var missed []bufferedStatus
+	for _, update := range session.updates {
+		if update.seq > seq {
+			missed = append(missed, update)
+		}
+	}
+	session.attached = true
+	session.lastUse = time.Now()
+	return id, missed, true
+}
+
+// latest returns the last update buffered for the session.
+func (ss *statusSessions) latest(id string) (cluster.SandboxStatus, bool) {
+	ss.Lock()
+	defer ss.Unlock()
+
+	session, ok := ss.sessions[id]
+	if !ok || len(session.updates) == 0 {
+		return cluster.SandboxStatus{}, false
+	}
+	return session.updates[len(session.updates)-1].status, true
+}
+
+// add buffers the update, and returns its resume token.
+func (ss *statusSessions) add(id string, status cluster.SandboxStatus) string {
+	ss.Lock()
+	defer ss.Unlock()
+
+	session, ok := ss.sessions[id]
+	if !ok {
+		return ""
+	}
+
+	seq := session.nextSeq
+	session.nextSeq++
+	session.lastUse = time.Now()
+	session.updates = append(session.updates, bufferedStatus{
+		seq:    seq,
+		time:   session.lastUse,
+		status: status,
+	})
+	if len(session.updates) > maxBufferedUpdates {
+		session.updates = session.updates[len(session.updates)-maxBufferedUpdates:]
+	}
+	return makeResumeToken(id, seq)
+}
+
+// recent returns the most recent updates sent by any of the namespace's
+// sessions, oldest first.
+func (ss *statusSessions) recent(namespace string, max int) []bufferedStatus {
+	ss.Lock()
+	defer ss.Unlock()
+
+	var updates []bufferedStatus
+	for _, session := range ss.sessions {
+		if session.namespace == namespace {
+			updates = append(updates, session.updates...)
+		}
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].time.Before(updates[j].time)
+	})
+	if len(updates) > max {
+		updates = updates[len(updates)-max:]
+	}
+	return updates
+}
+
+// detach marks the session as resumable by another stream.
+func (ss *statusSessions) detach(id string) {
+	ss.Lock()
+	defer ss.Unlock()
+
+	if session, ok := ss.sessions[id]; ok {
+		session.attached = false
+		session.lastUse = time.Now()
+	}
+}
+
+// expire deletes sessions that haven't been used within statusSessionTTL.
+// The caller must hold the lock.
+func (ss *statusSessions) expire() {
+	for id, session := range ss.sessions {
+		if !session.attached && time.Since(session.lastUse) > statusSessionTTL {
+			delete(ss.sessions, id)
+		}
+	}
+}
+
+func makeResumeToken(id string, seq int) string {
+	return id + "." + strconv.Itoa(seq)
+}
+
+func parseResumeToken(token string) (string, int, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return "", 0, errors.New("malformed resume token")
+	}
+
+	seq, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, errors.WithContext("parse sequence number", err)
+	}
+	return parts[0], seq, nil
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func TestStatusSessions(t *testing.T) {
+	ss := newStatusSessions()
+	statusWithPhase := func(phase cluster.SandboxStatus_SandboxPhase) cluster.SandboxStatus {
+		return cluster.SandboxStatus{Phase: phase}
+	}
+
+	id, missed, resumed, err := ss.resume("", "namespace", nil)
+	require.NoError(t, err)
+	assert.False(t, resumed)
+	assert.Empty(t, missed)
+
+	token := ss.add(id, statusWithPhase(cluster.SandboxStatus_RUNNING))
+	ss.add(id, statusWithPhase(cluster.SandboxStatus_TERMINATING))
+	ss.add(id, statusWithPhase(cluster.SandboxStatus_DOES_NOT_EXIST))
+
+	// Sessions can't be resumed while they're attached to a stream.
+	_, _, resumed, err = ss.resume(token, "namespace", nil)
+	require.NoError(t, err)
+	assert.False(t, resumed)
+
+	// Resuming replays the updates sent after the token.
+	ss.detach(id)
+	resumedID, missed, resumed, err := ss.resume(token, "namespace", nil)
+	require.NoError(t, err)
+	assert.True(t, resumed)
+	assert.Equal(t, id, resumedID)
+	require.Len(t, missed, 2)
+	assert.Equal(t, cluster.SandboxStatus_TERMINATING, missed[0].status.Phase)
+	assert.Equal(t, cluster.SandboxStatus_DOES_NOT_EXIST, missed[1].status.Phase)
+
+	latest, ok := ss.latest(id)
+	assert.True(t, ok)
+	assert.Equal(t, cluster.SandboxStatus_DOES_NOT_EXIST, latest.Phase)
+
+	// Sessions can only be resumed by the same namespace, with the same
+	// services.
+	ss.detach(id)
+	_, _, resumed, err = ss.resume(token, "other-namespace", nil)
+	require.NoError(t, err)
+	assert.False(t, resumed)
+
+	_, _, resumed, err = ss.resume(token, "namespace", []string{"web"})
+	require.NoError(t, err)
+	assert.False(t, resumed)
+
+	// Clients that missed more updates than are buffered get a new session.
+	for i := 0; i < maxBufferedUpdates; i++ {
+		ss.add(id, statusWithPhase(cluster.SandboxStatus_RUNNING))
+	}
+	_, _, resumed, err = ss.resume(token, "namespace", nil)
+	require.NoError(t, err)
+	assert.False(t, resumed)
+
+	_, _, resumed, err = ss.resume("malformed", "namespace", nil)
+	require.NoError(t, err)
+	assert.False(t, resumed)
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/names"
+)
+
+const (
+	// kindLabel is used to select the records of a given kind.
+	kindLabel = "blimp.record-kind"
+
+	// keyAnnotation records the record's key, since the object's name is
+	// sanitized.
+	keyAnnotation = "blimp.record-key"
+)
+
+var recordResource = kube.CustomResource{
+	Group:    "blimp.kelda.io",
+	Version:  "v1",
+	Kind:     "ManagerRecord",
+	Singular: "managerrecord",
+	Plural:   "managerrecords",
+}
+
+type crdStore struct {
+	client dynamic.NamespaceableResourceInterface
+}
+
+// NewCRD returns a store that keeps records as ManagerRecord custom resources
+// in the Blimp system namespace. The CustomResourceDefinition is created if
+// it doesn't exist.
+func NewCRD(restConfig *rest.Config) (Store, error) {
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.WithContext("create client", err)
+	}
+
+	if err := kube.CreateCustomResourceDefinition(dynamicClient, recordResource); err != nil {
+		return nil, errors.WithContext("create custom resource definition", err)
+	}
+	return crdStore{dynamicClient.Resource(recordResource.GroupVersionResource())}, nil
+}
+
+func (s crdStore) Get(kind, key string) ([]byte, error) {
+	record, err := s.client.Namespace(kube.BlimpNamespace).Get(objectName(kind, key), metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.WithContext("get", err)
+	}
+	return getValue(record)
+}
+
+func (s crdStore) List(kind string) (map[string][]byte, error) {
+	records, err := s.client.Namespace(kube.BlimpNamespace).List(metav1.ListOptions{
+		LabelSelector: kindLabel + "=" + names.ToDNS1123(kind),
+	})
+	if err != nil {
+		return nil, errors.WithContext("list", err)
+	}
+
+	values := map[string][]byte{}
+	for _, record := range records.Items {
+		value, err := getValue(&record)
+		if err != nil {
+			return nil, err
+		}
+		values[record.GetAnnotations()[keyAnnotation]] = value
+	}
+	return values, nil
+}
+
+func (s crdStore) Update(kind, key string, fn func([]byte) ([]byte, error)) error {
+	client := s.client.Namespace(kube.BlimpNamespace)
+	name := objectName(kind, key)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		record, err := client.Get(name, metav1.GetOptions{})
+		var value []byte
+		switch {
+		case kerrors.IsNotFound(err):
+			record = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": recordResource.APIVersion(),
+				"kind":       recordResource.Kind,
+			}}
+			record.SetName(name)
+			record.SetNamespace(kube.BlimpNamespace)
+			record.SetLabels(map[string]string{kindLabel: names.ToDNS1123(kind)})
+			record.SetAnnotations(map[string]string{keyAnnotation: key})
+		case err != nil:
+			return errors.WithContext("get", err)
+		default:
+			value, err = getValue(record)
+			if err != nil {
+				return err
+			}
+		}
+
+		newValue, err := fn(value)
+		if err != nil {
+			return err
+		}
+
+		if err := unstructured.SetNestedField(record.Object, string(newValue), "spec", "value"); err != nil {
+			return errors.WithContext("set value", err)
+		}
+
+		// Return the error directly so that RetryOnConflict can detect
+		// conflicts.
+		if record.GetResourceVersion() == "" {
+			_, err = client.Create(record, metav1.CreateOptions{})
+		} else {
+			_, err = client.Update(record, metav1.UpdateOptions{})
+		}
+		return err
+	})
+}
+
+func (s crdStore) Delete(kind, key string) error {
+	err := s.client.Namespace(kube.BlimpNamespace).Delete(objectName(kind, key), nil)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.WithContext("delete", err)
+	}
+	return nil
+}
+
+func (s crdStore) Close() error {
+	return nil
+}
+
+func getValue(record *unstructured.Unstructured) ([]byte, error) {
+	value, _, err := unstructured.NestedString(record.Object, "spec", "value")
+	if err != nil {
+		return nil, errors.WithContext("parse value", err)
+	}
+	return []byte(value), nil
+}
+
+func objectName(kind, key string) string {
+	return names.ToDNS1123(kind + "-" + key)
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	// Register the database drivers.
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// The records are stored in a single table so that operators can query them
+// directly. For example, the usage of all sandboxes can be listed with
+// `SELECT key, value FROM manager_records WHERE kind = 'usage'`.
+const createTableQuery = `CREATE TABLE IF NOT EXISTS manager_records (
+	kind TEXT NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (kind, key)
+)`
+
+type sqlStore struct {
+	db *sql.DB
+
+	// lockQuery is run at the beginning of Update's transaction to prevent
+	// other transactions from modifying the record. It's needed because
+	// `SELECT ... FOR UPDATE` doesn't lock rows that don't exist yet.
+	lockQuery string
+
+	// rebind converts the `?` placeholders in the query to the format
+	// expected by the driver.
+	rebind func(query string) string
+}
+
+// NewPostgres returns a store backed by the Postgres database at the given
+// URL.
+func NewPostgres(url string) (Store, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, errors.WithContext("open database", err)
+	}
+
+	return newSQLStore(db, "SELECT pg_advisory_xact_lock(hashtext(?::text || '/' || ?::text))", rebindPostgres)
+}
+
+// NewSQLite returns a store backed by the SQLite database at the given path.
+// The database is created if it doesn't exist.
+func NewSQLite(path string) (Store, error) {
+	// Take the write lock when transactions begin rather than when they
+	// first write. Otherwise, concurrent updates to the same record would
+	// deadlock when upgrading their read locks.
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_txlock=immediate&_busy_timeout=5000", path))
+	if err != nil {
+		return nil, errors.WithContext("open database", err)
+	}
+
+	// SQLite only supports a single writer.
+	db.SetMaxOpenConns(1)
+	return newSQLStore(db, "", func(query string) string { return query })
+}
+
+func newSQLStore(db *sql.DB, lockQuery string, rebind func(string) string) (Store, error) {
+	if _, err := db.Exec(createTableQuery); err != nil {
+		db.Close()
+		return nil, errors.WithContext("create table", err)
+	}
+	return sqlStore{db: db, lockQuery: lockQuery, rebind: rebind}, nil
+}
+
+func (s sqlStore) Get(kind, key string) ([]byte, error) {
+	var value string
+	err := s.db.QueryRow(s.rebind("SELECT value FROM manager_records WHERE kind = ? AND key = ?"),
+		kind, key).Scan(&value)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrNotFound
+	case err != nil:
+		return nil, errors.WithContext("query", err)
+	}
+	return []byte(value), nil
+}
+
+func (s sqlStore) List(kind string) (map[string][]byte, error) {
+	rows, err := s.db.Query(s.rebind("SELECT key, value FROM manager_records WHERE kind = ?"), kind)
+	if err != nil {
+		return nil, errors.WithContext("query", err)
+	}
+	defer rows.Close()
+
+	records := map[string][]byte{}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, errors.WithContext("scan", err)
+		}
+		records[key] = []byte(value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.WithContext("query", err)
+	}
+	return records, nil
+}
+
+func (s sqlStore) Update(kind, key string, fn func([]byte) ([]byte, error)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return errors.WithContext("begin transaction", err)
+	}
+	// Rollback is a no-op if the transaction was committed.
+	defer tx.Rollback() //nolint:errcheck
+
+	if s.lockQuery != "" {
+		if _, err := tx.Exec(s.rebind(s.lockQuery), kind, key); err != nil {
+			return errors.WithContext("lock", err)
+		}
+	}
+
+	var value []byte
+	var currValue string
+	err = tx.QueryRow(s.rebind("SELECT value FROM manager_records WHERE kind = ? AND key = ?"),
+		kind, key).Scan(&currValue)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return errors.WithContext("query", err)
+	default:
+		value = []byte(currValue)
+	}
+
+	newValue, err := fn(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(s.rebind(`INSERT INTO manager_records (kind, key, value, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (kind, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`),
+		kind, key, string(newValue), time.Now().UTC())
+	if err != nil {
+		return errors.WithContext("write", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.WithContext("commit", err)
+	}
+	return nil
+}
+
+func (s sqlStore) Delete(kind, key string) error {
+	_, err := s.db.Exec(s.rebind("DELETE FROM manager_records WHERE kind = ? AND key = ?"), kind, key)
+	if err != nil {
+		return errors.WithContext("delete", err)
+	}
+	return nil
+}
+
+func (s sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// rebindPostgres converts `?` placeholders to Postgres's numbered
+// placeholders. None of the queries contain literal question marks.
+func rebindPostgres(query string) string {
+	var rebound strings.Builder
+	var n int
+	for _, c := range query {
+		if c != '?' {
+			rebound.WriteRune(c)
+			continue
+		}
+		n++
+		fmt.Fprintf(&rebound, "$%d", n)
+	}
+	return rebound.String()
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blimp-store")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s, err := New("sqlite://"+filepath.Join(dir, "manager.db"), nil)
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.Get("usage", "namespace")
+	assert.Equal(t, ErrNotFound, err)
+
+	// Concurrent updates to the same record shouldn't be lost.
+	increment := func(value []byte) ([]byte, error) {
+		n, _ := strconv.Atoi(string(value))
+		return []byte(strconv.Itoa(n + 1)), nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.Update("usage", "namespace", increment))
+		}()
+	}
+	wg.Wait()
+
+	value, err := s.Get("usage", "namespace")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", string(value))
+
+	require.NoError(t, s.Update("usage", "other-namespace", increment))
+	require.NoError(t, s.Update("other-kind", "namespace", increment))
+	records, err := s.List("usage")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"namespace":       []byte("10"),
+		"other-namespace": []byte("1"),
+	}, records)
+
+	require.NoError(t, s.Delete("usage", "namespace"))
+	require.NoError(t, s.Delete("usage", "namespace"))
+	_, err = s.Get("usage", "namespace")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestRebindPostgres(t *testing.T) {
+	assert.Equal(t, "SELECT value FROM manager_records WHERE kind = $1 AND key = $2",
+		rebindPostgres("SELECT value FROM manager_records WHERE kind = ? AND key = ?"))
+}
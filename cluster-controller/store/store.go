@@ -0,0 +1,79 @@
+// Package store persists the cluster manager's bookkeeping, such as the
+// resources consumed by each sandbox.
+//
+// Records are JSON documents identified by a kind and a key. By default,
+// records are stored as custom resources in the Blimp system namespace, so
+// they live alongside the sandboxes. Larger installations can instead store
+// them in Postgres or SQLite, so that the manager's state can be queried and
+// backed up independently of the cluster.
+package store
+
+import (
+	"net/url"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// ErrNotFound is returned by Get when the record doesn't exist.
+var ErrNotFound = errors.New("record not found")
+
+// Store is a key-value store for the manager's records. Implementations must
+// be safe for use by multiple goroutines, and by multiple manager replicas.
+type Store interface {
+	// Get returns the value of the record. It returns ErrNotFound if the
+	// record doesn't exist.
+	Get(kind, key string) ([]byte, error)
+
+	// List returns the values of all the records of the given kind, keyed by
+	// the records' keys.
+	List(kind string) (map[string][]byte, error)
+
+	// Update atomically replaces the value of the record with the result of
+	// `fn`. The current value is nil if the record doesn't exist. `fn` may be
+	// called multiple times if the record is modified concurrently.
+	Update(kind, key string, fn func(value []byte) ([]byte, error)) error
+
+	// Delete removes the record. Deleting a record that doesn't exist is not
+	// an error.
+	Delete(kind, key string) error
+
+	Close() error
+}
+
+// New returns the store described by the given URL. The URL's scheme selects
+// the backend:
+//   - crd:// stores records as custom resources in the cluster.
+//   - postgres:// or postgresql:// stores records in a Postgres database. The
+//     URL is passed directly to the Postgres driver.
+//   - sqlite:///path/to/db stores records in a SQLite database at the given
+//     path.
+//
+// An empty URL selects the crd backend.
+func New(storeURL string, restConfig *rest.Config) (Store, error) {
+	if storeURL == "" {
+		return NewCRD(restConfig)
+	}
+
+	parsed, err := url.Parse(storeURL)
+	if err != nil {
+		return nil, errors.WithContext("parse store url", err)
+	}
+
+	switch parsed.Scheme {
+	case "crd":
+		return NewCRD(restConfig)
+	case "postgres", "postgresql":
+		return NewPostgres(storeURL)
+	case "sqlite":
+		path := parsed.Path
+		if parsed.Host != "" {
+			// Support relative paths, such as sqlite://manager.db.
+			path = parsed.Host + path
+		}
+		return NewSQLite(path)
+	default:
+		return nil, errors.New("unsupported store %q", parsed.Scheme)
+	}
+}
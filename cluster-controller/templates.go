@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// templates is the catalog of starter projects that `blimp init` scaffolds.
+// Increment a template's version whenever its files change.
+var templates = []*cluster.Template{
+	{
+		Name:        "django",
+		Description: "Django with PostgreSQL",
+		Version:     1,
+		Files: map[string]string{
+			"docker-compose.yml": djangoCompose,
+			"Dockerfile":         djangoDockerfile,
+		},
+	},
+	{
+		Name:        "node-redis",
+		Description: "Node.js with Redis",
+		Version:     1,
+		Files: map[string]string{
+			"docker-compose.yml": nodeRedisCompose,
+			"Dockerfile":         nodeRedisDockerfile,
+		},
+	},
+	{
+		Name:        "rails-postgres",
+		Description: "Ruby on Rails with PostgreSQL",
+		Version:     1,
+		Files: map[string]string{
+			"docker-compose.yml": railsPostgresCompose,
+			"Dockerfile":         railsPostgresDockerfile,
+		},
+	},
+}
+
+func (s *server) ListTemplates(ctx context.Context, req *cluster.ListTemplatesRequest) (
+	*cluster.ListTemplatesResponse, error) {
+	if _, err := auth.AuthorizeRequest(req.GetAuth()); err != nil {
+		return &cluster.ListTemplatesResponse{}, err
+	}
+
+	// Don't send the files since they're only needed when scaffolding.
+	var summaries []*cluster.Template
+	for _, tmpl := range templates {
+		summaries = append(summaries, &cluster.Template{
+			Name:        tmpl.Name,
+			Description: tmpl.Description,
+			Version:     tmpl.Version,
+		})
+	}
+	return &cluster.ListTemplatesResponse{Templates: summaries}, nil
+}
+
+func (s *server) GetTemplate(ctx context.Context, req *cluster.GetTemplateRequest) (
+	*cluster.GetTemplateResponse, error) {
+	if _, err := auth.AuthorizeRequest(req.GetAuth()); err != nil {
+		return &cluster.GetTemplateResponse{}, err
+	}
+
+	tmpl, ok := getTemplate(req.GetName())
+	if !ok {
+		var names []string
+		for _, tmpl := range templates {
+			names = append(names, tmpl.Name)
+		}
+		sort.Strings(names)
+		return &cluster.GetTemplateResponse{}, errors.NewFriendlyError(
+			"Unknown template %q. The available templates are: %s",
+			req.GetName(), strings.Join(names, ", "))
+	}
+	return &cluster.GetTemplateResponse{Template: tmpl}, nil
+}
+
+func getTemplate(name string) (*cluster.Template, bool) {
+	for _, tmpl := range templates {
+		if tmpl.Name == name {
+			return tmpl, true
+		}
+	}
+	return nil, false
+}
+
+const djangoCompose = `version: "3.8"
+
+services:
+  web:
+    build: .
+    command: python manage.py runserver 0.0.0.0:8000
+    ports:
+      - "8000:8000"
+    volumes:
+      - .:/app
+    environment:
+      DATABASE_URL: postgres://postgres:postgres@db:5432/postgres
+    depends_on:
+      - db
+    x-blimp:
+      # The database takes a while to initialize the first time it boots.
+      init_timeouts:
+        depends_on: 5m
+
+  db:
+    image: postgres:12
+    environment:
+      POSTGRES_PASSWORD: postgres
+    volumes:
+      - db-data:/var/lib/postgresql/data
+
+volumes:
+  db-data:
+`
+
+const djangoDockerfile = `FROM python:3.8
+
+ENV PYTHONUNBUFFERED=1
+WORKDIR /app
+
+COPY requirements.txt ./
+RUN pip install -r requirements.txt
+
+COPY . .
+EXPOSE 8000
+`
+
+const nodeRedisCompose = `version: "3.8"
+
+services:
+  web:
+    build: .
+    command: npm start
+    ports:
+      - "3000:3000"
+    volumes:
+      - .:/app
+      # Keep the dependencies installed in the image rather than syncing the
+      # local node_modules directory.
+      - node_modules:/app/node_modules
+    environment:
+      REDIS_URL: redis://redis:6379
+    depends_on:
+      - redis
+    x-blimp:
+      # The initial sync of the source code can be slow for large projects.
+      init_timeouts:
+        sync: 10m
+
+  redis:
+    image: redis:6
+
+volumes:
+  node_modules:
+`
+
+const nodeRedisDockerfile = `FROM node:14
+
+WORKDIR /app
+
+COPY package*.json ./
+RUN npm install
+
+COPY . .
+EXPOSE 3000
+`
+
+const railsPostgresCompose = `version: "3.8"
+
+services:
+  web:
+    build: .
+    command: bash -c "rm -f tmp/pids/server.pid && bundle exec rails server -b 0.0.0.0"
+    ports:
+      - "3000:3000"
+    volumes:
+      - .:/app
+    environment:
+      DATABASE_URL: postgres://postgres:postgres@db:5432/app_development
+    depends_on:
+      - db
+    x-blimp:
+      init_timeouts:
+        depends_on: 5m
+
+  db:
+    image: postgres:12
+    environment:
+      POSTGRES_PASSWORD: postgres
+    volumes:
+      - db-data:/var/lib/postgresql/data
+
+volumes:
+  db-data:
+`
+
+const railsPostgresDockerfile = `FROM ruby:2.7
+
+RUN apt-get update && apt-get install -y nodejs postgresql-client
+WORKDIR /app
+
+COPY Gemfile Gemfile.lock ./
+RUN bundle install
+
+COPY . .
+EXPOSE 3000
+`
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelda/blimp/pkg/compose"
+)
+
+func TestTemplatesAreValid(t *testing.T) {
+	names := map[string]struct{}{}
+	for _, tmpl := range templates {
+		_, duplicate := names[tmpl.Name]
+		assert.False(t, duplicate, "duplicate template %s", tmpl.Name)
+		names[tmpl.Name] = struct{}{}
+
+		composeFile, ok := tmpl.Files["docker-compose.yml"]
+		if !assert.True(t, ok, "%s is missing docker-compose.yml", tmpl.Name) {
+			continue
+		}
+
+		parsed, err := compose.Parse([]byte(composeFile))
+		if !assert.NoError(t, err, tmpl.Name) {
+			continue
+		}
+		assert.Empty(t, compose.Validate(parsed), tmpl.Name)
+
+		for _, svc := range parsed.Services {
+			_, err := compose.GetServiceExtension(svc)
+			assert.NoError(t, err, "%s: %s", tmpl.Name, svc.Name)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kelda/blimp/cluster-controller/store"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const getStatusMethod = "/blimp.cluster.v0.Manager/GetStatus"
+
+// tokenScopes maps the scopes that can be granted to scoped tokens to the
+// RPCs that they allow.
+var tokenScopes = map[string][]string{
+	"status": {
+		getStatusMethod,
+		"/blimp.cluster.v0.Manager/GetStatuses",
+		"/blimp.cluster.v0.Manager/WatchStatus",
+		"/blimp.cluster.v0.Manager/PollStatus",
+		"/blimp.cluster.v0.Manager/WatchEvents",
+	},
+	"logs": {
+		"/blimp.cluster.v0.Manager/GetLogs",
+		"/blimp.cluster.v0.Manager/StreamLogs",
+	},
+}
+
+// scopedTokenKind is the kind of the store records that hold each sandbox's
+// scoped tokens. The records are keyed by namespace.
+const scopedTokenKind = "scoped-tokens"
+
+// scopedToken is a token created with `blimp token create`. Only a hash of
+// the token's secret is stored.
+type scopedToken struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name,omitempty"`
+	Scopes    []string `json:"scopes"`
+	Hash      string   `json:"hash"`
+	CreatedAt int64    `json:"createdAt"`
+}
+
+func (t scopedToken) allows(method string) bool {
+	for _, scope := range t.Scopes {
+		if contains(tokenScopes[scope], method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t scopedToken) toProtobuf() *cluster.ScopedToken {
+	return &cluster.ScopedToken{
+		Id:        t.ID,
+		Name:      t.Name,
+		Scopes:    t.Scopes,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func (s *server) CreateToken(ctx context.Context, req *cluster.CreateTokenRequest) (
+	*cluster.CreateTokenResponse, error) {
+	log.Info("Start CreateToken")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.CreateTokenResponse{}, err
+	}
+
+	if len(req.GetScopes()) == 0 {
+		return &cluster.CreateTokenResponse{}, errors.NewFriendlyError(
+			"At least one scope is required. The valid scopes are: %s.", validScopes())
+	}
+
+	for _, scope := range req.GetScopes() {
+		if _, ok := tokenScopes[scope]; !ok {
+			return &cluster.CreateTokenResponse{}, errors.NewFriendlyError(
+				"Unknown scope %q. The valid scopes are: %s.", scope, validScopes())
+		}
+	}
+
+	// Tokens can only be created once the user has claimed their namespace,
+	// so that they're revoked if the claim is released.
+	records, err := s.namespaces.lookup(user.Namespace)
+	if err != nil {
+		return &cluster.CreateTokenResponse{}, err
+	}
+
+	record, ok := records[user.Namespace]
+	switch {
+	case !ok:
+		return &cluster.CreateTokenResponse{}, errors.NewFriendlyError(
+			"Your sandbox doesn't exist yet. Run `blimp up` before creating tokens.")
+	case record.Owner != user.Name:
+		return &cluster.CreateTokenResponse{}, kube.NamespaceCollisionError(user.Namespace)
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return &cluster.CreateTokenResponse{}, errors.WithContext("generate ID", err)
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return &cluster.CreateTokenResponse{}, errors.WithContext("generate secret", err)
+	}
+
+	token := scopedToken{
+		ID:        id,
+		Name:      req.GetName(),
+		Scopes:    req.GetScopes(),
+		Hash:      hashTokenSecret(secret),
+		CreatedAt: time.Now().Unix(),
+	}
+	err = s.updateScopedTokens(user.Namespace, func(tokens []scopedToken) ([]scopedToken, error) {
+		return append(tokens, token), nil
+	})
+	if err != nil {
+		return &cluster.CreateTokenResponse{}, err
+	}
+
+	return &cluster.CreateTokenResponse{
+		Token: auth.ScopedToken(user.Namespace, id, secret),
+		Info:  token.toProtobuf(),
+	}, nil
+}
+
+func (s *server) ListTokens(ctx context.Context, req *cluster.ListTokensRequest) (
+	*cluster.ListTokensResponse, error) {
+	log.Info("Start ListTokens")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.ListTokensResponse{}, err
+	}
+
+	tokens, err := s.getScopedTokens(user.Namespace)
+	if err != nil {
+		return &cluster.ListTokensResponse{}, err
+	}
+
+	var tokensPB []*cluster.ScopedToken
+	for _, token := range tokens {
+		tokensPB = append(tokensPB, token.toProtobuf())
+	}
+	return &cluster.ListTokensResponse{Tokens: tokensPB}, nil
+}
+
+func (s *server) RevokeToken(ctx context.Context, req *cluster.RevokeTokenRequest) (
+	*cluster.RevokeTokenResponse, error) {
+	log.Info("Start RevokeToken")
+
+	user, err := auth.AuthorizeRequest(req.GetAuth())
+	if err != nil {
+		return &cluster.RevokeTokenResponse{}, err
+	}
+
+	err = s.updateScopedTokens(user.Namespace, func(tokens []scopedToken) ([]scopedToken, error) {
+		var updated []scopedToken
+		for _, token := range tokens {
+			if token.ID != req.GetId() {
+				updated = append(updated, token)
+			}
+		}
+
+		if len(updated) == len(tokens) {
+			return nil, errors.NewFriendlyError(
+				"Token %s doesn't exist. Run `blimp token list` to see your tokens.", req.GetId())
+		}
+		return updated, nil
+	})
+	if err != nil {
+		return &cluster.RevokeTokenResponse{}, err
+	}
+	return &cluster.RevokeTokenResponse{}, nil
+}
+
+// scopedTokenUnaryInterceptor verifies requests made with scoped tokens and
+// share tokens. If the token allows the RPC, it's replaced with the sandbox
+// owner's token so that the RPC's handler doesn't need to know about them.
+func (s *server) scopedTokenUnaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.checkScopedToken(req, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *server) scopedTokenStreamInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, scopedTokenStream{ServerStream: ss, s: s, method: info.FullMethod})
+}
+
+// scopedTokenStream checks the requests received by streaming RPCs.
+type scopedTokenStream struct {
+	grpc.ServerStream
+	s      *server
+	method string
+}
+
+func (ss scopedTokenStream) RecvMsg(m interface{}) error {
+	if err := ss.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return ss.s.checkScopedToken(m, ss.method)
+}
+
+func (s *server) checkScopedToken(req interface{}, method string) error {
+	// Scoped tokens are only supported in the `auth` field, since the
+	// legacy token field can't be rewritten.
+	authReq, ok := req.(auth.AuthenticatedRequest)
+	if !ok || authReq.GetAuth() == nil {
+		return nil
+	}
+
+	blimpAuth := authReq.GetAuth()
+	var owner string
+	var err error
+	switch token := blimpAuth.GetToken(); {
+	case auth.IsScopedToken(token):
+		owner, err = s.verifyScopedToken(token, method)
+	case auth.IsShareToken(token):
+		owner, err = s.verifyShareToken(token, method)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	blimpAuth.Token = owner
+	return nil
+}
+
+// verifyScopedToken checks that the token is valid, and that it allows the
+// RPC. It returns the token of the sandbox's owner.
+func (s *server) verifyScopedToken(token, method string) (string, error) {
+	invalidTokenErr := errors.NewCodedError(errors.CodeUnauthorized, "Invalid token.")
+	namespace, id, secret, ok := auth.ParseScopedToken(token)
+	if !ok {
+		return "", invalidTokenErr
+	}
+
+	tokens, err := s.getScopedTokens(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	for _, t := range tokens {
+		if t.ID != id {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(t.Hash), []byte(hashTokenSecret(secret))) != 1 {
+			return "", invalidTokenErr
+		}
+
+		if !t.allows(method) {
+			return "", errors.NewCodedError(errors.CodeUnauthorized,
+				"This token doesn't have access to %s. Its scopes are: %s.",
+				method[strings.LastIndex(method, "/")+1:], strings.Join(t.Scopes, ", "))
+		}
+
+		records, err := s.namespaces.lookup(namespace)
+		if err != nil {
+			return "", err
+		}
+
+		record, ok := records[namespace]
+		if !ok {
+			return "", invalidTokenErr
+		}
+		return record.Owner, nil
+	}
+	return "", invalidTokenErr
+}
+
+func (s *server) getScopedTokens(namespace string) ([]scopedToken, error) {
+	value, err := s.store.Get(scopedTokenKind, namespace)
+	switch {
+	case err == store.ErrNotFound:
+		return nil, nil
+	case err != nil:
+		return nil, errors.WithContext("get scoped tokens", err)
+	}
+	return parseScopedTokens(value)
+}
+
+func parseScopedTokens(value []byte) ([]scopedToken, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var tokens []scopedToken
+	if err := json.Unmarshal(value, &tokens); err != nil {
+		return nil, errors.WithContext("parse scoped tokens", err)
+	}
+	return tokens, nil
+}
+
+// updateScopedTokens replaces the tokens recorded for the namespace with the
+// result of `update`.
+func (s *server) updateScopedTokens(namespace string,
+	update func([]scopedToken) ([]scopedToken, error)) error {
+	return s.store.Update(scopedTokenKind, namespace, func(value []byte) ([]byte, error) {
+		tokens, err := parseScopedTokens(value)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens, err = update(tokens)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(tokens)
+	})
+}
+
+// deleteScopedTokens revokes all of the namespace's tokens. It's called when
+// the namespace is released so that the tokens can't be used to access the
+// namespace's next owner's sandbox.
+func (s *server) deleteScopedTokens(namespace string) error {
+	if err := s.store.Delete(scopedTokenKind, namespace); err != nil && err != store.ErrNotFound {
+		return errors.WithContext("delete scoped tokens", err)
+	}
+	return nil
+}
+
+// migrateScopedTokens moves the tokens that were stored in namespace
+// annotations by old versions of the manager into the store. Namespaces that
+// already have tokens in the store keep them, so the migration can safely be
+// retried.
+func (s *server) migrateScopedTokens() error {
+	namespacesClient := s.kubeClient.CoreV1().Namespaces()
+	namespaces, err := namespacesClient.List(metav1.ListOptions{
+		LabelSelector: "blimp.sandbox=true",
+	})
+	if err != nil {
+		return errors.WithContext("list namespaces", err)
+	}
+
+	for _, namespace := range namespaces.Items {
+		tokensJSON, ok := namespace.Annotations[kube.ScopedTokensAnnotation]
+		if !ok {
+			continue
+		}
+
+		err := s.store.Update(scopedTokenKind, namespace.Name, func(value []byte) ([]byte, error) {
+			if len(value) != 0 {
+				return value, nil
+			}
+			return []byte(tokensJSON), nil
+		})
+		if err != nil {
+			return errors.WithContext(fmt.Sprintf("migrate %s", namespace.Name), err)
+		}
+
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			ns, err := namespacesClient.Get(namespace.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			delete(ns.Annotations, kube.ScopedTokensAnnotation)
+			_, err = namespacesClient.Update(ns)
+			return err
+		})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.WithContext(fmt.Sprintf("remove annotation from %s", namespace.Name), err)
+		}
+	}
+	return nil
+}
+
+func validScopes() string {
+	var scopes []string
+	for scope := range tokenScopes {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return strings.Join(scopes, ", ")
+}
+
+func hashTokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
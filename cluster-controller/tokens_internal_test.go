@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	authProto "github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const getLogsMethod = "/blimp.cluster.v0.Manager/GetLogs"
+
+func newTokensTestServer(t *testing.T) (*server, auth.User) {
+	alice, err := auth.ParseIDToken("alice")
+	require.NoError(t, err)
+
+	managerStore := memStore{}
+	s := &server{
+		kubeClient: fakeKube.NewSimpleClientset(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        alice.Namespace,
+				Annotations: map[string]string{kube.OwnerAnnotation: alice.Name},
+			},
+		}),
+		namespaces:   newNamespaceRegistry(managerStore),
+		store:        managerStore,
+		shareLinkKey: []byte("share-link-key"),
+	}
+	return s, alice
+}
+
+func createToken(t *testing.T, s *server, scopes ...string) *cluster.CreateTokenResponse {
+	resp, err := s.CreateToken(context.Background(), &cluster.CreateTokenRequest{
+		Auth:   &authProto.BlimpAuth{Token: "alice"},
+		Scopes: scopes,
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestVerifyScopedToken(t *testing.T) {
+	s, alice := newTokensTestServer(t)
+
+	// Tokens can't be created until the namespace is claimed.
+	_, err := s.CreateToken(context.Background(), &cluster.CreateTokenRequest{
+		Auth:   &authProto.BlimpAuth{Token: "alice"},
+		Scopes: []string{"status"},
+	})
+	assert.Error(t, err)
+	require.NoError(t, s.namespaces.claim(alice))
+
+	// Unknown scopes are rejected.
+	_, err = s.CreateToken(context.Background(), &cluster.CreateTokenRequest{
+		Auth:   &authProto.BlimpAuth{Token: "alice"},
+		Scopes: []string{"admin"},
+	})
+	assert.Error(t, err)
+
+	created := createToken(t, s, "status")
+	owner, err := s.verifyScopedToken(created.Token, getStatusMethod)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", owner)
+
+	// Tokens only allow the RPCs in their scopes.
+	_, err = s.verifyScopedToken(created.Token, getLogsMethod)
+	codedErr, ok := errors.GetCode(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeUnauthorized, codedErr.Code())
+
+	// Tokens with the wrong secret, or for other namespaces, are rejected.
+	_, err = s.verifyScopedToken(auth.ScopedToken(alice.Namespace, created.Info.Id, "wrong"),
+		getStatusMethod)
+	assert.Error(t, err)
+	_, err = s.verifyScopedToken(auth.ScopedToken("other", created.Info.Id, "secret"),
+		getStatusMethod)
+	assert.Error(t, err)
+
+	// Only a hash of the secret is stored.
+	list, err := s.ListTokens(context.Background(), &cluster.ListTokensRequest{
+		Auth: &authProto.BlimpAuth{Token: "alice"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*cluster.ScopedToken{created.Info}, list.Tokens)
+	tokens, err := s.getScopedTokens(alice.Namespace)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.NotContains(t, created.Token, tokens[0].Hash)
+
+	// Revoked tokens are rejected.
+	logsToken := createToken(t, s, "logs")
+	_, err = s.RevokeToken(context.Background(), &cluster.RevokeTokenRequest{
+		Auth: &authProto.BlimpAuth{Token: "alice"},
+		Id:   created.Info.Id,
+	})
+	require.NoError(t, err)
+	_, err = s.verifyScopedToken(created.Token, getStatusMethod)
+	assert.Error(t, err)
+
+	_, err = s.RevokeToken(context.Background(), &cluster.RevokeTokenRequest{
+		Auth: &authProto.BlimpAuth{Token: "alice"},
+		Id:   created.Info.Id,
+	})
+	assert.Error(t, err)
+
+	owner, err = s.verifyScopedToken(logsToken.Token, getLogsMethod)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", owner)
+
+	// All tokens are revoked when the namespace is released.
+	require.NoError(t, s.deleteScopedTokens(alice.Namespace))
+	require.NoError(t, s.namespaces.release(alice.Namespace))
+	_, err = s.verifyScopedToken(logsToken.Token, getLogsMethod)
+	assert.Error(t, err)
+}
+
+// fakeServerStream receives a single GetStatusRequest with the given token.
+type fakeServerStream struct {
+	grpc.ServerStream
+	token string
+}
+
+func (ss fakeServerStream) RecvMsg(m interface{}) error {
+	m.(*cluster.GetStatusRequest).Auth = &authProto.BlimpAuth{Token: ss.token}
+	return nil
+}
+
+func TestScopedTokenInterceptors(t *testing.T) {
+	s, alice := newTokensTestServer(t)
+	require.NoError(t, s.namespaces.claim(alice))
+	statusToken := createToken(t, s, "status").Token
+
+	shareToken := func(expiresAt time.Time) string {
+		token, err := auth.SignShareToken(s.shareLinkKey, auth.ShareClaims{
+			Namespace: alice.Namespace,
+			Scopes:    []string{"status"},
+			ExpiresAt: expiresAt.Unix(),
+		})
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		method   string
+		expToken string
+		expErr   bool
+	}{
+		{
+			name:     "IDToken",
+			token:    "alice",
+			method:   getLogsMethod,
+			expToken: "alice",
+		},
+		{
+			name:     "ScopedToken",
+			token:    statusToken,
+			method:   getStatusMethod,
+			expToken: "alice",
+		},
+		{
+			name:   "ScopedTokenOutOfScope",
+			token:  statusToken,
+			method: getLogsMethod,
+			expErr: true,
+		},
+		{
+			name:     "ShareToken",
+			token:    shareToken(time.Now().Add(time.Hour)),
+			method:   getStatusMethod,
+			expToken: "alice",
+		},
+		{
+			name:   "ShareTokenOutOfScope",
+			token:  shareToken(time.Now().Add(time.Hour)),
+			method: getLogsMethod,
+			expErr: true,
+		},
+		{
+			name:   "ExpiredShareToken",
+			token:  shareToken(time.Now().Add(-time.Minute)),
+			method: getStatusMethod,
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The handler should see the owner's token in place of the
+			// scoped token.
+			var handledToken string
+			unaryHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handledToken = req.(*cluster.GetStatusRequest).GetAuth().GetToken()
+				return nil, nil
+			}
+			_, err := s.scopedTokenUnaryInterceptor(context.Background(),
+				&cluster.GetStatusRequest{Auth: &authProto.BlimpAuth{Token: test.token}},
+				&grpc.UnaryServerInfo{FullMethod: test.method}, unaryHandler)
+			if test.expErr {
+				assert.Error(t, err)
+				assert.Empty(t, handledToken, "handler shouldn't be called")
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expToken, handledToken)
+			}
+
+			streamHandler := func(srv interface{}, stream grpc.ServerStream) error {
+				var req cluster.GetStatusRequest
+				if err := stream.RecvMsg(&req); err != nil {
+					return err
+				}
+				handledToken = req.GetAuth().GetToken()
+				return nil
+			}
+			handledToken = ""
+			err = s.scopedTokenStreamInterceptor(nil, fakeServerStream{token: test.token},
+				&grpc.StreamServerInfo{FullMethod: test.method}, streamHandler)
+			if test.expErr {
+				assert.Error(t, err)
+				assert.Empty(t, handledToken)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expToken, handledToken)
+			}
+		})
+	}
+
+	// Revoked tokens are rejected by both interceptors.
+	require.NoError(t, s.deleteScopedTokens(alice.Namespace))
+	_, err := s.scopedTokenUnaryInterceptor(context.Background(),
+		&cluster.GetStatusRequest{Auth: &authProto.BlimpAuth{Token: statusToken}},
+		&grpc.UnaryServerInfo{FullMethod: getStatusMethod},
+		func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil })
+	assert.Error(t, err)
+	err = s.scopedTokenStreamInterceptor(nil, fakeServerStream{token: statusToken},
+		&grpc.StreamServerInfo{FullMethod: getStatusMethod},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			return stream.RecvMsg(&cluster.GetStatusRequest{})
+		})
+	assert.Error(t, err)
+}
+
+func TestMigrateScopedTokens(t *testing.T) {
+	s, alice := newTokensTestServer(t)
+	tokensJSON := `[{"id":"abc","scopes":["status"],"hash":"hash","createdAt":1}]`
+	namespacesClient := s.kubeClient.CoreV1().Namespaces()
+	_, err := namespacesClient.Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "legacy",
+			Labels:      map[string]string{"blimp.sandbox": "true"},
+			Annotations: map[string]string{kube.ScopedTokensAnnotation: tokensJSON},
+		},
+	})
+	require.NoError(t, err)
+
+	// Tokens that are already in the store aren't overwritten.
+	aliceNamespace, err := namespacesClient.Get(alice.Namespace, metav1.GetOptions{})
+	require.NoError(t, err)
+	aliceNamespace.Labels = map[string]string{"blimp.sandbox": "true"}
+	aliceNamespace.Annotations[kube.ScopedTokensAnnotation] = `[{"id":"stale"}]`
+	_, err = namespacesClient.Update(aliceNamespace)
+	require.NoError(t, err)
+	require.NoError(t, s.updateScopedTokens(alice.Namespace, func([]scopedToken) ([]scopedToken, error) {
+		return []scopedToken{{ID: "current"}}, nil
+	}))
+
+	require.NoError(t, s.migrateScopedTokens())
+	require.NoError(t, s.migrateScopedTokens())
+
+	tokens, err := s.getScopedTokens("legacy")
+	require.NoError(t, err)
+	assert.Equal(t, []scopedToken{{ID: "abc", Scopes: []string{"status"}, Hash: "hash", CreatedAt: 1}}, tokens)
+
+	// The tokens are removed from the namespaces once they're migrated.
+	for _, namespace := range []string{"legacy", alice.Namespace} {
+		ns, err := namespacesClient.Get(namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotContains(t, ns.Annotations, kube.ScopedTokensAnnotation)
+	}
+
+	tokens, err = s.getScopedTokens(alice.Namespace)
+	require.NoError(t, err)
+	assert.Equal(t, []scopedToken{{ID: "current"}}, tokens)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/cluster-controller/metering"
+	clusterAuth "github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// usageSampleInterval is how often the resources used by sandboxes are
+// metered.
+const usageSampleInterval = time.Minute
+
+func (s *server) GetUsage(ctx context.Context, req *cluster.GetUsageRequest) (*cluster.GetUsageResponse, error) {
+	log.Info("Start GetUsage")
+
+	period := req.GetPeriod()
+	if period == "" {
+		period = time.Now().Format(metering.PeriodFormat)
+	} else if _, err := time.Parse(metering.PeriodFormat, period); err != nil {
+		return &cluster.GetUsageResponse{}, errors.NewFriendlyError(
+			"Invalid period %q. The period should be formatted as YYYY-MM.", period)
+	}
+
+	var usage map[string]metering.Usage
+	if req.GetAdminToken() != "" {
+		if err := clusterAuth.AuthorizeAdminRequest(req.GetAdminToken()); err != nil {
+			return &cluster.GetUsageResponse{}, err
+		}
+
+		var err error
+		usage, err = s.meter.List(period)
+		if err != nil {
+			return &cluster.GetUsageResponse{}, errors.WithContext("list usage", err)
+		}
+	} else {
+		user, err := clusterAuth.AuthorizeRequest(req.GetAuth())
+		if err != nil {
+			return &cluster.GetUsageResponse{}, err
+		}
+
+		nsUsage, err := s.meter.Get(user.Namespace, period)
+		if err != nil {
+			return &cluster.GetUsageResponse{}, errors.WithContext("get usage", err)
+		}
+		usage = map[string]metering.Usage{user.Namespace: nsUsage}
+	}
+
+	var records []*cluster.UsageRecord
+	for namespace, nsUsage := range usage {
+		records = append(records, &cluster.UsageRecord{
+			Namespace:       namespace,
+			Period:          period,
+			CpuCoreHours:    nsUsage.CPUCoreHours,
+			MemoryGibHours:  nsUsage.MemoryGiBHours,
+			StorageGibHours: nsUsage.StorageGiBHours,
+			EgressBytes:     nsUsage.EgressBytes,
+
+			BoostCpuCoreHours: nsUsage.BoostCPUCoreHours,
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Namespace < records[j].Namespace
+	})
+
+	quota := s.usageQuota
+	quota.Period = period
+	return &cluster.GetUsageResponse{Usage: records, Quota: &quota}, nil
+}
+
+// getUsageQuota reads the monthly quota for each sandbox from the environment.
+// The quota is only used for reporting, and isn't enforced.
+func getUsageQuota() cluster.UsageRecord {
+	parseFloat := func(key string) float64 {
+		str, ok := os.LookupEnv(key)
+		if !ok {
+			return 0
+		}
+
+		val, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			log.WithError(err).WithField(key, str).Warnf("Couldn't parse $%s", key)
+			return 0
+		}
+		return val
+	}
+
+	return cluster.UsageRecord{
+		CpuCoreHours:    parseFloat("BLIMP_QUOTA_CPU_CORE_HOURS"),
+		MemoryGibHours:  parseFloat("BLIMP_QUOTA_MEMORY_GIB_HOURS"),
+		StorageGibHours: parseFloat("BLIMP_QUOTA_STORAGE_GIB_HOURS"),
+		EgressBytes:     int64(parseFloat("BLIMP_QUOTA_EGRESS_BYTES")),
+	}
+}
@@ -1,7 +1,7 @@
 /*
 Package volume manages the persistent storage that backs Blimp volumes.
 
-BACKGROUND
+# BACKGROUND
 
 Each namespace has a single PersistentVolume. This volume persists across
 `blimp down`s, and is only deleted when `blimp down --volumes` is run.
@@ -11,7 +11,7 @@ volumes, and bind volumes) as subdirectories. Using a single PersistentVolume
 (rather than a PersistentVolume per user volume) is required to work around
 limits on the number of PersistentVolumes that can be bound to a single Node.
 
-VOLUME CREATION
+# VOLUME CREATION
 
 Blimp doesn't create PersistentVolumes directly. The PersistentVolume for each
 namespace is created by using a PersistentVolumeClaim with an empty storage
@@ -27,7 +27,7 @@ in the user's namespace. When the user's namespace is created, a
 PersistentVolumeClaim is deployed that explicitly references the user's
 PersistentVolume. Pods then mount the PersistentVolumeClaim.
 
-PERSISTING ACROSS `blimp down`s
+# PERSISTING ACROSS `blimp down`s
 
 Because PVC objects are namespaced, deleting the user's namespace during `blimp
 down` also deletes the PVC. Therefore, we ensure that the user's
@@ -46,7 +46,7 @@ by unsetting all claims if the volume is Released. There should never be a case
 where a new PVC is created while another PVC is bound to the namespace's
 PersistentVolume.
 
-VOLUME DELETION
+# VOLUME DELETION
 
 Permanently deleting a PersistentVolume is done by setting the PersistentVolume's
 reclaim policy to Delete. When the claim associated with the PersistentVolume
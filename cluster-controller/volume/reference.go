@@ -1,7 +1,10 @@
 package volume
 
 import (
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -12,6 +15,10 @@ const (
 	// PersistentVolumeClaimName is the name used for the PVC backing all Blimp
 	// volumes in a namespace.
 	PersistentVolumeClaimName = "blimp-volume"
+
+	// VCPStateDir is the path within the PV where the volume initialization
+	// container records what it copied into each named volume.
+	VCPStateDir = "vcp-state"
 )
 
 var (
@@ -33,8 +40,38 @@ func NamedVolumeDir(name string) string {
 	return filepath.Join("volume", hash.DNSCompliant(name))
 }
 
-// NamedVolumeDir returns the path within the PV that's used to back the given
+// BindVolumeDir returns the path within the PV that's used to back the given
 // path on the CLI.
 func BindVolumeDir(cliPath string) string {
-	return filepath.Join("bind", cliPath)
+	return filepath.Join("bind", slashPath(cliPath))
+}
+
+// slashPath converts Windows paths into slash separated paths that are valid
+// on Linux. Drive letters become directories, so `C:\Users\kevin` becomes
+// `/c/Users/kevin`, and network shares become directories under `/unc`.
+// Other paths are returned unchanged.
+func slashPath(cliPath string) string {
+	// Strip the prefix used by Windows for paths longer than MAX_PATH.
+	if strings.HasPrefix(cliPath, `\\?\UNC\`) {
+		cliPath = `\\` + strings.TrimPrefix(cliPath, `\\?\UNC\`)
+	} else {
+		cliPath = strings.TrimPrefix(cliPath, `\\?\`)
+	}
+
+	switch {
+	case windowsDriveRegex.MatchString(cliPath):
+		drive := strings.ToLower(cliPath[:1])
+		return path.Join("/", drive, strings.ReplaceAll(cliPath[2:], `\`, "/"))
+	case strings.HasPrefix(cliPath, `\\`):
+		return path.Join("/unc", strings.ReplaceAll(cliPath[2:], `\`, "/"))
+	}
+	return cliPath
+}
+
+var windowsDriveRegex = regexp.MustCompile(`^[a-zA-Z]:(\\|/|$)`)
+
+// AddonDir returns the path within the PV that's used to store the data and
+// snapshots of the given add-on.
+func AddonDir(name string) string {
+	return filepath.Join("addon", hash.DNSCompliant(name))
 }
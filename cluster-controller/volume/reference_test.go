@@ -0,0 +1,25 @@
+package volume_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelda/blimp/cluster-controller/volume"
+)
+
+func TestBindVolumeDir(t *testing.T) {
+	tests := map[string]string{
+		"/Users/kevin/app":                 "bind/Users/kevin/app",
+		`C:\Users\kevin\app`:               "bind/c/Users/kevin/app",
+		`d:\src\`:                          "bind/d/src",
+		"C:/Users/kevin/app":               "bind/c/Users/kevin/app",
+		`\\?\C:\Users\kevin\very\long\app`: "bind/c/Users/kevin/very/long/app",
+		`\\fileserver\share\app`:           "bind/unc/fileserver/share/app",
+		`\\?\UNC\fileserver\share\app`:     "bind/unc/fileserver/share/app",
+	}
+
+	for cliPath, exp := range tests {
+		assert.Equal(t, exp, volume.BindVolumeDir(cliPath), cliPath)
+	}
+}
@@ -3,6 +3,7 @@ package volume
 import (
 	"context"
 	"fmt"
+	"os"
 	"sort"
 
 	log "github.com/sirupsen/logrus"
@@ -13,8 +14,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
 
-	"github.com/kelda/blimp/pkg/kube"
 	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
 	"github.com/kelda/blimp/pkg/kubewait"
 )
 
@@ -27,6 +28,12 @@ const (
 	// user will experience out of disk errors if the combined size of all bind
 	// and named volumes exceeds this amount.
 	pvSize = "25Gi"
+
+	// sharedStorageClassEnv is the name of a StorageClass that provisions
+	// ReadWriteMany volumes, such as an NFS or Filestore provisioner. If it's
+	// set, new sandboxes get volumes that can be mounted from multiple nodes,
+	// so their pods can be spread across nodes when they don't fit on one.
+	sharedStorageClassEnv = "SHARED_STORAGE_CLASS"
 )
 
 // CreatePVC ensures that the namespace's PersistentVolumeClaim exists, and is
@@ -51,6 +58,10 @@ func CreatePVC(ctx context.Context, kubeClient kubernetes.Interface, namespace s
 			VolumeMode: &persistentFs,
 		},
 	}
+	if storageClass := os.Getenv(sharedStorageClassEnv); storageClass != "" {
+		pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+		pvc.Spec.StorageClassName = &storageClass
+	}
 
 	// If the PVC already exists, there's nothing more for us to do.
 	pvcClient := kubeClient.CoreV1().PersistentVolumeClaims(namespace)
@@ -74,6 +85,14 @@ func CreatePVC(ctx context.Context, kubeClient kubernetes.Interface, namespace s
 			}
 		}
 		pvName = pv.Name
+
+		// Volumes created before the shared storage class was configured
+		// keep their original access mode and class.
+		pvc.Spec.AccessModes = pv.Spec.AccessModes
+		if pv.Spec.StorageClassName != "" {
+			storageClass := pv.Spec.StorageClassName
+			pvc.Spec.StorageClassName = &storageClass
+		}
 	case errNoPersistentVolume:
 		pvName, err = createPersistentVolume(ctx, kubeClient, namespace, pvc.Spec)
 		if err != nil {
@@ -104,6 +123,23 @@ func CreatePVC(ctx context.Context, kubeClient kubernetes.Interface, namespace s
 	return nil
 }
 
+// IsShared returns whether the namespace's volume can be mounted by pods on
+// different nodes at the same time.
+func IsShared(kubeClient kubernetes.Interface, namespace string) (bool, error) {
+	pvc, err := kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(
+		PersistentVolumeClaimName, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.WithContext("get pvc", err)
+	}
+
+	for _, mode := range pvc.Spec.AccessModes {
+		if mode == corev1.ReadWriteMany {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // PermanentlyDeletePVC deletes the namespace's persistent volume, and its
 // underlying storage.
 // This function only sends the required requests to the API server. It does
@@ -293,6 +329,27 @@ func getPersistentVolume(kubeClient kubernetes.Interface, namespace string) (
 	}
 }
 
+// GetCapacities returns the storage capacity of every namespace's
+// PersistentVolume, keyed by namespace. Volumes are included even if their
+// namespace has been deleted by `blimp down` since they still consume storage.
+func GetCapacities(kubeClient kubernetes.Interface) (map[string]resource.Quantity, error) {
+	pvs, err := kubeClient.CoreV1().PersistentVolumes().List(metav1.ListOptions{
+		LabelSelector: pvNamespaceLabel,
+	})
+	if err != nil {
+		return nil, errors.WithContext("list", err)
+	}
+
+	capacities := map[string]resource.Quantity{}
+	for _, pv := range pvs.Items {
+		namespace := pv.Labels[pvNamespaceLabel]
+		capacity := capacities[namespace]
+		capacity.Add(*pv.Spec.Capacity.Storage())
+		capacities[namespace] = capacity
+	}
+	return capacities, nil
+}
+
 // pvUpdateFn specifies how to update a PersistentVolume. The update is aborted
 // if the second return argument is false.
 type pvUpdateFn func(corev1.PersistentVolume) (corev1.PersistentVolume, bool)
@@ -0,0 +1,48 @@
+package main
+
+import (
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+	"github.com/kelda/blimp/pkg/proto/wait"
+)
+
+// getVolumeInitStatus returns the progress of initializing the pod's volumes,
+// as reported by the volume initialization container. Once the service boots,
+// it's only returned if a volume failed to initialize, since the service
+// boots regardless.
+func getVolumeInitStatus(pod *corev1.Pod, phase cluster.ServicePhase) []*cluster.VolumeInitStatus {
+	volumeInit, ok := pod.Annotations[metadata.VolumeInitKey]
+	if !ok {
+		return nil
+	}
+
+	progress, err := metadata.ParseVolumeInit(volumeInit)
+	if err != nil {
+		log.WithError(err).WithField("namespace", pod.Namespace).
+			WithField("pod", pod.Name).Warn("Failed to parse volume initialization progress")
+		return nil
+	}
+
+	var statuses []*cluster.VolumeInitStatus
+	failed := false
+	for _, volume := range progress {
+		if volume.State == wait.VolumeInitProgress_FAILED {
+			failed = true
+		}
+		statuses = append(statuses, &cluster.VolumeInitStatus{
+			Volume:      volume.Volume,
+			State:       cluster.VolumeInitStatus_State(volume.State),
+			CopiedBytes: volume.CopiedBytes,
+			TotalBytes:  volume.TotalBytes,
+			Error:       volume.Error,
+		})
+	}
+
+	if phase != cluster.ServicePhase_INITIALIZING_VOLUMES && !failed {
+		return nil
+	}
+	return statuses
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+	"github.com/kelda/blimp/pkg/proto/wait"
+)
+
+func TestGetVolumeInitStatus(t *testing.T) {
+	copying := []*wait.VolumeInitProgress{
+		{Volume: "/data", State: wait.VolumeInitProgress_COPYING, CopiedBytes: 10, TotalBytes: 100},
+		{Volume: "/cache", State: wait.VolumeInitProgress_UNCHANGED, TotalBytes: 5},
+	}
+	failed := []*wait.VolumeInitProgress{
+		{Volume: "/data", State: wait.VolumeInitProgress_FAILED, Error: "cp: no space left on device"},
+		{Volume: "/cache", State: wait.VolumeInitProgress_DONE},
+	}
+
+	tests := []struct {
+		name     string
+		progress []*wait.VolumeInitProgress
+		rawValue string
+		phase    cluster.ServicePhase
+		exp      []*cluster.VolumeInitStatus
+	}{
+		{
+			name:  "NoAnnotation",
+			phase: cluster.ServicePhase_INITIALIZING_VOLUMES,
+		},
+		{
+			name:     "Initializing",
+			progress: copying,
+			phase:    cluster.ServicePhase_INITIALIZING_VOLUMES,
+			exp: []*cluster.VolumeInitStatus{
+				{Volume: "/data", State: cluster.VolumeInitStatus_COPYING, CopiedBytes: 10, TotalBytes: 100},
+				{Volume: "/cache", State: cluster.VolumeInitStatus_UNCHANGED, TotalBytes: 5},
+			},
+		},
+		{
+			name:     "HiddenOnceBooted",
+			progress: copying,
+			phase:    cluster.ServicePhase_RUNNING,
+		},
+		{
+			name:     "FailuresShownOnceBooted",
+			progress: failed,
+			phase:    cluster.ServicePhase_RUNNING,
+			exp: []*cluster.VolumeInitStatus{
+				{Volume: "/data", State: cluster.VolumeInitStatus_FAILED, Error: "cp: no space left on device"},
+				{Volume: "/cache", State: cluster.VolumeInitStatus_DONE},
+			},
+		},
+		{
+			name:     "MalformedAnnotation",
+			rawValue: "{",
+			phase:    cluster.ServicePhase_INITIALIZING_VOLUMES,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			switch {
+			case test.rawValue != "":
+				pod.Annotations[metadata.VolumeInitKey] = test.rawValue
+			case test.progress != nil:
+				value, err := metadata.VolumeInit(test.progress)
+				require.NoError(t, err)
+				pod.Annotations[metadata.VolumeInitKey] = value
+			}
+
+			assert.Equal(t, test.exp, getVolumeInitStatus(pod, test.phase))
+		})
+	}
+}
@@ -18,4 +18,11 @@ echo "AuthToken: ${BLIMP_TOKEN}" > ~/.blimp/auth.yaml
 git clone "${GIT_REPO}" /app
 cd /app
 
+# GIT_REF may be a ref that isn't fetched by default, such as a pull request's
+# head.
+if [ -n "${GIT_REF}" ]; then
+	git fetch origin "${GIT_REF}"
+	git checkout FETCH_HEAD
+fi
+
 exec "$@"
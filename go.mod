@@ -33,7 +33,9 @@ require (
 	github.com/gorilla/mux v1.7.4 // indirect
 	github.com/gorilla/websocket v1.4.2
 	github.com/kelda/compose-go v0.0.0-20200902144940-ad3de8621596
+	github.com/lib/pq v1.2.0
 	github.com/lithammer/dedent v1.1.0
+	github.com/mattn/go-sqlite3 v1.9.0
 	github.com/miekg/dns v1.1.28
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/moby/buildkit v0.6.4
@@ -44,8 +46,11 @@ require (
 	github.com/stretchr/testify v1.5.1
 	github.com/syncthing/syncthing v1.6.1
 	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
+	golang.org/x/net v0.0.0-20200625001655-4c5254603344
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	golang.org/x/sync v0.0.0-20200625203802-6e8e738ad208
+	golang.org/x/sys v0.0.0-20200523222454-059865788121
+	golang.org/x/time v0.0.0-20200416051211-89c76fbcd5d1
 	google.golang.org/grpc v1.29.1
 	gopkg.in/square/go-jose.v2 v2.4.1
 	k8s.io/api v0.17.4
@@ -93,6 +93,10 @@ func (tc *tunnelConn) Read(b []byte) (n int, err error) {
 	}
 
 	msg, err := tc.tunnel.Recv()
+	// The node controller sends empty buffers to keep idle tunnels alive.
+	for err == nil && msg.GetBuf() != nil && len(msg.GetBuf()) == 0 {
+		msg, err = tc.tunnel.Recv()
+	}
 	if err == io.EOF || status.Code(err) == codes.Canceled {
 		// We attempt to send EOF and close the stream if possible, but it
 		// probably won't work and that's ok.
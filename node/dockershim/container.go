@@ -0,0 +1,412 @@
+package dockershim
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	mathrand "math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+const (
+	// containerAnnotation stores the container's Docker configuration on its
+	// pod, so that the container can be inspected after the node controller
+	// restarts.
+	containerAnnotation = "blimp.docker-container"
+
+	// The range that published ports are allocated from when the client
+	// doesn't request a specific port. It's the same as Docker's default.
+	minHostPort = 32768
+	maxHostPort = 60999
+)
+
+// container is a container created through the shim. Containers are only
+// backed by a pod once they're started.
+type container struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Created   time.Time       `json:"created"`
+	Config    containerConfig `json:"config"`
+	HostPorts map[string]int  `json:"hostPorts"`
+
+	// The pod that created the container, and its namespace.
+	Owner     string `json:"owner"`
+	Namespace string `json:"namespace"`
+}
+
+func newContainer(owner *corev1.Pod, name string, req createRequest, usedHostPorts map[int]bool) (
+	*container, error) {
+	if err := checkHostConfig(req.HostConfig); err != nil {
+		return nil, err
+	}
+
+	if req.Image == "" {
+		return nil, errors.NewFriendlyError("An image is required")
+	}
+
+	if _, err := parseUser(req.User); err != nil {
+		return nil, err
+	}
+
+	idBytes := make([]byte, 32)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, errors.WithContext("generate ID", err)
+	}
+	id := hex.EncodeToString(idBytes)
+
+	if name == "" {
+		name = "blimp_" + id[:12]
+	}
+
+	hostPorts, err := allocateHostPorts(req, usedHostPorts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &container{
+		ID:        id,
+		Name:      strings.TrimPrefix(name, "/"),
+		Created:   time.Now(),
+		Config:    req.containerConfig,
+		HostPorts: hostPorts,
+		Owner:     string(owner.UID),
+		Namespace: owner.Namespace,
+	}, nil
+}
+
+// checkHostConfig rejects the settings that would give the container access
+// to the node, or that can't be emulated with a pod.
+func checkHostConfig(cfg hostConfig) error {
+	switch {
+	case cfg.Privileged:
+		return errors.NewFriendlyError("Privileged containers aren't supported")
+	case cfg.NetworkMode == "host", cfg.PidMode == "host", cfg.IpcMode == "host":
+		return errors.NewFriendlyError("Containers can't use the host's namespaces")
+	case len(cfg.Binds) != 0 || len(cfg.Mounts) != 0:
+		return errors.NewFriendlyError("Volumes aren't supported")
+	case len(cfg.Devices) != 0:
+		return errors.NewFriendlyError("Devices aren't supported")
+	case len(cfg.CapAdd) != 0:
+		return errors.NewFriendlyError("Adding capabilities isn't supported")
+	}
+	return nil
+}
+
+// allocateHostPorts picks the published port for each of the container's
+// ports. Ports requested by the client are used as is, and the rest are
+// picked at random.
+func allocateHostPorts(req createRequest, used map[int]bool) (map[string]int, error) {
+	hostPorts := map[string]int{}
+	for port, bindings := range req.HostConfig.PortBindings {
+		for _, binding := range bindings {
+			if binding.HostPort == "" {
+				continue
+			}
+
+			hostPort, err := strconv.Atoi(binding.HostPort)
+			if err != nil {
+				return nil, errors.NewFriendlyError("Invalid host port %q", binding.HostPort)
+			}
+
+			if used[hostPort] {
+				return nil, errors.NewFriendlyError("Port %d is already allocated", hostPort)
+			}
+			hostPorts[port] = hostPort
+			used[hostPort] = true
+			break
+		}
+	}
+
+	ports := map[string]struct{}{}
+	for port := range req.ExposedPorts {
+		ports[port] = struct{}{}
+	}
+	for port := range req.HostConfig.PortBindings {
+		ports[port] = struct{}{}
+	}
+
+	for port := range ports {
+		if _, ok := hostPorts[port]; ok {
+			continue
+		}
+
+		if strings.HasSuffix(port, "/udp") {
+			return nil, errors.NewFriendlyError("UDP ports aren't supported")
+		}
+
+		for {
+			hostPort := minHostPort + mathrand.Intn(maxHostPort-minHostPort+1)
+			if !used[hostPort] {
+				hostPorts[port] = hostPort
+				used[hostPort] = true
+				break
+			}
+		}
+	}
+	return hostPorts, nil
+}
+
+// toPod returns the pod that runs the container. The pod is owned by the
+// service's pod, so Kubernetes deletes it when the service is removed or
+// restarted.
+func (c *container) toPod(owner *corev1.Pod) (corev1.Pod, error) {
+	containerJSON, err := json.Marshal(c)
+	if err != nil {
+		return corev1.Pod{}, errors.WithContext("marshal container", err)
+	}
+
+	var env []corev1.EnvVar
+	for _, kv := range c.Config.Env {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 1 {
+			continue
+		}
+		env = append(env, corev1.EnvVar{Name: parts[0], Value: parts[1]})
+	}
+
+	securityContext, err := parseUser(c.Config.User)
+	if err != nil {
+		return corev1.Pod{}, err
+	}
+
+	var ports []corev1.ContainerPort
+	for port := range c.HostPorts {
+		portNum, err := parsePort(port)
+		if err != nil {
+			return corev1.Pod{}, err
+		}
+		ports = append(ports, corev1.ContainerPort{ContainerPort: int32(portNum)})
+	}
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.podName(),
+			Namespace: c.Namespace,
+			Labels: map[string]string{
+				kube.DockerOwnerLabel:         c.Owner,
+				affinity.ColocateNamespaceKey: c.Namespace,
+			},
+			Annotations: map[string]string{
+				containerAnnotation: string(containerJSON),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Name:       owner.Name,
+					UID:        owner.UID,
+				},
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:            "container",
+					Image:           c.Config.Image,
+					Command:         c.Config.Entrypoint,
+					Args:            c.Config.Cmd,
+					Env:             env,
+					WorkingDir:      c.Config.WorkingDir,
+					TTY:             c.Config.Tty,
+					Ports:           ports,
+					SecurityContext: securityContext,
+				},
+			},
+			RestartPolicy:                corev1.RestartPolicyNever,
+			AutomountServiceAccountToken: falsePtr(),
+			EnableServiceLinks:           falsePtr(),
+			Affinity:                     owner.Spec.Affinity,
+			Tolerations:                  owner.Spec.Tolerations,
+			ImagePullSecrets:             owner.Spec.ImagePullSecrets,
+
+			// Run the container with the same isolation as the pod that
+			// created it.
+			RuntimeClassName: owner.Spec.RuntimeClassName,
+		},
+	}, nil
+}
+
+func (c *container) podName() string {
+	return "docker-" + c.ID[:12]
+}
+
+// matches returns whether the client's reference to a container refers to
+// this container. Like Docker, containers can be referenced by name, ID, or a
+// prefix of the ID.
+func (c *container) matches(ref string) bool {
+	ref = strings.TrimPrefix(ref, "/")
+	return ref != "" && (c.Name == ref || strings.HasPrefix(c.ID, ref))
+}
+
+func containerFromPod(pod *corev1.Pod) (*container, error) {
+	var c container
+	if err := json.Unmarshal([]byte(pod.Annotations[containerAnnotation]), &c); err != nil {
+		return nil, errors.WithContext("parse container", err)
+	}
+	return &c, nil
+}
+
+// status is the state of a container.
+type status struct {
+	state containerState
+	ip    string
+}
+
+// createdStatus is the status of containers that haven't been started.
+var createdStatus = status{state: containerState{Status: "created"}}
+
+// exitedStatus is the status of containers that were stopped by the client.
+func exitedStatus(exitCode int, finishedAt time.Time) status {
+	return status{state: containerState{
+		Status:     "exited",
+		ExitCode:   exitCode,
+		FinishedAt: finishedAt.Format(time.RFC3339Nano),
+	}}
+}
+
+func podStatus(pod *corev1.Pod) status {
+	s := status{ip: pod.Status.PodIP, state: containerState{Status: "created"}}
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return s
+	}
+
+	cs := pod.Status.ContainerStatuses[0]
+	switch {
+	case cs.State.Running != nil:
+		s.state.Status = "running"
+		s.state.Running = true
+		s.state.Pid = 1
+		s.state.StartedAt = cs.State.Running.StartedAt.Format(time.RFC3339Nano)
+	case cs.State.Terminated != nil:
+		s.state.Status = "exited"
+		s.state.ExitCode = int(cs.State.Terminated.ExitCode)
+		s.state.OOMKilled = cs.State.Terminated.Reason == "OOMKilled"
+		s.state.StartedAt = cs.State.Terminated.StartedAt.Format(time.RFC3339Nano)
+		s.state.FinishedAt = cs.State.Terminated.FinishedAt.Format(time.RFC3339Nano)
+	case cs.State.Waiting != nil:
+		s.state.Error = cs.State.Waiting.Message
+	}
+	return s
+}
+
+func (c *container) inspect(s status) containerJSON {
+	var path string
+	var args []string
+	cmd := append(append([]string{}, c.Config.Entrypoint...), c.Config.Cmd...)
+	if len(cmd) != 0 {
+		path = cmd[0]
+		args = cmd[1:]
+	}
+
+	ports := map[string][]portBinding{}
+	for port, hostPort := range c.HostPorts {
+		ports[port] = []portBinding{{HostIP: publishIP, HostPort: strconv.Itoa(hostPort)}}
+	}
+
+	return containerJSON{
+		ID:      c.ID,
+		Created: c.Created.Format(time.RFC3339Nano),
+		Path:    path,
+		Args:    args,
+		State:   s.state,
+		Image:   c.Config.Image,
+		Name:    "/" + c.Name,
+		Config:  c.Config,
+		NetworkSettings: networkSettings{
+			IPAddress: s.ip,
+			Ports:     ports,
+			Networks: map[string]endpointSetting{
+				"bridge": {IPAddress: s.ip},
+			},
+		},
+	}
+}
+
+func (c *container) summary(s status) containerSummary {
+	var ports []summaryPort
+	for port, hostPort := range c.HostPorts {
+		portNum, err := parsePort(port)
+		if err != nil {
+			continue
+		}
+
+		ports = append(ports, summaryPort{
+			IP:          publishIP,
+			PrivatePort: portNum,
+			PublicPort:  hostPort,
+			Type:        "tcp",
+		})
+	}
+
+	return containerSummary{
+		ID:      c.ID,
+		Names:   []string{"/" + c.Name},
+		Image:   c.Config.Image,
+		Command: strings.Join(append(append([]string{}, c.Config.Entrypoint...), c.Config.Cmd...), " "),
+		Created: c.Created.Unix(),
+		State:   s.state.Status,
+		Status:  s.state.Status,
+		Labels:  c.Config.Labels,
+		Ports:   ports,
+	}
+}
+
+// parseUser converts the user that the container should run as into a
+// security context. Pods can only run as numeric IDs, so user and group names
+// other than root are rejected.
+func parseUser(user string) (*corev1.SecurityContext, error) {
+	if user == "" {
+		return nil, nil
+	}
+
+	parseID := func(id string) (*int64, error) {
+		if id == "root" {
+			id = "0"
+		}
+
+		parsed, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || parsed < 0 {
+			return nil, errors.NewFriendlyError("Unsupported user %q. "+
+				"Only numeric user and group IDs are supported, such as `1000` or `1000:1000`.", user)
+		}
+		return &parsed, nil
+	}
+
+	parts := strings.SplitN(user, ":", 2)
+	uid, err := parseID(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	securityContext := &corev1.SecurityContext{RunAsUser: uid}
+	if len(parts) == 2 {
+		securityContext.RunAsGroup, err = parseID(parts[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return securityContext, nil
+}
+
+// parsePort parses ports in the Docker format, such as "80/tcp".
+func parsePort(port string) (int, error) {
+	portNum, err := strconv.Atoi(strings.TrimSuffix(port, "/tcp"))
+	if err != nil {
+		return 0, errors.NewFriendlyError("Invalid port %q", port)
+	}
+	return portNum, nil
+}
+
+func falsePtr() *bool {
+	f := false
+	return &f
+}
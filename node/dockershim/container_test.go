@@ -0,0 +1,190 @@
+package dockershim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+func TestCheckHostConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    hostConfig
+		expErr bool
+	}{
+		{name: "Default"},
+		{name: "Privileged", cfg: hostConfig{Privileged: true}, expErr: true},
+		{name: "HostNetwork", cfg: hostConfig{NetworkMode: "host"}, expErr: true},
+		{name: "BridgeNetwork", cfg: hostConfig{NetworkMode: "bridge"}},
+		{name: "HostPID", cfg: hostConfig{PidMode: "host"}, expErr: true},
+		{name: "Binds", cfg: hostConfig{Binds: []string{"/:/host"}}, expErr: true},
+		{name: "Devices", cfg: hostConfig{Devices: []json.RawMessage{[]byte(`{}`)}}, expErr: true},
+		{name: "CapAdd", cfg: hostConfig{CapAdd: []string{"SYS_ADMIN"}}, expErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkHostConfig(test.cfg)
+			if test.expErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAllocateHostPorts(t *testing.T) {
+	req := createRequest{
+		containerConfig: containerConfig{
+			ExposedPorts: map[string]struct{}{"80/tcp": {}, "443/tcp": {}},
+		},
+		HostConfig: hostConfig{
+			PortBindings: map[string][]portBinding{
+				"80/tcp": {{HostPort: "8080"}},
+			},
+		},
+	}
+
+	used := map[int]bool{}
+	hostPorts, err := allocateHostPorts(req, used)
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, hostPorts["80/tcp"])
+	assert.True(t, hostPorts["443/tcp"] >= minHostPort && hostPorts["443/tcp"] <= maxHostPort)
+	assert.True(t, used[8080])
+	assert.True(t, used[hostPorts["443/tcp"]])
+
+	// Ports that are already in use can't be requested again.
+	_, err = allocateHostPorts(req, used)
+	assert.Error(t, err)
+
+	_, err = allocateHostPorts(createRequest{
+		containerConfig: containerConfig{ExposedPorts: map[string]struct{}{"53/udp": {}}},
+	}, map[int]bool{})
+	assert.Error(t, err)
+}
+
+func TestParseUser(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	tests := []struct {
+		user   string
+		exp    *corev1.SecurityContext
+		expErr bool
+	}{
+		{user: ""},
+		{user: "1000", exp: &corev1.SecurityContext{RunAsUser: int64Ptr(1000)}},
+		{
+			user: "1000:2000",
+			exp:  &corev1.SecurityContext{RunAsUser: int64Ptr(1000), RunAsGroup: int64Ptr(2000)},
+		},
+		{user: "root", exp: &corev1.SecurityContext{RunAsUser: int64Ptr(0)}},
+		{
+			user: "root:root",
+			exp:  &corev1.SecurityContext{RunAsUser: int64Ptr(0), RunAsGroup: int64Ptr(0)},
+		},
+		{user: "postgres", expErr: true},
+		{user: "1000:staff", expErr: true},
+		{user: "-1", expErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.user, func(t *testing.T) {
+			securityContext, err := parseUser(test.user)
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.exp, securityContext)
+		})
+	}
+}
+
+func TestToPod(t *testing.T) {
+	gvisor := "gvisor"
+	owner := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "sandbox",
+			UID:       "owner-uid",
+		},
+		Spec: corev1.PodSpec{
+			RuntimeClassName: &gvisor,
+			Tolerations:      []corev1.Toleration{{Key: "dedicated", Value: "blimp"}},
+		},
+	}
+
+	req := createRequest{
+		containerConfig: containerConfig{
+			Image: "postgres",
+			Cmd:   strSlice{"postgres", "-c", "fsync=off"},
+			Env:   []string{"POSTGRES_PASSWORD=password", "IGNORED"},
+			User:  "999:999",
+		},
+	}
+	c, err := newContainer(owner, "/db", req, map[int]bool{})
+	assert.NoError(t, err)
+	assert.Equal(t, "db", c.Name)
+	assert.True(t, c.matches("db"))
+	assert.True(t, c.matches(c.ID[:6]))
+	assert.False(t, c.matches(""))
+
+	pod, err := c.toPod(owner)
+	assert.NoError(t, err)
+	assert.Equal(t, "sandbox", pod.Namespace)
+	assert.Equal(t, "owner-uid", pod.Labels[kube.DockerOwnerLabel])
+	assert.Equal(t, owner.UID, pod.OwnerReferences[0].UID)
+	assert.Equal(t, owner.Spec.RuntimeClassName, pod.Spec.RuntimeClassName)
+	assert.Equal(t, owner.Spec.Tolerations, pod.Spec.Tolerations)
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, "postgres", container.Image)
+	assert.Equal(t, []string{"postgres", "-c", "fsync=off"}, container.Args)
+	assert.Equal(t, []corev1.EnvVar{{Name: "POSTGRES_PASSWORD", Value: "password"}}, container.Env)
+	if assert.NotNil(t, container.SecurityContext) {
+		assert.Equal(t, int64(999), *container.SecurityContext.RunAsUser)
+		assert.Equal(t, int64(999), *container.SecurityContext.RunAsGroup)
+	}
+
+	// The container can be recovered from its pod.
+	parsed, err := containerFromPod(&pod)
+	assert.NoError(t, err)
+	assert.Equal(t, c.ID, parsed.ID)
+	assert.Equal(t, c.Config, parsed.Config)
+
+	// Users that can't be mapped to a pod are rejected when the container
+	// is created.
+	req.User = "postgres"
+	_, err = newContainer(owner, "", req, map[int]bool{})
+	assert.Error(t, err)
+}
+
+func TestPodStatus(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{PodIP: "10.0.0.5"}}
+	assert.Equal(t, status{ip: "10.0.0.5", state: containerState{Status: "created"}}, podStatus(pod))
+
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+	}
+	running := podStatus(pod)
+	assert.Equal(t, "running", running.state.Status)
+	assert.True(t, running.state.Running)
+
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 1,
+			Reason:   "OOMKilled",
+		}}},
+	}
+	exited := podStatus(pod)
+	assert.Equal(t, "exited", exited.state.Status)
+	assert.Equal(t, 1, exited.state.ExitCode)
+	assert.True(t, exited.state.OOMKilled)
+}
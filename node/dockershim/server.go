@@ -0,0 +1,613 @@
+// Package dockershim emulates the Docker socket for services that start
+// containers at runtime, such as test suites that use testcontainers.
+//
+// Services that enable `x-blimp.docker_socket` run a sidecar that listens on
+// a Unix socket, and forwards each connection to the node controller. The
+// node controller implements a subset of the Docker Engine API by running
+// each container as a pod in the service's namespace. The pods are owned by
+// the service's pod, so they're removed when the service is.
+//
+// Containers that aren't backed by a pod -- ones that were created but not
+// started yet, and ones that were stopped by the client -- are only tracked
+// in the node controller's memory. If the node controller restarts, created
+// containers are forgotten, and stopped containers are reported as removed,
+// so `docker wait` on them returns exit code 137 rather than the container's
+// exit code. Clients such as testcontainers start containers right after
+// creating them, and remove them right after stopping them, so this rarely
+// matters in practice.
+package dockershim
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+const (
+	// Port is the port that the sidecars in the services connect to.
+	Port = 9004
+
+	// PublishedPortsPath lists the ports that the sidecar should publish on
+	// localhost. It isn't part of the Docker API.
+	PublishedPortsPath = "/blimp/published-ports"
+
+	// publishIP is the address that published ports are reachable on from
+	// the service.
+	publishIP = "127.0.0.1"
+
+	apiVersion = "1.40"
+
+	// maxContainers is the maximum number of containers that each service
+	// can have at once.
+	maxContainers = 10
+
+	// startTimeout is how long starting a container waits for the image to
+	// be pulled and the container to boot.
+	startTimeout = 5 * time.Minute
+)
+
+type Server struct {
+	kubeClient kubernetes.Interface
+	podLister  listers.PodLister
+
+	// containers tracks the containers that aren't backed by a pod: either
+	// because they haven't been started yet, or because they were stopped
+	// by the client. exitCodes and finishedAt record the results of the
+	// stopped containers. They're all protected by `lock`. They aren't
+	// persisted, so they're lost if the node controller restarts.
+	containers map[string]*container
+	exitCodes  map[string]int
+	finishedAt map[string]time.Time
+	lock       sync.Mutex
+}
+
+func NewServer(kubeClient kubernetes.Interface, podLister listers.PodLister) *Server {
+	return &Server{
+		kubeClient: kubeClient,
+		podLister:  podLister,
+		containers: map[string]*container{},
+		exitCodes:  map[string]int{},
+		finishedAt: map[string]time.Time{},
+	}
+}
+
+// Run serves the Docker API to the services' sidecars. It only listens on
+// listenIP, which should be the node controller's pod IP, since that's the
+// address that the sidecars connect to.
+func (s *Server) Run(listenIP string) error {
+	if listenIP == "" {
+		return errors.New("listen IP is required")
+	}
+
+	addr := net.JoinHostPort(listenIP, strconv.Itoa(Port))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.WithContext("listen", err)
+	}
+
+	go s.pruneContainers()
+
+	log.WithField("address", addr).Info("Listening for Docker API requests")
+	return http.Serve(ln, s)
+}
+
+type handler func(w http.ResponseWriter, r *http.Request, caller *corev1.Pod, args []string)
+
+type route struct {
+	method  string
+	path    *regexp.Regexp
+	handler handler
+}
+
+// versionPrefix matches the optional API version that clients prefix paths
+// with, such as `/v1.40/containers/json`.
+var versionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+func (s *Server) routes() []route {
+	r := func(method, path string, h handler) route {
+		return route{method, regexp.MustCompile("^" + path + "$"), h}
+	}
+
+	return []route{
+		r(http.MethodGet, "/_ping", s.ping),
+		r(http.MethodHead, "/_ping", s.ping),
+		r(http.MethodGet, "/version", s.version),
+		r(http.MethodGet, "/info", s.info),
+		r(http.MethodPost, "/images/create", s.pullImage),
+		r(http.MethodGet, "/images/(.+)/json", s.inspectImage),
+		r(http.MethodGet, "/containers/json", s.listContainers),
+		r(http.MethodPost, "/containers/create", s.createContainer),
+		r(http.MethodGet, "/containers/([^/]+)/json", s.inspectContainer),
+		r(http.MethodPost, "/containers/([^/]+)/start", s.startContainer),
+		r(http.MethodPost, "/containers/([^/]+)/(?:stop|kill)", s.stopContainer),
+		r(http.MethodPost, "/containers/([^/]+)/wait", s.waitContainer),
+		r(http.MethodGet, "/containers/([^/]+)/logs", s.containerLogs),
+		r(http.MethodDelete, "/containers/([^/]+)", s.removeContainer),
+		r(http.MethodGet, PublishedPortsPath, s.publishedPorts),
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	caller, err := s.getCaller(r)
+	if err != nil {
+		log.WithError(err).WithField("remote", r.RemoteAddr).Debug("Rejected Docker API request")
+		writeError(w, http.StatusForbidden, errors.NewFriendlyError(
+			"The Docker socket isn't enabled for this service. Set `x-blimp.docker_socket` to enable it."))
+		return
+	}
+
+	path := versionPrefix.ReplaceAllString(r.URL.Path, "")
+	for _, route := range s.routes() {
+		if route.method != r.Method {
+			continue
+		}
+
+		match := route.path.FindStringSubmatch(path)
+		if match != nil {
+			w.Header().Set("Api-Version", apiVersion)
+			route.handler(w, r, caller, match[1:])
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotImplemented, errors.NewFriendlyError(
+		"%s %s isn't supported by Blimp's Docker socket", r.Method, path))
+}
+
+// getCaller returns the pod that sent the request. Requests from pods that
+// didn't enable the Docker socket are rejected.
+func (s *Server) getCaller(r *http.Request) (*corev1.Pod, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil, errors.WithContext("parse remote address", err)
+	}
+
+	pods, err := s.podLister.List(labels.Set{kube.DockerSocketLabel: "true"}.AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("list pods", err)
+	}
+
+	for _, pod := range pods {
+		if pod.Status.PodIP == host {
+			return pod, nil
+		}
+	}
+	return nil, errors.New("no pod with IP %s has the Docker socket enabled", host)
+}
+
+func (s *Server) ping(w http.ResponseWriter, _ *http.Request, _ *corev1.Pod, _ []string) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("OSType", "linux")
+	fmt.Fprint(w, "OK")
+}
+
+func (s *Server) version(w http.ResponseWriter, _ *http.Request, _ *corev1.Pod, _ []string) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"Version":       "19.03.0-blimp",
+		"ApiVersion":    apiVersion,
+		"MinAPIVersion": "1.12",
+		"Os":            "linux",
+		"Arch":          "amd64",
+	})
+}
+
+func (s *Server) info(w http.ResponseWriter, _ *http.Request, caller *corev1.Pod, _ []string) {
+	containers, err := s.listOwned(caller)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var running int
+	for _, c := range containers {
+		if c.status.state.Running {
+			running++
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"ID":                caller.Namespace,
+		"Name":              caller.Name,
+		"Containers":        len(containers),
+		"ContainersRunning": running,
+		"OperatingSystem":   "Blimp",
+		"OSType":            "linux",
+		"Architecture":      "x86_64",
+		"ServerVersion":     "19.03.0-blimp",
+		"Labels":            []string{},
+	})
+}
+
+// pullImage is a no-op since images are pulled when the container's pod
+// boots.
+func (s *Server) pullImage(w http.ResponseWriter, r *http.Request, _ *corev1.Pod, _ []string) {
+	image := r.URL.Query().Get("fromImage")
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		image += ":" + tag
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status": fmt.Sprintf("%s will be pulled when the container starts", image),
+	})
+}
+
+// inspectImage always reports that the image exists, so that clients don't
+// try to pull it.
+func (s *Server) inspectImage(w http.ResponseWriter, _ *http.Request, _ *corev1.Pod, args []string) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"Id":       args[0],
+		"RepoTags": []string{args[0]},
+		"Os":       "linux",
+		"Config":   map[string]interface{}{},
+	})
+}
+
+func (s *Server) listContainers(w http.ResponseWriter, r *http.Request, caller *corev1.Pod, _ []string) {
+	containers, err := s.listOwned(caller)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	summaries := []containerSummary{}
+	for _, c := range containers {
+		if all || c.status.state.Running {
+			summaries = append(summaries, c.summary(c.status))
+		}
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) createContainer(w http.ResponseWriter, r *http.Request, caller *corev1.Pod, _ []string) {
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.WithContext("parse request", err))
+		return
+	}
+
+	containers, err := s.listOwned(caller)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	usedHostPorts := map[int]bool{}
+	for _, c := range containers {
+		if name != "" && c.Name == name {
+			writeError(w, http.StatusConflict, errors.NewFriendlyError(
+				"The container name %q is already in use", name))
+			return
+		}
+
+		for _, hostPort := range c.HostPorts {
+			usedHostPorts[hostPort] = true
+		}
+	}
+
+	if len(containers) >= maxContainers {
+		writeError(w, http.StatusConflict, errors.NewFriendlyError(
+			"Services can only have %d containers at once. Remove some containers first.", maxContainers))
+		return
+	}
+
+	c, err := newContainer(caller, name, req, usedHostPorts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.lock.Lock()
+	s.containers[c.ID] = c
+	s.lock.Unlock()
+
+	log.WithField("namespace", caller.Namespace).WithField("container", c.ID).Info("Created container")
+	writeJSON(w, http.StatusCreated, createResponse{ID: c.ID, Warnings: []string{}})
+}
+
+func (s *Server) inspectContainer(w http.ResponseWriter, _ *http.Request, caller *corev1.Pod, args []string) {
+	c, ok := s.find(w, caller, args[0])
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, c.inspect(c.status))
+}
+
+func (s *Server) startContainer(w http.ResponseWriter, r *http.Request, caller *corev1.Pod, args []string) {
+	c, ok := s.find(w, caller, args[0])
+	if !ok {
+		return
+	}
+
+	if c.pod != nil {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	s.lock.Lock()
+	_, stopped := s.exitCodes[c.ID]
+	s.lock.Unlock()
+	if stopped {
+		writeError(w, http.StatusBadRequest, errors.NewFriendlyError(
+			"Stopped containers can't be restarted. Create a new container instead."))
+		return
+	}
+
+	pod, err := c.toPod(caller)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	pod, err = kube.PatchSandboxPod(pod)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.WithContext("patch pod", err))
+		return
+	}
+
+	if _, err := s.kubeClient.CoreV1().Pods(pod.Namespace).Create(&pod); err != nil {
+		writeError(w, http.StatusInternalServerError, errors.WithContext("create pod", err))
+		return
+	}
+
+	// The pod now tracks the container.
+	s.lock.Lock()
+	delete(s.containers, c.ID)
+	s.lock.Unlock()
+
+	// Like `docker start`, block until the container is running.
+	ctx, cancel := context.WithTimeout(r.Context(), startTimeout)
+	defer cancel()
+	if err := s.waitForStart(ctx, pod.Namespace, pod.Name); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// waitForStart blocks until the pod's container starts running, or exits.
+func (s *Server) waitForStart(ctx context.Context, namespace, name string) error {
+	for {
+		pod, err := s.kubeClient.CoreV1().Pods(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return errors.WithContext("get pod", err)
+		}
+
+		for _, cs := range pod.Status.ContainerStatuses {
+			switch {
+			case cs.State.Running != nil, cs.State.Terminated != nil:
+				return nil
+			case cs.State.Waiting != nil && isPullError(cs.State.Waiting.Reason):
+				return errors.NewFriendlyError("Failed to pull image: %s", cs.State.Waiting.Message)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.NewFriendlyError("Timed out waiting for container to start")
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func isPullError(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+		return true
+	}
+	return false
+}
+
+func (s *Server) stopContainer(w http.ResponseWriter, _ *http.Request, caller *corev1.Pod, args []string) {
+	c, ok := s.find(w, caller, args[0])
+	if !ok {
+		return
+	}
+
+	if c.pod == nil {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// Record the status before deleting the pod, so that the client can
+	// still inspect the container.
+	exitCode := 137
+	if c.status.state.Status == "exited" {
+		exitCode = c.status.state.ExitCode
+	}
+
+	s.lock.Lock()
+	s.containers[c.ID] = c.container
+	s.exitCodes[c.ID] = exitCode
+	s.finishedAt[c.ID] = time.Now()
+	s.lock.Unlock()
+
+	if err := kube.DeletePod(s.kubeClient, c.pod.Namespace, c.pod.Name); err != nil {
+		writeError(w, http.StatusInternalServerError, errors.WithContext("delete pod", err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) waitContainer(w http.ResponseWriter, r *http.Request, caller *corev1.Pod, args []string) {
+	c, ok := s.find(w, caller, args[0])
+	if !ok {
+		return
+	}
+
+	for c.status.state.Status != "exited" {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(time.Second):
+		}
+
+		var err error
+		c, err = s.get(caller, c.ID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// The container was removed while waiting.
+		if c == nil {
+			writeJSON(w, http.StatusOK, waitResponse{StatusCode: 137})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, waitResponse{StatusCode: c.status.state.ExitCode})
+}
+
+func (s *Server) containerLogs(w http.ResponseWriter, r *http.Request, caller *corev1.Pod, args []string) {
+	c, ok := s.find(w, caller, args[0])
+	if !ok {
+		return
+	}
+
+	if c.pod == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	query := r.URL.Query()
+	opts := corev1.PodLogOptions{}
+	opts.Follow, _ = strconv.ParseBool(query.Get("follow"))
+	opts.Timestamps, _ = strconv.ParseBool(query.Get("timestamps"))
+	if tail, err := strconv.ParseInt(query.Get("tail"), 10, 64); err == nil {
+		opts.TailLines = &tail
+	}
+
+	stream, err := s.kubeClient.CoreV1().Pods(c.pod.Namespace).GetLogs(c.pod.Name, &opts).
+		Context(r.Context()).Stream()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.WithContext("get logs", err))
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "application/vnd.docker.raw-stream")
+	w.WriteHeader(http.StatusOK)
+
+	out := io.Writer(w)
+	if !c.Config.Tty {
+		out = multiplexedWriter{w}
+	}
+
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := stream.Read(buf)
+		if n > 0 {
+			if _, err := out.Write(buf[:n]); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// multiplexedWriter frames the logs in the format Docker uses for containers
+// without a TTY. Kubernetes doesn't distinguish between stdout and stderr, so
+// all the logs are sent as stdout.
+type multiplexedWriter struct {
+	w io.Writer
+}
+
+func (mw multiplexedWriter) Write(p []byte) (int, error) {
+	header := make([]byte, 8)
+	header[0] = 1
+	binary.BigEndian.PutUint32(header[4:], uint32(len(p)))
+	if _, err := mw.w.Write(header); err != nil {
+		return 0, err
+	}
+	return mw.w.Write(p)
+}
+
+func (s *Server) removeContainer(w http.ResponseWriter, _ *http.Request, caller *corev1.Pod, args []string) {
+	c, ok := s.find(w, caller, args[0])
+	if !ok {
+		return
+	}
+
+	if c.pod != nil {
+		err := kube.DeletePod(s.kubeClient, c.pod.Namespace, c.pod.Name)
+		if err != nil && !kerrors.IsNotFound(err) {
+			writeError(w, http.StatusInternalServerError, errors.WithContext("delete pod", err))
+			return
+		}
+	}
+
+	s.lock.Lock()
+	delete(s.containers, c.ID)
+	delete(s.exitCodes, c.ID)
+	delete(s.finishedAt, c.ID)
+	s.lock.Unlock()
+
+	log.WithField("namespace", caller.Namespace).WithField("container", c.ID).Info("Removed container")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) publishedPorts(w http.ResponseWriter, _ *http.Request, caller *corev1.Pod, _ []string) {
+	containers, err := s.listOwned(caller)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	ports := []PublishedPort{}
+	for _, c := range containers {
+		if !c.status.state.Running || c.status.ip == "" {
+			continue
+		}
+
+		for port, hostPort := range c.HostPorts {
+			containerPort, err := parsePort(port)
+			if err != nil {
+				continue
+			}
+
+			ports = append(ports, PublishedPort{
+				HostPort:      hostPort,
+				ContainerIP:   c.status.ip,
+				ContainerPort: containerPort,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, ports)
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.WithError(err).Debug("Failed to write Docker API response")
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, errorResponse{Message: errors.GetPrintableMessage(err)})
+}
@@ -0,0 +1,158 @@
+package dockershim
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+	listers "k8s.io/client-go/listers/core/v1"
+	kubeTesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+func TestServer(t *testing.T) {
+	gvisor := "gvisor"
+	owner := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tests",
+			Namespace: "sandbox",
+			UID:       "owner-uid",
+			Labels:    map[string]string{kube.DockerSocketLabel: "true"},
+		},
+		Spec:   corev1.PodSpec{RuntimeClassName: &gvisor},
+		Status: corev1.PodStatus{PodIP: "10.0.0.2"},
+	}
+	other := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "sandbox", UID: "other-uid"},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.3"},
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, indexer.Add(owner))
+	require.NoError(t, indexer.Add(other))
+
+	// Mark pods as running as soon as they're created so that starting
+	// containers doesn't block.
+	kubeClient := fakeKube.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "pods",
+		func(action kubeTesting.Action) (bool, runtime.Object, error) {
+			pod := action.(kubeTesting.CreateAction).GetObject().(*corev1.Pod)
+			pod.Status.PodIP = "10.0.0.10"
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			}
+			return false, nil, nil
+		})
+
+	s := NewServer(kubeClient, listers.NewPodLister(indexer))
+	request := func(remoteIP, method, path string, body interface{}) *httptest.ResponseRecorder {
+		var bodyReader bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&bodyReader).Encode(body))
+		}
+
+		req := httptest.NewRequest(method, path, &bodyReader)
+		req.RemoteAddr = remoteIP + ":41234"
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w
+	}
+
+	// Pods that didn't enable the Docker socket can't use it.
+	assert.Equal(t, http.StatusForbidden, request(other.Status.PodIP, http.MethodGet, "/_ping", nil).Code)
+	assert.Equal(t, http.StatusOK, request(owner.Status.PodIP, http.MethodGet, "/v1.40/_ping", nil).Code)
+	assert.Equal(t, http.StatusNotImplemented,
+		request(owner.Status.PodIP, http.MethodPost, "/v1.40/containers/prune", nil).Code)
+
+	// Settings that would give the container access to the node are
+	// rejected.
+	w := request(owner.Status.PodIP, http.MethodPost, "/containers/create", createRequest{
+		containerConfig: containerConfig{Image: "alpine"},
+		HostConfig:      hostConfig{Privileged: true},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = request(owner.Status.PodIP, http.MethodPost, "/containers/create?name=db", createRequest{
+		containerConfig: containerConfig{Image: "postgres", User: "999"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created createResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+
+	// Container names are unique.
+	w = request(owner.Status.PodIP, http.MethodPost, "/containers/create?name=db", createRequest{
+		containerConfig: containerConfig{Image: "postgres"},
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Created containers are only listed with `all`.
+	listContainers := func(query string) []containerSummary {
+		w := request(owner.Status.PodIP, http.MethodGet, "/containers/json"+query, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var summaries []containerSummary
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&summaries))
+		return summaries
+	}
+	assert.Len(t, listContainers(""), 0)
+	if summaries := listContainers("?all=1"); assert.Len(t, summaries, 1) {
+		assert.Equal(t, created.ID, summaries[0].ID)
+		assert.Equal(t, "created", summaries[0].State)
+	}
+
+	// Other pods can't see the container.
+	require.NoError(t, indexer.Update(func() *corev1.Pod {
+		updated := other.DeepCopy()
+		updated.Labels = map[string]string{kube.DockerSocketLabel: "true"}
+		return updated
+	}()))
+	assert.Equal(t, http.StatusNotFound,
+		request(other.Status.PodIP, http.MethodGet, "/containers/db/json", nil).Code)
+
+	w = request(owner.Status.PodIP, http.MethodPost, "/containers/db/start", nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	pod, err := kubeClient.CoreV1().Pods("sandbox").Get("docker-"+created.ID[:12], metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &gvisor, pod.Spec.RuntimeClassName)
+	if assert.NotNil(t, pod.Spec.Containers[0].SecurityContext) {
+		assert.Equal(t, int64(999), *pod.Spec.Containers[0].SecurityContext.RunAsUser)
+	}
+
+	// Once started, the container is tracked by its pod.
+	require.NoError(t, indexer.Add(pod))
+	w = request(owner.Status.PodIP, http.MethodGet, "/containers/"+created.ID[:6]+"/json", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var inspected containerJSON
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&inspected))
+	assert.True(t, inspected.State.Running)
+	assert.Equal(t, "10.0.0.10", inspected.NetworkSettings.IPAddress)
+
+	// Stopped containers keep their exit status until they're removed.
+	w = request(owner.Status.PodIP, http.MethodPost, "/containers/db/stop", nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.NoError(t, indexer.Delete(pod))
+
+	w = request(owner.Status.PodIP, http.MethodPost, "/containers/db/wait", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var waited waitResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&waited))
+	assert.Equal(t, 137, waited.StatusCode)
+
+	assert.Equal(t, http.StatusBadRequest,
+		request(owner.Status.PodIP, http.MethodPost, "/containers/db/start", nil).Code)
+
+	assert.Equal(t, http.StatusNoContent,
+		request(owner.Status.PodIP, http.MethodDelete, "/containers/db", nil).Code)
+	assert.Len(t, listContainers("?all=1"), 0)
+}
@@ -0,0 +1,127 @@
+package dockershim
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// ownedContainer is a container along with its current status. pod is nil
+// if the container isn't running in a pod.
+type ownedContainer struct {
+	*container
+	pod    *corev1.Pod
+	status status
+}
+
+// listOwned returns the containers created by the caller, sorted by when
+// they were created.
+func (s *Server) listOwned(caller *corev1.Pod) ([]*ownedContainer, error) {
+	pods, err := s.podLister.Pods(caller.Namespace).List(
+		labels.Set{kube.DockerOwnerLabel: string(caller.UID)}.AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("list pods", err)
+	}
+
+	containers := map[string]*ownedContainer{}
+	for _, pod := range pods {
+		c, err := containerFromPod(pod)
+		if err != nil {
+			log.WithError(err).WithField("pod", pod.Name).Warn("Failed to parse container")
+			continue
+		}
+		containers[c.ID] = &ownedContainer{container: c, pod: pod, status: podStatus(pod)}
+	}
+
+	// Containers tracked in memory take precedence, since the pods of
+	// stopped containers may still be terminating.
+	s.lock.Lock()
+	for id, c := range s.containers {
+		if c.Owner != string(caller.UID) {
+			continue
+		}
+
+		oc := &ownedContainer{container: c, status: createdStatus}
+		if exitCode, ok := s.exitCodes[id]; ok {
+			oc.status = exitedStatus(exitCode, s.finishedAt[id])
+		}
+		containers[id] = oc
+	}
+	s.lock.Unlock()
+
+	var sorted []*ownedContainer
+	for _, c := range containers {
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Created.Before(sorted[j].Created)
+	})
+	return sorted, nil
+}
+
+// get returns the caller's container that's referenced by `ref`, or nil if
+// it doesn't exist.
+func (s *Server) get(caller *corev1.Pod, ref string) (*ownedContainer, error) {
+	containers, err := s.listOwned(caller)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range containers {
+		if c.matches(ref) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+// find is like get, except that it writes the error response if the
+// container can't be found.
+func (s *Server) find(w http.ResponseWriter, caller *corev1.Pod, ref string) (*ownedContainer, bool) {
+	c, err := s.get(caller, ref)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	if c == nil {
+		writeError(w, http.StatusNotFound, errors.NewFriendlyError("No such container: %s", ref))
+		return nil, false
+	}
+	return c, true
+}
+
+// pruneContainers forgets the containers tracked in memory once the pods
+// that created them are gone. Containers backed by pods don't need to be
+// pruned since Kubernetes deletes them along with their owner.
+func (s *Server) pruneContainers() {
+	for range time.Tick(time.Minute) {
+		owners := map[types.UID]struct{}{}
+		pods, err := s.podLister.List(labels.Set{kube.DockerSocketLabel: "true"}.AsSelector())
+		if err != nil {
+			log.WithError(err).Warn("Failed to list pods")
+			continue
+		}
+		for _, pod := range pods {
+			owners[pod.UID] = struct{}{}
+		}
+
+		s.lock.Lock()
+		for id, c := range s.containers {
+			if _, ok := owners[types.UID(c.Owner)]; !ok {
+				delete(s.containers, id)
+				delete(s.exitCodes, id)
+				delete(s.finishedAt, id)
+			}
+		}
+		s.lock.Unlock()
+	}
+}
@@ -0,0 +1,141 @@
+package dockershim
+
+import (
+	"encoding/json"
+)
+
+// The types in this file mirror the subset of the Docker Engine API that the
+// shim implements. Fields that the shim doesn't support are omitted, and
+// ignored if sent by the client.
+
+type containerConfig struct {
+	Image        string              `json:"Image"`
+	Cmd          strSlice            `json:"Cmd,omitempty"`
+	Entrypoint   strSlice            `json:"Entrypoint,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	WorkingDir   string              `json:"WorkingDir,omitempty"`
+	User         string              `json:"User,omitempty"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	Tty          bool                `json:"Tty,omitempty"`
+}
+
+type createRequest struct {
+	containerConfig
+	HostConfig hostConfig `json:"HostConfig"`
+}
+
+// hostConfig contains the host settings that the shim checks for. Most of
+// them give the container access to the node, and are rejected.
+type hostConfig struct {
+	Privileged   bool                     `json:"Privileged"`
+	NetworkMode  string                   `json:"NetworkMode"`
+	PidMode      string                   `json:"PidMode"`
+	IpcMode      string                   `json:"IpcMode"`
+	Binds        []string                 `json:"Binds"`
+	Mounts       []json.RawMessage        `json:"Mounts"`
+	Devices      []json.RawMessage        `json:"Devices"`
+	CapAdd       []string                 `json:"CapAdd"`
+	PortBindings map[string][]portBinding `json:"PortBindings"`
+}
+
+type portBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// strSlice is a list of strings that may also be sent as a single string.
+type strSlice []string
+
+func (s *strSlice) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		*s = strSlice{str}
+		return nil
+	}
+
+	var strs []string
+	if err := json.Unmarshal(b, &strs); err != nil {
+		return err
+	}
+	*s = strs
+	return nil
+}
+
+type createResponse struct {
+	ID       string   `json:"Id"`
+	Warnings []string `json:"Warnings"`
+}
+
+type containerJSON struct {
+	ID              string          `json:"Id"`
+	Created         string          `json:"Created"`
+	Path            string          `json:"Path"`
+	Args            []string        `json:"Args"`
+	State           containerState  `json:"State"`
+	Image           string          `json:"Image"`
+	Name            string          `json:"Name"`
+	Config          containerConfig `json:"Config"`
+	NetworkSettings networkSettings `json:"NetworkSettings"`
+}
+
+type containerState struct {
+	Status     string `json:"Status"`
+	Running    bool   `json:"Running"`
+	Paused     bool   `json:"Paused"`
+	Restarting bool   `json:"Restarting"`
+	OOMKilled  bool   `json:"OOMKilled"`
+	Dead       bool   `json:"Dead"`
+	Pid        int    `json:"Pid"`
+	ExitCode   int    `json:"ExitCode"`
+	Error      string `json:"Error"`
+	StartedAt  string `json:"StartedAt"`
+	FinishedAt string `json:"FinishedAt"`
+}
+
+type networkSettings struct {
+	IPAddress string                     `json:"IPAddress"`
+	Ports     map[string][]portBinding   `json:"Ports"`
+	Networks  map[string]endpointSetting `json:"Networks"`
+}
+
+type endpointSetting struct {
+	IPAddress string `json:"IPAddress"`
+}
+
+type containerSummary struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	Command string            `json:"Command"`
+	Created int64             `json:"Created"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+	Labels  map[string]string `json:"Labels"`
+	Ports   []summaryPort     `json:"Ports"`
+}
+
+type summaryPort struct {
+	IP          string `json:"IP"`
+	PrivatePort int    `json:"PrivatePort"`
+	PublicPort  int    `json:"PublicPort"`
+	Type        string `json:"Type"`
+}
+
+// PublishedPort is returned by PublishedPortsPath. The sidecar in the
+// service's pod listens on HostPort, and forwards connections to
+// ContainerPort on ContainerIP, so that published ports are reachable on
+// localhost like they are with Docker.
+type PublishedPort struct {
+	HostPort      int    `json:"hostPort"`
+	ContainerIP   string `json:"containerIP"`
+	ContainerPort int    `json:"containerPort"`
+}
+
+type waitResponse struct {
+	StatusCode int `json:"StatusCode"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
@@ -10,13 +10,18 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 
+	"github.com/kelda/blimp/node/dockershim"
+	"github.com/kelda/blimp/node/sshagent"
 	"github.com/kelda/blimp/node/wait"
 	"github.com/kelda/blimp/pkg/auth"
 	"github.com/kelda/blimp/pkg/errors"
@@ -24,6 +29,7 @@ import (
 	"github.com/kelda/blimp/pkg/kube"
 	"github.com/kelda/blimp/pkg/names"
 	"github.com/kelda/blimp/pkg/ports"
+	authProto "github.com/kelda/blimp/pkg/proto/auth"
 	"github.com/kelda/blimp/pkg/proto/node"
 	"github.com/kelda/blimp/pkg/tunnel"
 
@@ -34,6 +40,16 @@ import (
 const (
 	CertPath = "/etc/blimp/certs/cert.pem"
 	KeyPath  = "/etc/blimp/certs/key.pem"
+
+	// heartbeatInterval is how long a client connection can be idle before
+	// it's pinged. Connections whose clients don't respond within
+	// heartbeatTimeout are closed, which tears down their tunnels.
+	heartbeatInterval = 30 * time.Second
+	heartbeatTimeout  = 60 * time.Second
+
+	// tunnelDestinationTimeout is how long tunnels wait for the destination
+	// pod to boot, such as while a service is being updated.
+	tunnelDestinationTimeout = 30 * time.Second
 )
 
 func main() {
@@ -55,6 +71,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := loadPodPatches(); err != nil {
+		log.WithError(err).Error("Failed to load pod patches")
+		os.Exit(1)
+	}
+
 	syncTracker := wait.NewSyncTracker()
 	go wait.Run(kubeClient, syncTracker)
 
@@ -70,10 +91,25 @@ func main() {
 	go nsInformer.Informer().Run(nil)
 	cache.WaitForCacheSync(nil, nsInformer.Informer().HasSynced)
 
+	sshAgentForwarder := sshagent.NewForwarder(podInformer.Lister())
+	go func() {
+		if err := sshAgentForwarder.Run(); err != nil {
+			log.WithError(err).Error("SSH agent forwarder crashed")
+		}
+	}()
+
+	dockerShim := dockershim.NewServer(kubeClient, podInformer.Lister())
+	go func() {
+		if err := dockerShim.Run(os.Getenv("POD_IP")); err != nil {
+			log.WithError(err).Error("Docker API shim crashed")
+		}
+	}()
+
 	s := &server{
-		syncTracker: syncTracker,
-		podLister:   podInformer.Lister(),
-		nsLister:    nsInformer.Lister(),
+		syncTracker:       syncTracker,
+		sshAgentForwarder: sshAgentForwarder,
+		podLister:         podInformer.Lister(),
+		nsLister:          nsInformer.Lister(),
 	}
 	addr := fmt.Sprintf("0.0.0.0:%d", ports.NodeControllerInternalPort)
 	if err := s.listenAndServe(addr); err != nil {
@@ -83,9 +119,10 @@ func main() {
 }
 
 type server struct {
-	syncTracker *wait.SyncTracker
-	podLister   listers.PodLister
-	nsLister    listers.NamespaceLister
+	syncTracker       *wait.SyncTracker
+	sshAgentForwarder *sshagent.Forwarder
+	podLister         listers.PodLister
+	nsLister          listers.NamespaceLister
 }
 
 func (s *server) listenAndServe(address string) error {
@@ -100,11 +137,46 @@ func (s *server) listenAndServe(address string) error {
 	}
 
 	log.WithField("address", address).Info("Listening for connections..")
-	grpcServer := grpc.NewServer(grpc.Creds(creds), grpc.UnaryInterceptor(errors.UnaryServerInterceptor))
+	grpcServer := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.UnaryInterceptor(errors.UnaryServerInterceptor),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    heartbeatInterval,
+			Timeout: heartbeatTimeout,
+		}),
+		// The CLI pings every 30 seconds while it has streams open.
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             20 * time.Second,
+			PermitWithoutStream: true,
+		}))
 	node.RegisterControllerServer(grpcServer, s)
 	return grpcServer.Serve(lis)
 }
 
+// authorize validates the user's credentials, and checks that the user owns
+// their namespace. Users whose namespace collides with another user's are
+// rejected so that they can't access the other user's sandbox.
+func (s *server) authorize(blimpAuth *authProto.BlimpAuth) (auth.User, error) {
+	user, err := auth.AuthorizeRequest(blimpAuth)
+	if err != nil {
+		return auth.User{}, err
+	}
+
+	owner, err := kube.NamespaceOwner(s.nsLister, user.Namespace)
+	if err != nil {
+		return auth.User{}, errors.WithContext("check namespace owner", err)
+	}
+
+	if owner != "" && owner != user.Name {
+		log.WithField("namespace", user.Namespace).
+			WithField("owner", owner).
+			WithField("user", user.Name).
+			Warn("Rejected user whose namespace collides with another user's")
+		return auth.User{}, kube.NamespaceCollisionError(user.Namespace)
+	}
+	return user, nil
+}
+
 func (s *server) Tunnel(nsrv node.Controller_TunnelServer) error {
 	msg, err := nsrv.Recv()
 	if err != nil {
@@ -116,7 +188,7 @@ func (s *server) Tunnel(nsrv node.Controller_TunnelServer) error {
 		return status.New(codes.Internal, "first message must be a header").Err()
 	}
 
-	user, err := auth.AuthorizeRequest(auth.GetAuth(header))
+	user, err := s.authorize(auth.GetAuth(header))
 	if err != nil {
 		return errors.WithContext("bad token", err)
 	}
@@ -131,7 +203,7 @@ func (s *server) Tunnel(nsrv node.Controller_TunnelServer) error {
 		podName = names.ToDNS1123(header.Name)
 	}
 
-	dstPod, err := s.podLister.Pods(user.Namespace).Get(podName)
+	dstPod, err := s.getTunnelDestination(user.Namespace, podName, header.Name)
 	if err != nil {
 		return status.New(codes.OutOfRange, "unknown destination").Err()
 	}
@@ -175,13 +247,13 @@ func (s *server) ExposedTunnel(nsrv node.Controller_ExposedTunnelServer) error {
 	}
 
 	info, ok := annotation[header.Token]
-	if !ok {
+	if !ok || info.Expired(time.Now()) {
 		return status.New(codes.OutOfRange, "unknown destination").Err()
 	}
 
 	podName := names.ToDNS1123(info.Service)
 
-	dstPod, err := s.podLister.Pods(header.Namespace).Get(podName)
+	dstPod, err := s.getTunnelDestination(header.Namespace, podName, info.Service)
 	if err != nil {
 		return status.New(codes.OutOfRange, "unknown destination").Err()
 	}
@@ -192,20 +264,121 @@ func (s *server) ExposedTunnel(nsrv node.Controller_ExposedTunnelServer) error {
 		return status.New(codes.Internal, err.Error()).Err()
 	}
 
+	// Connections through links that expire are closed when the link
+	// expires, rather than staying open for as long as they're in use. The
+	// tunnel is closed when this handler returns.
+	if info.ExpiresAt != 0 {
+		tunnel.ServerStreamUntil(nsrv, stream, time.Unix(info.ExpiresAt, 0))
+		return nil
+	}
 	tunnel.ServerStream(nsrv, stream)
 	return nil
 }
 
+// getTunnelDestination returns the pod that should receive the traffic for
+// the service. Services are briefly served by a surge pod while they're
+// updated, and have no pod at all while they're recreated. In the latter
+// case, getTunnelDestination waits for the new pod to boot so that tunnels
+// reconnect to it transparently.
+func (s *server) getTunnelDestination(namespace, podName, service string) (*corev1.Pod, error) {
+	deadline := time.Now().Add(tunnelDestinationTimeout)
+	for {
+		if pod, ok := s.findTunnelDestination(namespace, podName, service); ok {
+			return pod, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.New("no pod for %s", service)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (s *server) findTunnelDestination(namespace, podName, service string) (*corev1.Pod, bool) {
+	podLister := s.podLister.Pods(namespace)
+
+	// Surge pods are only ready while the service's pod is being replaced,
+	// so they take precedence.
+	surgePods, err := podLister.List(labels.SelectorFromSet(map[string]string{
+		"blimp.service": service,
+		"blimp.surge":   "true",
+	}))
+	if err == nil {
+		for _, pod := range surgePods {
+			if canReceiveTraffic(pod) && podIsReady(pod) {
+				return pod, true
+			}
+		}
+	}
+
+	pod, err := podLister.Get(podName)
+	if err == nil && canReceiveTraffic(pod) {
+		return pod, true
+	}
+	return nil, false
+}
+
+func canReceiveTraffic(pod *corev1.Pod) bool {
+	return pod.Status.PodIP != "" && pod.DeletionTimestamp == nil
+}
+
+func podIsReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *server) SyncNotifications(srv node.Controller_SyncNotificationsServer) error {
 	handshake, err := srv.Recv()
 	if err != nil {
 		return err
 	}
 
-	user, err := auth.AuthorizeRequest(auth.GetAuth(handshake))
+	user, err := s.authorize(auth.GetAuth(handshake))
 	if err != nil {
 		return errors.WithContext("validate token", err)
 	}
 
 	return s.syncTracker.RunServer(user.Namespace, srv)
 }
+
+func (s *server) ForwardSSHAgent(req *node.ForwardSSHAgentRequest,
+	srv node.Controller_ForwardSSHAgentServer) error {
+	user, err := s.authorize(req.GetAuth())
+	if err != nil {
+		return errors.WithContext("validate token", err)
+	}
+
+	return s.sshAgentForwarder.ServeCLI(user.Namespace, srv)
+}
+
+// SSHAgentTunnel connects a service to the user's SSH agent. The CLI opens a
+// tunnel for each connection announced by ForwardSSHAgent.
+func (s *server) SSHAgentTunnel(nsrv node.Controller_SSHAgentTunnelServer) error {
+	msg, err := nsrv.Recv()
+	if err != nil {
+		return err
+	}
+
+	header := msg.GetSshAgentHeader()
+	if header == nil {
+		return status.New(codes.Internal, "first message must be an SSH agent header").Err()
+	}
+
+	user, err := s.authorize(header.GetAuth())
+	if err != nil {
+		return errors.WithContext("bad token", err)
+	}
+
+	stream, tunneled, err := s.sshAgentForwarder.GetConn(user.Namespace, header.GetId())
+	if err != nil {
+		return status.New(codes.OutOfRange, "unknown connection").Err()
+	}
+	defer close(tunneled)
+
+	tunnel.ServerStream(nsrv, stream)
+	return nil
+}
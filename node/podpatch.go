@@ -0,0 +1,35 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+// loadPodPatches loads the sandbox pod patches that the cluster manager
+// publishes in kube.PodPatchesConfigMap, so that the pods created for the
+// emulated Docker socket are patched like the rest of the sandbox's pods.
+func loadPodPatches() error {
+	path, ok := os.LookupEnv("POD_PATCHES_PATH")
+	if !ok {
+		return nil
+	}
+
+	patchesYAML, err := ioutil.ReadFile(path)
+	if err != nil {
+		// The ConfigMap may not be mounted if the node controller was
+		// deployed by an older version of the cluster manager.
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.WithContext("read patches", err)
+	}
+
+	patches, err := kube.ParsePodPatches(patchesYAML)
+	if err != nil {
+		return err
+	}
+	return kube.SetSandboxPodPatches(patches)
+}
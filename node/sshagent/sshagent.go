@@ -0,0 +1,213 @@
+// Package sshagent forwards connections from services to the SSH agent on the
+// user's machine.
+//
+// Services that enable `x-blimp.ssh_agent` run a sidecar that listens on a
+// Unix socket, and forwards each connection to the node controller. The node
+// controller identifies the service by the connection's source IP, and asks
+// the CLI for that sandbox to open a tunnel to the user's local agent. Only
+// services that enabled forwarding can reach the agent.
+package sshagent
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/proto/node"
+)
+
+const (
+	// Port is the port that the sidecars in the services connect to.
+	Port = 9003
+
+	// tunnelTimeout is how long a service's connection waits for the CLI
+	// to open a tunnel to the agent.
+	tunnelTimeout = 10 * time.Second
+)
+
+// Forwarder matches connections from services to the tunnels opened by the
+// CLI.
+type Forwarder struct {
+	podLister listers.PodLister
+
+	// clis maps namespaces to the stream connected to its CLI. pending maps
+	// connection IDs to the service connections waiting for a tunnel. Both
+	// are protected by `lock`.
+	clis    map[string]*cliConn
+	pending map[string]pendingConn
+	lock    sync.Mutex
+}
+
+type cliConn struct {
+	srv      node.Controller_ForwardSSHAgentServer
+	sendLock sync.Mutex
+}
+
+type pendingConn struct {
+	namespace string
+	conn      net.Conn
+	tunneled  chan struct{}
+}
+
+func NewForwarder(podLister listers.PodLister) *Forwarder {
+	return &Forwarder{
+		podLister: podLister,
+		clis:      map[string]*cliConn{},
+		pending:   map[string]pendingConn{},
+	}
+}
+
+// Run accepts connections from the services' sidecars.
+func (f *Forwarder) Run() error {
+	addr := fmt.Sprintf("0.0.0.0:%d", Port)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.WithContext("listen", err)
+	}
+
+	log.WithField("address", addr).Info("Listening for SSH agent connections")
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return errors.WithContext("accept", err)
+		}
+
+		go func() {
+			if err := f.handleConn(conn); err != nil {
+				log.WithError(err).
+					WithField("remote", conn.RemoteAddr().String()).
+					Debug("Rejected SSH agent connection")
+				conn.Close()
+			}
+		}()
+	}
+}
+
+// ServeCLI registers the CLI's stream, and blocks until the CLI
+// disconnects.
+func (f *Forwarder) ServeCLI(namespace string, srv node.Controller_ForwardSSHAgentServer) error {
+	log.WithField("namespace", namespace).Info("CLI is forwarding its SSH agent")
+
+	cc := &cliConn{srv: srv}
+	f.lock.Lock()
+	f.clis[namespace] = cc
+	f.lock.Unlock()
+
+	<-srv.Context().Done()
+
+	// Don't delete the connection if it's already been replaced by another.
+	f.lock.Lock()
+	if f.clis[namespace] == cc {
+		delete(f.clis, namespace)
+	}
+	f.lock.Unlock()
+	return nil
+}
+
+// GetConn returns the service connection that the CLI opened a tunnel for.
+// The returned channel must be closed once the tunnel finishes.
+func (f *Forwarder) GetConn(namespace, id string) (net.Conn, chan<- struct{}, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	pending, ok := f.pending[id]
+	if !ok || pending.namespace != namespace {
+		return nil, nil, errors.New("unknown connection")
+	}
+	delete(f.pending, id)
+	return pending.conn, pending.tunneled, nil
+}
+
+func (f *Forwarder) handleConn(conn net.Conn) error {
+	pod, err := f.getSourcePod(conn)
+	if err != nil {
+		return err
+	}
+
+	f.lock.Lock()
+	cc, ok := f.clis[pod.Namespace]
+	f.lock.Unlock()
+	if !ok {
+		return errors.New("no CLI is forwarding its SSH agent for namespace %s", pod.Namespace)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return errors.WithContext("generate ID", err)
+	}
+
+	pending := pendingConn{
+		namespace: pod.Namespace,
+		conn:      conn,
+		tunneled:  make(chan struct{}),
+	}
+	f.lock.Lock()
+	f.pending[id] = pending
+	f.lock.Unlock()
+
+	cc.sendLock.Lock()
+	err = cc.srv.Send(&node.SSHAgentConnection{
+		Id:      id,
+		Service: pod.Labels["blimp.service"],
+	})
+	cc.sendLock.Unlock()
+
+	if err == nil {
+		select {
+		case <-pending.tunneled:
+			return nil
+		case <-time.After(tunnelTimeout):
+			err = errors.New("timed out waiting for tunnel")
+		}
+	}
+
+	// Give up on the connection, unless the CLI claimed it in the meantime.
+	f.lock.Lock()
+	_, stillPending := f.pending[id]
+	delete(f.pending, id)
+	f.lock.Unlock()
+	if !stillPending {
+		<-pending.tunneled
+		return nil
+	}
+	return err
+}
+
+// getSourcePod returns the pod that opened the connection. Connections from
+// pods that didn't enable SSH agent forwarding are rejected.
+func (f *Forwarder) getSourcePod(conn net.Conn) (*corev1.Pod, error) {
+	addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return nil, errors.New("unexpected address type")
+	}
+
+	pods, err := f.podLister.List(labels.Set{kube.SSHAgentLabel: "true"}.AsSelector())
+	if err != nil {
+		return nil, errors.WithContext("list pods", err)
+	}
+
+	for _, pod := range pods {
+		if pod.Status.PodIP == addr.IP.String() {
+			return pod, nil
+		}
+	}
+	return nil, errors.New("no pod with IP %s has SSH agent forwarding enabled", addr.IP)
+}
+
+func newID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,59 @@
+package wait
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+
+	"google.golang.org/grpc/peer"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/proto/wait"
+)
+
+// ReportVolumeInit records the progress of a pod's volume initialization in
+// the pod's annotations, where the cluster manager reads it when computing
+// the service's status. Only the pod itself may report its progress.
+func (s *server) ReportVolumeInit(ctx context.Context, req *wait.ReportVolumeInitRequest) (
+	*wait.ReportVolumeInitResponse, error) {
+	pod, err := s.podLister.Pods(req.GetNamespace()).Get(req.GetPod())
+	if err != nil {
+		return &wait.ReportVolumeInitResponse{Error: errors.Marshal(errors.WithContext("get pod", err))}, nil
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return &wait.ReportVolumeInitResponse{Error: errors.Marshal(errors.New("unknown caller"))}, nil
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil || pod.Status.PodIP == "" || host != pod.Status.PodIP {
+		return &wait.ReportVolumeInitResponse{
+			Error: errors.Marshal(errors.New("progress can only be reported by the pod itself")),
+		}, nil
+	}
+
+	progress, err := metadata.VolumeInit(req.GetVolumes())
+	if err != nil {
+		return &wait.ReportVolumeInitResponse{Error: errors.Marshal(err)}, nil
+	}
+	if pod.Annotations[metadata.VolumeInitKey] == progress {
+		return &wait.ReportVolumeInitResponse{}, nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{metadata.VolumeInitKey: progress},
+		},
+	})
+	if err != nil {
+		return &wait.ReportVolumeInitResponse{Error: errors.Marshal(errors.WithContext("marshal patch", err))}, nil
+	}
+
+	_, err = s.kubeClient.CoreV1().Pods(pod.Namespace).Patch(pod.Name, types.MergePatchType, patch)
+	if err != nil {
+		return &wait.ReportVolumeInitResponse{Error: errors.Marshal(errors.WithContext("patch pod", err))}, nil
+	}
+	return &wait.ReportVolumeInitResponse{}, nil
+}
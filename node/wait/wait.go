@@ -26,6 +26,7 @@ import (
 )
 
 type server struct {
+	kubeClient  kubernetes.Interface
 	podInformer cache.SharedIndexInformer
 	podLister   listers.PodLister
 	podWatcher  *kube.Watcher
@@ -54,6 +55,7 @@ func Run(kubeClient kubernetes.Interface, syncTracker *SyncTracker) {
 	podInformer := informers.NewSharedInformerFactory(kubeClient, 30*time.Second).
 		Core().V1().Pods()
 	s := &server{
+		kubeClient:  kubeClient,
 		podInformer: podInformer.Informer(),
 		podLister:   podInformer.Lister(),
 		podWatcher:  kube.NewWatcher(podInformer.Informer()),
@@ -211,12 +213,16 @@ func (w podWaiter) wait(ctx context.Context, updates chan<- string) error {
 func conditionPodHealthy(pod corev1.Pod) (string, bool) {
 	// Make sure that all the pod's containers have passed their
 	// healthchecks. The healthchecks are configured at pod creation by the
-	// cluster manager.
+	// cluster manager. Services with a ready log pattern also have to log
+	// their ready line.
 	for _, container := range pod.Status.ContainerStatuses {
 		if !container.Ready {
 			return "not ready", false
 		}
 	}
+	if !kube.IsLogReady(pod) {
+		return "waiting for ready log", false
+	}
 	return "ready", true
 }
 
@@ -1,13 +1,23 @@
 package auth
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/kelda/blimp/pkg/names"
 )
 
 type User struct {
+	// Name is the identity that the user authenticated with.
+	Name      string
 	Namespace string
 }
 
+// previewTokenPrefix marks the tokens of sandboxes that were created to
+// preview pull requests.
+const previewTokenPrefix = "preview:"
+
 // Blimp used to use Auth0 for account management. Auth0 tokens were used to
 // identify and authorize users.
 // Blimp no longer does per-user authentication since only self-hosted clusters
@@ -15,5 +25,32 @@ type User struct {
 // control to the cluster is controlled via a shared secret. Therefore, we
 // don't do any validation on the token.
 func ParseIDToken(token string) (User, error) {
-	return User{Namespace: names.ToDNS1123(token)}, nil
+	if repo, number, ok := parsePreviewToken(token); ok {
+		return User{Name: token, Namespace: names.PreviewNamespace(repo, number)}, nil
+	}
+	return User{Name: token, Namespace: names.ToDNS1123(token)}, nil
+}
+
+// PreviewToken returns the token used by the preview sandbox for the given
+// pull request.
+func PreviewToken(repo string, number uint32) string {
+	return fmt.Sprintf("%s%s#%d", previewTokenPrefix, repo, number)
+}
+
+func parsePreviewToken(token string) (repo string, number uint32, ok bool) {
+	if !strings.HasPrefix(token, previewTokenPrefix) {
+		return "", 0, false
+	}
+
+	token = strings.TrimPrefix(token, previewTokenPrefix)
+	sep := strings.LastIndex(token, "#")
+	if sep == -1 {
+		return "", 0, false
+	}
+
+	parsed, err := strconv.ParseUint(token[sep+1:], 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	return token[:sep], uint32(parsed), true
 }
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIDToken(t *testing.T) {
+	user, err := ParseIDToken(PreviewToken("kelda/blimp", 42))
+	assert.NoError(t, err)
+	assert.Equal(t, User{Name: "preview:kelda/blimp#42", Namespace: "preview-kelda-blimp-pr-42"}, user)
+
+	user, err = ParseIDToken("preview:kelda/blimp")
+	assert.NoError(t, err)
+	assert.Equal(t, User{Name: "preview:kelda/blimp", Namespace: "previewkeldablimp-51485d4a99"}, user)
+}
+
+func TestParseScopedToken(t *testing.T) {
+	namespace, id, secret, ok := ParseScopedToken(ScopedToken("ns", "id", "secret"))
+	assert.True(t, ok)
+	assert.Equal(t, "ns", namespace)
+	assert.Equal(t, "id", id)
+	assert.Equal(t, "secret", secret)
+
+	_, _, _, ok = ParseScopedToken("scoped:ns:id")
+	assert.False(t, ok)
+
+	_, _, _, ok = ParseScopedToken("username")
+	assert.False(t, ok)
+}
+
+func TestShareToken(t *testing.T) {
+	key := []byte("key")
+	now := time.Unix(1000, 0)
+	claims := ShareClaims{Namespace: "ns", Scopes: []string{"status"}, ExpiresAt: 2000}
+
+	token, err := SignShareToken(key, claims)
+	assert.NoError(t, err)
+	assert.True(t, IsShareToken(token))
+
+	verified, err := VerifyShareToken(key, token, now)
+	assert.NoError(t, err)
+	assert.Equal(t, claims, verified)
+
+	// Tokens signed with a different key are rejected.
+	_, err = VerifyShareToken([]byte("other"), token, now)
+	assert.Error(t, err)
+
+	// Tampering with the claims invalidates the signature.
+	forged, err := SignShareToken([]byte("other"), ShareClaims{Namespace: "ns", Scopes: []string{"status"}, ExpiresAt: 9000})
+	assert.NoError(t, err)
+	sig := token[strings.LastIndex(token, "."):]
+	_, err = VerifyShareToken(key, forged[:strings.LastIndex(forged, ".")]+sig, now)
+	assert.Error(t, err)
+
+	// Expired tokens are rejected.
+	_, err = VerifyShareToken(key, token, time.Unix(2000, 0))
+	assert.Error(t, err)
+}
@@ -11,24 +11,60 @@ import (
 func AuthorizeRequest(blimpAuth *proto.BlimpAuth) (User, error) {
 	if clusterToken, ok := os.LookupEnv("BLIMP_CLUSTER_SECRET"); ok {
 		if subtle.ConstantTimeCompare([]byte(blimpAuth.GetClusterAuth()), []byte(clusterToken)) != 1 {
-			return User{}, errors.NewFriendlyError("You do not have authorization to access this cluster.")
+			return User{}, errors.NewCodedError(errors.CodeUnauthorized,
+				"You do not have authorization to access this cluster.")
 		}
 	}
 
+	// Scoped and share tokens that reach this point weren't verified, either
+	// because the RPC doesn't support them, or because they were sent to a
+	// server other than the cluster manager.
+	if IsScopedToken(blimpAuth.GetToken()) || IsShareToken(blimpAuth.GetToken()) {
+		return User{}, errors.NewCodedError(errors.CodeUnauthorized,
+			"Scoped tokens can't be used for this request.")
+	}
+
 	return ParseIDToken(blimpAuth.GetToken())
 }
 
+// AuthorizeAdminRequest checks that the given token matches the admin secret
+// configured for the cluster. Admin RPCs are disabled if no admin secret is
+// configured.
+func AuthorizeAdminRequest(adminToken string) error {
+	adminSecret, ok := os.LookupEnv("BLIMP_ADMIN_SECRET")
+	if !ok || adminSecret == "" {
+		return errors.NewFriendlyError("Admin commands are disabled on this cluster.\n" +
+			"Set BLIMP_ADMIN_SECRET on the Blimp manager to enable them.")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(adminToken), []byte(adminSecret)) != 1 {
+		return errors.NewFriendlyError("You do not have admin access to this cluster.")
+	}
+	return nil
+}
+
+// AuthenticatedRequest is implemented by the requests that contain
+// credentials.
 type AuthenticatedRequest interface {
-	GetOldToken() string
 	GetAuth() *proto.BlimpAuth
 }
 
+// legacyAuthenticatedRequest is implemented by the requests that also accept
+// the token field used by old versions of the CLI.
+type legacyAuthenticatedRequest interface {
+	GetOldToken() string
+}
+
 func GetAuth(req AuthenticatedRequest) *proto.BlimpAuth {
 	if req.GetAuth() != nil {
 		return req.GetAuth()
 	}
 
+	var oldToken string
+	if legacyReq, ok := req.(legacyAuthenticatedRequest); ok {
+		oldToken = legacyReq.GetOldToken()
+	}
 	return &proto.BlimpAuth{
-		Token: req.GetOldToken(),
+		Token: oldToken,
 	}
 }
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"strings"
+)
+
+// scopedTokenPrefix marks tokens created with `blimp token create`. Scoped
+// tokens are verified by the cluster manager, which swaps them for the
+// sandbox owner's token before handling the request.
+const scopedTokenPrefix = "scoped:"
+
+// ScopedToken returns the token for the scoped token with the given ID and
+// secret.
+func ScopedToken(namespace, id, secret string) string {
+	return scopedTokenPrefix + strings.Join([]string{namespace, id, secret}, ":")
+}
+
+// ParseScopedToken returns the components of a scoped token. `ok` is false
+// if the token isn't a scoped token.
+func ParseScopedToken(token string) (namespace, id, secret string, ok bool) {
+	if !IsScopedToken(token) {
+		return "", "", "", false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(token, scopedTokenPrefix), ":")
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+func IsScopedToken(token string) bool {
+	return strings.HasPrefix(token, scopedTokenPrefix)
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// shareTokenPrefix marks guest tokens created with `blimp share link`.
+// Unlike scoped tokens, share tokens aren't stored anywhere. They're signed
+// by the cluster manager, and are only valid until they expire.
+const shareTokenPrefix = "share:"
+
+// ShareClaims are the permissions granted by a share token.
+type ShareClaims struct {
+	Namespace string   `json:"ns"`
+	Scopes    []string `json:"scopes"`
+
+	// ExpiresAt is the Unix timestamp after which the token is rejected.
+	ExpiresAt int64 `json:"exp"`
+}
+
+// SignShareToken returns a share token for the given claims, signed with
+// `key`.
+func SignShareToken(key []byte, claims ShareClaims) (string, error) {
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.WithContext("marshal claims", err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(claimsJSON)
+	return shareTokenPrefix + payload + "." + signSharePayload(key, payload), nil
+}
+
+// VerifyShareToken checks the token's signature and expiration, and returns
+// the claims that it grants.
+func VerifyShareToken(key []byte, token string, now time.Time) (ShareClaims, error) {
+	invalidTokenErr := errors.NewCodedError(errors.CodeUnauthorized, "Invalid share token.")
+	if !IsShareToken(token) {
+		return ShareClaims{}, invalidTokenErr
+	}
+
+	parts := strings.Split(strings.TrimPrefix(token, shareTokenPrefix), ".")
+	if len(parts) != 2 {
+		return ShareClaims{}, invalidTokenErr
+	}
+
+	payload, sig := parts[0], parts[1]
+	if !hmac.Equal([]byte(sig), []byte(signSharePayload(key, payload))) {
+		return ShareClaims{}, invalidTokenErr
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return ShareClaims{}, invalidTokenErr
+	}
+
+	var claims ShareClaims
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return ShareClaims{}, invalidTokenErr
+	}
+
+	if now.Unix() >= claims.ExpiresAt {
+		return ShareClaims{}, errors.NewCodedError(errors.CodeUnauthorized,
+			"This share link expired at %s. Ask its owner for a new one.",
+			time.Unix(claims.ExpiresAt, 0).Format(time.RFC1123))
+	}
+	return claims, nil
+}
+
+func IsShareToken(token string) bool {
+	return strings.HasPrefix(token, shareTokenPrefix)
+}
+
+func signSharePayload(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -28,7 +28,9 @@ func New(tunnelManager tunnel.Manager, regCreds auth.RegistryCredentials) (build
 	tunnelErr := make(chan error)
 	tunnelReady := make(chan struct{})
 	go func() {
-		tunnelErr <- tunnelManager.Run("127.0.0.1", 1234, "buildkitd", 1234, tunnelReady)
+		// Build contexts can be large, so they shouldn't slow down the
+		// tunnels to services.
+		tunnelErr <- tunnelManager.Bulk().Run("127.0.0.1", 1234, "buildkitd", 1234, tunnelReady)
 	}()
 	select {
 	case err := <-tunnelErr:
@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	homedir "github.com/mitchellh/go-homedir"
@@ -15,11 +16,39 @@ import (
 type Config struct {
 	OptOutAnalytics bool `json:"opt_out_analytics"`
 
+	// AnalyticsEndpoint overrides where anonymous usage analytics are sent.
+	AnalyticsEndpoint string `json:"analytics_endpoint"`
+
 	ClusterToken string `json:"cluster_token"`
+	AdminToken   string `json:"admin_token"`
 
 	KubeHost    string `json:"kube_host"`
 	ManagerHost string `json:"manager_host"`
 	ManagerCert string `json:"manager_cert"`
+
+	// WebSocketURL is the URL of the manager's WebSocket tunnel, such as
+	// `wss://blimp.example.com/api/grpc-tunnel`. If it's set, connections
+	// that fail are retried through the tunnel, which only requires HTTPS
+	// access.
+	WebSocketURL string `json:"websocket_url"`
+
+	// ForceWebSocket makes all connections use the WebSocket tunnel.
+	ForceWebSocket bool `json:"force_websocket"`
+
+	// Sync and Tunnels limit the bandwidth used by file sync and by tunnels
+	// to services.
+	Sync    BandwidthLimits `json:"sync,omitempty"`
+	Tunnels BandwidthLimits `json:"tunnels,omitempty"`
+
+	// MaxMessageBytes is the largest gRPC message that the CLI accepts from
+	// Blimp's servers. It defaults to 64MiB.
+	MaxMessageBytes int `json:"max_message_bytes,omitempty"`
+}
+
+// BandwidthLimits are rates such as `5MB/s`. Empty values are unlimited.
+type BandwidthLimits struct {
+	MaxUpload   string `json:"max-upload,omitempty"`
+	MaxDownload string `json:"max-download,omitempty"`
 }
 
 var ConfigDir string
@@ -65,3 +94,47 @@ func ParseConfig() (Config, error) {
 
 	return cfg, nil
 }
+
+// SetConfigValue sets the field in blimp.yaml at the given dot-separated key,
+// such as `sync.max-upload`. The field is removed if the value is empty. The
+// caller is responsible for validating the key and value.
+func SetConfigValue(key, value string) error {
+	cfgPath := Expand("blimp.yaml")
+	cfgContents, err := ioutil.ReadFile(cfgPath)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.WithContext("read config", err)
+	}
+
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal(cfgContents, &cfg); err != nil {
+		return errors.WithContext("parse config", err)
+	}
+	if cfg == nil {
+		cfg = map[string]interface{}{}
+	}
+
+	// Walk to the map containing the field, creating maps as needed.
+	path := strings.Split(key, ".")
+	parent := cfg
+	for _, name := range path[:len(path)-1] {
+		child, ok := parent[name].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			parent[name] = child
+		}
+		parent = child
+	}
+
+	field := path[len(path)-1]
+	if value == "" {
+		delete(parent, field)
+	} else {
+		parent[field] = value
+	}
+
+	newContents, err := yaml.Marshal(cfg)
+	if err != nil {
+		return errors.WithContext("marshal config", err)
+	}
+	return ioutil.WriteFile(cfgPath, newContents, 0644)
+}
@@ -1,4 +1,4 @@
-package dockercompose
+package compose
 
 import (
 	"fmt"
@@ -10,7 +10,6 @@ import (
 
 	"github.com/buger/goterm"
 	"github.com/ghodss/yaml"
-	"github.com/kelda/compose-go/envfile"
 	"github.com/kelda/compose-go/loader"
 	"github.com/kelda/compose-go/types"
 	log "github.com/sirupsen/logrus"
@@ -25,52 +24,28 @@ var fs = afero.NewOsFs()
 // Load loads and merges the given compose files. If `services` is non-empty,
 // the return config only includes the services specified in `services`.
 func Load(composePath string, overridePaths, services []string) (types.Project, error) {
-	var configFiles []types.ConfigFile
-	for _, path := range append([]string{composePath}, overridePaths...) {
-		b, err := afero.ReadFile(fs, path)
-		if err != nil {
-			return types.Project{}, errors.WithContext("read compose file", err)
-		}
-
-		configIntf, err := loader.ParseYAML(b)
-		if err != nil {
-			msg := fmt.Sprintf("Failed to parse Compose file (%s)\n"+
-				"Error: %s", path, err)
-			if context, ok := getErrorContext(b, err.Error()); ok {
-				msg += "\n\n" + context
-			}
-			return types.Project{}, errors.NewFriendlyError(msg)
-		}
+	configFiles, err := readConfigFiles(composePath, overridePaths)
+	if err != nil {
+		return types.Project{}, err
+	}
 
-		configFiles = append(configFiles, types.ConfigFile{
-			Filename: filepath.Base(path),
-			Config:   configIntf,
-		})
+	if err := resolveEnvFiles(configFiles, filepath.Dir(composePath)); err != nil {
+		return types.Project{}, err
 	}
 
-	env := map[string]string{}
-	dotenvPath := filepath.Join(filepath.Dir(composePath), ".env")
-	if _, err := os.Stat(dotenvPath); err == nil {
-		dotenv, err := parseEnvFile(dotenvPath)
-		if err != nil {
-			return types.Project{}, errors.NewFriendlyError(
-				"Failed to parse .env file at %s.\n\n"+
-					"The full error was:\n%s",
-				dotenvPath, err)
-		}
+	env, err := loadEnv(composePath)
+	if err != nil {
+		return types.Project{}, err
+	}
 
-		env = dotenv
+	explicitProjectName, err := getExplicitProjectName(configFiles, env)
+	if err != nil {
+		return types.Project{}, err
 	}
 
-	// Environment variables in the shell take precedence over the .env file:
-	// https://docs.docker.com/compose/environment-variables/#the-env-file
-	for _, e := range os.Environ() {
-		pair := strings.SplitN(e, "=", 2)
-		var val string
-		if len(pair) == 2 {
-			val = pair[1]
-		}
-		env[pair[0]] = val
+	// The project name is handled by Blimp rather than the loader.
+	for _, configFile := range configFiles {
+		delete(configFile.Config, "name")
 	}
 
 	opts := []func(*loader.Options){
@@ -117,8 +92,20 @@ func Load(composePath string, overridePaths, services []string) (types.Project,
 			continue
 		}
 
-		cfgPtr.Services[svcIdx].ContainerName = fmt.Sprintf("%s_%s_1",
-			filepath.Base(filepath.Dir(composePath)), svc.Name)
+		containerPrefix := filepath.Base(filepath.Dir(composePath))
+		if explicitProjectName != "" {
+			containerPrefix = explicitProjectName
+		}
+		cfgPtr.Services[svcIdx].ContainerName = fmt.Sprintf("%s_%s_1", containerPrefix, svc.Name)
+	}
+
+	developConfigs, err := parseDevelopConfigs(configFiles)
+	if err != nil {
+		return types.Project{}, err
+	}
+
+	if err := applyDevelopConfigs(cfgPtr, filepath.Dir(composePath), developConfigs); err != nil {
+		return types.Project{}, err
 	}
 
 	// Convert build contexts to absolute paths, set the default value for the
@@ -211,29 +198,93 @@ func Load(composePath string, overridePaths, services []string) (types.Project,
 		cfgPtr.Services = filtered
 	}
 
+	if err := persistExtensions(cfgPtr); err != nil {
+		return types.Project{}, errors.WithContext("persist extensions", err)
+	}
+
 	cfgPtr.Name = getProjectName(composePath)
+	if explicitProjectName != "" {
+		cfgPtr.Name = explicitProjectName
+	}
 	return *cfgPtr, nil
 }
 
-func parseEnvFile(path string) (map[string]string, error) {
-	parsed, err := envfile.Parse(path)
+// LoadProjectName returns the project name set by the COMPOSE_PROJECT_NAME
+// environment variable, or by the top-level `name` field in the Compose
+// files. It returns an empty string if neither is set, in which case the
+// services aren't namespaced by project.
+func LoadProjectName(composePath string, overridePaths []string) (string, error) {
+	configFiles, err := readConfigFiles(composePath, overridePaths)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	ret := map[string]string{}
-	for k, vPtr := range parsed {
-		v := ""
-		if vPtr != nil {
-			v = *vPtr
+	env, err := loadEnv(composePath)
+	if err != nil {
+		return "", err
+	}
+	return getExplicitProjectName(configFiles, env)
+}
+
+func readConfigFiles(composePath string, overridePaths []string) ([]types.ConfigFile, error) {
+	var configFiles []types.ConfigFile
+	for _, path := range append([]string{composePath}, overridePaths...) {
+		b, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return nil, errors.WithContext("read compose file", err)
 		}
-		ret[k] = v
+
+		configIntf, err := loader.ParseYAML(b)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to parse Compose file (%s)\n"+
+				"Error: %s", path, err)
+			if context, ok := getErrorContext(b, err.Error()); ok {
+				msg += "\n\n" + context
+			}
+			return nil, errors.NewFriendlyError(msg)
+		}
+
+		configFiles = append(configFiles, types.ConfigFile{
+			Filename: filepath.Base(path),
+			Config:   configIntf,
+		})
+	}
+	return configFiles, nil
+}
+
+// loadEnv returns the environment used to interpolate the Compose files.
+func loadEnv(composePath string) (map[string]string, error) {
+	env := map[string]string{}
+	dotenvPath := filepath.Join(filepath.Dir(composePath), ".env")
+	if _, err := os.Stat(dotenvPath); err == nil {
+		dotenv, err := parseEnvFile(dotenvPath)
+		if err != nil {
+			return nil, errors.NewFriendlyError(
+				"Failed to parse .env file at %s.\n\n"+
+					"The full error was:\n%s",
+				dotenvPath, err)
+		}
+
+		env = dotenv
+	}
+
+	// Environment variables in the shell take precedence over the .env file:
+	// https://docs.docker.com/compose/environment-variables/#the-env-file
+	for _, e := range os.Environ() {
+		pair := strings.SplitN(e, "=", 2)
+		var val string
+		if len(pair) == 2 {
+			val = pair[1]
+		}
+		env[pair[0]] = val
 	}
-	return ret, nil
+	return env, nil
 }
 
-// Unmarshal loads the parsed compose spec that was serialized by the Marshal function.
-func Unmarshal(b []byte) (parsed types.Project, err error) {
+// Parse loads the parsed compose spec that was serialized by the Marshal
+// function. Unlike Load, it doesn't interpolate variables or resolve paths
+// since that was already done by the client.
+func Parse(b []byte) (parsed types.Project, err error) {
 	configIntf, err := loader.ParseYAML(b)
 	if err != nil {
 		return types.Project{}, errors.WithContext("parse", err)
@@ -254,8 +305,8 @@ func Unmarshal(b []byte) (parsed types.Project, err error) {
 }
 
 // Marshal serializes a parsed compose spec so that it can be loaded by the
-// Unmarshal function.
-// Note that `Marshal` and `Unmarshal` used to use `types.Config` types, but
+// Parse function.
+// Note that `Marshal` and `Parse` used to use `types.Config` types, but
 // switching to `types.Project` is fully backwards compatible, since
 // `types.Project` and `types.Config` both have the same field names and types
 // for `Services`, `Networks`, and `Volumes`.
@@ -0,0 +1,202 @@
+package compose
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelda/compose-go/types"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// developKey is the service field that contains the Compose Spec's
+// development configuration. For example:
+// ```
+// services:
+//   web:
+//     build: .
+//     develop:
+//       watch:
+//         - action: sync+restart
+//           path: ./src
+//           target: /app/src
+//         - action: rebuild
+//           path: package.json
+// ```
+const developKey = "develop"
+
+// WatchAction specifies what should happen when a watched path changes.
+type WatchAction string
+
+const (
+	// WatchActionSync syncs changes into the container without restarting it.
+	WatchActionSync WatchAction = "sync"
+
+	// WatchActionSyncRestart syncs changes into the container, and then
+	// restarts the service.
+	WatchActionSyncRestart WatchAction = "sync+restart"
+
+	// WatchActionRebuild rebuilds the service's image, and redeploys the
+	// service with the new image.
+	WatchActionRebuild WatchAction = "rebuild"
+)
+
+// DevelopConfig is the parsed contents of a service's `develop` field.
+type DevelopConfig struct {
+	Watch []WatchRule `json:"watch,omitempty"`
+}
+
+// WatchRule is a single entry in `develop.watch`.
+type WatchRule struct {
+	Action WatchAction `json:"action"`
+
+	// Path is the local path to watch. After loading, it's always absolute.
+	Path string `json:"path"`
+
+	// Target is the path in the container that Path is synced to. It's only
+	// used by the sync actions.
+	Target string `json:"target,omitempty"`
+
+	// Ignore contains patterns, relative to Path, for files that shouldn't
+	// trigger the action.
+	Ignore []string `json:"ignore,omitempty"`
+}
+
+// Ignored returns whether changes to the given path, which is relative to the
+// rule's Path, should be ignored.
+func (rule WatchRule) Ignored(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	for _, pattern := range rule.Ignore {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+
+		// Patterns can match the full path, any parent directory, or the
+		// file name.
+		if strings.HasPrefix(relPath, pattern+"/") {
+			return true
+		}
+		if match, _ := filepath.Match(pattern, relPath); match {
+			return true
+		}
+		if match, _ := filepath.Match(pattern, filepath.Base(relPath)); match {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDevelopConfig returns the `develop` configuration for the given service.
+// It's only available on the client, since the configuration isn't retained
+// by Marshal.
+func GetDevelopConfig(svc types.ServiceConfig) DevelopConfig {
+	cfg, _ := svc.Extras[developKey].(DevelopConfig)
+	return cfg
+}
+
+// parseDevelopConfigs extracts the `develop` field of each service from the
+// raw Compose files. The Compose library predates the field, and drops it
+// while loading. Later files override the configuration in earlier files.
+func parseDevelopConfigs(configFiles []types.ConfigFile) (map[string]DevelopConfig, error) {
+	configs := map[string]DevelopConfig{}
+	for _, configFile := range configFiles {
+		services, ok := configFile.Config["services"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for name, svcIntf := range services {
+			svc, ok := svcIntf.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			developIntf, ok := svc[developKey]
+			if !ok {
+				continue
+			}
+
+			developJSON, err := json.Marshal(developIntf)
+			if err != nil {
+				return nil, errors.WithContext("marshal", err)
+			}
+
+			var develop DevelopConfig
+			if err := json.Unmarshal(developJSON, &develop); err != nil {
+				return nil, errors.NewFriendlyError(
+					"Failed to parse the develop section for service %s (%s): %s",
+					name, configFile.Filename, err)
+			}
+			configs[name] = develop
+		}
+	}
+	return configs, nil
+}
+
+// applyDevelopConfigs validates the development configuration, and attaches
+// it to the services. Paths that are synced into containers are converted
+// into bind volumes so that they're synced using the same mechanism.
+func applyDevelopConfigs(cfg *types.Project, workingDir string, configs map[string]DevelopConfig) error {
+	for svcIdx, svc := range cfg.Services {
+		develop, ok := configs[svc.Name]
+		if !ok {
+			continue
+		}
+
+		for ruleIdx, rule := range develop.Watch {
+			if !filepath.IsAbs(rule.Path) {
+				rule.Path = filepath.Join(workingDir, rule.Path)
+			}
+
+			if _, err := os.Stat(rule.Path); err != nil {
+				return errors.NewFriendlyError(
+					"Can't watch %s for service %s: %s", rule.Path, svc.Name, err)
+			}
+
+			switch rule.Action {
+			case WatchActionSync, WatchActionSyncRestart:
+				if rule.Target == "" {
+					return errors.NewFriendlyError(
+						"The %s watch rule for %s in service %s requires a target.",
+						rule.Action, rule.Path, svc.Name)
+				}
+
+				if !hasVolumeTarget(svc, rule.Target) {
+					svc.Volumes = append(svc.Volumes, types.ServiceVolumeConfig{
+						Type:   types.VolumeTypeBind,
+						Source: rule.Path,
+						Target: rule.Target,
+					})
+				}
+			case WatchActionRebuild:
+				if svc.Build == nil {
+					return errors.NewFriendlyError(
+						"Service %s has a rebuild watch rule, but doesn't have a build section.",
+						svc.Name)
+				}
+			default:
+				return errors.NewFriendlyError(
+					"Unknown watch action %q for service %s. "+
+						"Supported actions are %q, %q, and %q.",
+					rule.Action, svc.Name, WatchActionSync, WatchActionSyncRestart, WatchActionRebuild)
+			}
+			develop.Watch[ruleIdx] = rule
+		}
+
+		if svc.Extras == nil {
+			svc.Extras = map[string]interface{}{}
+		}
+		svc.Extras[developKey] = develop
+		cfg.Services[svcIdx] = svc
+	}
+	return nil
+}
+
+func hasVolumeTarget(svc types.ServiceConfig, target string) bool {
+	for _, v := range svc.Volumes {
+		if v.Target == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/kelda/compose-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDevelopConfigs(t *testing.T) {
+	configFiles := []types.ConfigFile{
+		{
+			Filename: "docker-compose.yml",
+			Config: map[string]interface{}{
+				"services": map[string]interface{}{
+					"web": map[string]interface{}{
+						"image": "web",
+						"develop": map[string]interface{}{
+							"watch": []interface{}{
+								map[string]interface{}{
+									"action": "sync",
+									"path":   "./src",
+									"target": "/app/src",
+								},
+							},
+						},
+					},
+					"db": map[string]interface{}{
+						"image": "postgres",
+					},
+				},
+			},
+		},
+		{
+			Filename: "docker-compose.override.yml",
+			Config: map[string]interface{}{
+				"services": map[string]interface{}{
+					"web": map[string]interface{}{
+						"develop": map[string]interface{}{
+							"watch": []interface{}{
+								map[string]interface{}{
+									"action": "rebuild",
+									"path":   "package.json",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	configs, err := parseDevelopConfigs(configFiles)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]DevelopConfig{
+		"web": {
+			Watch: []WatchRule{
+				{Action: WatchActionRebuild, Path: "package.json"},
+			},
+		},
+	}, configs)
+}
+
+func TestWatchRuleIgnored(t *testing.T) {
+	rule := WatchRule{Ignore: []string{"node_modules/", "*.log", "build/out.js"}}
+
+	assert.True(t, rule.Ignored("node_modules"))
+	assert.True(t, rule.Ignored("node_modules/react/index.js"))
+	assert.True(t, rule.Ignored("debug.log"))
+	assert.True(t, rule.Ignored("logs/debug.log"))
+	assert.True(t, rule.Ignored("build/out.js"))
+	assert.False(t, rule.Ignored("build/index.js"))
+	assert.False(t, rule.Ignored("src/index.js"))
+}
@@ -0,0 +1,15 @@
+/*
+Package compose parses Docker Compose files, and translates them into the
+Kubernetes objects that make up a sandbox.
+
+The CLI and the manager both use this package so that they interpret Compose
+files identically. The main entrypoints are:
+
+  - Load and Parse, which read Compose files and normalize them into a
+    composeTypes.Project.
+  - Validate and UnsupportedFeatures, which check for configuration that
+    Blimp can't run.
+  - ToKubernetes, which converts the services in a project into pods and the
+    ConfigMaps they reference.
+*/
+package compose
@@ -0,0 +1,187 @@
+package compose
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/kelda/compose-go/types"
+	"github.com/spf13/afero"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// resolveEnvFiles replaces each service's `env_file` with the variables
+// defined in the files. Variables in `environment` take precedence over
+// variables in env files, and later env files take precedence over earlier
+// ones. The files are parsed here rather than by the loader so that we can
+// support the same syntax as shell scripts, such as `export`.
+func resolveEnvFiles(configFiles []types.ConfigFile, workingDir string) error {
+	for _, configFile := range configFiles {
+		services, ok := configFile.Config["services"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for name, svcIntf := range services {
+			svc, ok := svcIntf.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			envFilesIntf, ok := svc["env_file"]
+			if !ok {
+				continue
+			}
+
+			var envFiles []string
+			switch envFilesIntf := envFilesIntf.(type) {
+			case string:
+				envFiles = []string{envFilesIntf}
+			case []interface{}:
+				for _, path := range envFilesIntf {
+					pathStr, ok := path.(string)
+					if !ok {
+						return errors.NewFriendlyError(
+							"The env_file for service %s (%s) must be a list of paths",
+							name, configFile.Filename)
+					}
+					envFiles = append(envFiles, pathStr)
+				}
+			default:
+				return errors.NewFriendlyError(
+					"The env_file for service %s (%s) must be a path or a list of paths",
+					name, configFile.Filename)
+			}
+
+			env := map[string]interface{}{}
+			for _, path := range envFiles {
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(workingDir, path)
+				}
+
+				vars, err := parseEnvFile(path)
+				if err != nil {
+					return errors.NewFriendlyError(
+						"Failed to parse env_file %s for service %s.\n\n"+
+							"The full error was:\n%s", path, name, err)
+				}
+
+				for k, v := range vars {
+					// Escape dollar signs since the loader interpolates the
+					// environment, but values in env files are literal.
+					env[k] = strings.Replace(v, "$", "$$", -1)
+				}
+			}
+
+			switch environment := svc["environment"].(type) {
+			case map[string]interface{}:
+				for k, v := range environment {
+					env[k] = v
+				}
+			case []interface{}:
+				for _, kvIntf := range environment {
+					kv, ok := kvIntf.(string)
+					if !ok {
+						continue
+					}
+
+					parts := strings.SplitN(kv, "=", 2)
+					if len(parts) == 2 {
+						env[parts[0]] = parts[1]
+					} else {
+						env[parts[0]] = nil
+					}
+				}
+			}
+
+			svc["environment"] = env
+			delete(svc, "env_file")
+		}
+	}
+	return nil
+}
+
+// parseEnvFile parses a file containing environment variables. Each line
+// contains a single variable in the form `KEY=VALUE`, and may optionally be
+// prefixed with `export`. Values may be quoted, and blank lines and lines
+// starting with `#` are ignored. If a line only contains a key, the value is
+// taken from the local environment.
+func parseEnvFile(path string) (map[string]string, error) {
+	contents, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	return parseEnv(contents)
+}
+
+func parseEnv(contents []byte) (map[string]string, error) {
+	env := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "export ") {
+			line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if key == "" || strings.IndexFunc(key, unicode.IsSpace) != -1 {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", lineNum, key)
+		}
+
+		if len(parts) == 1 {
+			if val, ok := os.LookupEnv(key); ok {
+				env[key] = val
+			}
+			continue
+		}
+
+		val, err := parseEnvValue(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNum, err)
+		}
+		env[key] = val
+	}
+	return env, scanner.Err()
+}
+
+// parseEnvValue unquotes the value of an environment variable. Unquoted
+// values end at the first ` #`, so that lines can have trailing comments.
+func parseEnvValue(val string) (string, error) {
+	if val == "" {
+		return "", nil
+	}
+
+	quote := val[0]
+	if quote != '"' && quote != '\'' {
+		if i := strings.Index(val, " #"); i != -1 {
+			val = strings.TrimSpace(val[:i])
+		}
+		return val, nil
+	}
+
+	end := strings.LastIndexByte(val, quote)
+	if end == 0 {
+		return "", fmt.Errorf("unterminated quote")
+	}
+
+	rest := strings.TrimSpace(val[end+1:])
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("unexpected characters after quoted value: %s", rest)
+	}
+
+	val = val[1:end]
+	if quote == '"' {
+		val = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(val)
+	}
+	return val, nil
+}
@@ -0,0 +1,94 @@
+package compose
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kelda/compose-go/types"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEnv(t *testing.T) {
+	os.Setenv("BLIMP_TEST_INHERITED", "inherited")
+	defer os.Unsetenv("BLIMP_TEST_INHERITED")
+
+	env, err := parseEnv([]byte(`
+# Comments and blank lines are ignored.
+
+PLAIN=value
+export EXPORTED=exported
+  SPACES = padded value
+TRAILING=value # comment
+EMPTY=
+DOUBLE="line one\nline \"two\""
+SINGLE='not $interpolated\n'
+HASH="value # not a comment"
+BLIMP_TEST_INHERITED
+BLIMP_TEST_UNSET
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"PLAIN":                "value",
+		"EXPORTED":             "exported",
+		"SPACES":               "padded value",
+		"TRAILING":             "value",
+		"EMPTY":                "",
+		"DOUBLE":               "line one\nline \"two\"",
+		"SINGLE":               `not $interpolated\n`,
+		"HASH":                 "value # not a comment",
+		"BLIMP_TEST_INHERITED": "inherited",
+	}, env)
+
+	_, err = parseEnv([]byte("BAD NAME=value"))
+	assert.EqualError(t, err, `line 1: invalid variable name "BAD NAME"`)
+
+	_, err = parseEnv([]byte("\nQUOTED=\"unterminated"))
+	assert.EqualError(t, err, "line 2: unterminated quote")
+}
+
+func TestResolveEnvFiles(t *testing.T) {
+	fs = afero.NewMemMapFs()
+	defer func() { fs = afero.NewOsFs() }()
+
+	assert.NoError(t, afero.WriteFile(fs, "/project/common.env",
+		[]byte("A=common\nB=common\nPRICE=$5\n"), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "/project/web.env",
+		[]byte("export B=web\nC=web\n"), 0644))
+
+	configFiles := []types.ConfigFile{
+		{
+			Filename: "docker-compose.yml",
+			Config: map[string]interface{}{
+				"services": map[string]interface{}{
+					"web": map[string]interface{}{
+						"env_file":    []interface{}{"common.env", "/project/web.env"},
+						"environment": []interface{}{"C=environment", "D"},
+					},
+					"worker": map[string]interface{}{
+						"env_file": "./common.env",
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, resolveEnvFiles(configFiles, "/project"))
+	assert.Equal(t, map[string]interface{}{
+		"web": map[string]interface{}{
+			"environment": map[string]interface{}{
+				"A":     "common",
+				"B":     "web",
+				"C":     "environment",
+				"D":     nil,
+				"PRICE": "$$5",
+			},
+		},
+		"worker": map[string]interface{}{
+			"environment": map[string]interface{}{
+				"A":     "common",
+				"B":     "common",
+				"PRICE": "$$5",
+			},
+		},
+	}, configFiles[0].Config["services"])
+}
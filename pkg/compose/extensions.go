@@ -0,0 +1,311 @@
+package compose
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kelda/compose-go/types"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// ExtensionKey is the Compose extension field used for Blimp-specific
+// service configuration. For example:
+// ```
+// services:
+//   web:
+//     image: nginx
+//     x-blimp:
+//       mtls: true
+//       faults:
+//         - to: db
+//           drop: 5%
+//           delay: 300ms
+//       on_sync: kill -HUP 1
+//       on_ready: open http://localhost:3000
+//       ready_log_pattern: "Listening on port \\d+"
+//       init_timeouts:
+//         depends_on: 5m
+//       secret_env:
+//         - DATABASE_PASSWORD
+//       pod_annotations:
+//         prometheus.io/scrape: "true"
+//       pod_labels:
+//         team: payments
+//       pull_policy: if_not_present
+//       pin_digest: true
+//       ssh_agent: true
+//       docker_socket: true
+//       volumes:
+//         /data:
+//           max_file_size: 100MB
+//           compression: false
+//           delta: true
+//           owner: "1000:1000"
+//           normalize_line_endings:
+//             - "*.sh"
+//   cleanup:
+//     image: cleanup
+//     x-blimp:
+//       schedule: "*/5 * * * *"
+// ```
+const ExtensionKey = "x-blimp"
+
+// extensionLabel is the service label that Load uses to pass the x-blimp
+// extension through to the cluster manager. Extension fields aren't retained
+// by Marshal, but labels are.
+const extensionLabel = "io.kelda.blimp/x-blimp"
+
+// ServiceExtension is the parsed contents of a service's x-blimp extension.
+type ServiceExtension struct {
+	// MTLS enables issuing a certificate for the service that's signed by
+	// the sandbox's certificate authority.
+	MTLS bool `json:"mtls,omitempty"`
+
+	// Faults degrade the network traffic sent from the service to other
+	// services.
+	Faults []FaultConfig `json:"faults,omitempty"`
+
+	// OnSync contains shell commands that are run in the service's container
+	// after changes to its bind volumes finish syncing.
+	OnSync Commands `json:"on_sync,omitempty"`
+
+	// OnReady contains shell commands that are run on the user's machine by
+	// `blimp up` each time the service starts running. They're run from the
+	// directory containing the Compose file.
+	OnReady Commands `json:"on_ready,omitempty"`
+
+	// ReadyLogPattern is a regular expression that's matched against each
+	// line that the service logs. If it's set, the service isn't considered
+	// healthy until a line matches, so that services without a healthcheck
+	// can still gate the services that depend on them with
+	// `condition: service_healthy`, and the tunnels to them.
+	ReadyLogPattern string `json:"ready_log_pattern,omitempty"`
+
+	// InitTimeouts overrides how long the service may wait in each phase of
+	// booting before it's reported as stuck.
+	InitTimeouts InitTimeouts `json:"init_timeouts,omitempty"`
+
+	// SecretEnv lists the environment variables that are stored in a
+	// Kubernetes Secret rather than directly in the pod spec, so that they
+	// aren't shown by commands such as `kubectl describe`.
+	SecretEnv []string `json:"secret_env,omitempty"`
+
+	// Schedule is a cron schedule, such as "*/5 * * * *". If it's set, the
+	// service is run on the schedule rather than continuously.
+	Schedule string `json:"schedule,omitempty"`
+
+	// Volumes configures how the service's bind volumes are synced. It's
+	// keyed by the path that the volume is mounted at in the container.
+	Volumes map[string]VolumeExtension `json:"volumes,omitempty"`
+
+	// PodAnnotations and PodLabels are added to the service's pod, so that
+	// tools running in the cluster, such as metrics scrapers, can select
+	// and configure the service.
+	PodAnnotations map[string]string `json:"pod_annotations,omitempty"`
+	PodLabels      map[string]string `json:"pod_labels,omitempty"`
+
+	// PullPolicy controls when the service's image is pulled. It's one of
+	// "always", "if_not_present", or "never". Images are always pulled by
+	// default, so that mutable tags such as `latest` stay up to date.
+	PullPolicy string `json:"pull_policy,omitempty"`
+
+	// PinDigest resolves the service's image tag to a digest when running
+	// `blimp up`, so that the service runs the same image until the next
+	// `blimp up`, even if the tag is pushed to in the meantime.
+	PinDigest bool `json:"pin_digest,omitempty"`
+
+	// SSHAgent forwards the SSH agent on the user's machine into the
+	// service, and points SSH_AUTH_SOCK at it. Only services that set it can
+	// use the agent.
+	SSHAgent bool `json:"ssh_agent,omitempty"`
+
+	// DockerSocket gives the service an emulated Docker socket, and points
+	// DOCKER_HOST at it. Containers started through the socket run as pods
+	// in the sandbox, and are removed when the service is.
+	DockerSocket bool `json:"docker_socket,omitempty"`
+}
+
+// VolumeExtension configures how a bind volume is synced.
+type VolumeExtension struct {
+	// MaxFileSize is a size such as "100MB". Files larger than it aren't
+	// synced, so that large data files don't stall booting.
+	MaxFileSize string `json:"max_file_size,omitempty"`
+
+	// Compression can be set to false to stop compressing file data, which
+	// is wasted effort for files that are already compressed. Compression
+	// applies to all volumes, so disabling it for any volume disables it
+	// for all of them.
+	Compression *bool `json:"compression,omitempty"`
+
+	// Delta enables delta transfers for large files that have data inserted
+	// into them, such as databases, so that only the changed parts are sent.
+	Delta bool `json:"delta,omitempty"`
+
+	// Owner is the `UID:GID` that owns the volume's files in the sandbox, so
+	// that services that don't run as root can write to them. By default,
+	// files are owned by root.
+	Owner string `json:"owner,omitempty"`
+
+	// NormalizeLineEndings contains patterns, such as `*.sh`, for the files
+	// whose Windows line endings should be converted to Unix line endings.
+	// Patterns without a slash match file names, and other patterns match
+	// paths relative to the volume. The converted files are synced back to
+	// the local machine.
+	NormalizeLineEndings []string `json:"normalize_line_endings,omitempty"`
+}
+
+// GetMaxFileSize returns the maximum size of synced files in bytes. It
+// returns zero if there's no limit.
+func (v VolumeExtension) GetMaxFileSize() (int64, error) {
+	if v.MaxFileSize == "" {
+		return 0, nil
+	}
+
+	size, err := ParseSize(v.MaxFileSize)
+	if err != nil {
+		return 0, errors.NewFriendlyError(
+			"Invalid max_file_size %q. It should be formatted like `100MB`.", v.MaxFileSize)
+	}
+	return size, nil
+}
+
+var sizeRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([kmgt]?)i?b?$`)
+
+// ParseSize parses sizes such as "512KB" or "1.5GB". Units are powers of
+// 1024, to match Docker.
+func ParseSize(str string) (int64, error) {
+	match := sizeRegex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(str)))
+	if match == nil {
+		return 0, errors.New("malformed size")
+	}
+
+	num, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, errors.WithContext("parse number", err)
+	}
+
+	multiplier := map[string]float64{
+		"":  1,
+		"k": 1 << 10,
+		"m": 1 << 20,
+		"g": 1 << 30,
+		"t": 1 << 40,
+	}[match[2]]
+	return int64(num * multiplier), nil
+}
+
+// InitTimeouts contains durations such as "10m" for each phase that a
+// service waits in before booting. Empty durations use the default.
+type InitTimeouts struct {
+	// DependsOn limits how long to wait for the services in `depends_on`
+	// and `links`.
+	DependsOn string `json:"depends_on,omitempty"`
+
+	// Sync limits how long to wait for bind volumes to finish their initial
+	// sync.
+	Sync string `json:"sync,omitempty"`
+
+	// Volumes limits how long to wait for other services to initialize
+	// shared volumes.
+	Volumes string `json:"volumes,omitempty"`
+}
+
+// Commands is a list of shell commands that may also be written as a single
+// command.
+type Commands []string
+
+func (c *Commands) UnmarshalJSON(b []byte) error {
+	var cmd string
+	if err := json.Unmarshal(b, &cmd); err == nil {
+		*c = Commands{cmd}
+		return nil
+	}
+
+	var cmds []string
+	if err := json.Unmarshal(b, &cmds); err != nil {
+		return err
+	}
+	*c = cmds
+	return nil
+}
+
+// FaultConfig is a single entry in `x-blimp.faults`.
+type FaultConfig struct {
+	// To is the name of the service whose traffic is affected.
+	To string `json:"to"`
+
+	// Drop is the percentage of packets to drop, such as "5%".
+	Drop Percent `json:"drop,omitempty"`
+
+	// Delay is a duration such as "300ms" that's added to every packet.
+	Delay string `json:"delay,omitempty"`
+}
+
+// Percent is a percentage that may be written either as a number, or as a
+// string with a percent sign.
+type Percent string
+
+func (p *Percent) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		*p = Percent(str)
+		return nil
+	}
+
+	var num float64
+	if err := json.Unmarshal(b, &num); err != nil {
+		return err
+	}
+	*p = Percent(strconv.FormatFloat(num, 'f', -1, 64))
+	return nil
+}
+
+// GetServiceExtension returns the x-blimp configuration for the given
+// service. It returns the zero value if the service doesn't have any Blimp
+// specific configuration.
+func GetServiceExtension(svc types.ServiceConfig) (ServiceExtension, error) {
+	var extJSON []byte
+	if extIntf, ok := svc.Extras[ExtensionKey]; ok {
+		var err error
+		extJSON, err = json.Marshal(extIntf)
+		if err != nil {
+			return ServiceExtension{}, errors.WithContext("marshal", err)
+		}
+	} else if extStr, ok := svc.Labels[extensionLabel]; ok {
+		extJSON = []byte(extStr)
+	} else {
+		return ServiceExtension{}, nil
+	}
+
+	var ext ServiceExtension
+	if err := json.Unmarshal(extJSON, &ext); err != nil {
+		return ServiceExtension{}, errors.NewFriendlyError(
+			"Failed to parse %s for service %s: %s", ExtensionKey, svc.Name, err)
+	}
+	return ext, nil
+}
+
+// persistExtensions copies each service's x-blimp extension into a label so
+// that it survives being sent to the cluster manager.
+func persistExtensions(cfg *types.Project) error {
+	for i, svc := range cfg.Services {
+		extIntf, ok := svc.Extras[ExtensionKey]
+		if !ok {
+			continue
+		}
+
+		extJSON, err := json.Marshal(extIntf)
+		if err != nil {
+			return errors.WithContext("marshal extension", err)
+		}
+
+		if cfg.Services[i].Labels == nil {
+			cfg.Services[i].Labels = types.Labels{}
+		}
+		cfg.Services[i].Labels[extensionLabel] = string(extJSON)
+	}
+	return nil
+}
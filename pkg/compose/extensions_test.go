@@ -0,0 +1,37 @@
+package compose_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelda/blimp/pkg/compose"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		str    string
+		exp    int64
+		expErr bool
+	}{
+		{str: "100", exp: 100},
+		{str: "512KB", exp: 512 * 1024},
+		{str: "100MB", exp: 100 * 1024 * 1024},
+		{str: "100MiB", exp: 100 * 1024 * 1024},
+		{str: "1.5gb", exp: 3 * 512 * 1024 * 1024},
+		{str: "10 m", exp: 10 * 1024 * 1024},
+		{str: "MB", expErr: true},
+		{str: "-1MB", expErr: true},
+		{str: "100PB", expErr: true},
+	}
+
+	for _, test := range tests {
+		size, err := compose.ParseSize(test.str)
+		if test.expErr {
+			assert.Error(t, err, test.str)
+			continue
+		}
+		assert.NoError(t, err, test.str)
+		assert.Equal(t, test.exp, size, test.str)
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package compose
 
 import (
 	"fmt"
@@ -10,8 +10,8 @@ import (
 	"github.com/kelda/blimp/pkg/strs"
 )
 
-// GetUnsupportedFeatures checks for any references to unsupported features.
-func GetUnsupportedFeatures(cfg types.Project) []string {
+// UnsupportedFeatures checks for any references to unsupported features.
+func UnsupportedFeatures(cfg types.Project) []string {
 	var messages []string
 	if len(cfg.Secrets) != 0 {
 		messages = append(messages, "secrets")
@@ -66,10 +66,20 @@ func validateServices(services types.Services) []string {
 		{ID: ".Ports.Mode", AllowedValues: []interface{}{"ingress"}},
 		{ID: ".Restart", AllowedValues: []interface{}{"no", "always", "unless-stopped", "on-failure"}},
 		{ID: ".StdinOpen"},
+		{ID: ".StopGracePeriod"},
+		{ID: ".StopSignal"},
+		{ID: ".Init"},
 		{ID: ".Tty"},
-		{ID: ".Volumes.Type", AllowedValues: []interface{}{types.VolumeTypeBind, types.VolumeTypeVolume}},
+		{ID: ".Volumes.Type", AllowedValues: []interface{}{
+			types.VolumeTypeBind, types.VolumeTypeVolume, types.VolumeTypeTmpfs}},
 		{ID: ".Volumes.Source"},
 		{ID: ".Volumes.Target"},
+		{ID: ".Volumes.Tmpfs.Size"},
+		{ID: ".Tmpfs"},
+		{ID: ".ShmSize"},
+		// Validate rejects any sysctls and ulimits that aren't supported.
+		{ID: ".Sysctls"},
+		{ID: ".Ulimits"},
 		{ID: ".WorkingDir"},
 		{ID: ".User"},
 
@@ -1,4 +1,4 @@
-package main_test
+package compose_test
 
 import (
 	"testing"
@@ -6,7 +6,7 @@ import (
 	"github.com/kelda/compose-go/types"
 	"github.com/stretchr/testify/assert"
 
-	main "github.com/kelda/blimp/cluster-controller"
+	"github.com/kelda/blimp/pkg/compose"
 )
 
 func TestValidateFeatures(t *testing.T) {
@@ -157,6 +157,6 @@ func TestValidateFeatures(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		assert.Equal(t, test.exp, main.GetUnsupportedFeatures(test.cfg))
+		assert.Equal(t, test.exp, compose.UnsupportedFeatures(test.cfg))
 	}
 }
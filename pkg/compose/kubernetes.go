@@ -0,0 +1,1484 @@
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/golang/protobuf/proto"
+	composeTypes "github.com/kelda/compose-go/types"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kelda/blimp/cluster-controller/affinity"
+	"github.com/kelda/blimp/cluster-controller/volume"
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/hash"
+	"github.com/kelda/blimp/pkg/imagecache"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/names"
+	"github.com/kelda/blimp/pkg/proto/wait"
+	"github.com/kelda/blimp/pkg/strs"
+	"github.com/kelda/blimp/pkg/version"
+)
+
+// The resources requested by each service.
+const (
+	CPURequest         = 20
+	CPURequestUnits    = "m"
+	MemoryRequest      = 50
+	MemoryRequestUnits = "Mi"
+)
+
+// CPULimit is the maximum CPU each service may use, in CPURequestUnits.
+const CPULimit = 4000
+
+// MaxServices is the maximum number of service pods allowed in a single
+// sandbox.
+const MaxServices = 150
+
+// WaitTimeoutEnv is the environment variable on waiter init containers that
+// contains how long the waiter may block before the service is reported as
+// stuck.
+const WaitTimeoutEnv = "WAIT_TIMEOUT"
+
+// The default timeouts for each init phase. Syncing bind volumes gets the
+// most time since large directories can take a while to upload.
+const (
+	defaultDependsOnTimeout = 10 * time.Minute
+	defaultSyncTimeout      = 30 * time.Minute
+	defaultVolumesTimeout   = 10 * time.Minute
+)
+
+// getPullPolicy translates the service's x-blimp.pull_policy into a
+// Kubernetes pull policy.
+func getPullPolicy(svcName, policy string) (corev1.PullPolicy, error) {
+	switch policy {
+	case "", "always":
+		return corev1.PullAlways, nil
+	case "if_not_present", "missing":
+		return corev1.PullIfNotPresent, nil
+	case "never":
+		return corev1.PullNever, nil
+	default:
+		return "", errors.NewFriendlyError(
+			"Invalid %s.pull_policy %q for service %s. It should be \"always\", \"if_not_present\", or \"never\".",
+			ExtensionKey, policy, svcName)
+	}
+}
+
+// getInitTimeouts returns the timeout for each waiter init container, keyed
+// by container name.
+func getInitTimeouts(svcName string, cfg InitTimeouts) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, phase := range []struct {
+		field, container, configured string
+		defaultTimeout               time.Duration
+	}{
+		{"depends_on", kube.ContainerNameWaitDependsOn, cfg.DependsOn, defaultDependsOnTimeout},
+		{"sync", kube.ContainerNameWaitInitialSync, cfg.Sync, defaultSyncTimeout},
+		{"volumes", kube.ContainerNameWaitInitializedVolumes, cfg.Volumes, defaultVolumesTimeout},
+	} {
+		if phase.configured == "" {
+			timeouts[phase.container] = phase.defaultTimeout
+			continue
+		}
+
+		timeout, err := time.ParseDuration(phase.configured)
+		if err != nil || timeout <= 0 {
+			return nil, errors.NewFriendlyError(
+				"Invalid %s.init_timeouts.%s %q for service %s. Timeouts should be formatted like \"5m\".",
+				ExtensionKey, phase.field, phase.configured, svcName)
+		}
+		timeouts[phase.container] = timeout
+	}
+	return timeouts, nil
+}
+
+const (
+	// MTLSMountPath is where services that opt into mTLS can find their
+	// certificates.
+	MTLSMountPath = "/etc/blimp/mtls"
+
+	// The keys in the mTLS secrets.
+	MTLSCACertKey = "ca.crt"
+	MTLSCertKey   = "tls.crt"
+	MTLSKeyKey    = "tls.key"
+
+	// SSHAgentMountPath is where services that opt into SSH agent forwarding
+	// can find the agent's socket.
+	SSHAgentMountPath = "/run/blimp/ssh-agent"
+
+	// DockerSocketMountPath is where services that opt into the Docker
+	// socket can find it.
+	DockerSocketMountPath = "/run/blimp/docker"
+)
+
+// MTLSSecretName returns the name of the secret containing the service's mTLS
+// certificates.
+func MTLSSecretName(svc string) string {
+	return names.ToDNS1123("mtls-" + svc)
+}
+
+// SecretEnvName returns the name of the secret containing the service's secret
+// environment variables.
+func SecretEnvName(svc string) string {
+	return names.ToDNS1123("env-" + svc)
+}
+
+// GetSecretEnv returns the values of the service's environment variables that
+// are marked as secret in its x-blimp extension.
+func GetSecretEnv(svc composeTypes.ServiceConfig) (map[string]string, error) {
+	ext, err := GetServiceExtension(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	secretEnv := map[string]string{}
+	for _, name := range ext.SecretEnv {
+		vPtr, ok := svc.Environment[name]
+		if !ok {
+			return nil, errors.NewFriendlyError(
+				"The %s service marks %s as a secret environment variable, "+
+					"but it's not in the service's environment", svc.Name, name)
+		}
+
+		var v string
+		if vPtr != nil {
+			v = *vPtr
+		}
+		secretEnv[name] = v
+	}
+	return secretEnv, nil
+}
+
+// KubeOptions contains the sandbox-specific information needed to translate
+// services into Kubernetes objects.
+type KubeOptions struct {
+	User auth.User
+
+	// DNSIP is the IP of the sandbox's DNS server.
+	DNSIP string
+
+	// NodeControllerIP is the IP of the node controller on the sandbox's node.
+	NodeControllerIP string
+
+	// BuiltImages maps service names to the images that were built for them.
+	BuiltImages map[string]string
+
+	// ImageCache is the hostname of the image cache. If set, images that
+	// aren't built by Blimp are pulled through the cache.
+	ImageCache string
+
+	// CPUMultiplier scales the CPU resources of each service while the
+	// sandbox is boosted. Services get the default resources if it's unset.
+	CPUMultiplier float64
+
+	// Placement is where the sandbox's pods should run.
+	Placement affinity.Placement
+
+	// MeshCompatibility configures the pods to work with Istio's sidecar
+	// injection.
+	MeshCompatibility bool
+
+	// Project is the name of the Compose project that the services belong
+	// to. It's empty if the project wasn't explicitly named.
+	Project string
+}
+
+// ToKubernetes translates the services in the Compose file into pods, along
+// with the ConfigMaps that the pods reference.
+func ToKubernetes(cfg composeTypes.Project, opts KubeOptions) (
+	pods []corev1.Pod, configMaps []corev1.ConfigMap, err error) {
+
+	if len(cfg.Services) > MaxServices {
+		return nil, nil, errors.NewFriendlyError(
+			"Blimp supports a maximum of %d services, but %d are defined.",
+			MaxServices, len(cfg.Services))
+	}
+
+	b, err := newPodBuilder(opts.User, opts.DNSIP, opts.NodeControllerIP, opts.BuiltImages,
+		cfg.Services, cfg.Volumes)
+	if err != nil {
+		return nil, nil, errors.WithContext("make pod builder", err)
+	}
+	b.imageCache = opts.ImageCache
+	b.placement = opts.Placement
+
+	for _, svc := range cfg.Services {
+		p, cm, err := b.ToPod(svc)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if opts.CPUMultiplier > 1 {
+			SetCPUMultiplier(&p, opts.CPUMultiplier)
+		}
+
+		if opts.MeshCompatibility {
+			setMeshCompatibility(&p, svc, opts.NodeControllerIP)
+		}
+
+		if opts.Project != "" {
+			p.Labels["blimp.project"] = opts.Project
+		}
+
+		pods = append(pods, p)
+		configMaps = append(configMaps, cm...)
+	}
+
+	return pods, configMaps, nil
+}
+
+type podBuilder struct {
+	user             auth.User
+	dnsIP            string
+	nodeControllerIP string
+	builtImages      map[string]string
+	// builtTags maps custom tags defined in the docker-compose.yml to the
+	// corresponding pushed images. For instance, if a service is defined with a
+	// build context and `image: myimage:v3`, then builtTags will map
+	// "myimage:v3" to the builtImage for the service.
+	builtTags         map[string]string
+	svcAliasesMapping map[string][]string
+	volumeToServices  map[string][]string
+	// namedBindVolumes accounts for named volumes that are specified as bind
+	// volumes using DriverOpts. It maps from volume names to source
+	// directories.
+	namedBindVolumes map[string]string
+	imageCache       string
+	placement        affinity.Placement
+}
+
+type podSpec struct {
+	namespace  string
+	image      string
+	pullPolicy corev1.PullPolicy
+	pod        corev1.Pod
+	configMaps []corev1.ConfigMap
+}
+
+func newPodBuilder(user auth.User, dnsIP, nodeControllerIP string, builtImages map[string]string,
+	services []composeTypes.ServiceConfig, volumes map[string]composeTypes.VolumeConfig) (
+	podBuilder, error) {
+
+	serviceToAliases := make(map[string][]string)
+	aliasToService := make(map[string]string)
+	for _, svc := range services {
+		for _, link := range svc.Links {
+			var svcToBeAliased, alias string
+			switch linkParts := strings.Split(link, ":"); len(linkParts) {
+			// A link without an alias. Nothing for us to do.
+			case 1:
+				continue
+			case 2:
+				svcToBeAliased = linkParts[0]
+				alias = linkParts[1]
+			default:
+				log.WithField("link", link).Warn("Link in unexpected format. Skipping.")
+				continue
+			}
+
+			// Error if two services are using the same alias for different services.
+			if svcPresent, added := aliasToService[alias]; added && svcPresent != svcToBeAliased {
+				return podBuilder{}, errors.NewFriendlyError(
+					"links error: service %s and %s are using %s to refer to different services",
+					svcPresent, svcToBeAliased, alias)
+			}
+
+			aliasToService[alias] = svcToBeAliased
+			serviceToAliases[svcToBeAliased] = append(serviceToAliases[svcToBeAliased], alias)
+		}
+	}
+
+	builtTags := map[string]string{}
+	for _, svc := range services {
+		if svc.Build != nil && svc.Image != "" {
+			builtTags[svc.Image] = builtImages[svc.Name]
+		}
+	}
+
+	namedBindVolumes := map[string]string{}
+	for name, vol := range volumes {
+		source, ok := ParseNamedBindVolume(vol)
+
+		if ok {
+			namedBindVolumes[name] = source
+		}
+	}
+
+	volumeToServices := map[string][]string{}
+	for _, svc := range services {
+		for _, v := range svc.Volumes {
+			if v.Type != composeTypes.VolumeTypeVolume {
+				continue
+			}
+			volumeToServices[v.Source] = append(volumeToServices[v.Source], svc.Name)
+		}
+	}
+
+	return podBuilder{
+		user:              user,
+		dnsIP:             dnsIP,
+		nodeControllerIP:  nodeControllerIP,
+		builtImages:       builtImages,
+		builtTags:         builtTags,
+		svcAliasesMapping: serviceToAliases,
+		volumeToServices:  volumeToServices,
+		namedBindVolumes:  namedBindVolumes,
+	}, nil
+}
+
+func (b podBuilder) ToPod(svc composeTypes.ServiceConfig) (corev1.Pod, []corev1.ConfigMap, error) {
+	ext, err := GetServiceExtension(svc)
+	if err != nil {
+		return corev1.Pod{}, nil, err
+	}
+
+	initTimeouts, err := getInitTimeouts(svc.Name, ext.InitTimeouts)
+	if err != nil {
+		return corev1.Pod{}, nil, err
+	}
+
+	pullPolicy, err := getPullPolicy(svc.Name, ext.PullPolicy)
+	if err != nil {
+		return corev1.Pod{}, nil, err
+	}
+
+	spec := podSpec{namespace: b.user.Namespace, pullPolicy: pullPolicy}
+	spec.pod.Spec.Affinity = affinity.ForUser(b.user, b.placement)
+	spec.pod.Spec.Tolerations = affinity.Tolerations()
+
+	if svc.Build != nil {
+		spec.image = b.builtImages[svc.Name]
+		// TODO: Error if image DNE.
+	} else {
+		spec.image = svc.Image
+		if builtTag, ok := b.builtTags[spec.image]; ok {
+			spec.image = builtTag
+		} else if b.imageCache != "" {
+			// If the image name can't be parsed, fall back to pulling it
+			// directly so that the user sees the pull error from the kubelet.
+			cachedImage, err := imagecache.ToCachedImage(b.imageCache, spec.image)
+			if err == nil {
+				spec.image = cachedImage
+			} else {
+				log.WithError(err).WithField("image", spec.image).Warn("Failed to get cached image name")
+			}
+		}
+	}
+
+	var nativeVolumes []composeTypes.ServiceVolumeConfig
+	var bindVolumes []string
+	for _, v := range svc.Volumes {
+		switch v.Type {
+		case composeTypes.VolumeTypeVolume:
+			source, ok := b.namedBindVolumes[v.Source]
+			if ok {
+				bindVolumes = append(bindVolumes, source)
+			} else {
+				nativeVolumes = append(nativeVolumes, v)
+			}
+		case composeTypes.VolumeTypeBind:
+			bindVolumes = append(bindVolumes, v.Source)
+		}
+	}
+
+	if len(nativeVolumes) != 0 {
+		spec.addVolumeSeeder(b.nodeControllerIP, nativeVolumes)
+
+		var servicesSharingVolumes []string
+		for _, volume := range nativeVolumes {
+			servicesSharingVolumes = append(servicesSharingVolumes, b.volumeToServices[volume.Source]...)
+		}
+		servicesSharingVolumes = remove(strs.Unique(servicesSharingVolumes), svc.Name)
+		if len(servicesSharingVolumes) != 0 {
+			err := spec.addWaiter(b.nodeControllerIP, svc.Name, kube.ContainerNameWaitInitializedVolumes,
+				wait.WaitSpec{FinishedVolumeInit: servicesSharingVolumes},
+				initTimeouts[kube.ContainerNameWaitInitializedVolumes])
+			if err != nil {
+				return corev1.Pod{}, nil, err
+			}
+		}
+	}
+
+	var dependencies []*wait.ServiceCondition
+	if len(svc.DependsOn) != 0 {
+		dependencies = marshalDependencies(svc.DependsOn, svc.Links)
+		err := spec.addWaiter(b.nodeControllerIP, svc.Name, kube.ContainerNameWaitDependsOn,
+			wait.WaitSpec{DependsOn: dependencies}, initTimeouts[kube.ContainerNameWaitDependsOn])
+		if err != nil {
+			return corev1.Pod{}, nil, err
+		}
+	}
+
+	if len(bindVolumes) != 0 {
+		err := spec.addWaiter(b.nodeControllerIP, svc.Name, kube.ContainerNameWaitInitialSync,
+			wait.WaitSpec{BindVolumes: bindVolumes},
+			initTimeouts[kube.ContainerNameWaitInitialSync])
+		if err != nil {
+			return corev1.Pod{}, nil, err
+		}
+	}
+
+	if err := spec.addRuntimeContainer(svc, b.dnsIP, b.svcAliasesMapping, b.namedBindVolumes); err != nil {
+		return corev1.Pod{}, nil, err
+	}
+	spec.addSysctls(svc.Sysctls)
+	if nofile, ok := svc.Ulimits["nofile"]; ok {
+		spec.addNofileLimit(nofileLimits(nofile))
+	}
+
+	// Record the dependencies so that the status fetcher can explain why the
+	// service is stuck waiting for them.
+	if len(dependencies) != 0 {
+		var dependencyNames []string
+		for _, dep := range dependencies {
+			dependencyNames = append(dependencyNames, dep.Service)
+		}
+		spec.pod.Annotations[metadata.DependsOnKey] = metadata.DependsOn(dependencyNames)
+	}
+
+	if ext.MTLS {
+		spec.addMTLSCerts(svc.Name)
+	}
+
+	if ext.SSHAgent {
+		spec.addSSHAgent(b.nodeControllerIP)
+	}
+
+	if ext.DockerSocket {
+		spec.addDockerSocket(b.nodeControllerIP)
+	}
+
+	if ext.ReadyLogPattern != "" {
+		if _, err := regexp.Compile(ext.ReadyLogPattern); err != nil {
+			return corev1.Pod{}, nil, errors.NewFriendlyError(
+				"Invalid %s.ready_log_pattern for service %s: %s", ExtensionKey, svc.Name, err)
+		}
+		spec.addReadyLogPattern(ext.ReadyLogPattern)
+	}
+
+	if len(ext.SecretEnv) != 0 {
+		secretEnv, err := GetSecretEnv(svc)
+		if err != nil {
+			return corev1.Pod{}, nil, err
+		}
+		spec.addSecretEnv(svc.Name, secretEnv)
+	}
+
+	if err := spec.addPodMetadata(svc.Name, ext.PodAnnotations, ext.PodLabels); err != nil {
+		return corev1.Pod{}, nil, err
+	}
+
+	spec.sanitize()
+	return spec.pod, spec.configMaps, nil
+}
+
+// addPodMetadata adds the annotations and labels from the service's x-blimp
+// extension to the pod. Keys that Blimp uses itself are rejected so that
+// they can't interfere with the sandbox.
+func (p *podSpec) addPodMetadata(svcName string, annotations, labels map[string]string) error {
+	isReserved := func(key string) bool {
+		return strings.HasPrefix(key, "blimp.") || strings.HasPrefix(key, "io.kelda.blimp/")
+	}
+
+	var passthrough []string
+	for key, val := range annotations {
+		if isReserved(key) {
+			return errors.NewFriendlyError(
+				"The annotation %q on service %s is reserved for use by Blimp.", key, svcName)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+			return errors.NewFriendlyError("The annotation %q on service %s is invalid: %s",
+				key, svcName, strings.Join(errs, ", "))
+		}
+		p.pod.Annotations[key] = val
+		passthrough = append(passthrough, key)
+	}
+	if len(passthrough) != 0 {
+		sort.Strings(passthrough)
+		p.pod.Annotations[metadata.PassthroughAnnotationsKey] = strings.Join(passthrough, ",")
+	}
+
+	for key, val := range labels {
+		if isReserved(key) {
+			return errors.NewFriendlyError(
+				"The label %q on service %s is reserved for use by Blimp.", key, svcName)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+			return errors.NewFriendlyError("The label %q on service %s is invalid: %s",
+				key, svcName, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(val); len(errs) != 0 {
+			return errors.NewFriendlyError("The value of label %q on service %s is invalid: %s",
+				key, svcName, strings.Join(errs, ", "))
+		}
+		p.pod.Labels[key] = val
+	}
+	return nil
+}
+
+func (p *podSpec) addVolumeSeeder(nodeControllerIP string, volumes []composeTypes.ServiceVolumeConfig) {
+	// Write blimp-cp and cp to a volume so that we can access them from the
+	// user's image.
+	p.addVolume(corev1.Volume{
+		Name: "vcpbin",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	vcpBinMount := []corev1.VolumeMount{{
+		Name:      "vcpbin",
+		MountPath: "/vcpbin",
+	}}
+
+	p.addInitContainers(
+		corev1.Container{
+			Name:  kube.ContainerNameCopyVCP,
+			Image: version.InitImage,
+			Command: []string{"sh", "-c",
+				"/bin/cp /bin/busybox.static /vcpbin/cp && " +
+					"/bin/cp /bin/blimp-vcp /vcpbin/blimp-cp"},
+			VolumeMounts: vcpBinMount,
+		},
+	)
+
+	// Mount all the volumes for the namespace so that vcp can copy into them.
+	p.addVolume(volume.PersistentVolume)
+
+	// Configure the container that executes the copy from the image filesystem
+	// into the volume.
+	var vcpArgs []string
+	for _, v := range volumes {
+		vcpTarget := filepath.Join("/pv", volume.NamedVolumeDir(v.Source))
+		vcpArgs = append(vcpArgs, fmt.Sprintf("%s:%s", v.Target, vcpTarget))
+	}
+
+	// In GKE, host path volumes are created as root.
+	hostPathOwner := int64(0)
+	p.addInitContainers(
+		corev1.Container{
+			Name:            kube.ContainerNameInitializeVolumeFromImage,
+			Image:           p.image,
+			ImagePullPolicy: p.pullPolicy,
+			Command:         append([]string{"/vcpbin/blimp-cp", "/vcpbin/cp"}, vcpArgs...),
+			SecurityContext: &corev1.SecurityContext{
+				// Run the container as the directory's owner so that vcp can
+				// write to it. Note that this UID doesn't need to exist within
+				// the container -- it just needs to match up with the UID in
+				// the host's filesystem.
+				RunAsUser: &hostPathOwner,
+			},
+			VolumeMounts: append(vcpBinMount, corev1.VolumeMount{
+				Name:      volume.PersistentVolume.Name,
+				MountPath: "/pv",
+			}),
+			Env: []corev1.EnvVar{
+				{
+					Name: "NAMESPACE",
+					ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: "metadata.namespace",
+						},
+					},
+				},
+
+				// vcp reports its progress to the node controller so that
+				// it's shown in the service's status.
+				{
+					Name:  "NODE_CONTROLLER_HOST",
+					Value: nodeControllerIP,
+				},
+				{
+					Name: "POD_NAME",
+					ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: "metadata.name",
+						},
+					},
+				},
+
+				// vcp records the hash of the contents it copied into each
+				// volume, so that unchanged volumes are skipped.
+				{
+					Name:  "VCP_STATE_DIR",
+					Value: "/pv/" + volume.VCPStateDir,
+				},
+			},
+		},
+	)
+}
+
+// addSysctls sets the sysctls for the pod. Only safelisted sysctls are set --
+// Validate rejects any others before the sandbox is deployed.
+func (p *podSpec) addSysctls(sysctls composeTypes.Mapping) {
+	var keys []string
+	for key := range sysctls {
+		if isAllowedSysctl(key) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+
+	if p.pod.Spec.SecurityContext == nil {
+		p.pod.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	for _, key := range keys {
+		p.pod.Spec.SecurityContext.Sysctls = append(p.pod.Spec.SecurityContext.Sysctls,
+			corev1.Sysctl{Name: key, Value: sysctls[key]})
+	}
+}
+
+// addNofileLimit sets the open file limit of the service's processes.
+// Kubernetes doesn't support ulimits, so blimp-prlimit is copied into the
+// container, and run as a postStart hook to update the limits of the
+// processes in the container.
+func (p *podSpec) addNofileLimit(soft, hard int) {
+	p.addVolume(corev1.Volume{
+		Name: "prlimitbin",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	prlimitBinMount := corev1.VolumeMount{
+		Name:      "prlimitbin",
+		MountPath: "/prlimitbin",
+	}
+
+	p.addInitContainers(
+		corev1.Container{
+			Name:         kube.ContainerNameCopyPrlimit,
+			Image:        version.InitImage,
+			Command:      []string{"/bin/cp", "/bin/blimp-prlimit", "/prlimitbin/blimp-prlimit"},
+			VolumeMounts: []corev1.VolumeMount{prlimitBinMount},
+		},
+	)
+
+	container := &p.pod.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, prlimitBinMount)
+	if container.Lifecycle == nil {
+		container.Lifecycle = &corev1.Lifecycle{}
+	}
+	container.Lifecycle.PostStart = &corev1.Handler{
+		Exec: &corev1.ExecAction{
+			Command: []string{"/prlimitbin/blimp-prlimit",
+				strconv.Itoa(soft), strconv.Itoa(hard)},
+		},
+	}
+}
+
+func (p *podSpec) addRuntimeContainer(svc composeTypes.ServiceConfig, dnsIP string,
+	svcAliasesMapping map[string][]string, namedBindVolumes map[string]string) error {
+
+	p.pod.Namespace = p.namespace
+	p.pod.Name = names.ToDNS1123(svc.Name)
+	p.pod.Labels = map[string]string{
+		"blimp.service":               svc.Name,
+		"blimp.customerPod":           "true",
+		affinity.ColocateNamespaceKey: p.namespace,
+	}
+
+	var volumeMounts []corev1.VolumeMount
+	var tmpfsMounts []tmpfsMount
+	for _, v := range svc.Volumes {
+		var subPath string
+		switch v.Type {
+		case composeTypes.VolumeTypeVolume:
+			source, ok := namedBindVolumes[v.Source]
+			if ok {
+				subPath = volume.BindVolumeDir(source)
+			} else {
+				subPath = volume.NamedVolumeDir(v.Source)
+			}
+		case composeTypes.VolumeTypeBind:
+			subPath = volume.BindVolumeDir(v.Source)
+		case composeTypes.VolumeTypeTmpfs:
+			mount := tmpfsMount{path: v.Target}
+			if v.Tmpfs != nil {
+				mount.size = v.Tmpfs.Size
+			}
+			tmpfsMounts = append(tmpfsMounts, mount)
+			continue
+		}
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      volume.PersistentVolume.Name,
+			SubPath:   subPath,
+			MountPath: v.Target,
+		})
+	}
+
+	if len(volumeMounts) != 0 {
+		p.addVolume(volume.PersistentVolume)
+	}
+
+	for _, tmpfs := range svc.Tmpfs {
+		mount, err := parseTmpfs(tmpfs)
+		if err != nil {
+			return errors.NewFriendlyError("Invalid tmpfs entry (%s) for service %s: %s",
+				tmpfs, svc.Name, err)
+		}
+		tmpfsMounts = append(tmpfsMounts, mount)
+	}
+
+	if svc.ShmSize != "" {
+		size, err := parseByteSize(svc.ShmSize)
+		if err != nil {
+			return errors.NewFriendlyError("Invalid shm_size (%s) for service %s: %s",
+				svc.ShmSize, svc.Name, err)
+		}
+		tmpfsMounts = append(tmpfsMounts, tmpfsMount{path: "/dev/shm", size: size})
+	}
+
+	// Back tmpfs mounts with memory. Kubernetes doesn't limit the size of
+	// the tmpfs itself, but it evicts the pod if the volume grows larger than
+	// the size limit.
+	for i, mount := range tmpfsMounts {
+		name := fmt.Sprintf("tmpfs-%d", i)
+		emptyDir := &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}
+		if mount.size > 0 {
+			emptyDir.SizeLimit = resource.NewQuantity(mount.size, resource.BinarySI)
+		}
+
+		p.addVolume(corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{EmptyDir: emptyDir},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: mount.path,
+		})
+	}
+
+	var securityContext *corev1.SecurityContext
+	if svc.User != "" {
+		securityContext = &corev1.SecurityContext{}
+		var ids []int
+		for _, idStr := range strings.Split(svc.User, ":") {
+			id, err := strconv.Atoi(idStr)
+			if err != nil {
+				return errors.NewFriendlyError("Invalid user field (%s) for service %s.\n"+
+					"Only numeric IDs are allowed. Please convert the user and group name to a numeric ID.",
+					svc.User, svc.Name)
+			}
+
+			ids = append(ids, id)
+		}
+
+		// The first ID is the user, and the second (optional) ID is the group.
+		switch len(ids) {
+		case 1:
+			user := int64(ids[0])
+			securityContext.RunAsUser = &user
+		case 2:
+			user := int64(ids[0])
+			group := int64(ids[1])
+			securityContext.RunAsUser = &user
+			securityContext.RunAsGroup = &group
+		default:
+			return errors.NewFriendlyError("Invalid user field (%s) for service %s.\n"+
+				"Expected at most two values.", svc.User, svc.Name)
+		}
+	}
+
+	p.pod.Spec.Containers = []corev1.Container{
+		{
+			Args:            svc.Command,
+			Command:         svc.Entrypoint,
+			Env:             toEnvVars(svc.Environment),
+			Image:           p.image,
+			ImagePullPolicy: p.pullPolicy,
+			Name:            names.ToDNS1123(svc.Name),
+			SecurityContext: securityContext,
+			Stdin:           svc.StdinOpen,
+			TTY:             svc.Tty,
+			VolumeMounts:    volumeMounts,
+			WorkingDir:      svc.WorkingDir,
+			ReadinessProbe:  toReadinessProbe(svc.HealthCheck),
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					"cpu": resource.MustParse(
+						fmt.Sprintf("%d%s", CPULimit, CPURequestUnits)),
+					"memory": resource.MustParse("16Gi"),
+				},
+				// If Requests are not set, they will default to the
+				// same as the Limits, which are too high.
+				Requests: corev1.ResourceList{
+					"cpu": resource.MustParse(
+						fmt.Sprintf("%d%s", CPURequest, CPURequestUnits)),
+					"memory": resource.MustParse(
+						fmt.Sprintf("%d%s", MemoryRequest, MemoryRequestUnits)),
+				},
+			},
+		},
+	}
+
+	// Blimp doesn't support multiple networks, so just aggregate the
+	// aliases from all of them.
+	var aliases []string
+	for _, network := range svc.Networks {
+		if network == nil {
+			continue
+		}
+		aliases = append(aliases, network.Aliases...)
+	}
+
+	aliases = append(aliases, svcAliasesMapping[svc.Name]...)
+	if svc.ContainerName != "" {
+		aliases = append(aliases, svc.ContainerName)
+	}
+
+	p.pod.Annotations = map[string]string{}
+	if len(aliases) > 0 {
+		p.pod.Annotations[metadata.AliasesKey] = metadata.Aliases(aliases)
+	}
+
+	// Set the pod's hostname.
+	// Ignore the hostname setting if it's not a valid Kubernetes hostname.
+	// Although this may break some applications, it's better than aborting the deployment entirely since
+	// most applications don't seem to rely on the container's hostname.
+	if svc.Hostname != "" && len(validation.IsDNS1123Label(svc.Hostname)) == 0 {
+		p.pod.Spec.Hostname = svc.Hostname
+	} else if len(validation.IsDNS1123Label(svc.Name)) == 0 {
+		p.pod.Spec.Hostname = svc.Name
+	}
+
+	// Collect a map of ips to their aliases.
+	aliasMap := map[string][]string{}
+	for _, hostAlias := range svc.ExtraHosts {
+		aliasParts := strings.Split(hostAlias, ":")
+		if len(aliasParts) != 2 {
+			return errors.NewFriendlyError("Malformed extra_hosts alias: %s.\n"+
+				"Expected format hostname:ip", hostAlias)
+		}
+
+		hostname := aliasParts[0]
+		ip := aliasParts[1]
+		aliasMap[ip] = append(aliasMap[ip], hostname)
+	}
+
+	var hostAliases []corev1.HostAlias
+	for ip, hostnames := range aliasMap {
+		hostAliases = append(hostAliases, corev1.HostAlias{
+			IP:        ip,
+			Hostnames: hostnames,
+		})
+	}
+
+	// Sort for consistency.
+	sort.Slice(hostAliases, func(i, j int) bool { return hostAliases[i].IP < hostAliases[j].IP })
+	p.pod.Spec.HostAliases = hostAliases
+
+	// Set the pod's restart policy.
+	p.pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+	switch svc.Restart {
+	case "no":
+		p.pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+	case "always", "unless-stopped":
+		p.pod.Spec.RestartPolicy = corev1.RestartPolicyAlways
+	case "on-failure":
+		p.pod.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+	}
+
+	// Match Docker's shutdown behavior. Kubernetes only supports whole
+	// seconds, so round up rather than stopping the service early.
+	if svc.StopGracePeriod != nil {
+		gracePeriod := int64(math.Ceil(time.Duration(*svc.StopGracePeriod).Seconds()))
+		p.pod.Spec.TerminationGracePeriodSeconds = &gracePeriod
+	}
+
+	// Docker's init process reaps zombie processes. When the process
+	// namespace is shared, the pod's pause container does the same.
+	if svc.Init != nil && *svc.Init {
+		p.pod.Spec.ShareProcessNamespace = svc.Init
+	}
+
+	if svc.StopSignal != "" {
+		lifecycle, err := toStopSignalLifecycle(svc.StopSignal, svc.Init != nil && *svc.Init)
+		if err != nil {
+			return errors.NewFriendlyError("Invalid stop_signal (%s) for service %s: %s",
+				svc.StopSignal, svc.Name, err)
+		}
+		p.pod.Spec.Containers[0].Lifecycle = lifecycle
+	}
+
+	// Setup DNS.
+	p.pod.Spec.DNSPolicy = corev1.DNSNone
+	p.pod.Spec.DNSConfig = &corev1.PodDNSConfig{
+		Nameservers: []string{dnsIP},
+		// TODO: There's Searches and Options, look into how to replicate these.
+	}
+
+	// Setup image credentials.
+	p.pod.Spec.ImagePullSecrets = []corev1.LocalObjectReference{
+		{Name: "registry-auth"},
+	}
+	p.pod.Spec.ServiceAccountName = "pod-runner"
+
+	// Disable the environment variables that are automatically
+	// added by Kubernetes (e.g. SANDBOX_SERVICE_PORT).
+	p.pod.Spec.EnableServiceLinks = falsePtr()
+
+	return nil
+}
+
+type tmpfsMount struct {
+	path string
+
+	// size is the maximum size of the mount in bytes. The size is unlimited
+	// if it's zero.
+	size int64
+}
+
+// parseTmpfs parses entries in the `tmpfs` field, which may contain mount
+// options in the same format as `docker run --tmpfs`. For example,
+// `/run:rw,size=64m`. All options except for the size are ignored.
+func parseTmpfs(tmpfs string) (tmpfsMount, error) {
+	parts := strings.SplitN(tmpfs, ":", 2)
+	mount := tmpfsMount{path: parts[0]}
+	if !filepath.IsAbs(mount.path) {
+		return tmpfsMount{}, errors.New("mount path must be absolute")
+	}
+
+	if len(parts) == 2 {
+		for _, opt := range strings.Split(parts[1], ",") {
+			if !strings.HasPrefix(opt, "size=") {
+				continue
+			}
+
+			size, err := parseByteSize(strings.TrimPrefix(opt, "size="))
+			if err != nil {
+				return tmpfsMount{}, err
+			}
+			mount.size = size
+		}
+	}
+	return mount, nil
+}
+
+// parseByteSize parses sizes in the format used by Docker, such as `64m` or
+// `1gb`. Units are powers of 1024, and sizes without a unit are in bytes.
+func parseByteSize(sizeStr string) (int64, error) {
+	str := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(sizeStr)), "b")
+	multiplier := int64(1)
+	if len(str) != 0 {
+		switch str[len(str)-1] {
+		case 'k':
+			multiplier = 1 << 10
+		case 'm':
+			multiplier = 1 << 20
+		case 'g':
+			multiplier = 1 << 30
+		case 't':
+			multiplier = 1 << 40
+		}
+	}
+	if multiplier != 1 {
+		str = str[:len(str)-1]
+	}
+
+	size, err := strconv.ParseFloat(str, 64)
+	if err != nil || size < 0 {
+		return 0, errors.New("invalid size %q", sizeStr)
+	}
+	return int64(size * float64(multiplier)), nil
+}
+
+// SetCPUMultiplier scales the CPU resources of the service's container by
+// `multiplier`, relative to the default resources. A multiplier of 1 resets
+// the container to the default resources.
+func SetCPUMultiplier(pod *corev1.Pod, multiplier float64) {
+	if len(pod.Spec.Containers) == 0 {
+		return
+	}
+
+	resources := &pod.Spec.Containers[0].Resources
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	if resources.Limits == nil {
+		resources.Limits = corev1.ResourceList{}
+	}
+	resources.Requests["cpu"] = *resource.NewMilliQuantity(int64(CPURequest*multiplier), resource.DecimalSI)
+	resources.Limits["cpu"] = *resource.NewMilliQuantity(int64(CPULimit*multiplier), resource.DecimalSI)
+
+	if multiplier == 1 {
+		delete(pod.Annotations, metadata.CPUMultiplierKey)
+		return
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[metadata.CPUMultiplierKey] = strconv.FormatFloat(multiplier, 'f', -1, 64)
+}
+
+func toEnvVars(vars composeTypes.MappingWithEquals) (kubeVars []corev1.EnvVar) {
+	for k, vPtr := range vars {
+		// vPtr may be nil if only the key is specified.
+		var v string
+		if vPtr != nil {
+			v = *vPtr
+		}
+
+		// Escape dollar signs to disable interpolation by Kubernetes. Any variable
+		// interpolation will have occurred by now during the initial parsing of
+		// the Docker Compose file.
+		v = strings.Replace(v, "$", "$$", -1)
+
+		kubeVars = append(kubeVars, corev1.EnvVar{
+			Name:  k,
+			Value: v,
+		})
+	}
+
+	// Sort for consistency to avoid unnecessary pod restarts.
+	sort.Slice(kubeVars, func(i, j int) bool {
+		return kubeVars[i].Name < kubeVars[j].Name
+	})
+	return
+}
+
+func toReadinessProbe(healthCheck *composeTypes.HealthCheckConfig) *corev1.Probe {
+	if healthCheck == nil || len(healthCheck.Test) <= 1 {
+		return nil
+	}
+
+	var command []string
+	switch healthCheck.Test[0] {
+	case "NONE":
+		return nil
+	case "CMD":
+		command = healthCheck.Test[1:]
+	case "CMD-SHELL":
+		command = []string{"sh", "-c", healthCheck.Test[1]}
+	default:
+		// XXX: We should really inform the user that we failed to parse the
+		// healthcheck, but we currently don't have a way of sending warnings
+		// back to the CLI.
+		log.WithField("command", command).Warn("Ignoring healthcheck with unrecognized command")
+		return nil
+	}
+
+	probe := &corev1.Probe{
+		Handler: corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: command,
+			},
+		},
+
+		// We use the Docker defaults for the healthcheck settings, rather than
+		// the Kubernetes defaults.
+		// By default, Kubernetes checks more aggressively (e.g. its default
+		// check interval is 10 seconds), which exacerbates Docker performance
+		// issues with exec probes.
+		//
+		// We don't know the root cause of the performance issues, but for some
+		// possible related issues see:
+		// * https://github.com/kubernetes/kubernetes/issues/82440
+		// * https://serverfault.com/questions/973817/google-cloud-kuberbetes-run-away-systemd-100-cpu-usage
+		TimeoutSeconds:      30,
+		PeriodSeconds:       30,
+		InitialDelaySeconds: 0,
+		FailureThreshold:    3,
+	}
+
+	if healthCheck.Timeout != nil {
+		probe.TimeoutSeconds = int32(time.Duration(*healthCheck.Timeout).Seconds())
+	}
+	if healthCheck.Interval != nil {
+		probe.PeriodSeconds = int32(time.Duration(*healthCheck.Interval).Seconds())
+	}
+	if healthCheck.StartPeriod != nil {
+		probe.InitialDelaySeconds = int32(time.Duration(*healthCheck.StartPeriod).Seconds())
+	}
+	if healthCheck.Retries != nil {
+		probe.FailureThreshold = int32(*healthCheck.Retries)
+	}
+
+	return probe
+}
+
+// findMainProcessScript sets $main to the PID of the container's main process
+// when the pod's process namespace is shared. PID 1 is then the pod's pause
+// container, so the main process is the container's oldest process that was
+// started from outside the namespace (its parent PID is 0). Processes in other
+// containers are excluded by comparing their root directories.
+const findMainProcessScript = `main=""; ` +
+	`for d in /proc/[0-9]*; do ` +
+	`p=${d#/proc/}; ppid=""; ` +
+	`while read -r k v; do [ "$k" = PPid: ] && ppid=$v; done 2>/dev/null < "$d/status"; ` +
+	`[ "$p" != 1 ] && [ "$p" != "$$" ] && [ "$ppid" = 0 ] && ` +
+	`[ "$d/root/" -ef /proc/$$/root/ ] && ` +
+	`{ [ -z "$main" ] || [ "$p" -lt "$main" ]; } && main=$p; ` +
+	`done`
+
+// toStopSignalLifecycle returns a lifecycle hook that sends the given signal
+// to the service before it's stopped. Kubernetes always stops containers with
+// SIGTERM, so the hook sends the stop signal to the service's main process
+// first, and then waits for it to exit. If the service doesn't exit within the
+// termination grace period, Kubernetes stops it as usual.
+//
+// The hook requires `sh` and `kill` in the image.
+func toStopSignalLifecycle(signal string, sharedProcessNamespace bool) (*corev1.Lifecycle, error) {
+	signal = strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	if signal == "" {
+		return nil, errors.New("empty signal")
+	}
+
+	for _, c := range signal {
+		if !unicode.IsDigit(c) && !unicode.IsUpper(c) {
+			return nil, errors.New("unknown signal")
+		}
+	}
+
+	// Kubernetes already sends SIGTERM.
+	if signal == "TERM" || signal == "15" {
+		return nil, nil
+	}
+
+	signalFlag := "-s " + signal
+	if _, err := strconv.Atoi(signal); err == nil {
+		signalFlag = "-" + signal
+	}
+
+	// Only the main process is signaled, as with Docker. It's responsible for
+	// stopping its children.
+	var script string
+	target := "1"
+	if sharedProcessNamespace {
+		script = findMainProcessScript + "; "
+		target = `"$main"`
+	}
+	script += fmt.Sprintf("kill %s %s; while kill -0 %s 2>/dev/null; do sleep 1; done",
+		signalFlag, target, target)
+
+	return &corev1.Lifecycle{
+		PreStop: &corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sh", "-c", script},
+			},
+		},
+	}, nil
+}
+
+func marshalDependencies(dependsOn composeTypes.DependsOnConfig, links []string) []*wait.ServiceCondition {
+	pbDeps := []*wait.ServiceCondition{}
+	for name, condition := range dependsOn {
+		pbDeps = append(pbDeps, &wait.ServiceCondition{
+			Service:   name,
+			Condition: condition.Condition,
+		})
+	}
+
+	for _, link := range links {
+		var service string
+		switch linkParts := strings.Split(link, ":"); len(linkParts) {
+		case 1, 2:
+			service = linkParts[0]
+		default:
+			log.WithField("link", link).Warn("Malformed link. Ignoring.")
+			continue
+		}
+
+		depExists := func() bool {
+			for _, dep := range pbDeps {
+				if dep.Service == service {
+					return true
+				}
+			}
+			return false
+		}
+
+		// Any dependency conditions specified in `depends_on` take precedence.
+		if !depExists() {
+			pbDeps = append(pbDeps, &wait.ServiceCondition{
+				Service:   service,
+				Condition: composeTypes.ServiceConditionStarted,
+			})
+		}
+	}
+
+	sort.Slice(pbDeps, func(i, j int) bool {
+		return strings.Compare(pbDeps[i].Service, pbDeps[j].Service) > 0
+	})
+
+	return pbDeps
+}
+
+func (p *podSpec) sanitize() {
+	// Retain the same order to avoid unnecessary changes to the pod spec.
+	var volumes []corev1.Volume
+	volumesSet := map[string]struct{}{}
+	for _, volume := range p.pod.Spec.Volumes {
+		if _, ok := volumesSet[volume.Name]; ok {
+			continue
+		}
+
+		volumes = append(volumes, volume)
+		volumesSet[volume.Name] = struct{}{}
+	}
+
+	p.pod.Spec.Volumes = volumes
+}
+
+// Each pod has a corresponding ConfigMap containing the dependencies
+// it requires before it should boot. This ConfigMap is mounted as a
+// volume into the pod's init container. The init container passes it
+// to the node controller, which blocks boot until the requirements
+// are met.
+func (p *podSpec) addWaiter(nodeControllerIP, svcName, waitType string, spec wait.WaitSpec,
+	timeout time.Duration) error {
+	waitSpecBytes, err := proto.Marshal(&spec)
+	if err != nil {
+		return errors.WithContext("marshal wait spec", err)
+	}
+
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: p.namespace,
+			Name:      names.ToDNS1123(fmt.Sprintf("wait-spec-%s-%s", waitType, svcName)),
+		},
+		BinaryData: map[string][]byte{
+			"wait-spec": waitSpecBytes,
+		},
+	}
+	p.addConfigMap(configMap)
+
+	volume := corev1.Volume{
+		Name: configMap.Name,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMap.Name,
+				},
+				Items: []corev1.KeyToPath{
+					{Key: "wait-spec", Path: "wait-spec"},
+				},
+			},
+		},
+	}
+	p.addVolume(volume)
+
+	container := corev1.Container{
+		Name:  waitType,
+		Image: version.InitImage,
+		Env: []corev1.EnvVar{
+			{
+				Name:  "NODE_CONTROLLER_HOST",
+				Value: nodeControllerIP,
+			},
+			{
+				Name: "NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.namespace",
+					},
+				},
+			},
+
+			// Trigger a restart if the wait spec changes.
+			{
+				Name:  "WAIT_SPEC_HASH",
+				Value: hash.Bytes(waitSpecBytes),
+			},
+			{
+				Name:  WaitTimeoutEnv,
+				Value: timeout.String(),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      volume.Name,
+				MountPath: "/etc/blimp",
+			},
+		},
+	}
+	p.addInitContainers(container)
+
+	return nil
+}
+
+func (p *podSpec) addVolume(v corev1.Volume) {
+	p.pod.Spec.Volumes = append(p.pod.Spec.Volumes, v)
+}
+
+func (p *podSpec) addInitContainers(containers ...corev1.Container) {
+	p.pod.Spec.InitContainers = append(p.pod.Spec.InitContainers, containers...)
+}
+
+func (p *podSpec) addConfigMap(cm corev1.ConfigMap) {
+	p.configMaps = append(p.configMaps, cm)
+}
+
+func falsePtr() *bool {
+	f := false
+	return &f
+}
+
+func remove(strs []string, toRemove string) (removed []string) {
+	for _, str := range strs {
+		if str != toRemove {
+			removed = append(removed, str)
+		}
+	}
+	return removed
+}
+
+// addMTLSCerts mounts the service's certificates into its container, and
+// points to them with environment variables.
+// addSecretEnv references the service's secret environment variables from
+// its secret rather than setting their values directly. The values are hashed
+// into an annotation so that the pod is restarted when they change.
+func (p *podSpec) addSecretEnv(svc string, secretEnv map[string]string) {
+	container := &p.pod.Spec.Containers[0]
+	for i, env := range container.Env {
+		if _, ok := secretEnv[env.Name]; !ok {
+			continue
+		}
+
+		container.Env[i] = corev1.EnvVar{
+			Name: env.Name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: SecretEnvName(svc),
+					},
+					Key: env.Name,
+				},
+			},
+		}
+	}
+
+	// json.Marshal sorts map keys, so the hash is deterministic.
+	secretEnvJSON, err := json.Marshal(secretEnv)
+	if err != nil {
+		// Marshalling a map of strings never fails, but make sure the pod is
+		// still restarted if it does.
+		secretEnvJSON = []byte(err.Error())
+	}
+	p.pod.Annotations[metadata.SecretEnvHashKey] = hash.Bytes(secretEnvJSON)
+}
+
+// addReadyLogPattern gates the pod's readiness on the cluster manager finding
+// a log line that matches the pattern.
+func (p *podSpec) addReadyLogPattern(pattern string) {
+	p.pod.Annotations[metadata.ReadyLogPatternKey] = pattern
+	p.pod.Spec.ReadinessGates = append(p.pod.Spec.ReadinessGates,
+		corev1.PodReadinessGate{ConditionType: kube.LogReadyCondition})
+}
+
+func (p *podSpec) addMTLSCerts(svc string) {
+	volumeName := "blimp-mtls"
+	p.addVolume(corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: MTLSSecretName(svc),
+			},
+		},
+	})
+
+	container := &p.pod.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      volumeName,
+		MountPath: MTLSMountPath,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: "BLIMP_MTLS_CA_FILE", Value: MTLSMountPath + "/" + MTLSCACertKey},
+		corev1.EnvVar{Name: "BLIMP_MTLS_CERT_FILE", Value: MTLSMountPath + "/" + MTLSCertKey},
+		corev1.EnvVar{Name: "BLIMP_MTLS_KEY_FILE", Value: MTLSMountPath + "/" + MTLSKeyKey},
+	)
+}
+
+// addSSHAgent runs a sidecar that forwards connections to the SSH agent on
+// the user's machine through the node controller.
+func (p *podSpec) addSSHAgent(nodeControllerIP string) {
+	volumeName := "blimp-ssh-agent"
+	p.addVolume(corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	mount := corev1.VolumeMount{
+		Name:      volumeName,
+		MountPath: SSHAgentMountPath,
+	}
+	sockEnv := corev1.EnvVar{Name: "SSH_AUTH_SOCK", Value: SSHAgentMountPath + "/agent.sock"}
+
+	container := &p.pod.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, mount)
+	container.Env = append(container.Env, sockEnv)
+
+	p.pod.Spec.Containers = append(p.pod.Spec.Containers, corev1.Container{
+		Name:    kube.ContainerNameSSHAgent,
+		Image:   version.InitImage,
+		Command: []string{"/bin/blimp-ssh-agent"},
+		Env: []corev1.EnvVar{
+			{Name: "NODE_CONTROLLER_HOST", Value: nodeControllerIP},
+			sockEnv,
+		},
+		VolumeMounts: []corev1.VolumeMount{mount},
+	})
+	p.pod.Labels[kube.SSHAgentLabel] = "true"
+}
+
+// addDockerSocket runs a sidecar that forwards the Docker API requests made
+// by the service to the node controller.
+func (p *podSpec) addDockerSocket(nodeControllerIP string) {
+	volumeName := "blimp-docker-socket"
+	p.addVolume(corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	mount := corev1.VolumeMount{
+		Name:      volumeName,
+		MountPath: DockerSocketMountPath,
+	}
+	hostEnv := corev1.EnvVar{Name: "DOCKER_HOST", Value: "unix://" + DockerSocketMountPath + "/docker.sock"}
+
+	container := &p.pod.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, mount)
+	container.Env = append(container.Env,
+		hostEnv,
+		// Ryuk cleans up after testcontainers by mounting the Docker socket,
+		// which isn't supported. The containers are cleaned up when the
+		// service is removed instead.
+		corev1.EnvVar{Name: "TESTCONTAINERS_RYUK_DISABLED", Value: "true"},
+	)
+
+	p.pod.Spec.Containers = append(p.pod.Spec.Containers, corev1.Container{
+		Name:    kube.ContainerNameDockerSocket,
+		Image:   version.InitImage,
+		Command: []string{"/bin/blimp-docker-socket"},
+		Env: []corev1.EnvVar{
+			{Name: "NODE_CONTROLLER_HOST", Value: nodeControllerIP},
+			hostEnv,
+		},
+		VolumeMounts: []corev1.VolumeMount{mount},
+	})
+	p.pod.Labels[kube.DockerSocketLabel] = "true"
+}
@@ -0,0 +1,465 @@
+package compose
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	composeTypes "github.com/kelda/compose-go/types"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kelda/blimp/pkg/hash"
+	"github.com/kelda/blimp/pkg/kube"
+	"github.com/kelda/blimp/pkg/metadata"
+	"github.com/kelda/blimp/pkg/proto/wait"
+)
+
+func TestWaitSpecHash(t *testing.T) {
+	spec := wait.WaitSpec{
+		DependsOn: marshalDependencies(
+			composeTypes.DependsOnConfig{},
+			[]string{"foo", "bar", "baz", "quux", "quuz"},
+		)}
+
+	getHash := func() string {
+		waitSpecBytes, err := proto.Marshal(&spec)
+		if err != nil {
+			panic(err)
+		}
+		return hash.Bytes(waitSpecBytes)
+	}
+
+	lastHash := getHash()
+	for i := 0; i < 100; i++ {
+		if h := getHash(); h != lastHash {
+			t.Errorf("got different hashes: %s and %s", h, lastHash)
+		}
+	}
+}
+
+func TestToKubernetesMaxServices(t *testing.T) {
+	var cfg composeTypes.Project
+	for i := 0; i <= MaxServices; i++ {
+		cfg.Services = append(cfg.Services, composeTypes.ServiceConfig{
+			Name:  fmt.Sprintf("service-%d", i),
+			Image: "nginx",
+		})
+	}
+
+	if _, _, err := ToKubernetes(cfg, KubeOptions{}); err == nil {
+		t.Errorf("expected an error when deploying more than %d services", MaxServices)
+	}
+}
+
+func TestToKubernetesRuntimeOverrides(t *testing.T) {
+	gracePeriod := composeTypes.Duration(30 * time.Second)
+	init := true
+	cfg := composeTypes.Project{
+		Services: composeTypes.Services{
+			{
+				Name:            "web",
+				Image:           "nginx",
+				Entrypoint:      composeTypes.ShellCommand{"/docker-entrypoint.sh"},
+				Command:         composeTypes.ShellCommand{"nginx", "-g", "daemon off;"},
+				WorkingDir:      "/srv",
+				User:            "1000:1001",
+				StopGracePeriod: &gracePeriod,
+				StopSignal:      "SIGQUIT",
+				Init:            &init,
+			},
+		},
+	}
+
+	pods, _, err := ToKubernetes(cfg, KubeOptions{})
+	assert.NoError(t, err)
+	if !assert.Len(t, pods, 1) {
+		return
+	}
+
+	pod := pods[0]
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, []string{"/docker-entrypoint.sh"}, container.Command)
+	assert.Equal(t, []string{"nginx", "-g", "daemon off;"}, container.Args)
+	assert.Equal(t, "/srv", container.WorkingDir)
+	assert.Equal(t, int64(1000), *container.SecurityContext.RunAsUser)
+	assert.Equal(t, int64(1001), *container.SecurityContext.RunAsGroup)
+	assert.Equal(t, int64(30), *pod.Spec.TerminationGracePeriodSeconds)
+	assert.True(t, *pod.Spec.ShareProcessNamespace)
+	assert.Equal(t, []string{"sh", "-c", findMainProcessScript +
+		`; kill -s QUIT "$main"; while kill -0 "$main" 2>/dev/null; do sleep 1; done`},
+		container.Lifecycle.PreStop.Exec.Command)
+
+	// Grace periods are rounded up to whole seconds.
+	gracePeriod = composeTypes.Duration(1500 * time.Millisecond)
+	pods, _, err = ToKubernetes(cfg, KubeOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, pods, 1) {
+		assert.Equal(t, int64(2), *pods[0].Spec.TerminationGracePeriodSeconds)
+	}
+}
+
+func TestToStopSignalLifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		signal   string
+		shared   bool
+		expCmd   string
+		expError bool
+	}{
+		{name: "Default signal", signal: "SIGTERM"},
+		{name: "Signal name", signal: "SIGINT",
+			expCmd: "kill -s INT 1; while kill -0 1 2>/dev/null; do sleep 1; done"},
+		{name: "Short signal name", signal: "usr1",
+			expCmd: "kill -s USR1 1; while kill -0 1 2>/dev/null; do sleep 1; done"},
+		{name: "Signal number", signal: "9",
+			expCmd: "kill -9 1; while kill -0 1 2>/dev/null; do sleep 1; done"},
+		{name: "Shared process namespace", signal: "SIGINT", shared: true,
+			expCmd: findMainProcessScript +
+				`; kill -s INT "$main"; while kill -0 "$main" 2>/dev/null; do sleep 1; done`},
+		{name: "Invalid signal", signal: "INT; rm -rf /", expError: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			lifecycle, err := toStopSignalLifecycle(test.signal, test.shared)
+			if test.expError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			if test.expCmd == "" {
+				assert.Nil(t, lifecycle)
+				return
+			}
+			assert.Equal(t, &corev1.Lifecycle{
+				PreStop: &corev1.Handler{
+					Exec: &corev1.ExecAction{Command: []string{"sh", "-c", test.expCmd}},
+				},
+			}, lifecycle)
+		})
+	}
+}
+
+func TestSetCPUMultiplier(t *testing.T) {
+	pods, _, err := ToKubernetes(composeTypes.Project{
+		Services: composeTypes.Services{{Name: "web", Image: "nginx"}},
+	}, KubeOptions{CPUMultiplier: 2})
+	assert.NoError(t, err)
+	if !assert.Len(t, pods, 1) {
+		return
+	}
+
+	pod := pods[0]
+	resources := pod.Spec.Containers[0].Resources
+	assert.Equal(t, int64(2*CPURequest), resources.Requests.Cpu().MilliValue())
+	assert.Equal(t, int64(2*CPULimit), resources.Limits.Cpu().MilliValue())
+	assert.Equal(t, "2", pod.Annotations[metadata.CPUMultiplierKey])
+
+	SetCPUMultiplier(&pod, 1)
+	resources = pod.Spec.Containers[0].Resources
+	assert.Equal(t, int64(CPURequest), resources.Requests.Cpu().MilliValue())
+	assert.Equal(t, int64(CPULimit), resources.Limits.Cpu().MilliValue())
+	assert.NotContains(t, pod.Annotations, metadata.CPUMultiplierKey)
+}
+
+func TestToKubernetesTmpfs(t *testing.T) {
+	pods, _, err := ToKubernetes(composeTypes.Project{
+		Services: composeTypes.Services{
+			{
+				Name:    "chrome",
+				Image:   "browserless/chrome",
+				Tmpfs:   composeTypes.StringList{"/run", "/tmp:rw,size=64m"},
+				ShmSize: "1gb",
+			},
+		},
+	}, KubeOptions{})
+	assert.NoError(t, err)
+	if !assert.Len(t, pods, 1) {
+		return
+	}
+
+	pod := pods[0]
+	size := func(bytes int64) *resource.Quantity {
+		return resource.NewQuantity(bytes, resource.BinarySI)
+	}
+	assert.Equal(t, []corev1.Volume{
+		{
+			Name: "tmpfs-0",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+			},
+		},
+		{
+			Name: "tmpfs-1",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    corev1.StorageMediumMemory,
+					SizeLimit: size(64 << 20),
+				},
+			},
+		},
+		{
+			Name: "tmpfs-2",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    corev1.StorageMediumMemory,
+					SizeLimit: size(1 << 30),
+				},
+			},
+		},
+	}, pod.Spec.Volumes)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "tmpfs-0", MountPath: "/run"},
+		{Name: "tmpfs-1", MountPath: "/tmp"},
+		{Name: "tmpfs-2", MountPath: "/dev/shm"},
+	}, pod.Spec.Containers[0].VolumeMounts)
+}
+
+func TestToKubernetesSysctlsAndUlimits(t *testing.T) {
+	pods, _, err := ToKubernetes(composeTypes.Project{
+		Services: composeTypes.Services{
+			{
+				Name:  "nginx",
+				Image: "nginx",
+				Sysctls: composeTypes.Mapping{
+					"net.core.somaxconn":      "1024",
+					"net.ipv4.tcp_syncookies": "0",
+				},
+				Ulimits: map[string]*composeTypes.UlimitsConfig{
+					"nofile": {Soft: 20000, Hard: 40000},
+				},
+			},
+		},
+	}, KubeOptions{})
+	assert.NoError(t, err)
+	if !assert.Len(t, pods, 1) {
+		return
+	}
+
+	pod := pods[0]
+	assert.Equal(t, []corev1.Sysctl{
+		{Name: "net.core.somaxconn", Value: "1024"},
+		{Name: "net.ipv4.tcp_syncookies", Value: "0"},
+	}, pod.Spec.SecurityContext.Sysctls)
+
+	if assert.Len(t, pod.Spec.InitContainers, 1) {
+		assert.Equal(t, kube.ContainerNameCopyPrlimit, pod.Spec.InitContainers[0].Name)
+	}
+	assert.Equal(t, &corev1.Lifecycle{
+		PostStart: &corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"/prlimitbin/blimp-prlimit", "20000", "40000"},
+			},
+		},
+	}, pod.Spec.Containers[0].Lifecycle)
+	assert.Contains(t, pod.Spec.Containers[0].VolumeMounts,
+		corev1.VolumeMount{Name: "prlimitbin", MountPath: "/prlimitbin"})
+}
+
+func TestToKubernetesSecretEnv(t *testing.T) {
+	password := "hunter2"
+	debug := "true"
+	svc := composeTypes.ServiceConfig{
+		Name:  "api",
+		Image: "api",
+		Environment: composeTypes.MappingWithEquals{
+			"DATABASE_PASSWORD": &password,
+			"DEBUG":             &debug,
+		},
+		Extras: map[string]interface{}{
+			ExtensionKey: map[string]interface{}{
+				"secret_env": []interface{}{"DATABASE_PASSWORD"},
+			},
+		},
+	}
+
+	pods, _, err := ToKubernetes(composeTypes.Project{
+		Services: composeTypes.Services{svc},
+	}, KubeOptions{})
+	assert.NoError(t, err)
+	if !assert.Len(t, pods, 1) {
+		return
+	}
+
+	pod := pods[0]
+	assert.Equal(t, []corev1.EnvVar{
+		{
+			Name: "DATABASE_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "env-api"},
+					Key:                  "DATABASE_PASSWORD",
+				},
+			},
+		},
+		{Name: "DEBUG", Value: "true"},
+	}, pod.Spec.Containers[0].Env)
+	assert.NotEmpty(t, pod.Annotations[metadata.SecretEnvHashKey])
+
+	secretEnv, err := GetSecretEnv(svc)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"DATABASE_PASSWORD": "hunter2"}, secretEnv)
+
+	// Changing the secret should change the pod so that it gets restarted.
+	password = "hunter3"
+	updatedPods, _, err := ToKubernetes(composeTypes.Project{
+		Services: composeTypes.Services{svc},
+	}, KubeOptions{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, pod.Annotations[metadata.SecretEnvHashKey],
+		updatedPods[0].Annotations[metadata.SecretEnvHashKey])
+
+	// Variables that aren't in the environment can't be secret.
+	delete(svc.Environment, "DATABASE_PASSWORD")
+	_, err = GetSecretEnv(svc)
+	assert.Error(t, err)
+}
+
+func TestToKubernetesPodMetadata(t *testing.T) {
+	svc := composeTypes.ServiceConfig{
+		Name:  "api",
+		Image: "api",
+		Extras: map[string]interface{}{
+			ExtensionKey: map[string]interface{}{
+				"pod_annotations": map[string]interface{}{
+					"prometheus.io/scrape": "true",
+					"prometheus.io/port":   "9090",
+				},
+				"pod_labels": map[string]interface{}{"team": "payments"},
+			},
+		},
+	}
+
+	pods, _, err := ToKubernetes(composeTypes.Project{
+		Services: composeTypes.Services{svc},
+	}, KubeOptions{})
+	assert.NoError(t, err)
+	if !assert.Len(t, pods, 1) {
+		return
+	}
+
+	pod := pods[0]
+	assert.Equal(t, "true", pod.Annotations["prometheus.io/scrape"])
+	assert.Equal(t, "payments", pod.Labels["team"])
+	assert.Equal(t, "api", pod.Labels["blimp.service"])
+	assert.Equal(t, "prometheus.io/port,prometheus.io/scrape",
+		pod.Annotations[metadata.PassthroughAnnotationsKey])
+	assert.True(t, metadata.IsCustomPodAnnotation(pod.Annotations, "prometheus.io/port"))
+	assert.False(t, metadata.IsCustomPodAnnotation(pod.Annotations, "other"))
+
+	// Blimp's own labels can't be overridden.
+	svc.Extras[ExtensionKey] = map[string]interface{}{
+		"pod_labels": map[string]interface{}{"blimp.service": "other"},
+	}
+	_, _, err = ToKubernetes(composeTypes.Project{
+		Services: composeTypes.Services{svc},
+	}, KubeOptions{})
+	assert.Error(t, err)
+}
+
+func TestToKubernetesPullPolicy(t *testing.T) {
+	toPullPolicy := func(ext map[string]interface{}) (corev1.PullPolicy, error) {
+		svc := composeTypes.ServiceConfig{
+			Name:   "api",
+			Image:  "api",
+			Extras: map[string]interface{}{ExtensionKey: ext},
+		}
+		pods, _, err := ToKubernetes(composeTypes.Project{
+			Services: composeTypes.Services{svc},
+		}, KubeOptions{})
+		if err != nil {
+			return "", err
+		}
+		return pods[0].Spec.Containers[0].ImagePullPolicy, nil
+	}
+
+	policy, err := toPullPolicy(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.PullAlways, policy)
+
+	policy, err = toPullPolicy(map[string]interface{}{"pull_policy": "if_not_present"})
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.PullIfNotPresent, policy)
+
+	policy, err = toPullPolicy(map[string]interface{}{"pull_policy": "never"})
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.PullNever, policy)
+
+	_, err = toPullPolicy(map[string]interface{}{"pull_policy": "sometimes"})
+	assert.Error(t, err)
+}
+
+func TestToKubernetesReadyLogPattern(t *testing.T) {
+	svc := composeTypes.ServiceConfig{
+		Name:  "api",
+		Image: "api",
+		Extras: map[string]interface{}{
+			ExtensionKey: map[string]interface{}{"ready_log_pattern": `Listening on port \d+`},
+		},
+	}
+
+	pods, _, err := ToKubernetes(composeTypes.Project{
+		Services: composeTypes.Services{svc},
+	}, KubeOptions{})
+	assert.NoError(t, err)
+	if !assert.Len(t, pods, 1) {
+		return
+	}
+	assert.Equal(t, `Listening on port \d+`, pods[0].Annotations[metadata.ReadyLogPatternKey])
+	assert.Equal(t, []corev1.PodReadinessGate{{ConditionType: kube.LogReadyCondition}},
+		pods[0].Spec.ReadinessGates)
+	assert.False(t, kube.IsLogReady(pods[0]))
+
+	svc.Extras[ExtensionKey] = map[string]interface{}{"ready_log_pattern": "Listening ("}
+	_, _, err = ToKubernetes(composeTypes.Project{
+		Services: composeTypes.Services{svc},
+	}, KubeOptions{})
+	assert.Error(t, err)
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		str      string
+		exp      int64
+		expError bool
+	}{
+		{str: "1024", exp: 1024},
+		{str: "64m", exp: 64 << 20},
+		{str: "64MB", exp: 64 << 20},
+		{str: "1.5g", exp: 3 << 29},
+		{str: "2k", exp: 2048},
+		{str: "big", expError: true},
+		{str: "", expError: true},
+	}
+
+	for _, test := range tests {
+		size, err := parseByteSize(test.str)
+		if test.expError {
+			assert.Error(t, err, test.str)
+		} else {
+			assert.NoError(t, err, test.str)
+			assert.Equal(t, test.exp, size, test.str)
+		}
+	}
+}
+
+func TestParseTmpfs(t *testing.T) {
+	mount, err := parseTmpfs("/run")
+	assert.NoError(t, err)
+	assert.Equal(t, tmpfsMount{path: "/run"}, mount)
+
+	mount, err = parseTmpfs("/tmp:rw,noexec,size=100k")
+	assert.NoError(t, err)
+	assert.Equal(t, tmpfsMount{path: "/tmp", size: 100 << 10}, mount)
+
+	_, err = parseTmpfs("tmp")
+	assert.Error(t, err)
+
+	_, err = parseTmpfs("/tmp:size=lots")
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package compose
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kelda/blimp/pkg/metadata"
+)
+
+// setMeshCompatibility configures the pod so that it works when the cluster
+// runs Istio and injects its proxy sidecar into sandbox pods.
+func setMeshCompatibility(pod *corev1.Pod, svc composeTypes.ServiceConfig, nodeControllerIP string) {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+
+	// Istio redirects all outbound traffic through the proxy, including the
+	// traffic from init containers, which run before the proxy starts. Blimp's
+	// init containers wait for the node controller, so its traffic must
+	// bypass the proxy.
+	if nodeControllerIP != "" {
+		pod.Annotations[metadata.MeshExcludeOutboundIPRangesKey] = nodeControllerIP + "/32"
+	}
+
+	// Tunnels from `blimp up` are sent from the node controller, which isn't
+	// part of the mesh. Their traffic bypasses the proxy so that it isn't
+	// rejected when the mesh requires mutual TLS.
+	var tunneledPorts []string
+	for _, port := range svc.Ports {
+		if port.Published != 0 {
+			tunneledPorts = append(tunneledPorts, strconv.Itoa(int(port.Target)))
+		}
+	}
+	if len(tunneledPorts) != 0 {
+		sort.Strings(tunneledPorts)
+		pod.Annotations[metadata.MeshExcludeInboundPortsKey] = strings.Join(tunneledPorts, ",")
+	}
+
+	// Start the service after the proxy is ready. Otherwise, the service's
+	// first connections fail.
+	pod.Annotations[metadata.MeshProxyConfigKey] = `{"holdApplicationUntilProxyStarts": true}`
+}
@@ -0,0 +1,29 @@
+package compose
+
+import (
+	"testing"
+
+	composeTypes "github.com/kelda/compose-go/types"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kelda/blimp/pkg/metadata"
+)
+
+func TestSetMeshCompatibility(t *testing.T) {
+	var pod corev1.Pod
+	setMeshCompatibility(&pod, composeTypes.ServiceConfig{
+		Ports: []composeTypes.ServicePortConfig{
+			{Target: 8080, Published: 80},
+			{Target: 443, Published: 443},
+			// Unpublished ports aren't tunneled.
+			{Target: 9000},
+		},
+	}, "10.0.0.5")
+
+	assert.Equal(t, map[string]string{
+		metadata.MeshExcludeOutboundIPRangesKey: "10.0.0.5/32",
+		metadata.MeshExcludeInboundPortsKey:     "443,8080",
+		metadata.MeshProxyConfigKey:             `{"holdApplicationUntilProxyStarts": true}`,
+	}, pod.Annotations)
+}
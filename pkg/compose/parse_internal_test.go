@@ -1,6 +1,6 @@
-// This is not in dockercompose_test so that we can override the fs global
+// This is not in compose_test so that we can override the fs global
 // variable.
-package dockercompose
+package compose
 
 import (
 	"testing"
@@ -0,0 +1,89 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// PersonalOverrideName is the name of the Compose file that developers can use
+// to customize their own sandbox. It's loaded after all other Compose files,
+// and should be ignored by version control.
+const PersonalOverrideName = "blimp.override"
+
+// GetPaths returns the absolute paths to the Compose files that should be
+// loaded, in the order that they should be merged. If the personal override
+// file exists next to the main Compose file, it's always loaded last.
+func GetPaths(composePaths []string) (string, []string, error) {
+	composePath, overridePaths, err := getComposePaths(composePaths)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Don't load the personal override twice if it was explicitly specified.
+	for _, path := range append([]string{composePath}, overridePaths...) {
+		if IsPersonalOverride(path) {
+			return composePath, overridePaths, nil
+		}
+	}
+
+	personalOverridePrefix := filepath.Join(filepath.Dir(composePath), PersonalOverrideName)
+	for _, path := range []string{personalOverridePrefix + ".yaml", personalOverridePrefix + ".yml"} {
+		if _, err := os.Stat(path); err == nil {
+			overridePaths = append(overridePaths, path)
+			break
+		}
+	}
+	return composePath, overridePaths, nil
+}
+
+// IsPersonalOverride returns whether the path refers to a personal override
+// file.
+func IsPersonalOverride(path string) bool {
+	base := filepath.Base(path)
+	return base == PersonalOverrideName+".yaml" || base == PersonalOverrideName+".yml"
+}
+
+func getComposePaths(composePaths []string) (string, []string, error) {
+	getYamlFile := func(prefix string) (string, error) {
+		paths := []string{
+			prefix + ".yaml",
+			prefix + ".yml",
+		}
+
+		var err error
+		for _, path := range paths {
+			if _, err = os.Stat(path); err == nil {
+				return filepath.Abs(path)
+			}
+		}
+
+		// Return the error from the last path we tried to stat.
+		return "", err
+	}
+
+	// If the user doesn't explicitly specify any files, try to get the
+	// default files.
+	if len(composePaths) == 0 {
+		composePath, err := getYamlFile("docker-compose")
+		if err != nil {
+			return "", nil, err
+		}
+
+		var overridePaths []string
+		if overridePath, err := getYamlFile("docker-compose.override"); err == nil {
+			overridePaths = []string{overridePath}
+		}
+		return composePath, overridePaths, nil
+	}
+
+	var absPaths []string
+	for _, composePath := range composePaths {
+		p, err := filepath.Abs(composePath)
+		if err != nil {
+			return "", nil, err
+		}
+		absPaths = append(absPaths, p)
+	}
+
+	return absPaths[0], absPaths[1:], nil
+}
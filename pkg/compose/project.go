@@ -0,0 +1,149 @@
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kelda/compose-go/types"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// ProjectNameEnv is the environment variable that overrides the project's
+// `name` field, as with docker-compose.
+const ProjectNameEnv = "COMPOSE_PROJECT_NAME"
+
+var validProjectName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// getExplicitProjectName returns the project name set by ProjectNameEnv, or
+// by the top-level `name` field. The last Compose file that sets `name` takes
+// precedence. It returns an empty string if the name isn't set.
+func getExplicitProjectName(configFiles []types.ConfigFile, env map[string]string) (string, error) {
+	var name string
+	for _, configFile := range configFiles {
+		nameIntf, ok := configFile.Config["name"]
+		if !ok {
+			continue
+		}
+
+		nameStr, ok := nameIntf.(string)
+		if !ok {
+			return "", errors.NewFriendlyError(
+				"The project name in %s must be a string.", configFile.Filename)
+		}
+		name = nameStr
+	}
+
+	if envName := env[ProjectNameEnv]; envName != "" {
+		name = envName
+	}
+
+	if name != "" && !validProjectName.MatchString(name) {
+		return "", errors.NewFriendlyError(
+			"Invalid project name %q. Project names must only contain lowercase letters, "+
+				"numbers, dashes and underscores, and must start with a letter or number.", name)
+	}
+	return name, nil
+}
+
+// ProjectServiceName returns the name that the given service is deployed as
+// when it's part of the given project.
+func ProjectServiceName(project, service string) string {
+	if project == "" {
+		return service
+	}
+	return fmt.Sprintf("%s-%s", project, service)
+}
+
+// TrimProjectServiceName returns the name of the service within its project.
+// It's the inverse of ProjectServiceName.
+func TrimProjectServiceName(project, service string) string {
+	if project == "" {
+		return service
+	}
+	return strings.TrimPrefix(service, project+"-")
+}
+
+// ApplyProjectName renames the services and named volumes in the config so
+// that they don't conflict with other projects that are deployed into the same
+// sandbox. References between services are updated to match, so that the
+// services can still refer to each other by their original names.
+func ApplyProjectName(cfg *types.Project, project string) error {
+	if project == "" {
+		return nil
+	}
+
+	cfg.Name = project
+
+	volumes := map[string]types.VolumeConfig{}
+	for name, vol := range cfg.Volumes {
+		volumes[ProjectServiceName(project, name)] = vol
+	}
+	cfg.Volumes = volumes
+
+	for i, svc := range cfg.Services {
+		svc.Name = ProjectServiceName(project, svc.Name)
+
+		if len(svc.DependsOn) != 0 {
+			dependsOn := types.DependsOnConfig{}
+			for dep, condition := range svc.DependsOn {
+				dependsOn[ProjectServiceName(project, dep)] = condition
+			}
+			svc.DependsOn = dependsOn
+		}
+
+		// Links without an alias are given the service's original name as an
+		// alias so that the link's hostname doesn't change.
+		for j, link := range svc.Links {
+			linkParts := strings.SplitN(link, ":", 2)
+			alias := linkParts[0]
+			if len(linkParts) == 2 {
+				alias = linkParts[1]
+			}
+			svc.Links[j] = fmt.Sprintf("%s:%s", ProjectServiceName(project, linkParts[0]), alias)
+		}
+
+		for j, vol := range svc.Volumes {
+			if vol.Type == types.VolumeTypeVolume && vol.Source != "" {
+				svc.Volumes[j].Source = ProjectServiceName(project, vol.Source)
+			}
+		}
+
+		if err := applyProjectNameToExtension(&svc, project); err != nil {
+			return err
+		}
+		cfg.Services[i] = svc
+	}
+	return nil
+}
+
+// applyProjectNameToExtension updates the services referenced by the
+// service's x-blimp extension.
+func applyProjectNameToExtension(svc *types.ServiceConfig, project string) error {
+	ext, err := GetServiceExtension(*svc)
+	if err != nil {
+		return err
+	}
+
+	if len(ext.Faults) == 0 {
+		return nil
+	}
+
+	for i, fault := range ext.Faults {
+		ext.Faults[i].To = ProjectServiceName(project, fault.To)
+	}
+
+	extJSON, err := json.Marshal(ext)
+	if err != nil {
+		return errors.WithContext("marshal extension", err)
+	}
+
+	delete(svc.Extras, ExtensionKey)
+	if svc.Labels == nil {
+		svc.Labels = types.Labels{}
+	}
+	svc.Labels[extensionLabel] = string(extJSON)
+	return nil
+}
@@ -0,0 +1,74 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/kelda/compose-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyProjectName(t *testing.T) {
+	cfg := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name: "web",
+				DependsOn: types.DependsOnConfig{
+					"db": {Condition: types.ServiceConditionStarted},
+				},
+				Links: []string{"db", "cache:redis"},
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeVolume, Source: "data", Target: "/data"},
+					{Type: types.VolumeTypeBind, Source: "/src", Target: "/app"},
+				},
+				Labels: types.Labels{
+					extensionLabel: `{"faults":[{"to":"db","drop":"5"}]}`,
+				},
+			},
+			{Name: "db"},
+			{Name: "cache"},
+		},
+		Volumes: map[string]types.VolumeConfig{
+			"data": {},
+		},
+	}
+
+	assert.NoError(t, ApplyProjectName(&cfg, "shop"))
+	assert.Equal(t, "shop", cfg.Name)
+	assert.ElementsMatch(t, []string{"shop-web", "shop-db", "shop-cache"}, cfg.ServiceNames())
+	assert.Len(t, cfg.Volumes, 1)
+	assert.Contains(t, cfg.Volumes, "shop-data")
+
+	web := cfg.Services[0]
+	assert.Equal(t, types.DependsOnConfig{
+		"shop-db": {Condition: types.ServiceConditionStarted},
+	}, web.DependsOn)
+	assert.Equal(t, []string{"shop-db:db", "shop-cache:redis"}, web.Links)
+	assert.Equal(t, "shop-data", web.Volumes[0].Source)
+	assert.Equal(t, "/src", web.Volumes[1].Source)
+
+	ext, err := GetServiceExtension(web)
+	assert.NoError(t, err)
+	assert.Equal(t, []FaultConfig{{To: "shop-db", Drop: "5"}}, ext.Faults)
+}
+
+func TestGetExplicitProjectName(t *testing.T) {
+	configFiles := []types.ConfigFile{
+		{Filename: "docker-compose.yml", Config: map[string]interface{}{"name": "base"}},
+		{Filename: "docker-compose.override.yml", Config: map[string]interface{}{"name": "override"}},
+	}
+
+	name, err := getExplicitProjectName(configFiles, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "override", name)
+
+	name, err = getExplicitProjectName(configFiles, map[string]string{ProjectNameEnv: "env"})
+	assert.NoError(t, err)
+	assert.Equal(t, "env", name)
+
+	name, err = getExplicitProjectName(nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+
+	_, err = getExplicitProjectName(nil, map[string]string{ProjectNameEnv: "My Project"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kelda/compose-go/loader"
+	"github.com/spf13/afero"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// FieldSource is a field in the merged Compose configuration, along with the
+// file that set it.
+type FieldSource struct {
+	// Path is the dotted path to the field, such as
+	// `services.web.environment.DEBUG`.
+	Path  string
+	Value string
+	File  string
+}
+
+// GetProvenance returns the file that sets each field when the given Compose
+// files are merged. Later files override the fields set by earlier files,
+// except for lists, which Compose merges. So lists that are set in multiple
+// files have an entry for each file.
+func GetProvenance(paths []string) ([]FieldSource, error) {
+	fields := map[string][]FieldSource{}
+	for _, path := range paths {
+		b, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return nil, errors.WithContext("read compose file", err)
+		}
+
+		configIntf, err := loader.ParseYAML(b)
+		if err != nil {
+			return nil, errors.NewFriendlyError("Failed to parse Compose file (%s)\n"+
+				"Error: %s", path, err)
+		}
+
+		flattenFields("", configIntf, func(fieldPath string, value interface{}) {
+			source := FieldSource{Path: fieldPath, Value: formatFieldValue(value), File: path}
+			if _, isList := value.([]interface{}); isList {
+				fields[fieldPath] = append(fields[fieldPath], source)
+			} else {
+				fields[fieldPath] = []FieldSource{source}
+			}
+		})
+	}
+
+	var fieldPaths []string
+	for fieldPath := range fields {
+		fieldPaths = append(fieldPaths, fieldPath)
+	}
+	sort.Strings(fieldPaths)
+
+	var sources []FieldSource
+	for _, fieldPath := range fieldPaths {
+		sources = append(sources, fields[fieldPath]...)
+	}
+	return sources, nil
+}
+
+// flattenFields calls visit for each scalar or list in the Compose file.
+// Lists of `KEY=VALUE` pairs are treated as maps so that overrides of
+// individual keys are tracked.
+func flattenFields(prefix string, value interface{}, visit func(string, interface{})) {
+	fields, ok := value.(map[string]interface{})
+	if !ok || len(fields) == 0 {
+		visit(prefix, value)
+		return
+	}
+
+	for key, child := range fields {
+		childPath := key
+		if prefix != "" {
+			childPath = prefix + "." + key
+		}
+
+		if list, ok := child.([]interface{}); ok && isKeyValueList(key) {
+			child = keyValueListToMap(list)
+		}
+		flattenFields(childPath, child, visit)
+	}
+}
+
+// isKeyValueList returns whether the field may be written as either a list
+// of `KEY=VALUE` pairs, or a map.
+func isKeyValueList(key string) bool {
+	return key == "environment" || key == "labels" || key == "args"
+}
+
+func keyValueListToMap(list []interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, kvIntf := range list {
+		parts := strings.SplitN(fmt.Sprint(kvIntf), "=", 2)
+		if len(parts) == 2 {
+			m[parts[0]] = parts[1]
+		} else {
+			m[parts[0]] = nil
+		}
+	}
+	return m
+}
+
+func formatFieldValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []interface{}, map[string]interface{}:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(value)
+	}
+}
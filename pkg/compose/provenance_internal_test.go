@@ -0,0 +1,41 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProvenance(t *testing.T) {
+	fs = afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "docker-compose.yml", []byte(`version: "3"
+services:
+  web:
+    image: web
+    ports:
+      - "80:80"
+    environment:
+      - DEBUG=false
+      - LOG_LEVEL=info
+`), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "blimp.override.yaml", []byte(`services:
+  web:
+    image: web:dev
+    ports:
+      - "8080:80"
+    environment:
+      DEBUG: "true"
+`), 0644))
+
+	sources, err := GetProvenance([]string{"docker-compose.yml", "blimp.override.yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldSource{
+		{Path: "services.web.environment.DEBUG", Value: "true", File: "blimp.override.yaml"},
+		{Path: "services.web.environment.LOG_LEVEL", Value: "info", File: "docker-compose.yml"},
+		{Path: "services.web.image", Value: "web:dev", File: "blimp.override.yaml"},
+		{Path: "services.web.ports", Value: `["80:80"]`, File: "docker-compose.yml"},
+		{Path: "services.web.ports", Value: `["8080:80"]`, File: "blimp.override.yaml"},
+		{Path: "version", Value: "3", File: "docker-compose.yml"},
+	}, sources)
+}
@@ -0,0 +1,149 @@
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kelda/compose-go/types"
+)
+
+// Validate attempts to find common cases of broken Compose files so we can
+// abort early and provide a helpful error.
+// This is needlessly abstract for now, but I imagine more will be added.
+func Validate(cfg types.Project) []string {
+	problems := []string{}
+
+	problems = append(problems, checkNonexistentDepends(cfg.Services)...)
+	problems = append(problems, checkSysctls(cfg.Services)...)
+	problems = append(problems, checkUlimits(cfg.Services)...)
+
+	return problems
+}
+
+// checkNonexistentDepends checks that every DependsOn matches an actual service
+// defined in the list of services.
+func checkNonexistentDepends(services types.Services) []string {
+	problems := []string{}
+
+	serviceExists := func(service string) bool {
+		for _, matchingService := range services {
+			if service == matchingService.Name {
+				// We found a match!
+				return true
+			}
+		}
+		// No match found.
+		return false
+	}
+
+	for _, service := range services {
+		for dependency := range service.DependsOn {
+			if !serviceExists(dependency) {
+				problems = append(problems, fmt.Sprintf(
+					"The %s service depends on %q, which does not exist",
+					service.Name, dependency))
+			}
+		}
+	}
+
+	return problems
+}
+
+// allowedSysctls are the sysctls that services may set. They're namespaced by
+// the kernel, so they can't affect other sandboxes on the same node.
+// net.core.somaxconn isn't considered safe by Kubernetes, so it requires the
+// kubelet to be started with --allowed-unsafe-sysctls=net.core.somaxconn.
+var allowedSysctls = []string{
+	"kernel.shm_rmid_forced",
+	"net.core.somaxconn",
+	"net.ipv4.ip_local_port_range",
+	"net.ipv4.tcp_syncookies",
+}
+
+// maxNofile is the highest open file limit that services may request. It's
+// the default limit of the container runtime, and processes can't raise their
+// hard limit past it without CAP_SYS_RESOURCE.
+const maxNofile = 1048576
+
+// checkSysctls checks that services only set safelisted sysctls.
+func checkSysctls(services types.Services) []string {
+	problems := []string{}
+	for _, service := range services {
+		var keys []string
+		for key := range service.Sysctls {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if !isAllowedSysctl(key) {
+				problems = append(problems, fmt.Sprintf(
+					"The %s service sets the %q sysctl, which isn't supported. "+
+						"Supported sysctls: %s",
+					service.Name, key, strings.Join(allowedSysctls, ", ")))
+			}
+		}
+	}
+	return problems
+}
+
+func isAllowedSysctl(key string) bool {
+	for _, allowed := range allowedSysctls {
+		if key == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUlimits checks that services only set the nofile ulimit, and that the
+// limit is within what the container runtime allows.
+func checkUlimits(services types.Services) []string {
+	problems := []string{}
+	for _, service := range services {
+		var keys []string
+		for key := range service.Ulimits {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if key != "nofile" {
+				problems = append(problems, fmt.Sprintf(
+					"The %s service sets the %q ulimit, which isn't supported. "+
+						"Only nofile is supported",
+					service.Name, key))
+				continue
+			}
+
+			soft, hard := nofileLimits(service.Ulimits[key])
+			switch {
+			case soft > hard:
+				problems = append(problems, fmt.Sprintf(
+					"The %s service's nofile soft limit (%d) is greater than its hard limit (%d)",
+					service.Name, soft, hard))
+			case hard > maxNofile:
+				problems = append(problems, fmt.Sprintf(
+					"The %s service's nofile limit (%d) is greater than the maximum (%d)",
+					service.Name, hard, maxNofile))
+			case soft <= 0:
+				problems = append(problems, fmt.Sprintf(
+					"The %s service's nofile limit must be positive", service.Name))
+			}
+		}
+	}
+	return problems
+}
+
+// nofileLimits returns the soft and hard limits for the ulimit.
+func nofileLimits(ulimit *types.UlimitsConfig) (soft, hard int) {
+	if ulimit == nil {
+		return 0, 0
+	}
+
+	if ulimit.Single != 0 {
+		return ulimit.Single, ulimit.Single
+	}
+	return ulimit.Soft, ulimit.Hard
+}
@@ -1,4 +1,4 @@
-package main_test
+package compose_test
 
 import (
 	"testing"
@@ -6,7 +6,7 @@ import (
 	"github.com/kelda/compose-go/types"
 	"github.com/stretchr/testify/assert"
 
-	main "github.com/kelda/blimp/cluster-controller"
+	"github.com/kelda/blimp/pkg/compose"
 )
 
 func TestValidateComposeConfig(t *testing.T) {
@@ -73,9 +73,49 @@ func TestValidateComposeConfig(t *testing.T) {
 			},
 			expProblems: []string{"The test1 service depends on \"dne\", which does not exist"},
 		},
+		// Supported sysctls and ulimits.
+		{
+			cfg: types.Project{
+				Services: types.Services([]types.ServiceConfig{
+					{
+						Name:    "test",
+						Image:   "alpine",
+						Sysctls: types.Mapping{"net.core.somaxconn": "1024"},
+						Ulimits: map[string]*types.UlimitsConfig{
+							"nofile": {Single: 65536},
+						},
+					},
+				}),
+			},
+			expProblems: []string{},
+		},
+		// Unsupported sysctls and ulimits.
+		{
+			cfg: types.Project{
+				Services: types.Services([]types.ServiceConfig{
+					{
+						Name:    "test",
+						Image:   "alpine",
+						Sysctls: types.Mapping{"vm.max_map_count": "262144"},
+						Ulimits: map[string]*types.UlimitsConfig{
+							"nofile":  {Soft: 20000, Hard: 10000},
+							"memlock": {Single: -1},
+						},
+					},
+				}),
+			},
+			expProblems: []string{
+				"The test service sets the \"vm.max_map_count\" sysctl, which isn't supported. " +
+					"Supported sysctls: kernel.shm_rmid_forced, net.core.somaxconn, " +
+					"net.ipv4.ip_local_port_range, net.ipv4.tcp_syncookies",
+				"The test service sets the \"memlock\" ulimit, which isn't supported. " +
+					"Only nofile is supported",
+				"The test service's nofile soft limit (20000) is greater than its hard limit (10000)",
+			},
+		},
 	}
 
 	for _, test := range tests {
-		assert.Equal(t, test.expProblems, main.ValidateComposeFile(test.cfg))
+		assert.Equal(t, test.expProblems, compose.Validate(test.cfg))
 	}
 }
@@ -0,0 +1,125 @@
+package errors
+
+import (
+	"strings"
+)
+
+// helpURL is the base URL for Blimp's troubleshooting documentation.
+const helpURL = "https://kelda.io/blimp/docs/help/"
+
+// Code identifies an error that users commonly run into, so that the CLI can
+// show consistent instructions for fixing it, no matter whether the error
+// came from the CLI or the manager.
+type Code string
+
+const (
+	// CodeCertMismatch is used when the manager's TLS certificate doesn't
+	// match the certificate in the user's blimp.yaml.
+	CodeCertMismatch Code = "cert-mismatch"
+
+	// CodeUnauthorized is used when the user's credentials are rejected.
+	CodeUnauthorized Code = "unauthorized"
+
+	// CodeQuotaExceeded is used when the sandbox has used up its quota.
+	CodeQuotaExceeded Code = "quota-exceeded"
+
+	// CodeClusterOverloaded is used when the cluster can't take any more
+	// sandboxes.
+	CodeClusterOverloaded Code = "cluster-overloaded"
+
+	// CodeSandboxNotFound is used by commands that require a running
+	// sandbox.
+	CodeSandboxNotFound Code = "sandbox-not-found"
+
+	// CodeInvalidCompose is used when the Docker Compose file has issues
+	// that prevent it from being deployed.
+	CodeInvalidCompose Code = "invalid-compose"
+
+	// CodeNamespaceCollision is used when the user's token maps to the
+	// namespace of another user's sandbox.
+	CodeNamespaceCollision Code = "namespace-collision"
+
+	// CodePaymentRequired and CodePlanLimitReached are used when the
+	// cluster's billing system denies an action. Their remediation URL is
+	// the billing system's upgrade page rather than Blimp's documentation.
+	CodePaymentRequired  Code = "payment-required"
+	CodePlanLimitReached Code = "plan-limit-reached"
+)
+
+// remediationHints are printed after the messages of errors with the code.
+var remediationHints = map[Code]string{
+	CodeCertMismatch: "The Blimp cluster's certificate doesn't match the `manager_cert` " +
+		"in ~/.blimp/blimp.yaml. Update it with the cluster's current certificate.",
+	CodeUnauthorized: "Check that the `cluster_token` in ~/.blimp/blimp.yaml " +
+		"matches the cluster's secret.",
+	CodeQuotaExceeded:     "Run `blimp usage` to see your usage for this month.",
+	CodeClusterOverloaded: "Other sandboxes are using all of the cluster's capacity.",
+	CodeSandboxNotFound:   "Run `blimp up` to create your sandbox.",
+	CodeInvalidCompose:    "Run `blimp config` to see the merged Docker Compose file.",
+	CodeNamespaceCollision: "Use a different username in ~/.blimp/auth.yaml. " +
+		"Your cluster operator can see who owns the sandbox with `blimp-admin namespaces lookup`.",
+	CodePaymentRequired:  "Update your payment details at the link above.",
+	CodePlanLimitReached: "Upgrade your plan at the link above.",
+}
+
+// RemediationURL returns the documentation page on fixing errors with the
+// code.
+func (code Code) RemediationURL() string {
+	return helpURL + "errors/#" + string(code)
+}
+
+// A CodedError is a FriendlyError from the catalog of common errors.
+type CodedError interface {
+	FriendlyError
+	Code() Code
+	RemediationURL() string
+}
+
+// NewCodedError returns a new user friendly error with the given code. The
+// remediation URL is set based on the code.
+func NewCodedError(code Code, f string, args ...interface{}) error {
+	err := NewFriendlyError(f, args...).(friendlyErrorImpl)
+	err.code = code
+	err.remediationURL = code.RemediationURL()
+	return err
+}
+
+// NewCodedErrorWithURL is the same as NewCodedError, except that the error
+// links to the given URL. The catalog's URL is used if it's empty.
+func NewCodedErrorWithURL(code Code, remediationURL, f string, args ...interface{}) error {
+	err := NewCodedError(code, f, args...).(friendlyErrorImpl)
+	if remediationURL != "" {
+		err.remediationURL = remediationURL
+	}
+	return err
+}
+
+// GetCode returns the code of the first coded error in the error chain.
+func GetCode(err error) (CodedError, bool) {
+	for err != nil {
+		if codedErr, ok := err.(CodedError); ok && codedErr.Code() != "" {
+			return codedErr, true
+		}
+
+		cause, ok := Cause(err)
+		if !ok {
+			return nil, false
+		}
+		err = cause
+	}
+	return nil, false
+}
+
+// classify converts errors that are known to have a common cause, such as
+// TLS failures, into coded errors.
+func classify(err error) error {
+	if _, ok := GetCode(err); ok {
+		return err
+	}
+
+	if msg := err.Error(); strings.Contains(msg, "x509:") {
+		return NewCodedError(CodeCertMismatch,
+			"Failed to verify the Blimp cluster's TLS certificate (%s).", msg)
+	}
+	return err
+}
@@ -0,0 +1,30 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+func TestCodedError(t *testing.T) {
+	err := errors.WithContext("create sandbox",
+		errors.NewCodedError(errors.CodeQuotaExceeded, "Used %d%% of quota", 100))
+	assert.Equal(t, "Used 100% of quota", errors.GetPrintableMessage(err))
+
+	codedErr, ok := errors.GetCode(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeQuotaExceeded, codedErr.Code())
+	assert.Equal(t, "https://kelda.io/blimp/docs/help/errors/#quota-exceeded", codedErr.RemediationURL())
+
+	// The code and remediation URL are preserved when sent over gRPC.
+	assert.Equal(t, err, errors.Unmarshal(nil, errors.Marshal(err)))
+
+	_, ok = errors.GetCode(errors.WithContext("context", errors.NewFriendlyError("friendly")))
+	assert.False(t, ok)
+
+	_, ok = errors.GetCode(errors.New("error"))
+	assert.False(t, ok)
+}
@@ -47,6 +47,10 @@ func (err contextErrorImpl) Cause() error {
 
 type friendlyErrorImpl struct {
 	message string
+
+	// code and remediationURL are set for errors from the catalog.
+	code           Code
+	remediationURL string
 }
 
 func (err friendlyErrorImpl) Error() string {
@@ -57,6 +61,14 @@ func (err friendlyErrorImpl) FriendlyMessage() string {
 	return err.message
 }
 
+func (err friendlyErrorImpl) Code() Code {
+	return err.code
+}
+
+func (err friendlyErrorImpl) RemediationURL() string {
+	return err.remediationURL
+}
+
 // WithContext returns an error that can be unwrapped by `Cause`.
 func WithContext(context string, err error) error {
 	return contextErrorImpl{err, context}
@@ -117,10 +129,43 @@ func getFriendlyMessage(err error) (string, bool) {
 	return getFriendlyMessage(cause)
 }
 
+// fatalErrorHooks are run by HandleFatalError before it exits.
+var fatalErrorHooks []func(error)
+
+// OnFatalError registers a function to run when HandleFatalError is called,
+// such as to record the error before the program exits.
+func OnFatalError(hook func(error)) {
+	fatalErrorHooks = append(fatalErrorHooks, hook)
+}
+
 func HandleFatalError(err error) {
-	body := GetPrintableMessage(err)
-	fmt.Fprintln(os.Stderr,
-		goterm.Color("[Error] Get help at https://kelda.io/blimp/docs/help/", goterm.RED))
-	fmt.Fprintln(os.Stderr, body)
+	err = classify(err)
+	for _, hook := range fatalErrorHooks {
+		hook(err)
+	}
+
+	fmt.Fprint(os.Stderr, formatFatalError(err))
 	os.Exit(1)
 }
+
+// formatFatalError formats the error for printing to the user. Errors from
+// the catalog include their code, and instructions for fixing them.
+func formatFatalError(err error) string {
+	body := GetPrintableMessage(err)
+	codedErr, ok := GetCode(err)
+	if !ok {
+		return goterm.Color("[Error] Get help at "+helpURL, goterm.RED) + "\n" + body + "\n"
+	}
+
+	url := codedErr.RemediationURL()
+	if url == "" {
+		url = codedErr.Code().RemediationURL()
+	}
+
+	header := fmt.Sprintf("[Error %s] Get help at %s", codedErr.Code(), url)
+	msg := goterm.Color(header, goterm.RED) + "\n" + body + "\n"
+	if hint, ok := remediationHints[codedErr.Code()]; ok {
+		msg += hint + "\n"
+	}
+	return msg
+}
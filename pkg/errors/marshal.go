@@ -22,11 +22,14 @@ func Marshal(err error) *proto.Error {
 	}
 
 	if friendlyErr, ok := err.(FriendlyError); ok {
-		return &proto.Error{
-			FriendlyError: &proto.FriendlyError{
-				FriendlyMessage: friendlyErr.FriendlyMessage(),
-			},
+		protoErr := &proto.FriendlyError{
+			FriendlyMessage: friendlyErr.FriendlyMessage(),
+		}
+		if codedErr, ok := err.(CodedError); ok {
+			protoErr.Code = string(codedErr.Code())
+			protoErr.RemediationUrl = codedErr.RemediationURL()
 		}
+		return &proto.Error{FriendlyError: protoErr}
 	}
 
 	return &proto.Error{Text: err.Error()}
@@ -48,7 +51,9 @@ func unmarshalProtoError(protoErr *proto.Error) error {
 
 	if protoErr.FriendlyError != nil {
 		return friendlyErrorImpl{
-			message: protoErr.FriendlyError.FriendlyMessage,
+			message:        protoErr.FriendlyError.FriendlyMessage,
+			code:           Code(protoErr.FriendlyError.Code),
+			remediationURL: protoErr.FriendlyError.RemediationUrl,
 		}
 	}
 
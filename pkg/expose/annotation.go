@@ -2,6 +2,7 @@ package expose
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/kelda/blimp/pkg/errors"
 )
@@ -9,6 +10,14 @@ import (
 type ExposeInfo struct {
 	Service string
 	Port    int
+
+	// ExpiresAt is the Unix timestamp after which the link stops working. Links
+	// created with `blimp expose` never expire, and leave it unset.
+	ExpiresAt int64 `json:",omitempty"`
+}
+
+func (info ExposeInfo) Expired(now time.Time) bool {
+	return info.ExpiresAt != 0 && now.Unix() >= info.ExpiresAt
 }
 
 // ExposeAnnotation maps secret tokens to their underlying ExposeInfos.
@@ -0,0 +1,185 @@
+// Package faults defines the network faults that can be injected between
+// pairs of services in a sandbox. The rules are stored in a ConfigMap in the
+// sandbox, and enforced by the chaos agent running alongside the source
+// service.
+package faults
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap that contains the rules for
+	// a sandbox.
+	ConfigMapName = "blimp-faults"
+
+	// ComposeRulesKey is the ConfigMap key containing the rules defined in
+	// the Compose file. They're replaced every time the sandbox is deployed.
+	ComposeRulesKey = "compose.json"
+
+	// RuntimeRulesKey is the ConfigMap key containing the rules defined with
+	// `blimp faults`. They take precedence over the Compose rules.
+	RuntimeRulesKey = "runtime.json"
+
+	// MaxRulesPerService is the maximum number of services that a service can
+	// have faults to. It's limited by the number of bands supported by the
+	// `prio` queueing discipline.
+	MaxRulesPerService = 13
+)
+
+// Rule degrades the network traffic sent from one service to another.
+type Rule struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// DropPercent is the percentage of packets to drop.
+	DropPercent float64 `json:"dropPercent,omitempty"`
+
+	// Delay is added to every packet.
+	Delay time.Duration `json:"delay,omitempty"`
+}
+
+func (rule Rule) String() string {
+	var faults []string
+	if rule.DropPercent != 0 {
+		faults = append(faults, fmt.Sprintf("drop %s%%", strconv.FormatFloat(rule.DropPercent, 'f', -1, 64)))
+	}
+	if rule.Delay != 0 {
+		faults = append(faults, fmt.Sprintf("delay %s", rule.Delay))
+	}
+	return fmt.Sprintf("%s -> %s: %s", rule.From, rule.To, strings.Join(faults, ", "))
+}
+
+// Validate returns an error if the rule can't be enforced.
+func (rule Rule) Validate() error {
+	if rule.From == "" || rule.To == "" {
+		return errors.NewFriendlyError("Fault rules must specify both the source and destination service.")
+	}
+
+	if rule.From == rule.To {
+		return errors.NewFriendlyError("Can't add a fault rule from %s to itself.", rule.From)
+	}
+
+	if rule.DropPercent < 0 || rule.DropPercent > 100 {
+		return errors.NewFriendlyError("Drop percentage for %s -> %s must be between 0 and 100.",
+			rule.From, rule.To)
+	}
+
+	if rule.Delay < 0 {
+		return errors.NewFriendlyError("Delay for %s -> %s can't be negative.", rule.From, rule.To)
+	}
+
+	if rule.DropPercent == 0 && rule.Delay == 0 {
+		return errors.NewFriendlyError("Fault rule %s -> %s must specify a drop percentage or delay.",
+			rule.From, rule.To)
+	}
+	return nil
+}
+
+// NetemArgs returns the arguments to the netem queueing discipline that
+// implement the rule.
+func (rule Rule) NetemArgs() []string {
+	args := []string{"netem"}
+	if rule.Delay != 0 {
+		args = append(args, "delay", fmt.Sprintf("%dms", rule.Delay.Milliseconds()))
+	}
+	if rule.DropPercent != 0 {
+		args = append(args, "loss", strconv.FormatFloat(rule.DropPercent, 'f', -1, 64)+"%")
+	}
+	return args
+}
+
+// ParsePercent parses a percentage such as "5%". The percent sign is
+// optional.
+func ParsePercent(str string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(str), "%"), 64)
+	if err != nil {
+		return 0, errors.NewFriendlyError("Invalid percentage %q.", str)
+	}
+	return percent, nil
+}
+
+// Merge combines the rules from the Compose file with the rules defined at
+// runtime. Runtime rules replace any Compose rules for the same pair of
+// services.
+func Merge(compose, runtime []Rule) []Rule {
+	type pair struct{ from, to string }
+	overridden := map[pair]struct{}{}
+	for _, rule := range runtime {
+		overridden[pair{rule.From, rule.To}] = struct{}{}
+	}
+
+	merged := append([]Rule{}, runtime...)
+	for _, rule := range compose {
+		if _, ok := overridden[pair{rule.From, rule.To}]; !ok {
+			merged = append(merged, rule)
+		}
+	}
+	return merged
+}
+
+// Sources returns the set of services that have faults to other services.
+func Sources(rules []Rule) map[string]struct{} {
+	sources := map[string]struct{}{}
+	for _, rule := range rules {
+		sources[rule.From] = struct{}{}
+	}
+	return sources
+}
+
+// Marshal serializes rules for storage in the ConfigMap.
+func Marshal(rules []Rule) (string, error) {
+	if rules == nil {
+		rules = []Rule{}
+	}
+
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return "", errors.WithContext("marshal", err)
+	}
+	return string(rulesJSON), nil
+}
+
+// Unmarshal parses rules stored in the ConfigMap.
+func Unmarshal(rulesJSON string) ([]Rule, error) {
+	if rulesJSON == "" {
+		return nil, nil
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal([]byte(rulesJSON), &rules); err != nil {
+		return nil, errors.WithContext("unmarshal", err)
+	}
+	return rules, nil
+}
+
+func ToProtobuf(rules []Rule) (pbRules []*cluster.FaultRule) {
+	for _, rule := range rules {
+		pbRules = append(pbRules, &cluster.FaultRule{
+			From:        rule.From,
+			To:          rule.To,
+			DropPercent: rule.DropPercent,
+			DelayMs:     rule.Delay.Milliseconds(),
+		})
+	}
+	return pbRules
+}
+
+func FromProtobuf(pbRules []*cluster.FaultRule) (rules []Rule) {
+	for _, rule := range pbRules {
+		rules = append(rules, Rule{
+			From:        rule.GetFrom(),
+			To:          rule.GetTo(),
+			DropPercent: rule.GetDropPercent(),
+			Delay:       time.Duration(rule.GetDelayMs()) * time.Millisecond,
+		})
+	}
+	return rules
+}
@@ -0,0 +1,36 @@
+package faults
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	compose := []Rule{
+		{From: "api", To: "db", DropPercent: 5},
+		{From: "web", To: "api", Delay: 300 * time.Millisecond},
+	}
+	runtime := []Rule{
+		{From: "api", To: "db", DropPercent: 50},
+	}
+
+	assert.Equal(t, []Rule{
+		{From: "api", To: "db", DropPercent: 50},
+		{From: "web", To: "api", Delay: 300 * time.Millisecond},
+	}, Merge(compose, runtime))
+}
+
+func TestParsePercent(t *testing.T) {
+	percent, err := ParsePercent("5%")
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, percent)
+
+	percent, err = ParsePercent("0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, percent)
+
+	_, err = ParsePercent("five")
+	assert.Error(t, err)
+}
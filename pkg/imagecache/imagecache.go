@@ -0,0 +1,57 @@
+// Package imagecache contains helpers for pulling images through the Blimp
+// image cache.
+//
+// The image cache is a pull-through registry that's shared by all sandboxes.
+// Images are pulled through it by prefixing their fully qualified name with
+// the cache's hostname. For example, `nginx` is pulled as
+// `<cache>/index.docker.io/library/nginx:latest`.
+package imagecache
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// ToCachedImage returns the name that should be used to pull the given image
+// through the cache running at cacheHost.
+func ToCachedImage(cacheHost, image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", errors.WithContext("parse image reference", err)
+	}
+
+	repo := ref.Context()
+	if repo.RegistryStr() == cacheHost {
+		return image, nil
+	}
+
+	cachedRepo := fmt.Sprintf("%s/%s/%s", cacheHost, repo.RegistryStr(), repo.RepositoryStr())
+	if digest, ok := ref.(name.Digest); ok {
+		return cachedRepo + "@" + digest.DigestStr(), nil
+	}
+	return cachedRepo + ":" + ref.Identifier(), nil
+}
+
+// UpstreamRepository parses the repository name used in requests to the
+// cache, and returns the upstream repository that it refers to. The upstream
+// registry must be explicitly included in the name.
+func UpstreamRepository(cachedRepo string) (name.Repository, error) {
+	parts := strings.SplitN(cachedRepo, "/", 2)
+	if len(parts) != 2 {
+		return name.Repository{}, errors.New("repository %q doesn't contain an upstream registry", cachedRepo)
+	}
+
+	repo, err := name.NewRepository(cachedRepo, name.StrictValidation)
+	if err != nil {
+		return name.Repository{}, err
+	}
+
+	if repo.RegistryStr() != parts[0] {
+		return name.Repository{}, errors.New("repository %q doesn't contain an upstream registry", cachedRepo)
+	}
+	return repo, nil
+}
@@ -0,0 +1,65 @@
+package imagecache_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelda/blimp/pkg/imagecache"
+)
+
+func TestToCachedImage(t *testing.T) {
+	tests := []struct {
+		name  string
+		image string
+		exp   string
+	}{
+		{
+			name:  "docker hub official image",
+			image: "nginx",
+			exp:   "cache.blimp/index.docker.io/library/nginx:latest",
+		},
+		{
+			name:  "docker hub tag",
+			image: "kelda/blimp:v1",
+			exp:   "cache.blimp/index.docker.io/kelda/blimp:v1",
+		},
+		{
+			name:  "other registry",
+			image: "gcr.io/kelda-blimp/blimp-init:latest",
+			exp:   "cache.blimp/gcr.io/kelda-blimp/blimp-init:latest",
+		},
+		{
+			name:  "digest",
+			image: "postgres@sha256:93c7b4f3c53ce44b1d9c5d9c2d1c4ac2d97e9a7d0c4b7fcd5f8e0d3d2f7b8a5c",
+			exp:   "cache.blimp/index.docker.io/library/postgres@sha256:93c7b4f3c53ce44b1d9c5d9c2d1c4ac2d97e9a7d0c4b7fcd5f8e0d3d2f7b8a5c",
+		},
+		{
+			name:  "already cached",
+			image: "cache.blimp/index.docker.io/library/nginx:latest",
+			exp:   "cache.blimp/index.docker.io/library/nginx:latest",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			cached, err := imagecache.ToCachedImage("cache.blimp", test.image)
+			assert.NoError(t, err)
+			assert.Equal(t, test.exp, cached)
+		})
+	}
+}
+
+func TestUpstreamRepository(t *testing.T) {
+	repo, err := imagecache.UpstreamRepository("index.docker.io/library/nginx")
+	assert.NoError(t, err)
+	assert.Equal(t, "index.docker.io", repo.RegistryStr())
+	assert.Equal(t, "library/nginx", repo.RepositoryStr())
+
+	_, err = imagecache.UpstreamRepository("library/nginx")
+	assert.Error(t, err)
+
+	_, err = imagecache.UpstreamRepository("nginx")
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package kube
+
+import (
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// CustomResource describes a namespaced custom resource managed by Blimp.
+type CustomResource struct {
+	Group    string
+	Version  string
+	Kind     string
+	Singular string
+	Plural   string
+
+	// StatusSubresource enables the status subresource, so that updates to
+	// the status don't modify the object's generation.
+	StatusSubresource bool
+
+	// PrinterColumns are shown by `kubectl get`.
+	PrinterColumns []PrinterColumn
+}
+
+// PrinterColumn is a string field shown by `kubectl get`.
+type PrinterColumn struct {
+	Name string
+
+	// JSONPath is relative to the object, such as `.status.phase`.
+	JSONPath string
+}
+
+// GroupVersionResource returns the resource used to access the custom
+// resource with the dynamic client.
+func (cr CustomResource) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    cr.Group,
+		Version:  cr.Version,
+		Resource: cr.Plural,
+	}
+}
+
+// APIVersion returns the apiVersion of the custom resource's objects.
+func (cr CustomResource) APIVersion() string {
+	return cr.Group + "/" + cr.Version
+}
+
+// CreateCustomResourceDefinition creates the CustomResourceDefinition for
+// the given resource if it doesn't already exist.
+func CreateCustomResourceDefinition(dynamicClient dynamic.Interface, cr CustomResource) error {
+	spec := map[string]interface{}{
+		"group":   cr.Group,
+		"version": cr.Version,
+		"scope":   "Namespaced",
+		"names": map[string]interface{}{
+			"kind":     cr.Kind,
+			"plural":   cr.Plural,
+			"singular": cr.Singular,
+		},
+	}
+
+	if cr.StatusSubresource {
+		spec["subresources"] = map[string]interface{}{
+			"status": map[string]interface{}{},
+		}
+	}
+
+	var columns []interface{}
+	for _, column := range cr.PrinterColumns {
+		columns = append(columns, map[string]interface{}{
+			"name":     column.Name,
+			"type":     "string",
+			"JSONPath": column.JSONPath,
+		})
+	}
+	if len(columns) != 0 {
+		spec["additionalPrinterColumns"] = columns
+	}
+
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1beta1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]interface{}{
+			"name": cr.Plural + "." + cr.Group,
+		},
+		"spec": spec,
+	}}
+
+	crdResource := schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1beta1",
+		Resource: "customresourcedefinitions",
+	}
+	_, err := dynamicClient.Resource(crdResource).Create(crd, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
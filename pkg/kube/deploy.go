@@ -3,6 +3,9 @@ package kube
 import (
 	"fmt"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -70,6 +73,11 @@ type DeployPodOptions struct {
 }
 
 func DeployPod(kubeClient kubernetes.Interface, pod corev1.Pod, opts DeployPodOptions) error {
+	pod, err := PatchSandboxPod(pod)
+	if err != nil {
+		return errors.WithContext("patch pod", err)
+	}
+
 	// Get the current pod, if it exists.
 	podClient := kubeClient.CoreV1().Pods(pod.Namespace)
 	curr, err := podClient.Get(pod.Name, metav1.GetOptions{})
@@ -82,18 +90,12 @@ func DeployPod(kubeClient kubernetes.Interface, pod corev1.Pod, opts DeployPodOp
 		// If the currently deployed pod is already up to date, we don't have
 		// to do anything.
 		if !opts.ForceRestart {
-			// Make a copy to avoid modifying the desired pod, since that pod is used
-			// to deploy.
-			sanitized := (&pod).DeepCopy()
-			for _, sanitize := range opts.Sanitizers {
-				sanitized = sanitize(sanitized, curr)
-			}
-			annot, err := runtime.Encode(unstructured.UnstructuredJSONScheme, sanitized)
+			upToDate, err := podUpToDate(pod, curr, opts.Sanitizers)
 			if err != nil {
-				return errors.WithContext("make apply annotation", err)
+				return err
 			}
 
-			if string(annot) == curr.Annotations["blimp.appliedObject"] {
+			if upToDate {
 				return nil
 			}
 		}
@@ -125,6 +127,48 @@ func DeployPod(kubeClient kubernetes.Interface, pod corev1.Pod, opts DeployPodOp
 	return nil
 }
 
+// PodOutOfDate returns whether the pod is already deployed, but with a
+// different spec than the given pod. In other words, it returns whether
+// DeployPod would recreate the pod.
+func PodOutOfDate(kubeClient kubernetes.Interface, pod corev1.Pod, opts DeployPodOptions) (bool, error) {
+	pod, err := PatchSandboxPod(pod)
+	if err != nil {
+		return false, errors.WithContext("patch pod", err)
+	}
+
+	curr, err := kubeClient.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.WithContext("get pod", err)
+	}
+
+	if opts.ForceRestart {
+		return true, nil
+	}
+
+	upToDate, err := podUpToDate(pod, curr, opts.Sanitizers)
+	return !upToDate, err
+}
+
+// podUpToDate returns whether the current pod was deployed from the desired
+// pod.
+func podUpToDate(desired corev1.Pod, curr *corev1.Pod, sanitizers []Sanitizer) (bool, error) {
+	// Make a copy to avoid modifying the desired pod, since that pod is used
+	// to deploy.
+	sanitized := (&desired).DeepCopy()
+	for _, sanitize := range sanitizers {
+		sanitized = sanitize(sanitized, curr)
+	}
+	annot, err := runtime.Encode(unstructured.UnstructuredJSONScheme, sanitized)
+	if err != nil {
+		return false, errors.WithContext("make apply annotation", err)
+	}
+
+	return string(annot) == curr.Annotations["blimp.appliedObject"], nil
+}
+
 func DeployServiceAccount(kubeClient kubernetes.Interface, sa corev1.ServiceAccount, roles ...rbacv1.Role) error {
 	return deployServiceAccount(kubeClient, sa, roles, nil)
 }
@@ -223,6 +267,121 @@ func DeployConfigMap(kubeClient kubernetes.Interface, configMap corev1.ConfigMap
 	return nil
 }
 
+func DeploySecret(kubeClient kubernetes.Interface, secret corev1.Secret) error {
+	secretClient := kubeClient.CoreV1().Secrets(secret.Namespace)
+	currSecret, err := secretClient.Get(secret.Name, metav1.GetOptions{})
+	if err == nil {
+		secret.ResourceVersion = currSecret.ResourceVersion
+		if _, err := secretClient.Update(&secret); err != nil {
+			return errors.WithContext("update secret", err)
+		}
+	} else if _, err := secretClient.Create(&secret); err != nil {
+		return errors.WithContext("create secret", err)
+	}
+	return nil
+}
+
+func DeployService(kubeClient kubernetes.Interface, service corev1.Service) error {
+	serviceClient := kubeClient.CoreV1().Services(service.Namespace)
+	currService, err := serviceClient.Get(service.Name, metav1.GetOptions{})
+	if err == nil {
+		// The cluster IP can't be changed once it's allocated.
+		service.ResourceVersion = currService.ResourceVersion
+		service.Spec.ClusterIP = currService.Spec.ClusterIP
+		if _, err := serviceClient.Update(&service); err != nil {
+			return errors.WithContext("update service", err)
+		}
+	} else if _, err := serviceClient.Create(&service); err != nil {
+		return errors.WithContext("create service", err)
+	}
+	return nil
+}
+
+func DeployDeployment(kubeClient kubernetes.Interface, deployment appsv1.Deployment) error {
+	template, err := PatchSandboxPodTemplate(deployment.Namespace, deployment.Spec.Template)
+	if err != nil {
+		return errors.WithContext("patch pod template", err)
+	}
+	deployment.Spec.Template = template
+
+	deploymentClient := kubeClient.AppsV1().Deployments(deployment.Namespace)
+	currDeployment, err := deploymentClient.Get(deployment.Name, metav1.GetOptions{})
+	if err == nil {
+		deployment.ResourceVersion = currDeployment.ResourceVersion
+		if _, err := deploymentClient.Update(&deployment); err != nil {
+			return errors.WithContext("update deployment", err)
+		}
+	} else if _, err := deploymentClient.Create(&deployment); err != nil {
+		return errors.WithContext("create deployment", err)
+	}
+	return nil
+}
+
+func DeployStatefulSet(kubeClient kubernetes.Interface, statefulSet appsv1.StatefulSet) error {
+	template, err := PatchSandboxPodTemplate(statefulSet.Namespace, statefulSet.Spec.Template)
+	if err != nil {
+		return errors.WithContext("patch pod template", err)
+	}
+	statefulSet.Spec.Template = template
+
+	statefulSetClient := kubeClient.AppsV1().StatefulSets(statefulSet.Namespace)
+	currStatefulSet, err := statefulSetClient.Get(statefulSet.Name, metav1.GetOptions{})
+	if err == nil {
+		statefulSet.ResourceVersion = currStatefulSet.ResourceVersion
+		if _, err := statefulSetClient.Update(&statefulSet); err != nil {
+			return errors.WithContext("update statefulset", err)
+		}
+	} else if _, err := statefulSetClient.Create(&statefulSet); err != nil {
+		return errors.WithContext("create statefulset", err)
+	}
+	return nil
+}
+
+// DeployJob creates the job if it doesn't already exist. Existing jobs aren't
+// modified since their pod templates are immutable, and recreating them would
+// rerun jobs that already completed.
+func DeployJob(kubeClient kubernetes.Interface, job batchv1.Job) error {
+	jobClient := kubeClient.BatchV1().Jobs(job.Namespace)
+	_, err := jobClient.Get(job.Name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return nil
+	case !kerrors.IsNotFound(err):
+		return errors.WithContext("get job", err)
+	}
+
+	template, err := PatchSandboxPodTemplate(job.Namespace, job.Spec.Template)
+	if err != nil {
+		return errors.WithContext("patch pod template", err)
+	}
+	job.Spec.Template = template
+
+	if _, err := jobClient.Create(&job); err != nil {
+		return errors.WithContext("create job", err)
+	}
+	return nil
+}
+
+func DeployCronJob(kubeClient kubernetes.Interface, cronJob batchv1beta1.CronJob) error {
+	template, err := PatchSandboxPodTemplate(cronJob.Namespace, cronJob.Spec.JobTemplate.Spec.Template)
+	if err != nil {
+		return errors.WithContext("patch pod template", err)
+	}
+	cronJob.Spec.JobTemplate.Spec.Template = template
+
+	cronJobClient := kubeClient.BatchV1beta1().CronJobs(cronJob.Namespace)
+	currCronJob, err := cronJobClient.Get(cronJob.Name, metav1.GetOptions{})
+	if err == nil {
+		cronJob.ResourceVersion = currCronJob.ResourceVersion
+		if _, err := cronJobClient.Update(&cronJob); err != nil {
+			return errors.WithContext("update cronjob", err)
+		}
+	} else if _, err := cronJobClient.Create(&cronJob); err != nil {
+		return errors.WithContext("create cronjob", err)
+	}
+	return nil
+}
+
 func SanitizeIgnoreInitContainerImages(desired, curr *corev1.Pod) *corev1.Pod {
 	currImages := map[string]string{}
 	for _, c := range curr.Spec.InitContainers {
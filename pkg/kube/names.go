@@ -2,17 +2,49 @@ package kube
 
 const (
 	ContainerNameCopyVCP                   = "copy-vcp"
+	ContainerNameCopyPrlimit               = "copy-prlimit"
 	ContainerNameInitializeVolumeFromImage = "vcp"
 	ContainerNameWaitDependsOn             = "wait-depends-on"
 	ContainerNameWaitInitialSync           = "wait-sync"
 	ContainerNameWaitInitializedVolumes    = "wait-initialized-volumes"
+	ContainerNameTestResults               = "test-results"
+	ContainerNameSSHAgent                  = "ssh-agent"
+	ContainerNameDockerSocket              = "docker-socket"
 
 	BlimpNamespace      = "blimp-system"
 	PreviewCLINamespace = "blimp-cli"
 
 	ExposeAnnotation            = "blimp.exposed"
 	NodePublicAddressAnnotation = "blimp.public-address"
+	OwnerAnnotation             = "blimp.owner"
+	LastActivityAnnotation      = "blimp.last-activity"
+	BoostAnnotation             = "blimp.boost"
+	AddonsAnnotation            = "blimp.addons"
+	PausedAnnotation            = "blimp.paused"
+	PlacementAnnotation         = "blimp.placement"
+	SandboxEnvAnnotation        = "blimp.sandbox-env"
+	RuntimeClassAnnotation      = "blimp.runtime-class"
+	EgressPolicyAnnotation      = "blimp.egress-policy"
+	ImageScanWarningsAnnotation = "blimp.image-scan-warnings"
+
+	// ScopedTokensAnnotation held the scoped tokens of sandboxes created by
+	// old versions of the manager. It's only read to migrate the tokens into
+	// the manager's store.
+	ScopedTokensAnnotation = "blimp.scoped-tokens"
+
+	// SSHAgentLabel marks the pods that may use the user's SSH agent.
+	SSHAgentLabel = "blimp.ssh-agent"
+
+	// DockerSocketLabel marks the pods that may use the emulated Docker
+	// socket. DockerOwnerLabel records the UID of the pod that started a
+	// container through the socket.
+	DockerSocketLabel = "blimp.docker-socket"
+	DockerOwnerLabel  = "blimp.docker-owner"
 
 	PodNameSyncthing = "syncthing"
 	PodNameBuildkitd = "buildkitd"
+
+	// TestResultsDir is where `blimp test` expects test services to write
+	// their results, such as JUnit reports.
+	TestResultsDir = "/blimp-test-results"
 )
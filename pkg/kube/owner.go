@@ -0,0 +1,30 @@
+package kube
+
+import (
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// NamespaceOwner returns the user that owns the sandbox namespace, according
+// to its OwnerAnnotation. It returns an empty string if the namespace doesn't
+// exist yet. Other errors are returned so that callers reject the request
+// rather than skipping the ownership check.
+func NamespaceOwner(namespaceLister listers.NamespaceLister, namespace string) (string, error) {
+	ns, err := namespaceLister.Get(namespace)
+	switch {
+	case kerrors.IsNotFound(err):
+		return "", nil
+	case err != nil:
+		return "", errors.WithContext("get namespace", err)
+	}
+	return ns.Annotations[OwnerAnnotation], nil
+}
+
+// NamespaceCollisionError is returned to users whose namespace is owned by
+// another user.
+func NamespaceCollisionError(namespace string) error {
+	return errors.NewCodedError(errors.CodeNamespaceCollision,
+		"Your sandbox's name (%s) is already used by another user's sandbox.", namespace)
+}
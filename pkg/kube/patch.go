@@ -0,0 +1,173 @@
+package kube
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+const (
+	// PodPatchesConfigMap shares the sandbox pod patches with the node
+	// controllers, which create pods for the emulated Docker socket.
+	PodPatchesConfigMap = "sandbox-pod-patches"
+
+	// PodPatchesKey is the key in PodPatchesConfigMap that contains the
+	// patches.
+	PodPatchesKey = "patches.yaml"
+)
+
+// sandboxPodPatches are strategic merge patches that are applied to every pod
+// in a sandbox namespace, in order.
+var sandboxPodPatches [][]byte
+
+// SetSandboxPodPatches configures the strategic merge patches that are
+// applied to sandbox pods, such as to add an operator's labels, tolerations,
+// or sidecars. The patches are JSON. It should be called before any pods are
+// deployed.
+func SetSandboxPodPatches(patches [][]byte) error {
+	// Make sure the patches are valid so that errors are reported at startup
+	// rather than when pods are deployed.
+	for i, patch := range patches {
+		if _, err := applyPodPatches(corev1.Pod{}, [][]byte{patch}); err != nil {
+			return errors.WithContext(fmt.Sprintf("patch %d", i), err)
+		}
+	}
+	sandboxPodPatches = patches
+	return nil
+}
+
+// ParsePodPatches parses the YAML documents in patchesYAML into strategic
+// merge patches that can be passed to SetSandboxPodPatches.
+func ParsePodPatches(patchesYAML []byte) ([][]byte, error) {
+	var patches [][]byte
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(patchesYAML)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithContext("read patch", err)
+		}
+
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		patch, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, errors.WithContext("parse patch", err)
+		}
+		patches = append(patches, patch)
+	}
+	return patches, nil
+}
+
+// SandboxPodPatchesConfigMap returns the ConfigMap that shares the configured
+// sandbox pod patches with the node controllers. The patches are stored as
+// YAML documents so that they can be parsed with ParsePodPatches.
+func SandboxPodPatchesConfigMap() corev1.ConfigMap {
+	return corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PodPatchesConfigMap,
+			Namespace: BlimpNamespace,
+		},
+		Data: map[string]string{
+			PodPatchesKey: string(bytes.Join(sandboxPodPatches, []byte("\n---\n"))),
+		},
+	}
+}
+
+// RuntimeClassFunc returns the RuntimeClass that pods in the given namespace
+// should run with. An empty string means that the cluster's default runtime
+// should be used.
+type RuntimeClassFunc func(namespace string) (string, error)
+
+// sandboxRuntimeClass selects the RuntimeClass of sandbox pods, such as to
+// isolate them with gVisor or Kata Containers.
+var sandboxRuntimeClass RuntimeClassFunc
+
+// SetSandboxRuntimeClass configures how the RuntimeClass of sandbox pods is
+// picked. Pods that already specify a RuntimeClass are left as is. It
+// should be called before any pods are deployed.
+func SetSandboxRuntimeClass(fn RuntimeClassFunc) {
+	sandboxRuntimeClass = fn
+}
+
+// PatchSandboxPod sets the pod's RuntimeClass, and applies the sandbox pod
+// patches to it. Pods in the Blimp system namespaces aren't patched.
+// DeployPod and the other Deploy functions patch pods automatically, so this
+// only needs to be called for pods that are created directly.
+func PatchSandboxPod(pod corev1.Pod) (corev1.Pod, error) {
+	if pod.Namespace == BlimpNamespace || pod.Namespace == PreviewCLINamespace {
+		return pod, nil
+	}
+
+	if sandboxRuntimeClass != nil && pod.Spec.RuntimeClassName == nil {
+		runtimeClass, err := sandboxRuntimeClass(pod.Namespace)
+		if err != nil {
+			return corev1.Pod{}, errors.WithContext("get runtime class", err)
+		}
+
+		if runtimeClass != "" {
+			pod.Spec.RuntimeClassName = &runtimeClass
+		}
+	}
+
+	if len(sandboxPodPatches) == 0 {
+		return pod, nil
+	}
+	return applyPodPatches(pod, sandboxPodPatches)
+}
+
+// PatchSandboxPodTemplate is like PatchSandboxPod, but for the pod templates
+// of workloads such as Deployments and Jobs.
+func PatchSandboxPodTemplate(namespace string, template corev1.PodTemplateSpec) (
+	corev1.PodTemplateSpec, error) {
+	pod := corev1.Pod{
+		ObjectMeta: template.ObjectMeta,
+		Spec:       template.Spec,
+	}
+	pod.Namespace = namespace
+
+	patched, err := PatchSandboxPod(pod)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	// Templates don't have a namespace of their own.
+	patched.Namespace = template.Namespace
+	return corev1.PodTemplateSpec{
+		ObjectMeta: patched.ObjectMeta,
+		Spec:       patched.Spec,
+	}, nil
+}
+
+func applyPodPatches(pod corev1.Pod, patches [][]byte) (corev1.Pod, error) {
+	podJSON, err := json.Marshal(pod)
+	if err != nil {
+		return corev1.Pod{}, errors.WithContext("marshal pod", err)
+	}
+
+	for _, patch := range patches {
+		podJSON, err = strategicpatch.StrategicMergePatch(podJSON, patch, corev1.Pod{})
+		if err != nil {
+			return corev1.Pod{}, errors.WithContext("apply patch", err)
+		}
+	}
+
+	var patched corev1.Pod
+	if err := json.Unmarshal(podJSON, &patched); err != nil {
+		return corev1.Pod{}, errors.WithContext("unmarshal patched pod", err)
+	}
+	return patched, nil
+}
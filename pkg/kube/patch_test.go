@@ -0,0 +1,219 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPatchSandboxPod(t *testing.T) {
+	defer func() { sandboxPodPatches = nil }()
+
+	err := SetSandboxPodPatches([][]byte{
+		[]byte(`{"metadata": {"labels": {"company": "kelda"}}}`),
+		[]byte(`{"spec": {"containers": [{"name": "security-agent", "image": "agent"}]}}`),
+	})
+	assert.NoError(t, err)
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "sandbox",
+			Labels:    map[string]string{"blimp.service": "web"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "web", Image: "nginx"}},
+		},
+	}
+
+	patched, err := PatchSandboxPod(pod)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"blimp.service": "web", "company": "kelda"}, patched.Labels)
+	assert.ElementsMatch(t, []corev1.Container{
+		{Name: "security-agent", Image: "agent"},
+		{Name: "web", Image: "nginx"},
+	}, patched.Spec.Containers)
+
+	// Patching is idempotent, so restarting a pod from its current spec
+	// doesn't add duplicate sidecars.
+	repatched, err := PatchSandboxPod(patched)
+	assert.NoError(t, err)
+	assert.Equal(t, patched, repatched)
+
+	// Pods in the Blimp namespace aren't patched.
+	pod.Namespace = BlimpNamespace
+	unpatched, err := PatchSandboxPod(pod)
+	assert.NoError(t, err)
+	assert.Equal(t, pod, unpatched)
+
+	assert.Error(t, SetSandboxPodPatches([][]byte{[]byte(`not json`)}))
+}
+
+func TestSandboxRuntimeClass(t *testing.T) {
+	defer func() { sandboxRuntimeClass = nil }()
+
+	SetSandboxRuntimeClass(func(namespace string) (string, error) {
+		if namespace == "trusted" {
+			return "", nil
+		}
+		return "gvisor", nil
+	})
+
+	pod := func(namespace string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace}}
+	}
+
+	patched, err := PatchSandboxPod(pod("sandbox"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, patched.Spec.RuntimeClassName) {
+		assert.Equal(t, "gvisor", *patched.Spec.RuntimeClassName)
+	}
+
+	patched, err = PatchSandboxPod(pod("trusted"))
+	assert.NoError(t, err)
+	assert.Nil(t, patched.Spec.RuntimeClassName)
+
+	patched, err = PatchSandboxPod(pod(BlimpNamespace))
+	assert.NoError(t, err)
+	assert.Nil(t, patched.Spec.RuntimeClassName)
+
+	// Pods that already specify a runtime class keep it.
+	kata := "kata"
+	withClass := pod("sandbox")
+	withClass.Spec.RuntimeClassName = &kata
+	patched, err = PatchSandboxPod(withClass)
+	assert.NoError(t, err)
+	assert.Equal(t, &kata, patched.Spec.RuntimeClassName)
+}
+
+func TestDeployWorkloadsRuntimeClass(t *testing.T) {
+	defer func() { sandboxRuntimeClass = nil }()
+
+	SetSandboxRuntimeClass(func(namespace string) (string, error) {
+		return "gvisor", nil
+	})
+
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "web", Image: "nginx"}},
+		},
+	}
+	meta := metav1.ObjectMeta{Name: "web", Namespace: "sandbox"}
+	kubeClient := fakeKube.NewSimpleClientset()
+
+	assert.NoError(t, DeployDeployment(kubeClient, appsv1.Deployment{
+		ObjectMeta: meta,
+		Spec:       appsv1.DeploymentSpec{Template: template},
+	}))
+	assert.NoError(t, DeployStatefulSet(kubeClient, appsv1.StatefulSet{
+		ObjectMeta: meta,
+		Spec:       appsv1.StatefulSetSpec{Template: template},
+	}))
+	assert.NoError(t, DeployJob(kubeClient, batchv1.Job{
+		ObjectMeta: meta,
+		Spec:       batchv1.JobSpec{Template: template},
+	}))
+	assert.NoError(t, DeployCronJob(kubeClient, batchv1beta1.CronJob{
+		ObjectMeta: meta,
+		Spec: batchv1beta1.CronJobSpec{
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{Template: template},
+			},
+		},
+	}))
+
+	deployment, err := kubeClient.AppsV1().Deployments("sandbox").Get("web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	statefulSet, err := kubeClient.AppsV1().StatefulSets("sandbox").Get("web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	job, err := kubeClient.BatchV1().Jobs("sandbox").Get("web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	cronJob, err := kubeClient.BatchV1beta1().CronJobs("sandbox").Get("web", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	for _, deployed := range []corev1.PodTemplateSpec{
+		deployment.Spec.Template,
+		statefulSet.Spec.Template,
+		job.Spec.Template,
+		cronJob.Spec.JobTemplate.Spec.Template,
+	} {
+		if assert.NotNil(t, deployed.Spec.RuntimeClassName) {
+			assert.Equal(t, "gvisor", *deployed.Spec.RuntimeClassName)
+		}
+		// The template's namespace isn't set, even though the namespace is
+		// used to pick the RuntimeClass.
+		assert.Empty(t, deployed.Namespace)
+		assert.Equal(t, template.Labels, deployed.Labels)
+	}
+}
+
+func TestPatchSandboxPodTemplate(t *testing.T) {
+	defer func() { sandboxPodPatches = nil }()
+
+	err := SetSandboxPodPatches([][]byte{
+		[]byte(`{"metadata": {"labels": {"company": "kelda"}}}`),
+		[]byte(`{"spec": {"containers": [{"name": "security-agent", "image": "agent"}]}}`),
+	})
+	assert.NoError(t, err)
+
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "web", Image: "nginx"}},
+		},
+	}
+
+	patched, err := PatchSandboxPodTemplate("sandbox", template)
+	assert.NoError(t, err)
+	assert.Empty(t, patched.Namespace)
+	assert.Equal(t, map[string]string{"app": "web", "company": "kelda"}, patched.Labels)
+	assert.ElementsMatch(t, []corev1.Container{
+		{Name: "security-agent", Image: "agent"},
+		{Name: "web", Image: "nginx"},
+	}, patched.Spec.Containers)
+
+	// Templates in the Blimp namespace aren't patched.
+	unpatched, err := PatchSandboxPodTemplate(BlimpNamespace, template)
+	assert.NoError(t, err)
+	assert.Equal(t, template, unpatched)
+}
+
+func TestSandboxPodPatchesConfigMap(t *testing.T) {
+	defer func() { sandboxPodPatches = nil }()
+
+	patches, err := ParsePodPatches([]byte(`
+metadata:
+  labels:
+    company: kelda
+---
+spec:
+  tolerations:
+  - key: dedicated
+    value: blimp
+    effect: NoSchedule
+`))
+	assert.NoError(t, err)
+	assert.Len(t, patches, 2)
+	assert.NoError(t, SetSandboxPodPatches(patches))
+
+	// The node controllers parse the published patches into the same
+	// patches.
+	configMap := SandboxPodPatchesConfigMap()
+	assert.Equal(t, BlimpNamespace, configMap.Namespace)
+	published, err := ParsePodPatches([]byte(configMap.Data[PodPatchesKey]))
+	assert.NoError(t, err)
+	assert.Equal(t, patches, published)
+
+	// No patches are published when none are configured.
+	sandboxPodPatches = nil
+	published, err = ParsePodPatches([]byte(SandboxPodPatchesConfigMap().Data[PodPatchesKey]))
+	assert.NoError(t, err)
+	assert.Empty(t, published)
+}
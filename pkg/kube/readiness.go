@@ -0,0 +1,32 @@
+package kube
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// LogReadyCondition is the readiness gate of pods for services that set
+// x-blimp.ready_log_pattern. The cluster manager sets it once the service
+// logs a line that matches the pattern.
+const LogReadyCondition corev1.PodConditionType = "blimp.kelda.io/log-ready"
+
+// IsLogReady returns whether the pod's container has logged a line matching
+// its ready log pattern since it last started. Pods without a ready log
+// pattern are always ready.
+func IsLogReady(pod corev1.Pod) bool {
+	gated := false
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == LogReadyCondition {
+			gated = true
+		}
+	}
+	if !gated {
+		return true
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == LogReadyCondition {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -2,14 +2,82 @@ package metadata
 
 import (
 	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+
+	"github.com/kelda/blimp/pkg/proto/wait"
 )
 
 const AliasesKey = "io.kelda.blimp/aliases"
 
+// DependsOnKey is the annotation containing the services that a pod waits
+// for before booting.
+const DependsOnKey = "io.kelda.blimp/depends-on"
+
+// CPUMultiplierKey is the annotation containing how much the CPU resources of
+// a pod were scaled by a boost.
+const CPUMultiplierKey = "io.kelda.blimp/cpu-multiplier"
+
+// SecretEnvHashKey is the annotation containing a hash of a pod's secret
+// environment variables, so that the pod is restarted when they change.
+const SecretEnvHashKey = "io.kelda.blimp/secret-env-hash"
+
+// ReadyLogPatternKey is the annotation containing the service's
+// x-blimp.ready_log_pattern. The cluster manager tails the logs of pods with
+// the annotation, and marks them as ready once a line matches.
+const ReadyLogPatternKey = "io.kelda.blimp/ready-log-pattern"
+
+// VolumeInitKey is the annotation containing the progress of initializing
+// the pod's volumes from its image. It's set by the node controller when the
+// volume initialization container reports its progress.
+const VolumeInitKey = "io.kelda.blimp/volume-init"
+
+// The annotations that configure Istio's proxy sidecar when the manager runs
+// in mesh compatibility mode.
+const (
+	MeshExcludeOutboundIPRangesKey = "traffic.sidecar.istio.io/excludeOutboundIPRanges"
+	MeshExcludeInboundPortsKey     = "traffic.sidecar.istio.io/excludeInboundPorts"
+	MeshProxyConfigKey             = "proxy.istio.io/config"
+)
+
+// PassthroughAnnotationsKey is the annotation listing the annotations that
+// were copied onto a pod from its service's x-blimp.pod_annotations.
+const PassthroughAnnotationsKey = "io.kelda.blimp/passthrough-annotations"
+
 // CustomPodAnnotations contains all annotations that Blimp could apply to pods
 // that should persist across restarts, except blimp.appliedObject.
 var CustomPodAnnotations = []string{
 	AliasesKey,
+	DependsOnKey,
+	CPUMultiplierKey,
+	SecretEnvHashKey,
+	ReadyLogPatternKey,
+	PassthroughAnnotationsKey,
+	MeshExcludeOutboundIPRangesKey,
+	MeshExcludeInboundPortsKey,
+	MeshProxyConfigKey,
+}
+
+// IsCustomPodAnnotation returns whether the annotation was set by Blimp, or
+// passed through from the service's Compose config, and so should persist
+// when the pod is restarted.
+func IsCustomPodAnnotation(annotations map[string]string, key string) bool {
+	for _, custom := range CustomPodAnnotations {
+		if key == custom {
+			return true
+		}
+	}
+
+	passthrough, ok := annotations[PassthroughAnnotationsKey]
+	if !ok {
+		return false
+	}
+	for _, custom := range strings.Split(passthrough, ",") {
+		if key == custom {
+			return true
+		}
+	}
+	return false
 }
 
 func ParseAliases(aliases string) []string {
@@ -19,3 +87,26 @@ func ParseAliases(aliases string) []string {
 func Aliases(aliases []string) string {
 	return strings.Join(aliases, ",")
 }
+
+func ParseDependsOn(dependsOn string) []string {
+	return strings.Split(dependsOn, ",")
+}
+
+func DependsOn(services []string) string {
+	return strings.Join(services, ",")
+}
+
+// VolumeInit formats the progress of each volume for VolumeInitKey.
+func VolumeInit(volumes []*wait.VolumeInitProgress) (string, error) {
+	marshaler := jsonpb.Marshaler{}
+	return marshaler.MarshalToString(&wait.ReportVolumeInitRequest{Volumes: volumes})
+}
+
+// ParseVolumeInit parses the value of VolumeInitKey.
+func ParseVolumeInit(volumeInit string) ([]*wait.VolumeInitProgress, error) {
+	var progress wait.ReportVolumeInitRequest
+	if err := jsonpb.UnmarshalString(volumeInit, &progress); err != nil {
+		return nil, err
+	}
+	return progress.Volumes, nil
+}
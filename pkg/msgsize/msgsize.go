@@ -0,0 +1,123 @@
+// Package msgsize negotiates the maximum size of the gRPC messages exchanged
+// by the CLI and the cluster manager. The CLI advertises the largest message
+// it can receive in the metadata of each request, and the manager splits
+// large responses into chunks that fit.
+package msgsize
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+const (
+	// DefaultLimit is gRPC's default limit on received messages. It's assumed
+	// for clients that don't advertise their limit.
+	DefaultLimit = 4 * 1024 * 1024
+
+	// DefaultCLILimit is the largest message that the CLI accepts by default.
+	DefaultCLILimit = 64 * 1024 * 1024
+
+	// metadataKey is the metadata that clients advertise their limit in.
+	metadataKey = "blimp-max-recv-message-bytes"
+)
+
+// UnaryClientInterceptor advertises that the client can receive messages up
+// to `recvLimit` bytes.
+func UnaryClientInterceptor(recvLimit int) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(withLimit(ctx, recvLimit), method, req, reply, cc, opts...)
+		if isTooLargeErr(err) {
+			return errors.NewFriendlyError("The response from the Blimp cluster was larger than "+
+				"the limit of %s.\n"+
+				"Raise the limit by setting `max_message_bytes` in ~/.blimp/blimp.yaml.",
+				FormatBytes(recvLimit))
+		}
+		return err
+	}
+}
+
+// StreamClientInterceptor is the streaming equivalent of
+// UnaryClientInterceptor.
+func StreamClientInterceptor(recvLimit int) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withLimit(ctx, recvLimit), desc, cc, method, opts...)
+	}
+}
+
+// SendLimitInterceptor rejects requests that are larger than the server's
+// limit before they're sent, so that the user gets a useful error. The limit
+// is looked up for each request since it's only known once the client has
+// connected. Requests aren't checked if the limit isn't positive.
+func SendLimitInterceptor(sendLimit func() int) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := checkSize(req, sendLimit()); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func withLimit(ctx context.Context, limit int) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, metadataKey, strconv.Itoa(limit))
+}
+
+func checkSize(req interface{}, limit int) error {
+	msg, ok := req.(proto.Message)
+	if !ok || limit <= 0 {
+		return nil
+	}
+
+	if size := proto.Size(msg); size > limit {
+		return errors.NewFriendlyError("The request is %s, which is larger than the "+
+			"Blimp cluster's limit of %s.\n"+
+			"Ask your cluster operator to raise GRPC_MAX_MESSAGE_BYTES on the Blimp manager.",
+			FormatBytes(size), FormatBytes(limit))
+	}
+	return nil
+}
+
+// isTooLargeErr returns whether gRPC rejected a received message because it
+// was larger than the client's limit.
+func isTooLargeErr(err error) bool {
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.ResourceExhausted &&
+		strings.Contains(st.Message(), "received message larger than max")
+}
+
+// ClientLimit returns the largest message that the client that made the
+// request can receive.
+func ClientLimit(ctx context.Context) int {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return DefaultLimit
+	}
+
+	vals := md.Get(metadataKey)
+	if len(vals) == 0 {
+		return DefaultLimit
+	}
+
+	limit, err := strconv.Atoi(vals[0])
+	if err != nil || limit <= 0 {
+		return DefaultLimit
+	}
+	return limit
+}
+
+// FormatBytes formats the size in MiB, which is how the limits are usually
+// configured.
+func FormatBytes(n int) string {
+	return strconv.FormatFloat(float64(n)/(1024*1024), 'f', 1, 64) + " MiB"
+}
@@ -0,0 +1,29 @@
+package msgsize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func TestClientLimit(t *testing.T) {
+	assert.Equal(t, DefaultLimit, ClientLimit(context.Background()))
+
+	incoming := func(limit string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(metadataKey, limit))
+	}
+	assert.Equal(t, 1024, ClientLimit(incoming("1024")))
+	assert.Equal(t, DefaultLimit, ClientLimit(incoming("malformed")))
+	assert.Equal(t, DefaultLimit, ClientLimit(incoming("-1")))
+}
+
+func TestCheckSize(t *testing.T) {
+	req := &cluster.CheckVersionRequest{Version: "0.15.0"}
+	assert.NoError(t, checkSize(req, 1024))
+	assert.NoError(t, checkSize(req, 0))
+	assert.Error(t, checkSize(req, 2))
+}
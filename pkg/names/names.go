@@ -48,3 +48,23 @@ func ToDNS1123(id string) string {
 
 	return fmt.Sprintf("%s-%s", sanitized, h)
 }
+
+// PreviewNamespace returns the namespace used by the preview sandbox for the
+// given pull request. Unlike ToDNS1123, the name doesn't include a hash so
+// that operators can tell which pull request a sandbox belongs to.
+func PreviewNamespace(repo string, number uint32) string {
+	const prefix = "preview-"
+	suffix := fmt.Sprintf("-pr-%d", number)
+
+	sanitized := regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(repo), "-")
+	sanitized = strings.Trim(sanitized, "-")
+
+	// The final name must be less than 64 characters.
+	if maxLen := 63 - len(prefix) - len(suffix); len(sanitized) > maxLen {
+		sanitized = strings.TrimRight(sanitized[:maxLen], "-")
+	}
+	if len(sanitized) == 0 {
+		sanitized = "empty"
+	}
+	return prefix + sanitized + suffix
+}
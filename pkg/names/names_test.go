@@ -76,3 +76,41 @@ func TestToDNS1123(t *testing.T) {
 		assert.Equal(t, test.expOutput, podName, test.name)
 	}
 }
+
+func TestPreviewNamespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      string
+		number    uint32
+		expOutput string
+	}{
+		{
+			name:      "github repo",
+			repo:      "kelda/blimp",
+			number:    42,
+			expOutput: "preview-kelda-blimp-pr-42",
+		},
+		{
+			name:      "gitlab subgroup",
+			repo:      "Kelda/Tools/Web_App",
+			number:    7,
+			expOutput: "preview-kelda-tools-web-app-pr-7",
+		},
+		{
+			name:      "truncate",
+			repo:      "abcdefghijklmnopqrstuvwxyz/abcdefghijklmnopqrstuvwxyz-abcdefghijkl",
+			number:    1234,
+			expOutput: "preview-abcdefghijklmnopqrstuvwxyz-abcdefghijklmnopqrst-pr-1234",
+		},
+		{
+			name:      "only invalid characters",
+			repo:      "!@#",
+			number:    1,
+			expOutput: "preview-empty-pr-1",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expOutput, names.PreviewNamespace(test.repo, test.number), test.name)
+	}
+}
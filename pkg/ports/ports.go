@@ -6,4 +6,5 @@ const (
 
 	ClusterManagerGRPCInternalPort = 9000
 	ClusterManagerHTTPInternalPort = 9002
+	ClusterManagerMetricsPort      = 9003
 )
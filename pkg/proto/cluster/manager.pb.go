@@ -63,18 +63,34 @@ const (
 	ServicePhase_EXITED               ServicePhase = 6
 	ServicePhase_UNHEALTHY            ServicePhase = 7
 	ServicePhase_UNSCHEDULABLE        ServicePhase = 8
+	ServicePhase_INIT_TIMEOUT         ServicePhase = 9
+	ServicePhase_RATE_LIMITED         ServicePhase = 10
+	// SCHEDULED services run on a cron schedule, and are waiting for their
+	// next run.
+	ServicePhase_SCHEDULED ServicePhase = 11
+	// RESCHEDULING services were running on a node that was preempted, and
+	// are being moved onto another node.
+	ServicePhase_RESCHEDULING ServicePhase = 12
+	// WAITING_FOR_CAPACITY services can't be scheduled until the cluster adds
+	// more nodes.
+	ServicePhase_WAITING_FOR_CAPACITY ServicePhase = 13
 )
 
 var ServicePhase_name = map[int32]string{
-	0: "UNKNOWN",
-	1: "INITIALIZING_VOLUMES",
-	2: "WAIT_DEPENDS_ON",
-	3: "WAIT_SYNC_BIND",
-	4: "PENDING",
-	5: "RUNNING",
-	6: "EXITED",
-	7: "UNHEALTHY",
-	8: "UNSCHEDULABLE",
+	0:  "UNKNOWN",
+	1:  "INITIALIZING_VOLUMES",
+	2:  "WAIT_DEPENDS_ON",
+	3:  "WAIT_SYNC_BIND",
+	4:  "PENDING",
+	5:  "RUNNING",
+	6:  "EXITED",
+	7:  "UNHEALTHY",
+	8:  "UNSCHEDULABLE",
+	9:  "INIT_TIMEOUT",
+	10: "RATE_LIMITED",
+	11: "SCHEDULED",
+	12: "RESCHEDULING",
+	13: "WAITING_FOR_CAPACITY",
 }
 
 var ServicePhase_value = map[string]int32{
@@ -87,6 +103,11 @@ var ServicePhase_value = map[string]int32{
 	"EXITED":               6,
 	"UNHEALTHY":            7,
 	"UNSCHEDULABLE":        8,
+	"INIT_TIMEOUT":         9,
+	"RATE_LIMITED":         10,
+	"SCHEDULED":            11,
+	"RESCHEDULING":         12,
+	"WAITING_FOR_CAPACITY": 13,
 }
 
 func (x ServicePhase) String() string {
@@ -105,6 +126,10 @@ const (
 	SandboxStatus_TERMINATING    SandboxStatus_SandboxPhase = 2
 	SandboxStatus_DOES_NOT_EXIST SandboxStatus_SandboxPhase = 3
 	SandboxStatus_PREPARING      SandboxStatus_SandboxPhase = 4
+	// PAUSED sandboxes have had their services stopped by `blimp pause`.
+	// Their volumes and configuration are kept so that they can be resumed
+	// quickly.
+	SandboxStatus_PAUSED SandboxStatus_SandboxPhase = 5
 )
 
 var SandboxStatus_SandboxPhase_name = map[int32]string{
@@ -113,6 +138,7 @@ var SandboxStatus_SandboxPhase_name = map[int32]string{
 	2: "TERMINATING",
 	3: "DOES_NOT_EXIST",
 	4: "PREPARING",
+	5: "PAUSED",
 }
 
 var SandboxStatus_SandboxPhase_value = map[string]int32{
@@ -121,6 +147,7 @@ var SandboxStatus_SandboxPhase_value = map[string]int32{
 	"TERMINATING":    2,
 	"DOES_NOT_EXIST": 3,
 	"PREPARING":      4,
+	"PAUSED":         5,
 }
 
 func (x SandboxStatus_SandboxPhase) String() string {
@@ -128,11 +155,98 @@ func (x SandboxStatus_SandboxPhase) String() string {
 }
 
 func (SandboxStatus_SandboxPhase) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{14, 0}
+	return fileDescriptor_d156d5389f4d1cd6, []int{23, 0}
+}
+
+type VolumeInitStatus_State int32
+
+const (
+	VolumeInitStatus_PENDING   VolumeInitStatus_State = 0
+	VolumeInitStatus_COPYING   VolumeInitStatus_State = 1
+	VolumeInitStatus_DONE      VolumeInitStatus_State = 2
+	VolumeInitStatus_UNCHANGED VolumeInitStatus_State = 3
+	VolumeInitStatus_NOT_EMPTY VolumeInitStatus_State = 4
+	VolumeInitStatus_FAILED    VolumeInitStatus_State = 5
+)
+
+var VolumeInitStatus_State_name = map[int32]string{
+	0: "PENDING",
+	1: "COPYING",
+	2: "DONE",
+	3: "UNCHANGED",
+	4: "NOT_EMPTY",
+	5: "FAILED",
+}
+
+var VolumeInitStatus_State_value = map[string]int32{
+	"PENDING":   0,
+	"COPYING":   1,
+	"DONE":      2,
+	"UNCHANGED": 3,
+	"NOT_EMPTY": 4,
+	"FAILED":    5,
+}
+
+func (x VolumeInitStatus_State) String() string {
+	return proto.EnumName(VolumeInitStatus_State_name, int32(x))
+}
+
+func (VolumeInitStatus_State) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{25, 0}
+}
+
+type LifecycleEvent_Type int32
+
+const (
+	LifecycleEvent_UNKNOWN             LifecycleEvent_Type = 0
+	LifecycleEvent_IMAGE_PULL_STARTED  LifecycleEvent_Type = 1
+	LifecycleEvent_IMAGE_PULL_FINISHED LifecycleEvent_Type = 2
+	LifecycleEvent_CONTAINER_STARTED   LifecycleEvent_Type = 3
+	// CONTAINER_EXITED is sent when the container exits with a zero exit
+	// code, and CONTAINER_CRASHED is sent when it exits with a non-zero exit
+	// code.
+	LifecycleEvent_CONTAINER_EXITED     LifecycleEvent_Type = 4
+	LifecycleEvent_CONTAINER_CRASHED    LifecycleEvent_Type = 5
+	LifecycleEvent_CONTAINER_OOM_KILLED LifecycleEvent_Type = 6
+	// SYNC_COMPLETED is sent when the service's bind volumes finish their
+	// initial sync.
+	LifecycleEvent_SYNC_COMPLETED LifecycleEvent_Type = 7
+)
+
+var LifecycleEvent_Type_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "IMAGE_PULL_STARTED",
+	2: "IMAGE_PULL_FINISHED",
+	3: "CONTAINER_STARTED",
+	4: "CONTAINER_EXITED",
+	5: "CONTAINER_CRASHED",
+	6: "CONTAINER_OOM_KILLED",
+	7: "SYNC_COMPLETED",
+}
+
+var LifecycleEvent_Type_value = map[string]int32{
+	"UNKNOWN":              0,
+	"IMAGE_PULL_STARTED":   1,
+	"IMAGE_PULL_FINISHED":  2,
+	"CONTAINER_STARTED":    3,
+	"CONTAINER_EXITED":     4,
+	"CONTAINER_CRASHED":    5,
+	"CONTAINER_OOM_KILLED": 6,
+	"SYNC_COMPLETED":       7,
+}
+
+func (x LifecycleEvent_Type) String() string {
+	return proto.EnumName(LifecycleEvent_Type_name, int32(x))
+}
+
+func (LifecycleEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{120, 0}
 }
 
 type CheckVersionRequest struct {
-	Version              string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// max_message_bytes is the largest message that the CLI can receive.
+	MaxMessageBytes      int64    `protobuf:"varint,2,opt,name=max_message_bytes,json=maxMessageBytes,proto3" json:"max_message_bytes,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -170,14 +284,24 @@ func (m *CheckVersionRequest) GetVersion() string {
 	return ""
 }
 
+func (m *CheckVersionRequest) GetMaxMessageBytes() int64 {
+	if m != nil {
+		return m.MaxMessageBytes
+	}
+	return 0
+}
+
 type CheckVersionResponse struct {
-	Version              string        `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
-	DisplayMessage       string        `protobuf:"bytes,2,opt,name=display_message,json=displayMessage,proto3" json:"display_message,omitempty"`
-	Action               CLIAction     `protobuf:"varint,3,opt,name=action,proto3,enum=blimp.cluster.v0.CLIAction" json:"action,omitempty"`
-	Error                *errors.Error `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Version        string        `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	DisplayMessage string        `protobuf:"bytes,2,opt,name=display_message,json=displayMessage,proto3" json:"display_message,omitempty"`
+	Action         CLIAction     `protobuf:"varint,3,opt,name=action,proto3,enum=blimp.cluster.v0.CLIAction" json:"action,omitempty"`
+	Error          *errors.Error `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// max_message_bytes is the largest message that the manager can receive.
+	// Clients should reject larger requests rather than sending them.
+	MaxMessageBytes      int64    `protobuf:"varint,5,opt,name=max_message_bytes,json=maxMessageBytes,proto3" json:"max_message_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *CheckVersionResponse) Reset()         { *m = CheckVersionResponse{} }
@@ -233,15 +357,34 @@ func (m *CheckVersionResponse) GetError() *errors.Error {
 	return nil
 }
 
+func (m *CheckVersionResponse) GetMaxMessageBytes() int64 {
+	if m != nil {
+		return m.MaxMessageBytes
+	}
+	return 0
+}
+
 type CreateSandboxRequest struct {
-	OldToken             string                         `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth                `protobuf:"bytes,5,opt,name=auth,proto3" json:"auth,omitempty"`
-	ComposeFile          string                         `protobuf:"bytes,2,opt,name=composeFile,proto3" json:"composeFile,omitempty"`
-	RegistryCredentials  map[string]*RegistryCredential `protobuf:"bytes,3,rep,name=registryCredentials,proto3" json:"registryCredentials,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	SyncedFolders        map[string]string              `protobuf:"bytes,4,rep,name=syncedFolders,proto3" json:"syncedFolders,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
-	XXX_unrecognized     []byte                         `json:"-"`
-	XXX_sizecache        int32                          `json:"-"`
+	OldToken            string                         `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
+	Auth                *auth.BlimpAuth                `protobuf:"bytes,5,opt,name=auth,proto3" json:"auth,omitempty"`
+	ComposeFile         string                         `protobuf:"bytes,2,opt,name=composeFile,proto3" json:"composeFile,omitempty"`
+	RegistryCredentials map[string]*RegistryCredential `protobuf:"bytes,3,rep,name=registryCredentials,proto3" json:"registryCredentials,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	SyncedFolders       map[string]string              `protobuf:"bytes,4,rep,name=syncedFolders,proto3" json:"syncedFolders,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The IDs of the synced folders that should use delta transfers for files
+	// that have data inserted into them.
+	DeltaSyncFolders []string `protobuf:"bytes,6,rep,name=deltaSyncFolders,proto3" json:"deltaSyncFolders,omitempty"`
+	// The owners of the files in each synced folder, in the form `UID:GID`,
+	// keyed by folder ID.
+	SyncFolderOwners map[string]string `protobuf:"bytes,7,rep,name=syncFolderOwners,proto3" json:"syncFolderOwners,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The IDs of the synced folders whose scripts should be made executable
+	// because they're synced from a filesystem without executable bits.
+	InferExecutableFolders []string `protobuf:"bytes,8,rep,name=inferExecutableFolders,proto3" json:"inferExecutableFolders,omitempty"`
+	// The patterns for the files whose Windows line endings should be
+	// converted to Unix line endings, keyed by folder ID.
+	LineEndingRules      map[string]*FilePatterns `protobuf:"bytes,9,rep,name=lineEndingRules,proto3" json:"lineEndingRules,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
 }
 
 func (m *CreateSandboxRequest) Reset()         { *m = CreateSandboxRequest{} }
@@ -304,6 +447,73 @@ func (m *CreateSandboxRequest) GetSyncedFolders() map[string]string {
 	return nil
 }
 
+func (m *CreateSandboxRequest) GetDeltaSyncFolders() []string {
+	if m != nil {
+		return m.DeltaSyncFolders
+	}
+	return nil
+}
+
+func (m *CreateSandboxRequest) GetSyncFolderOwners() map[string]string {
+	if m != nil {
+		return m.SyncFolderOwners
+	}
+	return nil
+}
+
+func (m *CreateSandboxRequest) GetInferExecutableFolders() []string {
+	if m != nil {
+		return m.InferExecutableFolders
+	}
+	return nil
+}
+
+func (m *CreateSandboxRequest) GetLineEndingRules() map[string]*FilePatterns {
+	if m != nil {
+		return m.LineEndingRules
+	}
+	return nil
+}
+
+type FilePatterns struct {
+	Patterns             []string `protobuf:"bytes,1,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FilePatterns) Reset()         { *m = FilePatterns{} }
+func (m *FilePatterns) String() string { return proto.CompactTextString(m) }
+func (*FilePatterns) ProtoMessage()    {}
+func (*FilePatterns) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{3}
+}
+
+func (m *FilePatterns) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilePatterns.Unmarshal(m, b)
+}
+func (m *FilePatterns) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilePatterns.Marshal(b, m, deterministic)
+}
+func (m *FilePatterns) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilePatterns.Merge(m, src)
+}
+func (m *FilePatterns) XXX_Size() int {
+	return xxx_messageInfo_FilePatterns.Size(m)
+}
+func (m *FilePatterns) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilePatterns.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilePatterns proto.InternalMessageInfo
+
+func (m *FilePatterns) GetPatterns() []string {
+	if m != nil {
+		return m.Patterns
+	}
+	return nil
+}
+
 type RegistryCredential struct {
 	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
 	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
@@ -316,7 +526,7 @@ func (m *RegistryCredential) Reset()         { *m = RegistryCredential{} }
 func (m *RegistryCredential) String() string { return proto.CompactTextString(m) }
 func (*RegistryCredential) ProtoMessage()    {}
 func (*RegistryCredential) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{3}
+	return fileDescriptor_d156d5389f4d1cd6, []int{4}
 }
 
 func (m *RegistryCredential) XXX_Unmarshal(b []byte) error {
@@ -363,7 +573,7 @@ func (m *AttachToSandboxRequest) Reset()         { *m = AttachToSandboxRequest{}
 func (m *AttachToSandboxRequest) String() string { return proto.CompactTextString(m) }
 func (*AttachToSandboxRequest) ProtoMessage()    {}
 func (*AttachToSandboxRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{4}
+	return fileDescriptor_d156d5389f4d1cd6, []int{5}
 }
 
 func (m *AttachToSandboxRequest) XXX_Unmarshal(b []byte) error {
@@ -412,7 +622,7 @@ func (m *AttachToSandboxResponse) Reset()         { *m = AttachToSandboxResponse
 func (m *AttachToSandboxResponse) String() string { return proto.CompactTextString(m) }
 func (*AttachToSandboxResponse) ProtoMessage()    {}
 func (*AttachToSandboxResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{5}
+	return fileDescriptor_d156d5389f4d1cd6, []int{6}
 }
 
 func (m *AttachToSandboxResponse) XXX_Unmarshal(b []byte) error {
@@ -478,7 +688,7 @@ func (m *CreateSandboxResponse) Reset()         { *m = CreateSandboxResponse{} }
 func (m *CreateSandboxResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateSandboxResponse) ProtoMessage()    {}
 func (*CreateSandboxResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{6}
+	return fileDescriptor_d156d5389f4d1cd6, []int{7}
 }
 
 func (m *CreateSandboxResponse) XXX_Unmarshal(b []byte) error {
@@ -549,20 +759,28 @@ func (m *CreateSandboxResponse) GetAction() CLIAction {
 }
 
 type DeployRequest struct {
-	OldToken             string            `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth   `protobuf:"bytes,4,opt,name=auth,proto3" json:"auth,omitempty"`
-	ComposeFile          string            `protobuf:"bytes,2,opt,name=composeFile,proto3" json:"composeFile,omitempty"`
-	BuiltImages          map[string]string `protobuf:"bytes,3,rep,name=builtImages,proto3" json:"builtImages,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	OldToken    string            `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
+	Auth        *auth.BlimpAuth   `protobuf:"bytes,4,opt,name=auth,proto3" json:"auth,omitempty"`
+	ComposeFile string            `protobuf:"bytes,2,opt,name=composeFile,proto3" json:"composeFile,omitempty"`
+	BuiltImages map[string]string `protobuf:"bytes,3,rep,name=builtImages,proto3" json:"builtImages,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Kubernetes YAML files that are deployed into the sandbox alongside the
+	// Compose services.
+	KubernetesManifests []string `protobuf:"bytes,5,rep,name=kubernetes_manifests,json=kubernetesManifests,proto3" json:"kubernetes_manifests,omitempty"`
+	// project is the name of the Compose project. Deploying a project only
+	// affects the services in that project, so that multiple projects can be
+	// deployed into the same sandbox. The services of unnamed projects are
+	// deployed into the default project.
+	Project              string   `protobuf:"bytes,6,opt,name=project,proto3" json:"project,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DeployRequest) Reset()         { *m = DeployRequest{} }
 func (m *DeployRequest) String() string { return proto.CompactTextString(m) }
 func (*DeployRequest) ProtoMessage()    {}
 func (*DeployRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{7}
+	return fileDescriptor_d156d5389f4d1cd6, []int{8}
 }
 
 func (m *DeployRequest) XXX_Unmarshal(b []byte) error {
@@ -611,6 +829,20 @@ func (m *DeployRequest) GetBuiltImages() map[string]string {
 	return nil
 }
 
+func (m *DeployRequest) GetKubernetesManifests() []string {
+	if m != nil {
+		return m.KubernetesManifests
+	}
+	return nil
+}
+
+func (m *DeployRequest) GetProject() string {
+	if m != nil {
+		return m.Project
+	}
+	return ""
+}
+
 type DeployResponse struct {
 	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
@@ -622,7 +854,7 @@ func (m *DeployResponse) Reset()         { *m = DeployResponse{} }
 func (m *DeployResponse) String() string { return proto.CompactTextString(m) }
 func (*DeployResponse) ProtoMessage()    {}
 func (*DeployResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{8}
+	return fileDescriptor_d156d5389f4d1cd6, []int{9}
 }
 
 func (m *DeployResponse) XXX_Unmarshal(b []byte) error {
@@ -664,7 +896,7 @@ func (m *KubeCredentials) Reset()         { *m = KubeCredentials{} }
 func (m *KubeCredentials) String() string { return proto.CompactTextString(m) }
 func (*KubeCredentials) ProtoMessage()    {}
 func (*KubeCredentials) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{9}
+	return fileDescriptor_d156d5389f4d1cd6, []int{10}
 }
 
 func (m *KubeCredentials) XXX_Unmarshal(b []byte) error {
@@ -714,19 +946,24 @@ func (m *KubeCredentials) GetNamespace() string {
 }
 
 type DeleteSandboxRequest struct {
-	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth `protobuf:"bytes,3,opt,name=auth,proto3" json:"auth,omitempty"`
-	DeleteVolumes        bool            `protobuf:"varint,2,opt,name=delete_volumes,json=deleteVolumes,proto3" json:"delete_volumes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	OldToken      string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
+	Auth          *auth.BlimpAuth `protobuf:"bytes,3,opt,name=auth,proto3" json:"auth,omitempty"`
+	DeleteVolumes bool            `protobuf:"varint,2,opt,name=delete_volumes,json=deleteVolumes,proto3" json:"delete_volumes,omitempty"`
+	// project is the name of the Compose project to remove. If it's set, and
+	// other projects are still deployed, only the project's services are
+	// removed rather than the entire sandbox. Volumes are only deleted along
+	// with the entire sandbox.
+	Project              string   `protobuf:"bytes,4,opt,name=project,proto3" json:"project,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DeleteSandboxRequest) Reset()         { *m = DeleteSandboxRequest{} }
 func (m *DeleteSandboxRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteSandboxRequest) ProtoMessage()    {}
 func (*DeleteSandboxRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{10}
+	return fileDescriptor_d156d5389f4d1cd6, []int{11}
 }
 
 func (m *DeleteSandboxRequest) XXX_Unmarshal(b []byte) error {
@@ -768,18 +1005,28 @@ func (m *DeleteSandboxRequest) GetDeleteVolumes() bool {
 	return false
 }
 
+func (m *DeleteSandboxRequest) GetProject() string {
+	if m != nil {
+		return m.Project
+	}
+	return ""
+}
+
 type DeleteSandboxResponse struct {
-	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// project_only is set if only the requested project's services were
+	// removed because other projects are still deployed in the sandbox.
+	ProjectOnly          bool     `protobuf:"varint,2,opt,name=project_only,json=projectOnly,proto3" json:"project_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DeleteSandboxResponse) Reset()         { *m = DeleteSandboxResponse{} }
 func (m *DeleteSandboxResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteSandboxResponse) ProtoMessage()    {}
 func (*DeleteSandboxResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{11}
+	return fileDescriptor_d156d5389f4d1cd6, []int{12}
 }
 
 func (m *DeleteSandboxResponse) XXX_Unmarshal(b []byte) error {
@@ -807,1232 +1054,7281 @@ func (m *DeleteSandboxResponse) GetError() *errors.Error {
 	return nil
 }
 
-type GetStatusRequest struct {
-	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+func (m *DeleteSandboxResponse) GetProjectOnly() bool {
+	if m != nil {
+		return m.ProjectOnly
+	}
+	return false
+}
+
+type PauseSandboxRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
 }
 
-func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
-func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
-func (*GetStatusRequest) ProtoMessage()    {}
-func (*GetStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{12}
+func (m *PauseSandboxRequest) Reset()         { *m = PauseSandboxRequest{} }
+func (m *PauseSandboxRequest) String() string { return proto.CompactTextString(m) }
+func (*PauseSandboxRequest) ProtoMessage()    {}
+func (*PauseSandboxRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{13}
 }
 
-func (m *GetStatusRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetStatusRequest.Unmarshal(m, b)
+func (m *PauseSandboxRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseSandboxRequest.Unmarshal(m, b)
 }
-func (m *GetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetStatusRequest.Marshal(b, m, deterministic)
+func (m *PauseSandboxRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseSandboxRequest.Marshal(b, m, deterministic)
 }
-func (m *GetStatusRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetStatusRequest.Merge(m, src)
+func (m *PauseSandboxRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseSandboxRequest.Merge(m, src)
 }
-func (m *GetStatusRequest) XXX_Size() int {
-	return xxx_messageInfo_GetStatusRequest.Size(m)
+func (m *PauseSandboxRequest) XXX_Size() int {
+	return xxx_messageInfo_PauseSandboxRequest.Size(m)
 }
-func (m *GetStatusRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetStatusRequest.DiscardUnknown(m)
+func (m *PauseSandboxRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseSandboxRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetStatusRequest proto.InternalMessageInfo
-
-func (m *GetStatusRequest) GetOldToken() string {
-	if m != nil {
-		return m.OldToken
-	}
-	return ""
-}
+var xxx_messageInfo_PauseSandboxRequest proto.InternalMessageInfo
 
-func (m *GetStatusRequest) GetAuth() *auth.BlimpAuth {
+func (m *PauseSandboxRequest) GetAuth() *auth.BlimpAuth {
 	if m != nil {
 		return m.Auth
 	}
 	return nil
 }
 
-type GetStatusResponse struct {
-	Error                *errors.Error  `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	Status               *SandboxStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+type PauseSandboxResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *GetStatusResponse) Reset()         { *m = GetStatusResponse{} }
-func (m *GetStatusResponse) String() string { return proto.CompactTextString(m) }
-func (*GetStatusResponse) ProtoMessage()    {}
-func (*GetStatusResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{13}
+func (m *PauseSandboxResponse) Reset()         { *m = PauseSandboxResponse{} }
+func (m *PauseSandboxResponse) String() string { return proto.CompactTextString(m) }
+func (*PauseSandboxResponse) ProtoMessage()    {}
+func (*PauseSandboxResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{14}
 }
 
-func (m *GetStatusResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetStatusResponse.Unmarshal(m, b)
+func (m *PauseSandboxResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseSandboxResponse.Unmarshal(m, b)
 }
-func (m *GetStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetStatusResponse.Marshal(b, m, deterministic)
+func (m *PauseSandboxResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseSandboxResponse.Marshal(b, m, deterministic)
 }
-func (m *GetStatusResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetStatusResponse.Merge(m, src)
+func (m *PauseSandboxResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseSandboxResponse.Merge(m, src)
 }
-func (m *GetStatusResponse) XXX_Size() int {
-	return xxx_messageInfo_GetStatusResponse.Size(m)
+func (m *PauseSandboxResponse) XXX_Size() int {
+	return xxx_messageInfo_PauseSandboxResponse.Size(m)
 }
-func (m *GetStatusResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetStatusResponse.DiscardUnknown(m)
+func (m *PauseSandboxResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseSandboxResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetStatusResponse proto.InternalMessageInfo
+var xxx_messageInfo_PauseSandboxResponse proto.InternalMessageInfo
 
-func (m *GetStatusResponse) GetError() *errors.Error {
+func (m *PauseSandboxResponse) GetError() *errors.Error {
 	if m != nil {
 		return m.Error
 	}
 	return nil
 }
 
-func (m *GetStatusResponse) GetStatus() *SandboxStatus {
+type ResumeSandboxRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ResumeSandboxRequest) Reset()         { *m = ResumeSandboxRequest{} }
+func (m *ResumeSandboxRequest) String() string { return proto.CompactTextString(m) }
+func (*ResumeSandboxRequest) ProtoMessage()    {}
+func (*ResumeSandboxRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{15}
+}
+
+func (m *ResumeSandboxRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeSandboxRequest.Unmarshal(m, b)
+}
+func (m *ResumeSandboxRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeSandboxRequest.Marshal(b, m, deterministic)
+}
+func (m *ResumeSandboxRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeSandboxRequest.Merge(m, src)
+}
+func (m *ResumeSandboxRequest) XXX_Size() int {
+	return xxx_messageInfo_ResumeSandboxRequest.Size(m)
+}
+func (m *ResumeSandboxRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeSandboxRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResumeSandboxRequest proto.InternalMessageInfo
+
+func (m *ResumeSandboxRequest) GetAuth() *auth.BlimpAuth {
 	if m != nil {
-		return m.Status
+		return m.Auth
 	}
 	return nil
 }
 
-type SandboxStatus struct {
-	Services             map[string]*ServiceStatus  `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Phase                SandboxStatus_SandboxPhase `protobuf:"varint,2,opt,name=phase,proto3,enum=blimp.cluster.v0.SandboxStatus_SandboxPhase" json:"phase,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
-	XXX_unrecognized     []byte                     `json:"-"`
-	XXX_sizecache        int32                      `json:"-"`
+type ResumeSandboxResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *SandboxStatus) Reset()         { *m = SandboxStatus{} }
-func (m *SandboxStatus) String() string { return proto.CompactTextString(m) }
-func (*SandboxStatus) ProtoMessage()    {}
-func (*SandboxStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{14}
+func (m *ResumeSandboxResponse) Reset()         { *m = ResumeSandboxResponse{} }
+func (m *ResumeSandboxResponse) String() string { return proto.CompactTextString(m) }
+func (*ResumeSandboxResponse) ProtoMessage()    {}
+func (*ResumeSandboxResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{16}
 }
 
-func (m *SandboxStatus) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SandboxStatus.Unmarshal(m, b)
+func (m *ResumeSandboxResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeSandboxResponse.Unmarshal(m, b)
 }
-func (m *SandboxStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SandboxStatus.Marshal(b, m, deterministic)
+func (m *ResumeSandboxResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeSandboxResponse.Marshal(b, m, deterministic)
 }
-func (m *SandboxStatus) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SandboxStatus.Merge(m, src)
+func (m *ResumeSandboxResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeSandboxResponse.Merge(m, src)
 }
-func (m *SandboxStatus) XXX_Size() int {
-	return xxx_messageInfo_SandboxStatus.Size(m)
+func (m *ResumeSandboxResponse) XXX_Size() int {
+	return xxx_messageInfo_ResumeSandboxResponse.Size(m)
 }
-func (m *SandboxStatus) XXX_DiscardUnknown() {
-	xxx_messageInfo_SandboxStatus.DiscardUnknown(m)
+func (m *ResumeSandboxResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeSandboxResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SandboxStatus proto.InternalMessageInfo
+var xxx_messageInfo_ResumeSandboxResponse proto.InternalMessageInfo
 
-func (m *SandboxStatus) GetServices() map[string]*ServiceStatus {
+func (m *ResumeSandboxResponse) GetError() *errors.Error {
 	if m != nil {
-		return m.Services
+		return m.Error
 	}
 	return nil
 }
 
-func (m *SandboxStatus) GetPhase() SandboxStatus_SandboxPhase {
-	if m != nil {
-		return m.Phase
-	}
-	return SandboxStatus_UNKNOWN
+type GetStatusRequest struct {
+	OldToken string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
+	Auth     *auth.BlimpAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+	// services limits the response to the given services. If it's empty, all
+	// services are included. When watching, updates are only sent when the
+	// sandbox or one of the given services changes.
+	Services []string `protobuf:"bytes,3,rep,name=services,proto3" json:"services,omitempty"`
+	// resume_token is the resume_token from the last response received by a
+	// watch that was disconnected. The manager only sends the updates that
+	// were missed since then. If the token has expired, the full status is
+	// sent.
+	ResumeToken          string   `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type ServiceStatus struct {
-	Phase                ServicePhase `protobuf:"varint,1,opt,name=phase,proto3,enum=blimp.cluster.v0.ServicePhase" json:"phase,omitempty"`
-	Msg                  string       `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
-	HasStarted           bool         `protobuf:"varint,3,opt,name=has_started,json=hasStarted,proto3" json:"has_started,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
-}
-
-func (m *ServiceStatus) Reset()         { *m = ServiceStatus{} }
-func (m *ServiceStatus) String() string { return proto.CompactTextString(m) }
-func (*ServiceStatus) ProtoMessage()    {}
-func (*ServiceStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{15}
+func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
+func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusRequest) ProtoMessage()    {}
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{17}
 }
 
-func (m *ServiceStatus) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ServiceStatus.Unmarshal(m, b)
+func (m *GetStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusRequest.Unmarshal(m, b)
 }
-func (m *ServiceStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ServiceStatus.Marshal(b, m, deterministic)
+func (m *GetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusRequest.Marshal(b, m, deterministic)
 }
-func (m *ServiceStatus) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ServiceStatus.Merge(m, src)
+func (m *GetStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusRequest.Merge(m, src)
 }
-func (m *ServiceStatus) XXX_Size() int {
-	return xxx_messageInfo_ServiceStatus.Size(m)
+func (m *GetStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStatusRequest.Size(m)
 }
-func (m *ServiceStatus) XXX_DiscardUnknown() {
-	xxx_messageInfo_ServiceStatus.DiscardUnknown(m)
+func (m *GetStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ServiceStatus proto.InternalMessageInfo
+var xxx_messageInfo_GetStatusRequest proto.InternalMessageInfo
 
-func (m *ServiceStatus) GetPhase() ServicePhase {
+func (m *GetStatusRequest) GetOldToken() string {
 	if m != nil {
-		return m.Phase
+		return m.OldToken
 	}
-	return ServicePhase_UNKNOWN
+	return ""
 }
 
-func (m *ServiceStatus) GetMsg() string {
+func (m *GetStatusRequest) GetAuth() *auth.BlimpAuth {
 	if m != nil {
-		return m.Msg
+		return m.Auth
 	}
-	return ""
+	return nil
 }
 
-func (m *ServiceStatus) GetHasStarted() bool {
+func (m *GetStatusRequest) GetServices() []string {
 	if m != nil {
-		return m.HasStarted
+		return m.Services
 	}
-	return false
+	return nil
 }
 
-type RestartRequest struct {
-	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth `protobuf:"bytes,3,opt,name=auth,proto3" json:"auth,omitempty"`
-	Service              string          `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+func (m *GetStatusRequest) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
 }
 
-func (m *RestartRequest) Reset()         { *m = RestartRequest{} }
-func (m *RestartRequest) String() string { return proto.CompactTextString(m) }
-func (*RestartRequest) ProtoMessage()    {}
-func (*RestartRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{16}
+type GetStatusResponse struct {
+	Error  *errors.Error  `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Status *SandboxStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// resume_token is set on updates sent by WatchStatus. It can be used to
+	// resume the watch if the connection breaks.
+	ResumeToken          string   `protobuf:"bytes,3,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RestartRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RestartRequest.Unmarshal(m, b)
+func (m *GetStatusResponse) Reset()         { *m = GetStatusResponse{} }
+func (m *GetStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStatusResponse) ProtoMessage()    {}
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{18}
 }
-func (m *RestartRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RestartRequest.Marshal(b, m, deterministic)
+
+func (m *GetStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusResponse.Unmarshal(m, b)
 }
-func (m *RestartRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RestartRequest.Merge(m, src)
+func (m *GetStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusResponse.Marshal(b, m, deterministic)
 }
-func (m *RestartRequest) XXX_Size() int {
-	return xxx_messageInfo_RestartRequest.Size(m)
+func (m *GetStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusResponse.Merge(m, src)
 }
-func (m *RestartRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_RestartRequest.DiscardUnknown(m)
+func (m *GetStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStatusResponse.Size(m)
+}
+func (m *GetStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RestartRequest proto.InternalMessageInfo
+var xxx_messageInfo_GetStatusResponse proto.InternalMessageInfo
 
-func (m *RestartRequest) GetOldToken() string {
+func (m *GetStatusResponse) GetError() *errors.Error {
 	if m != nil {
-		return m.OldToken
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetStatusResponse) GetStatus() *SandboxStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *GetStatusResponse) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
 	}
 	return ""
 }
 
-func (m *RestartRequest) GetAuth() *auth.BlimpAuth {
+type GetStatusesRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// namespaces are the sandboxes to get the status of. The caller's own
+	// sandbox is always allowed. Other sandboxes must be shared with the caller
+	// by including a token with the `status` scope for them in `tokens`.
+	Namespaces           []string `protobuf:"bytes,2,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Tokens               []string `protobuf:"bytes,3,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStatusesRequest) Reset()         { *m = GetStatusesRequest{} }
+func (m *GetStatusesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusesRequest) ProtoMessage()    {}
+func (*GetStatusesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{19}
+}
+
+func (m *GetStatusesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusesRequest.Unmarshal(m, b)
+}
+func (m *GetStatusesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusesRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStatusesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusesRequest.Merge(m, src)
+}
+func (m *GetStatusesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStatusesRequest.Size(m)
+}
+func (m *GetStatusesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusesRequest proto.InternalMessageInfo
+
+func (m *GetStatusesRequest) GetAuth() *auth.BlimpAuth {
 	if m != nil {
 		return m.Auth
 	}
 	return nil
 }
 
-func (m *RestartRequest) GetService() string {
+func (m *GetStatusesRequest) GetNamespaces() []string {
 	if m != nil {
-		return m.Service
+		return m.Namespaces
 	}
-	return ""
+	return nil
 }
 
-type RestartResponse struct {
-	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+func (m *GetStatusesRequest) GetTokens() []string {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
 }
 
-func (m *RestartResponse) Reset()         { *m = RestartResponse{} }
-func (m *RestartResponse) String() string { return proto.CompactTextString(m) }
-func (*RestartResponse) ProtoMessage()    {}
-func (*RestartResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{17}
+type GetStatusesResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// statuses maps each requested namespace to its status.
+	Statuses             map[string]*SandboxStatus `protobuf:"bytes,2,rep,name=statuses,proto3" json:"statuses,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
 }
 
-func (m *RestartResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RestartResponse.Unmarshal(m, b)
+func (m *GetStatusesResponse) Reset()         { *m = GetStatusesResponse{} }
+func (m *GetStatusesResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStatusesResponse) ProtoMessage()    {}
+func (*GetStatusesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{20}
 }
-func (m *RestartResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RestartResponse.Marshal(b, m, deterministic)
+
+func (m *GetStatusesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusesResponse.Unmarshal(m, b)
 }
-func (m *RestartResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RestartResponse.Merge(m, src)
+func (m *GetStatusesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusesResponse.Marshal(b, m, deterministic)
 }
-func (m *RestartResponse) XXX_Size() int {
-	return xxx_messageInfo_RestartResponse.Size(m)
+func (m *GetStatusesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusesResponse.Merge(m, src)
 }
-func (m *RestartResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_RestartResponse.DiscardUnknown(m)
+func (m *GetStatusesResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStatusesResponse.Size(m)
+}
+func (m *GetStatusesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusesResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RestartResponse proto.InternalMessageInfo
+var xxx_messageInfo_GetStatusesResponse proto.InternalMessageInfo
 
-func (m *RestartResponse) GetError() *errors.Error {
+func (m *GetStatusesResponse) GetError() *errors.Error {
 	if m != nil {
 		return m.Error
 	}
 	return nil
 }
 
-type TagImageRequest struct {
-	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
-	Image                string   `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
-	Tag                  string   `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+func (m *GetStatusesResponse) GetStatuses() map[string]*SandboxStatus {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+// PollStatusRequest is used by clients that can't keep a WatchStatus stream
+// open, such as when a proxy kills long-lived connections.
+type PollStatusRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// services limits the response to the given services. If it's empty, all
+	// services are included.
+	Services []string `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	// cursor is the cursor returned by the previous poll. If it's empty, the
+	// full status is returned.
+	Cursor string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// wait_seconds is how long the manager should wait for the status to
+	// change before responding with not_modified.
+	WaitSeconds          int32    `protobuf:"varint,4,opt,name=wait_seconds,json=waitSeconds,proto3" json:"wait_seconds,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *TagImageRequest) Reset()         { *m = TagImageRequest{} }
-func (m *TagImageRequest) String() string { return proto.CompactTextString(m) }
-func (*TagImageRequest) ProtoMessage()    {}
-func (*TagImageRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{18}
+func (m *PollStatusRequest) Reset()         { *m = PollStatusRequest{} }
+func (m *PollStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*PollStatusRequest) ProtoMessage()    {}
+func (*PollStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{21}
 }
 
-func (m *TagImageRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TagImageRequest.Unmarshal(m, b)
+func (m *PollStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PollStatusRequest.Unmarshal(m, b)
 }
-func (m *TagImageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TagImageRequest.Marshal(b, m, deterministic)
+func (m *PollStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PollStatusRequest.Marshal(b, m, deterministic)
 }
-func (m *TagImageRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TagImageRequest.Merge(m, src)
+func (m *PollStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PollStatusRequest.Merge(m, src)
 }
-func (m *TagImageRequest) XXX_Size() int {
-	return xxx_messageInfo_TagImageRequest.Size(m)
+func (m *PollStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_PollStatusRequest.Size(m)
 }
-func (m *TagImageRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_TagImageRequest.DiscardUnknown(m)
+func (m *PollStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PollStatusRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TagImageRequest proto.InternalMessageInfo
+var xxx_messageInfo_PollStatusRequest proto.InternalMessageInfo
 
-func (m *TagImageRequest) GetService() string {
+func (m *PollStatusRequest) GetAuth() *auth.BlimpAuth {
 	if m != nil {
-		return m.Service
+		return m.Auth
 	}
-	return ""
+	return nil
 }
 
-func (m *TagImageRequest) GetImage() string {
+func (m *PollStatusRequest) GetServices() []string {
 	if m != nil {
-		return m.Image
+		return m.Services
 	}
-	return ""
+	return nil
 }
 
-func (m *TagImageRequest) GetTag() string {
+func (m *PollStatusRequest) GetCursor() string {
 	if m != nil {
-		return m.Tag
+		return m.Cursor
 	}
 	return ""
 }
 
-type TagImagesRequest struct {
-	OldToken             string                         `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth                `protobuf:"bytes,4,opt,name=auth,proto3" json:"auth,omitempty"`
-	TagRequests          []*TagImageRequest             `protobuf:"bytes,2,rep,name=tagRequests,proto3" json:"tagRequests,omitempty"`
-	RegistryCredentials  map[string]*RegistryCredential `protobuf:"bytes,3,rep,name=registryCredentials,proto3" json:"registryCredentials,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
-	XXX_unrecognized     []byte                         `json:"-"`
-	XXX_sizecache        int32                          `json:"-"`
+func (m *PollStatusRequest) GetWaitSeconds() int32 {
+	if m != nil {
+		return m.WaitSeconds
+	}
+	return 0
 }
 
-func (m *TagImagesRequest) Reset()         { *m = TagImagesRequest{} }
-func (m *TagImagesRequest) String() string { return proto.CompactTextString(m) }
-func (*TagImagesRequest) ProtoMessage()    {}
-func (*TagImagesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{19}
+type PollStatusResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// not_modified is set if the status hasn't changed since the request's
+	// cursor. The other fields are empty in that case.
+	NotModified bool `protobuf:"varint,2,opt,name=not_modified,json=notModified,proto3" json:"not_modified,omitempty"`
+	// cursor identifies the status returned by this response. It should be
+	// passed in the next poll.
+	Cursor string                     `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Phase  SandboxStatus_SandboxPhase `protobuf:"varint,4,opt,name=phase,proto3,enum=blimp.cluster.v0.SandboxStatus_SandboxPhase" json:"phase,omitempty"`
+	// services contains the services whose status changed since the request's
+	// cursor.
+	Services map[string]*ServiceStatus `protobuf:"bytes,5,rep,name=services,proto3" json:"services,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// removed_services contains the services that no longer exist.
+	RemovedServices      []string `protobuf:"bytes,6,rep,name=removed_services,json=removedServices,proto3" json:"removed_services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *TagImagesRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TagImagesRequest.Unmarshal(m, b)
+func (m *PollStatusResponse) Reset()         { *m = PollStatusResponse{} }
+func (m *PollStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*PollStatusResponse) ProtoMessage()    {}
+func (*PollStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{22}
 }
-func (m *TagImagesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TagImagesRequest.Marshal(b, m, deterministic)
+
+func (m *PollStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PollStatusResponse.Unmarshal(m, b)
 }
-func (m *TagImagesRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TagImagesRequest.Merge(m, src)
+func (m *PollStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PollStatusResponse.Marshal(b, m, deterministic)
 }
-func (m *TagImagesRequest) XXX_Size() int {
-	return xxx_messageInfo_TagImagesRequest.Size(m)
+func (m *PollStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PollStatusResponse.Merge(m, src)
 }
-func (m *TagImagesRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_TagImagesRequest.DiscardUnknown(m)
+func (m *PollStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_PollStatusResponse.Size(m)
+}
+func (m *PollStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PollStatusResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TagImagesRequest proto.InternalMessageInfo
+var xxx_messageInfo_PollStatusResponse proto.InternalMessageInfo
 
-func (m *TagImagesRequest) GetOldToken() string {
+func (m *PollStatusResponse) GetError() *errors.Error {
 	if m != nil {
-		return m.OldToken
+		return m.Error
 	}
-	return ""
+	return nil
 }
 
-func (m *TagImagesRequest) GetAuth() *auth.BlimpAuth {
+func (m *PollStatusResponse) GetNotModified() bool {
 	if m != nil {
-		return m.Auth
+		return m.NotModified
 	}
-	return nil
+	return false
 }
 
-func (m *TagImagesRequest) GetTagRequests() []*TagImageRequest {
+func (m *PollStatusResponse) GetCursor() string {
 	if m != nil {
-		return m.TagRequests
+		return m.Cursor
 	}
-	return nil
+	return ""
 }
 
-func (m *TagImagesRequest) GetRegistryCredentials() map[string]*RegistryCredential {
+func (m *PollStatusResponse) GetPhase() SandboxStatus_SandboxPhase {
 	if m != nil {
-		return m.RegistryCredentials
+		return m.Phase
 	}
-	return nil
+	return SandboxStatus_UNKNOWN
 }
 
-type TagImagesResponse struct {
-	Service              string        `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
-	Error                *errors.Error `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+func (m *PollStatusResponse) GetServices() map[string]*ServiceStatus {
+	if m != nil {
+		return m.Services
+	}
+	return nil
 }
 
-func (m *TagImagesResponse) Reset()         { *m = TagImagesResponse{} }
-func (m *TagImagesResponse) String() string { return proto.CompactTextString(m) }
-func (*TagImagesResponse) ProtoMessage()    {}
-func (*TagImagesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{20}
+func (m *PollStatusResponse) GetRemovedServices() []string {
+	if m != nil {
+		return m.RemovedServices
+	}
+	return nil
 }
 
-func (m *TagImagesResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TagImagesResponse.Unmarshal(m, b)
+type SandboxStatus struct {
+	Services map[string]*ServiceStatus  `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Phase    SandboxStatus_SandboxPhase `protobuf:"varint,2,opt,name=phase,proto3,enum=blimp.cluster.v0.SandboxStatus_SandboxPhase" json:"phase,omitempty"`
+	// warnings are problems with the sandbox that don't stop it from running,
+	// such as vulnerabilities found in its images.
+	Warnings             []string `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *TagImagesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TagImagesResponse.Marshal(b, m, deterministic)
+
+func (m *SandboxStatus) Reset()         { *m = SandboxStatus{} }
+func (m *SandboxStatus) String() string { return proto.CompactTextString(m) }
+func (*SandboxStatus) ProtoMessage()    {}
+func (*SandboxStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{23}
 }
-func (m *TagImagesResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TagImagesResponse.Merge(m, src)
+
+func (m *SandboxStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SandboxStatus.Unmarshal(m, b)
 }
-func (m *TagImagesResponse) XXX_Size() int {
-	return xxx_messageInfo_TagImagesResponse.Size(m)
+func (m *SandboxStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SandboxStatus.Marshal(b, m, deterministic)
 }
-func (m *TagImagesResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_TagImagesResponse.DiscardUnknown(m)
+func (m *SandboxStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SandboxStatus.Merge(m, src)
+}
+func (m *SandboxStatus) XXX_Size() int {
+	return xxx_messageInfo_SandboxStatus.Size(m)
+}
+func (m *SandboxStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_SandboxStatus.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TagImagesResponse proto.InternalMessageInfo
+var xxx_messageInfo_SandboxStatus proto.InternalMessageInfo
 
-func (m *TagImagesResponse) GetService() string {
+func (m *SandboxStatus) GetServices() map[string]*ServiceStatus {
 	if m != nil {
-		return m.Service
+		return m.Services
 	}
-	return ""
+	return nil
 }
 
-func (m *TagImagesResponse) GetError() *errors.Error {
+func (m *SandboxStatus) GetPhase() SandboxStatus_SandboxPhase {
 	if m != nil {
-		return m.Error
+		return m.Phase
+	}
+	return SandboxStatus_UNKNOWN
+}
+
+func (m *SandboxStatus) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
 	}
 	return nil
 }
 
-type ExposeRequest struct {
-	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth `protobuf:"bytes,4,opt,name=auth,proto3" json:"auth,omitempty"`
-	Service              string          `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
-	Port                 uint32          `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+type ServiceStatus struct {
+	Phase      ServicePhase `protobuf:"varint,1,opt,name=phase,proto3,enum=blimp.cluster.v0.ServicePhase" json:"phase,omitempty"`
+	Msg        string       `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	HasStarted bool         `protobuf:"varint,3,opt,name=has_started,json=hasStarted,proto3" json:"has_started,omitempty"`
+	// retry_at is the Unix timestamp of when the manager will next retry
+	// pulling the service's image. It's only set if the phase is RATE_LIMITED.
+	RetryAt int64 `protobuf:"varint,4,opt,name=retry_at,json=retryAt,proto3" json:"retry_at,omitempty"`
+	// exit_code is the exit code of the service's container. It's only set if
+	// the phase is EXITED, or if the phase is SCHEDULED and the service has
+	// finished a run.
+	ExitCode int32 `protobuf:"varint,5,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	// schedule is the cron schedule of services that set x-blimp.schedule.
+	Schedule string `protobuf:"bytes,6,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	// last_run_at is the Unix timestamp of when a scheduled service's most
+	// recent run started. It's zero if the service hasn't run yet.
+	LastRunAt int64 `protobuf:"varint,7,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`
+	// mesh is the status of the service mesh's sidecar. It's only set if a
+	// sidecar was injected into the service's pod.
+	Mesh *MeshStatus `protobuf:"bytes,8,opt,name=mesh,proto3" json:"mesh,omitempty"`
+	// project is the name of the Compose project that the service belongs to.
+	// It's empty for services in the default project.
+	Project string `protobuf:"bytes,9,opt,name=project,proto3" json:"project,omitempty"`
+	// volume_init is the progress of initializing each of the service's
+	// volumes from its image. It's only set while the volumes are being
+	// initialized.
+	VolumeInit           []*VolumeInitStatus `protobuf:"bytes,10,rep,name=volume_init,json=volumeInit,proto3" json:"volume_init,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
-func (m *ExposeRequest) Reset()         { *m = ExposeRequest{} }
-func (m *ExposeRequest) String() string { return proto.CompactTextString(m) }
-func (*ExposeRequest) ProtoMessage()    {}
-func (*ExposeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{21}
+func (m *ServiceStatus) Reset()         { *m = ServiceStatus{} }
+func (m *ServiceStatus) String() string { return proto.CompactTextString(m) }
+func (*ServiceStatus) ProtoMessage()    {}
+func (*ServiceStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{24}
 }
 
-func (m *ExposeRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ExposeRequest.Unmarshal(m, b)
+func (m *ServiceStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceStatus.Unmarshal(m, b)
 }
-func (m *ExposeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ExposeRequest.Marshal(b, m, deterministic)
+func (m *ServiceStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceStatus.Marshal(b, m, deterministic)
 }
-func (m *ExposeRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ExposeRequest.Merge(m, src)
+func (m *ServiceStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceStatus.Merge(m, src)
 }
-func (m *ExposeRequest) XXX_Size() int {
-	return xxx_messageInfo_ExposeRequest.Size(m)
+func (m *ServiceStatus) XXX_Size() int {
+	return xxx_messageInfo_ServiceStatus.Size(m)
 }
-func (m *ExposeRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ExposeRequest.DiscardUnknown(m)
+func (m *ServiceStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceStatus.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ExposeRequest proto.InternalMessageInfo
+var xxx_messageInfo_ServiceStatus proto.InternalMessageInfo
 
-func (m *ExposeRequest) GetOldToken() string {
+func (m *ServiceStatus) GetPhase() ServicePhase {
 	if m != nil {
-		return m.OldToken
+		return m.Phase
 	}
-	return ""
+	return ServicePhase_UNKNOWN
 }
 
-func (m *ExposeRequest) GetAuth() *auth.BlimpAuth {
+func (m *ServiceStatus) GetMsg() string {
 	if m != nil {
-		return m.Auth
+		return m.Msg
 	}
-	return nil
+	return ""
 }
 
-func (m *ExposeRequest) GetService() string {
+func (m *ServiceStatus) GetHasStarted() bool {
 	if m != nil {
-		return m.Service
+		return m.HasStarted
 	}
-	return ""
+	return false
 }
 
-func (m *ExposeRequest) GetPort() uint32 {
+func (m *ServiceStatus) GetRetryAt() int64 {
 	if m != nil {
-		return m.Port
+		return m.RetryAt
 	}
 	return 0
 }
 
-type ExposeResponse struct {
-	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	Link                 string        `protobuf:"bytes,2,opt,name=link,proto3" json:"link,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+func (m *ServiceStatus) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
 }
 
-func (m *ExposeResponse) Reset()         { *m = ExposeResponse{} }
-func (m *ExposeResponse) String() string { return proto.CompactTextString(m) }
-func (*ExposeResponse) ProtoMessage()    {}
-func (*ExposeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{22}
+func (m *ServiceStatus) GetSchedule() string {
+	if m != nil {
+		return m.Schedule
+	}
+	return ""
 }
 
-func (m *ExposeResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ExposeResponse.Unmarshal(m, b)
-}
-func (m *ExposeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ExposeResponse.Marshal(b, m, deterministic)
-}
-func (m *ExposeResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ExposeResponse.Merge(m, src)
-}
-func (m *ExposeResponse) XXX_Size() int {
-	return xxx_messageInfo_ExposeResponse.Size(m)
-}
-func (m *ExposeResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ExposeResponse.DiscardUnknown(m)
+func (m *ServiceStatus) GetLastRunAt() int64 {
+	if m != nil {
+		return m.LastRunAt
+	}
+	return 0
 }
 
-var xxx_messageInfo_ExposeResponse proto.InternalMessageInfo
-
-func (m *ExposeResponse) GetError() *errors.Error {
+func (m *ServiceStatus) GetMesh() *MeshStatus {
 	if m != nil {
-		return m.Error
+		return m.Mesh
 	}
 	return nil
 }
 
-func (m *ExposeResponse) GetLink() string {
+func (m *ServiceStatus) GetProject() string {
 	if m != nil {
-		return m.Link
+		return m.Project
 	}
 	return ""
 }
 
-type UnexposeRequest struct {
-	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+func (m *ServiceStatus) GetVolumeInit() []*VolumeInitStatus {
+	if m != nil {
+		return m.VolumeInit
+	}
+	return nil
 }
 
-func (m *UnexposeRequest) Reset()         { *m = UnexposeRequest{} }
-func (m *UnexposeRequest) String() string { return proto.CompactTextString(m) }
-func (*UnexposeRequest) ProtoMessage()    {}
-func (*UnexposeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{23}
+type VolumeInitStatus struct {
+	// volume is the path that the volume is mounted at in the container.
+	Volume               string                 `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	State                VolumeInitStatus_State `protobuf:"varint,2,opt,name=state,proto3,enum=blimp.cluster.v0.VolumeInitStatus_State" json:"state,omitempty"`
+	CopiedBytes          int64                  `protobuf:"varint,3,opt,name=copied_bytes,json=copiedBytes,proto3" json:"copied_bytes,omitempty"`
+	TotalBytes           int64                  `protobuf:"varint,4,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	Error                string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
 }
 
-func (m *UnexposeRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UnexposeRequest.Unmarshal(m, b)
+func (m *VolumeInitStatus) Reset()         { *m = VolumeInitStatus{} }
+func (m *VolumeInitStatus) String() string { return proto.CompactTextString(m) }
+func (*VolumeInitStatus) ProtoMessage()    {}
+func (*VolumeInitStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{25}
 }
-func (m *UnexposeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UnexposeRequest.Marshal(b, m, deterministic)
+
+func (m *VolumeInitStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VolumeInitStatus.Unmarshal(m, b)
 }
-func (m *UnexposeRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UnexposeRequest.Merge(m, src)
+func (m *VolumeInitStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VolumeInitStatus.Marshal(b, m, deterministic)
 }
-func (m *UnexposeRequest) XXX_Size() int {
-	return xxx_messageInfo_UnexposeRequest.Size(m)
+func (m *VolumeInitStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VolumeInitStatus.Merge(m, src)
 }
-func (m *UnexposeRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_UnexposeRequest.DiscardUnknown(m)
+func (m *VolumeInitStatus) XXX_Size() int {
+	return xxx_messageInfo_VolumeInitStatus.Size(m)
+}
+func (m *VolumeInitStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_VolumeInitStatus.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UnexposeRequest proto.InternalMessageInfo
+var xxx_messageInfo_VolumeInitStatus proto.InternalMessageInfo
 
-func (m *UnexposeRequest) GetOldToken() string {
+func (m *VolumeInitStatus) GetVolume() string {
 	if m != nil {
-		return m.OldToken
+		return m.Volume
 	}
 	return ""
 }
 
-func (m *UnexposeRequest) GetAuth() *auth.BlimpAuth {
+func (m *VolumeInitStatus) GetState() VolumeInitStatus_State {
 	if m != nil {
-		return m.Auth
+		return m.State
 	}
-	return nil
+	return VolumeInitStatus_PENDING
 }
 
-type UnexposeResponse struct {
-	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+func (m *VolumeInitStatus) GetCopiedBytes() int64 {
+	if m != nil {
+		return m.CopiedBytes
+	}
+	return 0
 }
 
-func (m *UnexposeResponse) Reset()         { *m = UnexposeResponse{} }
-func (m *UnexposeResponse) String() string { return proto.CompactTextString(m) }
-func (*UnexposeResponse) ProtoMessage()    {}
-func (*UnexposeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{24}
+func (m *VolumeInitStatus) GetTotalBytes() int64 {
+	if m != nil {
+		return m.TotalBytes
+	}
+	return 0
 }
 
-func (m *UnexposeResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UnexposeResponse.Unmarshal(m, b)
+func (m *VolumeInitStatus) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
 }
-func (m *UnexposeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UnexposeResponse.Marshal(b, m, deterministic)
+
+type MeshStatus struct {
+	// sidecar_ready is whether the mesh's proxy is ready. Traffic between
+	// services whose proxies are ready is encrypted with mutual TLS.
+	SidecarReady         bool     `protobuf:"varint,1,opt,name=sidecar_ready,json=sidecarReady,proto3" json:"sidecar_ready,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *UnexposeResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UnexposeResponse.Merge(m, src)
+
+func (m *MeshStatus) Reset()         { *m = MeshStatus{} }
+func (m *MeshStatus) String() string { return proto.CompactTextString(m) }
+func (*MeshStatus) ProtoMessage()    {}
+func (*MeshStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{26}
 }
-func (m *UnexposeResponse) XXX_Size() int {
-	return xxx_messageInfo_UnexposeResponse.Size(m)
+
+func (m *MeshStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MeshStatus.Unmarshal(m, b)
 }
-func (m *UnexposeResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_UnexposeResponse.DiscardUnknown(m)
+func (m *MeshStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MeshStatus.Marshal(b, m, deterministic)
+}
+func (m *MeshStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MeshStatus.Merge(m, src)
+}
+func (m *MeshStatus) XXX_Size() int {
+	return xxx_messageInfo_MeshStatus.Size(m)
+}
+func (m *MeshStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_MeshStatus.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UnexposeResponse proto.InternalMessageInfo
+var xxx_messageInfo_MeshStatus proto.InternalMessageInfo
 
-func (m *UnexposeResponse) GetError() *errors.Error {
+func (m *MeshStatus) GetSidecarReady() bool {
 	if m != nil {
-		return m.Error
+		return m.SidecarReady
 	}
-	return nil
+	return false
 }
 
-type GetImageNamespaceRequest struct {
+type RestartRequest struct {
 	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,3,opt,name=auth,proto3" json:"auth,omitempty"`
+	Service              string          `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
 }
 
-func (m *GetImageNamespaceRequest) Reset()         { *m = GetImageNamespaceRequest{} }
-func (m *GetImageNamespaceRequest) String() string { return proto.CompactTextString(m) }
-func (*GetImageNamespaceRequest) ProtoMessage()    {}
-func (*GetImageNamespaceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{25}
+func (m *RestartRequest) Reset()         { *m = RestartRequest{} }
+func (m *RestartRequest) String() string { return proto.CompactTextString(m) }
+func (*RestartRequest) ProtoMessage()    {}
+func (*RestartRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{27}
 }
 
-func (m *GetImageNamespaceRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetImageNamespaceRequest.Unmarshal(m, b)
+func (m *RestartRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestartRequest.Unmarshal(m, b)
 }
-func (m *GetImageNamespaceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetImageNamespaceRequest.Marshal(b, m, deterministic)
+func (m *RestartRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestartRequest.Marshal(b, m, deterministic)
 }
-func (m *GetImageNamespaceRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetImageNamespaceRequest.Merge(m, src)
+func (m *RestartRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestartRequest.Merge(m, src)
 }
-func (m *GetImageNamespaceRequest) XXX_Size() int {
-	return xxx_messageInfo_GetImageNamespaceRequest.Size(m)
+func (m *RestartRequest) XXX_Size() int {
+	return xxx_messageInfo_RestartRequest.Size(m)
 }
-func (m *GetImageNamespaceRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetImageNamespaceRequest.DiscardUnknown(m)
+func (m *RestartRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestartRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetImageNamespaceRequest proto.InternalMessageInfo
+var xxx_messageInfo_RestartRequest proto.InternalMessageInfo
 
-func (m *GetImageNamespaceRequest) GetOldToken() string {
+func (m *RestartRequest) GetOldToken() string {
 	if m != nil {
 		return m.OldToken
 	}
 	return ""
 }
 
-func (m *GetImageNamespaceRequest) GetAuth() *auth.BlimpAuth {
+func (m *RestartRequest) GetAuth() *auth.BlimpAuth {
 	if m != nil {
 		return m.Auth
 	}
 	return nil
 }
 
-type GetImageNamespaceResponse struct {
+func (m *RestartRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+type RestartResponse struct {
 	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	Namespace            string        `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
 	XXX_unrecognized     []byte        `json:"-"`
 	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *GetImageNamespaceResponse) Reset()         { *m = GetImageNamespaceResponse{} }
-func (m *GetImageNamespaceResponse) String() string { return proto.CompactTextString(m) }
-func (*GetImageNamespaceResponse) ProtoMessage()    {}
-func (*GetImageNamespaceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{26}
+func (m *RestartResponse) Reset()         { *m = RestartResponse{} }
+func (m *RestartResponse) String() string { return proto.CompactTextString(m) }
+func (*RestartResponse) ProtoMessage()    {}
+func (*RestartResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{28}
 }
 
-func (m *GetImageNamespaceResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetImageNamespaceResponse.Unmarshal(m, b)
+func (m *RestartResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestartResponse.Unmarshal(m, b)
 }
-func (m *GetImageNamespaceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetImageNamespaceResponse.Marshal(b, m, deterministic)
+func (m *RestartResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestartResponse.Marshal(b, m, deterministic)
 }
-func (m *GetImageNamespaceResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetImageNamespaceResponse.Merge(m, src)
+func (m *RestartResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestartResponse.Merge(m, src)
 }
-func (m *GetImageNamespaceResponse) XXX_Size() int {
-	return xxx_messageInfo_GetImageNamespaceResponse.Size(m)
+func (m *RestartResponse) XXX_Size() int {
+	return xxx_messageInfo_RestartResponse.Size(m)
 }
-func (m *GetImageNamespaceResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetImageNamespaceResponse.DiscardUnknown(m)
+func (m *RestartResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestartResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetImageNamespaceResponse proto.InternalMessageInfo
+var xxx_messageInfo_RestartResponse proto.InternalMessageInfo
 
-func (m *GetImageNamespaceResponse) GetError() *errors.Error {
+func (m *RestartResponse) GetError() *errors.Error {
 	if m != nil {
 		return m.Error
 	}
 	return nil
 }
 
-func (m *GetImageNamespaceResponse) GetNamespace() string {
-	if m != nil {
-		return m.Namespace
-	}
-	return ""
-}
-
-type GetBuildkitRequest struct {
-	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
-	Auth                 *auth.BlimpAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+type TagImageRequest struct {
+	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Image                string   `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Tag                  string   `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetBuildkitRequest) Reset()         { *m = GetBuildkitRequest{} }
-func (m *GetBuildkitRequest) String() string { return proto.CompactTextString(m) }
-func (*GetBuildkitRequest) ProtoMessage()    {}
-func (*GetBuildkitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{27}
+func (m *TagImageRequest) Reset()         { *m = TagImageRequest{} }
+func (m *TagImageRequest) String() string { return proto.CompactTextString(m) }
+func (*TagImageRequest) ProtoMessage()    {}
+func (*TagImageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{29}
 }
 
-func (m *GetBuildkitRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetBuildkitRequest.Unmarshal(m, b)
+func (m *TagImageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TagImageRequest.Unmarshal(m, b)
 }
-func (m *GetBuildkitRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetBuildkitRequest.Marshal(b, m, deterministic)
+func (m *TagImageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TagImageRequest.Marshal(b, m, deterministic)
 }
-func (m *GetBuildkitRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetBuildkitRequest.Merge(m, src)
+func (m *TagImageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagImageRequest.Merge(m, src)
 }
-func (m *GetBuildkitRequest) XXX_Size() int {
-	return xxx_messageInfo_GetBuildkitRequest.Size(m)
+func (m *TagImageRequest) XXX_Size() int {
+	return xxx_messageInfo_TagImageRequest.Size(m)
 }
-func (m *GetBuildkitRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetBuildkitRequest.DiscardUnknown(m)
+func (m *TagImageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagImageRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetBuildkitRequest proto.InternalMessageInfo
+var xxx_messageInfo_TagImageRequest proto.InternalMessageInfo
 
-func (m *GetBuildkitRequest) GetOldToken() string {
+func (m *TagImageRequest) GetService() string {
 	if m != nil {
-		return m.OldToken
+		return m.Service
 	}
 	return ""
 }
 
-func (m *GetBuildkitRequest) GetAuth() *auth.BlimpAuth {
-	if m != nil {
-		return m.Auth
-	}
-	return nil
-}
-
-type GetBuildkitResponse struct {
-	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	NodeAddress          string        `protobuf:"bytes,2,opt,name=NodeAddress,proto3" json:"NodeAddress,omitempty"`
-	NodeCert             string        `protobuf:"bytes,3,opt,name=NodeCert,proto3" json:"NodeCert,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
-}
-
-func (m *GetBuildkitResponse) Reset()         { *m = GetBuildkitResponse{} }
-func (m *GetBuildkitResponse) String() string { return proto.CompactTextString(m) }
-func (*GetBuildkitResponse) ProtoMessage()    {}
-func (*GetBuildkitResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{28}
-}
-
-func (m *GetBuildkitResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetBuildkitResponse.Unmarshal(m, b)
-}
-func (m *GetBuildkitResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetBuildkitResponse.Marshal(b, m, deterministic)
-}
-func (m *GetBuildkitResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetBuildkitResponse.Merge(m, src)
-}
-func (m *GetBuildkitResponse) XXX_Size() int {
-	return xxx_messageInfo_GetBuildkitResponse.Size(m)
-}
-func (m *GetBuildkitResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetBuildkitResponse.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_GetBuildkitResponse proto.InternalMessageInfo
-
-func (m *GetBuildkitResponse) GetError() *errors.Error {
-	if m != nil {
-		return m.Error
-	}
-	return nil
-}
-
-func (m *GetBuildkitResponse) GetNodeAddress() string {
+func (m *TagImageRequest) GetImage() string {
 	if m != nil {
-		return m.NodeAddress
+		return m.Image
 	}
 	return ""
 }
 
-func (m *GetBuildkitResponse) GetNodeCert() string {
+func (m *TagImageRequest) GetTag() string {
 	if m != nil {
-		return m.NodeCert
+		return m.Tag
 	}
 	return ""
 }
 
-type BlimpUpPreviewRequest struct {
-	Auth                 *auth.BlimpAuth   `protobuf:"bytes,5,opt,name=auth,proto3" json:"auth,omitempty"`
-	Repo                 string            `protobuf:"bytes,2,opt,name=repo,proto3" json:"repo,omitempty"`
-	ComposeFiles         []string          `protobuf:"bytes,3,rep,name=composeFiles,proto3" json:"composeFiles,omitempty"`
-	Env                  map[string]string `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+type TagImagesRequest struct {
+	OldToken             string                         `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
+	Auth                 *auth.BlimpAuth                `protobuf:"bytes,4,opt,name=auth,proto3" json:"auth,omitempty"`
+	TagRequests          []*TagImageRequest             `protobuf:"bytes,2,rep,name=tagRequests,proto3" json:"tagRequests,omitempty"`
+	RegistryCredentials  map[string]*RegistryCredential `protobuf:"bytes,3,rep,name=registryCredentials,proto3" json:"registryCredentials,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
 }
 
-func (m *BlimpUpPreviewRequest) Reset()         { *m = BlimpUpPreviewRequest{} }
-func (m *BlimpUpPreviewRequest) String() string { return proto.CompactTextString(m) }
-func (*BlimpUpPreviewRequest) ProtoMessage()    {}
-func (*BlimpUpPreviewRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{29}
+func (m *TagImagesRequest) Reset()         { *m = TagImagesRequest{} }
+func (m *TagImagesRequest) String() string { return proto.CompactTextString(m) }
+func (*TagImagesRequest) ProtoMessage()    {}
+func (*TagImagesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{30}
 }
 
-func (m *BlimpUpPreviewRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_BlimpUpPreviewRequest.Unmarshal(m, b)
+func (m *TagImagesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TagImagesRequest.Unmarshal(m, b)
 }
-func (m *BlimpUpPreviewRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_BlimpUpPreviewRequest.Marshal(b, m, deterministic)
+func (m *TagImagesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TagImagesRequest.Marshal(b, m, deterministic)
 }
-func (m *BlimpUpPreviewRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BlimpUpPreviewRequest.Merge(m, src)
+func (m *TagImagesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagImagesRequest.Merge(m, src)
 }
-func (m *BlimpUpPreviewRequest) XXX_Size() int {
-	return xxx_messageInfo_BlimpUpPreviewRequest.Size(m)
+func (m *TagImagesRequest) XXX_Size() int {
+	return xxx_messageInfo_TagImagesRequest.Size(m)
 }
-func (m *BlimpUpPreviewRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_BlimpUpPreviewRequest.DiscardUnknown(m)
+func (m *TagImagesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagImagesRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_BlimpUpPreviewRequest proto.InternalMessageInfo
+var xxx_messageInfo_TagImagesRequest proto.InternalMessageInfo
 
-func (m *BlimpUpPreviewRequest) GetAuth() *auth.BlimpAuth {
+func (m *TagImagesRequest) GetOldToken() string {
 	if m != nil {
-		return m.Auth
+		return m.OldToken
 	}
-	return nil
+	return ""
 }
 
-func (m *BlimpUpPreviewRequest) GetRepo() string {
+func (m *TagImagesRequest) GetAuth() *auth.BlimpAuth {
 	if m != nil {
-		return m.Repo
+		return m.Auth
 	}
-	return ""
+	return nil
 }
 
-func (m *BlimpUpPreviewRequest) GetComposeFiles() []string {
+func (m *TagImagesRequest) GetTagRequests() []*TagImageRequest {
 	if m != nil {
-		return m.ComposeFiles
+		return m.TagRequests
 	}
 	return nil
 }
 
-func (m *BlimpUpPreviewRequest) GetEnv() map[string]string {
+func (m *TagImagesRequest) GetRegistryCredentials() map[string]*RegistryCredential {
 	if m != nil {
-		return m.Env
+		return m.RegistryCredentials
 	}
 	return nil
 }
 
-type BlimpUpPreviewResponse struct {
-	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	StartedCli           bool          `protobuf:"varint,2,opt,name=started_cli,json=startedCli,proto3" json:"started_cli,omitempty"`
-	Output               []byte        `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"`
+type TagImagesResponse struct {
+	Service              string        `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Error                *errors.Error `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
 	XXX_unrecognized     []byte        `json:"-"`
 	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *BlimpUpPreviewResponse) Reset()         { *m = BlimpUpPreviewResponse{} }
-func (m *BlimpUpPreviewResponse) String() string { return proto.CompactTextString(m) }
-func (*BlimpUpPreviewResponse) ProtoMessage()    {}
-func (*BlimpUpPreviewResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d156d5389f4d1cd6, []int{30}
+func (m *TagImagesResponse) Reset()         { *m = TagImagesResponse{} }
+func (m *TagImagesResponse) String() string { return proto.CompactTextString(m) }
+func (*TagImagesResponse) ProtoMessage()    {}
+func (*TagImagesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{31}
 }
 
-func (m *BlimpUpPreviewResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_BlimpUpPreviewResponse.Unmarshal(m, b)
+func (m *TagImagesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TagImagesResponse.Unmarshal(m, b)
 }
-func (m *BlimpUpPreviewResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_BlimpUpPreviewResponse.Marshal(b, m, deterministic)
+func (m *TagImagesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TagImagesResponse.Marshal(b, m, deterministic)
 }
-func (m *BlimpUpPreviewResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BlimpUpPreviewResponse.Merge(m, src)
+func (m *TagImagesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagImagesResponse.Merge(m, src)
 }
-func (m *BlimpUpPreviewResponse) XXX_Size() int {
-	return xxx_messageInfo_BlimpUpPreviewResponse.Size(m)
+func (m *TagImagesResponse) XXX_Size() int {
+	return xxx_messageInfo_TagImagesResponse.Size(m)
 }
-func (m *BlimpUpPreviewResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_BlimpUpPreviewResponse.DiscardUnknown(m)
+func (m *TagImagesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagImagesResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_BlimpUpPreviewResponse proto.InternalMessageInfo
-
-func (m *BlimpUpPreviewResponse) GetError() *errors.Error {
-	if m != nil {
-		return m.Error
-	}
-	return nil
-}
+var xxx_messageInfo_TagImagesResponse proto.InternalMessageInfo
 
-func (m *BlimpUpPreviewResponse) GetStartedCli() bool {
+func (m *TagImagesResponse) GetService() string {
 	if m != nil {
-		return m.StartedCli
+		return m.Service
 	}
-	return false
+	return ""
 }
 
-func (m *BlimpUpPreviewResponse) GetOutput() []byte {
+func (m *TagImagesResponse) GetError() *errors.Error {
 	if m != nil {
-		return m.Output
+		return m.Error
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterEnum("blimp.cluster.v0.CLIAction", CLIAction_name, CLIAction_value)
-	proto.RegisterEnum("blimp.cluster.v0.ServicePhase", ServicePhase_name, ServicePhase_value)
-	proto.RegisterEnum("blimp.cluster.v0.SandboxStatus_SandboxPhase", SandboxStatus_SandboxPhase_name, SandboxStatus_SandboxPhase_value)
-	proto.RegisterType((*CheckVersionRequest)(nil), "blimp.cluster.v0.CheckVersionRequest")
-	proto.RegisterType((*CheckVersionResponse)(nil), "blimp.cluster.v0.CheckVersionResponse")
-	proto.RegisterType((*CreateSandboxRequest)(nil), "blimp.cluster.v0.CreateSandboxRequest")
-	proto.RegisterMapType((map[string]*RegistryCredential)(nil), "blimp.cluster.v0.CreateSandboxRequest.RegistryCredentialsEntry")
-	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.CreateSandboxRequest.SyncedFoldersEntry")
-	proto.RegisterType((*RegistryCredential)(nil), "blimp.cluster.v0.RegistryCredential")
-	proto.RegisterType((*AttachToSandboxRequest)(nil), "blimp.cluster.v0.AttachToSandboxRequest")
-	proto.RegisterType((*AttachToSandboxResponse)(nil), "blimp.cluster.v0.AttachToSandboxResponse")
-	proto.RegisterType((*CreateSandboxResponse)(nil), "blimp.cluster.v0.CreateSandboxResponse")
-	proto.RegisterType((*DeployRequest)(nil), "blimp.cluster.v0.DeployRequest")
-	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.DeployRequest.BuiltImagesEntry")
-	proto.RegisterType((*DeployResponse)(nil), "blimp.cluster.v0.DeployResponse")
-	proto.RegisterType((*KubeCredentials)(nil), "blimp.cluster.v0.KubeCredentials")
-	proto.RegisterType((*DeleteSandboxRequest)(nil), "blimp.cluster.v0.DeleteSandboxRequest")
-	proto.RegisterType((*DeleteSandboxResponse)(nil), "blimp.cluster.v0.DeleteSandboxResponse")
-	proto.RegisterType((*GetStatusRequest)(nil), "blimp.cluster.v0.GetStatusRequest")
-	proto.RegisterType((*GetStatusResponse)(nil), "blimp.cluster.v0.GetStatusResponse")
-	proto.RegisterType((*SandboxStatus)(nil), "blimp.cluster.v0.SandboxStatus")
-	proto.RegisterMapType((map[string]*ServiceStatus)(nil), "blimp.cluster.v0.SandboxStatus.ServicesEntry")
-	proto.RegisterType((*ServiceStatus)(nil), "blimp.cluster.v0.ServiceStatus")
-	proto.RegisterType((*RestartRequest)(nil), "blimp.cluster.v0.RestartRequest")
-	proto.RegisterType((*RestartResponse)(nil), "blimp.cluster.v0.RestartResponse")
-	proto.RegisterType((*TagImageRequest)(nil), "blimp.cluster.v0.TagImageRequest")
-	proto.RegisterType((*TagImagesRequest)(nil), "blimp.cluster.v0.TagImagesRequest")
-	proto.RegisterMapType((map[string]*RegistryCredential)(nil), "blimp.cluster.v0.TagImagesRequest.RegistryCredentialsEntry")
-	proto.RegisterType((*TagImagesResponse)(nil), "blimp.cluster.v0.TagImagesResponse")
-	proto.RegisterType((*ExposeRequest)(nil), "blimp.cluster.v0.ExposeRequest")
-	proto.RegisterType((*ExposeResponse)(nil), "blimp.cluster.v0.ExposeResponse")
-	proto.RegisterType((*UnexposeRequest)(nil), "blimp.cluster.v0.UnexposeRequest")
-	proto.RegisterType((*UnexposeResponse)(nil), "blimp.cluster.v0.UnexposeResponse")
-	proto.RegisterType((*GetImageNamespaceRequest)(nil), "blimp.cluster.v0.GetImageNamespaceRequest")
-	proto.RegisterType((*GetImageNamespaceResponse)(nil), "blimp.cluster.v0.GetImageNamespaceResponse")
-	proto.RegisterType((*GetBuildkitRequest)(nil), "blimp.cluster.v0.GetBuildkitRequest")
-	proto.RegisterType((*GetBuildkitResponse)(nil), "blimp.cluster.v0.GetBuildkitResponse")
-	proto.RegisterType((*BlimpUpPreviewRequest)(nil), "blimp.cluster.v0.BlimpUpPreviewRequest")
-	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.BlimpUpPreviewRequest.EnvEntry")
-	proto.RegisterType((*BlimpUpPreviewResponse)(nil), "blimp.cluster.v0.BlimpUpPreviewResponse")
+type ExposeRequest struct {
+	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,4,opt,name=auth,proto3" json:"auth,omitempty"`
+	Service              string          `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	Port                 uint32          `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func init() {
-	proto.RegisterFile("blimp/cluster/v0/manager.proto", fileDescriptor_d156d5389f4d1cd6)
+func (m *ExposeRequest) Reset()         { *m = ExposeRequest{} }
+func (m *ExposeRequest) String() string { return proto.CompactTextString(m) }
+func (*ExposeRequest) ProtoMessage()    {}
+func (*ExposeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{32}
 }
 
-var fileDescriptor_d156d5389f4d1cd6 = []byte{
-	// 1724 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x19, 0x5d, 0x73, 0xda, 0xc6,
-	0xd6, 0x02, 0x8c, 0xe1, 0x60, 0x40, 0x59, 0x3b, 0xbe, 0x5c, 0x25, 0x37, 0x76, 0x74, 0x6f, 0x12,
-	0x5f, 0xdf, 0x5c, 0xec, 0x71, 0xee, 0x67, 0x3a, 0x93, 0x04, 0x83, 0xe2, 0x50, 0xdb, 0xb2, 0x47,
-	0x80, 0xf3, 0x51, 0x77, 0x18, 0x01, 0x3b, 0xa0, 0x41, 0x20, 0xa2, 0x15, 0x24, 0xee, 0x4b, 0xa7,
-	0x4f, 0xcd, 0x63, 0x7f, 0x45, 0x5f, 0xfb, 0x07, 0xfa, 0xde, 0xf7, 0x3e, 0xf6, 0xcf, 0xa4, 0xb3,
-	0x5a, 0x49, 0x96, 0x40, 0xb6, 0x31, 0x13, 0x67, 0xa6, 0x4f, 0xec, 0x9e, 0x3d, 0xdf, 0x7b, 0xce,
-	0xd9, 0x73, 0x04, 0xdc, 0x69, 0xe8, 0x5a, 0x6f, 0xb0, 0xd9, 0xd4, 0x87, 0xc4, 0xc2, 0xe6, 0xe6,
-	0x68, 0x6b, 0xb3, 0xa7, 0xf6, 0xd5, 0x36, 0x36, 0xf3, 0x03, 0xd3, 0xb0, 0x0c, 0xc4, 0xdb, 0xe7,
-	0x79, 0xe7, 0x3c, 0x3f, 0xda, 0x12, 0x72, 0x8c, 0x42, 0x1d, 0x5a, 0x1d, 0x8a, 0x4e, 0x7f, 0x19,
-	0xae, 0x70, 0x9b, 0x9d, 0x60, 0xd3, 0x34, 0x4c, 0x42, 0xcf, 0xd8, 0x8a, 0x9d, 0x8a, 0x9b, 0xb0,
-	0x54, 0xec, 0xe0, 0x66, 0xf7, 0x18, 0x9b, 0x44, 0x33, 0xfa, 0x0a, 0x7e, 0x3b, 0xc4, 0xc4, 0x42,
-	0x39, 0x58, 0x18, 0x31, 0x48, 0x8e, 0x5b, 0xe3, 0xd6, 0x93, 0x8a, 0xbb, 0x15, 0x7f, 0xe6, 0x60,
-	0x39, 0x48, 0x41, 0x06, 0x46, 0x9f, 0xe0, 0xf3, 0x49, 0xd0, 0x03, 0xc8, 0xb6, 0x34, 0x32, 0xd0,
-	0xd5, 0xd3, 0x7a, 0x0f, 0x13, 0xa2, 0xb6, 0x71, 0x2e, 0x62, 0x63, 0x64, 0x1c, 0xf0, 0x01, 0x83,
-	0xa2, 0x47, 0x10, 0x57, 0x9b, 0x16, 0xe5, 0x10, 0x5d, 0xe3, 0xd6, 0x33, 0xdb, 0xb7, 0xf2, 0xe3,
-	0x76, 0xe6, 0x8b, 0xfb, 0xe5, 0x82, 0x8d, 0xa2, 0x38, 0xa8, 0xe8, 0x21, 0xcc, 0xdb, 0x16, 0xe5,
-	0x62, 0x6b, 0xdc, 0x7a, 0x6a, 0x7b, 0xc5, 0xa1, 0x71, 0xac, 0x1c, 0x6d, 0xe5, 0x25, 0xba, 0x52,
-	0x18, 0x92, 0xf8, 0x7d, 0x0c, 0x96, 0x8b, 0x26, 0x56, 0x2d, 0x5c, 0x51, 0xfb, 0xad, 0x86, 0xf1,
-	0xde, 0xb5, 0xf8, 0x16, 0x24, 0x0d, 0xbd, 0x55, 0xb7, 0x8c, 0x2e, 0x76, 0x0d, 0x48, 0x18, 0x7a,
-	0xab, 0x4a, 0xf7, 0xe8, 0x21, 0xc4, 0xa8, 0x47, 0x73, 0xf3, 0xb6, 0x88, 0x9c, 0x23, 0xc2, 0x76,
-	0xf2, 0x68, 0x2b, 0xbf, 0x43, 0x77, 0x85, 0xa1, 0xd5, 0x51, 0x6c, 0x2c, 0xb4, 0x06, 0xa9, 0xa6,
-	0xd1, 0x1b, 0x18, 0x04, 0x3f, 0xd7, 0x74, 0xd7, 0x56, 0x3f, 0x08, 0xbd, 0x85, 0x25, 0x13, 0xb7,
-	0x35, 0x62, 0x99, 0xa7, 0x45, 0x13, 0xb7, 0x70, 0xdf, 0xd2, 0x54, 0x9d, 0xe4, 0xa2, 0x6b, 0xd1,
-	0xf5, 0xd4, 0xf6, 0xd3, 0x10, 0xab, 0x43, 0x34, 0xce, 0x2b, 0x93, 0x1c, 0xa4, 0xbe, 0x65, 0x9e,
-	0x2a, 0x61, 0xbc, 0x51, 0x1d, 0xd2, 0xe4, 0xb4, 0xdf, 0xc4, 0xad, 0xe7, 0x86, 0xde, 0xc2, 0x26,
-	0xc9, 0xc5, 0x6c, 0x61, 0xff, 0x9f, 0x52, 0x58, 0xc5, 0x4f, 0xcb, 0xc4, 0x04, 0xf9, 0x09, 0x3a,
-	0xe4, 0xce, 0xd3, 0x08, 0xf1, 0x10, 0xed, 0xe2, 0x53, 0xc7, 0xad, 0x74, 0x89, 0x1e, 0xc3, 0xfc,
-	0x48, 0xd5, 0x87, 0xcc, 0x3b, 0xa9, 0xed, 0xbf, 0x4d, 0xaa, 0x31, 0xc9, 0x4c, 0x61, 0x24, 0x8f,
-	0x23, 0xff, 0xe3, 0x84, 0x67, 0x80, 0x26, 0x55, 0x0a, 0x91, 0xb3, 0xec, 0x97, 0x93, 0xf4, 0x71,
-	0x10, 0xf7, 0x01, 0x4d, 0x8a, 0x40, 0x02, 0x24, 0x86, 0x04, 0x9b, 0x7d, 0xb5, 0x87, 0xdd, 0x28,
-	0x70, 0xf7, 0xf4, 0x6c, 0xa0, 0x12, 0xf2, 0xce, 0x30, 0x5b, 0x0e, 0x3b, 0x6f, 0x2f, 0x36, 0x61,
-	0xa5, 0x60, 0x59, 0x6a, 0xb3, 0x53, 0x35, 0x66, 0x09, 0xac, 0xc8, 0x34, 0x81, 0x25, 0xfe, 0xca,
-	0xc1, 0x9f, 0x26, 0xa4, 0x38, 0xe9, 0xe7, 0xa5, 0x01, 0x37, 0x45, 0x1a, 0xd0, 0x10, 0x95, 0x8d,
-	0x16, 0x2e, 0xb4, 0x5a, 0x26, 0x26, 0xc4, 0x0d, 0x51, 0x1f, 0x88, 0x1a, 0x4b, 0xb7, 0x45, 0x6c,
-	0x5a, 0x76, 0x36, 0x26, 0x15, 0x6f, 0x8f, 0xf6, 0x20, 0xdb, 0x1d, 0x36, 0xb0, 0x3f, 0x74, 0x59,
-	0xf2, 0xdd, 0x9d, 0xbc, 0xc6, 0xbd, 0x20, 0xa2, 0x32, 0x4e, 0x29, 0xfe, 0x12, 0x81, 0x9b, 0x63,
-	0x21, 0xf7, 0x07, 0x37, 0x09, 0xdd, 0x87, 0x4c, 0xb9, 0xa7, 0xb6, 0xb1, 0xac, 0xf6, 0x30, 0x19,
-	0xa8, 0x4d, 0x6c, 0x17, 0x8e, 0xa4, 0x32, 0x06, 0xa5, 0x25, 0xd3, 0x2d, 0x88, 0x71, 0x56, 0x32,
-	0x7b, 0x13, 0x95, 0x70, 0x61, 0xea, 0x4a, 0x28, 0xfe, 0x10, 0x81, 0x74, 0x09, 0x0f, 0x74, 0xe3,
-	0xf4, 0x4a, 0xb1, 0x17, 0xfb, 0x44, 0x45, 0x4d, 0x81, 0x54, 0x63, 0xa8, 0xe9, 0x96, 0x6d, 0xa4,
-	0x5b, 0xcc, 0xb6, 0x26, 0x15, 0x0f, 0xa8, 0x98, 0xdf, 0x39, 0x23, 0x61, 0x65, 0xc5, 0xcf, 0x44,
-	0x78, 0x02, 0xfc, 0x38, 0xc2, 0x95, 0x92, 0xfc, 0x09, 0x64, 0x5c, 0x71, 0xb3, 0x04, 0x95, 0x68,
-	0x40, 0x76, 0xec, 0xb6, 0x11, 0x82, 0x58, 0xc7, 0x20, 0x96, 0x23, 0xdf, 0x5e, 0x53, 0x05, 0x9a,
-	0x6a, 0xd1, 0xb4, 0x5c, 0x05, 0xec, 0x0d, 0x85, 0x32, 0xcf, 0xb3, 0x60, 0x63, 0x1b, 0x74, 0x1b,
-	0x92, 0x7d, 0x2f, 0x2e, 0x62, 0xf6, 0xc9, 0x19, 0x40, 0xfc, 0xc0, 0xc1, 0x72, 0x09, 0xeb, 0x78,
-	0xb6, 0xf7, 0x29, 0x3a, 0xd5, 0x55, 0xde, 0x83, 0x4c, 0xcb, 0x16, 0x51, 0x1f, 0x19, 0xfa, 0xb0,
-	0x87, 0x59, 0xb2, 0x24, 0x94, 0x34, 0x83, 0x1e, 0x33, 0xa0, 0x28, 0xc1, 0xcd, 0x31, 0x4d, 0x66,
-	0x72, 0xe1, 0xd7, 0xc0, 0xef, 0x62, 0xab, 0x62, 0xa9, 0xd6, 0x90, 0x5c, 0x43, 0x4d, 0xfc, 0x06,
-	0x6e, 0xf8, 0xd8, 0xcf, 0x54, 0x39, 0xfe, 0x0b, 0x71, 0x62, 0xd3, 0x3b, 0x22, 0x57, 0x27, 0x63,
-	0xd6, 0x71, 0x81, 0x23, 0xc6, 0x41, 0x17, 0x7f, 0x8b, 0x40, 0x3a, 0x70, 0x82, 0xca, 0x90, 0x20,
-	0xd8, 0x1c, 0x69, 0x4d, 0x4c, 0x72, 0x9c, 0x9d, 0x00, 0xff, 0xbc, 0x84, 0x59, 0xbe, 0xe2, 0xe0,
-	0xb3, 0xe8, 0xf7, 0xc8, 0xd1, 0x0e, 0xcc, 0x0f, 0x3a, 0x2a, 0x61, 0x41, 0x9d, 0xd9, 0x7e, 0x78,
-	0x29, 0x1f, 0xb6, 0x3b, 0xa2, 0x34, 0x0a, 0x23, 0x15, 0x4e, 0x20, 0x1d, 0x60, 0x1f, 0x92, 0x3b,
-	0xff, 0x0e, 0x3e, 0xc4, 0x61, 0xb6, 0x33, 0x0e, 0x8e, 0xed, 0xbe, 0xe4, 0x3a, 0x81, 0x45, 0xbf,
-	0x50, 0x94, 0x82, 0x85, 0x9a, 0xbc, 0x27, 0x1f, 0xbe, 0x94, 0xf9, 0x39, 0xba, 0x51, 0x6a, 0xb2,
-	0x5c, 0x96, 0x77, 0x79, 0x0e, 0x65, 0x21, 0x55, 0x95, 0x94, 0x83, 0xb2, 0x5c, 0xa8, 0x52, 0x40,
-	0x04, 0x21, 0xc8, 0x94, 0x0e, 0xa5, 0x4a, 0x5d, 0x3e, 0xac, 0xd6, 0xa5, 0x57, 0xe5, 0x4a, 0x95,
-	0x8f, 0xa2, 0x34, 0x24, 0x8f, 0x14, 0xe9, 0xa8, 0xa0, 0x50, 0x94, 0x98, 0xf8, 0xde, 0xd3, 0xdd,
-	0xf1, 0xed, 0xbf, 0x5c, 0x87, 0x70, 0xb6, 0x43, 0xee, 0x9c, 0xab, 0xa9, 0xdf, 0x05, 0xd4, 0xe2,
-	0x1e, 0x69, 0x3b, 0x89, 0x49, 0x97, 0x68, 0x15, 0x52, 0x1d, 0x95, 0xd4, 0x89, 0xa5, 0x9a, 0x16,
-	0x6e, 0xd9, 0x39, 0x93, 0x50, 0xa0, 0xa3, 0x92, 0x0a, 0x83, 0x88, 0x43, 0xc8, 0x28, 0xd8, 0x3e,
-	0xbe, 0x86, 0xe4, 0xcb, 0xc1, 0x82, 0x73, 0xc5, 0x8e, 0x4e, 0xee, 0x56, 0x7c, 0x0a, 0x59, 0x4f,
-	0xec, 0x4c, 0x99, 0x56, 0x81, 0x6c, 0x55, 0x6d, 0xdb, 0xa5, 0xd2, 0xd7, 0xc7, 0xbb, 0xd2, 0xb8,
-	0x80, 0x34, 0x5a, 0x9c, 0xb4, 0xde, 0x59, 0x2b, 0xce, 0x36, 0xd4, 0x5b, 0x96, 0xda, 0x76, 0x0a,
-	0x16, 0x5d, 0x8a, 0x1f, 0x23, 0xc0, 0xbb, 0x5c, 0xc9, 0x35, 0xbc, 0x2b, 0x45, 0x48, 0x59, 0x6a,
-	0xdb, 0x61, 0x4c, 0x33, 0x30, 0x1a, 0xfe, 0xe8, 0x8e, 0x59, 0xa6, 0xf8, 0xa9, 0x50, 0xef, 0xa2,
-	0x7e, 0xfa, 0x8b, 0xf3, 0x99, 0x91, 0x99, 0x7a, 0xe9, 0xcf, 0xdb, 0xea, 0x8a, 0x5f, 0xc1, 0x0d,
-	0x9f, 0xbe, 0x67, 0xd3, 0xd6, 0x39, 0x17, 0xeb, 0xc5, 0x4c, 0x64, 0x9a, 0x98, 0xf9, 0xc0, 0x41,
-	0x5a, 0x7a, 0x4f, 0xdf, 0xf0, 0x6b, 0xb8, 0xdb, 0x73, 0x63, 0x9d, 0x3e, 0xa2, 0x03, 0xc3, 0x69,
-	0xc3, 0xd2, 0x8a, 0xbd, 0x16, 0x15, 0xc8, 0xb8, 0x9a, 0xcc, 0x54, 0xc6, 0x11, 0xc4, 0x74, 0xad,
-	0xdf, 0x75, 0x44, 0xd9, 0x6b, 0xf1, 0x04, 0xb2, 0xb5, 0x3e, 0xbe, 0xba, 0x7d, 0xd3, 0xbd, 0x3d,
-	0xcf, 0x80, 0x3f, 0xe3, 0x3e, 0x53, 0xca, 0x62, 0xc8, 0xed, 0x62, 0x2b, 0xd8, 0x16, 0x5e, 0x83,
-	0xa2, 0x6d, 0xf8, 0x73, 0x88, 0x98, 0x99, 0xbc, 0x1c, 0x68, 0x5f, 0x22, 0xe3, 0xed, 0x4b, 0x1d,
-	0xd0, 0x2e, 0xb6, 0x68, 0xcb, 0xd6, 0xea, 0x6a, 0xd6, 0x35, 0x58, 0xf2, 0x1d, 0x07, 0x4b, 0x01,
-	0x09, 0x9f, 0x7f, 0x56, 0x10, 0x3f, 0x72, 0x70, 0xd3, 0xd6, 0xab, 0x36, 0x38, 0x32, 0xf1, 0x48,
-	0xc3, 0xef, 0x5c, 0x43, 0xaf, 0xf6, 0x9d, 0x00, 0x41, 0xcc, 0xc4, 0x03, 0xc3, 0x0d, 0x58, 0xba,
-	0x46, 0x22, 0x2c, 0xfa, 0x7a, 0x6a, 0x56, 0xc2, 0x92, 0x4a, 0x00, 0x86, 0x76, 0x20, 0x8a, 0xfb,
-	0x23, 0x67, 0x80, 0x0f, 0x69, 0xb0, 0x43, 0x75, 0xcb, 0x4b, 0xfd, 0x11, 0x2b, 0x69, 0x94, 0x58,
-	0xf8, 0x0f, 0x24, 0x5c, 0xc0, 0x55, 0x1a, 0xea, 0x2f, 0x63, 0x09, 0x8e, 0x8f, 0x88, 0xdf, 0xc2,
-	0xca, 0xb8, 0x90, 0x99, 0xee, 0x61, 0x15, 0x52, 0xce, 0x33, 0x5c, 0x6f, 0xea, 0x9a, 0xd3, 0x86,
-	0x82, 0x03, 0x2a, 0xea, 0x1a, 0x5a, 0x81, 0xb8, 0x31, 0xb4, 0x06, 0x43, 0x76, 0x09, 0x8b, 0x8a,
-	0xb3, 0xdb, 0xf8, 0x0b, 0x24, 0xbd, 0xf9, 0x07, 0xc5, 0x21, 0x72, 0xb8, 0xc7, 0xcf, 0xa1, 0x04,
-	0xc4, 0xa4, 0x57, 0xe5, 0x2a, 0xcf, 0x6d, 0xfc, 0xc8, 0xc1, 0xa2, 0xbf, 0x19, 0x08, 0xb6, 0x26,
-	0x39, 0x58, 0x2e, 0xcb, 0xe5, 0x6a, 0xb9, 0xb0, 0x5f, 0x7e, 0x53, 0x96, 0x77, 0xeb, 0xc7, 0x87,
-	0xfb, 0xb5, 0x03, 0xa9, 0xc2, 0x73, 0x68, 0x09, 0xb2, 0x2f, 0x0b, 0xe5, 0x6a, 0xbd, 0x24, 0x1d,
-	0x49, 0x72, 0xa9, 0x52, 0x3f, 0x94, 0x59, 0xaf, 0x62, 0x03, 0x2b, 0xaf, 0xe5, 0x62, 0x7d, 0xa7,
-	0x2c, 0x97, 0xf8, 0x28, 0xe5, 0x47, 0x31, 0xec, 0x4e, 0xc5, 0xdf, 0xea, 0xcc, 0x23, 0x80, 0x38,
-	0x55, 0x42, 0x2a, 0xf1, 0x71, 0xda, 0xd1, 0xd4, 0xe4, 0x17, 0x52, 0x61, 0xbf, 0xfa, 0xe2, 0x35,
-	0xbf, 0x80, 0x6e, 0x40, 0xba, 0x26, 0x57, 0x8a, 0x2f, 0xa4, 0x52, 0x6d, 0xbf, 0xb0, 0xb3, 0x2f,
-	0xf1, 0x89, 0xed, 0x9f, 0x00, 0x16, 0x0e, 0xd8, 0xa7, 0x3d, 0xd4, 0x81, 0xec, 0xd8, 0x70, 0x8f,
-	0xd6, 0x27, 0x2f, 0x37, 0xfc, 0x2b, 0x83, 0xf0, 0xf7, 0x29, 0x30, 0xd9, 0x15, 0x89, 0x73, 0xa8,
-	0x0d, 0x99, 0xe0, 0xf5, 0xa1, 0x07, 0x53, 0x46, 0x91, 0xb0, 0x7e, 0x39, 0xa2, 0x2b, 0x66, 0x8b,
-	0x43, 0x0d, 0x48, 0x07, 0x46, 0x7b, 0x74, 0x7f, 0xba, 0xcf, 0x4d, 0xc2, 0x83, 0x4b, 0xf1, 0x3c,
-	0x63, 0x8e, 0x21, 0xcb, 0x46, 0xbc, 0x33, 0xb7, 0xad, 0x5e, 0x32, 0x74, 0x0a, 0x6b, 0xe7, 0x23,
-	0x78, 0x7c, 0x1b, 0x74, 0x98, 0xf6, 0x8d, 0x3f, 0x61, 0xba, 0x87, 0x4d, 0x6a, 0x61, 0xba, 0x87,
-	0xce, 0x51, 0xe2, 0x1c, 0x3a, 0x81, 0x94, 0xaf, 0x98, 0xa1, 0x90, 0xd6, 0x60, 0xb2, 0x9a, 0x0a,
-	0xf7, 0x2e, 0xc1, 0xf2, 0x79, 0x26, 0xe9, 0x8d, 0x46, 0x48, 0x0c, 0xa5, 0x0a, 0x8c, 0x65, 0xc2,
-	0x5f, 0x2f, 0xc4, 0xf1, 0xf8, 0xf6, 0xed, 0x91, 0x6b, 0xec, 0x5b, 0xc6, 0x46, 0x28, 0x6d, 0xe8,
-	0xcb, 0x26, 0xfc, 0x63, 0x2a, 0x5c, 0x4f, 0xde, 0x1b, 0x48, 0xbd, 0x54, 0xad, 0x66, 0xe7, 0x93,
-	0x5b, 0xb2, 0xc5, 0xa1, 0x3a, 0x2c, 0xfa, 0xbf, 0x66, 0xa3, 0x10, 0xe7, 0x86, 0x7c, 0x1f, 0x17,
-	0xee, 0x5f, 0x86, 0xe6, 0x29, 0x7f, 0x04, 0x0b, 0x4e, 0x57, 0x8f, 0xd6, 0xc2, 0x3a, 0x3f, 0xff,
-	0x9c, 0x21, 0xdc, 0xbd, 0x00, 0xc3, 0xe3, 0xf8, 0x0a, 0x92, 0x5e, 0x3f, 0x18, 0xe6, 0x8c, 0xf1,
-	0xe6, 0x36, 0xcc, 0x19, 0x13, 0x0d, 0xa5, 0xed, 0x8c, 0x03, 0x88, 0xb3, 0x0e, 0x2c, 0x2c, 0x83,
-	0x02, 0x5d, 0x62, 0x58, 0x06, 0x05, 0x9b, 0x37, 0x71, 0x0e, 0x55, 0x20, 0xe1, 0xb6, 0x47, 0x28,
-	0xc4, 0xb2, 0xb1, 0xc6, 0x4c, 0x10, 0x2f, 0x42, 0x71, 0x99, 0xee, 0x6c, 0xbc, 0x59, 0x6f, 0x6b,
-	0x56, 0x67, 0xd8, 0xc8, 0x37, 0x8d, 0xde, 0x66, 0x17, 0xeb, 0x2d, 0x75, 0x93, 0xfd, 0xc3, 0x31,
-	0xe8, 0xb6, 0x37, 0xed, 0x3f, 0x35, 0xdc, 0xff, 0x4d, 0x1a, 0x71, 0x7b, 0xfb, 0xe8, 0xf7, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0xf6, 0x90, 0x1d, 0xe1, 0x4f, 0x19, 0x00, 0x00,
+func (m *ExposeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExposeRequest.Unmarshal(m, b)
 }
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
-
-// ManagerClient is the client API for Manager service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ManagerClient interface {
-	AttachToSandbox(ctx context.Context, in *AttachToSandboxRequest, opts ...grpc.CallOption) (*AttachToSandboxResponse, error)
-	BlimpUpPreview(ctx context.Context, in *BlimpUpPreviewRequest, opts ...grpc.CallOption) (Manager_BlimpUpPreviewClient, error)
-	CreateSandbox(ctx context.Context, in *CreateSandboxRequest, opts ...grpc.CallOption) (*CreateSandboxResponse, error)
-	DeployToSandbox(ctx context.Context, in *DeployRequest, opts ...grpc.CallOption) (*DeployResponse, error)
-	DeleteSandbox(ctx context.Context, in *DeleteSandboxRequest, opts ...grpc.CallOption) (*DeleteSandboxResponse, error)
-	GetBuildkit(ctx context.Context, in *GetBuildkitRequest, opts ...grpc.CallOption) (*GetBuildkitResponse, error)
-	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
-	GetImageNamespace(ctx context.Context, in *GetImageNamespaceRequest, opts ...grpc.CallOption) (*GetImageNamespaceResponse, error)
-	WatchStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (Manager_WatchStatusClient, error)
-	CheckVersion(ctx context.Context, in *CheckVersionRequest, opts ...grpc.CallOption) (*CheckVersionResponse, error)
-	Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error)
-	TagImages(ctx context.Context, in *TagImagesRequest, opts ...grpc.CallOption) (Manager_TagImagesClient, error)
-	Expose(ctx context.Context, in *ExposeRequest, opts ...grpc.CallOption) (*ExposeResponse, error)
-	Unexpose(ctx context.Context, in *UnexposeRequest, opts ...grpc.CallOption) (*UnexposeResponse, error)
+func (m *ExposeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExposeRequest.Marshal(b, m, deterministic)
 }
-
-type managerClient struct {
-	cc grpc.ClientConnInterface
+func (m *ExposeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExposeRequest.Merge(m, src)
 }
-
-func NewManagerClient(cc grpc.ClientConnInterface) ManagerClient {
-	return &managerClient{cc}
+func (m *ExposeRequest) XXX_Size() int {
+	return xxx_messageInfo_ExposeRequest.Size(m)
+}
+func (m *ExposeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExposeRequest.DiscardUnknown(m)
 }
 
-func (c *managerClient) AttachToSandbox(ctx context.Context, in *AttachToSandboxRequest, opts ...grpc.CallOption) (*AttachToSandboxResponse, error) {
-	out := new(AttachToSandboxResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/AttachToSandbox", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ExposeRequest proto.InternalMessageInfo
+
+func (m *ExposeRequest) GetOldToken() string {
+	if m != nil {
+		return m.OldToken
 	}
-	return out, nil
+	return ""
 }
 
-func (c *managerClient) BlimpUpPreview(ctx context.Context, in *BlimpUpPreviewRequest, opts ...grpc.CallOption) (Manager_BlimpUpPreviewClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Manager_serviceDesc.Streams[0], "/blimp.cluster.v0.Manager/BlimpUpPreview", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &managerBlimpUpPreviewClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+func (m *ExposeRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	return nil
+}
+
+func (m *ExposeRequest) GetService() string {
+	if m != nil {
+		return m.Service
 	}
-	return x, nil
+	return ""
 }
 
-type Manager_BlimpUpPreviewClient interface {
-	Recv() (*BlimpUpPreviewResponse, error)
-	grpc.ClientStream
+func (m *ExposeRequest) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
 }
 
-type managerBlimpUpPreviewClient struct {
-	grpc.ClientStream
+type ExposeResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Link                 string        `protobuf:"bytes,2,opt,name=link,proto3" json:"link,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
-func (x *managerBlimpUpPreviewClient) Recv() (*BlimpUpPreviewResponse, error) {
-	m := new(BlimpUpPreviewResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (m *ExposeResponse) Reset()         { *m = ExposeResponse{} }
+func (m *ExposeResponse) String() string { return proto.CompactTextString(m) }
+func (*ExposeResponse) ProtoMessage()    {}
+func (*ExposeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{33}
 }
 
-func (c *managerClient) CreateSandbox(ctx context.Context, in *CreateSandboxRequest, opts ...grpc.CallOption) (*CreateSandboxResponse, error) {
-	out := new(CreateSandboxResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/CreateSandbox", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *ExposeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExposeResponse.Unmarshal(m, b)
+}
+func (m *ExposeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExposeResponse.Marshal(b, m, deterministic)
+}
+func (m *ExposeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExposeResponse.Merge(m, src)
+}
+func (m *ExposeResponse) XXX_Size() int {
+	return xxx_messageInfo_ExposeResponse.Size(m)
+}
+func (m *ExposeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExposeResponse.DiscardUnknown(m)
 }
 
-func (c *managerClient) DeployToSandbox(ctx context.Context, in *DeployRequest, opts ...grpc.CallOption) (*DeployResponse, error) {
-	out := new(DeployResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/DeployToSandbox", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ExposeResponse proto.InternalMessageInfo
+
+func (m *ExposeResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
 	}
-	return out, nil
+	return nil
 }
 
-func (c *managerClient) DeleteSandbox(ctx context.Context, in *DeleteSandboxRequest, opts ...grpc.CallOption) (*DeleteSandboxResponse, error) {
-	out := new(DeleteSandboxResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/DeleteSandbox", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ExposeResponse) GetLink() string {
+	if m != nil {
+		return m.Link
 	}
-	return out, nil
+	return ""
+}
+
+type UnexposeRequest struct {
+	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *UnexposeRequest) Reset()         { *m = UnexposeRequest{} }
+func (m *UnexposeRequest) String() string { return proto.CompactTextString(m) }
+func (*UnexposeRequest) ProtoMessage()    {}
+func (*UnexposeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{34}
+}
+
+func (m *UnexposeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnexposeRequest.Unmarshal(m, b)
+}
+func (m *UnexposeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnexposeRequest.Marshal(b, m, deterministic)
+}
+func (m *UnexposeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnexposeRequest.Merge(m, src)
+}
+func (m *UnexposeRequest) XXX_Size() int {
+	return xxx_messageInfo_UnexposeRequest.Size(m)
+}
+func (m *UnexposeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnexposeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnexposeRequest proto.InternalMessageInfo
+
+func (m *UnexposeRequest) GetOldToken() string {
+	if m != nil {
+		return m.OldToken
+	}
+	return ""
+}
+
+func (m *UnexposeRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type UnexposeResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *UnexposeResponse) Reset()         { *m = UnexposeResponse{} }
+func (m *UnexposeResponse) String() string { return proto.CompactTextString(m) }
+func (*UnexposeResponse) ProtoMessage()    {}
+func (*UnexposeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{35}
+}
+
+func (m *UnexposeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnexposeResponse.Unmarshal(m, b)
+}
+func (m *UnexposeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnexposeResponse.Marshal(b, m, deterministic)
+}
+func (m *UnexposeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnexposeResponse.Merge(m, src)
+}
+func (m *UnexposeResponse) XXX_Size() int {
+	return xxx_messageInfo_UnexposeResponse.Size(m)
+}
+func (m *UnexposeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnexposeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnexposeResponse proto.InternalMessageInfo
+
+func (m *UnexposeResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type GetImageNamespaceRequest struct {
+	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetImageNamespaceRequest) Reset()         { *m = GetImageNamespaceRequest{} }
+func (m *GetImageNamespaceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetImageNamespaceRequest) ProtoMessage()    {}
+func (*GetImageNamespaceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{36}
+}
+
+func (m *GetImageNamespaceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetImageNamespaceRequest.Unmarshal(m, b)
+}
+func (m *GetImageNamespaceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetImageNamespaceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetImageNamespaceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetImageNamespaceRequest.Merge(m, src)
+}
+func (m *GetImageNamespaceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetImageNamespaceRequest.Size(m)
+}
+func (m *GetImageNamespaceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetImageNamespaceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetImageNamespaceRequest proto.InternalMessageInfo
+
+func (m *GetImageNamespaceRequest) GetOldToken() string {
+	if m != nil {
+		return m.OldToken
+	}
+	return ""
+}
+
+func (m *GetImageNamespaceRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type GetImageNamespaceResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Namespace            string        `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetImageNamespaceResponse) Reset()         { *m = GetImageNamespaceResponse{} }
+func (m *GetImageNamespaceResponse) String() string { return proto.CompactTextString(m) }
+func (*GetImageNamespaceResponse) ProtoMessage()    {}
+func (*GetImageNamespaceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{37}
+}
+
+func (m *GetImageNamespaceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetImageNamespaceResponse.Unmarshal(m, b)
+}
+func (m *GetImageNamespaceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetImageNamespaceResponse.Marshal(b, m, deterministic)
+}
+func (m *GetImageNamespaceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetImageNamespaceResponse.Merge(m, src)
+}
+func (m *GetImageNamespaceResponse) XXX_Size() int {
+	return xxx_messageInfo_GetImageNamespaceResponse.Size(m)
+}
+func (m *GetImageNamespaceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetImageNamespaceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetImageNamespaceResponse proto.InternalMessageInfo
+
+func (m *GetImageNamespaceResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetImageNamespaceResponse) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type GetBuildkitRequest struct {
+	OldToken             string          `protobuf:"bytes,1,opt,name=old_token,json=oldToken,proto3" json:"old_token,omitempty"`
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetBuildkitRequest) Reset()         { *m = GetBuildkitRequest{} }
+func (m *GetBuildkitRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBuildkitRequest) ProtoMessage()    {}
+func (*GetBuildkitRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{38}
+}
+
+func (m *GetBuildkitRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBuildkitRequest.Unmarshal(m, b)
+}
+func (m *GetBuildkitRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBuildkitRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBuildkitRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBuildkitRequest.Merge(m, src)
+}
+func (m *GetBuildkitRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBuildkitRequest.Size(m)
+}
+func (m *GetBuildkitRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBuildkitRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBuildkitRequest proto.InternalMessageInfo
+
+func (m *GetBuildkitRequest) GetOldToken() string {
+	if m != nil {
+		return m.OldToken
+	}
+	return ""
+}
+
+func (m *GetBuildkitRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type GetBuildkitResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	NodeAddress          string        `protobuf:"bytes,2,opt,name=NodeAddress,proto3" json:"NodeAddress,omitempty"`
+	NodeCert             string        `protobuf:"bytes,3,opt,name=NodeCert,proto3" json:"NodeCert,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetBuildkitResponse) Reset()         { *m = GetBuildkitResponse{} }
+func (m *GetBuildkitResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBuildkitResponse) ProtoMessage()    {}
+func (*GetBuildkitResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{39}
+}
+
+func (m *GetBuildkitResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBuildkitResponse.Unmarshal(m, b)
+}
+func (m *GetBuildkitResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBuildkitResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBuildkitResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBuildkitResponse.Merge(m, src)
+}
+func (m *GetBuildkitResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBuildkitResponse.Size(m)
+}
+func (m *GetBuildkitResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBuildkitResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBuildkitResponse proto.InternalMessageInfo
+
+func (m *GetBuildkitResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetBuildkitResponse) GetNodeAddress() string {
+	if m != nil {
+		return m.NodeAddress
+	}
+	return ""
+}
+
+func (m *GetBuildkitResponse) GetNodeCert() string {
+	if m != nil {
+		return m.NodeCert
+	}
+	return ""
+}
+
+type BlimpUpPreviewRequest struct {
+	Auth                 *auth.BlimpAuth   `protobuf:"bytes,5,opt,name=auth,proto3" json:"auth,omitempty"`
+	Repo                 string            `protobuf:"bytes,2,opt,name=repo,proto3" json:"repo,omitempty"`
+	ComposeFiles         []string          `protobuf:"bytes,3,rep,name=composeFiles,proto3" json:"composeFiles,omitempty"`
+	Env                  map[string]string `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *BlimpUpPreviewRequest) Reset()         { *m = BlimpUpPreviewRequest{} }
+func (m *BlimpUpPreviewRequest) String() string { return proto.CompactTextString(m) }
+func (*BlimpUpPreviewRequest) ProtoMessage()    {}
+func (*BlimpUpPreviewRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{40}
+}
+
+func (m *BlimpUpPreviewRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlimpUpPreviewRequest.Unmarshal(m, b)
+}
+func (m *BlimpUpPreviewRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlimpUpPreviewRequest.Marshal(b, m, deterministic)
+}
+func (m *BlimpUpPreviewRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlimpUpPreviewRequest.Merge(m, src)
+}
+func (m *BlimpUpPreviewRequest) XXX_Size() int {
+	return xxx_messageInfo_BlimpUpPreviewRequest.Size(m)
+}
+func (m *BlimpUpPreviewRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlimpUpPreviewRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlimpUpPreviewRequest proto.InternalMessageInfo
+
+func (m *BlimpUpPreviewRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *BlimpUpPreviewRequest) GetRepo() string {
+	if m != nil {
+		return m.Repo
+	}
+	return ""
+}
+
+func (m *BlimpUpPreviewRequest) GetComposeFiles() []string {
+	if m != nil {
+		return m.ComposeFiles
+	}
+	return nil
+}
+
+func (m *BlimpUpPreviewRequest) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+type BlimpUpPreviewResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	StartedCli           bool          `protobuf:"varint,2,opt,name=started_cli,json=startedCli,proto3" json:"started_cli,omitempty"`
+	Output               []byte        `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *BlimpUpPreviewResponse) Reset()         { *m = BlimpUpPreviewResponse{} }
+func (m *BlimpUpPreviewResponse) String() string { return proto.CompactTextString(m) }
+func (*BlimpUpPreviewResponse) ProtoMessage()    {}
+func (*BlimpUpPreviewResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{41}
+}
+
+func (m *BlimpUpPreviewResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlimpUpPreviewResponse.Unmarshal(m, b)
+}
+func (m *BlimpUpPreviewResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlimpUpPreviewResponse.Marshal(b, m, deterministic)
+}
+func (m *BlimpUpPreviewResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlimpUpPreviewResponse.Merge(m, src)
+}
+func (m *BlimpUpPreviewResponse) XXX_Size() int {
+	return xxx_messageInfo_BlimpUpPreviewResponse.Size(m)
+}
+func (m *BlimpUpPreviewResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlimpUpPreviewResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlimpUpPreviewResponse proto.InternalMessageInfo
+
+func (m *BlimpUpPreviewResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *BlimpUpPreviewResponse) GetStartedCli() bool {
+	if m != nil {
+		return m.StartedCli
+	}
+	return false
+}
+
+func (m *BlimpUpPreviewResponse) GetOutput() []byte {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+// PullRequest identifies the pull request (or GitLab merge request) that a
+// preview sandbox was created for.
+type PullRequest struct {
+	// repo is the full name of the repository, such as "kelda/blimp".
+	Repo                 string   `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Number               uint32   `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PullRequest) Reset()         { *m = PullRequest{} }
+func (m *PullRequest) String() string { return proto.CompactTextString(m) }
+func (*PullRequest) ProtoMessage()    {}
+func (*PullRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{42}
+}
+
+func (m *PullRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PullRequest.Unmarshal(m, b)
+}
+func (m *PullRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PullRequest.Marshal(b, m, deterministic)
+}
+func (m *PullRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PullRequest.Merge(m, src)
+}
+func (m *PullRequest) XXX_Size() int {
+	return xxx_messageInfo_PullRequest.Size(m)
+}
+func (m *PullRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PullRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PullRequest proto.InternalMessageInfo
+
+func (m *PullRequest) GetRepo() string {
+	if m != nil {
+		return m.Repo
+	}
+	return ""
+}
+
+func (m *PullRequest) GetNumber() uint32 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+type CreatePreviewRequest struct {
+	// Only the cluster auth is used. The sandbox's token is derived from the
+	// pull request.
+	Auth        *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	PullRequest *PullRequest    `protobuf:"bytes,2,opt,name=pull_request,json=pullRequest,proto3" json:"pull_request,omitempty"`
+	// clone_url and ref are used to check out the pull request's code.
+	CloneUrl     string            `protobuf:"bytes,3,opt,name=clone_url,json=cloneUrl,proto3" json:"clone_url,omitempty"`
+	Ref          string            `protobuf:"bytes,4,opt,name=ref,proto3" json:"ref,omitempty"`
+	ComposeFiles []string          `protobuf:"bytes,5,rep,name=compose_files,json=composeFiles,proto3" json:"compose_files,omitempty"`
+	Env          map[string]string `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The port of the service that's exposed at the preview link.
+	Service              string   `protobuf:"bytes,7,opt,name=service,proto3" json:"service,omitempty"`
+	Port                 uint32   `protobuf:"varint,8,opt,name=port,proto3" json:"port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreatePreviewRequest) Reset()         { *m = CreatePreviewRequest{} }
+func (m *CreatePreviewRequest) String() string { return proto.CompactTextString(m) }
+func (*CreatePreviewRequest) ProtoMessage()    {}
+func (*CreatePreviewRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{43}
+}
+
+func (m *CreatePreviewRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreatePreviewRequest.Unmarshal(m, b)
+}
+func (m *CreatePreviewRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreatePreviewRequest.Marshal(b, m, deterministic)
+}
+func (m *CreatePreviewRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreatePreviewRequest.Merge(m, src)
+}
+func (m *CreatePreviewRequest) XXX_Size() int {
+	return xxx_messageInfo_CreatePreviewRequest.Size(m)
+}
+func (m *CreatePreviewRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreatePreviewRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreatePreviewRequest proto.InternalMessageInfo
+
+func (m *CreatePreviewRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *CreatePreviewRequest) GetPullRequest() *PullRequest {
+	if m != nil {
+		return m.PullRequest
+	}
+	return nil
+}
+
+func (m *CreatePreviewRequest) GetCloneUrl() string {
+	if m != nil {
+		return m.CloneUrl
+	}
+	return ""
+}
+
+func (m *CreatePreviewRequest) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+func (m *CreatePreviewRequest) GetComposeFiles() []string {
+	if m != nil {
+		return m.ComposeFiles
+	}
+	return nil
+}
+
+func (m *CreatePreviewRequest) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+func (m *CreatePreviewRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *CreatePreviewRequest) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+type CreatePreviewResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Namespace            string        `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Link                 string        `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *CreatePreviewResponse) Reset()         { *m = CreatePreviewResponse{} }
+func (m *CreatePreviewResponse) String() string { return proto.CompactTextString(m) }
+func (*CreatePreviewResponse) ProtoMessage()    {}
+func (*CreatePreviewResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{44}
+}
+
+func (m *CreatePreviewResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreatePreviewResponse.Unmarshal(m, b)
+}
+func (m *CreatePreviewResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreatePreviewResponse.Marshal(b, m, deterministic)
+}
+func (m *CreatePreviewResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreatePreviewResponse.Merge(m, src)
+}
+func (m *CreatePreviewResponse) XXX_Size() int {
+	return xxx_messageInfo_CreatePreviewResponse.Size(m)
+}
+func (m *CreatePreviewResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreatePreviewResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreatePreviewResponse proto.InternalMessageInfo
+
+func (m *CreatePreviewResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *CreatePreviewResponse) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *CreatePreviewResponse) GetLink() string {
+	if m != nil {
+		return m.Link
+	}
+	return ""
+}
+
+type DeletePreviewRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	PullRequest          *PullRequest    `protobuf:"bytes,2,opt,name=pull_request,json=pullRequest,proto3" json:"pull_request,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *DeletePreviewRequest) Reset()         { *m = DeletePreviewRequest{} }
+func (m *DeletePreviewRequest) String() string { return proto.CompactTextString(m) }
+func (*DeletePreviewRequest) ProtoMessage()    {}
+func (*DeletePreviewRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{45}
+}
+
+func (m *DeletePreviewRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeletePreviewRequest.Unmarshal(m, b)
+}
+func (m *DeletePreviewRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeletePreviewRequest.Marshal(b, m, deterministic)
+}
+func (m *DeletePreviewRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeletePreviewRequest.Merge(m, src)
+}
+func (m *DeletePreviewRequest) XXX_Size() int {
+	return xxx_messageInfo_DeletePreviewRequest.Size(m)
+}
+func (m *DeletePreviewRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeletePreviewRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeletePreviewRequest proto.InternalMessageInfo
+
+func (m *DeletePreviewRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *DeletePreviewRequest) GetPullRequest() *PullRequest {
+	if m != nil {
+		return m.PullRequest
+	}
+	return nil
+}
+
+type DeletePreviewResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *DeletePreviewResponse) Reset()         { *m = DeletePreviewResponse{} }
+func (m *DeletePreviewResponse) String() string { return proto.CompactTextString(m) }
+func (*DeletePreviewResponse) ProtoMessage()    {}
+func (*DeletePreviewResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{46}
+}
+
+func (m *DeletePreviewResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeletePreviewResponse.Unmarshal(m, b)
+}
+func (m *DeletePreviewResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeletePreviewResponse.Marshal(b, m, deterministic)
+}
+func (m *DeletePreviewResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeletePreviewResponse.Merge(m, src)
+}
+func (m *DeletePreviewResponse) XXX_Size() int {
+	return xxx_messageInfo_DeletePreviewResponse.Size(m)
+}
+func (m *DeletePreviewResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeletePreviewResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeletePreviewResponse proto.InternalMessageInfo
+
+func (m *DeletePreviewResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// Template is a starter project that `blimp init` scaffolds.
+type Template struct {
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// version is incremented whenever the template's files change.
+	Version uint32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// files maps paths relative to the project's root to their contents. It's
+	// only set by GetTemplate.
+	Files                map[string]string `protobuf:"bytes,4,rep,name=files,proto3" json:"files,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Template) Reset()         { *m = Template{} }
+func (m *Template) String() string { return proto.CompactTextString(m) }
+func (*Template) ProtoMessage()    {}
+func (*Template) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{47}
+}
+
+func (m *Template) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Template.Unmarshal(m, b)
+}
+func (m *Template) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Template.Marshal(b, m, deterministic)
+}
+func (m *Template) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Template.Merge(m, src)
+}
+func (m *Template) XXX_Size() int {
+	return xxx_messageInfo_Template.Size(m)
+}
+func (m *Template) XXX_DiscardUnknown() {
+	xxx_messageInfo_Template.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Template proto.InternalMessageInfo
+
+func (m *Template) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Template) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Template) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Template) GetFiles() map[string]string {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
+type ListTemplatesRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListTemplatesRequest) Reset()         { *m = ListTemplatesRequest{} }
+func (m *ListTemplatesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTemplatesRequest) ProtoMessage()    {}
+func (*ListTemplatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{48}
+}
+
+func (m *ListTemplatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTemplatesRequest.Unmarshal(m, b)
+}
+func (m *ListTemplatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTemplatesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTemplatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTemplatesRequest.Merge(m, src)
+}
+func (m *ListTemplatesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTemplatesRequest.Size(m)
+}
+func (m *ListTemplatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTemplatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTemplatesRequest proto.InternalMessageInfo
+
+func (m *ListTemplatesRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type ListTemplatesResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Templates            []*Template   `protobuf:"bytes,2,rep,name=templates,proto3" json:"templates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListTemplatesResponse) Reset()         { *m = ListTemplatesResponse{} }
+func (m *ListTemplatesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTemplatesResponse) ProtoMessage()    {}
+func (*ListTemplatesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{49}
+}
+
+func (m *ListTemplatesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTemplatesResponse.Unmarshal(m, b)
+}
+func (m *ListTemplatesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTemplatesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListTemplatesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTemplatesResponse.Merge(m, src)
+}
+func (m *ListTemplatesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListTemplatesResponse.Size(m)
+}
+func (m *ListTemplatesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTemplatesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTemplatesResponse proto.InternalMessageInfo
+
+func (m *ListTemplatesResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *ListTemplatesResponse) GetTemplates() []*Template {
+	if m != nil {
+		return m.Templates
+	}
+	return nil
+}
+
+type GetTemplateRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Name                 string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetTemplateRequest) Reset()         { *m = GetTemplateRequest{} }
+func (m *GetTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTemplateRequest) ProtoMessage()    {}
+func (*GetTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{50}
+}
+
+func (m *GetTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTemplateRequest.Unmarshal(m, b)
+}
+func (m *GetTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTemplateRequest.Merge(m, src)
+}
+func (m *GetTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTemplateRequest.Size(m)
+}
+func (m *GetTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTemplateRequest proto.InternalMessageInfo
+
+func (m *GetTemplateRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *GetTemplateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetTemplateResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Template             *Template     `protobuf:"bytes,2,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetTemplateResponse) Reset()         { *m = GetTemplateResponse{} }
+func (m *GetTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTemplateResponse) ProtoMessage()    {}
+func (*GetTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{51}
+}
+
+func (m *GetTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTemplateResponse.Unmarshal(m, b)
+}
+func (m *GetTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTemplateResponse.Merge(m, src)
+}
+func (m *GetTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTemplateResponse.Size(m)
+}
+func (m *GetTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTemplateResponse proto.InternalMessageInfo
+
+func (m *GetTemplateResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetTemplateResponse) GetTemplate() *Template {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+// Addon is a database managed by Blimp that runs alongside the services in
+// the sandbox.
+type Addon struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// engine is the type of database, such as "postgres".
+	Engine  string `protobuf:"bytes,2,opt,name=engine,proto3" json:"engine,omitempty"`
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// services are the services that the connection environment variables are
+	// injected into. If empty, they're injected into all services.
+	Services []string `protobuf:"bytes,4,rep,name=services,proto3" json:"services,omitempty"`
+	// env contains the environment variables for connecting to the database.
+	Env map[string]string `protobuf:"bytes,5,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// phase is a human-readable description of the add-on's state, such as
+	// "Running".
+	Phase string `protobuf:"bytes,6,opt,name=phase,proto3" json:"phase,omitempty"`
+	// snapshots are the names of the snapshots that can be restored.
+	Snapshots            []string `protobuf:"bytes,7,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Addon) Reset()         { *m = Addon{} }
+func (m *Addon) String() string { return proto.CompactTextString(m) }
+func (*Addon) ProtoMessage()    {}
+func (*Addon) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{52}
+}
+
+func (m *Addon) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Addon.Unmarshal(m, b)
+}
+func (m *Addon) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Addon.Marshal(b, m, deterministic)
+}
+func (m *Addon) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Addon.Merge(m, src)
+}
+func (m *Addon) XXX_Size() int {
+	return xxx_messageInfo_Addon.Size(m)
+}
+func (m *Addon) XXX_DiscardUnknown() {
+	xxx_messageInfo_Addon.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Addon proto.InternalMessageInfo
+
+func (m *Addon) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Addon) GetEngine() string {
+	if m != nil {
+		return m.Engine
+	}
+	return ""
+}
+
+func (m *Addon) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *Addon) GetServices() []string {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+func (m *Addon) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+func (m *Addon) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *Addon) GetSnapshots() []string {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+type CreateAddonRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Name                 string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Engine               string          `protobuf:"bytes,3,opt,name=engine,proto3" json:"engine,omitempty"`
+	Version              string          `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Services             []string        `protobuf:"bytes,5,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CreateAddonRequest) Reset()         { *m = CreateAddonRequest{} }
+func (m *CreateAddonRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateAddonRequest) ProtoMessage()    {}
+func (*CreateAddonRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{53}
+}
+
+func (m *CreateAddonRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAddonRequest.Unmarshal(m, b)
+}
+func (m *CreateAddonRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAddonRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateAddonRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAddonRequest.Merge(m, src)
+}
+func (m *CreateAddonRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateAddonRequest.Size(m)
+}
+func (m *CreateAddonRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAddonRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAddonRequest proto.InternalMessageInfo
+
+func (m *CreateAddonRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *CreateAddonRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateAddonRequest) GetEngine() string {
+	if m != nil {
+		return m.Engine
+	}
+	return ""
+}
+
+func (m *CreateAddonRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *CreateAddonRequest) GetServices() []string {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+type CreateAddonResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Addon *Addon        `protobuf:"bytes,2,opt,name=addon,proto3" json:"addon,omitempty"`
+	// restarted_services contains the running services that were restarted to
+	// pick up the connection environment variables.
+	RestartedServices    []string `protobuf:"bytes,3,rep,name=restarted_services,json=restartedServices,proto3" json:"restarted_services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAddonResponse) Reset()         { *m = CreateAddonResponse{} }
+func (m *CreateAddonResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateAddonResponse) ProtoMessage()    {}
+func (*CreateAddonResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{54}
+}
+
+func (m *CreateAddonResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAddonResponse.Unmarshal(m, b)
+}
+func (m *CreateAddonResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAddonResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateAddonResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAddonResponse.Merge(m, src)
+}
+func (m *CreateAddonResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateAddonResponse.Size(m)
+}
+func (m *CreateAddonResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAddonResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAddonResponse proto.InternalMessageInfo
+
+func (m *CreateAddonResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *CreateAddonResponse) GetAddon() *Addon {
+	if m != nil {
+		return m.Addon
+	}
+	return nil
+}
+
+func (m *CreateAddonResponse) GetRestartedServices() []string {
+	if m != nil {
+		return m.RestartedServices
+	}
+	return nil
+}
+
+type DeleteAddonRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Name string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// delete_data controls whether the add-on's data and snapshots are
+	// deleted as well.
+	DeleteData           bool     `protobuf:"varint,3,opt,name=delete_data,json=deleteData,proto3" json:"delete_data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteAddonRequest) Reset()         { *m = DeleteAddonRequest{} }
+func (m *DeleteAddonRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteAddonRequest) ProtoMessage()    {}
+func (*DeleteAddonRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{55}
+}
+
+func (m *DeleteAddonRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteAddonRequest.Unmarshal(m, b)
+}
+func (m *DeleteAddonRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteAddonRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteAddonRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteAddonRequest.Merge(m, src)
+}
+func (m *DeleteAddonRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteAddonRequest.Size(m)
+}
+func (m *DeleteAddonRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteAddonRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteAddonRequest proto.InternalMessageInfo
+
+func (m *DeleteAddonRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *DeleteAddonRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DeleteAddonRequest) GetDeleteData() bool {
+	if m != nil {
+		return m.DeleteData
+	}
+	return false
+}
+
+type DeleteAddonResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *DeleteAddonResponse) Reset()         { *m = DeleteAddonResponse{} }
+func (m *DeleteAddonResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteAddonResponse) ProtoMessage()    {}
+func (*DeleteAddonResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{56}
+}
+
+func (m *DeleteAddonResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteAddonResponse.Unmarshal(m, b)
+}
+func (m *DeleteAddonResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteAddonResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteAddonResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteAddonResponse.Merge(m, src)
+}
+func (m *DeleteAddonResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteAddonResponse.Size(m)
+}
+func (m *DeleteAddonResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteAddonResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteAddonResponse proto.InternalMessageInfo
+
+func (m *DeleteAddonResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type ListAddonsRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListAddonsRequest) Reset()         { *m = ListAddonsRequest{} }
+func (m *ListAddonsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAddonsRequest) ProtoMessage()    {}
+func (*ListAddonsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{57}
+}
+
+func (m *ListAddonsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAddonsRequest.Unmarshal(m, b)
+}
+func (m *ListAddonsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAddonsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListAddonsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAddonsRequest.Merge(m, src)
+}
+func (m *ListAddonsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAddonsRequest.Size(m)
+}
+func (m *ListAddonsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAddonsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAddonsRequest proto.InternalMessageInfo
+
+func (m *ListAddonsRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type ListAddonsResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Addons               []*Addon      `protobuf:"bytes,2,rep,name=addons,proto3" json:"addons,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListAddonsResponse) Reset()         { *m = ListAddonsResponse{} }
+func (m *ListAddonsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAddonsResponse) ProtoMessage()    {}
+func (*ListAddonsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{58}
+}
+
+func (m *ListAddonsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAddonsResponse.Unmarshal(m, b)
+}
+func (m *ListAddonsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAddonsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAddonsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAddonsResponse.Merge(m, src)
+}
+func (m *ListAddonsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAddonsResponse.Size(m)
+}
+func (m *ListAddonsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAddonsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAddonsResponse proto.InternalMessageInfo
+
+func (m *ListAddonsResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *ListAddonsResponse) GetAddons() []*Addon {
+	if m != nil {
+		return m.Addons
+	}
+	return nil
+}
+
+type SnapshotAddonRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Name string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// snapshot is the name of the snapshot. If empty, a name based on the
+	// current time is used.
+	Snapshot             string   `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotAddonRequest) Reset()         { *m = SnapshotAddonRequest{} }
+func (m *SnapshotAddonRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotAddonRequest) ProtoMessage()    {}
+func (*SnapshotAddonRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{59}
+}
+
+func (m *SnapshotAddonRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotAddonRequest.Unmarshal(m, b)
+}
+func (m *SnapshotAddonRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotAddonRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotAddonRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotAddonRequest.Merge(m, src)
+}
+func (m *SnapshotAddonRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotAddonRequest.Size(m)
+}
+func (m *SnapshotAddonRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotAddonRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotAddonRequest proto.InternalMessageInfo
+
+func (m *SnapshotAddonRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *SnapshotAddonRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SnapshotAddonRequest) GetSnapshot() string {
+	if m != nil {
+		return m.Snapshot
+	}
+	return ""
+}
+
+type SnapshotAddonResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Snapshot             string        `protobuf:"bytes,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *SnapshotAddonResponse) Reset()         { *m = SnapshotAddonResponse{} }
+func (m *SnapshotAddonResponse) String() string { return proto.CompactTextString(m) }
+func (*SnapshotAddonResponse) ProtoMessage()    {}
+func (*SnapshotAddonResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{60}
+}
+
+func (m *SnapshotAddonResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotAddonResponse.Unmarshal(m, b)
+}
+func (m *SnapshotAddonResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotAddonResponse.Marshal(b, m, deterministic)
+}
+func (m *SnapshotAddonResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotAddonResponse.Merge(m, src)
+}
+func (m *SnapshotAddonResponse) XXX_Size() int {
+	return xxx_messageInfo_SnapshotAddonResponse.Size(m)
+}
+func (m *SnapshotAddonResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotAddonResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotAddonResponse proto.InternalMessageInfo
+
+func (m *SnapshotAddonResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *SnapshotAddonResponse) GetSnapshot() string {
+	if m != nil {
+		return m.Snapshot
+	}
+	return ""
+}
+
+type RestoreAddonRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Name                 string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Snapshot             string          `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *RestoreAddonRequest) Reset()         { *m = RestoreAddonRequest{} }
+func (m *RestoreAddonRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreAddonRequest) ProtoMessage()    {}
+func (*RestoreAddonRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{61}
+}
+
+func (m *RestoreAddonRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreAddonRequest.Unmarshal(m, b)
+}
+func (m *RestoreAddonRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreAddonRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreAddonRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreAddonRequest.Merge(m, src)
+}
+func (m *RestoreAddonRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreAddonRequest.Size(m)
+}
+func (m *RestoreAddonRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreAddonRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreAddonRequest proto.InternalMessageInfo
+
+func (m *RestoreAddonRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *RestoreAddonRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RestoreAddonRequest) GetSnapshot() string {
+	if m != nil {
+		return m.Snapshot
+	}
+	return ""
+}
+
+type RestoreAddonResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *RestoreAddonResponse) Reset()         { *m = RestoreAddonResponse{} }
+func (m *RestoreAddonResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreAddonResponse) ProtoMessage()    {}
+func (*RestoreAddonResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{62}
+}
+
+func (m *RestoreAddonResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreAddonResponse.Unmarshal(m, b)
+}
+func (m *RestoreAddonResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreAddonResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreAddonResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreAddonResponse.Merge(m, src)
+}
+func (m *RestoreAddonResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreAddonResponse.Size(m)
+}
+func (m *RestoreAddonResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreAddonResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreAddonResponse proto.InternalMessageInfo
+
+func (m *RestoreAddonResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// SandboxEnvVar is an environment variable that's set with `blimp env`. It
+// overrides the value set by the Compose file.
+type SandboxEnvVar struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// services are the services that the variable is injected into. If empty,
+	// it's injected into all services.
+	Services             []string `protobuf:"bytes,3,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SandboxEnvVar) Reset()         { *m = SandboxEnvVar{} }
+func (m *SandboxEnvVar) String() string { return proto.CompactTextString(m) }
+func (*SandboxEnvVar) ProtoMessage()    {}
+func (*SandboxEnvVar) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{63}
+}
+
+func (m *SandboxEnvVar) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SandboxEnvVar.Unmarshal(m, b)
+}
+func (m *SandboxEnvVar) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SandboxEnvVar.Marshal(b, m, deterministic)
+}
+func (m *SandboxEnvVar) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SandboxEnvVar.Merge(m, src)
+}
+func (m *SandboxEnvVar) XXX_Size() int {
+	return xxx_messageInfo_SandboxEnvVar.Size(m)
+}
+func (m *SandboxEnvVar) XXX_DiscardUnknown() {
+	xxx_messageInfo_SandboxEnvVar.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SandboxEnvVar proto.InternalMessageInfo
+
+func (m *SandboxEnvVar) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SandboxEnvVar) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *SandboxEnvVar) GetServices() []string {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+type ListSandboxEnvRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListSandboxEnvRequest) Reset()         { *m = ListSandboxEnvRequest{} }
+func (m *ListSandboxEnvRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSandboxEnvRequest) ProtoMessage()    {}
+func (*ListSandboxEnvRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{64}
+}
+
+func (m *ListSandboxEnvRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSandboxEnvRequest.Unmarshal(m, b)
+}
+func (m *ListSandboxEnvRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSandboxEnvRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSandboxEnvRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSandboxEnvRequest.Merge(m, src)
+}
+func (m *ListSandboxEnvRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSandboxEnvRequest.Size(m)
+}
+func (m *ListSandboxEnvRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSandboxEnvRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSandboxEnvRequest proto.InternalMessageInfo
+
+func (m *ListSandboxEnvRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type ListSandboxEnvResponse struct {
+	Error                *errors.Error    `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Env                  []*SandboxEnvVar `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListSandboxEnvResponse) Reset()         { *m = ListSandboxEnvResponse{} }
+func (m *ListSandboxEnvResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSandboxEnvResponse) ProtoMessage()    {}
+func (*ListSandboxEnvResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{65}
+}
+
+func (m *ListSandboxEnvResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSandboxEnvResponse.Unmarshal(m, b)
+}
+func (m *ListSandboxEnvResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSandboxEnvResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSandboxEnvResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSandboxEnvResponse.Merge(m, src)
+}
+func (m *ListSandboxEnvResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSandboxEnvResponse.Size(m)
+}
+func (m *ListSandboxEnvResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSandboxEnvResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSandboxEnvResponse proto.InternalMessageInfo
+
+func (m *ListSandboxEnvResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *ListSandboxEnvResponse) GetEnv() []*SandboxEnvVar {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+type SetSandboxEnvRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// env replaces the variables with the same names.
+	Env                  []*SandboxEnvVar `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SetSandboxEnvRequest) Reset()         { *m = SetSandboxEnvRequest{} }
+func (m *SetSandboxEnvRequest) String() string { return proto.CompactTextString(m) }
+func (*SetSandboxEnvRequest) ProtoMessage()    {}
+func (*SetSandboxEnvRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{66}
+}
+
+func (m *SetSandboxEnvRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetSandboxEnvRequest.Unmarshal(m, b)
+}
+func (m *SetSandboxEnvRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetSandboxEnvRequest.Marshal(b, m, deterministic)
+}
+func (m *SetSandboxEnvRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetSandboxEnvRequest.Merge(m, src)
+}
+func (m *SetSandboxEnvRequest) XXX_Size() int {
+	return xxx_messageInfo_SetSandboxEnvRequest.Size(m)
+}
+func (m *SetSandboxEnvRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetSandboxEnvRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetSandboxEnvRequest proto.InternalMessageInfo
+
+func (m *SetSandboxEnvRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *SetSandboxEnvRequest) GetEnv() []*SandboxEnvVar {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+type SetSandboxEnvResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// restarted_services contains the running services that were restarted to
+	// pick up the change.
+	RestartedServices    []string `protobuf:"bytes,2,rep,name=restarted_services,json=restartedServices,proto3" json:"restarted_services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetSandboxEnvResponse) Reset()         { *m = SetSandboxEnvResponse{} }
+func (m *SetSandboxEnvResponse) String() string { return proto.CompactTextString(m) }
+func (*SetSandboxEnvResponse) ProtoMessage()    {}
+func (*SetSandboxEnvResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{67}
+}
+
+func (m *SetSandboxEnvResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetSandboxEnvResponse.Unmarshal(m, b)
+}
+func (m *SetSandboxEnvResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetSandboxEnvResponse.Marshal(b, m, deterministic)
+}
+func (m *SetSandboxEnvResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetSandboxEnvResponse.Merge(m, src)
+}
+func (m *SetSandboxEnvResponse) XXX_Size() int {
+	return xxx_messageInfo_SetSandboxEnvResponse.Size(m)
+}
+func (m *SetSandboxEnvResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetSandboxEnvResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetSandboxEnvResponse proto.InternalMessageInfo
+
+func (m *SetSandboxEnvResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *SetSandboxEnvResponse) GetRestartedServices() []string {
+	if m != nil {
+		return m.RestartedServices
+	}
+	return nil
+}
+
+type UnsetSandboxEnvRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Names                []string        `protobuf:"bytes,2,rep,name=names,proto3" json:"names,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *UnsetSandboxEnvRequest) Reset()         { *m = UnsetSandboxEnvRequest{} }
+func (m *UnsetSandboxEnvRequest) String() string { return proto.CompactTextString(m) }
+func (*UnsetSandboxEnvRequest) ProtoMessage()    {}
+func (*UnsetSandboxEnvRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{68}
+}
+
+func (m *UnsetSandboxEnvRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnsetSandboxEnvRequest.Unmarshal(m, b)
+}
+func (m *UnsetSandboxEnvRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnsetSandboxEnvRequest.Marshal(b, m, deterministic)
+}
+func (m *UnsetSandboxEnvRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnsetSandboxEnvRequest.Merge(m, src)
+}
+func (m *UnsetSandboxEnvRequest) XXX_Size() int {
+	return xxx_messageInfo_UnsetSandboxEnvRequest.Size(m)
+}
+func (m *UnsetSandboxEnvRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnsetSandboxEnvRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnsetSandboxEnvRequest proto.InternalMessageInfo
+
+func (m *UnsetSandboxEnvRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *UnsetSandboxEnvRequest) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+type UnsetSandboxEnvResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	RestartedServices    []string      `protobuf:"bytes,2,rep,name=restarted_services,json=restartedServices,proto3" json:"restarted_services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *UnsetSandboxEnvResponse) Reset()         { *m = UnsetSandboxEnvResponse{} }
+func (m *UnsetSandboxEnvResponse) String() string { return proto.CompactTextString(m) }
+func (*UnsetSandboxEnvResponse) ProtoMessage()    {}
+func (*UnsetSandboxEnvResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{69}
+}
+
+func (m *UnsetSandboxEnvResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnsetSandboxEnvResponse.Unmarshal(m, b)
+}
+func (m *UnsetSandboxEnvResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnsetSandboxEnvResponse.Marshal(b, m, deterministic)
+}
+func (m *UnsetSandboxEnvResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnsetSandboxEnvResponse.Merge(m, src)
+}
+func (m *UnsetSandboxEnvResponse) XXX_Size() int {
+	return xxx_messageInfo_UnsetSandboxEnvResponse.Size(m)
+}
+func (m *UnsetSandboxEnvResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnsetSandboxEnvResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnsetSandboxEnvResponse proto.InternalMessageInfo
+
+func (m *UnsetSandboxEnvResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *UnsetSandboxEnvResponse) GetRestartedServices() []string {
+	if m != nil {
+		return m.RestartedServices
+	}
+	return nil
+}
+
+// ScopedToken is a token that can only make the RPCs allowed by its scopes,
+// and only for the sandbox that created it.
+type ScopedToken struct {
+	Id     string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name   string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// created_at is a Unix timestamp in seconds.
+	CreatedAt            int64    `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScopedToken) Reset()         { *m = ScopedToken{} }
+func (m *ScopedToken) String() string { return proto.CompactTextString(m) }
+func (*ScopedToken) ProtoMessage()    {}
+func (*ScopedToken) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{70}
+}
+
+func (m *ScopedToken) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScopedToken.Unmarshal(m, b)
+}
+func (m *ScopedToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScopedToken.Marshal(b, m, deterministic)
+}
+func (m *ScopedToken) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScopedToken.Merge(m, src)
+}
+func (m *ScopedToken) XXX_Size() int {
+	return xxx_messageInfo_ScopedToken.Size(m)
+}
+func (m *ScopedToken) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScopedToken.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScopedToken proto.InternalMessageInfo
+
+func (m *ScopedToken) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ScopedToken) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ScopedToken) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *ScopedToken) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type CreateTokenRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Name                 string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes               []string        `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CreateTokenRequest) Reset()         { *m = CreateTokenRequest{} }
+func (m *CreateTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateTokenRequest) ProtoMessage()    {}
+func (*CreateTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{71}
+}
+
+func (m *CreateTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTokenRequest.Unmarshal(m, b)
+}
+func (m *CreateTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTokenRequest.Merge(m, src)
+}
+func (m *CreateTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateTokenRequest.Size(m)
+}
+func (m *CreateTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTokenRequest proto.InternalMessageInfo
+
+func (m *CreateTokenRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *CreateTokenRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateTokenRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+type CreateTokenResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// token is only returned when the token is created. It can't be retrieved
+	// later.
+	Token                string       `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	Info                 *ScopedToken `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *CreateTokenResponse) Reset()         { *m = CreateTokenResponse{} }
+func (m *CreateTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateTokenResponse) ProtoMessage()    {}
+func (*CreateTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{72}
+}
+
+func (m *CreateTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTokenResponse.Unmarshal(m, b)
+}
+func (m *CreateTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTokenResponse.Merge(m, src)
+}
+func (m *CreateTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateTokenResponse.Size(m)
+}
+func (m *CreateTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTokenResponse proto.InternalMessageInfo
+
+func (m *CreateTokenResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *CreateTokenResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *CreateTokenResponse) GetInfo() *ScopedToken {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+type ListTokensRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListTokensRequest) Reset()         { *m = ListTokensRequest{} }
+func (m *ListTokensRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTokensRequest) ProtoMessage()    {}
+func (*ListTokensRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{73}
+}
+
+func (m *ListTokensRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTokensRequest.Unmarshal(m, b)
+}
+func (m *ListTokensRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTokensRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTokensRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTokensRequest.Merge(m, src)
+}
+func (m *ListTokensRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTokensRequest.Size(m)
+}
+func (m *ListTokensRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTokensRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTokensRequest proto.InternalMessageInfo
+
+func (m *ListTokensRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type ListTokensResponse struct {
+	Error                *errors.Error  `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Tokens               []*ScopedToken `protobuf:"bytes,2,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListTokensResponse) Reset()         { *m = ListTokensResponse{} }
+func (m *ListTokensResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTokensResponse) ProtoMessage()    {}
+func (*ListTokensResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{74}
+}
+
+func (m *ListTokensResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTokensResponse.Unmarshal(m, b)
+}
+func (m *ListTokensResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTokensResponse.Marshal(b, m, deterministic)
+}
+func (m *ListTokensResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTokensResponse.Merge(m, src)
+}
+func (m *ListTokensResponse) XXX_Size() int {
+	return xxx_messageInfo_ListTokensResponse.Size(m)
+}
+func (m *ListTokensResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTokensResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTokensResponse proto.InternalMessageInfo
+
+func (m *ListTokensResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *ListTokensResponse) GetTokens() []*ScopedToken {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type RevokeTokenRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Id                   string          `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *RevokeTokenRequest) Reset()         { *m = RevokeTokenRequest{} }
+func (m *RevokeTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeTokenRequest) ProtoMessage()    {}
+func (*RevokeTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{75}
+}
+
+func (m *RevokeTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeTokenRequest.Unmarshal(m, b)
+}
+func (m *RevokeTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeTokenRequest.Merge(m, src)
+}
+func (m *RevokeTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeTokenRequest.Size(m)
+}
+func (m *RevokeTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeTokenRequest proto.InternalMessageInfo
+
+func (m *RevokeTokenRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *RevokeTokenRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type RevokeTokenResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *RevokeTokenResponse) Reset()         { *m = RevokeTokenResponse{} }
+func (m *RevokeTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeTokenResponse) ProtoMessage()    {}
+func (*RevokeTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{76}
+}
+
+func (m *RevokeTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeTokenResponse.Unmarshal(m, b)
+}
+func (m *RevokeTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *RevokeTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeTokenResponse.Merge(m, src)
+}
+func (m *RevokeTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeTokenResponse.Size(m)
+}
+func (m *RevokeTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeTokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeTokenResponse proto.InternalMessageInfo
+
+func (m *RevokeTokenResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// SandboxInfo is an operator's view of a sandbox.
+type SandboxInfo struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// owner is the identity of the user that created the sandbox.
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// created_at and last_activity are Unix timestamps in seconds.
+	// last_activity is zero if no activity has been recorded.
+	CreatedAt    int64                      `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastActivity int64                      `protobuf:"varint,4,opt,name=last_activity,json=lastActivity,proto3" json:"last_activity,omitempty"`
+	Phase        SandboxStatus_SandboxPhase `protobuf:"varint,5,opt,name=phase,proto3,enum=blimp.cluster.v0.SandboxStatus_SandboxPhase" json:"phase,omitempty"`
+	NumPods      int32                      `protobuf:"varint,6,opt,name=num_pods,json=numPods,proto3" json:"num_pods,omitempty"`
+	// cpu_requested and memory_requested are the sum of the resource requests
+	// of all pods in the sandbox, formatted as Kubernetes quantities.
+	CpuRequested         string   `protobuf:"bytes,7,opt,name=cpu_requested,json=cpuRequested,proto3" json:"cpu_requested,omitempty"`
+	MemoryRequested      string   `protobuf:"bytes,8,opt,name=memory_requested,json=memoryRequested,proto3" json:"memory_requested,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SandboxInfo) Reset()         { *m = SandboxInfo{} }
+func (m *SandboxInfo) String() string { return proto.CompactTextString(m) }
+func (*SandboxInfo) ProtoMessage()    {}
+func (*SandboxInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{77}
+}
+
+func (m *SandboxInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SandboxInfo.Unmarshal(m, b)
+}
+func (m *SandboxInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SandboxInfo.Marshal(b, m, deterministic)
+}
+func (m *SandboxInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SandboxInfo.Merge(m, src)
+}
+func (m *SandboxInfo) XXX_Size() int {
+	return xxx_messageInfo_SandboxInfo.Size(m)
+}
+func (m *SandboxInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SandboxInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SandboxInfo proto.InternalMessageInfo
+
+func (m *SandboxInfo) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *SandboxInfo) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *SandboxInfo) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *SandboxInfo) GetLastActivity() int64 {
+	if m != nil {
+		return m.LastActivity
+	}
+	return 0
+}
+
+func (m *SandboxInfo) GetPhase() SandboxStatus_SandboxPhase {
+	if m != nil {
+		return m.Phase
+	}
+	return SandboxStatus_UNKNOWN
+}
+
+func (m *SandboxInfo) GetNumPods() int32 {
+	if m != nil {
+		return m.NumPods
+	}
+	return 0
+}
+
+func (m *SandboxInfo) GetCpuRequested() string {
+	if m != nil {
+		return m.CpuRequested
+	}
+	return ""
+}
+
+func (m *SandboxInfo) GetMemoryRequested() string {
+	if m != nil {
+		return m.MemoryRequested
+	}
+	return ""
+}
+
+type ListSandboxesRequest struct {
+	AdminToken           string   `protobuf:"bytes,1,opt,name=admin_token,json=adminToken,proto3" json:"admin_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSandboxesRequest) Reset()         { *m = ListSandboxesRequest{} }
+func (m *ListSandboxesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSandboxesRequest) ProtoMessage()    {}
+func (*ListSandboxesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{78}
+}
+
+func (m *ListSandboxesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSandboxesRequest.Unmarshal(m, b)
+}
+func (m *ListSandboxesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSandboxesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSandboxesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSandboxesRequest.Merge(m, src)
+}
+func (m *ListSandboxesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSandboxesRequest.Size(m)
+}
+func (m *ListSandboxesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSandboxesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSandboxesRequest proto.InternalMessageInfo
+
+func (m *ListSandboxesRequest) GetAdminToken() string {
+	if m != nil {
+		return m.AdminToken
+	}
+	return ""
+}
+
+type ListSandboxesResponse struct {
+	Error                *errors.Error  `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Sandboxes            []*SandboxInfo `protobuf:"bytes,2,rep,name=sandboxes,proto3" json:"sandboxes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListSandboxesResponse) Reset()         { *m = ListSandboxesResponse{} }
+func (m *ListSandboxesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSandboxesResponse) ProtoMessage()    {}
+func (*ListSandboxesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{79}
+}
+
+func (m *ListSandboxesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSandboxesResponse.Unmarshal(m, b)
+}
+func (m *ListSandboxesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSandboxesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSandboxesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSandboxesResponse.Merge(m, src)
+}
+func (m *ListSandboxesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSandboxesResponse.Size(m)
+}
+func (m *ListSandboxesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSandboxesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSandboxesResponse proto.InternalMessageInfo
+
+func (m *ListSandboxesResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *ListSandboxesResponse) GetSandboxes() []*SandboxInfo {
+	if m != nil {
+		return m.Sandboxes
+	}
+	return nil
+}
+
+// GetDiagnosticsRequest is used by `blimp bug-report` to collect information
+// that's only visible to the manager.
+type GetDiagnosticsRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// max_status_snapshots caps how many recent status updates are returned.
+	MaxStatusSnapshots   int32    `protobuf:"varint,2,opt,name=max_status_snapshots,json=maxStatusSnapshots,proto3" json:"max_status_snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetDiagnosticsRequest) Reset()         { *m = GetDiagnosticsRequest{} }
+func (m *GetDiagnosticsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetDiagnosticsRequest) ProtoMessage()    {}
+func (*GetDiagnosticsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{80}
+}
+
+func (m *GetDiagnosticsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDiagnosticsRequest.Unmarshal(m, b)
+}
+func (m *GetDiagnosticsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDiagnosticsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetDiagnosticsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDiagnosticsRequest.Merge(m, src)
+}
+func (m *GetDiagnosticsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetDiagnosticsRequest.Size(m)
+}
+func (m *GetDiagnosticsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDiagnosticsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDiagnosticsRequest proto.InternalMessageInfo
+
+func (m *GetDiagnosticsRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *GetDiagnosticsRequest) GetMaxStatusSnapshots() int32 {
+	if m != nil {
+		return m.MaxStatusSnapshots
+	}
+	return 0
+}
+
+type GetDiagnosticsResponse struct {
+	Error  *errors.Error  `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Status *SandboxStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// status_history contains the most recent status updates sent to the
+	// sandbox's clients, oldest first.
+	StatusHistory []*StatusSnapshot `protobuf:"bytes,3,rep,name=status_history,json=statusHistory,proto3" json:"status_history,omitempty"`
+	Pods          []*PodDiagnostics `protobuf:"bytes,4,rep,name=pods,proto3" json:"pods,omitempty"`
+	// events are the Kubernetes events in the sandbox, oldest first.
+	Events               []*SandboxEvent `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetDiagnosticsResponse) Reset()         { *m = GetDiagnosticsResponse{} }
+func (m *GetDiagnosticsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetDiagnosticsResponse) ProtoMessage()    {}
+func (*GetDiagnosticsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{81}
+}
+
+func (m *GetDiagnosticsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDiagnosticsResponse.Unmarshal(m, b)
+}
+func (m *GetDiagnosticsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDiagnosticsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetDiagnosticsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDiagnosticsResponse.Merge(m, src)
+}
+func (m *GetDiagnosticsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetDiagnosticsResponse.Size(m)
+}
+func (m *GetDiagnosticsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDiagnosticsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDiagnosticsResponse proto.InternalMessageInfo
+
+func (m *GetDiagnosticsResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetDiagnosticsResponse) GetStatus() *SandboxStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *GetDiagnosticsResponse) GetStatusHistory() []*StatusSnapshot {
+	if m != nil {
+		return m.StatusHistory
+	}
+	return nil
+}
+
+func (m *GetDiagnosticsResponse) GetPods() []*PodDiagnostics {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
+func (m *GetDiagnosticsResponse) GetEvents() []*SandboxEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type StatusSnapshot struct {
+	// time is a Unix timestamp in seconds.
+	Time                 int64          `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Status               *SandboxStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *StatusSnapshot) Reset()         { *m = StatusSnapshot{} }
+func (m *StatusSnapshot) String() string { return proto.CompactTextString(m) }
+func (*StatusSnapshot) ProtoMessage()    {}
+func (*StatusSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{82}
+}
+
+func (m *StatusSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusSnapshot.Unmarshal(m, b)
+}
+func (m *StatusSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusSnapshot.Marshal(b, m, deterministic)
+}
+func (m *StatusSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusSnapshot.Merge(m, src)
+}
+func (m *StatusSnapshot) XXX_Size() int {
+	return xxx_messageInfo_StatusSnapshot.Size(m)
+}
+func (m *StatusSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusSnapshot proto.InternalMessageInfo
+
+func (m *StatusSnapshot) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *StatusSnapshot) GetStatus() *SandboxStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type PodDiagnostics struct {
+	// service is empty for pods that aren't services, such as Syncthing.
+	Service              string                  `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Pod                  string                  `protobuf:"bytes,2,opt,name=pod,proto3" json:"pod,omitempty"`
+	Phase                string                  `protobuf:"bytes,3,opt,name=phase,proto3" json:"phase,omitempty"`
+	Node                 string                  `protobuf:"bytes,4,opt,name=node,proto3" json:"node,omitempty"`
+	Containers           []*ContainerDiagnostics `protobuf:"bytes,5,rep,name=containers,proto3" json:"containers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *PodDiagnostics) Reset()         { *m = PodDiagnostics{} }
+func (m *PodDiagnostics) String() string { return proto.CompactTextString(m) }
+func (*PodDiagnostics) ProtoMessage()    {}
+func (*PodDiagnostics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{83}
+}
+
+func (m *PodDiagnostics) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PodDiagnostics.Unmarshal(m, b)
+}
+func (m *PodDiagnostics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PodDiagnostics.Marshal(b, m, deterministic)
+}
+func (m *PodDiagnostics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PodDiagnostics.Merge(m, src)
+}
+func (m *PodDiagnostics) XXX_Size() int {
+	return xxx_messageInfo_PodDiagnostics.Size(m)
+}
+func (m *PodDiagnostics) XXX_DiscardUnknown() {
+	xxx_messageInfo_PodDiagnostics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PodDiagnostics proto.InternalMessageInfo
+
+func (m *PodDiagnostics) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *PodDiagnostics) GetPod() string {
+	if m != nil {
+		return m.Pod
+	}
+	return ""
+}
+
+func (m *PodDiagnostics) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *PodDiagnostics) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *PodDiagnostics) GetContainers() []*ContainerDiagnostics {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+type ContainerDiagnostics struct {
+	Name         string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ready        bool   `protobuf:"varint,2,opt,name=ready,proto3" json:"ready,omitempty"`
+	RestartCount int32  `protobuf:"varint,3,opt,name=restart_count,json=restartCount,proto3" json:"restart_count,omitempty"`
+	// state describes the container's current state, such as `running`, or
+	// `waiting: CrashLoopBackOff`.
+	State string `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	// last_termination describes why the container last exited, if it has
+	// restarted.
+	LastTermination      string   `protobuf:"bytes,5,opt,name=last_termination,json=lastTermination,proto3" json:"last_termination,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContainerDiagnostics) Reset()         { *m = ContainerDiagnostics{} }
+func (m *ContainerDiagnostics) String() string { return proto.CompactTextString(m) }
+func (*ContainerDiagnostics) ProtoMessage()    {}
+func (*ContainerDiagnostics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{84}
+}
+
+func (m *ContainerDiagnostics) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContainerDiagnostics.Unmarshal(m, b)
+}
+func (m *ContainerDiagnostics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContainerDiagnostics.Marshal(b, m, deterministic)
+}
+func (m *ContainerDiagnostics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContainerDiagnostics.Merge(m, src)
+}
+func (m *ContainerDiagnostics) XXX_Size() int {
+	return xxx_messageInfo_ContainerDiagnostics.Size(m)
+}
+func (m *ContainerDiagnostics) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContainerDiagnostics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContainerDiagnostics proto.InternalMessageInfo
+
+func (m *ContainerDiagnostics) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ContainerDiagnostics) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
+func (m *ContainerDiagnostics) GetRestartCount() int32 {
+	if m != nil {
+		return m.RestartCount
+	}
+	return 0
+}
+
+func (m *ContainerDiagnostics) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *ContainerDiagnostics) GetLastTermination() string {
+	if m != nil {
+		return m.LastTermination
+	}
+	return ""
+}
+
+type SandboxEvent struct {
+	// time is a Unix timestamp in seconds.
+	Time                 int64    `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Object               string   `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	Type                 string   `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message              string   `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Count                int32    `protobuf:"varint,6,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SandboxEvent) Reset()         { *m = SandboxEvent{} }
+func (m *SandboxEvent) String() string { return proto.CompactTextString(m) }
+func (*SandboxEvent) ProtoMessage()    {}
+func (*SandboxEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{85}
+}
+
+func (m *SandboxEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SandboxEvent.Unmarshal(m, b)
+}
+func (m *SandboxEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SandboxEvent.Marshal(b, m, deterministic)
+}
+func (m *SandboxEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SandboxEvent.Merge(m, src)
+}
+func (m *SandboxEvent) XXX_Size() int {
+	return xxx_messageInfo_SandboxEvent.Size(m)
+}
+func (m *SandboxEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_SandboxEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SandboxEvent proto.InternalMessageInfo
+
+func (m *SandboxEvent) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *SandboxEvent) GetObject() string {
+	if m != nil {
+		return m.Object
+	}
+	return ""
+}
+
+func (m *SandboxEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *SandboxEvent) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *SandboxEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *SandboxEvent) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type DescribeSandboxRequest struct {
+	AdminToken           string   `protobuf:"bytes,1,opt,name=admin_token,json=adminToken,proto3" json:"admin_token,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DescribeSandboxRequest) Reset()         { *m = DescribeSandboxRequest{} }
+func (m *DescribeSandboxRequest) String() string { return proto.CompactTextString(m) }
+func (*DescribeSandboxRequest) ProtoMessage()    {}
+func (*DescribeSandboxRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{86}
+}
+
+func (m *DescribeSandboxRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeSandboxRequest.Unmarshal(m, b)
+}
+func (m *DescribeSandboxRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeSandboxRequest.Marshal(b, m, deterministic)
+}
+func (m *DescribeSandboxRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeSandboxRequest.Merge(m, src)
+}
+func (m *DescribeSandboxRequest) XXX_Size() int {
+	return xxx_messageInfo_DescribeSandboxRequest.Size(m)
+}
+func (m *DescribeSandboxRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeSandboxRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeSandboxRequest proto.InternalMessageInfo
+
+func (m *DescribeSandboxRequest) GetAdminToken() string {
+	if m != nil {
+		return m.AdminToken
+	}
+	return ""
+}
+
+func (m *DescribeSandboxRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type DescribeSandboxResponse struct {
+	Error                *errors.Error  `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Sandbox              *SandboxInfo   `protobuf:"bytes,2,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
+	Status               *SandboxStatus `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *DescribeSandboxResponse) Reset()         { *m = DescribeSandboxResponse{} }
+func (m *DescribeSandboxResponse) String() string { return proto.CompactTextString(m) }
+func (*DescribeSandboxResponse) ProtoMessage()    {}
+func (*DescribeSandboxResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{87}
+}
+
+func (m *DescribeSandboxResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeSandboxResponse.Unmarshal(m, b)
+}
+func (m *DescribeSandboxResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeSandboxResponse.Marshal(b, m, deterministic)
+}
+func (m *DescribeSandboxResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeSandboxResponse.Merge(m, src)
+}
+func (m *DescribeSandboxResponse) XXX_Size() int {
+	return xxx_messageInfo_DescribeSandboxResponse.Size(m)
+}
+func (m *DescribeSandboxResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeSandboxResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeSandboxResponse proto.InternalMessageInfo
+
+func (m *DescribeSandboxResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *DescribeSandboxResponse) GetSandbox() *SandboxInfo {
+	if m != nil {
+		return m.Sandbox
+	}
+	return nil
+}
+
+func (m *DescribeSandboxResponse) GetStatus() *SandboxStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type EvictSandboxRequest struct {
+	AdminToken           string   `protobuf:"bytes,1,opt,name=admin_token,json=adminToken,proto3" json:"admin_token,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	DeleteVolumes        bool     `protobuf:"varint,3,opt,name=delete_volumes,json=deleteVolumes,proto3" json:"delete_volumes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EvictSandboxRequest) Reset()         { *m = EvictSandboxRequest{} }
+func (m *EvictSandboxRequest) String() string { return proto.CompactTextString(m) }
+func (*EvictSandboxRequest) ProtoMessage()    {}
+func (*EvictSandboxRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{88}
+}
+
+func (m *EvictSandboxRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EvictSandboxRequest.Unmarshal(m, b)
+}
+func (m *EvictSandboxRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EvictSandboxRequest.Marshal(b, m, deterministic)
+}
+func (m *EvictSandboxRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvictSandboxRequest.Merge(m, src)
+}
+func (m *EvictSandboxRequest) XXX_Size() int {
+	return xxx_messageInfo_EvictSandboxRequest.Size(m)
+}
+func (m *EvictSandboxRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvictSandboxRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvictSandboxRequest proto.InternalMessageInfo
+
+func (m *EvictSandboxRequest) GetAdminToken() string {
+	if m != nil {
+		return m.AdminToken
+	}
+	return ""
+}
+
+func (m *EvictSandboxRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *EvictSandboxRequest) GetDeleteVolumes() bool {
+	if m != nil {
+		return m.DeleteVolumes
+	}
+	return false
+}
+
+type EvictSandboxResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *EvictSandboxResponse) Reset()         { *m = EvictSandboxResponse{} }
+func (m *EvictSandboxResponse) String() string { return proto.CompactTextString(m) }
+func (*EvictSandboxResponse) ProtoMessage()    {}
+func (*EvictSandboxResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{89}
+}
+
+func (m *EvictSandboxResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EvictSandboxResponse.Unmarshal(m, b)
+}
+func (m *EvictSandboxResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EvictSandboxResponse.Marshal(b, m, deterministic)
+}
+func (m *EvictSandboxResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvictSandboxResponse.Merge(m, src)
+}
+func (m *EvictSandboxResponse) XXX_Size() int {
+	return xxx_messageInfo_EvictSandboxResponse.Size(m)
+}
+func (m *EvictSandboxResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvictSandboxResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvictSandboxResponse proto.InternalMessageInfo
+
+func (m *EvictSandboxResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type RunSelfTestRequest struct {
+	AdminToken           string   `protobuf:"bytes,1,opt,name=admin_token,json=adminToken,proto3" json:"admin_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RunSelfTestRequest) Reset()         { *m = RunSelfTestRequest{} }
+func (m *RunSelfTestRequest) String() string { return proto.CompactTextString(m) }
+func (*RunSelfTestRequest) ProtoMessage()    {}
+func (*RunSelfTestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{90}
+}
+
+func (m *RunSelfTestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunSelfTestRequest.Unmarshal(m, b)
+}
+func (m *RunSelfTestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunSelfTestRequest.Marshal(b, m, deterministic)
+}
+func (m *RunSelfTestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunSelfTestRequest.Merge(m, src)
+}
+func (m *RunSelfTestRequest) XXX_Size() int {
+	return xxx_messageInfo_RunSelfTestRequest.Size(m)
+}
+func (m *RunSelfTestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunSelfTestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RunSelfTestRequest proto.InternalMessageInfo
+
+func (m *RunSelfTestRequest) GetAdminToken() string {
+	if m != nil {
+		return m.AdminToken
+	}
+	return ""
+}
+
+type RunSelfTestResponse struct {
+	Error                *errors.Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Result               *SelfTestResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *RunSelfTestResponse) Reset()         { *m = RunSelfTestResponse{} }
+func (m *RunSelfTestResponse) String() string { return proto.CompactTextString(m) }
+func (*RunSelfTestResponse) ProtoMessage()    {}
+func (*RunSelfTestResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{91}
+}
+
+func (m *RunSelfTestResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunSelfTestResponse.Unmarshal(m, b)
+}
+func (m *RunSelfTestResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunSelfTestResponse.Marshal(b, m, deterministic)
+}
+func (m *RunSelfTestResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunSelfTestResponse.Merge(m, src)
+}
+func (m *RunSelfTestResponse) XXX_Size() int {
+	return xxx_messageInfo_RunSelfTestResponse.Size(m)
+}
+func (m *RunSelfTestResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunSelfTestResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RunSelfTestResponse proto.InternalMessageInfo
+
+func (m *RunSelfTestResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *RunSelfTestResponse) GetResult() *SelfTestResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type SelfTestResult struct {
+	Passed bool `protobuf:"varint,1,opt,name=passed,proto3" json:"passed,omitempty"`
+	// started_at is a Unix timestamp in seconds.
+	StartedAt            int64           `protobuf:"varint,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	Steps                []*SelfTestStep `protobuf:"bytes,3,rep,name=steps,proto3" json:"steps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *SelfTestResult) Reset()         { *m = SelfTestResult{} }
+func (m *SelfTestResult) String() string { return proto.CompactTextString(m) }
+func (*SelfTestResult) ProtoMessage()    {}
+func (*SelfTestResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{92}
+}
+
+func (m *SelfTestResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SelfTestResult.Unmarshal(m, b)
+}
+func (m *SelfTestResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SelfTestResult.Marshal(b, m, deterministic)
+}
+func (m *SelfTestResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SelfTestResult.Merge(m, src)
+}
+func (m *SelfTestResult) XXX_Size() int {
+	return xxx_messageInfo_SelfTestResult.Size(m)
+}
+func (m *SelfTestResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_SelfTestResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SelfTestResult proto.InternalMessageInfo
+
+func (m *SelfTestResult) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *SelfTestResult) GetStartedAt() int64 {
+	if m != nil {
+		return m.StartedAt
+	}
+	return 0
+}
+
+func (m *SelfTestResult) GetSteps() []*SelfTestStep {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
+}
+
+type SelfTestStep struct {
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Passed bool   `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`
+	// msg describes what was verified if the step passed, or why it failed.
+	Msg                  string   `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	DurationMs           int64    `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SelfTestStep) Reset()         { *m = SelfTestStep{} }
+func (m *SelfTestStep) String() string { return proto.CompactTextString(m) }
+func (*SelfTestStep) ProtoMessage()    {}
+func (*SelfTestStep) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{93}
+}
+
+func (m *SelfTestStep) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SelfTestStep.Unmarshal(m, b)
+}
+func (m *SelfTestStep) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SelfTestStep.Marshal(b, m, deterministic)
+}
+func (m *SelfTestStep) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SelfTestStep.Merge(m, src)
+}
+func (m *SelfTestStep) XXX_Size() int {
+	return xxx_messageInfo_SelfTestStep.Size(m)
+}
+func (m *SelfTestStep) XXX_DiscardUnknown() {
+	xxx_messageInfo_SelfTestStep.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SelfTestStep proto.InternalMessageInfo
+
+func (m *SelfTestStep) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SelfTestStep) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *SelfTestStep) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+func (m *SelfTestStep) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
+	}
+	return 0
+}
+
+type RunTestRequest struct {
+	Auth    *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Service string          `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	// command overrides the service's command if it's non-empty.
+	Command []string `protobuf:"bytes,3,rep,name=command,proto3" json:"command,omitempty"`
+	// If shard_count is greater than one, the tests run in a temporary clone of
+	// the sandbox, and the shard is passed to the tests via the
+	// BLIMP_TEST_SHARD_INDEX and BLIMP_TEST_SHARD_COUNT environment variables.
+	ShardIndex int32 `protobuf:"varint,4,opt,name=shard_index,json=shardIndex,proto3" json:"shard_index,omitempty"`
+	ShardCount int32 `protobuf:"varint,5,opt,name=shard_count,json=shardCount,proto3" json:"shard_count,omitempty"`
+	// copy_volumes copies the contents of the sandbox's volumes into the clone.
+	CopyVolumes          bool     `protobuf:"varint,6,opt,name=copy_volumes,json=copyVolumes,proto3" json:"copy_volumes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RunTestRequest) Reset()         { *m = RunTestRequest{} }
+func (m *RunTestRequest) String() string { return proto.CompactTextString(m) }
+func (*RunTestRequest) ProtoMessage()    {}
+func (*RunTestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{94}
+}
+
+func (m *RunTestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunTestRequest.Unmarshal(m, b)
+}
+func (m *RunTestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunTestRequest.Marshal(b, m, deterministic)
+}
+func (m *RunTestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunTestRequest.Merge(m, src)
+}
+func (m *RunTestRequest) XXX_Size() int {
+	return xxx_messageInfo_RunTestRequest.Size(m)
+}
+func (m *RunTestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunTestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RunTestRequest proto.InternalMessageInfo
+
+func (m *RunTestRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *RunTestRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *RunTestRequest) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *RunTestRequest) GetShardIndex() int32 {
+	if m != nil {
+		return m.ShardIndex
+	}
+	return 0
+}
+
+func (m *RunTestRequest) GetShardCount() int32 {
+	if m != nil {
+		return m.ShardCount
+	}
+	return 0
+}
+
+func (m *RunTestRequest) GetCopyVolumes() bool {
+	if m != nil {
+		return m.CopyVolumes
+	}
+	return false
+}
+
+type RunTestResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// output contains the next chunk of output from the test container.
+	Output []byte `protobuf:"bytes,2,opt,name=output,proto3" json:"output,omitempty"`
+	// pod_name is the name of the pod running the tests. It's set once the test
+	// container starts, and the pod remains available for collecting test
+	// results until the client closes the stream.
+	PodName string `protobuf:"bytes,3,opt,name=pod_name,json=podName,proto3" json:"pod_name,omitempty"`
+	// finished is set on the last message, once the test container has exited.
+	Finished bool  `protobuf:"varint,4,opt,name=finished,proto3" json:"finished,omitempty"`
+	ExitCode int32 `protobuf:"varint,5,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	// namespace is the namespace of the test pod.
+	Namespace            string   `protobuf:"bytes,6,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RunTestResponse) Reset()         { *m = RunTestResponse{} }
+func (m *RunTestResponse) String() string { return proto.CompactTextString(m) }
+func (*RunTestResponse) ProtoMessage()    {}
+func (*RunTestResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{95}
+}
+
+func (m *RunTestResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunTestResponse.Unmarshal(m, b)
+}
+func (m *RunTestResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunTestResponse.Marshal(b, m, deterministic)
+}
+func (m *RunTestResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunTestResponse.Merge(m, src)
+}
+func (m *RunTestResponse) XXX_Size() int {
+	return xxx_messageInfo_RunTestResponse.Size(m)
+}
+func (m *RunTestResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunTestResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RunTestResponse proto.InternalMessageInfo
+
+func (m *RunTestResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *RunTestResponse) GetOutput() []byte {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (m *RunTestResponse) GetPodName() string {
+	if m != nil {
+		return m.PodName
+	}
+	return ""
+}
+
+func (m *RunTestResponse) GetFinished() bool {
+	if m != nil {
+		return m.Finished
+	}
+	return false
+}
+
+func (m *RunTestResponse) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
+}
+
+func (m *RunTestResponse) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type GetUsageRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// period is the month to report usage for, formatted as YYYY-MM. Defaults
+	// to the current month.
+	Period string `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	// If admin_token is set, the usage of all sandboxes is returned rather
+	// than just the caller's. `auth` is ignored.
+	AdminToken           string   `protobuf:"bytes,3,opt,name=admin_token,json=adminToken,proto3" json:"admin_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetUsageRequest) Reset()         { *m = GetUsageRequest{} }
+func (m *GetUsageRequest) String() string { return proto.CompactTextString(m) }
+func (*GetUsageRequest) ProtoMessage()    {}
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{96}
+}
+
+func (m *GetUsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUsageRequest.Unmarshal(m, b)
+}
+func (m *GetUsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUsageRequest.Marshal(b, m, deterministic)
+}
+func (m *GetUsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUsageRequest.Merge(m, src)
+}
+func (m *GetUsageRequest) XXX_Size() int {
+	return xxx_messageInfo_GetUsageRequest.Size(m)
+}
+func (m *GetUsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUsageRequest proto.InternalMessageInfo
+
+func (m *GetUsageRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *GetUsageRequest) GetPeriod() string {
+	if m != nil {
+		return m.Period
+	}
+	return ""
+}
+
+func (m *GetUsageRequest) GetAdminToken() string {
+	if m != nil {
+		return m.AdminToken
+	}
+	return ""
+}
+
+type GetUsageResponse struct {
+	Error *errors.Error  `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Usage []*UsageRecord `protobuf:"bytes,2,rep,name=usage,proto3" json:"usage,omitempty"`
+	// quota contains the monthly quota for each sandbox. Fields that are zero
+	// have no quota.
+	Quota                *UsageRecord `protobuf:"bytes,3,opt,name=quota,proto3" json:"quota,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetUsageResponse) Reset()         { *m = GetUsageResponse{} }
+func (m *GetUsageResponse) String() string { return proto.CompactTextString(m) }
+func (*GetUsageResponse) ProtoMessage()    {}
+func (*GetUsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{97}
+}
+
+func (m *GetUsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUsageResponse.Unmarshal(m, b)
+}
+func (m *GetUsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUsageResponse.Marshal(b, m, deterministic)
+}
+func (m *GetUsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUsageResponse.Merge(m, src)
+}
+func (m *GetUsageResponse) XXX_Size() int {
+	return xxx_messageInfo_GetUsageResponse.Size(m)
+}
+func (m *GetUsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUsageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUsageResponse proto.InternalMessageInfo
+
+func (m *GetUsageResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetUsageResponse) GetUsage() []*UsageRecord {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+func (m *GetUsageResponse) GetQuota() *UsageRecord {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+type UsageRecord struct {
+	Namespace       string  `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Period          string  `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	CpuCoreHours    float64 `protobuf:"fixed64,3,opt,name=cpu_core_hours,json=cpuCoreHours,proto3" json:"cpu_core_hours,omitempty"`
+	MemoryGibHours  float64 `protobuf:"fixed64,4,opt,name=memory_gib_hours,json=memoryGibHours,proto3" json:"memory_gib_hours,omitempty"`
+	StorageGibHours float64 `protobuf:"fixed64,5,opt,name=storage_gib_hours,json=storageGibHours,proto3" json:"storage_gib_hours,omitempty"`
+	EgressBytes     int64   `protobuf:"varint,6,opt,name=egress_bytes,json=egressBytes,proto3" json:"egress_bytes,omitempty"`
+	// boost_cpu_core_hours is the portion of cpu_core_hours that was used by
+	// services while their CPU was boosted.
+	BoostCpuCoreHours    float64  `protobuf:"fixed64,7,opt,name=boost_cpu_core_hours,json=boostCpuCoreHours,proto3" json:"boost_cpu_core_hours,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsageRecord) Reset()         { *m = UsageRecord{} }
+func (m *UsageRecord) String() string { return proto.CompactTextString(m) }
+func (*UsageRecord) ProtoMessage()    {}
+func (*UsageRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{98}
+}
+
+func (m *UsageRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageRecord.Unmarshal(m, b)
+}
+func (m *UsageRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageRecord.Marshal(b, m, deterministic)
+}
+func (m *UsageRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageRecord.Merge(m, src)
+}
+func (m *UsageRecord) XXX_Size() int {
+	return xxx_messageInfo_UsageRecord.Size(m)
+}
+func (m *UsageRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageRecord proto.InternalMessageInfo
+
+func (m *UsageRecord) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *UsageRecord) GetPeriod() string {
+	if m != nil {
+		return m.Period
+	}
+	return ""
+}
+
+func (m *UsageRecord) GetCpuCoreHours() float64 {
+	if m != nil {
+		return m.CpuCoreHours
+	}
+	return 0
+}
+
+func (m *UsageRecord) GetMemoryGibHours() float64 {
+	if m != nil {
+		return m.MemoryGibHours
+	}
+	return 0
+}
+
+func (m *UsageRecord) GetStorageGibHours() float64 {
+	if m != nil {
+		return m.StorageGibHours
+	}
+	return 0
+}
+
+func (m *UsageRecord) GetEgressBytes() int64 {
+	if m != nil {
+		return m.EgressBytes
+	}
+	return 0
+}
+
+func (m *UsageRecord) GetBoostCpuCoreHours() float64 {
+	if m != nil {
+		return m.BoostCpuCoreHours
+	}
+	return 0
+}
+
+type BoostRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// cpu_multiplier is how much to scale the CPU available to each service.
+	CpuMultiplier        float64  `protobuf:"fixed64,2,opt,name=cpu_multiplier,json=cpuMultiplier,proto3" json:"cpu_multiplier,omitempty"`
+	DurationSeconds      int64    `protobuf:"varint,3,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BoostRequest) Reset()         { *m = BoostRequest{} }
+func (m *BoostRequest) String() string { return proto.CompactTextString(m) }
+func (*BoostRequest) ProtoMessage()    {}
+func (*BoostRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{99}
+}
+
+func (m *BoostRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BoostRequest.Unmarshal(m, b)
+}
+func (m *BoostRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BoostRequest.Marshal(b, m, deterministic)
+}
+func (m *BoostRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BoostRequest.Merge(m, src)
+}
+func (m *BoostRequest) XXX_Size() int {
+	return xxx_messageInfo_BoostRequest.Size(m)
+}
+func (m *BoostRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BoostRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BoostRequest proto.InternalMessageInfo
+
+func (m *BoostRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *BoostRequest) GetCpuMultiplier() float64 {
+	if m != nil {
+		return m.CpuMultiplier
+	}
+	return 0
+}
+
+func (m *BoostRequest) GetDurationSeconds() int64 {
+	if m != nil {
+		return m.DurationSeconds
+	}
+	return 0
+}
+
+type BoostResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// expires_at is when the boost ends, as a Unix timestamp.
+	ExpiresAt int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// restarted_services contains the services that were restarted to pick up
+	// the boost because they were being throttled. Other services are boosted
+	// the next time they're deployed or restarted.
+	RestartedServices    []string `protobuf:"bytes,3,rep,name=restarted_services,json=restartedServices,proto3" json:"restarted_services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BoostResponse) Reset()         { *m = BoostResponse{} }
+func (m *BoostResponse) String() string { return proto.CompactTextString(m) }
+func (*BoostResponse) ProtoMessage()    {}
+func (*BoostResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{100}
+}
+
+func (m *BoostResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BoostResponse.Unmarshal(m, b)
+}
+func (m *BoostResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BoostResponse.Marshal(b, m, deterministic)
+}
+func (m *BoostResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BoostResponse.Merge(m, src)
+}
+func (m *BoostResponse) XXX_Size() int {
+	return xxx_messageInfo_BoostResponse.Size(m)
+}
+func (m *BoostResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BoostResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BoostResponse proto.InternalMessageInfo
+
+func (m *BoostResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *BoostResponse) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *BoostResponse) GetRestartedServices() []string {
+	if m != nil {
+		return m.RestartedServices
+	}
+	return nil
+}
+
+type FaultRule struct {
+	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	DropPercent          float64  `protobuf:"fixed64,3,opt,name=drop_percent,json=dropPercent,proto3" json:"drop_percent,omitempty"`
+	DelayMs              int64    `protobuf:"varint,4,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FaultRule) Reset()         { *m = FaultRule{} }
+func (m *FaultRule) String() string { return proto.CompactTextString(m) }
+func (*FaultRule) ProtoMessage()    {}
+func (*FaultRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{101}
+}
+
+func (m *FaultRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FaultRule.Unmarshal(m, b)
+}
+func (m *FaultRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FaultRule.Marshal(b, m, deterministic)
+}
+func (m *FaultRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FaultRule.Merge(m, src)
+}
+func (m *FaultRule) XXX_Size() int {
+	return xxx_messageInfo_FaultRule.Size(m)
+}
+func (m *FaultRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_FaultRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FaultRule proto.InternalMessageInfo
+
+func (m *FaultRule) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *FaultRule) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *FaultRule) GetDropPercent() float64 {
+	if m != nil {
+		return m.DropPercent
+	}
+	return 0
+}
+
+func (m *FaultRule) GetDelayMs() int64 {
+	if m != nil {
+		return m.DelayMs
+	}
+	return 0
+}
+
+type GetFaultsRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetFaultsRequest) Reset()         { *m = GetFaultsRequest{} }
+func (m *GetFaultsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetFaultsRequest) ProtoMessage()    {}
+func (*GetFaultsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{102}
+}
+
+func (m *GetFaultsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFaultsRequest.Unmarshal(m, b)
+}
+func (m *GetFaultsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFaultsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetFaultsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFaultsRequest.Merge(m, src)
+}
+func (m *GetFaultsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetFaultsRequest.Size(m)
+}
+func (m *GetFaultsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFaultsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFaultsRequest proto.InternalMessageInfo
+
+func (m *GetFaultsRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type GetFaultsResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// compose_rules are the rules defined in the Compose file.
+	ComposeRules []*FaultRule `protobuf:"bytes,2,rep,name=compose_rules,json=composeRules,proto3" json:"compose_rules,omitempty"`
+	// runtime_rules are the rules defined with `blimp faults`. They override
+	// the Compose rules between the same services.
+	RuntimeRules         []*FaultRule `protobuf:"bytes,3,rep,name=runtime_rules,json=runtimeRules,proto3" json:"runtime_rules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetFaultsResponse) Reset()         { *m = GetFaultsResponse{} }
+func (m *GetFaultsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetFaultsResponse) ProtoMessage()    {}
+func (*GetFaultsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{103}
+}
+
+func (m *GetFaultsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFaultsResponse.Unmarshal(m, b)
+}
+func (m *GetFaultsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFaultsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetFaultsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFaultsResponse.Merge(m, src)
+}
+func (m *GetFaultsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetFaultsResponse.Size(m)
+}
+func (m *GetFaultsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFaultsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFaultsResponse proto.InternalMessageInfo
+
+func (m *GetFaultsResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetFaultsResponse) GetComposeRules() []*FaultRule {
+	if m != nil {
+		return m.ComposeRules
+	}
+	return nil
+}
+
+func (m *GetFaultsResponse) GetRuntimeRules() []*FaultRule {
+	if m != nil {
+		return m.RuntimeRules
+	}
+	return nil
+}
+
+type SetFaultsRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// runtime_rules replaces all the rules previously set with `blimp faults`.
+	RuntimeRules         []*FaultRule `protobuf:"bytes,2,rep,name=runtime_rules,json=runtimeRules,proto3" json:"runtime_rules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SetFaultsRequest) Reset()         { *m = SetFaultsRequest{} }
+func (m *SetFaultsRequest) String() string { return proto.CompactTextString(m) }
+func (*SetFaultsRequest) ProtoMessage()    {}
+func (*SetFaultsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{104}
+}
+
+func (m *SetFaultsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetFaultsRequest.Unmarshal(m, b)
+}
+func (m *SetFaultsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetFaultsRequest.Marshal(b, m, deterministic)
+}
+func (m *SetFaultsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetFaultsRequest.Merge(m, src)
+}
+func (m *SetFaultsRequest) XXX_Size() int {
+	return xxx_messageInfo_SetFaultsRequest.Size(m)
+}
+func (m *SetFaultsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetFaultsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetFaultsRequest proto.InternalMessageInfo
+
+func (m *SetFaultsRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *SetFaultsRequest) GetRuntimeRules() []*FaultRule {
+	if m != nil {
+		return m.RuntimeRules
+	}
+	return nil
+}
+
+type SetFaultsResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *SetFaultsResponse) Reset()         { *m = SetFaultsResponse{} }
+func (m *SetFaultsResponse) String() string { return proto.CompactTextString(m) }
+func (*SetFaultsResponse) ProtoMessage()    {}
+func (*SetFaultsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{105}
+}
+
+func (m *SetFaultsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetFaultsResponse.Unmarshal(m, b)
+}
+func (m *SetFaultsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetFaultsResponse.Marshal(b, m, deterministic)
+}
+func (m *SetFaultsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetFaultsResponse.Merge(m, src)
+}
+func (m *SetFaultsResponse) XXX_Size() int {
+	return xxx_messageInfo_SetFaultsResponse.Size(m)
+}
+func (m *SetFaultsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetFaultsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetFaultsResponse proto.InternalMessageInfo
+
+func (m *SetFaultsResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type GetDependencyGraphRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetDependencyGraphRequest) Reset()         { *m = GetDependencyGraphRequest{} }
+func (m *GetDependencyGraphRequest) String() string { return proto.CompactTextString(m) }
+func (*GetDependencyGraphRequest) ProtoMessage()    {}
+func (*GetDependencyGraphRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{106}
+}
+
+func (m *GetDependencyGraphRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDependencyGraphRequest.Unmarshal(m, b)
+}
+func (m *GetDependencyGraphRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDependencyGraphRequest.Marshal(b, m, deterministic)
+}
+func (m *GetDependencyGraphRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDependencyGraphRequest.Merge(m, src)
+}
+func (m *GetDependencyGraphRequest) XXX_Size() int {
+	return xxx_messageInfo_GetDependencyGraphRequest.Size(m)
+}
+func (m *GetDependencyGraphRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDependencyGraphRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDependencyGraphRequest proto.InternalMessageInfo
+
+func (m *GetDependencyGraphRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type GetDependencyGraphResponse struct {
+	Error                *errors.Error  `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Services             []*ServiceNode `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetDependencyGraphResponse) Reset()         { *m = GetDependencyGraphResponse{} }
+func (m *GetDependencyGraphResponse) String() string { return proto.CompactTextString(m) }
+func (*GetDependencyGraphResponse) ProtoMessage()    {}
+func (*GetDependencyGraphResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{107}
+}
+
+func (m *GetDependencyGraphResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDependencyGraphResponse.Unmarshal(m, b)
+}
+func (m *GetDependencyGraphResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDependencyGraphResponse.Marshal(b, m, deterministic)
+}
+func (m *GetDependencyGraphResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDependencyGraphResponse.Merge(m, src)
+}
+func (m *GetDependencyGraphResponse) XXX_Size() int {
+	return xxx_messageInfo_GetDependencyGraphResponse.Size(m)
+}
+func (m *GetDependencyGraphResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDependencyGraphResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDependencyGraphResponse proto.InternalMessageInfo
+
+func (m *GetDependencyGraphResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetDependencyGraphResponse) GetServices() []*ServiceNode {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+type ServiceNode struct {
+	Name   string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status *ServiceStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// depends_on contains the services that must be ready before this service
+	// boots, as declared by `depends_on` and `links`.
+	DependsOn            []string `protobuf:"bytes,3,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceNode) Reset()         { *m = ServiceNode{} }
+func (m *ServiceNode) String() string { return proto.CompactTextString(m) }
+func (*ServiceNode) ProtoMessage()    {}
+func (*ServiceNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{108}
+}
+
+func (m *ServiceNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceNode.Unmarshal(m, b)
+}
+func (m *ServiceNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceNode.Marshal(b, m, deterministic)
+}
+func (m *ServiceNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceNode.Merge(m, src)
+}
+func (m *ServiceNode) XXX_Size() int {
+	return xxx_messageInfo_ServiceNode.Size(m)
+}
+func (m *ServiceNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceNode proto.InternalMessageInfo
+
+func (m *ServiceNode) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ServiceNode) GetStatus() *ServiceStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *ServiceNode) GetDependsOn() []string {
+	if m != nil {
+		return m.DependsOn
+	}
+	return nil
+}
+
+type CreateSnapshotRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// compose_file is the Compose file that the sandbox was deployed with, as
+	// serialized by `compose.Marshal`.
+	ComposeFile string `protobuf:"bytes,2,opt,name=compose_file,json=composeFile,proto3" json:"compose_file,omitempty"`
+	// registry_credentials are used to copy the images of the running services
+	// into the snapshot.
+	RegistryCredentials  map[string]*RegistryCredential `protobuf:"bytes,3,rep,name=registry_credentials,json=registryCredentials,proto3" json:"registry_credentials,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
+}
+
+func (m *CreateSnapshotRequest) Reset()         { *m = CreateSnapshotRequest{} }
+func (m *CreateSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateSnapshotRequest) ProtoMessage()    {}
+func (*CreateSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{109}
+}
+
+func (m *CreateSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSnapshotRequest.Unmarshal(m, b)
+}
+func (m *CreateSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSnapshotRequest.Merge(m, src)
+}
+func (m *CreateSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateSnapshotRequest.Size(m)
+}
+func (m *CreateSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSnapshotRequest proto.InternalMessageInfo
+
+func (m *CreateSnapshotRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *CreateSnapshotRequest) GetComposeFile() string {
+	if m != nil {
+		return m.ComposeFile
+	}
+	return ""
+}
+
+func (m *CreateSnapshotRequest) GetRegistryCredentials() map[string]*RegistryCredential {
+	if m != nil {
+		return m.RegistryCredentials
+	}
+	return nil
+}
+
+type CreateSnapshotResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// id can be shared with other users so that they can boot the snapshot.
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSnapshotResponse) Reset()         { *m = CreateSnapshotResponse{} }
+func (m *CreateSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateSnapshotResponse) ProtoMessage()    {}
+func (*CreateSnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{110}
+}
+
+func (m *CreateSnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSnapshotResponse.Unmarshal(m, b)
+}
+func (m *CreateSnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateSnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSnapshotResponse.Merge(m, src)
+}
+func (m *CreateSnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateSnapshotResponse.Size(m)
+}
+func (m *CreateSnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSnapshotResponse proto.InternalMessageInfo
+
+func (m *CreateSnapshotResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *CreateSnapshotResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type GetSnapshotRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Id                   string          `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetSnapshotRequest) Reset()         { *m = GetSnapshotRequest{} }
+func (m *GetSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSnapshotRequest) ProtoMessage()    {}
+func (*GetSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{111}
+}
+
+func (m *GetSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSnapshotRequest.Unmarshal(m, b)
+}
+func (m *GetSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *GetSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSnapshotRequest.Merge(m, src)
+}
+func (m *GetSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_GetSnapshotRequest.Size(m)
+}
+func (m *GetSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSnapshotRequest proto.InternalMessageInfo
+
+func (m *GetSnapshotRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *GetSnapshotRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type GetSnapshotResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// compose_file and built_images should be passed to DeployToSandbox once
+	// the snapshot's volumes have been restored with BootSnapshot.
+	ComposeFile string            `protobuf:"bytes,2,opt,name=compose_file,json=composeFile,proto3" json:"compose_file,omitempty"`
+	BuiltImages map[string]string `protobuf:"bytes,3,rep,name=built_images,json=builtImages,proto3" json:"built_images,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// created_at is when the snapshot was created, as a Unix timestamp.
+	CreatedAt            int64    `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetSnapshotResponse) Reset()         { *m = GetSnapshotResponse{} }
+func (m *GetSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*GetSnapshotResponse) ProtoMessage()    {}
+func (*GetSnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{112}
+}
+
+func (m *GetSnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSnapshotResponse.Unmarshal(m, b)
+}
+func (m *GetSnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *GetSnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSnapshotResponse.Merge(m, src)
+}
+func (m *GetSnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_GetSnapshotResponse.Size(m)
+}
+func (m *GetSnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSnapshotResponse proto.InternalMessageInfo
+
+func (m *GetSnapshotResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetSnapshotResponse) GetComposeFile() string {
+	if m != nil {
+		return m.ComposeFile
+	}
+	return ""
+}
+
+func (m *GetSnapshotResponse) GetBuiltImages() map[string]string {
+	if m != nil {
+		return m.BuiltImages
+	}
+	return nil
+}
+
+func (m *GetSnapshotResponse) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type BootSnapshotRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Id                   string          `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *BootSnapshotRequest) Reset()         { *m = BootSnapshotRequest{} }
+func (m *BootSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*BootSnapshotRequest) ProtoMessage()    {}
+func (*BootSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{113}
+}
+
+func (m *BootSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BootSnapshotRequest.Unmarshal(m, b)
+}
+func (m *BootSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BootSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *BootSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BootSnapshotRequest.Merge(m, src)
+}
+func (m *BootSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_BootSnapshotRequest.Size(m)
+}
+func (m *BootSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BootSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BootSnapshotRequest proto.InternalMessageInfo
+
+func (m *BootSnapshotRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *BootSnapshotRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type BootSnapshotResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *BootSnapshotResponse) Reset()         { *m = BootSnapshotResponse{} }
+func (m *BootSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*BootSnapshotResponse) ProtoMessage()    {}
+func (*BootSnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{114}
+}
+
+func (m *BootSnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BootSnapshotResponse.Unmarshal(m, b)
+}
+func (m *BootSnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BootSnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *BootSnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BootSnapshotResponse.Merge(m, src)
+}
+func (m *BootSnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_BootSnapshotResponse.Size(m)
+}
+func (m *BootSnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BootSnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BootSnapshotResponse proto.InternalMessageInfo
+
+func (m *BootSnapshotResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type GetLogsRequest struct {
+	Auth    *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Service string          `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	// since is the Unix timestamp in nanoseconds of the oldest line to return.
+	// If it's zero, all retained lines are returned.
+	Since int64 `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	// tail is the maximum number of lines to return, starting from the most
+	// recent line. If it's zero, the number of lines isn't limited.
+	Tail                 int32    `protobuf:"varint,4,opt,name=tail,proto3" json:"tail,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLogsRequest) Reset()         { *m = GetLogsRequest{} }
+func (m *GetLogsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetLogsRequest) ProtoMessage()    {}
+func (*GetLogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{115}
+}
+
+func (m *GetLogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLogsRequest.Unmarshal(m, b)
+}
+func (m *GetLogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLogsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetLogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLogsRequest.Merge(m, src)
+}
+func (m *GetLogsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetLogsRequest.Size(m)
+}
+func (m *GetLogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLogsRequest proto.InternalMessageInfo
+
+func (m *GetLogsRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *GetLogsRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *GetLogsRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *GetLogsRequest) GetTail() int32 {
+	if m != nil {
+		return m.Tail
+	}
+	return 0
+}
+
+// StreamLogs splits the lines into multiple GetLogsResponses so that each
+// response fits within the client's maximum message size.
+type GetLogsResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// buffered is false if the manager doesn't retain logs. Clients should
+	// read the logs from the service's container instead.
+	Buffered bool `protobuf:"varint,2,opt,name=buffered,proto3" json:"buffered,omitempty"`
+	// lines are sorted from oldest to newest. They include the logs from
+	// before the service's container restarted.
+	Lines                []*LogLine `protobuf:"bytes,3,rep,name=lines,proto3" json:"lines,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetLogsResponse) Reset()         { *m = GetLogsResponse{} }
+func (m *GetLogsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetLogsResponse) ProtoMessage()    {}
+func (*GetLogsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{116}
+}
+
+func (m *GetLogsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLogsResponse.Unmarshal(m, b)
+}
+func (m *GetLogsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLogsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetLogsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLogsResponse.Merge(m, src)
+}
+func (m *GetLogsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetLogsResponse.Size(m)
+}
+func (m *GetLogsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLogsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLogsResponse proto.InternalMessageInfo
+
+func (m *GetLogsResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetLogsResponse) GetBuffered() bool {
+	if m != nil {
+		return m.Buffered
+	}
+	return false
+}
+
+func (m *GetLogsResponse) GetLines() []*LogLine {
+	if m != nil {
+		return m.Lines
+	}
+	return nil
+}
+
+type LogLine struct {
+	// time is the Unix timestamp in nanoseconds of when the line was logged.
+	Time                 int64    `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogLine) Reset()         { *m = LogLine{} }
+func (m *LogLine) String() string { return proto.CompactTextString(m) }
+func (*LogLine) ProtoMessage()    {}
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{117}
+}
+
+func (m *LogLine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogLine.Unmarshal(m, b)
+}
+func (m *LogLine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogLine.Marshal(b, m, deterministic)
+}
+func (m *LogLine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogLine.Merge(m, src)
+}
+func (m *LogLine) XXX_Size() int {
+	return xxx_messageInfo_LogLine.Size(m)
+}
+func (m *LogLine) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogLine.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogLine proto.InternalMessageInfo
+
+func (m *LogLine) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *LogLine) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type WatchEventsRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// services limits the events to the given services. If it's empty, the
+	// events of all services are sent.
+	Services             []string `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchEventsRequest) Reset()         { *m = WatchEventsRequest{} }
+func (m *WatchEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchEventsRequest) ProtoMessage()    {}
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{118}
+}
+
+func (m *WatchEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchEventsRequest.Unmarshal(m, b)
+}
+func (m *WatchEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchEventsRequest.Merge(m, src)
+}
+func (m *WatchEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchEventsRequest.Size(m)
+}
+func (m *WatchEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchEventsRequest proto.InternalMessageInfo
+
+func (m *WatchEventsRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *WatchEventsRequest) GetServices() []string {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+type WatchEventsResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// events are the events that happened since the last response, sorted by
+	// when they happened. Only events that happen after the watch starts are
+	// sent.
+	Events               []*LifecycleEvent `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *WatchEventsResponse) Reset()         { *m = WatchEventsResponse{} }
+func (m *WatchEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchEventsResponse) ProtoMessage()    {}
+func (*WatchEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{119}
+}
+
+func (m *WatchEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchEventsResponse.Unmarshal(m, b)
+}
+func (m *WatchEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchEventsResponse.Merge(m, src)
+}
+func (m *WatchEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchEventsResponse.Size(m)
+}
+func (m *WatchEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchEventsResponse proto.InternalMessageInfo
+
+func (m *WatchEventsResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *WatchEventsResponse) GetEvents() []*LifecycleEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type LifecycleEvent struct {
+	Type    LifecycleEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=blimp.cluster.v0.LifecycleEvent_Type" json:"type,omitempty"`
+	Service string              `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	// time is the Unix timestamp of when the event happened.
+	Time int64 `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	// message is a human-readable description of the event, such as the
+	// image that's being pulled.
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// exit_code is only set for events about the container exiting.
+	ExitCode             int32    `protobuf:"varint,5,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LifecycleEvent) Reset()         { *m = LifecycleEvent{} }
+func (m *LifecycleEvent) String() string { return proto.CompactTextString(m) }
+func (*LifecycleEvent) ProtoMessage()    {}
+func (*LifecycleEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{120}
+}
+
+func (m *LifecycleEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LifecycleEvent.Unmarshal(m, b)
+}
+func (m *LifecycleEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LifecycleEvent.Marshal(b, m, deterministic)
+}
+func (m *LifecycleEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LifecycleEvent.Merge(m, src)
+}
+func (m *LifecycleEvent) XXX_Size() int {
+	return xxx_messageInfo_LifecycleEvent.Size(m)
+}
+func (m *LifecycleEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_LifecycleEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LifecycleEvent proto.InternalMessageInfo
+
+func (m *LifecycleEvent) GetType() LifecycleEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return LifecycleEvent_UNKNOWN
+}
+
+func (m *LifecycleEvent) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *LifecycleEvent) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *LifecycleEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *LifecycleEvent) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
+}
+
+type GetImageScansRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetImageScansRequest) Reset()         { *m = GetImageScansRequest{} }
+func (m *GetImageScansRequest) String() string { return proto.CompactTextString(m) }
+func (*GetImageScansRequest) ProtoMessage()    {}
+func (*GetImageScansRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{121}
+}
+
+func (m *GetImageScansRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetImageScansRequest.Unmarshal(m, b)
+}
+func (m *GetImageScansRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetImageScansRequest.Marshal(b, m, deterministic)
+}
+func (m *GetImageScansRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetImageScansRequest.Merge(m, src)
+}
+func (m *GetImageScansRequest) XXX_Size() int {
+	return xxx_messageInfo_GetImageScansRequest.Size(m)
+}
+func (m *GetImageScansRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetImageScansRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetImageScansRequest proto.InternalMessageInfo
+
+func (m *GetImageScansRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type GetImageScansResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// scans contains the most recent scan of each service's image.
+	Scans []*ImageScan `protobuf:"bytes,2,rep,name=scans,proto3" json:"scans,omitempty"`
+	// block_severity is the severity at or above which vulnerabilities block
+	// deployments. It's empty if deployments are never blocked.
+	BlockSeverity        string   `protobuf:"bytes,3,opt,name=block_severity,json=blockSeverity,proto3" json:"block_severity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetImageScansResponse) Reset()         { *m = GetImageScansResponse{} }
+func (m *GetImageScansResponse) String() string { return proto.CompactTextString(m) }
+func (*GetImageScansResponse) ProtoMessage()    {}
+func (*GetImageScansResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{122}
+}
+
+func (m *GetImageScansResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetImageScansResponse.Unmarshal(m, b)
+}
+func (m *GetImageScansResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetImageScansResponse.Marshal(b, m, deterministic)
+}
+func (m *GetImageScansResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetImageScansResponse.Merge(m, src)
+}
+func (m *GetImageScansResponse) XXX_Size() int {
+	return xxx_messageInfo_GetImageScansResponse.Size(m)
+}
+func (m *GetImageScansResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetImageScansResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetImageScansResponse proto.InternalMessageInfo
+
+func (m *GetImageScansResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *GetImageScansResponse) GetScans() []*ImageScan {
+	if m != nil {
+		return m.Scans
+	}
+	return nil
+}
+
+func (m *GetImageScansResponse) GetBlockSeverity() string {
+	if m != nil {
+		return m.BlockSeverity
+	}
+	return ""
+}
+
+type ImageScan struct {
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Image   string `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	// scanned_at is the Unix timestamp of when the image was scanned.
+	ScannedAt int64 `protobuf:"varint,3,opt,name=scanned_at,json=scannedAt,proto3" json:"scanned_at,omitempty"`
+	// error is set if the image couldn't be scanned.
+	Error                string           `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Vulnerabilities      []*Vulnerability `protobuf:"bytes,5,rep,name=vulnerabilities,proto3" json:"vulnerabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ImageScan) Reset()         { *m = ImageScan{} }
+func (m *ImageScan) String() string { return proto.CompactTextString(m) }
+func (*ImageScan) ProtoMessage()    {}
+func (*ImageScan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{123}
+}
+
+func (m *ImageScan) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImageScan.Unmarshal(m, b)
+}
+func (m *ImageScan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImageScan.Marshal(b, m, deterministic)
+}
+func (m *ImageScan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImageScan.Merge(m, src)
+}
+func (m *ImageScan) XXX_Size() int {
+	return xxx_messageInfo_ImageScan.Size(m)
+}
+func (m *ImageScan) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImageScan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImageScan proto.InternalMessageInfo
+
+func (m *ImageScan) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *ImageScan) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *ImageScan) GetScannedAt() int64 {
+	if m != nil {
+		return m.ScannedAt
+	}
+	return 0
+}
+
+func (m *ImageScan) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ImageScan) GetVulnerabilities() []*Vulnerability {
+	if m != nil {
+		return m.Vulnerabilities
+	}
+	return nil
+}
+
+type Vulnerability struct {
+	Id               string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Package          string `protobuf:"bytes,2,opt,name=package,proto3" json:"package,omitempty"`
+	InstalledVersion string `protobuf:"bytes,3,opt,name=installed_version,json=installedVersion,proto3" json:"installed_version,omitempty"`
+	FixedVersion     string `protobuf:"bytes,4,opt,name=fixed_version,json=fixedVersion,proto3" json:"fixed_version,omitempty"`
+	// severity is UNKNOWN, LOW, MEDIUM, HIGH, or CRITICAL.
+	Severity             string   `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
+	Title                string   `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Vulnerability) Reset()         { *m = Vulnerability{} }
+func (m *Vulnerability) String() string { return proto.CompactTextString(m) }
+func (*Vulnerability) ProtoMessage()    {}
+func (*Vulnerability) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{124}
+}
+
+func (m *Vulnerability) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Vulnerability.Unmarshal(m, b)
+}
+func (m *Vulnerability) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Vulnerability.Marshal(b, m, deterministic)
+}
+func (m *Vulnerability) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Vulnerability.Merge(m, src)
+}
+func (m *Vulnerability) XXX_Size() int {
+	return xxx_messageInfo_Vulnerability.Size(m)
+}
+func (m *Vulnerability) XXX_DiscardUnknown() {
+	xxx_messageInfo_Vulnerability.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Vulnerability proto.InternalMessageInfo
+
+func (m *Vulnerability) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Vulnerability) GetPackage() string {
+	if m != nil {
+		return m.Package
+	}
+	return ""
+}
+
+func (m *Vulnerability) GetInstalledVersion() string {
+	if m != nil {
+		return m.InstalledVersion
+	}
+	return ""
+}
+
+func (m *Vulnerability) GetFixedVersion() string {
+	if m != nil {
+		return m.FixedVersion
+	}
+	return ""
+}
+
+func (m *Vulnerability) GetSeverity() string {
+	if m != nil {
+		return m.Severity
+	}
+	return ""
+}
+
+func (m *Vulnerability) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+type CreateShareLinkRequest struct {
+	Auth *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	// ports are the services whose preview URLs are shared.
+	Ports []*SharedPort `protobuf:"bytes,2,rep,name=ports,proto3" json:"ports,omitempty"`
+	// scopes are the read-only RPCs that the guest token has access to, such
+	// as "status" and "logs".
+	Scopes []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// expires_in is how long the link is valid for, in seconds.
+	ExpiresIn            int64    `protobuf:"varint,4,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateShareLinkRequest) Reset()         { *m = CreateShareLinkRequest{} }
+func (m *CreateShareLinkRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateShareLinkRequest) ProtoMessage()    {}
+func (*CreateShareLinkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{125}
+}
+
+func (m *CreateShareLinkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateShareLinkRequest.Unmarshal(m, b)
+}
+func (m *CreateShareLinkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateShareLinkRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateShareLinkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateShareLinkRequest.Merge(m, src)
+}
+func (m *CreateShareLinkRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateShareLinkRequest.Size(m)
+}
+func (m *CreateShareLinkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateShareLinkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateShareLinkRequest proto.InternalMessageInfo
+
+func (m *CreateShareLinkRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *CreateShareLinkRequest) GetPorts() []*SharedPort {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+func (m *CreateShareLinkRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *CreateShareLinkRequest) GetExpiresIn() int64 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+type SharedPort struct {
+	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Port                 uint32   `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SharedPort) Reset()         { *m = SharedPort{} }
+func (m *SharedPort) String() string { return proto.CompactTextString(m) }
+func (*SharedPort) ProtoMessage()    {}
+func (*SharedPort) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{126}
+}
+
+func (m *SharedPort) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SharedPort.Unmarshal(m, b)
+}
+func (m *SharedPort) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SharedPort.Marshal(b, m, deterministic)
+}
+func (m *SharedPort) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SharedPort.Merge(m, src)
+}
+func (m *SharedPort) XXX_Size() int {
+	return xxx_messageInfo_SharedPort.Size(m)
+}
+func (m *SharedPort) XXX_DiscardUnknown() {
+	xxx_messageInfo_SharedPort.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SharedPort proto.InternalMessageInfo
+
+func (m *SharedPort) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *SharedPort) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+type CreateShareLinkResponse struct {
+	Error *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// token is a signed guest token that grants the requested scopes. It's
+	// empty if no scopes were requested.
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	// links are the preview URLs for the shared ports, in the same order as
+	// the request.
+	Links []string `protobuf:"bytes,3,rep,name=links,proto3" json:"links,omitempty"`
+	// expires_at is the Unix timestamp of when the token and links expire.
+	ExpiresAt            int64    `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateShareLinkResponse) Reset()         { *m = CreateShareLinkResponse{} }
+func (m *CreateShareLinkResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateShareLinkResponse) ProtoMessage()    {}
+func (*CreateShareLinkResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{127}
+}
+
+func (m *CreateShareLinkResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateShareLinkResponse.Unmarshal(m, b)
+}
+func (m *CreateShareLinkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateShareLinkResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateShareLinkResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateShareLinkResponse.Merge(m, src)
+}
+func (m *CreateShareLinkResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateShareLinkResponse.Size(m)
+}
+func (m *CreateShareLinkResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateShareLinkResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateShareLinkResponse proto.InternalMessageInfo
+
+func (m *CreateShareLinkResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *CreateShareLinkResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *CreateShareLinkResponse) GetLinks() []string {
+	if m != nil {
+		return m.Links
+	}
+	return nil
+}
+
+func (m *CreateShareLinkResponse) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+// LookupNamespaceRequest looks up the owner of a namespace, or the namespace
+// of a user. All namespaces are returned if neither is set.
+type LookupNamespaceRequest struct {
+	AdminToken           string   `protobuf:"bytes,1,opt,name=admin_token,json=adminToken,proto3" json:"admin_token,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	User                 string   `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LookupNamespaceRequest) Reset()         { *m = LookupNamespaceRequest{} }
+func (m *LookupNamespaceRequest) String() string { return proto.CompactTextString(m) }
+func (*LookupNamespaceRequest) ProtoMessage()    {}
+func (*LookupNamespaceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{128}
+}
+
+func (m *LookupNamespaceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LookupNamespaceRequest.Unmarshal(m, b)
+}
+func (m *LookupNamespaceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LookupNamespaceRequest.Marshal(b, m, deterministic)
+}
+func (m *LookupNamespaceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LookupNamespaceRequest.Merge(m, src)
+}
+func (m *LookupNamespaceRequest) XXX_Size() int {
+	return xxx_messageInfo_LookupNamespaceRequest.Size(m)
+}
+func (m *LookupNamespaceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LookupNamespaceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LookupNamespaceRequest proto.InternalMessageInfo
+
+func (m *LookupNamespaceRequest) GetAdminToken() string {
+	if m != nil {
+		return m.AdminToken
+	}
+	return ""
+}
+
+func (m *LookupNamespaceRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *LookupNamespaceRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type LookupNamespaceResponse struct {
+	Error                *errors.Error      `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Records              []*NamespaceRecord `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *LookupNamespaceResponse) Reset()         { *m = LookupNamespaceResponse{} }
+func (m *LookupNamespaceResponse) String() string { return proto.CompactTextString(m) }
+func (*LookupNamespaceResponse) ProtoMessage()    {}
+func (*LookupNamespaceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{129}
+}
+
+func (m *LookupNamespaceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LookupNamespaceResponse.Unmarshal(m, b)
+}
+func (m *LookupNamespaceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LookupNamespaceResponse.Marshal(b, m, deterministic)
+}
+func (m *LookupNamespaceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LookupNamespaceResponse.Merge(m, src)
+}
+func (m *LookupNamespaceResponse) XXX_Size() int {
+	return xxx_messageInfo_LookupNamespaceResponse.Size(m)
+}
+func (m *LookupNamespaceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LookupNamespaceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LookupNamespaceResponse proto.InternalMessageInfo
+
+func (m *LookupNamespaceResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *LookupNamespaceResponse) GetRecords() []*NamespaceRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type NamespaceRecord struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Owner     string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// claimed_at is the Unix timestamp of when the owner claimed the namespace.
+	ClaimedAt int64 `protobuf:"varint,3,opt,name=claimed_at,json=claimedAt,proto3" json:"claimed_at,omitempty"`
+	// rejected_users are the other users whose names map to the namespace.
+	RejectedUsers        []string `protobuf:"bytes,4,rep,name=rejected_users,json=rejectedUsers,proto3" json:"rejected_users,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NamespaceRecord) Reset()         { *m = NamespaceRecord{} }
+func (m *NamespaceRecord) String() string { return proto.CompactTextString(m) }
+func (*NamespaceRecord) ProtoMessage()    {}
+func (*NamespaceRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d156d5389f4d1cd6, []int{130}
+}
+
+func (m *NamespaceRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NamespaceRecord.Unmarshal(m, b)
+}
+func (m *NamespaceRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NamespaceRecord.Marshal(b, m, deterministic)
+}
+func (m *NamespaceRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceRecord.Merge(m, src)
+}
+func (m *NamespaceRecord) XXX_Size() int {
+	return xxx_messageInfo_NamespaceRecord.Size(m)
+}
+func (m *NamespaceRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceRecord proto.InternalMessageInfo
+
+func (m *NamespaceRecord) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *NamespaceRecord) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *NamespaceRecord) GetClaimedAt() int64 {
+	if m != nil {
+		return m.ClaimedAt
+	}
+	return 0
+}
+
+func (m *NamespaceRecord) GetRejectedUsers() []string {
+	if m != nil {
+		return m.RejectedUsers
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("blimp.cluster.v0.CLIAction", CLIAction_name, CLIAction_value)
+	proto.RegisterEnum("blimp.cluster.v0.ServicePhase", ServicePhase_name, ServicePhase_value)
+	proto.RegisterEnum("blimp.cluster.v0.SandboxStatus_SandboxPhase", SandboxStatus_SandboxPhase_name, SandboxStatus_SandboxPhase_value)
+	proto.RegisterEnum("blimp.cluster.v0.VolumeInitStatus_State", VolumeInitStatus_State_name, VolumeInitStatus_State_value)
+	proto.RegisterEnum("blimp.cluster.v0.LifecycleEvent_Type", LifecycleEvent_Type_name, LifecycleEvent_Type_value)
+	proto.RegisterType((*CheckVersionRequest)(nil), "blimp.cluster.v0.CheckVersionRequest")
+	proto.RegisterType((*CheckVersionResponse)(nil), "blimp.cluster.v0.CheckVersionResponse")
+	proto.RegisterType((*CreateSandboxRequest)(nil), "blimp.cluster.v0.CreateSandboxRequest")
+	proto.RegisterMapType((map[string]*RegistryCredential)(nil), "blimp.cluster.v0.CreateSandboxRequest.RegistryCredentialsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.CreateSandboxRequest.SyncedFoldersEntry")
+	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.CreateSandboxRequest.SyncFolderOwnersEntry")
+	proto.RegisterMapType((map[string]*FilePatterns)(nil), "blimp.cluster.v0.CreateSandboxRequest.LineEndingRulesEntry")
+	proto.RegisterType((*FilePatterns)(nil), "blimp.cluster.v0.FilePatterns")
+	proto.RegisterType((*RegistryCredential)(nil), "blimp.cluster.v0.RegistryCredential")
+	proto.RegisterType((*AttachToSandboxRequest)(nil), "blimp.cluster.v0.AttachToSandboxRequest")
+	proto.RegisterType((*AttachToSandboxResponse)(nil), "blimp.cluster.v0.AttachToSandboxResponse")
+	proto.RegisterType((*CreateSandboxResponse)(nil), "blimp.cluster.v0.CreateSandboxResponse")
+	proto.RegisterType((*DeployRequest)(nil), "blimp.cluster.v0.DeployRequest")
+	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.DeployRequest.BuiltImagesEntry")
+	proto.RegisterType((*DeployResponse)(nil), "blimp.cluster.v0.DeployResponse")
+	proto.RegisterType((*KubeCredentials)(nil), "blimp.cluster.v0.KubeCredentials")
+	proto.RegisterType((*DeleteSandboxRequest)(nil), "blimp.cluster.v0.DeleteSandboxRequest")
+	proto.RegisterType((*DeleteSandboxResponse)(nil), "blimp.cluster.v0.DeleteSandboxResponse")
+	proto.RegisterType((*PauseSandboxRequest)(nil), "blimp.cluster.v0.PauseSandboxRequest")
+	proto.RegisterType((*PauseSandboxResponse)(nil), "blimp.cluster.v0.PauseSandboxResponse")
+	proto.RegisterType((*ResumeSandboxRequest)(nil), "blimp.cluster.v0.ResumeSandboxRequest")
+	proto.RegisterType((*ResumeSandboxResponse)(nil), "blimp.cluster.v0.ResumeSandboxResponse")
+	proto.RegisterType((*GetStatusRequest)(nil), "blimp.cluster.v0.GetStatusRequest")
+	proto.RegisterType((*GetStatusResponse)(nil), "blimp.cluster.v0.GetStatusResponse")
+	proto.RegisterType((*GetStatusesRequest)(nil), "blimp.cluster.v0.GetStatusesRequest")
+	proto.RegisterType((*GetStatusesResponse)(nil), "blimp.cluster.v0.GetStatusesResponse")
+	proto.RegisterMapType((map[string]*SandboxStatus)(nil), "blimp.cluster.v0.GetStatusesResponse.StatusesEntry")
+	proto.RegisterType((*PollStatusRequest)(nil), "blimp.cluster.v0.PollStatusRequest")
+	proto.RegisterType((*PollStatusResponse)(nil), "blimp.cluster.v0.PollStatusResponse")
+	proto.RegisterMapType((map[string]*ServiceStatus)(nil), "blimp.cluster.v0.PollStatusResponse.ServicesEntry")
+	proto.RegisterType((*SandboxStatus)(nil), "blimp.cluster.v0.SandboxStatus")
+	proto.RegisterMapType((map[string]*ServiceStatus)(nil), "blimp.cluster.v0.SandboxStatus.ServicesEntry")
+	proto.RegisterType((*ServiceStatus)(nil), "blimp.cluster.v0.ServiceStatus")
+	proto.RegisterType((*VolumeInitStatus)(nil), "blimp.cluster.v0.VolumeInitStatus")
+	proto.RegisterType((*MeshStatus)(nil), "blimp.cluster.v0.MeshStatus")
+	proto.RegisterType((*RestartRequest)(nil), "blimp.cluster.v0.RestartRequest")
+	proto.RegisterType((*RestartResponse)(nil), "blimp.cluster.v0.RestartResponse")
+	proto.RegisterType((*TagImageRequest)(nil), "blimp.cluster.v0.TagImageRequest")
+	proto.RegisterType((*TagImagesRequest)(nil), "blimp.cluster.v0.TagImagesRequest")
+	proto.RegisterMapType((map[string]*RegistryCredential)(nil), "blimp.cluster.v0.TagImagesRequest.RegistryCredentialsEntry")
+	proto.RegisterType((*TagImagesResponse)(nil), "blimp.cluster.v0.TagImagesResponse")
+	proto.RegisterType((*ExposeRequest)(nil), "blimp.cluster.v0.ExposeRequest")
+	proto.RegisterType((*ExposeResponse)(nil), "blimp.cluster.v0.ExposeResponse")
+	proto.RegisterType((*UnexposeRequest)(nil), "blimp.cluster.v0.UnexposeRequest")
+	proto.RegisterType((*UnexposeResponse)(nil), "blimp.cluster.v0.UnexposeResponse")
+	proto.RegisterType((*GetImageNamespaceRequest)(nil), "blimp.cluster.v0.GetImageNamespaceRequest")
+	proto.RegisterType((*GetImageNamespaceResponse)(nil), "blimp.cluster.v0.GetImageNamespaceResponse")
+	proto.RegisterType((*GetBuildkitRequest)(nil), "blimp.cluster.v0.GetBuildkitRequest")
+	proto.RegisterType((*GetBuildkitResponse)(nil), "blimp.cluster.v0.GetBuildkitResponse")
+	proto.RegisterType((*BlimpUpPreviewRequest)(nil), "blimp.cluster.v0.BlimpUpPreviewRequest")
+	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.BlimpUpPreviewRequest.EnvEntry")
+	proto.RegisterType((*BlimpUpPreviewResponse)(nil), "blimp.cluster.v0.BlimpUpPreviewResponse")
+	proto.RegisterType((*PullRequest)(nil), "blimp.cluster.v0.PullRequest")
+	proto.RegisterType((*CreatePreviewRequest)(nil), "blimp.cluster.v0.CreatePreviewRequest")
+	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.CreatePreviewRequest.EnvEntry")
+	proto.RegisterType((*CreatePreviewResponse)(nil), "blimp.cluster.v0.CreatePreviewResponse")
+	proto.RegisterType((*DeletePreviewRequest)(nil), "blimp.cluster.v0.DeletePreviewRequest")
+	proto.RegisterType((*DeletePreviewResponse)(nil), "blimp.cluster.v0.DeletePreviewResponse")
+	proto.RegisterType((*Template)(nil), "blimp.cluster.v0.Template")
+	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.Template.FilesEntry")
+	proto.RegisterType((*ListTemplatesRequest)(nil), "blimp.cluster.v0.ListTemplatesRequest")
+	proto.RegisterType((*ListTemplatesResponse)(nil), "blimp.cluster.v0.ListTemplatesResponse")
+	proto.RegisterType((*GetTemplateRequest)(nil), "blimp.cluster.v0.GetTemplateRequest")
+	proto.RegisterType((*GetTemplateResponse)(nil), "blimp.cluster.v0.GetTemplateResponse")
+	proto.RegisterType((*Addon)(nil), "blimp.cluster.v0.Addon")
+	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.Addon.EnvEntry")
+	proto.RegisterType((*CreateAddonRequest)(nil), "blimp.cluster.v0.CreateAddonRequest")
+	proto.RegisterType((*CreateAddonResponse)(nil), "blimp.cluster.v0.CreateAddonResponse")
+	proto.RegisterType((*DeleteAddonRequest)(nil), "blimp.cluster.v0.DeleteAddonRequest")
+	proto.RegisterType((*DeleteAddonResponse)(nil), "blimp.cluster.v0.DeleteAddonResponse")
+	proto.RegisterType((*ListAddonsRequest)(nil), "blimp.cluster.v0.ListAddonsRequest")
+	proto.RegisterType((*ListAddonsResponse)(nil), "blimp.cluster.v0.ListAddonsResponse")
+	proto.RegisterType((*SnapshotAddonRequest)(nil), "blimp.cluster.v0.SnapshotAddonRequest")
+	proto.RegisterType((*SnapshotAddonResponse)(nil), "blimp.cluster.v0.SnapshotAddonResponse")
+	proto.RegisterType((*RestoreAddonRequest)(nil), "blimp.cluster.v0.RestoreAddonRequest")
+	proto.RegisterType((*RestoreAddonResponse)(nil), "blimp.cluster.v0.RestoreAddonResponse")
+	proto.RegisterType((*SandboxEnvVar)(nil), "blimp.cluster.v0.SandboxEnvVar")
+	proto.RegisterType((*ListSandboxEnvRequest)(nil), "blimp.cluster.v0.ListSandboxEnvRequest")
+	proto.RegisterType((*ListSandboxEnvResponse)(nil), "blimp.cluster.v0.ListSandboxEnvResponse")
+	proto.RegisterType((*SetSandboxEnvRequest)(nil), "blimp.cluster.v0.SetSandboxEnvRequest")
+	proto.RegisterType((*SetSandboxEnvResponse)(nil), "blimp.cluster.v0.SetSandboxEnvResponse")
+	proto.RegisterType((*UnsetSandboxEnvRequest)(nil), "blimp.cluster.v0.UnsetSandboxEnvRequest")
+	proto.RegisterType((*UnsetSandboxEnvResponse)(nil), "blimp.cluster.v0.UnsetSandboxEnvResponse")
+	proto.RegisterType((*ScopedToken)(nil), "blimp.cluster.v0.ScopedToken")
+	proto.RegisterType((*CreateTokenRequest)(nil), "blimp.cluster.v0.CreateTokenRequest")
+	proto.RegisterType((*CreateTokenResponse)(nil), "blimp.cluster.v0.CreateTokenResponse")
+	proto.RegisterType((*ListTokensRequest)(nil), "blimp.cluster.v0.ListTokensRequest")
+	proto.RegisterType((*ListTokensResponse)(nil), "blimp.cluster.v0.ListTokensResponse")
+	proto.RegisterType((*RevokeTokenRequest)(nil), "blimp.cluster.v0.RevokeTokenRequest")
+	proto.RegisterType((*RevokeTokenResponse)(nil), "blimp.cluster.v0.RevokeTokenResponse")
+	proto.RegisterType((*SandboxInfo)(nil), "blimp.cluster.v0.SandboxInfo")
+	proto.RegisterType((*ListSandboxesRequest)(nil), "blimp.cluster.v0.ListSandboxesRequest")
+	proto.RegisterType((*ListSandboxesResponse)(nil), "blimp.cluster.v0.ListSandboxesResponse")
+	proto.RegisterType((*GetDiagnosticsRequest)(nil), "blimp.cluster.v0.GetDiagnosticsRequest")
+	proto.RegisterType((*GetDiagnosticsResponse)(nil), "blimp.cluster.v0.GetDiagnosticsResponse")
+	proto.RegisterType((*StatusSnapshot)(nil), "blimp.cluster.v0.StatusSnapshot")
+	proto.RegisterType((*PodDiagnostics)(nil), "blimp.cluster.v0.PodDiagnostics")
+	proto.RegisterType((*ContainerDiagnostics)(nil), "blimp.cluster.v0.ContainerDiagnostics")
+	proto.RegisterType((*SandboxEvent)(nil), "blimp.cluster.v0.SandboxEvent")
+	proto.RegisterType((*DescribeSandboxRequest)(nil), "blimp.cluster.v0.DescribeSandboxRequest")
+	proto.RegisterType((*DescribeSandboxResponse)(nil), "blimp.cluster.v0.DescribeSandboxResponse")
+	proto.RegisterType((*EvictSandboxRequest)(nil), "blimp.cluster.v0.EvictSandboxRequest")
+	proto.RegisterType((*EvictSandboxResponse)(nil), "blimp.cluster.v0.EvictSandboxResponse")
+	proto.RegisterType((*RunSelfTestRequest)(nil), "blimp.cluster.v0.RunSelfTestRequest")
+	proto.RegisterType((*RunSelfTestResponse)(nil), "blimp.cluster.v0.RunSelfTestResponse")
+	proto.RegisterType((*SelfTestResult)(nil), "blimp.cluster.v0.SelfTestResult")
+	proto.RegisterType((*SelfTestStep)(nil), "blimp.cluster.v0.SelfTestStep")
+	proto.RegisterType((*RunTestRequest)(nil), "blimp.cluster.v0.RunTestRequest")
+	proto.RegisterType((*RunTestResponse)(nil), "blimp.cluster.v0.RunTestResponse")
+	proto.RegisterType((*GetUsageRequest)(nil), "blimp.cluster.v0.GetUsageRequest")
+	proto.RegisterType((*GetUsageResponse)(nil), "blimp.cluster.v0.GetUsageResponse")
+	proto.RegisterType((*UsageRecord)(nil), "blimp.cluster.v0.UsageRecord")
+	proto.RegisterType((*BoostRequest)(nil), "blimp.cluster.v0.BoostRequest")
+	proto.RegisterType((*BoostResponse)(nil), "blimp.cluster.v0.BoostResponse")
+	proto.RegisterType((*FaultRule)(nil), "blimp.cluster.v0.FaultRule")
+	proto.RegisterType((*GetFaultsRequest)(nil), "blimp.cluster.v0.GetFaultsRequest")
+	proto.RegisterType((*GetFaultsResponse)(nil), "blimp.cluster.v0.GetFaultsResponse")
+	proto.RegisterType((*SetFaultsRequest)(nil), "blimp.cluster.v0.SetFaultsRequest")
+	proto.RegisterType((*SetFaultsResponse)(nil), "blimp.cluster.v0.SetFaultsResponse")
+	proto.RegisterType((*GetDependencyGraphRequest)(nil), "blimp.cluster.v0.GetDependencyGraphRequest")
+	proto.RegisterType((*GetDependencyGraphResponse)(nil), "blimp.cluster.v0.GetDependencyGraphResponse")
+	proto.RegisterType((*ServiceNode)(nil), "blimp.cluster.v0.ServiceNode")
+	proto.RegisterType((*CreateSnapshotRequest)(nil), "blimp.cluster.v0.CreateSnapshotRequest")
+	proto.RegisterMapType((map[string]*RegistryCredential)(nil), "blimp.cluster.v0.CreateSnapshotRequest.RegistryCredentialsEntry")
+	proto.RegisterType((*CreateSnapshotResponse)(nil), "blimp.cluster.v0.CreateSnapshotResponse")
+	proto.RegisterType((*GetSnapshotRequest)(nil), "blimp.cluster.v0.GetSnapshotRequest")
+	proto.RegisterType((*GetSnapshotResponse)(nil), "blimp.cluster.v0.GetSnapshotResponse")
+	proto.RegisterMapType((map[string]string)(nil), "blimp.cluster.v0.GetSnapshotResponse.BuiltImagesEntry")
+	proto.RegisterType((*BootSnapshotRequest)(nil), "blimp.cluster.v0.BootSnapshotRequest")
+	proto.RegisterType((*BootSnapshotResponse)(nil), "blimp.cluster.v0.BootSnapshotResponse")
+	proto.RegisterType((*GetLogsRequest)(nil), "blimp.cluster.v0.GetLogsRequest")
+	proto.RegisterType((*GetLogsResponse)(nil), "blimp.cluster.v0.GetLogsResponse")
+	proto.RegisterType((*LogLine)(nil), "blimp.cluster.v0.LogLine")
+	proto.RegisterType((*WatchEventsRequest)(nil), "blimp.cluster.v0.WatchEventsRequest")
+	proto.RegisterType((*WatchEventsResponse)(nil), "blimp.cluster.v0.WatchEventsResponse")
+	proto.RegisterType((*LifecycleEvent)(nil), "blimp.cluster.v0.LifecycleEvent")
+	proto.RegisterType((*GetImageScansRequest)(nil), "blimp.cluster.v0.GetImageScansRequest")
+	proto.RegisterType((*GetImageScansResponse)(nil), "blimp.cluster.v0.GetImageScansResponse")
+	proto.RegisterType((*ImageScan)(nil), "blimp.cluster.v0.ImageScan")
+	proto.RegisterType((*Vulnerability)(nil), "blimp.cluster.v0.Vulnerability")
+	proto.RegisterType((*CreateShareLinkRequest)(nil), "blimp.cluster.v0.CreateShareLinkRequest")
+	proto.RegisterType((*SharedPort)(nil), "blimp.cluster.v0.SharedPort")
+	proto.RegisterType((*CreateShareLinkResponse)(nil), "blimp.cluster.v0.CreateShareLinkResponse")
+	proto.RegisterType((*LookupNamespaceRequest)(nil), "blimp.cluster.v0.LookupNamespaceRequest")
+	proto.RegisterType((*LookupNamespaceResponse)(nil), "blimp.cluster.v0.LookupNamespaceResponse")
+	proto.RegisterType((*NamespaceRecord)(nil), "blimp.cluster.v0.NamespaceRecord")
+}
+
+func init() {
+	proto.RegisterFile("blimp/cluster/v0/manager.proto", fileDescriptor_d156d5389f4d1cd6)
+}
+
+var fileDescriptor_d156d5389f4d1cd6 = []byte{
+	// 5830 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x3d, 0x5b, 0x6c, 0xe4, 0xc8,
+	0x71, 0xe6, 0x3c, 0x24, 0x4d, 0x8d, 0x1e, 0xb3, 0xad, 0xc7, 0xea, 0x68, 0xdf, 0xae, 0x96, 0xb7,
+	0x5a, 0xe9, 0xd6, 0x67, 0xed, 0xde, 0x9e, 0x7d, 0xf6, 0xf9, 0x02, 0xfb, 0x66, 0xa5, 0x59, 0xed,
+	0xf8, 0xa4, 0x91, 0xc0, 0x91, 0xf6, 0xbc, 0xf6, 0xc5, 0x04, 0x35, 0x6c, 0x69, 0x18, 0x71, 0xc8,
+	0x39, 0x92, 0xa3, 0x5d, 0xc5, 0x30, 0x8c, 0x38, 0x71, 0x90, 0x20, 0x41, 0x3e, 0x12, 0x20, 0x08,
+	0xf2, 0x40, 0x1e, 0x08, 0xf2, 0x91, 0x8f, 0x00, 0x01, 0x02, 0x03, 0x41, 0x90, 0xcf, 0x20, 0x08,
+	0xf2, 0x15, 0xff, 0xe4, 0x2b, 0x9f, 0x01, 0x92, 0xdf, 0xfc, 0x07, 0x0e, 0xfa, 0xc5, 0x69, 0x72,
+	0x38, 0x0f, 0x71, 0x75, 0x97, 0xe4, 0x4b, 0xd3, 0xc5, 0xea, 0xaa, 0xea, 0xea, 0x62, 0x75, 0x77,
+	0x75, 0x15, 0x05, 0xb7, 0x4e, 0x1c, 0xbb, 0xd3, 0x7d, 0xd0, 0x72, 0x7a, 0x41, 0x88, 0xfd, 0x07,
+	0x17, 0x0f, 0x1f, 0x74, 0x4c, 0xd7, 0x3c, 0xc3, 0xfe, 0x56, 0xd7, 0xf7, 0x42, 0x0f, 0x55, 0xe8,
+	0xf3, 0x2d, 0xfe, 0x7c, 0xeb, 0xe2, 0xa1, 0xba, 0xca, 0x7a, 0x98, 0xbd, 0xb0, 0x4d, 0xd0, 0xc9,
+	0x5f, 0x86, 0xab, 0x7e, 0x81, 0x3d, 0xc1, 0xbe, 0xef, 0xf9, 0x01, 0x79, 0xc6, 0x7e, 0xb1, 0xa7,
+	0xda, 0x77, 0x61, 0x71, 0xbb, 0x8d, 0x5b, 0xe7, 0xcf, 0xb0, 0x1f, 0xd8, 0x9e, 0xab, 0xe3, 0x4f,
+	0x7a, 0x38, 0x08, 0xd1, 0x2a, 0x4c, 0x5f, 0x30, 0xc8, 0xaa, 0xb2, 0xa6, 0x6c, 0x96, 0x74, 0xd1,
+	0x44, 0xf7, 0xe1, 0x46, 0xc7, 0x7c, 0x69, 0x74, 0x70, 0x10, 0x98, 0x67, 0xd8, 0x38, 0xb9, 0x0c,
+	0x71, 0xb0, 0x9a, 0x5b, 0x53, 0x36, 0xf3, 0xfa, 0x42, 0xc7, 0x7c, 0xb9, 0xcf, 0xe0, 0x8f, 0x09,
+	0x58, 0xfb, 0x0f, 0x05, 0x96, 0xe2, 0xd4, 0x83, 0xae, 0xe7, 0x06, 0x78, 0x04, 0xf9, 0x0d, 0x58,
+	0xb0, 0xec, 0xa0, 0xeb, 0x98, 0x97, 0x82, 0x05, 0x25, 0x5e, 0xd2, 0xe7, 0x39, 0x98, 0x33, 0x40,
+	0xef, 0xc0, 0x94, 0xd9, 0x0a, 0x09, 0x85, 0xfc, 0x9a, 0xb2, 0x39, 0xff, 0xe8, 0xf3, 0x5b, 0x49,
+	0x9d, 0x6c, 0x6d, 0xef, 0xd5, 0xab, 0x14, 0x45, 0xe7, 0xa8, 0xe8, 0x2d, 0x28, 0xd2, 0xd1, 0xaf,
+	0x16, 0xd6, 0x94, 0xcd, 0xf2, 0xa3, 0x15, 0xde, 0x87, 0x6b, 0xe4, 0xe2, 0xe1, 0x56, 0x8d, 0xfc,
+	0xd2, 0x19, 0x52, 0xfa, 0x50, 0x8b, 0xe9, 0x43, 0xfd, 0xf7, 0x69, 0x58, 0xda, 0xf6, 0xb1, 0x19,
+	0xe2, 0xa6, 0xe9, 0x5a, 0x27, 0xde, 0x4b, 0xa1, 0xc9, 0xcf, 0x43, 0xc9, 0x73, 0x2c, 0x23, 0xf4,
+	0xce, 0xb1, 0x18, 0xec, 0x8c, 0xe7, 0x58, 0x47, 0xa4, 0x8d, 0xde, 0x82, 0x02, 0x99, 0x29, 0x4a,
+	0xb4, 0xfc, 0x68, 0x95, 0x8b, 0x43, 0x27, 0xef, 0xe2, 0xe1, 0xd6, 0x63, 0xd2, 0xaa, 0xf6, 0xc2,
+	0xb6, 0x4e, 0xb1, 0xd0, 0x1a, 0x94, 0x5b, 0x5e, 0xa7, 0xeb, 0x05, 0xf8, 0x89, 0xed, 0x08, 0xbd,
+	0xc8, 0x20, 0xf4, 0x09, 0x2c, 0xfa, 0xf8, 0xcc, 0x0e, 0x42, 0xff, 0x72, 0xdb, 0xc7, 0x16, 0x76,
+	0x43, 0xdb, 0x74, 0x82, 0xd5, 0xfc, 0x5a, 0x7e, 0xb3, 0xfc, 0xe8, 0x9b, 0x29, 0x1a, 0x4a, 0x91,
+	0x78, 0x4b, 0x1f, 0xa4, 0x50, 0x73, 0x43, 0xff, 0x52, 0x4f, 0xa3, 0x8d, 0x0c, 0x98, 0x0b, 0x2e,
+	0xdd, 0x16, 0xb6, 0x9e, 0x78, 0x8e, 0x85, 0xfd, 0x60, 0xb5, 0x40, 0x99, 0xbd, 0x37, 0x21, 0xb3,
+	0xa6, 0xdc, 0x97, 0xb1, 0x89, 0xd3, 0x43, 0xf7, 0xa1, 0x62, 0x61, 0x27, 0x34, 0x09, 0xa6, 0xe0,
+	0x31, 0xb5, 0x96, 0xdf, 0x2c, 0xe9, 0x03, 0x70, 0xd4, 0x86, 0x4a, 0x10, 0x35, 0x0f, 0x5e, 0xb8,
+	0x04, 0x77, 0x9a, 0xca, 0xf3, 0x73, 0x57, 0x90, 0x47, 0xee, 0xce, 0x44, 0x1a, 0xa0, 0x8a, 0xde,
+	0x85, 0x15, 0xdb, 0x3d, 0xc5, 0x7e, 0xed, 0x25, 0x6e, 0xf5, 0x42, 0xf3, 0xc4, 0xc1, 0x42, 0xb6,
+	0x19, 0x2a, 0xdb, 0x90, 0xa7, 0x08, 0xc3, 0x82, 0x63, 0xbb, 0xb8, 0xe6, 0x5a, 0xb6, 0x7b, 0xa6,
+	0xf7, 0x1c, 0x1c, 0xac, 0x96, 0xa8, 0x80, 0xef, 0x4f, 0x28, 0xe0, 0x5e, 0xbc, 0x37, 0x93, 0x2f,
+	0x49, 0x53, 0x75, 0x60, 0x75, 0xd8, 0x34, 0xa2, 0x0a, 0xe4, 0xcf, 0xf1, 0x25, 0xb7, 0x45, 0xf2,
+	0x13, 0x7d, 0x1d, 0x8a, 0x17, 0xa6, 0xd3, 0x63, 0x26, 0x55, 0x7e, 0x74, 0x77, 0x50, 0x94, 0x41,
+	0x62, 0x3a, 0xeb, 0xf2, 0xf5, 0xdc, 0xd7, 0x14, 0xf5, 0x03, 0x40, 0x83, 0xf3, 0x98, 0xc2, 0x67,
+	0x49, 0xe6, 0x53, 0x92, 0x29, 0x6c, 0xc3, 0x72, 0xaa, 0xe6, 0xaf, 0x44, 0xe4, 0x04, 0x96, 0xd2,
+	0xb4, 0x93, 0x42, 0xe3, 0xcb, 0xf1, 0x01, 0xdf, 0x1a, 0x1c, 0x30, 0x79, 0x9d, 0x0e, 0xcd, 0x30,
+	0xc4, 0xbe, 0x1b, 0x48, 0x3c, 0xb4, 0xfb, 0x30, 0x2b, 0x3f, 0x42, 0x2a, 0xcc, 0x74, 0xf9, 0xef,
+	0x55, 0x85, 0xce, 0x7c, 0xd4, 0xd6, 0xf6, 0x00, 0x0d, 0xea, 0x8d, 0xf4, 0xe8, 0x05, 0xd8, 0x77,
+	0xcd, 0x0e, 0x16, 0xfe, 0x40, 0xb4, 0x19, 0xb5, 0x20, 0x78, 0xe1, 0xf9, 0x16, 0x1f, 0x5e, 0xd4,
+	0xd6, 0x5a, 0xb0, 0x52, 0x0d, 0x43, 0xb3, 0xd5, 0x3e, 0xf2, 0xb2, 0xb8, 0x98, 0xdc, 0x24, 0x2e,
+	0x46, 0xfb, 0xa9, 0x02, 0x37, 0x07, 0xb8, 0x70, 0xa7, 0x1d, 0x39, 0x4f, 0x65, 0x12, 0xe7, 0xb9,
+	0x06, 0xe5, 0x86, 0x67, 0xe1, 0xaa, 0x65, 0xf9, 0x38, 0x08, 0x84, 0xb3, 0x92, 0x40, 0x64, 0xb0,
+	0xa4, 0xb9, 0x8d, 0xfd, 0x90, 0xfa, 0xf0, 0x92, 0x1e, 0xb5, 0xd1, 0x87, 0xb0, 0x70, 0xde, 0x3b,
+	0xc1, 0xb2, 0x13, 0x63, 0x2e, 0xfb, 0xce, 0xe0, 0x54, 0x7d, 0x18, 0x47, 0xd4, 0x93, 0x3d, 0xb5,
+	0x7f, 0xcc, 0xc1, 0x72, 0xe2, 0x5d, 0xfa, 0x7f, 0x3e, 0x24, 0x74, 0x0f, 0xe6, 0xeb, 0x1d, 0xf3,
+	0x0c, 0x37, 0xcc, 0x0e, 0x0e, 0xba, 0x66, 0x0b, 0xd3, 0x25, 0xa4, 0xa4, 0x27, 0xa0, 0x64, 0xa1,
+	0x15, 0xcb, 0xe8, 0x14, 0x5b, 0x68, 0x3b, 0x03, 0xeb, 0xe7, 0xf4, 0xc4, 0xeb, 0xa7, 0xf6, 0x6f,
+	0x39, 0x98, 0xdb, 0xc1, 0x5d, 0xc7, 0xbb, 0xbc, 0x92, 0xed, 0x15, 0xae, 0x69, 0x79, 0xd3, 0xa1,
+	0x7c, 0xd2, 0xb3, 0x9d, 0x90, 0x0e, 0x52, 0x2c, 0x6b, 0x0f, 0x07, 0x05, 0x8f, 0x89, 0xb8, 0xf5,
+	0xb8, 0xdf, 0x85, 0x79, 0x4b, 0x99, 0x08, 0x7a, 0x1b, 0x96, 0x88, 0x72, 0x7d, 0x17, 0x87, 0x38,
+	0x30, 0x3a, 0xa6, 0x6b, 0x9f, 0xe2, 0x20, 0x24, 0xeb, 0x3c, 0x79, 0x99, 0x17, 0xfb, 0xcf, 0xf6,
+	0xc5, 0x23, 0xa2, 0xd4, 0xae, 0xef, 0xfd, 0x02, 0x6e, 0x85, 0x42, 0xa9, 0xbc, 0xa9, 0x7e, 0x03,
+	0x2a, 0x49, 0x6e, 0x57, 0xf1, 0x60, 0xda, 0x37, 0x60, 0x5e, 0xc8, 0x9e, 0xc5, 0x42, 0x35, 0x0f,
+	0x16, 0x12, 0xa6, 0x83, 0x10, 0x14, 0xda, 0x5e, 0x10, 0x72, 0xfe, 0xf4, 0x37, 0x11, 0xa0, 0x65,
+	0x6e, 0xfb, 0xa1, 0x10, 0x80, 0x36, 0x08, 0x94, 0x4d, 0x23, 0xb3, 0x5c, 0xd6, 0x40, 0x5f, 0x80,
+	0x92, 0x1b, 0x19, 0x59, 0x81, 0x3e, 0xe9, 0x03, 0xb4, 0x3f, 0x53, 0x60, 0x69, 0x07, 0x3b, 0x38,
+	0xdb, 0xb6, 0x27, 0x3f, 0x91, 0x5d, 0xac, 0xc3, 0xbc, 0x45, 0x59, 0x18, 0x17, 0x9e, 0xd3, 0xeb,
+	0xf0, 0xed, 0xe6, 0x8c, 0x3e, 0xc7, 0xa0, 0xcf, 0x18, 0x50, 0x9e, 0x95, 0x42, 0x6c, 0x56, 0xb4,
+	0x36, 0x2c, 0x27, 0x64, 0xcc, 0xf4, 0xfa, 0xdf, 0x81, 0x59, 0x4e, 0xd1, 0xf0, 0x5c, 0xe7, 0x92,
+	0x4b, 0x51, 0xe6, 0xb0, 0x03, 0xd7, 0xb9, 0xd4, 0xb6, 0x61, 0xf1, 0xd0, 0xec, 0x05, 0x49, 0x65,
+	0x88, 0xf1, 0x2a, 0x13, 0xf9, 0xe0, 0x1d, 0x58, 0x8a, 0x13, 0xc9, 0x64, 0x0a, 0x3b, 0xb0, 0xa4,
+	0xe3, 0xa0, 0xd7, 0x79, 0x35, 0x59, 0x6a, 0xb0, 0x9c, 0xa0, 0x92, 0x49, 0x98, 0x3f, 0x54, 0xa0,
+	0xb2, 0x8b, 0xc3, 0x66, 0x68, 0x86, 0xbd, 0xe0, 0xfa, 0x97, 0x2d, 0xe2, 0x77, 0x03, 0xec, 0x5f,
+	0xd8, 0x2d, 0xee, 0x15, 0x4a, 0x7a, 0xd4, 0x26, 0xd3, 0xe6, 0xd3, 0x21, 0x70, 0x4e, 0xcc, 0x38,
+	0xca, 0x0c, 0x46, 0x99, 0x69, 0x7f, 0xa4, 0xc0, 0x0d, 0x49, 0xbc, 0x4c, 0xd6, 0xf1, 0x55, 0x98,
+	0x0a, 0x68, 0x7f, 0x2e, 0xf2, 0xed, 0x41, 0xb7, 0xc4, 0x75, 0xc8, 0xd9, 0x70, 0xf4, 0x01, 0xf9,
+	0xf2, 0x83, 0xf2, 0xfd, 0x22, 0xa0, 0x48, 0x3c, 0x1c, 0x64, 0x9a, 0x49, 0x74, 0x0b, 0x20, 0x7a,
+	0x6d, 0x89, 0x8c, 0x44, 0x49, 0x12, 0x04, 0xad, 0xc0, 0x14, 0xe5, 0x2f, 0x14, 0xc8, 0x5b, 0xda,
+	0x7f, 0x29, 0xb0, 0x18, 0x63, 0x9e, 0x49, 0x3b, 0x07, 0x30, 0x13, 0x70, 0x0a, 0x94, 0x77, 0xf9,
+	0xd1, 0x3b, 0x83, 0xfa, 0x49, 0x61, 0xb3, 0x25, 0x00, 0xcc, 0x73, 0x47, 0x44, 0xd4, 0x8f, 0x61,
+	0x2e, 0xf6, 0x28, 0xc5, 0xcd, 0x7e, 0x25, 0xbe, 0xc9, 0x1b, 0x3b, 0x21, 0x92, 0x1f, 0xfe, 0x3d,
+	0x05, 0x6e, 0x1c, 0x7a, 0x8e, 0x13, 0x37, 0xd8, 0xab, 0x29, 0x5c, 0xb6, 0xc9, 0x5c, 0xc2, 0x26,
+	0x57, 0x60, 0xaa, 0xd5, 0xf3, 0x03, 0xcf, 0xe7, 0xb3, 0xcd, 0x5b, 0xc4, 0x16, 0x5e, 0x98, 0x76,
+	0x68, 0x04, 0xb8, 0xe5, 0xb9, 0x16, 0xdb, 0x20, 0x14, 0xf5, 0x32, 0x81, 0x35, 0x19, 0x48, 0xfb,
+	0xfd, 0x3c, 0x20, 0x59, 0xb4, 0xac, 0xae, 0xcc, 0xf5, 0x42, 0xa3, 0xe3, 0x59, 0xf6, 0xa9, 0x8d,
+	0x2d, 0xe1, 0xca, 0x5c, 0x2f, 0xdc, 0xe7, 0xa0, 0xa1, 0x22, 0x3e, 0x86, 0x62, 0xb7, 0x6d, 0x06,
+	0x6c, 0x2d, 0x98, 0x7f, 0xf4, 0xd6, 0x18, 0xad, 0x8a, 0xd6, 0x21, 0xe9, 0xa3, 0xb3, 0xae, 0xa8,
+	0x21, 0xa9, 0xa6, 0x48, 0xad, 0xe1, 0xd1, 0x20, 0x99, 0xc1, 0x41, 0x6e, 0x35, 0x79, 0x27, 0x61,
+	0x0c, 0xbc, 0x89, 0xde, 0x84, 0x8a, 0x8f, 0x3b, 0xde, 0x05, 0xb6, 0x8c, 0x88, 0x2e, 0x3b, 0x22,
+	0x2e, 0x70, 0xb8, 0xe8, 0x49, 0xed, 0x46, 0xa6, 0x92, 0xcd, 0x6e, 0x18, 0x85, 0x41, 0xbb, 0xf9,
+	0x51, 0x1e, 0xe6, 0x62, 0xc3, 0x47, 0x75, 0x69, 0xa8, 0x0a, 0x1d, 0xea, 0x97, 0xc6, 0x6a, 0x6c,
+	0xc8, 0x28, 0x23, 0xcd, 0xe7, 0xb2, 0x6b, 0x5e, 0x85, 0x99, 0x17, 0xa6, 0xef, 0xda, 0xee, 0x59,
+	0xe4, 0x28, 0x45, 0xfb, 0x53, 0x56, 0x4d, 0x1b, 0x66, 0x65, 0x81, 0x50, 0x19, 0xa6, 0x8f, 0x1b,
+	0x1f, 0x36, 0x0e, 0x3e, 0x6a, 0x54, 0x3e, 0x47, 0x1a, 0xfa, 0x71, 0xa3, 0x51, 0x6f, 0xec, 0x56,
+	0x14, 0xb4, 0x00, 0xe5, 0xa3, 0x9a, 0xbe, 0x5f, 0x6f, 0x54, 0x8f, 0x08, 0x20, 0x87, 0x10, 0xcc,
+	0xef, 0x1c, 0xd4, 0x9a, 0x46, 0xe3, 0xe0, 0xc8, 0xa8, 0x7d, 0xbb, 0xde, 0x3c, 0xaa, 0xe4, 0xd1,
+	0x1c, 0x94, 0x0e, 0xf5, 0xda, 0x61, 0x55, 0x27, 0x28, 0x05, 0x04, 0x30, 0x75, 0x58, 0x3d, 0x6e,
+	0xd6, 0x76, 0x2a, 0x45, 0xed, 0xbf, 0x73, 0x30, 0x17, 0x13, 0x83, 0x1c, 0xf7, 0x98, 0xe6, 0x14,
+	0xaa, 0xb9, 0x5b, 0x43, 0xc5, 0x8e, 0xe9, 0xaa, 0x02, 0xf9, 0x4e, 0x70, 0xc6, 0xf7, 0x48, 0xe4,
+	0x27, 0xba, 0x0d, 0xe5, 0xb6, 0x19, 0x18, 0x41, 0x68, 0xfa, 0x21, 0xb6, 0xe8, 0x8b, 0x31, 0xa3,
+	0x43, 0xdb, 0x0c, 0x9a, 0x0c, 0x82, 0x5e, 0x83, 0x19, 0x1f, 0x87, 0xfe, 0xa5, 0x61, 0xb2, 0x4d,
+	0x48, 0x5e, 0x9f, 0xa6, 0xed, 0x2a, 0x5d, 0xed, 0xf0, 0x4b, 0x3b, 0x34, 0x5a, 0x9e, 0xc5, 0x36,
+	0xeb, 0x45, 0x7d, 0x86, 0x00, 0xb6, 0x3d, 0x8b, 0x4e, 0x4b, 0xd0, 0x6a, 0x63, 0xab, 0xe7, 0x88,
+	0x7d, 0x7a, 0xd4, 0x46, 0xb7, 0xa0, 0xec, 0x98, 0x41, 0x68, 0xf8, 0x3d, 0x97, 0x90, 0x9d, 0xa6,
+	0x64, 0x4b, 0x04, 0xa4, 0xf7, 0xdc, 0x6a, 0x88, 0x1e, 0x42, 0xa1, 0x83, 0x83, 0xf6, 0xea, 0x0c,
+	0x9d, 0x92, 0x2f, 0x0c, 0x8e, 0x6d, 0x1f, 0x07, 0x6d, 0x3e, 0x1f, 0x14, 0x53, 0xde, 0x29, 0x95,
+	0x62, 0x3b, 0x25, 0xb4, 0x0d, 0x65, 0xb6, 0xc7, 0x32, 0x6c, 0xd7, 0x0e, 0x57, 0x81, 0x1a, 0xac,
+	0x36, 0x48, 0x92, 0xed, 0xb9, 0xea, 0xae, 0x2d, 0xd6, 0x4c, 0xb8, 0x88, 0x20, 0xda, 0x1f, 0xe4,
+	0xa0, 0x92, 0x44, 0x20, 0xee, 0x84, 0xa1, 0x70, 0x73, 0xe2, 0x2d, 0xf4, 0x0d, 0x28, 0x12, 0x9f,
+	0x2e, 0x8c, 0x7a, 0x73, 0x3c, 0x2f, 0xba, 0x24, 0x60, 0x9d, 0x75, 0x23, 0x9e, 0xac, 0xe5, 0x75,
+	0x6d, 0x6c, 0xf1, 0xf0, 0x5c, 0x9e, 0xaa, 0xa7, 0xcc, 0x60, 0x34, 0x34, 0x47, 0x66, 0x2d, 0xf4,
+	0x42, 0xd3, 0xe1, 0x18, 0x6c, 0x5e, 0x80, 0x82, 0x18, 0xc2, 0x92, 0xf0, 0x9d, 0xec, 0x0c, 0xc5,
+	0x1a, 0xda, 0x31, 0x14, 0x29, 0x27, 0x62, 0x9c, 0x87, 0xb5, 0xc6, 0x0e, 0x31, 0x34, 0x6a, 0xa9,
+	0xdb, 0x07, 0x87, 0xcf, 0x99, 0xa5, 0xce, 0x40, 0x61, 0xe7, 0xa0, 0x51, 0xab, 0xe4, 0x88, 0x39,
+	0x1e, 0x37, 0xb6, 0x9f, 0x56, 0x1b, 0xbb, 0xb5, 0x1d, 0x66, 0x9d, 0xd4, 0x58, 0xf7, 0x0f, 0x8f,
+	0x9e, 0x33, 0xeb, 0x7c, 0x52, 0xad, 0xef, 0x51, 0xeb, 0x7c, 0x1b, 0xa0, 0x3f, 0x21, 0xe8, 0x0d,
+	0x98, 0x0b, 0x6c, 0x0b, 0xb7, 0x4c, 0xdf, 0xf0, 0xb1, 0x69, 0xb1, 0x97, 0x6d, 0x46, 0x9f, 0xe5,
+	0x40, 0x9d, 0xc0, 0xb4, 0x1e, 0xcc, 0xeb, 0x98, 0x1a, 0xdd, 0xa7, 0xb0, 0xbb, 0x5e, 0x85, 0x69,
+	0xee, 0x61, 0xb8, 0xa5, 0x8b, 0xa6, 0xf6, 0x4d, 0x58, 0x88, 0xd8, 0x66, 0xda, 0xf5, 0x35, 0x61,
+	0xe1, 0xc8, 0x3c, 0xa3, 0x67, 0x21, 0x29, 0xae, 0x2c, 0xb8, 0x29, 0x31, 0x6e, 0x64, 0x12, 0xec,
+	0x4e, 0x3f, 0xdc, 0xcb, 0x1a, 0xe4, 0x1d, 0x0c, 0xcd, 0x33, 0xbe, 0x04, 0x91, 0x9f, 0xda, 0xcf,
+	0x72, 0x50, 0x11, 0x54, 0x83, 0x4f, 0xe1, 0x14, 0xba, 0x0d, 0xe5, 0xd0, 0x3c, 0xe3, 0x84, 0xc5,
+	0x66, 0x25, 0xe5, 0x88, 0x9e, 0x18, 0x99, 0x2e, 0xf7, 0x42, 0x9d, 0x51, 0x71, 0xd8, 0xf7, 0x87,
+	0x13, 0x0b, 0x32, 0xc5, 0x60, 0x3f, 0xdb, 0x68, 0x9f, 0xf6, 0x5d, 0xb8, 0x21, 0xc9, 0xdb, 0x8f,
+	0xe8, 0x0f, 0x99, 0xd8, 0xc8, 0x66, 0x72, 0x93, 0xd8, 0xcc, 0xaf, 0x29, 0x30, 0x57, 0x7b, 0x49,
+	0x4e, 0xfc, 0x9f, 0xc2, 0xdc, 0x0e, 0xb5, 0x75, 0x72, 0x4a, 0xee, 0x7a, 0x3c, 0x68, 0x33, 0xa7,
+	0xd3, 0xdf, 0x9a, 0x0e, 0xf3, 0x42, 0x92, 0x4c, 0x9b, 0x2c, 0x04, 0x05, 0xc7, 0x76, 0xcf, 0x39,
+	0x2b, 0xfa, 0x5b, 0xfb, 0x18, 0x16, 0x8e, 0x5d, 0x7c, 0xf5, 0xf1, 0x4d, 0x16, 0xbd, 0xfb, 0x00,
+	0x2a, 0x7d, 0xea, 0x99, 0x5e, 0x59, 0x0c, 0xab, 0xbb, 0x38, 0x8c, 0x07, 0x91, 0x3e, 0x05, 0x41,
+	0xcf, 0xe0, 0xb5, 0x14, 0x36, 0x99, 0xb4, 0x1c, 0x8b, 0x4f, 0xe4, 0x92, 0xf1, 0x09, 0x83, 0x9e,
+	0x9c, 0x48, 0x4c, 0xc6, 0x3a, 0xb7, 0xc3, 0x4f, 0x61, 0x24, 0xbf, 0xc4, 0x8e, 0x47, 0x7d, 0x0e,
+	0x9f, 0x7d, 0x64, 0x51, 0xfb, 0x99, 0x02, 0xcb, 0x54, 0xae, 0xe3, 0xee, 0xa1, 0x8f, 0x2f, 0x6c,
+	0xfc, 0x22, 0x79, 0x62, 0x99, 0xec, 0x7e, 0x09, 0x41, 0xc1, 0xc7, 0x5d, 0x4f, 0x18, 0x2c, 0xf9,
+	0x8d, 0x34, 0x98, 0x95, 0x22, 0x70, 0x62, 0xd3, 0x18, 0x83, 0xa1, 0xc7, 0x90, 0xc7, 0xee, 0xc5,
+	0x6a, 0x61, 0x58, 0x38, 0x2e, 0x55, 0xb6, 0xad, 0x9a, 0x7b, 0xc1, 0x5c, 0x1a, 0xe9, 0xac, 0xbe,
+	0x0b, 0x33, 0x02, 0x70, 0x95, 0x88, 0xd9, 0xb7, 0x0a, 0x33, 0x4a, 0x25, 0xa7, 0xfd, 0x10, 0x56,
+	0x92, 0x4c, 0x32, 0xcd, 0xc3, 0x6d, 0x28, 0xf3, 0xcd, 0x9d, 0xd1, 0x72, 0x6c, 0x7e, 0x2c, 0x02,
+	0x0e, 0xda, 0x76, 0x6c, 0xb2, 0x8d, 0xf1, 0x7a, 0x61, 0xb7, 0xc7, 0x26, 0x61, 0x56, 0xe7, 0x2d,
+	0xed, 0x3d, 0x28, 0x1f, 0xf6, 0x1c, 0x47, 0xe8, 0x5d, 0x68, 0x52, 0x91, 0x34, 0xb9, 0x02, 0x53,
+	0x6e, 0xaf, 0x73, 0x82, 0x99, 0x23, 0x9c, 0xd3, 0x79, 0x4b, 0xfb, 0xe5, 0xbc, 0xb8, 0x39, 0x1c,
+	0x32, 0x79, 0x93, 0x1d, 0x37, 0x3f, 0x80, 0xd9, 0x6e, 0xcf, 0x71, 0x0c, 0x9f, 0xf5, 0xe6, 0xe6,
+	0xfb, 0x7a, 0xca, 0xb9, 0xaa, 0x2f, 0xa7, 0x5e, 0xee, 0xf6, 0x1b, 0xe4, 0xad, 0x68, 0x39, 0x9e,
+	0x8b, 0x8d, 0x9e, 0xef, 0x08, 0x1b, 0xa3, 0x80, 0x63, 0xdf, 0x21, 0x73, 0xe2, 0xe3, 0x53, 0x1e,
+	0x3c, 0x21, 0x3f, 0xc9, 0xd6, 0x85, 0x5b, 0x81, 0x71, 0x6a, 0x3b, 0xfc, 0x24, 0x97, 0x34, 0x8d,
+	0x2a, 0x33, 0x8d, 0x29, 0x6a, 0x1a, 0x0f, 0x86, 0x5d, 0x71, 0x8d, 0xb2, 0x0c, 0xd9, 0x69, 0x4f,
+	0xa7, 0x3b, 0xed, 0x99, 0xbe, 0xd3, 0xce, 0x6a, 0x47, 0xda, 0x0b, 0x58, 0x4e, 0xc8, 0x72, 0xfd,
+	0xde, 0x28, 0x5a, 0x11, 0xf2, 0xd2, 0x8a, 0xf0, 0xab, 0x51, 0x04, 0xf5, 0x7f, 0x77, 0xfa, 0x49,
+	0xa8, 0x2f, 0x21, 0x47, 0xa6, 0x15, 0xe4, 0x5f, 0x14, 0x98, 0x39, 0xc2, 0x9d, 0xae, 0x43, 0xb6,
+	0xce, 0x08, 0x0a, 0xd2, 0x3d, 0x17, 0xfd, 0x4d, 0x7c, 0x9d, 0x85, 0x83, 0x96, 0x6f, 0x77, 0xe9,
+	0xed, 0x03, 0xf7, 0x75, 0x12, 0x48, 0xce, 0x0e, 0x60, 0xeb, 0xb1, 0x68, 0xa2, 0xf7, 0xa1, 0xc8,
+	0x6c, 0x8d, 0xf9, 0x9a, 0xf5, 0x94, 0x9d, 0x14, 0x67, 0x4d, 0x2f, 0xf0, 0xf8, 0x9e, 0x89, 0xf5,
+	0x51, 0xbf, 0x06, 0xd0, 0x07, 0x5e, 0xc9, 0x38, 0x76, 0xc8, 0xc5, 0x62, 0x10, 0x0a, 0xda, 0xd9,
+	0x02, 0x42, 0xda, 0x0f, 0x61, 0x39, 0x41, 0x25, 0x93, 0x89, 0x7d, 0x0d, 0x4a, 0xa1, 0x20, 0xc1,
+	0xb7, 0xa7, 0xea, 0x70, 0x3d, 0xe8, 0x7d, 0x64, 0xed, 0x19, 0x5d, 0x0c, 0xa3, 0x27, 0x99, 0xec,
+	0x4c, 0xcc, 0x68, 0xae, 0x3f, 0xa3, 0xda, 0xf7, 0x61, 0x31, 0x46, 0x37, 0xd3, 0xb0, 0xde, 0x85,
+	0x19, 0x21, 0x29, 0x37, 0xde, 0x51, 0xa3, 0x8a, 0x70, 0xb5, 0x5f, 0xcf, 0x41, 0xb1, 0x6a, 0x59,
+	0x9e, 0x9b, 0x6a, 0x6c, 0x2b, 0x30, 0x85, 0xdd, 0x33, 0xdb, 0x15, 0x02, 0xf3, 0x56, 0xd2, 0xc4,
+	0xa4, 0x04, 0x14, 0x39, 0x6c, 0x57, 0x48, 0x84, 0xed, 0x1e, 0x31, 0x6f, 0xc6, 0x42, 0x56, 0x6b,
+	0x83, 0xe2, 0x51, 0x39, 0x12, 0xee, 0x6b, 0x49, 0xc4, 0x1e, 0xd8, 0xb9, 0x9e, 0x35, 0x88, 0x9f,
+	0x08, 0x5c, 0xb3, 0x1b, 0xb4, 0xbd, 0x90, 0x65, 0x28, 0x94, 0xf4, 0x3e, 0x20, 0xb3, 0x13, 0xfb,
+	0x73, 0x05, 0x10, 0xf3, 0x62, 0x54, 0x92, 0x6b, 0x9b, 0x61, 0x49, 0x8d, 0xf9, 0x61, 0x6a, 0x2c,
+	0x0c, 0x57, 0x63, 0x31, 0xae, 0x46, 0xed, 0x4f, 0x15, 0x58, 0x8c, 0x89, 0x99, 0xc9, 0x60, 0xbe,
+	0x04, 0x45, 0x93, 0x74, 0xe7, 0xd6, 0x72, 0x73, 0xc8, 0x74, 0xe8, 0x0c, 0x0b, 0x7d, 0x09, 0x90,
+	0x8f, 0xc5, 0xe2, 0x9e, 0xb8, 0x2c, 0xb8, 0x11, 0x3d, 0x11, 0x01, 0x30, 0xed, 0x05, 0x20, 0xe6,
+	0x0d, 0xaf, 0x59, 0x93, 0xb7, 0x89, 0xf7, 0xa3, 0x97, 0x59, 0x96, 0x19, 0x9a, 0x22, 0x84, 0xc4,
+	0x40, 0x3b, 0x66, 0x68, 0x92, 0x2b, 0xa4, 0x18, 0xe3, 0x4c, 0x4e, 0xb8, 0x0a, 0x37, 0x88, 0xab,
+	0xa1, 0x24, 0x32, 0x7a, 0xab, 0x00, 0x90, 0x4c, 0x22, 0xd3, 0x14, 0x3d, 0x80, 0x29, 0xaa, 0x7c,
+	0xe1, 0xa7, 0x86, 0xce, 0x11, 0x47, 0xd3, 0x42, 0x58, 0x6a, 0xf2, 0xb7, 0xe0, 0x9a, 0xf5, 0x4e,
+	0xec, 0x91, 0x53, 0x16, 0x7b, 0x1b, 0xd1, 0xd6, 0x4c, 0x58, 0x4e, 0x70, 0xcd, 0x34, 0x5a, 0x99,
+	0x45, 0x2e, 0xc1, 0x22, 0x80, 0x45, 0x1d, 0x07, 0xa1, 0xe7, 0xe3, 0xcf, 0x70, 0x5c, 0xec, 0x0a,
+	0x50, 0x62, 0x9a, 0xc9, 0x96, 0x8e, 0xa3, 0x90, 0x76, 0xcd, 0xbd, 0x78, 0x66, 0xfa, 0xa9, 0x7e,
+	0x36, 0xd5, 0x27, 0x8d, 0xba, 0x96, 0x23, 0xdb, 0x0d, 0x62, 0x5f, 0x7d, 0xd2, 0xd9, 0xcc, 0xf4,
+	0x12, 0x56, 0x92, 0x64, 0x32, 0x4d, 0xde, 0xdb, 0xcc, 0xb5, 0x33, 0x3b, 0x1d, 0x7e, 0x55, 0xc4,
+	0x54, 0x40, 0x3d, 0xbb, 0xf6, 0x02, 0x96, 0x9a, 0xf8, 0x55, 0x07, 0x90, 0x85, 0x71, 0x08, 0xcb,
+	0x4d, 0xfc, 0xea, 0x43, 0x4e, 0xf7, 0x88, 0xb9, 0x61, 0x1e, 0xf1, 0x63, 0x58, 0x39, 0x76, 0x83,
+	0x57, 0x1f, 0xf0, 0x12, 0x14, 0xe9, 0x8e, 0x98, 0x73, 0x62, 0x0d, 0xed, 0x02, 0x6e, 0x0e, 0x50,
+	0xff, 0x2c, 0x46, 0xd5, 0x86, 0x72, 0xb3, 0xe5, 0x75, 0x31, 0x3f, 0xfc, 0xcf, 0x43, 0xce, 0xb6,
+	0xb8, 0x65, 0xe7, 0x6c, 0x6b, 0xd8, 0x62, 0x18, 0x90, 0x2e, 0xd1, 0x4d, 0x29, 0x6b, 0xa1, 0xd7,
+	0x01, 0x5a, 0x74, 0x55, 0xb3, 0xfa, 0xe1, 0xff, 0x12, 0x87, 0x54, 0x43, 0xcd, 0x15, 0x6b, 0x33,
+	0xe5, 0x74, 0xad, 0x6b, 0x73, 0x9a, 0x38, 0xda, 0x6f, 0x46, 0xab, 0x2c, 0x67, 0x98, 0x49, 0x9d,
+	0x51, 0x52, 0x48, 0x4e, 0x4e, 0x0a, 0x79, 0x1b, 0x0a, 0xb6, 0x7b, 0xea, 0xad, 0xe6, 0x87, 0x9d,
+	0x32, 0x24, 0x9d, 0xea, 0x14, 0x55, 0x2c, 0x49, 0x14, 0x14, 0x64, 0x7d, 0xd7, 0x91, 0x4c, 0x22,
+	0xd3, 0x78, 0xbe, 0x12, 0x5d, 0x73, 0xb3, 0x37, 0x6e, 0x8c, 0xec, 0x1c, 0x59, 0xd3, 0x01, 0xe9,
+	0xf8, 0xc2, 0x3b, 0x7f, 0x95, 0xc9, 0x63, 0xb6, 0x95, 0x13, 0xb6, 0x45, 0x56, 0xfa, 0x18, 0xcd,
+	0x4c, 0xde, 0xf9, 0x6f, 0x72, 0x50, 0xe6, 0xef, 0x4c, 0xdd, 0x3d, 0xf5, 0xe2, 0x07, 0x50, 0x25,
+	0x79, 0x00, 0x5d, 0x82, 0xa2, 0x47, 0x92, 0x2b, 0xc5, 0x6c, 0xd2, 0x46, 0xc2, 0x70, 0xf3, 0x09,
+	0xc3, 0x25, 0x07, 0x7d, 0x7a, 0x01, 0x45, 0x72, 0xc0, 0x2e, 0xec, 0xf0, 0x92, 0x9b, 0xf6, 0x2c,
+	0x01, 0x56, 0x39, 0xac, 0x7f, 0x39, 0x59, 0xcc, 0x7e, 0x39, 0xf9, 0x1a, 0xcc, 0xb8, 0xbd, 0x8e,
+	0xd1, 0xf5, 0xac, 0x80, 0xee, 0x96, 0x8b, 0xfa, 0xb4, 0xdb, 0xeb, 0x1c, 0x7a, 0x16, 0xbd, 0x27,
+	0x69, 0x75, 0x7b, 0xe2, 0x74, 0x8b, 0x2d, 0x1e, 0x0a, 0x98, 0x6d, 0x75, 0x7b, 0xba, 0x80, 0x91,
+	0x6b, 0xe0, 0x0e, 0xee, 0x78, 0xfe, 0xa5, 0x84, 0x37, 0x43, 0xf1, 0x16, 0x18, 0x3c, 0x42, 0xd5,
+	0xbe, 0xca, 0x4e, 0x74, 0x5c, 0x8a, 0xfe, 0x89, 0xee, 0x36, 0x94, 0x4d, 0xab, 0x63, 0xbb, 0xb1,
+	0xd8, 0x20, 0x50, 0x10, 0x4b, 0xc5, 0xf8, 0x91, 0x02, 0xcb, 0x89, 0x9e, 0x99, 0xec, 0xf0, 0x7d,
+	0x28, 0x05, 0x82, 0xc4, 0x08, 0x53, 0xec, 0xcf, 0xac, 0xde, 0xc7, 0x27, 0xc1, 0x8a, 0x5d, 0x1c,
+	0xee, 0xd8, 0xe6, 0x99, 0xeb, 0x05, 0xa1, 0xdd, 0xca, 0x98, 0xa1, 0xf0, 0x10, 0x96, 0x48, 0x7e,
+	0x3b, 0xcb, 0xa9, 0x30, 0xfa, 0xe7, 0x91, 0x1c, 0xd5, 0x3d, 0xea, 0x98, 0x7c, 0xb2, 0xc4, 0xe6,
+	0x28, 0xd0, 0x7e, 0x92, 0x83, 0x95, 0x24, 0xe7, 0xcf, 0x36, 0x5b, 0x66, 0x17, 0xe6, 0xb9, 0xbc,
+	0x6d, 0x3b, 0x08, 0x3d, 0xff, 0x72, 0x35, 0x3f, 0xec, 0x34, 0x16, 0x17, 0x5e, 0x9f, 0x63, 0xfd,
+	0x9e, 0xb2, 0x6e, 0xe8, 0xcb, 0x24, 0x78, 0x64, 0x89, 0x48, 0xc2, 0x5a, 0x5a, 0xfe, 0x81, 0x25,
+	0x8f, 0x93, 0x62, 0xa3, 0x77, 0x61, 0x0a, 0x5f, 0x60, 0x37, 0x14, 0x79, 0x0b, 0xb7, 0x86, 0x2f,
+	0xd8, 0x04, 0x4d, 0xe7, 0xd8, 0xda, 0xcf, 0xc3, 0x7c, 0x5c, 0x1c, 0xe2, 0xca, 0x43, 0x9b, 0xef,
+	0xa2, 0xf2, 0x3a, 0xfd, 0x9d, 0x59, 0x2b, 0xda, 0x5f, 0x29, 0x30, 0x1f, 0x97, 0x77, 0xc4, 0x85,
+	0x4c, 0x05, 0xf2, 0x5d, 0x4f, 0x38, 0x22, 0xf2, 0xb3, 0x7f, 0x46, 0xcd, 0xcb, 0x67, 0x54, 0xb2,
+	0xd8, 0x90, 0xcb, 0xea, 0x02, 0x5f, 0x6c, 0xc8, 0x45, 0xf5, 0x13, 0x80, 0x96, 0xe7, 0x86, 0xa6,
+	0x4d, 0x53, 0xeb, 0x99, 0x0e, 0xee, 0xa5, 0x84, 0xf5, 0x04, 0x8e, 0xac, 0x41, 0xa9, 0xa7, 0xf6,
+	0x17, 0xa4, 0x32, 0x24, 0x05, 0x69, 0xd8, 0xe6, 0x92, 0x5d, 0x8e, 0xb2, 0x78, 0x2c, 0x6b, 0x10,
+	0x97, 0xc0, 0x97, 0x73, 0xa3, 0xe5, 0xf5, 0x5c, 0xe6, 0xb8, 0x8a, 0xfa, 0x2c, 0x07, 0x6e, 0x13,
+	0x18, 0xe9, 0xca, 0xae, 0x97, 0xd9, 0x20, 0x58, 0x83, 0x38, 0x0a, 0xea, 0xd1, 0x42, 0xec, 0x77,
+	0x6c, 0xd7, 0xa4, 0x71, 0x28, 0x76, 0xf7, 0xbb, 0x40, 0xe0, 0x47, 0x7d, 0xb0, 0xf6, 0xbb, 0x0a,
+	0xcc, 0xca, 0x33, 0x9a, 0x3a, 0x6f, 0x24, 0x2a, 0x7c, 0x42, 0xef, 0xd3, 0x79, 0x94, 0x81, 0xb5,
+	0x28, 0xee, 0x65, 0x57, 0xa8, 0x95, 0xfe, 0x26, 0xb8, 0x3e, 0x36, 0x83, 0xe8, 0xc4, 0xcc, 0x5b,
+	0x72, 0xa6, 0x6e, 0x31, 0x9e, 0xa9, 0x4b, 0xb2, 0x35, 0xe9, 0x00, 0x99, 0x4f, 0x64, 0x0d, 0xed,
+	0x23, 0x58, 0xd9, 0xa1, 0x31, 0xb3, 0x93, 0x64, 0x86, 0xdf, 0x38, 0x1f, 0x36, 0xe6, 0xca, 0xe4,
+	0x6f, 0x15, 0xb8, 0x39, 0x40, 0x39, 0xe3, 0x4b, 0x3e, 0xcd, 0x7d, 0xd6, 0xf0, 0x70, 0xa4, 0xec,
+	0xe1, 0x04, 0xb6, 0xf4, 0x1e, 0xe4, 0xaf, 0xf6, 0x1e, 0x7c, 0x1f, 0x16, 0x6b, 0x17, 0x76, 0x2b,
+	0xbc, 0x56, 0x8d, 0xa4, 0x24, 0xa0, 0xe6, 0x53, 0x12, 0x50, 0xc9, 0x71, 0x2b, 0xce, 0x3c, 0xd3,
+	0x82, 0xfe, 0x15, 0x40, 0x7a, 0xcf, 0x6d, 0x62, 0xe7, 0xf4, 0x08, 0x07, 0xe1, 0xc4, 0xeb, 0xd2,
+	0x0f, 0x60, 0x31, 0xd6, 0x2d, 0x63, 0x68, 0x71, 0xca, 0xc7, 0x41, 0xcf, 0x11, 0xe1, 0xe3, 0x34,
+	0xa7, 0xda, 0xe7, 0xd0, 0x73, 0x42, 0x9d, 0xe3, 0x6b, 0x3f, 0x80, 0xf9, 0xf8, 0x13, 0x62, 0xe7,
+	0x5d, 0x33, 0x08, 0xb0, 0xc5, 0x53, 0x1a, 0x78, 0x8b, 0x6c, 0x36, 0xc4, 0xee, 0xdc, 0x0c, 0x79,
+	0xe1, 0x58, 0x89, 0x43, 0xaa, 0x21, 0x49, 0xd5, 0x09, 0x42, 0xdc, 0x15, 0x77, 0xe5, 0xb7, 0x86,
+	0x4b, 0xd0, 0x0c, 0x71, 0x57, 0x67, 0xc8, 0x5a, 0x07, 0x66, 0x65, 0xf0, 0xb0, 0x50, 0x20, 0x17,
+	0x28, 0x17, 0x13, 0x88, 0xa7, 0xf9, 0xe4, 0x63, 0x69, 0x3e, 0x56, 0xcf, 0xa7, 0xef, 0xbf, 0xd1,
+	0x89, 0x12, 0x46, 0x04, 0x68, 0x3f, 0xd0, 0xfe, 0x55, 0x81, 0x79, 0xbd, 0xe7, 0xca, 0x13, 0x74,
+	0xb5, 0x95, 0x77, 0xf8, 0x45, 0xf4, 0x2a, 0x4c, 0xb7, 0xbc, 0x4e, 0xc7, 0x74, 0x2d, 0xbe, 0x9d,
+	0x17, 0x4d, 0x22, 0x55, 0xd0, 0x36, 0x7d, 0xcb, 0xb0, 0x5d, 0x0b, 0xbf, 0xe4, 0xa9, 0x81, 0x40,
+	0x41, 0x75, 0x02, 0xe9, 0x23, 0x30, 0x6f, 0x51, 0x94, 0x10, 0x98, 0x33, 0x64, 0xb9, 0x32, 0x97,
+	0x91, 0x15, 0x4f, 0xb1, 0xac, 0x3f, 0x02, 0x13, 0x36, 0xfc, 0x4f, 0x0a, 0x2c, 0x44, 0x23, 0xcb,
+	0x64, 0x43, 0xfd, 0x1b, 0xb2, 0x9c, 0x7c, 0x43, 0x46, 0x36, 0x77, 0x5d, 0xcf, 0x32, 0xe8, 0xb4,
+	0xf0, 0x90, 0x6b, 0xd7, 0xb3, 0x1a, 0x3c, 0x86, 0x71, 0x6a, 0xbb, 0x76, 0xd0, 0xc6, 0x16, 0x1d,
+	0xd6, 0x8c, 0x1e, 0xb5, 0x47, 0xa7, 0x4d, 0xc5, 0x5e, 0xdb, 0xa9, 0xa4, 0x23, 0x7b, 0x09, 0x0b,
+	0xbb, 0x38, 0x3c, 0x0e, 0xa4, 0xf4, 0x93, 0xab, 0xcd, 0x12, 0xb1, 0x18, 0xec, 0xdb, 0xd1, 0x5a,
+	0xc9, 0x5b, 0xc9, 0x97, 0x31, 0x3f, 0xf0, 0x32, 0xfe, 0x25, 0x4b, 0x77, 0xe6, 0xac, 0x33, 0xa9,
+	0xf1, 0x1d, 0x28, 0xf6, 0x78, 0xf5, 0xe3, 0x90, 0xbd, 0x21, 0xa7, 0xde, 0xf2, 0x7c, 0x4b, 0x67,
+	0xb8, 0xa4, 0xd3, 0x27, 0x3d, 0x8f, 0x87, 0x15, 0xc7, 0x77, 0xa2, 0xb8, 0xda, 0xef, 0xe4, 0xa0,
+	0x2c, 0x81, 0xc7, 0x9c, 0x20, 0x86, 0xe9, 0xe4, 0x2e, 0xcc, 0x93, 0x0d, 0x7a, 0xcb, 0xf3, 0xb1,
+	0xd1, 0xf6, 0x7a, 0x3e, 0xf3, 0x91, 0x0a, 0xdd, 0xa1, 0x6f, 0x7b, 0x3e, 0x7e, 0x4a, 0x60, 0x68,
+	0x33, 0xda, 0xa1, 0x9f, 0xd9, 0x27, 0x1c, 0xaf, 0x40, 0xf1, 0xe6, 0x19, 0x7c, 0xd7, 0x3e, 0x61,
+	0x98, 0xf7, 0xe1, 0x46, 0x10, 0x7a, 0x3e, 0xa9, 0xbb, 0xec, 0xa3, 0x16, 0x29, 0xea, 0x02, 0x7f,
+	0x10, 0xe1, 0xde, 0x81, 0x59, 0x7c, 0xe6, 0xe3, 0x20, 0xe0, 0x19, 0x5e, 0x53, 0x2c, 0x07, 0x8c,
+	0xc1, 0x58, 0x8a, 0xd7, 0x03, 0x58, 0x3a, 0xf1, 0xbc, 0x20, 0x34, 0x12, 0x42, 0x4e, 0x53, 0x8a,
+	0x37, 0xe8, 0xb3, 0x6d, 0x49, 0x52, 0xed, 0xb7, 0x14, 0x98, 0x7d, 0x4c, 0xa0, 0xd9, 0x4c, 0x67,
+	0x9d, 0xa9, 0xa3, 0xd3, 0x73, 0x42, 0xbb, 0xeb, 0xd8, 0xfc, 0xc4, 0xa5, 0xe8, 0xe4, 0x14, 0xb3,
+	0x1f, 0x01, 0xc9, 0x46, 0x24, 0xf2, 0x34, 0x22, 0xe7, 0x97, 0x9d, 0xbf, 0x16, 0x04, 0x5c, 0xe4,
+	0xfd, 0xfe, 0x86, 0x02, 0x73, 0x5c, 0xa0, 0x4c, 0x06, 0xf5, 0x3a, 0x00, 0x7e, 0xd9, 0xb5, 0x7d,
+	0x1c, 0x48, 0x7e, 0x97, 0x43, 0xaa, 0xe1, 0x55, 0xc3, 0xe3, 0x1d, 0x28, 0x3d, 0x31, 0xc9, 0x02,
+	0xd0, 0x73, 0xe8, 0x46, 0xf1, 0xd4, 0xf7, 0x3a, 0xc2, 0xdb, 0x92, 0xdf, 0xe4, 0xb0, 0x1b, 0x8a,
+	0x4c, 0x82, 0x5c, 0xe8, 0x91, 0x39, 0xb2, 0x7c, 0xaf, 0x6b, 0x74, 0xb1, 0xdf, 0xc2, 0x7c, 0xb3,
+	0xa6, 0xe8, 0x65, 0x02, 0x3b, 0x64, 0x20, 0xe2, 0x21, 0x2c, 0x4c, 0x0b, 0x7f, 0x85, 0xcf, 0x9d,
+	0xa6, 0xed, 0xfd, 0x80, 0x24, 0xb6, 0xec, 0xe2, 0x90, 0x72, 0xcc, 0x18, 0x3b, 0xf8, 0x07, 0x96,
+	0xe2, 0x2f, 0x48, 0x64, 0x52, 0xe1, 0x07, 0xfd, 0x1b, 0x6f, 0x9f, 0x56, 0x6e, 0xb2, 0x77, 0x33,
+	0xa5, 0x72, 0x2a, 0xd2, 0x4d, 0x74, 0x1d, 0x4e, 0x1a, 0x01, 0xa1, 0xe0, 0xf7, 0x5c, 0xb2, 0x67,
+	0xe4, 0x14, 0xf2, 0x13, 0x50, 0xe0, 0x3d, 0x28, 0x05, 0x72, 0xfe, 0xac, 0x34, 0x5f, 0x49, 0x15,
+	0x83, 0x42, 0xe4, 0xae, 0x2a, 0x44, 0x15, 0x6e, 0x34, 0x5f, 0x4d, 0x97, 0x5a, 0x9d, 0x66, 0x00,
+	0xed, 0xe0, 0x2e, 0x76, 0x2d, 0xec, 0xb6, 0x2e, 0x77, 0x7d, 0xb3, 0xdb, 0xce, 0x36, 0xb5, 0x3f,
+	0x56, 0x40, 0x4d, 0xa3, 0x95, 0x69, 0x8e, 0xdf, 0x4b, 0x64, 0xed, 0xa7, 0x6f, 0x5a, 0x19, 0x06,
+	0x49, 0xc0, 0x91, 0x22, 0xda, 0x97, 0x50, 0x96, 0x1e, 0xa4, 0xee, 0x41, 0x26, 0x39, 0xe0, 0xc5,
+	0x12, 0xa8, 0x39, 0x3a, 0x79, 0x7b, 0x2d, 0x3a, 0xbe, 0xc0, 0xf0, 0x5c, 0xfe, 0x5a, 0x96, 0x38,
+	0xe4, 0xc0, 0xd5, 0xfe, 0xb9, 0x5f, 0xe1, 0x28, 0x8e, 0xbb, 0x99, 0x4c, 0xe3, 0x0e, 0xcc, 0xca,
+	0x39, 0x1d, 0x69, 0x35, 0x78, 0x01, 0x2c, 0x89, 0x14, 0x44, 0xa3, 0x35, 0x90, 0xdb, 0xf8, 0xc1,
+	0xd0, 0x2a, 0xe6, 0xb8, 0x5c, 0xff, 0xa7, 0x13, 0x1c, 0x9f, 0xc1, 0x4a, 0x52, 0xe8, 0x4c, 0xb6,
+	0x94, 0x0c, 0xf8, 0xe9, 0xac, 0x8c, 0xe7, 0x95, 0x66, 0x28, 0x49, 0xf3, 0x4f, 0x72, 0xb0, 0x18,
+	0x23, 0x9a, 0xb5, 0x1e, 0x64, 0xdc, 0xbc, 0x3f, 0x87, 0x59, 0x5a, 0x36, 0x69, 0xd8, 0x72, 0xf1,
+	0xe5, 0xbb, 0xe9, 0x55, 0x3c, 0x09, 0x69, 0xc6, 0x94, 0x60, 0x8e, 0x0e, 0x9c, 0xbf, 0x72, 0x51,
+	0x65, 0x13, 0x16, 0x1f, 0x7b, 0xde, 0x35, 0xeb, 0x7d, 0x07, 0x96, 0xe2, 0x44, 0x33, 0x79, 0xc1,
+	0x1f, 0x29, 0x30, 0xbf, 0x8b, 0xc3, 0x3d, 0xef, 0x2c, 0xb8, 0xee, 0x83, 0x04, 0x89, 0x7c, 0xd8,
+	0x6e, 0x0b, 0xf3, 0xfd, 0x04, 0x6b, 0xd0, 0x88, 0x84, 0x69, 0x3b, 0xfc, 0xf4, 0x40, 0x7f, 0x93,
+	0x8b, 0x82, 0x85, 0x48, 0x88, 0xac, 0x37, 0x9f, 0x27, 0xbd, 0xd3, 0x53, 0xec, 0x47, 0x87, 0xab,
+	0xa8, 0x8d, 0x1e, 0x40, 0xd1, 0xb1, 0xdd, 0xc8, 0x60, 0x5e, 0x1b, 0x34, 0x98, 0x3d, 0xef, 0x8c,
+	0x94, 0xed, 0xeb, 0x0c, 0x4f, 0xfb, 0x2a, 0x4c, 0x73, 0x48, 0x6a, 0xac, 0x45, 0x8a, 0x93, 0xe4,
+	0x62, 0x71, 0x12, 0xed, 0x7b, 0x80, 0x3e, 0x32, 0xc3, 0x56, 0x9b, 0xc6, 0x69, 0xae, 0xbf, 0x68,
+	0x8b, 0x1c, 0xb1, 0x63, 0xf4, 0xb3, 0x1e, 0xb1, 0x79, 0x00, 0x31, 0x37, 0x2c, 0xf0, 0xb8, 0x67,
+	0x9f, 0xe2, 0xd6, 0x65, 0xcb, 0xc1, 0xf1, 0x10, 0xe2, 0x7f, 0xe6, 0x60, 0x3e, 0xfe, 0x08, 0xbd,
+	0xc7, 0xe3, 0x4b, 0xac, 0xac, 0x65, 0x7d, 0x1c, 0xa9, 0xad, 0xa3, 0xcb, 0x2e, 0xe6, 0x61, 0xa8,
+	0x91, 0xa9, 0xd0, 0x54, 0xe9, 0xf9, 0x74, 0xa5, 0x17, 0xe2, 0xc1, 0xa9, 0x51, 0xe7, 0x33, 0xed,
+	0x27, 0x0a, 0x14, 0x08, 0xcf, 0x78, 0xb1, 0xcf, 0x0a, 0xa0, 0xfa, 0x7e, 0x75, 0xb7, 0x66, 0x1c,
+	0x1e, 0xef, 0xed, 0x19, 0xcd, 0xa3, 0xaa, 0x7e, 0x54, 0xdb, 0xa9, 0x28, 0xe8, 0x26, 0x2c, 0x4a,
+	0xf0, 0x27, 0xf5, 0x46, 0xbd, 0xf9, 0xb4, 0xb6, 0x53, 0xc9, 0xa1, 0x65, 0xb8, 0xb1, 0x7d, 0xd0,
+	0x38, 0xaa, 0xd6, 0x1b, 0x35, 0x3d, 0xc2, 0xcf, 0xa3, 0x25, 0xa8, 0xf4, 0xc1, 0xb5, 0x6f, 0xd7,
+	0x09, 0xb4, 0x10, 0x47, 0xde, 0xd6, 0xab, 0x94, 0x46, 0x11, 0xad, 0xc2, 0x52, 0x1f, 0x7c, 0x70,
+	0xb0, 0x6f, 0x7c, 0x58, 0xdf, 0x23, 0x05, 0x19, 0x53, 0xa4, 0xba, 0xa8, 0xf9, 0xbc, 0xb1, 0x6d,
+	0x6c, 0x1f, 0xec, 0x1f, 0xee, 0xd5, 0x08, 0x91, 0x69, 0xf2, 0x76, 0x8b, 0xfc, 0xe4, 0x66, 0xcb,
+	0xcc, 0x7a, 0x5f, 0xf5, 0xc7, 0x0a, 0x2c, 0x27, 0xc8, 0x64, 0xbc, 0x9b, 0x2e, 0x06, 0xa4, 0xfb,
+	0xf0, 0x8d, 0x5a, 0xc4, 0x42, 0x67, 0x98, 0xe4, 0xfc, 0x71, 0xe2, 0x78, 0xad, 0x73, 0x23, 0xc0,
+	0x17, 0xd8, 0x27, 0x97, 0x36, 0xec, 0x94, 0x3a, 0x47, 0xa1, 0x4d, 0x0e, 0xd4, 0xfe, 0x4e, 0x81,
+	0x52, 0xd4, 0xf7, 0xca, 0xc5, 0x19, 0x24, 0x94, 0xd3, 0x32, 0x5d, 0x37, 0x76, 0x6f, 0xc4, 0x21,
+	0xd5, 0xb0, 0x5f, 0x56, 0x53, 0x90, 0xca, 0x6a, 0x50, 0x1d, 0x16, 0x2e, 0x7a, 0x8e, 0x8b, 0x7d,
+	0xf3, 0xc4, 0x76, 0xec, 0xd0, 0x8e, 0x4a, 0x00, 0x53, 0xf6, 0x42, 0xcf, 0x24, 0xc4, 0x4b, 0x3d,
+	0xd9, 0x4f, 0xfb, 0x7b, 0x05, 0xe6, 0x62, 0x28, 0x03, 0xd7, 0xb7, 0xa4, 0xd2, 0xc9, 0x6c, 0x9d,
+	0x4b, 0xce, 0x82, 0x37, 0xd1, 0x17, 0xe1, 0x86, 0xed, 0x06, 0xa1, 0xe9, 0x38, 0xd8, 0x32, 0xe2,
+	0xa9, 0x60, 0x95, 0xe8, 0x01, 0xff, 0x6c, 0x11, 0x09, 0x35, 0x9f, 0xda, 0x2f, 0x25, 0x44, 0x36,
+	0xa2, 0x59, 0x0a, 0x7c, 0x26, 0x67, 0x3c, 0x71, 0x65, 0x17, 0x79, 0x26, 0x06, 0x6f, 0x13, 0x55,
+	0x84, 0x76, 0x18, 0x15, 0x77, 0xb1, 0x86, 0xf6, 0xd7, 0x4a, 0xb4, 0xd1, 0x68, 0x9b, 0x3e, 0xde,
+	0xb3, 0xdd, 0xf3, 0x6c, 0x5e, 0xeb, 0x11, 0x14, 0xbb, 0x9e, 0x1f, 0xf9, 0x94, 0x94, 0x1a, 0x30,
+	0xca, 0xc0, 0x3a, 0xf4, 0xfc, 0x50, 0x67, 0xa8, 0xa3, 0x6e, 0xb1, 0xc5, 0x39, 0xd1, 0x76, 0xc5,
+	0x62, 0xcc, 0x21, 0x75, 0x57, 0xfb, 0x3a, 0x40, 0x9f, 0xd6, 0x08, 0x8b, 0x11, 0xb9, 0xb9, 0x39,
+	0xa9, 0xa0, 0xe2, 0xb7, 0x15, 0xb8, 0x39, 0x30, 0xde, 0x6b, 0xbc, 0x95, 0x5e, 0xa2, 0x4b, 0xcd,
+	0xb9, 0x18, 0x11, 0x6b, 0x24, 0x0e, 0xbe, 0x85, 0xc4, 0xc1, 0x57, 0x3b, 0x87, 0x95, 0x3d, 0xcf,
+	0x3b, 0xef, 0x75, 0x07, 0xea, 0x1d, 0x5e, 0x31, 0x6a, 0x8c, 0xa0, 0xd0, 0x0b, 0xb0, 0x28, 0x9f,
+	0xa5, 0xbf, 0xb5, 0x5f, 0x51, 0xe0, 0xe6, 0x00, 0xb7, 0x8c, 0xf7, 0x87, 0xd3, 0x3e, 0x8d, 0xd7,
+	0x8c, 0x28, 0x51, 0x92, 0x78, 0x10, 0x4c, 0x5d, 0xf4, 0x20, 0xb1, 0x84, 0x85, 0xc4, 0xc3, 0xcc,
+	0x17, 0xc7, 0x8e, 0x69, 0x77, 0xe2, 0x17, 0xc7, 0x0c, 0x52, 0x0d, 0x89, 0x13, 0xf2, 0x31, 0xb9,
+	0x08, 0xc1, 0x96, 0x41, 0x86, 0x2f, 0x12, 0x2a, 0xe7, 0x04, 0xf4, 0x98, 0x00, 0xef, 0xbf, 0x0e,
+	0xa5, 0xe8, 0x4b, 0x23, 0x68, 0x0a, 0x72, 0x07, 0x1f, 0x56, 0x3e, 0x47, 0x4a, 0xeb, 0x88, 0x4b,
+	0xaf, 0x28, 0xf7, 0x7f, 0x9c, 0x23, 0xc1, 0xdd, 0x7e, 0x79, 0x66, 0x7c, 0x31, 0x59, 0x85, 0xa5,
+	0x7a, 0xa3, 0x7e, 0x54, 0xaf, 0xee, 0xd5, 0xbf, 0x53, 0x6f, 0xec, 0x1a, 0xcf, 0x0e, 0xf6, 0x8e,
+	0xf7, 0x6b, 0xcd, 0x8a, 0x82, 0x16, 0x61, 0xe1, 0xa3, 0x6a, 0xfd, 0xc8, 0xd8, 0xa9, 0x91, 0xea,
+	0xbd, 0xa6, 0x71, 0xd0, 0x60, 0xa5, 0xa4, 0x14, 0x48, 0x3d, 0xfe, 0xe3, 0x7a, 0x83, 0xac, 0x23,
+	0x52, 0x7d, 0x5f, 0x41, 0xae, 0x44, 0x2d, 0x92, 0xba, 0x3d, 0xbe, 0xae, 0x4c, 0xb1, 0x0a, 0xbf,
+	0xa7, 0xb5, 0xea, 0xde, 0xd1, 0xd3, 0xe7, 0x95, 0x69, 0x74, 0x03, 0xe6, 0x8e, 0x1b, 0xcd, 0xed,
+	0xa7, 0xb5, 0x9d, 0xe3, 0xbd, 0xea, 0xe3, 0xbd, 0x5a, 0x65, 0x06, 0x55, 0x60, 0x96, 0x88, 0x62,
+	0x1c, 0xd5, 0xf7, 0x6b, 0x07, 0xc7, 0x47, 0x95, 0x12, 0x81, 0xe8, 0xd5, 0xa3, 0x9a, 0xb1, 0x57,
+	0xdf, 0xa7, 0x54, 0x80, 0x50, 0xe1, 0x9d, 0x6a, 0x3b, 0x95, 0x32, 0x45, 0xa8, 0x71, 0x00, 0x61,
+	0x39, 0x4b, 0xc6, 0x43, 0x04, 0x24, 0x43, 0x79, 0x72, 0xa0, 0x1b, 0xdb, 0xd5, 0xc3, 0xea, 0x76,
+	0xfd, 0xe8, 0x79, 0x65, 0xee, 0xd1, 0x4f, 0x37, 0x60, 0x7a, 0x9f, 0x7d, 0x05, 0x0e, 0xb5, 0x61,
+	0x21, 0xf1, 0x95, 0x1e, 0x94, 0x52, 0x39, 0x99, 0xfe, 0xb9, 0x20, 0xf5, 0xcd, 0x09, 0x30, 0x99,
+	0x4d, 0x6a, 0x9f, 0x43, 0x67, 0x30, 0x1f, 0xaf, 0xac, 0x40, 0x1b, 0x13, 0x16, 0x78, 0xa8, 0x9b,
+	0xe3, 0x11, 0x05, 0x9b, 0x87, 0x0a, 0x3a, 0x81, 0xb9, 0x58, 0x02, 0x3e, 0xba, 0x37, 0x59, 0xb5,
+	0x80, 0xba, 0x31, 0x16, 0x2f, 0x1a, 0xcc, 0x09, 0xf9, 0x7a, 0x8d, 0x83, 0x47, 0xf2, 0x48, 0xcb,
+	0xc5, 0x57, 0x37, 0xc6, 0xe2, 0xc9, 0x3c, 0x62, 0xdf, 0x1a, 0x1a, 0x3e, 0x8e, 0xc4, 0xb4, 0x6c,
+	0x8c, 0xc5, 0x8b, 0x78, 0x3c, 0x83, 0x05, 0xf6, 0x99, 0x98, 0xfe, 0xf4, 0xdf, 0x1e, 0xf3, 0x15,
+	0x1c, 0x75, 0x6d, 0x38, 0xc2, 0xa0, 0x7e, 0x46, 0xc8, 0x9e, 0xf6, 0xb5, 0x17, 0x75, 0x63, 0x2c,
+	0x5e, 0xc4, 0xc3, 0x80, 0x59, 0xf9, 0xeb, 0x26, 0x28, 0x65, 0xd7, 0x9a, 0xf2, 0x09, 0x15, 0xf5,
+	0xde, 0x38, 0x34, 0x79, 0x10, 0xb1, 0x4f, 0x96, 0xa4, 0x0d, 0x22, 0xed, 0xcb, 0x28, 0xea, 0xc6,
+	0x58, 0xbc, 0x88, 0xc7, 0xc7, 0x50, 0x96, 0x8a, 0xbe, 0xd0, 0xdd, 0xd4, 0x53, 0x70, 0xa2, 0xea,
+	0x4c, 0x5d, 0x1f, 0x83, 0x25, 0x4d, 0x6f, 0x29, 0xfa, 0x14, 0x06, 0xd2, 0x46, 0x7c, 0x27, 0x43,
+	0x50, 0x7e, 0x63, 0x24, 0x4e, 0x44, 0xd7, 0xa5, 0x21, 0xd0, 0xc4, 0x17, 0xa2, 0xee, 0xa7, 0xf6,
+	0x4d, 0xad, 0x00, 0x54, 0xbf, 0x38, 0x11, 0x6e, 0xc4, 0xef, 0x3b, 0x50, 0xa6, 0x07, 0xa6, 0x6b,
+	0x1f, 0xc9, 0x43, 0x05, 0x7d, 0x8f, 0xd3, 0x66, 0x87, 0xb1, 0xb4, 0x19, 0x18, 0x3c, 0x0b, 0xaa,
+	0xeb, 0x63, 0xb0, 0x24, 0xfa, 0xcf, 0x01, 0xfa, 0x1f, 0xa0, 0x40, 0x6f, 0x8c, 0xfe, 0x3c, 0x05,
+	0xa3, 0x7e, 0x77, 0x92, 0x6f, 0x58, 0x44, 0xc6, 0xc3, 0xc0, 0x38, 0x18, 0x62, 0x3c, 0x89, 0x8f,
+	0xbd, 0xa8, 0xeb, 0x63, 0xb0, 0xe4, 0xf7, 0x4b, 0xfe, 0xe4, 0x66, 0xda, 0xfb, 0x95, 0xf2, 0xc1,
+	0x4f, 0xf5, 0xde, 0x38, 0xb4, 0x88, 0xc1, 0x21, 0x4c, 0xf3, 0xb2, 0x70, 0xb4, 0x96, 0xfa, 0xc6,
+	0x48, 0x85, 0xea, 0xea, 0x9d, 0x11, 0x18, 0x11, 0xc5, 0x6f, 0x43, 0x29, 0x2a, 0x28, 0x4e, 0xb3,
+	0x92, 0x64, 0x75, 0xb4, 0xfa, 0xc6, 0x48, 0x1c, 0x69, 0x16, 0xf7, 0x61, 0x8a, 0x95, 0xf0, 0xa6,
+	0xf9, 0xc7, 0x58, 0x99, 0xb1, 0xba, 0x36, 0x1c, 0x21, 0x12, 0xb4, 0x09, 0x33, 0xa2, 0xbe, 0x16,
+	0xa5, 0x8c, 0x2c, 0x51, 0xd9, 0xab, 0x6a, 0xa3, 0x50, 0x22, 0xa2, 0x3a, 0x4c, 0xf3, 0x1b, 0xd7,
+	0x54, 0x7d, 0xc6, 0xae, 0x99, 0xd5, 0x3b, 0x23, 0x30, 0xa4, 0x71, 0x37, 0x61, 0x46, 0xdc, 0x3f,
+	0xa6, 0x09, 0x9a, 0xb8, 0x16, 0x55, 0xb5, 0x51, 0x28, 0x09, 0xb7, 0xc4, 0xa2, 0xfe, 0x43, 0x5e,
+	0xe6, 0xd8, 0xb5, 0x84, 0xfa, 0xc6, 0x48, 0x1c, 0x99, 0x6e, 0x73, 0x14, 0xdd, 0xe6, 0x04, 0x74,
+	0x9b, 0x29, 0x74, 0x3f, 0x01, 0x34, 0x78, 0x2d, 0x80, 0xd2, 0x7d, 0x58, 0xfa, 0x45, 0x84, 0xfa,
+	0xd6, 0x64, 0xc8, 0x11, 0xcb, 0x6f, 0x41, 0x91, 0xde, 0xd1, 0xa1, 0x94, 0xbc, 0x05, 0xf9, 0x36,
+	0x51, 0xbd, 0x3d, 0xf4, 0xb9, 0xbc, 0x8e, 0xc5, 0xca, 0xc5, 0xd2, 0xd6, 0xb1, 0xb4, 0xaa, 0x34,
+	0x75, 0x63, 0x2c, 0x5e, 0xc2, 0x15, 0x89, 0x27, 0x43, 0x5c, 0x51, 0xa2, 0x60, 0x4c, 0x5d, 0x1f,
+	0x83, 0x25, 0x53, 0x97, 0xca, 0x7c, 0xd2, 0xa8, 0x0f, 0x16, 0x2b, 0xa9, 0xeb, 0x63, 0xb0, 0x64,
+	0xea, 0x52, 0xa1, 0x4c, 0x1a, 0xf5, 0xc1, 0x02, 0x1e, 0x75, 0x7d, 0x0c, 0x56, 0x44, 0xfd, 0x39,
+	0x40, 0xbf, 0xfc, 0x25, 0xcd, 0xff, 0x0f, 0xd4, 0xd7, 0xa8, 0x77, 0x47, 0x23, 0xc9, 0x13, 0x1b,
+	0x2b, 0x37, 0x49, 0x9b, 0xd8, 0xb4, 0x2a, 0x18, 0x75, 0x63, 0x2c, 0x9e, 0xbc, 0x0a, 0xc8, 0xa5,
+	0x1f, 0x69, 0xab, 0x40, 0x4a, 0x3d, 0x8a, 0x7a, 0x6f, 0x1c, 0x5a, 0xc4, 0x00, 0xc3, 0xbc, 0x94,
+	0x06, 0x5b, 0x73, 0x2f, 0xd0, 0x10, 0xb3, 0x1b, 0x28, 0x17, 0x50, 0x37, 0xc7, 0x23, 0xc6, 0x74,
+	0x25, 0x17, 0x05, 0xa4, 0xea, 0x2a, 0xa5, 0x26, 0x41, 0xdd, 0x18, 0x8b, 0x17, 0xf1, 0x68, 0xc3,
+	0x42, 0xa2, 0xf4, 0x20, 0xed, 0x30, 0x95, 0x5e, 0xfb, 0xa0, 0xbe, 0x39, 0x01, 0xe6, 0xe0, 0x0b,
+	0xc1, 0xe2, 0x07, 0x43, 0x5f, 0x08, 0x39, 0xc9, 0x5c, 0x5d, 0x1f, 0x83, 0x95, 0x34, 0x59, 0x0a,
+	0x1e, 0x6a, 0xb2, 0xb1, 0xfc, 0x7b, 0xf5, 0xee, 0x68, 0x24, 0x59, 0x70, 0x29, 0x55, 0x1d, 0xa5,
+	0xde, 0xa8, 0x25, 0xb3, 0xe3, 0xd5, 0xf5, 0x31, 0x58, 0xb2, 0x2d, 0xc5, 0xef, 0xdb, 0xd0, 0xc6,
+	0x84, 0xd7, 0x88, 0xea, 0xe6, 0x78, 0xc4, 0xe4, 0xbe, 0x4b, 0xf0, 0xb8, 0x3b, 0xe6, 0xea, 0x6a,
+	0xe4, 0xbe, 0x6b, 0x90, 0xba, 0x41, 0xf3, 0x45, 0xfa, 0xe4, 0xd7, 0x53, 0x3d, 0xfc, 0x00, 0xfd,
+	0x7b, 0xe3, 0xd0, 0x64, 0x2d, 0xc5, 0x53, 0xaf, 0xd3, 0xb4, 0x94, 0x9a, 0x16, 0xae, 0x6e, 0x8e,
+	0x47, 0x94, 0xb7, 0x77, 0xfc, 0x32, 0x28, 0x6d, 0x3b, 0x12, 0xbf, 0xac, 0x52, 0xef, 0x8c, 0xc0,
+	0x88, 0x28, 0x1e, 0x03, 0x34, 0x43, 0x1f, 0x9b, 0x9d, 0x6b, 0x24, 0xca, 0x02, 0x06, 0xb1, 0xe0,
+	0x7a, 0x9a, 0x6b, 0x48, 0x0b, 0xe2, 0xab, 0x1b, 0x63, 0xf1, 0x64, 0xd7, 0x90, 0x08, 0x58, 0xa2,
+	0xe1, 0x16, 0x97, 0x88, 0xe1, 0xaa, 0x6f, 0x4e, 0x80, 0x99, 0x5c, 0xed, 0xa3, 0xb2, 0x82, 0x61,
+	0xab, 0x7d, 0xb2, 0x62, 0x41, 0xdd, 0x18, 0x8b, 0x27, 0x8f, 0x26, 0x91, 0xd8, 0x9b, 0x36, 0x9a,
+	0xf4, 0xac, 0x62, 0xf5, 0xcd, 0x09, 0x30, 0xe5, 0x97, 0x41, 0x4e, 0x85, 0x4d, 0x7b, 0x19, 0x52,
+	0xf2, 0x74, 0xd5, 0x7b, 0xe3, 0xd0, 0x62, 0x0e, 0xa9, 0x9f, 0xee, 0x9a, 0xea, 0x90, 0x06, 0x92,
+	0x68, 0xd5, 0xf5, 0x31, 0x58, 0xb2, 0xa2, 0x12, 0x51, 0xda, 0x34, 0x45, 0xa5, 0x87, 0x8d, 0xd5,
+	0x37, 0x27, 0xc0, 0x14, 0x9c, 0x1e, 0xdf, 0xff, 0xce, 0xe6, 0x99, 0x1d, 0xb6, 0x7b, 0x27, 0x5b,
+	0x2d, 0xaf, 0xf3, 0xe0, 0x1c, 0x3b, 0x96, 0xf9, 0x80, 0xfd, 0xbf, 0x86, 0xee, 0xf9, 0xd9, 0x03,
+	0xfa, 0x2f, 0x1a, 0xc4, 0x7f, 0x81, 0x38, 0x99, 0xa2, 0xcd, 0x77, 0xfe, 0x67, 0x00, 0x23, 0x7b,
+	0xfe, 0x21, 0x1d, 0x62, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ManagerClient is the client API for Manager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ManagerClient interface {
+	AttachToSandbox(ctx context.Context, in *AttachToSandboxRequest, opts ...grpc.CallOption) (*AttachToSandboxResponse, error)
+	BlimpUpPreview(ctx context.Context, in *BlimpUpPreviewRequest, opts ...grpc.CallOption) (Manager_BlimpUpPreviewClient, error)
+	CreatePreview(ctx context.Context, in *CreatePreviewRequest, opts ...grpc.CallOption) (*CreatePreviewResponse, error)
+	DeletePreview(ctx context.Context, in *DeletePreviewRequest, opts ...grpc.CallOption) (*DeletePreviewResponse, error)
+	CreateSandbox(ctx context.Context, in *CreateSandboxRequest, opts ...grpc.CallOption) (*CreateSandboxResponse, error)
+	DeployToSandbox(ctx context.Context, in *DeployRequest, opts ...grpc.CallOption) (*DeployResponse, error)
+	DeleteSandbox(ctx context.Context, in *DeleteSandboxRequest, opts ...grpc.CallOption) (*DeleteSandboxResponse, error)
+	PauseSandbox(ctx context.Context, in *PauseSandboxRequest, opts ...grpc.CallOption) (*PauseSandboxResponse, error)
+	ResumeSandbox(ctx context.Context, in *ResumeSandboxRequest, opts ...grpc.CallOption) (*ResumeSandboxResponse, error)
+	GetBuildkit(ctx context.Context, in *GetBuildkitRequest, opts ...grpc.CallOption) (*GetBuildkitResponse, error)
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	GetImageNamespace(ctx context.Context, in *GetImageNamespaceRequest, opts ...grpc.CallOption) (*GetImageNamespaceResponse, error)
+	WatchStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (Manager_WatchStatusClient, error)
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (Manager_WatchEventsClient, error)
+	PollStatus(ctx context.Context, in *PollStatusRequest, opts ...grpc.CallOption) (*PollStatusResponse, error)
+	GetStatuses(ctx context.Context, in *GetStatusesRequest, opts ...grpc.CallOption) (*GetStatusesResponse, error)
+	CheckVersion(ctx context.Context, in *CheckVersionRequest, opts ...grpc.CallOption) (*CheckVersionResponse, error)
+	Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error)
+	TagImages(ctx context.Context, in *TagImagesRequest, opts ...grpc.CallOption) (Manager_TagImagesClient, error)
+	Expose(ctx context.Context, in *ExposeRequest, opts ...grpc.CallOption) (*ExposeResponse, error)
+	Unexpose(ctx context.Context, in *UnexposeRequest, opts ...grpc.CallOption) (*UnexposeResponse, error)
+	RunTest(ctx context.Context, in *RunTestRequest, opts ...grpc.CallOption) (Manager_RunTestClient, error)
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
+	GetFaults(ctx context.Context, in *GetFaultsRequest, opts ...grpc.CallOption) (*GetFaultsResponse, error)
+	SetFaults(ctx context.Context, in *SetFaultsRequest, opts ...grpc.CallOption) (*SetFaultsResponse, error)
+	GetDependencyGraph(ctx context.Context, in *GetDependencyGraphRequest, opts ...grpc.CallOption) (*GetDependencyGraphResponse, error)
+	Boost(ctx context.Context, in *BoostRequest, opts ...grpc.CallOption) (*BoostResponse, error)
+	ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error)
+	GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error)
+	CreateAddon(ctx context.Context, in *CreateAddonRequest, opts ...grpc.CallOption) (*CreateAddonResponse, error)
+	DeleteAddon(ctx context.Context, in *DeleteAddonRequest, opts ...grpc.CallOption) (*DeleteAddonResponse, error)
+	ListAddons(ctx context.Context, in *ListAddonsRequest, opts ...grpc.CallOption) (*ListAddonsResponse, error)
+	SnapshotAddon(ctx context.Context, in *SnapshotAddonRequest, opts ...grpc.CallOption) (*SnapshotAddonResponse, error)
+	RestoreAddon(ctx context.Context, in *RestoreAddonRequest, opts ...grpc.CallOption) (*RestoreAddonResponse, error)
+	ListSandboxEnv(ctx context.Context, in *ListSandboxEnvRequest, opts ...grpc.CallOption) (*ListSandboxEnvResponse, error)
+	SetSandboxEnv(ctx context.Context, in *SetSandboxEnvRequest, opts ...grpc.CallOption) (*SetSandboxEnvResponse, error)
+	UnsetSandboxEnv(ctx context.Context, in *UnsetSandboxEnvRequest, opts ...grpc.CallOption) (*UnsetSandboxEnvResponse, error)
+	CreateToken(ctx context.Context, in *CreateTokenRequest, opts ...grpc.CallOption) (*CreateTokenResponse, error)
+	ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensResponse, error)
+	RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenResponse, error)
+	CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*CreateSnapshotResponse, error)
+	GetSnapshot(ctx context.Context, in *GetSnapshotRequest, opts ...grpc.CallOption) (*GetSnapshotResponse, error)
+	BootSnapshot(ctx context.Context, in *BootSnapshotRequest, opts ...grpc.CallOption) (*BootSnapshotResponse, error)
+	GetDiagnostics(ctx context.Context, in *GetDiagnosticsRequest, opts ...grpc.CallOption) (*GetDiagnosticsResponse, error)
+	GetLogs(ctx context.Context, in *GetLogsRequest, opts ...grpc.CallOption) (*GetLogsResponse, error)
+	StreamLogs(ctx context.Context, in *GetLogsRequest, opts ...grpc.CallOption) (Manager_StreamLogsClient, error)
+	GetImageScans(ctx context.Context, in *GetImageScansRequest, opts ...grpc.CallOption) (*GetImageScansResponse, error)
+	CreateShareLink(ctx context.Context, in *CreateShareLinkRequest, opts ...grpc.CallOption) (*CreateShareLinkResponse, error)
+	// The following RPCs are for cluster operators, and require the admin
+	// token configured on the manager.
+	ListSandboxes(ctx context.Context, in *ListSandboxesRequest, opts ...grpc.CallOption) (*ListSandboxesResponse, error)
+	DescribeSandbox(ctx context.Context, in *DescribeSandboxRequest, opts ...grpc.CallOption) (*DescribeSandboxResponse, error)
+	EvictSandbox(ctx context.Context, in *EvictSandboxRequest, opts ...grpc.CallOption) (*EvictSandboxResponse, error)
+	RunSelfTest(ctx context.Context, in *RunSelfTestRequest, opts ...grpc.CallOption) (*RunSelfTestResponse, error)
+	LookupNamespace(ctx context.Context, in *LookupNamespaceRequest, opts ...grpc.CallOption) (*LookupNamespaceResponse, error)
+}
+
+type managerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewManagerClient(cc grpc.ClientConnInterface) ManagerClient {
+	return &managerClient{cc}
+}
+
+func (c *managerClient) AttachToSandbox(ctx context.Context, in *AttachToSandboxRequest, opts ...grpc.CallOption) (*AttachToSandboxResponse, error) {
+	out := new(AttachToSandboxResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/AttachToSandbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) BlimpUpPreview(ctx context.Context, in *BlimpUpPreviewRequest, opts ...grpc.CallOption) (Manager_BlimpUpPreviewClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Manager_serviceDesc.Streams[0], "/blimp.cluster.v0.Manager/BlimpUpPreview", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &managerBlimpUpPreviewClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Manager_BlimpUpPreviewClient interface {
+	Recv() (*BlimpUpPreviewResponse, error)
+	grpc.ClientStream
+}
+
+type managerBlimpUpPreviewClient struct {
+	grpc.ClientStream
+}
+
+func (x *managerBlimpUpPreviewClient) Recv() (*BlimpUpPreviewResponse, error) {
+	m := new(BlimpUpPreviewResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *managerClient) CreatePreview(ctx context.Context, in *CreatePreviewRequest, opts ...grpc.CallOption) (*CreatePreviewResponse, error) {
+	out := new(CreatePreviewResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/CreatePreview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) DeletePreview(ctx context.Context, in *DeletePreviewRequest, opts ...grpc.CallOption) (*DeletePreviewResponse, error) {
+	out := new(DeletePreviewResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/DeletePreview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) CreateSandbox(ctx context.Context, in *CreateSandboxRequest, opts ...grpc.CallOption) (*CreateSandboxResponse, error) {
+	out := new(CreateSandboxResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/CreateSandbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) DeployToSandbox(ctx context.Context, in *DeployRequest, opts ...grpc.CallOption) (*DeployResponse, error) {
+	out := new(DeployResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/DeployToSandbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) DeleteSandbox(ctx context.Context, in *DeleteSandboxRequest, opts ...grpc.CallOption) (*DeleteSandboxResponse, error) {
+	out := new(DeleteSandboxResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/DeleteSandbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) PauseSandbox(ctx context.Context, in *PauseSandboxRequest, opts ...grpc.CallOption) (*PauseSandboxResponse, error) {
+	out := new(PauseSandboxResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/PauseSandbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) ResumeSandbox(ctx context.Context, in *ResumeSandboxRequest, opts ...grpc.CallOption) (*ResumeSandboxResponse, error) {
+	out := new(ResumeSandboxResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/ResumeSandbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 func (c *managerClient) GetBuildkit(ctx context.Context, in *GetBuildkitRequest, opts ...grpc.CallOption) (*GetBuildkitResponse, error) {
@@ -2041,453 +8337,1706 @@ func (c *managerClient) GetBuildkit(ctx context.Context, in *GetBuildkitRequest,
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *managerClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) GetImageNamespace(ctx context.Context, in *GetImageNamespaceRequest, opts ...grpc.CallOption) (*GetImageNamespaceResponse, error) {
+	out := new(GetImageNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetImageNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) WatchStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (Manager_WatchStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Manager_serviceDesc.Streams[1], "/blimp.cluster.v0.Manager/WatchStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &managerWatchStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Manager_WatchStatusClient interface {
+	Recv() (*GetStatusResponse, error)
+	grpc.ClientStream
+}
+
+type managerWatchStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *managerWatchStatusClient) Recv() (*GetStatusResponse, error) {
+	m := new(GetStatusResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *managerClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (Manager_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Manager_serviceDesc.Streams[2], "/blimp.cluster.v0.Manager/WatchEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &managerWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Manager_WatchEventsClient interface {
+	Recv() (*WatchEventsResponse, error)
+	grpc.ClientStream
+}
+
+type managerWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *managerWatchEventsClient) Recv() (*WatchEventsResponse, error) {
+	m := new(WatchEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *managerClient) PollStatus(ctx context.Context, in *PollStatusRequest, opts ...grpc.CallOption) (*PollStatusResponse, error) {
+	out := new(PollStatusResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/PollStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) GetStatuses(ctx context.Context, in *GetStatusesRequest, opts ...grpc.CallOption) (*GetStatusesResponse, error) {
+	out := new(GetStatusesResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetStatuses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) CheckVersion(ctx context.Context, in *CheckVersionRequest, opts ...grpc.CallOption) (*CheckVersionResponse, error) {
+	out := new(CheckVersionResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/CheckVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error) {
+	out := new(RestartResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/Restart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) TagImages(ctx context.Context, in *TagImagesRequest, opts ...grpc.CallOption) (Manager_TagImagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Manager_serviceDesc.Streams[3], "/blimp.cluster.v0.Manager/TagImages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &managerTagImagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Manager_TagImagesClient interface {
+	Recv() (*TagImagesResponse, error)
+	grpc.ClientStream
+}
+
+type managerTagImagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *managerTagImagesClient) Recv() (*TagImagesResponse, error) {
+	m := new(TagImagesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *managerClient) Expose(ctx context.Context, in *ExposeRequest, opts ...grpc.CallOption) (*ExposeResponse, error) {
+	out := new(ExposeResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/Expose", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) Unexpose(ctx context.Context, in *UnexposeRequest, opts ...grpc.CallOption) (*UnexposeResponse, error) {
+	out := new(UnexposeResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/Unexpose", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) RunTest(ctx context.Context, in *RunTestRequest, opts ...grpc.CallOption) (Manager_RunTestClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Manager_serviceDesc.Streams[4], "/blimp.cluster.v0.Manager/RunTest", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &managerRunTestClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Manager_RunTestClient interface {
+	Recv() (*RunTestResponse, error)
+	grpc.ClientStream
+}
+
+type managerRunTestClient struct {
+	grpc.ClientStream
+}
+
+func (x *managerRunTestClient) Recv() (*RunTestResponse, error) {
+	m := new(RunTestResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *managerClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) GetFaults(ctx context.Context, in *GetFaultsRequest, opts ...grpc.CallOption) (*GetFaultsResponse, error) {
+	out := new(GetFaultsResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetFaults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) SetFaults(ctx context.Context, in *SetFaultsRequest, opts ...grpc.CallOption) (*SetFaultsResponse, error) {
+	out := new(SetFaultsResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/SetFaults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) GetDependencyGraph(ctx context.Context, in *GetDependencyGraphRequest, opts ...grpc.CallOption) (*GetDependencyGraphResponse, error) {
+	out := new(GetDependencyGraphResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetDependencyGraph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) Boost(ctx context.Context, in *BoostRequest, opts ...grpc.CallOption) (*BoostResponse, error) {
+	out := new(BoostResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/Boost", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error) {
+	out := new(ListTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/ListTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error) {
+	out := new(GetTemplateResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) CreateAddon(ctx context.Context, in *CreateAddonRequest, opts ...grpc.CallOption) (*CreateAddonResponse, error) {
+	out := new(CreateAddonResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/CreateAddon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) DeleteAddon(ctx context.Context, in *DeleteAddonRequest, opts ...grpc.CallOption) (*DeleteAddonResponse, error) {
+	out := new(DeleteAddonResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/DeleteAddon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) ListAddons(ctx context.Context, in *ListAddonsRequest, opts ...grpc.CallOption) (*ListAddonsResponse, error) {
+	out := new(ListAddonsResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/ListAddons", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) SnapshotAddon(ctx context.Context, in *SnapshotAddonRequest, opts ...grpc.CallOption) (*SnapshotAddonResponse, error) {
+	out := new(SnapshotAddonResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/SnapshotAddon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) RestoreAddon(ctx context.Context, in *RestoreAddonRequest, opts ...grpc.CallOption) (*RestoreAddonResponse, error) {
+	out := new(RestoreAddonResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/RestoreAddon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) ListSandboxEnv(ctx context.Context, in *ListSandboxEnvRequest, opts ...grpc.CallOption) (*ListSandboxEnvResponse, error) {
+	out := new(ListSandboxEnvResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/ListSandboxEnv", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) SetSandboxEnv(ctx context.Context, in *SetSandboxEnvRequest, opts ...grpc.CallOption) (*SetSandboxEnvResponse, error) {
+	out := new(SetSandboxEnvResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/SetSandboxEnv", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) UnsetSandboxEnv(ctx context.Context, in *UnsetSandboxEnvRequest, opts ...grpc.CallOption) (*UnsetSandboxEnvResponse, error) {
+	out := new(UnsetSandboxEnvResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/UnsetSandboxEnv", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) CreateToken(ctx context.Context, in *CreateTokenRequest, opts ...grpc.CallOption) (*CreateTokenResponse, error) {
+	out := new(CreateTokenResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/CreateToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensResponse, error) {
+	out := new(ListTokensResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/ListTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenResponse, error) {
+	out := new(RevokeTokenResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/RevokeToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*CreateSnapshotResponse, error) {
+	out := new(CreateSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/CreateSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) GetSnapshot(ctx context.Context, in *GetSnapshotRequest, opts ...grpc.CallOption) (*GetSnapshotResponse, error) {
+	out := new(GetSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) BootSnapshot(ctx context.Context, in *BootSnapshotRequest, opts ...grpc.CallOption) (*BootSnapshotResponse, error) {
+	out := new(BootSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/BootSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) GetDiagnostics(ctx context.Context, in *GetDiagnosticsRequest, opts ...grpc.CallOption) (*GetDiagnosticsResponse, error) {
+	out := new(GetDiagnosticsResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetDiagnostics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) GetLogs(ctx context.Context, in *GetLogsRequest, opts ...grpc.CallOption) (*GetLogsResponse, error) {
+	out := new(GetLogsResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) StreamLogs(ctx context.Context, in *GetLogsRequest, opts ...grpc.CallOption) (Manager_StreamLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Manager_serviceDesc.Streams[5], "/blimp.cluster.v0.Manager/StreamLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &managerStreamLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Manager_StreamLogsClient interface {
+	Recv() (*GetLogsResponse, error)
+	grpc.ClientStream
+}
+
+type managerStreamLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *managerStreamLogsClient) Recv() (*GetLogsResponse, error) {
+	m := new(GetLogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *managerClient) GetImageScans(ctx context.Context, in *GetImageScansRequest, opts ...grpc.CallOption) (*GetImageScansResponse, error) {
+	out := new(GetImageScansResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetImageScans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) CreateShareLink(ctx context.Context, in *CreateShareLinkRequest, opts ...grpc.CallOption) (*CreateShareLinkResponse, error) {
+	out := new(CreateShareLinkResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/CreateShareLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) ListSandboxes(ctx context.Context, in *ListSandboxesRequest, opts ...grpc.CallOption) (*ListSandboxesResponse, error) {
+	out := new(ListSandboxesResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/ListSandboxes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) DescribeSandbox(ctx context.Context, in *DescribeSandboxRequest, opts ...grpc.CallOption) (*DescribeSandboxResponse, error) {
+	out := new(DescribeSandboxResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/DescribeSandbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) EvictSandbox(ctx context.Context, in *EvictSandboxRequest, opts ...grpc.CallOption) (*EvictSandboxResponse, error) {
+	out := new(EvictSandboxResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/EvictSandbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) RunSelfTest(ctx context.Context, in *RunSelfTestRequest, opts ...grpc.CallOption) (*RunSelfTestResponse, error) {
+	out := new(RunSelfTestResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/RunSelfTest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) LookupNamespace(ctx context.Context, in *LookupNamespaceRequest, opts ...grpc.CallOption) (*LookupNamespaceResponse, error) {
+	out := new(LookupNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/LookupNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ManagerServer is the server API for Manager service.
+type ManagerServer interface {
+	AttachToSandbox(context.Context, *AttachToSandboxRequest) (*AttachToSandboxResponse, error)
+	BlimpUpPreview(*BlimpUpPreviewRequest, Manager_BlimpUpPreviewServer) error
+	CreatePreview(context.Context, *CreatePreviewRequest) (*CreatePreviewResponse, error)
+	DeletePreview(context.Context, *DeletePreviewRequest) (*DeletePreviewResponse, error)
+	CreateSandbox(context.Context, *CreateSandboxRequest) (*CreateSandboxResponse, error)
+	DeployToSandbox(context.Context, *DeployRequest) (*DeployResponse, error)
+	DeleteSandbox(context.Context, *DeleteSandboxRequest) (*DeleteSandboxResponse, error)
+	PauseSandbox(context.Context, *PauseSandboxRequest) (*PauseSandboxResponse, error)
+	ResumeSandbox(context.Context, *ResumeSandboxRequest) (*ResumeSandboxResponse, error)
+	GetBuildkit(context.Context, *GetBuildkitRequest) (*GetBuildkitResponse, error)
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	GetImageNamespace(context.Context, *GetImageNamespaceRequest) (*GetImageNamespaceResponse, error)
+	WatchStatus(*GetStatusRequest, Manager_WatchStatusServer) error
+	WatchEvents(*WatchEventsRequest, Manager_WatchEventsServer) error
+	PollStatus(context.Context, *PollStatusRequest) (*PollStatusResponse, error)
+	GetStatuses(context.Context, *GetStatusesRequest) (*GetStatusesResponse, error)
+	CheckVersion(context.Context, *CheckVersionRequest) (*CheckVersionResponse, error)
+	Restart(context.Context, *RestartRequest) (*RestartResponse, error)
+	TagImages(*TagImagesRequest, Manager_TagImagesServer) error
+	Expose(context.Context, *ExposeRequest) (*ExposeResponse, error)
+	Unexpose(context.Context, *UnexposeRequest) (*UnexposeResponse, error)
+	RunTest(*RunTestRequest, Manager_RunTestServer) error
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
+	GetFaults(context.Context, *GetFaultsRequest) (*GetFaultsResponse, error)
+	SetFaults(context.Context, *SetFaultsRequest) (*SetFaultsResponse, error)
+	GetDependencyGraph(context.Context, *GetDependencyGraphRequest) (*GetDependencyGraphResponse, error)
+	Boost(context.Context, *BoostRequest) (*BoostResponse, error)
+	ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error)
+	GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error)
+	CreateAddon(context.Context, *CreateAddonRequest) (*CreateAddonResponse, error)
+	DeleteAddon(context.Context, *DeleteAddonRequest) (*DeleteAddonResponse, error)
+	ListAddons(context.Context, *ListAddonsRequest) (*ListAddonsResponse, error)
+	SnapshotAddon(context.Context, *SnapshotAddonRequest) (*SnapshotAddonResponse, error)
+	RestoreAddon(context.Context, *RestoreAddonRequest) (*RestoreAddonResponse, error)
+	ListSandboxEnv(context.Context, *ListSandboxEnvRequest) (*ListSandboxEnvResponse, error)
+	SetSandboxEnv(context.Context, *SetSandboxEnvRequest) (*SetSandboxEnvResponse, error)
+	UnsetSandboxEnv(context.Context, *UnsetSandboxEnvRequest) (*UnsetSandboxEnvResponse, error)
+	CreateToken(context.Context, *CreateTokenRequest) (*CreateTokenResponse, error)
+	ListTokens(context.Context, *ListTokensRequest) (*ListTokensResponse, error)
+	RevokeToken(context.Context, *RevokeTokenRequest) (*RevokeTokenResponse, error)
+	CreateSnapshot(context.Context, *CreateSnapshotRequest) (*CreateSnapshotResponse, error)
+	GetSnapshot(context.Context, *GetSnapshotRequest) (*GetSnapshotResponse, error)
+	BootSnapshot(context.Context, *BootSnapshotRequest) (*BootSnapshotResponse, error)
+	GetDiagnostics(context.Context, *GetDiagnosticsRequest) (*GetDiagnosticsResponse, error)
+	GetLogs(context.Context, *GetLogsRequest) (*GetLogsResponse, error)
+	StreamLogs(*GetLogsRequest, Manager_StreamLogsServer) error
+	GetImageScans(context.Context, *GetImageScansRequest) (*GetImageScansResponse, error)
+	CreateShareLink(context.Context, *CreateShareLinkRequest) (*CreateShareLinkResponse, error)
+	// The following RPCs are for cluster operators, and require the admin
+	// token configured on the manager.
+	ListSandboxes(context.Context, *ListSandboxesRequest) (*ListSandboxesResponse, error)
+	DescribeSandbox(context.Context, *DescribeSandboxRequest) (*DescribeSandboxResponse, error)
+	EvictSandbox(context.Context, *EvictSandboxRequest) (*EvictSandboxResponse, error)
+	RunSelfTest(context.Context, *RunSelfTestRequest) (*RunSelfTestResponse, error)
+	LookupNamespace(context.Context, *LookupNamespaceRequest) (*LookupNamespaceResponse, error)
+}
+
+// UnimplementedManagerServer can be embedded to have forward compatible implementations.
+type UnimplementedManagerServer struct {
+}
+
+func (*UnimplementedManagerServer) AttachToSandbox(ctx context.Context, req *AttachToSandboxRequest) (*AttachToSandboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AttachToSandbox not implemented")
+}
+func (*UnimplementedManagerServer) BlimpUpPreview(req *BlimpUpPreviewRequest, srv Manager_BlimpUpPreviewServer) error {
+	return status.Errorf(codes.Unimplemented, "method BlimpUpPreview not implemented")
+}
+func (*UnimplementedManagerServer) CreatePreview(ctx context.Context, req *CreatePreviewRequest) (*CreatePreviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePreview not implemented")
+}
+func (*UnimplementedManagerServer) DeletePreview(ctx context.Context, req *DeletePreviewRequest) (*DeletePreviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePreview not implemented")
+}
+func (*UnimplementedManagerServer) CreateSandbox(ctx context.Context, req *CreateSandboxRequest) (*CreateSandboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSandbox not implemented")
+}
+func (*UnimplementedManagerServer) DeployToSandbox(ctx context.Context, req *DeployRequest) (*DeployResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeployToSandbox not implemented")
+}
+func (*UnimplementedManagerServer) DeleteSandbox(ctx context.Context, req *DeleteSandboxRequest) (*DeleteSandboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSandbox not implemented")
+}
+func (*UnimplementedManagerServer) PauseSandbox(ctx context.Context, req *PauseSandboxRequest) (*PauseSandboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseSandbox not implemented")
+}
+func (*UnimplementedManagerServer) ResumeSandbox(ctx context.Context, req *ResumeSandboxRequest) (*ResumeSandboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeSandbox not implemented")
+}
+func (*UnimplementedManagerServer) GetBuildkit(ctx context.Context, req *GetBuildkitRequest) (*GetBuildkitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBuildkit not implemented")
+}
+func (*UnimplementedManagerServer) GetStatus(ctx context.Context, req *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (*UnimplementedManagerServer) GetImageNamespace(ctx context.Context, req *GetImageNamespaceRequest) (*GetImageNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImageNamespace not implemented")
+}
+func (*UnimplementedManagerServer) WatchStatus(req *GetStatusRequest, srv Manager_WatchStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
+func (*UnimplementedManagerServer) WatchEvents(req *WatchEventsRequest, srv Manager_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (*UnimplementedManagerServer) PollStatus(ctx context.Context, req *PollStatusRequest) (*PollStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PollStatus not implemented")
+}
+func (*UnimplementedManagerServer) GetStatuses(ctx context.Context, req *GetStatusesRequest) (*GetStatusesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatuses not implemented")
+}
+func (*UnimplementedManagerServer) CheckVersion(ctx context.Context, req *CheckVersionRequest) (*CheckVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckVersion not implemented")
+}
+func (*UnimplementedManagerServer) Restart(ctx context.Context, req *RestartRequest) (*RestartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restart not implemented")
+}
+func (*UnimplementedManagerServer) TagImages(req *TagImagesRequest, srv Manager_TagImagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method TagImages not implemented")
+}
+func (*UnimplementedManagerServer) Expose(ctx context.Context, req *ExposeRequest) (*ExposeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Expose not implemented")
+}
+func (*UnimplementedManagerServer) Unexpose(ctx context.Context, req *UnexposeRequest) (*UnexposeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unexpose not implemented")
+}
+func (*UnimplementedManagerServer) RunTest(req *RunTestRequest, srv Manager_RunTestServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunTest not implemented")
+}
+func (*UnimplementedManagerServer) GetUsage(ctx context.Context, req *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (*UnimplementedManagerServer) GetFaults(ctx context.Context, req *GetFaultsRequest) (*GetFaultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFaults not implemented")
+}
+func (*UnimplementedManagerServer) SetFaults(ctx context.Context, req *SetFaultsRequest) (*SetFaultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFaults not implemented")
+}
+func (*UnimplementedManagerServer) GetDependencyGraph(ctx context.Context, req *GetDependencyGraphRequest) (*GetDependencyGraphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependencyGraph not implemented")
+}
+func (*UnimplementedManagerServer) Boost(ctx context.Context, req *BoostRequest) (*BoostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Boost not implemented")
+}
+func (*UnimplementedManagerServer) ListTemplates(ctx context.Context, req *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTemplates not implemented")
+}
+func (*UnimplementedManagerServer) GetTemplate(ctx context.Context, req *GetTemplateRequest) (*GetTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTemplate not implemented")
+}
+func (*UnimplementedManagerServer) CreateAddon(ctx context.Context, req *CreateAddonRequest) (*CreateAddonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAddon not implemented")
+}
+func (*UnimplementedManagerServer) DeleteAddon(ctx context.Context, req *DeleteAddonRequest) (*DeleteAddonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAddon not implemented")
+}
+func (*UnimplementedManagerServer) ListAddons(ctx context.Context, req *ListAddonsRequest) (*ListAddonsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAddons not implemented")
+}
+func (*UnimplementedManagerServer) SnapshotAddon(ctx context.Context, req *SnapshotAddonRequest) (*SnapshotAddonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotAddon not implemented")
+}
+func (*UnimplementedManagerServer) RestoreAddon(ctx context.Context, req *RestoreAddonRequest) (*RestoreAddonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreAddon not implemented")
+}
+func (*UnimplementedManagerServer) ListSandboxEnv(ctx context.Context, req *ListSandboxEnvRequest) (*ListSandboxEnvResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSandboxEnv not implemented")
+}
+func (*UnimplementedManagerServer) SetSandboxEnv(ctx context.Context, req *SetSandboxEnvRequest) (*SetSandboxEnvResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSandboxEnv not implemented")
+}
+func (*UnimplementedManagerServer) UnsetSandboxEnv(ctx context.Context, req *UnsetSandboxEnvRequest) (*UnsetSandboxEnvResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnsetSandboxEnv not implemented")
+}
+func (*UnimplementedManagerServer) CreateToken(ctx context.Context, req *CreateTokenRequest) (*CreateTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateToken not implemented")
+}
+func (*UnimplementedManagerServer) ListTokens(ctx context.Context, req *ListTokensRequest) (*ListTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTokens not implemented")
+}
+func (*UnimplementedManagerServer) RevokeToken(ctx context.Context, req *RevokeTokenRequest) (*RevokeTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeToken not implemented")
+}
+func (*UnimplementedManagerServer) CreateSnapshot(ctx context.Context, req *CreateSnapshotRequest) (*CreateSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSnapshot not implemented")
+}
+func (*UnimplementedManagerServer) GetSnapshot(ctx context.Context, req *GetSnapshotRequest) (*GetSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshot not implemented")
+}
+func (*UnimplementedManagerServer) BootSnapshot(ctx context.Context, req *BootSnapshotRequest) (*BootSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BootSnapshot not implemented")
+}
+func (*UnimplementedManagerServer) GetDiagnostics(ctx context.Context, req *GetDiagnosticsRequest) (*GetDiagnosticsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDiagnostics not implemented")
+}
+func (*UnimplementedManagerServer) GetLogs(ctx context.Context, req *GetLogsRequest) (*GetLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogs not implemented")
+}
+func (*UnimplementedManagerServer) StreamLogs(req *GetLogsRequest, srv Manager_StreamLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLogs not implemented")
+}
+func (*UnimplementedManagerServer) GetImageScans(ctx context.Context, req *GetImageScansRequest) (*GetImageScansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImageScans not implemented")
+}
+func (*UnimplementedManagerServer) CreateShareLink(ctx context.Context, req *CreateShareLinkRequest) (*CreateShareLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateShareLink not implemented")
+}
+func (*UnimplementedManagerServer) ListSandboxes(ctx context.Context, req *ListSandboxesRequest) (*ListSandboxesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSandboxes not implemented")
+}
+func (*UnimplementedManagerServer) DescribeSandbox(ctx context.Context, req *DescribeSandboxRequest) (*DescribeSandboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeSandbox not implemented")
+}
+func (*UnimplementedManagerServer) EvictSandbox(ctx context.Context, req *EvictSandboxRequest) (*EvictSandboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EvictSandbox not implemented")
+}
+func (*UnimplementedManagerServer) RunSelfTest(ctx context.Context, req *RunSelfTestRequest) (*RunSelfTestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunSelfTest not implemented")
+}
+func (*UnimplementedManagerServer) LookupNamespace(ctx context.Context, req *LookupNamespaceRequest) (*LookupNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupNamespace not implemented")
+}
+
+func RegisterManagerServer(s *grpc.Server, srv ManagerServer) {
+	s.RegisterService(&_Manager_serviceDesc, srv)
+}
+
+func _Manager_AttachToSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachToSandboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).AttachToSandbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/AttachToSandbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).AttachToSandbox(ctx, req.(*AttachToSandboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_BlimpUpPreview_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlimpUpPreviewRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ManagerServer).BlimpUpPreview(m, &managerBlimpUpPreviewServer{stream})
+}
+
+type Manager_BlimpUpPreviewServer interface {
+	Send(*BlimpUpPreviewResponse) error
+	grpc.ServerStream
+}
+
+type managerBlimpUpPreviewServer struct {
+	grpc.ServerStream
+}
+
+func (x *managerBlimpUpPreviewServer) Send(m *BlimpUpPreviewResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Manager_CreatePreview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePreviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).CreatePreview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/CreatePreview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).CreatePreview(ctx, req.(*CreatePreviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_DeletePreview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePreviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).DeletePreview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/DeletePreview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).DeletePreview(ctx, req.(*DeletePreviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_CreateSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSandboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).CreateSandbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/CreateSandbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).CreateSandbox(ctx, req.(*CreateSandboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_DeployToSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeployRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).DeployToSandbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/DeployToSandbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).DeployToSandbox(ctx, req.(*DeployRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *managerClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
-	out := new(GetStatusResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetStatus", in, out, opts...)
-	if err != nil {
+func _Manager_DeleteSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSandboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).DeleteSandbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/DeleteSandbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).DeleteSandbox(ctx, req.(*DeleteSandboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_PauseSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseSandboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).PauseSandbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/PauseSandbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).PauseSandbox(ctx, req.(*PauseSandboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_ResumeSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeSandboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).ResumeSandbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/ResumeSandbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).ResumeSandbox(ctx, req.(*ResumeSandboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_GetBuildkit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBuildkitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).GetBuildkit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/GetBuildkit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).GetBuildkit(ctx, req.(*GetBuildkitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_GetImageNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImageNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).GetImageNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/GetImageNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).GetImageNamespace(ctx, req.(*GetImageNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ManagerServer).WatchStatus(m, &managerWatchStatusServer{stream})
+}
+
+type Manager_WatchStatusServer interface {
+	Send(*GetStatusResponse) error
+	grpc.ServerStream
+}
+
+type managerWatchStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *managerWatchStatusServer) Send(m *GetStatusResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Manager_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ManagerServer).WatchEvents(m, &managerWatchEventsServer{stream})
+}
+
+type Manager_WatchEventsServer interface {
+	Send(*WatchEventsResponse) error
+	grpc.ServerStream
+}
+
+type managerWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *managerWatchEventsServer) Send(m *WatchEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Manager_PollStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PollStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).PollStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/PollStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).PollStatus(ctx, req.(*PollStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_GetStatuses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).GetStatuses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/GetStatuses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).GetStatuses(ctx, req.(*GetStatusesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_CheckVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).CheckVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/CheckVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).CheckVersion(ctx, req.(*CheckVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_Restart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).Restart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/Restart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).Restart(ctx, req.(*RestartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_TagImages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TagImagesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ManagerServer).TagImages(m, &managerTagImagesServer{stream})
+}
+
+type Manager_TagImagesServer interface {
+	Send(*TagImagesResponse) error
+	grpc.ServerStream
+}
+
+type managerTagImagesServer struct {
+	grpc.ServerStream
+}
+
+func (x *managerTagImagesServer) Send(m *TagImagesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Manager_Expose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExposeRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ManagerServer).Expose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/Expose",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).Expose(ctx, req.(*ExposeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *managerClient) GetImageNamespace(ctx context.Context, in *GetImageNamespaceRequest, opts ...grpc.CallOption) (*GetImageNamespaceResponse, error) {
-	out := new(GetImageNamespaceResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/GetImageNamespace", in, out, opts...)
-	if err != nil {
+func _Manager_Unexpose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnexposeRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *managerClient) WatchStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (Manager_WatchStatusClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Manager_serviceDesc.Streams[1], "/blimp.cluster.v0.Manager/WatchStatus", opts...)
-	if err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ManagerServer).Unexpose(ctx, in)
 	}
-	x := &managerWatchStatusClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/Unexpose",
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).Unexpose(ctx, req.(*UnexposeRequest))
 	}
-	return x, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-type Manager_WatchStatusClient interface {
-	Recv() (*GetStatusResponse, error)
-	grpc.ClientStream
+func _Manager_RunTest_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunTestRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ManagerServer).RunTest(m, &managerRunTestServer{stream})
 }
 
-type managerWatchStatusClient struct {
-	grpc.ClientStream
+type Manager_RunTestServer interface {
+	Send(*RunTestResponse) error
+	grpc.ServerStream
 }
 
-func (x *managerWatchStatusClient) Recv() (*GetStatusResponse, error) {
-	m := new(GetStatusResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+type managerRunTestServer struct {
+	grpc.ServerStream
 }
 
-func (c *managerClient) CheckVersion(ctx context.Context, in *CheckVersionRequest, opts ...grpc.CallOption) (*CheckVersionResponse, error) {
-	out := new(CheckVersionResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/CheckVersion", in, out, opts...)
-	if err != nil {
+func (x *managerRunTestServer) Send(m *RunTestResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Manager_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ManagerServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/GetUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *managerClient) Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error) {
-	out := new(RestartResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/Restart", in, out, opts...)
-	if err != nil {
+func _Manager_GetFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFaultsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ManagerServer).GetFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/GetFaults",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).GetFaults(ctx, req.(*GetFaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *managerClient) TagImages(ctx context.Context, in *TagImagesRequest, opts ...grpc.CallOption) (Manager_TagImagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Manager_serviceDesc.Streams[2], "/blimp.cluster.v0.Manager/TagImages", opts...)
-	if err != nil {
+func _Manager_SetFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFaultsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &managerTagImagesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ManagerServer).SetFaults(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/SetFaults",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).SetFaults(ctx, req.(*SetFaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Manager_TagImagesClient interface {
-	Recv() (*TagImagesResponse, error)
-	grpc.ClientStream
+func _Manager_GetDependencyGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependencyGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).GetDependencyGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/GetDependencyGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).GetDependencyGraph(ctx, req.(*GetDependencyGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type managerTagImagesClient struct {
-	grpc.ClientStream
+func _Manager_Boost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BoostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).Boost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/Boost",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).Boost(ctx, req.(*BoostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *managerTagImagesClient) Recv() (*TagImagesResponse, error) {
-	m := new(TagImagesResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _Manager_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTemplatesRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(ManagerServer).ListTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/ListTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).ListTemplates(ctx, req.(*ListTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *managerClient) Expose(ctx context.Context, in *ExposeRequest, opts ...grpc.CallOption) (*ExposeResponse, error) {
-	out := new(ExposeResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/Expose", in, out, opts...)
-	if err != nil {
+func _Manager_GetTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemplateRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ManagerServer).GetTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/GetTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).GetTemplate(ctx, req.(*GetTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *managerClient) Unexpose(ctx context.Context, in *UnexposeRequest, opts ...grpc.CallOption) (*UnexposeResponse, error) {
-	out := new(UnexposeResponse)
-	err := c.cc.Invoke(ctx, "/blimp.cluster.v0.Manager/Unexpose", in, out, opts...)
-	if err != nil {
+func _Manager_CreateAddon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAddonRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ManagerServer).CreateAddon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/CreateAddon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).CreateAddon(ctx, req.(*CreateAddonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// ManagerServer is the server API for Manager service.
-type ManagerServer interface {
-	AttachToSandbox(context.Context, *AttachToSandboxRequest) (*AttachToSandboxResponse, error)
-	BlimpUpPreview(*BlimpUpPreviewRequest, Manager_BlimpUpPreviewServer) error
-	CreateSandbox(context.Context, *CreateSandboxRequest) (*CreateSandboxResponse, error)
-	DeployToSandbox(context.Context, *DeployRequest) (*DeployResponse, error)
-	DeleteSandbox(context.Context, *DeleteSandboxRequest) (*DeleteSandboxResponse, error)
-	GetBuildkit(context.Context, *GetBuildkitRequest) (*GetBuildkitResponse, error)
-	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
-	GetImageNamespace(context.Context, *GetImageNamespaceRequest) (*GetImageNamespaceResponse, error)
-	WatchStatus(*GetStatusRequest, Manager_WatchStatusServer) error
-	CheckVersion(context.Context, *CheckVersionRequest) (*CheckVersionResponse, error)
-	Restart(context.Context, *RestartRequest) (*RestartResponse, error)
-	TagImages(*TagImagesRequest, Manager_TagImagesServer) error
-	Expose(context.Context, *ExposeRequest) (*ExposeResponse, error)
-	Unexpose(context.Context, *UnexposeRequest) (*UnexposeResponse, error)
+func _Manager_DeleteAddon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAddonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).DeleteAddon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/DeleteAddon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).DeleteAddon(ctx, req.(*DeleteAddonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedManagerServer can be embedded to have forward compatible implementations.
-type UnimplementedManagerServer struct {
+func _Manager_ListAddons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAddonsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).ListAddons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/ListAddons",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).ListAddons(ctx, req.(*ListAddonsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (*UnimplementedManagerServer) AttachToSandbox(ctx context.Context, req *AttachToSandboxRequest) (*AttachToSandboxResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AttachToSandbox not implemented")
-}
-func (*UnimplementedManagerServer) BlimpUpPreview(req *BlimpUpPreviewRequest, srv Manager_BlimpUpPreviewServer) error {
-	return status.Errorf(codes.Unimplemented, "method BlimpUpPreview not implemented")
-}
-func (*UnimplementedManagerServer) CreateSandbox(ctx context.Context, req *CreateSandboxRequest) (*CreateSandboxResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateSandbox not implemented")
-}
-func (*UnimplementedManagerServer) DeployToSandbox(ctx context.Context, req *DeployRequest) (*DeployResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeployToSandbox not implemented")
-}
-func (*UnimplementedManagerServer) DeleteSandbox(ctx context.Context, req *DeleteSandboxRequest) (*DeleteSandboxResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteSandbox not implemented")
-}
-func (*UnimplementedManagerServer) GetBuildkit(ctx context.Context, req *GetBuildkitRequest) (*GetBuildkitResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetBuildkit not implemented")
-}
-func (*UnimplementedManagerServer) GetStatus(ctx context.Context, req *GetStatusRequest) (*GetStatusResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
-}
-func (*UnimplementedManagerServer) GetImageNamespace(ctx context.Context, req *GetImageNamespaceRequest) (*GetImageNamespaceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetImageNamespace not implemented")
-}
-func (*UnimplementedManagerServer) WatchStatus(req *GetStatusRequest, srv Manager_WatchStatusServer) error {
-	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
-}
-func (*UnimplementedManagerServer) CheckVersion(ctx context.Context, req *CheckVersionRequest) (*CheckVersionResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CheckVersion not implemented")
-}
-func (*UnimplementedManagerServer) Restart(ctx context.Context, req *RestartRequest) (*RestartResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Restart not implemented")
-}
-func (*UnimplementedManagerServer) TagImages(req *TagImagesRequest, srv Manager_TagImagesServer) error {
-	return status.Errorf(codes.Unimplemented, "method TagImages not implemented")
-}
-func (*UnimplementedManagerServer) Expose(ctx context.Context, req *ExposeRequest) (*ExposeResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Expose not implemented")
-}
-func (*UnimplementedManagerServer) Unexpose(ctx context.Context, req *UnexposeRequest) (*UnexposeResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Unexpose not implemented")
+func _Manager_SnapshotAddon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotAddonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).SnapshotAddon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/SnapshotAddon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).SnapshotAddon(ctx, req.(*SnapshotAddonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-
-func RegisterManagerServer(s *grpc.Server, srv ManagerServer) {
-	s.RegisterService(&_Manager_serviceDesc, srv)
+
+func _Manager_RestoreAddon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreAddonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).RestoreAddon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/RestoreAddon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).RestoreAddon(ctx, req.(*RestoreAddonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_AttachToSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AttachToSandboxRequest)
+func _Manager_ListSandboxEnv_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSandboxEnvRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).AttachToSandbox(ctx, in)
+		return srv.(ManagerServer).ListSandboxEnv(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/AttachToSandbox",
+		FullMethod: "/blimp.cluster.v0.Manager/ListSandboxEnv",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).AttachToSandbox(ctx, req.(*AttachToSandboxRequest))
+		return srv.(ManagerServer).ListSandboxEnv(ctx, req.(*ListSandboxEnvRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_BlimpUpPreview_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(BlimpUpPreviewRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Manager_SetSandboxEnv_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSandboxEnvRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(ManagerServer).BlimpUpPreview(m, &managerBlimpUpPreviewServer{stream})
+	if interceptor == nil {
+		return srv.(ManagerServer).SetSandboxEnv(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/SetSandboxEnv",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).SetSandboxEnv(ctx, req.(*SetSandboxEnvRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Manager_BlimpUpPreviewServer interface {
-	Send(*BlimpUpPreviewResponse) error
-	grpc.ServerStream
+func _Manager_UnsetSandboxEnv_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsetSandboxEnvRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).UnsetSandboxEnv(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/UnsetSandboxEnv",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).UnsetSandboxEnv(ctx, req.(*UnsetSandboxEnvRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type managerBlimpUpPreviewServer struct {
-	grpc.ServerStream
+func _Manager_CreateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).CreateToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/CreateToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).CreateToken(ctx, req.(*CreateTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *managerBlimpUpPreviewServer) Send(m *BlimpUpPreviewResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _Manager_ListTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).ListTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/ListTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).ListTokens(ctx, req.(*ListTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_CreateSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateSandboxRequest)
+func _Manager_RevokeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeTokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).CreateSandbox(ctx, in)
+		return srv.(ManagerServer).RevokeToken(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/CreateSandbox",
+		FullMethod: "/blimp.cluster.v0.Manager/RevokeToken",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).CreateSandbox(ctx, req.(*CreateSandboxRequest))
+		return srv.(ManagerServer).RevokeToken(ctx, req.(*RevokeTokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_DeployToSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeployRequest)
+func _Manager_CreateSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSnapshotRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).DeployToSandbox(ctx, in)
+		return srv.(ManagerServer).CreateSnapshot(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/DeployToSandbox",
+		FullMethod: "/blimp.cluster.v0.Manager/CreateSnapshot",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).DeployToSandbox(ctx, req.(*DeployRequest))
+		return srv.(ManagerServer).CreateSnapshot(ctx, req.(*CreateSnapshotRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_DeleteSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteSandboxRequest)
+func _Manager_GetSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSnapshotRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).DeleteSandbox(ctx, in)
+		return srv.(ManagerServer).GetSnapshot(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/DeleteSandbox",
+		FullMethod: "/blimp.cluster.v0.Manager/GetSnapshot",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).DeleteSandbox(ctx, req.(*DeleteSandboxRequest))
+		return srv.(ManagerServer).GetSnapshot(ctx, req.(*GetSnapshotRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_GetBuildkit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetBuildkitRequest)
+func _Manager_BootSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BootSnapshotRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).GetBuildkit(ctx, in)
+		return srv.(ManagerServer).BootSnapshot(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/GetBuildkit",
+		FullMethod: "/blimp.cluster.v0.Manager/BootSnapshot",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).GetBuildkit(ctx, req.(*GetBuildkitRequest))
+		return srv.(ManagerServer).BootSnapshot(ctx, req.(*BootSnapshotRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetStatusRequest)
+func _Manager_GetDiagnostics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDiagnosticsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).GetStatus(ctx, in)
+		return srv.(ManagerServer).GetDiagnostics(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/GetStatus",
+		FullMethod: "/blimp.cluster.v0.Manager/GetDiagnostics",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).GetStatus(ctx, req.(*GetStatusRequest))
+		return srv.(ManagerServer).GetDiagnostics(ctx, req.(*GetDiagnosticsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_GetImageNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetImageNamespaceRequest)
+func _Manager_GetLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).GetImageNamespace(ctx, in)
+		return srv.(ManagerServer).GetLogs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/GetImageNamespace",
+		FullMethod: "/blimp.cluster.v0.Manager/GetLogs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).GetImageNamespace(ctx, req.(*GetImageNamespaceRequest))
+		return srv.(ManagerServer).GetLogs(ctx, req.(*GetLogsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetStatusRequest)
+func _Manager_StreamLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLogsRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ManagerServer).WatchStatus(m, &managerWatchStatusServer{stream})
+	return srv.(ManagerServer).StreamLogs(m, &managerStreamLogsServer{stream})
 }
 
-type Manager_WatchStatusServer interface {
-	Send(*GetStatusResponse) error
+type Manager_StreamLogsServer interface {
+	Send(*GetLogsResponse) error
 	grpc.ServerStream
 }
 
-type managerWatchStatusServer struct {
+type managerStreamLogsServer struct {
 	grpc.ServerStream
 }
 
-func (x *managerWatchStatusServer) Send(m *GetStatusResponse) error {
+func (x *managerStreamLogsServer) Send(m *GetLogsResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Manager_CheckVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CheckVersionRequest)
+func _Manager_GetImageScans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImageScansRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).CheckVersion(ctx, in)
+		return srv.(ManagerServer).GetImageScans(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/CheckVersion",
+		FullMethod: "/blimp.cluster.v0.Manager/GetImageScans",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).CheckVersion(ctx, req.(*CheckVersionRequest))
+		return srv.(ManagerServer).GetImageScans(ctx, req.(*GetImageScansRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_Restart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RestartRequest)
+func _Manager_CreateShareLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateShareLinkRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).Restart(ctx, in)
+		return srv.(ManagerServer).CreateShareLink(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/Restart",
+		FullMethod: "/blimp.cluster.v0.Manager/CreateShareLink",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).Restart(ctx, req.(*RestartRequest))
+		return srv.(ManagerServer).CreateShareLink(ctx, req.(*CreateShareLinkRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_TagImages_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(TagImagesRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Manager_ListSandboxes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSandboxesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(ManagerServer).TagImages(m, &managerTagImagesServer{stream})
-}
-
-type Manager_TagImagesServer interface {
-	Send(*TagImagesResponse) error
-	grpc.ServerStream
+	if interceptor == nil {
+		return srv.(ManagerServer).ListSandboxes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/ListSandboxes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).ListSandboxes(ctx, req.(*ListSandboxesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type managerTagImagesServer struct {
-	grpc.ServerStream
+func _Manager_DescribeSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeSandboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).DescribeSandbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/DescribeSandbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).DescribeSandbox(ctx, req.(*DescribeSandboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *managerTagImagesServer) Send(m *TagImagesResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _Manager_EvictSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvictSandboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).EvictSandbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.cluster.v0.Manager/EvictSandbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).EvictSandbox(ctx, req.(*EvictSandboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_Expose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ExposeRequest)
+func _Manager_RunSelfTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunSelfTestRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).Expose(ctx, in)
+		return srv.(ManagerServer).RunSelfTest(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/Expose",
+		FullMethod: "/blimp.cluster.v0.Manager/RunSelfTest",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).Expose(ctx, req.(*ExposeRequest))
+		return srv.(ManagerServer).RunSelfTest(ctx, req.(*RunSelfTestRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Manager_Unexpose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UnexposeRequest)
+func _Manager_LookupNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupNamespaceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ManagerServer).Unexpose(ctx, in)
+		return srv.(ManagerServer).LookupNamespace(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blimp.cluster.v0.Manager/Unexpose",
+		FullMethod: "/blimp.cluster.v0.Manager/LookupNamespace",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ManagerServer).Unexpose(ctx, req.(*UnexposeRequest))
+		return srv.(ManagerServer).LookupNamespace(ctx, req.(*LookupNamespaceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -2500,6 +10049,14 @@ var _Manager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "AttachToSandbox",
 			Handler:    _Manager_AttachToSandbox_Handler,
 		},
+		{
+			MethodName: "CreatePreview",
+			Handler:    _Manager_CreatePreview_Handler,
+		},
+		{
+			MethodName: "DeletePreview",
+			Handler:    _Manager_DeletePreview_Handler,
+		},
 		{
 			MethodName: "CreateSandbox",
 			Handler:    _Manager_CreateSandbox_Handler,
@@ -2512,6 +10069,14 @@ var _Manager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteSandbox",
 			Handler:    _Manager_DeleteSandbox_Handler,
 		},
+		{
+			MethodName: "PauseSandbox",
+			Handler:    _Manager_PauseSandbox_Handler,
+		},
+		{
+			MethodName: "ResumeSandbox",
+			Handler:    _Manager_ResumeSandbox_Handler,
+		},
 		{
 			MethodName: "GetBuildkit",
 			Handler:    _Manager_GetBuildkit_Handler,
@@ -2524,6 +10089,14 @@ var _Manager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetImageNamespace",
 			Handler:    _Manager_GetImageNamespace_Handler,
 		},
+		{
+			MethodName: "PollStatus",
+			Handler:    _Manager_PollStatus_Handler,
+		},
+		{
+			MethodName: "GetStatuses",
+			Handler:    _Manager_GetStatuses_Handler,
+		},
 		{
 			MethodName: "CheckVersion",
 			Handler:    _Manager_CheckVersion_Handler,
@@ -2540,6 +10113,126 @@ var _Manager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Unexpose",
 			Handler:    _Manager_Unexpose_Handler,
 		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _Manager_GetUsage_Handler,
+		},
+		{
+			MethodName: "GetFaults",
+			Handler:    _Manager_GetFaults_Handler,
+		},
+		{
+			MethodName: "SetFaults",
+			Handler:    _Manager_SetFaults_Handler,
+		},
+		{
+			MethodName: "GetDependencyGraph",
+			Handler:    _Manager_GetDependencyGraph_Handler,
+		},
+		{
+			MethodName: "Boost",
+			Handler:    _Manager_Boost_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _Manager_ListTemplates_Handler,
+		},
+		{
+			MethodName: "GetTemplate",
+			Handler:    _Manager_GetTemplate_Handler,
+		},
+		{
+			MethodName: "CreateAddon",
+			Handler:    _Manager_CreateAddon_Handler,
+		},
+		{
+			MethodName: "DeleteAddon",
+			Handler:    _Manager_DeleteAddon_Handler,
+		},
+		{
+			MethodName: "ListAddons",
+			Handler:    _Manager_ListAddons_Handler,
+		},
+		{
+			MethodName: "SnapshotAddon",
+			Handler:    _Manager_SnapshotAddon_Handler,
+		},
+		{
+			MethodName: "RestoreAddon",
+			Handler:    _Manager_RestoreAddon_Handler,
+		},
+		{
+			MethodName: "ListSandboxEnv",
+			Handler:    _Manager_ListSandboxEnv_Handler,
+		},
+		{
+			MethodName: "SetSandboxEnv",
+			Handler:    _Manager_SetSandboxEnv_Handler,
+		},
+		{
+			MethodName: "UnsetSandboxEnv",
+			Handler:    _Manager_UnsetSandboxEnv_Handler,
+		},
+		{
+			MethodName: "CreateToken",
+			Handler:    _Manager_CreateToken_Handler,
+		},
+		{
+			MethodName: "ListTokens",
+			Handler:    _Manager_ListTokens_Handler,
+		},
+		{
+			MethodName: "RevokeToken",
+			Handler:    _Manager_RevokeToken_Handler,
+		},
+		{
+			MethodName: "CreateSnapshot",
+			Handler:    _Manager_CreateSnapshot_Handler,
+		},
+		{
+			MethodName: "GetSnapshot",
+			Handler:    _Manager_GetSnapshot_Handler,
+		},
+		{
+			MethodName: "BootSnapshot",
+			Handler:    _Manager_BootSnapshot_Handler,
+		},
+		{
+			MethodName: "GetDiagnostics",
+			Handler:    _Manager_GetDiagnostics_Handler,
+		},
+		{
+			MethodName: "GetLogs",
+			Handler:    _Manager_GetLogs_Handler,
+		},
+		{
+			MethodName: "GetImageScans",
+			Handler:    _Manager_GetImageScans_Handler,
+		},
+		{
+			MethodName: "CreateShareLink",
+			Handler:    _Manager_CreateShareLink_Handler,
+		},
+		{
+			MethodName: "ListSandboxes",
+			Handler:    _Manager_ListSandboxes_Handler,
+		},
+		{
+			MethodName: "DescribeSandbox",
+			Handler:    _Manager_DescribeSandbox_Handler,
+		},
+		{
+			MethodName: "EvictSandbox",
+			Handler:    _Manager_EvictSandbox_Handler,
+		},
+		{
+			MethodName: "RunSelfTest",
+			Handler:    _Manager_RunSelfTest_Handler,
+		},
+		{
+			MethodName: "LookupNamespace",
+			Handler:    _Manager_LookupNamespace_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2552,11 +10245,26 @@ var _Manager_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Manager_WatchStatus_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _Manager_WatchEvents_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "TagImages",
 			Handler:       _Manager_TagImages_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "RunTest",
+			Handler:       _Manager_RunTest_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamLogs",
+			Handler:       _Manager_StreamLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "blimp/cluster/v0/manager.proto",
 }
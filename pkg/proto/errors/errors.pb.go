@@ -128,7 +128,12 @@ func (m *ContextError) GetContext() string {
 }
 
 type FriendlyError struct {
-	FriendlyMessage      string   `protobuf:"bytes,1,opt,name=friendly_message,json=friendlyMessage,proto3" json:"friendly_message,omitempty"`
+	FriendlyMessage string `protobuf:"bytes,1,opt,name=friendly_message,json=friendlyMessage,proto3" json:"friendly_message,omitempty"`
+	// code identifies errors from the catalog in pkg/errors, such as
+	// `cert-mismatch`. It's empty for other errors.
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	// remediation_url links to documentation on how to fix the error.
+	RemediationUrl       string   `protobuf:"bytes,3,opt,name=remediation_url,json=remediationUrl,proto3" json:"remediation_url,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -166,6 +171,20 @@ func (m *FriendlyError) GetFriendlyMessage() string {
 	return ""
 }
 
+func (m *FriendlyError) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *FriendlyError) GetRemediationUrl() string {
+	if m != nil {
+		return m.RemediationUrl
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Error)(nil), "blimp.errors.v0.Error")
 	proto.RegisterType((*ContextError)(nil), "blimp.errors.v0.ContextError")
@@ -177,21 +196,23 @@ func init() {
 }
 
 var fileDescriptor_634bedf48a53d953 = []byte{
-	// 247 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x8e, 0x2f, 0x28, 0xca,
-	0x2f, 0xc9, 0xd7, 0x4f, 0xca, 0xc9, 0xcc, 0x2d, 0xd0, 0x4f, 0x2d, 0x2a, 0xca, 0x2f, 0x2a, 0xd6,
-	0x2f, 0x33, 0x80, 0xb2, 0xf4, 0xc0, 0x92, 0x42, 0xfc, 0x60, 0x59, 0x3d, 0xa8, 0x58, 0x99, 0x81,
-	0xd2, 0x32, 0x46, 0x2e, 0x56, 0x57, 0x10, 0x4f, 0xc8, 0x89, 0x8b, 0x37, 0x39, 0x3f, 0xaf, 0x24,
-	0xb5, 0xa2, 0x24, 0x1e, 0x2c, 0x2d, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0x6d, 0x24, 0xab, 0x87, 0xa6,
-	0x45, 0xcf, 0x19, 0xa2, 0x0a, 0xac, 0x2b, 0x88, 0x27, 0x19, 0x89, 0x27, 0xe4, 0xca, 0xc5, 0x97,
-	0x56, 0x94, 0x99, 0x9a, 0x97, 0x92, 0x53, 0x09, 0x35, 0x84, 0x09, 0x6c, 0x88, 0x1c, 0x86, 0x21,
-	0x6e, 0x50, 0x65, 0x10, 0x53, 0x78, 0xd3, 0x90, 0xb9, 0x42, 0x42, 0x5c, 0x2c, 0x20, 0x33, 0x25,
-	0x58, 0x14, 0x18, 0x35, 0x38, 0x83, 0xc0, 0x6c, 0xa5, 0x30, 0x2e, 0x1e, 0x64, 0x8b, 0x85, 0x74,
-	0xb8, 0x58, 0x91, 0x9d, 0x29, 0x86, 0x61, 0x03, 0xc4, 0x64, 0x88, 0x22, 0x21, 0x09, 0x2e, 0x76,
-	0xa8, 0x43, 0xc1, 0x2e, 0xe2, 0x0c, 0x82, 0x71, 0x95, 0xac, 0xb8, 0x78, 0x51, 0xdc, 0x22, 0xa4,
-	0xc9, 0x25, 0x00, 0xf7, 0x43, 0x6e, 0x6a, 0x71, 0x71, 0x62, 0x7a, 0x2a, 0xd8, 0x0e, 0xce, 0x20,
-	0x7e, 0x98, 0xb8, 0x2f, 0x44, 0xd8, 0x49, 0x33, 0x4a, 0x3d, 0x3d, 0xb3, 0x24, 0xa3, 0x34, 0x49,
-	0x2f, 0x39, 0x3f, 0x57, 0x3f, 0x3b, 0x35, 0x27, 0x25, 0x11, 0x1a, 0xfc, 0x05, 0xd9, 0xe9, 0xfa,
-	0x90, 0xe8, 0x80, 0x38, 0x28, 0x89, 0x0d, 0xcc, 0x33, 0x06, 0x04, 0x00, 0x00, 0xff, 0xff, 0x32,
-	0x1e, 0x43, 0x2d, 0xa6, 0x01, 0x00, 0x00,
+	// 278 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x51, 0xcb, 0x4e, 0xc3, 0x30,
+	0x10, 0x54, 0xa0, 0x05, 0x75, 0x69, 0x1a, 0xe4, 0x03, 0xca, 0x05, 0x54, 0x85, 0x43, 0x5b, 0x09,
+	0xc5, 0x15, 0xfc, 0x41, 0x51, 0xb9, 0x71, 0x89, 0x04, 0x07, 0x2e, 0x51, 0x1e, 0x6e, 0xb0, 0xea,
+	0xc4, 0x91, 0xe3, 0x16, 0xf8, 0x21, 0xbe, 0x13, 0x65, 0xed, 0x22, 0x43, 0x6f, 0x3b, 0x33, 0xbb,
+	0xb3, 0xb3, 0x5a, 0xb8, 0x4d, 0x5b, 0x25, 0xb5, 0xa4, 0xb9, 0xe0, 0x75, 0x4b, 0x99, 0x52, 0x52,
+	0x75, 0x74, 0xbf, 0xb4, 0x55, 0x8c, 0x22, 0x09, 0x50, 0x8d, 0x2d, 0xb7, 0x5f, 0x46, 0xdf, 0x1e,
+	0x0c, 0xd7, 0x3d, 0x22, 0x2b, 0xf0, 0x0b, 0xd9, 0x68, 0xf6, 0xa9, 0x53, 0x94, 0x43, 0x6f, 0xea,
+	0xcd, 0x2f, 0xee, 0xaf, 0xe3, 0x7f, 0x23, 0xf1, 0xa3, 0xe9, 0xc2, 0xa9, 0x64, 0x5c, 0x38, 0x88,
+	0xac, 0x61, 0xb2, 0x51, 0x9c, 0x35, 0xa5, 0xf8, 0xb2, 0x26, 0x27, 0x68, 0x72, 0x73, 0x64, 0xf2,
+	0x64, 0xdb, 0x8c, 0x8b, 0xbf, 0x71, 0x21, 0x21, 0x30, 0xe8, 0x3d, 0xc3, 0xc1, 0xd4, 0x9b, 0x8f,
+	0x12, 0xac, 0xa3, 0x57, 0x18, 0xbb, 0x8b, 0xc9, 0x1d, 0x0c, 0xdd, 0x98, 0x57, 0x47, 0x1b, 0x8c,
+	0xb3, 0x69, 0x22, 0x21, 0x9c, 0xdb, 0xa0, 0x98, 0x68, 0x94, 0x1c, 0x60, 0xf4, 0x01, 0xfe, 0x9f,
+	0x2c, 0x64, 0x01, 0x97, 0xbf, 0x37, 0xd4, 0xac, 0xeb, 0xb2, 0x8a, 0xe1, 0x8e, 0x51, 0x12, 0x1c,
+	0xf8, 0x67, 0x43, 0xf7, 0x39, 0x0b, 0x59, 0x32, 0x6b, 0x89, 0x35, 0x99, 0x41, 0xa0, 0x58, 0xcd,
+	0x4a, 0x9e, 0x69, 0x2e, 0x9b, 0x74, 0xa7, 0x44, 0x78, 0x8a, 0xf2, 0xc4, 0xa1, 0x5f, 0x94, 0x58,
+	0x2d, 0xde, 0x66, 0x15, 0xd7, 0xef, 0xbb, 0x3c, 0x2e, 0x64, 0x4d, 0xb7, 0x4c, 0x94, 0x99, 0xfd,
+	0x5d, 0xbb, 0xad, 0xa8, 0xf9, 0xa5, 0xb9, 0x26, 0x3f, 0x43, 0xf4, 0xf0, 0x33, 0x00, 0xef, 0x09,
+	0xa1, 0xe6, 0xe3, 0x01, 0x00, 0x00,
 }
@@ -136,6 +136,142 @@ func (m *ExposedTunnelHeader) GetNamespace() string {
 	return ""
 }
 
+// SSHAgentTunnelHeader identifies the SSHAgentConnection that the tunnel is
+// for.
+type SSHAgentTunnelHeader struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Id                   string          `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *SSHAgentTunnelHeader) Reset()         { *m = SSHAgentTunnelHeader{} }
+func (m *SSHAgentTunnelHeader) String() string { return proto.CompactTextString(m) }
+func (*SSHAgentTunnelHeader) ProtoMessage()    {}
+func (*SSHAgentTunnelHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ffe3c8ce6343e9a1, []int{2}
+}
+
+func (m *SSHAgentTunnelHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SSHAgentTunnelHeader.Unmarshal(m, b)
+}
+func (m *SSHAgentTunnelHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SSHAgentTunnelHeader.Marshal(b, m, deterministic)
+}
+func (m *SSHAgentTunnelHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SSHAgentTunnelHeader.Merge(m, src)
+}
+func (m *SSHAgentTunnelHeader) XXX_Size() int {
+	return xxx_messageInfo_SSHAgentTunnelHeader.Size(m)
+}
+func (m *SSHAgentTunnelHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_SSHAgentTunnelHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SSHAgentTunnelHeader proto.InternalMessageInfo
+
+func (m *SSHAgentTunnelHeader) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *SSHAgentTunnelHeader) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ForwardSSHAgentRequest struct {
+	Auth                 *auth.BlimpAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ForwardSSHAgentRequest) Reset()         { *m = ForwardSSHAgentRequest{} }
+func (m *ForwardSSHAgentRequest) String() string { return proto.CompactTextString(m) }
+func (*ForwardSSHAgentRequest) ProtoMessage()    {}
+func (*ForwardSSHAgentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ffe3c8ce6343e9a1, []int{3}
+}
+
+func (m *ForwardSSHAgentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ForwardSSHAgentRequest.Unmarshal(m, b)
+}
+func (m *ForwardSSHAgentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ForwardSSHAgentRequest.Marshal(b, m, deterministic)
+}
+func (m *ForwardSSHAgentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ForwardSSHAgentRequest.Merge(m, src)
+}
+func (m *ForwardSSHAgentRequest) XXX_Size() int {
+	return xxx_messageInfo_ForwardSSHAgentRequest.Size(m)
+}
+func (m *ForwardSSHAgentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ForwardSSHAgentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ForwardSSHAgentRequest proto.InternalMessageInfo
+
+func (m *ForwardSSHAgentRequest) GetAuth() *auth.BlimpAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+type SSHAgentConnection struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// service is the service that connected to the agent.
+	Service              string   `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SSHAgentConnection) Reset()         { *m = SSHAgentConnection{} }
+func (m *SSHAgentConnection) String() string { return proto.CompactTextString(m) }
+func (*SSHAgentConnection) ProtoMessage()    {}
+func (*SSHAgentConnection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ffe3c8ce6343e9a1, []int{4}
+}
+
+func (m *SSHAgentConnection) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SSHAgentConnection.Unmarshal(m, b)
+}
+func (m *SSHAgentConnection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SSHAgentConnection.Marshal(b, m, deterministic)
+}
+func (m *SSHAgentConnection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SSHAgentConnection.Merge(m, src)
+}
+func (m *SSHAgentConnection) XXX_Size() int {
+	return xxx_messageInfo_SSHAgentConnection.Size(m)
+}
+func (m *SSHAgentConnection) XXX_DiscardUnknown() {
+	xxx_messageInfo_SSHAgentConnection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SSHAgentConnection proto.InternalMessageInfo
+
+func (m *SSHAgentConnection) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SSHAgentConnection) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
 type EOF struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -146,7 +282,7 @@ func (m *EOF) Reset()         { *m = EOF{} }
 func (m *EOF) String() string { return proto.CompactTextString(m) }
 func (*EOF) ProtoMessage()    {}
 func (*EOF) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ffe3c8ce6343e9a1, []int{2}
+	return fileDescriptor_ffe3c8ce6343e9a1, []int{5}
 }
 
 func (m *EOF) XXX_Unmarshal(b []byte) error {
@@ -175,6 +311,7 @@ type TunnelMsg struct {
 	//	*TunnelMsg_Error
 	//	*TunnelMsg_Header
 	//	*TunnelMsg_ExposedHeader
+	//	*TunnelMsg_SshAgentHeader
 	//	*TunnelMsg_Buf
 	//	*TunnelMsg_Eof
 	Msg                  isTunnelMsg_Msg `protobuf_oneof:"msg"`
@@ -187,7 +324,7 @@ func (m *TunnelMsg) Reset()         { *m = TunnelMsg{} }
 func (m *TunnelMsg) String() string { return proto.CompactTextString(m) }
 func (*TunnelMsg) ProtoMessage()    {}
 func (*TunnelMsg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ffe3c8ce6343e9a1, []int{3}
+	return fileDescriptor_ffe3c8ce6343e9a1, []int{6}
 }
 
 func (m *TunnelMsg) XXX_Unmarshal(b []byte) error {
@@ -224,6 +361,10 @@ type TunnelMsg_ExposedHeader struct {
 	ExposedHeader *ExposedTunnelHeader `protobuf:"bytes,5,opt,name=exposed_header,json=exposedHeader,proto3,oneof"`
 }
 
+type TunnelMsg_SshAgentHeader struct {
+	SshAgentHeader *SSHAgentTunnelHeader `protobuf:"bytes,6,opt,name=ssh_agent_header,json=sshAgentHeader,proto3,oneof"`
+}
+
 type TunnelMsg_Buf struct {
 	Buf []byte `protobuf:"bytes,3,opt,name=buf,proto3,oneof"`
 }
@@ -238,6 +379,8 @@ func (*TunnelMsg_Header) isTunnelMsg_Msg() {}
 
 func (*TunnelMsg_ExposedHeader) isTunnelMsg_Msg() {}
 
+func (*TunnelMsg_SshAgentHeader) isTunnelMsg_Msg() {}
+
 func (*TunnelMsg_Buf) isTunnelMsg_Msg() {}
 
 func (*TunnelMsg_Eof) isTunnelMsg_Msg() {}
@@ -270,6 +413,13 @@ func (m *TunnelMsg) GetExposedHeader() *ExposedTunnelHeader {
 	return nil
 }
 
+func (m *TunnelMsg) GetSshAgentHeader() *SSHAgentTunnelHeader {
+	if x, ok := m.GetMsg().(*TunnelMsg_SshAgentHeader); ok {
+		return x.SshAgentHeader
+	}
+	return nil
+}
+
 func (m *TunnelMsg) GetBuf() []byte {
 	if x, ok := m.GetMsg().(*TunnelMsg_Buf); ok {
 		return x.Buf
@@ -290,6 +440,7 @@ func (*TunnelMsg) XXX_OneofWrappers() []interface{} {
 		(*TunnelMsg_Error)(nil),
 		(*TunnelMsg_Header)(nil),
 		(*TunnelMsg_ExposedHeader)(nil),
+		(*TunnelMsg_SshAgentHeader)(nil),
 		(*TunnelMsg_Buf)(nil),
 		(*TunnelMsg_Eof)(nil),
 	}
@@ -310,7 +461,7 @@ func (m *SyncStatusResponse) Reset()         { *m = SyncStatusResponse{} }
 func (m *SyncStatusResponse) String() string { return proto.CompactTextString(m) }
 func (*SyncStatusResponse) ProtoMessage()    {}
 func (*SyncStatusResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ffe3c8ce6343e9a1, []int{4}
+	return fileDescriptor_ffe3c8ce6343e9a1, []int{7}
 }
 
 func (m *SyncStatusResponse) XXX_Unmarshal(b []byte) error {
@@ -400,7 +551,7 @@ func (m *GetSyncStatusRequest) Reset()         { *m = GetSyncStatusRequest{} }
 func (m *GetSyncStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*GetSyncStatusRequest) ProtoMessage()    {}
 func (*GetSyncStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ffe3c8ce6343e9a1, []int{5}
+	return fileDescriptor_ffe3c8ce6343e9a1, []int{8}
 }
 
 func (m *GetSyncStatusRequest) XXX_Unmarshal(b []byte) error {
@@ -424,6 +575,9 @@ var xxx_messageInfo_GetSyncStatusRequest proto.InternalMessageInfo
 func init() {
 	proto.RegisterType((*TunnelHeader)(nil), "blimp.node.v0.TunnelHeader")
 	proto.RegisterType((*ExposedTunnelHeader)(nil), "blimp.node.v0.ExposedTunnelHeader")
+	proto.RegisterType((*SSHAgentTunnelHeader)(nil), "blimp.node.v0.SSHAgentTunnelHeader")
+	proto.RegisterType((*ForwardSSHAgentRequest)(nil), "blimp.node.v0.ForwardSSHAgentRequest")
+	proto.RegisterType((*SSHAgentConnection)(nil), "blimp.node.v0.SSHAgentConnection")
 	proto.RegisterType((*EOF)(nil), "blimp.node.v0.EOF")
 	proto.RegisterType((*TunnelMsg)(nil), "blimp.node.v0.TunnelMsg")
 	proto.RegisterType((*SyncStatusResponse)(nil), "blimp.node.v0.SyncStatusResponse")
@@ -435,40 +589,48 @@ func init() {
 }
 
 var fileDescriptor_ffe3c8ce6343e9a1 = []byte{
-	// 526 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x51, 0x6f, 0xd3, 0x30,
-	0x10, 0x4e, 0x96, 0xa4, 0x6a, 0xae, 0x2b, 0x02, 0x33, 0x4d, 0x51, 0x37, 0x50, 0x09, 0x02, 0xfa,
-	0x80, 0x9c, 0xa8, 0x88, 0x1f, 0x40, 0x51, 0x47, 0x06, 0x1a, 0x93, 0xb2, 0x3d, 0xf1, 0x32, 0xa5,
-	0x89, 0x9b, 0x46, 0x4d, 0xed, 0x10, 0x3b, 0x15, 0x7b, 0x45, 0x48, 0xfc, 0x6b, 0x84, 0x6c, 0x87,
-	0xd1, 0x96, 0xc2, 0x0b, 0x4f, 0xbd, 0xf3, 0x7d, 0x77, 0xfe, 0xee, 0xfb, 0xea, 0xc0, 0xe3, 0x59,
-	0x59, 0xac, 0xaa, 0x80, 0xb2, 0x8c, 0x04, 0xeb, 0x30, 0x48, 0x19, 0x15, 0x35, 0x2b, 0x4b, 0x52,
-	0xe3, 0xaa, 0x66, 0x82, 0xa1, 0xbe, 0xaa, 0x63, 0x59, 0xc7, 0xeb, 0x70, 0xe0, 0x69, 0x78, 0xd2,
-	0x88, 0x85, 0x84, 0xcb, 0x5f, 0x0d, 0x1c, 0x9c, 0xea, 0x0a, 0xa9, 0x6b, 0x56, 0x73, 0x59, 0xd3,
-	0x91, 0xae, 0xfa, 0xdf, 0x4d, 0x38, 0xbc, 0x6e, 0x28, 0x25, 0x65, 0x44, 0x92, 0x8c, 0xd4, 0x08,
-	0x81, 0x4d, 0x93, 0x15, 0xf1, 0xcc, 0xa1, 0x39, 0x72, 0x63, 0x15, 0xcb, 0xb3, 0x8a, 0xd5, 0xc2,
-	0x3b, 0x18, 0x9a, 0xa3, 0x7e, 0xac, 0x62, 0x74, 0x02, 0x2e, 0x2b, 0xb3, 0x1b, 0xc1, 0x96, 0x84,
-	0x7a, 0x96, 0x02, 0x77, 0x59, 0x99, 0x5d, 0xcb, 0x1c, 0xbd, 0x04, 0x5b, 0x32, 0xf0, 0x9c, 0xa1,
-	0x39, 0xea, 0x8d, 0x3d, 0xac, 0xb9, 0x2a, 0x52, 0xeb, 0x10, 0x4f, 0x64, 0xf6, 0xa6, 0x11, 0x8b,
-	0x58, 0xa1, 0xde, 0xdb, 0x5d, 0xfb, 0xbe, 0xe3, 0x9f, 0xc3, 0xc3, 0xe9, 0x97, 0x8a, 0x71, 0x92,
-	0x6d, 0xf1, 0x39, 0x02, 0x47, 0xdf, 0xa1, 0x09, 0xe9, 0x04, 0x9d, 0x82, 0x2b, 0x99, 0xf1, 0x2a,
-	0x49, 0x89, 0xa2, 0xe5, 0xc6, 0xbf, 0x0f, 0x7c, 0x07, 0xac, 0xe9, 0xe5, 0x99, 0xff, 0xf5, 0x00,
-	0x5c, 0x3d, 0xeb, 0x82, 0xe7, 0x08, 0x83, 0xa3, 0x36, 0x57, 0x83, 0x7a, 0xe3, 0xe3, 0x96, 0x54,
-	0xab, 0xc6, 0x3a, 0xc4, 0x53, 0x19, 0x45, 0x46, 0xac, 0x61, 0xe8, 0x35, 0x74, 0x16, 0x8a, 0x82,
-	0x9a, 0xdf, 0x1b, 0x9f, 0xe0, 0x2d, 0xc5, 0xf1, 0x26, 0xcb, 0xc8, 0x88, 0x5b, 0x30, 0xfa, 0x00,
-	0xf7, 0x88, 0x5e, 0xe3, 0xa6, 0x6d, 0xd7, 0x22, 0xf8, 0x3b, 0xed, 0x7b, 0x76, 0x8d, 0x8c, 0xb8,
-	0xdf, 0xf6, 0xde, 0x99, 0x61, 0xcd, 0x9a, 0xb9, 0x92, 0xf7, 0x30, 0x32, 0x62, 0x99, 0xa0, 0xe7,
-	0x60, 0x11, 0x36, 0xf7, 0x6c, 0x35, 0x15, 0xed, 0x4e, 0xbd, 0x3c, 0x93, 0x38, 0xc2, 0xe6, 0x13,
-	0x07, 0xac, 0x15, 0xcf, 0xfd, 0x6f, 0x26, 0xa0, 0xab, 0x5b, 0x9a, 0x5e, 0x89, 0x44, 0x34, 0x3c,
-	0x26, 0xbc, 0x62, 0x94, 0x13, 0xf4, 0x68, 0xd3, 0x3e, 0x25, 0x6d, 0x64, 0x6c, 0x18, 0x88, 0x5b,
-	0x03, 0xad, 0x7f, 0x1b, 0x18, 0x19, 0xda, 0x42, 0xe4, 0x41, 0x87, 0xdf, 0xd2, 0x94, 0x64, 0x4a,
-	0xac, 0xae, 0xd4, 0x43, 0xe7, 0xbf, 0x68, 0x1c, 0xc3, 0xd1, 0x3b, 0x22, 0x36, 0x89, 0x7c, 0x6e,
-	0x08, 0x17, 0xe3, 0x1f, 0x26, 0xc0, 0xdb, 0xbb, 0xff, 0x36, 0x9a, 0x40, 0x47, 0x2b, 0x82, 0xbc,
-	0xbd, 0x72, 0x5f, 0xf0, 0x7c, 0xf0, 0xd7, 0x8a, 0x6f, 0x8c, 0xcc, 0xd0, 0x44, 0xe7, 0xd0, 0xdf,
-	0x12, 0xf7, 0x3f, 0x46, 0x25, 0xf0, 0x40, 0x52, 0xfe, 0xc8, 0x44, 0x31, 0x2f, 0xd2, 0x44, 0x14,
-	0x8c, 0x72, 0xf4, 0x64, 0xa7, 0xe9, 0x4f, 0x75, 0x07, 0x4f, 0x77, 0x20, 0xfb, 0x56, 0xd7, 0x57,
-	0x4c, 0x5e, 0x7c, 0x7a, 0x96, 0x17, 0x62, 0xd1, 0xcc, 0x70, 0xca, 0x56, 0xc1, 0x92, 0x94, 0x59,
-	0x12, 0xe8, 0x17, 0x5b, 0x2d, 0xf3, 0x40, 0x3d, 0x52, 0xf5, 0x11, 0x98, 0x75, 0x54, 0xfc, 0xea,
-	0x67, 0x00, 0x00, 0x00, 0xff, 0xff, 0x2b, 0x8d, 0x67, 0x05, 0x19, 0x04, 0x00, 0x00,
+	// 656 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xcd, 0x6e, 0xd3, 0x4c,
+	0x14, 0xb5, 0xe3, 0x24, 0x5f, 0x73, 0xdb, 0xe4, 0x2b, 0x43, 0x55, 0x59, 0x69, 0x41, 0xad, 0x51,
+	0x21, 0x0b, 0xe4, 0x44, 0x45, 0x6c, 0x91, 0x9a, 0xaa, 0xc1, 0x2d, 0x2a, 0x95, 0x9c, 0xae, 0xd8,
+	0x44, 0x8e, 0x3d, 0x49, 0xac, 0x3a, 0x33, 0xc6, 0x33, 0x0e, 0x74, 0x8f, 0xc4, 0xc3, 0xf0, 0x38,
+	0xbc, 0x10, 0x9a, 0x1f, 0xb7, 0x89, 0x49, 0x11, 0x3f, 0x2b, 0xdf, 0xeb, 0x7b, 0xe7, 0xcc, 0x99,
+	0x7b, 0xce, 0x0c, 0x3c, 0x1d, 0x27, 0xf1, 0x3c, 0xed, 0x12, 0x1a, 0xe1, 0xee, 0xa2, 0xd7, 0x0d,
+	0x29, 0xe1, 0x19, 0x4d, 0x12, 0x9c, 0xb9, 0x69, 0x46, 0x39, 0x45, 0x4d, 0x59, 0x77, 0x45, 0xdd,
+	0x5d, 0xf4, 0xda, 0xb6, 0x6a, 0x0f, 0x72, 0x3e, 0x13, 0xed, 0xe2, 0xab, 0x1a, 0xdb, 0xfb, 0xaa,
+	0x82, 0xb3, 0x8c, 0x66, 0x4c, 0xd4, 0x54, 0xa4, 0xaa, 0xce, 0x57, 0x13, 0xb6, 0xae, 0x73, 0x42,
+	0x70, 0xe2, 0xe1, 0x20, 0xc2, 0x19, 0x42, 0x50, 0x25, 0xc1, 0x1c, 0xdb, 0xe6, 0x81, 0xd9, 0x69,
+	0xf8, 0x32, 0x16, 0xff, 0x52, 0x9a, 0x71, 0xbb, 0x72, 0x60, 0x76, 0x9a, 0xbe, 0x8c, 0xd1, 0x1e,
+	0x34, 0x68, 0x12, 0x8d, 0x38, 0xbd, 0xc1, 0xc4, 0xb6, 0x64, 0xf3, 0x06, 0x4d, 0xa2, 0x6b, 0x91,
+	0xa3, 0x97, 0x50, 0x15, 0x0c, 0xec, 0xda, 0x81, 0xd9, 0xd9, 0x3c, 0xb6, 0x5d, 0xc5, 0x55, 0x92,
+	0x5a, 0xf4, 0xdc, 0xbe, 0xc8, 0x4e, 0x72, 0x3e, 0xf3, 0x65, 0xd7, 0x45, 0x75, 0xa3, 0xba, 0x5d,
+	0x73, 0xce, 0xe1, 0xf1, 0xd9, 0xe7, 0x94, 0x32, 0x1c, 0xad, 0xf0, 0xd9, 0x81, 0x9a, 0xda, 0x43,
+	0x11, 0x52, 0x09, 0xda, 0x87, 0x86, 0x60, 0xc6, 0xd2, 0x20, 0xc4, 0x92, 0x56, 0xc3, 0xbf, 0xff,
+	0xe1, 0x5c, 0xc3, 0xce, 0x70, 0xe8, 0x9d, 0x4c, 0x31, 0xe1, 0x2b, 0x58, 0x05, 0x2d, 0xf3, 0x77,
+	0x68, 0xa1, 0x16, 0x54, 0xe2, 0x48, 0x83, 0x57, 0xe2, 0xc8, 0x19, 0xc0, 0xee, 0x80, 0x66, 0x9f,
+	0x82, 0x2c, 0x2a, 0xc0, 0x7d, 0xfc, 0x31, 0xc7, 0x8c, 0xff, 0x19, 0xae, 0xf3, 0x06, 0x50, 0x01,
+	0x70, 0x4a, 0x09, 0xc1, 0x21, 0x8f, 0x29, 0xd1, 0xbb, 0x99, 0xc5, 0x6e, 0xc8, 0x86, 0xff, 0x18,
+	0xce, 0x16, 0xf1, 0xdd, 0xf9, 0x8a, 0xd4, 0xa9, 0x81, 0x75, 0x76, 0x35, 0x70, 0xbe, 0x57, 0xa0,
+	0xa1, 0x4e, 0x77, 0xc9, 0xa6, 0xc8, 0x85, 0x9a, 0xd4, 0x55, 0x73, 0xd8, 0xd5, 0x1c, 0xb4, 0xd6,
+	0x8b, 0x9e, 0x7b, 0x26, 0x22, 0xcf, 0xf0, 0x55, 0x1b, 0x7a, 0x0d, 0xf5, 0x99, 0x1c, 0x8a, 0x44,
+	0xdf, 0x3c, 0xde, 0x73, 0x57, 0xfc, 0xe4, 0x2e, 0xcf, 0xcd, 0x33, 0x7c, 0xdd, 0x8c, 0xde, 0x41,
+	0x0b, 0x2b, 0x91, 0x46, 0x7a, 0xb9, 0x92, 0xd8, 0x29, 0x2d, 0x5f, 0xa3, 0xa4, 0x67, 0xf8, 0x4d,
+	0xbd, 0x56, 0xcb, 0x71, 0x05, 0xdb, 0x8c, 0xcd, 0x46, 0x81, 0x98, 0x44, 0x01, 0x57, 0x97, 0x70,
+	0xcf, 0x4a, 0x70, 0xeb, 0xd4, 0xf4, 0x0c, 0xbf, 0xc5, 0xd8, 0x4c, 0xfe, 0xbf, 0xf3, 0xae, 0x35,
+	0xce, 0x27, 0xd2, 0x8d, 0x5b, 0x9e, 0xe1, 0x8b, 0x04, 0x3d, 0x07, 0x0b, 0xd3, 0x89, 0x5d, 0x95,
+	0xb8, 0xa8, 0x4c, 0xf3, 0x6a, 0x20, 0xfa, 0x30, 0x9d, 0xf4, 0x6b, 0x60, 0xcd, 0xd9, 0xd4, 0xf9,
+	0x62, 0x02, 0x1a, 0xde, 0x92, 0x70, 0xc8, 0x03, 0x9e, 0x33, 0x1f, 0xb3, 0x94, 0x12, 0x86, 0xd1,
+	0x93, 0x65, 0xb7, 0x4b, 0x91, 0x3c, 0x63, 0xc9, 0xef, 0xae, 0x36, 0x80, 0xf5, 0x6b, 0x03, 0x78,
+	0x86, 0xb6, 0x96, 0x0d, 0x75, 0x76, 0x4b, 0x42, 0xac, 0xec, 0xb5, 0x21, 0x06, 0xac, 0xf2, 0x82,
+	0xc6, 0x2e, 0xec, 0xbc, 0xc5, 0x7c, 0x99, 0x88, 0x74, 0xda, 0xf1, 0x37, 0x0b, 0xe0, 0xf4, 0xee,
+	0x29, 0x40, 0x7d, 0xa8, 0xab, 0x91, 0x20, 0x7b, 0xad, 0x7e, 0x97, 0x6c, 0xda, 0x7e, 0xb0, 0xe2,
+	0x18, 0x1d, 0xb3, 0x67, 0xa2, 0x73, 0x68, 0xae, 0xa8, 0xf5, 0x0f, 0x50, 0x01, 0x3c, 0x12, 0x94,
+	0xdf, 0x53, 0x1e, 0x4f, 0xe2, 0x30, 0x10, 0xbe, 0x66, 0xe8, 0xb0, 0xac, 0xe5, 0x4f, 0xd3, 0x6d,
+	0x97, 0xe5, 0x5e, 0x77, 0x74, 0xbd, 0xc5, 0x08, 0xfe, 0x2f, 0x5d, 0x42, 0x74, 0x54, 0x5a, 0xbd,
+	0xfe, 0x92, 0xb6, 0x0f, 0x1f, 0xf0, 0xd4, 0xfd, 0x1d, 0x74, 0x8c, 0x9e, 0x89, 0x2e, 0xa0, 0xb5,
+	0xea, 0xb6, 0xbf, 0x9f, 0x47, 0xff, 0xc5, 0x87, 0xa3, 0x69, 0xcc, 0x67, 0xf9, 0xd8, 0x0d, 0xe9,
+	0xbc, 0x7b, 0x83, 0x93, 0x28, 0xe8, 0xaa, 0xd7, 0x38, 0xbd, 0x99, 0x76, 0xe5, 0x03, 0x2c, 0x1f,
+	0xf8, 0x71, 0x5d, 0xc6, 0xaf, 0x7e, 0x0c, 0x00, 0x1b, 0xbd, 0xb9, 0xce, 0xf5, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -489,6 +651,12 @@ type ControllerClient interface {
 	// querying the CLI for status updates, but the CLI is initiating the
 	// connection.
 	SyncNotifications(ctx context.Context, opts ...grpc.CallOption) (Controller_SyncNotificationsClient, error)
+	// ForwardSSHAgent is held open by the CLI while `blimp up` is running. The
+	// node controller sends a request each time a service connects to its SSH
+	// agent socket, and the CLI handles the request by opening an
+	// SSHAgentTunnel to the user's local agent.
+	ForwardSSHAgent(ctx context.Context, in *ForwardSSHAgentRequest, opts ...grpc.CallOption) (Controller_ForwardSSHAgentClient, error)
+	SSHAgentTunnel(ctx context.Context, opts ...grpc.CallOption) (Controller_SSHAgentTunnelClient, error)
 }
 
 type controllerClient struct {
@@ -592,6 +760,69 @@ func (x *controllerSyncNotificationsClient) Recv() (*GetSyncStatusRequest, error
 	return m, nil
 }
 
+func (c *controllerClient) ForwardSSHAgent(ctx context.Context, in *ForwardSSHAgentRequest, opts ...grpc.CallOption) (Controller_ForwardSSHAgentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Controller_serviceDesc.Streams[3], "/blimp.node.v0.Controller/ForwardSSHAgent", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controllerForwardSSHAgentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Controller_ForwardSSHAgentClient interface {
+	Recv() (*SSHAgentConnection, error)
+	grpc.ClientStream
+}
+
+type controllerForwardSSHAgentClient struct {
+	grpc.ClientStream
+}
+
+func (x *controllerForwardSSHAgentClient) Recv() (*SSHAgentConnection, error) {
+	m := new(SSHAgentConnection)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controllerClient) SSHAgentTunnel(ctx context.Context, opts ...grpc.CallOption) (Controller_SSHAgentTunnelClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Controller_serviceDesc.Streams[4], "/blimp.node.v0.Controller/SSHAgentTunnel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controllerSSHAgentTunnelClient{stream}
+	return x, nil
+}
+
+type Controller_SSHAgentTunnelClient interface {
+	Send(*TunnelMsg) error
+	Recv() (*TunnelMsg, error)
+	grpc.ClientStream
+}
+
+type controllerSSHAgentTunnelClient struct {
+	grpc.ClientStream
+}
+
+func (x *controllerSSHAgentTunnelClient) Send(m *TunnelMsg) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *controllerSSHAgentTunnelClient) Recv() (*TunnelMsg, error) {
+	m := new(TunnelMsg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ControllerServer is the server API for Controller service.
 type ControllerServer interface {
 	Tunnel(Controller_TunnelServer) error
@@ -600,6 +831,12 @@ type ControllerServer interface {
 	// querying the CLI for status updates, but the CLI is initiating the
 	// connection.
 	SyncNotifications(Controller_SyncNotificationsServer) error
+	// ForwardSSHAgent is held open by the CLI while `blimp up` is running. The
+	// node controller sends a request each time a service connects to its SSH
+	// agent socket, and the CLI handles the request by opening an
+	// SSHAgentTunnel to the user's local agent.
+	ForwardSSHAgent(*ForwardSSHAgentRequest, Controller_ForwardSSHAgentServer) error
+	SSHAgentTunnel(Controller_SSHAgentTunnelServer) error
 }
 
 // UnimplementedControllerServer can be embedded to have forward compatible implementations.
@@ -615,6 +852,12 @@ func (*UnimplementedControllerServer) ExposedTunnel(srv Controller_ExposedTunnel
 func (*UnimplementedControllerServer) SyncNotifications(srv Controller_SyncNotificationsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SyncNotifications not implemented")
 }
+func (*UnimplementedControllerServer) ForwardSSHAgent(req *ForwardSSHAgentRequest, srv Controller_ForwardSSHAgentServer) error {
+	return status.Errorf(codes.Unimplemented, "method ForwardSSHAgent not implemented")
+}
+func (*UnimplementedControllerServer) SSHAgentTunnel(srv Controller_SSHAgentTunnelServer) error {
+	return status.Errorf(codes.Unimplemented, "method SSHAgentTunnel not implemented")
+}
 
 func RegisterControllerServer(s *grpc.Server, srv ControllerServer) {
 	s.RegisterService(&_Controller_serviceDesc, srv)
@@ -698,6 +941,53 @@ func (x *controllerSyncNotificationsServer) Recv() (*SyncStatusResponse, error)
 	return m, nil
 }
 
+func _Controller_ForwardSSHAgent_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ForwardSSHAgentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControllerServer).ForwardSSHAgent(m, &controllerForwardSSHAgentServer{stream})
+}
+
+type Controller_ForwardSSHAgentServer interface {
+	Send(*SSHAgentConnection) error
+	grpc.ServerStream
+}
+
+type controllerForwardSSHAgentServer struct {
+	grpc.ServerStream
+}
+
+func (x *controllerForwardSSHAgentServer) Send(m *SSHAgentConnection) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Controller_SSHAgentTunnel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ControllerServer).SSHAgentTunnel(&controllerSSHAgentTunnelServer{stream})
+}
+
+type Controller_SSHAgentTunnelServer interface {
+	Send(*TunnelMsg) error
+	Recv() (*TunnelMsg, error)
+	grpc.ServerStream
+}
+
+type controllerSSHAgentTunnelServer struct {
+	grpc.ServerStream
+}
+
+func (x *controllerSSHAgentTunnelServer) Send(m *TunnelMsg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *controllerSSHAgentTunnelServer) Recv() (*TunnelMsg, error) {
+	m := new(TunnelMsg)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Controller_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "blimp.node.v0.Controller",
 	HandlerType: (*ControllerServer)(nil),
@@ -721,6 +1011,17 @@ var _Controller_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ForwardSSHAgent",
+			Handler:       _Controller_ForwardSSHAgent_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SSHAgentTunnel",
+			Handler:       _Controller_SSHAgentTunnel_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "blimp/node/v0/controller.proto",
 }
@@ -25,6 +25,45 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type VolumeInitProgress_State int32
+
+const (
+	VolumeInitProgress_PENDING VolumeInitProgress_State = 0
+	VolumeInitProgress_COPYING VolumeInitProgress_State = 1
+	VolumeInitProgress_DONE    VolumeInitProgress_State = 2
+	// The volume already contains the image's contents.
+	VolumeInitProgress_UNCHANGED VolumeInitProgress_State = 3
+	// The volume contains other data, so it's left alone.
+	VolumeInitProgress_NOT_EMPTY VolumeInitProgress_State = 4
+	VolumeInitProgress_FAILED    VolumeInitProgress_State = 5
+)
+
+var VolumeInitProgress_State_name = map[int32]string{
+	0: "PENDING",
+	1: "COPYING",
+	2: "DONE",
+	3: "UNCHANGED",
+	4: "NOT_EMPTY",
+	5: "FAILED",
+}
+
+var VolumeInitProgress_State_value = map[string]int32{
+	"PENDING":   0,
+	"COPYING":   1,
+	"DONE":      2,
+	"UNCHANGED": 3,
+	"NOT_EMPTY": 4,
+	"FAILED":    5,
+}
+
+func (x VolumeInitProgress_State) String() string {
+	return proto.EnumName(VolumeInitProgress_State_name, int32(x))
+}
+
+func (VolumeInitProgress_State) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_d3a1998debca718e, []int{5, 0}
+}
+
 type CheckReadyRequest struct {
 	Namespace            string    `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	WaitSpec             *WaitSpec `protobuf:"bytes,2,opt,name=wait_spec,json=waitSpec,proto3" json:"wait_spec,omitempty"`
@@ -237,11 +276,181 @@ func (m *CheckReadyResponse) GetReason() string {
 	return ""
 }
 
+type ReportVolumeInitRequest struct {
+	Namespace            string                `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Pod                  string                `protobuf:"bytes,2,opt,name=pod,proto3" json:"pod,omitempty"`
+	Volumes              []*VolumeInitProgress `protobuf:"bytes,3,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ReportVolumeInitRequest) Reset()         { *m = ReportVolumeInitRequest{} }
+func (m *ReportVolumeInitRequest) String() string { return proto.CompactTextString(m) }
+func (*ReportVolumeInitRequest) ProtoMessage()    {}
+func (*ReportVolumeInitRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d3a1998debca718e, []int{4}
+}
+
+func (m *ReportVolumeInitRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportVolumeInitRequest.Unmarshal(m, b)
+}
+func (m *ReportVolumeInitRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportVolumeInitRequest.Marshal(b, m, deterministic)
+}
+func (m *ReportVolumeInitRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportVolumeInitRequest.Merge(m, src)
+}
+func (m *ReportVolumeInitRequest) XXX_Size() int {
+	return xxx_messageInfo_ReportVolumeInitRequest.Size(m)
+}
+func (m *ReportVolumeInitRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportVolumeInitRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportVolumeInitRequest proto.InternalMessageInfo
+
+func (m *ReportVolumeInitRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ReportVolumeInitRequest) GetPod() string {
+	if m != nil {
+		return m.Pod
+	}
+	return ""
+}
+
+func (m *ReportVolumeInitRequest) GetVolumes() []*VolumeInitProgress {
+	if m != nil {
+		return m.Volumes
+	}
+	return nil
+}
+
+type VolumeInitProgress struct {
+	// volume is the path that the volume is mounted at in the container.
+	Volume               string                   `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	State                VolumeInitProgress_State `protobuf:"varint,2,opt,name=state,proto3,enum=blimp.wait.v0.VolumeInitProgress_State" json:"state,omitempty"`
+	CopiedBytes          int64                    `protobuf:"varint,3,opt,name=copied_bytes,json=copiedBytes,proto3" json:"copied_bytes,omitempty"`
+	TotalBytes           int64                    `protobuf:"varint,4,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	Error                string                   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *VolumeInitProgress) Reset()         { *m = VolumeInitProgress{} }
+func (m *VolumeInitProgress) String() string { return proto.CompactTextString(m) }
+func (*VolumeInitProgress) ProtoMessage()    {}
+func (*VolumeInitProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d3a1998debca718e, []int{5}
+}
+
+func (m *VolumeInitProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VolumeInitProgress.Unmarshal(m, b)
+}
+func (m *VolumeInitProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VolumeInitProgress.Marshal(b, m, deterministic)
+}
+func (m *VolumeInitProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VolumeInitProgress.Merge(m, src)
+}
+func (m *VolumeInitProgress) XXX_Size() int {
+	return xxx_messageInfo_VolumeInitProgress.Size(m)
+}
+func (m *VolumeInitProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_VolumeInitProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VolumeInitProgress proto.InternalMessageInfo
+
+func (m *VolumeInitProgress) GetVolume() string {
+	if m != nil {
+		return m.Volume
+	}
+	return ""
+}
+
+func (m *VolumeInitProgress) GetState() VolumeInitProgress_State {
+	if m != nil {
+		return m.State
+	}
+	return VolumeInitProgress_PENDING
+}
+
+func (m *VolumeInitProgress) GetCopiedBytes() int64 {
+	if m != nil {
+		return m.CopiedBytes
+	}
+	return 0
+}
+
+func (m *VolumeInitProgress) GetTotalBytes() int64 {
+	if m != nil {
+		return m.TotalBytes
+	}
+	return 0
+}
+
+func (m *VolumeInitProgress) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ReportVolumeInitResponse struct {
+	Error                *errors.Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ReportVolumeInitResponse) Reset()         { *m = ReportVolumeInitResponse{} }
+func (m *ReportVolumeInitResponse) String() string { return proto.CompactTextString(m) }
+func (*ReportVolumeInitResponse) ProtoMessage()    {}
+func (*ReportVolumeInitResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d3a1998debca718e, []int{6}
+}
+
+func (m *ReportVolumeInitResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportVolumeInitResponse.Unmarshal(m, b)
+}
+func (m *ReportVolumeInitResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportVolumeInitResponse.Marshal(b, m, deterministic)
+}
+func (m *ReportVolumeInitResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportVolumeInitResponse.Merge(m, src)
+}
+func (m *ReportVolumeInitResponse) XXX_Size() int {
+	return xxx_messageInfo_ReportVolumeInitResponse.Size(m)
+}
+func (m *ReportVolumeInitResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportVolumeInitResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportVolumeInitResponse proto.InternalMessageInfo
+
+func (m *ReportVolumeInitResponse) GetError() *errors.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("blimp.wait.v0.VolumeInitProgress_State", VolumeInitProgress_State_name, VolumeInitProgress_State_value)
 	proto.RegisterType((*CheckReadyRequest)(nil), "blimp.wait.v0.CheckReadyRequest")
 	proto.RegisterType((*WaitSpec)(nil), "blimp.wait.v0.WaitSpec")
 	proto.RegisterType((*ServiceCondition)(nil), "blimp.wait.v0.ServiceCondition")
 	proto.RegisterType((*CheckReadyResponse)(nil), "blimp.wait.v0.CheckReadyResponse")
+	proto.RegisterType((*ReportVolumeInitRequest)(nil), "blimp.wait.v0.ReportVolumeInitRequest")
+	proto.RegisterType((*VolumeInitProgress)(nil), "blimp.wait.v0.VolumeInitProgress")
+	proto.RegisterType((*ReportVolumeInitResponse)(nil), "blimp.wait.v0.ReportVolumeInitResponse")
 }
 
 func init() {
@@ -249,32 +458,46 @@ func init() {
 }
 
 var fileDescriptor_d3a1998debca718e = []byte{
-	// 399 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0x51, 0x8b, 0xd4, 0x30,
-	0x10, 0xc7, 0xed, 0x2d, 0x77, 0x6e, 0x67, 0x15, 0x34, 0x1c, 0x67, 0x38, 0x0e, 0xec, 0x15, 0xc4,
-	0x3e, 0x48, 0x5b, 0xaa, 0xcf, 0x3e, 0xdc, 0xe1, 0x83, 0xbe, 0x08, 0x39, 0x54, 0xf0, 0xa5, 0xb4,
-	0xe9, 0xb8, 0x1b, 0x76, 0x9b, 0xc4, 0x24, 0xdb, 0xc3, 0x4f, 0xe3, 0x57, 0x95, 0x26, 0x2d, 0xab,
-	0x2b, 0xdc, 0x53, 0x67, 0xfe, 0xf9, 0x65, 0xa6, 0xf9, 0xcf, 0x00, 0x6d, 0x77, 0xa2, 0xd7, 0xc5,
-	0x7d, 0x23, 0x5c, 0x31, 0x94, 0xfe, 0x9b, 0x6b, 0xa3, 0x9c, 0x22, 0x4f, 0xfd, 0x49, 0xee, 0x95,
-	0xa1, 0xbc, 0xbc, 0x0a, 0x20, 0x1a, 0xa3, 0x8c, 0x1d, 0xd1, 0x10, 0x05, 0x38, 0x5d, 0xc3, 0xf3,
-	0xdb, 0x0d, 0xf2, 0x2d, 0xc3, 0xa6, 0xfb, 0xc5, 0xf0, 0xe7, 0x1e, 0xad, 0x23, 0x57, 0x10, 0xcb,
-	0xa6, 0x47, 0xab, 0x1b, 0x8e, 0x34, 0x4a, 0xa2, 0x2c, 0x66, 0x07, 0x81, 0xbc, 0x83, 0x78, 0xac,
-	0x5d, 0x5b, 0x8d, 0x9c, 0x9e, 0x24, 0x51, 0xb6, 0xaa, 0x5e, 0xe4, 0xff, 0xf4, 0xcc, 0xbf, 0x35,
-	0xc2, 0xdd, 0x69, 0xe4, 0x6c, 0x79, 0x3f, 0x45, 0xe9, 0xef, 0x08, 0x96, 0xb3, 0x4c, 0xde, 0x03,
-	0x74, 0xa8, 0x51, 0x76, 0xb6, 0x56, 0x92, 0x46, 0xc9, 0x22, 0x5b, 0x55, 0x2f, 0x8f, 0x6a, 0xdc,
-	0xa1, 0x19, 0x04, 0xc7, 0x5b, 0x25, 0x3b, 0xe1, 0x84, 0x92, 0x2c, 0x9e, 0xae, 0x7c, 0x96, 0xe4,
-	0x1a, 0x9e, 0xb4, 0x42, 0x76, 0xf5, 0xa0, 0x76, 0xfb, 0x1e, 0x2d, 0x3d, 0x49, 0x16, 0x59, 0xcc,
-	0x56, 0xa3, 0xf6, 0x35, 0x48, 0xa4, 0x84, 0xf3, 0x1f, 0x42, 0x0a, 0xbb, 0xc1, 0x19, 0xab, 0x85,
-	0x14, 0x8e, 0x2e, 0x3c, 0x4a, 0xe6, 0xb3, 0x80, 0x7f, 0x94, 0xc2, 0xa5, 0x9f, 0xe0, 0xd9, 0x71,
-	0x4f, 0x42, 0xe1, 0xb1, 0x0d, 0xda, 0xe4, 0xc3, 0x9c, 0x8e, 0x1e, 0xf1, 0x19, 0xf3, 0x2e, 0xc4,
-	0xec, 0x20, 0xa4, 0x1a, 0xc8, 0xdf, 0xb6, 0x5a, 0xad, 0xa4, 0x45, 0xf2, 0x06, 0x4e, 0xbd, 0xf9,
-	0xbe, 0xd6, 0xaa, 0xba, 0x98, 0x5e, 0x3c, 0x0d, 0x64, 0x28, 0xf3, 0x0f, 0x63, 0xc4, 0x02, 0x44,
-	0xce, 0xe1, 0xd4, 0x8c, 0xd7, 0x7d, 0xf5, 0x25, 0x0b, 0x09, 0xb9, 0x80, 0x33, 0x83, 0x8d, 0x55,
-	0x92, 0x2e, 0x7c, 0xd3, 0x29, 0xab, 0x38, 0xc0, 0x8d, 0x52, 0x6e, 0xb4, 0x18, 0x0d, 0xf9, 0x02,
-	0x70, 0xe8, 0x4f, 0x92, 0x23, 0x6b, 0xff, 0x9b, 0xf8, 0xe5, 0xf5, 0x03, 0x44, 0xf8, 0xf9, 0xf4,
-	0x51, 0x19, 0xdd, 0xbc, 0xfe, 0xfe, 0x6a, 0x2d, 0xdc, 0x66, 0xdf, 0xe6, 0x5c, 0xf5, 0xc5, 0x16,
-	0x77, 0x5d, 0x53, 0x84, 0xf5, 0xd2, 0xdb, 0x75, 0xe1, 0x37, 0xca, 0x6f, 0x62, 0x7b, 0xe6, 0xe3,
-	0xb7, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x34, 0x8a, 0x0b, 0x9d, 0xa6, 0x02, 0x00, 0x00,
+	// 622 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xd1, 0x6e, 0xd3, 0x3c,
+	0x14, 0x5e, 0x9a, 0x75, 0x6b, 0x4e, 0xfe, 0xfd, 0x0a, 0xd6, 0xb4, 0x45, 0xd3, 0xa4, 0x75, 0x91,
+	0x60, 0xbd, 0x40, 0x69, 0x55, 0xb8, 0x43, 0x20, 0x6d, 0x6d, 0xd9, 0x86, 0xa0, 0xad, 0xbc, 0x0d,
+	0x34, 0x6e, 0xaa, 0x34, 0x39, 0x74, 0xd6, 0xda, 0x38, 0xc4, 0x5e, 0xa7, 0xbd, 0x00, 0x4f, 0x81,
+	0xc4, 0xfb, 0xf0, 0x54, 0xc8, 0x76, 0xa2, 0x42, 0x27, 0x18, 0xe2, 0x2a, 0xe7, 0xfb, 0xfc, 0x1d,
+	0x7f, 0xc7, 0xe7, 0x38, 0x06, 0x7f, 0x3c, 0x65, 0xb3, 0xac, 0x79, 0x1b, 0x31, 0xd9, 0x9c, 0xb7,
+	0xf4, 0x37, 0xcc, 0x72, 0x2e, 0x39, 0xd9, 0xd0, 0x2b, 0xa1, 0x66, 0xe6, 0xad, 0x9d, 0x5d, 0x23,
+	0xc4, 0x3c, 0xe7, 0xb9, 0x50, 0x52, 0x13, 0x19, 0x71, 0x30, 0x81, 0x47, 0x9d, 0x2b, 0x8c, 0xaf,
+	0x29, 0x46, 0xc9, 0x1d, 0xc5, 0xcf, 0x37, 0x28, 0x24, 0xd9, 0x05, 0x27, 0x8d, 0x66, 0x28, 0xb2,
+	0x28, 0x46, 0xdf, 0xaa, 0x5b, 0x0d, 0x87, 0x2e, 0x08, 0xf2, 0x1c, 0x1c, 0xb5, 0xf7, 0x48, 0x64,
+	0x18, 0xfb, 0x95, 0xba, 0xd5, 0x70, 0xdb, 0xdb, 0xe1, 0x2f, 0x9e, 0xe1, 0x87, 0x88, 0xc9, 0xb3,
+	0x0c, 0x63, 0x5a, 0xbb, 0x2d, 0xa2, 0xe0, 0x9b, 0x05, 0xb5, 0x92, 0x26, 0xaf, 0x00, 0x12, 0xcc,
+	0x30, 0x4d, 0xc4, 0x88, 0xa7, 0xbe, 0x55, 0xb7, 0x1b, 0x6e, 0x7b, 0x6f, 0x69, 0x8f, 0x33, 0xcc,
+	0xe7, 0x2c, 0xc6, 0x0e, 0x4f, 0x13, 0x26, 0x19, 0x4f, 0xa9, 0x53, 0xa4, 0x0c, 0x52, 0xb2, 0x0f,
+	0xff, 0x8d, 0x59, 0x9a, 0x8c, 0xe6, 0x7c, 0x7a, 0x33, 0x43, 0xe1, 0x57, 0xea, 0x76, 0xc3, 0xa1,
+	0xae, 0xe2, 0xde, 0x1b, 0x8a, 0xb4, 0x60, 0xf3, 0x13, 0x4b, 0x99, 0xb8, 0xc2, 0x52, 0x36, 0x62,
+	0x29, 0x93, 0xbe, 0xad, 0xa5, 0xa4, 0x5c, 0x33, 0xf2, 0xd3, 0x94, 0xc9, 0xe0, 0x0d, 0x78, 0xcb,
+	0x9e, 0xc4, 0x87, 0x75, 0x61, 0xb8, 0xa2, 0x0f, 0x25, 0x54, 0x3d, 0x8a, 0x4b, 0x99, 0xee, 0x82,
+	0x43, 0x17, 0x44, 0x90, 0x01, 0xf9, 0xb9, 0xad, 0x22, 0xe3, 0xa9, 0x40, 0xf2, 0x14, 0xaa, 0xba,
+	0xf9, 0x7a, 0x2f, 0xb7, 0xbd, 0x55, 0x9c, 0xb8, 0x18, 0xc8, 0xbc, 0x15, 0xf6, 0x54, 0x44, 0x8d,
+	0x88, 0x6c, 0x42, 0x35, 0x57, 0xe9, 0x7a, 0xf7, 0x1a, 0x35, 0x80, 0x6c, 0xc1, 0x5a, 0x8e, 0x91,
+	0xe0, 0xa9, 0x6f, 0x6b, 0xd3, 0x02, 0x05, 0x5f, 0x2c, 0xd8, 0xa6, 0x98, 0xf1, 0x5c, 0x2e, 0x8e,
+	0xf4, 0x77, 0xf3, 0xf4, 0xc0, 0xce, 0x78, 0x52, 0x9c, 0x41, 0x85, 0xe4, 0x05, 0xac, 0x97, 0x9d,
+	0xb5, 0xf5, 0x6c, 0xf6, 0x97, 0x66, 0xb3, 0xb0, 0x18, 0xe6, 0x7c, 0x92, 0xa3, 0x10, 0xb4, 0xcc,
+	0x08, 0xbe, 0x56, 0x80, 0xdc, 0x5f, 0x57, 0x75, 0x1b, 0x45, 0x51, 0x40, 0x81, 0xc8, 0x4b, 0xa8,
+	0x0a, 0x19, 0x49, 0xd4, 0xfe, 0xff, 0xb7, 0x0f, 0x1e, 0x74, 0x0a, 0xcf, 0x94, 0x9c, 0x9a, 0x2c,
+	0x75, 0x13, 0x62, 0x9e, 0x31, 0x4c, 0x46, 0xe3, 0x3b, 0xa9, 0xeb, 0xb5, 0x1a, 0x36, 0x75, 0x0d,
+	0x77, 0xa4, 0x28, 0xb2, 0x07, 0xae, 0xe4, 0x32, 0x9a, 0x16, 0x8a, 0x55, 0xad, 0x00, 0x4d, 0x19,
+	0xc1, 0x66, 0x39, 0x96, 0xaa, 0xae, 0xcc, 0x80, 0xe0, 0x02, 0xaa, 0xda, 0x89, 0xb8, 0xb0, 0x3e,
+	0xec, 0xf5, 0xbb, 0xa7, 0xfd, 0x63, 0x6f, 0x45, 0x81, 0xce, 0x60, 0x78, 0xa9, 0x80, 0x45, 0x6a,
+	0xb0, 0xda, 0x1d, 0xf4, 0x7b, 0x5e, 0x85, 0x6c, 0x80, 0x73, 0xd1, 0xef, 0x9c, 0x1c, 0xf6, 0x8f,
+	0x7b, 0x5d, 0xcf, 0x56, 0xb0, 0x3f, 0x38, 0x1f, 0xf5, 0xde, 0x0d, 0xcf, 0x2f, 0xbd, 0x55, 0x02,
+	0xb0, 0xf6, 0xfa, 0xf0, 0xf4, 0x6d, 0xaf, 0xeb, 0x55, 0x83, 0x13, 0xf0, 0xef, 0x8f, 0xe9, 0x5f,
+	0xee, 0x47, 0xfb, 0xbb, 0x05, 0x70, 0xc4, 0xb9, 0x54, 0x7f, 0x15, 0xe6, 0xe4, 0x02, 0x60, 0x71,
+	0xe5, 0x48, 0x7d, 0xa9, 0x8f, 0xf7, 0x7e, 0xf2, 0x9d, 0xfd, 0x3f, 0x28, 0x4c, 0x3d, 0xc1, 0x4a,
+	0xcb, 0x22, 0x08, 0xde, 0x72, 0xbd, 0xe4, 0xc9, 0x52, 0xea, 0x6f, 0xee, 0xdd, 0xce, 0xc1, 0x83,
+	0xba, 0xd2, 0xe8, 0xe8, 0xe0, 0xe3, 0xe3, 0x09, 0x93, 0x57, 0x37, 0xe3, 0x30, 0xe6, 0xb3, 0xe6,
+	0x35, 0x4e, 0x93, 0xa8, 0x69, 0x1e, 0xae, 0xec, 0x7a, 0xd2, 0xd4, 0x6f, 0x95, 0x7e, 0xe3, 0xc6,
+	0x6b, 0x3a, 0x7e, 0xf6, 0x63, 0x00, 0x20, 0x3a, 0xa9, 0xa2, 0x00, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -290,6 +513,9 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type BootWaiterClient interface {
 	CheckReady(ctx context.Context, in *CheckReadyRequest, opts ...grpc.CallOption) (BootWaiter_CheckReadyClient, error)
+	// ReportVolumeInit is called by the volume initialization container to
+	// report its progress copying each volume.
+	ReportVolumeInit(ctx context.Context, in *ReportVolumeInitRequest, opts ...grpc.CallOption) (*ReportVolumeInitResponse, error)
 }
 
 type bootWaiterClient struct {
@@ -332,9 +558,21 @@ func (x *bootWaiterCheckReadyClient) Recv() (*CheckReadyResponse, error) {
 	return m, nil
 }
 
+func (c *bootWaiterClient) ReportVolumeInit(ctx context.Context, in *ReportVolumeInitRequest, opts ...grpc.CallOption) (*ReportVolumeInitResponse, error) {
+	out := new(ReportVolumeInitResponse)
+	err := c.cc.Invoke(ctx, "/blimp.wait.v0.BootWaiter/ReportVolumeInit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BootWaiterServer is the server API for BootWaiter service.
 type BootWaiterServer interface {
 	CheckReady(*CheckReadyRequest, BootWaiter_CheckReadyServer) error
+	// ReportVolumeInit is called by the volume initialization container to
+	// report its progress copying each volume.
+	ReportVolumeInit(context.Context, *ReportVolumeInitRequest) (*ReportVolumeInitResponse, error)
 }
 
 // UnimplementedBootWaiterServer can be embedded to have forward compatible implementations.
@@ -344,6 +582,9 @@ type UnimplementedBootWaiterServer struct {
 func (*UnimplementedBootWaiterServer) CheckReady(req *CheckReadyRequest, srv BootWaiter_CheckReadyServer) error {
 	return status.Errorf(codes.Unimplemented, "method CheckReady not implemented")
 }
+func (*UnimplementedBootWaiterServer) ReportVolumeInit(ctx context.Context, req *ReportVolumeInitRequest) (*ReportVolumeInitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportVolumeInit not implemented")
+}
 
 func RegisterBootWaiterServer(s *grpc.Server, srv BootWaiterServer) {
 	s.RegisterService(&_BootWaiter_serviceDesc, srv)
@@ -370,10 +611,33 @@ func (x *bootWaiterCheckReadyServer) Send(m *CheckReadyResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _BootWaiter_ReportVolumeInit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportVolumeInitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BootWaiterServer).ReportVolumeInit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blimp.wait.v0.BootWaiter/ReportVolumeInit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BootWaiterServer).ReportVolumeInit(ctx, req.(*ReportVolumeInitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _BootWaiter_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "blimp.wait.v0.BootWaiter",
 	HandlerType: (*BootWaiterServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReportVolumeInit",
+			Handler:    _BootWaiter_ReportVolumeInit_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "CheckReady",
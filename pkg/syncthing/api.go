@@ -7,6 +7,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"path"
+	"strconv"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -28,6 +30,21 @@ type Completion struct {
 	NeedItems   int `json:"needItems"`
 }
 
+type Event struct {
+	ID   int       `json:"id"`
+	Type string    `json:"type"`
+	Data EventData `json:"data"`
+}
+
+type EventData struct {
+	Folder string `json:"folder"`
+
+	// The following fields are only set for ItemFinished events.
+	Item   string  `json:"item"`
+	Action string  `json:"action"`
+	Error  *string `json:"error"`
+}
+
 type Connections struct {
 	Connections map[string]Connection `json:"connections"`
 }
@@ -36,6 +53,47 @@ type Connection struct {
 	Connected bool `json:"connected"`
 }
 
+type Config struct {
+	Folders []FolderConfig `json:"folders"`
+}
+
+type FolderConfig struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+type FileInfo struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+	Deleted  bool      `json:"deleted"`
+}
+
+// FileDetails contains the device's version of a file, and the newest
+// version of the file known to the cluster.
+type FileDetails struct {
+	Local  FileInfo `json:"local"`
+	Global FileInfo `json:"global"`
+}
+
+// Need contains the files that a device needs to sync to be up to date.
+type Need struct {
+	Progress []FileInfo `json:"progress"`
+	Queued   []FileInfo `json:"queued"`
+	Rest     []FileInfo `json:"rest"`
+	Files    []FileInfo `json:"files"`
+}
+
+// All returns all of the needed files.
+func (need Need) All() []FileInfo {
+	var all []FileInfo
+	for _, files := range [][]FileInfo{need.Progress, need.Queued, need.Rest, need.Files} {
+		all = append(all, files...)
+	}
+	return all
+}
+
 func (api APIClient) OverrideVersion(folder string) error {
 	return api.post("/rest/db/override", map[string]string{"folder": folder})
 }
@@ -67,6 +125,55 @@ func (api APIClient) GetConnections() (conns Connections, err error) {
 	return conns, err
 }
 
+// GetEvents blocks until there are events of the given type after `since`,
+// or the timeout expires.
+func (api APIClient) GetEvents(since int, eventType string, timeout time.Duration) (events []Event, err error) {
+	opts := map[string]string{
+		"since":   strconv.Itoa(since),
+		"events":  eventType,
+		"timeout": strconv.Itoa(int(timeout.Seconds())),
+	}
+	err = api.get("/rest/events", opts, &events)
+	return events, err
+}
+
+func (api APIClient) GetConfig() (config Config, err error) {
+	err = api.get("/rest/system/config", nil, &config)
+	return config, err
+}
+
+// Scan rescans the entire folder for changes, rather than relying on the
+// filesystem watcher.
+func (api APIClient) Scan(folder string) error {
+	return api.post("/rest/db/scan", map[string]string{"folder": folder})
+}
+
+func (api APIClient) GetFile(folder, file string) (details FileDetails, err error) {
+	opts := map[string]string{
+		"folder": folder,
+		"file":   file,
+	}
+	err = api.get("/rest/db/file", opts, &details)
+	return details, err
+}
+
+// GetNeed returns the files that the device needs from other devices.
+func (api APIClient) GetNeed(folder string) (need Need, err error) {
+	err = api.get("/rest/db/need", map[string]string{"folder": folder}, &need)
+	return need, err
+}
+
+// GetRemoteNeed returns the files that the given device needs from this
+// device.
+func (api APIClient) GetRemoteNeed(folder, device string) (need Need, err error) {
+	opts := map[string]string{
+		"folder": folder,
+		"device": device,
+	}
+	err = api.get("/rest/db/remoteneed", opts, &need)
+	return need, err
+}
+
 func (api APIClient) Ping() error {
 	return api.get("/rest/system/ping", nil, nil)
 }
@@ -116,8 +223,10 @@ func (api APIClient) do(method, p string, params map[string]string, respObj inte
 	return nil
 }
 
-func setLocalFolderType(ctx context.Context, c APIClient, t string, idPathMap map[string]string) error {
-	config := makeConfig(false, idPathMap, t)
+func setLocalFolderType(ctx context.Context, c APIClient, t string,
+	idPathMap map[string]string, opts configOptions) error {
+
+	config := makeConfig(false, idPathMap, t, opts)
 	err := ioutil.WriteFile(cfgdir.Expand("config.xml"), []byte(config), 0644)
 	if err != nil {
 		return errors.WithContext("write config", err)
@@ -216,24 +325,8 @@ func waitUntilSynced(ctx context.Context, local APIClient, folders []string) err
 			if err := local.OverrideVersion(folder); err != nil {
 				return false, errors.WithContext("override version", err)
 			}
-
-			// Although the status RPC also returns NeedBytes, we use the
-			// completion RPC instead because it compares the given device's
-			// completion with the index on the local device. In other words,
-			// we don't have to make sure that the versions from the
-			// OverrideVersion call have propagated to the remote device before
-			// checking for completion.
-			completion, err := local.GetCompletion(folder, RemoteDeviceID)
-			if err != nil {
-				return false, errors.WithContext("get remote folder completion", err)
-			}
-
-			if completion.NeedBytes != 0 || completion.NeedDeletes != 0 || completion.NeedItems != 0 {
-				return false, nil
-			}
 		}
-
-		return true, nil
+		return remoteCompleted(local, folders)
 	}
 
 	return waitUntil(ctx, 10, func() progressStatus {
@@ -251,3 +344,25 @@ func waitUntilSynced(ctx context.Context, local APIClient, folders []string) err
 		return progressStatus{phase: ProgressPending}
 	})
 }
+
+// remoteCompleted returns whether the remote device is up to date with the
+// local device's index for all of the folders.
+func remoteCompleted(local APIClient, folders []string) (bool, error) {
+	for _, folder := range folders {
+		// Although the status RPC also returns NeedBytes, we use the
+		// completion RPC instead because it compares the given device's
+		// completion with the index on the local device. In other words,
+		// we don't have to make sure that the versions from the
+		// OverrideVersion call have propagated to the remote device before
+		// checking for completion.
+		completion, err := local.GetCompletion(folder, RemoteDeviceID)
+		if err != nil {
+			return false, errors.WithContext("get remote folder completion", err)
+		}
+
+		if completion.NeedBytes != 0 || completion.NeedDeletes != 0 || completion.NeedItems != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
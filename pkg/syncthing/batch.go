@@ -0,0 +1,95 @@
+package syncthing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// batchPollTimeout is how long to wait for local changes before polling
+	// again.
+	batchPollTimeout = time.Minute
+
+	// batchCompletionInterval is how often to check whether the remote has
+	// finished syncing changes.
+	batchCompletionInterval = time.Second
+)
+
+// WatchSyncBatches calls `onBatch` with the local paths of the folders whose
+// changes have been fully synced to the sandbox. Changes that are detected
+// together are reported together once the sandbox has received all of them.
+// It returns when the context is cancelled.
+func WatchSyncBatches(ctx context.Context, idPathMap map[string]string, onBatch func(paths []string)) {
+	local := APIClient{fmt.Sprintf("127.0.0.1:%d", APIPort)}
+
+	since := 0
+	startedWatching := false
+	dirty := map[string]struct{}{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		// Don't block for long when there are changes that haven't been
+		// synced yet so that we notice when the sync completes. The first
+		// poll returns immediately so that the only events that are ignored
+		// are the ones from before we started watching.
+		timeout := batchPollTimeout
+		switch {
+		case !startedWatching:
+			timeout = 0
+		case len(dirty) != 0:
+			timeout = batchCompletionInterval
+		}
+
+		events, err := local.GetEvents(since, "LocalIndexUpdated", timeout)
+		if err != nil {
+			// Syncthing may not have booted yet.
+			log.WithError(err).Debug("Failed to get Syncthing events")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, event := range events {
+			since = event.ID
+
+			// Ignore the changes from before we started watching, such as
+			// the initial scan.
+			if !startedWatching {
+				continue
+			}
+
+			if _, ok := idPathMap[event.Data.Folder]; ok {
+				dirty[event.Data.Folder] = struct{}{}
+			}
+		}
+		startedWatching = true
+
+		var synced []string
+		for folder := range dirty {
+			completion, err := local.GetCompletion(folder, RemoteDeviceID)
+			if err != nil {
+				log.WithError(err).WithField("folder", folder).Debug("Failed to get sync completion")
+				continue
+			}
+
+			if completion.NeedBytes == 0 && completion.NeedDeletes == 0 && completion.NeedItems == 0 {
+				synced = append(synced, idPathMap[folder])
+				delete(dirty, folder)
+			}
+		}
+
+		if len(synced) != 0 {
+			onBatch(synced)
+		}
+	}
+}
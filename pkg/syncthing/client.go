@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
 	"sort"
@@ -30,6 +31,24 @@ const stignoreHeader = `# Generated by Blimp. DO NOT EDIT.
 
 type Client struct {
 	mounts []Mount
+
+	// disableCompression is set if any volume disabled compression, since
+	// compression applies to the entire connection.
+	disableCompression bool
+
+	// maxUpload and maxDownload limit the bandwidth used by file sync, in
+	// bytes per second. Zero means unlimited.
+	maxUpload   int64
+	maxDownload int64
+}
+
+// WithBandwidthLimits returns a Client that limits the bandwidth used for
+// syncing files. The limits are in bytes per second, and zero means
+// unlimited.
+func (c Client) WithBandwidthLimits(maxUpload, maxDownload int64) Client {
+	c.maxUpload = maxUpload
+	c.maxDownload = maxDownload
+	return c
 }
 
 func (c Client) GetIDPathMap() map[string]string {
@@ -40,12 +59,111 @@ func (c Client) GetIDPathMap() map[string]string {
 	return idPathMap
 }
 
+// GetDeltaFolders returns the IDs of the folders that use delta transfers.
+func (c Client) GetDeltaFolders() []string {
+	var ids []string
+	for _, m := range c.mounts {
+		if m.Delta {
+			ids = append(ids, m.ID())
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetFolderOwners returns the owners of the files in each folder, keyed by
+// folder ID.
+func (c Client) GetFolderOwners() map[string]string {
+	owners := map[string]string{}
+	for _, m := range c.mounts {
+		if m.Owner != "" {
+			owners[m.ID()] = m.Owner
+		}
+	}
+	return owners
+}
+
+// GetLineEndingRules returns the patterns for the files whose line endings
+// should be normalized, keyed by folder ID.
+func (c Client) GetLineEndingRules() map[string][]string {
+	rules := map[string][]string{}
+	for _, m := range c.mounts {
+		if len(m.NormalizeLineEndings) != 0 {
+			rules[m.ID()] = m.NormalizeLineEndings
+		}
+	}
+	return rules
+}
+
+// GetInferExecutableFolders returns the IDs of the folders whose scripts
+// should be made executable in the sandbox. Windows doesn't have executable
+// bits, so scripts would otherwise fail to run in the sandbox.
+func (c Client) GetInferExecutableFolders() []string {
+	if runtime.GOOS != "windows" {
+		return nil
+	}
+
+	var ids []string
+	for _, m := range c.mounts {
+		ids = append(ids, m.ID())
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (c Client) configOptions() configOptions {
+	opts := configOptions{
+		compression: "always",
+		delta:       map[string]bool{},
+		ignorePerms: runtime.GOOS == "windows",
+		maxSendKbps: toKbps(c.maxUpload),
+		maxRecvKbps: toKbps(c.maxDownload),
+	}
+	if c.disableCompression {
+		opts.compression = "metadata"
+	}
+	for _, id := range c.GetDeltaFolders() {
+		opts.delta[id] = true
+	}
+	return opts
+}
+
+// toKbps converts bytes per second into the KiB per second used by Syncthing.
+// Small limits are rounded up to 1 KiB/s, since Syncthing treats zero as
+// unlimited.
+func toKbps(bytesPerSecond int64) int64 {
+	if bytesPerSecond <= 0 {
+		return 0
+	}
+
+	kbps := bytesPerSecond / 1024
+	if kbps == 0 {
+		return 1
+	}
+	return kbps
+}
+
 // BindVolume represents a bind volume used by a single service, along with any
 // subdirectories that are masked off by native volumes mounted into this
 // service.
 type BindVolume struct {
 	LocalPath string
 	Masks     []string
+
+	// DisableCompression turns off compressing file data, which wastes CPU
+	// for files that are already compressed, such as archives and images.
+	DisableCompression bool
+
+	// Delta enables delta transfers for files that have data inserted into
+	// them, such as databases and logs.
+	Delta bool
+
+	// Owner is the `UID:GID` that owns the volume's files in the sandbox.
+	Owner string
+
+	// NormalizeLineEndings contains patterns for the files whose Windows line
+	// endings are converted to Unix line endings in the sandbox.
+	NormalizeLineEndings []string
 }
 
 type Mount struct {
@@ -56,6 +174,16 @@ type Mount struct {
 	// only be set if Include is nil and SyncAll is true.
 	Ignore  []string
 	SyncAll bool
+
+	// Delta is set if any of the volumes in the mount use delta transfers.
+	Delta bool
+
+	// Owner is the owner of the first volume in the mount that sets one.
+	Owner string
+
+	// NormalizeLineEndings contains the patterns from all the volumes in the
+	// mount. Patterns that contain a slash are relative to the mount's path.
+	NormalizeLineEndings []string
 }
 
 // GetStignore returns the stignore file needed to include only the paths in
@@ -149,8 +277,11 @@ func (m Mount) Includes(child string) bool {
 
 func NewClient(volumes []BindVolume) Client {
 	var allMounts []Mount
+	var disableCompression bool
 	// Collect all the mounts, regardless of whether they're nested.
 	for _, volume := range volumes {
+		disableCompression = disableCompression || volume.DisableCompression
+
 		// For directories, we just mount the entire directory. For other
 		// files, we mount the parent directory, and use .stignore to only sync
 		// the desired files.
@@ -159,6 +290,10 @@ func NewClient(volumes []BindVolume) Client {
 				Path:    volume.LocalPath,
 				SyncAll: true,
 				Ignore:  collapseIgnores(volume.Masks),
+				Delta:   volume.Delta,
+				Owner:   volume.Owner,
+
+				NormalizeLineEndings: volume.NormalizeLineEndings,
 			})
 		} else {
 			if len(volume.Masks) > 0 {
@@ -167,6 +302,10 @@ func NewClient(volumes []BindVolume) Client {
 			allMounts = append(allMounts, Mount{
 				Path:    filepath.Dir(volume.LocalPath),
 				Include: []string{filepath.Base(volume.LocalPath)},
+				Delta:   volume.Delta,
+				Owner:   volume.Owner,
+
+				NormalizeLineEndings: volume.NormalizeLineEndings,
 			})
 		}
 	}
@@ -280,6 +419,19 @@ func NewClient(volumes []BindVolume) Client {
 					parent.Include = append(parent.Include, filepath.Join(relPath, include))
 				}
 			}
+			parent.Delta = parent.Delta || mount.Delta
+			for _, pattern := range mount.NormalizeLineEndings {
+				if strings.Contains(pattern, "/") {
+					pattern = path.Join(filepath.ToSlash(relPath), pattern)
+				}
+				parent.NormalizeLineEndings = append(parent.NormalizeLineEndings, pattern)
+			}
+			if parent.Owner == "" {
+				parent.Owner = mount.Owner
+			} else if mount.Owner != "" && mount.Owner != parent.Owner {
+				log.WithField("path", mount.Path).Warnf("Volume is nested in a volume "+
+					"with a different owner. Using %s as the owner.", parent.Owner)
+			}
 			skipIndices[mi] = struct{}{}
 		}
 
@@ -287,7 +439,8 @@ func NewClient(volumes []BindVolume) Client {
 	}
 
 	return Client{
-		mounts: collapsedMounts,
+		mounts:             collapsedMounts,
+		disableCompression: disableCompression,
 	}
 }
 
@@ -295,6 +448,7 @@ func (c Client) Run(ctx context.Context, ncc node.ControllerClient,
 	auth *auth.BlimpAuth, tunnelManager tunnel.Manager) ([]byte, error) {
 
 	tunnelsErr := c.startTunnels(tunnelManager)
+	go c.warnBrokenSymlinks()
 
 	idPathMap := c.GetIDPathMap()
 	if err := c.WriteConfig(idPathMap); err != nil {
@@ -358,6 +512,36 @@ func (c Client) Run(ctx context.Context, ncc node.ControllerClient,
 	return out.Bytes(), waitErr
 }
 
+// warnBrokenSymlinks warns about symlinks that won't work in the sandbox.
+// Syncthing syncs symlinks as symlinks, so links to files outside the synced
+// folder are broken in the sandbox. Syncthing doesn't support symlinks at
+// all on Windows.
+func (c Client) warnBrokenSymlinks() {
+	for _, m := range c.mounts {
+		if !m.SyncAll {
+			continue
+		}
+
+		symlinks, err := FindSymlinks(m.Path)
+		if err != nil {
+			log.WithError(err).WithField("path", m.Path).Debug("Failed to check for symlinks")
+			continue
+		}
+
+		for _, symlink := range symlinks {
+			path := filepath.Join(m.Path, symlink.Path)
+			switch {
+			case runtime.GOOS == "windows":
+				log.Warnf("%s is a symlink, which isn't synced to your sandbox "+
+					"because symlinks aren't supported on Windows.", path)
+			case symlink.External:
+				log.Warnf("%s is a symlink to %s, which isn't synced to your sandbox. "+
+					"The symlink will be broken in your containers.", path, symlink.Target)
+			}
+		}
+	}
+}
+
 func (c Client) startTunnels(tm tunnel.Manager) chan error {
 	tunnels := []struct {
 		localPort, remotePort uint32
@@ -371,7 +555,7 @@ func (c Client) startTunnels(tm tunnel.Manager) chan error {
 		tunnel := tunnel
 		go func() {
 			select {
-			case errChan <- tm.Run("127.0.0.1", tunnel.localPort, "syncthing", tunnel.remotePort, nil):
+			case errChan <- tm.Bulk().Run("127.0.0.1", tunnel.localPort, "syncthing", tunnel.remotePort, nil):
 			default:
 			}
 		}()
@@ -403,7 +587,7 @@ func (c Client) performInitialSync(ctx context.Context, remoteAPIAddr string, id
 		return errors.WithContext("wait for initial sync", err)
 	}
 
-	if err := setLocalFolderType(ctx, localAPI, "sendreceive", idPathMap); err != nil {
+	if err := setLocalFolderType(ctx, localAPI, "sendreceive", idPathMap, c.configOptions()); err != nil {
 		return errors.WithContext("switch to sendreceive", err)
 	}
 
@@ -458,7 +642,7 @@ func (c Client) WriteConfig(idPathMap map[string]string) error {
 	}
 
 	fileMap := map[string]string{
-		"config.xml": makeConfig(false, idPathMap, "sendonly"),
+		"config.xml": makeConfig(false, idPathMap, "sendonly", c.configOptions()),
 		"cert.pem":   cert,
 		"key.pem":    key,
 	}
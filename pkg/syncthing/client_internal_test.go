@@ -2,6 +2,7 @@ package syncthing
 
 import (
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -396,6 +397,63 @@ func TestCalculateMounts(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Owner in nested volume",
+			volumes: []BindVolume{
+				{LocalPath: "/Users/kevin/kelda.io"},
+				{LocalPath: "/Users/kevin/kelda.io/app", Owner: "1000:1000"},
+			},
+			dirs: []string{
+				"/Users/kevin/kelda.io",
+				"/Users/kevin/kelda.io/app",
+			},
+			exp: []Mount{
+				{
+					Path:    "/Users/kevin/kelda.io",
+					SyncAll: true,
+					Owner:   "1000:1000",
+				},
+			},
+		},
+		{
+			name: "Line endings in nested volume",
+			volumes: []BindVolume{
+				{LocalPath: "/Users/kevin/kelda.io", NormalizeLineEndings: []string{"*.sh"}},
+				{
+					LocalPath:            "/Users/kevin/kelda.io/app",
+					NormalizeLineEndings: []string{"Makefile", "bin/*"},
+				},
+			},
+			dirs: []string{
+				"/Users/kevin/kelda.io",
+				"/Users/kevin/kelda.io/app",
+			},
+			exp: []Mount{
+				{
+					Path:                 "/Users/kevin/kelda.io",
+					SyncAll:              true,
+					NormalizeLineEndings: []string{"*.sh", "Makefile", "app/bin/*"},
+				},
+			},
+		},
+		{
+			name: "Delta in nested volume",
+			volumes: []BindVolume{
+				{LocalPath: "/Users/kevin/kelda.io"},
+				{LocalPath: "/Users/kevin/kelda.io/data", Delta: true},
+			},
+			dirs: []string{
+				"/Users/kevin/kelda.io",
+				"/Users/kevin/kelda.io/data",
+			},
+			exp: []Mount{
+				{
+					Path:    "/Users/kevin/kelda.io",
+					SyncAll: true,
+					Delta:   true,
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -415,3 +473,35 @@ func TestCalculateMounts(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigOptions(t *testing.T) {
+	isDir = func(path string) bool { return true }
+
+	client := NewClient([]BindVolume{
+		{LocalPath: "/Users/kevin/src"},
+		{LocalPath: "/Users/kevin/data", Delta: true, DisableCompression: true},
+	})
+	dataID := Mount{Path: "/Users/kevin/data"}.ID()
+	assert.Equal(t, []string{dataID}, client.GetDeltaFolders())
+	assert.Equal(t, configOptions{
+		compression: "metadata",
+		delta:       map[string]bool{dataID: true},
+		ignorePerms: runtime.GOOS == "windows",
+	}, client.configOptions())
+
+	assert.Equal(t, configOptions{
+		compression: "always",
+		delta:       map[string]bool{},
+		ignorePerms: runtime.GOOS == "windows",
+	}, NewClient([]BindVolume{{LocalPath: "/Users/kevin/src"}}).configOptions())
+
+	limited := NewClient([]BindVolume{{LocalPath: "/Users/kevin/src"}}).
+		WithBandwidthLimits(5*1024*1024, 100)
+	assert.Equal(t, configOptions{
+		compression: "always",
+		delta:       map[string]bool{},
+		ignorePerms: runtime.GOOS == "windows",
+		maxSendKbps: 5 * 1024,
+		maxRecvKbps: 1,
+	}, limited.configOptions())
+}
@@ -0,0 +1,222 @@
+package syncthing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// Owner is the user and group that own synced files in the sandbox.
+// Syncthing doesn't sync file ownership, so without an Owner, files are owned
+// by root.
+type Owner struct {
+	UID int
+	GID int
+}
+
+// ParseOwner parses owners of the form `UID:GID`, or `UID`, in which case the
+// group is the same as the user.
+func ParseOwner(str string) (Owner, error) {
+	parts := strings.SplitN(str, ":", 2)
+	uid, err := strconv.Atoi(parts[0])
+	if err != nil || uid < 0 {
+		return Owner{}, errors.New("malformed uid %q", parts[0])
+	}
+
+	if len(parts) == 1 {
+		return Owner{UID: uid, GID: uid}, nil
+	}
+
+	gid, err := strconv.Atoi(parts[1])
+	if err != nil || gid < 0 {
+		return Owner{}, errors.New("malformed gid %q", parts[1])
+	}
+	return Owner{UID: uid, GID: gid}, nil
+}
+
+func (o Owner) String() string {
+	return strconv.Itoa(o.UID) + ":" + strconv.Itoa(o.GID)
+}
+
+// FileFixes are changes that the sandbox makes to files after they're synced,
+// for things that Syncthing can't sync by itself.
+type FileFixes struct {
+	Owner *Owner
+
+	// InferExecutable marks scripts that start with a shebang as executable.
+	// It's used for volumes synced from Windows, which doesn't have executable
+	// bits.
+	InferExecutable bool
+
+	// NormalizeLineEndings contains patterns for the files whose Windows line
+	// endings should be converted to Unix line endings. Patterns without a
+	// slash match the file's name, and other patterns match the file's path
+	// relative to the folder.
+	NormalizeLineEndings []string
+}
+
+// FixFolder applies the fixes to all the files in the folder. It's used to
+// fix files that were synced before the sandbox started watching for synced
+// files.
+func FixFolder(dir string, fixes FileFixes) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// The file was probably deleted while we were walking.
+			return nil
+		}
+
+		if info.IsDir() && info.Name() == Marker {
+			return filepath.SkipDir
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return errors.WithContext("get relative path", err)
+		}
+
+		if err := FixFile(dir, relPath, fixes); err != nil {
+			log.WithError(err).WithField("path", path).Warn("Failed to fix file")
+		}
+		return nil
+	})
+}
+
+// FixFile applies the fixes to the file at relPath within the folder at dir.
+// Symlinks are chowned, but not followed.
+func FixFile(dir, relPath string, fixes FileFixes) error {
+	path := filepath.Join(dir, relPath)
+	info, err := os.Lstat(path)
+	if err != nil {
+		return errors.WithContext("stat", err)
+	}
+
+	if fixes.Owner != nil {
+		if err := os.Lchown(path, fixes.Owner.UID, fixes.Owner.GID); err != nil {
+			return errors.WithContext("chown", err)
+		}
+	}
+
+	mode := info.Mode()
+	if !mode.IsRegular() {
+		return nil
+	}
+
+	if matchesAny(fixes.NormalizeLineEndings, relPath) {
+		if err := normalizeLineEndings(path, mode.Perm()); err != nil {
+			return errors.WithContext("normalize line endings", err)
+		}
+	}
+
+	if fixes.InferExecutable && mode&0111 == 0 {
+		isScript, err := hasShebang(path)
+		if err != nil {
+			return errors.WithContext("read shebang", err)
+		}
+
+		if isScript {
+			// Allow executing the file by anyone who can read it.
+			executable := mode.Perm() | (mode.Perm()&0444)>>2
+			if err := os.Chmod(path, executable); err != nil {
+				return errors.WithContext("chmod", err)
+			}
+		}
+	}
+	return nil
+}
+
+func hasShebang(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(f, header); err != nil {
+		// Empty files aren't scripts.
+		return false, nil
+	}
+	return string(header) == "#!", nil
+}
+
+func matchesAny(patterns []string, relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	for _, pattern := range patterns {
+		name := relPath
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(relPath)
+		}
+
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLineEndings converts CRLF line endings to LF. Files that look
+// binary are left alone. The file is modified in place, so the change is
+// also synced back to the local machine.
+func normalizeLineEndings(path string, perm os.FileMode) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Contains(contents, []byte("\r\n")) || bytes.IndexByte(contents, 0) != -1 {
+		return nil
+	}
+
+	normalized := bytes.ReplaceAll(contents, []byte("\r\n"), []byte("\n"))
+	return ioutil.WriteFile(path, normalized, perm)
+}
+
+// WatchFiles applies the fixes to files as they're synced by the Syncthing at
+// the given API. folders maps folder IDs to their paths, and fixes maps folder
+// IDs to the fixes for the folder's files. It runs until the context is
+// cancelled.
+func WatchFiles(ctx context.Context, api APIClient, folders map[string]string,
+	fixes map[string]FileFixes) {
+
+	var since int
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		events, err := api.GetEvents(since, "ItemFinished", time.Minute)
+		if err != nil {
+			log.WithError(err).Debug("Failed to get Syncthing events")
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
+		for _, event := range events {
+			since = event.ID
+
+			folderFixes, ok := fixes[event.Data.Folder]
+			if !ok || event.Data.Error != nil || event.Data.Action == "delete" {
+				continue
+			}
+
+			dir := folders[event.Data.Folder]
+			if err := FixFile(dir, event.Data.Item, folderFixes); err != nil {
+				log.WithError(err).WithField("path", filepath.Join(dir, event.Data.Item)).
+					Warn("Failed to fix file")
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package syncthing_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelda/blimp/pkg/syncthing"
+)
+
+func TestParseOwner(t *testing.T) {
+	owner, err := syncthing.ParseOwner("1000:50")
+	assert.NoError(t, err)
+	assert.Equal(t, syncthing.Owner{UID: 1000, GID: 50}, owner)
+	assert.Equal(t, "1000:50", owner.String())
+
+	owner, err = syncthing.ParseOwner("1000")
+	assert.NoError(t, err)
+	assert.Equal(t, syncthing.Owner{UID: 1000, GID: 1000}, owner)
+
+	for _, malformed := range []string{"", "node", "1000:", "-1:0"} {
+		_, err = syncthing.ParseOwner(malformed)
+		assert.Error(t, err, malformed)
+	}
+}
+
+func TestFileFixesArgs(t *testing.T) {
+	args := syncthing.PermissionsToArgs(map[string]string{"src": "1000:1000"}, []string{"src", "bin"})
+	args = append(args, syncthing.LineEndingsToArgs(map[string][]string{
+		"src": {"*.sh", "data,old/*.csv"},
+	})...)
+	args = append(args, "src,/pv/src")
+
+	fixes, err := syncthing.ArgsToFileFixes(args)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]syncthing.FileFixes{
+		"src": {
+			Owner:                &syncthing.Owner{UID: 1000, GID: 1000},
+			InferExecutable:      true,
+			NormalizeLineEndings: []string{"*.sh", "data,old/*.csv"},
+		},
+		"bin": {InferExecutable: true},
+	}, fixes)
+	assert.Equal(t, map[string]string{"src": "/pv/src"}, syncthing.ArgsToMap(args))
+}
+
+func TestFixFolder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blimp-file-fixes")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]struct {
+		contents    string
+		mode        os.FileMode
+		expContents string
+		expMode     os.FileMode
+	}{
+		"script.sh":        {"#!/bin/sh\r\necho hi\r\n", 0644, "#!/bin/sh\necho hi\n", 0755},
+		"private.sh":       {"#!/bin/sh\necho hi\n", 0600, "#!/bin/sh\necho hi\n", 0700},
+		"executable.sh":    {"#!/bin/sh\necho hi\n", 0750, "#!/bin/sh\necho hi\n", 0750},
+		"README.md":        {"# Readme\r\n", 0644, "# Readme\r\n", 0644},
+		"empty":            {"", 0644, "", 0644},
+		"scripts/build.py": {"print(1)\r\n", 0644, "print(1)\n", 0644},
+		"scripts/data.py":  {"\x00\r\n", 0644, "\x00\r\n", 0644},
+		"other/build.py":   {"print(1)\r\n", 0644, "print(1)\r\n", 0644},
+	}
+	for name, file := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(file.contents), file.mode))
+		require.NoError(t, os.Chmod(path, file.mode))
+	}
+
+	// Chowning to the current user is allowed without root.
+	owner := syncthing.Owner{UID: os.Getuid(), GID: os.Getgid()}
+	fixes := syncthing.FileFixes{
+		Owner:                &owner,
+		InferExecutable:      true,
+		NormalizeLineEndings: []string{"*.sh", "scripts/*.py"},
+	}
+	require.NoError(t, syncthing.FixFolder(dir, fixes))
+
+	for name, file := range files {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, file.expMode, info.Mode().Perm(), name)
+
+		contents, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, file.expContents, string(contents), name)
+	}
+}
@@ -0,0 +1,44 @@
+package syncthing
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// LargeFile is a file that's skipped by syncing because it's too large.
+type LargeFile struct {
+	// Path is relative to the directory that was searched.
+	Path string
+	Size int64
+}
+
+// FindLargeFiles returns the files in the directory that are larger than
+// maxSize bytes. The files can then be added to the volume's Masks so that
+// they're not synced.
+func FindLargeFiles(dir string, maxSize int64) ([]LargeFile, error) {
+	var largeFiles []LargeFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Ignore files that were deleted, or can't be read, while walking.
+			// Syncthing will report them if they can't be synced.
+			return nil
+		}
+
+		if !info.Mode().IsRegular() || info.Size() <= maxSize {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return errors.WithContext("get relative path", err)
+		}
+		largeFiles = append(largeFiles, LargeFile{Path: relPath, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return largeFiles, nil
+}
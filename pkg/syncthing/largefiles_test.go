@@ -0,0 +1,38 @@
+package syncthing_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelda/blimp/pkg/syncthing"
+)
+
+func TestFindLargeFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blimp-large-files")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]int{
+		"small.txt":          10,
+		"exact.bin":          100,
+		"data/large.db":      101,
+		"data/nested/big.gz": 1000,
+	}
+	for path, size := range files {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, make([]byte, size), 0644))
+	}
+
+	largeFiles, err := syncthing.FindLargeFiles(dir, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []syncthing.LargeFile{
+		{Path: filepath.Join("data", "large.db"), Size: 101},
+		{Path: filepath.Join("data", "nested", "big.gz"), Size: 1000},
+	}, largeFiles)
+}
@@ -0,0 +1,130 @@
+package syncthing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// LocalAPI and RemoteAPI connect to the Syncthing daemons that sync files
+// while `blimp up` is running. The remote daemon is reached through the
+// tunnel started by `blimp up`.
+var (
+	LocalAPI  = APIClient{fmt.Sprintf("127.0.0.1:%d", APIPort)}
+	RemoteAPI = APIClient{fmt.Sprintf("127.0.0.1:%d", TunneledAPIPort)}
+)
+
+// Conflict is a file that differs between the local and remote folders.
+type Conflict struct {
+	// Folder is the local path of the folder containing the file.
+	Folder string
+
+	// Path is the file's path relative to the folder.
+	Path string
+
+	Local  FileInfo
+	Remote FileInfo
+}
+
+// Resync rescans the folders on both devices, and waits for them to sync.
+// This picks up changes that were missed by the filesystem watchers, such as
+// files that were replaced by editors that save by renaming a temporary file.
+func Resync(ctx context.Context, local, remote APIClient, folders []string) error {
+	for _, folder := range folders {
+		// Scans block until they complete.
+		if err := local.Scan(folder); err != nil {
+			return errors.WithContext("scan local folder", err)
+		}
+
+		if err := remote.Scan(folder); err != nil {
+			return errors.WithContext("scan remote folder", err)
+		}
+	}
+
+	return waitUntil(ctx, 10, func() progressStatus {
+		log.Debug("Waiting for Syncthing to resync")
+
+		synced, err := inSync(local, folders)
+		if err != nil {
+			return progressStatus{phase: ProgressError, err: err}
+		}
+
+		if synced {
+			return progressStatus{phase: ProgressDone}
+		}
+		return progressStatus{phase: ProgressPending}
+	})
+}
+
+// inSync returns whether neither device needs any files from the other.
+func inSync(local APIClient, folders []string) (bool, error) {
+	remoteSynced, err := remoteCompleted(local, folders)
+	if err != nil || !remoteSynced {
+		return false, err
+	}
+
+	for _, folder := range folders {
+		need, err := local.GetNeed(folder)
+		if err != nil {
+			return false, errors.WithContext("get local need", err)
+		}
+
+		if len(need.All()) != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// GetConflicts returns the files that differ between the local and remote
+// devices, sorted by folder and path.
+func GetConflicts(local, remote APIClient, folders []FolderConfig) ([]Conflict, error) {
+	var conflicts []Conflict
+	for _, folder := range folders {
+		remoteNeed, err := local.GetRemoteNeed(folder.ID, RemoteDeviceID)
+		if err != nil {
+			return nil, errors.WithContext("get remote need", err)
+		}
+
+		localNeed, err := local.GetNeed(folder.ID)
+		if err != nil {
+			return nil, errors.WithContext("get local need", err)
+		}
+
+		paths := map[string]struct{}{}
+		for _, file := range append(remoteNeed.All(), localNeed.All()...) {
+			paths[file.Name] = struct{}{}
+		}
+
+		for path := range paths {
+			localFile, err := local.GetFile(folder.ID, path)
+			if err != nil {
+				return nil, errors.WithContext(fmt.Sprintf("get local file %s", path), err)
+			}
+
+			remoteFile, err := remote.GetFile(folder.ID, path)
+			if err != nil {
+				return nil, errors.WithContext(fmt.Sprintf("get remote file %s", path), err)
+			}
+
+			conflicts = append(conflicts, Conflict{
+				Folder: folder.Path,
+				Path:   path,
+				Local:  localFile.Local,
+				Remote: remoteFile.Local,
+			})
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Folder != conflicts[j].Folder {
+			return conflicts[i].Folder < conflicts[j].Folder
+		}
+		return conflicts[i].Path < conflicts[j].Path
+	})
+	return conflicts, nil
+}
@@ -0,0 +1,96 @@
+package syncthing_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelda/blimp/pkg/syncthing"
+)
+
+// fakeAPI serves the given responses, keyed by the request's path and the
+// `file` query parameter, if any.
+func fakeAPI(t *testing.T, responses map[string]interface{}) (syncthing.APIClient, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if file := r.URL.Query().Get("file"); file != "" {
+			key += "?file=" + file
+		}
+
+		resp, ok := responses[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	return syncthing.APIClient{Address: strings.TrimPrefix(server.URL, "http://")}, server.Close
+}
+
+func TestGetConflicts(t *testing.T) {
+	older := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	local, closeLocal := fakeAPI(t, map[string]interface{}{
+		"/rest/db/remoteneed": syncthing.Need{
+			Files: []syncthing.FileInfo{{Name: "src/app.js"}},
+		},
+		"/rest/db/need": syncthing.Need{
+			Rest: []syncthing.FileInfo{{Name: "package-lock.json"}, {Name: "src/app.js"}},
+		},
+		"/rest/db/file?file=src/app.js": syncthing.FileDetails{
+			Local: syncthing.FileInfo{Name: "src/app.js", Size: 20, Modified: newer},
+		},
+		"/rest/db/file?file=package-lock.json": syncthing.FileDetails{
+			Local: syncthing.FileInfo{Name: "package-lock.json", Size: 100, Modified: older},
+		},
+	})
+	defer closeLocal()
+
+	remote, closeRemote := fakeAPI(t, map[string]interface{}{
+		"/rest/db/file?file=src/app.js": syncthing.FileDetails{
+			Local: syncthing.FileInfo{Name: "src/app.js", Size: 10, Modified: older},
+		},
+		"/rest/db/file?file=package-lock.json": syncthing.FileDetails{
+			Local: syncthing.FileInfo{Name: "package-lock.json", Size: 200, Modified: newer},
+		},
+	})
+	defer closeRemote()
+
+	conflicts, err := syncthing.GetConflicts(local, remote, []syncthing.FolderConfig{
+		{ID: "folder", Path: "/Users/kevin/app"},
+	})
+	require.NoError(t, err)
+
+	// The times are compared separately since they lose their location when
+	// they're decoded.
+	for i := range conflicts {
+		assert.True(t, conflicts[i].Local.Modified.Equal(map[string]time.Time{
+			"package-lock.json": older,
+			"src/app.js":        newer,
+		}[conflicts[i].Path]))
+		conflicts[i].Local.Modified = time.Time{}
+		conflicts[i].Remote.Modified = time.Time{}
+	}
+
+	assert.Equal(t, []syncthing.Conflict{
+		{
+			Folder: "/Users/kevin/app",
+			Path:   "package-lock.json",
+			Local:  syncthing.FileInfo{Name: "package-lock.json", Size: 100},
+			Remote: syncthing.FileInfo{Name: "package-lock.json", Size: 200},
+		},
+		{
+			Folder: "/Users/kevin/app",
+			Path:   "src/app.js",
+			Local:  syncthing.FileInfo{Name: "src/app.js", Size: 20},
+			Remote: syncthing.FileInfo{Name: "src/app.js", Size: 10},
+		},
+	}, conflicts)
+}
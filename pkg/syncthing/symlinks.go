@@ -0,0 +1,60 @@
+package syncthing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+// Symlink is a symlink within a synced folder.
+type Symlink struct {
+	// Path is relative to the directory that was searched.
+	Path   string
+	Target string
+
+	// External is set if the symlink points outside of the directory that
+	// was searched, or is an absolute path. Absolute paths are broken in the
+	// sandbox since the folder is synced to a different path.
+	External bool
+}
+
+// FindSymlinks returns the symlinks in the directory.
+func FindSymlinks(dir string) ([]Symlink, error) {
+	var symlinks []Symlink
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			// Ignore files that were deleted, or can't be read, while walking.
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return errors.WithContext("get relative path", err)
+		}
+
+		target, err := os.Readlink(path)
+		if err != nil {
+			return nil
+		}
+
+		external := filepath.IsAbs(target)
+		if !external {
+			resolved := filepath.Join(filepath.Dir(relPath), target)
+			external = resolved == ".." ||
+				strings.HasPrefix(resolved, ".."+string(filepath.Separator))
+		}
+
+		symlinks = append(symlinks, Symlink{
+			Path:     relPath,
+			Target:   target,
+			External: external,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return symlinks, nil
+}
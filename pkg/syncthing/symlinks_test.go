@@ -0,0 +1,39 @@
+package syncthing_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelda/blimp/pkg/syncthing"
+)
+
+func TestFindSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blimp-symlinks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bin"), 0755))
+	symlinks := map[string]string{
+		"bin/run":    "../scripts/run.sh",
+		"bin/local":  "run",
+		"config":     "/etc/app/config",
+		"bin/shared": "../../shared",
+	}
+	for path, target := range symlinks {
+		require.NoError(t, os.Symlink(target, filepath.Join(dir, path)))
+	}
+
+	found, err := syncthing.FindSymlinks(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []syncthing.Symlink{
+		{Path: filepath.Join("bin", "local"), Target: "run"},
+		{Path: filepath.Join("bin", "run"), Target: "../scripts/run.sh"},
+		{Path: filepath.Join("bin", "shared"), Target: "../../shared", External: true},
+		{Path: "config", Target: "/etc/app/config", External: true},
+	}, found)
+}
@@ -1,6 +1,8 @@
 package syncthing
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"sort"
@@ -8,6 +10,8 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/errors"
 )
 
 const (
@@ -23,6 +27,16 @@ const (
 	APIPort         = 8384
 	TunneledAPIPort = 8385
 
+	// deltaArgPrefix marks the arguments to the sandbox's Syncthing that
+	// contain the IDs of folders that should use delta transfers.
+	deltaArgPrefix = "delta="
+
+	// ownerArgPrefix, inferExecArgPrefix, and lfArgPrefix mark the arguments
+	// that contain the FileFixes for folders.
+	ownerArgPrefix     = "owner="
+	inferExecArgPrefix = "inferexec="
+	lfArgPrefix        = "lf="
+
 	CLIDeviceID    = "ROHA7NN-4KWKQ3Q-CHJMZBK-6UD7Z6D-ZTWQR5C-TYLN6WG-Q2EQJAI-JU73EQN"
 	RemoteDeviceID = "K6QHA3P-VGHXBZE-2NILDY3-Y4E2EUU-7DCSOVF-DFVCQRM-P5BVGMB-LDLP6QA"
 )
@@ -61,9 +75,105 @@ func MapToArgs(m map[string]string) []string {
 	return args
 }
 
+// DeltaToArgs converts the IDs of folders that should use delta transfers
+// into arguments for the sandbox's Syncthing.
+func DeltaToArgs(folders []string) []string {
+	var args []string
+	for _, id := range folders {
+		args = append(args, deltaArgPrefix+id)
+	}
+	sort.Strings(args)
+	return args
+}
+
+// ArgsToDelta returns the IDs of the folders that should use delta transfers.
+func ArgsToDelta(args []string) map[string]bool {
+	delta := map[string]bool{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, deltaArgPrefix) {
+			delta[strings.TrimPrefix(arg, deltaArgPrefix)] = true
+		}
+	}
+	return delta
+}
+
+// PermissionsToArgs converts the permissions for each folder into arguments
+// for the sandbox's Syncthing. owners maps folder IDs to owners of the form
+// `UID:GID`.
+func PermissionsToArgs(owners map[string]string, inferExecutable []string) []string {
+	var args []string
+	for id, owner := range owners {
+		args = append(args, ownerArgPrefix+id+","+owner)
+	}
+	for _, id := range inferExecutable {
+		args = append(args, inferExecArgPrefix+id)
+	}
+	sort.Strings(args)
+	return args
+}
+
+// LineEndingsToArgs converts the patterns for the files whose line endings
+// should be normalized, keyed by folder ID, into arguments for the sandbox's
+// Syncthing.
+func LineEndingsToArgs(patterns map[string][]string) []string {
+	var args []string
+	for id, folderPatterns := range patterns {
+		for _, pattern := range folderPatterns {
+			args = append(args, lfArgPrefix+id+","+pattern)
+		}
+	}
+	sort.Strings(args)
+	return args
+}
+
+// ArgsToFileFixes returns the FileFixes for each folder.
+func ArgsToFileFixes(args []string) (map[string]FileFixes, error) {
+	fixes := map[string]FileFixes{}
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, ownerArgPrefix):
+			kv := strings.SplitN(strings.TrimPrefix(arg, ownerArgPrefix), ",", 2)
+			if len(kv) != 2 {
+				return nil, errors.New("malformed owner argument %q", arg)
+			}
+
+			owner, err := ParseOwner(kv[1])
+			if err != nil {
+				return nil, errors.WithContext("parse owner", err)
+			}
+
+			folderFixes := fixes[kv[0]]
+			folderFixes.Owner = &owner
+			fixes[kv[0]] = folderFixes
+		case strings.HasPrefix(arg, inferExecArgPrefix):
+			id := strings.TrimPrefix(arg, inferExecArgPrefix)
+			folderFixes := fixes[id]
+			folderFixes.InferExecutable = true
+			fixes[id] = folderFixes
+		case strings.HasPrefix(arg, lfArgPrefix):
+			kv := strings.SplitN(strings.TrimPrefix(arg, lfArgPrefix), ",", 2)
+			if len(kv) != 2 {
+				return nil, errors.New("malformed line ending argument %q", arg)
+			}
+
+			folderFixes := fixes[kv[0]]
+			folderFixes.NormalizeLineEndings = append(folderFixes.NormalizeLineEndings, kv[1])
+			fixes[kv[0]] = folderFixes
+		}
+	}
+	return fixes, nil
+}
+
 func ArgsToMap(args []string) map[string]string {
 	m := map[string]string{}
 	for _, arg := range args {
+		if strings.HasPrefix(arg, deltaArgPrefix) ||
+			strings.HasPrefix(arg, ownerArgPrefix) ||
+			strings.HasPrefix(arg, inferExecArgPrefix) ||
+			strings.HasPrefix(arg, lfArgPrefix) {
+			continue
+		}
+
 		kv := strings.Split(arg, ",")
 		path := kv[1]
 
@@ -87,16 +197,40 @@ func MakeMarkers(folders map[string]string) error {
 	return nil
 }
 
-func MakeServer(folders map[string]string) string {
-	return makeConfig(true, folders, "sendreceive")
+func MakeServer(folders map[string]string, delta map[string]bool) string {
+	return makeConfig(true, folders, "sendreceive", configOptions{
+		compression: "always",
+		delta:       delta,
+	})
 }
 
-func makeConfig(server bool, folders map[string]string, folderType string) string {
+type configOptions struct {
+	// compression is the Syncthing compression mode for data sent to the
+	// other device.
+	compression string
+
+	// delta contains the IDs of the folders that always use weak hashes when
+	// pulling files, so that blocks that shifted because data was inserted
+	// earlier in the file are reused rather than transferred again.
+	delta map[string]bool
+
+	// ignorePerms stops the device from syncing permission bits. It's set on
+	// Windows, which doesn't have permission bits, so that the sandbox keeps
+	// the executable bits that it already has, rather than having them reset.
+	ignorePerms bool
+
+	// maxSendKbps and maxRecvKbps limit the bandwidth used by the device, in
+	// KiB per second. Zero means unlimited.
+	maxSendKbps int64
+	maxRecvKbps int64
+}
+
+func makeConfig(server bool, folders map[string]string, folderType string, opts configOptions) string {
 	// A folder is a map from folder ID to a path.
 
 	var folderStrs []string
 	for id, path := range folders {
-		folderStrs = append(folderStrs, makeFolder(id, path, folderType))
+		folderStrs = append(folderStrs, makeFolder(id, path, folderType, opts.delta[id], opts.ignorePerms))
 	}
 
 	var listenAddress, address string
@@ -117,12 +251,17 @@ func makeConfig(server bool, folders map[string]string, folderType string) strin
         <address>%s</address>
         <apikey>%s</apikey>
     </gui>
-    <device id="%s" compression="always">
+    <device id="%s" compression="%s">
         <address>%s</address>
     </device>
-    <device id="%s" compression="always"/>
+    <device id="%s" compression="%s"/>
     <options>
         <listenAddress>%s</listenAddress>
+        <maxSendKbps>%d</maxSendKbps>
+        <maxRecvKbps>%d</maxRecvKbps>
+
+        <!-- The devices connect over a tunnel to localhost, which would otherwise be exempt from the limits. -->
+        <limitBandwidthInLan>true</limitBandwidthInLan>
         <globalAnnounceEnabled>false</globalAnnounceEnabled>
         <localAnnounceEnabled>false</localAnnounceEnabled>
         <reconnectionIntervalS>10</reconnectionIntervalS>
@@ -143,25 +282,38 @@ func makeConfig(server bool, folders map[string]string, folderType string) strin
         <keepTemporariesH>0</keepTemporariesH>
     </options>
 </configuration>`,
-		strings.Join(folderStrs, ""), guiAddress, apiKey, RemoteDeviceID,
-		address, CLIDeviceID, listenAddress)
+		strings.Join(folderStrs, ""), guiAddress, apiKey, RemoteDeviceID, opts.compression,
+		address, CLIDeviceID, opts.compression, listenAddress, opts.maxSendKbps, opts.maxRecvKbps)
 }
 
-func makeFolder(id, path, folderType string) string {
+func makeFolder(id, path, folderType string, delta, ignorePerms bool) string {
+	// Paths may contain characters that must be escaped in XML, such as
+	// ampersands in Windows user names. Writes to a bytes.Buffer never fail.
+	var escapedPath bytes.Buffer
+	_ = xml.EscapeText(&escapedPath, []byte(path))
+
+	// Syncthing's default is to only use weak hashes when at least 25% of a
+	// file changed.
+	weakHashThresholdPct := 25
+	if delta {
+		weakHashThresholdPct = -1
+	}
+
 	//nolint:lll
 	return fmt.Sprintf(`
     <folder id="%s" path="%s" type="%s"
         rescanIntervalS="30" fsWatcherEnabled="true" fsWatcherDelayS="1"
-        autoNormalize="true">
+        autoNormalize="true" ignorePerms="%t">
         <device id="%s"/>
         <device id="%s"/>
         <order>oldestFirst</order>
         <markerName>%s</markerName>
         <ignoreDelete>false</ignoreDelete>
+        <weakHashThresholdPct>%d</weakHashThresholdPct>
 
         <!-- Don't create conflict files. We just let Syncthing resolve the conflict based on modtime, which is basically always good enough.-->
         <maxConflicts>0</maxConflicts>
-    </folder>`, id, path, folderType, RemoteDeviceID, CLIDeviceID, Marker)
+    </folder>`, id, escapedPath.String(), folderType, ignorePerms, RemoteDeviceID, CLIDeviceID, Marker, weakHashThresholdPct)
 }
 
 func ensureDirExists(path string) {
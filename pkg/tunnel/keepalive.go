@@ -0,0 +1,96 @@
+package tunnel
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// These are variables so that the tests can shorten them.
+var (
+	// keepAlivePeriod is how often keepalives are sent on idle connections.
+	// It's well below the idle timeouts of common NATs and proxies, such as
+	// the 60 second timeout of AWS ELBs.
+	keepAlivePeriod = 20 * time.Second
+
+	// deadAfter is how long a tunnel can go without hearing from its peer
+	// before it's assumed to be half-open, and is closed. Peers send a
+	// keepalive at least every 2*keepAlivePeriod.
+	deadAfter = 90 * time.Second
+)
+
+// enableKeepAlive turns on TCP keepalives for the connection, so that idle
+// connections aren't dropped by NATs, and so that the kernel detects
+// connections whose other end disappeared.
+func enableKeepAlive(conn net.Conn) {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return
+	}
+
+	if err := tcpConn.SetKeepAlive(true); err != nil {
+		log.WithError(err).Debug("Failed to enable TCP keepalive")
+		return
+	}
+	if err := tcpConn.SetKeepAlivePeriod(keepAlivePeriod); err != nil {
+		log.WithError(err).Debug("Failed to set TCP keepalive period")
+	}
+}
+
+// Tunnels are kept alive by sending empty buffers when they're idle. Empty
+// buffers are harmless to peers that predate keepalives, since writing an
+// empty buffer is a no-op. Liveness is only enforced once the peer has sent
+// a keepalive, so that idle tunnels to old peers aren't closed.
+type liveness struct {
+	// peerSendsKeepAlives is set to 1 once a keepalive is received.
+	peerSendsKeepAlives int32
+
+	// recvSince is the UnixNano time that the tunnel started waiting for a
+	// message from the peer. It's zero while the tunnel isn't waiting, such
+	// as while it's blocked writing to a slow local connection.
+	recvSince int64
+}
+
+func (l *liveness) startRecv() {
+	atomic.StoreInt64(&l.recvSince, time.Now().UnixNano())
+}
+
+func (l *liveness) finishRecv(keepAlive bool) {
+	atomic.StoreInt64(&l.recvSince, 0)
+	if keepAlive {
+		atomic.StoreInt32(&l.peerSendsKeepAlives, 1)
+	}
+}
+
+// peerDead returns whether the peer hasn't sent anything for longer than
+// deadAfter.
+func (l *liveness) peerDead(now time.Time) bool {
+	if atomic.LoadInt32(&l.peerSendsKeepAlives) == 0 {
+		return false
+	}
+
+	recvSince := atomic.LoadInt64(&l.recvSince)
+	return recvSince != 0 && now.Sub(time.Unix(0, recvSince)) > deadAfter
+}
+
+// isDeadConnErr returns whether the error is because the other end of the
+// connection stopped responding, as opposed to it closing the connection.
+func isDeadConnErr(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
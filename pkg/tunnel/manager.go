@@ -11,38 +11,87 @@ import (
 )
 
 type Manager struct {
-	ncc  node.ControllerClient
-	auth *auth.BlimpAuth
+	ncc   node.ControllerClient
+	auth  *auth.BlimpAuth
+	bulk  bool
+	ready ReadyFunc
 }
 
+// ReadyFunc returns a channel that's closed once the service is ready to
+// accept connections.
+type ReadyFunc func(service string) <-chan struct{}
+
 func NewManager(ncc node.ControllerClient, auth *auth.BlimpAuth) Manager {
-	return Manager{ncc, auth}
+	return Manager{ncc: ncc, auth: auth}
+}
+
+// Bulk returns a Manager whose tunnels are for bulk transfers, such as file
+// sync. Bulk tunnels are slowed down while other tunnels are in use, so that
+// they don't make services slow to respond.
+func (m Manager) Bulk() Manager {
+	m.bulk = true
+	return m
 }
 
 func (m Manager) Run(hostIP string, hostPort uint32, serviceName string, servicePort uint32, readyNotifier chan struct{}) error {
-	addr := fmt.Sprintf("%s:%d", hostIP, hostPort)
-	ln, err := net.Listen("tcp", addr)
+	ln, err := Listen(hostIP, hostPort, serviceName)
 	if err != nil {
-		switch {
-		case strings.Contains(err.Error(), "permission denied"):
-			return errors.NewFriendlyError("Permission denied while listening for connections\n"+
-				"Make sure that the local port for the service %q is above 1024.\n\n"+
-				"The full error was:\n%s", serviceName, err)
-		case strings.Contains(err.Error(), "address already in use"):
-			return errors.NewFriendlyError("Another process is already listening on the same port\n"+
-				"If you have been using docker-compose, make sure to run docker-compose down.\n"+
-				"Make sure that the there aren't any other "+
-				"services listening locally on port %d. This can be checked with the following command:\n"+
-				"sudo lsof -i -P -n | grep :%d\n\n"+
-				"The full error was:\n%s", hostPort, hostPort, err)
-		}
-
-		return errors.WithContext("listen locally", err)
+		return err
 	}
 
 	if readyNotifier != nil {
 		close(readyNotifier)
 	}
 
-	return Client(m.ncc, ln, m.auth, serviceName, servicePort)
+	return m.Serve(ln, serviceName, servicePort)
+}
+
+// WaitForReady returns a Manager whose tunnels hold new connections until
+// the service is ready, rather than forwarding them to a service that would
+// reset them.
+func (m Manager) WaitForReady(ready ReadyFunc) Manager {
+	m.ready = ready
+	return m
+}
+
+// Serve forwards the connections to the listener to the service.
+func (m Manager) Serve(ln net.Listener, serviceName string, servicePort uint32) error {
+	return client(m.ncc, ln, m.auth, serviceName, servicePort, m.bulk, m.ready)
+}
+
+// Listen listens for connections to forward to the service.
+func Listen(hostIP string, hostPort uint32, serviceName string) (net.Listener, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf("%s:%d", hostIP, hostPort))
+	if err != nil {
+		return nil, ListenError(err, hostPort, serviceName)
+	}
+	return ln, nil
+}
+
+// ListenError converts errors from listening on a local port into friendly
+// errors that explain how to fix common problems.
+func ListenError(err error, hostPort uint32, serviceName string) error {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		return errors.NewFriendlyError("Permission denied while listening for connections\n"+
+			"Make sure that the local port for the service %q is above 1024.\n\n"+
+			"The full error was:\n%s", serviceName, err)
+	case IsAddressInUse(err):
+		return errors.NewFriendlyError("Another process is already listening on the same port\n"+
+			"If you have been using docker-compose, make sure to run docker-compose down.\n"+
+			"Make sure that the there aren't any other "+
+			"services listening locally on port %d. This can be checked with the following command:\n"+
+			"sudo lsof -i -P -n | grep :%d\n\n"+
+			"The full error was:\n%s", hostPort, hostPort, err)
+	}
+
+	return errors.WithContext("listen locally", err)
+}
+
+// IsAddressInUse returns whether the error is because another process is
+// already listening on the address.
+func IsAddressInUse(err error) bool {
+	// Windows uses a different message.
+	return strings.Contains(err.Error(), "address already in use") ||
+		strings.Contains(err.Error(), "Only one usage of each socket address")
 }
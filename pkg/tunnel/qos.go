@@ -0,0 +1,120 @@
+package tunnel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// limiterBurst is the most data that's sent at once by a rate limited
+	// tunnel.
+	limiterBurst = 64 * 1024
+
+	// interactiveWindow is how long after interactive traffic that bulk
+	// traffic is slowed down.
+	interactiveWindow = time.Second
+
+	// bulkRateWhileInteractive is the rate at which bulk traffic is sent
+	// while interactive traffic is active. All bulk tunnels share the rate.
+	bulkRateWhileInteractive = 256 * 1024
+)
+
+// Limits are the maximum rates, in bytes per second, for traffic sent and
+// received by tunnels. Zero means unlimited.
+type Limits struct {
+	Upload   int64
+	Download int64
+}
+
+// shaper limits the rate of traffic through tunnels. Tunnels are either
+// interactive, such as tunnels to services, or bulk, such as the tunnels
+// used for file sync and image builds. Bulk traffic yields to interactive
+// traffic so that large transfers don't make services slow to respond.
+type shaper struct {
+	upload   *rate.Limiter
+	download *rate.Limiter
+
+	// bulkWhileInteractive is shared by all bulk tunnels, and is only used
+	// while interactive traffic is active.
+	bulkWhileInteractive *rate.Limiter
+
+	// lastInteractive is the UnixNano time of the last interactive traffic.
+	lastInteractive int64
+}
+
+var (
+	shaperLock sync.Mutex
+	current    = newShaper(Limits{})
+)
+
+// SetLimits sets the bandwidth limits for tunnels. The limits apply to the
+// combined traffic of all tunnels, and only affect tunnels created after
+// they're set.
+func SetLimits(limits Limits) {
+	shaperLock.Lock()
+	defer shaperLock.Unlock()
+	current = newShaper(limits)
+}
+
+func getShaper() *shaper {
+	shaperLock.Lock()
+	defer shaperLock.Unlock()
+	return current
+}
+
+func newShaper(limits Limits) *shaper {
+	newLimiter := func(bytesPerSecond int64) *rate.Limiter {
+		if bytesPerSecond <= 0 {
+			return nil
+		}
+		return rate.NewLimiter(rate.Limit(bytesPerSecond), limiterBurst)
+	}
+
+	return &shaper{
+		upload:               newLimiter(limits.Upload),
+		download:             newLimiter(limits.Download),
+		bulkWhileInteractive: newLimiter(bulkRateWhileInteractive),
+	}
+}
+
+// wait blocks until n bytes may be sent or received, depending on whether
+// limiter is the upload or download limiter.
+func (s *shaper) wait(ctx context.Context, limiter *rate.Limiter, bulk bool, n int) error {
+	if bulk {
+		lastInteractive := time.Unix(0, atomic.LoadInt64(&s.lastInteractive))
+		if time.Since(lastInteractive) < interactiveWindow {
+			if err := waitN(ctx, s.bulkWhileInteractive, n); err != nil {
+				return err
+			}
+		}
+	} else {
+		atomic.StoreInt64(&s.lastInteractive, time.Now().UnixNano())
+	}
+
+	return waitN(ctx, limiter, n)
+}
+
+// waitN waits for n tokens from the limiter, in chunks no larger than the
+// limiter's burst. A nil limiter never blocks.
+func waitN(ctx context.Context, limiter *rate.Limiter, n int) error {
+	if limiter == nil {
+		return nil
+	}
+
+	for n > 0 {
+		chunk := n
+		if chunk > limiter.Burst() {
+			chunk = limiter.Burst()
+		}
+
+		if err := limiter.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
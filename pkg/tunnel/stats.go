@@ -0,0 +1,230 @@
+package tunnel
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+const (
+	// statsInterval is how often processes with tunnels write their
+	// statistics to disk for `blimp status --tunnels`.
+	statsInterval = 5 * time.Second
+
+	// statsStaleAfter is how long until the statistics written by a process
+	// are assumed to be from a process that exited.
+	statsStaleAfter = 3 * statsInterval
+)
+
+// Stats are the connection statistics for a tunnel.
+type Stats struct {
+	Service     string `json:"service"`
+	ServicePort uint32 `json:"servicePort"`
+	LocalAddr   string `json:"localAddr"`
+
+	// Bulk is true for the tunnels used internally for file sync and image
+	// builds.
+	Bulk bool `json:"bulk,omitempty"`
+
+	ActiveConnections int64 `json:"activeConnections"`
+	TotalConnections  int64 `json:"totalConnections"`
+
+	// DeadConnections is the number of connections that were closed because
+	// one end stopped responding, such as after a NAT timeout.
+	DeadConnections int64 `json:"deadConnections"`
+
+	// BytesSent and BytesReceived are from the perspective of the local
+	// client.
+	BytesSent     int64 `json:"bytesSent"`
+	BytesReceived int64 `json:"bytesReceived"`
+
+	// LastActivity is when data was last sent or received through the
+	// tunnel. It's zero if the tunnel hasn't been used.
+	LastActivity time.Time `json:"lastActivity,omitempty"`
+}
+
+// tunnelStats are updated concurrently by the tunnel's connections.
+type tunnelStats struct {
+	service     string
+	servicePort uint32
+	localAddr   string
+	bulk        bool
+
+	active, total, dead  int64
+	bytesSent, bytesRecv int64
+	lastActivityUnixNano int64
+}
+
+func (ts *tunnelStats) snapshot() Stats {
+	stats := Stats{
+		Service:           ts.service,
+		ServicePort:       ts.servicePort,
+		LocalAddr:         ts.localAddr,
+		Bulk:              ts.bulk,
+		ActiveConnections: atomic.LoadInt64(&ts.active),
+		TotalConnections:  atomic.LoadInt64(&ts.total),
+		DeadConnections:   atomic.LoadInt64(&ts.dead),
+		BytesSent:         atomic.LoadInt64(&ts.bytesSent),
+		BytesReceived:     atomic.LoadInt64(&ts.bytesRecv),
+	}
+	if lastActivity := atomic.LoadInt64(&ts.lastActivityUnixNano); lastActivity != 0 {
+		stats.LastActivity = time.Unix(0, lastActivity)
+	}
+	return stats
+}
+
+func (ts *tunnelStats) recordTraffic(sent, received int) {
+	if sent == 0 && received == 0 {
+		return
+	}
+	atomic.AddInt64(&ts.bytesSent, int64(sent))
+	atomic.AddInt64(&ts.bytesRecv, int64(received))
+	atomic.StoreInt64(&ts.lastActivityUnixNano, time.Now().UnixNano())
+}
+
+var (
+	statsLock      sync.Mutex
+	allStats       []*tunnelStats
+	startStatsOnce sync.Once
+)
+
+// registerStats starts tracking the statistics for a tunnel. The statistics
+// are periodically written to disk so that they can be read by other Blimp
+// processes.
+func registerStats(ts *tunnelStats) {
+	statsLock.Lock()
+	allStats = append(allStats, ts)
+	statsLock.Unlock()
+
+	startStatsOnce.Do(func() {
+		go writeStatsLoop()
+	})
+}
+
+func unregisterStats(ts *tunnelStats) {
+	statsLock.Lock()
+	defer statsLock.Unlock()
+	for i, other := range allStats {
+		if other == ts {
+			allStats = append(allStats[:i], allStats[i+1:]...)
+			return
+		}
+	}
+}
+
+func statsDir() string {
+	return cfgdir.Expand("tunnel-stats")
+}
+
+func writeStatsLoop() {
+	path := filepath.Join(statsDir(), strconv.Itoa(os.Getpid())+".json")
+	for {
+		statsLock.Lock()
+		var stats []Stats
+		for _, ts := range allStats {
+			stats = append(stats, ts.snapshot())
+		}
+		statsLock.Unlock()
+
+		if err := writeStats(path, stats); err != nil {
+			log.WithError(err).Debug("Failed to write tunnel stats")
+		}
+		time.Sleep(statsInterval)
+	}
+}
+
+func writeStats(path string, stats []Stats) error {
+	statsJSON, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that readers never see a partially
+	// written file.
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, statsJSON, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ReadStats returns the statistics for the tunnels run by all Blimp
+// processes on this machine, such as `blimp up` and `blimp tunnel`.
+func ReadStats() ([]Stats, error) {
+	files, err := ioutil.ReadDir(statsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.WithContext("read stats directory", err)
+	}
+
+	var result []Stats
+	for _, f := range files {
+		path := filepath.Join(statsDir(), f.Name())
+		if filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+
+		// Clean up after processes that exited.
+		if time.Since(f.ModTime()) > statsStaleAfter {
+			//nolint:errcheck // Another process may have already removed it.
+			os.Remove(path)
+			continue
+		}
+
+		statsJSON, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.WithContext("read stats", err)
+		}
+
+		var stats []Stats
+		if err := json.Unmarshal(statsJSON, &stats); err != nil {
+			log.WithError(err).WithField("path", path).Debug("Ignoring malformed tunnel stats")
+			continue
+		}
+		result = append(result, stats...)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Service != result[j].Service {
+			return result[i].Service < result[j].Service
+		}
+		return result[i].LocalAddr < result[j].LocalAddr
+	})
+	return result, nil
+}
+
+// statsConn records the traffic through a local connection.
+type statsConn struct {
+	net.Conn
+	stats *tunnelStats
+}
+
+func (c statsConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.stats.recordTraffic(n, 0)
+	return n, err
+}
+
+func (c statsConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.stats.recordTraffic(0, n)
+	return n, err
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
@@ -14,18 +16,41 @@ import (
 	"github.com/kelda/blimp/pkg/proto/node"
 )
 
+// readyTimeout is how long connections wait for the service to become ready
+// before they're closed.
+const readyTimeout = 5 * time.Minute
+
 type tunnel interface {
 	Send(*node.TunnelMsg) error
 	Recv() (*node.TunnelMsg, error)
 }
 
 func ServerStream(nsrv node.Controller_TunnelServer, stream net.Conn) {
-	streamBidirectional(stream, nsrv, func() {})
+	enableKeepAlive(stream)
+	streamBidirectional(stream, nsrv, func() {}, nil)
+}
+
+// ServerStreamUntil is like ServerStream, but closes the tunnel once
+// `deadline` passes, even if it's still in use. It's used for links that
+// expire.
+func ServerStreamUntil(nsrv node.Controller_TunnelServer, stream net.Conn, deadline time.Time) {
+	enableKeepAlive(stream)
+	expired := time.NewTimer(time.Until(deadline))
+	defer expired.Stop()
+	streamBidirectional(stream, nsrv, func() {}, expired.C)
 }
 
 // TODO, How does this thing get cleaned up?  Do we leak a go routine here?
 func Client(scc node.ControllerClient, ln net.Listener, auth *protoAuth.BlimpAuth,
 	name string, port uint32) error {
+	return client(scc, ln, auth, name, port, false, nil)
+}
+
+// client forwards connections to the listener through tunnels. Bulk tunnels
+// yield to other tunnels. If `ready` is set, connections aren't forwarded
+// until the service is ready.
+func client(scc node.ControllerClient, ln net.Listener, auth *protoAuth.BlimpAuth,
+	name string, port uint32, bulk bool, ready ReadyFunc) error {
 
 	fields := log.Fields{
 		"listen": ln.Addr().String(),
@@ -33,6 +58,15 @@ func Client(scc node.ControllerClient, ln net.Listener, auth *protoAuth.BlimpAut
 		"port":   port,
 	}
 
+	stats := &tunnelStats{
+		service:     name,
+		servicePort: port,
+		localAddr:   ln.Addr().String(),
+		bulk:        bulk,
+	}
+	registerStats(stats)
+	defer unregisterStats(stats)
+
 	for {
 		stream, err := ln.Accept()
 		if err != nil {
@@ -40,22 +74,50 @@ func Client(scc node.ControllerClient, ln net.Listener, auth *protoAuth.BlimpAut
 		}
 
 		log.WithFields(fields).Trace("new connection")
+		enableKeepAlive(stream)
 		go func() {
-			connect(scc, stream, auth, name, port)
+			if ready != nil && !waitForReady(ready(name)) {
+				log.WithFields(fields).Debug("Timed out waiting for service to become ready")
+				stream.Close()
+				return
+			}
+
+			atomic.AddInt64(&stats.active, 1)
+			atomic.AddInt64(&stats.total, 1)
+			dead := connect(scc, statsConn{Conn: stream, stats: stats}, auth, name, port, bulk)
+			atomic.AddInt64(&stats.active, -1)
+			if dead {
+				atomic.AddInt64(&stats.dead, 1)
+				log.WithFields(fields).Debug("Closed connection because the other end stopped responding")
+			}
 			log.WithFields(fields).Trace("finish connection")
 		}()
 	}
 }
 
+// waitForReady blocks until `ready` is closed. It returns false if the
+// service doesn't become ready within readyTimeout.
+func waitForReady(ready <-chan struct{}) bool {
+	select {
+	case <-ready:
+		return true
+	case <-time.After(readyTimeout):
+		return false
+	}
+}
+
+// connect forwards the connection through a new tunnel. It returns whether
+// the connection was closed because one end stopped responding.
 func connect(scc node.ControllerClient, stream net.Conn,
-	auth *protoAuth.BlimpAuth, name string, port uint32) {
+	auth *protoAuth.BlimpAuth, name string, port uint32, bulk bool) bool {
 	defer stream.Close()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	tnl, err := scc.Tunnel(ctx)
 	if err != nil {
 		log.WithError(err).Error("failed to establish tunnel")
-		return
+		cancel()
+		return isDeadConnErr(err)
 	}
 
 	err = tnl.Send(&node.TunnelMsg{Msg: &node.TunnelMsg_Header{
@@ -68,33 +130,157 @@ func connect(scc node.ControllerClient, stream net.Conn,
 		log.WithError(err).Error("failed to send tunnel connect")
 		//nolint:errcheck // Nothing we could do to handle this anyway.
 		tnl.CloseSend()
+		cancel()
+		return isDeadConnErr(err)
+	}
+
+	shaped := shapedTunnel{
+		tunnel: tnl,
+		ctx:    ctx,
+		shaper: getShaper(),
+		bulk:   bulk,
+	}
+	return streamBidirectional(stream, shaped, cancel, nil)
+}
+
+// ConnectSSHAgent forwards the connection announced by the node controller to
+// the local SSH agent.
+func ConnectSSHAgent(scc node.ControllerClient, agent net.Conn,
+	auth *protoAuth.BlimpAuth, id string) {
+	defer agent.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tnl, err := scc.SSHAgentTunnel(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to establish SSH agent tunnel")
+		cancel()
+		return
+	}
+
+	err = tnl.Send(&node.TunnelMsg{Msg: &node.TunnelMsg_SshAgentHeader{
+		SshAgentHeader: &node.SSHAgentTunnelHeader{
+			Auth: auth,
+			Id:   id,
+		}}})
+	if err != nil {
+		log.WithError(err).Error("failed to send SSH agent tunnel connect")
+		//nolint:errcheck // Nothing we could do to handle this anyway.
+		tnl.CloseSend()
+		cancel()
 		return
 	}
 
-	streamBidirectional(stream, tnl, cancel)
+	streamBidirectional(agent, tnl, cancel, nil)
+}
+
+// shapedTunnel limits the rate of the data sent and received through the
+// tunnel.
+type shapedTunnel struct {
+	tunnel
+	ctx    context.Context
+	shaper *shaper
+	bulk   bool
 }
 
-func streamBidirectional(stream net.Conn, tnl tunnel, cancel func()) {
+// Send splits buffers into chunks no larger than the limiter's burst, so
+// that the peer keeps receiving data while the upload is rate limited, and
+// doesn't mistake the tunnel for being dead.
+func (t shapedTunnel) Send(msg *node.TunnelMsg) error {
+	buf := msg.GetBuf()
+	if len(buf) == 0 {
+		// Don't rate limit keepalives and control messages.
+		return t.tunnel.Send(msg)
+	}
+
+	for len(buf) > 0 {
+		chunk := buf
+		if len(chunk) > limiterBurst {
+			chunk = chunk[:limiterBurst]
+		}
+
+		if err := t.shaper.wait(t.ctx, t.shaper.upload, t.bulk, len(chunk)); err != nil {
+			return err
+		}
+		if err := t.tunnel.Send(&node.TunnelMsg{Msg: &node.TunnelMsg_Buf{Buf: chunk}}); err != nil {
+			return err
+		}
+		buf = buf[len(chunk):]
+	}
+	return nil
+}
+
+// Recv waits after receiving data so that the flow control in gRPC slows down
+// the sender.
+func (t shapedTunnel) Recv() (*node.TunnelMsg, error) {
+	msg, err := t.tunnel.Recv()
+	if err != nil || len(msg.GetBuf()) == 0 {
+		return msg, err
+	}
+
+	if err := t.shaper.wait(t.ctx, t.shaper.download, t.bulk, len(msg.GetBuf())); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// streamBidirectional copies data between the stream and the tunnel until
+// either end closes, or until `expired` fires. It returns whether the
+// connection was closed because one end stopped responding.
+func streamBidirectional(stream net.Conn, tnl tunnel, cancel func(), expired <-chan time.Time) bool {
 	var wg sync.WaitGroup
 	wg.Add(2)
 
 	streamDone := make(chan struct{})
+	var recvErr, sendErr error
+	var peer liveness
 
 	go func() {
-		tunnelToStream(stream, tnl)
+		recvErr = tunnelToStream(stream, tnl, &peer)
 		close(streamDone)
 		wg.Done()
 
 	}()
 
 	go func() {
-		streamToTunnel(stream, tnl, streamDone)
+		sendErr = streamToTunnel(stream, tnl, streamDone)
 		cancel()
 		wg.Done()
 	}()
 
-	wg.Wait()
-	stream.Close()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	checkLiveness := time.NewTicker(keepAlivePeriod)
+	defer checkLiveness.Stop()
+	for {
+		select {
+		case <-done:
+			stream.Close()
+			return isDeadConnErr(recvErr) || isDeadConnErr(sendErr)
+		case now := <-checkLiveness.C:
+			if !peer.peerDead(now) {
+				continue
+			}
+
+			// Don't wait for the goroutines to exit since they may be
+			// blocked on the dead tunnel. Closing the stream and canceling
+			// the tunnel unblocks them.
+			log.Debug("Closing half-open tunnel")
+			stream.Close()
+			cancel()
+			return true
+		case <-expired:
+			// As above, the goroutines are unblocked by closing the stream
+			// and the tunnel.
+			log.Debug("Closing expired tunnel")
+			stream.Close()
+			cancel()
+			return false
+		}
+	}
 }
 
 type readResult struct {
@@ -130,7 +316,10 @@ func asyncReadStream(ctx context.Context, stream io.Reader,
 	}
 }
 
-func streamToTunnel(stream io.Reader, tnl tunnel, done <-chan struct{}) {
+// streamToTunnel sends the data read from the stream through the tunnel, and
+// sends keepalives while the stream is idle. It returns the error that caused
+// it to stop, or nil if the stream or tunnel was closed.
+func streamToTunnel(stream io.Reader, tnl tunnel, done <-chan struct{}) error {
 	var buf [1024 * 1024]byte
 
 	bufChan := make(chan []byte)
@@ -141,24 +330,48 @@ func streamToTunnel(stream io.Reader, tnl tunnel, done <-chan struct{}) {
 	resultChan := make(chan readResult)
 	go asyncReadStream(asyncReadCtx, stream, bufChan, resultChan)
 
+	keepAlive := time.NewTicker(keepAlivePeriod)
+	defer keepAlive.Stop()
+	lastSend := time.Now()
+
+	var readErr error
+	reading := false
 loop:
 	for {
 		var result readResult
 
-		bufChan <- buf[:]
+		if !reading {
+			bufChan <- buf[:]
+			reading = true
+		}
 		select {
 		case <-done:
 			break loop
+		case <-keepAlive.C:
+			if time.Since(lastSend) < keepAlivePeriod {
+				continue
+			}
+
+			msg := node.TunnelMsg{
+				Msg: &node.TunnelMsg_Buf{Buf: []byte{}}}
+			if err := tnl.Send(&msg); err != nil {
+				log.WithError(err).Debug("tunnel keepalive error")
+				return err
+			}
+			lastSend = time.Now()
+			continue
 		case result = <-resultChan:
+			reading = false
 		}
 
-		if result.buf != nil {
+		if len(result.buf) != 0 {
 			msg := node.TunnelMsg{
 				Msg: &node.TunnelMsg_Buf{Buf: result.buf}}
 			if err := tnl.Send(&msg); err != nil {
 				log.WithError(err).Debug("tunnel send error")
-				return
+				return err
 			}
+			lastSend = time.Now()
 		}
 
 		err := result.err
@@ -166,6 +379,7 @@ loop:
 			break loop
 		} else if err != nil {
 			log.WithError(err).Debug("failed to read from local")
+			readErr = err
 			break loop
 		}
 	}
@@ -176,23 +390,29 @@ loop:
 		status.Code(err) != codes.Canceled {
 		log.WithError(err).Debug("failed to send eof")
 	}
+	return readErr
 }
 
-func tunnelToStream(stream io.ReadWriter, tnl tunnel) {
+// tunnelToStream writes the data received from the tunnel to the stream. It
+// returns the error that caused it to stop, or nil if the stream or tunnel
+// was closed.
+func tunnelToStream(stream io.ReadWriter, tnl tunnel, peer *liveness) error {
 	for {
+		peer.startRecv()
 		msg, err := tnl.Recv()
 		switch {
 		case err == io.EOF:
-			return
+			return nil
 		case status.Code(err) == codes.Canceled:
-			return
+			return nil
 		case err != nil:
 			log.WithError(err).Debug("failed to receive on tunnel")
-			return
+			return err
 		}
 
 		if eof := msg.GetEof(); eof != nil {
-			return
+			peer.finishRecv(false)
+			return nil
 		}
 
 		buf := msg.GetBuf()
@@ -202,11 +422,16 @@ func tunnelToStream(stream io.ReadWriter, tnl tunnel) {
 			// wrong type of msg. Panicking seems too much though,
 			// so just error and close the connection.
 			log.Error("tunnel protocol error. expected buffer")
-			return
+			return nil
+		}
+
+		peer.finishRecv(len(buf) == 0)
+		if len(buf) == 0 {
+			continue
 		}
 
 		if _, err := stream.Write(buf); err != nil {
-			return
+			return err
 		}
 	}
 }
@@ -0,0 +1,324 @@
+package tunnel
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/kelda/blimp/pkg/cfgdir"
+	"github.com/kelda/blimp/pkg/proto/node"
+)
+
+// fakeTunnel is an in-memory tunnel. Messages written to `recv` are received
+// from the tunnel, and closing `recv` closes the tunnel.
+type fakeTunnel struct {
+	grpc.ServerStream
+
+	recv chan *node.TunnelMsg
+
+	sentLock sync.Mutex
+	sent     []*node.TunnelMsg
+}
+
+func newFakeTunnel() *fakeTunnel {
+	return &fakeTunnel{recv: make(chan *node.TunnelMsg)}
+}
+
+func (t *fakeTunnel) Send(msg *node.TunnelMsg) error {
+	t.sentLock.Lock()
+	defer t.sentLock.Unlock()
+	t.sent = append(t.sent, msg)
+	return nil
+}
+
+func (t *fakeTunnel) Recv() (*node.TunnelMsg, error) {
+	msg, ok := <-t.recv
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func (t *fakeTunnel) sentMsgs() []*node.TunnelMsg {
+	t.sentLock.Lock()
+	defer t.sentLock.Unlock()
+	return append([]*node.TunnelMsg(nil), t.sent...)
+}
+
+func TestServerStreamUntil(t *testing.T) {
+	tnl := newFakeTunnel()
+	defer close(tnl.recv)
+	local, remote := net.Pipe()
+	defer local.Close()
+
+	done := make(chan struct{})
+	go func() {
+		ServerStreamUntil(tnl, remote, time.Now().Add(500*time.Millisecond))
+		close(done)
+	}()
+
+	// Data flows normally until the deadline.
+	tnl.recv <- &node.TunnelMsg{Msg: &node.TunnelMsg_Buf{Buf: []byte("hello")}}
+	buf := make([]byte, 5)
+	_, err := io.ReadFull(local, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+
+	// The tunnel is closed once the deadline passes, even though neither end
+	// closed it.
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tunnel wasn't closed after it expired")
+	}
+
+	_, err = local.Read(buf)
+	assert.Error(t, err)
+}
+
+func TestServerStreamUntilPassedDeadline(t *testing.T) {
+	tnl := newFakeTunnel()
+	defer close(tnl.recv)
+	local, remote := net.Pipe()
+	defer local.Close()
+
+	done := make(chan struct{})
+	go func() {
+		ServerStreamUntil(tnl, remote, time.Now().Add(-time.Minute))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tunnel wasn't closed after it expired")
+	}
+}
+
+// shortenKeepAlives speeds up keepalives and the detection of dead peers. It
+// returns a function that restores the defaults.
+func shortenKeepAlives(period, dead time.Duration) func() {
+	origPeriod, origDeadAfter := keepAlivePeriod, deadAfter
+	keepAlivePeriod, deadAfter = period, dead
+	return func() {
+		keepAlivePeriod, deadAfter = origPeriod, origDeadAfter
+	}
+}
+
+func keepAliveMsg() *node.TunnelMsg {
+	return &node.TunnelMsg{Msg: &node.TunnelMsg_Buf{Buf: []byte{}}}
+}
+
+func isKeepAlive(msg *node.TunnelMsg) bool {
+	buf := msg.GetBuf()
+	return buf != nil && len(buf) == 0
+}
+
+func TestStreamToTunnelKeepAlive(t *testing.T) {
+	defer shortenKeepAlives(10*time.Millisecond, time.Minute)()
+
+	tnl := newFakeTunnel()
+	local, remote := net.Pipe()
+	errChan := make(chan error)
+	go func() {
+		errChan <- streamToTunnel(remote, tnl, nil)
+	}()
+
+	// Keepalives are sent while the connection is idle.
+	countKeepAlives := func() (count int) {
+		for _, msg := range tnl.sentMsgs() {
+			if isKeepAlive(msg) {
+				count++
+			}
+		}
+		return count
+	}
+	assert.Eventually(t, func() bool { return countKeepAlives() >= 2 },
+		5*time.Second, 10*time.Millisecond)
+
+	_, err := local.Write([]byte("hello"))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		for _, msg := range tnl.sentMsgs() {
+			if string(msg.GetBuf()) == "hello" {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Closing the local connection sends an EOF through the tunnel.
+	require.NoError(t, local.Close())
+	select {
+	case err := <-errChan:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("streamToTunnel didn't return after the connection closed")
+	}
+	sent := tnl.sentMsgs()
+	assert.NotNil(t, sent[len(sent)-1].GetEof())
+}
+
+func TestLiveness(t *testing.T) {
+	var peer liveness
+	peer.startRecv()
+	now := time.Now()
+
+	// Peers that don't send keepalives are never considered dead, since
+	// they may predate keepalives.
+	assert.False(t, peer.peerDead(now.Add(2*deadAfter)))
+
+	peer.finishRecv(true)
+	assert.False(t, peer.peerDead(now.Add(2*deadAfter)), "not waiting for the peer")
+
+	peer.startRecv()
+	assert.False(t, peer.peerDead(now.Add(deadAfter/2)))
+	assert.True(t, peer.peerDead(now.Add(2*deadAfter)))
+
+	// Data from the peer also shows that it's alive.
+	peer.finishRecv(false)
+	assert.False(t, peer.peerDead(now.Add(2*deadAfter)))
+}
+
+func TestHalfOpenTunnel(t *testing.T) {
+	defer shortenKeepAlives(10*time.Millisecond, 50*time.Millisecond)()
+
+	tnl := newFakeTunnel()
+	var closeOnce sync.Once
+	cancel := func() {
+		closeOnce.Do(func() { close(tnl.recv) })
+	}
+	local, remote := net.Pipe()
+	defer local.Close()
+
+	deadChan := make(chan bool)
+	go func() {
+		deadChan <- streamBidirectional(remote, tnl, cancel, nil)
+	}()
+
+	// The peer sends a keepalive, and then stops responding.
+	tnl.recv <- keepAliveMsg()
+	select {
+	case dead := <-deadChan:
+		assert.True(t, dead)
+	case <-time.After(5 * time.Second):
+		t.Fatal("half-open tunnel wasn't closed")
+	}
+
+	_, err := local.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
+
+func TestIdleTunnelWithoutKeepAlives(t *testing.T) {
+	defer shortenKeepAlives(10*time.Millisecond, 50*time.Millisecond)()
+
+	tnl := newFakeTunnel()
+	var closeOnce sync.Once
+	cancel := func() {
+		closeOnce.Do(func() { close(tnl.recv) })
+	}
+	local, remote := net.Pipe()
+
+	deadChan := make(chan bool)
+	go func() {
+		deadChan <- streamBidirectional(remote, tnl, cancel, nil)
+	}()
+
+	tnl.recv <- &node.TunnelMsg{Msg: &node.TunnelMsg_Buf{Buf: []byte("hello")}}
+	buf := make([]byte, 5)
+	_, err := io.ReadFull(local, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+
+	// Peers that have never sent a keepalive may not support them, so the
+	// tunnel isn't closed even though the peer has been idle for longer
+	// than deadAfter.
+	select {
+	case <-deadChan:
+		t.Fatal("tunnel to an idle peer was closed")
+	case <-time.After(5 * deadAfter):
+	}
+
+	require.NoError(t, local.Close())
+	select {
+	case dead := <-deadChan:
+		assert.False(t, dead)
+	case <-time.After(5 * time.Second):
+		t.Fatal("tunnel wasn't closed after the connection closed")
+	}
+}
+
+func TestStatsConn(t *testing.T) {
+	stats := &tunnelStats{service: "web", servicePort: 80, localAddr: "127.0.0.1:8080"}
+	assert.Equal(t, Stats{Service: "web", ServicePort: 80, LocalAddr: "127.0.0.1:8080"}, stats.snapshot())
+
+	local, remote := net.Pipe()
+	defer local.Close()
+	conn := statsConn{Conn: remote, stats: stats}
+
+	go func() {
+		//nolint:errcheck // The test fails when the read doesn't match.
+		local.Write([]byte("request"))
+	}()
+	buf := make([]byte, 7)
+	_, err := io.ReadFull(conn, buf)
+	require.NoError(t, err)
+
+	go func() {
+		//nolint:errcheck // The test fails when the write doesn't match.
+		io.ReadFull(local, make([]byte, 8))
+	}()
+	_, err = conn.Write([]byte("response"))
+	require.NoError(t, err)
+
+	// Traffic is from the perspective of the local client, so data read
+	// from the local connection is sent through the tunnel.
+	snapshot := stats.snapshot()
+	assert.Equal(t, int64(7), snapshot.BytesSent)
+	assert.Equal(t, int64(8), snapshot.BytesReceived)
+	assert.WithinDuration(t, time.Now(), snapshot.LastActivity, time.Minute)
+}
+
+func TestReadStats(t *testing.T) {
+	configDir, err := ioutil.TempDir("", "blimp-tunnel-stats")
+	require.NoError(t, err)
+	defer os.RemoveAll(configDir)
+
+	origConfigDir := cfgdir.ConfigDir
+	cfgdir.ConfigDir = configDir
+	defer func() { cfgdir.ConfigDir = origConfigDir }()
+
+	stats, err := ReadStats()
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+
+	web := Stats{Service: "web", ServicePort: 80, LocalAddr: "127.0.0.1:8080", TotalConnections: 3}
+	db := Stats{Service: "db", ServicePort: 5432, LocalAddr: "127.0.0.1:5432", DeadConnections: 1}
+	require.NoError(t, writeStats(filepath.Join(statsDir(), "1.json"), []Stats{web}))
+	require.NoError(t, writeStats(filepath.Join(statsDir(), "2.json"), []Stats{db}))
+
+	// Malformed files and other files are ignored.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(statsDir(), "3.json"), []byte("{"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(statsDir(), "4.json.tmp"), []byte("{"), 0644))
+
+	// Stats from processes that exited are removed.
+	stalePath := filepath.Join(statsDir(), "5.json")
+	require.NoError(t, writeStats(stalePath, []Stats{{Service: "stale"}}))
+	staleTime := time.Now().Add(-2 * statsStaleAfter)
+	require.NoError(t, os.Chtimes(stalePath, staleTime, staleTime))
+
+	stats, err = ReadStats()
+	assert.NoError(t, err)
+	assert.Equal(t, []Stats{db, web}, stats)
+	_, err = os.Stat(stalePath)
+	assert.True(t, os.IsNotExist(err))
+}
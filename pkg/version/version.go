@@ -11,6 +11,7 @@ var (
 	ReservationImage    = ""
 	SyncthingImage      = ""
 	NodeControllerImage = ""
+	ChaosImage          = ""
 
 	BuildkitdImage = "moby/buildkit:master-rootless"
 )
@@ -27,4 +28,5 @@ func init() {
 	ReservationImage = repo + "/sandbox-reservation:" + Version
 	SyncthingImage = repo + "/sandbox-syncthing:" + Version
 	NodeControllerImage = repo + "/blimp-node-controller:" + Version
+	ChaosImage = repo + "/blimp-chaos:" + Version
 }
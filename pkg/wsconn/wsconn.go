@@ -0,0 +1,106 @@
+// Package wsconn adapts WebSocket connections into net.Conns, so that
+// streams such as gRPC connections can be tunneled over WebSockets. This
+// allows clients behind firewalls that only allow HTTP traffic to connect to
+// Blimp.
+package wsconn
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Conn implements net.Conn by sending data as binary WebSocket messages.
+// Like the underlying WebSocket connection, it supports one concurrent
+// reader, but multiple concurrent writers.
+type Conn struct {
+	ws *websocket.Conn
+
+	// reader is the message currently being read.
+	reader io.Reader
+
+	writeLock sync.Mutex
+}
+
+func New(ws *websocket.Conn) *Conn {
+	return &Conn{ws: ws}
+}
+
+func (c *Conn) Read(b []byte) (int, error) {
+	for {
+		if c.reader == nil {
+			msgType, reader, err := c.ws.NextReader()
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+
+			if msgType != websocket.BinaryMessage {
+				continue
+			}
+			c.reader = reader
+		}
+
+		n, err := c.reader.Read(b)
+		if err == io.EOF {
+			// Move on to the next message.
+			c.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (c *Conn) Write(b []byte) (int, error) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	if err := c.ws.WriteMessage(websocket.BinaryMessage, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close notifies the other end that the connection is closing, and closes the
+// underlying connection.
+func (c *Conn) Close() error {
+	c.writeLock.Lock()
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	// The close message is best effort since the connection may already be
+	// broken.
+	_ = c.ws.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+	c.writeLock.Unlock()
+
+	return c.ws.Close()
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return c.ws.LocalAddr()
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.ws.RemoteAddr()
+}
+
+func (c *Conn) SetDeadline(t time.Time) error {
+	if err := c.ws.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.ws.SetWriteDeadline(t)
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.ws.SetReadDeadline(t)
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.ws.SetWriteDeadline(t)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+type githubClient struct {
+	apiURL        string
+	token         string
+	webhookSecret string
+}
+
+type githubPullRequestEvent struct {
+	Action     string `json:"action"`
+	Number     uint32 `json:"number"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+	} `json:"repository"`
+}
+
+func (b *bot) handleGitHub(w http.ResponseWriter, r *http.Request) {
+	if b.github.webhookSecret == "" {
+		http.Error(w, "GitHub webhooks aren't configured", http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !validGitHubSignature(b.github.webhookSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, ok, err := parseGitHubEvent(r.Header.Get("X-GitHub-Event"), body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event.comment = func(body string) error {
+		return b.github.comment(event.pr.Repo, event.pr.Number, body)
+	}
+	go b.handle(event)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// parseGitHubEvent parses a webhook payload. It returns false if the event
+// doesn't affect previews.
+func parseGitHubEvent(eventType string, body []byte) (pullRequestEvent, bool, error) {
+	if eventType != "pull_request" {
+		return pullRequestEvent{}, false, nil
+	}
+
+	var payload githubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return pullRequestEvent{}, false, errors.WithContext("parse payload", err)
+	}
+
+	var action eventAction
+	switch payload.Action {
+	case "opened", "reopened":
+		action = actionOpened
+	case "synchronize":
+		action = actionUpdated
+	case "closed":
+		action = actionClosed
+	default:
+		return pullRequestEvent{}, false, nil
+	}
+
+	return pullRequestEvent{
+		pr: &cluster.PullRequest{
+			Repo:   payload.Repository.FullName,
+			Number: payload.Number,
+		},
+		action:   action,
+		cloneURL: payload.Repository.CloneURL,
+		ref:      fmt.Sprintf("refs/pull/%d/head", payload.Number),
+	}, true, nil
+}
+
+// validGitHubSignature checks the signature that GitHub computes over the
+// payload with the webhook's secret.
+func validGitHubSignature(secret string, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (c githubClient) comment(repo string, number uint32, body string) error {
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", c.apiURL, repo, number)
+	return postJSON(url,
+		map[string]string{"Authorization": "token " + c.token},
+		map[string]string{"body": body})
+}
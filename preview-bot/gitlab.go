@@ -0,0 +1,113 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+type gitlabClient struct {
+	apiURL        string
+	token         string
+	webhookSecret string
+}
+
+type gitlabMergeRequestEvent struct {
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		GitHTTPURL        string `json:"git_http_url"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    uint32 `json:"iid"`
+		Action string `json:"action"`
+
+		// OldRev is only set for updates that push new commits.
+		OldRev string `json:"oldrev"`
+	} `json:"object_attributes"`
+}
+
+func (b *bot) handleGitLab(w http.ResponseWriter, r *http.Request) {
+	if b.gitlab.webhookSecret == "" {
+		http.Error(w, "GitLab webhooks aren't configured", http.StatusNotFound)
+		return
+	}
+
+	token := r.Header.Get("X-Gitlab-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(b.gitlab.webhookSecret)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	event, ok, err := parseGitLabEvent(r.Header.Get("X-Gitlab-Event"), body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event.comment = func(body string) error {
+		return b.gitlab.comment(event.pr.Repo, event.pr.Number, body)
+	}
+	go b.handle(event)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// parseGitLabEvent parses a webhook payload. It returns false if the event
+// doesn't affect previews.
+func parseGitLabEvent(eventType string, body []byte) (pullRequestEvent, bool, error) {
+	if eventType != "Merge Request Hook" {
+		return pullRequestEvent{}, false, nil
+	}
+
+	var payload gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return pullRequestEvent{}, false, errors.WithContext("parse payload", err)
+	}
+
+	var action eventAction
+	attrs := payload.ObjectAttributes
+	switch {
+	case attrs.Action == "open" || attrs.Action == "reopen":
+		action = actionOpened
+	case attrs.Action == "update" && attrs.OldRev != "":
+		action = actionUpdated
+	case attrs.Action == "close" || attrs.Action == "merge":
+		action = actionClosed
+	default:
+		return pullRequestEvent{}, false, nil
+	}
+
+	return pullRequestEvent{
+		pr: &cluster.PullRequest{
+			Repo:   payload.Project.PathWithNamespace,
+			Number: attrs.IID,
+		},
+		action:   action,
+		cloneURL: payload.Project.GitHTTPURL,
+		ref:      fmt.Sprintf("refs/merge-requests/%d/head", attrs.IID),
+	}, true, nil
+}
+
+func (c gitlabClient) comment(project string, iid uint32, body string) error {
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/notes",
+		c.apiURL, url.PathEscape(project), iid)
+	return postJSON(endpoint,
+		map[string]string{"PRIVATE-TOKEN": c.token},
+		map[string]string{"body": body})
+}
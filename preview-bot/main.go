@@ -0,0 +1,212 @@
+// preview-bot creates a Blimp sandbox for each pull request, and comments on
+// the pull request with a link to it. The sandbox is updated when commits are
+// pushed, and deleted when the pull request is merged or closed.
+//
+// GitHub and GitLab send events to the bot through webhooks at /github and
+// /gitlab respectively.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/cli/util"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/proto/auth"
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+// maxPayloadSize is the largest webhook payload that the bot will read.
+const maxPayloadSize = 10 * 1024 * 1024
+
+type bot struct {
+	manager     cluster.ManagerClient
+	clusterAuth string
+
+	// The Compose files to boot, and the port of the service to link to.
+	composeFiles []string
+	service      string
+	port         uint32
+
+	github githubClient
+	gitlab gitlabClient
+}
+
+type eventAction int
+
+const (
+	actionOpened eventAction = iota
+	actionUpdated
+	actionClosed
+)
+
+// pullRequestEvent is a change to a pull request that affects its preview.
+type pullRequestEvent struct {
+	pr       *cluster.PullRequest
+	action   eventAction
+	cloneURL string
+	ref      string
+
+	// comment posts a comment on the pull request.
+	comment func(body string) error
+}
+
+func main() {
+	b, err := newBot()
+	if err != nil {
+		log.WithError(err).Fatal("Failed to configure bot")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/github", b.handleGitHub)
+	mux.HandleFunc("/gitlab", b.handleGitLab)
+
+	addr := ":8080"
+	if port, ok := os.LookupEnv("PORT"); ok {
+		addr = ":" + port
+	}
+	log.WithField("address", addr).Info("Listening for webhooks")
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.WithError(err).Fatal("Failed to serve webhooks")
+	}
+}
+
+func newBot() (*bot, error) {
+	cert, err := ioutil.ReadFile(os.Getenv("MANAGER_CERT_PATH"))
+	if err != nil {
+		return nil, errors.WithContext("read manager certificate", err)
+	}
+
+	conn, err := util.Dial(os.Getenv("MANAGER_HOST"), string(cert), "localhost")
+	if err != nil {
+		return nil, errors.WithContext("dial manager", err)
+	}
+
+	b := &bot{
+		manager:     cluster.NewManagerClient(conn),
+		clusterAuth: os.Getenv("BLIMP_CLUSTER_SECRET"),
+		service:     os.Getenv("PREVIEW_SERVICE"),
+		github: githubClient{
+			apiURL:        "https://api.github.com",
+			token:         os.Getenv("GITHUB_TOKEN"),
+			webhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		},
+		gitlab: gitlabClient{
+			apiURL:        "https://gitlab.com",
+			token:         os.Getenv("GITLAB_TOKEN"),
+			webhookSecret: os.Getenv("GITLAB_WEBHOOK_SECRET"),
+		},
+	}
+
+	if files := os.Getenv("PREVIEW_COMPOSE_FILES"); files != "" {
+		b.composeFiles = strings.Split(files, ",")
+	}
+
+	if b.service != "" {
+		port, err := strconv.ParseUint(os.Getenv("PREVIEW_PORT"), 10, 16)
+		if err != nil {
+			return nil, errors.WithContext("parse PREVIEW_PORT", err)
+		}
+		b.port = uint32(port)
+	}
+
+	if url, ok := os.LookupEnv("GITLAB_URL"); ok {
+		b.gitlab.apiURL = strings.TrimSuffix(url, "/")
+	}
+	return b, nil
+}
+
+// handle creates, updates, or deletes the preview for a pull request. It's
+// run in the background since booting sandboxes is slower than webhooks are
+// allowed to take.
+func (b *bot) handle(event pullRequestEvent) {
+	logger := log.WithField("repo", event.pr.Repo).WithField("number", event.pr.Number)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	blimpAuth := &auth.BlimpAuth{ClusterAuth: b.clusterAuth}
+	if event.action == actionClosed {
+		_, err := b.manager.DeletePreview(ctx, &cluster.DeletePreviewRequest{
+			Auth:        blimpAuth,
+			PullRequest: event.pr,
+		})
+		if err != nil {
+			logger.WithError(err).Error("Failed to delete preview")
+		}
+		return
+	}
+
+	resp, err := b.manager.CreatePreview(ctx, &cluster.CreatePreviewRequest{
+		Auth:         blimpAuth,
+		PullRequest:  event.pr,
+		CloneUrl:     event.cloneURL,
+		Ref:          event.ref,
+		ComposeFiles: b.composeFiles,
+		Service:      b.service,
+		Port:         b.port,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to create preview")
+		b.comment(logger, event, fmt.Sprintf("Failed to create a Blimp preview:\n```\n%s\n```", err))
+		return
+	}
+
+	// Only comment when the preview is first created, since the link doesn't
+	// change when the pull request is updated.
+	if event.action != actionOpened {
+		return
+	}
+
+	msg := fmt.Sprintf("A Blimp preview of this pull request is booting in sandbox `%s`.", resp.Namespace)
+	if resp.Link != "" {
+		msg = fmt.Sprintf("A Blimp preview of this pull request is booting at %s", resp.Link)
+	}
+	msg += "\n\nIt's updated when commits are pushed, and deleted when the pull request is closed."
+	b.comment(logger, event, msg)
+}
+
+func (b *bot) comment(logger *log.Entry, event pullRequestEvent, body string) {
+	if err := event.comment(body); err != nil {
+		logger.WithError(err).Warn("Failed to comment on pull request")
+	}
+}
+
+// postJSON sends a POST request with the given JSON body.
+func postJSON(url string, headers map[string]string, body interface{}) error {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return errors.WithContext("marshal", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyJSON))
+	if err != nil {
+		return errors.WithContext("make request", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return errors.New("unexpected status %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
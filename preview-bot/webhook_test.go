@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelda/blimp/pkg/proto/cluster"
+)
+
+func TestParseGitHubEvent(t *testing.T) {
+	payload := func(action string) []byte {
+		return []byte(`{"action": "` + action + `", "number": 42, "repository": {` +
+			`"full_name": "kelda/blimp", "clone_url": "https://github.com/kelda/blimp.git"}}`)
+	}
+
+	tests := []struct {
+		name      string
+		eventType string
+		body      []byte
+		expOK     bool
+		expAction eventAction
+	}{
+		{"opened", "pull_request", payload("opened"), true, actionOpened},
+		{"reopened", "pull_request", payload("reopened"), true, actionOpened},
+		{"pushed", "pull_request", payload("synchronize"), true, actionUpdated},
+		{"closed", "pull_request", payload("closed"), true, actionClosed},
+		{"labeled", "pull_request", payload("labeled"), false, 0},
+		{"other event", "push", payload("opened"), false, 0},
+	}
+
+	for _, test := range tests {
+		event, ok, err := parseGitHubEvent(test.eventType, test.body)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expOK, ok, test.name)
+		if !ok {
+			continue
+		}
+
+		assert.Equal(t, test.expAction, event.action, test.name)
+		assert.Equal(t, &cluster.PullRequest{Repo: "kelda/blimp", Number: 42}, event.pr, test.name)
+		assert.Equal(t, "https://github.com/kelda/blimp.git", event.cloneURL, test.name)
+		assert.Equal(t, "refs/pull/42/head", event.ref, test.name)
+	}
+}
+
+func TestParseGitLabEvent(t *testing.T) {
+	payload := func(action, oldRev string) []byte {
+		return []byte(`{"project": {"path_with_namespace": "kelda/blimp", ` +
+			`"git_http_url": "https://gitlab.com/kelda/blimp.git"}, ` +
+			`"object_attributes": {"iid": 7, "action": "` + action + `", "oldrev": "` + oldRev + `"}}`)
+	}
+
+	tests := []struct {
+		name      string
+		body      []byte
+		expOK     bool
+		expAction eventAction
+	}{
+		{"opened", payload("open", ""), true, actionOpened},
+		{"pushed", payload("update", "abc123"), true, actionUpdated},
+		{"edited", payload("update", ""), false, 0},
+		{"merged", payload("merge", ""), true, actionClosed},
+		{"closed", payload("close", ""), true, actionClosed},
+	}
+
+	for _, test := range tests {
+		event, ok, err := parseGitLabEvent("Merge Request Hook", test.body)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expOK, ok, test.name)
+		if !ok {
+			continue
+		}
+
+		assert.Equal(t, test.expAction, event.action, test.name)
+		assert.Equal(t, &cluster.PullRequest{Repo: "kelda/blimp", Number: 7}, event.pr, test.name)
+		assert.Equal(t, "refs/merge-requests/7/head", event.ref, test.name)
+	}
+}
+
+func TestValidGitHubSignature(t *testing.T) {
+	// Computed with `printf body | openssl dgst -sha256 -hmac secret`.
+	signature := "sha256=" + "dc46983557fea127b43af721467eb9b3fde2338fe3e14f51952aa8478c13d355"
+	assert.True(t, validGitHubSignature("secret", []byte("body"), signature))
+	assert.False(t, validGitHubSignature("wrong", []byte("body"), signature))
+	assert.False(t, validGitHubSignature("secret", []byte("body"), ""))
+}
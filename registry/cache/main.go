@@ -0,0 +1,479 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kelda/blimp/pkg/auth"
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/imagecache"
+	"github.com/kelda/blimp/pkg/kube"
+)
+
+const (
+	// pullSecretName is the name of the secret in each sandbox's namespace
+	// that contains the user's registry credentials. It's created by the
+	// manager when the sandbox is created.
+	pullSecretName = "registry-auth"
+
+	// authorizationTTL is how long a namespace may pull the blobs referenced
+	// by a manifest after pulling the manifest.
+	authorizationTTL = time.Hour
+)
+
+var pathPattern = regexp.MustCompile(`^/v2/(.+)/(manifests|blobs)/([^/]+)$`)
+
+// server is a read-only pull-through registry that caches images across all
+// sandboxes.
+//
+// Users can only pull images that they can access upstream. Every manifest
+// request is checked against the upstream registry with the user's own
+// credentials, and a user may only pull the blobs referenced by manifests
+// that they've pulled. Blobs are content-addressed, so once a user has
+// proven that they can access an image, it's safe to serve them cached blobs
+// that were fetched by another user.
+type server struct {
+	kubeClient kubernetes.Interface
+	cacheDir   string
+
+	// authorizedBlobs maps namespaces to the blobs that they may pull, and
+	// when they were authorized to pull them.
+	authorizedBlobs     map[string]map[v1.Hash]time.Time
+	authorizedBlobsLock sync.Mutex
+
+	// fetches deduplicates concurrent downloads of the same blob, which is
+	// common when multiple sandboxes boot the same image.
+	fetches singleflight.Group
+}
+
+func main() {
+	certPath := flag.String("tls-cert", "", "The path to the PEM-encoded certificate used for serving the registry")
+	keyPath := flag.String("tls-key", "", "The path to the PEM-encoded private key used for serving the registry")
+	cacheDir := flag.String("cache-dir", "/var/lib/blimp-registry-cache", "The directory to cache images in")
+	maxAge := flag.Duration("max-age", 7*24*time.Hour, "How long to keep cached images that haven't been pulled")
+	flag.Parse()
+
+	if *certPath == "" || *keyPath == "" {
+		log.Fatal("The TLS cert and key are required")
+	}
+
+	kubeClient, _, err := kube.GetClient()
+	if err != nil {
+		log.WithError(err).Fatal("Failed to get kubernetes client")
+	}
+
+	if err := os.MkdirAll(filepath.Join(*cacheDir, "tmp"), 0755); err != nil {
+		log.WithError(err).Fatal("Failed to create cache directory")
+	}
+
+	s := &server{
+		kubeClient:      kubeClient,
+		cacheDir:        *cacheDir,
+		authorizedBlobs: map[string]map[v1.Hash]time.Time{},
+	}
+	go s.runGarbageCollector(*maxAge)
+
+	httpServer := http.Server{
+		Addr:    ":443",
+		Handler: s,
+	}
+	if err := httpServer.ListenAndServeTLS(*certPath, *keyPath); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the image cache is read-only")
+		return
+	}
+
+	user, err := authenticate(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Blimp Image Cache"`)
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "authentication required")
+		return
+	}
+
+	if r.URL.Path == "/v2/" || r.URL.Path == "/v2" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	match := pathPattern.FindStringSubmatch(r.URL.Path)
+	if match == nil {
+		writeError(w, http.StatusNotFound, "UNSUPPORTED", "unknown endpoint")
+		return
+	}
+
+	repo, err := imagecache.UpstreamRepository(match[1])
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NAME_INVALID", err.Error())
+		return
+	}
+
+	logger := log.WithField("namespace", user.Namespace).WithField("repo", repo.Name())
+	upstreamAuth, err := s.getUpstreamAuth(user.Namespace, repo.Registry)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get upstream credentials")
+		writeError(w, http.StatusInternalServerError, "UNKNOWN", "failed to get registry credentials")
+		return
+	}
+
+	switch match[2] {
+	case "manifests":
+		err = s.serveManifest(w, r, user.Namespace, repo, match[3], upstreamAuth)
+	case "blobs":
+		err = s.serveBlob(w, r, user.Namespace, repo, match[3], upstreamAuth)
+	}
+	if err != nil {
+		logger.WithError(err).WithField("reference", match[3]).Info("Failed to serve request")
+		writeUpstreamError(w, err)
+	}
+}
+
+// authenticate parses the Blimp registry credentials that the kubelet sends
+// with each request.
+func authenticate(r *http.Request) (auth.User, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return auth.User{}, errors.New("missing credentials")
+	}
+
+	blimpAuth, err := auth.BlimpRegistryAuth{
+		Username: username,
+		Password: password,
+	}.ToBlimpAuth()
+	if err != nil {
+		return auth.User{}, errors.WithContext("parse regcred", err)
+	}
+	return auth.AuthorizeRequest(blimpAuth)
+}
+
+// getUpstreamAuth returns the credentials that the user deployed their
+// sandbox with for the given registry.
+func (s *server) getUpstreamAuth(namespace string, registry name.Registry) (authn.Authenticator, error) {
+	secret, err := s.kubeClient.CoreV1().Secrets(namespace).Get(pullSecretName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return authn.Anonymous, nil
+		}
+		return nil, errors.WithContext("get pull secret", err)
+	}
+
+	var dockerConfig struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &dockerConfig); err != nil {
+		return nil, errors.WithContext("parse pull secret", err)
+	}
+
+	for host, cred := range dockerConfig.Auths {
+		if cred.Username == "" && cred.Password == "" {
+			continue
+		}
+
+		// Docker Hub credentials are keyed by its legacy index URL, and
+		// hostnames sometimes include a scheme. See pushImage in the manager.
+		isDockerHub := host == authn.DefaultAuthKey && registry.Name() == name.DefaultRegistry
+		if isDockerHub || host == registry.Name() ||
+			host == fmt.Sprintf("%s://%s", registry.Scheme(), registry.Name()) {
+			return &authn.Basic{
+				Username: cred.Username,
+				Password: cred.Password,
+			}, nil
+		}
+	}
+	return authn.Anonymous, nil
+}
+
+func (s *server) serveManifest(w http.ResponseWriter, r *http.Request, namespace string,
+	repo name.Repository, reference string, upstreamAuth authn.Authenticator) error {
+
+	var ref name.Reference = repo.Tag(reference)
+	if strings.Contains(reference, ":") {
+		ref = repo.Digest(reference)
+	}
+
+	// Always check with the upstream registry, even if the manifest is
+	// cached, so that users can only pull images they have access to. HEAD
+	// requests don't count towards Docker Hub's rate limits.
+	desc, err := remote.Head(ref, remote.WithAuth(upstreamAuth))
+	if err != nil {
+		return errors.WithContext("check upstream manifest", err)
+	}
+
+	manifest, err := s.getManifest(repo, desc.Digest, upstreamAuth)
+	if err != nil {
+		return errors.WithContext("get manifest", err)
+	}
+	s.authorizeBlobs(namespace, manifest)
+
+	w.Header().Set("Content-Type", string(desc.MediaType))
+	w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(manifest)))
+	if r.Method == http.MethodGet {
+		if _, err := w.Write(manifest); err != nil {
+			log.WithError(err).Debug("Failed to write manifest")
+		}
+	}
+	return nil
+}
+
+func (s *server) getManifest(repo name.Repository, digest v1.Hash, upstreamAuth authn.Authenticator) ([]byte, error) {
+	path := s.cachePath("manifests", digest)
+	if manifest, err := ioutil.ReadFile(path); err == nil {
+		touch(path)
+		return manifest, nil
+	}
+
+	desc, err := remote.Get(repo.Digest(digest.String()), remote.WithAuth(upstreamAuth))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.writeVerified(path, digest, bytes.NewReader(desc.Manifest)); err != nil {
+		return nil, errors.WithContext("cache manifest", err)
+	}
+	return desc.Manifest, nil
+}
+
+// authorizeBlobs allows the namespace to pull the config and layers
+// referenced by the manifest. Manifest lists don't reference any blobs
+// directly. Instead, the kubelet pulls the platform-specific manifest, which
+// is authorized separately.
+func (s *server) authorizeBlobs(namespace string, manifestBytes []byte) {
+	manifest, err := v1.ParseManifest(bytes.NewReader(manifestBytes))
+	if err != nil {
+		log.WithError(err).WithField("namespace", namespace).Warn("Failed to parse manifest")
+		return
+	}
+
+	digests := []v1.Hash{manifest.Config.Digest}
+	for _, layer := range manifest.Layers {
+		digests = append(digests, layer.Digest)
+	}
+
+	s.authorizedBlobsLock.Lock()
+	defer s.authorizedBlobsLock.Unlock()
+
+	if _, ok := s.authorizedBlobs[namespace]; !ok {
+		s.authorizedBlobs[namespace] = map[v1.Hash]time.Time{}
+	}
+	for _, digest := range digests {
+		if digest.Hex != "" {
+			s.authorizedBlobs[namespace][digest] = time.Now()
+		}
+	}
+}
+
+func (s *server) isAuthorized(namespace string, digest v1.Hash) bool {
+	s.authorizedBlobsLock.Lock()
+	defer s.authorizedBlobsLock.Unlock()
+
+	authorizedAt, ok := s.authorizedBlobs[namespace][digest]
+	return ok && time.Since(authorizedAt) < authorizationTTL
+}
+
+func (s *server) serveBlob(w http.ResponseWriter, r *http.Request, namespace string,
+	repo name.Repository, digestStr string, upstreamAuth authn.Authenticator) error {
+
+	digest, err := v1.NewHash(digestStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", err.Error())
+		return nil
+	}
+
+	if !s.isAuthorized(namespace, digest) {
+		writeError(w, http.StatusForbidden, "DENIED", "blobs can only be pulled after pulling a manifest that references them")
+		return nil
+	}
+
+	path := s.cachePath("blobs", digest)
+	if _, err := os.Stat(path); err != nil {
+		_, err, _ := s.fetches.Do(digest.String(), func() (interface{}, error) {
+			return nil, s.fetchBlob(repo.Digest(digest.String()), path, upstreamAuth)
+		})
+		if err != nil {
+			return errors.WithContext("fetch blob", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.WithContext("open blob", err)
+	}
+	defer f.Close()
+	touch(path)
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", digest.String())
+	http.ServeContent(w, r, "", time.Time{}, f)
+	return nil
+}
+
+func (s *server) fetchBlob(ref name.Digest, path string, upstreamAuth authn.Authenticator) error {
+	layer, err := remote.Layer(ref, remote.WithAuth(upstreamAuth))
+	if err != nil {
+		return err
+	}
+
+	digest, err := layer.Digest()
+	if err != nil {
+		return errors.WithContext("get digest", err)
+	}
+
+	blob, err := layer.Compressed()
+	if err != nil {
+		return errors.WithContext("start download", err)
+	}
+	defer blob.Close()
+
+	return s.writeVerified(path, digest, blob)
+}
+
+// writeVerified writes the contents of r to path if it matches the expected
+// digest. The file is written atomically so that partial downloads are
+// never served.
+func (s *server) writeVerified(path string, digest v1.Hash, r io.Reader) error {
+	tmp, err := ioutil.TempFile(filepath.Join(s.cacheDir, "tmp"), "download")
+	if err != nil {
+		return errors.WithContext("create temp file", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hasher), r); err != nil {
+		tmp.Close()
+		return errors.WithContext("download", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.WithContext("close temp file", err)
+	}
+
+	if actual := hex.EncodeToString(hasher.Sum(nil)); actual != digest.Hex {
+		return errors.New("digest mismatch: expected %s, got sha256:%s", digest, actual)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.WithContext("create cache directory", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *server) cachePath(kind string, digest v1.Hash) string {
+	return filepath.Join(s.cacheDir, kind, digest.Algorithm, digest.Hex)
+}
+
+// runGarbageCollector periodically deletes cached images that haven't been
+// pulled recently, and forgets expired blob authorizations.
+func (s *server) runGarbageCollector(maxAge time.Duration) {
+	for range time.Tick(time.Hour) {
+		cutoff := time.Now().Add(-maxAge)
+		for _, kind := range []string{"manifests", "blobs"} {
+			err := filepath.Walk(filepath.Join(s.cacheDir, kind), func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() || info.ModTime().After(cutoff) {
+					return nil
+				}
+
+				if err := os.Remove(path); err != nil {
+					log.WithError(err).WithField("path", path).Warn("Failed to remove cached file")
+				}
+				return nil
+			})
+			if err != nil {
+				log.WithError(err).Warn("Failed to garbage collect cache")
+			}
+		}
+
+		s.authorizedBlobsLock.Lock()
+		for namespace, blobs := range s.authorizedBlobs {
+			for digest, authorizedAt := range blobs {
+				if time.Since(authorizedAt) > authorizationTTL {
+					delete(blobs, digest)
+				}
+			}
+			if len(blobs) == 0 {
+				delete(s.authorizedBlobs, namespace)
+			}
+		}
+		s.authorizedBlobsLock.Unlock()
+	}
+}
+
+// touch marks the file as recently used so that it isn't garbage collected.
+func touch(path string) {
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		log.WithError(err).WithField("path", path).Debug("Failed to update access time")
+	}
+}
+
+// writeUpstreamError converts errors from the upstream registry into the
+// registry error format. Authorization errors are reported as denials rather
+// than 401s so that the kubelet doesn't retry with the same credentials.
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	if terr, ok := errors.RootCause(err).(*transport.Error); ok && terr.StatusCode != 0 {
+		status = terr.StatusCode
+	}
+
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		writeError(w, http.StatusForbidden, "DENIED",
+			"access to the upstream image was denied. Make sure you're logged in with `docker login`")
+	case http.StatusNotFound:
+		writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", "image not found upstream")
+	case http.StatusTooManyRequests:
+		writeError(w, http.StatusTooManyRequests, "TOOMANYREQUESTS", err.Error())
+	default:
+		writeError(w, http.StatusBadGateway, "UNKNOWN", err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	type registryError struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(map[string][]registryError{
+		"errors": {{Code: code, Message: msg}},
+	})
+	if err != nil {
+		log.WithError(err).Debug("Failed to write error")
+	}
+}
@@ -80,7 +80,7 @@ func authenticate(input string) error {
 }
 
 // authorized validates that the user is attempting to interact with an image
-// in their namespace.
+// in their namespace, or pull a snapshot that another user shared with them.
 func authorize(input string) error {
 	var authReqInfo api.AuthRequestInfo
 	err := json.Unmarshal([]byte(input), &authReqInfo)
@@ -96,5 +96,21 @@ func authorize(input string) error {
 	if strings.HasPrefix(authReqInfo.Name, namespace+"/") {
 		return nil
 	}
+
+	// Snapshots are shared by their ID, so any user can pull them. This must
+	// match the repository used by the cluster manager.
+	parts := strings.Split(authReqInfo.Name, "/")
+	if len(parts) > 2 && parts[1] == "snapshots" && onlyPulls(authReqInfo.Actions) {
+		return nil
+	}
 	return errors.New("not within user's namespace")
 }
+
+func onlyPulls(actions []string) bool {
+	for _, action := range actions {
+		if action != "pull" {
+			return false
+		}
+	}
+	return len(actions) != 0
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelda/blimp/pkg/faults"
+)
+
+func TestTCCommands(t *testing.T) {
+	assert.Empty(t, tcCommands(nil))
+
+	rules := []resolvedRule{
+		{
+			rule: faults.Rule{From: "api", To: "db", DropPercent: 5},
+			ips:  []string{"10.0.0.2"},
+		},
+		{
+			rule: faults.Rule{From: "api", To: "web", Delay: 300 * time.Millisecond},
+			ips:  []string{"10.0.0.3", "10.0.0.4"},
+		},
+	}
+	assert.Equal(t, [][]string{
+		{"qdisc", "add", "dev", "eth0", "root", "handle", "1:", "prio", "bands", "5"},
+		{"qdisc", "add", "dev", "eth0", "parent", "1:4", "handle", "40:", "netem", "loss", "5%"},
+		{"filter", "add", "dev", "eth0", "parent", "1:0", "protocol", "ip", "prio", "1",
+			"u32", "match", "ip", "dst", "10.0.0.2/32", "flowid", "1:4"},
+		{"qdisc", "add", "dev", "eth0", "parent", "1:5", "handle", "50:", "netem", "delay", "300ms"},
+		{"filter", "add", "dev", "eth0", "parent", "1:0", "protocol", "ip", "prio", "1",
+			"u32", "match", "ip", "dst", "10.0.0.3/32", "flowid", "1:5"},
+		{"filter", "add", "dev", "eth0", "parent", "1:0", "protocol", "ip", "prio", "1",
+			"u32", "match", "ip", "dst", "10.0.0.4/32", "flowid", "1:5"},
+	}, tcCommands(rules))
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/faults"
+)
+
+const (
+	// pollInterval is how often the agent checks for changes to the rules,
+	// and to the IPs of the services they refer to.
+	pollInterval = 5 * time.Second
+
+	// iface is the pod's network interface.
+	iface = "eth0"
+)
+
+// resolvedRule is a fault rule along with the current IPs of its destination
+// service.
+type resolvedRule struct {
+	rule faults.Rule
+	ips  []string
+}
+
+func main() {
+	service := os.Getenv("BLIMP_SERVICE")
+	if service == "" {
+		log.Error("BLIMP_SERVICE environment variable is required")
+		os.Exit(1)
+	}
+
+	rulesDir := os.Getenv("BLIMP_FAULTS_DIR")
+	if rulesDir == "" {
+		log.Error("BLIMP_FAULTS_DIR environment variable is required")
+		os.Exit(1)
+	}
+
+	var applied []resolvedRule
+	for {
+		desired, err := getDesiredRules(service, rulesDir)
+		if err != nil {
+			log.WithError(err).Warn("Failed to get fault rules")
+		} else if !reflect.DeepEqual(desired, applied) {
+			if err := apply(desired); err != nil {
+				log.WithError(err).Warn("Failed to apply fault rules")
+			} else {
+				applied = desired
+				logRules(applied)
+			}
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+// getDesiredRules reads the rules that apply to traffic sent by the given
+// service, and resolves the IPs of the destination services.
+func getDesiredRules(service, rulesDir string) ([]resolvedRule, error) {
+	readRules := func(key string) ([]faults.Rule, error) {
+		rulesJSON, err := ioutil.ReadFile(filepath.Join(rulesDir, key))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return faults.Unmarshal(string(rulesJSON))
+	}
+
+	composeRules, err := readRules(faults.ComposeRulesKey)
+	if err != nil {
+		return nil, errors.WithContext("read compose rules", err)
+	}
+
+	runtimeRules, err := readRules(faults.RuntimeRulesKey)
+	if err != nil {
+		return nil, errors.WithContext("read runtime rules", err)
+	}
+
+	var desired []resolvedRule
+	for _, rule := range faults.Merge(composeRules, runtimeRules) {
+		if rule.From != service {
+			continue
+		}
+
+		// Services are resolved with the sandbox's DNS server, so if the
+		// destination isn't running, there's no traffic to degrade.
+		addrs, err := net.LookupHost(rule.To)
+		if err != nil {
+			log.WithError(err).WithField("service", rule.To).Debug("Failed to resolve service")
+			continue
+		}
+
+		var ips []string
+		for _, addr := range addrs {
+			if ip := net.ParseIP(addr); ip != nil && ip.To4() != nil {
+				ips = append(ips, addr)
+			}
+		}
+		if len(ips) == 0 {
+			continue
+		}
+
+		sort.Strings(ips)
+		desired = append(desired, resolvedRule{rule: rule, ips: ips})
+	}
+
+	sort.Slice(desired, func(i, j int) bool {
+		return desired[i].rule.To < desired[j].rule.To
+	})
+
+	if len(desired) > faults.MaxRulesPerService {
+		desired = desired[:faults.MaxRulesPerService]
+	}
+	return desired, nil
+}
+
+// apply replaces the interface's queueing discipline with one that
+// implements the given rules.
+func apply(rules []resolvedRule) error {
+	// Remove the current rules. This fails if there aren't any rules
+	// installed, which is fine.
+	_ = runTC("qdisc", "del", "dev", iface, "root")
+
+	for _, args := range tcCommands(rules) {
+		if err := runTC(args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tcCommands returns the tc commands that install the given rules. Traffic
+// is classified with a `prio` queueing discipline. The first three bands
+// are used for traffic without faults, and each rule gets its own band with
+// a netem queueing discipline that implements the faults.
+func tcCommands(rules []resolvedRule) (cmds [][]string) {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	cmds = append(cmds, []string{"qdisc", "add", "dev", iface, "root", "handle", "1:",
+		"prio", "bands", fmt.Sprintf("%d", 3+len(rules))})
+	for i, rule := range rules {
+		band := 4 + i
+		netemCmd := []string{"qdisc", "add", "dev", iface, "parent", fmt.Sprintf("1:%d", band),
+			"handle", fmt.Sprintf("%d0:", band)}
+		cmds = append(cmds, append(netemCmd, rule.rule.NetemArgs()...))
+
+		for _, ip := range rule.ips {
+			cmds = append(cmds, []string{"filter", "add", "dev", iface, "parent", "1:0",
+				"protocol", "ip", "prio", "1", "u32", "match", "ip", "dst", ip + "/32",
+				"flowid", fmt.Sprintf("1:%d", band)})
+		}
+	}
+	return cmds
+}
+
+func runTC(args ...string) error {
+	out, err := exec.Command("tc", args...).CombinedOutput()
+	if err != nil {
+		return errors.WithContext(fmt.Sprintf("tc %s (%s)",
+			strings.Join(args, " "), strings.TrimSpace(string(out))), err)
+	}
+	return nil
+}
+
+func logRules(rules []resolvedRule) {
+	if len(rules) == 0 {
+		log.Info("No fault rules are active")
+		return
+	}
+
+	for _, rule := range rules {
+		log.WithField("ips", rule.ips).Infof("Applied fault rule %s", rule.rule)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"net"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -51,6 +52,18 @@ type dnsTable struct {
 
 	recordLock sync.Mutex
 	records    map[string]net.IP
+	projects   projectRecords
+}
+
+// projectRecords contains the names that are only resolvable by the pods in
+// a named Compose project. This lets the services in different projects use
+// the same names to refer to each other.
+type projectRecords struct {
+	// records maps project names to the project's records.
+	records map[string]map[string]net.IP
+
+	// podProjects maps the IPs of pods to the project they belong to.
+	podProjects map[string]string
 }
 
 func run(kubeClient kubernetes.Interface, namespace string) {
@@ -116,14 +129,32 @@ func (table *dnsTable) UpdateTable() {
 		return
 	}
 
-	records := podsToDNS(pods)
+	// Add-ons run alongside the customer pods, and are resolved the same
+	// way.
+	addonPods, err := table.lister.Pods(table.namespace).
+		List(labels.Set(
+			map[string]string{"blimp.addon": "true"},
+		).AsSelector())
+	if err != nil {
+		log.WithError(err).Error("Failed to list add-on pods")
+		return
+	}
+	pods = append(pods, addonPods...)
+
+	records, projects := podsToDNS(pods)
 	table.records = records
+	table.projects = projects
 }
 
 func (table *dnsTable) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
 	defer w.Close()
 
-	resp := table.genResponse(req)
+	var clientIP string
+	if addr, ok := w.RemoteAddr().(*net.UDPAddr); ok {
+		clientIP = addr.IP.String()
+	}
+
+	resp := table.genResponse(req, clientIP)
 	if resp == nil {
 		return
 	}
@@ -133,7 +164,7 @@ func (table *dnsTable) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
 	}
 }
 
-func (table *dnsTable) genResponse(req *dns.Msg) *dns.Msg {
+func (table *dnsTable) genResponse(req *dns.Msg, clientIP string) *dns.Msg {
 	resp := &dns.Msg{}
 	if len(req.Question) != 1 {
 		return resp.SetRcode(req, dns.RcodeNotImplemented)
@@ -150,7 +181,7 @@ func (table *dnsTable) genResponse(req *dns.Msg) *dns.Msg {
 		return resp.SetRcode(req, dns.RcodeNotImplemented)
 	}
 
-	ips := table.lookupA(q.Name)
+	ips := table.lookupA(q.Name, clientIP)
 	if len(ips) == 0 {
 		// Even though the client asked for a Kelda hostname that we know
 		// nothing about, it's possible we'll learn about it in the future.  For
@@ -180,13 +211,16 @@ func (table *dnsTable) genResponse(req *dns.Msg) *dns.Msg {
 	return resp
 }
 
-func (table *dnsTable) lookupA(name string) []net.IP {
+func (table *dnsTable) lookupA(name, clientIP string) []net.IP {
 	name = strings.TrimRight(strings.ToLower(name), ".")
 
-	// Try to see if it's an internal name first. If not, we'll fallback to
-	// external DNS.
+	// Try to see if it's an internal name first, starting with the names in
+	// the client's project. If not, we'll fallback to external DNS.
 	table.recordLock.Lock()
-	ip := table.records[name]
+	ip := table.projects.records[table.projects.podProjects[clientIP]][name]
+	if ip == nil {
+		ip = table.records[name]
+	}
 	table.recordLock.Unlock()
 	if ip != nil {
 		return []net.IP{ip}
@@ -226,8 +260,24 @@ func makeTable(namespace string, lister listers.PodLister) *dnsTable {
 	return tbl
 }
 
-func podsToDNS(pods []*corev1.Pod) map[string]net.IP {
+// podsToDNS returns the DNS records for the pods. Every service is resolvable
+// by its full name. The aliases of services in named projects, and their names
+// without the project prefix, are only resolvable from within the project.
+func podsToDNS(pods []*corev1.Pod) (map[string]net.IP, projectRecords) {
 	records := map[string]net.IP{}
+	projects := projectRecords{
+		records:     map[string]map[string]net.IP{},
+		podProjects: map[string]string{},
+	}
+
+	// Multiple pods can have the same service name while a service is being
+	// updated. Process the ready pods last so that their records take
+	// precedence.
+	pods = append([]*corev1.Pod(nil), pods...)
+	sort.SliceStable(pods, func(i, j int) bool {
+		return podPriority(pods[i]) < podPriority(pods[j])
+	})
+
 	for _, pod := range pods {
 		ip := net.ParseIP(pod.Status.PodIP)
 		if ip == nil {
@@ -237,6 +287,16 @@ func podsToDNS(pods []*corev1.Pod) map[string]net.IP {
 		serviceName := pod.Labels["blimp.service"]
 		records[strings.ToLower(serviceName)] = ip
 
+		scopedRecords := records
+		if project, ok := pod.Labels["blimp.project"]; ok {
+			projects.podProjects[pod.Status.PodIP] = project
+			if _, ok := projects.records[project]; !ok {
+				projects.records[project] = map[string]net.IP{}
+			}
+			scopedRecords = projects.records[project]
+			scopedRecords[strings.ToLower(strings.TrimPrefix(serviceName, project+"-"))] = ip
+		}
+
 		// Add aliases to DNS.
 		aliases, ok := pod.Annotations[metadata.AliasesKey]
 		if !ok {
@@ -244,10 +304,24 @@ func podsToDNS(pods []*corev1.Pod) map[string]net.IP {
 		}
 
 		for _, alias := range metadata.ParseAliases(aliases) {
-			records[strings.ToLower(alias)] = ip
+			scopedRecords[strings.ToLower(alias)] = ip
+		}
+	}
+	return records, projects
+}
+
+// podPriority ranks how suitable a pod is for receiving traffic.
+func podPriority(pod *corev1.Pod) int {
+	if pod.DeletionTimestamp != nil {
+		return 0
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			return 2
 		}
 	}
-	return records
+	return 1
 }
 
 var listenAndServe = func(table *dnsTable) error {
@@ -5,8 +5,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/metadata"
 )
 
 func TestLookupA(t *testing.T) {
@@ -75,6 +78,89 @@ func TestLookupA(t *testing.T) {
 	for _, test := range tests {
 		lookupHost = test.lookupExternalHost
 		tbl := dnsTable{records: test.records}
-		assert.Equal(t, test.expIPs, tbl.lookupA(test.req), test.name)
+		assert.Equal(t, test.expIPs, tbl.lookupA(test.req, ""), test.name)
 	}
 }
+
+func TestProjectLookup(t *testing.T) {
+	pod := func(service, project, ip string, aliases ...string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"blimp.service": service},
+				Annotations: map[string]string{
+					metadata.AliasesKey: metadata.Aliases(aliases),
+				},
+			},
+			Status: corev1.PodStatus{PodIP: ip},
+		}
+		if project != "" {
+			pod.Labels["blimp.project"] = project
+		}
+		return pod
+	}
+
+	var tbl dnsTable
+	tbl.records, tbl.projects = podsToDNS([]*corev1.Pod{
+		pod("db", "", "10.0.0.1", "database"),
+		pod("shop-web", "shop", "10.0.1.1"),
+		pod("shop-db", "shop", "10.0.1.2", "database"),
+		pod("blog-web", "blog", "10.0.2.1"),
+		pod("blog-db", "blog", "10.0.2.2"),
+	})
+
+	lookupHost = func(string) ([]string, error) {
+		return nil, errors.New("unknown host")
+	}
+
+	// Names in the client's project take precedence.
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.1.2")}, tbl.lookupA("db.", "10.0.1.1"))
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.1.2")}, tbl.lookupA("database.", "10.0.1.1"))
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.2.2")}, tbl.lookupA("db.", "10.0.2.1"))
+
+	// Services in the default project don't see the names scoped to other
+	// projects.
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1")}, tbl.lookupA("db.", "10.0.0.1"))
+	assert.Empty(t, tbl.lookupA("web.", "10.0.0.1"))
+
+	// Names that aren't in the client's project fall back to the default
+	// project.
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1")}, tbl.lookupA("database.", "10.0.2.1"))
+
+	// Services in other projects are resolvable by their full name.
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.2.1")}, tbl.lookupA("blog-web.", "10.0.1.1"))
+}
+
+func TestPreferReadyPods(t *testing.T) {
+	pod := func(ip string, ready, deleting bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"blimp.service": "web"},
+			},
+			Status: corev1.PodStatus{PodIP: ip},
+		}
+		if ready {
+			pod.Status.Conditions = []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			}
+		}
+		if deleting {
+			now := metav1.Now()
+			pod.DeletionTimestamp = &now
+		}
+		return pod
+	}
+
+	// The ready surge pod takes precedence over the booting pod.
+	records, _ := podsToDNS([]*corev1.Pod{
+		pod("10.0.0.1", true, false),
+		pod("10.0.0.2", false, false),
+	})
+	assert.Equal(t, net.ParseIP("10.0.0.1"), records["web"])
+
+	// Pods that are being deleted are only used as a last resort.
+	records, _ = podsToDNS([]*corev1.Pod{
+		pod("10.0.0.2", false, false),
+		pod("10.0.0.1", true, true),
+	})
+	assert.Equal(t, net.ParseIP("10.0.0.2"), records["web"])
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/node/dockershim"
+)
+
+// The dockersocket sidecar listens on the socket that the service's
+// DOCKER_HOST points to, and forwards each connection to the Docker API shim
+// in the node controller. It also publishes the ports of the containers
+// started through the socket on localhost, like Docker does.
+func main() {
+	sockPath := strings.TrimPrefix(os.Getenv("DOCKER_HOST"), "unix://")
+	shimAddr := fmt.Sprintf("%s:%d", os.Getenv("NODE_CONTROLLER_HOST"), dockershim.Port)
+
+	// Remove the socket left behind if the container restarted.
+	if err := os.Remove(sockPath); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).Fatal("Failed to remove stale socket")
+	}
+
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to listen")
+	}
+
+	// The service may run as any user.
+	if err := os.Chmod(sockPath, 0777); err != nil {
+		log.WithError(err).Fatal("Failed to set socket permissions")
+	}
+
+	go publishPorts(shimAddr)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			log.WithError(err).Fatal("Failed to accept connection")
+		}
+
+		go func() {
+			upstream, err := net.Dial("tcp", shimAddr)
+			if err != nil {
+				log.WithError(err).Warn("Failed to connect to node controller")
+				conn.Close()
+				return
+			}
+			forward(conn, upstream)
+		}()
+	}
+}
+
+// publishPorts polls for the containers' published ports, and forwards
+// connections to them from localhost.
+func publishPorts(shimAddr string) {
+	listeners := map[dockershim.PublishedPort]net.Listener{}
+	for range time.Tick(time.Second) {
+		ports, err := getPublishedPorts(shimAddr)
+		if err != nil {
+			log.WithError(err).Debug("Failed to get published ports")
+			continue
+		}
+
+		published := map[dockershim.PublishedPort]struct{}{}
+		for _, port := range ports {
+			published[port] = struct{}{}
+			if _, ok := listeners[port]; ok {
+				continue
+			}
+
+			ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port.HostPort))
+			if err != nil {
+				log.WithError(err).WithField("port", port.HostPort).Warn("Failed to publish port")
+				continue
+			}
+			listeners[port] = ln
+			go acceptAndForward(ln, fmt.Sprintf("%s:%d", port.ContainerIP, port.ContainerPort))
+		}
+
+		for port, ln := range listeners {
+			if _, ok := published[port]; !ok {
+				ln.Close()
+				delete(listeners, port)
+			}
+		}
+	}
+}
+
+func getPublishedPorts(shimAddr string) ([]dockershim.PublishedPort, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s%s", shimAddr, dockershim.PublishedPortsPath))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var ports []dockershim.PublishedPort
+	if err := json.NewDecoder(resp.Body).Decode(&ports); err != nil {
+		return nil, err
+	}
+	return ports, nil
+}
+
+func acceptAndForward(ln net.Listener, dst string) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			upstream, err := net.Dial("tcp", dst)
+			if err != nil {
+				log.WithError(err).WithField("destination", dst).Warn("Failed to connect to container")
+				conn.Close()
+				return
+			}
+			forward(conn, upstream)
+		}()
+	}
+}
+
+func forward(a, b net.Conn) {
+	defer a.Close()
+	defer b.Close()
+
+	done := make(chan struct{}, 2)
+	copyConn := func(dst, src net.Conn) {
+		//nolint:errcheck // The connection is closed either way.
+		io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go copyConn(a, b)
+	go copyConn(b, a)
+	<-done
+}
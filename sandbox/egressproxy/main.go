@@ -0,0 +1,286 @@
+// The egress proxy enforces the domain allowlist of sandboxes whose egress is
+// restricted. Services can't reach the internet directly, so they're
+// configured to send their HTTP and HTTPS traffic through this proxy, which
+// only forwards connections to allowlisted domains.
+//
+// Domains are resolved by the proxy, and connections are refused if any of
+// the addresses are in a denied range, so that an allowlisted domain can't be
+// pointed at an internal network.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/errors"
+)
+
+const (
+	// Port is the port that the proxy listens on.
+	Port = 3128
+
+	dialTimeout = 30 * time.Second
+)
+
+func main() {
+	p, err := newProxy(os.Getenv("ALLOWED_DOMAINS"), os.Getenv("ALLOWED_CIDRS"), os.Getenv("DENIED_CIDRS"))
+	if err != nil {
+		log.WithError(err).Fatal("Failed to parse policy")
+	}
+
+	log.WithField("allowedDomains", p.allowedDomains).Info("Starting egress proxy")
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", Port), p); err != nil {
+		log.WithError(err).Fatal("Failed to serve")
+	}
+}
+
+type proxy struct {
+	allowedDomains []string
+	allowedCIDRs   []*net.IPNet
+	deniedCIDRs    []*net.IPNet
+
+	resolver  *net.Resolver
+	transport *http.Transport
+}
+
+func newProxy(allowedDomains, allowedCIDRs, deniedCIDRs string) (*proxy, error) {
+	p := &proxy{
+		allowedDomains: splitList(allowedDomains),
+		resolver:       net.DefaultResolver,
+	}
+
+	var err error
+	p.allowedCIDRs, err = parseCIDRs(splitList(allowedCIDRs))
+	if err != nil {
+		return nil, errors.WithContext("parse allowed CIDRs", err)
+	}
+
+	p.deniedCIDRs, err = parseCIDRs(splitList(deniedCIDRs))
+	if err != nil {
+		return nil, errors.WithContext("parse denied CIDRs", err)
+	}
+
+	p.transport = &http.Transport{
+		Proxy:               nil,
+		DialContext:         p.dial,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	return p, nil
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.serveConnect(w, r)
+		return
+	}
+
+	if r.URL.Host == "" {
+		http.Error(w, "This is a proxy. Requests must use an absolute URL.", http.StatusBadRequest)
+		return
+	}
+
+	// Check the policy before forwarding the request so that denied
+	// requests get a clear response. The connection made by the transport is
+	// checked again when it's dialed.
+	if _, err := p.resolve(r.Context(), r.URL.Hostname()); err != nil {
+		p.writeError(w, r.URL.Host, err, http.StatusForbidden)
+		return
+	}
+
+	// Forward the request without the hop-by-hop headers that only apply to
+	// the connection with the proxy.
+	outReq := r.Clone(r.Context())
+	outReq.RequestURI = ""
+	outReq.Header.Del("Proxy-Connection")
+	outReq.Header.Del("Proxy-Authorization")
+
+	resp, err := p.transport.RoundTrip(outReq)
+	if err != nil {
+		p.writeError(w, r.URL.Host, err, http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+func (p *proxy) serveConnect(w http.ResponseWriter, r *http.Request) {
+	upstream, err := p.dial(r.Context(), "tcp", r.Host)
+	if err != nil {
+		status := http.StatusBadGateway
+		if _, ok := err.(errors.FriendlyError); ok {
+			status = http.StatusForbidden
+		}
+		p.writeError(w, r.Host, err, status)
+		return
+	}
+	defer upstream.Close()
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Connection hijacking isn't supported", http.StatusInternalServerError)
+		return
+	}
+
+	client, _, err := hijacker.Hijack()
+	if err != nil {
+		log.WithError(err).Warn("Failed to hijack connection")
+		return
+	}
+	defer client.Close()
+
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		io.Copy(upstream, client)
+		if tcpConn, ok := upstream.(*net.TCPConn); ok {
+			tcpConn.CloseWrite()
+		}
+		wg.Done()
+	}()
+	go func() {
+		io.Copy(client, upstream)
+		if tcpConn, ok := client.(*net.TCPConn); ok {
+			tcpConn.CloseWrite()
+		}
+		wg.Done()
+	}()
+	wg.Wait()
+}
+
+// dial connects to the address if it's allowed by the policy. The host is
+// resolved here, and the connection is made to the checked IP, so that the
+// domain can't resolve to a different address between the check and the
+// connection.
+func (p *proxy) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.NewFriendlyError("Invalid address %q", addr)
+	}
+
+	ips, err := p.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	var lastErr error
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// resolve returns the addresses that connections to the host may be made to.
+func (p *proxy) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		if !containsIP(p.allowedCIDRs, ip) {
+			return nil, errDenied(host)
+		}
+		return []net.IP{ip}, nil
+	}
+
+	if !domainAllowed(p.allowedDomains, host) {
+		return nil, errDenied(host)
+	}
+
+	addrs, err := p.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, errors.WithContext("resolve", err)
+	}
+
+	var ips []net.IP
+	for _, addr := range addrs {
+		if containsIP(p.deniedCIDRs, addr.IP) {
+			return nil, errors.NewFriendlyError(
+				"%s resolves to %s, which Blimp's egress policy doesn't allow", host, addr.IP)
+		}
+		ips = append(ips, addr.IP)
+	}
+	return ips, nil
+}
+
+func (p *proxy) writeError(w http.ResponseWriter, host string, err error, status int) {
+	log.WithError(err).WithField("host", host).Info("Refused connection")
+	http.Error(w, errors.GetPrintableMessage(err), status)
+}
+
+func errDenied(host string) error {
+	return errors.NewFriendlyError("Blimp's egress policy doesn't allow connections to %s", host)
+}
+
+// domainAllowed returns whether the host matches one of the allowed domains.
+// Domains starting with "*." match all of their subdomains.
+func domainAllowed(allowed []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, domain := range allowed {
+		domain = strings.ToLower(domain)
+		if strings.HasPrefix(domain, "*.") {
+			if strings.HasSuffix(host, domain[1:]) {
+				return true
+			}
+			continue
+		}
+
+		if host == domain {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIP(cidrs []*net.IPNet, ip net.IP) bool {
+	for _, cidr := range cidrs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCIDRs(strs []string) ([]*net.IPNet, error) {
+	var cidrs []*net.IPNet
+	for _, str := range strs {
+		_, cidr, err := net.ParseCIDR(str)
+		if err != nil {
+			return nil, err
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	return cidrs, nil
+}
+
+func splitList(str string) []string {
+	var items []string
+	for _, item := range strings.Split(str, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainAllowed(t *testing.T) {
+	allowed := []string{"github.com", "*.npmjs.org"}
+	assert.True(t, domainAllowed(allowed, "github.com"))
+	assert.True(t, domainAllowed(allowed, "GitHub.com."))
+	assert.True(t, domainAllowed(allowed, "registry.npmjs.org"))
+	assert.False(t, domainAllowed(allowed, "api.github.com"))
+	assert.False(t, domainAllowed(allowed, "npmjs.org"))
+	assert.False(t, domainAllowed(allowed, "evilnpmjs.org"))
+}
+
+func TestResolveIPs(t *testing.T) {
+	p, err := newProxy("", "203.0.113.0/24", "10.0.0.0/8")
+	assert.NoError(t, err)
+
+	ips, err := p.resolve(context.Background(), "203.0.113.5")
+	assert.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("203.0.113.5")}, ips)
+
+	_, err = p.resolve(context.Background(), "10.0.0.1")
+	assert.Error(t, err)
+
+	_, err = p.resolve(context.Background(), "example.com")
+	assert.Error(t, err)
+
+	_, err = newProxy("", "not a cidr", "")
+	assert.Error(t, err)
+}
@@ -13,8 +13,8 @@ import (
 	"google.golang.org/grpc/encoding/gzip"
 
 	"github.com/kelda/blimp/node/wait"
-	protoWait "github.com/kelda/blimp/pkg/proto/wait"
 	"github.com/kelda/blimp/pkg/errors"
+	protoWait "github.com/kelda/blimp/pkg/proto/wait"
 )
 
 func main() {
@@ -55,6 +55,21 @@ func main() {
 	}
 
 	log.WithField("waitSpec", waitSpec).Info("Started")
+
+	// Keep waiting after the timeout so that the service boots if its
+	// requirements are eventually met. The cluster manager is responsible
+	// for reporting the timeout to the user.
+	if timeoutStr := os.Getenv("WAIT_TIMEOUT"); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			log.WithError(err).Warn("Failed to parse WAIT_TIMEOUT")
+		} else {
+			time.AfterFunc(timeout, func() {
+				log.WithField("timeout", timeout).Warn("Timed out waiting to boot. Still waiting.")
+			})
+		}
+	}
+
 	for {
 		if err := runOnce(nodeControllerHost, namespace, waitSpec); err != nil {
 			log.WithError(err).Error("Failed to run. Retrying in 10 seconds.")
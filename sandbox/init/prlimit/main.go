@@ -0,0 +1,72 @@
+// prlimit sets the open file limit of all the processes in a container. It's
+// run as a postStart hook since Kubernetes doesn't support setting ulimits.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintf(os.Stderr, "usage: %s SOFT HARD\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	soft, err := strconv.ParseUint(os.Args[1], 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid soft limit: %s\n", err)
+		os.Exit(1)
+	}
+
+	hard, err := strconv.ParseUint(os.Args[2], 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid hard limit: %s\n", err)
+		os.Exit(1)
+	}
+
+	limit := unix.Rlimit{Cur: soft, Max: hard}
+
+	// Scan twice so that processes that were forked while we were setting
+	// the limits of their parents are also updated.
+	for i := 0; i < 2; i++ {
+		if err := setAll(limit); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set limits: %s\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// setAll sets the open file limit of every process that we have permission
+// to modify.
+func setAll(limit unix.Rlimit) error {
+	procs, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return err
+	}
+
+	var set bool
+	self := os.Getpid()
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+		if err != nil || pid == self {
+			continue
+		}
+
+		// Ignore errors since processes may exit while we're scanning, and
+		// processes owned by other users (such as the pause process when the
+		// process namespace is shared) can't be modified.
+		if err := unix.Prlimit(pid, unix.RLIMIT_NOFILE, &limit, nil); err == nil {
+			set = true
+		}
+	}
+
+	if !set {
+		return fmt.Errorf("no processes could be updated")
+	}
+	return nil
+}
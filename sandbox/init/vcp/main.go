@@ -1,88 +1,91 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/proto/wait"
 )
 
+// maxParallelCopies is the maximum number of volumes that are initialized at
+// once.
+const maxParallelCopies = 4
+
 func main() {
 	fmt.Println("Hello World! This is Volume CP!")
+
+	cpPath := os.Args[1]
+	podName := os.Getenv("POD_NAME")
+	var volumes []*volumeInit
 	for _, arg := range os.Args[2:] {
 		argSplit := strings.Split(arg, ":")
-		from, to := argSplit[0], argSplit[1]
-		log.WithField("from", from).WithField("to", to).Info("Starting initialization")
+		volumes = append(volumes, &volumeInit{
+			from:     argSplit[0],
+			to:       argSplit[1],
+			source:   podName + ":" + argSplit[0],
+			progress: &wait.VolumeInitProgress{Volume: argSplit[0]},
+		})
+	}
 
-		// Resolve symlinks before copying so that we don't end just copying
-		// the symlink, which will make Kubernetes unable to mount the path as
-		// a directory.
-		// Instead, if it's a symlink, we should copy the contents of the
-		// destination.
-		var err error
-		from, err = filepath.EvalSymlinks(from)
-		if err != nil {
-			if os.IsNotExist(err) {
-				log.Info("Container image doesn't contain folder. Nothing to do.")
-			}
-			log.WithError(err).Warn("Failed to resolve symlinks (if any). Skipping")
-			continue
-		}
+	stateDir := os.Getenv("VCP_STATE_DIR")
+	if stateDir == "" {
+		log.Warn("VCP_STATE_DIR isn't set. Volumes won't be skipped if they're unchanged.")
+	}
 
-		fromInfo, err := os.Stat(from)
-		switch {
-		case err != nil:
-			log.WithError(err).Error("Failed to stat image contents. Skipping.")
-			continue
-		case !fromInfo.IsDir():
-			// Docker errors out in this case, but we just skip it to simplify
-			// the error handling.
-			log.Warn("Path in image isn't a directory. Skipping.")
-			continue
-		}
+	ctx, cancelReporter := context.WithCancel(context.Background())
+	reporterDone := make(chan struct{})
+	reporter := newReporter(os.Getenv("NODE_CONTROLLER_HOST"), os.Getenv("NAMESPACE"),
+		podName, volumes)
+	go func() {
+		reporter.Run(ctx)
+		close(reporterDone)
+	}()
 
-		toContents, err := ioutil.ReadDir(to)
-		switch {
-		case os.IsNotExist(err):
-			// It's ok if the volume does not exist yet -- the copy will create
-			// it.
-		case err != nil:
-			log.WithError(err).Error("Failed to stat volume contents. Skipping.")
-			continue
-		case len(toContents) != 0:
-			log.Info("Volume not empty. Skipping.")
-			continue
-		}
+	initAll(cpPath, stateDir, volumes, maxParallelCopies)
+
+	cancelReporter()
+	<-reporterDone
+}
 
-		// Make sure the parent directories exist.
-		if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
-			log.WithError(err).Error("Failed to make parent directories for target. Skipping.")
-			continue
+// initAll initializes the volumes, running up to `parallelism` copies at
+// once. Copies into the same volume, such as if the volume is mounted at
+// multiple paths, are run one after another since they aren't independent.
+func initAll(cpPath, stateDir string, volumes []*volumeInit, parallelism int) {
+	var targets []string
+	byTarget := map[string][]*volumeInit{}
+	for _, v := range volumes {
+		if _, ok := byTarget[v.to]; !ok {
+			targets = append(targets, v.to)
 		}
+		byTarget[v.to] = append(byTarget[v.to], v)
+	}
 
-		cmd := exec.Command(os.Args[1],
-			// Copy recursively, and preserve permissions and symlinks.
-			"-a",
-			// Overwrite the existing directory. This way we don't copy
-			// _into_ the directory if it already exists.
-			"-T",
-			from, to)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		fmt.Println(cmd)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	for _, target := range targets {
+		wg.Add(1)
+		go func(volumes []*volumeInit) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-		if err := cmd.Run(); err != nil {
-			// XXX: We don't fail even if the copy fails so that the container
-			// will continue to boot. It's currently not possible for users to
-			// debug these initialization containers, so it's better for us to
-			// proceed with the boot so that users can inspect the running
-			// container, and Blimp doesn't feel stuck.
-			// Eventually, we should propagate error messages back to the user.
-			log.WithError(err).Error("Failed to initialize volume")
-		}
+			for _, v := range volumes {
+				// XXX: We don't fail even if the copy fails so that the
+				// container will continue to boot. It's better for us to
+				// proceed with the boot so that users can inspect the
+				// running container, and Blimp doesn't feel stuck. The
+				// error is shown in the service's status.
+				if err := v.run(cpPath, stateDir); err != nil {
+					log.WithError(err).WithField("from", v.from).WithField("to", v.to).
+						Error("Failed to initialize volume")
+				}
+			}
+		}(byTarget[target])
 	}
+	wg.Wait()
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/kelda/blimp/node/wait"
+	"github.com/kelda/blimp/pkg/errors"
+	protoWait "github.com/kelda/blimp/pkg/proto/wait"
+)
+
+// reportInterval is how often the progress of the volumes is sent to the
+// node controller.
+const reportInterval = 2 * time.Second
+
+// reporter sends the progress of each volume to the node controller, which
+// makes it available to the status fetcher.
+type reporter struct {
+	nodeControllerHost string
+	namespace, pod     string
+	volumes            []*volumeInit
+}
+
+func newReporter(nodeControllerHost, namespace, pod string, volumes []*volumeInit) *reporter {
+	return &reporter{
+		nodeControllerHost: nodeControllerHost,
+		namespace:          namespace,
+		pod:                pod,
+		volumes:            volumes,
+	}
+}
+
+// Run reports the volumes' progress until the context is cancelled, and then
+// reports their final state. Progress isn't critical, so failures to report
+// are only logged.
+func (r *reporter) Run(ctx context.Context) {
+	if r.nodeControllerHost == "" || r.namespace == "" || r.pod == "" {
+		log.Warn("Node controller isn't configured. Not reporting progress.")
+		return
+	}
+
+	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", r.nodeControllerHost, wait.Port),
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(errors.UnaryClientInterceptor))
+	if err != nil {
+		log.WithError(err).Warn("Failed to connect to node controller. Not reporting progress.")
+		return
+	}
+	defer conn.Close()
+	client := protoWait.NewBootWaiterClient(conn)
+
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.report(ctx, client)
+		case <-ctx.Done():
+			// Send the final state even though the context is done.
+			finalCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			r.report(finalCtx, client)
+			cancel()
+			return
+		}
+	}
+}
+
+func (r *reporter) report(ctx context.Context, client protoWait.BootWaiterClient) {
+	req := &protoWait.ReportVolumeInitRequest{
+		Namespace: r.namespace,
+		Pod:       r.pod,
+	}
+	for _, v := range r.volumes {
+		req.Volumes = append(req.Volumes, v.getProgress())
+	}
+
+	if _, err := client.ReportVolumeInit(ctx, req); err != nil && ctx.Err() == nil {
+		log.WithError(err).Debug("Failed to report volume initialization progress")
+	}
+}
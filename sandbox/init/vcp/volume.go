@@ -0,0 +1,347 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/pkg/errors"
+	"github.com/kelda/blimp/pkg/hash"
+	"github.com/kelda/blimp/pkg/proto/wait"
+)
+
+// copyProgressInterval is how often the size of volumes is measured while
+// they're being copied.
+const copyProgressInterval = 2 * time.Second
+
+// volumeInit copies a directory from the image into a volume.
+type volumeInit struct {
+	from, to string
+
+	// source identifies where the volume's contents come from, so that
+	// volumes shared by multiple services are only reinitialized when the
+	// image of the service that initialized them changes.
+	source string
+
+	progressLock sync.Mutex
+	progress     *wait.VolumeInitProgress
+}
+
+// volumeState is persisted in the state directory for each volume, so that
+// later runs can tell whether the volume needs to be initialized again.
+type volumeState struct {
+	// Hash is the hash of the image contents that were copied into the
+	// volume.
+	Hash string `json:"hash,omitempty"`
+
+	// Source is the source of the volumeInit that copied the contents.
+	Source string `json:"source,omitempty"`
+
+	// Copying is set while the volume is being copied, so that copies that
+	// are interrupted, such as by the pod being deleted, are restarted.
+	Copying bool `json:"copying,omitempty"`
+}
+
+func (v *volumeInit) run(cpPath, stateDir string) error {
+	logger := log.WithField("from", v.from).WithField("to", v.to)
+	logger.Info("Starting initialization")
+
+	state, err := v.initialize(cpPath, stateDir)
+	v.updateProgress(func(p *wait.VolumeInitProgress) {
+		p.State = state
+		if err != nil {
+			p.Error = err.Error()
+		}
+	})
+	return err
+}
+
+func (v *volumeInit) initialize(cpPath, stateDir string) (wait.VolumeInitProgress_State, error) {
+	logger := log.WithField("from", v.from).WithField("to", v.to)
+
+	// Resolve symlinks before copying so that we don't end just copying
+	// the symlink, which will make Kubernetes unable to mount the path as
+	// a directory.
+	// Instead, if it's a symlink, we should copy the contents of the
+	// destination.
+	from, err := filepath.EvalSymlinks(v.from)
+	if err != nil {
+		if os.IsNotExist(err) {
+			logger.Info("Container image doesn't contain folder. Nothing to do.")
+			return wait.VolumeInitProgress_DONE, nil
+		}
+		logger.WithError(err).Warn("Failed to resolve symlinks (if any). Skipping")
+		return wait.VolumeInitProgress_DONE, nil
+	}
+
+	fromInfo, err := os.Stat(from)
+	switch {
+	case err != nil:
+		return wait.VolumeInitProgress_FAILED, errors.WithContext("stat image contents", err)
+	case !fromInfo.IsDir():
+		// Docker errors out in this case, but we just skip it to simplify
+		// the error handling.
+		logger.Warn("Path in image isn't a directory. Skipping.")
+		return wait.VolumeInitProgress_DONE, nil
+	}
+
+	fromHash, totalBytes, err := hashTree(from)
+	if err != nil {
+		return wait.VolumeInitProgress_FAILED, errors.WithContext("hash image contents", err)
+	}
+	v.updateProgress(func(p *wait.VolumeInitProgress) {
+		p.TotalBytes = totalBytes
+	})
+
+	statePath := ""
+	if stateDir != "" {
+		statePath = filepath.Join(stateDir, hash.DNSCompliant(v.to))
+	}
+	state, err := readState(statePath)
+	if err != nil {
+		return wait.VolumeInitProgress_FAILED, errors.WithContext("read state", err)
+	}
+
+	toContents, err := ioutil.ReadDir(v.to)
+	if err != nil && !os.IsNotExist(err) {
+		return wait.VolumeInitProgress_FAILED, errors.WithContext("stat volume contents", err)
+	}
+
+	// It's ok if the volume does not exist yet -- the copy will create it.
+	if len(toContents) != 0 {
+		switch {
+		case state.Copying:
+			logger.Info("Previous initialization was interrupted. Restarting it.")
+		case state.Hash == fromHash:
+			logger.Info("Volume already contains the image's contents. Skipping.")
+			return wait.VolumeInitProgress_UNCHANGED, nil
+		case state.Hash == "" || state.Source != v.source:
+			logger.Info("Volume not empty. Skipping.")
+			return wait.VolumeInitProgress_NOT_EMPTY, nil
+		default:
+			// The image changed since the volume was initialized. Only
+			// replace the volume's contents if they're exactly what was
+			// copied, so that data written by the services isn't lost.
+			toHash, _, err := hashTree(v.to)
+			if err != nil {
+				return wait.VolumeInitProgress_FAILED, errors.WithContext("hash volume contents", err)
+			}
+			if toHash != state.Hash {
+				logger.Info("Volume was modified since it was initialized. Skipping.")
+				return wait.VolumeInitProgress_NOT_EMPTY, nil
+			}
+			logger.Info("Image contents changed. Reinitializing volume.")
+		}
+	}
+
+	if err := writeState(statePath, volumeState{Copying: true}); err != nil {
+		return wait.VolumeInitProgress_FAILED, errors.WithContext("write state", err)
+	}
+	if err := os.RemoveAll(v.to); err != nil {
+		return wait.VolumeInitProgress_FAILED, errors.WithContext("clear volume", err)
+	}
+
+	// Make sure the parent directories exist.
+	if err := os.MkdirAll(filepath.Dir(v.to), 0755); err != nil {
+		return wait.VolumeInitProgress_FAILED, errors.WithContext("make parent directories", err)
+	}
+
+	v.updateProgress(func(p *wait.VolumeInitProgress) {
+		p.State = wait.VolumeInitProgress_COPYING
+	})
+	stopTracking := v.trackCopiedBytes(copyProgressInterval)
+	err = copyDir(cpPath, from, v.to)
+	stopTracking()
+	if err != nil {
+		return wait.VolumeInitProgress_FAILED, err
+	}
+
+	if err := writeState(statePath, volumeState{Hash: fromHash, Source: v.source}); err != nil {
+		return wait.VolumeInitProgress_FAILED, errors.WithContext("write state", err)
+	}
+	v.updateProgress(func(p *wait.VolumeInitProgress) {
+		p.CopiedBytes = totalBytes
+	})
+	return wait.VolumeInitProgress_DONE, nil
+}
+
+func copyDir(cpPath, from, to string) error {
+	cmd := exec.Command(cpPath,
+		// Copy recursively, and preserve permissions and symlinks.
+		"-a",
+		// Overwrite the existing directory. This way we don't copy
+		// _into_ the directory if it already exists.
+		"-T",
+		from, to)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	fmt.Println(cmd)
+	return cmd.Run()
+}
+
+// trackCopiedBytes periodically measures how much has been copied into the
+// volume until the returned function is called.
+func (v *volumeInit) trackCopiedBytes(interval time.Duration) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				copied, err := treeSize(v.to)
+				if err != nil {
+					continue
+				}
+				v.updateProgress(func(p *wait.VolumeInitProgress) {
+					p.CopiedBytes = copied
+				})
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+func (v *volumeInit) updateProgress(update func(*wait.VolumeInitProgress)) {
+	v.progressLock.Lock()
+	defer v.progressLock.Unlock()
+	update(v.progress)
+}
+
+func (v *volumeInit) getProgress() *wait.VolumeInitProgress {
+	v.progressLock.Lock()
+	defer v.progressLock.Unlock()
+	return proto.Clone(v.progress).(*wait.VolumeInitProgress)
+}
+
+// hashTree returns a hash of the directory's contents, and the total size of
+// its files. The hash covers the path, type, permissions and contents of each
+// file, and the targets of symlinks. It doesn't cover modification times or
+// owners, so that a directory hashes the same as its copy.
+func hashTree(root string) (string, int64, error) {
+	h := sha256.New()
+	var totalBytes int64
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%q %s\n", relPath, info.Mode())
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "-> %q\n", target)
+		case info.Mode().IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			n, err := io.Copy(h, f)
+			if err != nil {
+				return err
+			}
+			totalBytes += n
+			fmt.Fprintf(h, "\n%d\n", n)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), totalBytes, nil
+}
+
+// treeSize returns the total size of the files in the directory.
+func treeSize(root string) (int64, error) {
+	var size int64
+	err := filepath.Walk(root, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Files may be created and removed while the directory is
+			// being copied.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+func readState(path string) (volumeState, error) {
+	if path == "" {
+		return volumeState{}, nil
+	}
+
+	stateJSON, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return volumeState{}, nil
+	}
+	if err != nil {
+		return volumeState{}, err
+	}
+
+	var state volumeState
+	if err := json.Unmarshal(stateJSON, &state); err != nil {
+		// Treat corrupted state as an interrupted copy so that the volume
+		// is initialized again.
+		log.WithError(err).WithField("path", path).Warn("Failed to parse volume state")
+		return volumeState{Copying: true}, nil
+	}
+	return state, nil
+}
+
+// writeState atomically replaces the volume's state so that it's never
+// partially written.
+func writeState(path string, state volumeState) error {
+	if path == "" {
+		return nil
+	}
+
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, stateJSON, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
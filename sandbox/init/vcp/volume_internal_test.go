@@ -0,0 +1,176 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelda/blimp/pkg/hash"
+	"github.com/kelda/blimp/pkg/proto/wait"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for path, contents := range files {
+		path = filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0644))
+	}
+}
+
+func TestHashTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vcp")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	writeFiles(t, a, map[string]string{"index.html": "hello", "static/app.js": "app"})
+	writeFiles(t, b, map[string]string{"index.html": "hello", "static/app.js": "app"})
+	require.NoError(t, os.Symlink("index.html", filepath.Join(a, "link")))
+	require.NoError(t, os.Symlink("index.html", filepath.Join(b, "link")))
+
+	hashA, size, err := hashTree(a)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), size)
+
+	hashB, _, err := hashTree(b)
+	assert.NoError(t, err)
+	assert.Equal(t, hashA, hashB)
+
+	// Changing a file's contents changes the hash.
+	writeFiles(t, b, map[string]string{"index.html": "world"})
+	hashB, _, err = hashTree(b)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hashA, hashB)
+
+	// So does changing its permissions.
+	writeFiles(t, b, map[string]string{"index.html": "hello"})
+	require.NoError(t, os.Chmod(filepath.Join(b, "index.html"), 0600))
+	hashB, _, err = hashTree(b)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hashA, hashB)
+}
+
+func TestInitialize(t *testing.T) {
+	cpPath, err := exec.LookPath("cp")
+	if err != nil {
+		t.Skip("cp isn't installed")
+	}
+
+	dir, err := ioutil.TempDir("", "vcp")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	image := filepath.Join(dir, "image")
+	stateDir := filepath.Join(dir, "state")
+	writeFiles(t, image, map[string]string{"index.html": "v1"})
+
+	run := func(to string) (*wait.VolumeInitProgress, string) {
+		v := &volumeInit{from: image, to: to, source: "web:/usr/share/nginx/html",
+			progress: &wait.VolumeInitProgress{Volume: image}}
+		v.run(cpPath, stateDir)
+		contents, _ := ioutil.ReadFile(filepath.Join(to, "index.html"))
+		return v.getProgress(), string(contents)
+	}
+
+	// Empty volumes are initialized.
+	volume := filepath.Join(dir, "volume")
+	progress, contents := run(volume)
+	assert.Equal(t, wait.VolumeInitProgress_DONE, progress.State)
+	assert.Equal(t, int64(2), progress.TotalBytes)
+	assert.Equal(t, int64(2), progress.CopiedBytes)
+	assert.Equal(t, "v1", contents)
+
+	// Volumes that already contain the image's contents are skipped.
+	progress, _ = run(volume)
+	assert.Equal(t, wait.VolumeInitProgress_UNCHANGED, progress.State)
+
+	// If the image changes, volumes that weren't modified are reinitialized.
+	writeFiles(t, image, map[string]string{"index.html": "v2"})
+	progress, contents = run(volume)
+	assert.Equal(t, wait.VolumeInitProgress_DONE, progress.State)
+	assert.Equal(t, "v2", contents)
+
+	// Volumes that were modified are left alone.
+	writeFiles(t, volume, map[string]string{"data.db": "user data"})
+	writeFiles(t, image, map[string]string{"index.html": "v3"})
+	progress, contents = run(volume)
+	assert.Equal(t, wait.VolumeInitProgress_NOT_EMPTY, progress.State)
+	assert.Equal(t, "v2", contents)
+
+	// So are volumes that weren't initialized by vcp.
+	other := filepath.Join(dir, "other")
+	writeFiles(t, other, map[string]string{"index.html": "other"})
+	progress, contents = run(other)
+	assert.Equal(t, wait.VolumeInitProgress_NOT_EMPTY, progress.State)
+	assert.Equal(t, "other", contents)
+
+	// So are volumes that were initialized by another service.
+	writeFiles(t, image, map[string]string{"index.html": "v4"})
+	v := &volumeInit{from: image, to: volume, source: "api:/usr/share/nginx/html",
+		progress: &wait.VolumeInitProgress{}}
+	assert.NoError(t, v.run(cpPath, stateDir))
+	assert.Equal(t, wait.VolumeInitProgress_NOT_EMPTY, v.getProgress().State)
+
+	// Interrupted copies are restarted.
+	interrupted := filepath.Join(dir, "interrupted")
+	require.NoError(t, writeState(filepath.Join(stateDir, hash.DNSCompliant(interrupted)),
+		volumeState{Copying: true}))
+	writeFiles(t, interrupted, map[string]string{"partial": "partial"})
+	progress, contents = run(interrupted)
+	assert.Equal(t, wait.VolumeInitProgress_DONE, progress.State)
+	assert.Equal(t, "v4", contents)
+	_, err = os.Stat(filepath.Join(interrupted, "partial"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Images without the directory don't need any initialization.
+	v = &volumeInit{from: filepath.Join(dir, "missing"), to: filepath.Join(dir, "empty"),
+		progress: &wait.VolumeInitProgress{}}
+	assert.NoError(t, v.run(cpPath, stateDir))
+	assert.Equal(t, wait.VolumeInitProgress_DONE, v.getProgress().State)
+}
+
+func TestInitAll(t *testing.T) {
+	cpPath, err := exec.LookPath("cp")
+	if err != nil {
+		t.Skip("cp isn't installed")
+	}
+
+	dir, err := ioutil.TempDir("", "vcp")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var volumes []*volumeInit
+	for _, name := range []string{"a", "b", "c"} {
+		from := filepath.Join(dir, "image", name)
+		writeFiles(t, from, map[string]string{"file": name})
+		volumes = append(volumes, &volumeInit{
+			from:     from,
+			to:       filepath.Join(dir, "volumes", name),
+			source:   "web:" + from,
+			progress: &wait.VolumeInitProgress{Volume: from},
+		})
+	}
+
+	// Mount the same volume at two paths. Only the first path is copied
+	// since the volume isn't empty once it's done.
+	shared := filepath.Join(dir, "image", "shared")
+	writeFiles(t, shared, map[string]string{"file": "shared"})
+	volumes = append(volumes, &volumeInit{
+		from:     shared,
+		to:       filepath.Join(dir, "volumes", "a"),
+		source:   "web:" + shared,
+		progress: &wait.VolumeInitProgress{Volume: shared},
+	})
+
+	initAll(cpPath, filepath.Join(dir, "state"), volumes, 2)
+	for _, v := range volumes[:3] {
+		assert.Equal(t, wait.VolumeInitProgress_DONE, v.getProgress().State)
+	}
+	assert.Equal(t, wait.VolumeInitProgress_NOT_EMPTY, volumes[3].getProgress().State)
+}
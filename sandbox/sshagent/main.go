@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kelda/blimp/node/sshagent"
+)
+
+// The sshagent sidecar listens on the socket that the service's SSH_AUTH_SOCK
+// points to, and forwards each connection to the node controller, which
+// tunnels it to the SSH agent on the user's machine.
+func main() {
+	sockPath := os.Getenv("SSH_AUTH_SOCK")
+	nodeControllerAddr := fmt.Sprintf("%s:%d", os.Getenv("NODE_CONTROLLER_HOST"), sshagent.Port)
+
+	// Remove the socket left behind if the container restarted.
+	if err := os.Remove(sockPath); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).Fatal("Failed to remove stale socket")
+	}
+
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to listen")
+	}
+
+	// The service may run as any user.
+	if err := os.Chmod(sockPath, 0777); err != nil {
+		log.WithError(err).Fatal("Failed to set socket permissions")
+	}
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			log.WithError(err).Fatal("Failed to accept connection")
+		}
+
+		go forward(conn, nodeControllerAddr)
+	}
+}
+
+func forward(conn net.Conn, nodeControllerAddr string) {
+	defer conn.Close()
+
+	upstream, err := net.Dial("tcp", nodeControllerAddr)
+	if err != nil {
+		log.WithError(err).Warn("Failed to connect to node controller")
+		return
+	}
+	defer upstream.Close()
+
+	done := make(chan struct{}, 2)
+	copyConn := func(dst, src net.Conn) {
+		//nolint:errcheck // The connection is closed either way.
+		io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go copyConn(upstream, conn)
+	go copyConn(conn, upstream)
+	<-done
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,14 +10,21 @@ import (
 	"path/filepath"
 	"sort"
 
+	log "github.com/sirupsen/logrus"
+
 	"github.com/kelda/blimp/pkg/hash"
 	"github.com/kelda/blimp/pkg/syncthing"
 )
 
 func main() {
 	folders := syncthing.ArgsToMap(os.Args[1:])
+	delta := syncthing.ArgsToDelta(os.Args[1:])
+	fixes, err := syncthing.ArgsToFileFixes(os.Args[1:])
+	if err != nil {
+		panic(err)
+	}
 
-	err := syncthing.MakeMarkers(folders)
+	err = syncthing.MakeMarkers(folders)
 	if err != nil {
 		panic(err)
 	}
@@ -29,7 +37,7 @@ func main() {
 	}
 
 
-	homePath := fmt.Sprintf("/pv/syncthing-config/%s", configHash(folders))
+	homePath := fmt.Sprintf("/pv/syncthing-config/%s", configHash(folders, delta))
 
 	if _, err := os.Stat(homePath); os.IsNotExist(err) {
 		// This homePath is new, so create it. Copy from /var/syncthing/config/
@@ -44,7 +52,7 @@ func main() {
 			panic(err)
 		}
 
-		configFile := syncthing.MakeServer(folders)
+		configFile := syncthing.MakeServer(folders, delta)
 		configPath := filepath.Join(homePath, "config.xml")
 		err = ioutil.WriteFile(configPath, []byte(configFile), 0655)
 		if err != nil {
@@ -56,15 +64,41 @@ func main() {
 	cmd := exec.Command("/bin/syncthing", "-verbose", "-home", homePath)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := cmd.Start(); err != nil {
+		panic(err)
+	}
+
+	if len(fixes) != 0 {
+		go fixFiles(folders, fixes)
+	}
+
+	if err := cmd.Wait(); err != nil {
 		panic(err)
 	}
 }
 
-func configHash(folders map[string]string) string {
+// fixFiles applies the fixes for things that Syncthing doesn't sync, such as
+// file ownership, to files that were synced before we started, and then to
+// files as they're synced.
+func fixFiles(folders map[string]string, fixes map[string]syncthing.FileFixes) {
+	for id, folderFixes := range fixes {
+		if err := syncthing.FixFolder(folders[id], folderFixes); err != nil {
+			log.WithError(err).WithField("folder", folders[id]).Warn("Failed to fix files")
+		}
+	}
+
+	api := syncthing.APIClient{Address: fmt.Sprintf("127.0.0.1:%d", syncthing.APIPort)}
+	syncthing.WatchFiles(context.Background(), api, folders, fixes)
+}
+
+func configHash(folders map[string]string, delta map[string]bool) string {
 	type kv struct{Key, Value string}
 	slice := []kv{}
 	for k, v := range folders {
+		// Folders that use delta transfers get a different config.
+		if delta[k] {
+			v += ",delta"
+		}
 		slice = append(slice, kv{k, v})
 	}
 	sort.Slice(slice, func(i, j int) bool {
@@ -5,6 +5,13 @@ set -euo pipefail
 kubectl create serviceaccount blimp-cluster-controller >/dev/null
 kubectl create clusterrolebinding blimp-cluster-controller --clusterrole=cluster-admin --serviceaccount=default:blimp-cluster-controller >/dev/null
 
+# The manager tails the logs of services with x-blimp.ready_log_pattern, and
+# sets the readiness gate on their pods. cluster-admin already allows this,
+# but the rules are granted explicitly so that they survive if cluster-admin
+# is swapped for a narrower role.
+kubectl create clusterrole blimp-log-readiness --verb=get,update --resource=pods/log,pods/status >/dev/null
+kubectl create clusterrolebinding blimp-log-readiness --clusterrole=blimp-log-readiness --serviceaccount=default:blimp-cluster-controller >/dev/null
+
 # Read the current settings.
 context="$(kubectl config current-context)"
 cluster="$(kubectl config view -o "jsonpath={.contexts[?(@.name==\"$context\")].context.cluster}")"